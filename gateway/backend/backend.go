@@ -0,0 +1,147 @@
+// Package backend 管理 gateway 到各后端服务的 gRPC 连接。
+//
+// 连接在首次调用时才建立，建立失败时本次调用返回 Unavailable，并在 dialRetryInterval 后的调用中重试，
+// 因此依赖服务滚动发布或配置暂时有误时 gateway 仍能启动，只有涉及该服务的接口失败。
+// 同一服务在 gateway 内共用一个连接；Check 检查各服务的健康状态，供 /healthz 展示。
+package backend
+
+import (
+	"context"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/RigelNana/arkstudy/pkg/discovery"
+	"github.com/RigelNana/arkstudy/pkg/registry"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+// 建立连接失败后重试的最小间隔，避免每个请求都重新解析地址
+const dialRetryInterval = 5 * time.Second
+
+// 后端健康状态
+const (
+	StatusServing     = "serving"
+	StatusNotServing  = "not_serving"
+	StatusUnavailable = "unavailable"
+)
+
+// Conn 到单个后端服务的惰性连接，实现 grpc.ClientConnInterface，可直接传给生成的 NewXxxClient
+type Conn struct {
+	svc registry.Service
+
+	mu          sync.Mutex
+	conn        *grpc.ClientConn
+	lastErr     error
+	lastAttempt time.Time
+}
+
+var (
+	connsMu sync.Mutex
+	conns   = map[string]*Conn{}
+)
+
+// For 返回 svc 的共享连接，此时并不建立连接
+func For(svc registry.Service) *Conn {
+	connsMu.Lock()
+	defer connsMu.Unlock()
+	c, ok := conns[svc.Name]
+	if !ok {
+		c = &Conn{svc: svc}
+		conns[svc.Name] = c
+	}
+	return c
+}
+
+// Invoke 实现 grpc.ClientConnInterface
+func (c *Conn) Invoke(ctx context.Context, method string, args, reply any, opts ...grpc.CallOption) error {
+	conn, err := c.get()
+	if err != nil {
+		return err
+	}
+	return conn.Invoke(ctx, method, args, reply, opts...)
+}
+
+// NewStream 实现 grpc.ClientConnInterface
+func (c *Conn) NewStream(ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	conn, err := c.get()
+	if err != nil {
+		return nil, err
+	}
+	return conn.NewStream(ctx, desc, method, opts...)
+}
+
+// get 返回已建立的连接，尚未建立时尝试建立，距上次失败不足 dialRetryInterval 时直接返回上次的错误
+func (c *Conn) get() (*grpc.ClientConn, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.conn != nil {
+		return c.conn, nil
+	}
+	if c.lastErr != nil && time.Since(c.lastAttempt) < dialRetryInterval {
+		return nil, c.lastErr
+	}
+	c.lastAttempt = time.Now()
+	conn, err := discovery.Dial(c.svc)
+	if err != nil {
+		log.Printf("Warning: %v, retrying in %s", err, dialRetryInterval)
+		c.lastErr = status.Errorf(codes.Unavailable, "%s unavailable: %v", c.svc.Name, err)
+		return nil, c.lastErr
+	}
+	c.conn, c.lastErr = conn, nil
+	return conn, nil
+}
+
+// Health 单个后端的健康状态
+type Health struct {
+	Name   string `json:"name"`
+	Target string `json:"target"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// check 通过 grpc.health.v1 检查后端，未实现健康检查服务的后端（如 llm-service）视为健康，与 pkg/discovery 一致
+func (c *Conn) check(ctx context.Context) Health {
+	h := Health{Name: c.svc.Name, Target: discovery.Target(c.svc)}
+	resp, err := healthpb.NewHealthClient(c).Check(ctx, &healthpb.HealthCheckRequest{})
+	switch {
+	case status.Code(err) == codes.Unimplemented:
+		h.Status = StatusServing
+	case err != nil:
+		h.Status, h.Error = StatusUnavailable, err.Error()
+	case resp.Status == healthpb.HealthCheckResponse_SERVING:
+		h.Status = StatusServing
+	default:
+		h.Status = StatusNotServing
+	}
+	return h
+}
+
+// Check 并发检查全部已创建连接的后端，按服务名排序返回
+func Check(ctx context.Context, timeout time.Duration) []Health {
+	connsMu.Lock()
+	all := make([]*Conn, 0, len(conns))
+	for _, c := range conns {
+		all = append(all, c)
+	}
+	connsMu.Unlock()
+
+	out := make([]Health, len(all))
+	var wg sync.WaitGroup
+	for i, c := range all {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			cctx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+			out[i] = c.check(cctx)
+		}()
+	}
+	wg.Wait()
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
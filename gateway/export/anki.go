@@ -0,0 +1,351 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha1"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Anki 2.1 旧版（schema 11）集合的表结构，.apkg 中的 collection.anki2 需与之一致，导入时由 Anki 自行升级
+const (
+	ankiColSQL = "CREATE TABLE col (id integer primary key, crt integer not null, mod integer not null, " +
+		"scm integer not null, ver integer not null, dty integer not null, usn integer not null, ls integer not null, " +
+		"conf text not null, models text not null, decks text not null, dconf text not null, tags text not null)"
+	ankiNotesSQL = "CREATE TABLE notes (id integer primary key, guid text not null, mid integer not null, " +
+		"mod integer not null, usn integer not null, tags text not null, flds text not null, sfld integer not null, " +
+		"csum integer not null, flags integer not null, data text not null)"
+	ankiCardsSQL = "CREATE TABLE cards (id integer primary key, nid integer not null, did integer not null, " +
+		"ord integer not null, mod integer not null, usn integer not null, type integer not null, queue integer not null, " +
+		"due integer not null, ivl integer not null, factor integer not null, reps integer not null, lapses integer not null, " +
+		"left integer not null, odue integer not null, odid integer not null, flags integer not null, data text not null)"
+	ankiRevlogSQL = "CREATE TABLE revlog (id integer primary key, cid integer not null, usn integer not null, " +
+		"ease integer not null, ivl integer not null, lastIvl integer not null, factor integer not null, " +
+		"time integer not null, type integer not null)"
+	ankiGravesSQL = "CREATE TABLE graves (usn integer not null, oid integer not null, type integer not null)"
+)
+
+// 卡片与复习记录类型
+const (
+	ankiCardNew    = 0
+	ankiCardReview = 2
+
+	ankiRevlogLearn   = 0
+	ankiRevlogReview  = 1
+	ankiRevlogRelearn = 2
+
+	ankiEaseAgain = 1
+	ankiEaseGood  = 3
+)
+
+// 按 Anki 默认选项回放作答记录时使用的调度参数
+const (
+	ankiStartingEase     = 2500
+	ankiMinEase          = 1300
+	ankiLapseEasePenalty = 200
+	ankiGraduatingIvl    = 1
+	ankiMaxIvl           = 36500
+	ankiMaxAnswerMs      = 60000
+	// 未毕业卡片答错后的重学间隔（秒），复习记录中以负数表示
+	ankiLearnStepSecs = 600
+)
+
+// 固定的笔记类型 ID，重复导入时 Anki 复用同一笔记类型；笔记 guid 由题目 ID 派生，重复导入时更新而非新增
+const ankiModelID = 1718000000000
+
+const ankiBase91 = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789!#$%&()*+,-./:;<=>?@[]^_`{|}~"
+
+// ankiSchedule 回放作答记录后的卡片调度状态
+type ankiSchedule struct {
+	graduated  bool
+	ivl        int64 // 天
+	factor     int64 // 千分比
+	reps       int64
+	lapses     int64
+	lastReview time.Time
+	revlog     []ankiReview
+}
+
+type ankiReview struct {
+	at      time.Time
+	ease    int64
+	ivl     int64
+	lastIvl int64
+	factor  int64
+	timeMs  int64
+	typ     int64
+}
+
+// replayReviews 按 Anki SM-2 默认参数依次回放作答记录：答对记为"Good"，答错记为"Again"。
+// 首次答对即毕业为复习卡；复习卡提前作答（未到期）答对不延长间隔，答错则间隔重置为 1 天并降低难度系数
+func replayReviews(reviews []Review) ankiSchedule {
+	s := ankiSchedule{factor: ankiStartingEase}
+	for _, r := range reviews {
+		entry := ankiReview{at: r.At, lastIvl: s.ivl, timeMs: r.TimeSpentMs}
+		if entry.timeMs > ankiMaxAnswerMs {
+			entry.timeMs = ankiMaxAnswerMs
+		}
+		if entry.timeMs < 0 {
+			entry.timeMs = 0
+		}
+		s.reps++
+		switch {
+		case !s.graduated && r.Correct:
+			s.graduated = true
+			s.ivl = ankiGraduatingIvl
+			entry.ease, entry.typ, entry.ivl = ankiEaseGood, ankiRevlogLearn, s.ivl
+		case !s.graduated:
+			entry.ease, entry.typ, entry.ivl = ankiEaseAgain, ankiRevlogLearn, -ankiLearnStepSecs
+		case r.Correct:
+			elapsed := int64(r.At.Sub(s.lastReview) / (24 * time.Hour))
+			if elapsed >= s.ivl {
+				delay := elapsed - s.ivl
+				next := (s.ivl + delay/2) * s.factor / 1000
+				if next < s.ivl+1 {
+					next = s.ivl + 1
+				}
+				s.ivl = next
+			}
+			entry.ease, entry.typ = ankiEaseGood, ankiRevlogReview
+		default:
+			s.lapses++
+			s.factor -= ankiLapseEasePenalty
+			if s.factor < ankiMinEase {
+				s.factor = ankiMinEase
+			}
+			s.ivl = 1
+			entry.ease, entry.typ = ankiEaseAgain, ankiRevlogRelearn
+		}
+		if s.ivl > ankiMaxIvl {
+			s.ivl = ankiMaxIvl
+		}
+		if entry.ivl == 0 {
+			entry.ivl = s.ivl
+		}
+		entry.factor = s.factor
+		s.lastReview = r.At
+		s.revlog = append(s.revlog, entry)
+	}
+	return s
+}
+
+// RenderAnki 将牌组打包为 Anki .apkg：每张卡片为一条"正面/背面"笔记，知识点与题型作为标签；
+// 已有作答记录的卡片回放为复习进度（间隔、到期日、难度系数）并写入复习历史，导入后按原进度继续复习
+func RenderAnki(d *Deck) ([]byte, error) {
+	now := d.ExportedAt
+	// 集合创建日需早于全部复习记录，卡片到期日以距该日的天数表示
+	crt := dayStart(now)
+	for _, rs := range d.Reviews {
+		if len(rs) > 0 && rs[0].At.Before(crt) {
+			crt = dayStart(rs[0].At)
+		}
+	}
+	dayOf := func(t time.Time) int64 { return int64(t.Sub(crt) / (24 * time.Hour)) }
+
+	deckID := now.UnixMilli()
+	mod := now.Unix()
+
+	db := &sqliteDB{}
+	col := db.table("col", ankiColSQL)
+	notes := db.table("notes", ankiNotesSQL)
+	cards := db.table("cards", ankiCardsSQL)
+	revlog := db.table("revlog", ankiRevlogSQL)
+	db.table("graves", ankiGravesSQL)
+	db.index("ix_notes_usn", notes, "CREATE INDEX ix_notes_usn on notes (usn)", 4)
+	db.index("ix_cards_usn", cards, "CREATE INDEX ix_cards_usn on cards (usn)", 5)
+	db.index("ix_revlog_usn", revlog, "CREATE INDEX ix_revlog_usn on revlog (usn)", 2)
+	db.index("ix_cards_nid", cards, "CREATE INDEX ix_cards_nid on cards (nid)", 1)
+	db.index("ix_cards_sched", cards, "CREATE INDEX ix_cards_sched on cards (did, queue, due)", 2, 7, 8)
+	db.index("ix_revlog_cid", revlog, "CREATE INDEX ix_revlog_cid on revlog (cid)", 1)
+	db.index("ix_notes_csum", notes, "CREATE INDEX ix_notes_csum on notes (csum)", 8)
+
+	revlogIDs := map[int64]bool{}
+	for i, card := range d.Cards {
+		id := now.UnixMilli() + int64(i)
+		front := ankiField(card.Front)
+		sortField := strings.Join(strings.Fields(card.Front), " ")
+		notes.insert(id, nil, ankiGUID(card.QuestionID), int64(ankiModelID), mod, int64(-1),
+			ankiTags(card), front+"\x1f"+ankiField(card.Back), sortField, ankiChecksum(sortField), int64(0), "")
+
+		s := replayReviews(d.Reviews[card.QuestionID])
+		typ, queue, due, factor := int64(ankiCardNew), int64(ankiCardNew), int64(i+1), int64(0)
+		if s.graduated {
+			typ, queue, due, factor = ankiCardReview, ankiCardReview, dayOf(s.lastReview)+s.ivl, s.factor
+		}
+		cards.insert(id, nil, id, deckID, int64(0), mod, int64(-1), typ, queue, due,
+			s.ivl, factor, s.reps, s.lapses, int64(0), int64(0), int64(0), int64(0), "")
+
+		for _, r := range s.revlog {
+			rid := r.at.UnixMilli()
+			for revlogIDs[rid] {
+				rid++
+			}
+			revlogIDs[rid] = true
+			revlog.insert(rid, nil, id, int64(-1), r.ease, r.ivl, r.lastIvl, r.factor, r.timeMs, r.typ)
+		}
+	}
+
+	conf, models, decks, dconf, err := ankiCollectionJSON(d, deckID, mod, len(d.Cards))
+	if err != nil {
+		return nil, err
+	}
+	col.insert(1, nil, crt.Unix(), now.UnixMilli(), now.UnixMilli(), int64(11), int64(0), int64(0), int64(0),
+		conf, models, decks, dconf, "{}")
+
+	collection, err := db.bytes()
+	if err != nil {
+		return nil, fmt.Errorf("build anki collection: %w", err)
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, f := range []struct {
+		name string
+		data []byte
+	}{
+		{"collection.anki2", collection},
+		{"media", []byte("{}")},
+	} {
+		w, err := zw.Create(f.name)
+		if err != nil {
+			return nil, fmt.Errorf("create %s: %w", f.name, err)
+		}
+		if _, err := w.Write(f.data); err != nil {
+			return nil, fmt.Errorf("write %s: %w", f.name, err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("close apkg: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// ankiCollectionJSON 生成 col 表中的集合配置、笔记类型、牌组与牌组选项
+func ankiCollectionJSON(d *Deck, deckID, mod int64, cardCount int) (conf, models, decks, dconf string, err error) {
+	deckName := "ArkStudy::" + strings.ReplaceAll(strings.TrimSpace(d.Material.Title), "::", " - ")
+	deck := func(id int64, name string) map[string]interface{} {
+		return map[string]interface{}{
+			"id": id, "name": name, "mod": mod, "usn": -1, "desc": "", "dyn": 0, "conf": 1,
+			"collapsed": false, "browserCollapsed": false, "extendNew": 0, "extendRev": 0,
+			"newToday": []int{0, 0}, "revToday": []int{0, 0}, "lrnToday": []int{0, 0}, "timeToday": []int{0, 0},
+		}
+	}
+	field := func(name string, ord int) map[string]interface{} {
+		return map[string]interface{}{"name": name, "ord": ord, "sticky": false, "rtl": false, "font": "Arial", "size": 20, "media": []string{}}
+	}
+
+	values := []interface{}{
+		map[string]interface{}{
+			"nextPos": cardCount + 1, "estTimes": true, "activeDecks": []int64{deckID}, "sortType": "noteFld",
+			"timeLim": 0, "sortBackwards": false, "addToCur": true, "curDeck": deckID, "newBottom": true,
+			"newSpread": 0, "dueCounts": true, "curModel": ankiModelID, "collapseTime": 1200,
+		},
+		map[string]interface{}{
+			strconv.FormatInt(ankiModelID, 10): map[string]interface{}{
+				"id": ankiModelID, "name": "ArkStudy 问答", "type": 0, "mod": mod, "usn": -1, "sortf": 0, "did": deckID,
+				"tmpls": []map[string]interface{}{{
+					"name": "Card 1", "ord": 0, "did": nil, "bqfmt": "", "bafmt": "",
+					"qfmt": "{{Front}}",
+					"afmt": "{{FrontSide}}\n\n<hr id=answer>\n\n{{Back}}",
+				}},
+				"flds": []map[string]interface{}{field("Front", 0), field("Back", 1)},
+				"css":  ".card {\n font-family: arial;\n font-size: 20px;\n text-align: left;\n color: black;\n background-color: white;\n}\n",
+				"latexPre": "\\documentclass[12pt]{article}\n\\special{papersize=3in,5in}\n\\usepackage[utf8]{inputenc}\n" +
+					"\\usepackage{amssymb,amsmath}\n\\pagestyle{empty}\n\\setlength{\\parindent}{0in}\n\\begin{document}\n",
+				"latexPost": "\\end{document}",
+				"tags":      []string{},
+				"vers":      []int{},
+				"req":       []interface{}{[]interface{}{0, "any", []int{0}}},
+			},
+		},
+		map[string]interface{}{
+			"1":                           deck(1, "Default"),
+			strconv.FormatInt(deckID, 10): deck(deckID, deckName),
+		},
+		map[string]interface{}{
+			"1": map[string]interface{}{
+				"id": 1, "name": "Default", "mod": 0, "usn": 0, "maxTaken": 60, "autoplay": true, "timer": 0,
+				"replayq": true, "dyn": false,
+				"new": map[string]interface{}{
+					"bury": false, "delays": []int{1, 10}, "initialFactor": ankiStartingEase,
+					"ints": []int{ankiGraduatingIvl, 4, 0}, "order": 1, "perDay": 20,
+				},
+				"rev": map[string]interface{}{
+					"bury": false, "ease4": 1.3, "ivlFct": 1, "maxIvl": ankiMaxIvl, "perDay": 200, "hardFactor": 1.2,
+				},
+				"lapse": map[string]interface{}{
+					"delays": []int{10}, "leechAction": 1, "leechFails": 8, "minInt": 1, "mult": 0,
+				},
+			},
+		},
+	}
+	out := make([]string, len(values))
+	for i, v := range values {
+		raw, err := json.Marshal(v)
+		if err != nil {
+			return "", "", "", "", fmt.Errorf("marshal anki collection config: %w", err)
+		}
+		out[i] = string(raw)
+	}
+	return out[0], out[1], out[2], out[3], nil
+}
+
+// ankiField 将纯文本转为 Anki 字段 HTML
+func ankiField(s string) string {
+	return strings.ReplaceAll(html.EscapeString(strings.TrimSpace(s)), "\n", "<br>")
+}
+
+// ankiTags 以知识点与题型为标签，Anki 标签以空格分隔，标签内的空白替换为下划线
+func ankiTags(card Flashcard) string {
+	tags := []string{"arkstudy", questionTypeLabel(card.Type)}
+	seen := map[string]bool{}
+	for _, t := range tags {
+		seen[t] = true
+	}
+	for _, kp := range card.Tags {
+		tag := strings.Join(strings.Fields(kp), "_")
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		tags = append(tags, tag)
+	}
+	return " " + strings.Join(tags, " ") + " "
+}
+
+// ankiGUID 由题目 ID 派生稳定的笔记 guid（base91 编码的 64 位哈希）
+func ankiGUID(questionID string) string {
+	sum := sha1.Sum([]byte("arkstudy:" + questionID))
+	v := binary.BigEndian.Uint64(sum[:8])
+	var b []byte
+	for v > 0 {
+		b = append(b, ankiBase91[v%91])
+		v /= 91
+	}
+	if len(b) == 0 {
+		return string(ankiBase91[0])
+	}
+	for i, j := 0, len(b)-1; i < j; i, j = i+1, j-1 {
+		b[i], b[j] = b[j], b[i]
+	}
+	return string(b)
+}
+
+// ankiChecksum 为首字段 SHA1 的前 8 位十六进制，Anki 据此检测重复笔记
+func ankiChecksum(sortField string) int64 {
+	sum := sha1.Sum([]byte(sortField))
+	v, _ := strconv.ParseInt(hex.EncodeToString(sum[:4]), 16, 64)
+	return v
+}
+
+func dayStart(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
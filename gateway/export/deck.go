@@ -0,0 +1,130 @@
+package export
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	materialpb "github.com/RigelNana/arkstudy/proto/material"
+	quizpb "github.com/RigelNana/arkstudy/proto/quiz"
+)
+
+const (
+	// 回放复习记录时最多读取的答题历史条数（按时间倒序）
+	maxReviewHistory = 5000
+	historyPageSize  = 200
+)
+
+// Review 一次作答，对应 Anki 中的一次复习
+type Review struct {
+	At          time.Time
+	Correct     bool
+	TimeSpentMs int64
+}
+
+// Deck 单份资料的复习牌组：记忆卡片与客观题，附当前用户对每道题的作答记录
+type Deck struct {
+	Material   *materialpb.MaterialInfo
+	Cards      []Flashcard
+	Reviews    map[string][]Review // 按题目 ID，时间正序
+	ExportedAt time.Time
+}
+
+// Deck 收集资料的复习牌组。资料不存在或无权访问时返回 ErrMaterialNotFound；
+// 作答记录获取失败时卡片仍然导出，但不带复习进度
+func (c *Collector) Deck(ctx context.Context, userID, materialID string) (*Deck, error) {
+	material, err := c.material(ctx, userID, materialID)
+	if err != nil {
+		return nil, err
+	}
+	questions, err := c.listQuestions(ctx, userID, materialID)
+	if err != nil {
+		return nil, fmt.Errorf("list questions: %w", err)
+	}
+
+	deck := &Deck{Material: material, ExportedAt: time.Now().UTC()}
+	ids := map[string]bool{}
+	for _, q := range questions {
+		if card, ok := CardFromQuestion(q); ok {
+			deck.Cards = append(deck.Cards, card)
+			ids[card.QuestionID] = true
+		}
+	}
+	if len(deck.Cards) == 0 {
+		return deck, nil
+	}
+
+	reviews, err := c.listReviews(ctx, userID, ids)
+	if err != nil {
+		log.Printf("Export deck: list answer history of user %s failed, exporting without review progress: %v", userID, err)
+	}
+	deck.Reviews = reviews
+	return deck, nil
+}
+
+// CardFromQuestion 将题目转换为复习卡片：填空、判断、简答题同记忆卡片，
+// 选择题正面附选项、背面为正确选项；论述题答案开放，不制卡
+func CardFromQuestion(q *quizpb.Question) (Flashcard, bool) {
+	if card, ok := FlashcardFromQuestion(q); ok {
+		return card, true
+	}
+	if q.Type != quizpb.QuestionType_MULTIPLE_CHOICE || len(q.Options) == 0 || strings.TrimSpace(q.CorrectAnswer) == "" {
+		return Flashcard{}, false
+	}
+	lines := []string{strings.TrimSpace(q.Content)}
+	for i, opt := range q.Options {
+		lines = append(lines, optionText(i, opt))
+	}
+	back := strings.TrimSpace(q.CorrectAnswer)
+	if q.Explanation != "" {
+		back += "\n\n" + strings.TrimSpace(q.Explanation)
+	}
+	return Flashcard{
+		QuestionID: q.QuestionId,
+		Type:       q.Type,
+		Front:      strings.Join(lines, "\n"),
+		Back:       back,
+		Tags:       q.KnowledgePoints,
+	}, true
+}
+
+// listReviews 读取用户最近的答题历史，保留属于 questionIDs 的记录并按时间正序排列
+func (c *Collector) listReviews(ctx context.Context, userID string, questionIDs map[string]bool) (map[string][]Review, error) {
+	reviews := map[string][]Review{}
+	read := 0
+	for page := int32(1); read < maxReviewHistory; page++ {
+		qctx, cancel := context.WithTimeout(ctx, serviceTimeout)
+		resp, err := c.quiz.GetUserQuizHistory(qctx, &quizpb.GetUserQuizHistoryRequest{
+			UserId:   userID,
+			Page:     page,
+			PageSize: historyPageSize,
+		})
+		cancel()
+		if err != nil {
+			return reviews, err
+		}
+		if !resp.Success {
+			return reviews, fmt.Errorf("%s", resp.Message)
+		}
+		for _, a := range resp.Answers {
+			if !questionIDs[a.QuestionId] {
+				continue
+			}
+			if a.AnsweredAt == nil {
+				continue
+			}
+			reviews[a.QuestionId] = append(reviews[a.QuestionId], Review{At: a.AnsweredAt.AsTime(), Correct: a.IsCorrect, TimeSpentMs: a.TimeSpentMs})
+		}
+		read += len(resp.Answers)
+		if len(resp.Answers) < historyPageSize || int32(read) >= resp.Total {
+			break
+		}
+	}
+	for _, rs := range reviews {
+		sort.Slice(rs, func(i, j int) bool { return rs[i].At.Before(rs[j].At) })
+	}
+	return reviews, nil
+}
@@ -0,0 +1,317 @@
+// Package export 汇总单份资料的学习内容（摘要、核心概念、记忆卡片与练习题），
+// 从 material / asr / quiz / llm 各服务收集后渲染为可下载的 Markdown 或 PDF 学习笔记，
+// 记忆卡片与客观题另可连同作答记录打包为 Anki 牌组，音视频转写可导出为 SRT / WebVTT 字幕，
+// 课程合集可打包为移动端离线学习用的 JSON 压缩包。
+package export
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	asrpb "github.com/RigelNana/arkstudy/proto/asr"
+	llmpb "github.com/RigelNana/arkstudy/proto/llm"
+	materialpb "github.com/RigelNana/arkstudy/proto/material"
+	quizpb "github.com/RigelNana/arkstudy/proto/quiz"
+)
+
+const (
+	// 单份笔记最多收录的题目数
+	maxExportQuestions = 500
+	quizPageSize       = 100
+	// 核心概念最多列出的条数
+	maxKeyConcepts = 30
+	// 生成摘要的超时时间，其余服务调用共用 serviceTimeout
+	summaryTimeout = 90 * time.Second
+	serviceTimeout = 15 * time.Second
+)
+
+// 摘要来源
+const (
+	SummarySourceChapters = "chapters" // 音视频资料的章节摘要
+	SummarySourceLLM      = "llm"      // 由 llm-service 基于资料分片生成
+)
+
+// ErrMaterialNotFound 资料不存在或不属于当前用户
+var ErrMaterialNotFound = fmt.Errorf("material not found")
+
+// Chapter 音视频资料的章节及其摘要
+type Chapter struct {
+	Title     string
+	Summary   string
+	StartTime float32
+	EndTime   float32
+}
+
+// Concept 核心概念，取自题目关联的知识点，按涉及题目数排序
+type Concept struct {
+	Name      string
+	Questions int
+}
+
+// Flashcard 记忆卡片，正面为提示，背面为答案
+type Flashcard struct {
+	QuestionID string
+	Type       quizpb.QuestionType
+	Front      string
+	Back       string
+	Tags       []string
+}
+
+// Notes 单份资料的学习笔记
+type Notes struct {
+	Material      *materialpb.MaterialInfo
+	Summary       string
+	SummarySource string
+	Chapters      []Chapter
+	KeyConcepts   []Concept
+	Flashcards    []Flashcard
+	// 不适合做卡片的题目（选择题、论述题），附参考答案
+	Questions  []*quizpb.Question
+	ExportedAt time.Time
+	// 调用 llm-service 生成摘要时返回的元数据（含 total_tokens，用于配额计量）
+	LLMMetadata map[string]string
+	// 部分内容收集失败时的说明，笔记仍然导出
+	Warnings []string
+}
+
+// Options 导出选项
+type Options struct {
+	// 为 false 时不调用 llm-service 生成摘要（音视频资料仍使用已有章节摘要）
+	Summary bool
+}
+
+// Collector 从各服务收集笔记内容
+type Collector struct {
+	materials materialpb.MaterialServiceClient
+	llm       llmpb.LLMServiceClient
+	quiz      quizpb.QuizServiceClient
+	asr       asrpb.ASRServiceClient
+}
+
+func NewCollector(materials materialpb.MaterialServiceClient, llm llmpb.LLMServiceClient, quiz quizpb.QuizServiceClient, asr asrpb.ASRServiceClient) *Collector {
+	return &Collector{materials: materials, llm: llm, quiz: quiz, asr: asr}
+}
+
+// Collect 收集资料的笔记内容。资料不存在或无权访问时返回 ErrMaterialNotFound；
+// 摘要、章节与题目任一收集失败只记入 Warnings，不影响其余内容导出
+func (c *Collector) Collect(ctx context.Context, userID, materialID string, opts Options) (*Notes, error) {
+	material, err := c.material(ctx, userID, materialID)
+	if err != nil {
+		return nil, err
+	}
+	notes := &Notes{Material: material, ExportedAt: time.Now().UTC()}
+
+	questions, err := c.listQuestions(ctx, userID, materialID)
+	if err != nil {
+		log.Printf("Export notes: list questions of material %s failed: %v", materialID, err)
+		notes.Warnings = append(notes.Warnings, "题目获取失败："+err.Error())
+	}
+	notes.KeyConcepts = keyConcepts(questions)
+	for _, q := range questions {
+		if card, ok := FlashcardFromQuestion(q); ok {
+			notes.Flashcards = append(notes.Flashcards, card)
+		} else {
+			notes.Questions = append(notes.Questions, q)
+		}
+	}
+
+	if isMedia(material.FileType) {
+		chapters, err := c.chapters(ctx, userID, materialID)
+		if err != nil {
+			log.Printf("Export notes: get chapters of material %s failed: %v", materialID, err)
+			notes.Warnings = append(notes.Warnings, "章节获取失败："+err.Error())
+		}
+		notes.Chapters = chapters
+		if len(chapters) > 0 {
+			notes.SummarySource = SummarySourceChapters
+			return notes, nil
+		}
+	}
+	if opts.Summary {
+		summary, metadata, err := c.summarize(ctx, userID, material)
+		if err != nil {
+			log.Printf("Export notes: summarize material %s failed: %v", materialID, err)
+			notes.Warnings = append(notes.Warnings, "摘要生成失败："+err.Error())
+		} else if summary != "" {
+			notes.Summary = summary
+			notes.SummarySource = SummarySourceLLM
+		}
+		notes.LLMMetadata = metadata
+	}
+	return notes, nil
+}
+
+// material 获取资料并校验归属
+func (c *Collector) material(ctx context.Context, userID, materialID string) (*materialpb.MaterialInfo, error) {
+	mctx, cancel := context.WithTimeout(ctx, serviceTimeout)
+	defer cancel()
+	resp, err := c.materials.GetMaterial(mctx, &materialpb.GetMaterialRequest{MaterialId: materialID, UserId: userID})
+	if err != nil {
+		return nil, fmt.Errorf("get material: %w", err)
+	}
+	if !resp.Found || resp.Material == nil {
+		return nil, ErrMaterialNotFound
+	}
+	return resp.Material, nil
+}
+
+// listQuestions 分页拉取资料下当前用户的全部题目，最多 maxExportQuestions 道
+func (c *Collector) listQuestions(ctx context.Context, userID, materialID string) ([]*quizpb.Question, error) {
+	var questions []*quizpb.Question
+	for page := int32(1); len(questions) < maxExportQuestions; page++ {
+		qctx, cancel := context.WithTimeout(ctx, serviceTimeout)
+		resp, err := c.quiz.ListQuizzes(qctx, &quizpb.ListQuizzesRequest{
+			UserId:     userID,
+			MaterialId: materialID,
+			Page:       page,
+			PageSize:   quizPageSize,
+		})
+		cancel()
+		if err != nil {
+			return questions, err
+		}
+		if !resp.Success {
+			return questions, fmt.Errorf("%s", resp.Message)
+		}
+		questions = append(questions, resp.Questions...)
+		if len(resp.Questions) < quizPageSize || int32(len(questions)) >= resp.Total {
+			break
+		}
+	}
+	if len(questions) > maxExportQuestions {
+		questions = questions[:maxExportQuestions]
+	}
+	return questions, nil
+}
+
+func (c *Collector) chapters(ctx context.Context, userID, materialID string) ([]Chapter, error) {
+	actx, cancel := context.WithTimeout(ctx, serviceTimeout)
+	defer cancel()
+	resp, err := c.asr.GetChapters(actx, &asrpb.GetChaptersRequest{MaterialId: materialID, UserId: userID})
+	if err != nil {
+		return nil, err
+	}
+	if !resp.Success {
+		return nil, fmt.Errorf("%s", resp.Message)
+	}
+	chapters := make([]Chapter, 0, len(resp.Chapters))
+	for _, ch := range resp.Chapters {
+		chapters = append(chapters, Chapter{
+			Title:     ch.Title,
+			Summary:   ch.Summary,
+			StartTime: ch.StartTime,
+			EndTime:   ch.EndTime,
+		})
+	}
+	return chapters, nil
+}
+
+// summarize 请求 llm-service 基于资料分片生成结构化摘要
+func (c *Collector) summarize(ctx context.Context, userID string, material *materialpb.MaterialInfo) (string, map[string]string, error) {
+	sctx, cancel := context.WithTimeout(ctx, summaryTimeout)
+	defer cancel()
+	resp, err := c.llm.AskQuestion(sctx, &llmpb.QuestionRequest{
+		Question:    fmt.Sprintf("请为学习资料《%s》撰写一份学习摘要：先用一段话概括主要内容，再按要点列出关键结论。只依据资料内容作答。", material.Title),
+		UserId:      userID,
+		MaterialIds: []string{material.Id},
+	})
+	if err != nil {
+		return "", nil, err
+	}
+	if resp.Metadata["moderation"] == "blocked" {
+		return "", resp.Metadata, fmt.Errorf("summary blocked by moderation")
+	}
+	if resp.Metadata["grounding"] == "not_found" {
+		return "", resp.Metadata, fmt.Errorf("material has no indexed content to summarize")
+	}
+	return strings.TrimSpace(resp.Answer), resp.Metadata, nil
+}
+
+// FlashcardFromQuestion 将答案简短明确的题目（填空、判断、简答）转换为记忆卡片
+func FlashcardFromQuestion(q *quizpb.Question) (Flashcard, bool) {
+	var back string
+	switch q.Type {
+	case quizpb.QuestionType_FILL_BLANK:
+		back = q.CorrectAnswer
+		if len(q.Parts) > 0 {
+			answers := make([]string, 0, len(q.Parts))
+			for _, p := range q.Parts {
+				if p.Label != "" {
+					answers = append(answers, p.Label+"："+p.CorrectAnswer)
+				} else {
+					answers = append(answers, p.CorrectAnswer)
+				}
+			}
+			back = strings.Join(answers, "；")
+		}
+	case quizpb.QuestionType_TRUE_FALSE:
+		back = TrueFalseLabel(q.CorrectAnswer)
+	case quizpb.QuestionType_SHORT_ANSWER:
+		back = q.CorrectAnswer
+	default:
+		return Flashcard{}, false
+	}
+	back = strings.TrimSpace(back)
+	if strings.TrimSpace(q.Content) == "" || back == "" {
+		return Flashcard{}, false
+	}
+	if q.Explanation != "" {
+		back += "\n\n" + strings.TrimSpace(q.Explanation)
+	}
+	return Flashcard{
+		QuestionID: q.QuestionId,
+		Type:       q.Type,
+		Front:      strings.TrimSpace(q.Content),
+		Back:       back,
+		Tags:       q.KnowledgePoints,
+	}, true
+}
+
+// TrueFalseLabel 将判断题答案规范为"正确"/"错误"
+func TrueFalseLabel(answer string) string {
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "true", "t", "yes", "对", "正确", "√", "1":
+		return "正确"
+	case "false", "f", "no", "错", "错误", "×", "0":
+		return "错误"
+	}
+	return answer
+}
+
+// keyConcepts 统计题目关联的知识点，按涉及题目数降序
+func keyConcepts(questions []*quizpb.Question) []Concept {
+	counts := map[string]int{}
+	for _, q := range questions {
+		seen := map[string]bool{}
+		for _, kp := range q.KnowledgePoints {
+			kp = strings.TrimSpace(kp)
+			if kp == "" || seen[kp] {
+				continue
+			}
+			seen[kp] = true
+			counts[kp]++
+		}
+	}
+	concepts := make([]Concept, 0, len(counts))
+	for name, n := range counts {
+		concepts = append(concepts, Concept{Name: name, Questions: n})
+	}
+	sort.Slice(concepts, func(i, j int) bool {
+		if concepts[i].Questions != concepts[j].Questions {
+			return concepts[i].Questions > concepts[j].Questions
+		}
+		return concepts[i].Name < concepts[j].Name
+	})
+	if len(concepts) > maxKeyConcepts {
+		concepts = concepts[:maxKeyConcepts]
+	}
+	return concepts
+}
+
+func isMedia(fileType string) bool {
+	return fileType == "video" || fileType == "audio"
+}
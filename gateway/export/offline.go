@@ -0,0 +1,313 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/RigelNana/arkstudy/pkg/locale"
+	materialpb "github.com/RigelNana/arkstudy/proto/material"
+	quizpb "github.com/RigelNana/arkstudy/proto/quiz"
+)
+
+const (
+	// 离线包格式版本，客户端据此判断能否解析
+	OfflineBundleVersion = 1
+	// 单个离线包最多收录的资料数
+	maxOfflineMaterials = 200
+)
+
+// SummarySourceAnalysis 已完成的 LLM 分析结果（处理类型 LLM_ANALYSIS）
+const SummarySourceAnalysis = "analysis"
+
+// OfflineMaterial 离线包中单份资料的内容
+type OfflineMaterial struct {
+	Material      *materialpb.MaterialInfo
+	Summary       string
+	SummarySource string
+	Chapters      []Chapter
+	Flashcards    []Flashcard
+	Questions     []*quizpb.Question
+}
+
+// OfflineBundle 课程（合集资料）的离线学习包：资料列表、摘要、记忆卡片与题目
+type OfflineBundle struct {
+	Collection *materialpb.MaterialInfo
+	Materials  []OfflineMaterial
+	ExportedAt time.Time
+	// 部分内容收集失败时的说明，离线包仍然生成
+	Warnings []string
+}
+
+// OfflineBundle 收集合集（bundle 资料）中全部子资料的离线内容，非合集资料只包含其自身。
+// 摘要只使用已有的章节摘要或 LLM 分析结果，不会调用 LLM 生成；
+// 资料不存在或无权访问时返回 ErrMaterialNotFound，单份资料的内容收集失败只记入 Warnings；
+// 导出时间使用 ctx 中 Locale 的时区
+func (c *Collector) OfflineBundle(ctx context.Context, userID, collectionID string) (*OfflineBundle, error) {
+	collection, err := c.material(ctx, userID, collectionID)
+	if err != nil {
+		return nil, err
+	}
+	bundle := &OfflineBundle{Collection: collection, ExportedAt: time.Now().In(locale.FromContext(ctx).Location())}
+
+	materials := []*materialpb.MaterialInfo{collection}
+	if collection.FileType == "bundle" {
+		materials, err = c.children(ctx, userID, collectionID)
+		if err != nil {
+			return nil, fmt.Errorf("list collection materials: %w", err)
+		}
+		if len(materials) > maxOfflineMaterials {
+			bundle.Warnings = append(bundle.Warnings, fmt.Sprintf("合集包含 %d 份资料，只收录前 %d 份", len(materials), maxOfflineMaterials))
+			materials = materials[:maxOfflineMaterials]
+		}
+	}
+
+	for _, m := range materials {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		bundle.Materials = append(bundle.Materials, c.offlineMaterial(ctx, userID, m, &bundle.Warnings))
+	}
+	return bundle, nil
+}
+
+// offlineMaterial 收集单份资料的题目、卡片与摘要，失败的部分记入 warnings
+func (c *Collector) offlineMaterial(ctx context.Context, userID string, m *materialpb.MaterialInfo, warnings *[]string) OfflineMaterial {
+	out := OfflineMaterial{Material: m}
+	warn := func(what string, err error) {
+		log.Printf("Export offline bundle: %s of material %s failed: %v", what, m.Id, err)
+		*warnings = append(*warnings, fmt.Sprintf("《%s》%s失败：%v", m.Title, what, err))
+	}
+
+	questions, err := c.listQuestions(ctx, userID, m.Id)
+	if err != nil {
+		warn("题目获取", err)
+	}
+	out.Questions = questions
+	for _, q := range questions {
+		if card, ok := FlashcardFromQuestion(q); ok {
+			out.Flashcards = append(out.Flashcards, card)
+		}
+	}
+
+	if isMedia(m.FileType) {
+		chapters, err := c.chapters(ctx, userID, m.Id)
+		if err != nil {
+			warn("章节获取", err)
+		}
+		out.Chapters = chapters
+		if len(chapters) > 0 {
+			out.SummarySource = SummarySourceChapters
+			return out
+		}
+	}
+	summary, err := c.analysis(ctx, userID, m.Id)
+	if err != nil {
+		warn("摘要获取", err)
+	} else if summary != "" {
+		out.Summary, out.SummarySource = summary, SummarySourceAnalysis
+	}
+	return out
+}
+
+// children 列出合集的子资料
+func (c *Collector) children(ctx context.Context, userID, collectionID string) ([]*materialpb.MaterialInfo, error) {
+	mctx, cancel := context.WithTimeout(ctx, serviceTimeout)
+	defer cancel()
+	resp, err := c.materials.ListChildMaterials(mctx, &materialpb.ListChildMaterialsRequest{MaterialId: collectionID, UserId: userID})
+	if err != nil {
+		return nil, err
+	}
+	if !resp.Success {
+		return nil, fmt.Errorf("%s", resp.Message)
+	}
+	return resp.Materials, nil
+}
+
+// analysis 返回已完成的 LLM 分析结果，没有时为空串
+func (c *Collector) analysis(ctx context.Context, userID, materialID string) (string, error) {
+	mctx, cancel := context.WithTimeout(ctx, serviceTimeout)
+	defer cancel()
+	resp, err := c.materials.GetProcessingResult(mctx, &materialpb.GetProcessingResultRequest{
+		MaterialId: materialID,
+		UserId:     userID,
+		Type:       materialpb.ProcessingType_LLM_ANALYSIS,
+	})
+	if err != nil {
+		return "", err
+	}
+	if !resp.Found || resp.Result == nil || resp.Result.Status != materialpb.ProcessingStatus_COMPLETED {
+		return "", nil
+	}
+	return strings.TrimSpace(resp.Result.Content), nil
+}
+
+// 离线包内各 JSON 文件的结构，字段名与 REST 接口一致，时间为 UTC 的 RFC3339 字符串
+type (
+	offlineManifest struct {
+		Version      int      `json:"version"`
+		CollectionID string   `json:"collection_id"`
+		Title        string   `json:"title"`
+		ExportedAt   string   `json:"exported_at"`
+		Materials    int      `json:"material_count"`
+		Flashcards   int      `json:"flashcard_count"`
+		Questions    int      `json:"question_count"`
+		Files        []string `json:"files"`
+		Warnings     []string `json:"warnings,omitempty"`
+	}
+	offlineMaterialInfo struct {
+		ID               string  `json:"id"`
+		Title            string  `json:"title"`
+		OriginalFilename string  `json:"original_filename"`
+		FileType         string  `json:"file_type"`
+		SizeBytes        int64   `json:"size_bytes"`
+		Language         string  `json:"language,omitempty"`
+		DurationSeconds  float64 `json:"duration_seconds,omitempty"`
+		CreatedAt        string  `json:"created_at,omitempty"`
+	}
+	offlineSummary struct {
+		MaterialID string           `json:"material_id"`
+		Source     string           `json:"source"`
+		Summary    string           `json:"summary,omitempty"`
+		Chapters   []offlineChapter `json:"chapters,omitempty"`
+	}
+	offlineChapter struct {
+		Title     string  `json:"title"`
+		Summary   string  `json:"summary"`
+		StartTime float32 `json:"start_time"`
+		EndTime   float32 `json:"end_time"`
+	}
+	offlineFlashcard struct {
+		MaterialID string   `json:"material_id"`
+		QuestionID string   `json:"question_id"`
+		Type       string   `json:"type"`
+		Front      string   `json:"front"`
+		Back       string   `json:"back"`
+		Tags       []string `json:"tags,omitempty"`
+	}
+	offlineQuestion struct {
+		MaterialID       string                 `json:"material_id"`
+		QuestionID       string                 `json:"question_id"`
+		Type             string                 `json:"type"`
+		Difficulty       string                 `json:"difficulty"`
+		Content          string                 `json:"content"`
+		Options          []string               `json:"options,omitempty"`
+		CorrectAnswer    string                 `json:"correct_answer"`
+		AnswerAliases    []string               `json:"answer_aliases,omitempty"`
+		NumericTolerance float64                `json:"numeric_tolerance,omitempty"`
+		Parts            []*quizpb.QuestionPart `json:"parts,omitempty"`
+		Explanation      string                 `json:"explanation,omitempty"`
+		KnowledgePoints  []string               `json:"knowledge_points,omitempty"`
+	}
+)
+
+// RenderOfflineBundle 将离线包打包为 zip：manifest.json 描述内容与格式版本，
+// materials.json、summaries.json、flashcards.json、quizzes.json 分别为资料列表、摘要、记忆卡片与题目；
+// 时间均按 ExportedAt 的时区输出
+func RenderOfflineBundle(b *OfflineBundle) ([]byte, error) {
+	materials := make([]offlineMaterialInfo, 0, len(b.Materials))
+	summaries := []offlineSummary{}
+	flashcards := []offlineFlashcard{}
+	questions := []offlineQuestion{}
+	for _, m := range b.Materials {
+		info := offlineMaterialInfo{
+			ID:               m.Material.Id,
+			Title:            m.Material.Title,
+			OriginalFilename: m.Material.OriginalFilename,
+			FileType:         m.Material.FileType,
+			SizeBytes:        m.Material.SizeBytes,
+			Language:         m.Material.Language,
+			DurationSeconds:  m.Material.GetMedia().GetDurationSeconds(),
+		}
+		if m.Material.CreatedAt != nil {
+			info.CreatedAt = m.Material.CreatedAt.AsTime().In(b.ExportedAt.Location()).Format(time.RFC3339)
+		}
+		materials = append(materials, info)
+
+		if m.SummarySource != "" {
+			s := offlineSummary{MaterialID: m.Material.Id, Source: m.SummarySource, Summary: m.Summary}
+			for _, ch := range m.Chapters {
+				s.Chapters = append(s.Chapters, offlineChapter(ch))
+			}
+			summaries = append(summaries, s)
+		}
+		for _, card := range m.Flashcards {
+			flashcards = append(flashcards, offlineFlashcard{
+				MaterialID: m.Material.Id,
+				QuestionID: card.QuestionID,
+				Type:       card.Type.String(),
+				Front:      card.Front,
+				Back:       card.Back,
+				Tags:       card.Tags,
+			})
+		}
+		for _, q := range m.Questions {
+			questions = append(questions, offlineQuestion{
+				MaterialID:       m.Material.Id,
+				QuestionID:       q.QuestionId,
+				Type:             q.Type.String(),
+				Difficulty:       q.Difficulty.String(),
+				Content:          q.Content,
+				Options:          q.Options,
+				CorrectAnswer:    q.CorrectAnswer,
+				AnswerAliases:    q.AnswerAliases,
+				NumericTolerance: q.NumericTolerance,
+				Parts:            q.Parts,
+				Explanation:      q.Explanation,
+				KnowledgePoints:  q.KnowledgePoints,
+			})
+		}
+	}
+
+	files := []struct {
+		name string
+		v    any
+	}{
+		{"materials.json", materials},
+		{"summaries.json", summaries},
+		{"flashcards.json", flashcards},
+		{"quizzes.json", questions},
+	}
+	manifest := offlineManifest{
+		Version:      OfflineBundleVersion,
+		CollectionID: b.Collection.Id,
+		Title:        b.Collection.Title,
+		ExportedAt:   b.ExportedAt.Format(time.RFC3339),
+		Materials:    len(materials),
+		Flashcards:   len(flashcards),
+		Questions:    len(questions),
+		Warnings:     b.Warnings,
+	}
+	for _, f := range files {
+		manifest.Files = append(manifest.Files, f.name)
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	write := func(name string, v any) error {
+		w, err := zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: b.ExportedAt})
+		if err != nil {
+			return err
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	}
+	if err := write("manifest.json", manifest); err != nil {
+		return nil, err
+	}
+	for _, f := range files {
+		if err := write(f.name, f.v); err != nil {
+			return nil, err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,254 @@
+package export
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+)
+
+// PDF 使用阅读器内置的 STSong-Light 中文字体（Adobe-GB1，不嵌入字形），
+// 文本以 UniGB-UTF16-H 编码写入，无需在网关中打包字体文件
+const (
+	pageWidth    = 595.28 // A4
+	pageHeight   = 841.89
+	pageMargin   = 56.0
+	bodyFontSize = 10.5
+	lineSpacing  = 1.6
+)
+
+var headingFontSizes = map[int]float64{1: 18, 2: 14, 3: 12}
+
+// STSong-Light 中 ASCII 可打印字符（CID 1-95）的字宽，其余字符为全角 1000
+var asciiWidths = [95]int{
+	207, 270, 342, 467, 462, 797, 710, 239, 374, 374, 423, 605, 238, 375, 238, 334,
+	462, 462, 462, 462, 462, 462, 462, 462, 462, 462, 238, 238, 605, 605, 605, 344,
+	748, 684, 560, 695, 739, 563, 511, 729, 793, 318, 312, 666, 526, 896, 758, 772,
+	544, 772, 628, 465, 607, 753, 711, 972, 647, 620, 607, 374, 333, 374, 606, 500,
+	239, 417, 503, 427, 529, 415, 264, 444, 518, 241, 230, 495, 228, 793, 527, 524,
+	524, 504, 338, 336, 277, 517, 450, 652, 466, 452, 407, 370, 258, 370, 605,
+}
+
+// RenderPDF 将笔记排版为 A4 PDF，标题按层级放大字号，长行按版心宽度折行并自动分页
+func RenderPDF(n *Notes) ([]byte, error) {
+	w := &pdfWriter{}
+	w.newPage()
+	for _, bl := range n.blocks() {
+		switch bl.Kind {
+		case blockHeading:
+			size := headingFontSizes[bl.Level]
+			if size == 0 {
+				size = bodyFontSize
+			}
+			if !w.atTop() {
+				w.y -= size * 0.6
+			}
+			w.paragraph(bl.Text, size, 0, "", 0)
+			w.y -= 2
+		case blockParagraph:
+			w.paragraph(bl.Label+bl.Text, bodyFontSize, 0, "", 0)
+			w.y -= 4
+		case blockBullet:
+			w.paragraph(bl.Text, bodyFontSize, 12, "- ", 0)
+		case blockNote:
+			w.paragraph(bl.Text, bodyFontSize-1.5, 0, "", 0.45)
+			w.y -= 4
+		}
+	}
+	return w.finish(n.Material.Title, n.ExportedAt.Format("20060102150405"))
+}
+
+type pdfWriter struct {
+	pages []string
+	cur   strings.Builder
+	y     float64
+}
+
+func (w *pdfWriter) newPage() {
+	if w.cur.Len() > 0 || len(w.pages) > 0 {
+		w.pages = append(w.pages, w.cur.String())
+	}
+	w.cur.Reset()
+	w.y = pageHeight - pageMargin
+}
+
+func (w *pdfWriter) atTop() bool {
+	return w.y >= pageHeight-pageMargin
+}
+
+// paragraph 输出一段文本：按原有换行分段，每段按宽度折行；prefix 仅出现在首行，后续行悬挂缩进。
+// gray 为 0 时使用黑色
+func (w *pdfWriter) paragraph(text string, size, indent float64, prefix string, gray float64) {
+	lineHeight := size * lineSpacing
+	maxWidth := pageWidth - 2*pageMargin - indent
+	first := true
+	for _, raw := range strings.Split(text, "\n") {
+		for _, line := range wrapLine(sanitizePDFText(raw), size, maxWidth-textWidth(prefix, size)) {
+			if w.y-lineHeight < pageMargin {
+				w.newPage()
+			}
+			w.y -= lineHeight
+			x := pageMargin + indent
+			if prefix != "" {
+				if first {
+					w.text(prefix, size, x, gray)
+				}
+				x += textWidth(prefix, size)
+			}
+			first = false
+			if line != "" {
+				w.text(line, size, x, gray)
+			}
+		}
+	}
+}
+
+func (w *pdfWriter) text(s string, size, x, gray float64) {
+	if gray > 0 {
+		fmt.Fprintf(&w.cur, "%.2f g ", gray)
+	}
+	fmt.Fprintf(&w.cur, "BT /F1 %.1f Tf %.2f %.2f Td <%s> Tj ET", size, x, w.y, utf16Hex(s))
+	if gray > 0 {
+		w.cur.WriteString(" 0 g")
+	}
+	w.cur.WriteString("\n")
+}
+
+// finish 组装 PDF 对象并写出交叉引用表，页脚页码在此时补上
+func (w *pdfWriter) finish(title, created string) ([]byte, error) {
+	w.pages = append(w.pages, w.cur.String())
+	w.cur.Reset()
+
+	var buf bytes.Buffer
+	var offsets []int
+	object := func(body string) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	const firstPageObj = 7
+	kids := make([]string, len(w.pages))
+	for i := range w.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", firstPageObj+2*i)
+	}
+
+	buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(w.pages)))
+	object("<< /Type /Font /Subtype /Type0 /BaseFont /STSong-Light /Encoding /UniGB-UTF16-H /DescendantFonts [4 0 R] >>")
+	object("<< /Type /Font /Subtype /CIDFontType0 /BaseFont /STSong-Light " +
+		"/CIDSystemInfo << /Registry (Adobe) /Ordering (GB1) /Supplement 4 >> " +
+		"/FontDescriptor 5 0 R /DW 1000 /W [1 [" + joinInts(asciiWidths[:]) + "]] >>")
+	object("<< /Type /FontDescriptor /FontName /STSong-Light /Flags 6 /FontBBox [-25 -254 1000 880] " +
+		"/ItalicAngle 0 /Ascent 880 /Descent -120 /CapHeight 880 /StemV 93 >>")
+	object(fmt.Sprintf("<< /Title <FEFF%s> /Producer (arkstudy gateway) /CreationDate (D:%sZ) >>", utf16Hex(title), created))
+
+	for i, content := range w.pages {
+		footer := fmt.Sprintf("%d / %d", i+1, len(w.pages))
+		content += fmt.Sprintf("0.45 g BT /F1 9.0 Tf %.2f %.2f Td <%s> Tj ET 0 g\n",
+			(pageWidth-textWidth(footer, 9))/2, pageMargin/2, utf16Hex(footer))
+
+		var stream bytes.Buffer
+		zw := zlib.NewWriter(&stream)
+		if _, err := zw.Write([]byte(content)); err != nil {
+			return nil, fmt.Errorf("compress page %d: %w", i+1, err)
+		}
+		if err := zw.Close(); err != nil {
+			return nil, fmt.Errorf("compress page %d: %w", i+1, err)
+		}
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] "+
+			"/Resources << /Font << /F1 3 0 R >> >> /Contents %d 0 R >>", pageWidth, pageHeight, firstPageObj+2*i+1))
+		object(fmt.Sprintf("<< /Length %d /Filter /FlateDecode >>\nstream\n%s\nendstream", stream.Len(), stream.Bytes()))
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R /Info 6 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+	return buf.Bytes(), nil
+}
+
+// wrapLine 按宽度折行：西文优先在空格处断开，中文逐字断开
+func wrapLine(s string, size, maxWidth float64) []string {
+	runes := []rune(s)
+	if len(runes) == 0 {
+		return []string{""}
+	}
+	var lines []string
+	start, lastSpace := 0, -1
+	width := 0.0
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		if r == ' ' {
+			lastSpace = i
+		}
+		width += runeWidth(r, size)
+		if width <= maxWidth || i == start {
+			continue
+		}
+		end := i
+		if lastSpace > start {
+			end = lastSpace
+		}
+		lines = append(lines, strings.TrimRight(string(runes[start:end]), " "))
+		start = end
+		for start < len(runes) && runes[start] == ' ' {
+			start++
+		}
+		lastSpace = -1
+		width = 0
+		i = start - 1
+	}
+	if start < len(runes) {
+		lines = append(lines, string(runes[start:]))
+	}
+	return lines
+}
+
+func textWidth(s string, size float64) float64 {
+	width := 0.0
+	for _, r := range s {
+		width += runeWidth(r, size)
+	}
+	return width
+}
+
+func runeWidth(r rune, size float64) float64 {
+	if r >= 0x20 && r <= 0x7e {
+		return float64(asciiWidths[r-0x20]) * size / 1000
+	}
+	return size
+}
+
+// sanitizePDFText 制表符替换为空格，去掉其余控制字符
+func sanitizePDFText(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '\t' {
+			return ' '
+		}
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, s)
+}
+
+func utf16Hex(s string) string {
+	var b strings.Builder
+	for _, u := range utf16.Encode([]rune(s)) {
+		fmt.Fprintf(&b, "%04X", u)
+	}
+	return b.String()
+}
+
+func joinInts(values []int) string {
+	parts := make([]string, len(values))
+	for i, v := range values {
+		parts[i] = fmt.Sprint(v)
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,173 @@
+package export
+
+import (
+	"fmt"
+	"strings"
+
+	quizpb "github.com/RigelNana/arkstudy/proto/quiz"
+)
+
+// 笔记先展开为与格式无关的段落序列，再分别渲染为 Markdown 与 PDF
+type blockKind int
+
+const (
+	blockHeading blockKind = iota
+	blockParagraph
+	blockBullet
+	blockNote // 说明性文字（导出时间、警告等），Markdown 中以斜体呈现
+)
+
+type block struct {
+	Kind  blockKind
+	Level int    // 标题层级，1 起
+	Label string // 段落前的加粗标签，如"问："
+	Text  string
+}
+
+func (n *Notes) blocks() []block {
+	var out []block
+	heading := func(level int, text string) {
+		out = append(out, block{Kind: blockHeading, Level: level, Text: text})
+	}
+	para := func(label, text string) {
+		out = append(out, block{Kind: blockParagraph, Label: label, Text: text})
+	}
+	bullet := func(text string) {
+		out = append(out, block{Kind: blockBullet, Text: text})
+	}
+
+	heading(1, "学习笔记："+n.Material.Title)
+	out = append(out, block{Kind: blockNote, Text: fmt.Sprintf("资料：%s（%s），导出时间：%s",
+		n.Material.OriginalFilename, n.Material.FileType, n.ExportedAt.Format("2006-01-02 15:04 UTC"))})
+	for _, w := range n.Warnings {
+		out = append(out, block{Kind: blockNote, Text: "注意：" + w})
+	}
+
+	heading(2, "摘要")
+	switch {
+	case len(n.Chapters) > 0:
+		for i, ch := range n.Chapters {
+			heading(3, fmt.Sprintf("%d. %s（%s - %s）", i+1, ch.Title, formatTimestamp(ch.StartTime), formatTimestamp(ch.EndTime)))
+			if ch.Summary != "" {
+				para("", ch.Summary)
+			}
+		}
+	case n.Summary != "":
+		para("", n.Summary)
+		out = append(out, block{Kind: blockNote, Text: "以上摘要由 AI 根据资料内容生成，请对照原文核对"})
+	default:
+		para("", "暂无摘要")
+	}
+
+	heading(2, "核心概念")
+	if len(n.KeyConcepts) == 0 {
+		para("", "暂无，生成练习题后将根据题目关联的知识点整理")
+	}
+	for _, c := range n.KeyConcepts {
+		bullet(fmt.Sprintf("%s（%d 道题涉及）", c.Name, c.Questions))
+	}
+
+	heading(2, fmt.Sprintf("记忆卡片（%d 张）", len(n.Flashcards)))
+	if len(n.Flashcards) == 0 {
+		para("", "暂无")
+	}
+	for i, card := range n.Flashcards {
+		heading(3, fmt.Sprintf("卡片 %d", i+1))
+		para("问：", card.Front)
+		para("答：", card.Back)
+	}
+
+	heading(2, fmt.Sprintf("练习题（%d 道）", len(n.Questions)))
+	if len(n.Questions) == 0 {
+		para("", "暂无")
+	}
+	for i, q := range n.Questions {
+		para(fmt.Sprintf("%d. ", i+1), fmt.Sprintf("[%s] %s", questionTypeLabel(q.Type), q.Content))
+		for j, opt := range q.Options {
+			bullet(optionText(j, opt))
+		}
+	}
+	if len(n.Questions) > 0 {
+		heading(2, "参考答案")
+		for i, q := range n.Questions {
+			para(fmt.Sprintf("%d. ", i+1), q.CorrectAnswer)
+			if q.Explanation != "" {
+				para("解析：", q.Explanation)
+			}
+		}
+	}
+	return out
+}
+
+// RenderMarkdown 将笔记渲染为 Markdown
+func RenderMarkdown(n *Notes) []byte {
+	var b strings.Builder
+	prev := blockHeading
+	for _, bl := range n.blocks() {
+		// 列表结束后空一行，避免后续内容被并入最后一个列表项
+		if prev == blockBullet && bl.Kind != blockBullet {
+			b.WriteString("\n")
+		}
+		prev = bl.Kind
+		switch bl.Kind {
+		case blockHeading:
+			fmt.Fprintf(&b, "%s %s\n\n", strings.Repeat("#", bl.Level), bl.Text)
+		case blockParagraph:
+			if bl.Label != "" {
+				fmt.Fprintf(&b, "**%s**", strings.TrimSpace(bl.Label))
+				// 多行内容另起一段，避免加粗标签与首行列表标记粘连
+				if strings.Contains(bl.Text, "\n") {
+					b.WriteString("\n\n")
+				} else {
+					b.WriteString(" ")
+				}
+			}
+			b.WriteString(strings.TrimSpace(bl.Text))
+			b.WriteString("\n\n")
+		case blockBullet:
+			fmt.Fprintf(&b, "- %s\n", strings.Join(strings.Fields(bl.Text), " "))
+		case blockNote:
+			fmt.Fprintf(&b, "_%s_\n\n", bl.Text)
+		}
+	}
+	return []byte(strings.TrimRight(b.String(), "\n") + "\n")
+}
+
+func questionTypeLabel(t quizpb.QuestionType) string {
+	switch t {
+	case quizpb.QuestionType_MULTIPLE_CHOICE:
+		return "选择题"
+	case quizpb.QuestionType_FILL_BLANK:
+		return "填空题"
+	case quizpb.QuestionType_SHORT_ANSWER:
+		return "简答题"
+	case quizpb.QuestionType_TRUE_FALSE:
+		return "判断题"
+	case quizpb.QuestionType_ESSAY:
+		return "论述题"
+	}
+	return "题目"
+}
+
+// optionText 为选项补上 "A. " 等前缀，出题时选项文本通常已自带前缀
+func optionText(i int, opt string) string {
+	opt = strings.TrimSpace(opt)
+	if i >= 26 {
+		return opt
+	}
+	letter := string(rune('A' + i))
+	for _, sep := range []string{".", "．", "、", ")", "）", ":", "："} {
+		if strings.HasPrefix(opt, letter+sep) {
+			return opt
+		}
+	}
+	return letter + ". " + opt
+}
+
+func formatTimestamp(seconds float32) string {
+	s := int(seconds)
+	if s >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", s/3600, s%3600/60, s%60)
+	}
+	return fmt.Sprintf("%02d:%02d", s/60, s%60)
+}
@@ -0,0 +1,490 @@
+package export
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"sort"
+)
+
+// sqliteDB 是只写一次的最小 SQLite 数据库生成器，用于在网关内生成 Anki 牌组中的 collection.anki2，
+// 避免引入 cgo SQLite 驱动。仅支持整数主键表、整数列索引与 UTF-8 文本，生成后不可修改
+type sqliteDB struct {
+	tables  []*sqliteTable
+	indexes []*sqliteIndex
+}
+
+type sqliteTable struct {
+	name string
+	sql  string
+	rows []sqliteRow
+}
+
+type sqliteRow struct {
+	rowid  int64
+	values []interface{} // nil、int64 或 string；INTEGER PRIMARY KEY 列存 nil，值即 rowid
+}
+
+type sqliteIndex struct {
+	name    string
+	table   *sqliteTable
+	sql     string
+	columns []int // 被索引的列下标，列值须为 int64
+}
+
+const (
+	sqlitePageSize = 4096
+	// 表叶子页单元格可内联的最大负载，超出部分写入溢出页
+	sqliteTableMaxLocal = sqlitePageSize - 35
+	// 索引页单元格可内联的最大负载
+	sqliteIndexMaxLocal = (sqlitePageSize-12)*64/255 - 23
+	sqliteMinLocal      = (sqlitePageSize-12)*32/255 - 23
+	// 第 1 页前 100 字节为文件头
+	sqliteHeaderSize = 100
+)
+
+// B 树页类型
+const (
+	pageIndexInterior byte = 0x02
+	pageTableInterior byte = 0x05
+	pageIndexLeaf     byte = 0x0a
+	pageTableLeaf     byte = 0x0d
+)
+
+func (db *sqliteDB) table(name, sql string) *sqliteTable {
+	t := &sqliteTable{name: name, sql: sql}
+	db.tables = append(db.tables, t)
+	return t
+}
+
+func (db *sqliteDB) index(name string, t *sqliteTable, sql string, columns ...int) {
+	db.indexes = append(db.indexes, &sqliteIndex{name: name, table: t, sql: sql, columns: columns})
+}
+
+func (t *sqliteTable) insert(rowid int64, values ...interface{}) {
+	t.rows = append(t.rows, sqliteRow{rowid: rowid, values: values})
+}
+
+// bytes 写出完整的数据库文件
+func (db *sqliteDB) bytes() ([]byte, error) {
+	w := &sqliteWriter{}
+	w.alloc() // 第 1 页固定为 sqlite_master 的根页
+
+	var schema []sqliteRow
+	for _, t := range db.tables {
+		root, err := w.tableTree(t.rows)
+		if err != nil {
+			return nil, fmt.Errorf("table %s: %w", t.name, err)
+		}
+		schema = append(schema, sqliteRow{
+			rowid:  int64(len(schema) + 1),
+			values: []interface{}{"table", t.name, t.name, int64(root), t.sql},
+		})
+	}
+	for _, idx := range db.indexes {
+		root, err := w.indexTree(idx)
+		if err != nil {
+			return nil, fmt.Errorf("index %s: %w", idx.name, err)
+		}
+		schema = append(schema, sqliteRow{
+			rowid:  int64(len(schema) + 1),
+			values: []interface{}{"index", idx.name, idx.table.name, int64(root), idx.sql},
+		})
+	}
+
+	// sqlite_master 的根页必须是第 1 页，这里只支持单页的模式表
+	var cells [][]byte
+	for _, row := range schema {
+		cell, err := w.tableLeafCell(row)
+		if err != nil {
+			return nil, err
+		}
+		cells = append(cells, cell)
+	}
+	if err := w.writePage(1, pageTableLeaf, cells, 0); err != nil {
+		return nil, fmt.Errorf("schema does not fit in the first page: %w", err)
+	}
+	w.writeHeader()
+
+	var buf bytes.Buffer
+	for _, p := range w.pages {
+		buf.Write(p)
+	}
+	return buf.Bytes(), nil
+}
+
+type sqliteWriter struct {
+	pages [][]byte
+}
+
+func (w *sqliteWriter) alloc() int {
+	w.pages = append(w.pages, make([]byte, sqlitePageSize))
+	return len(w.pages)
+}
+
+func (w *sqliteWriter) writeHeader() {
+	h := w.pages[0][:sqliteHeaderSize]
+	copy(h, "SQLite format 3\x00")
+	binary.BigEndian.PutUint16(h[16:], sqlitePageSize)
+	h[18], h[19] = 1, 1 // 回滚日志模式
+	h[20] = 0           // 每页保留字节
+	h[21], h[22], h[23] = 64, 32, 32
+	binary.BigEndian.PutUint32(h[24:], 1)                    // 文件修改计数
+	binary.BigEndian.PutUint32(h[28:], uint32(len(w.pages))) // 页数
+	binary.BigEndian.PutUint32(h[40:], 1)                    // schema cookie
+	binary.BigEndian.PutUint32(h[44:], 4)                    // schema format
+	binary.BigEndian.PutUint32(h[56:], 1)                    // UTF-8
+	binary.BigEndian.PutUint32(h[92:], 1)                    // 与修改计数一致，页数字段才有效
+	binary.BigEndian.PutUint32(h[96:], 3040001)
+}
+
+// writePage 写入一个 B 树页，单元格内容从页尾向前排列
+func (w *sqliteWriter) writePage(pageNo int, typ byte, cells [][]byte, rightChild int) error {
+	page := w.pages[pageNo-1]
+	off := 0
+	if pageNo == 1 {
+		off = sqliteHeaderSize
+	}
+	hdr := 8
+	if typ == pageTableInterior || typ == pageIndexInterior {
+		hdr = 12
+	}
+	need := off + hdr + 2*len(cells)
+	for _, c := range cells {
+		need += len(c)
+	}
+	if need > sqlitePageSize {
+		return fmt.Errorf("page %d overflows: %d bytes", pageNo, need)
+	}
+
+	content := sqlitePageSize
+	for i, c := range cells {
+		content -= len(c)
+		copy(page[content:], c)
+		binary.BigEndian.PutUint16(page[off+hdr+2*i:], uint16(content))
+	}
+	page[off] = typ
+	binary.BigEndian.PutUint16(page[off+3:], uint16(len(cells)))
+	binary.BigEndian.PutUint16(page[off+5:], uint16(content))
+	if hdr == 12 {
+		binary.BigEndian.PutUint32(page[off+8:], uint32(rightChild))
+	}
+	return nil
+}
+
+// tableTree 自底向上构建表 B 树，返回根页号
+func (w *sqliteWriter) tableTree(rows []sqliteRow) (int, error) {
+	sort.Slice(rows, func(i, j int) bool { return rows[i].rowid < rows[j].rowid })
+	if len(rows) == 0 {
+		root := w.alloc()
+		return root, w.writePage(root, pageTableLeaf, nil, 0)
+	}
+
+	type node struct {
+		page     int
+		maxRowid int64
+	}
+	var level []node
+	var cells [][]byte
+	used := 0
+	flushLeaf := func(maxRowid int64) error {
+		page := w.alloc()
+		level = append(level, node{page: page, maxRowid: maxRowid})
+		err := w.writePage(page, pageTableLeaf, cells, 0)
+		cells, used = nil, 0
+		return err
+	}
+	for i, row := range rows {
+		cell, err := w.tableLeafCell(row)
+		if err != nil {
+			return 0, err
+		}
+		if len(cells) > 0 && used+len(cell)+2 > sqlitePageSize-8 {
+			if err := flushLeaf(rows[i-1].rowid); err != nil {
+				return 0, err
+			}
+		}
+		cells = append(cells, cell)
+		used += len(cell) + 2
+	}
+	if err := flushLeaf(rows[len(rows)-1].rowid); err != nil {
+		return 0, err
+	}
+
+	// 内部页：除最后一个子页外，每个子页对应一个 (子页号, 子树最大 rowid) 单元格
+	for len(level) > 1 {
+		var next, group []node
+		used := 0
+		closeGroup := func() error {
+			var cells [][]byte
+			for _, child := range group[:len(group)-1] {
+				cells = append(cells, tableInteriorCell(child.page, child.maxRowid))
+			}
+			last := group[len(group)-1]
+			page := w.alloc()
+			next = append(next, node{page: page, maxRowid: last.maxRowid})
+			group, used = nil, 0
+			return w.writePage(page, pageTableInterior, cells, last.page)
+		}
+		for _, child := range level {
+			if len(group) > 0 {
+				size := len(tableInteriorCell(group[len(group)-1].page, group[len(group)-1].maxRowid)) + 2
+				if used+size > sqlitePageSize-12 {
+					if err := closeGroup(); err != nil {
+						return 0, err
+					}
+				} else {
+					used += size
+				}
+			}
+			group = append(group, child)
+		}
+		if err := closeGroup(); err != nil {
+			return 0, err
+		}
+		level = next
+	}
+	return level[0].page, nil
+}
+
+// indexTree 构建索引 B 树。索引 B 树中每个条目只出现一次，相邻子页之间的分隔条目存放在父页中
+func (w *sqliteWriter) indexTree(idx *sqliteIndex) (int, error) {
+	type entry struct {
+		key     []int64
+		payload []byte
+	}
+	entries := make([]entry, 0, len(idx.table.rows))
+	for _, row := range idx.table.rows {
+		key := make([]int64, 0, len(idx.columns)+1)
+		values := make([]interface{}, 0, len(idx.columns)+1)
+		for _, col := range idx.columns {
+			v, ok := row.values[col].(int64)
+			if !ok {
+				return 0, fmt.Errorf("column %d of row %d is not an integer", col, row.rowid)
+			}
+			key = append(key, v)
+			values = append(values, v)
+		}
+		key = append(key, row.rowid)
+		values = append(values, row.rowid)
+		payload := encodeRecord(values)
+		if len(payload) > sqliteIndexMaxLocal {
+			return 0, fmt.Errorf("index entry too large")
+		}
+		entries = append(entries, entry{key: key, payload: payload})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		a, b := entries[i].key, entries[j].key
+		for k := range a {
+			if a[k] != b[k] {
+				return a[k] < b[k]
+			}
+		}
+		return false
+	})
+	if len(entries) == 0 {
+		root := w.alloc()
+		return root, w.writePage(root, pageIndexLeaf, nil, 0)
+	}
+
+	// 叶子层
+	cells := make([][]byte, len(entries))
+	sizes := make([]int, len(entries))
+	for i, e := range entries {
+		cells[i] = append(appendVarint(nil, uint64(len(e.payload))), e.payload...)
+		sizes[i] = len(cells[i]) + 2
+	}
+	splits, err := planSplits(sizes, sqlitePageSize-8)
+	if err != nil {
+		return 0, err
+	}
+	var children []int
+	var seps [][]byte
+	start := 0
+	for _, s := range append(splits, len(entries)) {
+		page := w.alloc()
+		if err := w.writePage(page, pageIndexLeaf, cells[start:s], 0); err != nil {
+			return 0, err
+		}
+		children = append(children, page)
+		if s < len(entries) {
+			seps = append(seps, entries[s].payload)
+		}
+		start = s + 1
+	}
+
+	// 内部层：单元格为 (左子页号, 分隔条目)，最后一个子页作为右指针
+	for len(children) > 1 {
+		n := len(children) - 1
+		cells := make([][]byte, n)
+		sizes := make([]int, n)
+		for i := 0; i < n; i++ {
+			cell := binary.BigEndian.AppendUint32(nil, uint32(children[i]))
+			cell = appendVarint(cell, uint64(len(seps[i])))
+			cells[i] = append(cell, seps[i]...)
+			sizes[i] = len(cells[i]) + 2
+		}
+		splits, err := planSplits(sizes, sqlitePageSize-12)
+		if err != nil {
+			return 0, err
+		}
+		var nextChildren []int
+		var nextSeps [][]byte
+		start := 0
+		for _, s := range append(splits, n) {
+			// 被提升的分隔项的左子页成为本页的右指针
+			page := w.alloc()
+			if err := w.writePage(page, pageIndexInterior, cells[start:s], children[s]); err != nil {
+				return 0, err
+			}
+			nextChildren = append(nextChildren, page)
+			if s < n {
+				nextSeps = append(nextSeps, seps[s])
+			}
+			start = s + 1
+		}
+		children, seps = nextChildren, nextSeps
+	}
+	return children[0], nil
+}
+
+// planSplits 按顺序将各项装入容量为 capacity 的页，返回被提升为分隔项的下标。
+// 分隔项不占用本层的页，且每页至少有一项
+func planSplits(sizes []int, capacity int) ([]int, error) {
+	var splits []int
+	used, count := 0, 0
+	for i := 0; i < len(sizes); i++ {
+		if count == 0 || used+sizes[i] <= capacity {
+			used += sizes[i]
+			count++
+			continue
+		}
+		if i == len(sizes)-1 {
+			// 分隔项之后必须还有一页，改为提升前一项
+			if count < 2 {
+				return nil, fmt.Errorf("index entries too large to split")
+			}
+			splits = append(splits, i-1)
+			break
+		}
+		splits = append(splits, i)
+		used, count = 0, 0
+	}
+	return splits, nil
+}
+
+// tableLeafCell 编码表叶子单元格，负载超出内联上限时其余部分写入溢出页链
+func (w *sqliteWriter) tableLeafCell(row sqliteRow) ([]byte, error) {
+	payload := encodeRecord(row.values)
+	cell := appendVarint(nil, uint64(len(payload)))
+	cell = appendVarint(cell, uint64(row.rowid))
+	if len(payload) <= sqliteTableMaxLocal {
+		return append(cell, payload...), nil
+	}
+
+	local := sqliteMinLocal + (len(payload)-sqliteMinLocal)%(sqlitePageSize-4)
+	if local > sqliteTableMaxLocal {
+		local = sqliteMinLocal
+	}
+	cell = append(cell, payload[:local]...)
+	rest := payload[local:]
+	first := w.alloc()
+	cell = binary.BigEndian.AppendUint32(cell, uint32(first))
+	for page := first; ; {
+		n := copy(w.pages[page-1][4:], rest)
+		rest = rest[n:]
+		if len(rest) == 0 {
+			break
+		}
+		next := w.alloc()
+		binary.BigEndian.PutUint32(w.pages[page-1], uint32(next))
+		page = next
+	}
+	return cell, nil
+}
+
+func tableInteriorCell(child int, key int64) []byte {
+	cell := binary.BigEndian.AppendUint32(nil, uint32(child))
+	return appendVarint(cell, uint64(key))
+}
+
+// encodeRecord 按 SQLite 记录格式编码一行：头部为各列的类型码，随后依次为列值
+func encodeRecord(values []interface{}) []byte {
+	var header, body []byte
+	for _, v := range values {
+		switch v := v.(type) {
+		case nil:
+			header = appendVarint(header, 0)
+		case int64:
+			typ, size := intSerialType(v)
+			header = appendVarint(header, typ)
+			for i := size - 1; i >= 0; i-- {
+				body = append(body, byte(v>>(8*uint(i))))
+			}
+		case string:
+			header = appendVarint(header, uint64(2*len(v)+13))
+			body = append(body, v...)
+		default:
+			panic(fmt.Sprintf("sqlite: unsupported value type %T", v))
+		}
+	}
+	// 头部长度包含长度字段自身
+	size := len(header) + 1
+	for len(appendVarint(nil, uint64(size))) != size-len(header) {
+		size = len(header) + len(appendVarint(nil, uint64(size)))
+	}
+	record := appendVarint(nil, uint64(size))
+	record = append(record, header...)
+	return append(record, body...)
+}
+
+// intSerialType 返回整数的类型码与字节数，0 与 1 不占用数据字节
+func intSerialType(v int64) (uint64, int) {
+	switch {
+	case v == 0:
+		return 8, 0
+	case v == 1:
+		return 9, 0
+	case v >= -1<<7 && v < 1<<7:
+		return 1, 1
+	case v >= -1<<15 && v < 1<<15:
+		return 2, 2
+	case v >= -1<<23 && v < 1<<23:
+		return 3, 3
+	case v >= -1<<31 && v < 1<<31:
+		return 4, 4
+	case v >= -1<<47 && v < 1<<47:
+		return 5, 6
+	default:
+		return 6, 8
+	}
+}
+
+// appendVarint 追加 SQLite 大端变长整数：前 8 字节每字节 7 位，第 9 字节为完整 8 位
+func appendVarint(buf []byte, v uint64) []byte {
+	if v > 1<<56-1 {
+		var b [9]byte
+		b[8] = byte(v)
+		v >>= 8
+		for i := 7; i >= 0; i-- {
+			b[i] = byte(v&0x7f) | 0x80
+			v >>= 7
+		}
+		return append(buf, b[:]...)
+	}
+	var tmp [8]byte
+	n := 0
+	for {
+		tmp[n] = byte(v&0x7f) | 0x80
+		n++
+		v >>= 7
+		if v == 0 {
+			break
+		}
+	}
+	tmp[0] &= 0x7f
+	for i := n - 1; i >= 0; i-- {
+		buf = append(buf, tmp[i])
+	}
+	return buf
+}
@@ -0,0 +1,110 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	asrpb "github.com/RigelNana/arkstudy/proto/asr"
+)
+
+// 字幕格式
+const (
+	SubtitleFormatSRT = "srt"
+	SubtitleFormatVTT = "vtt"
+)
+
+// 翻译字幕会批量调用 LLM，超时时间与摘要相同
+const translateTimeout = summaryTimeout
+
+// Cue 一条字幕，Translation 仅双语字幕非空
+type Cue struct {
+	Start, End  float32
+	Text        string
+	Translation string
+}
+
+// Subtitles 获取资料的转写字幕。language 非空时附带该语言的译文（已翻译的分段直接复用）。
+// 资料不存在或无权访问时返回 ErrMaterialNotFound
+func (c *Collector) Subtitles(ctx context.Context, userID, materialID, language string) ([]Cue, error) {
+	if _, err := c.material(ctx, userID, materialID); err != nil {
+		return nil, err
+	}
+	if language != "" {
+		tctx, cancel := context.WithTimeout(ctx, translateTimeout)
+		defer cancel()
+		resp, err := c.asr.TranslateTranscript(tctx, &asrpb.TranslateTranscriptRequest{
+			MaterialId:     materialID,
+			UserId:         userID,
+			TargetLanguage: language,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if !resp.Success {
+			return nil, fmt.Errorf("%s", resp.Message)
+		}
+		cues := make([]Cue, 0, len(resp.Segments))
+		for _, s := range resp.Segments {
+			cues = append(cues, Cue{
+				Start:       s.Segment.GetStartTime(),
+				End:         s.Segment.GetEndTime(),
+				Text:        s.Segment.GetText(),
+				Translation: s.TranslatedText,
+			})
+		}
+		return cues, nil
+	}
+
+	actx, cancel := context.WithTimeout(ctx, serviceTimeout)
+	defer cancel()
+	resp, err := c.asr.GetSegments(actx, &asrpb.GetSegmentsRequest{MaterialId: materialID, UserId: userID})
+	if err != nil {
+		return nil, err
+	}
+	if !resp.Success {
+		return nil, fmt.Errorf("%s", resp.Message)
+	}
+	cues := make([]Cue, 0, len(resp.Segments))
+	for _, s := range resp.Segments {
+		cues = append(cues, Cue{Start: s.StartTime, End: s.EndTime, Text: s.Text})
+	}
+	return cues, nil
+}
+
+// RenderSubtitles 将字幕渲染为 SRT 或 WebVTT，双语字幕的译文在原文下一行
+func RenderSubtitles(cues []Cue, format string) []byte {
+	var b bytes.Buffer
+	sep := ","
+	if format == SubtitleFormatVTT {
+		b.WriteString("WEBVTT\n\n")
+		sep = "."
+	}
+	n := 0
+	for _, cue := range cues {
+		text := strings.TrimSpace(cue.Text)
+		if text == "" {
+			continue
+		}
+		n++
+		if format == SubtitleFormatSRT {
+			fmt.Fprintf(&b, "%d\n", n)
+		}
+		fmt.Fprintf(&b, "%s --> %s\n%s\n", cueTimestamp(cue.Start, sep), cueTimestamp(cue.End, sep), text)
+		if t := strings.TrimSpace(cue.Translation); t != "" {
+			b.WriteString(t + "\n")
+		}
+		b.WriteString("\n")
+	}
+	return b.Bytes()
+}
+
+// cueTimestamp 格式化为 HH:MM:SS,mmm（WebVTT 使用 . 分隔毫秒）
+func cueTimestamp(seconds float32, sep string) string {
+	ms := int64(seconds*1000 + 0.5)
+	if ms < 0 {
+		ms = 0
+	}
+	return fmt.Sprintf("%02d:%02d:%02d%s%03d", ms/3600000, ms/60000%60, ms/1000%60, sep, ms%1000)
+}
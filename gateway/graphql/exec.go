@@ -0,0 +1,423 @@
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// 默认的查询限制，Schema 中为 0 时使用
+const (
+	defaultMaxDepth       = 8
+	defaultMaxFields      = 200
+	defaultMaxConcurrency = 8
+)
+
+// Object 对象类型
+type Object struct {
+	Name   string
+	Fields map[string]*FieldDef
+}
+
+// FieldDef 字段定义。Type 为 nil 时是标量字段，解析结果直接序列化为 JSON；
+// 否则解析结果为该对象类型的值（或值的切片），由 Type 的字段继续解析，nil 输出为 null
+type FieldDef struct {
+	Type    *Object
+	Resolve func(p ResolveParams) (interface{}, error)
+}
+
+// ResolveParams 字段解析参数，Source 为所在对象的值，顶层字段为 nil
+type ResolveParams struct {
+	Context context.Context
+	Source  interface{}
+	Args    map[string]interface{}
+}
+
+// Schema 只含查询根类型；MaxDepth 为选择集的最大嵌套层数，MaxFields 为查询中字段总数（展开片段后）的上限，
+// MaxConcurrency 为单次查询同时执行的解析函数数
+type Schema struct {
+	Query          *Object
+	MaxDepth       int
+	MaxFields      int
+	MaxConcurrency int
+}
+
+// Request GraphQL over HTTP 的请求体
+type Request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// Response 执行结果。解析、校验失败时 Data 为空；单个字段解析失败时该字段为 null 并记入 Errors
+type Response struct {
+	Data   interface{} `json:"data,omitempty"`
+	Errors []*Error    `json:"errors,omitempty"`
+}
+
+// Error 查询错误，Path 为出错字段在结果中的路径
+type Error struct {
+	Message string        `json:"message"`
+	Path    []interface{} `json:"path,omitempty"`
+}
+
+// Execute 解析、校验并执行查询
+func (s *Schema) Execute(ctx context.Context, req Request) *Response {
+	doc, err := Parse(req.Query, s.MaxDepth)
+	if err != nil {
+		return errorResponse(err.Error())
+	}
+	op, err := selectOperation(doc, req.OperationName)
+	if err != nil {
+		return errorResponse(err.Error())
+	}
+	vars, err := coerceVariables(op, req.Variables)
+	if err != nil {
+		return errorResponse(err.Error())
+	}
+	if err := s.validate(doc, op); err != nil {
+		return errorResponse(err.Error())
+	}
+
+	e := &executor{
+		doc:  doc,
+		vars: vars,
+		sem:  make(chan struct{}, orDefault(s.MaxConcurrency, defaultMaxConcurrency)),
+	}
+	data := e.object(ctx, s.Query, nil, op.Selections, nil)
+	return &Response{Data: data, Errors: e.errors}
+}
+
+func errorResponse(message string) *Response {
+	return &Response{Errors: []*Error{{Message: message}}}
+}
+
+func orDefault(n, fallback int) int {
+	if n > 0 {
+		return n
+	}
+	return fallback
+}
+
+// selectOperation 按 operationName 选择操作，文档只有一个操作时可省略
+func selectOperation(doc *Document, name string) (*Operation, error) {
+	var op *Operation
+	if name == "" {
+		if len(doc.Operations) > 1 {
+			return nil, fmt.Errorf("文档包含多个操作时必须指定 operationName")
+		}
+		op = doc.Operations[0]
+	} else {
+		for _, o := range doc.Operations {
+			if o.Name == name {
+				op = o
+				break
+			}
+		}
+		if op == nil {
+			return nil, fmt.Errorf("操作 %s 不存在", name)
+		}
+	}
+	if op.Type != "query" {
+		return nil, fmt.Errorf("不支持 %s 操作，仅支持 query", op.Type)
+	}
+	return op, nil
+}
+
+// coerceVariables 合并请求中的变量与默认值，检查必填变量
+func coerceVariables(op *Operation, provided map[string]interface{}) (map[string]interface{}, error) {
+	vars := map[string]interface{}{}
+	for _, def := range op.Variables {
+		v, ok := provided[def.Name]
+		if !ok && def.HasDefault {
+			v, ok = resolveValue(def.Default, nil), true
+		}
+		if def.NonNull && (!ok || v == nil) {
+			return nil, fmt.Errorf("缺少必填变量 $%s（%s!）", def.Name, def.Type)
+		}
+		if ok {
+			vars[def.Name] = v
+		}
+	}
+	return vars, nil
+}
+
+// resolveValue 将参数字面量转换为 Go 值，枚举值转为字符串，变量替换为请求中的值
+func resolveValue(v Value, vars map[string]interface{}) interface{} {
+	switch v := v.(type) {
+	case Variable:
+		return vars[string(v)]
+	case EnumValue:
+		return string(v)
+	case []Value:
+		list := make([]interface{}, len(v))
+		for i, item := range v {
+			list[i] = resolveValue(item, vars)
+		}
+		return list
+	case map[string]Value:
+		obj := make(map[string]interface{}, len(v))
+		for k, item := range v {
+			obj[k] = resolveValue(item, vars)
+		}
+		return obj
+	}
+	return v
+}
+
+// validate 检查字段是否存在、标量与对象字段的选择集、片段引用，以及嵌套层数与字段总数
+func (s *Schema) validate(doc *Document, op *Operation) error {
+	maxDepth := orDefault(s.MaxDepth, defaultMaxDepth)
+	maxFields := orDefault(s.MaxFields, defaultMaxFields)
+	fields := 0
+
+	var walk func(typ *Object, selections []Selection, depth int) error
+	walk = func(typ *Object, selections []Selection, depth int) error {
+		if depth > maxDepth {
+			return fmt.Errorf("查询嵌套超过 %d 层", maxDepth)
+		}
+		for _, sel := range selections {
+			switch sel := sel.(type) {
+			case *Field:
+				fields++
+				if fields > maxFields {
+					return fmt.Errorf("查询字段数超过 %d 个", maxFields)
+				}
+				if sel.Name == "__typename" {
+					if len(sel.Selections) > 0 {
+						return fmt.Errorf("标量字段 __typename 不能有选择集")
+					}
+					continue
+				}
+				def, ok := typ.Fields[sel.Name]
+				if !ok {
+					return fmt.Errorf("类型 %s 没有字段 %s", typ.Name, sel.Name)
+				}
+				if def.Type == nil && len(sel.Selections) > 0 {
+					return fmt.Errorf("标量字段 %s.%s 不能有选择集", typ.Name, sel.Name)
+				}
+				if def.Type != nil {
+					if len(sel.Selections) == 0 {
+						return fmt.Errorf("对象字段 %s.%s 需要选择集", typ.Name, sel.Name)
+					}
+					if err := walk(def.Type, sel.Selections, depth+1); err != nil {
+						return err
+					}
+				}
+			case *FragmentSpread:
+				f, ok := doc.Fragments[sel.Name]
+				if !ok {
+					return fmt.Errorf("片段 %s 未定义", sel.Name)
+				}
+				// 片段循环引用会在此处超过嵌套层数
+				if err := walk(typ, f.Selections, depth+1); err != nil {
+					return err
+				}
+			case *InlineFragment:
+				if err := walk(typ, sel.Selections, depth); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+	return walk(s.Query, op.Selections, 1)
+}
+
+type executor struct {
+	doc  *Document
+	vars map[string]interface{}
+	sem  chan struct{}
+
+	mu     sync.Mutex
+	errors []*Error
+}
+
+func (e *executor) addError(path []interface{}, err error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.errors = append(e.errors, &Error{Message: err.Error(), Path: path})
+}
+
+// object 并发解析 source 上选中的字段，按选择顺序输出
+func (e *executor) object(ctx context.Context, typ *Object, source interface{}, selections []Selection, path []interface{}) *orderedMap {
+	keys, fields := e.collectFields(typ, selections, nil, map[string][]*Field{})
+	out := &orderedMap{keys: keys, values: make(map[string]interface{}, len(keys))}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, key := range keys {
+		group := fields[key]
+		fieldPath := append(append([]interface{}{}, path...), key)
+		if group[0].Name == "__typename" {
+			mu.Lock()
+			out.values[key] = typ.Name
+			mu.Unlock()
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			v := e.field(ctx, typ, source, group, fieldPath)
+			mu.Lock()
+			out.values[key] = v
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	return out
+}
+
+// collectFields 展开片段并按结果键合并同名字段，跳过 @skip / @include 排除的选择
+func (e *executor) collectFields(typ *Object, selections []Selection, keys []string, fields map[string][]*Field) ([]string, map[string][]*Field) {
+	for _, sel := range selections {
+		switch sel := sel.(type) {
+		case *Field:
+			if !e.included(sel.Directives) {
+				continue
+			}
+			key := sel.ResponseKey()
+			if _, seen := fields[key]; !seen {
+				keys = append(keys, key)
+			}
+			fields[key] = append(fields[key], sel)
+		case *FragmentSpread:
+			f := e.doc.Fragments[sel.Name]
+			if !e.included(sel.Directives) || f.TypeCondition != typ.Name {
+				continue
+			}
+			keys, fields = e.collectFields(typ, f.Selections, keys, fields)
+		case *InlineFragment:
+			if !e.included(sel.Directives) || (sel.TypeCondition != "" && sel.TypeCondition != typ.Name) {
+				continue
+			}
+			keys, fields = e.collectFields(typ, sel.Selections, keys, fields)
+		}
+	}
+	return keys, fields
+}
+
+func (e *executor) included(directives []*Directive) bool {
+	for _, d := range directives {
+		if d.Name != "skip" && d.Name != "include" {
+			continue
+		}
+		var cond bool
+		for _, arg := range d.Args {
+			if arg.Name == "if" {
+				cond, _ = resolveValue(arg.Value, e.vars).(bool)
+			}
+		}
+		if d.Name == "skip" && cond || d.Name == "include" && !cond {
+			return false
+		}
+	}
+	return true
+}
+
+// field 解析单个字段（同名字段的选择集合并），解析失败时记录错误并返回 nil
+func (e *executor) field(ctx context.Context, typ *Object, source interface{}, group []*Field, path []interface{}) interface{} {
+	f := group[0]
+	def := typ.Fields[f.Name]
+	args := make(map[string]interface{}, len(f.Args))
+	for _, arg := range f.Args {
+		args[arg.Name] = resolveValue(arg.Value, e.vars)
+	}
+
+	e.sem <- struct{}{}
+	v, err := safeResolve(def, ResolveParams{Context: ctx, Source: source, Args: args})
+	<-e.sem
+	if err != nil {
+		e.addError(path, err)
+		return nil
+	}
+	if def.Type == nil || isNil(v) {
+		return v
+	}
+
+	var selections []Selection
+	for _, f := range group {
+		selections = append(selections, f.Selections...)
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice {
+		return e.object(ctx, def.Type, v, selections, path)
+	}
+	list := make([]interface{}, rv.Len())
+	for i := range list {
+		item := rv.Index(i).Interface()
+		if isNil(item) {
+			continue
+		}
+		list[i] = e.object(ctx, def.Type, item, selections, append(append([]interface{}{}, path...), i))
+	}
+	return list
+}
+
+// safeResolve 将解析函数的 panic 转为字段错误，避免一个字段导致整个请求失败
+func safeResolve(def *FieldDef, p ResolveParams) (v interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			v, err = nil, fmt.Errorf("内部错误: %v", r)
+		}
+	}()
+	return def.Resolve(p)
+}
+
+func isNil(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Interface, reflect.Func:
+		return rv.IsNil()
+	}
+	return false
+}
+
+// orderedMap 按字段选择顺序序列化的 JSON 对象
+type orderedMap struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+func (m *orderedMap) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range m.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		k, _ := json.Marshal(key)
+		buf.Write(k)
+		buf.WriteByte(':')
+		v, err := json.Marshal(m.values[key])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(v)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// String 返回字符串参数，未提供或类型不符时为空串
+func (p ResolveParams) String(name string) string {
+	s, _ := p.Args[name].(string)
+	return s
+}
+
+// Int 返回整数参数，未提供时为 fallback。变量经 JSON 解码为 float64，一并接受
+func (p ResolveParams) Int(name string, fallback int) int {
+	switch v := p.Args[name].(type) {
+	case int64:
+		return int(v)
+	case float64:
+		return int(v)
+	}
+	return fallback
+}
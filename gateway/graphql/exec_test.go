@@ -0,0 +1,169 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type testMaterial struct {
+	ID    string
+	Title string
+	Tags  []string
+}
+
+// testSchema material(id) 返回资料，materials 返回列表；broken 与 panics 用于验证字段错误
+func testSchema() *Schema {
+	materials := map[string]*testMaterial{
+		"m1": {ID: "m1", Title: "线性代数", Tags: []string{"math"}},
+		"m2": {ID: "m2", Title: "概率论"},
+	}
+	material := &Object{Name: "Material"}
+	material.Fields = map[string]*FieldDef{
+		"id":    {Resolve: func(p ResolveParams) (interface{}, error) { return p.Source.(*testMaterial).ID, nil }},
+		"title": {Resolve: func(p ResolveParams) (interface{}, error) { return p.Source.(*testMaterial).Title, nil }},
+		"tags":  {Resolve: func(p ResolveParams) (interface{}, error) { return p.Source.(*testMaterial).Tags, nil }},
+		"related": {Type: material, Resolve: func(p ResolveParams) (interface{}, error) {
+			return materials["m2"], nil
+		}},
+		"broken": {Resolve: func(p ResolveParams) (interface{}, error) { return nil, errors.New("后端不可用") }},
+		"panics": {Resolve: func(p ResolveParams) (interface{}, error) { panic("boom") }},
+	}
+	query := &Object{Name: "Query", Fields: map[string]*FieldDef{
+		"material": {Type: material, Resolve: func(p ResolveParams) (interface{}, error) {
+			m, ok := materials[p.String("id")]
+			if !ok {
+				return nil, nil
+			}
+			return m, nil
+		}},
+		"materials": {Type: material, Resolve: func(p ResolveParams) (interface{}, error) {
+			list := []*testMaterial{materials["m1"], materials["m2"]}
+			return list[:min(p.Int("limit", len(list)), len(list))], nil
+		}},
+	}}
+	return &Schema{Query: query}
+}
+
+func execute(t *testing.T, s *Schema, req Request) (string, []*Error) {
+	t.Helper()
+	resp := s.Execute(context.Background(), req)
+	if resp.Data == nil {
+		return "", resp.Errors
+	}
+	data, err := json.Marshal(resp.Data)
+	if err != nil {
+		t.Fatalf("marshal data: %v", err)
+	}
+	return string(data), resp.Errors
+}
+
+func TestExecuteQuery(t *testing.T) {
+	data, errs := execute(t, testSchema(), Request{
+		Query: `query Q($id: ID!, $withTags: Boolean = false) {
+			m: material(id: $id) { __typename title ...Fields tags @include(if: $withTags) }
+			missing: material(id: "none") { id }
+			materials(limit: 1) { id }
+		}
+		fragment Fields on Material { id related { title } }`,
+		Variables: map[string]interface{}{"id": "m1"},
+	})
+	if len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v", errs[0].Message)
+	}
+	// 字段按选择顺序输出，片段展开在原位置，@include 为 false 的字段被跳过
+	want := `{"m":{"__typename":"Material","title":"线性代数","id":"m1","related":{"title":"概率论"}},"missing":null,"materials":[{"id":"m1"}]}`
+	if data != want {
+		t.Fatalf("unexpected data:\n got %s\nwant %s", data, want)
+	}
+}
+
+func TestExecuteMergesFieldsAndSkips(t *testing.T) {
+	data, errs := execute(t, testSchema(), Request{
+		Query: `{ material(id: "m1") { related { id } related { title } title @skip(if: true) } }`,
+	})
+	if len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v", errs[0].Message)
+	}
+	if want := `{"material":{"related":{"id":"m2","title":"概率论"}}}`; data != want {
+		t.Fatalf("unexpected data:\n got %s\nwant %s", data, want)
+	}
+}
+
+func TestExecuteFieldErrors(t *testing.T) {
+	data, errs := execute(t, testSchema(), Request{Query: `{ material(id: "m1") { id broken panics } }`})
+	if want := `{"material":{"id":"m1","broken":null,"panics":null}}`; data != want {
+		t.Fatalf("unexpected data:\n got %s\nwant %s", data, want)
+	}
+	if len(errs) != 2 {
+		t.Fatalf("expected 2 field errors, got %d", len(errs))
+	}
+	messages := map[string]string{}
+	for _, e := range errs {
+		path, _ := json.Marshal(e.Path)
+		messages[string(path)] = e.Message
+	}
+	if messages[`["material","broken"]`] != "后端不可用" {
+		t.Fatalf("unexpected errors %v", messages)
+	}
+	if !strings.Contains(messages[`["material","panics"]`], "内部错误") {
+		t.Fatalf("unexpected errors %v", messages)
+	}
+}
+
+func TestExecuteRejectsInvalidRequests(t *testing.T) {
+	cases := []struct {
+		req  Request
+		want string
+	}{
+		{Request{Query: `mutation { material(id: "m1") { id } }`}, "仅支持 query"},
+		{Request{Query: `query A { materials { id } } query B { materials { id } }`}, "必须指定 operationName"},
+		{Request{Query: `query A { materials { id } }`, OperationName: "B"}, "操作 B 不存在"},
+		{Request{Query: `query ($id: ID!) { material(id: $id) { id } }`}, "缺少必填变量 $id"},
+		{Request{Query: `{ material(id: "m1") { unknown } }`}, "没有字段 unknown"},
+		{Request{Query: `{ material(id: "m1") }`}, "需要选择集"},
+		{Request{Query: `{ material(id: "m1") { id { x } } }`}, "不能有选择集"},
+		{Request{Query: `{ materials { ...Missing } }`}, "片段 Missing 未定义"},
+		{Request{Query: `{ materials { ...A } } fragment A on Material { ...A }`}, "查询嵌套超过"},
+	}
+	s := testSchema()
+	for _, c := range cases {
+		data, errs := execute(t, s, c.req)
+		if data != "" || len(errs) != 1 || !strings.Contains(errs[0].Message, c.want) {
+			t.Errorf("%q: expected error containing %q, got data=%s errors=%v", c.req.Query, c.want, data, errs)
+		}
+	}
+}
+
+func TestExecuteLimits(t *testing.T) {
+	s := testSchema()
+	s.MaxDepth = 3
+	s.MaxFields = 4
+
+	if _, errs := execute(t, s, Request{Query: `{ material(id: "m1") { related { related { id } } } }`}); len(errs) != 1 || !strings.Contains(errs[0].Message, "查询嵌套超过 3 层") {
+		t.Fatalf("expected depth error, got %v", errs)
+	}
+	if _, errs := execute(t, s, Request{Query: `{ material(id: "m1") { a: id b: id c: id d: id } }`}); len(errs) != 1 || !strings.Contains(errs[0].Message, "查询字段数超过 4 个") {
+		t.Fatalf("expected field count error, got %v", errs)
+	}
+	// 片段展开后的字段同样计数
+	query := `{ material(id: "m1") { ...F ...F } } fragment F on Material { id title }`
+	if _, errs := execute(t, s, Request{Query: query}); len(errs) != 1 || !strings.Contains(errs[0].Message, "查询字段数超过 4 个") {
+		t.Fatalf("expected field count error, got %v", errs)
+	}
+	if _, errs := execute(t, s, Request{Query: `{ material(id: "m1") { related { id } } }`}); len(errs) != 0 {
+		t.Fatalf("unexpected errors within limits: %v", errs[0].Message)
+	}
+}
+
+func TestResolveParams(t *testing.T) {
+	p := ResolveParams{Args: map[string]interface{}{"s": "x", "i": int64(3), "f": float64(4), "bad": "5"}}
+	if p.String("s") != "x" || p.String("i") != "" {
+		t.Fatalf("unexpected String results")
+	}
+	if p.Int("i", 0) != 3 || p.Int("f", 0) != 4 || p.Int("bad", 7) != 7 || p.Int("none", 9) != 9 {
+		t.Fatalf("unexpected Int results")
+	}
+}
@@ -0,0 +1,559 @@
+// Package graphql 是 gateway 内的最小 GraphQL 查询执行器，用于在一次请求中组合多个后端服务的数据。
+//
+// 只支持 query 操作：字段、别名、参数、变量、命名片段与内联片段、@include / @skip 指令以及 __typename；
+// 不支持 mutation、subscription 与内省查询。与 export 包中的 SQLite 生成器一样，只实现 gateway 用到的部分，
+// 避免引入完整的 GraphQL 依赖。
+package graphql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Document 解析后的查询文档
+type Document struct {
+	Operations []*Operation
+	Fragments  map[string]*Fragment
+}
+
+// Operation 查询操作，匿名查询的 Name 为空
+type Operation struct {
+	Type       string // 仅支持 query
+	Name       string
+	Variables  []*VariableDef
+	Selections []Selection
+}
+
+// VariableDef 变量定义，如 $id: ID! = "x"
+type VariableDef struct {
+	Name       string
+	Type       string
+	NonNull    bool
+	Default    Value
+	HasDefault bool
+}
+
+// Selection 为 *Field、*FragmentSpread 或 *InlineFragment
+type Selection interface{}
+
+type Field struct {
+	Alias      string
+	Name       string
+	Args       []*Argument
+	Directives []*Directive
+	Selections []Selection
+}
+
+// ResponseKey 字段在结果中的键，有别名时为别名
+func (f *Field) ResponseKey() string {
+	if f.Alias != "" {
+		return f.Alias
+	}
+	return f.Name
+}
+
+type FragmentSpread struct {
+	Name       string
+	Directives []*Directive
+}
+
+type InlineFragment struct {
+	TypeCondition string
+	Directives    []*Directive
+	Selections    []Selection
+}
+
+type Fragment struct {
+	Name          string
+	TypeCondition string
+	Selections    []Selection
+}
+
+type Argument struct {
+	Name  string
+	Value Value
+}
+
+type Directive struct {
+	Name string
+	Args []*Argument
+}
+
+// Value 参数字面量：nil、bool、int64、float64、string、EnumValue、Variable、[]Value 或 map[string]Value
+type Value interface{}
+
+// EnumValue 未加引号的枚举值
+type EnumValue string
+
+// Variable 对变量的引用
+type Variable string
+
+// SyntaxError 查询语法错误，Line / Column 从 1 开始
+type SyntaxError struct {
+	Line    int
+	Column  int
+	Message string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("语法错误（第 %d 行第 %d 列）：%s", e.Line, e.Column, e.Message)
+}
+
+// 词法单元类型
+const (
+	tokEOF = iota
+	tokPunct
+	tokName
+	tokInt
+	tokFloat
+	tokString
+)
+
+type token struct {
+	kind  int
+	value string
+	pos   int
+}
+
+// 参数值与变量类型中列表、对象的最大嵌套层数
+const maxValueDepth = 32
+
+type parser struct {
+	src string
+	pos int
+	tok token
+
+	maxDepth int // 选择集的最大嵌套层数
+	depth    int // 当前所在选择集的层数
+	nesting  int // 当前所在参数值或类型的层数
+}
+
+// Parse 解析查询文档。选择集嵌套超过 maxDepth 层（0 时使用默认值，内联片段也计入层数）时立即返回语法错误，
+// 避免深层嵌套的请求在校验前耗尽栈空间
+func Parse(src string, maxDepth int) (doc *Document, err error) {
+	p := &parser{src: strings.TrimPrefix(src, "\ufeff"), maxDepth: orDefault(maxDepth, defaultMaxDepth)}
+	defer func() {
+		if r := recover(); r != nil {
+			se, ok := r.(*SyntaxError)
+			if !ok {
+				panic(r)
+			}
+			doc, err = nil, se
+		}
+	}()
+	p.next()
+	doc = &Document{Fragments: map[string]*Fragment{}}
+	for p.tok.kind != tokEOF {
+		switch {
+		case p.peek("{"):
+			doc.Operations = append(doc.Operations, &Operation{Type: "query", Selections: p.selectionSet()})
+		case p.tok.kind == tokName && p.tok.value == "fragment":
+			f := p.fragment()
+			if _, dup := doc.Fragments[f.Name]; dup {
+				p.fail("片段 %s 重复定义", f.Name)
+			}
+			doc.Fragments[f.Name] = f
+		case p.tok.kind == tokName:
+			doc.Operations = append(doc.Operations, p.operation())
+		default:
+			p.fail("意外的 %q", p.tok.value)
+		}
+	}
+	if len(doc.Operations) == 0 {
+		return nil, &SyntaxError{Line: 1, Column: 1, Message: "查询中没有操作"}
+	}
+	return doc, nil
+}
+
+func (p *parser) operation() *Operation {
+	op := &Operation{Type: p.name()}
+	if p.tok.kind == tokName {
+		op.Name = p.name()
+	}
+	if p.skip("(") {
+		for !p.skip(")") {
+			op.Variables = append(op.Variables, p.variableDef())
+		}
+	}
+	p.directives()
+	op.Selections = p.selectionSet()
+	return op
+}
+
+func (p *parser) variableDef() *VariableDef {
+	p.expect("$")
+	v := &VariableDef{Name: p.name()}
+	p.expect(":")
+	v.Type, v.NonNull = p.typeRef()
+	if p.skip("=") {
+		v.Default, v.HasDefault = p.value(true), true
+	}
+	p.directives()
+	return v
+}
+
+// typeRef 解析变量类型，列表类型返回如 [ID!] 的文本
+func (p *parser) typeRef() (string, bool) {
+	var typ string
+	if p.skip("[") {
+		p.enterValue()
+		defer p.leaveValue()
+		inner, nonNull := p.typeRef()
+		if nonNull {
+			inner += "!"
+		}
+		p.expect("]")
+		typ = "[" + inner + "]"
+	} else {
+		typ = p.name()
+	}
+	return typ, p.skip("!")
+}
+
+func (p *parser) fragment() *Fragment {
+	p.name() // fragment
+	f := &Fragment{Name: p.name()}
+	if f.Name == "on" {
+		p.fail("片段名不能为 on")
+	}
+	if p.tok.kind != tokName || p.tok.value != "on" {
+		p.fail("片段 %s 缺少类型条件", f.Name)
+	}
+	p.next()
+	f.TypeCondition = p.name()
+	p.directives()
+	f.Selections = p.selectionSet()
+	return f
+}
+
+func (p *parser) selectionSet() []Selection {
+	p.expect("{")
+	p.depth++
+	defer func() { p.depth-- }()
+	if p.depth > p.maxDepth {
+		p.fail("查询嵌套超过 %d 层", p.maxDepth)
+	}
+	var selections []Selection
+	for !p.skip("}") {
+		selections = append(selections, p.selection())
+	}
+	if len(selections) == 0 {
+		p.fail("选择集不能为空")
+	}
+	return selections
+}
+
+func (p *parser) selection() Selection {
+	if p.skip("...") {
+		if p.tok.kind == tokName && p.tok.value != "on" {
+			return &FragmentSpread{Name: p.name(), Directives: p.directives()}
+		}
+		inline := &InlineFragment{}
+		if p.tok.kind == tokName && p.tok.value == "on" {
+			p.next()
+			inline.TypeCondition = p.name()
+		}
+		inline.Directives = p.directives()
+		inline.Selections = p.selectionSet()
+		return inline
+	}
+
+	f := &Field{Name: p.name()}
+	if p.skip(":") {
+		f.Alias, f.Name = f.Name, p.name()
+	}
+	f.Args = p.arguments(false)
+	f.Directives = p.directives()
+	if p.peek("{") {
+		f.Selections = p.selectionSet()
+	}
+	return f
+}
+
+func (p *parser) arguments(constant bool) []*Argument {
+	if !p.skip("(") {
+		return nil
+	}
+	var args []*Argument
+	for !p.skip(")") {
+		arg := &Argument{Name: p.name()}
+		p.expect(":")
+		arg.Value = p.value(constant)
+		args = append(args, arg)
+	}
+	return args
+}
+
+func (p *parser) directives() []*Directive {
+	var directives []*Directive
+	for p.skip("@") {
+		directives = append(directives, &Directive{Name: p.name(), Args: p.arguments(false)})
+	}
+	return directives
+}
+
+// value 解析参数值，constant 为 true 时（变量默认值）不允许引用变量
+func (p *parser) value(constant bool) Value {
+	tok := p.tok
+	switch tok.kind {
+	case tokInt:
+		p.next()
+		n, err := strconv.ParseInt(tok.value, 10, 64)
+		if err != nil {
+			p.failAt(tok.pos, "整数 %s 超出范围", tok.value)
+		}
+		return n
+	case tokFloat:
+		p.next()
+		f, err := strconv.ParseFloat(tok.value, 64)
+		if err != nil {
+			p.failAt(tok.pos, "无效的数字 %s", tok.value)
+		}
+		return f
+	case tokString:
+		p.next()
+		return tok.value
+	case tokName:
+		p.next()
+		switch tok.value {
+		case "true":
+			return true
+		case "false":
+			return false
+		case "null":
+			return nil
+		}
+		return EnumValue(tok.value)
+	}
+
+	switch {
+	case p.skip("$"):
+		if constant {
+			p.failAt(tok.pos, "此处不能引用变量")
+		}
+		return Variable(p.name())
+	case p.skip("["):
+		p.enterValue()
+		defer p.leaveValue()
+		list := []Value{}
+		for !p.skip("]") {
+			list = append(list, p.value(constant))
+		}
+		return list
+	case p.skip("{"):
+		p.enterValue()
+		defer p.leaveValue()
+		obj := map[string]Value{}
+		for !p.skip("}") {
+			name := p.name()
+			p.expect(":")
+			obj[name] = p.value(constant)
+		}
+		return obj
+	}
+	p.fail("需要参数值，得到 %q", tok.value)
+	return nil
+}
+
+func (p *parser) enterValue() {
+	p.nesting++
+	if p.nesting > maxValueDepth {
+		p.fail("参数值嵌套超过 %d 层", maxValueDepth)
+	}
+}
+
+func (p *parser) leaveValue() {
+	p.nesting--
+}
+
+func (p *parser) name() string {
+	if p.tok.kind != tokName {
+		p.fail("需要名称，得到 %q", p.tok.value)
+	}
+	name := p.tok.value
+	p.next()
+	return name
+}
+
+func (p *parser) peek(punct string) bool {
+	return p.tok.kind == tokPunct && p.tok.value == punct
+}
+
+func (p *parser) skip(punct string) bool {
+	if p.peek(punct) {
+		p.next()
+		return true
+	}
+	return false
+}
+
+func (p *parser) expect(punct string) {
+	if !p.skip(punct) {
+		p.fail("需要 %q，得到 %q", punct, p.tok.value)
+	}
+}
+
+func (p *parser) fail(format string, args ...interface{}) {
+	p.failAt(p.tok.pos, format, args...)
+}
+
+func (p *parser) failAt(pos int, format string, args ...interface{}) {
+	line := 1 + strings.Count(p.src[:pos], "\n")
+	col := 1 + utf8.RuneCountInString(p.src[strings.LastIndex(p.src[:pos], "\n")+1:pos])
+	panic(&SyntaxError{Line: line, Column: col, Message: fmt.Sprintf(format, args...)})
+}
+
+// next 读取下一个词法单元，跳过空白、逗号与 # 注释
+func (p *parser) next() {
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		if c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == ',' {
+			p.pos++
+			continue
+		}
+		if c == '#' {
+			for p.pos < len(p.src) && p.src[p.pos] != '\n' {
+				p.pos++
+			}
+			continue
+		}
+		break
+	}
+	start := p.pos
+	if p.pos >= len(p.src) {
+		p.tok = token{kind: tokEOF, value: "<EOF>", pos: start}
+		return
+	}
+
+	c := p.src[p.pos]
+	switch {
+	case strings.HasPrefix(p.src[p.pos:], "..."):
+		p.pos += 3
+		p.tok = token{kind: tokPunct, value: "...", pos: start}
+	case strings.IndexByte("!$():=@[]{}", c) >= 0:
+		p.pos++
+		p.tok = token{kind: tokPunct, value: string(c), pos: start}
+	case c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z':
+		for p.pos < len(p.src) && isNameChar(p.src[p.pos]) {
+			p.pos++
+		}
+		p.tok = token{kind: tokName, value: p.src[start:p.pos], pos: start}
+	case c == '-' || c >= '0' && c <= '9':
+		p.number()
+	case c == '"':
+		p.string()
+	default:
+		r, _ := utf8.DecodeRuneInString(p.src[p.pos:])
+		p.failAt(start, "无法识别的字符 %q", r)
+	}
+}
+
+func isNameChar(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func (p *parser) number() {
+	start := p.pos
+	kind := tokInt
+	if p.src[p.pos] == '-' {
+		p.pos++
+	}
+	digits := func() {
+		n := p.pos
+		for p.pos < len(p.src) && isDigit(p.src[p.pos]) {
+			p.pos++
+		}
+		if p.pos == n {
+			p.failAt(p.pos, "无效的数字")
+		}
+	}
+	digits()
+	if p.pos < len(p.src) && p.src[p.pos] == '.' {
+		kind = tokFloat
+		p.pos++
+		digits()
+	}
+	if p.pos < len(p.src) && (p.src[p.pos] == 'e' || p.src[p.pos] == 'E') {
+		kind = tokFloat
+		p.pos++
+		if p.pos < len(p.src) && (p.src[p.pos] == '+' || p.src[p.pos] == '-') {
+			p.pos++
+		}
+		digits()
+	}
+	if p.pos < len(p.src) && (isNameChar(p.src[p.pos]) || p.src[p.pos] == '.') {
+		p.failAt(p.pos, "无效的数字")
+	}
+	p.tok = token{kind: kind, value: p.src[start:p.pos], pos: start}
+}
+
+// string 读取普通字符串或 """ 块字符串；块字符串不做缩进处理，只去掉首尾空行
+func (p *parser) string() {
+	start := p.pos
+	if strings.HasPrefix(p.src[p.pos:], `"""`) {
+		end := strings.Index(p.src[p.pos+3:], `"""`)
+		if end < 0 {
+			p.failAt(start, "块字符串未结束")
+		}
+		raw := p.src[p.pos+3 : p.pos+3+end]
+		p.pos += end + 6
+		p.tok = token{kind: tokString, value: strings.Trim(raw, "\r\n"), pos: start}
+		return
+	}
+
+	var b strings.Builder
+	p.pos++
+	for {
+		if p.pos >= len(p.src) || p.src[p.pos] == '\n' {
+			p.failAt(start, "字符串未结束")
+		}
+		c := p.src[p.pos]
+		if c == '"' {
+			p.pos++
+			break
+		}
+		if c != '\\' {
+			b.WriteByte(c)
+			p.pos++
+			continue
+		}
+		if p.pos+1 >= len(p.src) {
+			p.failAt(start, "字符串未结束")
+		}
+		esc := p.src[p.pos+1]
+		p.pos += 2
+		switch esc {
+		case '"', '\\', '/':
+			b.WriteByte(esc)
+		case 'b':
+			b.WriteByte('\b')
+		case 'f':
+			b.WriteByte('\f')
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case 't':
+			b.WriteByte('\t')
+		case 'u':
+			if p.pos+4 > len(p.src) {
+				p.failAt(p.pos, "无效的 Unicode 转义")
+			}
+			n, err := strconv.ParseUint(p.src[p.pos:p.pos+4], 16, 32)
+			if err != nil {
+				p.failAt(p.pos, "无效的 Unicode 转义")
+			}
+			b.WriteRune(rune(n))
+			p.pos += 4
+		default:
+			p.failAt(p.pos-2, "无效的转义字符 \\%c", esc)
+		}
+	}
+	p.tok = token{kind: tokString, value: b.String(), pos: start}
+}
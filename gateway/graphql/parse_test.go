@@ -0,0 +1,147 @@
+package graphql
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseQuery(t *testing.T) {
+	doc, err := Parse(`
+		# 注释与逗号会被忽略
+		query Material($id: ID!, $limit: Int = 10, $tags: [String!]) {
+			m: material(id: $id) {
+				title,
+				...Counts @include(if: true)
+				... on Material { id }
+			}
+		}
+		fragment Counts on Material {
+			questions(limit: $limit, filter: {types: [ESSAY, "short"], min: -1.5e2}) { id }
+		}
+	`, 0)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if len(doc.Operations) != 1 {
+		t.Fatalf("expected 1 operation, got %d", len(doc.Operations))
+	}
+	op := doc.Operations[0]
+	if op.Type != "query" || op.Name != "Material" {
+		t.Fatalf("unexpected operation %s %s", op.Type, op.Name)
+	}
+	if len(op.Variables) != 3 {
+		t.Fatalf("expected 3 variables, got %d", len(op.Variables))
+	}
+	if v := op.Variables[0]; v.Name != "id" || v.Type != "ID" || !v.NonNull {
+		t.Fatalf("unexpected variable %+v", v)
+	}
+	if v := op.Variables[1]; !v.HasDefault || v.Default != int64(10) {
+		t.Fatalf("unexpected default %+v", v)
+	}
+	if v := op.Variables[2]; v.Type != "[String!]" || v.NonNull {
+		t.Fatalf("unexpected list variable %+v", v)
+	}
+
+	field := op.Selections[0].(*Field)
+	if field.ResponseKey() != "m" || field.Name != "material" {
+		t.Fatalf("unexpected field %s: %s", field.Alias, field.Name)
+	}
+	if arg := field.Args[0]; arg.Name != "id" || arg.Value != Variable("id") {
+		t.Fatalf("unexpected argument %+v", arg)
+	}
+	if len(field.Selections) != 3 {
+		t.Fatalf("expected 3 selections, got %d", len(field.Selections))
+	}
+	spread := field.Selections[1].(*FragmentSpread)
+	if spread.Name != "Counts" || spread.Directives[0].Name != "include" {
+		t.Fatalf("unexpected spread %+v", spread)
+	}
+	if inline := field.Selections[2].(*InlineFragment); inline.TypeCondition != "Material" {
+		t.Fatalf("unexpected inline fragment %+v", inline)
+	}
+
+	frag := doc.Fragments["Counts"]
+	if frag == nil || frag.TypeCondition != "Material" {
+		t.Fatalf("fragment Counts not parsed: %+v", frag)
+	}
+	filter := frag.Selections[0].(*Field).Args[1].Value.(map[string]Value)
+	types := filter["types"].([]Value)
+	if types[0] != EnumValue("ESSAY") || types[1] != "short" || filter["min"] != -150.0 {
+		t.Fatalf("unexpected filter %+v", filter)
+	}
+}
+
+func TestParseStrings(t *testing.T) {
+	doc, err := Parse(`{ a(s: "x\"中\n", b: """
+块字符串 "引号"
+""") }`, 0)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	args := doc.Operations[0].Selections[0].(*Field).Args
+	if args[0].Value != "x\"中\n" {
+		t.Fatalf("unexpected string %q", args[0].Value)
+	}
+	if args[1].Value != `块字符串 "引号"` {
+		t.Fatalf("unexpected block string %q", args[1].Value)
+	}
+}
+
+func TestParseSyntaxErrors(t *testing.T) {
+	cases := map[string]string{
+		"":                                     "查询中没有操作",
+		"{}":                                   "选择集不能为空",
+		"{ a(x: $v) }\nfragment on on T { a }": "片段名不能为 on",
+		"{ a }\nfragment F on T { a }\nfragment F on T { b }": "片段 F 重复定义",
+		"query ($v: Int = $w) { a }":                          "此处不能引用变量",
+		"{ a(x: 99999999999999999999) }":                      "超出范围",
+		"{ a(x: \"abc) }":                                     "字符串未结束",
+		"{ a ? }":                                             "无法识别的字符",
+	}
+	for src, want := range cases {
+		_, err := Parse(src, 0)
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("Parse(%q): expected error containing %q, got %v", src, want, err)
+		}
+	}
+}
+
+func TestParseSyntaxErrorPosition(t *testing.T) {
+	_, err := Parse("{\n  a(x: )\n}", 0)
+	var se *SyntaxError
+	if !errors.As(err, &se) {
+		t.Fatalf("expected *SyntaxError, got %v", err)
+	}
+	if se.Line != 2 || se.Column != 8 {
+		t.Fatalf("expected line 2 column 8, got line %d column %d", se.Line, se.Column)
+	}
+}
+
+func TestParseRejectsDeepSelectionSets(t *testing.T) {
+	// 未加限制时约 50 万层递归，须在解析阶段直接拒绝
+	src := strings.Repeat("{a", 500000) + strings.Repeat("}", 500000)
+	_, err := Parse(src, 8)
+	if err == nil || !strings.Contains(err.Error(), "查询嵌套超过 8 层") {
+		t.Fatalf("expected depth error, got %v", err)
+	}
+
+	// 恰好达到上限时可以解析
+	src = strings.Repeat("{a", 8) + strings.Repeat("}", 8)
+	if _, err := Parse(src, 8); err != nil {
+		t.Fatalf("Parse at the depth limit: %v", err)
+	}
+}
+
+func TestParseRejectsDeepValues(t *testing.T) {
+	for _, src := range []string{
+		"{ a(x: " + strings.Repeat("[", 100000) + ") }",
+		"{ a(x: " + strings.Repeat("{k: ", 100000) + ") }",
+		"query ($v: " + strings.Repeat("[", 100000) + ") { a }",
+	} {
+		_, err := Parse(src, 0)
+		if err == nil || !strings.Contains(err.Error(), "参数值嵌套超过") {
+			t.Errorf("expected nesting error, got %v", err)
+		}
+	}
+}
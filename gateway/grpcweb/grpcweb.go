@@ -0,0 +1,438 @@
+// Package grpcweb 将选定的后端 gRPC 方法以 gRPC-Web 与 Connect 协议开放给浏览器，
+// 前端可直接使用由 proto 生成的类型化客户端（如 @connectrpc/connect-web、grpc-web），服务端流无需 SSE。
+//
+// 支持的请求格式（按 Content-Type 区分）：
+//   - gRPC-Web：application/grpc-web[+proto]、application/grpc-web-text[+proto]，一元与服务端流
+//   - Connect 一元：application/proto、application/json
+//   - Connect 流：application/connect+proto、application/connect+json（仅服务端流）
+//
+// gateway 解码请求后将其中的 user_id 改写为当前登录用户，再经 backend 的共享连接转发，
+// 因此只开放请求消息带有 user_id 字段的方法，见 NewMethod。
+package grpcweb
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// 帧头标志位
+const (
+	flagTrailer    = 0x80 // gRPC-Web 的 trailer 帧
+	flagEndStream  = 0x02 // Connect 流的结束帧
+	frameHeaderLen = 5
+)
+
+// 调用的默认与最长超时，客户端可通过 grpc-timeout / Connect-Timeout-Ms 缩短
+const (
+	unaryTimeout  = 30 * time.Second
+	streamTimeout = 5 * time.Minute
+)
+
+// 请求协议
+type protocol int
+
+const (
+	protocolGRPCWeb protocol = iota
+	protocolGRPCWebText
+	protocolConnectUnary
+	protocolConnectStream
+)
+
+// Method 开放的后端方法
+type Method struct {
+	// Path 为路由路径中的方法部分，如 /llm.LLMService/AskQuestionStream
+	Path string
+	// OnRequest 在转发前校验已改写 user_id 的请求（可选），返回 gRPC status 错误时按其错误码响应，其余错误视为 InvalidArgument
+	OnRequest func(c *gin.Context, req proto.Message) error
+	// OnMessage 在每条响应消息转发前调用（可选），如从流的最后一个分片中读取 token 用量
+	OnMessage func(c *gin.Context, msg proto.Message)
+
+	conn      grpc.ClientConnInterface
+	desc      protoreflect.MethodDescriptor
+	input     protoreflect.MessageType
+	output    protoreflect.MessageType
+	userField protoreflect.FieldDescriptor
+}
+
+// NewMethod 按全名（如 llm.LLMService.AskQuestionStream）查找已注册的方法描述，conn 为其所在服务的连接。
+// 只接受一元或服务端流方法，且请求消息须有 string 类型的 user_id 字段
+func NewMethod(conn grpc.ClientConnInterface, fullName string) (*Method, error) {
+	d, err := protoregistry.GlobalFiles.FindDescriptorByName(protoreflect.FullName(fullName))
+	if err != nil {
+		return nil, fmt.Errorf("grpcweb: method %s not found: %w", fullName, err)
+	}
+	desc, ok := d.(protoreflect.MethodDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("grpcweb: %s is not a method", fullName)
+	}
+	if desc.IsStreamingClient() {
+		return nil, fmt.Errorf("grpcweb: client streaming method %s is not supported", fullName)
+	}
+	input, err := protoregistry.GlobalTypes.FindMessageByName(desc.Input().FullName())
+	if err != nil {
+		return nil, fmt.Errorf("grpcweb: input type of %s: %w", fullName, err)
+	}
+	output, err := protoregistry.GlobalTypes.FindMessageByName(desc.Output().FullName())
+	if err != nil {
+		return nil, fmt.Errorf("grpcweb: output type of %s: %w", fullName, err)
+	}
+	userField := desc.Input().Fields().ByName("user_id")
+	if userField == nil || userField.Kind() != protoreflect.StringKind || userField.IsList() {
+		return nil, fmt.Errorf("grpcweb: %s has no string user_id field to scope the call to the caller", desc.Input().FullName())
+	}
+	return &Method{
+		Path:      "/" + string(desc.Parent().FullName()) + "/" + string(desc.Name()),
+		conn:      conn,
+		desc:      desc,
+		input:     input,
+		output:    output,
+		userField: userField,
+	}, nil
+}
+
+// Streaming 是否为服务端流方法
+func (m *Method) Streaming() bool {
+	return m.desc.IsStreamingServer()
+}
+
+// Handler 返回处理该方法的 gin 处理函数，rpcContext 为转发使用的 ctx（携带请求 ID），
+// 当前用户取自认证中间件设置的 user_id
+func (m *Method) Handler(rpcContext func(*gin.Context) context.Context) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		prot, codec, ok := detectProtocol(c.ContentType())
+		if !ok {
+			c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": "unsupported content type " + c.ContentType()})
+			return
+		}
+		w := &responseWriter{c: c, protocol: prot, codec: codec}
+		if prot == protocolConnectUnary && m.Streaming() {
+			w.error(status.Errorf(codes.InvalidArgument, "streaming method %s requires the Connect streaming protocol", m.Path))
+			return
+		}
+		if prot == protocolConnectStream && !m.Streaming() {
+			w.error(status.Errorf(codes.InvalidArgument, "unary method %s requires the Connect unary protocol", m.Path))
+			return
+		}
+
+		userID := c.GetString("user_id")
+		if userID == "" {
+			w.error(status.Error(codes.Unauthenticated, "user not authenticated"))
+			return
+		}
+		req, err := m.readRequest(c, prot, codec)
+		if err != nil {
+			w.error(status.Error(codes.InvalidArgument, err.Error()))
+			return
+		}
+		req.ProtoReflect().Set(m.userField, protoreflect.ValueOfString(userID))
+		if m.OnRequest != nil {
+			if err := m.OnRequest(c, req); err != nil {
+				if _, ok := status.FromError(err); !ok {
+					err = status.Error(codes.InvalidArgument, err.Error())
+				}
+				w.error(err)
+				return
+			}
+		}
+
+		timeout := unaryTimeout
+		if m.Streaming() {
+			timeout = streamTimeout
+		}
+		if d, ok := requestTimeout(c); ok && d < timeout {
+			timeout = d
+		}
+		ctx, cancel := context.WithTimeout(rpcContext(c), timeout)
+		defer cancel()
+		// 客户端断开时取消流式调用，避免后端继续生成
+		if m.Streaming() {
+			stop := context.AfterFunc(c.Request.Context(), cancel)
+			defer stop()
+		}
+
+		if !m.Streaming() {
+			resp := m.output.New().Interface()
+			if err := m.conn.Invoke(ctx, m.Path, req, resp); err != nil {
+				w.error(err)
+				return
+			}
+			if m.OnMessage != nil {
+				m.OnMessage(c, resp)
+			}
+			w.unary(resp)
+			return
+		}
+
+		stream, err := m.conn.NewStream(ctx, &grpc.StreamDesc{StreamName: string(m.desc.Name()), ServerStreams: true}, m.Path)
+		if err == nil {
+			err = stream.SendMsg(req)
+		}
+		if err == nil {
+			err = stream.CloseSend()
+		}
+		if err != nil {
+			w.error(err)
+			return
+		}
+		for {
+			msg := m.output.New().Interface()
+			if err := stream.RecvMsg(msg); err != nil {
+				if errors.Is(err, io.EOF) {
+					err = nil
+				}
+				w.end(err)
+				return
+			}
+			if m.OnMessage != nil {
+				m.OnMessage(c, msg)
+			}
+			if err := w.message(msg); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// detectProtocol 按 Content-Type 判断协议与消息编码（proto 或 json）
+func detectProtocol(contentType string) (protocol, string, bool) {
+	switch contentType {
+	case "application/grpc-web", "application/grpc-web+proto":
+		return protocolGRPCWeb, "proto", true
+	case "application/grpc-web-text", "application/grpc-web-text+proto":
+		return protocolGRPCWebText, "proto", true
+	case "application/proto":
+		return protocolConnectUnary, "proto", true
+	case "application/json":
+		return protocolConnectUnary, "json", true
+	case "application/connect+proto":
+		return protocolConnectStream, "proto", true
+	case "application/connect+json":
+		return protocolConnectStream, "json", true
+	}
+	return 0, "", false
+}
+
+// readRequest 解码请求消息：gRPC-Web 与 Connect 流的请求体为单个数据帧，Connect 一元为消息本身
+func (m *Method) readRequest(c *gin.Context, prot protocol, codec string) (proto.Message, error) {
+	var body io.Reader = c.Request.Body
+	if prot == protocolGRPCWebText {
+		body = base64.NewDecoder(base64.StdEncoding, body)
+	}
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request: %v", err)
+	}
+	if prot != protocolConnectUnary {
+		if len(data) < frameHeaderLen || data[0] != 0 {
+			return nil, fmt.Errorf("request must be a single uncompressed message frame")
+		}
+		n := binary.BigEndian.Uint32(data[1:frameHeaderLen])
+		if uint64(len(data)-frameHeaderLen) != uint64(n) {
+			return nil, fmt.Errorf("request frame length mismatch")
+		}
+		data = data[frameHeaderLen:]
+	}
+
+	req := m.input.New().Interface()
+	if codec == "json" {
+		err = protojson.UnmarshalOptions{DiscardUnknown: true}.Unmarshal(data, req)
+	} else {
+		err = proto.Unmarshal(data, req)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid request message: %v", err)
+	}
+	return req, nil
+}
+
+// requestTimeout 读取 gRPC-Web 的 grpc-timeout（如 10S、500m）或 Connect 的 Connect-Timeout-Ms
+func requestTimeout(c *gin.Context) (time.Duration, bool) {
+	if v := c.GetHeader("Connect-Timeout-Ms"); v != "" {
+		ms, err := strconv.ParseInt(v, 10, 64)
+		return time.Duration(ms) * time.Millisecond, err == nil && ms > 0
+	}
+	v := c.GetHeader("grpc-timeout")
+	if len(v) < 2 {
+		return 0, false
+	}
+	n, err := strconv.ParseInt(v[:len(v)-1], 10, 64)
+	if err != nil || n <= 0 {
+		return 0, false
+	}
+	units := map[byte]time.Duration{'H': time.Hour, 'M': time.Minute, 'S': time.Second, 'm': time.Millisecond, 'u': time.Microsecond, 'n': time.Nanosecond}
+	unit, ok := units[v[len(v)-1]]
+	return time.Duration(n) * unit, ok
+}
+
+// responseWriter 按请求协议写出响应消息与最终状态
+type responseWriter struct {
+	c        *gin.Context
+	protocol protocol
+	codec    string
+	started  bool
+}
+
+func (w *responseWriter) marshal(msg proto.Message) ([]byte, error) {
+	if w.codec == "json" {
+		return protojson.Marshal(msg)
+	}
+	return proto.Marshal(msg)
+}
+
+func (w *responseWriter) start() {
+	if w.started {
+		return
+	}
+	w.started = true
+	var contentType string
+	switch w.protocol {
+	case protocolGRPCWeb:
+		contentType = "application/grpc-web+proto"
+	case protocolGRPCWebText:
+		contentType = "application/grpc-web-text+proto"
+	case protocolConnectStream:
+		contentType = "application/connect+" + w.codec
+	}
+	w.c.Header("Content-Type", contentType)
+	w.c.Status(http.StatusOK)
+}
+
+// frame 写出一个帧，gRPC-Web 文本格式逐帧 base64 编码
+func (w *responseWriter) frame(flag byte, payload []byte) error {
+	w.start()
+	buf := make([]byte, frameHeaderLen+len(payload))
+	buf[0] = flag
+	binary.BigEndian.PutUint32(buf[1:frameHeaderLen], uint32(len(payload)))
+	copy(buf[frameHeaderLen:], payload)
+	if w.protocol == protocolGRPCWebText {
+		buf = []byte(base64.StdEncoding.EncodeToString(buf))
+	}
+	if _, err := w.c.Writer.Write(buf); err != nil {
+		return err
+	}
+	w.c.Writer.Flush()
+	return nil
+}
+
+// message 写出流中的一条消息
+func (w *responseWriter) message(msg proto.Message) error {
+	data, err := w.marshal(msg)
+	if err != nil {
+		return err
+	}
+	return w.frame(0, data)
+}
+
+// unary 写出一元调用的成功响应
+func (w *responseWriter) unary(msg proto.Message) {
+	if w.protocol != protocolConnectUnary {
+		if err := w.message(msg); err == nil {
+			w.end(nil)
+		}
+		return
+	}
+	data, err := w.marshal(msg)
+	if err != nil {
+		w.error(status.Error(codes.Internal, err.Error()))
+		return
+	}
+	w.c.Data(http.StatusOK, "application/"+w.codec, data)
+}
+
+// end 写出流的结束状态：gRPC-Web 为 trailer 帧，Connect 流为结束帧
+func (w *responseWriter) end(err error) {
+	st := status.Convert(err)
+	switch w.protocol {
+	case protocolGRPCWeb, protocolGRPCWebText:
+		trailer := fmt.Sprintf("grpc-status: %d\r\ngrpc-message: %s\r\n", st.Code(), percentEncode(st.Message()))
+		_ = w.frame(flagTrailer, []byte(trailer))
+	case protocolConnectStream:
+		end := map[string]interface{}{}
+		if err != nil {
+			end["error"] = connectError(st)
+		}
+		data, _ := json.Marshal(end)
+		_ = w.frame(flagEndStream, data)
+	}
+}
+
+// error 写出调用失败：Connect 一元按错误码返回 HTTP 状态与 JSON 错误，其余协议以结束状态返回
+func (w *responseWriter) error(err error) {
+	if w.protocol != protocolConnectUnary {
+		w.end(err)
+		return
+	}
+	st := status.Convert(err)
+	w.c.JSON(connectHTTPStatus(st.Code()), connectError(st))
+}
+
+func connectError(st *status.Status) gin.H {
+	return gin.H{"code": connectCode(st.Code()), "message": st.Message()}
+}
+
+// connectCode 返回 Connect 协议中的错误码名称，如 not_found
+func connectCode(code codes.Code) string {
+	name := code.String()
+	var b strings.Builder
+	for i, r := range name {
+		if r >= 'A' && r <= 'Z' {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			r += 'a' - 'A'
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// connectHTTPStatus Connect 协议规定的错误码与 HTTP 状态码对应关系
+func connectHTTPStatus(code codes.Code) int {
+	switch code {
+	case codes.Canceled:
+		return 499
+	case codes.InvalidArgument, codes.FailedPrecondition, codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	}
+	return http.StatusInternalServerError
+}
+
+// percentEncode 按 gRPC 规范对 grpc-message 做百分号编码
+func percentEncode(s string) string {
+	return strings.ReplaceAll(url.PathEscape(s), "%20", " ")
+}
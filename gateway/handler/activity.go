@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"os"
+	"time"
+
+	arkkafka "github.com/RigelNana/arkstudy/pkg/kafka"
+	kafkaMetrics "github.com/RigelNana/arkstudy/pkg/metrics/kafka"
+	"github.com/google/uuid"
+	kafka "github.com/segmentio/kafka-go"
+)
+
+// 用户活动类型，与 user-service models 中的定义保持一致
+const (
+	ActivityUpload         = "upload"
+	ActivityQuizAttempt    = "quiz_attempt"
+	ActivityAIQuestion     = "ai_question"
+	ActivityMaterialViewed = "material_viewed"
+	// 下载或播放资料原文件
+	ActivityMaterialDownloaded = "material_downloaded"
+)
+
+// 活动摘要的最大长度（按字符），避免把完整问题文本写入时间线
+const maxActivitySummaryRunes = 200
+
+// activityEvent 为发布到 user.activity topic 的消息结构，由 user-service 消费落库
+type activityEvent struct {
+	EventID    string            `json:"event_id"`
+	UserID     string            `json:"user_id"`
+	Type       string            `json:"type"`
+	TargetID   string            `json:"target_id"`
+	Summary    string            `json:"summary"`
+	Metadata   map[string]string `json:"metadata,omitempty"`
+	OccurredAt time.Time         `json:"occurred_at"`
+}
+
+// ActivityRecorder 将用户活动异步发布到 Kafka。未配置 KAFKA_BROKERS 时为空操作，
+// nil 接收者同样可以安全调用。
+type ActivityRecorder struct {
+	writer *kafka.Writer
+}
+
+// NewActivityRecorder 使用 env KAFKA_BROKERS 与 KAFKA_TOPIC_USER_ACTIVITY（默认 user.activity）创建记录器
+func NewActivityRecorder() *ActivityRecorder {
+	brokers := arkkafka.SplitBrokers(os.Getenv("KAFKA_BROKERS"))
+	if len(brokers) == 0 {
+		log.Printf("KAFKA_BROKERS not set, user activity recording disabled")
+		return &ActivityRecorder{}
+	}
+	topic := os.Getenv("KAFKA_TOPIC_USER_ACTIVITY")
+	if topic == "" {
+		topic = arkkafka.UserActivity.Name
+	}
+	w := arkkafka.NewWriter(brokers, topic)
+	// 异步发送，不阻塞请求处理；失败只记录日志
+	w.Async = true
+	w.Completion = func(messages []kafka.Message, err error) {
+		if err != nil {
+			log.Printf("Warning: failed to publish %d user activity events: %v", len(messages), err)
+		}
+	}
+	return &ActivityRecorder{writer: kafkaMetrics.InstrumentWriter("gateway", w)}
+}
+
+// Record 记录一条用户活动。消息 key 为 user_id，保证同一用户的活动有序
+func (r *ActivityRecorder) Record(userID, activityType, targetID, summary string, metadata map[string]string) {
+	if r == nil || r.writer == nil || userID == "" {
+		return
+	}
+	if runes := []rune(summary); len(runes) > maxActivitySummaryRunes {
+		summary = string(runes[:maxActivitySummaryRunes]) + "…"
+	}
+	value, err := json.Marshal(activityEvent{
+		EventID:    uuid.New().String(),
+		UserID:     userID,
+		Type:       activityType,
+		TargetID:   targetID,
+		Summary:    summary,
+		Metadata:   metadata,
+		OccurredAt: time.Now().UTC(),
+	})
+	if err != nil {
+		log.Printf("Warning: failed to marshal user activity: %v", err)
+		return
+	}
+	if err := r.writer.WriteMessages(context.Background(), kafka.Message{Key: arkkafka.UserKey(userID), Value: value}); err != nil {
+		log.Printf("Warning: failed to enqueue user activity: %v", err)
+	}
+}
@@ -2,15 +2,18 @@ package handler
 
 import (
 	"context"
+	"fmt"
 	"net/http"
-	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 
+	"github.com/RigelNana/arkstudy/gateway/backend"
+	"github.com/RigelNana/arkstudy/gateway/middleware"
+	"github.com/RigelNana/arkstudy/pkg/discovery"
+	"github.com/RigelNana/arkstudy/pkg/langdetect"
+	"github.com/RigelNana/arkstudy/pkg/registry"
 	"github.com/RigelNana/arkstudy/proto/asr"
 )
 
@@ -19,14 +22,10 @@ type ASRHandler struct {
 	logger *logrus.Logger
 }
 
-func NewASRHandler(serviceAddr string, logger *logrus.Logger) *ASRHandler {
-	// Create gRPC connection to ASR service
-	conn, err := grpc.Dial(serviceAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
-	if err != nil {
-		logger.WithError(err).Fatal("Failed to connect to ASR service")
-	}
-
-	client := asr.NewASRServiceClient(conn)
+func NewASRHandler(logger *logrus.Logger) *ASRHandler {
+	// The connection to ASR service is established on first use
+	logger.Infof("ASR service target: %s", discovery.Target(registry.ASR))
+	client := asr.NewASRServiceClient(backend.For(registry.ASR))
 
 	return &ASRHandler{
 		client: client,
@@ -34,15 +33,42 @@ func NewASRHandler(serviceAddr string, logger *logrus.Logger) *ASRHandler {
 	}
 }
 
+// userID extracts the authenticated user ID set by the auth middleware
+func (h *ASRHandler) userID(c *gin.Context) (string, bool) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"success": false,
+			"message": "User not authenticated",
+		})
+		return "", false
+	}
+	userIDStr, ok := userID.(string)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Invalid user ID format",
+		})
+		return "", false
+	}
+	return userIDStr, true
+}
+
 // ProcessVideo handles video ASR processing requests
 func (h *ASRHandler) ProcessVideo(c *gin.Context) {
 	h.logger.Info("Received ASR process video request")
 
+	userID, ok := h.userID(c)
+	if !ok {
+		return
+	}
+
 	// Parse request JSON
 	var req struct {
-		MaterialID uint   `json:"material_id" binding:"required"`
+		MaterialID string `json:"material_id" binding:"required"`
 		VideoURL   string `json:"video_url"`
 		VideoPath  string `json:"video_path"`
+		TaskID     string `json:"task_id"`
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -56,13 +82,15 @@ func (h *ASRHandler) ProcessVideo(c *gin.Context) {
 
 	// Create gRPC request
 	grpcReq := &asr.ProcessVideoRequest{
-		MaterialId: uint64(req.MaterialID),
+		MaterialId: req.MaterialID,
+		UserId:     userID,
 		VideoUrl:   req.VideoURL,
 		VideoPath:  req.VideoPath,
+		TaskId:     req.TaskID,
 	}
 
 	// Call ASR service
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := context.WithTimeout(middleware.RPCContext(c), 30*time.Second)
 	defer cancel()
 
 	resp, err := h.client.ProcessVideo(ctx, grpcReq)
@@ -85,35 +113,34 @@ func (h *ASRHandler) ProcessVideo(c *gin.Context) {
 			"end_time":         segment.EndTime,
 			"text":             segment.Text,
 			"confidence":       segment.Confidence,
+			"low_confidence":   segment.LowConfidence,
+			"model":            segment.Model,
 			"embedding_vector": segment.EmbeddingVector,
-			"created_at":       segment.CreatedAt,
-			"updated_at":       segment.UpdatedAt,
+			"created_at":       formatTimestamp(c, segment.CreatedAt),
+			"updated_at":       formatTimestamp(c, segment.UpdatedAt),
 		}
 	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"success":  resp.Success,
 		"message":  resp.Message,
+		"task_id":  resp.TaskId,
 		"segments": segments,
 	})
 }
 
 // GetSegments retrieves ASR segments for a material
 func (h *ASRHandler) GetSegments(c *gin.Context) {
-	materialIDStr := c.Param("material_id")
-	if materialIDStr == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"message": "Material ID is required",
-		})
+	userID, ok := h.userID(c)
+	if !ok {
 		return
 	}
 
-	materialID, err := strconv.ParseUint(materialIDStr, 10, 64)
-	if err != nil {
+	materialID := c.Param("material_id")
+	if materialID == "" {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"success": false,
-			"message": "Invalid material ID",
+			"message": "Material ID is required",
 		})
 		return
 	}
@@ -123,10 +150,11 @@ func (h *ASRHandler) GetSegments(c *gin.Context) {
 	// Create gRPC request
 	grpcReq := &asr.GetSegmentsRequest{
 		MaterialId: materialID,
+		UserId:     userID,
 	}
 
 	// Call ASR service
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(middleware.RPCContext(c), 10*time.Second)
 	defer cancel()
 
 	resp, err := h.client.GetSegments(ctx, grpcReq)
@@ -149,9 +177,11 @@ func (h *ASRHandler) GetSegments(c *gin.Context) {
 			"end_time":         segment.EndTime,
 			"text":             segment.Text,
 			"confidence":       segment.Confidence,
+			"low_confidence":   segment.LowConfidence,
+			"model":            segment.Model,
 			"embedding_vector": segment.EmbeddingVector,
-			"created_at":       segment.CreatedAt,
-			"updated_at":       segment.UpdatedAt,
+			"created_at":       formatTimestamp(c, segment.CreatedAt),
+			"updated_at":       formatTimestamp(c, segment.UpdatedAt),
 		}
 	}
 
@@ -166,11 +196,16 @@ func (h *ASRHandler) GetSegments(c *gin.Context) {
 func (h *ASRHandler) SearchSegments(c *gin.Context) {
 	h.logger.Info("Received ASR search segments request")
 
+	userID, ok := h.userID(c)
+	if !ok {
+		return
+	}
+
 	// Parse request JSON
 	var req struct {
-		Query      string `json:"query" binding:"required"`
-		MaterialID *uint  `json:"material_id,omitempty"`
-		Limit      *int   `json:"limit,omitempty"`
+		Query      string  `json:"query" binding:"required"`
+		MaterialID *string `json:"material_id,omitempty"`
+		Limit      *int    `json:"limit,omitempty"`
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -184,11 +219,12 @@ func (h *ASRHandler) SearchSegments(c *gin.Context) {
 
 	// Create gRPC request
 	grpcReq := &asr.SearchSegmentsRequest{
-		Query: req.Query,
+		Query:  req.Query,
+		UserId: userID,
 	}
 
 	if req.MaterialID != nil {
-		grpcReq.MaterialId = uint64(*req.MaterialID)
+		grpcReq.MaterialId = *req.MaterialID
 	}
 
 	if req.Limit != nil {
@@ -196,7 +232,7 @@ func (h *ASRHandler) SearchSegments(c *gin.Context) {
 	}
 
 	// Call ASR service
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(middleware.RPCContext(c), 10*time.Second)
 	defer cancel()
 
 	resp, err := h.client.SearchSegments(ctx, grpcReq)
@@ -219,9 +255,11 @@ func (h *ASRHandler) SearchSegments(c *gin.Context) {
 			"end_time":         segment.EndTime,
 			"text":             segment.Text,
 			"confidence":       segment.Confidence,
+			"low_confidence":   segment.LowConfidence,
+			"model":            segment.Model,
 			"embedding_vector": segment.EmbeddingVector,
-			"created_at":       segment.CreatedAt,
-			"updated_at":       segment.UpdatedAt,
+			"created_at":       formatTimestamp(c, segment.CreatedAt),
+			"updated_at":       formatTimestamp(c, segment.UpdatedAt),
 		}
 	}
 
@@ -232,6 +270,278 @@ func (h *ASRHandler) SearchSegments(c *gin.Context) {
 	})
 }
 
+// TranslateTranscript translates a material's transcript into bilingual subtitles
+func (h *ASRHandler) TranslateTranscript(c *gin.Context) {
+	userID, ok := h.userID(c)
+	if !ok {
+		return
+	}
+
+	materialID := c.Param("material_id")
+
+	var req struct {
+		TargetLanguage string `json:"target_language" binding:"required"`
+		Force          bool   `json:"force"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.WithError(err).Error("Failed to parse request body")
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid request format",
+		})
+		return
+	}
+	if !langdetect.ValidTag(req.TargetLanguage) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "target_language must be a language tag such as en or zh-CN",
+		})
+		return
+	}
+
+	h.logger.WithField("material_id", materialID).WithField("target_language", req.TargetLanguage).Info("Translating ASR transcript")
+
+	// Translation runs batched LLM calls, allow more time than other ASR calls
+	ctx, cancel := context.WithTimeout(middleware.RPCContext(c), 2*time.Minute)
+	defer cancel()
+
+	resp, err := h.client.TranslateTranscript(ctx, &asr.TranslateTranscriptRequest{
+		MaterialId:     materialID,
+		UserId:         userID,
+		TargetLanguage: req.TargetLanguage,
+		Force:          req.Force,
+	})
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to call ASR service")
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "ASR service call failed",
+		})
+		return
+	}
+
+	// Convert gRPC response to HTTP response
+	segments := make([]map[string]interface{}, len(resp.Segments))
+	for i, translated := range resp.Segments {
+		segment := translated.Segment
+		segments[i] = map[string]interface{}{
+			"id":              segment.GetId(),
+			"material_id":     segment.GetMaterialId(),
+			"start_time":      segment.GetStartTime(),
+			"end_time":        segment.GetEndTime(),
+			"text":            segment.GetText(),
+			"translated_text": translated.TranslatedText,
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":          resp.Success,
+		"message":          resp.Message,
+		"target_language":  resp.TargetLanguage,
+		"translated_count": resp.TranslatedCount,
+		"segments":         segments,
+	})
+}
+
+// GetChapters returns the chapter outline of a material for video navigation
+func (h *ASRHandler) GetChapters(c *gin.Context) {
+	userID, ok := h.userID(c)
+	if !ok {
+		return
+	}
+
+	materialID := c.Param("material_id")
+	regenerate := c.Query("regenerate") == "true"
+
+	h.logger.WithField("material_id", materialID).Info("Retrieving ASR chapters")
+
+	// Chapters may be detected on demand via the LLM
+	ctx, cancel := context.WithTimeout(middleware.RPCContext(c), 2*time.Minute)
+	defer cancel()
+
+	resp, err := h.client.GetChapters(ctx, &asr.GetChaptersRequest{
+		MaterialId: materialID,
+		UserId:     userID,
+		Regenerate: regenerate,
+	})
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to call ASR service")
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "ASR service call failed",
+		})
+		return
+	}
+
+	// Convert gRPC response to HTTP response
+	chapters := make([]map[string]interface{}, len(resp.Chapters))
+	for i, chapter := range resp.Chapters {
+		chapters[i] = map[string]interface{}{
+			"id":            chapter.Id,
+			"chapter_index": chapter.ChapterIndex,
+			"title":         chapter.Title,
+			"summary":       chapter.Summary,
+			"start_time":    chapter.StartTime,
+			"end_time":      chapter.EndTime,
+			"start_segment": chapter.StartSegment,
+			"end_segment":   chapter.EndSegment,
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":  resp.Success,
+		"message":  resp.Message,
+		"chapters": chapters,
+	})
+}
+
+// SearchMoments searches lecture moments across all videos of a collection and returns jump links
+func (h *ASRHandler) SearchMoments(c *gin.Context) {
+	userID, ok := h.userID(c)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Query            string   `json:"query" binding:"required"`
+		CollectionID     string   `json:"collection_id"`
+		MaterialIDs      []string `json:"material_ids"`
+		Limit            int32    `json:"limit"`
+		PerMaterialLimit int32    `json:"per_material_limit"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.WithError(err).Error("Failed to parse request body")
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid request format",
+		})
+		return
+	}
+	if req.CollectionID == "" && len(req.MaterialIDs) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "collection_id or material_ids is required",
+		})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(middleware.RPCContext(c), 15*time.Second)
+	defer cancel()
+
+	resp, err := h.client.SearchMoments(ctx, &asr.SearchMomentsRequest{
+		Query:            req.Query,
+		UserId:           userID,
+		CollectionId:     req.CollectionID,
+		MaterialIds:      req.MaterialIDs,
+		Limit:            req.Limit,
+		PerMaterialLimit: req.PerMaterialLimit,
+	})
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to call ASR service")
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "ASR service call failed",
+		})
+		return
+	}
+
+	// Jump links point at the authenticated media proxy with a media fragment,
+	// so players seek straight to the matching time
+	moments := make([]map[string]interface{}, len(resp.Moments))
+	for i, moment := range resp.Moments {
+		moments[i] = map[string]interface{}{
+			"material_id":    moment.MaterialId,
+			"material_title": moment.MaterialTitle,
+			"start_time":     moment.StartTime,
+			"end_time":       moment.EndTime,
+			"text":           moment.Text,
+			"score":          moment.Score,
+			"segment_id":     moment.SegmentId,
+			"jump_url":       fmt.Sprintf("/api/materials/%s/media#t=%d", moment.MaterialId, int(moment.StartTime)),
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": resp.Success,
+		"message": resp.Message,
+		"moments": moments,
+	})
+}
+
+// RetranscribeSegments re-transcribes low-confidence (or the given) segments with a larger model
+func (h *ASRHandler) RetranscribeSegments(c *gin.Context) {
+	userID, ok := h.userID(c)
+	if !ok {
+		return
+	}
+
+	materialID := c.Param("material_id")
+
+	var req struct {
+		VideoURL   string   `json:"video_url"`
+		SegmentIDs []string `json:"segment_ids"`
+		Model      string   `json:"model"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.WithError(err).Error("Failed to parse request body")
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid request format",
+		})
+		return
+	}
+
+	h.logger.WithField("material_id", materialID).WithField("segment_count", len(req.SegmentIDs)).Info("Re-transcribing ASR segments")
+
+	// Every segment is a separate transcription call, allow more time than other ASR calls
+	ctx, cancel := context.WithTimeout(middleware.RPCContext(c), 5*time.Minute)
+	defer cancel()
+
+	resp, err := h.client.RetranscribeSegments(ctx, &asr.RetranscribeSegmentsRequest{
+		MaterialId: materialID,
+		UserId:     userID,
+		VideoUrl:   req.VideoURL,
+		SegmentIds: req.SegmentIDs,
+		Model:      req.Model,
+	})
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to call ASR service")
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "ASR service call failed",
+		})
+		return
+	}
+
+	// Convert gRPC response to HTTP response
+	segments := make([]map[string]interface{}, len(resp.Segments))
+	for i, segment := range resp.Segments {
+		segments[i] = map[string]interface{}{
+			"id":             segment.Id,
+			"material_id":    segment.MaterialId,
+			"start_time":     segment.StartTime,
+			"end_time":       segment.EndTime,
+			"text":           segment.Text,
+			"confidence":     segment.Confidence,
+			"low_confidence": segment.LowConfidence,
+			"model":          segment.Model,
+			"updated_at":     formatTimestamp(c, segment.UpdatedAt),
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":         resp.Success,
+		"message":         resp.Message,
+		"model":           resp.Model,
+		"requested_count": resp.RequestedCount,
+		"improved_count":  resp.ImprovedCount,
+		"segments":        segments,
+	})
+}
+
 // HealthCheck provides health status of ASR service
 func (h *ASRHandler) HealthCheck(c *gin.Context) {
 	h.logger.Info("ASR health check request")
@@ -240,7 +550,7 @@ func (h *ASRHandler) HealthCheck(c *gin.Context) {
 	grpcReq := &asr.HealthCheckRequest{}
 
 	// Call ASR service
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(middleware.RPCContext(c), 5*time.Second)
 	defer cancel()
 
 	resp, err := h.client.HealthCheck(ctx, grpcReq)
@@ -1,26 +1,29 @@
 package handler
 
 import (
-	"context"
 	"log"
 	"net/http"
-	"os"
+	"strconv"
+	"strings"
 
+	"github.com/RigelNana/arkstudy/gateway/backend"
+	"github.com/RigelNana/arkstudy/gateway/middleware"
+	"github.com/RigelNana/arkstudy/pkg/registry"
 	authpb "github.com/RigelNana/arkstudy/proto/auth"
 	materialpb "github.com/RigelNana/arkstudy/proto/material"
 	userpb "github.com/RigelNana/arkstudy/proto/user"
 
 	"github.com/gin-gonic/gin"
-	"google.golang.org/grpc"
 )
 
 type AuthHandler struct {
-	authClient authpb.AuthServiceClient
-	userClient userpb.UserServiceClient
+	authClient  authpb.AuthServiceClient
+	userClient  userpb.UserServiceClient
+	invitations *InvitationSender
 }
 
-func NewAuthHandler(authClient authpb.AuthServiceClient, userClient userpb.UserServiceClient) *AuthHandler {
-	return &AuthHandler{authClient: authClient, userClient: userClient}
+func NewAuthHandler(authClient authpb.AuthServiceClient, userClient userpb.UserServiceClient, invitations *InvitationSender) *AuthHandler {
+	return &AuthHandler{authClient: authClient, userClient: userClient, invitations: invitations}
 }
 
 // Register expects username,email,password ->
@@ -29,14 +32,29 @@ func NewAuthHandler(authClient authpb.AuthServiceClient, userClient userpb.UserS
 func (h *AuthHandler) Register(c *gin.Context) {
 	var req struct{ Username, Email, Password string }
 	if err := c.ShouldBindJSON(&req); err != nil || req.Username == "" || req.Email == "" || req.Password == "" {
-		log.Printf("Register validation failed: err=%v, username=%s, email=%s, password=%s", err, req.Username, req.Email, req.Password)
+		log.Printf("Register validation failed: err=%v, username=%s, email=%s", err, req.Username, req.Email)
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input"})
 		return
 	}
 	log.Printf("Register request: username=%s, email=%s", req.Username, req.Email)
 
+	// 先校验密码策略，避免创建用户后因密码不合格注册失败
+	pr, err := h.authClient.CheckPasswordPolicy(c.Request.Context(), &authpb.CheckPasswordPolicyRequest{
+		Password:   req.Password,
+		Identities: []string{req.Username, req.Email},
+	})
+	if err != nil {
+		log.Printf("CheckPasswordPolicy gRPC error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error", "detail": err.Error()})
+		return
+	}
+	if !pr.Valid {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "password does not meet policy", "violations": pr.Violations, "policy": pr.Policy})
+		return
+	}
+
 	// create user
-	cuResp, err := h.userClient.CreateUser(context.Background(), &userpb.CreateUserRequest{Username: req.Username, Email: req.Email, Role: "student", Description: ""})
+	cuResp, err := h.userClient.CreateUser(middleware.RPCContext(c), &userpb.CreateUserRequest{Username: req.Username, Email: req.Email, Role: "student", Description: ""})
 	if err != nil {
 		log.Printf("CreateUser gRPC error: %v", err)
 		c.JSON(http.StatusBadRequest, gin.H{"error": "create user failed", "detail": err.Error()})
@@ -51,15 +69,80 @@ func (h *AuthHandler) Register(c *gin.Context) {
 	log.Printf("CreateUser success: userID=%s", userID)
 
 	// register auth
-	ar, err := h.authClient.Register(context.Background(), &authpb.RegisterRequest{UserId: userID, Password: req.Password})
+	ar, err := h.authClient.Register(middleware.RPCContext(c), &authpb.RegisterRequest{UserId: userID, Password: req.Password})
 	if err != nil || !ar.Success {
 		log.Printf("Auth register failed: err=%v, success=%v, message=%s", err, ar.Success, ar.GetMessage())
-		c.JSON(http.StatusBadRequest, gin.H{"error": "auth register failed", "detail": ar.GetMessage()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "auth register failed", "detail": ar.GetMessage(), "violations": ar.GetViolations()})
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"user_id": userID, "message": "registered"})
 }
 
+// CheckPassword 按当前密码策略校验密码并估计强度，供注册、改密页面实时提示
+// POST /api/password/check {"password": "...", "username": "...", "email": "..."}
+func (h *AuthHandler) CheckPassword(c *gin.Context) {
+	var req struct {
+		Password string `json:"password"`
+		Username string `json:"username"`
+		Email    string `json:"email"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input"})
+		return
+	}
+	resp, err := h.authClient.CheckPasswordPolicy(c.Request.Context(), &authpb.CheckPasswordPolicyRequest{
+		Password:   req.Password,
+		Identities: []string{req.Username, req.Email},
+	})
+	if err != nil {
+		log.Printf("CheckPasswordPolicy gRPC error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error", "detail": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"valid":      resp.Valid,
+		"strength":   resp.Strength,
+		"violations": resp.Violations,
+		"policy":     resp.Policy,
+	})
+}
+
+// UpdatePassword 校验当前密码后修改密码
+// PUT /api/users/me/password {"current_password": "...", "new_password": "..."}
+func (h *AuthHandler) UpdatePassword(c *gin.Context) {
+	var req struct {
+		CurrentPassword string `json:"current_password" binding:"required"`
+		NewPassword     string `json:"new_password" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input", "detail": err.Error()})
+		return
+	}
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	resp, err := h.authClient.UpdatePassword(c.Request.Context(), &authpb.UpdatePasswordRequest{
+		UserId:          userID,
+		CurrentPassword: req.CurrentPassword,
+		NewPassword:     req.NewPassword,
+	})
+	if err != nil {
+		log.Printf("UpdatePassword gRPC error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error", "detail": err.Error()})
+		return
+	}
+	if !resp.Success {
+		status := http.StatusBadRequest
+		if resp.Message == "invalid credentials" {
+			status = http.StatusUnauthorized
+		}
+		c.JSON(status, gin.H{"error": "update password failed", "detail": resp.Message, "violations": resp.Violations})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "message": "password updated"})
+}
+
 // Login expects user_id + password (或未来支持 username/email -> 查询 user-service)
 func (h *AuthHandler) Login(c *gin.Context) {
 	var req struct{ Identifier, Password string }
@@ -68,21 +151,172 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 	// 查询 user_id
-	ur, err := h.userClient.GetUserByUsername(context.Background(), &userpb.GetUserByUsernameRequest{Username: req.Identifier})
+	ur, err := h.userClient.GetUserByUsername(middleware.RPCContext(c), &userpb.GetUserByUsernameRequest{Username: req.Identifier})
 	if err != nil || !ur.Found {
-		ur, err = h.userClient.GetUserByEmail(context.Background(), &userpb.GetUserByEmailRequest{Email: req.Identifier})
+		ur, err = h.userClient.GetUserByEmail(middleware.RPCContext(c), &userpb.GetUserByEmailRequest{Email: req.Identifier})
 		if err != nil || !ur.Found {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found"})
 			return
 		}
 	}
 	userID := ur.User.Id
-	lr, err := h.authClient.Login(context.Background(), &authpb.LoginRequest{UserId: userID, Password: req.Password})
+	lr, err := h.authClient.Login(middleware.RPCContext(c), &authpb.LoginRequest{
+		UserId:    userID,
+		Password:  req.Password,
+		UserAgent: c.Request.UserAgent(),
+		Ip:        c.ClientIP(),
+	})
 	if err != nil || !lr.Success {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "login failed", "detail": lr.GetMessage()})
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"token": lr.Token})
+	c.JSON(http.StatusOK, gin.H{"token": lr.Token, "session_id": lr.SessionId, "password_change_required": lr.PasswordChangeRequired})
+}
+
+// Impersonate 管理员以目标用户身份获取限时 token 以复现问题，需填写原因，签发与之后的每个请求都会记录审计
+// POST /api/admin/impersonate {"user_id": "...", "reason": "...", "duration_minutes": 15}
+func (h *AuthHandler) Impersonate(c *gin.Context) {
+	var req struct {
+		UserID          string `json:"user_id" binding:"required"`
+		Reason          string `json:"reason" binding:"required"`
+		DurationMinutes int32  `json:"duration_minutes" binding:"gte=0"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input", "detail": err.Error()})
+		return
+	}
+	adminID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	resp, err := h.authClient.ImpersonateUser(c.Request.Context(), &authpb.ImpersonateUserRequest{
+		AdminUserId:     adminID,
+		TargetUserId:    req.UserID,
+		Reason:          req.Reason,
+		DurationMinutes: req.DurationMinutes,
+		UserAgent:       c.Request.UserAgent(),
+		Ip:              c.ClientIP(),
+	})
+	if err != nil {
+		log.Printf("ImpersonateUser gRPC error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error", "detail": err.Error()})
+		return
+	}
+	if !resp.Success {
+		c.JSON(adminErrorStatus(resp.Message), gin.H{"error": "impersonation failed", "detail": resp.Message})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": gin.H{
+		"token":      resp.Token,
+		"session_id": resp.SessionId,
+		"expires_at": resp.ExpiresAt,
+		"audit_id":   resp.AuditId,
+	}})
+}
+
+// ListImpersonations 代登录审计记录，可按 user_id（目标用户）、impersonator_id 过滤
+// GET /api/admin/impersonations?user_id=&impersonator_id=&limit=
+func (h *AuthHandler) ListImpersonations(c *gin.Context) {
+	adminID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	resp, err := h.authClient.ListImpersonations(c.Request.Context(), &authpb.ListImpersonationsRequest{
+		AdminUserId:    adminID,
+		TargetUserId:   c.Query("user_id"),
+		ImpersonatorId: c.Query("impersonator_id"),
+		Limit:          int32(limit),
+	})
+	if err != nil {
+		log.Printf("ListImpersonations gRPC error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error", "detail": err.Error()})
+		return
+	}
+	if !resp.Success {
+		c.JSON(adminErrorStatus(resp.Message), gin.H{"error": "list impersonations failed", "detail": resp.Message})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": resp.Impersonations})
+}
+
+// adminErrorStatus 非管理员或目标为管理员时返回 403
+func adminErrorStatus(message string) int {
+	switch {
+	case strings.HasPrefix(message, "permission denied"):
+		return http.StatusForbidden
+	case message == "target user not found":
+		return http.StatusNotFound
+	default:
+		return http.StatusBadRequest
+	}
+}
+
+// ListSessions 当前用户已登录的设备，current 标记发起请求的设备
+// GET /api/users/me/sessions
+func (h *AuthHandler) ListSessions(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	resp, err := h.authClient.ListSessions(c.Request.Context(), &authpb.ListSessionsRequest{
+		UserId:           userID,
+		CurrentSessionId: c.GetString("session_id"),
+	})
+	if err != nil {
+		log.Printf("ListSessions gRPC error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error", "detail": err.Error()})
+		return
+	}
+	if !resp.Success {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "list sessions failed", "detail": resp.Message})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": resp.Sessions})
+}
+
+// RevokeSession 注销指定设备，该设备的 token 随即失效（gateway 启用本地验签时最长延迟 JWT_REVOCATION_CACHE_TTL）
+// DELETE /api/users/me/sessions/:id
+func (h *AuthHandler) RevokeSession(c *gin.Context) {
+	h.revokeSessions(c, c.Param("id"))
+}
+
+// RevokeOtherSessions 注销除当前设备外的全部设备
+// DELETE /api/users/me/sessions
+func (h *AuthHandler) RevokeOtherSessions(c *gin.Context) {
+	h.revokeSessions(c, "")
+}
+
+func (h *AuthHandler) revokeSessions(c *gin.Context, sessionID string) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	current := c.GetString("session_id")
+	if sessionID == "" && current == "" {
+		// 旧 token 没有会话，无法判断当前设备，避免把自己也注销
+		c.JSON(http.StatusBadRequest, gin.H{"error": "current session unknown", "detail": "please sign in again before signing out other devices"})
+		return
+	}
+	resp, err := h.authClient.RevokeSession(c.Request.Context(), &authpb.RevokeSessionRequest{
+		UserId:        userID,
+		SessionId:     sessionID,
+		KeepSessionId: current,
+	})
+	if err != nil {
+		log.Printf("RevokeSession gRPC error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error", "detail": err.Error()})
+		return
+	}
+	if !resp.Success {
+		status := http.StatusBadRequest
+		if resp.Message == "session not found" {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{"error": "revoke session failed", "detail": resp.Message})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "revoked": resp.Revoked})
 }
 
 // Validate token -> 返回 user_id
@@ -103,7 +337,7 @@ func (h *AuthHandler) Validate(c *gin.Context) {
 		return
 	}
 
-	vr, err := h.authClient.ValidateToken(context.Background(), &authpb.ValidateTokenRequest{Token: token})
+	vr, err := h.authClient.ValidateToken(middleware.RPCContext(c), &authpb.ValidateTokenRequest{Token: token})
 	if err != nil || !vr.Valid {
 		c.JSON(http.StatusUnauthorized, gin.H{"valid": false, "message": vr.GetMessage()})
 		return
@@ -113,36 +347,12 @@ func (h *AuthHandler) Validate(c *gin.Context) {
 
 // Helpers to create gRPC clients
 func NewAuthServiceClient() authpb.AuthServiceClient {
-	addr := os.Getenv("AUTH_GRPC_ADDR")
-	if addr == "" {
-		addr = "localhost:50051"
-	}
-	conn, err := grpc.Dial(addr, grpc.WithInsecure())
-	if err != nil {
-		log.Fatalf("dial auth-service: %v", err)
-	}
-	return authpb.NewAuthServiceClient(conn)
+	return authpb.NewAuthServiceClient(backend.For(registry.Auth))
 }
 func NewUserServiceClient() userpb.UserServiceClient {
-	addr := os.Getenv("USER_GRPC_ADDR")
-	if addr == "" {
-		addr = "localhost:50052"
-	}
-	conn, err := grpc.Dial(addr, grpc.WithInsecure())
-	if err != nil {
-		log.Fatalf("dial user-service: %v", err)
-	}
-	return userpb.NewUserServiceClient(conn)
+	return userpb.NewUserServiceClient(backend.For(registry.User))
 }
 
 func NewMaterialServiceClient() materialpb.MaterialServiceClient {
-	addr := os.Getenv("MATERIAL_GRPC_ADDR")
-	if addr == "" {
-		addr = "localhost:50053"
-	}
-	conn, err := grpc.Dial(addr, grpc.WithInsecure())
-	if err != nil {
-		log.Fatalf("dial material-service: %v", err)
-	}
-	return materialpb.NewMaterialServiceClient(conn)
+	return materialpb.NewMaterialServiceClient(backend.For(registry.Material))
 }
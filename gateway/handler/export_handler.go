@@ -0,0 +1,188 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/RigelNana/arkstudy/gateway/backend"
+	"github.com/RigelNana/arkstudy/gateway/export"
+	arkkafka "github.com/RigelNana/arkstudy/pkg/kafka"
+	"github.com/RigelNana/arkstudy/pkg/langdetect"
+	"github.com/RigelNana/arkstudy/pkg/registry"
+	asrpb "github.com/RigelNana/arkstudy/proto/asr"
+	quizpb "github.com/RigelNana/arkstudy/proto/quiz"
+	"github.com/gin-gonic/gin"
+)
+
+// ExportHandler 导出资料的学习笔记，内容由 export.Collector 从各服务汇总
+type ExportHandler struct {
+	collector *export.Collector
+	tasks     *TaskRecorder
+	// 异步生成的离线学习包
+	bundles *offlineBundleStore
+}
+
+func NewExportHandler(collector *export.Collector, tasks *TaskRecorder) *ExportHandler {
+	return &ExportHandler{collector: collector, tasks: tasks, bundles: newOfflineBundleStore()}
+}
+
+// NewQuizServiceClient creates a gRPC client to quiz-service resolved through pkg/discovery
+func NewQuizServiceClient() quizpb.QuizServiceClient {
+	return quizpb.NewQuizServiceClient(backend.For(registry.Quiz))
+}
+
+// NewASRServiceClient creates a gRPC client to asr-service resolved through pkg/discovery
+func NewASRServiceClient() asrpb.ASRServiceClient {
+	return asrpb.NewASRServiceClient(backend.For(registry.ASR))
+}
+
+// GET /api/materials/:id/notes/export?format=markdown|pdf&summary=true|false
+// 导出资料的学习笔记：摘要、核心概念、记忆卡片与练习题（附参考答案），以附件形式下载。
+// 音视频资料使用已有的章节摘要，其余资料由 llm-service 生成摘要，summary=false 时跳过
+func (h *ExportHandler) ExportNotes(c *gin.Context) {
+	format := strings.ToLower(c.DefaultQuery("format", "markdown"))
+	if format == "md" {
+		format = "markdown"
+	}
+	if format != "markdown" && format != "pdf" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be markdown or pdf"})
+		return
+	}
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	materialID := c.Param("id")
+
+	// 导出需要汇总多个服务并可能调用 LLM 生成摘要，登记到任务中心
+	task := h.tasks.Start(c.Request.Context(), userID, arkkafka.TaskKindExport, "导出学习笔记（"+format+"）", materialID)
+	notes, err := h.collector.Collect(c.Request.Context(), userID, materialID, export.Options{
+		Summary: c.DefaultQuery("summary", "true") != "false",
+	})
+	if err != nil {
+		task.Finish(err, nil)
+	}
+	if errors.Is(err, export.ErrMaterialNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "material not found"})
+		return
+	}
+	if err != nil {
+		log.Printf("ExportNotes collect error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "export failed", "detail": err.Error()})
+		return
+	}
+	reportTokenUsage(c, notes.LLMMetadata)
+
+	filename := "notes-" + safeFilename(notes.Material.Id)
+	if format == "pdf" {
+		data, err := export.RenderPDF(notes)
+		task.Finish(err, map[string]string{"format": format})
+		if err != nil {
+			log.Printf("ExportNotes render pdf error: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "export failed", "detail": err.Error()})
+			return
+		}
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.pdf"`, filename))
+		c.Data(http.StatusOK, "application/pdf", data)
+		return
+	}
+	task.Finish(nil, map[string]string{"format": format})
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.md"`, filename))
+	c.Data(http.StatusOK, "text/markdown; charset=utf-8", export.RenderMarkdown(notes))
+}
+
+// GET /api/materials/:id/anki
+// 将资料的记忆卡片与客观题导出为 Anki 牌组（.apkg），已作答的题目按作答记录保留复习进度
+func (h *ExportHandler) ExportAnki(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	task := h.tasks.Start(c.Request.Context(), userID, arkkafka.TaskKindExport, "导出 Anki 牌组", c.Param("id"))
+	deck, err := h.collector.Deck(c.Request.Context(), userID, c.Param("id"))
+	if err != nil {
+		task.Finish(err, nil)
+	}
+	if errors.Is(err, export.ErrMaterialNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "material not found"})
+		return
+	}
+	if err != nil {
+		log.Printf("ExportAnki collect error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "export failed", "detail": err.Error()})
+		return
+	}
+	if len(deck.Cards) == 0 {
+		task.Finish(errors.New("no cards to export"), nil)
+		c.JSON(http.StatusNotFound, gin.H{"error": "material has no flashcards or objective questions to export"})
+		return
+	}
+
+	data, err := export.RenderAnki(deck)
+	task.Finish(err, map[string]string{"format": "apkg", "card_count": strconv.Itoa(len(deck.Cards))})
+	if err != nil {
+		log.Printf("ExportAnki render error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "export failed", "detail": err.Error()})
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="deck-%s.apkg"`, safeFilename(deck.Material.Id)))
+	c.Data(http.StatusOK, "application/octet-stream", data)
+}
+
+// GET /api/materials/:id/subtitles?format=srt|vtt&lang=
+// 将音视频资料的转写导出为字幕文件，lang 非空时导出原文加译文的双语字幕（未翻译的分段会先翻译）
+func (h *ExportHandler) ExportSubtitles(c *gin.Context) {
+	format := strings.ToLower(c.DefaultQuery("format", export.SubtitleFormatSRT))
+	if format != export.SubtitleFormatSRT && format != export.SubtitleFormatVTT {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be srt or vtt"})
+		return
+	}
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	lang := c.Query("lang")
+	if lang != "" && !langdetect.ValidTag(lang) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "lang must be a language tag such as en or zh-CN"})
+		return
+	}
+	cues, err := h.collector.Subtitles(c.Request.Context(), userID, c.Param("id"), lang)
+	if errors.Is(err, export.ErrMaterialNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "material not found"})
+		return
+	}
+	if err != nil {
+		log.Printf("ExportSubtitles error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "export failed", "detail": err.Error()})
+		return
+	}
+	if len(cues) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "material has no transcript to export"})
+		return
+	}
+
+	filename := "subtitles-" + safeFilename(c.Param("id"))
+	if lang != "" {
+		filename += "-" + safeFilename(lang)
+	}
+	contentType := "application/x-subrip; charset=utf-8"
+	if format == export.SubtitleFormatVTT {
+		contentType = "text/vtt; charset=utf-8"
+	}
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.%s"`, filename, format))
+	c.Data(http.StatusOK, contentType, export.RenderSubtitles(cues, format))
+}
+
+// safeFilename 去掉写入 Content-Disposition 的文件名中不安全的字符
+func safeFilename(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '-' || r == '_' || (r >= '0' && r <= '9') || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') {
+			return r
+		}
+		return -1
+	}, s)
+}
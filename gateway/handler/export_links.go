@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/RigelNana/arkstudy/gateway/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+// ExportLinkHandler 为导出接口签发限时下载地址
+type ExportLinkHandler struct {
+	signer *middleware.URLSigner
+}
+
+func NewExportLinkHandler(signer *middleware.URLSigner) *ExportLinkHandler {
+	return &ExportLinkHandler{signer: signer}
+}
+
+// POST /api/exports/links {"url": "/api/materials/:id/notes/export?format=pdf", "expires_in": 3600}
+// 为当前用户签发导出地址，expires_in 单位为秒，0 为默认有效期。
+// 持有地址的任何人都能在有效期内以当前用户身份下载该导出，分享前注意有效期
+func (h *ExportLinkHandler) CreateLink(c *gin.Context) {
+	var req struct {
+		URL       string `json:"url" binding:"required"`
+		ExpiresIn int64  `json:"expires_in" binding:"gte=0"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input", "detail": err.Error()})
+		return
+	}
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	link, expiresAt, err := h.signer.Sign(userID, req.URL, time.Duration(req.ExpiresIn)*time.Second)
+	if errors.Is(err, middleware.ErrURLInvalidTTL) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input", "detail": "expires_in must be at most " + h.signer.MaxTTL().String()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input", "detail": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": gin.H{
+		"url":        link,
+		"expires_at": expiresAt.UTC().Format(time.RFC3339),
+	}})
+}
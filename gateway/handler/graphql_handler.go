@@ -0,0 +1,370 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/RigelNana/arkstudy/gateway/graphql"
+	"github.com/RigelNana/arkstudy/gateway/middleware"
+	"github.com/RigelNana/arkstudy/pkg/locale"
+	asrpb "github.com/RigelNana/arkstudy/proto/asr"
+	materialpb "github.com/RigelNana/arkstudy/proto/material"
+	quizpb "github.com/RigelNana/arkstudy/proto/quiz"
+	userpb "github.com/RigelNana/arkstudy/proto/user"
+	"github.com/gin-gonic/gin"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const (
+	// 单次 GraphQL 查询的总超时，各字段的后端调用共用
+	graphqlTimeout = 30 * time.Second
+	// 列表字段单页的最大条数
+	graphqlMaxPageSize = 100
+	// 资料的处理结果最多返回的条数
+	graphqlMaxProcessingResults = 50
+)
+
+// GraphQLEnabled 是否开放 /api/graphql，由 GRAPHQL_ENABLED=true 开启，默认关闭
+func GraphQLEnabled() bool {
+	return os.Getenv("GRAPHQL_ENABLED") == "true"
+}
+
+// GraphQLHandler 组合查询：在一次请求中获取资料及其处理结果、摘要、题目数等，减少 Web 端的 REST 往返。
+// 字段名与对应 REST 接口的 JSON 字段一致，时间按请求时区格式化
+type GraphQLHandler struct {
+	users     userpb.UserServiceClient
+	materials materialpb.MaterialServiceClient
+	quiz      quizpb.QuizServiceClient
+	asr       asrpb.ASRServiceClient
+	schema    *graphql.Schema
+}
+
+func NewGraphQLHandler(users userpb.UserServiceClient, materials materialpb.MaterialServiceClient, quiz quizpb.QuizServiceClient, asr asrpb.ASRServiceClient) *GraphQLHandler {
+	h := &GraphQLHandler{users: users, materials: materials, quiz: quiz, asr: asr}
+	h.schema = h.buildSchema()
+	return h
+}
+
+type graphqlUserKey struct{}
+
+// POST /api/graphql，请求体为 {"query", "operationName", "variables"}。
+// 与 GraphQL over HTTP 一致，查询能执行时返回 200，字段错误在 errors 中；语法或校验错误返回 400
+func (h *GraphQLHandler) Query(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	var req graphql.Request
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, graphql.Response{Errors: []*graphql.Error{{Message: err.Error()}}})
+		return
+	}
+	if strings.TrimSpace(req.Query) == "" {
+		c.JSON(http.StatusBadRequest, graphql.Response{Errors: []*graphql.Error{{Message: "query 不能为空"}}})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(middleware.RPCContext(c), graphqlTimeout)
+	defer cancel()
+	ctx = locale.NewContext(ctx, locale.FromContext(c.Request.Context()))
+	ctx = context.WithValue(ctx, graphqlUserKey{}, userID)
+
+	resp := h.schema.Execute(ctx, req)
+	status := http.StatusOK
+	if resp.Data == nil {
+		status = http.StatusBadRequest
+	}
+	c.JSON(status, resp)
+}
+
+func graphqlUserID(ctx context.Context) string {
+	userID, _ := ctx.Value(graphqlUserKey{}).(string)
+	return userID
+}
+
+// buildSchema 定义可查询的类型：
+//
+//	query {
+//	  me: User
+//	  material(id: String!): Material
+//	  materials(page: Int = 1, page_size: Int = 20): MaterialPage
+//	}
+//
+// Material 在资料字段之外提供 processing_results(type)、summary、chapters、segments、
+// question_count、questions(page, page_size) 与 children，各自调用对应的后端服务
+func (h *GraphQLHandler) buildSchema() *graphql.Schema {
+	user := protoObject("User", &userpb.UserInfo{})
+	processingResult := protoObject("ProcessingResult", &materialpb.ProcessingResult{})
+	chapter := protoObject("Chapter", &asrpb.ASRChapter{})
+	segment := protoObject("Segment", &asrpb.ASRSegment{}, "embedding_vector")
+	question := protoObject("Question", &quizpb.Question{})
+	material := protoObject("Material", &materialpb.MaterialInfo{})
+	materialPage := &graphql.Object{Name: "MaterialPage", Fields: map[string]*graphql.FieldDef{
+		"total": {Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return p.Source.(*materialpb.ListMaterialsResponse).Total, nil
+		}},
+		"items": {Type: material, Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return p.Source.(*materialpb.ListMaterialsResponse).Materials, nil
+		}},
+	}}
+
+	material.Fields["processing_results"] = &graphql.FieldDef{Type: processingResult, Resolve: h.processingResults}
+	material.Fields["summary"] = &graphql.FieldDef{Resolve: h.summary}
+	material.Fields["chapters"] = &graphql.FieldDef{Type: chapter, Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+		return h.chapters(p.Context, p.Source.(*materialpb.MaterialInfo))
+	}}
+	material.Fields["segments"] = &graphql.FieldDef{Type: segment, Resolve: h.segments}
+	material.Fields["question_count"] = &graphql.FieldDef{Resolve: h.questionCount}
+	material.Fields["questions"] = &graphql.FieldDef{Type: question, Resolve: h.questions}
+	material.Fields["children"] = &graphql.FieldDef{Type: material, Resolve: h.children}
+
+	query := &graphql.Object{Name: "Query", Fields: map[string]*graphql.FieldDef{
+		"me":        {Type: user, Resolve: h.me},
+		"material":  {Type: material, Resolve: h.material},
+		"materials": {Type: materialPage, Resolve: h.listMaterials},
+	}}
+	return &graphql.Schema{Query: query}
+}
+
+func (h *GraphQLHandler) me(p graphql.ResolveParams) (interface{}, error) {
+	resp, err := h.users.GetUserByID(p.Context, &userpb.GetUserByIDRequest{Id: graphqlUserID(p.Context)})
+	if err != nil {
+		return nil, err
+	}
+	if !resp.Found {
+		return nil, nil
+	}
+	return resp.User, nil
+}
+
+// material 资料不存在或不属于当前用户时为 null
+func (h *GraphQLHandler) material(p graphql.ResolveParams) (interface{}, error) {
+	id := p.String("id")
+	if id == "" {
+		return nil, fmt.Errorf("id 不能为空")
+	}
+	resp, err := h.materials.GetMaterial(p.Context, &materialpb.GetMaterialRequest{MaterialId: id, UserId: graphqlUserID(p.Context)})
+	if err != nil {
+		return nil, err
+	}
+	if !resp.Found {
+		return nil, nil
+	}
+	return resp.Material, nil
+}
+
+func (h *GraphQLHandler) listMaterials(p graphql.ResolveParams) (interface{}, error) {
+	page, pageSize, err := pageArgs(p)
+	if err != nil {
+		return nil, err
+	}
+	return h.materials.ListMaterials(p.Context, &materialpb.ListMaterialsRequest{
+		UserId:   graphqlUserID(p.Context),
+		Page:     int32(page),
+		PageSize: int32(pageSize),
+	})
+}
+
+// processingResults type 为 OCR / ASR / LLM_ANALYSIS 时只返回该类型的结果
+func (h *GraphQLHandler) processingResults(p graphql.ResolveParams) (interface{}, error) {
+	m := p.Source.(*materialpb.MaterialInfo)
+	var filter *materialpb.ProcessingType
+	if name := p.String("type"); name != "" {
+		v, ok := materialpb.ProcessingType_value[strings.ToUpper(name)]
+		if !ok {
+			return nil, fmt.Errorf("无效的处理类型 %s", name)
+		}
+		t := materialpb.ProcessingType(v)
+		filter = &t
+	}
+	resp, err := h.materials.ListProcessingResults(p.Context, &materialpb.ListProcessingResultsRequest{
+		MaterialId: m.Id,
+		UserId:     graphqlUserID(p.Context),
+		Page:       1,
+		PageSize:   graphqlMaxProcessingResults,
+	})
+	if err != nil {
+		return nil, err
+	}
+	results := make([]*materialpb.ProcessingResult, 0, len(resp.Results))
+	for _, r := range resp.Results {
+		if filter == nil || r.Type == *filter {
+			results = append(results, r)
+		}
+	}
+	return results, nil
+}
+
+// summary 音视频资料为各章节摘要，其余资料为已完成的 LLM 分析结果，没有时为 null；不会触发新的 LLM 调用
+func (h *GraphQLHandler) summary(p graphql.ResolveParams) (interface{}, error) {
+	m := p.Source.(*materialpb.MaterialInfo)
+	if m.FileType == "video" || m.FileType == "audio" {
+		chapters, err := h.chapters(p.Context, m)
+		if err != nil {
+			return nil, err
+		}
+		var parts []string
+		for _, ch := range chapters {
+			if s := strings.TrimSpace(ch.Summary); s != "" {
+				parts = append(parts, s)
+			}
+		}
+		if len(parts) > 0 {
+			return strings.Join(parts, "\n\n"), nil
+		}
+	}
+
+	resp, err := h.materials.GetProcessingResult(p.Context, &materialpb.GetProcessingResultRequest{
+		MaterialId: m.Id,
+		UserId:     graphqlUserID(p.Context),
+		Type:       materialpb.ProcessingType_LLM_ANALYSIS,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !resp.Found || resp.Result == nil || resp.Result.Status != materialpb.ProcessingStatus_COMPLETED {
+		return nil, nil
+	}
+	return resp.Result.Content, nil
+}
+
+// chapters 非音视频资料没有章节，返回空列表
+func (h *GraphQLHandler) chapters(ctx context.Context, m *materialpb.MaterialInfo) ([]*asrpb.ASRChapter, error) {
+	if m.FileType != "video" && m.FileType != "audio" {
+		return []*asrpb.ASRChapter{}, nil
+	}
+	resp, err := h.asr.GetChapters(ctx, &asrpb.GetChaptersRequest{MaterialId: m.Id, UserId: graphqlUserID(ctx)})
+	if err != nil {
+		return nil, err
+	}
+	if !resp.Success {
+		return nil, fmt.Errorf("%s", resp.Message)
+	}
+	return resp.Chapters, nil
+}
+
+func (h *GraphQLHandler) segments(p graphql.ResolveParams) (interface{}, error) {
+	m := p.Source.(*materialpb.MaterialInfo)
+	if m.FileType != "video" && m.FileType != "audio" {
+		return []*asrpb.ASRSegment{}, nil
+	}
+	resp, err := h.asr.GetSegments(p.Context, &asrpb.GetSegmentsRequest{MaterialId: m.Id, UserId: graphqlUserID(p.Context)})
+	if err != nil {
+		return nil, err
+	}
+	if !resp.Success {
+		return nil, fmt.Errorf("%s", resp.Message)
+	}
+	return resp.Segments, nil
+}
+
+func (h *GraphQLHandler) questionCount(p graphql.ResolveParams) (interface{}, error) {
+	m := p.Source.(*materialpb.MaterialInfo)
+	resp, err := h.quiz.ListQuizzes(p.Context, &quizpb.ListQuizzesRequest{
+		UserId:     graphqlUserID(p.Context),
+		MaterialId: m.Id,
+		Page:       1,
+		PageSize:   1,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !resp.Success {
+		return nil, fmt.Errorf("%s", resp.Message)
+	}
+	return resp.Total, nil
+}
+
+func (h *GraphQLHandler) questions(p graphql.ResolveParams) (interface{}, error) {
+	m := p.Source.(*materialpb.MaterialInfo)
+	page, pageSize, err := pageArgs(p)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := h.quiz.ListQuizzes(p.Context, &quizpb.ListQuizzesRequest{
+		UserId:     graphqlUserID(p.Context),
+		MaterialId: m.Id,
+		Page:       int32(page),
+		PageSize:   int32(pageSize),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !resp.Success {
+		return nil, fmt.Errorf("%s", resp.Message)
+	}
+	return resp.Questions, nil
+}
+
+// children 压缩包资料展开出的子资料，其余资料为空列表
+func (h *GraphQLHandler) children(p graphql.ResolveParams) (interface{}, error) {
+	m := p.Source.(*materialpb.MaterialInfo)
+	if m.FileType != "bundle" {
+		return []*materialpb.MaterialInfo{}, nil
+	}
+	resp, err := h.materials.ListChildMaterials(p.Context, &materialpb.ListChildMaterialsRequest{MaterialId: m.Id, UserId: graphqlUserID(p.Context)})
+	if err != nil {
+		return nil, err
+	}
+	if !resp.Success {
+		return nil, fmt.Errorf("%s", resp.Message)
+	}
+	return resp.Materials, nil
+}
+
+// pageArgs 读取 page（默认 1）与 page_size（默认 20，最大 graphqlMaxPageSize）
+func pageArgs(p graphql.ResolveParams) (int, int, error) {
+	page, pageSize := p.Int("page", 1), p.Int("page_size", 20)
+	if page < 1 || pageSize < 1 || pageSize > graphqlMaxPageSize {
+		return 0, 0, fmt.Errorf("page 须大于 0，page_size 须在 1 到 %d 之间", graphqlMaxPageSize)
+	}
+	return page, pageSize, nil
+}
+
+// protoObject 以消息的各字段作为标量字段定义对象类型，字段名与值的格式同 protoJSON；
+// 嵌套消息整体作为 JSON 值输出。omit 中的字段（如向量）不开放查询
+func protoObject(name string, sample proto.Message, omit ...string) *graphql.Object {
+	obj := &graphql.Object{Name: name, Fields: map[string]*graphql.FieldDef{}}
+	fields := sample.ProtoReflect().Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		if slices.Contains(omit, string(fd.Name())) {
+			continue
+		}
+		obj.Fields[string(fd.Name())] = &graphql.FieldDef{Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return protoFieldJSON(p.Context, p.Source.(proto.Message).ProtoReflect(), fd), nil
+		}}
+	}
+	return obj
+}
+
+// protoFieldJSON 未设置的消息字段为 null，其余与 messageJSON 一致（未设置的标量取零值）
+func protoFieldJSON(ctx context.Context, m protoreflect.Message, fd protoreflect.FieldDescriptor) interface{} {
+	loc := locale.FromContext(ctx).Location()
+	v := m.Get(fd)
+	switch {
+	case fd.IsList():
+		list := v.List()
+		items := make([]any, list.Len())
+		for i := range items {
+			items[i] = singularJSON(fd, list.Get(i), loc)
+		}
+		return items
+	case fd.IsMap():
+		entries := map[string]any{}
+		v.Map().Range(func(k protoreflect.MapKey, mv protoreflect.Value) bool {
+			entries[k.String()] = singularJSON(fd.MapValue(), mv, loc)
+			return true
+		})
+		return entries
+	case fd.Kind() == protoreflect.MessageKind && !m.Has(fd):
+		return nil
+	}
+	return singularJSON(fd, v, loc)
+}
@@ -0,0 +1,45 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/RigelNana/arkstudy/gateway/backend"
+	"github.com/gin-gonic/gin"
+)
+
+// HealthHandler 展示 gateway 自身与各后端服务的健康状态
+type HealthHandler struct {
+	timeout time.Duration
+}
+
+// NewHealthHandler HEALTH_CHECK_TIMEOUT 为检查单个后端的超时时间（默认 500ms），
+// 应小于探针的超时时间，避免某个后端无响应时探针本身超时
+func NewHealthHandler() *HealthHandler {
+	timeout := 500 * time.Millisecond
+	if v := os.Getenv("HEALTH_CHECK_TIMEOUT"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d > 0 {
+			timeout = d
+		} else {
+			log.Printf("invalid HEALTH_CHECK_TIMEOUT=%q, using %s", v, timeout)
+		}
+	}
+	return &HealthHandler{timeout: timeout}
+}
+
+// Healthz 返回各后端的健康状态。后端不可用时 status 为 degraded，但仍返回 200：
+// 依赖服务滚动发布期间 gateway 应继续提供其余接口，而不是被探针重启或摘除
+// GET /healthz、GET /readyz
+func (h *HealthHandler) Healthz(c *gin.Context) {
+	backends := backend.Check(c.Request.Context(), h.timeout)
+	overall := "ok"
+	for _, b := range backends {
+		if b.Status != backend.StatusServing {
+			overall = "degraded"
+			break
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{"status": overall, "backends": backends})
+}
@@ -1,38 +1,97 @@
 package handler
 
 import (
-	"context"
 	"encoding/json"
+	"fmt"
 	"log"
 	"net/http"
-	"os"
 	"strconv"
 	"strings"
 
+	"github.com/RigelNana/arkstudy/gateway/backend"
+	"github.com/RigelNana/arkstudy/gateway/middleware"
+	"github.com/RigelNana/arkstudy/pkg/registry"
 	llmpb "github.com/RigelNana/arkstudy/proto/llm"
+	materialpb "github.com/RigelNana/arkstudy/proto/material"
 	"github.com/gin-gonic/gin"
-	"google.golang.org/grpc"
+	"github.com/google/uuid"
 )
 
 type LLMHandler struct {
-	client llmpb.LLMServiceClient
+	client   llmpb.LLMServiceClient
+	activity *ActivityRecorder
+	// sessions 向共享会话的在线成员推送新的问答
+	sessions *SessionHub
+	// materialClient 为检索结果附带用户批注
+	materialClient materialpb.MaterialServiceClient
 }
 
-func NewLLMHandler(client llmpb.LLMServiceClient) *LLMHandler {
-	return &LLMHandler{client: client}
+func NewLLMHandler(client llmpb.LLMServiceClient, activity *ActivityRecorder, sessions *SessionHub, materialClient materialpb.MaterialServiceClient) *LLMHandler {
+	return &LLMHandler{client: client, activity: activity, sessions: sessions, materialClient: materialClient}
 }
 
-// NewLLMServiceClient creates a gRPC client to llm-service using env LLM_GRPC_ADDR (default localhost:50054)
-func NewLLMServiceClient() llmpb.LLMServiceClient {
-	addr := os.Getenv("LLM_GRPC_ADDR")
-	if addr == "" {
-		addr = "localhost:50054"
+// top_k 的上限，避免一次召回过多片段撑满上下文
+const maxRetrievalTopK = 50
+
+// 一次提问最多可限定的资料数
+const maxAskMaterialIDs = 100
+
+// retrievalOptions 为 /api/ai/ask 的可选检索参数，经 QuestionRequest.context 透传给 llm-service：
+// top_k 为召回片段数，similarity_threshold 为最低相似度（0-1），rerank 控制是否启用重排序
+type retrievalOptions struct {
+	TopK                *int     `form:"top_k" json:"top_k"`
+	SimilarityThreshold *float64 `form:"similarity_threshold" json:"similarity_threshold"`
+	Rerank              *bool    `form:"rerank" json:"rerank"`
+	// 检索结果最高分低于该值时不生成答案，返回"资料中未找到"及建议；不传时使用 llm-service 的默认值
+	GroundingThreshold *float64 `form:"grounding_threshold" json:"grounding_threshold"`
+}
+
+func (o retrievalOptions) apply(ctx map[string]string) error {
+	if o.TopK != nil {
+		if *o.TopK < 1 || *o.TopK > maxRetrievalTopK {
+			return fmt.Errorf("top_k must be between 1 and %d", maxRetrievalTopK)
+		}
+		ctx["top_k"] = strconv.Itoa(*o.TopK)
 	}
-	conn, err := grpc.Dial(addr, grpc.WithInsecure())
-	if err != nil {
-		log.Fatalf("dial llm-service: %v", err)
+	if o.SimilarityThreshold != nil {
+		if *o.SimilarityThreshold < 0 || *o.SimilarityThreshold > 1 {
+			return fmt.Errorf("similarity_threshold must be between 0 and 1")
+		}
+		ctx["similarity_threshold"] = strconv.FormatFloat(*o.SimilarityThreshold, 'f', -1, 64)
+	}
+	if o.Rerank != nil {
+		ctx["rerank"] = strconv.FormatBool(*o.Rerank)
+	}
+	if o.GroundingThreshold != nil {
+		if *o.GroundingThreshold < 0 || *o.GroundingThreshold > 1 {
+			return fmt.Errorf("grounding_threshold must be between 0 and 1")
+		}
+		ctx["grounding_threshold"] = strconv.FormatFloat(*o.GroundingThreshold, 'f', -1, 64)
 	}
-	return llmpb.NewLLMServiceClient(conn)
+	return nil
+}
+
+// groundingSuggestions 资料中未找到答案时 llm-service 在 metadata.grounding_suggestions 中按行返回的建议
+func groundingSuggestions(metadata map[string]string) []string {
+	var out []string
+	for _, s := range strings.Split(metadata["grounding_suggestions"], "\n") {
+		if s = strings.TrimSpace(s); s != "" {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// reportTokenUsage 将 llm-service 在 metadata.total_tokens 中回报的（估算）用量交给配额中间件计费
+func reportTokenUsage(c *gin.Context, metadata map[string]string) {
+	if n, err := strconv.ParseInt(metadata["total_tokens"], 10, 64); err == nil && n > 0 {
+		c.Set(middleware.TokensUsedKey, n)
+	}
+}
+
+// NewLLMServiceClient creates a gRPC client to llm-service resolved through pkg/discovery
+func NewLLMServiceClient() llmpb.LLMServiceClient {
+	return llmpb.NewLLMServiceClient(backend.For(registry.LLM))
 }
 
 // POST /api/ai/ask
@@ -43,6 +102,8 @@ func (h *LLMHandler) Ask(c *gin.Context) {
 		Context     map[string]string `json:"context"`
 		SessionID   string            `form:"session_id" json:"session_id"`
 		MaxTurns    int               `form:"max_history_turns" json:"max_history_turns"`
+		NoCache     bool              `form:"no_cache" json:"no_cache"`
+		retrievalOptions
 	}
 	// 同时支持 JSON 和表单
 	_ = c.ShouldBind(&req)
@@ -92,6 +153,10 @@ func (h *LLMHandler) Ask(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input"})
 		return
 	}
+	if len(req.MaterialIDs) > maxAskMaterialIDs {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input", "detail": fmt.Sprintf("at most %d material_ids are allowed", maxAskMaterialIDs)})
+		return
+	}
 
 	userIDVal, ok := c.Get("user_id")
 	if !ok {
@@ -115,27 +180,51 @@ func (h *LLMHandler) Ask(c *gin.Context) {
 	if mht > 0 {
 		req.Context["max_history_turns"] = strconv.Itoa(mht)
 	}
+	// no_cache=true 时跳过答案缓存，强制重新生成
+	if req.NoCache || c.Query("no_cache") == "true" {
+		req.Context["no_cache"] = "true"
+	}
+	if err := req.retrievalOptions.apply(req.Context); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
-	resp, err := h.client.AskQuestion(context.Background(), &llmpb.QuestionRequest{
+	resp, err := h.client.AskQuestion(middleware.RPCContext(c), &llmpb.QuestionRequest{
 		Question:    req.Question,
 		UserId:      userID,
 		MaterialIds: req.MaterialIDs,
 		Context:     req.Context,
 	})
 	if err != nil {
+		// 共享会话中的只读成员或非成员提问时返回 403
+		if code := grpcHTTPStatus(err); code != http.StatusInternalServerError {
+			c.JSON(code, gin.H{"error": "ask failed", "detail": grpcErrorMessage(err)})
+			return
+		}
 		log.Printf("AskQuestion gRPC error: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "ask failed", "detail": err.Error()})
 		return
 	}
+	reportTokenUsage(c, resp.Metadata)
+	h.activity.Record(userID, ActivityAIQuestion, req.SessionID, req.Question, map[string]string{"material_ids": strings.Join(req.MaterialIDs, ",")})
+	// 非流式问答在回答完成后一并推送问题与回答
+	turnID := uuid.New().String()
+	h.sessions.Publish(req.SessionID, sessionEvent{Type: SessionEventQuestion, TurnID: turnID, UserID: userID, Content: req.Question})
+	h.publishAnswer(req.SessionID, turnID, userID, resp.Answer, resp.Sources, resp.Metadata)
 
+	data := gin.H{
+		"answer":     resp.Answer,
+		"confidence": resp.Confidence,
+		"sources":    resp.Sources,
+		"metadata":   resp.Metadata,
+	}
+	if resp.Metadata["grounding"] == "not_found" {
+		data["not_found"] = true
+		data["suggestions"] = groundingSuggestions(resp.Metadata)
+	}
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
-		"data": gin.H{
-			"answer":     resp.Answer,
-			"confidence": resp.Confidence,
-			"sources":    resp.Sources,
-			"metadata":   resp.Metadata,
-		},
+		"data":    data,
 	})
 }
 
@@ -148,6 +237,7 @@ func (h *LLMHandler) AskStream(c *gin.Context) {
 		Context     map[string]string `json:"context"`
 		SessionID   string            `form:"session_id" json:"session_id"`
 		MaxTurns    int               `form:"max_history_turns" json:"max_history_turns"`
+		retrievalOptions
 	}
 	_ = c.ShouldBind(&req)
 	if req.Question == "" {
@@ -193,6 +283,10 @@ func (h *LLMHandler) AskStream(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input"})
 		return
 	}
+	if len(req.MaterialIDs) > maxAskMaterialIDs {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input", "detail": fmt.Sprintf("at most %d material_ids are allowed", maxAskMaterialIDs)})
+		return
+	}
 
 	userIDVal, ok := c.Get("user_id")
 	if !ok {
@@ -201,6 +295,14 @@ func (h *LLMHandler) AskStream(c *gin.Context) {
 	}
 	userID, _ := userIDVal.(string)
 
+	if req.Context == nil {
+		req.Context = map[string]string{}
+	}
+	if err := req.retrievalOptions.apply(req.Context); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	// SSE headers
 	c.Header("Content-Type", "text/event-stream")
 	c.Header("Cache-Control", "no-cache")
@@ -222,7 +324,7 @@ func (h *LLMHandler) AskStream(c *gin.Context) {
 		req.Context["max_history_turns"] = strconv.Itoa(mht)
 	}
 
-	stream, err := h.client.AskQuestionStream(context.Background(), &llmpb.QuestionRequest{
+	stream, err := h.client.AskQuestionStream(middleware.RPCContext(c), &llmpb.QuestionRequest{
 		Question:    req.Question,
 		UserId:      userID,
 		MaterialIds: req.MaterialIDs,
@@ -233,24 +335,49 @@ func (h *LLMHandler) AskStream(c *gin.Context) {
 		c.Status(http.StatusInternalServerError)
 		return
 	}
+	h.activity.Record(userID, ActivityAIQuestion, req.SessionID, req.Question, map[string]string{"material_ids": strings.Join(req.MaterialIDs, ",")})
+
+	// 共享会话的在线成员同步收到问题与回答分片
+	turnID := uuid.New().String()
+	var answer strings.Builder
+	published := false
 
 	// 逐条写入 SSE data: token\n\n
 	for {
 		chunk, err := stream.Recv()
 		if err != nil {
+			// 共享会话的 ACL 拒绝在首个分片前返回，以 JSON 事件告知客户端
+			if code := grpcHTTPStatus(err); code != http.StatusInternalServerError {
+				if b, err := json.Marshal(map[string]any{"is_final": true, "error": grpcErrorMessage(err), "status": code}); err == nil {
+					_, _ = c.Writer.WriteString("data: " + string(b) + "\n\n")
+					c.Writer.Flush()
+				}
+			}
 			break
 		}
+		if !published {
+			h.sessions.Publish(req.SessionID, sessionEvent{Type: SessionEventQuestion, TurnID: turnID, UserID: userID, Content: req.Question})
+			published = true
+		}
 		if token := chunk.GetContent(); token != "" {
 			_, _ = c.Writer.WriteString("data: " + token + "\n\n")
 			c.Writer.Flush()
+			answer.WriteString(token)
+			h.sessions.Publish(req.SessionID, sessionEvent{Type: SessionEventToken, TurnID: turnID, UserID: userID, Content: token})
 		}
 		if chunk.GetIsFinal() {
+			reportTokenUsage(c, chunk.GetMetadata())
+			h.publishAnswer(req.SessionID, turnID, userID, answer.String(), nil, chunk.GetMetadata())
 			// Emit a final JSON event with session_id and any metadata so clients can capture it
 			finalPayload := map[string]any{
 				"is_final":   true,
 				"session_id": chunk.GetMetadata()["session_id"],
 				"metadata":   chunk.GetMetadata(),
 			}
+			if chunk.GetMetadata()["grounding"] == "not_found" {
+				finalPayload["not_found"] = true
+				finalPayload["suggestions"] = groundingSuggestions(chunk.GetMetadata())
+			}
 			if b, err := json.Marshal(finalPayload); err == nil {
 				_, _ = c.Writer.WriteString("data: " + string(b) + "\n\n")
 				c.Writer.Flush()
@@ -260,7 +387,8 @@ func (h *LLMHandler) AskStream(c *gin.Context) {
 	}
 }
 
-// GET /api/ai/search?query=&top_k=
+// GET /api/ai/search?query=&top_k=&include_annotations=
+// include_annotations=true 时为每条结果附带当前用户在对应页码或时间段上的批注
 func (h *LLMHandler) Search(c *gin.Context) {
 	query := c.Query("query")
 	if query == "" {
@@ -282,7 +410,7 @@ func (h *LLMHandler) Search(c *gin.Context) {
 	}
 	userID, _ := userIDVal.(string)
 
-	resp, err := h.client.SemanticSearch(context.Background(), &llmpb.SearchRequest{
+	resp, err := h.client.SemanticSearch(middleware.RPCContext(c), &llmpb.SearchRequest{
 		Query:  query,
 		UserId: userID,
 		TopK:   int32(topK),
@@ -293,5 +421,9 @@ func (h *LLMHandler) Search(c *gin.Context) {
 		return
 	}
 
+	if c.Query("include_annotations") == "true" {
+		c.JSON(http.StatusOK, gin.H{"success": true, "data": h.annotateResults(c, userID, resp.Results)})
+		return
+	}
 	c.JSON(http.StatusOK, gin.H{"success": true, "data": resp.Results})
 }
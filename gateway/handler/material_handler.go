@@ -4,22 +4,39 @@ import (
 	"context"
 	"io"
 	"log"
+	"mime"
 	"mime/multipart"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
+	"github.com/RigelNana/arkstudy/gateway/middleware"
 	materialpb "github.com/RigelNana/arkstudy/proto/material"
+	userpb "github.com/RigelNana/arkstudy/proto/user"
 	"github.com/gin-gonic/gin"
 )
 
 type MaterialHandler struct {
 	materialClient materialpb.MaterialServiceClient
+	// 查询资料访问记录（由 user-service 从活动流派生）
+	userClient userpb.UserServiceClient
+	activity   *ActivityRecorder
 }
 
-func NewMaterialHandler(materialClient materialpb.MaterialServiceClient) *MaterialHandler {
-	return &MaterialHandler{materialClient: materialClient}
+func NewMaterialHandler(materialClient materialpb.MaterialServiceClient, userClient userpb.UserServiceClient, activity *ActivityRecorder) *MaterialHandler {
+	return &MaterialHandler{materialClient: materialClient, userClient: userClient, activity: activity}
 }
 
+const (
+	// 上传转发时单个 gRPC 消息携带的数据大小
+	uploadChunkSize = 64 * 1024
+	// title、on_duplicate 等文本表单字段的读取上限
+	maxTitleBytes = 1024
+	// 网页摘录请求体上限，正文本身由 material-service 限制为 1MB
+	maxClipBodyBytes = 2 << 20
+)
+
 // UploadMaterial 上传文件
 // POST /api/materials/upload
 func (h *MaterialHandler) UploadMaterial(c *gin.Context) {
@@ -35,35 +52,68 @@ func (h *MaterialHandler) UploadMaterial(c *gin.Context) {
 		return
 	}
 
-	// 解析表单数据
-	title := c.PostForm("title")
+	// 以流式方式读取 multipart 表单，文件内容边读边转发，内存占用与文件大小无关。
+	// title 与 on_duplicate 字段需位于 file 之前（也可通过 ?title=、?on_duplicate= 传递）。
+	// on_duplicate=link 时若已有内容相同或相近的资料，不保存新文件而返回已有资料。
+	reader, err := c.Request.MultipartReader()
+	if err != nil {
+		log.Printf("UploadMaterial multipart reader error: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid multipart form", "detail": err.Error()})
+		return
+	}
+
+	title := c.Query("title")
+	onDuplicate := c.Query("on_duplicate")
+	var file *multipart.Part
+	for file == nil {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			log.Printf("UploadMaterial read form error: %v", err)
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid multipart form", "detail": err.Error()})
+			return
+		}
+		switch part.FormName() {
+		case "title":
+			value, err := io.ReadAll(io.LimitReader(part, maxTitleBytes))
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "failed to read title", "detail": err.Error()})
+				return
+			}
+			title = strings.TrimSpace(string(value))
+		case "on_duplicate":
+			value, err := io.ReadAll(io.LimitReader(part, maxTitleBytes))
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "failed to read on_duplicate", "detail": err.Error()})
+				return
+			}
+			onDuplicate = strings.TrimSpace(string(value))
+		case "file":
+			file = part
+		}
+	}
+
 	if title == "" {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "title is required"})
 		return
 	}
-
-	// 获取上传的文件
-	file, header, err := c.Request.FormFile("file")
-	if err != nil {
-		log.Printf("UploadMaterial get file error: %v", err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": "file is required", "detail": err.Error()})
+	if file == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "file is required"})
 		return
 	}
 	defer file.Close()
 
-	log.Printf("UploadMaterial request: userID=%s, title=%s, filename=%s, size=%d",
-		userID, title, header.Filename, header.Size)
+	log.Printf("UploadMaterial request: userID=%s, title=%s, filename=%s",
+		userID, title, file.FileName())
 
-	// 读取文件内容
-	fileData, err := readFileData(file)
-	if err != nil {
-		log.Printf("UploadMaterial read file error: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to read file", "detail": err.Error()})
-		return
-	}
+	// 客户端断开或中途出错时取消流，material-service 会放弃本次上传
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
 
 	// 创建 gRPC 流
-	stream, err := h.materialClient.UploadMaterial(context.Background())
+	stream, err := h.materialClient.UploadMaterial(ctx)
 	if err != nil {
 		log.Printf("UploadMaterial create stream error: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create upload stream", "detail": err.Error()})
@@ -76,9 +126,10 @@ func (h *MaterialHandler) UploadMaterial(c *gin.Context) {
 			Metadata: &materialpb.MaterialInfo{
 				UserId:           userID,
 				Title:            title,
-				OriginalFilename: header.Filename,
+				OriginalFilename: file.FileName(),
 			},
 		},
+		OnDuplicate: onDuplicate,
 	}
 
 	if err := stream.Send(metadataReq); err != nil {
@@ -87,26 +138,34 @@ func (h *MaterialHandler) UploadMaterial(c *gin.Context) {
 		return
 	}
 
-	// 分块发送文件数据
-	chunkSize := 1024 * 64 // 64KB chunks
-	for i := 0; i < len(fileData); i += chunkSize {
-		end := i + chunkSize
-		if end > len(fileData) {
-			end = len(fileData)
+	// 使用固定大小的缓冲区分块转发文件数据（Send 返回前已完成序列化，缓冲区可复用）
+	buf := make([]byte, uploadChunkSize)
+	var totalBytes int64
+	for {
+		n, readErr := io.ReadFull(file, buf)
+		if n > 0 {
+			chunkReq := &materialpb.UploadMaterialRequest{
+				Data: &materialpb.UploadMaterialRequest_ChunkData{
+					ChunkData: buf[:n],
+				},
+			}
+			if err := stream.Send(chunkReq); err != nil {
+				log.Printf("UploadMaterial send chunk error: %v", err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to send file chunk", "detail": err.Error()})
+				return
+			}
+			totalBytes += int64(n)
 		}
-
-		chunkReq := &materialpb.UploadMaterialRequest{
-			Data: &materialpb.UploadMaterialRequest_ChunkData{
-				ChunkData: fileData[i:end],
-			},
+		if readErr == io.EOF || readErr == io.ErrUnexpectedEOF {
+			break
 		}
-
-		if err := stream.Send(chunkReq); err != nil {
-			log.Printf("UploadMaterial send chunk error: %v", err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to send file chunk", "detail": err.Error()})
+		if readErr != nil {
+			log.Printf("UploadMaterial read file error: %v", readErr)
+			c.JSON(http.StatusBadRequest, gin.H{"error": "failed to read file", "detail": readErr.Error()})
 			return
 		}
 	}
+	log.Printf("UploadMaterial streamed %d bytes for %s", totalBytes, file.FileName())
 
 	// 关闭流并接收响应
 	resp, err := stream.CloseAndRecv()
@@ -122,11 +181,71 @@ func (h *MaterialHandler) UploadMaterial(c *gin.Context) {
 		return
 	}
 
-	log.Printf("UploadMaterial success: materialID=%s", resp.MaterialId)
+	log.Printf("UploadMaterial success: materialID=%s, linked=%v", resp.MaterialId, resp.Linked)
+	// 关联到已有资料时没有新资料，不记录上传动态
+	if !resp.Linked {
+		h.activity.Record(userID, ActivityUpload, resp.MaterialId, title, map[string]string{"filename": file.FileName()})
+	}
 	c.JSON(http.StatusOK, gin.H{
 		"success":     true,
 		"message":     resp.Message,
 		"material_id": resp.MaterialId,
+		"linked":      resp.Linked,
+		"duplicates":  protoJSON(c, resp.Duplicates),
+	})
+}
+
+// CreateClip 保存浏览器扩展提交的网页摘录（选中的文本或 HTML 片段及来源页面信息）为 clip 资料
+// POST /api/materials/clips
+func (h *MaterialHandler) CreateClip(c *gin.Context) {
+	var req struct {
+		Title       string `json:"title"`
+		Content     string `json:"content" binding:"required"`
+		ContentType string `json:"content_type"` // text（默认）或 html
+		SourceURL   string `json:"source_url"`
+		PageTitle   string `json:"page_title"`
+	}
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxClipBodyBytes)
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request", "detail": err.Error()})
+		return
+	}
+
+	userIDInterface, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+	userID, ok := userIDInterface.(string)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "invalid user_id format"})
+		return
+	}
+
+	resp, err := h.materialClient.CreateClip(c.Request.Context(), &materialpb.CreateClipRequest{
+		UserId:      userID,
+		Title:       req.Title,
+		Content:     req.Content,
+		ContentType: req.ContentType,
+		SourceUrl:   req.SourceURL,
+		PageTitle:   req.PageTitle,
+	})
+	if err != nil {
+		log.Printf("CreateClip gRPC error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error", "detail": err.Error()})
+		return
+	}
+	if !resp.Success {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "clip rejected", "detail": resp.Message})
+		return
+	}
+
+	h.activity.Record(userID, ActivityUpload, resp.Material.GetId(), resp.Material.GetTitle(), map[string]string{"source_url": req.SourceURL, "kind": "clip"})
+	c.JSON(http.StatusCreated, gin.H{
+		"success":     true,
+		"message":     resp.Message,
+		"material_id": resp.Material.GetId(),
+		"data":        protoJSON(c, resp.Material),
 	})
 }
 
@@ -153,7 +272,7 @@ func (h *MaterialHandler) DeleteMaterial(c *gin.Context) {
 
 	log.Printf("DeleteMaterial request: materialID=%s, userID=%s", materialID, userID)
 
-	resp, err := h.materialClient.DeleteMaterial(context.Background(), &materialpb.DeleteMaterialRequest{
+	resp, err := h.materialClient.DeleteMaterial(middleware.RPCContext(c), &materialpb.DeleteMaterialRequest{
 		MaterialId: materialID,
 		UserId:     userID,
 	})
@@ -217,7 +336,7 @@ func (h *MaterialHandler) ListMaterials(c *gin.Context) {
 
 	log.Printf("ListMaterials request: userID=%s, page=%d, pageSize=%d", userID, page, pageSize)
 
-	resp, err := h.materialClient.ListMaterials(context.Background(), &materialpb.ListMaterialsRequest{
+	resp, err := h.materialClient.ListMaterials(middleware.RPCContext(c), &materialpb.ListMaterialsRequest{
 		UserId:   userID,
 		Page:     int32(page),
 		PageSize: int32(pageSize),
@@ -232,7 +351,7 @@ func (h *MaterialHandler) ListMaterials(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
 		"data": gin.H{
-			"materials": resp.Materials,
+			"materials": protoJSON(c, resp.Materials),
 			"total":     resp.Total,
 			"page":      page,
 			"page_size": pageSize,
@@ -265,7 +384,7 @@ func (h *MaterialHandler) GetMaterialByID(c *gin.Context) {
 
 	// 由于 proto 中没有 GetMaterialByID，我们先用 ListMaterials 来实现
 	// 在实际项目中，应该在 proto 中添加 GetMaterialByID RPC
-	resp, err := h.materialClient.ListMaterials(context.Background(), &materialpb.ListMaterialsRequest{
+	resp, err := h.materialClient.ListMaterials(middleware.RPCContext(c), &materialpb.ListMaterialsRequest{
 		UserId:   userID,
 		Page:     1,
 		PageSize: 1000, // 设置较大值来获取所有材料
@@ -280,9 +399,10 @@ func (h *MaterialHandler) GetMaterialByID(c *gin.Context) {
 	for _, material := range resp.Materials {
 		if material.Id == materialID {
 			log.Printf("GetMaterialByID success: materialID=%s", materialID)
+			h.activity.Record(userID, ActivityMaterialViewed, materialID, material.Title, nil)
 			c.JSON(http.StatusOK, gin.H{
 				"success": true,
-				"data":    material,
+				"data":    protoJSON(c, material),
 			})
 			return
 		}
@@ -292,6 +412,215 @@ func (h *MaterialHandler) GetMaterialByID(c *gin.Context) {
 	c.JSON(http.StatusNotFound, gin.H{"error": "material not found"})
 }
 
+// mediaHTTPClient 用于代理媒体流。不设置整体超时（长视频传输时长不可预期），由请求上下文控制取消
+var mediaHTTPClient = &http.Client{}
+
+var (
+	// 透传给对象存储的请求头：Range 与条件请求
+	mediaRequestHeaders = []string{"Range", "If-Range", "If-None-Match", "If-Modified-Since"}
+	// 回传给客户端的响应头
+	mediaResponseHeaders = []string{"Content-Type", "Content-Length", "Content-Range", "Accept-Ranges", "ETag", "Last-Modified", "Content-Disposition"}
+)
+
+// isPlayableMedia 是否为可内联播放的音视频类型
+func isPlayableMedia(contentType string) bool {
+	return strings.HasPrefix(contentType, "audio/") || strings.HasPrefix(contentType, "video/")
+}
+
+// getMaterialURL 获取资料的预签名地址，失败时已写入响应
+func (h *MaterialHandler) getMaterialURL(c *gin.Context, expirySeconds int64) (*materialpb.GetMaterialURLResponse, bool) {
+	userIDInterface, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return nil, false
+	}
+	userID, ok := userIDInterface.(string)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "invalid user_id format"})
+		return nil, false
+	}
+
+	materialID := c.Param("id")
+	if materialID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "material_id is required"})
+		return nil, false
+	}
+
+	resp, err := h.materialClient.GetMaterialURL(c.Request.Context(), &materialpb.GetMaterialURLRequest{
+		MaterialId:    materialID,
+		UserId:        userID,
+		ExpirySeconds: expirySeconds,
+	})
+	if err != nil {
+		log.Printf("GetMaterialURL gRPC error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error", "detail": err.Error()})
+		return nil, false
+	}
+	if !resp.Success {
+		status := http.StatusBadRequest
+		switch resp.Message {
+		case "material not found":
+			status = http.StatusNotFound
+		case "permission denied":
+			status = http.StatusForbidden
+		}
+		c.JSON(status, gin.H{"error": resp.Message})
+		return nil, false
+	}
+	return resp, true
+}
+
+// GetMaterialMediaURL 返回带正确 Content-Type 的预签名地址，可直接作为 <video src> 使用（对象存储原生支持 Range）
+// GET /api/materials/:id/media-url?expiry=3600
+func (h *MaterialHandler) GetMaterialMediaURL(c *gin.Context) {
+	expiry, _ := strconv.ParseInt(c.DefaultQuery("expiry", "3600"), 10, 64)
+
+	resp, ok := h.getMaterialURL(c, expiry)
+	if !ok {
+		return
+	}
+	h.activity.Record(c.GetString("user_id"), ActivityMaterialDownloaded, c.Param("id"), "", map[string]string{"via": "media-url"})
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"url":          resp.Url,
+			"content_type": resp.ContentType,
+			"size_bytes":   resp.SizeBytes,
+			"expires_at":   formatTimestamp(c, resp.ExpiresAt),
+		},
+	})
+}
+
+// StreamMaterialMedia 代理资料文件并透传 Range 请求，供需要鉴权访问的播放器拖动进度
+// GET /api/materials/:id/media
+func (h *MaterialHandler) StreamMaterialMedia(c *gin.Context) {
+	// 代理请求只需短期有效的地址
+	resp, ok := h.getMaterialURL(c, 300)
+	if !ok {
+		return
+	}
+
+	req, err := http.NewRequestWithContext(c.Request.Context(), http.MethodGet, resp.Url, nil)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to build media request", "detail": err.Error()})
+		return
+	}
+	for _, name := range mediaRequestHeaders {
+		if v := c.GetHeader(name); v != "" {
+			req.Header.Set(name, v)
+		}
+	}
+
+	upstream, err := mediaHTTPClient.Do(req)
+	if err != nil {
+		log.Printf("StreamMaterialMedia upstream error: %v", err)
+		c.JSON(http.StatusBadGateway, gin.H{"error": "failed to fetch media", "detail": err.Error()})
+		return
+	}
+	defer upstream.Body.Close()
+
+	for _, name := range mediaResponseHeaders {
+		if v := upstream.Header.Get(name); v != "" {
+			c.Header(name, v)
+		}
+	}
+	if upstream.Header.Get("Accept-Ranges") == "" {
+		c.Header("Accept-Ranges", "bytes")
+	}
+	// 代理响应与 API 同源，音视频以外的文件（如 html、svg）内联打开会执行其中的脚本，
+	// 一律作为附件下载，并禁止浏览器猜测类型
+	c.Header("X-Content-Type-Options", "nosniff")
+	if !isPlayableMedia(resp.ContentType) {
+		disposition := "attachment"
+		if _, params, err := mime.ParseMediaType(upstream.Header.Get("Content-Disposition")); err == nil {
+			disposition = mime.FormatMediaType("attachment", params)
+		}
+		c.Header("Content-Disposition", disposition)
+	}
+
+	// 播放器拖动进度会发出大量 Range 请求，只有从头读取的请求计为一次下载
+	if upstream.StatusCode < http.StatusMultipleChoices {
+		if r := c.GetHeader("Range"); r == "" || strings.HasPrefix(r, "bytes=0-") {
+			h.activity.Record(c.GetString("user_id"), ActivityMaterialDownloaded, c.Param("id"), "", map[string]string{"via": "media"})
+		}
+	}
+
+	// 200 / 206 / 304 / 416 等状态原样返回
+	c.Status(upstream.StatusCode)
+	if _, err := io.Copy(c.Writer, upstream.Body); err != nil {
+		// 客户端拖动进度时会主动断开上一个请求，属于正常情况
+		log.Printf("StreamMaterialMedia copy interrupted: %v", err)
+	}
+}
+
+// ListAccessLog 资料访问记录：谁在何时查看、下载了资料或在 AI 提问中引用了资料，仅资料所有者可查看
+// GET /api/materials/:id/access-log?action=view|download|ai_question&limit=20&offset=0
+func (h *MaterialHandler) ListAccessLog(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	materialID := c.Param("id")
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit <= 0 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+	action := c.Query("action")
+	switch action {
+	case "", "view", "download", "ai_question":
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "action must be view, download or ai_question"})
+		return
+	}
+
+	// GetMaterial 按 user_id 过滤，非所有者视为资料不存在
+	mresp, err := h.materialClient.GetMaterial(c.Request.Context(), &materialpb.GetMaterialRequest{MaterialId: materialID, UserId: userID})
+	if err != nil {
+		log.Printf("ListAccessLog GetMaterial gRPC error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error", "detail": err.Error()})
+		return
+	}
+	if !mresp.Found {
+		c.JSON(http.StatusNotFound, gin.H{"error": "material not found"})
+		return
+	}
+
+	resp, err := h.userClient.ListMaterialAccess(c.Request.Context(), &userpb.ListMaterialAccessRequest{
+		MaterialId: materialID,
+		Action:     action,
+		Limit:      int32(limit),
+		Offset:     int32(offset),
+	})
+	if err != nil {
+		log.Printf("ListAccessLog gRPC error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error", "detail": err.Error()})
+		return
+	}
+	if !resp.Success {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "failed to list access log", "detail": resp.Message})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"entries": resp.Entries,
+			"total":   resp.Total,
+			"limit":   limit,
+			"offset":  offset,
+		},
+	})
+}
+
 // ProcessMaterial AI处理材料
 func (h *MaterialHandler) ProcessMaterial(c *gin.Context) {
 	var req struct {
@@ -340,7 +669,7 @@ func (h *MaterialHandler) ProcessMaterial(c *gin.Context) {
 		req.MaterialID, req.ProcessingType, userIDStr)
 
 	// 调用 gRPC 服务
-	resp, err := h.materialClient.ProcessMaterial(context.Background(), &materialpb.ProcessMaterialRequest{
+	resp, err := h.materialClient.ProcessMaterial(middleware.RPCContext(c), &materialpb.ProcessMaterialRequest{
 		MaterialId: req.MaterialID,
 		UserId:     userIDStr,
 		Type:       procType,
@@ -353,17 +682,33 @@ func (h *MaterialHandler) ProcessMaterial(c *gin.Context) {
 		return
 	}
 
+	if !resp.Success {
+		log.Printf("ProcessMaterial rejected: materialID=%s, message=%s", req.MaterialID, resp.Message)
+		processingRejected(c, resp.Message, resp.Limit)
+		return
+	}
+
 	log.Printf("ProcessMaterial success: taskID=%s", resp.TaskId)
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
 		"data": gin.H{
 			"task_id": resp.TaskId,
 			"message": resp.Message,
-			"result":  resp.Result,
+			"result":  protoJSON(c, resp.Result),
+			"limit":   protoJSON(c, resp.Limit),
 		},
 	})
 }
 
+// processingRejected 处理请求被拒绝：超出用户并发上限时返回 429 与当前用量，其余返回 400
+func processingRejected(c *gin.Context, message string, limit *materialpb.ProcessingLimit) {
+	if limit != nil {
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": message, "limit": protoJSON(c, limit)})
+		return
+	}
+	c.JSON(http.StatusBadRequest, gin.H{"error": message})
+}
+
 // GetProcessingResult 获取处理结果
 func (h *MaterialHandler) GetProcessingResult(c *gin.Context) {
 	materialID := c.Param("material_id")
@@ -414,7 +759,7 @@ func (h *MaterialHandler) GetProcessingResult(c *gin.Context) {
 	log.Printf("GetProcessingResult request: materialID=%s, type=%s, userID=%s", materialID, processingTypeStr, userIDStr)
 
 	// 调用 gRPC 服务
-	resp, err := h.materialClient.GetProcessingResult(context.Background(), &materialpb.GetProcessingResultRequest{
+	resp, err := h.materialClient.GetProcessingResult(middleware.RPCContext(c), &materialpb.GetProcessingResultRequest{
 		MaterialId: materialID,
 		UserId:     userIDStr,
 		Type:       procType,
@@ -435,7 +780,7 @@ func (h *MaterialHandler) GetProcessingResult(c *gin.Context) {
 	log.Printf("GetProcessingResult success: materialID=%s, type=%s", materialID, processingTypeStr)
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
-		"data":    resp.Result,
+		"data":    protoJSON(c, resp.Result),
 	})
 }
 
@@ -493,7 +838,7 @@ func (h *MaterialHandler) ListProcessingResults(c *gin.Context) {
 		userIDStr, materialID, processingTypeStr, page, pageSize)
 
 	// 调用 gRPC 服务
-	resp, err := h.materialClient.ListProcessingResults(context.Background(), &materialpb.ListProcessingResultsRequest{
+	resp, err := h.materialClient.ListProcessingResults(middleware.RPCContext(c), &materialpb.ListProcessingResultsRequest{
 		MaterialId: materialID,
 		UserId:     userIDStr,
 		Type:       procType,
@@ -511,7 +856,7 @@ func (h *MaterialHandler) ListProcessingResults(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
 		"data": gin.H{
-			"results":   resp.Results,
+			"results":   protoJSON(c, resp.Results),
 			"total":     resp.Total,
 			"page":      page,
 			"page_size": pageSize,
@@ -562,7 +907,7 @@ func (h *MaterialHandler) UpdateProcessingResult(c *gin.Context) {
 		taskID, req.Status)
 
 	// 调用 gRPC 服务
-	resp, err := h.materialClient.UpdateProcessingResult(context.Background(), &materialpb.UpdateProcessingResultRequest{
+	resp, err := h.materialClient.UpdateProcessingResult(middleware.RPCContext(c), &materialpb.UpdateProcessingResultRequest{
 		TaskId:       taskID,
 		Status:       procStatus,
 		Content:      req.Content,
@@ -583,11 +928,482 @@ func (h *MaterialHandler) UpdateProcessingResult(c *gin.Context) {
 	})
 }
 
-// readFileData 读取文件数据的辅助函数
-func readFileData(file multipart.File) ([]byte, error) {
-	data, err := io.ReadAll(file)
+// RetryProcessingTask 重试失败的处理任务
+func (h *MaterialHandler) RetryProcessingTask(c *gin.Context) {
+	taskID := c.Param("task_id")
+	if taskID == "" {
+		log.Println("RetryProcessingTask task_id is required")
+		c.JSON(http.StatusBadRequest, gin.H{"error": "task_id is required"})
+		return
+	}
+
+	// 从认证中间件获取用户ID
+	userID, exists := c.Get("user_id")
+	if !exists {
+		log.Println("RetryProcessingTask user_id not found in context")
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found"})
+		return
+	}
+
+	userIDStr, ok := userID.(string)
+	if !ok {
+		log.Printf("RetryProcessingTask invalid user_id type: %T", userID)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	log.Printf("RetryProcessingTask request: taskID=%s, userID=%s", taskID, userIDStr)
+
+	// 调用 gRPC 服务
+	resp, err := h.materialClient.RetryProcessingTask(middleware.RPCContext(c), &materialpb.RetryProcessingTaskRequest{
+		TaskId: taskID,
+		UserId: userIDStr,
+	})
+
 	if err != nil {
-		return nil, err
+		log.Printf("RetryProcessingTask gRPC error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retry processing task"})
+		return
 	}
-	return data, nil
+
+	if !resp.Success {
+		log.Printf("RetryProcessingTask rejected: taskID=%s, message=%s", taskID, resp.Message)
+		processingRejected(c, resp.Message, resp.Limit)
+		return
+	}
+
+	log.Printf("RetryProcessingTask success: taskID=%s", taskID)
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": resp.Message,
+		"data":    protoJSON(c, resp.Result),
+		"limit":   protoJSON(c, resp.Limit),
+	})
+}
+
+// CompareProcessingResults 对比资料的两次处理结果（如切换 OCR 引擎后的对比处理与当前结果），
+// 返回两侧的质量指标与逐行差异。base / target 为任务 ID，省略时 base 取当前结果、target 取最近一次其它结果；
+// type 默认 OCR，context 为差异块前后保留的行数
+// GET /api/processing/results/:material_id/compare
+func (h *MaterialHandler) CompareProcessingResults(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	procType := materialpb.ProcessingType_OCR
+	switch c.DefaultQuery("type", "OCR") {
+	case "OCR":
+	case "ASR":
+		procType = materialpb.ProcessingType_ASR
+	case "LLM_ANALYSIS":
+		procType = materialpb.ProcessingType_LLM_ANALYSIS
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid processing type"})
+		return
+	}
+	contextLines := 0
+	if v := c.Query("context"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid context", "detail": "context must be a non-negative integer"})
+			return
+		}
+		contextLines = n
+	}
+
+	resp, err := h.materialClient.CompareProcessingResults(middleware.RPCContext(c), &materialpb.CompareProcessingResultsRequest{
+		MaterialId:   c.Param("material_id"),
+		UserId:       userID,
+		Type:         procType,
+		BaseTaskId:   c.Query("base"),
+		TargetTaskId: c.Query("target"),
+		ContextLines: int32(contextLines),
+	})
+	if err != nil {
+		log.Printf("CompareProcessingResults gRPC error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error", "detail": err.Error()})
+		return
+	}
+	if !resp.Success {
+		c.JSON(derivedErrorStatus(resp.Message), gin.H{"error": resp.Message})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    protoJSON(c, resp),
+	})
+}
+
+// EstimateProcessing 在确认处理前预估资料的处理用量与费用：PDF 页数、音视频时长取自文件元数据，
+// 识别文本量与 token 按配置的系数估算。probed 为 false 时页数或时长按文件大小估算
+// GET /api/materials/:id/processing-estimate
+func (h *MaterialHandler) EstimateProcessing(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	// 探测需要读取 PDF 结构或运行 ffprobe，超时比普通查询更长
+	ctx, cancel := context.WithTimeout(middleware.RPCContext(c), 30*time.Second)
+	defer cancel()
+
+	resp, err := h.materialClient.EstimateProcessing(ctx, &materialpb.EstimateProcessingRequest{
+		MaterialId: c.Param("id"),
+		UserId:     userID,
+	})
+	if err != nil {
+		log.Printf("EstimateProcessing gRPC error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error", "detail": err.Error()})
+		return
+	}
+	if !resp.Success {
+		c.JSON(derivedErrorStatus(resp.Message), gin.H{"error": resp.Message})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    protoJSON(c, resp),
+	})
+}
+
+// ListChildMaterials 列出压缩包（.zip）上传后展开出的子资料
+// GET /api/materials/:id/children
+func (h *MaterialHandler) ListChildMaterials(c *gin.Context) {
+	userIDInterface, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+	userID, ok := userIDInterface.(string)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "invalid user_id format"})
+		return
+	}
+
+	materialID := c.Param("id")
+	resp, err := h.materialClient.ListChildMaterials(middleware.RPCContext(c), &materialpb.ListChildMaterialsRequest{
+		MaterialId: materialID,
+		UserId:     userID,
+	})
+	if err != nil {
+		log.Printf("ListChildMaterials gRPC error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error", "detail": err.Error()})
+		return
+	}
+	if !resp.Success {
+		c.JSON(derivedErrorStatus(resp.Message), gin.H{"error": resp.Message})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    protoJSON(c, resp.Materials),
+	})
+}
+
+// ListDuplicateMaterials 列出与资料内容相同或相近的本人资料
+// GET /api/materials/:id/duplicates
+func (h *MaterialHandler) ListDuplicateMaterials(c *gin.Context) {
+	userIDInterface, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+	userID, ok := userIDInterface.(string)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "invalid user_id format"})
+		return
+	}
+
+	resp, err := h.materialClient.ListDuplicateMaterials(middleware.RPCContext(c), &materialpb.ListDuplicateMaterialsRequest{
+		MaterialId: c.Param("id"),
+		UserId:     userID,
+	})
+	if err != nil {
+		log.Printf("ListDuplicateMaterials gRPC error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error", "detail": err.Error()})
+		return
+	}
+	if !resp.Success {
+		c.JSON(derivedErrorStatus(resp.Message), gin.H{"error": resp.Message})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    protoJSON(c, resp.Duplicates),
+	})
+}
+
+// ListDerivedArtifacts 查看资料派生内容（向量分片、摘要、题目）的新鲜度
+// GET /api/materials/:id/derived
+func (h *MaterialHandler) ListDerivedArtifacts(c *gin.Context) {
+	userIDInterface, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+	userID, ok := userIDInterface.(string)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "invalid user_id format"})
+		return
+	}
+
+	materialID := c.Param("id")
+	resp, err := h.materialClient.ListDerivedArtifacts(middleware.RPCContext(c), &materialpb.ListDerivedArtifactsRequest{
+		MaterialId: materialID,
+		UserId:     userID,
+	})
+	if err != nil {
+		log.Printf("ListDerivedArtifacts gRPC error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error", "detail": err.Error()})
+		return
+	}
+	if !resp.Success {
+		c.JSON(derivedErrorStatus(resp.Message), gin.H{"error": resp.Message})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    protoJSON(c, resp.Artifacts),
+	})
+}
+
+// RegenerateDerived 以资料最新的处理结果重建派生内容，kinds 为空时重建所有过期的派生内容
+// POST /api/materials/:id/derived/regenerate
+func (h *MaterialHandler) RegenerateDerived(c *gin.Context) {
+	var req struct {
+		Kinds []string `json:"kinds"`
+	}
+	// 请求体可省略
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	userIDInterface, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+	userID, ok := userIDInterface.(string)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "invalid user_id format"})
+		return
+	}
+
+	materialID := c.Param("id")
+	log.Printf("RegenerateDerived request: materialID=%s, userID=%s, kinds=%v", materialID, userID, req.Kinds)
+
+	resp, err := h.materialClient.RegenerateDerived(middleware.RPCContext(c), &materialpb.RegenerateDerivedRequest{
+		MaterialId: materialID,
+		UserId:     userID,
+		Kinds:      req.Kinds,
+	})
+	if err != nil {
+		log.Printf("RegenerateDerived gRPC error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error", "detail": err.Error()})
+		return
+	}
+	if !resp.Success {
+		c.JSON(derivedErrorStatus(resp.Message), gin.H{"error": resp.Message})
+		return
+	}
+
+	// 重建异步进行，通过 GET /api/materials/:id/derived 查看进度
+	c.JSON(http.StatusAccepted, gin.H{
+		"success": true,
+		"message": resp.Message,
+		"data":    protoJSON(c, resp.Artifacts),
+	})
+}
+
+// derivedErrorStatus 将 material-service 的错误信息映射为 HTTP 状态码
+func derivedErrorStatus(message string) int {
+	switch {
+	case strings.HasPrefix(message, "material not found"), strings.HasPrefix(message, "annotation not found"):
+		return http.StatusNotFound
+	case strings.HasPrefix(message, "permission denied"):
+		return http.StatusForbidden
+	default:
+		return http.StatusBadRequest
+	}
+}
+
+// ======================= 批注相关 =======================
+
+// ListAnnotations 列出当前用户在资料上的批注，可按 kind、page 或时间段（start_time / end_time，秒）过滤
+// GET /api/materials/:id/annotations
+func (h *MaterialHandler) ListAnnotations(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	req := &materialpb.ListAnnotationsRequest{
+		UserId:      userID,
+		MaterialIds: []string{c.Param("id")},
+		Kind:        c.Query("kind"),
+	}
+	if v := c.Query("page"); v != "" {
+		page, err := strconv.Atoi(v)
+		if err != nil || page < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "page must be a positive integer"})
+			return
+		}
+		req.Page = int32(page)
+	}
+	for name, dst := range map[string]*float64{"start_time": &req.StartTime, "end_time": &req.EndTime} {
+		if v := c.Query(name); v != "" {
+			t, err := strconv.ParseFloat(v, 64)
+			if err != nil || t < 0 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": name + " must be a non-negative number of seconds"})
+				return
+			}
+			*dst = t
+		}
+	}
+
+	resp, err := h.materialClient.ListAnnotations(c.Request.Context(), req)
+	if err != nil {
+		log.Printf("ListAnnotations gRPC error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error", "detail": err.Error()})
+		return
+	}
+	if !resp.Success {
+		c.JSON(derivedErrorStatus(resp.Message), gin.H{"error": resp.Message})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    protoJSON(c, resp.Annotations),
+	})
+}
+
+// CreateAnnotation 在资料上添加评论或高亮。anchor_type 为 page 时锚定到页码，为 time 时锚定到音视频的时间段，
+// 为空表示针对整份资料的评论
+// POST /api/materials/:id/annotations
+func (h *MaterialHandler) CreateAnnotation(c *gin.Context) {
+	var req struct {
+		Kind       string  `json:"kind" binding:"required,oneof=comment highlight"`
+		AnchorType string  `json:"anchor_type" binding:"omitempty,oneof=page time"`
+		Page       int32   `json:"page"`
+		StartTime  float64 `json:"start_time"`
+		EndTime    float64 `json:"end_time"`
+		Quote      string  `json:"quote"`
+		Content    string  `json:"content"`
+		Color      string  `json:"color"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input", "detail": err.Error()})
+		return
+	}
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	resp, err := h.materialClient.CreateAnnotation(c.Request.Context(), &materialpb.CreateAnnotationRequest{
+		UserId:     userID,
+		MaterialId: c.Param("id"),
+		Kind:       req.Kind,
+		AnchorType: req.AnchorType,
+		Page:       req.Page,
+		StartTime:  req.StartTime,
+		EndTime:    req.EndTime,
+		Quote:      req.Quote,
+		Content:    req.Content,
+		Color:      req.Color,
+	})
+	if err != nil {
+		log.Printf("CreateAnnotation gRPC error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error", "detail": err.Error()})
+		return
+	}
+	if !resp.Success {
+		c.JSON(derivedErrorStatus(resp.Message), gin.H{"error": resp.Message})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    protoJSON(c, resp.Annotation),
+	})
+}
+
+// UpdateAnnotation 修改批注内容或颜色，未提供的字段保持不变
+// PATCH /api/materials/:id/annotations/:annotation_id
+func (h *MaterialHandler) UpdateAnnotation(c *gin.Context) {
+	var req struct {
+		Content string `json:"content"`
+		Color   string `json:"color"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input", "detail": err.Error()})
+		return
+	}
+	if req.Content == "" && req.Color == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "content or color is required"})
+		return
+	}
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	resp, err := h.materialClient.UpdateAnnotation(c.Request.Context(), &materialpb.UpdateAnnotationRequest{
+		Id:      c.Param("annotation_id"),
+		UserId:  userID,
+		Content: req.Content,
+		Color:   req.Color,
+	})
+	if err != nil {
+		log.Printf("UpdateAnnotation gRPC error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error", "detail": err.Error()})
+		return
+	}
+	if !resp.Success {
+		c.JSON(derivedErrorStatus(resp.Message), gin.H{"error": resp.Message})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    protoJSON(c, resp.Annotation),
+	})
+}
+
+// DeleteAnnotation 删除当前用户的批注
+// DELETE /api/materials/:id/annotations/:annotation_id
+func (h *MaterialHandler) DeleteAnnotation(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	resp, err := h.materialClient.DeleteAnnotation(c.Request.Context(), &materialpb.DeleteAnnotationRequest{
+		Id:     c.Param("annotation_id"),
+		UserId: userID,
+	})
+	if err != nil {
+		log.Printf("DeleteAnnotation gRPC error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error", "detail": err.Error()})
+		return
+	}
+	if !resp.Success {
+		c.JSON(derivedErrorStatus(resp.Message), gin.H{"error": resp.Message})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": resp.Message,
+	})
 }
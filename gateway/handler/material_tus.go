@@ -0,0 +1,285 @@
+package handler
+
+import (
+	"encoding/base64"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/RigelNana/arkstudy/gateway/middleware"
+	materialpb "github.com/RigelNana/arkstudy/proto/material"
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	tusVersion = "1.0.0"
+	// 支持的 tus 扩展：创建、过期时间与终止
+	tusExtensions = "creation,expiration,termination"
+	// PATCH 请求体的类型
+	tusContentType = "application/offset+octet-stream"
+)
+
+// TusHandler 以 tus 1.0 协议（https://tus.io/protocols/resumable-upload）上传资料，对应 material-service 的上传会话：
+// POST 创建会话，PATCH 从 Upload-Offset 处追加内容，HEAD 查询已接收的字节数，DELETE 放弃上传。
+// 连接中断时已送达的内容仍会保存，客户端 HEAD 后从返回的偏移量继续，无需从头上传。
+// Upload-Metadata 支持 filename（必填）、title（默认为 filename）与 on_duplicate；
+// 接收满 Upload-Length 后创建资料，PATCH 与之后的 HEAD 响应在 X-Material-ID 中返回资料 ID
+type TusHandler struct {
+	materialClient materialpb.MaterialServiceClient
+	activity       *ActivityRecorder
+	maxSize        int64
+}
+
+func NewTusHandler(materials *MaterialHandler, maxSize int64) *TusHandler {
+	return &TusHandler{
+		materialClient: materials.materialClient,
+		activity:       materials.activity,
+		maxSize:        maxSize,
+	}
+}
+
+// TusResumable 为响应附加 Tus-Resumable，并拒绝协议版本不符的请求（OPTIONS 除外）
+func TusResumable() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("Tus-Resumable", tusVersion)
+		if c.Request.Method != http.MethodOptions && c.GetHeader("Tus-Resumable") != tusVersion {
+			c.Header("Tus-Version", tusVersion)
+			c.AbortWithStatusJSON(http.StatusPreconditionFailed, gin.H{"error": "unsupported tus version, expected " + tusVersion})
+			return
+		}
+		c.Next()
+	}
+}
+
+// Options 返回服务端支持的协议版本、扩展与大小上限
+// OPTIONS /api/materials/uploads
+func (h *TusHandler) Options(c *gin.Context) {
+	c.Header("Tus-Version", tusVersion)
+	c.Header("Tus-Extension", tusExtensions)
+	if h.maxSize > 0 {
+		c.Header("Tus-Max-Size", strconv.FormatInt(h.maxSize, 10))
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// Create 创建上传会话，Location 为之后 PATCH / HEAD / DELETE 的地址
+// POST /api/materials/uploads
+func (h *TusHandler) Create(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	if c.GetHeader("Upload-Defer-Length") != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Upload-Defer-Length is not supported"})
+		return
+	}
+	size, err := strconv.ParseInt(c.GetHeader("Upload-Length"), 10, 64)
+	if err != nil || size <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Upload-Length must be a positive integer"})
+		return
+	}
+	if h.maxSize > 0 && size > h.maxSize {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "upload exceeds Tus-Max-Size", "max_bytes": h.maxSize})
+		return
+	}
+	metadata, err := parseTusMetadata(c.GetHeader("Upload-Metadata"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid Upload-Metadata", "detail": err.Error()})
+		return
+	}
+	filename := strings.TrimSpace(metadata["filename"])
+	if filename == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "filename is required in Upload-Metadata"})
+		return
+	}
+	title := strings.TrimSpace(metadata["title"])
+	if title == "" {
+		title = filename
+	}
+	if len(title) > maxTitleBytes {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "title is too long"})
+		return
+	}
+
+	resp, err := h.materialClient.CreateUploadSession(middleware.RPCContext(c), &materialpb.CreateUploadSessionRequest{
+		UserId:           userID,
+		Title:            title,
+		OriginalFilename: filename,
+		Size:             size,
+		OnDuplicate:      metadata["on_duplicate"],
+	})
+	if err != nil {
+		tusError(c, "CreateUploadSession", err)
+		return
+	}
+	log.Printf("Tus upload created: session=%s, userID=%s, filename=%s, size=%d", resp.Session.Id, userID, filename, size)
+	setTusSessionHeaders(c, resp.Session)
+	c.Header("Location", strings.TrimSuffix(c.Request.URL.Path, "/")+"/"+resp.Session.Id)
+	c.Status(http.StatusCreated)
+}
+
+// Head 返回已接收的字节数，客户端据此从断点继续
+// HEAD /api/materials/uploads/:id
+func (h *TusHandler) Head(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	resp, err := h.materialClient.GetUploadSession(middleware.RPCContext(c), &materialpb.GetUploadSessionRequest{
+		SessionId: c.Param("id"),
+		UserId:    userID,
+	})
+	if err != nil {
+		c.Status(tusHTTPStatus(err))
+		return
+	}
+	setTusSessionHeaders(c, resp.Session)
+	c.Header("Upload-Length", strconv.FormatInt(resp.Session.Size, 10))
+	c.Header("Upload-Metadata", "filename "+base64.StdEncoding.EncodeToString([]byte(resp.Session.OriginalFilename))+
+		",title "+base64.StdEncoding.EncodeToString([]byte(resp.Session.Title)))
+	c.Header("Cache-Control", "no-store")
+	c.Status(http.StatusOK)
+}
+
+// Patch 从 Upload-Offset 处追加请求体。客户端中途断开时结束转发并提交已送达的部分，
+// 因此流使用不随请求取消的 ctx
+// PATCH /api/materials/uploads/:id
+func (h *TusHandler) Patch(c *gin.Context) {
+	if c.ContentType() != tusContentType {
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": "Content-Type must be " + tusContentType})
+		return
+	}
+	offset, err := strconv.ParseInt(c.GetHeader("Upload-Offset"), 10, 64)
+	if err != nil || offset < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Upload-Offset must be a non-negative integer"})
+		return
+	}
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	stream, err := h.materialClient.AppendUploadSession(middleware.RPCContext(c))
+	if err != nil {
+		log.Printf("AppendUploadSession create stream error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create upload stream", "detail": err.Error()})
+		return
+	}
+	err = stream.Send(&materialpb.AppendUploadSessionRequest{
+		Data: &materialpb.AppendUploadSessionRequest_Target{
+			Target: &materialpb.UploadSessionTarget{SessionId: c.Param("id"), UserId: userID, Offset: offset},
+		},
+	})
+
+	// Send 失败说明 material-service 已结束本次追加（如偏移量不符），原因由 CloseAndRecv 返回
+	buf := make([]byte, uploadChunkSize)
+	var received int64
+	for err == nil {
+		n, readErr := io.ReadFull(c.Request.Body, buf)
+		if n > 0 {
+			err = stream.Send(&materialpb.AppendUploadSessionRequest{
+				Data: &materialpb.AppendUploadSessionRequest_ChunkData{ChunkData: buf[:n]},
+			})
+			received += int64(n)
+		}
+		if readErr == io.EOF || readErr == io.ErrUnexpectedEOF {
+			break
+		}
+		if readErr != nil {
+			log.Printf("Tus upload %s: request body interrupted after %d bytes: %v", c.Param("id"), received, readErr)
+			break
+		}
+	}
+
+	resp, err := stream.CloseAndRecv()
+	if err != nil {
+		tusError(c, "AppendUploadSession", err)
+		return
+	}
+	session := resp.Session
+	setTusSessionHeaders(c, session)
+	if resp.Completed {
+		log.Printf("Tus upload completed: session=%s, materialID=%s, linked=%v", session.Id, session.MaterialId, resp.Linked)
+		// 关联到已有资料时没有新资料，不记录上传动态
+		if !resp.Linked {
+			h.activity.Record(userID, ActivityUpload, session.MaterialId, session.Title, map[string]string{"filename": session.OriginalFilename})
+		}
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// Delete 放弃上传并删除已接收的内容
+// DELETE /api/materials/uploads/:id
+func (h *TusHandler) Delete(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	_, err := h.materialClient.DeleteUploadSession(middleware.RPCContext(c), &materialpb.DeleteUploadSessionRequest{
+		SessionId: c.Param("id"),
+		UserId:    userID,
+	})
+	if err != nil {
+		tusError(c, "DeleteUploadSession", err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// setTusSessionHeaders 写入会话的偏移量、过期时间与完成后的资料 ID
+func setTusSessionHeaders(c *gin.Context, session *materialpb.UploadSession) {
+	c.Header("Upload-Offset", strconv.FormatInt(session.Offset, 10))
+	if session.ExpiresAt != nil && session.Status != "completed" {
+		c.Header("Upload-Expires", session.ExpiresAt.AsTime().UTC().Format(http.TimeFormat))
+	}
+	if session.MaterialId != "" {
+		c.Header("X-Material-ID", session.MaterialId)
+	}
+}
+
+// parseTusMetadata 解析 Upload-Metadata："key base64值" 以逗号分隔，值可省略
+func parseTusMetadata(header string) (map[string]string, error) {
+	metadata := map[string]string{}
+	for _, pair := range strings.Split(header, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		key, encoded, _ := strings.Cut(pair, " ")
+		value, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+		if err != nil {
+			return nil, errors.New("value of " + key + " is not valid base64")
+		}
+		metadata[key] = string(value)
+	}
+	return metadata, nil
+}
+
+// tusHTTPStatus 将上传会话的 gRPC 状态码映射为 tus 约定的 HTTP 状态码
+func tusHTTPStatus(err error) int {
+	switch status.Code(err) {
+	case codes.InvalidArgument:
+		return http.StatusBadRequest
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.FailedPrecondition:
+		return http.StatusGone // 会话已过期
+	case codes.Aborted:
+		return http.StatusConflict // Upload-Offset 与已接收的字节数不符
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func tusError(c *gin.Context, op string, err error) {
+	code := tusHTTPStatus(err)
+	if code == http.StatusInternalServerError {
+		log.Printf("%s gRPC error: %v", op, err)
+	}
+	c.JSON(code, gin.H{"error": "upload request failed", "detail": grpcErrorMessage(err)})
+}
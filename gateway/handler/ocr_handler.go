@@ -4,12 +4,14 @@ import (
 	"context"
 	"log"
 	"net/http"
-	"os"
 	"time"
 
+	"github.com/RigelNana/arkstudy/gateway/backend"
+	"github.com/RigelNana/arkstudy/gateway/middleware"
+	"github.com/RigelNana/arkstudy/pkg/discovery"
+	"github.com/RigelNana/arkstudy/pkg/registry"
 	aipb "github.com/RigelNana/arkstudy/proto/ai"
 	"github.com/gin-gonic/gin"
-	"google.golang.org/grpc"
 )
 
 type OCRHandler struct {
@@ -17,43 +19,30 @@ type OCRHandler struct {
 }
 
 func NewOCRHandler() *OCRHandler {
-	addr := os.Getenv("OCR_GRPC_ADDR")
-	if addr == "" {
-		// 检查K8s环境变量
-		ocrHost := os.Getenv("ARKSTUDY_OCR_SERVICE_SERVICE_HOST")
-		ocrPort := os.Getenv("ARKSTUDY_OCR_SERVICE_SERVICE_PORT")
-		if ocrHost != "" && ocrPort != "" {
-			addr = ocrHost + ":" + ocrPort
-		} else {
-			addr = "arkstudy-ocr-service:50055" // 使用服务名作为默认值
-		}
-	}
-	log.Printf("OCR service address: %s", addr)
-	conn, err := grpc.Dial(addr, grpc.WithInsecure())
-	if err != nil {
-		log.Fatalf("dial ocr-service: %v", err)
-	}
-	return &OCRHandler{client: aipb.NewAIServiceClient(conn)}
+	log.Printf("OCR service target: %s", discovery.Target(registry.OCR))
+	return &OCRHandler{client: aipb.NewAIServiceClient(backend.For(registry.OCR))}
 }
 
 func (h *OCRHandler) ProcessOCR(c *gin.Context) {
 	var req struct {
-		FileURL  string `json:"file_url" binding:"required"`
-		FileType string `json:"file_type"`
-		TaskID   string `json:"task_id"`
+		FileURL  string            `json:"file_url" binding:"required"`
+		FileType string            `json:"file_type"`
+		TaskID   string            `json:"task_id"`
+		Options  map[string]string `json:"options"` // 透传给 ocr-service，如 engine、lang、table
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := context.WithTimeout(middleware.RPCContext(c), 30*time.Second)
 	defer cancel()
 
 	resp, err := h.client.ProcessOCR(ctx, &aipb.OCRRequest{
 		FileUrl:  req.FileURL,
 		FileType: req.FileType,
 		TaskId:   req.TaskID,
+		Options:  req.Options,
 	})
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to process ocr", "detail": err.Error()})
@@ -0,0 +1,314 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/RigelNana/arkstudy/gateway/export"
+	"github.com/RigelNana/arkstudy/gateway/middleware"
+	arkkafka "github.com/RigelNana/arkstudy/pkg/kafka"
+	"github.com/RigelNana/arkstudy/pkg/locale"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// 生成单个离线包的超时时间
+	offlineBundleTimeout = 10 * time.Minute
+	// 每个用户同时生成的离线包数上限
+	maxRunningOfflineBundles = 2
+	// 每个用户保留的已完成离线包数上限，超出时删除最早完成的
+	maxFinishedOfflineBundles = 5
+	// 离线包状态与下载地址的路径前缀
+	offlineBundlePath = "/api/exports/offline-bundles/"
+)
+
+// offlineBundleJob 一次离线包生成任务，ID 与任务中心的任务 ID 相同
+type offlineBundleJob struct {
+	id         string
+	userID     string
+	materialID string
+	status     string // arkkafka.TaskStatus*
+	err        string
+	data       []byte
+	manifest   map[string]string
+	warnings   []string
+	createdAt  time.Time
+	finishedAt time.Time
+}
+
+// offlineBundleStore 保存生成中与已生成的离线包。文件保存在 gateway 内存中，完成后保留 ttl，
+// 每个用户最多保留 maxFinishedOfflineBundles 个，全部文件合计不超过 maxBytes，超出时删除最早完成的；
+// 多副本部署时状态与下载请求需路由到发起生成的副本（或由客户端在失败时重新生成）
+type offlineBundleStore struct {
+	mu       sync.Mutex
+	jobs     map[string]*offlineBundleJob
+	ttl      time.Duration
+	maxBytes int64
+	bytes    int64 // 已保存文件的总字节数
+}
+
+// newOfflineBundleStore OFFLINE_BUNDLE_TTL 为离线包生成后保留的时长（默认 1h），
+// OFFLINE_BUNDLE_MAX_TOTAL_MB 为内存中保存的离线包合计大小上限（默认 512）
+func newOfflineBundleStore() *offlineBundleStore {
+	ttl := time.Hour
+	if v := os.Getenv("OFFLINE_BUNDLE_TTL"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d > 0 {
+			ttl = d
+		} else {
+			log.Printf("invalid OFFLINE_BUNDLE_TTL=%q, using %s", v, ttl)
+		}
+	}
+	maxMB := int64(512)
+	if v := os.Getenv("OFFLINE_BUNDLE_MAX_TOTAL_MB"); v != "" {
+		if n, err := strconv.ParseInt(v, 10, 64); err == nil && n > 0 {
+			maxMB = n
+		} else {
+			log.Printf("invalid OFFLINE_BUNDLE_MAX_TOTAL_MB=%q, using %d", v, maxMB)
+		}
+	}
+	return &offlineBundleStore{jobs: map[string]*offlineBundleJob{}, ttl: ttl, maxBytes: maxMB << 20}
+}
+
+// start 登记一个生成中的任务，用户同时生成的数量已达上限时返回 false
+func (s *offlineBundleStore) start(job *offlineBundleJob) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.expireLocked()
+	running := 0
+	for _, j := range s.jobs {
+		if j.userID == job.userID && j.status == arkkafka.TaskStatusRunning {
+			running++
+		}
+	}
+	if running >= maxRunningOfflineBundles {
+		return false
+	}
+	s.jobs[job.id] = job
+	return true
+}
+
+// finish 记录生成结果，超过单个用户的数量上限或合计大小上限时删除最早完成的离线包。
+// 文件本身超过合计上限时不保存，任务记为失败并返回对应错误
+func (s *offlineBundleStore) finish(id string, data []byte, manifest map[string]string, warnings []string, err error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	job, ok := s.jobs[id]
+	if !ok {
+		return err
+	}
+	if err == nil && int64(len(data)) > s.maxBytes {
+		err = fmt.Errorf("offline bundle is too large: %d bytes exceeds the %d bytes limit", len(data), s.maxBytes)
+		data, manifest = nil, nil
+	}
+	job.finishedAt = time.Now()
+	job.status = arkkafka.TaskStatusSucceeded
+	job.data, job.manifest, job.warnings = data, manifest, warnings
+	if err != nil {
+		job.status = arkkafka.TaskStatusFailed
+		job.err = err.Error()
+	}
+	s.bytes += int64(len(job.data))
+	s.evictLocked(job.userID)
+	return err
+}
+
+// evictLocked 删除 userID 超出保留数量的已完成任务，以及超出合计大小上限时全部用户中最早完成的任务，调用方需持有锁
+func (s *offlineBundleStore) evictLocked(userID string) {
+	var finished, own []*offlineBundleJob
+	for _, j := range s.jobs {
+		if j.status == arkkafka.TaskStatusRunning {
+			continue
+		}
+		finished = append(finished, j)
+		if j.userID == userID {
+			own = append(own, j)
+		}
+	}
+	oldestFirst := func(jobs []*offlineBundleJob) {
+		sort.Slice(jobs, func(a, b int) bool { return jobs[a].finishedAt.Before(jobs[b].finishedAt) })
+	}
+	oldestFirst(own)
+	for i := 0; i < len(own)-maxFinishedOfflineBundles; i++ {
+		s.removeLocked(own[i].id)
+	}
+	oldestFirst(finished)
+	for _, j := range finished {
+		if s.bytes <= s.maxBytes {
+			break
+		}
+		s.removeLocked(j.id)
+	}
+}
+
+// removeLocked 删除任务并扣除其文件大小，调用方需持有锁
+func (s *offlineBundleStore) removeLocked(id string) {
+	if job, ok := s.jobs[id]; ok {
+		s.bytes -= int64(len(job.data))
+		delete(s.jobs, id)
+	}
+}
+
+// get 返回属于 userID 的任务副本，不属于该用户时与不存在一样返回 false
+func (s *offlineBundleStore) get(id, userID string) (offlineBundleJob, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.expireLocked()
+	job, ok := s.jobs[id]
+	if !ok || job.userID != userID {
+		return offlineBundleJob{}, false
+	}
+	return *job, true
+}
+
+// expireLocked 删除完成超过 ttl 的任务，调用方需持有锁
+func (s *offlineBundleStore) expireLocked() {
+	cutoff := time.Now().Add(-s.ttl)
+	for id, job := range s.jobs {
+		if job.status != arkkafka.TaskStatusRunning && job.finishedAt.Before(cutoff) {
+			s.removeLocked(id)
+		}
+	}
+}
+
+// POST /api/materials/:id/offline-bundle
+// 异步生成课程合集（bundle 资料，或单份资料）的离线学习包：资料列表、摘要、记忆卡片与题目的 JSON 压缩包，
+// 供移动端离线学习。立即返回 202 与任务状态地址，生成进度同时登记到任务中心；摘要只使用已有结果，不计入 AI 配额
+func (h *ExportHandler) CreateOfflineBundle(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	materialID := c.Param("id")
+
+	task := h.tasks.Start(c.Request.Context(), userID, arkkafka.TaskKindExport, "生成离线学习包", materialID)
+	job := &offlineBundleJob{
+		id:         task.ID(),
+		userID:     userID,
+		materialID: materialID,
+		status:     arkkafka.TaskStatusRunning,
+		createdAt:  time.Now(),
+	}
+	if !h.bundles.start(job) {
+		task.Finish(errors.New("too many offline bundles in progress"), nil)
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": fmt.Sprintf("at most %d offline bundles can be generated at the same time", maxRunningOfflineBundles)})
+		return
+	}
+
+	// 生成在请求结束后继续，使用不随客户端断开而取消的 ctx；离线包中的时间使用请求的时区
+	ctx := locale.NewContext(middleware.RPCContext(c), locale.FromContext(c.Request.Context()))
+	ctx, cancel := context.WithTimeout(ctx, offlineBundleTimeout)
+	go func() {
+		defer cancel()
+		data, manifest, warnings, err := h.buildOfflineBundle(ctx, userID, materialID)
+		if err == nil {
+			manifest["download_url"] = offlineBundlePath + job.id + "/download"
+		}
+		if err = h.bundles.finish(job.id, data, manifest, warnings, err); err != nil {
+			log.Printf("CreateOfflineBundle %s failed: %v", job.id, err)
+			manifest = nil
+		}
+		task.Finish(err, manifest)
+	}()
+
+	statusURL := offlineBundlePath + job.id
+	c.Header("Location", statusURL)
+	c.JSON(http.StatusAccepted, gin.H{"success": true, "data": gin.H{
+		"task_id":    job.id,
+		"status":     job.status,
+		"status_url": statusURL,
+	}})
+}
+
+// buildOfflineBundle 收集并打包离线包，返回文件与任务元数据（资料、卡片与题目数）
+func (h *ExportHandler) buildOfflineBundle(ctx context.Context, userID, materialID string) ([]byte, map[string]string, []string, error) {
+	bundle, err := h.collector.OfflineBundle(ctx, userID, materialID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	data, err := export.RenderOfflineBundle(bundle)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("render offline bundle: %w", err)
+	}
+	flashcards, questions := 0, 0
+	for _, m := range bundle.Materials {
+		flashcards += len(m.Flashcards)
+		questions += len(m.Questions)
+	}
+	manifest := map[string]string{
+		"format":          "zip",
+		"material_count":  strconv.Itoa(len(bundle.Materials)),
+		"flashcard_count": strconv.Itoa(flashcards),
+		"question_count":  strconv.Itoa(questions),
+		"size_bytes":      strconv.Itoa(len(data)),
+	}
+	return data, manifest, bundle.Warnings, nil
+}
+
+// GET /api/exports/offline-bundles/:task_id
+// 查询离线包的生成状态，成功后返回 download_url 与过期时间
+func (h *ExportHandler) GetOfflineBundle(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	job, ok := h.bundles.get(c.Param("task_id"), userID)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "offline bundle not found or expired"})
+		return
+	}
+
+	loc := locale.FromContext(c.Request.Context())
+	data := gin.H{
+		"task_id":     job.id,
+		"material_id": job.materialID,
+		"status":      job.status,
+		"created_at":  loc.FormatTime(job.createdAt),
+	}
+	if job.status != arkkafka.TaskStatusRunning {
+		data["finished_at"] = loc.FormatTime(job.finishedAt)
+		data["expires_at"] = loc.FormatTime(job.finishedAt.Add(h.bundles.ttl))
+	}
+	// 成功时附带 download_url 与资料、卡片、题目数
+	for k, v := range job.manifest {
+		data[k] = v
+	}
+	if len(job.warnings) > 0 {
+		data["warnings"] = job.warnings
+	}
+	if job.err != "" {
+		data["error"] = job.err
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": data})
+}
+
+// GET /api/exports/offline-bundles/:task_id/download
+// 下载已生成的离线包（zip），生成中返回 409
+func (h *ExportHandler) DownloadOfflineBundle(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	job, ok := h.bundles.get(c.Param("task_id"), userID)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "offline bundle not found or expired"})
+		return
+	}
+	switch job.status {
+	case arkkafka.TaskStatusRunning:
+		c.JSON(http.StatusConflict, gin.H{"error": "offline bundle is still being generated"})
+		return
+	case arkkafka.TaskStatusFailed:
+		c.JSON(http.StatusConflict, gin.H{"error": "offline bundle generation failed", "detail": job.err})
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="offline-%s.zip"`, safeFilename(job.materialID)))
+	c.Data(http.StatusOK, "application/zip", job.data)
+}
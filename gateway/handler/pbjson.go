@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"reflect"
+	"time"
+
+	"github.com/RigelNana/arkstudy/pkg/locale"
+	"github.com/gin-gonic/gin"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// protoJSON 将 proto 消息（或消息切片）转换为可直接 c.JSON 的值：google.protobuf.Timestamp
+// 输出为请求时区的 RFC3339 字符串，其余字段与 encoding/json 序列化生成代码的结果一致
+// （字段名为 proto 字段名，零值省略，枚举为数字）。其他类型原样返回
+func protoJSON(c *gin.Context, v any) any {
+	loc := locale.FromContext(c.Request.Context()).Location()
+	if m, ok := v.(proto.Message); ok {
+		if reflect.ValueOf(m).IsNil() {
+			return nil
+		}
+		return messageJSON(m.ProtoReflect(), loc)
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice || !rv.Type().Elem().Implements(reflect.TypeOf((*proto.Message)(nil)).Elem()) {
+		return v
+	}
+	out := make([]any, rv.Len())
+	for i := range out {
+		out[i] = protoJSON(c, rv.Index(i).Interface())
+	}
+	return out
+}
+
+// formatTimestamp 将 Timestamp 格式化为请求时区的 RFC3339 字符串，未设置时为空串
+func formatTimestamp(c *gin.Context, ts *timestamppb.Timestamp) string {
+	if ts == nil {
+		return ""
+	}
+	return locale.FromContext(c.Request.Context()).FormatTime(ts.AsTime())
+}
+
+func messageJSON(m protoreflect.Message, loc *time.Location) any {
+	if ts, ok := m.Interface().(*timestamppb.Timestamp); ok {
+		return ts.AsTime().In(loc).Format(time.RFC3339)
+	}
+	out := map[string]any{}
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		switch {
+		case fd.IsList():
+			list := v.List()
+			items := make([]any, list.Len())
+			for i := range items {
+				items[i] = singularJSON(fd, list.Get(i), loc)
+			}
+			out[string(fd.Name())] = items
+		case fd.IsMap():
+			entries := map[string]any{}
+			v.Map().Range(func(k protoreflect.MapKey, mv protoreflect.Value) bool {
+				entries[k.String()] = singularJSON(fd.MapValue(), mv, loc)
+				return true
+			})
+			out[string(fd.Name())] = entries
+		default:
+			out[string(fd.Name())] = singularJSON(fd, v, loc)
+		}
+		return true
+	})
+	return out
+}
+
+func singularJSON(fd protoreflect.FieldDescriptor, v protoreflect.Value, loc *time.Location) any {
+	switch fd.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return messageJSON(v.Message(), loc)
+	case protoreflect.EnumKind:
+		return int32(v.Enum())
+	default:
+		return v.Interface()
+	}
+}
@@ -0,0 +1,260 @@
+package handler
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	arkkafka "github.com/RigelNana/arkstudy/pkg/kafka"
+	kafkaMetrics "github.com/RigelNana/arkstudy/pkg/metrics/kafka"
+	authpb "github.com/RigelNana/arkstudy/proto/auth"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	kafka "github.com/segmentio/kafka-go"
+)
+
+// 批量导入的 CSV 大小上限
+const maxImportCSVBytes = 1 << 20
+
+// provisionTimeout 每个账号需要一次 bcrypt 与数次 user-service 调用，500 个账号约需半分钟
+const provisionTimeout = 2 * time.Minute
+
+// InvitationSender 经 notification.requests 发送开通邀请邮件。未配置 KAFKA_BROKERS 时不可用，
+// 调用方应改为在响应中返回临时密码
+type InvitationSender struct {
+	writer   *kafka.Writer
+	loginURL string
+}
+
+// NewInvitationSender 使用 env KAFKA_BROKERS、KAFKA_TOPIC_NOTIFICATIONS（默认 notification.requests）创建发送器，
+// INVITATION_LOGIN_URL 为邮件中的登录地址
+func NewInvitationSender() *InvitationSender {
+	s := &InvitationSender{loginURL: os.Getenv("INVITATION_LOGIN_URL")}
+	brokers := arkkafka.SplitBrokers(os.Getenv("KAFKA_BROKERS"))
+	if len(brokers) == 0 {
+		log.Printf("KAFKA_BROKERS not set, invitation emails disabled")
+		return s
+	}
+	topic := os.Getenv("KAFKA_TOPIC_NOTIFICATIONS")
+	if topic == "" {
+		topic = arkkafka.Notifications.Name
+	}
+	s.writer = kafkaMetrics.InstrumentWriter("gateway", arkkafka.NewWriter(brokers, topic))
+	return s
+}
+
+// Enabled 是否可以发送邀请
+func (s *InvitationSender) Enabled() bool { return s != nil && s.writer != nil }
+
+// Send 同步发送一批邀请，返回错误时调用方无法确认哪些已送达，应视为全部未发送
+func (s *InvitationSender) Send(ctx context.Context, created []*authpb.ProvisionResult) error {
+	if !s.Enabled() {
+		return errors.New("invitation emails disabled")
+	}
+	msgs := make([]kafka.Message, 0, len(created))
+	for _, r := range created {
+		subject, body, data := s.render(r)
+		msg, err := arkkafka.NotificationMessage(arkkafka.NotificationEvent{
+			// 同一账号的邀请只发送一次
+			NotificationID: uuid.NewSHA1(uuid.NameSpaceOID, []byte("user_invitation:"+r.UserId)).String(),
+			UserID:         r.UserId,
+			Channel:        arkkafka.NotificationChannelEmail,
+			To:             r.Email,
+			Template:       arkkafka.NotificationTemplateInvitation,
+			Subject:        subject,
+			Body:           body,
+			Data:           data,
+		})
+		if err != nil {
+			return err
+		}
+		msgs = append(msgs, msg)
+	}
+	wctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	return kafkaMetrics.WriteMessages(wctx, s.writer, msgs...)
+}
+
+// render 生成邀请的纯文本邮件与模板变量
+func (s *InvitationSender) render(r *authpb.ProvisionResult) (string, string, map[string]string) {
+	data := map[string]string{
+		"username":           r.Username,
+		"temporary_password": r.TemporaryPassword,
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s，你好！\n\n管理员已为你开通 ArkStudy 账号：\n\n", r.Username)
+	fmt.Fprintf(&b, "用户名：%s\n临时密码：%s\n\n", r.Username, r.TemporaryPassword)
+	if s.loginURL != "" {
+		data["login_url"] = s.loginURL
+		fmt.Fprintf(&b, "登录地址：%s\n\n", s.loginURL)
+	}
+	b.WriteString("首次登录后请立即修改密码。如非本人操作，请忽略本邮件。\n")
+	return "你的 ArkStudy 账号已开通", b.String(), data
+}
+
+// ImportUsers 管理员从 CSV 批量开通账号，仅管理员可用
+// POST /api/admin/users/import?dry_run=false&send_invitations=true
+// 请求体为 text/csv，或 multipart 表单的 file 字段。首行为表头，需包含 username、email，可选 role、description。
+// 开通成功的账号通过邮件发送临时密码；未发送邀请（send_invitations=false 或发送失败）时在响应中返回临时密码
+func (h *AuthHandler) ImportUsers(c *gin.Context) {
+	adminID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	dryRun := c.Query("dry_run") == "true"
+	sendInvitations := c.DefaultQuery("send_invitations", "true") != "false"
+	if sendInvitations && !dryRun && !h.invitations.Enabled() {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "invitation emails disabled", "detail": "set send_invitations=false to receive temporary passwords in the response"})
+		return
+	}
+
+	var body io.Reader = c.Request.Body
+	if strings.HasPrefix(c.ContentType(), "multipart/") {
+		fh, err := c.FormFile("file")
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "missing file", "detail": err.Error()})
+			return
+		}
+		f, err := fh.Open()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid file", "detail": err.Error()})
+			return
+		}
+		defer f.Close()
+		body = f
+	}
+	users, lines, err := parseUserCSV(io.LimitReader(body, maxImportCSVBytes+1))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid csv", "detail": err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), provisionTimeout)
+	defer cancel()
+	resp, err := h.authClient.ProvisionUsers(ctx, &authpb.ProvisionUsersRequest{AdminUserId: adminID, Users: users, DryRun: dryRun})
+	if err != nil {
+		log.Printf("ProvisionUsers gRPC error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error", "detail": err.Error()})
+		return
+	}
+	if !resp.Success {
+		c.JSON(adminErrorStatus(resp.Message), gin.H{"error": "import failed", "detail": resp.Message})
+		return
+	}
+
+	var created []*authpb.ProvisionResult
+	for _, r := range resp.Results {
+		if r.Status == "created" {
+			created = append(created, r)
+		}
+	}
+	invited := false
+	if sendInvitations && len(created) > 0 {
+		if err := h.invitations.Send(c.Request.Context(), created); err != nil {
+			// 账号已开通，改为返回临时密码由管理员分发
+			log.Printf("Warning: send %d invitations failed, returning temporary passwords to admin %s: %v", len(created), adminID, err)
+		} else {
+			invited = true
+		}
+	}
+
+	results := make([]gin.H, 0, len(resp.Results))
+	for _, r := range resp.Results {
+		item := gin.H{
+			"username": r.Username,
+			"email":    r.Email,
+			"status":   r.Status,
+		}
+		if i := int(r.Index); i >= 0 && i < len(lines) {
+			item["line"] = lines[i]
+		}
+		if r.Message != "" {
+			item["message"] = r.Message
+		}
+		if r.UserId != "" {
+			item["user_id"] = r.UserId
+		}
+		if r.TemporaryPassword != "" && !invited {
+			item["temporary_password"] = r.TemporaryPassword
+		}
+		results = append(results, item)
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": gin.H{
+		"dry_run":          dryRun,
+		"created":          resp.Created,
+		"failed":           resp.Failed,
+		"invitations_sent": invited,
+		"results":          results,
+	}})
+}
+
+// parseUserCSV 解析导入 CSV，返回账号与其所在行号。空行忽略，未知列忽略
+func parseUserCSV(r io.Reader) ([]*authpb.ProvisionUser, []int, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(data) > maxImportCSVBytes {
+		return nil, nil, fmt.Errorf("file larger than %d bytes", maxImportCSVBytes)
+	}
+	reader := csv.NewReader(strings.NewReader(strings.TrimPrefix(string(data), "\ufeff")))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, nil, errors.New("empty file")
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	cols := map[string]int{}
+	for i, name := range header {
+		cols[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range []string{"username", "email"} {
+		if _, ok := cols[required]; !ok {
+			return nil, nil, fmt.Errorf("missing %s column", required)
+		}
+	}
+	field := func(record []string, name string) string {
+		if i, ok := cols[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	var users []*authpb.ProvisionUser
+	var lines []int
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		if strings.TrimSpace(strings.Join(record, "")) == "" {
+			continue
+		}
+		line, _ := reader.FieldPos(0)
+		users = append(users, &authpb.ProvisionUser{
+			Username:    field(record, "username"),
+			Email:       field(record, "email"),
+			Role:        field(record, "role"),
+			Description: field(record, "description"),
+		})
+		lines = append(lines, line)
+	}
+	if len(users) == 0 {
+		return nil, nil, errors.New("no users in file")
+	}
+	return users, lines, nil
+}
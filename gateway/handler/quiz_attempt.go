@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/RigelNana/arkstudy/gateway/middleware"
+	pb "github.com/RigelNana/arkstudy/proto/quiz"
+)
+
+// 开始作答，选择题的选项按本次会话随机排列；提交答案时携带返回的 attempt_id
+// POST /api/quiz/:questionId/attempts
+func (h *QuizHandler) StartQuestionAttempt(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(middleware.RPCContext(c), 10*time.Second)
+	defer cancel()
+
+	resp, err := h.quizClient.StartQuestionAttempt(ctx, &pb.StartQuestionAttemptRequest{
+		QuestionId: c.Param("questionId"),
+		UserId:     userID,
+	})
+	if err != nil {
+		h.logger.Errorf("开始作答失败: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "开始作答失败"})
+		return
+	}
+	if !resp.Success {
+		status := http.StatusBadRequest
+		if resp.Message == "题目不存在" {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{"error": resp.Message})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":          true,
+		"attempt_id":       resp.AttemptId,
+		"question":         protoJSON(c, resp.Question),
+		"options_shuffled": resp.OptionsShuffled,
+	})
+}
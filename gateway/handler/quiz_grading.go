@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/RigelNana/arkstudy/gateway/middleware"
+	pb "github.com/RigelNana/arkstudy/proto/quiz"
+)
+
+// 单条作答的评分：accept 为 true 时采纳 AI 建议得分，否则 score（0-1）必填
+type AnswerGradeRequest struct {
+	AnswerID string   `json:"answer_id" binding:"required"`
+	Accept   bool     `json:"accept"`
+	Score    *float32 `json:"score"`
+	Comment  string   `json:"comment"`
+}
+
+// 批量评分请求结构
+type GradeAnswersRequest struct {
+	Grades []AnswerGradeRequest `json:"grades" binding:"required,min=1,dive"`
+}
+
+// 获取本人的评分队列：本人创建或审核的题目下待复核的简答题与论述题作答
+// GET /api/quiz/grading/queue
+func (h *QuizHandler) ListGradingQueue(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+
+	ctx, cancel := context.WithTimeout(middleware.RPCContext(c), 10*time.Second)
+	defer cancel()
+
+	resp, err := h.quizClient.ListGradingQueue(ctx, &pb.ListGradingQueueRequest{
+		UserId:      userID,
+		MaterialId:  c.Query("material_id"),
+		CourseId:    c.Query("course_id"),
+		QuestionId:  c.Query("question_id"),
+		FlaggedOnly: c.Query("flagged") == "true",
+		Page:        int32(page),
+		PageSize:    int32(pageSize),
+	})
+	if err != nil {
+		h.logger.Errorf("获取评分队列失败: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "获取评分队列失败"})
+		return
+	}
+	if !resp.Success {
+		c.JSON(http.StatusBadRequest, gin.H{"error": resp.Message})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"items":   protoJSON(c, resp.Items),
+		"total":   resp.Total,
+	})
+}
+
+// 批量确认或修改主观题得分
+// POST /api/quiz/grading/grade
+func (h *QuizHandler) GradeAnswers(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	var req GradeAnswersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	grades := make([]*pb.AnswerGrade, 0, len(req.Grades))
+	for _, g := range req.Grades {
+		grade := &pb.AnswerGrade{AnswerId: g.AnswerID, Accept: g.Accept, Comment: g.Comment}
+		if !g.Accept {
+			if g.Score == nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("作答 %s 未采纳建议得分时需要填写 score", g.AnswerID)})
+				return
+			}
+			grade.Score = *g.Score
+		}
+		grades = append(grades, grade)
+	}
+
+	ctx, cancel := context.WithTimeout(middleware.RPCContext(c), 30*time.Second)
+	defer cancel()
+
+	resp, err := h.quizClient.GradeAnswers(ctx, &pb.GradeAnswersRequest{
+		UserId: userID,
+		Grades: grades,
+	})
+	if err != nil {
+		h.logger.Errorf("批量评分失败: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "批量评分失败"})
+		return
+	}
+	if !resp.Success {
+		c.JSON(http.StatusBadRequest, gin.H{"error": resp.Message})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": resp.Message,
+		"answers": protoJSON(c, resp.Answers),
+		"skipped": protoJSON(c, resp.Skipped),
+	})
+}
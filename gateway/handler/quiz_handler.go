@@ -2,47 +2,107 @@ package handler
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
-	"google.golang.org/grpc"
 
+	"github.com/RigelNana/arkstudy/gateway/backend"
+	"github.com/RigelNana/arkstudy/gateway/middleware"
+	"github.com/RigelNana/arkstudy/pkg/discovery"
+	arkkafka "github.com/RigelNana/arkstudy/pkg/kafka"
+	"github.com/RigelNana/arkstudy/pkg/registry"
 	pb "github.com/RigelNana/arkstudy/proto/quiz"
 )
 
 type QuizHandler struct {
 	quizClient pb.QuizServiceClient
 	logger     *logrus.Logger
+	activity   *ActivityRecorder
+	tasks      *TaskRecorder
 }
 
-func NewQuizHandler(quizServiceAddr string, logger *logrus.Logger) *QuizHandler {
-	conn, err := grpc.Dial(quizServiceAddr, grpc.WithInsecure())
-	if err != nil {
-		logger.Fatalf("连接quiz服务失败: %v", err)
-	}
-
-	client := pb.NewQuizServiceClient(conn)
+func NewQuizHandler(logger *logrus.Logger, activity *ActivityRecorder, tasks *TaskRecorder) *QuizHandler {
+	logger.Infof("Quiz service target: %s", discovery.Target(registry.Quiz))
+	client := pb.NewQuizServiceClient(backend.For(registry.Quiz))
 	return &QuizHandler{
 		quizClient: client,
 		logger:     logger,
+		activity:   activity,
+		tasks:      tasks,
 	}
 }
 
 // 生成题目请求结构
 type GenerateQuizRequest struct {
-	MaterialID      string   `json:"material_id" binding:"required"`
+	MaterialID      string   `json:"material_id"`
 	QuestionTypes   []int32  `json:"question_types"`
 	Difficulty      int32    `json:"difficulty"`
 	Count           int32    `json:"count"`
 	KnowledgePoints []string `json:"knowledge_points"`
+	// 基于视频转写出题，start_time / end_time 为秒，指定时间范围时隐含 from_transcript
+	FromTranscript bool    `json:"from_transcript"`
+	StartTime      float32 `json:"start_time"`
+	EndTime        float32 `json:"end_time"`
+	// 题目语言（如 zh、en），为空时与材料语言一致
+	Language string `json:"language"`
+	// 在课程中出题时题目需经审核，指定 reviewer_id 时直接提交审核
+	CourseID   string `json:"course_id"`
+	ReviewerID string `json:"reviewer_id"`
+	// 基于多份材料出题（单元测验），material_id、material_ids 与 collection_id 至少提供一个
+	MaterialIDs  []string `json:"material_ids"`
+	CollectionID string   `json:"collection_id"`
+	// 组卷蓝图，指定时按槽位出题并忽略 question_types、difficulty 与 count
+	Blueprint []BlueprintSlotRequest `json:"blueprint"`
 }
 
-// 提交答案请求结构
+// 蓝图槽位：count 道 knowledge_point 知识点、difficulty 难度的 type 题型题目，knowledge_point 为空时不限
+type BlueprintSlotRequest struct {
+	Type           int32  `json:"type"`
+	KnowledgePoint string `json:"knowledge_point"`
+	Difficulty     int32  `json:"difficulty"`
+	Count          int32  `json:"count"`
+}
+
+// 选段出题的题目数量上限
+const maxSelectionQuestions = 10
+
+// 选段出题请求结构，text 与时间段二选一；page 为选中文本所在页码（可选）
+type QuizFromSelectionRequest struct {
+	MaterialID    string  `json:"material_id" binding:"required"`
+	Text          string  `json:"text"`
+	Page          int32   `json:"page"`
+	StartTime     float32 `json:"start_time"`
+	EndTime       float32 `json:"end_time"`
+	QuestionTypes []int32 `json:"question_types"`
+	Difficulty    int32   `json:"difficulty"`
+	Count         int32   `json:"count"`
+	Language      string  `json:"language"`
+	CourseID      string  `json:"course_id"`
+	ReviewerID    string  `json:"reviewer_id"`
+}
+
+// 提交答案请求结构，多空题可通过 part_answers 按空位顺序提交
 type SubmitAnswerRequest struct {
-	Answer string `json:"answer" binding:"required"`
+	Answer      string   `json:"answer"`
+	PartAnswers []string `json:"part_answers"`
+	TimeSpentMs int64    `json:"time_spent_ms" binding:"min=0"`
+	AttemptID   string   `json:"attempt_id"` // 开始作答时返回的会话ID，答案按该会话的选项顺序解读
+}
+
+// 评分策略请求结构，scope_type 为 global、course 或 material
+type GradingPolicyRequest struct {
+	ScopeType         string   `json:"scope_type" binding:"required"`
+	ScopeID           string   `json:"scope_id"`
+	PassThreshold     *float32 `json:"pass_threshold" binding:"required"` // 必填，未传时不能按 0 保存
+	PartialCreditMode string   `json:"partial_credit_mode"`
+	RetryPenalty      float32  `json:"retry_penalty"`
+	MaxRetryPenalty   float32  `json:"max_retry_penalty"`
 }
 
 // 生成题目
@@ -52,6 +112,10 @@ func (h *QuizHandler) GenerateQuiz(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	if req.MaterialID == "" && len(req.MaterialIDs) == 0 && req.CollectionID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "需要提供 material_id、material_ids 或 collection_id"})
+		return
+	}
 
 	// 从JWT token中获取用户ID
 	userID, exists := c.Get("user_id")
@@ -68,7 +132,16 @@ func (h *QuizHandler) GenerateQuiz(c *gin.Context) {
 		req.QuestionTypes = []int32{0, 1, 2} // 选择题、填空题、简答题
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	// 多材料出题需逐份检索材料，允许更长的时间
+	timeout := 30 * time.Second
+	if len(req.MaterialIDs) > 0 || req.CollectionID != "" {
+		timeout = time.Minute
+	}
+	// 蓝图逐个槽位生成并补齐不合格的题目
+	if len(req.Blueprint) > 0 {
+		timeout = 2 * time.Minute
+	}
+	ctx, cancel := context.WithTimeout(middleware.RPCContext(c), timeout)
 	defer cancel()
 
 	// 转换题目类型
@@ -76,6 +149,15 @@ func (h *QuizHandler) GenerateQuiz(c *gin.Context) {
 	for i, t := range req.QuestionTypes {
 		types[i] = pb.QuestionType(t)
 	}
+	blueprint := make([]*pb.BlueprintSlot, len(req.Blueprint))
+	for i, s := range req.Blueprint {
+		blueprint[i] = &pb.BlueprintSlot{
+			Type:           pb.QuestionType(s.Type),
+			KnowledgePoint: s.KnowledgePoint,
+			Difficulty:     pb.DifficultyLevel(s.Difficulty),
+			Count:          s.Count,
+		}
+	}
 
 	grpcReq := &pb.GenerateQuizRequest{
 		MaterialId:      req.MaterialID,
@@ -84,25 +166,117 @@ func (h *QuizHandler) GenerateQuiz(c *gin.Context) {
 		Difficulty:      pb.DifficultyLevel(req.Difficulty),
 		Count:           req.Count,
 		KnowledgePoints: req.KnowledgePoints,
+		FromTranscript:  req.FromTranscript,
+		StartTime:       req.StartTime,
+		EndTime:         req.EndTime,
+		Language:        req.Language,
+		CourseId:        req.CourseID,
+		ReviewerId:      req.ReviewerID,
+		MaterialIds:     req.MaterialIDs,
+		CollectionId:    req.CollectionID,
+		Blueprint:       blueprint,
+	}
+
+	h.generate(ctx, c, grpcReq, "生成题目")
+}
+
+// GenerateQuizFromSelection 针对选中的段落（text）或音视频时间段（start_time / end_time）出题，
+// 不检索整份资料，用于“就这段内容考考我”
+func (h *QuizHandler) GenerateQuizFromSelection(c *gin.Context) {
+	var req QuizFromSelectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
+	req.Text = strings.TrimSpace(req.Text)
+	hasRange := req.StartTime > 0 || req.EndTime > 0
+	if (req.Text == "") == !hasRange {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "需要提供选中的文本或时间段（二选一）"})
+		return
+	}
+	if req.Count < 0 || req.Count > maxSelectionQuestions {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("题目数量最多为 %d", maxSelectionQuestions)})
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "用户未认证"})
+		return
+	}
+
+	// 选段内容较少，默认题量低于整份资料出题
+	if req.Count == 0 {
+		req.Count = 3
+	}
+	if len(req.QuestionTypes) == 0 {
+		req.QuestionTypes = []int32{0, 1, 2}
+	}
+	types := make([]pb.QuestionType, len(req.QuestionTypes))
+	for i, t := range req.QuestionTypes {
+		types[i] = pb.QuestionType(t)
+	}
+
+	ctx, cancel := context.WithTimeout(middleware.RPCContext(c), 30*time.Second)
+	defer cancel()
 
+	h.generate(ctx, c, &pb.GenerateQuizRequest{
+		MaterialId:     req.MaterialID,
+		UserId:         userID.(string),
+		Types:          types,
+		Difficulty:     pb.DifficultyLevel(req.Difficulty),
+		Count:          req.Count,
+		SelectionText:  req.Text,
+		SelectionPage:  req.Page,
+		FromTranscript: hasRange,
+		StartTime:      req.StartTime,
+		EndTime:        req.EndTime,
+		Language:       req.Language,
+		CourseId:       req.CourseID,
+		ReviewerId:     req.ReviewerID,
+	}, "选段出题")
+}
+
+// generate 调用 quiz-service 出题并写回响应，出题耗时较长，登记到任务中心
+func (h *QuizHandler) generate(ctx context.Context, c *gin.Context, grpcReq *pb.GenerateQuizRequest, title string) {
+	// 多材料出题未指定 material_id 时，任务关联课程合集或第一份材料
+	targetID := grpcReq.MaterialId
+	if targetID == "" {
+		targetID = grpcReq.CollectionId
+	}
+	if targetID == "" && len(grpcReq.MaterialIds) > 0 {
+		targetID = grpcReq.MaterialIds[0]
+	}
+	task := h.tasks.Start(ctx, grpcReq.UserId, arkkafka.TaskKindQuizGeneration, title, targetID)
 	resp, err := h.quizClient.GenerateQuiz(ctx, grpcReq)
 	if err != nil {
+		task.Finish(err, nil)
 		h.logger.Errorf("生成题目失败: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "生成题目失败"})
 		return
 	}
 
 	if !resp.Success {
-		c.JSON(http.StatusBadRequest, gin.H{"error": resp.Message})
+		task.Finish(errors.New(resp.Message), nil)
+		body := gin.H{"error": resp.Message}
+		if resp.BlueprintReport != nil {
+			body["blueprint_report"] = protoJSON(c, resp.BlueprintReport)
+		}
+		c.JSON(http.StatusBadRequest, body)
 		return
 	}
+	task.Finish(nil, map[string]string{"question_count": strconv.Itoa(len(resp.Questions))})
 
-	c.JSON(http.StatusOK, gin.H{
+	body := gin.H{
 		"success":   resp.Success,
 		"message":   resp.Message,
-		"questions": resp.Questions,
-	})
+		"questions": protoJSON(c, resp.Questions),
+	}
+	// 按蓝图出题时附带各槽位的完成情况
+	if resp.BlueprintReport != nil {
+		body["blueprint_report"] = protoJSON(c, resp.BlueprintReport)
+	}
+	c.JSON(http.StatusOK, body)
 }
 
 // 获取题目详情
@@ -113,11 +287,18 @@ func (h *QuizHandler) GetQuiz(c *gin.Context) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "用户未认证"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(middleware.RPCContext(c), 10*time.Second)
 	defer cancel()
 
 	resp, err := h.quizClient.GetQuiz(ctx, &pb.GetQuizRequest{
 		QuestionId: questionID,
+		UserId:     userID.(string),
 	})
 	if err != nil {
 		h.logger.Errorf("获取题目失败: %v", err)
@@ -132,7 +313,7 @@ func (h *QuizHandler) GetQuiz(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{
 		"success":  resp.Success,
-		"question": resp.Question,
+		"question": protoJSON(c, resp.Question),
 	})
 }
 
@@ -145,6 +326,7 @@ func (h *QuizHandler) ListQuizzes(c *gin.Context) {
 	}
 
 	materialID := c.Query("material_id")
+	courseID := c.Query("course_id")
 	questionType := c.Query("type")
 	difficulty := c.Query("difficulty")
 	page := c.DefaultQuery("page", "1")
@@ -153,7 +335,7 @@ func (h *QuizHandler) ListQuizzes(c *gin.Context) {
 	pageInt, _ := strconv.Atoi(page)
 	pageSizeInt, _ := strconv.Atoi(pageSize)
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(middleware.RPCContext(c), 10*time.Second)
 	defer cancel()
 
 	req := &pb.ListQuizzesRequest{
@@ -165,6 +347,7 @@ func (h *QuizHandler) ListQuizzes(c *gin.Context) {
 	if materialID != "" {
 		req.MaterialId = materialID
 	}
+	req.CourseId = courseID
 	if questionType != "" {
 		if typeInt, err := strconv.Atoi(questionType); err == nil {
 			req.Type = pb.QuestionType(typeInt)
@@ -185,7 +368,7 @@ func (h *QuizHandler) ListQuizzes(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{
 		"success":   resp.Success,
-		"questions": resp.Questions,
+		"questions": protoJSON(c, resp.Questions),
 		"total":     resp.Total,
 		"page":      resp.Page,
 		"page_size": resp.PageSize,
@@ -211,20 +394,34 @@ func (h *QuizHandler) SubmitAnswer(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	if req.Answer == "" && len(req.PartAnswers) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "答案不能为空"})
+		return
+	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(middleware.RPCContext(c), 10*time.Second)
 	defer cancel()
 
 	resp, err := h.quizClient.SubmitAnswer(ctx, &pb.SubmitAnswerRequest{
-		QuestionId: questionID,
-		UserId:     userID.(string),
-		Answer:     req.Answer,
+		QuestionId:  questionID,
+		UserId:      userID.(string),
+		Answer:      req.Answer,
+		PartAnswers: req.PartAnswers,
+		TimeSpentMs: req.TimeSpentMs,
+		AttemptId:   req.AttemptID,
 	})
 	if err != nil {
 		h.logger.Errorf("提交答案失败: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "提交答案失败"})
 		return
 	}
+	if resp.Success {
+		h.activity.Record(userID.(string), ActivityQuizAttempt, questionID, "", map[string]string{
+			"is_correct":     strconv.FormatBool(resp.IsCorrect),
+			"score":          strconv.FormatFloat(float64(resp.Score), 'f', -1, 32),
+			"attempt_number": strconv.Itoa(int(resp.AttemptNumber)),
+		})
+	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"success":        resp.Success,
@@ -233,6 +430,14 @@ func (h *QuizHandler) SubmitAnswer(c *gin.Context) {
 		"score":          resp.Score,
 		"correct_answer": resp.CorrectAnswer,
 		"explanation":    resp.Explanation,
+		"blank_match":    resp.BlankMatch,
+		"part_results":   resp.PartResults,
+		"raw_score":      resp.RawScore,
+		"pass_threshold": resp.PassThreshold,
+		"attempt_number": resp.AttemptNumber,
+		"attempt_id":     resp.AttemptId,
+		"similarity":     resp.Similarity,
+		"grading_status": resp.GradingStatus,
 	})
 }
 
@@ -258,7 +463,7 @@ func (h *QuizHandler) GetUserHistory(c *gin.Context) {
 	pageInt, _ := strconv.Atoi(page)
 	pageSizeInt, _ := strconv.Atoi(pageSize)
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(middleware.RPCContext(c), 10*time.Second)
 	defer cancel()
 
 	resp, err := h.quizClient.GetUserQuizHistory(ctx, &pb.GetUserQuizHistoryRequest{
@@ -274,7 +479,7 @@ func (h *QuizHandler) GetUserHistory(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{
 		"success": resp.Success,
-		"answers": resp.Answers,
+		"answers": protoJSON(c, resp.Answers),
 		"total":   resp.Total,
 	})
 }
@@ -297,7 +502,7 @@ func (h *QuizHandler) GetKnowledgeStats(c *gin.Context) {
 
 	materialID := c.Query("material_id")
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(middleware.RPCContext(c), 10*time.Second)
 	defer cancel()
 
 	resp, err := h.quizClient.GetKnowledgeStats(ctx, &pb.GetKnowledgeStatsRequest{
@@ -316,3 +521,186 @@ func (h *QuizHandler) GetKnowledgeStats(c *gin.Context) {
 		"overall_accuracy": resp.OverallAccuracy,
 	})
 }
+
+// 获取题库作答分析
+func (h *QuizHandler) GetQuestionAnalytics(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "用户未认证"})
+		return
+	}
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "10"))
+	minAttempts, _ := strconv.Atoi(c.DefaultQuery("min_attempts", "0"))
+
+	ctx, cancel := context.WithTimeout(middleware.RPCContext(c), 10*time.Second)
+	defer cancel()
+
+	resp, err := h.quizClient.GetQuestionAnalytics(ctx, &pb.GetQuestionAnalyticsRequest{
+		QuestionId:  c.Query("question_id"),
+		MaterialId:  c.Query("material_id"),
+		MinAttempts: int32(minAttempts),
+		Page:        int32(page),
+		PageSize:    int32(pageSize),
+		UserId:      userID.(string),
+	})
+	if err != nil {
+		if code := grpcHTTPStatus(err); code == http.StatusForbidden {
+			c.JSON(code, gin.H{"error": grpcErrorMessage(err)})
+			return
+		}
+		h.logger.Errorf("获取题库分析失败: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "获取题库分析失败"})
+		return
+	}
+
+	if !resp.Success {
+		c.JSON(http.StatusNotFound, gin.H{"error": resp.Message})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":   resp.Success,
+		"analytics": protoJSON(c, resp.Analytics),
+		"total":     resp.Total,
+	})
+}
+
+// 获取错题本
+func (h *QuizHandler) ListMistakes(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "用户未认证"})
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+	includeMastered, _ := strconv.ParseBool(c.DefaultQuery("include_mastered", "false"))
+
+	ctx, cancel := context.WithTimeout(middleware.RPCContext(c), 10*time.Second)
+	defer cancel()
+
+	resp, err := h.quizClient.ListMistakes(ctx, &pb.ListMistakesRequest{
+		UserId:          userID.(string),
+		MaterialId:      c.Query("material_id"),
+		IncludeMastered: includeMastered,
+		Page:            int32(page),
+		PageSize:        int32(pageSize),
+	})
+	if err != nil {
+		h.logger.Errorf("获取错题本失败: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "获取错题本失败"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": resp.Success,
+		"groups":  protoJSON(c, resp.Groups),
+		"total":   resp.Total,
+	})
+}
+
+// 获取待重练的错题
+func (h *QuizHandler) GetRetryQuestions(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "用户未认证"})
+		return
+	}
+
+	count, _ := strconv.Atoi(c.DefaultQuery("count", "10"))
+
+	ctx, cancel := context.WithTimeout(middleware.RPCContext(c), 10*time.Second)
+	defer cancel()
+
+	resp, err := h.quizClient.GetRetryQuestions(ctx, &pb.GetRetryQuestionsRequest{
+		UserId:         userID.(string),
+		MaterialId:     c.Query("material_id"),
+		KnowledgePoint: c.Query("knowledge_point"),
+		Count:          int32(count),
+	})
+	if err != nil {
+		h.logger.Errorf("获取重练题目失败: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "获取重练题目失败"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":   resp.Success,
+		"message":   resp.Message,
+		"items":     protoJSON(c, resp.Items),
+		"remaining": resp.Remaining,
+	})
+}
+
+// 获取生效的评分策略
+func (h *QuizHandler) GetGradingPolicy(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(middleware.RPCContext(c), 10*time.Second)
+	defer cancel()
+
+	resp, err := h.quizClient.GetGradingPolicy(ctx, &pb.GetGradingPolicyRequest{
+		MaterialId: c.Query("material_id"),
+		CourseId:   c.Query("course_id"),
+	})
+	if err != nil {
+		h.logger.Errorf("获取评分策略失败: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "获取评分策略失败"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": resp.Success,
+		"policy":  resp.Policy,
+	})
+}
+
+// 设置评分策略
+func (h *QuizHandler) SetGradingPolicy(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "用户未认证"})
+		return
+	}
+
+	var req GradingPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(middleware.RPCContext(c), 10*time.Second)
+	defer cancel()
+
+	resp, err := h.quizClient.SetGradingPolicy(ctx, &pb.SetGradingPolicyRequest{
+		UserId: userID.(string),
+		Policy: &pb.GradingPolicy{
+			ScopeType:         req.ScopeType,
+			ScopeId:           req.ScopeID,
+			PassThreshold:     *req.PassThreshold,
+			PartialCreditMode: req.PartialCreditMode,
+			RetryPenalty:      req.RetryPenalty,
+			MaxRetryPenalty:   req.MaxRetryPenalty,
+		},
+	})
+	if err != nil {
+		if code := grpcHTTPStatus(err); code == http.StatusForbidden {
+			c.JSON(code, gin.H{"error": grpcErrorMessage(err)})
+			return
+		}
+		h.logger.Errorf("设置评分策略失败: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "设置评分策略失败"})
+		return
+	}
+
+	if !resp.Success {
+		c.JSON(http.StatusBadRequest, gin.H{"error": resp.Message})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": resp.Success,
+		"message": resp.Message,
+		"policy":  resp.Policy,
+	})
+}
@@ -0,0 +1,185 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/RigelNana/arkstudy/gateway/middleware"
+	pb "github.com/RigelNana/arkstudy/proto/quiz"
+)
+
+// 提交审核请求结构，也用于改派待审核题目的审核人
+type AssignReviewerRequest struct {
+	QuestionIDs []string `json:"question_ids" binding:"required,min=1"`
+	ReviewerID  string   `json:"reviewer_id" binding:"required"`
+}
+
+// 审核单题请求结构，decision 为 approve 或 reject，驳回时 comment 必填
+type ReviewQuestionRequest struct {
+	Decision string `json:"decision" binding:"required,oneof=approve reject"`
+	Comment  string `json:"comment"`
+}
+
+// 批量通过请求结构，question_ids 为空时通过 course_id 下分配给本人的全部待审核题目
+type BulkApproveRequest struct {
+	CourseID    string   `json:"course_id"`
+	QuestionIDs []string `json:"question_ids"`
+	Comment     string   `json:"comment"`
+}
+
+// 提交课程题目审核并指定审核人
+// POST /api/quiz/review/assign
+func (h *QuizHandler) AssignReviewer(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	var req AssignReviewerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(middleware.RPCContext(c), 10*time.Second)
+	defer cancel()
+
+	resp, err := h.quizClient.AssignQuestionReviewer(ctx, &pb.AssignQuestionReviewerRequest{
+		UserId:      userID,
+		QuestionIds: req.QuestionIDs,
+		ReviewerId:  req.ReviewerID,
+	})
+	if err != nil {
+		h.logger.Errorf("提交题目审核失败: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "提交题目审核失败"})
+		return
+	}
+	h.reviewJSON(c, resp)
+}
+
+// 审核单道题目
+// POST /api/quiz/:questionId/review
+func (h *QuizHandler) ReviewQuestion(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	var req ReviewQuestionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(middleware.RPCContext(c), 10*time.Second)
+	defer cancel()
+
+	resp, err := h.quizClient.ReviewQuestion(ctx, &pb.ReviewQuestionRequest{
+		UserId:     userID,
+		QuestionId: c.Param("questionId"),
+		Approve:    req.Decision == "approve",
+		Comment:    req.Comment,
+	})
+	if err != nil {
+		h.logger.Errorf("审核题目失败: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "审核题目失败"})
+		return
+	}
+	h.reviewJSON(c, resp)
+}
+
+// 批量通过待审核题目
+// POST /api/quiz/review/bulk-approve
+func (h *QuizHandler) BulkApprove(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	var req BulkApproveRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(middleware.RPCContext(c), 30*time.Second)
+	defer cancel()
+
+	resp, err := h.quizClient.BulkApproveQuestions(ctx, &pb.BulkApproveQuestionsRequest{
+		UserId:      userID,
+		CourseId:    req.CourseID,
+		QuestionIds: req.QuestionIDs,
+		Comment:     req.Comment,
+	})
+	if err != nil {
+		h.logger.Errorf("批量通过题目失败: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "批量通过题目失败"})
+		return
+	}
+	h.reviewJSON(c, resp)
+}
+
+// 获取本人的审核队列，status 缺省为 pending_review
+// GET /api/quiz/review/queue
+func (h *QuizHandler) ListReviewQueue(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+
+	ctx, cancel := context.WithTimeout(middleware.RPCContext(c), 10*time.Second)
+	defer cancel()
+
+	resp, err := h.quizClient.ListReviewQueue(ctx, &pb.ListReviewQueueRequest{
+		UserId:   userID,
+		CourseId: c.Query("course_id"),
+		Status:   c.Query("status"),
+		Page:     int32(page),
+		PageSize: int32(pageSize),
+	})
+	if err != nil {
+		h.logger.Errorf("获取审核队列失败: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "获取审核队列失败"})
+		return
+	}
+	if !resp.Success {
+		c.JSON(http.StatusBadRequest, gin.H{"error": resp.Message})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":   true,
+		"questions": protoJSON(c, resp.Questions),
+		"total":     resp.Total,
+	})
+}
+
+func (h *QuizHandler) reviewJSON(c *gin.Context, resp *pb.ReviewQuestionsResponse) {
+	if !resp.Success {
+		c.JSON(reviewErrorStatus(resp.Message), gin.H{"error": resp.Message})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success":   true,
+		"message":   resp.Message,
+		"questions": protoJSON(c, resp.Questions),
+		"skipped":   protoJSON(c, resp.Skipped),
+	})
+}
+
+// reviewErrorStatus 按 quiz-service 返回的错误信息选择状态码
+func reviewErrorStatus(message string) int {
+	switch message {
+	case "题目不存在":
+		return http.StatusNotFound
+	case "无权审核该题目":
+		return http.StatusForbidden
+	case "题目审核状态已变化，请刷新后重试":
+		return http.StatusConflict
+	default:
+		return http.StatusBadRequest
+	}
+}
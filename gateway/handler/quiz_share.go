@@ -0,0 +1,347 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"github.com/RigelNana/arkstudy/gateway/middleware"
+	pb "github.com/RigelNana/arkstudy/proto/quiz"
+)
+
+// 公开分享的访问前缀，令牌拼接在其后
+const publicSharePath = "/api/public/quiz-shares/"
+
+// QuizShareHandler 题目公开分享：创建者管理分享与查看作答结果，访客凭分享令牌作答
+type QuizShareHandler struct {
+	quizClient pb.QuizServiceClient
+	links      *middleware.ShareLinks
+	logger     *logrus.Logger
+}
+
+func NewQuizShareHandler(quiz *QuizHandler, links *middleware.ShareLinks) *QuizShareHandler {
+	return &QuizShareHandler{
+		quizClient: quiz.quizClient,
+		links:      links,
+		logger:     quiz.logger,
+	}
+}
+
+// 创建分享请求结构，question_ids 为空时分享 material_id 下本人创建的全部题目；expires_in 单位为秒，0 为默认有效期
+type CreateQuizShareRequest struct {
+	Title          string   `json:"title"`
+	MaterialID     string   `json:"material_id"`
+	QuestionIDs    []string `json:"question_ids"`
+	AllowAnonymous bool     `json:"allow_anonymous"`
+	ExpiresIn      int64    `json:"expires_in" binding:"gte=0"`
+}
+
+// 分享作答请求结构，登录用户作答时 nickname 可省略；telemetry 为前端记录的离开页面、粘贴事件，
+// 与各题 time_spent_ms 一起用于标记异常作答
+type SubmitQuizShareRequest struct {
+	Nickname string `json:"nickname"`
+	Answers  []struct {
+		QuestionID  string   `json:"question_id" binding:"required"`
+		Answer      string   `json:"answer"`
+		PartAnswers []string `json:"part_answers"`
+		TimeSpentMs int64    `json:"time_spent_ms" binding:"min=0"`
+	} `json:"answers" binding:"required,dive"`
+	Telemetry *struct {
+		DurationMs  int64 `json:"duration_ms"`
+		FocusEvents []struct {
+			QuestionID string `json:"question_id"`
+			OffsetMs   int64  `json:"offset_ms"`
+			AwayMs     int64  `json:"away_ms"`
+		} `json:"focus_events"`
+		PasteEvents []struct {
+			QuestionID string `json:"question_id"`
+			OffsetMs   int64  `json:"offset_ms"`
+			Length     int32  `json:"length"`
+		} `json:"paste_events"`
+	} `json:"telemetry"`
+}
+
+// 创建分享
+// POST /api/quiz/shares
+func (h *QuizShareHandler) CreateShare(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	var req CreateQuizShareRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(middleware.RPCContext(c), 10*time.Second)
+	defer cancel()
+
+	resp, err := h.quizClient.CreateQuizShare(ctx, &pb.CreateQuizShareRequest{
+		UserId:         userID,
+		Title:          req.Title,
+		MaterialId:     req.MaterialID,
+		QuestionIds:    req.QuestionIDs,
+		AllowAnonymous: req.AllowAnonymous,
+		TtlSeconds:     req.ExpiresIn,
+	})
+	if err != nil {
+		h.logger.Errorf("创建分享失败: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "创建分享失败"})
+		return
+	}
+	if !resp.Success {
+		c.JSON(shareErrorStatus(resp.Message), gin.H{"error": resp.Message})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    h.shareJSON(c, resp.Share),
+	})
+}
+
+// 获取本人创建的分享
+// GET /api/quiz/shares
+func (h *QuizShareHandler) ListShares(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+
+	ctx, cancel := context.WithTimeout(middleware.RPCContext(c), 10*time.Second)
+	defer cancel()
+
+	resp, err := h.quizClient.ListQuizShares(ctx, &pb.ListQuizSharesRequest{
+		UserId:   userID,
+		Page:     int32(page),
+		PageSize: int32(pageSize),
+	})
+	if err != nil {
+		h.logger.Errorf("获取分享列表失败: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "获取分享列表失败"})
+		return
+	}
+	if !resp.Success {
+		c.JSON(shareErrorStatus(resp.Message), gin.H{"error": resp.Message})
+		return
+	}
+
+	shares := make([]gin.H, 0, len(resp.Shares))
+	for _, s := range resp.Shares {
+		shares = append(shares, h.shareJSON(c, s))
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    shares,
+		"total":   resp.Total,
+	})
+}
+
+// 创建者查看分享，题目包含答案与解析
+// GET /api/quiz/shares/:id
+func (h *QuizShareHandler) GetShare(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	h.getShare(c, c.Param("id"), userID)
+}
+
+// 撤销分享，已签发的链接立即失效
+// DELETE /api/quiz/shares/:id
+func (h *QuizShareHandler) RevokeShare(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(middleware.RPCContext(c), 10*time.Second)
+	defer cancel()
+
+	resp, err := h.quizClient.RevokeQuizShare(ctx, &pb.RevokeQuizShareRequest{
+		ShareId: c.Param("id"),
+		UserId:  userID,
+	})
+	if err != nil {
+		h.logger.Errorf("撤销分享失败: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "撤销分享失败"})
+		return
+	}
+	if !resp.Success {
+		c.JSON(shareErrorStatus(resp.Message), gin.H{"error": resp.Message})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    protoJSON(c, resp.Share),
+	})
+}
+
+// 创建者查看分享的作答记录，含作答埋点与异常标记；flagged=true 时只返回有异常标记的作答
+// GET /api/quiz/shares/:id/attempts
+func (h *QuizShareHandler) ListAttempts(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+
+	ctx, cancel := context.WithTimeout(middleware.RPCContext(c), 10*time.Second)
+	defer cancel()
+
+	resp, err := h.quizClient.ListQuizShareAttempts(ctx, &pb.ListQuizShareAttemptsRequest{
+		ShareId:     c.Param("id"),
+		UserId:      userID,
+		Page:        int32(page),
+		PageSize:    int32(pageSize),
+		FlaggedOnly: c.Query("flagged") == "true",
+	})
+	if err != nil {
+		h.logger.Errorf("获取分享作答记录失败: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "获取分享作答记录失败"})
+		return
+	}
+	if !resp.Success {
+		c.JSON(shareErrorStatus(resp.Message), gin.H{"error": resp.Message})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    protoJSON(c, resp.Attempts),
+		"total":   resp.Total,
+	})
+}
+
+// 访客查看分享的题目，不含答案与解析
+// GET /api/public/quiz-shares/:token
+func (h *QuizShareHandler) GetPublicShare(c *gin.Context) {
+	h.getShare(c, c.GetString("share_id"), "")
+}
+
+// 访客提交作答，返回得分与各题对错，不返回标准答案，以免链接被转发后答案外泄
+// POST /api/public/quiz-shares/:token/attempts
+func (h *QuizShareHandler) SubmitPublicAttempt(c *gin.Context) {
+	var req SubmitQuizShareRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	answers := make([]*pb.QuizShareAnswer, 0, len(req.Answers))
+	for _, a := range req.Answers {
+		answers = append(answers, &pb.QuizShareAnswer{
+			QuestionId:  a.QuestionID,
+			Answer:      a.Answer,
+			PartAnswers: a.PartAnswers,
+			TimeSpentMs: a.TimeSpentMs,
+		})
+	}
+	var telemetry *pb.AttemptTelemetry
+	if t := req.Telemetry; t != nil {
+		telemetry = &pb.AttemptTelemetry{DurationMs: t.DurationMs}
+		for _, e := range t.FocusEvents {
+			telemetry.FocusEvents = append(telemetry.FocusEvents, &pb.FocusEvent{QuestionId: e.QuestionID, OffsetMs: e.OffsetMs, AwayMs: e.AwayMs})
+		}
+		for _, e := range t.PasteEvents {
+			telemetry.PasteEvents = append(telemetry.PasteEvents, &pb.PasteEvent{QuestionId: e.QuestionID, OffsetMs: e.OffsetMs, Length: e.Length})
+		}
+	}
+
+	// 整份作答逐题评分，主观题可能调用 LLM，超时比单题提交更长
+	ctx, cancel := context.WithTimeout(middleware.RPCContext(c), 30*time.Second)
+	defer cancel()
+
+	resp, err := h.quizClient.SubmitQuizShareAttempt(ctx, &pb.SubmitQuizShareAttemptRequest{
+		ShareId:   c.GetString("share_id"),
+		UserId:    c.GetString("user_id"),
+		Nickname:  req.Nickname,
+		Answers:   answers,
+		Telemetry: telemetry,
+	})
+	if err != nil {
+		h.logger.Errorf("提交分享作答失败: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "提交作答失败"})
+		return
+	}
+	if !resp.Success {
+		c.JSON(shareErrorStatus(resp.Message), gin.H{"error": resp.Message})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    protoJSON(c, resp.Attempt),
+	})
+}
+
+func (h *QuizShareHandler) getShare(c *gin.Context, shareID, userID string) {
+	ctx, cancel := context.WithTimeout(middleware.RPCContext(c), 10*time.Second)
+	defer cancel()
+
+	resp, err := h.quizClient.GetQuizShare(ctx, &pb.GetQuizShareRequest{
+		ShareId: shareID,
+		UserId:  userID,
+	})
+	if err != nil {
+		h.logger.Errorf("获取分享失败: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "获取分享失败"})
+		return
+	}
+	if !resp.Success {
+		c.JSON(shareErrorStatus(resp.Message), gin.H{"error": resp.Message})
+		return
+	}
+
+	share := h.shareJSON(c, resp.Share)
+	if userID == "" {
+		// 访客不需要创建者信息与分享令牌
+		share = gin.H{
+			"title":           resp.Share.Title,
+			"allow_anonymous": resp.Share.AllowAnonymous,
+			"expires_at":      formatTimestamp(c, resp.Share.ExpiresAt),
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success":   true,
+		"share":     share,
+		"questions": protoJSON(c, resp.Questions),
+	})
+}
+
+// shareJSON 分享信息附带访问令牌与地址，已撤销的分享不再返回令牌
+func (h *QuizShareHandler) shareJSON(c *gin.Context, share *pb.QuizShare) gin.H {
+	out := gin.H{"share": protoJSON(c, share)}
+	if share.Revoked {
+		return out
+	}
+	token := h.links.Token(share.ShareId, share.ExpiresAt.AsTime())
+	out["token"] = token
+	out["url"] = publicSharePath + token
+	if embed := h.links.EmbedURL(token); embed != "" {
+		out["embed_url"] = embed
+	}
+	return out
+}
+
+// shareErrorStatus 按 quiz-service 返回的错误信息选择状态码
+func shareErrorStatus(message string) int {
+	switch message {
+	case "分享不存在或已失效":
+		return http.StatusNotFound
+	case "无权访问该分享":
+		return http.StatusForbidden
+	case "该分享需要登录后作答":
+		return http.StatusUnauthorized
+	default:
+		return http.StatusBadRequest
+	}
+}
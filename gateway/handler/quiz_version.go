@@ -0,0 +1,176 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/RigelNana/arkstudy/gateway/middleware"
+	pb "github.com/RigelNana/arkstudy/proto/quiz"
+)
+
+// 编辑题目请求结构，整体替换可编辑字段；expected_version 为编辑前读取到的版本号，题目期间被他人修改时返回 409
+type UpdateQuestionRequest struct {
+	ExpectedVersion  int32    `json:"expected_version"`
+	Content          string   `json:"content" binding:"required"`
+	Options          []string `json:"options"`
+	CorrectAnswer    string   `json:"correct_answer"`
+	AnswerAliases    []string `json:"answer_aliases"`
+	NumericTolerance float64  `json:"numeric_tolerance"`
+	Parts            []struct {
+		Label            string   `json:"label"`
+		CorrectAnswer    string   `json:"correct_answer"`
+		AnswerAliases    []string `json:"answer_aliases"`
+		NumericTolerance float64  `json:"numeric_tolerance"`
+		Weight           float32  `json:"weight"`
+	} `json:"parts"`
+	Explanation     string   `json:"explanation"`
+	Difficulty      int32    `json:"difficulty"`
+	KnowledgePoints []string `json:"knowledge_points"`
+	ChangeNote      string   `json:"change_note"`
+}
+
+// 编辑题目，每次编辑保存一个新版本
+// PUT /api/quiz/:questionId
+func (h *QuizHandler) UpdateQuestion(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	var req UpdateQuestionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	parts := make([]*pb.QuestionPart, 0, len(req.Parts))
+	for _, p := range req.Parts {
+		parts = append(parts, &pb.QuestionPart{
+			Label:            p.Label,
+			CorrectAnswer:    p.CorrectAnswer,
+			AnswerAliases:    p.AnswerAliases,
+			NumericTolerance: p.NumericTolerance,
+			Weight:           p.Weight,
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(middleware.RPCContext(c), 10*time.Second)
+	defer cancel()
+
+	resp, err := h.quizClient.UpdateQuestion(ctx, &pb.UpdateQuestionRequest{
+		UserId:           userID,
+		QuestionId:       c.Param("questionId"),
+		ExpectedVersion:  req.ExpectedVersion,
+		Content:          req.Content,
+		Options:          req.Options,
+		CorrectAnswer:    req.CorrectAnswer,
+		AnswerAliases:    req.AnswerAliases,
+		NumericTolerance: req.NumericTolerance,
+		Parts:            parts,
+		Explanation:      req.Explanation,
+		Difficulty:       pb.DifficultyLevel(req.Difficulty),
+		KnowledgePoints:  req.KnowledgePoints,
+		ChangeNote:       req.ChangeNote,
+	})
+	if err != nil {
+		h.logger.Errorf("编辑题目失败: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "编辑题目失败"})
+		return
+	}
+	if !resp.Success {
+		c.JSON(versionErrorStatus(resp.Message), gin.H{"error": resp.Message})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":        true,
+		"message":        resp.Message,
+		"question":       protoJSON(c, resp.Question),
+		"changed_fields": resp.ChangedFields,
+	})
+}
+
+// 获取题目的版本历史
+// GET /api/quiz/:questionId/versions
+func (h *QuizHandler) ListQuestionVersions(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(middleware.RPCContext(c), 10*time.Second)
+	defer cancel()
+
+	resp, err := h.quizClient.ListQuestionVersions(ctx, &pb.ListQuestionVersionsRequest{
+		QuestionId: c.Param("questionId"),
+		UserId:     userID,
+	})
+	if err != nil {
+		h.logger.Errorf("获取题目版本失败: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "获取题目版本失败"})
+		return
+	}
+	if !resp.Success {
+		c.JSON(versionErrorStatus(resp.Message), gin.H{"error": resp.Message})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":  true,
+		"versions": protoJSON(c, resp.Versions),
+	})
+}
+
+// 获取题目的指定版本，作答过该版本的用户可查看，用于核对作答时的题面
+// GET /api/quiz/:questionId/versions/:version
+func (h *QuizHandler) GetQuestionVersion(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	version, err := strconv.Atoi(c.Param("version"))
+	if err != nil || version <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的版本号"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(middleware.RPCContext(c), 10*time.Second)
+	defer cancel()
+
+	resp, err := h.quizClient.GetQuestionVersion(ctx, &pb.GetQuestionVersionRequest{
+		QuestionId: c.Param("questionId"),
+		Version:    int32(version),
+		UserId:     userID,
+	})
+	if err != nil {
+		h.logger.Errorf("获取题目版本失败: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "获取题目版本失败"})
+		return
+	}
+	if !resp.Success {
+		c.JSON(versionErrorStatus(resp.Message), gin.H{"error": resp.Message})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"version": protoJSON(c, resp.Version),
+	})
+}
+
+// versionErrorStatus 按 quiz-service 返回的错误信息选择状态码
+func versionErrorStatus(message string) int {
+	switch message {
+	case "题目不存在", "题目版本不存在":
+		return http.StatusNotFound
+	case "只能编辑本人创建的题目", "无权查看该题目版本":
+		return http.StatusForbidden
+	case "题目已被修改，请刷新后重试":
+		return http.StatusConflict
+	default:
+		return http.StatusBadRequest
+	}
+}
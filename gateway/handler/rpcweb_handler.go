@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"fmt"
+
+	"github.com/RigelNana/arkstudy/gateway/backend"
+	"github.com/RigelNana/arkstudy/gateway/grpcweb"
+	"github.com/RigelNana/arkstudy/pkg/registry"
+	"github.com/gin-gonic/gin"
+	"google.golang.org/protobuf/proto"
+
+	llmpb "github.com/RigelNana/arkstudy/proto/llm"
+)
+
+// 经 gRPC-Web / Connect 开放的后端方法，只包含由调用者 user_id 限定范围的查询与问答；
+// 写操作与服务间回调（如 UpdateProcessingResult）仍只能经 REST 接口或内部调用。
+// 方法描述来自 handler 包中其他文件已导入的 proto 包
+var rpcWebMethods = []struct {
+	svc     registry.Service
+	methods []string
+	ai      bool // 计入每日 LLM 配额
+}{
+	{registry.LLM, []string{"llm.LLMService.AskQuestion", "llm.LLMService.AskQuestionStream", "llm.LLMService.SemanticSearch"}, true},
+	{registry.LLM, []string{"llm.LLMService.GetSessionHistory"}, false},
+	{registry.Material, []string{
+		"material.MaterialService.ListMaterials",
+		"material.MaterialService.GetMaterial",
+		"material.MaterialService.ListChildMaterials",
+		"material.MaterialService.GetProcessingResult",
+		"material.MaterialService.ListProcessingResults",
+		"material.MaterialService.ListAnnotations",
+	}, false},
+	{registry.Quiz, []string{
+		"quiz.QuizService.GetQuiz",
+		"quiz.QuizService.ListQuizzes",
+		"quiz.QuizService.GetUserQuizHistory",
+		"quiz.QuizService.GetKnowledgeStats",
+		"quiz.QuizService.ListMistakes",
+	}, false},
+	{registry.ASR, []string{"asr.ASRService.GetSegments", "asr.ASRService.GetChapters", "asr.ASRService.SearchSegments"}, false},
+}
+
+// RPCWebHandler 以 gRPC-Web 与 Connect 协议开放选定的后端方法，路由为 /api/rpc/<package.Service>/<Method>
+type RPCWebHandler struct {
+	methods []*grpcweb.Method
+	ai      map[*grpcweb.Method]bool
+}
+
+// NewRPCWebHandler 解析开放的方法，方法不存在或请求没有 user_id 字段时返回错误
+func NewRPCWebHandler() (*RPCWebHandler, error) {
+	h := &RPCWebHandler{ai: map[*grpcweb.Method]bool{}}
+	for _, group := range rpcWebMethods {
+		for _, name := range group.methods {
+			m, err := grpcweb.NewMethod(backend.For(group.svc), name)
+			if err != nil {
+				return nil, err
+			}
+			h.methods = append(h.methods, m)
+			h.ai[m] = group.ai
+		}
+	}
+	for _, m := range h.methods {
+		switch m.Path {
+		case "/llm.LLMService/AskQuestion", "/llm.LLMService/AskQuestionStream":
+			m.OnRequest = validateRPCQuestion
+			m.OnMessage = reportRPCTokenUsage
+		}
+	}
+	return h, nil
+}
+
+// Methods 开放的方法
+func (h *RPCWebHandler) Methods() []*grpcweb.Method {
+	return h.methods
+}
+
+// UsesAI 方法是否调用 LLM，需经配额中间件
+func (h *RPCWebHandler) UsesAI(m *grpcweb.Method) bool {
+	return h.ai[m]
+}
+
+// validateRPCQuestion 与 REST 问答接口相同的材料数量限制
+func validateRPCQuestion(c *gin.Context, req proto.Message) error {
+	q := req.(*llmpb.QuestionRequest)
+	if q.Question == "" {
+		return fmt.Errorf("question is required")
+	}
+	if len(q.MaterialIds) > maxAskMaterialIDs {
+		return fmt.Errorf("at most %d material_ids are allowed", maxAskMaterialIDs)
+	}
+	return nil
+}
+
+// reportRPCTokenUsage 从回答或流的最后一个分片中读取 token 用量计入配额
+func reportRPCTokenUsage(c *gin.Context, msg proto.Message) {
+	switch m := msg.(type) {
+	case *llmpb.QuestionResponse:
+		reportTokenUsage(c, m.Metadata)
+	case *llmpb.TokenChunk:
+		if m.IsFinal {
+			reportTokenUsage(c, m.Metadata)
+		}
+	}
+}
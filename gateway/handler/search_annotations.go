@@ -0,0 +1,122 @@
+package handler
+
+import (
+	"context"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	llmpb "github.com/RigelNana/arkstudy/proto/llm"
+	materialpb "github.com/RigelNana/arkstudy/proto/material"
+	"github.com/gin-gonic/gin"
+)
+
+// annotatedSearchResult 附带批注的检索结果
+type annotatedSearchResult struct {
+	*llmpb.SearchResult
+	Annotations []any `json:"annotations,omitempty"`
+}
+
+// annotateResults 批量查询结果所属资料上的批注，并按锚点挂到对应结果上：
+// 页码批注匹配 metadata.page 相同的片段，时间批注匹配时间段重叠的片段，未锚定的批注匹配该资料的所有片段。
+// 批注查询失败时只记录日志，检索结果照常返回
+func (h *LLMHandler) annotateResults(c *gin.Context, userID string, results []*llmpb.SearchResult) []annotatedSearchResult {
+	out := make([]annotatedSearchResult, 0, len(results))
+	seen := map[string]bool{}
+	var materialIDs []string
+	for _, r := range results {
+		out = append(out, annotatedSearchResult{SearchResult: r})
+		if id := r.GetMaterialId(); id != "" && !seen[id] {
+			seen[id] = true
+			materialIDs = append(materialIDs, id)
+		}
+	}
+	if len(materialIDs) == 0 || h.materialClient == nil {
+		return out
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+	resp, err := h.materialClient.ListAnnotations(ctx, &materialpb.ListAnnotationsRequest{
+		UserId:      userID,
+		MaterialIds: materialIDs,
+	})
+	if err != nil {
+		log.Printf("ListAnnotations gRPC error: %v", err)
+		return out
+	}
+	if !resp.Success {
+		log.Printf("Warning: failed to list annotations for search results: %s", resp.Message)
+		return out
+	}
+
+	byMaterial := map[string][]*materialpb.Annotation{}
+	for _, a := range resp.Annotations {
+		byMaterial[a.MaterialId] = append(byMaterial[a.MaterialId], a)
+	}
+	for i := range out {
+		for _, a := range byMaterial[out[i].GetMaterialId()] {
+			if annotationMatches(a, out[i].GetMetadata()) {
+				out[i].Annotations = append(out[i].Annotations, protoJSON(c, a))
+			}
+		}
+	}
+	return out
+}
+
+// annotationMatches 判断批注锚点是否落在检索片段上
+func annotationMatches(a *materialpb.Annotation, metadata map[string]string) bool {
+	switch a.AnchorType {
+	case "":
+		return true
+	case "page":
+		page, err := strconv.Atoi(metadata["page"])
+		return err == nil && int32(page) == a.Page
+	case "time":
+		start, end, ok := chunkTimeRange(metadata)
+		return ok && a.StartTime <= end && a.EndTime >= start
+	default:
+		return false
+	}
+}
+
+// chunkTimeRange 读取片段的时间段：优先使用 start_time / end_time（秒），否则解析 HH:MM:SS-HH:MM:SS 形式的 timecode
+func chunkTimeRange(metadata map[string]string) (float64, float64, bool) {
+	if start, err := strconv.ParseFloat(metadata["start_time"], 64); err == nil {
+		end, err := strconv.ParseFloat(metadata["end_time"], 64)
+		if err != nil || end < start {
+			end = start
+		}
+		return start, end, true
+	}
+	from, to, found := strings.Cut(metadata["timecode"], "-")
+	start, ok := parseClock(from)
+	if !ok {
+		return 0, 0, false
+	}
+	end := start
+	if found {
+		if t, ok := parseClock(to); ok && t >= start {
+			end = t
+		}
+	}
+	return start, end, true
+}
+
+// parseClock 解析 HH:MM:SS 或 MM:SS 为秒数
+func parseClock(s string) (float64, bool) {
+	parts := strings.Split(strings.TrimSpace(s), ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0, false
+	}
+	var total float64
+	for _, p := range parts {
+		v, err := strconv.ParseFloat(p, 64)
+		if err != nil || v < 0 {
+			return 0, false
+		}
+		total = total*60 + v
+	}
+	return total, true
+}
@@ -0,0 +1,131 @@
+package handler
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/RigelNana/arkstudy/gateway/middleware"
+	llmpb "github.com/RigelNana/arkstudy/proto/llm"
+	"github.com/gin-gonic/gin"
+)
+
+// exportedTurn 为导出文件中的单轮问答
+type exportedTurn struct {
+	UserID    string                   `json:"user_id"` // 提问者，共享会话中各轮可能来自不同成员
+	Question  string                   `json:"question"`
+	Answer    string                   `json:"answer"`
+	Sources   []*llmpb.SourceReference `json:"sources"`
+	LatencyMs int64                    `json:"latency_ms"`
+	Model     string                   `json:"model,omitempty"`
+	CreatedAt string                   `json:"created_at"`
+}
+
+// GET /api/ai/sessions/:id/export?format=markdown|json
+// 导出当前用户某个 AI 会话的全部问答轮次，默认 markdown，以附件形式下载
+func (h *LLMHandler) ExportSession(c *gin.Context) {
+	sessionID := c.Param("id")
+	format := strings.ToLower(c.DefaultQuery("format", "markdown"))
+	if format == "md" {
+		format = "markdown"
+	}
+	if format != "markdown" && format != "json" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be markdown or json"})
+		return
+	}
+
+	userIDVal, ok := c.Get("user_id")
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+	userID, _ := userIDVal.(string)
+
+	resp, err := h.client.GetSessionHistory(middleware.RPCContext(c), &llmpb.SessionHistoryRequest{
+		SessionId: sessionID,
+		UserId:    userID,
+	})
+	if err != nil {
+		log.Printf("GetSessionHistory gRPC error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "export failed", "detail": err.Error()})
+		return
+	}
+	if !resp.Success {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "export failed", "detail": resp.Message})
+		return
+	}
+	// 会话不存在与不属于当前用户统一返回 404，不泄露会话是否存在
+	if len(resp.Turns) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "session not found"})
+		return
+	}
+
+	turns := make([]exportedTurn, 0, len(resp.Turns))
+	for _, t := range resp.Turns {
+		turns = append(turns, exportedTurn{
+			UserID:    t.UserId,
+			Question:  t.Question,
+			Answer:    t.Answer,
+			Sources:   t.Sources,
+			LatencyMs: t.LatencyMs,
+			Model:     t.Model,
+			CreatedAt: t.CreatedAt,
+		})
+	}
+
+	// 会话 ID 由客户端提供，写入响应头前去掉文件名中不安全的字符
+	filename := "ai-session-" + safeFilename(sessionID)
+	if format == "json" {
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.json"`, filename))
+		c.JSON(http.StatusOK, gin.H{
+			"session_id":  sessionID,
+			"exported_at": time.Now().UTC().Format(time.RFC3339),
+			"turns":       turns,
+		})
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.md"`, filename))
+	c.Data(http.StatusOK, "text/markdown; charset=utf-8", []byte(renderSessionMarkdown(sessionID, turns)))
+}
+
+// renderSessionMarkdown 将会话渲染为 Markdown：每轮一个小节，依次为问题、回答与引用来源
+func renderSessionMarkdown(sessionID string, turns []exportedTurn) string {
+	// 共享会话（多个提问者）在每轮中注明提问者
+	multiUser := false
+	for _, t := range turns {
+		if t.UserID != turns[0].UserID {
+			multiUser = true
+			break
+		}
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "# AI 学习会话 %s\n\n", sessionID)
+	fmt.Fprintf(&b, "导出时间：%s，共 %d 轮\n", time.Now().UTC().Format(time.RFC3339), len(turns))
+	for i, t := range turns {
+		fmt.Fprintf(&b, "\n## 第 %d 轮", i+1)
+		if t.CreatedAt != "" {
+			fmt.Fprintf(&b, "（%s）", t.CreatedAt)
+		}
+		b.WriteString("\n\n")
+		if multiUser {
+			fmt.Fprintf(&b, "**提问者：** `%s`\n\n", t.UserID)
+		}
+		fmt.Fprintf(&b, "**问题：** %s\n\n", t.Question)
+		fmt.Fprintf(&b, "**回答：**\n\n%s\n", t.Answer)
+		if len(t.Sources) > 0 {
+			b.WriteString("\n**来源：**\n\n")
+			for _, s := range t.Sources {
+				snippet := strings.Join(strings.Fields(s.ContentSnippet), " ")
+				fmt.Fprintf(&b, "- `%s`（相关度 %.2f）：%s\n", s.MaterialId, s.RelevanceScore, snippet)
+			}
+		}
+		meta := []string{fmt.Sprintf("耗时 %d ms", t.LatencyMs)}
+		if t.Model != "" {
+			meta = append(meta, "模型 "+t.Model)
+		}
+		fmt.Fprintf(&b, "\n_%s_\n", strings.Join(meta, "，"))
+	}
+	return b.String()
+}
@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	llmpb "github.com/RigelNana/arkstudy/proto/llm"
+)
+
+// 共享会话推送事件类型
+const (
+	SessionEventQuestion = "question" // 成员提出的问题
+	SessionEventToken    = "token"    // 流式回答的分片
+	SessionEventAnswer   = "answer"   // 完整回答，流式回答结束时也会发送
+	SessionEventPresence = "presence" // 在线成员变化
+	SessionEventMembers  = "members"  // 成员或角色变化
+)
+
+// 每个连接的待发送事件上限，客户端消费过慢时丢弃事件而不阻塞提问请求（尽力而为的实时推送）
+const sessionEventBuffer = 64
+
+// sessionEvent 推送给共享会话成员的 WebSocket 消息。同一轮问答的 question、token、answer 事件
+// 使用相同的 turn_id，user_id 为提问者
+type sessionEvent struct {
+	Type      string                   `json:"type"`
+	SessionID string                   `json:"session_id"`
+	TurnID    string                   `json:"turn_id,omitempty"`
+	UserID    string                   `json:"user_id,omitempty"`
+	Content   string                   `json:"content,omitempty"`
+	Sources   []*llmpb.SourceReference `json:"sources,omitempty"`
+	Metadata  map[string]string        `json:"metadata,omitempty"`
+	Online    []string                 `json:"online,omitempty"`
+	Members   []*llmpb.SessionMember   `json:"members,omitempty"`
+	At        time.Time                `json:"at"`
+}
+
+type sessionSubscriber struct {
+	userID string
+	send   chan sessionEvent
+}
+
+// SessionHub 将共享会话的新问答推送给连接到本 gateway 实例的成员。
+// 广播只在实例内进行，多副本部署时需要按会话 ID 做粘性路由；未共享的会话没有订阅者，发布为空操作
+type SessionHub struct {
+	mu   sync.RWMutex
+	subs map[string]map[*sessionSubscriber]struct{}
+}
+
+func NewSessionHub() *SessionHub {
+	return &SessionHub{subs: map[string]map[*sessionSubscriber]struct{}{}}
+}
+
+// Publish 向会话的所有在线成员发送事件，nil 接收者或空会话 ID 时忽略
+func (h *SessionHub) Publish(sessionID string, ev sessionEvent) {
+	if h == nil || sessionID == "" {
+		return
+	}
+	ev.SessionID = sessionID
+	if ev.At.IsZero() {
+		ev.At = time.Now().UTC()
+	}
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for sub := range h.subs[sessionID] {
+		select {
+		case sub.send <- ev:
+		default:
+			log.Printf("Warning: dropping %s event of shared session %s for slow subscriber %s", ev.Type, sessionID, sub.userID)
+		}
+	}
+}
+
+func (h *SessionHub) subscribe(sessionID, userID string) *sessionSubscriber {
+	sub := &sessionSubscriber{userID: userID, send: make(chan sessionEvent, sessionEventBuffer)}
+	h.mu.Lock()
+	if h.subs[sessionID] == nil {
+		h.subs[sessionID] = map[*sessionSubscriber]struct{}{}
+	}
+	h.subs[sessionID][sub] = struct{}{}
+	h.mu.Unlock()
+	return sub
+}
+
+// unsubscribe 移除订阅并关闭其发送队列，可重复调用
+func (h *SessionHub) unsubscribe(sessionID string, sub *sessionSubscriber) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, ok := h.subs[sessionID][sub]; !ok {
+		return
+	}
+	delete(h.subs[sessionID], sub)
+	if len(h.subs[sessionID]) == 0 {
+		delete(h.subs, sessionID)
+	}
+	close(sub.send)
+}
+
+// Revoke 断开被移出会话的成员在本实例上的连接
+func (h *SessionHub) Revoke(sessionID, userID string) {
+	if h == nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for sub := range h.subs[sessionID] {
+		if sub.userID == userID {
+			delete(h.subs[sessionID], sub)
+			close(sub.send)
+		}
+	}
+	if len(h.subs[sessionID]) == 0 {
+		delete(h.subs, sessionID)
+	}
+}
+
+// Online 会话在本实例上在线的成员（去重，按 ID 排序）
+func (h *SessionHub) Online(sessionID string) []string {
+	if h == nil {
+		return nil
+	}
+	h.mu.RLock()
+	seen := map[string]bool{}
+	for sub := range h.subs[sessionID] {
+		seen[sub.userID] = true
+	}
+	h.mu.RUnlock()
+	out := make([]string, 0, len(seen))
+	for id := range seen {
+		out = append(out, id)
+	}
+	sort.Strings(out)
+	return out
+}
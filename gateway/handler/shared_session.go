@@ -0,0 +1,236 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	llmpb "github.com/RigelNana/arkstudy/proto/llm"
+	"github.com/gin-gonic/gin"
+	"golang.org/x/net/websocket"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// grpcHTTPStatus 将 llm-service 共享会话 ACL 返回的 gRPC 状态码映射为 HTTP 状态码
+func grpcHTTPStatus(err error) int {
+	switch status.Code(err) {
+	case codes.InvalidArgument:
+		return http.StatusBadRequest
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.NotFound:
+		return http.StatusNotFound
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// grpcErrorMessage 返回 gRPC 错误中的描述，不含状态码前缀
+func grpcErrorMessage(err error) string {
+	if s, ok := status.FromError(err); ok {
+		return s.Message()
+	}
+	return err.Error()
+}
+
+func (h *LLMHandler) sharedSessionError(c *gin.Context, op string, err error) {
+	code := grpcHTTPStatus(err)
+	if code == http.StatusInternalServerError {
+		log.Printf("%s gRPC error: %v", op, err)
+	}
+	c.JSON(code, gin.H{"error": "shared session request failed", "detail": grpcErrorMessage(err)})
+}
+
+// POST /api/ai/shared-sessions
+// 创建共享会话（session_id 为空时新建，否则共享自己已有的会话），当前用户成为 owner，响应中含邀请码
+func (h *LLMHandler) CreateSharedSession(c *gin.Context) {
+	var req struct {
+		Title       string `json:"title"`
+		SessionID   string `json:"session_id"`
+		DefaultRole string `json:"default_role"` // participant（默认）| viewer
+	}
+	if err := c.ShouldBindJSON(&req); err != nil && c.Request.ContentLength > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input", "detail": err.Error()})
+		return
+	}
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	resp, err := h.client.CreateSharedSession(c.Request.Context(), &llmpb.CreateSharedSessionRequest{
+		UserId:      userID,
+		Title:       req.Title,
+		SessionId:   req.SessionID,
+		DefaultRole: req.DefaultRole,
+	})
+	if err != nil {
+		h.sharedSessionError(c, "CreateSharedSession", err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"success": true, "data": gin.H{"session": resp.Session, "role": resp.Role}})
+}
+
+// GET /api/ai/shared-sessions/:id
+// 会话信息、成员列表与当前在线成员，仅成员可见
+func (h *LLMHandler) GetSharedSession(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	resp, err := h.client.GetSharedSession(c.Request.Context(), &llmpb.GetSharedSessionRequest{
+		SessionId: c.Param("id"),
+		UserId:    userID,
+	})
+	if err != nil {
+		h.sharedSessionError(c, "GetSharedSession", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": gin.H{
+		"session": resp.Session,
+		"role":    resp.Role,
+		"online":  h.sessions.Online(resp.Session.GetSessionId()),
+	}})
+}
+
+// POST /api/ai/shared-sessions/:id/join
+// 使用邀请码加入共享会话，角色为会话的 default_role
+func (h *LLMHandler) JoinSharedSession(c *gin.Context) {
+	var req struct {
+		JoinCode string `json:"join_code" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input", "detail": err.Error()})
+		return
+	}
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	resp, err := h.client.JoinSharedSession(c.Request.Context(), &llmpb.JoinSharedSessionRequest{
+		SessionId: c.Param("id"),
+		UserId:    userID,
+		JoinCode:  req.JoinCode,
+	})
+	if err != nil {
+		h.sharedSessionError(c, "JoinSharedSession", err)
+		return
+	}
+	h.sessions.Publish(resp.Session.GetSessionId(), sessionEvent{Type: SessionEventMembers, Members: resp.Session.GetMembers()})
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": gin.H{"session": resp.Session, "role": resp.Role}})
+}
+
+// PUT /api/ai/shared-sessions/:id/members/:user_id
+// owner 修改成员角色（participant | viewer）
+func (h *LLMHandler) UpdateSessionMember(c *gin.Context) {
+	var req struct {
+		Role string `json:"role" binding:"required,oneof=participant viewer"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input", "detail": err.Error()})
+		return
+	}
+	h.setSessionMember(c, req.Role)
+}
+
+// DELETE /api/ai/shared-sessions/:id/members/:user_id
+// owner 移除成员，被移除成员的 WebSocket 连接随即断开
+func (h *LLMHandler) RemoveSessionMember(c *gin.Context) {
+	h.setSessionMember(c, "")
+}
+
+func (h *LLMHandler) setSessionMember(c *gin.Context, role string) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	memberID := c.Param("user_id")
+	resp, err := h.client.SetSessionMember(c.Request.Context(), &llmpb.SetSessionMemberRequest{
+		SessionId: c.Param("id"),
+		UserId:    userID,
+		MemberId:  memberID,
+		Role:      role,
+	})
+	if err != nil {
+		h.sharedSessionError(c, "SetSessionMember", err)
+		return
+	}
+	sessionID := resp.Session.GetSessionId()
+	if role == "" {
+		h.sessions.Revoke(sessionID, memberID)
+	}
+	h.sessions.Publish(sessionID, sessionEvent{Type: SessionEventMembers, Members: resp.Session.GetMembers()})
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": gin.H{"session": resp.Session, "role": resp.Role}})
+}
+
+// GET /api/ai/shared-sessions/:id/ws
+// 订阅共享会话的实时推送：成员的提问、流式回答分片、完整回答以及在线与成员变化（JSON 消息，见 sessionEvent）。
+// 连接只用于接收，提问仍通过 /api/ai/ask 与 /api/ai/ask/stream 携带 session_id 发起，以便统一配额与 ACL。
+// 浏览器无法为 WebSocket 设置 Authorization 头，可通过 access_token 查询参数传递 token
+func (h *LLMHandler) SharedSessionSocket(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	resp, err := h.client.GetSharedSession(c.Request.Context(), &llmpb.GetSharedSessionRequest{
+		SessionId: c.Param("id"),
+		UserId:    userID,
+	})
+	if err != nil {
+		h.sharedSessionError(c, "GetSharedSession", err)
+		return
+	}
+	sessionID := resp.Session.GetSessionId()
+
+	server := websocket.Server{
+		// 身份由 JWT 校验，token 不来自 cookie，因此不限制 Origin
+		Handshake: func(*websocket.Config, *http.Request) error { return nil },
+		Handler: func(ws *websocket.Conn) {
+			defer ws.Close()
+			sub := h.sessions.subscribe(sessionID, userID)
+			h.sessions.Publish(sessionID, sessionEvent{Type: SessionEventPresence, UserID: userID, Online: h.sessions.Online(sessionID)})
+			defer func() {
+				h.sessions.unsubscribe(sessionID, sub)
+				h.sessions.Publish(sessionID, sessionEvent{Type: SessionEventPresence, UserID: userID, Online: h.sessions.Online(sessionID)})
+			}()
+
+			// 读取循环只用于发现连接关闭，客户端发送的消息被忽略
+			closed := make(chan struct{})
+			go func() {
+				defer close(closed)
+				var msg string
+				for websocket.Message.Receive(ws, &msg) == nil {
+				}
+			}()
+
+			for {
+				select {
+				case ev, ok := <-sub.send:
+					if !ok {
+						// 成员已被移出会话
+						return
+					}
+					_ = ws.SetWriteDeadline(time.Now().Add(10 * time.Second))
+					if err := websocket.JSON.Send(ws, ev); err != nil {
+						return
+					}
+				case <-closed:
+					return
+				}
+			}
+		},
+	}
+	server.ServeHTTP(c.Writer, c.Request)
+}
+
+// publishAnswer 将一轮完整回答推送给共享会话的在线成员
+func (h *LLMHandler) publishAnswer(sessionID, turnID, userID, answer string, sources []*llmpb.SourceReference, metadata map[string]string) {
+	h.sessions.Publish(sessionID, sessionEvent{
+		Type:     SessionEventAnswer,
+		TurnID:   turnID,
+		UserID:   userID,
+		Content:  answer,
+		Sources:  sources,
+		Metadata: metadata,
+	})
+}
@@ -0,0 +1,211 @@
+package handler
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/RigelNana/arkstudy/gateway/backend"
+	"github.com/RigelNana/arkstudy/gateway/middleware"
+	"github.com/RigelNana/arkstudy/pkg/registry"
+	studypb "github.com/RigelNana/arkstudy/proto/study"
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
+)
+
+type StudyHandler struct {
+	client studypb.StudyServiceClient
+}
+
+func NewStudyHandler(client studypb.StudyServiceClient) *StudyHandler {
+	return &StudyHandler{client: client}
+}
+
+// NewStudyServiceClient creates a gRPC client to study-service resolved through pkg/discovery
+func NewStudyServiceClient() studypb.StudyServiceClient {
+	return studypb.NewStudyServiceClient(backend.For(registry.Study))
+}
+
+func currentUserID(c *gin.Context) (string, bool) {
+	userIDInterface, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return "", false
+	}
+	userID, ok := userIDInterface.(string)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "invalid user_id format"})
+		return "", false
+	}
+	return userID, true
+}
+
+// StartSession 开始一次学习会话，之后前端需定期调用 heartbeat
+// POST /api/study/sessions  {"material_id": "..."}
+func (h *StudyHandler) StartSession(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	var req struct {
+		MaterialID string `json:"material_id"`
+	}
+	_ = c.ShouldBindJSON(&req)
+
+	resp, err := h.client.StartSession(middleware.RPCContext(c), &studypb.StartSessionRequest{UserId: userID, MaterialId: req.MaterialID})
+	if err != nil {
+		log.Printf("StartSession gRPC error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error", "detail": err.Error()})
+		return
+	}
+	if !resp.Success {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "failed to start session", "detail": resp.Message})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": resp.Session})
+}
+
+// Heartbeat 上报学习会话心跳
+// POST /api/study/sessions/:id/heartbeat
+func (h *StudyHandler) Heartbeat(c *gin.Context) {
+	h.sessionAction(c, "Heartbeat", h.client.Heartbeat)
+}
+
+// StopSession 结束学习会话
+// POST /api/study/sessions/:id/stop
+func (h *StudyHandler) StopSession(c *gin.Context) {
+	h.sessionAction(c, "StopSession", h.client.StopSession)
+}
+
+func (h *StudyHandler) sessionAction(c *gin.Context, name string, call func(ctx context.Context, in *studypb.SessionActionRequest, opts ...grpc.CallOption) (*studypb.SessionResponse, error)) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	sessionID := c.Param("id")
+	if sessionID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "session id is required"})
+		return
+	}
+
+	resp, err := call(middleware.RPCContext(c), &studypb.SessionActionRequest{SessionId: sessionID, UserId: userID})
+	if err != nil {
+		log.Printf("%s gRPC error: %v", name, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error", "detail": err.Error()})
+		return
+	}
+	if !resp.Success {
+		// 会话已结束或已过期时返回 409，前端应重新开始会话
+		status := http.StatusBadRequest
+		if resp.Session != nil {
+			status = http.StatusConflict
+		}
+		c.JSON(status, gin.H{"error": resp.Message, "data": resp.Session})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": resp.Session})
+}
+
+// GetStats 获取学习时长统计、目标完成情况与连续天数
+// GET /api/study/stats?days=7
+func (h *StudyHandler) GetStats(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	days, err := strconv.Atoi(c.DefaultQuery("days", "7"))
+	if err != nil || days <= 0 {
+		days = 7
+	}
+
+	resp, err := h.client.GetStudyStats(middleware.RPCContext(c), &studypb.GetStudyStatsRequest{UserId: userID, Days: int32(days)})
+	if err != nil {
+		log.Printf("GetStudyStats gRPC error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error", "detail": err.Error()})
+		return
+	}
+	if !resp.Success {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "failed to get study stats", "detail": resp.Message})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"today_seconds":     resp.TodaySeconds,
+			"week_seconds":      resp.WeekSeconds,
+			"total_seconds":     resp.TotalSeconds,
+			"daily":             resp.Daily,
+			"goals":             resp.Goals,
+			"study_streak_days": resp.StudyStreakDays,
+		},
+	})
+}
+
+// ListGoals 获取学习目标
+// GET /api/study/goals
+func (h *StudyHandler) ListGoals(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	resp, err := h.client.ListGoals(middleware.RPCContext(c), &studypb.ListGoalsRequest{UserId: userID})
+	if err != nil {
+		log.Printf("ListGoals gRPC error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error", "detail": err.Error()})
+		return
+	}
+	if !resp.Success {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "failed to list goals", "detail": resp.Message})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": resp.Goals})
+}
+
+// SetGoal 设置学习目标，period 为 daily 或 weekly
+// PUT /api/study/goals/:period  {"target_minutes": 30}
+func (h *StudyHandler) SetGoal(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	var req struct {
+		TargetMinutes int32 `json:"target_minutes" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.client.SetGoal(middleware.RPCContext(c), &studypb.SetGoalRequest{UserId: userID, Period: c.Param("period"), TargetMinutes: req.TargetMinutes})
+	if err != nil {
+		log.Printf("SetGoal gRPC error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error", "detail": err.Error()})
+		return
+	}
+	if !resp.Success {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "failed to set goal", "detail": resp.Message})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": resp.Goal})
+}
+
+// DeleteGoal 删除学习目标
+// DELETE /api/study/goals/:period
+func (h *StudyHandler) DeleteGoal(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	resp, err := h.client.DeleteGoal(middleware.RPCContext(c), &studypb.DeleteGoalRequest{UserId: userID, Period: c.Param("period")})
+	if err != nil {
+		log.Printf("DeleteGoal gRPC error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error", "detail": err.Error()})
+		return
+	}
+	if !resp.Success {
+		c.JSON(http.StatusNotFound, gin.H{"error": "failed to delete goal", "detail": resp.Message})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "message": resp.Message})
+}
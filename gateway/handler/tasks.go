@@ -0,0 +1,101 @@
+package handler
+
+import (
+	"context"
+	"log"
+	"os"
+	"time"
+
+	arkkafka "github.com/RigelNana/arkstudy/pkg/kafka"
+	kafkaMetrics "github.com/RigelNana/arkstudy/pkg/metrics/kafka"
+	"github.com/RigelNana/arkstudy/pkg/requestid"
+	"github.com/google/uuid"
+	kafka "github.com/segmentio/kafka-go"
+)
+
+// TaskRecorder 将 gateway 内执行的任务（出题、导出）的状态异步发布到 task.events，
+// 由 user-service 汇总到任务中心。与 ActivityRecorder 相同，未配置 KAFKA_BROKERS 时为空操作，
+// nil 接收者同样可以安全调用
+type TaskRecorder struct {
+	writer *kafka.Writer
+}
+
+// NewTaskRecorder 使用 env KAFKA_BROKERS 与 KAFKA_TOPIC_TASK_EVENTS（默认 task.events）创建记录器
+func NewTaskRecorder() *TaskRecorder {
+	brokers := arkkafka.SplitBrokers(os.Getenv("KAFKA_BROKERS"))
+	if len(brokers) == 0 {
+		log.Printf("KAFKA_BROKERS not set, task center recording disabled")
+		return &TaskRecorder{}
+	}
+	topic := os.Getenv("KAFKA_TOPIC_TASK_EVENTS")
+	if topic == "" {
+		topic = arkkafka.TaskEvents.Name
+	}
+	w := arkkafka.NewWriter(brokers, topic)
+	w.Async = true
+	w.Completion = func(messages []kafka.Message, err error) {
+		if err != nil {
+			log.Printf("Warning: failed to publish %d task events: %v", len(messages), err)
+		}
+	}
+	return &TaskRecorder{writer: kafkaMetrics.InstrumentWriter("gateway", w)}
+}
+
+// Task 一次执行中的任务，由 TaskRecorder.Start 创建
+type Task struct {
+	recorder *TaskRecorder
+	event    arkkafka.TaskEvent
+	// requestID 发起任务的请求 ID，随任务事件写入消息头，任务失败时可据此查日志
+	requestID string
+}
+
+// Start 登记一个运行中的任务并返回任务句柄，任务结束时调用 Finish
+func (r *TaskRecorder) Start(ctx context.Context, userID, kind, title, targetID string) *Task {
+	t := &Task{recorder: r, requestID: requestid.FromContext(ctx), event: arkkafka.TaskEvent{
+		TaskID:   uuid.New().String(),
+		UserID:   userID,
+		Kind:     kind,
+		Service:  "gateway",
+		Status:   arkkafka.TaskStatusRunning,
+		Title:    title,
+		TargetID: targetID,
+	}}
+	t.publish()
+	return t
+}
+
+// ID 任务中心中的任务 ID
+func (t *Task) ID() string {
+	return t.event.TaskID
+}
+
+// Finish 按 err 将任务标记为成功或失败，metadata 记录任务结果（如生成的题目数）
+func (t *Task) Finish(err error, metadata map[string]string) {
+	if t == nil {
+		return
+	}
+	t.event.Status = arkkafka.TaskStatusSucceeded
+	t.event.Progress = 1
+	if err != nil {
+		t.event.Status = arkkafka.TaskStatusFailed
+		t.event.Error = err.Error()
+	}
+	t.event.Metadata = metadata
+	t.publish()
+}
+
+func (t *Task) publish() {
+	r := t.recorder
+	if r == nil || r.writer == nil || t.event.UserID == "" {
+		return
+	}
+	t.event.OccurredAt = time.Now()
+	msg, err := arkkafka.TaskMessage(t.event)
+	if err != nil {
+		log.Printf("Warning: failed to marshal task event: %v", err)
+		return
+	}
+	if err := kafkaMetrics.WriteMessages(requestid.NewContext(context.Background(), t.requestID), r.writer, msg); err != nil {
+		log.Printf("Warning: failed to enqueue task event: %v", err)
+	}
+}
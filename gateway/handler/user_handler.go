@@ -1,11 +1,11 @@
 package handler
 
 import (
-	"context"
 	"log"
 	"net/http"
 	"strconv"
 
+	"github.com/RigelNana/arkstudy/gateway/middleware"
 	userpb "github.com/RigelNana/arkstudy/proto/user"
 	"github.com/gin-gonic/gin"
 )
@@ -29,7 +29,7 @@ func (h *UserHandler) GetUserByID(c *gin.Context) {
 
 	log.Printf("GetUserByID request: id=%s", id)
 
-	resp, err := h.userClient.GetUserByID(context.Background(), &userpb.GetUserByIDRequest{Id: id})
+	resp, err := h.userClient.GetUserByID(middleware.RPCContext(c), &userpb.GetUserByIDRequest{Id: id})
 	if err != nil {
 		log.Printf("GetUserByID gRPC error: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error", "detail": err.Error()})
@@ -60,7 +60,7 @@ func (h *UserHandler) GetUserByUsername(c *gin.Context) {
 
 	log.Printf("GetUserByUsername request: username=%s", username)
 
-	resp, err := h.userClient.GetUserByUsername(context.Background(), &userpb.GetUserByUsernameRequest{Username: username})
+	resp, err := h.userClient.GetUserByUsername(middleware.RPCContext(c), &userpb.GetUserByUsernameRequest{Username: username})
 	if err != nil {
 		log.Printf("GetUserByUsername gRPC error: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error", "detail": err.Error()})
@@ -91,7 +91,7 @@ func (h *UserHandler) GetUserByEmail(c *gin.Context) {
 
 	log.Printf("GetUserByEmail request: email=%s", email)
 
-	resp, err := h.userClient.GetUserByEmail(context.Background(), &userpb.GetUserByEmailRequest{Email: email})
+	resp, err := h.userClient.GetUserByEmail(middleware.RPCContext(c), &userpb.GetUserByEmailRequest{Email: email})
 	if err != nil {
 		log.Printf("GetUserByEmail gRPC error: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error", "detail": err.Error()})
@@ -133,7 +133,7 @@ func (h *UserHandler) ListUsers(c *gin.Context) {
 
 	log.Printf("ListUsers request: limit=%d, offset=%d", limit, offset)
 
-	resp, err := h.userClient.ListUsers(context.Background(), &userpb.ListUsersRequest{
+	resp, err := h.userClient.ListUsers(middleware.RPCContext(c), &userpb.ListUsersRequest{
 		Limit:  int32(limit),
 		Offset: int32(offset),
 	})
@@ -154,3 +154,193 @@ func (h *UserHandler) ListUsers(c *gin.Context) {
 		},
 	})
 }
+
+// ListMyActivity 获取当前用户的活动时间线（分页，按时间倒序）
+// GET /api/users/me/activity?type=upload&limit=20&offset=0
+func (h *UserHandler) ListMyActivity(c *gin.Context) {
+	userIDInterface, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+	userID, ok := userIDInterface.(string)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "invalid user_id format"})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit <= 0 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+	activityType := c.Query("type")
+
+	log.Printf("ListMyActivity request: userID=%s, type=%s, limit=%d, offset=%d", userID, activityType, limit, offset)
+
+	resp, err := h.userClient.ListUserActivity(middleware.RPCContext(c), &userpb.ListUserActivityRequest{
+		UserId: userID,
+		Type:   activityType,
+		Limit:  int32(limit),
+		Offset: int32(offset),
+	})
+	if err != nil {
+		log.Printf("ListMyActivity gRPC error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error", "detail": err.Error()})
+		return
+	}
+	if !resp.Success {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "failed to list activity", "detail": resp.Message})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"activities": resp.Activities,
+			"total":      resp.Total,
+			"limit":      limit,
+			"offset":     offset,
+		},
+	})
+}
+
+// ListMyTasks 获取当前用户在各服务中的异步任务（OCR、ASR、出题、导出），按更新时间倒序。
+// GET /api/tasks?kind=&status=&limit=&offset=
+func (h *UserHandler) ListMyTasks(c *gin.Context) {
+	userIDInterface, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+	userID, ok := userIDInterface.(string)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "invalid user_id format"})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit <= 0 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+	kind := c.Query("kind")
+	status := c.Query("status")
+
+	log.Printf("ListMyTasks request: userID=%s, kind=%s, status=%s, limit=%d, offset=%d", userID, kind, status, limit, offset)
+
+	resp, err := h.userClient.ListTasks(middleware.RPCContext(c), &userpb.ListTasksRequest{
+		UserId: userID,
+		Kind:   kind,
+		Status: status,
+		Limit:  int32(limit),
+		Offset: int32(offset),
+	})
+	if err != nil {
+		log.Printf("ListMyTasks gRPC error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error", "detail": err.Error()})
+		return
+	}
+	if !resp.Success {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "failed to list tasks", "detail": resp.Message})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"tasks":  resp.Tasks,
+			"total":  resp.Total,
+			"limit":  limit,
+			"offset": offset,
+		},
+	})
+}
+
+// SetDigestPreference 订阅或退订每周学习进度邮件
+// PUT /api/users/me/digest {"weekly_digest": true}
+func (h *UserHandler) SetDigestPreference(c *gin.Context) {
+	var req struct {
+		WeeklyDigest *bool `json:"weekly_digest" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input", "detail": err.Error()})
+		return
+	}
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	resp, err := h.userClient.SetDigestPreference(c.Request.Context(), &userpb.SetDigestPreferenceRequest{
+		UserId:       userID,
+		WeeklyDigest: *req.WeeklyDigest,
+	})
+	if err != nil {
+		log.Printf("SetDigestPreference gRPC error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error", "detail": err.Error()})
+		return
+	}
+	if !resp.Found {
+		c.JSON(http.StatusNotFound, gin.H{"error": "user not found", "detail": resp.Message})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    resp.User,
+	})
+}
+
+// SetLocalePreference 设置时区与语言，用于格式化答题记录、错题、周报等时间；传空串清除，改为按请求头决定
+// PUT /api/users/me/locale {"timezone": "Asia/Shanghai", "language": "zh-CN"}
+func (h *UserHandler) SetLocalePreference(c *gin.Context) {
+	var req struct {
+		Timezone string `json:"timezone"`
+		Language string `json:"language"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input", "detail": err.Error()})
+		return
+	}
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	resp, err := h.userClient.SetLocalePreference(c.Request.Context(), &userpb.SetLocalePreferenceRequest{
+		UserId:   userID,
+		Timezone: req.Timezone,
+		Language: req.Language,
+	})
+	if err != nil {
+		log.Printf("SetLocalePreference gRPC error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error", "detail": err.Error()})
+		return
+	}
+	if !resp.Found {
+		status := http.StatusNotFound
+		if resp.Message == "invalid timezone" || resp.Message == "unsupported language" {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, gin.H{"error": "set locale failed", "detail": resp.Message})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    resp.User,
+	})
+}
@@ -4,73 +4,78 @@ import (
 	"log"
 	"os"
 
+	"github.com/RigelNana/arkstudy/gateway/export"
 	"github.com/RigelNana/arkstudy/gateway/handler"
 	"github.com/RigelNana/arkstudy/gateway/router"
 	"github.com/RigelNana/arkstudy/pkg/metrics"
-	"github.com/gin-gonic/gin"
-	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/RigelNana/arkstudy/pkg/registry"
 	"github.com/sirupsen/logrus"
 )
 
 func main() {
-	// 启动 Prometheus metrics 服务器
-	metrics.StartMetricsServer("2112")
-	log.Printf("Prometheus metrics server started on :2112")
+	// 启动内部 HTTP 监听：/metrics 与 /healthz、/readyz，地址由 METRICS_ADDR 配置；主端口不再暴露 /metrics
+	log.Printf("Prometheus metrics server started on %s", metrics.StartMetricsServer())
+
+	if err := registry.ValidateClients(registry.Auth, registry.User, registry.Material, registry.LLM, registry.OCR, registry.Quiz, registry.ASR, registry.Study); err != nil {
+		log.Fatalf("%v", err)
+	}
 
 	authClient := handler.NewAuthServiceClient()
 	userClient := handler.NewUserServiceClient()
 	materialClient := handler.NewMaterialServiceClient()
 	llmClient := handler.NewLLMServiceClient()
 
-	authHandler := handler.NewAuthHandler(authClient, userClient)
+	// 用户活动经 Kafka 写入 user-service 的活动时间线
+	activity := handler.NewActivityRecorder()
+	// gateway 内执行的出题、导出任务经 Kafka 登记到 user-service 的任务中心
+	tasks := handler.NewTaskRecorder()
+
+	// 批量开通账号的邀请邮件经 Kafka 交给通知服务发送
+	authHandler := handler.NewAuthHandler(authClient, userClient, handler.NewInvitationSender())
 	userHandler := handler.NewUserHandler(userClient)
-	materialHandler := handler.NewMaterialHandler(materialClient)
-	llmHandler := handler.NewLLMHandler(llmClient)
+	materialHandler := handler.NewMaterialHandler(materialClient, userClient, activity)
+	llmHandler := handler.NewLLMHandler(llmClient, activity, handler.NewSessionHub(), materialClient)
 
 	// 初始化 Quiz Handler
 	logger := logrus.New()
-	quizServiceAddr := os.Getenv("QUIZ_SERVICE_ADDR")
-	if quizServiceAddr == "" {
-		// 检查K8s环境变量
-		quizHost := os.Getenv("ARKSTUDY_QUIZ_SERVICE_SERVICE_HOST")
-		quizPort := os.Getenv("ARKSTUDY_QUIZ_SERVICE_SERVICE_PORT")
-		if quizHost != "" && quizPort != "" {
-			quizServiceAddr = quizHost + ":" + quizPort
-		} else {
-			quizServiceAddr = "quiz-service:50056" // 使用正确的端口
-		}
-	}
-	log.Printf("Quiz service address: %s", quizServiceAddr)
-	quizHandler := handler.NewQuizHandler(quizServiceAddr, logger)
+	quizHandler := handler.NewQuizHandler(logger, activity, tasks)
 
 	// 初始化 ASR Handler
-	asrServiceAddr := os.Getenv("ASR_SERVICE_ADDR")
-	if asrServiceAddr == "" {
-		// 检查K8s环境变量
-		asrHost := os.Getenv("ARKSTUDY_ASR_SERVICE_SERVICE_HOST")
-		asrPort := os.Getenv("ARKSTUDY_ASR_SERVICE_SERVICE_PORT")
-		if asrHost != "" && asrPort != "" {
-			asrServiceAddr = asrHost + ":" + asrPort
-		} else {
-			asrServiceAddr = "asr-service:50057"
-		}
-	}
-	log.Printf("ASR service address: %s", asrServiceAddr)
-	asrHandler := handler.NewASRHandler(asrServiceAddr, logger)
+	asrHandler := handler.NewASRHandler(logger)
 
 	// 初始化 OCR Handler
 	ocrHandler := handler.NewOCRHandler()
 
-	r := router.Setup(authHandler, userHandler, materialHandler, llmHandler, quizHandler, asrHandler, ocrHandler)
+	studyHandler := handler.NewStudyHandler(handler.NewStudyServiceClient())
+
+	// 学习笔记导出汇总资料、章节摘要、题目与 LLM 摘要
+	exportHandler := handler.NewExportHandler(export.NewCollector(
+		materialClient,
+		llmClient,
+		handler.NewQuizServiceClient(),
+		handler.NewASRServiceClient(),
+	), tasks)
+
+	// 可选的 GraphQL 组合查询接口
+	var graphqlHandler *handler.GraphQLHandler
+	if handler.GraphQLEnabled() {
+		graphqlHandler = handler.NewGraphQLHandler(userClient, materialClient, handler.NewQuizServiceClient(), handler.NewASRServiceClient())
+	}
+
+	// 以 gRPC-Web / Connect 开放给前端的后端方法
+	rpcWebHandler, err := handler.NewRPCWebHandler()
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
 
-	// 添加 /metrics 端点到主服务器
-	r.GET("/metrics", gin.WrapH(promhttp.Handler()))
+	r := router.Setup(authHandler, userHandler, materialHandler, llmHandler, quizHandler, asrHandler, ocrHandler, studyHandler, exportHandler, graphqlHandler, rpcWebHandler)
 
 	port := os.Getenv("GATEWAY_PORT")
 	if port == "" {
 		port = "8080"
 	}
 	log.Printf("Gateway listening on %s", port)
+	metrics.SetReady(true)
 	if err := r.Run(":" + port); err != nil {
 		log.Fatalf("gateway failed: %v", err)
 	}
@@ -1,40 +1,40 @@
 package middleware
 
 import (
-	"context"
+	"log"
 	"net/http"
-	"os"
 	"strings"
 
+	"github.com/RigelNana/arkstudy/gateway/backend"
+	"github.com/RigelNana/arkstudy/pkg/registry"
 	authpb "github.com/RigelNana/arkstudy/proto/auth"
 
 	"github.com/gin-gonic/gin"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 )
 
-// AuthValidator 负责与 auth-service 通信
+// AuthValidator 负责与 auth-service 通信，启用本地验签时优先使用 JWKS 公钥校验
 type AuthValidator struct {
 	client authpb.AuthServiceClient
+	local  *localVerifier
 }
 
 func NewAuthValidator() *AuthValidator {
-	addr := os.Getenv("AUTH_GRPC_ADDR")
-	if addr == "" {
-		addr = "localhost:50051"
+	v := &AuthValidator{client: authpb.NewAuthServiceClient(backend.For(registry.Auth))}
+	if cfg := LoadLocalJWTConfig(); cfg.Enabled {
+		log.Printf("Local JWT validation enabled: jwks refresh=%s, revocation cache ttl=%s", cfg.RefreshInterval, cfg.RevocationTTL)
+		v.local = newLocalVerifier(v.client, cfg)
 	}
-
-	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
-	if err != nil {
-		panic("failed to dial auth-service: " + err.Error())
-	}
-	return &AuthValidator{client: authpb.NewAuthServiceClient(conn)}
+	return v
 }
 
-// JWTAuth 中间件：提取 Bearer token -> 远程 ValidateToken -> 注入 user_id
+// JWTAuth 中间件：提取 Bearer token -> 本地验签（可选）或远程 ValidateToken -> 注入 user_id
 func (v *AuthValidator) JWTAuth() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		header := c.GetHeader("Authorization")
+		// 浏览器无法为 WebSocket 握手设置请求头，升级请求允许通过 access_token 查询参数传递 token
+		if header == "" && strings.EqualFold(c.GetHeader("Upgrade"), "websocket") {
+			header = c.Query(accessTokenParam)
+		}
 		if header == "" {
 			unauthorized(c, "missing Authorization header")
 			return
@@ -47,12 +47,64 @@ func (v *AuthValidator) JWTAuth() gin.HandlerFunc {
 			unauthorized(c, "empty bearer token")
 			return
 		}
-		resp, err := v.client.ValidateToken(context.Background(), &authpb.ValidateTokenRequest{Token: token})
+		if v.local != nil {
+			if id, valid, handled, err := v.local.validate(c.Request.Context(), token, c.ClientIP()); handled {
+				if err != nil {
+					c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+					c.Abort()
+					return
+				}
+				if !valid {
+					unauthorized(c, "invalid token")
+					return
+				}
+				setIdentity(c, id)
+				c.Next()
+				return
+			}
+		}
+		resp, err := v.client.ValidateToken(RPCContext(c), &authpb.ValidateTokenRequest{Token: token, Ip: c.ClientIP()})
 		if err != nil || !resp.Valid {
 			unauthorized(c, "invalid token")
 			return
 		}
-		c.Set("user_id", resp.UserId)
+		setIdentity(c, identityFromResponse(resp))
+		c.Next()
+	}
+}
+
+// identity token 校验通过后的身份
+type identity struct {
+	userID         string
+	sessionID      string // 旧 token 没有会话
+	impersonatorID string // 管理员代登录时非空
+}
+
+func identityFromResponse(resp *authpb.ValidateTokenResponse) identity {
+	return identity{userID: resp.UserId, sessionID: resp.SessionId, impersonatorID: resp.ImpersonatorId}
+}
+
+// setIdentity 注入 user_id、session_id 与 impersonator_id。代登录 token 的每个请求都记录审计日志
+func setIdentity(c *gin.Context, id identity) {
+	c.Set("user_id", id.userID)
+	if id.sessionID != "" {
+		c.Set("session_id", id.sessionID)
+	}
+	if id.impersonatorID != "" {
+		c.Set("impersonator_id", id.impersonatorID)
+		log.Printf("impersonation audit: admin %s as user %s, session=%s %s %s from %s",
+			id.impersonatorID, id.userID, id.sessionID, c.Request.Method, c.Request.URL.Path, c.ClientIP())
+	}
+}
+
+// DenyImpersonation 拒绝代登录 token 访问的接口（修改密码、注销设备、再次代登录等敏感操作），需挂在 JWTAuth 之后
+func DenyImpersonation() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetString("impersonator_id") != "" {
+			c.JSON(http.StatusForbidden, gin.H{"error": "not allowed while impersonating"})
+			c.Abort()
+			return
+		}
 		c.Next()
 	}
 }
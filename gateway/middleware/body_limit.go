@@ -0,0 +1,163 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BodyLimitConfig 请求体限制，0 表示不限制
+type BodyLimitConfig struct {
+	MaxBytes       int64 // 未单独登记的路由的请求体上限
+	MaxUploadBytes int64 // 资料上传的请求体上限，文件内容流式转发，不占用 gateway 内存
+	MaxJSONDepth   int   // JSON 请求体允许的最大嵌套层数
+}
+
+// LoadBodyLimitConfig 从环境变量读取请求体限制：MAX_BODY_BYTES（默认 1MiB）、
+// MAX_UPLOAD_BYTES（默认 2GiB）、MAX_JSON_DEPTH（默认 32）
+func LoadBodyLimitConfig() BodyLimitConfig {
+	return BodyLimitConfig{
+		MaxBytes:       getEnvInt64("MAX_BODY_BYTES", 1<<20),
+		MaxUploadBytes: getEnvInt64("MAX_UPLOAD_BYTES", 2<<30),
+		MaxJSONDepth:   int(getEnvInt64("MAX_JSON_DEPTH", 32)),
+	}
+}
+
+// BodyLimiter 限制请求体大小，并在转交 handler 前检查 JSON 请求体：嵌套超过 MaxJSONDepth 或对象中有重复键时返回 400。
+// encoding/json 对重复键静默取最后一个值，前后端解析结果可能不一致，因此直接拒绝
+type BodyLimiter struct {
+	cfg    BodyLimitConfig
+	routes map[string]int64
+}
+
+func NewBodyLimiter(cfg BodyLimitConfig) *BodyLimiter {
+	return &BodyLimiter{cfg: cfg, routes: map[string]int64{}}
+}
+
+// MaxUploadBytes 资料上传的请求体上限
+func (l *BodyLimiter) MaxUploadBytes() int64 { return l.cfg.MaxUploadBytes }
+
+// Route 为路由（gin 的完整路径，如 /api/materials/upload）单独设置请求体上限，0 表示不限制
+func (l *BodyLimiter) Route(fullPath string, maxBytes int64) {
+	l.routes[fullPath] = maxBytes
+}
+
+// Middleware 需注册在路由匹配之后生效的位置（Engine.Use 或路由组），以便按 FullPath 查找单独设置的上限
+func (l *BodyLimiter) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Body == nil || c.Request.Body == http.NoBody {
+			c.Next()
+			return
+		}
+		limit, ok := l.routes[c.FullPath()]
+		if !ok {
+			limit = l.cfg.MaxBytes
+		}
+		if limit > 0 {
+			if c.Request.ContentLength > limit {
+				tooLarge(c, limit)
+				return
+			}
+			c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, limit)
+		}
+		if !isJSON(c.ContentType()) {
+			c.Next()
+			return
+		}
+
+		// JSON 请求体在上限内整体读入，检查后交还给 handler 解析
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			var maxErr *http.MaxBytesError
+			if errors.As(err, &maxErr) {
+				tooLarge(c, limit)
+				return
+			}
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "failed to read request body", "detail": err.Error()})
+			return
+		}
+		if err := checkJSON(body, l.cfg.MaxJSONDepth); err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid JSON body", "detail": err.Error()})
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		c.Next()
+	}
+}
+
+func tooLarge(c *gin.Context, limit int64) {
+	c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, gin.H{
+		"error":  "request body too large",
+		"detail": fmt.Sprintf("request body must not exceed %d bytes", limit),
+	})
+}
+
+func isJSON(contentType string) bool {
+	mt, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mt = contentType
+	}
+	return mt == "application/json" || strings.HasSuffix(mt, "+json")
+}
+
+// jsonFrame 扫描 JSON 时的一层容器，数组层的 keys 为 nil
+type jsonFrame struct {
+	keys    map[string]bool
+	wantKey bool
+}
+
+// checkJSON 逐个 token 扫描 body，检查嵌套层数与对象内的重复键。
+// 语法错误不在此处报告，留给 handler 按各自的格式返回
+func checkJSON(body []byte, maxDepth int) error {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	var stack []jsonFrame
+	// valueDone 一个值读取完毕，所在对象的下一个 token 应为键
+	valueDone := func() {
+		if n := len(stack); n > 0 && stack[n-1].keys != nil {
+			stack[n-1].wantKey = true
+		}
+	}
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil
+		}
+		if n := len(stack); n > 0 && stack[n-1].wantKey {
+			if tok == json.Delim('}') {
+				stack = stack[:n-1]
+				valueDone()
+				continue
+			}
+			key, _ := tok.(string)
+			if stack[n-1].keys[key] {
+				return fmt.Errorf("duplicate key %q", key)
+			}
+			stack[n-1].keys[key] = true
+			stack[n-1].wantKey = false
+			continue
+		}
+		switch tok {
+		case json.Delim('{'), json.Delim('['):
+			if maxDepth > 0 && len(stack) >= maxDepth {
+				return fmt.Errorf("JSON nesting exceeds %d levels", maxDepth)
+			}
+			frame := jsonFrame{}
+			if tok == json.Delim('{') {
+				frame = jsonFrame{keys: map[string]bool{}, wantKey: true}
+			}
+			stack = append(stack, frame)
+		case json.Delim(']'):
+			stack = stack[:len(stack)-1]
+			valueDone()
+		default:
+			valueDone()
+		}
+	}
+}
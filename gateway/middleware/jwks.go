@@ -0,0 +1,267 @@
+package middleware
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"sync"
+	"time"
+
+	authpb "github.com/RigelNana/arkstudy/proto/auth"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	// jwksMinRefreshGap 遇到未知 kid 时按需刷新的最小间隔，避免伪造 kid 的请求打满 auth-service
+	jwksMinRefreshGap = 30 * time.Second
+	// revocationCacheMax 吊销检查缓存条目上限，超过时清理过期条目
+	revocationCacheMax = 10000
+)
+
+// errRevocationCheckUnavailable 本地验签通过但无法向 auth-service 确认 token 未被吊销
+var errRevocationCheckUnavailable = errors.New("token revocation check unavailable, try again later")
+
+// LocalJWTConfig gateway 本地验签配置
+type LocalJWTConfig struct {
+	Enabled         bool
+	RefreshInterval time.Duration // 定期从 auth-service 拉取 JWKS 的间隔
+	RevocationTTL   time.Duration // 吊销检查结果的缓存时间，即修改密码或注销会话后旧 token 最长仍可使用的时间
+}
+
+// LoadLocalJWTConfig 从环境变量读取本地验签配置：
+// GATEWAY_JWT_LOCAL_VALIDATION（默认 false）、JWKS_REFRESH_INTERVAL（默认 5m）、
+// JWT_REVOCATION_CACHE_TTL（默认 30s）
+func LoadLocalJWTConfig() LocalJWTConfig {
+	return LocalJWTConfig{
+		Enabled:         getEnv("GATEWAY_JWT_LOCAL_VALIDATION", "false") == "true",
+		RefreshInterval: getEnvDuration("JWKS_REFRESH_INTERVAL", 5*time.Minute),
+		RevocationTTL:   getEnvDuration("JWT_REVOCATION_CACHE_TTL", 30*time.Second),
+	}
+}
+
+type tokenClaims struct {
+	UserID       string `json:"user_id"`
+	Impersonator string `json:"impersonator,omitempty"`
+	jwt.RegisteredClaims
+}
+
+type verificationKey struct {
+	alg string
+	key interface{}
+}
+
+type revocationEntry struct {
+	identity identity
+	valid    bool
+	expires  time.Time
+}
+
+// localVerifier 使用 auth-service 发布的公钥在本地校验 token 签名与有效期，
+// 吊销（修改密码、注销会话）检查仍由 ValidateToken 完成，结果按 token 缓存 RevocationTTL。
+// HS256 token、未知 kid 或尚未取得公钥时交回远程校验
+type localVerifier struct {
+	client authpb.AuthServiceClient
+	cfg    LocalJWTConfig
+
+	mu          sync.RWMutex
+	keys        map[string]verificationKey
+	lastRefresh time.Time
+	refreshMu   sync.Mutex
+
+	cacheMu sync.Mutex
+	cache   map[[sha256.Size]byte]revocationEntry
+}
+
+func newLocalVerifier(client authpb.AuthServiceClient, cfg LocalJWTConfig) *localVerifier {
+	v := &localVerifier{
+		client: client,
+		cfg:    cfg,
+		keys:   map[string]verificationKey{},
+		cache:  map[[sha256.Size]byte]revocationEntry{},
+	}
+	if err := v.refresh(); err != nil {
+		log.Printf("Warning: initial JWKS fetch failed, falling back to remote token validation: %v", err)
+	}
+	go func() {
+		ticker := time.NewTicker(cfg.RefreshInterval)
+		defer ticker.Stop()
+		for range ticker.C {
+			if err := v.refresh(); err != nil {
+				log.Printf("Warning: JWKS refresh failed, keeping %d cached keys: %v", v.keyCount(), err)
+			}
+			v.pruneCache()
+		}
+	}()
+	return v
+}
+
+// refresh 拉取 JWKS 并替换本地公钥，失败时保留已有公钥
+func (v *localVerifier) refresh() error {
+	v.refreshMu.Lock()
+	defer v.refreshMu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	resp, err := v.client.GetJWKS(ctx, &authpb.GetJWKSRequest{})
+	if err != nil {
+		return err
+	}
+	keys := make(map[string]verificationKey, len(resp.Keys))
+	for _, k := range resp.Keys {
+		key, err := parseJWK(k)
+		if err != nil {
+			log.Printf("Warning: skipping JWK %q: %v", k.Kid, err)
+			continue
+		}
+		keys[k.Kid] = key
+	}
+	v.mu.Lock()
+	v.keys = keys
+	v.lastRefresh = time.Now()
+	v.mu.Unlock()
+	return nil
+}
+
+func (v *localVerifier) keyCount() int {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	return len(v.keys)
+}
+
+// lookup 查找 kid 对应的公钥，未找到时在限频范围内刷新一次 JWKS（auth-service 可能刚轮换密钥）
+func (v *localVerifier) lookup(kid string) (verificationKey, bool) {
+	v.mu.RLock()
+	key, ok := v.keys[kid]
+	stale := time.Since(v.lastRefresh) >= jwksMinRefreshGap
+	v.mu.RUnlock()
+	if ok || !stale {
+		return key, ok
+	}
+	if err := v.refresh(); err != nil {
+		log.Printf("Warning: JWKS refresh for unknown kid failed: %v", err)
+		return verificationKey{}, false
+	}
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	key, ok = v.keys[kid]
+	return key, ok
+}
+
+// validate 本地校验 token，ip 随吊销检查转发给 auth-service 更新会话活动信息。
+// handled 为 false 时调用方应改用远程校验；吊销检查失败时返回 errRevocationCheckUnavailable
+func (v *localVerifier) validate(ctx context.Context, token, ip string) (id identity, valid, handled bool, err error) {
+	unverified, _, err := jwt.NewParser().ParseUnverified(token, &tokenClaims{})
+	if err != nil {
+		return identity{}, false, true, nil
+	}
+	kid, _ := unverified.Header["kid"].(string)
+	if kid == "" {
+		// 对称签名的 token 无法在 gateway 校验
+		return identity{}, false, false, nil
+	}
+	key, ok := v.lookup(kid)
+	if !ok {
+		return identity{}, false, false, nil
+	}
+
+	claims := &tokenClaims{}
+	parsed, err := jwt.ParseWithClaims(token, claims, func(*jwt.Token) (interface{}, error) {
+		return key.key, nil
+	}, jwt.WithValidMethods([]string{key.alg}), jwt.WithExpirationRequired())
+	if err != nil || !parsed.Valid || claims.UserID == "" {
+		return identity{}, false, true, nil
+	}
+
+	sum := sha256.Sum256([]byte(token))
+	now := time.Now()
+	v.cacheMu.Lock()
+	entry, ok := v.cache[sum]
+	v.cacheMu.Unlock()
+	if ok && now.Before(entry.expires) {
+		return entry.identity, entry.valid, true, nil
+	}
+
+	resp, err := v.client.ValidateToken(ctx, &authpb.ValidateTokenRequest{Token: token, Ip: ip})
+	if err != nil {
+		// 无法确认 token 未被吊销（登出、撤销会话）时拒绝请求，而不是以本地验签结果放行
+		log.Printf("Warning: token revocation check failed for user %s: %v", claims.UserID, err)
+		return identity{}, false, true, errRevocationCheckUnavailable
+	}
+	entry = revocationEntry{identity: identityFromResponse(resp), valid: resp.Valid, expires: now.Add(v.cfg.RevocationTTL)}
+	if exp := claims.ExpiresAt.Time; exp.Before(entry.expires) {
+		entry.expires = exp
+	}
+	v.cacheMu.Lock()
+	if len(v.cache) >= revocationCacheMax {
+		v.pruneLocked(now)
+	}
+	v.cache[sum] = entry
+	v.cacheMu.Unlock()
+	return entry.identity, entry.valid, true, nil
+}
+
+func (v *localVerifier) pruneCache() {
+	v.cacheMu.Lock()
+	defer v.cacheMu.Unlock()
+	v.pruneLocked(time.Now())
+}
+
+func (v *localVerifier) pruneLocked(now time.Time) {
+	for k, e := range v.cache {
+		if !now.Before(e.expires) {
+			delete(v.cache, k)
+		}
+	}
+	// 仍然过多时整体清空，下一次请求重新检查
+	if len(v.cache) >= revocationCacheMax {
+		clear(v.cache)
+	}
+}
+
+// parseJWK 将 JWKS 中的公钥转换为验签使用的密钥，支持 Ed25519（OKP）与 RSA
+func parseJWK(k *authpb.JSONWebKey) (verificationKey, error) {
+	switch k.Kty {
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return verificationKey{}, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return verificationKey{}, errors.New("invalid Ed25519 public key")
+		}
+		return verificationKey{alg: jwt.SigningMethodEdDSA.Alg(), key: ed25519.PublicKey(x)}, nil
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return verificationKey{}, errors.New("invalid RSA modulus")
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil || len(e) == 0 || len(e) > 4 {
+			return verificationKey{}, errors.New("invalid RSA exponent")
+		}
+		pub := &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+		if pub.N.BitLen() < 2048 {
+			return verificationKey{}, errors.New("RSA key shorter than 2048 bits")
+		}
+		return verificationKey{alg: jwt.SigningMethodRS256.Alg(), key: pub}, nil
+	default:
+		return verificationKey{}, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
+
+func getEnvDuration(key string, fallback time.Duration) time.Duration {
+	if v := getEnv(key, ""); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d > 0 {
+			return d
+		}
+		log.Printf("invalid %s=%q, using %s", key, v, fallback)
+	}
+	return fallback
+}
@@ -0,0 +1,97 @@
+package middleware
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/RigelNana/arkstudy/gateway/backend"
+	"github.com/RigelNana/arkstudy/pkg/locale"
+	"github.com/RigelNana/arkstudy/pkg/registry"
+	userpb "github.com/RigelNana/arkstudy/proto/user"
+
+	"github.com/gin-gonic/gin"
+)
+
+// localeCacheMax 用户偏好缓存条目上限，超过时整体清空
+const localeCacheMax = 10000
+
+type localeEntry struct {
+	timezone, language string
+	expires            time.Time
+}
+
+// LocaleResolver 解析请求的时区与语言：用户在个人设置中保存的偏好优先，
+// 其次为 X-Timezone（IANA 时区名，前端取浏览器时区）与 Accept-Language 请求头，都没有时为 UTC / zh-CN。
+// 偏好按用户缓存 LOCALE_CACHE_TTL（默认 1m），修改后最长在该时间后生效
+type LocaleResolver struct {
+	users userpb.UserServiceClient
+	ttl   time.Duration
+
+	mu    sync.Mutex
+	cache map[string]localeEntry
+}
+
+func NewLocaleResolver() *LocaleResolver {
+	return &LocaleResolver{
+		users: userpb.NewUserServiceClient(backend.For(registry.User)),
+		ttl:   getEnvDuration("LOCALE_CACHE_TTL", time.Minute),
+		cache: map[string]localeEntry{},
+	}
+}
+
+// Middleware 将解析结果写入请求 context，handler 序列化响应中的时间时按其时区格式化。
+// 需放在 JWTAuth 之后才能读取用户偏好
+func (r *LocaleResolver) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		l := locale.Default
+		if tz := c.GetHeader("X-Timezone"); locale.ValidTimezone(tz) {
+			l.Timezone = tz
+		}
+		if lang := locale.NormalizeLanguage(c.GetHeader("Accept-Language")); lang != "" {
+			l.Language = lang
+		}
+		if userID := c.GetString("user_id"); userID != "" {
+			pref := r.preference(c.Request.Context(), userID)
+			if pref.timezone != "" {
+				l.Timezone = pref.timezone
+			}
+			if pref.language != "" {
+				l.Language = pref.language
+			}
+		}
+		c.Request = c.Request.WithContext(locale.NewContext(c.Request.Context(), l))
+		c.Next()
+	}
+}
+
+// preference 读取用户偏好，user-service 不可用时按未设置处理且不缓存
+func (r *LocaleResolver) preference(ctx context.Context, userID string) localeEntry {
+	now := time.Now()
+	r.mu.Lock()
+	entry, ok := r.cache[userID]
+	r.mu.Unlock()
+	if ok && now.Before(entry.expires) {
+		return entry
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+	resp, err := r.users.GetUserByID(ctx, &userpb.GetUserByIDRequest{Id: userID})
+	if err != nil {
+		log.Printf("Warning: load locale preference of user %s failed: %v", userID, err)
+		return localeEntry{}
+	}
+	entry = localeEntry{expires: now.Add(r.ttl)}
+	if resp.Found {
+		entry.timezone, entry.language = resp.User.GetTimezone(), resp.User.GetLanguage()
+	}
+	r.mu.Lock()
+	if len(r.cache) >= localeCacheMax {
+		clear(r.cache)
+	}
+	r.cache[userID] = entry
+	r.mu.Unlock()
+	return entry
+}
@@ -0,0 +1,226 @@
+package middleware
+
+import (
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TokensUsedKey 处理函数通过 c.Set(TokensUsedKey, int64) 上报本次请求实际消耗的 token 数，
+// 由配额中间件在请求结束后计入当日用量
+const TokensUsedKey = "quota_tokens_used"
+
+// QuotaConfig 每用户每日 LLM 配额，0 表示不限制
+type QuotaConfig struct {
+	Enabled       bool
+	DailyRequests int64
+	DailyTokens   int64
+	// 出题接口每次固定计入的 token 数（quiz-service 不回报实际用量，按经验值估算）
+	QuizGenerateTokens int64
+	Location           *time.Location // 按该时区的自然日重置
+}
+
+// LoadQuotaConfig 从环境变量读取配额配置：
+// AI_QUOTA_ENABLED（默认 true）、AI_QUOTA_DAILY_REQUESTS（默认 200）、
+// AI_QUOTA_DAILY_TOKENS（默认 200000）、AI_QUOTA_QUIZ_GENERATE_TOKENS（默认 2000）、
+// AI_QUOTA_TIMEZONE（默认 Asia/Shanghai）
+func LoadQuotaConfig() QuotaConfig {
+	loc, err := time.LoadLocation(getEnv("AI_QUOTA_TIMEZONE", "Asia/Shanghai"))
+	if err != nil {
+		log.Printf("invalid AI_QUOTA_TIMEZONE, falling back to UTC: %v", err)
+		loc = time.UTC
+	}
+	return QuotaConfig{
+		Enabled:            getEnv("AI_QUOTA_ENABLED", "true") != "false",
+		DailyRequests:      getEnvInt64("AI_QUOTA_DAILY_REQUESTS", 200),
+		DailyTokens:        getEnvInt64("AI_QUOTA_DAILY_TOKENS", 200000),
+		QuizGenerateTokens: getEnvInt64("AI_QUOTA_QUIZ_GENERATE_TOKENS", 2000),
+		Location:           loc,
+	}
+}
+
+// QuotaUsage 某用户当日已用量
+type QuotaUsage struct {
+	Requests int64
+	Tokens   int64
+}
+
+// QuotaStore 配额计数存储。默认实现为进程内存，多副本部署时各副本独立计数，
+// 需要全局精确配额时可替换为共享存储（如 Redis）实现
+type QuotaStore interface {
+	// Reserve 在请求数未达 maxRequests（0 为不限）且 token 未达 maxTokens（0 为不限）时占用一次请求额度
+	Reserve(userID, day string, maxRequests, maxTokens int64) (QuotaUsage, bool)
+	// Release 归还 Reserve 占用的请求额度（请求失败时调用）
+	Release(userID, day string)
+	AddTokens(userID, day string, tokens int64) QuotaUsage
+}
+
+type memoryQuotaStore struct {
+	mu    sync.Mutex
+	day   string
+	usage map[string]*QuotaUsage
+}
+
+// NewMemoryQuotaStore 进程内配额存储，跨日时整体清空
+func NewMemoryQuotaStore() QuotaStore {
+	return &memoryQuotaStore{usage: map[string]*QuotaUsage{}}
+}
+
+func (s *memoryQuotaStore) entry(userID, day string) *QuotaUsage {
+	if s.day != day {
+		s.day = day
+		s.usage = map[string]*QuotaUsage{}
+	}
+	u, ok := s.usage[userID]
+	if !ok {
+		u = &QuotaUsage{}
+		s.usage[userID] = u
+	}
+	return u
+}
+
+func (s *memoryQuotaStore) Reserve(userID, day string, maxRequests, maxTokens int64) (QuotaUsage, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	u := s.entry(userID, day)
+	if (maxRequests > 0 && u.Requests >= maxRequests) || (maxTokens > 0 && u.Tokens >= maxTokens) {
+		return *u, false
+	}
+	u.Requests++
+	return *u, true
+}
+
+func (s *memoryQuotaStore) Release(userID, day string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if u := s.entry(userID, day); u.Requests > 0 {
+		u.Requests--
+	}
+}
+
+func (s *memoryQuotaStore) AddTokens(userID, day string, tokens int64) QuotaUsage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	u := s.entry(userID, day)
+	u.Tokens += tokens
+	return *u
+}
+
+// QuotaLimiter 按用户限制每日 LLM 请求数与 token 数，与按 IP 的通用限流相互独立
+type QuotaLimiter struct {
+	cfg   QuotaConfig
+	store QuotaStore
+	now   func() time.Time
+}
+
+func NewQuotaLimiter(cfg QuotaConfig, store QuotaStore) *QuotaLimiter {
+	if cfg.Location == nil {
+		cfg.Location = time.UTC
+	}
+	return &QuotaLimiter{cfg: cfg, store: store, now: time.Now}
+}
+
+// QuizGenerateTokens 出题接口的固定 token 计费
+func (q *QuotaLimiter) QuizGenerateTokens() int64 {
+	return q.cfg.QuizGenerateTokens
+}
+
+// Limit 返回配额中间件，需挂在 JWTAuth 之后。
+// fixedTokens 为每次请求固定计入的 token 数，用于上游不回报用量的接口（如出题）；
+// 处理函数通过 TokensUsedKey 上报的用量会额外累加。
+// 返回 4xx/5xx 的请求归还请求额度且不计固定 token，但处理函数已上报的 token 仍计入
+func (q *QuotaLimiter) Limit(fixedTokens int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !q.cfg.Enabled {
+			c.Next()
+			return
+		}
+		userID := c.GetString("user_id")
+		if userID == "" {
+			c.Next()
+			return
+		}
+
+		now := q.now().In(q.cfg.Location)
+		day := now.Format("2006-01-02")
+		y, m, d := now.Date()
+		resetAt := time.Date(y, m, d+1, 0, 0, 0, 0, q.cfg.Location)
+
+		usage, ok := q.store.Reserve(userID, day, q.cfg.DailyRequests, q.cfg.DailyTokens)
+		q.setHeaders(c, usage, resetAt)
+		if !ok {
+			reason := "daily request quota exceeded"
+			if q.cfg.DailyTokens > 0 && usage.Tokens >= q.cfg.DailyTokens {
+				reason = "daily token quota exceeded"
+			}
+			c.Header("Retry-After", strconv.FormatInt(int64(resetAt.Sub(now).Seconds())+1, 10))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
+				"error":  reason,
+				"detail": "AI usage is limited per user per day; the quota resets at " + resetAt.Format(time.RFC3339),
+				"quota": gin.H{
+					"requests_limit": q.cfg.DailyRequests,
+					"requests_used":  usage.Requests,
+					"tokens_limit":   q.cfg.DailyTokens,
+					"tokens_used":    usage.Tokens,
+					"reset_at":       resetAt.Format(time.RFC3339),
+				},
+			})
+			return
+		}
+
+		c.Next()
+
+		var tokens int64
+		if v, ok := c.Get(TokensUsedKey); ok {
+			tokens, _ = v.(int64)
+		}
+		if c.Writer.Status() >= http.StatusBadRequest {
+			q.store.Release(userID, day)
+		} else {
+			tokens += fixedTokens
+		}
+		if tokens > 0 {
+			q.store.AddTokens(userID, day, tokens)
+		}
+	}
+}
+
+// setHeaders 写入用量响应头（反映本次请求计入前的 token 用量），剩余量不限制时为 -1
+func (q *QuotaLimiter) setHeaders(c *gin.Context, usage QuotaUsage, resetAt time.Time) {
+	c.Header("X-Quota-Requests-Limit", strconv.FormatInt(q.cfg.DailyRequests, 10))
+	c.Header("X-Quota-Requests-Remaining", strconv.FormatInt(remaining(q.cfg.DailyRequests, usage.Requests), 10))
+	c.Header("X-Quota-Tokens-Limit", strconv.FormatInt(q.cfg.DailyTokens, 10))
+	c.Header("X-Quota-Tokens-Remaining", strconv.FormatInt(remaining(q.cfg.DailyTokens, usage.Tokens), 10))
+	c.Header("X-Quota-Reset", resetAt.Format(time.RFC3339))
+}
+
+func remaining(limit, used int64) int64 {
+	if limit <= 0 {
+		return -1
+	}
+	if used >= limit {
+		return 0
+	}
+	return limit - used
+}
+
+func getEnv(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
+
+func getEnvInt64(key string, fallback int64) int64 {
+	if v := os.Getenv(key); v != "" {
+		if n, err := strconv.ParseInt(v, 10, 64); err == nil && n >= 0 {
+			return n
+		}
+	}
+	return fallback
+}
@@ -0,0 +1,82 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/RigelNana/arkstudy/pkg/requestid"
+	"github.com/gin-gonic/gin"
+)
+
+// gin 上下文中保存请求 ID 的键
+const requestIDKey = "request_id"
+
+// WebSocket 升级请求传递 token 的查询参数
+const accessTokenParam = "access_token"
+
+// 访问日志中隐去取值的查询参数：WebSocket 的 token 与签名下载地址的签名
+var redactedQueryParams = map[string]bool{
+	accessTokenParam:  true,
+	signedURLSigParam: true,
+}
+
+// RequestID 沿用请求头中合法的 X-Request-ID，否则生成新的 ID；写入响应头，并放入 c.Request 的 ctx，
+// 经 gRPC metadata 与 Kafka 消息头传给下游服务。需注册在其他中间件之前，使所有响应（包括 404、认证失败）都带有请求 ID
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(requestid.Header)
+		if !requestid.Valid(id) {
+			id = requestid.New()
+		}
+		c.Set(requestIDKey, id)
+		c.Header(requestid.Header, id)
+		c.Request = c.Request.WithContext(requestid.NewContext(c.Request.Context(), id))
+		c.Next()
+	}
+}
+
+// GetRequestID 返回当前请求的请求 ID
+func GetRequestID(c *gin.Context) string {
+	return c.GetString(requestIDKey)
+}
+
+// RPCContext 返回携带请求 ID、但不随客户端断开而取消的 ctx，用于调用下游服务
+func RPCContext(c *gin.Context) context.Context {
+	return requestid.NewContext(context.Background(), GetRequestID(c))
+}
+
+// redactQuery 将路径中敏感查询参数的值替换为 REDACTED，其余参数原样保留
+func redactQuery(path string) string {
+	base, query, ok := strings.Cut(path, "?")
+	if !ok {
+		return path
+	}
+	pairs := strings.Split(query, "&")
+	for i, pair := range pairs {
+		key, _, _ := strings.Cut(pair, "=")
+		if name, err := url.QueryUnescape(key); err == nil && redactedQueryParams[name] {
+			pairs[i] = key + "=REDACTED"
+		}
+	}
+	return base + "?" + strings.Join(pairs, "&")
+}
+
+// AccessLogFormatter 与 gin 默认的访问日志格式一致，末尾附加请求 ID；token 与签名等查询参数不写入日志
+func AccessLogFormatter(p gin.LogFormatterParams) string {
+	if p.Latency > time.Minute {
+		p.Latency = p.Latency.Truncate(time.Second)
+	}
+	return fmt.Sprintf("[GIN] %v | %3d | %13v | %15s | %-7s %#v | request_id=%v\n%s",
+		p.TimeStamp.Format("2006/01/02 - 15:04:05"),
+		p.StatusCode,
+		p.Latency,
+		p.ClientIP,
+		p.Method,
+		redactQuery(p.Path),
+		p.Keys[requestIDKey],
+		p.ErrorMessage,
+	)
+}
@@ -0,0 +1,116 @@
+package middleware
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	errShareTokenMalformed = errors.New("malformed share token")
+	errShareTokenSignature = errors.New("invalid signature")
+	errShareTokenExpired   = errors.New("link expired")
+)
+
+// ShareLinks 为题目分享签发公开访问令牌：令牌为 <share_id>.<exp>.<sig>，sig 为 share_id 与 exp 的 HMAC，
+// 访客无需登录即可访问。令牌不落库，同一分享总是得到同一令牌；撤销与过期由 quiz-service 在每次访问时再次校验
+type ShareLinks struct {
+	secret   []byte
+	embedURL string
+}
+
+// NewShareLinks 从环境变量读取配置：SHARE_LINK_SECRET（多副本部署时必须一致）、
+// SHARE_EMBED_URL（前端嵌入页地址，令牌拼接在其后，如 https://app.example.com/embed/quiz/，未配置时不返回嵌入地址）。
+// 未配置密钥时使用进程内随机密钥，重启后已签发链接失效
+func NewShareLinks() *ShareLinks {
+	secret := []byte(getEnv("SHARE_LINK_SECRET", ""))
+	if len(secret) == 0 {
+		log.Println("Warning: SHARE_LINK_SECRET not set, quiz share links use a per-process key and break on restart or across replicas")
+		secret = make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			panic("generate share link secret: " + err.Error())
+		}
+	}
+	return &ShareLinks{secret: secret, embedURL: getEnv("SHARE_EMBED_URL", "")}
+}
+
+// Token 签发 shareID 在 expires 前有效的访问令牌
+func (s *ShareLinks) Token(shareID string, expires time.Time) string {
+	exp := strconv.FormatInt(expires.Unix(), 10)
+	return shareID + "." + exp + "." + s.signature(shareID, exp)
+}
+
+// EmbedURL 令牌对应的前端嵌入页地址，未配置 SHARE_EMBED_URL 时为空
+func (s *ShareLinks) EmbedURL(token string) string {
+	if s.embedURL == "" {
+		return ""
+	}
+	return s.embedURL + token
+}
+
+// Authenticate 校验路径参数 :token 并注入 share_id，无需 Authorization 头。
+// 请求携带 Authorization 头时交给 jwtAuth 识别作答者，token 无效时仍返回 401
+func (s *ShareLinks) Authenticate(jwtAuth gin.HandlerFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		shareID, err := s.verify(c.Param("token"))
+		if err != nil {
+			c.JSON(http.StatusForbidden, gin.H{"error": "invalid share link", "detail": err.Error()})
+			c.Abort()
+			return
+		}
+		c.Set("share_id", shareID)
+		if c.GetHeader("Authorization") != "" {
+			jwtAuth(c)
+			return
+		}
+		c.Next()
+	}
+}
+
+// PublicCORS 允许任意来源的页面嵌入调用公开接口。公开接口不使用 Cookie，因此不允许携带凭据；
+// 预检请求直接返回 204
+func PublicCORS() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("Access-Control-Allow-Origin", "*")
+		c.Header("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
+		c.Header("Access-Control-Allow-Headers", "Authorization, Content-Type, X-Request-ID")
+		c.Header("Access-Control-Expose-Headers", "X-Request-ID")
+		c.Header("Access-Control-Max-Age", "600")
+		if c.Request.Method == http.MethodOptions {
+			c.AbortWithStatus(http.StatusNoContent)
+			return
+		}
+		c.Next()
+	}
+}
+
+func (s *ShareLinks) verify(token string) (string, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 || parts[0] == "" {
+		return "", errShareTokenMalformed
+	}
+	shareID, exp, sig := parts[0], parts[1], parts[2]
+	if !hmac.Equal([]byte(sig), []byte(s.signature(shareID, exp))) {
+		return "", errShareTokenSignature
+	}
+	unix, err := strconv.ParseInt(exp, 10, 64)
+	if err != nil || time.Now().After(time.Unix(unix, 0)) {
+		return "", errShareTokenExpired
+	}
+	return shareID, nil
+}
+
+func (s *ShareLinks) signature(shareID, exp string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte("quiz-share\n" + shareID + "\n" + exp))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,176 @@
+package middleware
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 签名下载地址的查询参数
+const (
+	signedURLUserParam    = "uid"
+	signedURLExpiresParam = "exp"
+	signedURLSigParam     = "sig"
+)
+
+var (
+	ErrURLNotSignable  = errors.New("path is not a signable export")
+	ErrURLInvalidTTL   = errors.New("invalid expiry")
+	errURLSignature    = errors.New("invalid signature")
+	errURLExpired      = errors.New("link expired")
+	errURLMissingParam = errors.New("missing signature parameters")
+)
+
+// URLSigner 为导出类 GET 接口签发限时下载地址：地址中带 uid、exp 与 HMAC 签名，
+// 浏览器可直接下载或分享，无需 Authorization 头。签名覆盖路径与全部查询参数，改动任一参数即失效；
+// 地址在有效期内无法单独吊销，修改签名密钥会使所有已签发地址失效
+type URLSigner struct {
+	secret     []byte
+	defaultTTL time.Duration
+	maxTTL     time.Duration
+	routes     [][]string // 可签名的路由模板，按 / 切分
+}
+
+// NewURLSigner 从环境变量读取配置：EXPORT_URL_SECRET（多副本部署时必须一致）、
+// EXPORT_URL_DEFAULT_TTL（默认 1h）、EXPORT_URL_MAX_TTL（默认 168h）。
+// 未配置密钥时使用进程内随机密钥，重启后已签发地址失效
+func NewURLSigner() *URLSigner {
+	secret := []byte(getEnv("EXPORT_URL_SECRET", ""))
+	if len(secret) == 0 {
+		log.Println("Warning: EXPORT_URL_SECRET not set, signed export links use a per-process key and break on restart or across replicas")
+		secret = make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			panic("generate export url secret: " + err.Error())
+		}
+	}
+	s := &URLSigner{
+		secret:     secret,
+		defaultTTL: getEnvDuration("EXPORT_URL_DEFAULT_TTL", time.Hour),
+		maxTTL:     getEnvDuration("EXPORT_URL_MAX_TTL", 7*24*time.Hour),
+	}
+	if s.defaultTTL > s.maxTTL {
+		s.defaultTTL = s.maxTTL
+	}
+	return s
+}
+
+// Allow 登记可签名的路由模板（gin 的完整路径，如 /api/materials/:id/anki）
+func (s *URLSigner) Allow(fullPath string) {
+	s.routes = append(s.routes, strings.Split(strings.Trim(fullPath, "/"), "/"))
+}
+
+// MaxTTL 下载地址的最长有效期
+func (s *URLSigner) MaxTTL() time.Duration { return s.maxTTL }
+
+// Sign 为 userID 签发 rawURL（路径加可选查询参数）的下载地址，ttl 为 0 时使用默认有效期
+func (s *URLSigner) Sign(userID, rawURL string, ttl time.Duration) (string, time.Time, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.IsAbs() || u.Host != "" {
+		return "", time.Time{}, ErrURLNotSignable
+	}
+	if !s.signable(u.Path) {
+		return "", time.Time{}, ErrURLNotSignable
+	}
+	if ttl == 0 {
+		ttl = s.defaultTTL
+	}
+	if ttl < 0 || ttl > s.maxTTL {
+		return "", time.Time{}, ErrURLInvalidTTL
+	}
+	expires := time.Now().Add(ttl).Truncate(time.Second)
+
+	q := u.Query()
+	q.Del(signedURLSigParam)
+	q.Set(signedURLUserParam, userID)
+	q.Set(signedURLExpiresParam, strconv.FormatInt(expires.Unix(), 10))
+	q.Set(signedURLSigParam, s.signature(u.Path, q))
+	return u.Path + "?" + q.Encode(), expires, nil
+}
+
+// Authenticate 校验签名下载地址并注入 user_id，请求不带签名参数时交给 fallback（JWTAuth）
+func (s *URLSigner) Authenticate(fallback gin.HandlerFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		q := c.Request.URL.Query()
+		if !q.Has(signedURLSigParam) {
+			fallback(c)
+			return
+		}
+		userID, err := s.verify(c.Request.URL.Path, q)
+		if err != nil {
+			c.JSON(http.StatusForbidden, gin.H{"error": "invalid download link", "detail": err.Error()})
+			c.Abort()
+			return
+		}
+		c.Set("user_id", userID)
+		c.Next()
+	}
+}
+
+func (s *URLSigner) verify(path string, q url.Values) (string, error) {
+	userID, exp, sig := q.Get(signedURLUserParam), q.Get(signedURLExpiresParam), q.Get(signedURLSigParam)
+	if userID == "" || exp == "" || sig == "" {
+		return "", errURLMissingParam
+	}
+	q = cloneValues(q)
+	q.Del(signedURLSigParam)
+	if !hmac.Equal([]byte(sig), []byte(s.signature(path, q))) {
+		return "", errURLSignature
+	}
+	// exp 受签名保护，签名通过后才可信
+	unix, err := strconv.ParseInt(exp, 10, 64)
+	if err != nil || time.Now().After(time.Unix(unix, 0)) {
+		return "", errURLExpired
+	}
+	return userID, nil
+}
+
+// signature 对 GET、路径与按键排序的查询参数（不含 sig）计算 HMAC-SHA256
+func (s *URLSigner) signature(path string, q url.Values) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(http.MethodGet + "\n" + path + "\n" + q.Encode()))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// signable 路径是否匹配已登记的路由模板，:param 匹配任意非空段
+func (s *URLSigner) signable(path string) bool {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	for _, route := range s.routes {
+		if len(route) != len(parts) {
+			continue
+		}
+		ok := true
+		for i, seg := range route {
+			if strings.HasPrefix(seg, ":") {
+				if parts[i] == "" {
+					ok = false
+					break
+				}
+			} else if seg != parts[i] {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			return true
+		}
+	}
+	return false
+}
+
+func cloneValues(q url.Values) url.Values {
+	out := make(url.Values, len(q))
+	for k, v := range q {
+		out[k] = append([]string(nil), v...)
+	}
+	return out
+}
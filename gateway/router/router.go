@@ -9,69 +9,227 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-func Setup(authHandler *handler.AuthHandler, userHandler *handler.UserHandler, materialHandler *handler.MaterialHandler, llmHandler *handler.LLMHandler, quizHandler *handler.QuizHandler, asrHandler *handler.ASRHandler, ocrHandler *handler.OCRHandler) *gin.Engine {
-	r := gin.Default()
+func Setup(authHandler *handler.AuthHandler, userHandler *handler.UserHandler, materialHandler *handler.MaterialHandler, llmHandler *handler.LLMHandler, quizHandler *handler.QuizHandler, asrHandler *handler.ASRHandler, ocrHandler *handler.OCRHandler, studyHandler *handler.StudyHandler, exportHandler *handler.ExportHandler, graphqlHandler *handler.GraphQLHandler, rpcWebHandler *handler.RPCWebHandler) *gin.Engine {
+	r := gin.New()
+	// 请求 ID 最先生成，访问日志与 panic 恢复产生的响应都带有该 ID
+	r.Use(middleware.RequestID(), gin.LoggerWithFormatter(middleware.AccessLogFormatter), gin.Recovery())
 
 	// 添加 Prometheus 中间件
 	r.Use(ginMetrics.PrometheusMiddleware("gateway"))
 
 	// 创建认证中间件
 	authValidator := middleware.NewAuthValidator()
+	// 每用户每日 LLM 配额（/ai/* 与出题）
+	quota := middleware.NewQuotaLimiter(middleware.LoadQuotaConfig(), middleware.NewMemoryQuotaStore())
+	aiQuota := quota.Limit(0)
+	// 导出接口可通过签名下载地址访问，无需 Authorization 头
+	signer := middleware.NewURLSigner()
+	exportLinkHandler := handler.NewExportLinkHandler(signer)
+	// 按用户偏好或请求头解析时区与语言，响应中的时间据此格式化
+	locales := middleware.NewLocaleResolver()
+	// 题目公开分享：访客凭签名令牌访问，无需登录
+	shareLinks := middleware.NewShareLinks()
+	quizShareHandler := handler.NewQuizShareHandler(quizHandler, shareLinks)
 
 	// 文档与 OpenAPI 路由
 	docs.RegisterRoutes(r)
 
+	// 健康检查（无需认证），后端不可用时仍返回 200，详见 HealthHandler.Healthz
+	health := handler.NewHealthHandler()
+	r.GET("/healthz", health.Healthz)
+	r.GET("/readyz", health.Healthz)
+
+	// 请求体大小与 JSON 嵌套、重复键检查，上传类接口单独放宽上限
+	bodyLimits := middleware.NewBodyLimiter(middleware.LoadBodyLimitConfig())
+	bodyLimits.Route("/api/materials/upload", bodyLimits.MaxUploadBytes())
+	bodyLimits.Route("/api/materials/uploads/:id", bodyLimits.MaxUploadBytes())
+	bodyLimits.Route("/api/materials/clips", 2<<20)
+	bodyLimits.Route("/api/admin/users/import", 2<<20)
+
 	api := r.Group("/api")
+	api.Use(bodyLimits.Middleware())
 	{
 		// 公开的认证相关路由（无需认证）
 		api.POST("/register", authHandler.Register)
 		api.POST("/login", authHandler.Login)
 		api.GET("/validate", authHandler.Validate)
+		api.POST("/password/check", authHandler.CheckPassword)
+		// tus 客户端在创建上传前查询服务端能力，无需认证
+		tusHandler := handler.NewTusHandler(materialHandler, bodyLimits.MaxUploadBytes())
+		api.OPTIONS("/materials/uploads", handler.TusResumable(), tusHandler.Options)
 
 		// 需要认证的路由组
 		protected := api.Group("")
-		protected.Use(authValidator.JWTAuth()) // 应用 JWT 认证中间件
+		protected.Use(authValidator.JWTAuth(), locales.Middleware()) // 应用 JWT 认证中间件
 		{
 			// 用户相关路由（需要认证）
 			protected.GET("/users", userHandler.ListUsers)
+			protected.GET("/users/me/activity", userHandler.ListMyActivity)
+			protected.PUT("/users/me/digest", userHandler.SetDigestPreference)
+			protected.PUT("/users/me/locale", userHandler.SetLocalePreference)
+			// 代登录 token 不能修改密码、注销设备或再次代登录
+			protected.PUT("/users/me/password", middleware.DenyImpersonation(), authHandler.UpdatePassword)
+			protected.GET("/users/me/sessions", authHandler.ListSessions)
+			protected.DELETE("/users/me/sessions", middleware.DenyImpersonation(), authHandler.RevokeOtherSessions)
+			protected.DELETE("/users/me/sessions/:id", middleware.DenyImpersonation(), authHandler.RevokeSession)
+			// 管理员代登录，权限由 auth-service 按 user-service 中的角色校验
+			protected.POST("/admin/impersonate", middleware.DenyImpersonation(), authHandler.Impersonate)
+			protected.GET("/admin/impersonations", middleware.DenyImpersonation(), authHandler.ListImpersonations)
+			protected.POST("/admin/users/import", middleware.DenyImpersonation(), authHandler.ImportUsers)
+			// 任务中心：当前用户在各服务中的异步任务
+			protected.GET("/tasks", userHandler.ListMyTasks)
 			protected.GET("/users/:id", userHandler.GetUserByID)
 			protected.GET("/users/username/:username", userHandler.GetUserByUsername)
 			protected.GET("/users/email/:email", userHandler.GetUserByEmail)
 
 			// 材料相关路由（需要认证）
 			protected.POST("/materials/upload", materialHandler.UploadMaterial)
+			// 可续传上传（tus 协议），网络不稳定的移动端可从断点继续
+			uploads := protected.Group("/materials/uploads", handler.TusResumable())
+			uploads.POST("", tusHandler.Create)
+			uploads.HEAD("/:id", tusHandler.Head)
+			uploads.PATCH("/:id", tusHandler.Patch)
+			uploads.DELETE("/:id", tusHandler.Delete)
+			protected.POST("/materials/clips", materialHandler.CreateClip)
 			protected.GET("/materials", materialHandler.ListMaterials)
 			protected.GET("/materials/:id", materialHandler.GetMaterialByID)
 			protected.DELETE("/materials/:id", materialHandler.DeleteMaterial)
+			protected.GET("/materials/:id/media", materialHandler.StreamMaterialMedia)
+			protected.GET("/materials/:id/media-url", materialHandler.GetMaterialMediaURL)
+			protected.GET("/materials/:id/children", materialHandler.ListChildMaterials)
+			protected.GET("/materials/:id/duplicates", materialHandler.ListDuplicateMaterials)
+			protected.GET("/materials/:id/access-log", materialHandler.ListAccessLog)
+			protected.GET("/materials/:id/derived", materialHandler.ListDerivedArtifacts)
+			protected.POST("/materials/:id/derived/regenerate", materialHandler.RegenerateDerived)
+			// 批注：页码或时间段锚定的评论与高亮，仅本人可见
+			protected.GET("/materials/:id/annotations", materialHandler.ListAnnotations)
+			protected.POST("/materials/:id/annotations", materialHandler.CreateAnnotation)
+			protected.PATCH("/materials/:id/annotations/:annotation_id", materialHandler.UpdateAnnotation)
+			protected.DELETE("/materials/:id/annotations/:annotation_id", materialHandler.DeleteAnnotation)
+			// 签发导出接口的限时下载地址，代登录 token 不能签发
+			protected.POST("/exports/links", middleware.DenyImpersonation(), exportLinkHandler.CreateLink)
+			// 异步生成课程的离线学习包，完成后经下方的 download 地址下载
+			protected.POST("/materials/:id/offline-bundle", exportHandler.CreateOfflineBundle)
+			protected.GET("/exports/offline-bundles/:task_id", exportHandler.GetOfflineBundle)
 
 			// AI处理相关路由（需要认证）
 			protected.POST("/materials/process", materialHandler.ProcessMaterial)
+			protected.GET("/materials/:id/processing-estimate", materialHandler.EstimateProcessing)
 			protected.GET("/processing/results", materialHandler.ListProcessingResults)
 			protected.GET("/processing/results/:material_id", materialHandler.GetProcessingResult)
+			protected.GET("/processing/results/:material_id/compare", materialHandler.CompareProcessingResults)
 			protected.PUT("/processing/results/:task_id", materialHandler.UpdateProcessingResult)
+			protected.POST("/processing/tasks/:task_id/retry", materialHandler.RetryProcessingTask)
 
 			// LLM 对外最小可行路由
-			protected.POST("/ai/ask", llmHandler.Ask)
-			protected.GET("/ai/ask/stream", llmHandler.AskStream)
-			protected.POST("/ai/ask/stream", llmHandler.AskStream)
-			protected.GET("/ai/search", llmHandler.Search)
+			protected.POST("/ai/ask", aiQuota, llmHandler.Ask)
+			protected.GET("/ai/ask/stream", aiQuota, llmHandler.AskStream)
+			protected.POST("/ai/ask/stream", aiQuota, llmHandler.AskStream)
+			protected.GET("/ai/search", aiQuota, llmHandler.Search)
+			// 共享会话：成员管理与 WebSocket 实时推送（不调用模型，不计配额）
+			protected.POST("/ai/shared-sessions", llmHandler.CreateSharedSession)
+			protected.GET("/ai/shared-sessions/:id", llmHandler.GetSharedSession)
+			protected.POST("/ai/shared-sessions/:id/join", llmHandler.JoinSharedSession)
+			protected.PUT("/ai/shared-sessions/:id/members/:user_id", llmHandler.UpdateSessionMember)
+			protected.DELETE("/ai/shared-sessions/:id/members/:user_id", llmHandler.RemoveSessionMember)
+			protected.GET("/ai/shared-sessions/:id/ws", llmHandler.SharedSessionSocket)
 
 			// Quiz 自动出题相关路由（需要认证）
-			protected.POST("/quiz/generate", quizHandler.GenerateQuiz)
+			protected.POST("/quiz/generate", quota.Limit(quota.QuizGenerateTokens()), quizHandler.GenerateQuiz)
+			protected.POST("/quiz/generate/selection", quota.Limit(quota.QuizGenerateTokens()), quizHandler.GenerateQuizFromSelection)
+			// 课程题目审核
+			protected.POST("/quiz/review/assign", quizHandler.AssignReviewer)
+			protected.POST("/quiz/review/bulk-approve", quizHandler.BulkApprove)
+			protected.GET("/quiz/review/queue", quizHandler.ListReviewQueue)
+			protected.POST("/quiz/:questionId/review", quizHandler.ReviewQuestion)
+			// 主观题人工评分
+			protected.GET("/quiz/grading/queue", quizHandler.ListGradingQueue)
+			protected.POST("/quiz/grading/grade", quizHandler.GradeAnswers)
 			protected.GET("/quiz/:questionId", quizHandler.GetQuiz)
+			protected.PUT("/quiz/:questionId", quizHandler.UpdateQuestion)
+			protected.GET("/quiz/:questionId/versions", quizHandler.ListQuestionVersions)
+			protected.GET("/quiz/:questionId/versions/:version", quizHandler.GetQuestionVersion)
 			protected.GET("/quiz", quizHandler.ListQuizzes)
+			protected.POST("/quiz/:questionId/attempts", quizHandler.StartQuestionAttempt)
 			protected.POST("/quiz/:questionId/submit", quizHandler.SubmitAnswer)
 			protected.GET("/quiz/user/:userId/history", quizHandler.GetUserHistory)
 			protected.GET("/quiz/user/:userId/stats", quizHandler.GetKnowledgeStats)
+			protected.GET("/quiz/analytics", quizHandler.GetQuestionAnalytics)
+			protected.GET("/quiz/mistakes", quizHandler.ListMistakes)
+			protected.GET("/quiz/mistakes/retry", quizHandler.GetRetryQuestions)
+			protected.GET("/quiz/grading-policy", quizHandler.GetGradingPolicy)
+			protected.PUT("/quiz/grading-policy", quizHandler.SetGradingPolicy)
+			// 题目公开分享的管理与作答结果
+			protected.POST("/quiz/shares", quizShareHandler.CreateShare)
+			protected.GET("/quiz/shares", quizShareHandler.ListShares)
+			protected.GET("/quiz/shares/:id", quizShareHandler.GetShare)
+			protected.DELETE("/quiz/shares/:id", quizShareHandler.RevokeShare)
+			protected.GET("/quiz/shares/:id/attempts", quizShareHandler.ListAttempts)
 
 			// ASR 语音识别相关路由（需要认证）
 			protected.POST("/asr/process", asrHandler.ProcessVideo)
 			protected.GET("/asr/segments/:material_id", asrHandler.GetSegments)
 			protected.POST("/asr/search", asrHandler.SearchSegments)
+			protected.POST("/asr/moments/search", asrHandler.SearchMoments)
+			protected.POST("/asr/:material_id/translate", asrHandler.TranslateTranscript)
+			protected.GET("/asr/:material_id/chapters", asrHandler.GetChapters)
+			protected.POST("/asr/:material_id/retranscribe", asrHandler.RetranscribeSegments)
 			protected.GET("/asr/health", asrHandler.HealthCheck)
 
 			// OCR 相关路由 (需要认证)
 			protected.POST("/ocr/process", ocrHandler.ProcessOCR)
+
+			// 学习时长与目标（需要认证）
+			protected.POST("/study/sessions", studyHandler.StartSession)
+			protected.POST("/study/sessions/:id/heartbeat", studyHandler.Heartbeat)
+			protected.POST("/study/sessions/:id/stop", studyHandler.StopSession)
+			protected.GET("/study/stats", studyHandler.GetStats)
+			protected.GET("/study/goals", studyHandler.ListGoals)
+			protected.PUT("/study/goals/:period", studyHandler.SetGoal)
+			protected.DELETE("/study/goals/:period", studyHandler.DeleteGoal)
+
+			// GraphQL 组合查询（GRAPHQL_ENABLED=true 时开放）
+			if graphqlHandler != nil {
+				protected.POST("/graphql", graphqlHandler.Query)
+			}
+
+			// gRPC-Web / Connect：前端生成的类型化客户端以 /api/rpc 为 baseUrl，调用 LLM 的方法计入配额
+			rpc := protected.Group("/rpc")
+			for _, m := range rpcWebHandler.Methods() {
+				handlers := []gin.HandlerFunc{}
+				if rpcWebHandler.UsesAI(m) {
+					handlers = append(handlers, aiQuota)
+				}
+				rpc.POST(m.Path, append(handlers, m.Handler(middleware.RPCContext))...)
+			}
+		}
+
+		// 题目公开分享：不经过 JWTAuth，由分享令牌鉴权，可被任意站点嵌入；携带 Authorization 头时识别作答者。
+		// 登录作答者的主观题评分调用 LLM，计入作答者的配额；匿名作答的主观题不评分
+		public := api.Group("/public/quiz-shares/:token")
+		public.Use(middleware.PublicCORS(), shareLinks.Authenticate(authValidator.JWTAuth()), locales.Middleware())
+		{
+			public.GET("", quizShareHandler.GetPublicShare)
+			public.POST("/attempts", aiQuota, quizShareHandler.SubmitPublicAttempt)
+			// 预检请求由 PublicCORS 直接返回，此处只为让 OPTIONS 匹配到路由
+			public.OPTIONS("", middleware.PublicCORS())
+			public.OPTIONS("/attempts", middleware.PublicCORS())
+		}
+
+		// 导出接口：携带 Authorization 头，或使用 /api/exports/links 签发的下载地址
+		exports := api.Group("")
+		exports.Use(signer.Authenticate(authValidator.JWTAuth()), locales.Middleware())
+		{
+			exportRoute := func(path string, handlers ...gin.HandlerFunc) {
+				exports.GET(path, handlers...)
+				signer.Allow(exports.BasePath() + path)
+			}
+			// 学习笔记导出，非音视频资料会调用 LLM 生成摘要，计入配额
+			exportRoute("/materials/:id/notes/export", aiQuota, exportHandler.ExportNotes)
+			exportRoute("/materials/:id/anki", exportHandler.ExportAnki)
+			exportRoute("/materials/:id/subtitles", exportHandler.ExportSubtitles)
+			exportRoute("/ai/sessions/:id/export", aiQuota, llmHandler.ExportSession)
+			exportRoute("/exports/offline-bundles/:task_id/download", exportHandler.DownloadOfflineBundle)
 		}
 	}
 	return r
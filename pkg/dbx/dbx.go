@@ -0,0 +1,54 @@
+// Package dbx 提供各服务共用的 GORM 插件：强制软删除、按上下文填充 created_by/updated_by，
+// 以及基于 lock_version 列的乐观锁。
+//
+// 使用方式：连接数据库后调用 db.Use(dbx.Plugin{})，模型嵌入 dbx.Audit 并声明 dbx.Version 字段；
+// 写操作通过 db.WithContext(dbx.WithActor(ctx, userID)) 携带操作人。
+//
+// 配置了只读副本时，UseReplicas 注册副本路由，可容忍复制延迟的查询通过 Scopes(dbx.ReadReplica) 读取副本。
+package dbx
+
+import (
+	"context"
+	"errors"
+)
+
+var (
+	// ErrStaleVersion 乐观锁冲突：记录在读取后已被他人修改
+	ErrStaleVersion = errors.New("dbx: record has been modified by someone else")
+	// ErrHardDelete 对支持软删除的模型执行了永久删除，且上下文未通过 AllowHardDelete 声明
+	ErrHardDelete = errors.New("dbx: hard delete of soft-deletable model is not allowed")
+)
+
+type actorKey struct{}
+
+type hardDeleteKey struct{}
+
+// WithActor 返回携带操作人的上下文，插件据此填充 created_by 与 updated_by
+func WithActor(ctx context.Context, actor string) context.Context {
+	if actor == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFrom 读取上下文中的操作人
+func ActorFrom(ctx context.Context) (string, bool) {
+	if ctx == nil {
+		return "", false
+	}
+	actor, ok := ctx.Value(actorKey{}).(string)
+	return actor, ok && actor != ""
+}
+
+// AllowHardDelete 返回允许永久删除的上下文，用于重建派生数据等确需物理删除的场景
+func AllowHardDelete(ctx context.Context) context.Context {
+	return context.WithValue(ctx, hardDeleteKey{}, true)
+}
+
+func hardDeleteAllowed(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+	allowed, _ := ctx.Value(hardDeleteKey{}).(bool)
+	return allowed
+}
@@ -0,0 +1,18 @@
+module github.com/RigelNana/arkstudy/pkg/dbx
+
+go 1.24.0
+
+require (
+	google.golang.org/grpc v1.75.1
+	gorm.io/gorm v1.30.5
+)
+
+require (
+	github.com/jinzhu/inflection v1.0.0 // indirect
+	github.com/jinzhu/now v1.1.5 // indirect
+	golang.org/x/net v0.43.0 // indirect
+	golang.org/x/sys v0.36.0 // indirect
+	golang.org/x/text v0.29.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20250707201910-8d1bb00bc6a7 // indirect
+	google.golang.org/protobuf v1.36.9 // indirect
+)
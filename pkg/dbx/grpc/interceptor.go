@@ -0,0 +1,19 @@
+package grpc
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+
+	"github.com/RigelNana/arkstudy/pkg/dbx"
+)
+
+// UnaryServerInterceptor 将请求中的 user_id 作为操作人写入上下文，供 dbx 插件填充审计字段
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if r, ok := req.(interface{ GetUserId() string }); ok {
+			ctx = dbx.WithActor(ctx, r.GetUserId())
+		}
+		return handler(ctx, req)
+	}
+}
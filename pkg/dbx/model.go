@@ -0,0 +1,11 @@
+package dbx
+
+// Audit 审计字段，嵌入模型后由插件在写入时填充；上下文没有操作人（后台任务等）时保持原值
+type Audit struct {
+	CreatedBy string `gorm:"size:255" json:"created_by,omitempty"`
+	UpdatedBy string `gorm:"size:255" json:"updated_by,omitempty"`
+}
+
+// Version 乐观锁版本号。创建时为 1，每次更新加 1；
+// 按已读取的记录更新（Save 或 Model(&record)）时要求版本号未变，否则返回 ErrStaleVersion
+type Version int64
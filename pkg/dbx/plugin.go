@@ -0,0 +1,155 @@
+package dbx
+
+import (
+	"reflect"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+const (
+	createdByColumn = "created_by"
+	updatedByColumn = "updated_by"
+	// 记录本次更新检查的版本号，供更新后判断是否发生冲突
+	lockedVersionKey = "dbx:locked_version"
+)
+
+var (
+	versionType   = reflect.TypeOf(Version(0))
+	deletedAtType = reflect.TypeOf(gorm.DeletedAt{})
+)
+
+// Plugin 注册审计、乐观锁与软删除检查的回调
+type Plugin struct{}
+
+func (Plugin) Name() string { return "dbx" }
+
+func (Plugin) Initialize(db *gorm.DB) error {
+	if err := db.Callback().Create().Before("gorm:create").Register("dbx:before_create", beforeCreate); err != nil {
+		return err
+	}
+	if err := db.Callback().Update().Before("gorm:update").Register("dbx:before_update", beforeUpdate); err != nil {
+		return err
+	}
+	if err := db.Callback().Update().After("gorm:update").Register("dbx:after_update", afterUpdate); err != nil {
+		return err
+	}
+	return db.Callback().Delete().Before("gorm:delete").Register("dbx:before_delete", beforeDelete)
+}
+
+// 创建时填充创建人与更新人，版本号为零时置为 1
+func beforeCreate(db *gorm.DB) {
+	stmt := db.Statement
+	if db.Error != nil || stmt.Schema == nil {
+		return
+	}
+	actor, hasActor := ActorFrom(stmt.Context)
+	version := versionField(stmt.Schema)
+
+	eachRecord(stmt.ReflectValue, func(rv reflect.Value) {
+		if hasActor {
+			setIfZero(db, stmt.Schema.LookUpField(createdByColumn), rv, actor)
+			setIfZero(db, stmt.Schema.LookUpField(updatedByColumn), rv, actor)
+		}
+		setIfZero(db, version, rv, Version(1))
+	})
+}
+
+// 更新时填充更新人并处理版本号：按已读取的记录更新时追加版本条件并写入新版本号，
+// 按条件批量更新（map 或 Update 单列）时版本号自增，使他人持有的旧记录失效
+func beforeUpdate(db *gorm.DB) {
+	stmt := db.Statement
+	if db.Error != nil || stmt.Schema == nil {
+		return
+	}
+	if actor, ok := ActorFrom(stmt.Context); ok && stmt.Schema.LookUpField(updatedByColumn) != nil {
+		stmt.SetColumn(updatedByColumn, actor, true)
+	}
+
+	field := versionField(stmt.Schema)
+	if field == nil {
+		return
+	}
+	if stmt.ReflectValue.Kind() == reflect.Struct {
+		if value, zero := field.ValueOf(stmt.Context, stmt.ReflectValue); !zero {
+			current := value.(Version)
+			stmt.AddClause(clause.Where{Exprs: []clause.Expression{
+				clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: field.DBName}, Value: current},
+			}})
+			stmt.SetColumn(field.DBName, current+1, true)
+			db.InstanceSet(lockedVersionKey, current)
+			return
+		}
+	}
+	if dest, ok := stmt.Dest.(map[string]interface{}); ok {
+		dest[field.DBName] = gorm.Expr(stmt.Quote(field.DBName) + " + 1")
+	}
+}
+
+// 带版本条件的更新未命中记录时视为冲突，恢复内存中的版本号
+func afterUpdate(db *gorm.DB) {
+	value, ok := db.InstanceGet(lockedVersionKey)
+	if !ok || db.Error != nil || db.DryRun || db.RowsAffected > 0 {
+		return
+	}
+	if field := versionField(db.Statement.Schema); field != nil && db.Statement.ReflectValue.CanAddr() {
+		_ = field.Set(db.Statement.Context, db.Statement.ReflectValue, value)
+	}
+	db.AddError(ErrStaleVersion)
+}
+
+// 支持软删除的模型只允许软删除，Unscoped 删除需通过 AllowHardDelete 显式声明
+func beforeDelete(db *gorm.DB) {
+	stmt := db.Statement
+	if db.Error != nil || stmt.Schema == nil || !stmt.Unscoped {
+		return
+	}
+	if hasDeletedAt(stmt.Schema) && !hardDeleteAllowed(stmt.Context) {
+		db.AddError(ErrHardDelete)
+	}
+}
+
+func versionField(s *schema.Schema) *schema.Field {
+	if s == nil {
+		return nil
+	}
+	for _, f := range s.Fields {
+		if f.FieldType == versionType && f.DBName != "" {
+			return f
+		}
+	}
+	return nil
+}
+
+func hasDeletedAt(s *schema.Schema) bool {
+	for _, f := range s.Fields {
+		if f.FieldType == deletedAtType {
+			return true
+		}
+	}
+	return false
+}
+
+// eachRecord 对单条记录或批量创建的每条记录执行 fn
+func eachRecord(rv reflect.Value, fn func(reflect.Value)) {
+	switch rv.Kind() {
+	case reflect.Struct:
+		fn(rv)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			if elem := reflect.Indirect(rv.Index(i)); elem.Kind() == reflect.Struct {
+				fn(elem)
+			}
+		}
+	}
+}
+
+func setIfZero(db *gorm.DB, field *schema.Field, rv reflect.Value, value interface{}) {
+	if field == nil || !rv.CanAddr() {
+		return
+	}
+	if _, zero := field.ValueOf(db.Statement.Context, rv); zero {
+		db.AddError(field.Set(db.Statement.Context, rv, value))
+	}
+}
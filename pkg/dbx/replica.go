@@ -0,0 +1,82 @@
+package dbx
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"sync/atomic"
+
+	"gorm.io/gorm"
+)
+
+const replicaKey = "dbx:read_replica"
+
+// Replicas 只读副本路由插件：通过 ReadReplica 声明的查询轮询分配到副本，
+// 事务内的查询与未声明的查询仍走主库，避免读到复制延迟前的旧数据
+type Replicas struct {
+	pools []gorm.ConnPool
+	next  atomic.Uint64
+}
+
+func (r *Replicas) Name() string { return "dbx:replicas" }
+
+func (r *Replicas) Initialize(db *gorm.DB) error {
+	if err := db.Callback().Query().Before("gorm:query").Register("dbx:replica_query", r.route); err != nil {
+		return err
+	}
+	return db.Callback().Row().Before("gorm:row").Register("dbx:replica_row", r.route)
+}
+
+func (r *Replicas) route(db *gorm.DB) {
+	if db.Error != nil || len(r.pools) == 0 {
+		return
+	}
+	if use, ok := db.Get(replicaKey); !ok || use != true {
+		return
+	}
+	if _, inTx := db.Statement.ConnPool.(gorm.TxCommitter); inTx {
+		return
+	}
+	db.Statement.ConnPool = r.pools[(r.next.Add(1)-1)%uint64(len(r.pools))]
+}
+
+// ReadReplica 查询作用域：允许该查询读取只读副本，用于可以容忍复制延迟的列表与统计查询。
+// 未配置副本时不影响查询
+func ReadReplica(db *gorm.DB) *gorm.DB {
+	return db.Set(replicaKey, true)
+}
+
+// UseReplicas 连接 dsns 中的只读副本并为 db 注册路由插件，dsns 为空时不做任何事。
+// open 为数据库驱动的 Dialector 构造函数（如 postgres.Open），configure 可为 nil，用于设置副本的连接池
+func UseReplicas(db *gorm.DB, open func(dsn string) gorm.Dialector, dsns []string, configure func(*sql.DB)) error {
+	if len(dsns) == 0 {
+		return nil
+	}
+	replicas := &Replicas{}
+	for i, dsn := range dsns {
+		rdb, err := gorm.Open(open(dsn), &gorm.Config{Logger: db.Logger})
+		if err != nil {
+			return fmt.Errorf("connect read replica %d: %w", i+1, err)
+		}
+		if configure != nil {
+			sqlDB, err := rdb.DB()
+			if err != nil {
+				return fmt.Errorf("get sql.DB of read replica %d: %w", i+1, err)
+			}
+			configure(sqlDB)
+		}
+		replicas.pools = append(replicas.pools, rdb.ConnPool)
+	}
+	return db.Use(replicas)
+}
+
+// ParseDSNList 解析逗号分隔的 DSN 列表，忽略空白项
+func ParseDSNList(s string) []string {
+	var dsns []string
+	for _, dsn := range strings.Split(s, ",") {
+		if dsn = strings.TrimSpace(dsn); dsn != "" {
+			dsns = append(dsns, dsn)
+		}
+	}
+	return dsns
+}
@@ -0,0 +1,157 @@
+package discovery
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/resolver"
+)
+
+const (
+	consulScheme = "consul"
+	// 阻塞查询的最长等待时间，实例变化时 Consul 立即返回
+	consulWait = 30 * time.Second
+	// 查询失败后的重试间隔
+	consulRetry = 5 * time.Second
+)
+
+func init() {
+	resolver.Register(consulBuilder{})
+}
+
+type consulBuilder struct{}
+
+func (consulBuilder) Scheme() string { return consulScheme }
+
+func (consulBuilder) Build(target resolver.Target, cc resolver.ClientConn, _ resolver.BuildOptions) (resolver.Resolver, error) {
+	base := strings.TrimRight(os.Getenv("CONSUL_HTTP_ADDR"), "/")
+	if base == "" {
+		base = "http://127.0.0.1:8500"
+	}
+	if !strings.Contains(base, "://") {
+		base = "http://" + base
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	r := &consulResolver{
+		base:    base,
+		token:   os.Getenv("CONSUL_HTTP_TOKEN"),
+		service: target.Endpoint(),
+		cc:      cc,
+		ctx:     ctx,
+		cancel:  cancel,
+		now:     make(chan struct{}, 1),
+		client:  &http.Client{Timeout: consulWait + 10*time.Second},
+	}
+	go r.watch()
+	return r, nil
+}
+
+// consulResolver 通过 Consul 健康检查接口的阻塞查询跟踪服务的健康实例
+type consulResolver struct {
+	base    string
+	token   string
+	service string
+	cc      resolver.ClientConn
+	ctx     context.Context
+	cancel  context.CancelFunc
+	now     chan struct{}
+	client  *http.Client
+}
+
+// consulEntry /v1/health/service 返回的实例，只取需要的字段
+type consulEntry struct {
+	Node struct {
+		Address string
+	}
+	Service struct {
+		Address string
+		Port    int
+	}
+}
+
+func (r *consulResolver) watch() {
+	var index string
+	for {
+		addrs, next, err := r.query(index)
+		if err != nil {
+			if r.ctx.Err() != nil {
+				return
+			}
+			log.Printf("discovery: consul query for %s failed: %v", r.service, err)
+			r.cc.ReportError(err)
+			index = ""
+			select {
+			case <-r.ctx.Done():
+				return
+			case <-r.now:
+			case <-time.After(consulRetry):
+			}
+			continue
+		}
+		if next != index {
+			// 暂时没有健康实例时保留空列表，请求快速失败而不是发往已下线的实例
+			if err := r.cc.UpdateState(resolver.State{Addresses: addrs}); err != nil {
+				log.Printf("discovery: update %s instances: %v", r.service, err)
+			}
+		}
+		index = next
+	}
+}
+
+// query 执行一次阻塞查询，index 为上次返回的 X-Consul-Index
+func (r *consulResolver) query(index string) ([]resolver.Address, string, error) {
+	q := url.Values{"passing": {"true"}}
+	if index != "" {
+		q.Set("index", index)
+		q.Set("wait", consulWait.String())
+	}
+	req, err := http.NewRequestWithContext(r.ctx, http.MethodGet, r.base+"/v1/health/service/"+url.PathEscape(r.service)+"?"+q.Encode(), nil)
+	if err != nil {
+		return nil, "", err
+	}
+	if r.token != "" {
+		req.Header.Set("X-Consul-Token", r.token)
+	}
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("consul status %d", resp.StatusCode)
+	}
+	var entries []consulEntry
+	if err := json.NewDecoder(resp.Body).Decode(&entries); err != nil {
+		return nil, "", err
+	}
+	addrs := make([]resolver.Address, 0, len(entries))
+	for _, e := range entries {
+		host := e.Service.Address
+		if host == "" {
+			host = e.Node.Address
+		}
+		if host == "" || e.Service.Port == 0 {
+			continue
+		}
+		addrs = append(addrs, resolver.Address{Addr: net.JoinHostPort(host, strconv.Itoa(e.Service.Port))})
+	}
+	return addrs, resp.Header.Get("X-Consul-Index"), nil
+}
+
+func (r *consulResolver) ResolveNow(resolver.ResolveNowOptions) {
+	select {
+	case r.now <- struct{}{}:
+	default:
+	}
+}
+
+func (r *consulResolver) Close() { r.cancel() }
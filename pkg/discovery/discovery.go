@@ -0,0 +1,88 @@
+// Package discovery 为各服务的 gRPC 客户端统一解析后端实例，并在多个副本之间按健康状态负载均衡与故障转移。
+//
+// 解析方式由 DISCOVERY_MODE 选择：
+//   - env（默认）：使用 registry 解析出的地址；地址为逗号分隔的多个 host:port 时在这些实例之间轮询
+//   - dns：按地址中的主机名做 DNS 解析（配合 K8s headless Service 可得到全部 Pod），定期重新解析
+//   - consul：从 Consul（CONSUL_HTTP_ADDR，默认 http://127.0.0.1:8500）查询通过健康检查的实例，
+//     服务名为 registry 中的服务名，可用 CONSUL_SERVICE_PREFIX 加前缀
+//
+// 连接使用 round_robin 负载均衡并开启 gRPC 客户端健康检查：实例的 grpc.health.v1 状态不为 SERVING 时
+// 不再向其发送请求，待其恢复后重新加入；未实现健康检查服务的实例（如 llm-service）视为健康。
+// 服务端通过 RegisterHealth 注册健康检查服务。
+//
+// 连接会携带 ctx 中的请求 ID（见 pkg/requestid）。
+package discovery
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/RigelNana/arkstudy/pkg/registry"
+	"github.com/RigelNana/arkstudy/pkg/requestid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	// 注册客户端健康检查的实现，serviceConfig 中的 healthCheckConfig 依赖该包
+	_ "google.golang.org/grpc/health"
+)
+
+// 解析方式
+const (
+	ModeEnv    = "env"
+	ModeDNS    = "dns"
+	ModeConsul = "consul"
+)
+
+// serviceConfig 在解析出的全部实例之间轮询，并跳过健康检查未通过的实例
+const serviceConfig = `{"loadBalancingConfig":[{"round_robin":{}}],"healthCheckConfig":{"serviceName":""}}`
+
+// Mode 返回当前的解析方式
+func Mode() string {
+	switch m := strings.ToLower(strings.TrimSpace(os.Getenv("DISCOVERY_MODE"))); m {
+	case ModeDNS, ModeConsul:
+		return m
+	default:
+		return ModeEnv
+	}
+}
+
+// Target 返回连接 svc 使用的 gRPC target
+func Target(svc registry.Service) string {
+	return target(svc.Name, svc.Addr())
+}
+
+func target(name, addr string) string {
+	switch Mode() {
+	case ModeConsul:
+		return consulScheme + ":///" + os.Getenv("CONSUL_SERVICE_PREFIX") + name
+	case ModeDNS:
+		return "dns:///" + addr
+	}
+	if strings.Contains(addr, ",") {
+		return staticScheme + ":///" + addr
+	}
+	// 单个地址不做解析，与此前 grpc.Dial 的行为一致（由 K8s Service 负责转发）
+	return "passthrough:///" + addr
+}
+
+// Dial 创建到 svc 的客户端连接。连接是惰性的，实例暂时不可用不会导致失败，请求时再按当前健康的实例路由
+func Dial(svc registry.Service, opts ...grpc.DialOption) (*grpc.ClientConn, error) {
+	return DialAddr(svc.Name, svc.Addr(), opts...)
+}
+
+// DialAddr 与 Dial 相同，但使用调用方配置的地址 addr（env / dns 模式）；consul 模式下按服务名 name 查询
+func DialAddr(name, addr string, opts ...grpc.DialOption) (*grpc.ClientConn, error) {
+	t := target(name, addr)
+	dialOpts := append([]grpc.DialOption{
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithDefaultServiceConfig(serviceConfig),
+		// 请求 ID 随调用传给下游服务
+		grpc.WithChainUnaryInterceptor(requestid.UnaryClientInterceptor()),
+		grpc.WithChainStreamInterceptor(requestid.StreamClientInterceptor()),
+	}, opts...)
+	conn, err := grpc.NewClient(t, dialOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("dial %s (%s): %w", name, t, err)
+	}
+	return conn, nil
+}
@@ -0,0 +1,16 @@
+package discovery
+
+import (
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// RegisterHealth 在 s 上注册 grpc.health.v1 健康检查服务，初始状态为 SERVING。
+// 服务退出前可调用返回值的 Shutdown，使客户端提前将流量切到其他副本
+func RegisterHealth(s *grpc.Server) *health.Server {
+	h := health.NewServer()
+	h.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(s, h)
+	return h
+}
@@ -0,0 +1,41 @@
+package discovery
+
+import (
+	"fmt"
+	"strings"
+
+	"google.golang.org/grpc/resolver"
+)
+
+// staticScheme 逗号分隔的固定实例列表，如 static:///user-1:50052,user-2:50052
+const staticScheme = "static"
+
+func init() {
+	resolver.Register(staticBuilder{})
+}
+
+type staticBuilder struct{}
+
+func (staticBuilder) Scheme() string { return staticScheme }
+
+func (staticBuilder) Build(target resolver.Target, cc resolver.ClientConn, _ resolver.BuildOptions) (resolver.Resolver, error) {
+	var addrs []resolver.Address
+	for _, a := range strings.Split(target.Endpoint(), ",") {
+		if a = strings.TrimSpace(a); a != "" {
+			addrs = append(addrs, resolver.Address{Addr: a})
+		}
+	}
+	if len(addrs) == 0 {
+		return nil, fmt.Errorf("static target %q has no addresses", target.Endpoint())
+	}
+	if err := cc.UpdateState(resolver.State{Addresses: addrs}); err != nil {
+		return nil, err
+	}
+	return staticResolver{}, nil
+}
+
+// staticResolver 实例列表固定，无需重新解析
+type staticResolver struct{}
+
+func (staticResolver) ResolveNow(resolver.ResolveNowOptions) {}
+func (staticResolver) Close()                                {}
@@ -0,0 +1,210 @@
+// Package featureflags 提供运行时可切换的功能开关，用于灰度发布而无需重新部署。
+//
+// 开关值来源（优先级从高到低）：
+//   - 环境变量 FEATURE_<NAME>，如 FEATURE_HYBRID_SEARCH=true
+//   - FEATURE_FLAGS_PATH 指向的文件或目录，按 FEATURE_FLAGS_REFRESH（默认 30s）周期重新加载：
+//     目录时每个文件为一个开关（与 K8s 挂载 ConfigMap 的形式一致），文件名为开关名、内容为取值；
+//     文件时每行一个 name=value，# 开头为注释
+//
+// 取值支持 true/false、on/off、1/0 以及百分比（如 25%），百分比按 key 哈希做稳定的用户级灰度。
+package featureflags
+
+import (
+	"bufio"
+	"hash/fnv"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 已知的开关名，各服务共用同一套命名
+const (
+	KafkaOCRPath     = "kafka_ocr_path"    // material-service 通过 Kafka 派发 OCR 任务，关闭时走 gRPC 编排
+	ChapterDetection = "chapter_detection" // asr-service 转写完成后自动划分章节
+	HybridSearch     = "hybrid_search"     // 检索时同时使用关键词与向量召回
+	NewChunker       = "new_chunker"       // 使用新版文本切分策略
+	Reranker         = "reranker"          // llm-service 语义检索后增加重排序阶段（Python 实现见 app/core/feature_flags.py）
+)
+
+const envPrefix = "FEATURE_"
+
+// flag 为解析后的开关值，percent 取值 0~100
+type flag struct {
+	percent int
+}
+
+var (
+	mu       sync.RWMutex
+	fileVals = map[string]flag{}
+	initOnce sync.Once
+)
+
+// Enabled 返回开关是否打开，未配置时返回 defaultValue。百分比开关在此处只有 100% 视为打开。
+func Enabled(name string, defaultValue bool) bool {
+	f, ok := lookup(name)
+	if !ok {
+		return defaultValue
+	}
+	return f.percent >= 100
+}
+
+// EnabledFor 按 key（通常为用户 ID）判断开关是否对其打开，同一 key 的结果在百分比不变时保持稳定
+func EnabledFor(name, key string, defaultValue bool) bool {
+	f, ok := lookup(name)
+	if !ok {
+		return defaultValue
+	}
+	switch {
+	case f.percent <= 0:
+		return false
+	case f.percent >= 100:
+		return true
+	}
+	h := fnv.New32a()
+	h.Write([]byte(name))
+	h.Write([]byte{0})
+	h.Write([]byte(key))
+	return int(h.Sum32()%100) < f.percent
+}
+
+// Snapshot 返回当前从文件加载的开关（不含环境变量覆盖），便于调试接口展示
+func Snapshot() map[string]int {
+	initOnce.Do(start)
+	mu.RLock()
+	defer mu.RUnlock()
+	out := make(map[string]int, len(fileVals))
+	for name, f := range fileVals {
+		out[name] = f.percent
+	}
+	return out
+}
+
+func lookup(name string) (flag, bool) {
+	initOnce.Do(start)
+
+	name = normalize(name)
+	if raw, ok := os.LookupEnv(envPrefix + strings.ToUpper(name)); ok {
+		if f, ok := parseValue(raw); ok {
+			return f, true
+		}
+	}
+
+	mu.RLock()
+	defer mu.RUnlock()
+	f, ok := fileVals[name]
+	return f, ok
+}
+
+// start 首次使用时加载文件并启动周期刷新
+func start() {
+	path := os.Getenv("FEATURE_FLAGS_PATH")
+	if path == "" {
+		return
+	}
+	reload(path)
+
+	interval := 30 * time.Second
+	if v := os.Getenv("FEATURE_FLAGS_REFRESH"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d > 0 {
+			interval = d
+		}
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			reload(path)
+		}
+	}()
+}
+
+func reload(path string) {
+	vals, err := loadPath(path)
+	if err != nil {
+		// 读取失败时保留上一次的值，避免配置抖动导致开关被意外关闭
+		log.Printf("featureflags: failed to load %s: %v", path, err)
+		return
+	}
+	mu.Lock()
+	fileVals = vals
+	mu.Unlock()
+}
+
+func loadPath(path string) (map[string]flag, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	vals := map[string]flag{}
+
+	if info.IsDir() {
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range entries {
+			// K8s ConfigMap 挂载目录中以 .. 开头的是内部符号链接
+			if e.IsDir() || strings.HasPrefix(e.Name(), ".") {
+				continue
+			}
+			data, err := os.ReadFile(filepath.Join(path, e.Name()))
+			if err != nil {
+				return nil, err
+			}
+			if f, ok := parseValue(string(data)); ok {
+				vals[normalize(e.Name())] = f
+			}
+		}
+		return vals, nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		name, raw, found := strings.Cut(line, "=")
+		if !found {
+			continue
+		}
+		if f, ok := parseValue(raw); ok {
+			vals[normalize(name)] = f
+		}
+	}
+	return vals, scanner.Err()
+}
+
+// parseValue 解析开关取值，无法识别时返回 false
+func parseValue(raw string) (flag, bool) {
+	v := strings.ToLower(strings.TrimSpace(raw))
+	switch v {
+	case "true", "on", "yes", "1", "enabled":
+		return flag{percent: 100}, true
+	case "false", "off", "no", "0", "disabled":
+		return flag{percent: 0}, true
+	}
+	if strings.HasSuffix(v, "%") {
+		p, err := strconv.Atoi(strings.TrimSpace(strings.TrimSuffix(v, "%")))
+		if err != nil {
+			return flag{}, false
+		}
+		return flag{percent: min(max(p, 0), 100)}, true
+	}
+	return flag{}, false
+}
+
+// normalize 统一开关名：小写，- 与 . 视为 _
+func normalize(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	return strings.NewReplacer("-", "_", ".", "_").Replace(name)
+}
@@ -0,0 +1,130 @@
+// Package interceptor 提供各服务 gRPC 服务端共用的拦截器：
+//   - panic 恢复：处理函数 panic 时记录堆栈并返回 codes.Internal，不再导致整个进程退出
+//   - 错误规范化：非 gRPC status 的错误按 Options.Codes 与 context 错误映射状态码，其余为 codes.Internal，错误信息保持不变
+//   - 慢请求日志：一元调用处理时间超过 Options.SlowThreshold 时记录方法、耗时与状态码（流式调用时长由客户端决定，不记录）
+//   - 请求 ID：将 metadata 中的请求 ID 放入 ctx（见 pkg/requestid），以上日志均带有该 ID
+//
+// 应放在 metrics 拦截器之后，使指标记录的是规范化后的状态码。
+package interceptor
+
+import (
+	"context"
+	"errors"
+	"log"
+	"os"
+	"runtime/debug"
+	"time"
+
+	"github.com/RigelNana/arkstudy/pkg/requestid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// 默认的慢请求阈值
+const defaultSlowThreshold = time.Second
+
+// Options 拦截器配置
+type Options struct {
+	// Service 服务名，用于日志
+	Service string
+	// SlowThreshold 一元调用超过该耗时记录慢请求日志，0 表示不记录
+	SlowThreshold time.Duration
+	// Codes 业务错误对应的状态码，按 errors.Is 匹配（如 gorm.ErrRecordNotFound → codes.NotFound）
+	Codes map[error]codes.Code
+}
+
+// DefaultOptions 返回服务的默认配置，慢请求阈值读取 GRPC_SLOW_THRESHOLD（如 "500ms"，"0" 关闭），默认 1s
+func DefaultOptions(service string) Options {
+	opts := Options{Service: service, SlowThreshold: defaultSlowThreshold}
+	if v := os.Getenv("GRPC_SLOW_THRESHOLD"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d >= 0 {
+			opts.SlowThreshold = d
+		} else {
+			log.Printf("interceptor: invalid GRPC_SLOW_THRESHOLD %q, using %s", v, defaultSlowThreshold)
+		}
+	}
+	return opts
+}
+
+// UnaryServerInterceptor 为一元调用提供请求 ID、panic 恢复、错误规范化与慢请求日志
+func UnaryServerInterceptor(opts Options) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+		ctx = requestid.FromIncoming(ctx)
+		start := time.Now()
+		defer func() {
+			if r := recover(); r != nil {
+				resp, err = nil, opts.recovered(ctx, info.FullMethod, r)
+			}
+			err = opts.normalize(err)
+			if elapsed := time.Since(start); opts.SlowThreshold > 0 && elapsed >= opts.SlowThreshold {
+				log.Printf("%s: slow request %s took %s (%s)%s", opts.Service, info.FullMethod, elapsed.Round(time.Millisecond), status.Code(err), logRequestID(ctx))
+			}
+		}()
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor 为流式调用提供请求 ID、panic 恢复与错误规范化
+func StreamServerInterceptor(opts Options) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		stream := &serverStream{ServerStream: ss, ctx: requestid.FromIncoming(ss.Context())}
+		defer func() {
+			if r := recover(); r != nil {
+				err = opts.recovered(stream.ctx, info.FullMethod, r)
+			}
+			err = opts.normalize(err)
+		}()
+		return handler(srv, stream)
+	}
+}
+
+// serverStream 替换流的 ctx，使处理函数能取到请求 ID
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+// recovered 记录 panic 与堆栈，返回给客户端的错误不包含 panic 内容
+func (o Options) recovered(ctx context.Context, method string, r interface{}) error {
+	log.Printf("%s: panic in %s%s: %v\n%s", o.Service, method, logRequestID(ctx), r, debug.Stack())
+	return status.Error(codes.Internal, "internal error")
+}
+
+// logRequestID 返回追加在日志中的请求 ID，没有时为空串
+func logRequestID(ctx context.Context) string {
+	if id := requestid.FromContext(ctx); id != "" {
+		return " request_id=" + id
+	}
+	return ""
+}
+
+// normalize 将非 gRPC status 的错误转换为带状态码的 status，已是 status 的错误保持不变
+func (o Options) normalize(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	return status.Error(o.code(err), err.Error())
+}
+
+func (o Options) code(err error) codes.Code {
+	for target, code := range o.Codes {
+		if errors.Is(err, target) {
+			return code
+		}
+	}
+	switch {
+	case errors.Is(err, context.Canceled):
+		return codes.Canceled
+	case errors.Is(err, context.DeadlineExceeded):
+		return codes.DeadlineExceeded
+	}
+	return codes.Internal
+}
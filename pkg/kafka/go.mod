@@ -0,0 +1,10 @@
+module github.com/RigelNana/arkstudy/pkg/kafka
+
+go 1.24.0
+
+require github.com/segmentio/kafka-go v0.4.47
+
+require (
+	github.com/klauspost/compress v1.18.0 // indirect
+	github.com/pierrec/lz4/v4 v4.1.15 // indirect
+)
@@ -0,0 +1,88 @@
+// Package kafka 定义各服务共享的 Kafka topic、分区键与消费组扩缩容约定，并提供按约定配置的 reader / writer。
+//
+// 分区键：资料处理相关的 topic（ocr.requests、text.extracted、file.processing、material.events）
+// 统一以 material_id 作为消息 key，user.activity、task.events 与 notification.requests 以 user_id 作为 key。writer 使用 murmur2 分区器，
+// 与 Java 客户端及 librdkafka 的默认分区器一致，其他语言的生产者写入同一 topic 时同一 key 也落在同一分区。
+// 同一资料的消息因此进入同一分区，按发送顺序被消费。
+//
+// 消费组扩缩容：同一消费组内每个分区同一时刻只由一个副本消费，消费组的有效并发上限为分区数，
+// 超出分区数的副本处于空闲状态。topic 按预期的最大副本数预留分区（见 Topic.Partitions），
+// 扩容 ocr-worker、llm-service 等消费者副本时无需重新分区。分区数扩大后 key 到分区的映射会变化，
+// 同一资料扩容前后的消息可能乱序，应在低峰期进行。
+package kafka
+
+import (
+	"strings"
+
+	kafka "github.com/segmentio/kafka-go"
+)
+
+// Topic 共享 topic 的约定
+type Topic struct {
+	// Name 默认 topic 名称，各服务可通过环境变量覆盖
+	Name string
+	// Key 消息 key 使用的字段
+	Key string
+	// Partitions 创建 topic 时的分区数，即单个消费组可水平扩展的副本上限
+	Partitions int
+}
+
+// 分区键字段
+const (
+	KeyMaterialID = "material_id"
+	KeyUserID     = "user_id"
+)
+
+var (
+	OCRRequests    = Topic{Name: "ocr.requests", Key: KeyMaterialID, Partitions: 12}
+	TextExtracted  = Topic{Name: "text.extracted", Key: KeyMaterialID, Partitions: 12}
+	FileProcessing = Topic{Name: "file.processing", Key: KeyMaterialID, Partitions: 12}
+	MaterialEvents = Topic{Name: "material.events", Key: KeyMaterialID, Partitions: 6}
+	UserActivity   = Topic{Name: "user.activity", Key: KeyUserID, Partitions: 6}
+	TaskEvents     = Topic{Name: "task.events", Key: KeyUserID, Partitions: 6}
+	Notifications  = Topic{Name: "notification.requests", Key: KeyUserID, Partitions: 6}
+)
+
+// MaterialKey 返回资料相关消息的 key
+func MaterialKey(materialID string) []byte {
+	return []byte(materialID)
+}
+
+// UserKey 返回用户相关消息的 key
+func UserKey(userID string) []byte {
+	return []byte(userID)
+}
+
+// SplitBrokers 解析逗号分隔的 broker 列表，忽略空项
+func SplitBrokers(s string) []string {
+	var out []string
+	for _, b := range strings.Split(s, ",") {
+		if b = strings.TrimSpace(b); b != "" {
+			out = append(out, b)
+		}
+	}
+	return out
+}
+
+// NewWriter 创建写入 topic 的 writer，按消息 key 以 murmur2 选择分区。
+// 未设置 key 的消息随机分区，不保证顺序，生产者应始终按 Topic.Key 设置 key
+func NewWriter(brokers []string, topic string) *kafka.Writer {
+	return &kafka.Writer{
+		Addr:         kafka.TCP(brokers...),
+		Topic:        topic,
+		Balancer:     &kafka.Murmur2Balancer{},
+		RequiredAcks: kafka.RequireOne,
+		Compression:  kafka.Snappy,
+	}
+}
+
+// NewReader 创建加入消费组 groupID 的 reader。组内副本按分区分配，同一资料的消息由同一副本按序消费
+func NewReader(brokers []string, groupID, topic string) *kafka.Reader {
+	return kafka.NewReader(kafka.ReaderConfig{
+		Brokers:  brokers,
+		GroupID:  groupID,
+		Topic:    topic,
+		MinBytes: 1,
+		MaxBytes: 10 << 20,
+	})
+}
@@ -0,0 +1,47 @@
+package kafka
+
+import (
+	"encoding/json"
+	"time"
+
+	kafka "github.com/segmentio/kafka-go"
+)
+
+// 通知渠道与模板
+const (
+	NotificationChannelEmail = "email"
+	// 站内通知，按 UserID 投递，To 为空
+	NotificationChannelInApp = "in_app"
+
+	NotificationTemplateWeeklyDigest      = "weekly_digest"
+	NotificationTemplateInvitation        = "user_invitation"
+	NotificationTemplateProcessingStarted = "processing_started"
+)
+
+// NotificationEvent notification.requests 中的通知请求，由通知服务按 Channel 投递。
+// Subject / Body 为可直接发送的纯文本，Data 为模板变量，通知服务可据此渲染 HTML；
+// NotificationID 在重试时保持不变，通知服务据此去重
+type NotificationEvent struct {
+	NotificationID string            `json:"notification_id"`
+	UserID         string            `json:"user_id"`
+	Channel        string            `json:"channel"`
+	To             string            `json:"to"`
+	Template       string            `json:"template"`
+	Subject        string            `json:"subject"`
+	Body           string            `json:"body"`
+	Data           map[string]string `json:"data,omitempty"`
+	OccurredAt     time.Time         `json:"occurred_at"`
+}
+
+// NotificationMessage 将通知请求编码为以 user_id 为 key 的消息
+func NotificationMessage(event NotificationEvent) (kafka.Message, error) {
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now()
+	}
+	event.OccurredAt = event.OccurredAt.UTC()
+	value, err := json.Marshal(event)
+	if err != nil {
+		return kafka.Message{}, err
+	}
+	return kafka.Message{Key: UserKey(event.UserID), Value: value}, nil
+}
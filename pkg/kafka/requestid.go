@@ -0,0 +1,36 @@
+package kafka
+
+import (
+	"context"
+
+	"github.com/RigelNana/arkstudy/pkg/requestid"
+	kafka "github.com/segmentio/kafka-go"
+)
+
+// SetRequestID 将 ctx 中的请求 ID 写入各消息的 X-Request-ID 消息头，ctx 中没有请求 ID 或消息已带有时不修改
+func SetRequestID(ctx context.Context, msgs []kafka.Message) {
+	id := requestid.FromContext(ctx)
+	if id == "" {
+		return
+	}
+	for i := range msgs {
+		if RequestID(msgs[i]) == "" {
+			msgs[i].Headers = append(msgs[i].Headers, kafka.Header{Key: requestid.Header, Value: []byte(id)})
+		}
+	}
+}
+
+// RequestID 返回消息头中的请求 ID，没有或格式不合法时为空串
+func RequestID(msg kafka.Message) string {
+	for _, h := range msg.Headers {
+		if h.Key == requestid.Header && requestid.Valid(string(h.Value)) {
+			return string(h.Value)
+		}
+	}
+	return ""
+}
+
+// RequestIDContext 返回携带消息请求 ID 的 ctx，消费者处理消息时发出的 gRPC 调用与消息会继续带上该 ID
+func RequestIDContext(ctx context.Context, msg kafka.Message) context.Context {
+	return requestid.NewContext(ctx, RequestID(msg))
+}
@@ -0,0 +1,59 @@
+package kafka
+
+import (
+	"encoding/json"
+	"time"
+
+	kafka "github.com/segmentio/kafka-go"
+)
+
+// 任务类型
+const (
+	TaskKindOCR            = "ocr"
+	TaskKindASR            = "asr"
+	TaskKindQuizGeneration = "quiz_generation"
+	TaskKindExport         = "export"
+)
+
+// 统一的任务状态，各服务将自身的状态映射到这几种
+const (
+	TaskStatusPending   = "pending"
+	TaskStatusRunning   = "running"
+	TaskStatusSucceeded = "succeeded"
+	TaskStatusFailed    = "failed"
+)
+
+// TaskEvent task.events 中的任务事件。各服务在任务创建、进度变化与结束时发布任务的完整快照，
+// 由 user-service 按 task_id 合并为任务中心的一条记录，OccurredAt 较旧的事件不覆盖已有记录
+type TaskEvent struct {
+	TaskID   string  `json:"task_id"`
+	UserID   string  `json:"user_id"`
+	Kind     string  `json:"kind"`
+	Service  string  `json:"service"` // 发布事件的服务
+	Status   string  `json:"status"`
+	Progress float32 `json:"progress"` // 0~1
+	// Title 便于直接展示的任务名称，TargetID 为任务处理的对象（通常是资料 ID）
+	Title      string            `json:"title,omitempty"`
+	TargetID   string            `json:"target_id,omitempty"`
+	Error      string            `json:"error,omitempty"`
+	Metadata   map[string]string `json:"metadata,omitempty"`
+	OccurredAt time.Time         `json:"occurred_at"`
+}
+
+// TaskFinished 判断任务状态是否为终态
+func TaskFinished(status string) bool {
+	return status == TaskStatusSucceeded || status == TaskStatusFailed
+}
+
+// TaskMessage 将任务事件编码为以 user_id 为 key 的消息，同一任务的事件按发布顺序被消费
+func TaskMessage(event TaskEvent) (kafka.Message, error) {
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now()
+	}
+	event.OccurredAt = event.OccurredAt.UTC()
+	value, err := json.Marshal(event)
+	if err != nil {
+		return kafka.Message{}, err
+	}
+	return kafka.Message{Key: UserKey(event.UserID), Value: value}, nil
+}
@@ -0,0 +1,237 @@
+// Package langdetect 判断资料文本的主要语言，返回 ISO 639-1 语言代码（如 zh、en、ja）。
+//
+// 先按文字系统统计：汉字、假名与谚文按字计，以空格分词的字母文字按词计，避免中文资料中夹杂的
+// 英文术语压过正文；主要为拉丁字母时再按常见虚词区分英、法、德、西、葡、意语。
+// 不依赖模型，只适合判断整份资料的主要语言，不适合判断短句。
+package langdetect
+
+import (
+	"strings"
+	"unicode"
+)
+
+// 支持识别的语言
+const (
+	Chinese    = "zh"
+	Japanese   = "ja"
+	Korean     = "ko"
+	English    = "en"
+	French     = "fr"
+	German     = "de"
+	Spanish    = "es"
+	Portuguese = "pt"
+	Italian    = "it"
+	Russian    = "ru"
+	Arabic     = "ar"
+	Hindi      = "hi"
+	Thai       = "th"
+	Greek      = "el"
+	Hebrew     = "he"
+)
+
+const (
+	// 只统计前 maxRunes 个字符，长资料的开头足以判断主要语言
+	maxRunes = 200000
+	// 字与词合计少于 minUnits 时不判断
+	minUnits = 20
+	// 日文中假名占汉字与假名合计的最低比例，低于该比例视为中文
+	minKanaRatio = 0.1
+)
+
+var names = map[string]string{
+	Chinese:    "Chinese",
+	Japanese:   "Japanese",
+	Korean:     "Korean",
+	English:    "English",
+	French:     "French",
+	German:     "German",
+	Spanish:    "Spanish",
+	Portuguese: "Portuguese",
+	Italian:    "Italian",
+	Russian:    "Russian",
+	Arabic:     "Arabic",
+	Hindi:      "Hindi",
+	Thai:       "Thai",
+	Greek:      "Greek",
+	Hebrew:     "Hebrew",
+}
+
+// 拉丁字母语言的常见虚词，取各语言高频且与其他语言重叠较少的词
+var stopwords = map[string][]string{
+	English:    {"the", "and", "of", "to", "is", "that", "with", "for", "this", "are", "be", "which", "from", "it"},
+	French:     {"le", "les", "des", "et", "est", "une", "dans", "que", "pour", "qui", "pas", "sur", "du", "au"},
+	German:     {"der", "die", "und", "das", "ist", "nicht", "mit", "den", "ein", "eine", "sich", "auf", "auch", "für"},
+	Spanish:    {"el", "los", "las", "y", "es", "una", "por", "con", "para", "como", "del", "pero", "más", "se"},
+	Portuguese: {"os", "as", "e", "um", "uma", "não", "com", "para", "como", "do", "da", "dos", "mais", "são"},
+	Italian:    {"il", "gli", "e", "di", "che", "non", "una", "per", "con", "sono", "della", "del", "anche", "è"},
+}
+
+var stopwordLangs = func() map[string][]string {
+	m := make(map[string][]string)
+	for lang, words := range stopwords {
+		for _, w := range words {
+			m[w] = append(m[w], lang)
+		}
+	}
+	return m
+}()
+
+// 以空格分词的字母文字，按词计
+var alphabets = []struct {
+	lang  string
+	table *unicode.RangeTable
+}{
+	{Russian, unicode.Cyrillic},
+	{Arabic, unicode.Arabic},
+	{Hindi, unicode.Devanagari},
+	{Greek, unicode.Greek},
+	{Hebrew, unicode.Hebrew},
+}
+
+// Detect 返回 text 的主要语言代码，文本过短或无法判断时返回空串
+func Detect(text string) string {
+	var han, kana, hangul, thai, latinWords int
+	words := map[string]int{}
+	latin := map[string]int{}
+
+	// 当前词所属的文字，拉丁字母词保留小写词形用于匹配虚词
+	var word strings.Builder
+	wordLang := ""
+	endWord := func() {
+		switch wordLang {
+		case "":
+			return
+		case English:
+			latinWords++
+			for _, lang := range stopwordLangs[word.String()] {
+				latin[lang]++
+			}
+			word.Reset()
+		default:
+			words[wordLang]++
+		}
+		wordLang = ""
+	}
+
+	n := 0
+	for _, r := range text {
+		if n++; n > maxRunes {
+			break
+		}
+		if unicode.Is(unicode.Latin, r) {
+			if wordLang != English {
+				endWord()
+				wordLang = English
+			}
+			word.WriteRune(unicode.ToLower(r))
+			continue
+		}
+		if lang := alphabetOf(r); lang != "" {
+			if wordLang != lang {
+				endWord()
+				wordLang = lang
+			}
+			continue
+		}
+		endWord()
+		switch {
+		case unicode.Is(unicode.Han, r):
+			han++
+		case unicode.In(r, unicode.Hiragana, unicode.Katakana):
+			kana++
+		case unicode.Is(unicode.Hangul, r):
+			hangul++
+		case unicode.Is(unicode.Thai, r):
+			thai++
+		}
+	}
+	endWord()
+
+	best, bestUnits := "", 0
+	for lang, count := range words {
+		if count > bestUnits {
+			best, bestUnits = lang, count
+		}
+	}
+	// 泰文不以空格分词，每 4 个字母约合一个词
+	if units := thai / 4; units > bestUnits {
+		best, bestUnits = Thai, units
+	}
+	if cjk := han + kana; cjk > bestUnits {
+		best, bestUnits = Chinese, cjk
+		if float64(kana) >= minKanaRatio*float64(cjk) {
+			best = Japanese
+		}
+	}
+	if hangul > bestUnits {
+		best, bestUnits = Korean, hangul
+	}
+	if latinWords > bestUnits {
+		best, bestUnits = latinLanguage(latin), latinWords
+	}
+	if bestUnits < minUnits {
+		return ""
+	}
+	return best
+}
+
+func alphabetOf(r rune) string {
+	for _, a := range alphabets {
+		if unicode.Is(a.table, r) {
+			return a.lang
+		}
+	}
+	return ""
+}
+
+// latinLanguage 按虚词出现次数选出拉丁字母语言，没有命中任何虚词时返回空串
+func latinLanguage(counts map[string]int) string {
+	best, bestCount := "", 0
+	for lang, count := range counts {
+		// 次数相同时按语言代码取较小者，保证结果稳定
+		if count > bestCount || (count == bestCount && lang < best) {
+			best, bestCount = lang, count
+		}
+	}
+	return best
+}
+
+// Name 语言代码对应的英文名称，用于提示词；未知代码原样返回
+func Name(code string) string {
+	if name, ok := names[Normalize(code)]; ok {
+		return name
+	}
+	return code
+}
+
+// Normalize 将语言标签（如 zh-CN、en_US、EN）规范为小写的主语言代码
+func Normalize(tag string) string {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if i := strings.IndexAny(tag, "-_"); i >= 0 {
+		tag = tag[:i]
+	}
+	return tag
+}
+
+// MaxTagLength 语言标签的最大长度，与译文表 language 列 varchar(20) 一致
+const MaxTagLength = 20
+
+// ValidTag 判断是否为合法的 BCP-47 风格语言标签（如 zh、en-US、zh-Hant-TW）：
+// 主语言为 2～3 个字母，其后的子标签为 1～8 个字母或数字，以 - 分隔，总长不超过 MaxTagLength
+func ValidTag(tag string) bool {
+	if tag == "" || len(tag) > MaxTagLength {
+		return false
+	}
+	for i, sub := range strings.Split(tag, "-") {
+		if i == 0 && (len(sub) < 2 || len(sub) > 3) || len(sub) < 1 || len(sub) > 8 {
+			return false
+		}
+		for _, r := range sub {
+			isLetter := r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z'
+			if !isLetter && (i == 0 || r < '0' || r > '9') {
+				return false
+			}
+		}
+	}
+	return true
+}
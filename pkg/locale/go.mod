@@ -0,0 +1,3 @@
+module github.com/RigelNana/arkstudy/pkg/locale
+
+go 1.24.0
@@ -0,0 +1,100 @@
+// Package locale 描述用户的时区与语言，用于按用户所在时区格式化时间。
+//
+// 服务之间以 google.protobuf.Timestamp 传递时间，gateway 按请求头（X-Timezone、Accept-Language）
+// 或用户偏好解析出 Locale 放入请求 context，序列化响应时调用 FromContext(ctx).FormatTime(t)
+// 输出带时区偏移的 RFC3339 时间；user-service 按用户偏好格式化周报日期。
+// 未指定时区时使用 UTC。
+package locale
+
+import (
+	"context"
+	"strings"
+	"time"
+	// 镜像基于 alpine，不带系统时区数据库
+	_ "time/tzdata"
+)
+
+// 支持的语言，其他语言按 DefaultLanguage 处理
+const (
+	LanguageZH = "zh-CN"
+	LanguageEN = "en"
+
+	DefaultLanguage = LanguageZH
+)
+
+// Locale 用户的时区（IANA 名称，如 Asia/Shanghai）与语言
+type Locale struct {
+	Timezone string
+	Language string
+}
+
+// Default 未指定时区与语言时使用的 Locale
+var Default = Locale{Timezone: "UTC", Language: DefaultLanguage}
+
+type contextKey struct{}
+
+// NewContext 返回携带 l 的 context
+func NewContext(ctx context.Context, l Locale) context.Context {
+	return context.WithValue(ctx, contextKey{}, l)
+}
+
+// FromContext 取出 context 中的 Locale，没有时返回 Default
+func FromContext(ctx context.Context) Locale {
+	if l, ok := ctx.Value(contextKey{}).(Locale); ok {
+		return l
+	}
+	return Default
+}
+
+// ValidTimezone 是否为可加载的 IANA 时区名
+func ValidTimezone(tz string) bool {
+	if tz == "" || tz == "Local" {
+		return false
+	}
+	_, err := time.LoadLocation(tz)
+	return err == nil
+}
+
+// NormalizeLanguage 从语言标签或 Accept-Language 取第一个支持的语言，都不支持时返回空串
+func NormalizeLanguage(accept string) string {
+	for _, part := range strings.Split(accept, ",") {
+		tag, _, _ := strings.Cut(strings.TrimSpace(part), ";")
+		primary, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+		switch primary {
+		case "zh":
+			return LanguageZH
+		case "en":
+			return LanguageEN
+		}
+	}
+	return ""
+}
+
+// Location 时区，无效时为 UTC
+func (l Locale) Location() *time.Location {
+	if l.Timezone == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(l.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// FormatTime 将 t 格式化为用户时区的 RFC3339 时间（带偏移），零值返回空串
+func (l Locale) FormatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.In(l.Location()).Format(time.RFC3339)
+}
+
+// FormatDate 按语言格式化 t 在用户时区的日期，用于邮件等直接展示的文本
+func (l Locale) FormatDate(t time.Time) string {
+	t = t.In(l.Location())
+	if l.Language == LanguageEN {
+		return t.Format("Jan 2, 2006")
+	}
+	return t.Format("2006年1月2日")
+}
@@ -0,0 +1,147 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"time"
+
+	arkkafka "github.com/RigelNana/arkstudy/pkg/kafka"
+	"github.com/RigelNana/arkstudy/pkg/metrics"
+	"github.com/segmentio/kafka-go"
+)
+
+// KafkaMessagesTotal 的 status 取值
+const (
+	StatusProduced      = "produced"
+	StatusProduceFailed = "produce_failed"
+	StatusConsumed      = "consumed"
+	StatusFetchFailed   = "fetch_failed"
+	StatusCommitFailed  = "commit_failed"
+)
+
+// 统计信息采集间隔
+const statsInterval = 15 * time.Second
+
+var (
+	mu sync.RWMutex
+	// writers / readers 记录已接入指标的客户端所属的服务名
+	writers = map[*kafka.Writer]string{}
+	readers = map[*kafka.Reader]string{}
+)
+
+// InstrumentWriter 为 w 接入指标并返回 w：通过 Completion 统计每条消息的投递结果（原有 Completion 仍会被调用），
+// 并定期采集重试次数与错误数。w 为 nil 时直接返回 nil。
+// 注意 kafka-go 的 Stats 每次调用都会清零计数，接入后不应再在其他地方调用 w.Stats()
+func InstrumentWriter(service string, w *kafka.Writer) *kafka.Writer {
+	if w == nil {
+		return nil
+	}
+	next := w.Completion
+	w.Completion = func(messages []kafka.Message, err error) {
+		recordProduced(service, w.Topic, messages, err)
+		if next != nil {
+			next(messages, err)
+		}
+	}
+
+	mu.Lock()
+	writers[w] = service
+	mu.Unlock()
+
+	go func() {
+		ticker := time.NewTicker(statsInterval)
+		defer ticker.Stop()
+		for range ticker.C {
+			stats := w.Stats()
+			metrics.KafkaWriteRetries.WithLabelValues(service, stats.Topic).Add(float64(stats.Retries))
+			metrics.KafkaClientErrors.WithLabelValues(service, stats.Topic, "writer").Add(float64(stats.Errors))
+		}
+	}()
+	return w
+}
+
+// WriteMessages 调用 w.WriteMessages 并记录写入耗时。同步写入的耗时包含攒批与重试，
+// 异步 writer 的调用立即返回，不记录耗时（投递结果仍由 Completion 统计）。
+// ctx 中的请求 ID 会写入消息头（见 arkkafka.SetRequestID）
+func WriteMessages(ctx context.Context, w *kafka.Writer, msgs ...kafka.Message) error {
+	arkkafka.SetRequestID(ctx, msgs)
+	start := time.Now()
+	err := w.WriteMessages(ctx, msgs...)
+
+	mu.RLock()
+	service, ok := writers[w]
+	mu.RUnlock()
+	if ok && !w.Async {
+		metrics.KafkaWriteDuration.WithLabelValues(service, w.Topic).Observe(time.Since(start).Seconds())
+	}
+	return err
+}
+
+func recordProduced(service, topic string, messages []kafka.Message, err error) {
+	status := StatusProduced
+	if err != nil {
+		status = StatusProduceFailed
+	}
+	metrics.KafkaMessagesTotal.WithLabelValues(service, topic, status).Add(float64(len(messages)))
+	if err == nil {
+		metrics.KafkaLastSuccessTimestamp.WithLabelValues(service, topic, "produce").SetToCurrentTime()
+	}
+}
+
+// InstrumentReader 为 r 接入指标并返回 r：定期采集消费延迟（lag）与错误数，
+// 配合 FetchMessage / CommitMessages 统计消费与提交结果。
+// 与 InstrumentWriter 相同，接入后不应再在其他地方调用 r.Stats()
+func InstrumentReader(service string, r *kafka.Reader) *kafka.Reader {
+	cfg := r.Config()
+
+	mu.Lock()
+	readers[r] = service
+	mu.Unlock()
+
+	go func() {
+		ticker := time.NewTicker(statsInterval)
+		defer ticker.Stop()
+		for range ticker.C {
+			stats := r.Stats()
+			metrics.KafkaConsumerLag.WithLabelValues(service, cfg.Topic, cfg.GroupID).Set(float64(stats.Lag))
+			metrics.KafkaClientErrors.WithLabelValues(service, cfg.Topic, "reader").Add(float64(stats.Errors))
+		}
+	}()
+	return r
+}
+
+// FetchMessage 调用 r.FetchMessage 并记录消费结果，ctx 取消与 reader 关闭（io.EOF）不计为失败
+func FetchMessage(ctx context.Context, r *kafka.Reader) (kafka.Message, error) {
+	msg, err := r.FetchMessage(ctx)
+	if service, ok := readerService(r); ok {
+		topic := r.Config().Topic
+		switch {
+		case err == nil:
+			metrics.KafkaMessagesTotal.WithLabelValues(service, topic, StatusConsumed).Inc()
+			metrics.KafkaLastSuccessTimestamp.WithLabelValues(service, topic, "consume").SetToCurrentTime()
+		case ctx.Err() == nil && !errors.Is(err, context.Canceled) && !errors.Is(err, io.EOF):
+			metrics.KafkaMessagesTotal.WithLabelValues(service, topic, StatusFetchFailed).Inc()
+		}
+	}
+	return msg, err
+}
+
+// CommitMessages 调用 r.CommitMessages 并记录提交失败的消息数
+func CommitMessages(ctx context.Context, r *kafka.Reader, msgs ...kafka.Message) error {
+	err := r.CommitMessages(ctx, msgs...)
+	if err != nil {
+		if service, ok := readerService(r); ok {
+			metrics.KafkaMessagesTotal.WithLabelValues(service, r.Config().Topic, StatusCommitFailed).Add(float64(len(msgs)))
+		}
+	}
+	return err
+}
+
+func readerService(r *kafka.Reader) (string, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	service, ok := readers[r]
+	return service, ok
+}
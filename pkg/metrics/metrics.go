@@ -1,11 +1,10 @@
 package metrics
 
 import (
-	"net/http"
+	"database/sql"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
-	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
 var (
@@ -45,6 +44,47 @@ var (
 		[]string{"service", "topic", "status"},
 	)
 
+	KafkaWriteDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "kafka_write_duration_seconds",
+			Help:    "Duration of synchronous Kafka writes in seconds, including batching and retries",
+			Buckets: []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10},
+		},
+		[]string{"service", "topic"},
+	)
+
+	KafkaWriteRetries = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "kafka_write_retries_total",
+			Help: "Total number of Kafka produce request retries",
+		},
+		[]string{"service", "topic"},
+	)
+
+	KafkaClientErrors = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "kafka_client_errors_total",
+			Help: "Total number of errors reported by Kafka readers and writers",
+		},
+		[]string{"service", "topic", "client"},
+	)
+
+	KafkaConsumerLag = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "kafka_consumer_lag",
+			Help: "Number of messages between the consumer offset and the partition high watermark",
+		},
+		[]string{"service", "topic", "group"},
+	)
+
+	KafkaLastSuccessTimestamp = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "kafka_last_success_timestamp_seconds",
+			Help: "Unix time of the last successful Kafka produce or consume",
+		},
+		[]string{"service", "topic", "operation"},
+	)
+
 	// 业务指标
 	ActiveUsers = prometheus.NewGauge(
 		prometheus.GaugeOpts{
@@ -67,6 +107,92 @@ var (
 			Help: "Vector search latency in seconds",
 		},
 	)
+
+	// OCR 指标
+	OCRActiveTasks = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "ocr_active_tasks",
+			Help: "Number of OCR tasks currently running",
+		},
+	)
+
+	OCRQueueDepth = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "ocr_queue_depth",
+			Help: "Number of OCR calls waiting for a PaddleOCR concurrency slot",
+		},
+	)
+
+	OCRTaskDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "ocr_task_duration_seconds",
+			Help:    "OCR task duration in seconds, from download to result",
+			Buckets: []float64{0.5, 1, 2, 5, 10, 20, 30, 60, 120, 300},
+		},
+		[]string{"engine", "status"},
+	)
+
+	// 资料上传流水线指标（material-service）
+	MaterialUploadStepDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "material_upload_step_duration_seconds",
+			Help:    "Duration of each material upload step in seconds, one observation per attempt",
+			Buckets: []float64{0.01, 0.05, 0.1, 0.25, 0.5, 1, 2, 5, 10, 30, 60},
+		},
+		[]string{"step", "status"},
+	)
+
+	MaterialUploadCompensations = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "material_upload_compensations_total",
+			Help: "Total number of compensating actions run after a material upload step failed",
+		},
+		[]string{"step"},
+	)
+
+	// 资料处理耗时（material-service），按 ProcessingResult 记录的阶段时间戳计算
+	ProcessingDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "processing_duration_seconds",
+			Help:    "End-to-end material processing latency in seconds, from the processing request to completion or failure",
+			Buckets: []float64{1, 5, 10, 30, 60, 120, 300, 600, 1200, 1800, 3600},
+		},
+		[]string{"type", "status"},
+	)
+
+	ProcessingStageDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "processing_stage_duration_seconds",
+			Help:    "Time spent reaching each material processing stage from the previous recorded stage, in seconds",
+			Buckets: []float64{0.1, 0.5, 1, 5, 10, 30, 60, 120, 300, 600, 1800, 3600},
+		},
+		[]string{"type", "stage"},
+	)
+
+	// 语音转写用量（asr-service），按发送给转写 API 的音频时长计
+	ASRAudioMinutes = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Name:    "asr_request_audio_minutes",
+			Help:    "Minutes of audio sent to the transcription API per request",
+			Buckets: []float64{1, 5, 10, 15, 30, 45, 60, 90, 120, 180},
+		},
+	)
+
+	ASRRejectedTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "asr_audio_limit_rejections_total",
+			Help: "Total number of transcriptions rejected for exceeding the per-request audio minutes cap",
+		},
+		[]string{"stage"},
+	)
+
+	PaddleOCRRequestsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "paddle_ocr_requests_total",
+			Help: "Total number of PaddleOCR inference requests",
+		},
+		[]string{"status"},
+	)
 )
 
 func init() {
@@ -76,24 +202,44 @@ func init() {
 		RequestDuration,
 		DatabaseConnections,
 		KafkaMessagesTotal,
+		KafkaWriteDuration,
+		KafkaWriteRetries,
+		KafkaClientErrors,
+		KafkaConsumerLag,
+		KafkaLastSuccessTimestamp,
 		ActiveUsers,
 		MaterialsProcessed,
 		VectorSearchLatency,
+		OCRActiveTasks,
+		OCRQueueDepth,
+		OCRTaskDuration,
+		PaddleOCRRequestsTotal,
+		MaterialUploadStepDuration,
+		MaterialUploadCompensations,
+		ProcessingDuration,
+		ProcessingStageDuration,
+		ASRAudioMinutes,
+		ASRRejectedTotal,
 	)
 }
 
-// StartMetricsServer 启动独立的 metrics HTTP 服务器
-func StartMetricsServer(port string) {
-	http.Handle("/metrics", promhttp.Handler())
-	go func() {
-		if err := http.ListenAndServe(":"+port, nil); err != nil {
-			panic("failed to start metrics server: " + err.Error())
-		}
-	}()
-}
-
 // RecordRequest 记录请求指标的助手函数
 func RecordRequest(service, method, status string, duration time.Duration) {
 	RequestsTotal.WithLabelValues(service, method, status).Inc()
 	RequestDuration.WithLabelValues(service, method).Observe(duration.Seconds())
 }
+
+// StartDBStatsCollector 定期将数据库连接池状态写入 DatabaseConnections 指标
+func StartDBStatsCollector(service string, db *sql.DB, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			stats := db.Stats()
+			DatabaseConnections.WithLabelValues(service, "open").Set(float64(stats.OpenConnections))
+			DatabaseConnections.WithLabelValues(service, "in_use").Set(float64(stats.InUse))
+			DatabaseConnections.WithLabelValues(service, "idle").Set(float64(stats.Idle))
+			DatabaseConnections.WithLabelValues(service, "wait_count").Set(float64(stats.WaitCount))
+		}
+	}()
+}
@@ -0,0 +1,108 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// DefaultAddr 内部监听器的默认地址，可通过 METRICS_ADDR 覆盖
+const DefaultAddr = ":2112"
+
+// 单项就绪检查的超时时间，需小于探针超时（默认 1s）
+const readinessCheckTimeout = 800 * time.Millisecond
+
+var (
+	serverOnce sync.Once
+	serverAddr string
+
+	ready    atomic.Bool
+	checksMu sync.RWMutex
+	checks   []readinessCheck
+)
+
+type readinessCheck struct {
+	name  string
+	check func(context.Context) error
+}
+
+// ListenAddr 返回内部监听地址：读取 METRICS_ADDR（如 ":9100"、"127.0.0.1:2112"，只写端口时按 ":端口" 处理），未设置时为 DefaultAddr
+func ListenAddr() string {
+	addr := strings.TrimSpace(os.Getenv("METRICS_ADDR"))
+	if addr == "" {
+		return DefaultAddr
+	}
+	if !strings.Contains(addr, ":") {
+		addr = ":" + addr
+	}
+	return addr
+}
+
+// StartMetricsServer 在 ListenAddr 上启动内部 HTTP 服务器，提供 /metrics、/healthz（存活）与 /readyz（就绪），返回实际监听地址。
+// 使用独立的 ServeMux，重复调用只启动一次；监听失败时 panic
+func StartMetricsServer() string {
+	serverOnce.Do(func() {
+		lis, err := net.Listen("tcp", ListenAddr())
+		if err != nil {
+			panic("failed to start metrics server: " + err.Error())
+		}
+		serverAddr = lis.Addr().String()
+
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", promhttp.Handler())
+		mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("ok\n"))
+		})
+		mux.HandleFunc("/readyz", serveReadiness)
+		go func() {
+			if err := http.Serve(lis, mux); err != nil {
+				panic("metrics server stopped: " + err.Error())
+			}
+		}()
+	})
+	return serverAddr
+}
+
+// SetReady 设置服务是否就绪：启动完成、开始接收请求后置为 true，优雅退出前置为 false 以便先摘除流量
+func SetReady(v bool) {
+	ready.Store(v)
+}
+
+// AddReadinessCheck 注册一项就绪检查（如数据库 Ping）。/readyz 在 SetReady(true) 且全部检查通过时返回 200
+func AddReadinessCheck(name string, check func(context.Context) error) {
+	checksMu.Lock()
+	defer checksMu.Unlock()
+	checks = append(checks, readinessCheck{name: name, check: check})
+}
+
+func serveReadiness(w http.ResponseWriter, r *http.Request) {
+	if !ready.Load() {
+		http.Error(w, "not ready", http.StatusServiceUnavailable)
+		return
+	}
+	checksMu.RLock()
+	defer checksMu.RUnlock()
+
+	var failed []string
+	for _, c := range checks {
+		ctx, cancel := context.WithTimeout(r.Context(), readinessCheckTimeout)
+		err := c.check(ctx)
+		cancel()
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", c.name, err))
+		}
+	}
+	if len(failed) > 0 {
+		http.Error(w, strings.Join(failed, "\n"), http.StatusServiceUnavailable)
+		return
+	}
+	w.Write([]byte("ok\n"))
+}
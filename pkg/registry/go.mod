@@ -0,0 +1,3 @@
+module github.com/RigelNana/arkstudy/pkg/registry
+
+go 1.24.0
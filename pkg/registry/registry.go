@@ -0,0 +1,144 @@
+// Package registry 集中定义各服务的默认 gRPC 端口与地址配置，避免各服务各自硬编码端口而相互冲突。
+//
+// 客户端地址的解析顺序（优先级从高到低）：
+//   - 服务的地址环境变量（如 QUIZ_GRPC_ADDR；兼容旧名 QUIZ_SERVICE_ADDR）
+//   - K8s 服务发现注入的 ARKSTUDY_<SERVICE>_SERVICE_HOST / _PORT（Helm release 名为 arkstudy）
+//   - localhost:<默认端口>
+//
+// 服务端监听端口从服务的端口环境变量读取，取值可以是端口或 host:port，未配置时为默认端口。
+// 各服务启动时调用 Validate 校验端口与依赖地址，配置错误直接退出，而不是运行后才连错服务。
+package registry
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Service 单个服务的地址配置
+type Service struct {
+	Name string // 服务名，如 quiz-service
+	Port string // 默认 gRPC 端口，各服务互不相同
+	// 客户端地址的环境变量，按优先级排列
+	AddrEnv []string
+	// 服务端监听端口的环境变量，按优先级排列
+	PortEnv []string
+}
+
+var (
+	Auth     = Service{Name: "auth-service", Port: "50051", AddrEnv: []string{"AUTH_GRPC_ADDR"}, PortEnv: []string{"AUTH_GRPC_PORT", "GRPC_PORT"}}
+	User     = Service{Name: "user-service", Port: "50052", AddrEnv: []string{"USER_GRPC_ADDR"}, PortEnv: []string{"USER_GRPC_PORT"}}
+	Material = Service{Name: "material-service", Port: "50053", AddrEnv: []string{"MATERIAL_GRPC_ADDR"}, PortEnv: []string{"MATERIAL_GRPC_PORT", "MATERIAL_GRPC_ADDR"}}
+	LLM      = Service{Name: "llm-service", Port: "50054", AddrEnv: []string{"LLM_GRPC_ADDR", "LLM_SERVICE_ADDR"}, PortEnv: []string{"LLM_GRPC_PORT"}}
+	OCR      = Service{Name: "ocr-service", Port: "50055", AddrEnv: []string{"OCR_GRPC_ADDR"}, PortEnv: []string{"OCR_GRPC_PORT", "OCR_GRPC_ADDR"}}
+	Quiz     = Service{Name: "quiz-service", Port: "50056", AddrEnv: []string{"QUIZ_GRPC_ADDR", "QUIZ_SERVICE_ADDR"}, PortEnv: []string{"QUIZ_GRPC_PORT", "GRPC_PORT"}}
+	ASR      = Service{Name: "asr-service", Port: "50057", AddrEnv: []string{"ASR_GRPC_ADDR", "ASR_SERVICE_ADDR"}, PortEnv: []string{"ASR_GRPC_PORT", "GRPC_PORT"}}
+	Study    = Service{Name: "study-service", Port: "50058", AddrEnv: []string{"STUDY_GRPC_ADDR"}, PortEnv: []string{"STUDY_GRPC_PORT"}}
+)
+
+// All 全部已登记的服务
+var All = []Service{Auth, User, Material, LLM, OCR, Quiz, ASR, Study}
+
+// Addr 返回连接该服务使用的地址
+func (s Service) Addr() string {
+	if v := firstEnv(s.AddrEnv); v != "" {
+		return v
+	}
+	prefix := "ARKSTUDY_" + strings.ToUpper(strings.ReplaceAll(s.Name, "-", "_")) + "_SERVICE_"
+	host, port := os.Getenv(prefix+"HOST"), os.Getenv(prefix+"PORT")
+	if host != "" && port != "" {
+		return net.JoinHostPort(host, port)
+	}
+	return net.JoinHostPort("localhost", s.Port)
+}
+
+// ListenPort 返回该服务的 gRPC 监听端口
+func (s Service) ListenPort() string {
+	v := firstEnv(s.PortEnv)
+	if v == "" {
+		return s.Port
+	}
+	// 兼容以 host:port 形式配置的端口（如 MATERIAL_GRPC_ADDR）
+	if i := strings.LastIndex(v, ":"); i >= 0 {
+		return v[i+1:]
+	}
+	return v
+}
+
+// Validate 校验服务自身的监听端口与其依赖服务的地址：
+// 端口须为 1-65535，且不能占用其他服务的默认端口；依赖地址须为 host:port，且不能指向本服务自身
+func Validate(self Service, deps ...Service) error {
+	var errs []string
+	port := self.ListenPort()
+	if err := checkPort(port); err != nil {
+		errs = append(errs, fmt.Sprintf("%s listen port: %v", self.Name, err))
+	}
+	for _, other := range All {
+		if other.Name != self.Name && other.Port == port {
+			errs = append(errs, fmt.Sprintf("%s listen port %s collides with the default port of %s", self.Name, port, other.Name))
+		}
+	}
+	errs = append(errs, checkDeps(self.Name, port, deps)...)
+	return joinErrors(errs)
+}
+
+// ValidateClients 校验依赖服务的地址，用于不登记 gRPC 端口的进程（如 gateway）
+func ValidateClients(deps ...Service) error {
+	return joinErrors(checkDeps("", "", deps))
+}
+
+func checkDeps(selfName, selfPort string, deps []Service) []string {
+	var errs []string
+	for _, dep := range deps {
+		// 地址可以是逗号分隔的多个副本（见 pkg/discovery）
+		for _, addr := range strings.Split(dep.Addr(), ",") {
+			addr = strings.TrimSpace(addr)
+			host, depPort, err := net.SplitHostPort(addr)
+			if err == nil {
+				err = checkPort(depPort)
+			}
+			if err != nil {
+				errs = append(errs, fmt.Sprintf("%s address %q: %v", dep.Name, addr, err))
+				continue
+			}
+			if selfPort != "" && depPort == selfPort && isLocal(host) {
+				errs = append(errs, fmt.Sprintf("%s address %q points at %s itself", dep.Name, addr, selfName))
+			}
+		}
+	}
+	return errs
+}
+
+func joinErrors(errs []string) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	return fmt.Errorf("invalid service addressing: %s", strings.Join(errs, "; "))
+}
+
+func checkPort(port string) error {
+	n, err := strconv.Atoi(port)
+	if err != nil || n < 1 || n > 65535 {
+		return fmt.Errorf("invalid port %q", port)
+	}
+	return nil
+}
+
+func isLocal(host string) bool {
+	switch host {
+	case "", "localhost", "127.0.0.1", "::1", "0.0.0.0", "::":
+		return true
+	}
+	return false
+}
+
+func firstEnv(keys []string) string {
+	for _, k := range keys {
+		if v := strings.TrimSpace(os.Getenv(k)); v != "" {
+			return v
+		}
+	}
+	return ""
+}
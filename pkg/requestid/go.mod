@@ -0,0 +1,15 @@
+module github.com/RigelNana/arkstudy/pkg/requestid
+
+go 1.24.0
+
+toolchain go1.24.7
+
+require google.golang.org/grpc v1.75.1
+
+require (
+	golang.org/x/net v0.43.0 // indirect
+	golang.org/x/sys v0.36.0 // indirect
+	golang.org/x/text v0.29.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20250707201910-8d1bb00bc6a7 // indirect
+	google.golang.org/protobuf v1.36.9 // indirect
+)
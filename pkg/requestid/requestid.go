@@ -0,0 +1,101 @@
+// Package requestid 在一次请求经过的各服务之间传递请求 ID，便于按用户反馈的 ID 在各服务日志中定位。
+//
+// gateway 沿用客户端传入的 X-Request-ID（格式不合法时重新生成）并写入响应头；请求 ID 随 ctx 传递，
+// 经 gRPC metadata（x-request-id）与 Kafka 消息头（X-Request-ID，见 pkg/kafka）传给下游。
+// discovery.Dial 创建的连接会自动携带 ctx 中的请求 ID，服务端由 pkg/interceptor 取出放入 ctx。
+package requestid
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// Header HTTP 请求头与 Kafka 消息头中的请求 ID
+const Header = "X-Request-ID"
+
+// metadataKey gRPC metadata 中的请求 ID，metadata 的 key 均为小写
+const metadataKey = "x-request-id"
+
+// 客户端传入的请求 ID 最大长度
+const maxLength = 128
+
+type ctxKey struct{}
+
+// New 生成新的请求 ID（32 位十六进制）
+func New() string {
+	var b [16]byte
+	rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// Valid 判断外部传入的请求 ID 是否可以沿用：非空、不超过 128 个字符，只含字母、数字与 - _ . :
+func Valid(id string) bool {
+	if id == "" || len(id) > maxLength {
+		return false
+	}
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '-', r == '_', r == '.', r == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// NewContext 返回携带请求 ID 的 ctx，id 为空时返回 ctx 本身
+func NewContext(ctx context.Context, id string) context.Context {
+	if id == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, ctxKey{}, id)
+}
+
+// FromContext 返回 ctx 中的请求 ID，没有时为空串
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(ctxKey{}).(string)
+	return id
+}
+
+// FromIncoming 将 gRPC 请求 metadata 中的请求 ID 放入 ctx，供服务端处理函数使用
+func FromIncoming(ctx context.Context) context.Context {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ctx
+	}
+	if ids := md.Get(metadataKey); len(ids) > 0 && Valid(ids[0]) {
+		return NewContext(ctx, ids[0])
+	}
+	return ctx
+}
+
+// outgoing 将 ctx 中的请求 ID 写入发出请求的 metadata，已经设置时不重复添加
+func outgoing(ctx context.Context) context.Context {
+	id := FromContext(ctx)
+	if id == "" {
+		return ctx
+	}
+	if md, ok := metadata.FromOutgoingContext(ctx); ok && len(md.Get(metadataKey)) > 0 {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, metadataKey, id)
+}
+
+// UnaryClientInterceptor 为一元调用携带 ctx 中的请求 ID
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return invoker(outgoing(ctx), method, req, reply, cc, opts...)
+	}
+}
+
+// StreamClientInterceptor 为流式调用携带 ctx 中的请求 ID
+func StreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return streamer(outgoing(ctx), desc, cc, method, opts...)
+	}
+}
@@ -9,6 +9,7 @@ package asr
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -24,9 +25,11 @@ const (
 // 处理视频请求
 type ProcessVideoRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	MaterialId    uint64                 `protobuf:"varint,1,opt,name=material_id,json=materialId,proto3" json:"material_id,omitempty"`
+	MaterialId    string                 `protobuf:"bytes,1,opt,name=material_id,json=materialId,proto3" json:"material_id,omitempty"` // material-service 中的材料UUID
 	VideoUrl      string                 `protobuf:"bytes,2,opt,name=video_url,json=videoUrl,proto3" json:"video_url,omitempty"`
 	VideoPath     string                 `protobuf:"bytes,3,opt,name=video_path,json=videoPath,proto3" json:"video_path,omitempty"`
+	UserId        string                 `protobuf:"bytes,4,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	TaskId        string                 `protobuf:"bytes,5,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"` // material-service ProcessingResult 任务ID，为空时由 asr-service 自行登记
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -61,11 +64,11 @@ func (*ProcessVideoRequest) Descriptor() ([]byte, []int) {
 	return file_asr_proto_rawDescGZIP(), []int{0}
 }
 
-func (x *ProcessVideoRequest) GetMaterialId() uint64 {
+func (x *ProcessVideoRequest) GetMaterialId() string {
 	if x != nil {
 		return x.MaterialId
 	}
-	return 0
+	return ""
 }
 
 func (x *ProcessVideoRequest) GetVideoUrl() string {
@@ -82,12 +85,27 @@ func (x *ProcessVideoRequest) GetVideoPath() string {
 	return ""
 }
 
+func (x *ProcessVideoRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *ProcessVideoRequest) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
 // 处理视频响应
 type ProcessVideoResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
 	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
 	Segments      []*ASRSegment          `protobuf:"bytes,3,rep,name=segments,proto3" json:"segments,omitempty"`
+	TaskId        string                 `protobuf:"bytes,4,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"` // 回写到 material-service 的任务ID
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -143,10 +161,18 @@ func (x *ProcessVideoResponse) GetSegments() []*ASRSegment {
 	return nil
 }
 
+func (x *ProcessVideoResponse) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
 // 获取分段请求
 type GetSegmentsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	MaterialId    uint64                 `protobuf:"varint,1,opt,name=material_id,json=materialId,proto3" json:"material_id,omitempty"`
+	MaterialId    string                 `protobuf:"bytes,1,opt,name=material_id,json=materialId,proto3" json:"material_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // 可选，提供时校验材料归属
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -181,11 +207,18 @@ func (*GetSegmentsRequest) Descriptor() ([]byte, []int) {
 	return file_asr_proto_rawDescGZIP(), []int{2}
 }
 
-func (x *GetSegmentsRequest) GetMaterialId() uint64 {
+func (x *GetSegmentsRequest) GetMaterialId() string {
 	if x != nil {
 		return x.MaterialId
 	}
-	return 0
+	return ""
+}
+
+func (x *GetSegmentsRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
 }
 
 // 获取分段响应
@@ -253,8 +286,9 @@ func (x *GetSegmentsResponse) GetSegments() []*ASRSegment {
 type SearchSegmentsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Query         string                 `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
-	MaterialId    uint64                 `protobuf:"varint,2,opt,name=material_id,json=materialId,proto3" json:"material_id,omitempty"` // 可选，指定材料ID
-	Limit         int32                  `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`                             // 可选，结果数量限制
+	MaterialId    string                 `protobuf:"bytes,2,opt,name=material_id,json=materialId,proto3" json:"material_id,omitempty"` // 可选，指定材料ID
+	Limit         int32                  `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`                            // 可选，结果数量限制
+	UserId        string                 `protobuf:"bytes,4,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -296,11 +330,11 @@ func (x *SearchSegmentsRequest) GetQuery() string {
 	return ""
 }
 
-func (x *SearchSegmentsRequest) GetMaterialId() uint64 {
+func (x *SearchSegmentsRequest) GetMaterialId() string {
 	if x != nil {
 		return x.MaterialId
 	}
-	return 0
+	return ""
 }
 
 func (x *SearchSegmentsRequest) GetLimit() int32 {
@@ -310,6 +344,13 @@ func (x *SearchSegmentsRequest) GetLimit() int32 {
 	return 0
 }
 
+func (x *SearchSegmentsRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
 // 搜索分段响应
 type SearchSegmentsResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -464,15 +505,17 @@ func (x *HealthCheckResponse) GetMessage() string {
 // ASR分段信息
 type ASRSegment struct {
 	state           protoimpl.MessageState `protogen:"open.v1"`
-	Id              uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	MaterialId      uint64                 `protobuf:"varint,2,opt,name=material_id,json=materialId,proto3" json:"material_id,omitempty"`
+	Id              string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	MaterialId      string                 `protobuf:"bytes,2,opt,name=material_id,json=materialId,proto3" json:"material_id,omitempty"`
 	StartTime       float32                `protobuf:"fixed32,3,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
 	EndTime         float32                `protobuf:"fixed32,4,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`
 	Text            string                 `protobuf:"bytes,5,opt,name=text,proto3" json:"text,omitempty"`
-	Confidence      float32                `protobuf:"fixed32,6,opt,name=confidence,proto3" json:"confidence,omitempty"`
+	Confidence      float32                `protobuf:"fixed32,6,opt,name=confidence,proto3" json:"confidence,omitempty"`                                // 由 Whisper avg_logprob 归一化的置信度，0-1
 	EmbeddingVector string                 `protobuf:"bytes,7,opt,name=embedding_vector,json=embeddingVector,proto3" json:"embedding_vector,omitempty"` // JSON格式的向量数据
-	CreatedAt       string                 `protobuf:"bytes,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
-	UpdatedAt       string                 `protobuf:"bytes,9,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	CreatedAt       *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt       *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	LowConfidence   bool                   `protobuf:"varint,12,opt,name=low_confidence,json=lowConfidence,proto3" json:"low_confidence,omitempty"` // 置信度低于阈值或疑似重复幻觉，可重新转写
+	Model           string                 `protobuf:"bytes,13,opt,name=model,proto3" json:"model,omitempty"`                                       // 产生该分段文本的转写模型
 	unknownFields   protoimpl.UnknownFields
 	sizeCache       protoimpl.SizeCache
 }
@@ -507,18 +550,18 @@ func (*ASRSegment) Descriptor() ([]byte, []int) {
 	return file_asr_proto_rawDescGZIP(), []int{8}
 }
 
-func (x *ASRSegment) GetId() uint64 {
+func (x *ASRSegment) GetId() string {
 	if x != nil {
 		return x.Id
 	}
-	return 0
+	return ""
 }
 
-func (x *ASRSegment) GetMaterialId() uint64 {
+func (x *ASRSegment) GetMaterialId() string {
 	if x != nil {
 		return x.MaterialId
 	}
-	return 0
+	return ""
 }
 
 func (x *ASRSegment) GetStartTime() float32 {
@@ -556,77 +599,1012 @@ func (x *ASRSegment) GetEmbeddingVector() string {
 	return ""
 }
 
-func (x *ASRSegment) GetCreatedAt() string {
+func (x *ASRSegment) GetCreatedAt() *timestamppb.Timestamp {
 	if x != nil {
 		return x.CreatedAt
 	}
-	return ""
+	return nil
 }
 
-func (x *ASRSegment) GetUpdatedAt() string {
+func (x *ASRSegment) GetUpdatedAt() *timestamppb.Timestamp {
 	if x != nil {
 		return x.UpdatedAt
 	}
+	return nil
+}
+
+func (x *ASRSegment) GetLowConfidence() bool {
+	if x != nil {
+		return x.LowConfidence
+	}
+	return false
+}
+
+func (x *ASRSegment) GetModel() string {
+	if x != nil {
+		return x.Model
+	}
 	return ""
 }
 
-var File_asr_proto protoreflect.FileDescriptor
+// 转写翻译请求
+type TranslateTranscriptRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	MaterialId     string                 `protobuf:"bytes,1,opt,name=material_id,json=materialId,proto3" json:"material_id,omitempty"`
+	UserId         string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	TargetLanguage string                 `protobuf:"bytes,3,opt,name=target_language,json=targetLanguage,proto3" json:"target_language,omitempty"` // 目标语言，如 "zh"、"en"
+	Force          bool                   `protobuf:"varint,4,opt,name=force,proto3" json:"force,omitempty"`                                        // 为 true 时重新翻译已有译文的分段
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
 
-const file_asr_proto_rawDesc = "" +
-	"\n" +
-	"\tasr.proto\x12\x03asr\"r\n" +
-	"\x13ProcessVideoRequest\x12\x1f\n" +
-	"\vmaterial_id\x18\x01 \x01(\x04R\n" +
-	"materialId\x12\x1b\n" +
-	"\tvideo_url\x18\x02 \x01(\tR\bvideoUrl\x12\x1d\n" +
-	"\n" +
-	"video_path\x18\x03 \x01(\tR\tvideoPath\"w\n" +
-	"\x14ProcessVideoResponse\x12\x18\n" +
-	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
-	"\amessage\x18\x02 \x01(\tR\amessage\x12+\n" +
-	"\bsegments\x18\x03 \x03(\v2\x0f.asr.ASRSegmentR\bsegments\"5\n" +
-	"\x12GetSegmentsRequest\x12\x1f\n" +
-	"\vmaterial_id\x18\x01 \x01(\x04R\n" +
-	"materialId\"v\n" +
-	"\x13GetSegmentsResponse\x12\x18\n" +
-	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
-	"\amessage\x18\x02 \x01(\tR\amessage\x12+\n" +
-	"\bsegments\x18\x03 \x03(\v2\x0f.asr.ASRSegmentR\bsegments\"d\n" +
-	"\x15SearchSegmentsRequest\x12\x14\n" +
-	"\x05query\x18\x01 \x01(\tR\x05query\x12\x1f\n" +
-	"\vmaterial_id\x18\x02 \x01(\x04R\n" +
-	"materialId\x12\x14\n" +
-	"\x05limit\x18\x03 \x01(\x05R\x05limit\"y\n" +
-	"\x16SearchSegmentsResponse\x12\x18\n" +
-	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
-	"\amessage\x18\x02 \x01(\tR\amessage\x12+\n" +
-	"\bsegments\x18\x03 \x03(\v2\x0f.asr.ASRSegmentR\bsegments\"\x14\n" +
-	"\x12HealthCheckRequest\"G\n" +
-	"\x13HealthCheckResponse\x12\x16\n" +
-	"\x06status\x18\x01 \x01(\tR\x06status\x12\x18\n" +
-	"\amessage\x18\x02 \x01(\tR\amessage\"\x94\x02\n" +
-	"\n" +
-	"ASRSegment\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\x04R\x02id\x12\x1f\n" +
-	"\vmaterial_id\x18\x02 \x01(\x04R\n" +
-	"materialId\x12\x1d\n" +
-	"\n" +
-	"start_time\x18\x03 \x01(\x02R\tstartTime\x12\x19\n" +
-	"\bend_time\x18\x04 \x01(\x02R\aendTime\x12\x12\n" +
-	"\x04text\x18\x05 \x01(\tR\x04text\x12\x1e\n" +
-	"\n" +
-	"confidence\x18\x06 \x01(\x02R\n" +
-	"confidence\x12)\n" +
-	"\x10embedding_vector\x18\a \x01(\tR\x0fembeddingVector\x12\x1d\n" +
-	"\n" +
-	"created_at\x18\b \x01(\tR\tcreatedAt\x12\x1d\n" +
-	"\n" +
-	"updated_at\x18\t \x01(\tR\tupdatedAt2\xa0\x02\n" +
+func (x *TranslateTranscriptRequest) Reset() {
+	*x = TranslateTranscriptRequest{}
+	mi := &file_asr_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TranslateTranscriptRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TranslateTranscriptRequest) ProtoMessage() {}
+
+func (x *TranslateTranscriptRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_asr_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TranslateTranscriptRequest.ProtoReflect.Descriptor instead.
+func (*TranslateTranscriptRequest) Descriptor() ([]byte, []int) {
+	return file_asr_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *TranslateTranscriptRequest) GetMaterialId() string {
+	if x != nil {
+		return x.MaterialId
+	}
+	return ""
+}
+
+func (x *TranslateTranscriptRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *TranslateTranscriptRequest) GetTargetLanguage() string {
+	if x != nil {
+		return x.TargetLanguage
+	}
+	return ""
+}
+
+func (x *TranslateTranscriptRequest) GetForce() bool {
+	if x != nil {
+		return x.Force
+	}
+	return false
+}
+
+// 原文分段及其译文
+type TranslatedSegment struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Segment        *ASRSegment            `protobuf:"bytes,1,opt,name=segment,proto3" json:"segment,omitempty"`
+	TranslatedText string                 `protobuf:"bytes,2,opt,name=translated_text,json=translatedText,proto3" json:"translated_text,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *TranslatedSegment) Reset() {
+	*x = TranslatedSegment{}
+	mi := &file_asr_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TranslatedSegment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TranslatedSegment) ProtoMessage() {}
+
+func (x *TranslatedSegment) ProtoReflect() protoreflect.Message {
+	mi := &file_asr_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TranslatedSegment.ProtoReflect.Descriptor instead.
+func (*TranslatedSegment) Descriptor() ([]byte, []int) {
+	return file_asr_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *TranslatedSegment) GetSegment() *ASRSegment {
+	if x != nil {
+		return x.Segment
+	}
+	return nil
+}
+
+func (x *TranslatedSegment) GetTranslatedText() string {
+	if x != nil {
+		return x.TranslatedText
+	}
+	return ""
+}
+
+// 转写翻译响应
+type TranslateTranscriptResponse struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Success         bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message         string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	TargetLanguage  string                 `protobuf:"bytes,3,opt,name=target_language,json=targetLanguage,proto3" json:"target_language,omitempty"`
+	Segments        []*TranslatedSegment   `protobuf:"bytes,4,rep,name=segments,proto3" json:"segments,omitempty"`
+	TranslatedCount int32                  `protobuf:"varint,5,opt,name=translated_count,json=translatedCount,proto3" json:"translated_count,omitempty"` // 本次新翻译的分段数
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *TranslateTranscriptResponse) Reset() {
+	*x = TranslateTranscriptResponse{}
+	mi := &file_asr_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TranslateTranscriptResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TranslateTranscriptResponse) ProtoMessage() {}
+
+func (x *TranslateTranscriptResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_asr_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TranslateTranscriptResponse.ProtoReflect.Descriptor instead.
+func (*TranslateTranscriptResponse) Descriptor() ([]byte, []int) {
+	return file_asr_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *TranslateTranscriptResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *TranslateTranscriptResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *TranslateTranscriptResponse) GetTargetLanguage() string {
+	if x != nil {
+		return x.TargetLanguage
+	}
+	return ""
+}
+
+func (x *TranslateTranscriptResponse) GetSegments() []*TranslatedSegment {
+	if x != nil {
+		return x.Segments
+	}
+	return nil
+}
+
+func (x *TranslateTranscriptResponse) GetTranslatedCount() int32 {
+	if x != nil {
+		return x.TranslatedCount
+	}
+	return 0
+}
+
+// 章节查询请求
+type GetChaptersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MaterialId    string                 `protobuf:"bytes,1,opt,name=material_id,json=materialId,proto3" json:"material_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Regenerate    bool                   `protobuf:"varint,3,opt,name=regenerate,proto3" json:"regenerate,omitempty"` // 为 true 时重新检测章节
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetChaptersRequest) Reset() {
+	*x = GetChaptersRequest{}
+	mi := &file_asr_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetChaptersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetChaptersRequest) ProtoMessage() {}
+
+func (x *GetChaptersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_asr_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetChaptersRequest.ProtoReflect.Descriptor instead.
+func (*GetChaptersRequest) Descriptor() ([]byte, []int) {
+	return file_asr_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *GetChaptersRequest) GetMaterialId() string {
+	if x != nil {
+		return x.MaterialId
+	}
+	return ""
+}
+
+func (x *GetChaptersRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *GetChaptersRequest) GetRegenerate() bool {
+	if x != nil {
+		return x.Regenerate
+	}
+	return false
+}
+
+// 转写章节
+type ASRChapter struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	MaterialId    string                 `protobuf:"bytes,2,opt,name=material_id,json=materialId,proto3" json:"material_id,omitempty"`
+	ChapterIndex  int32                  `protobuf:"varint,3,opt,name=chapter_index,json=chapterIndex,proto3" json:"chapter_index,omitempty"`
+	Title         string                 `protobuf:"bytes,4,opt,name=title,proto3" json:"title,omitempty"`
+	Summary       string                 `protobuf:"bytes,5,opt,name=summary,proto3" json:"summary,omitempty"`
+	StartTime     float32                `protobuf:"fixed32,6,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	EndTime       float32                `protobuf:"fixed32,7,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`
+	StartSegment  int32                  `protobuf:"varint,8,opt,name=start_segment,json=startSegment,proto3" json:"start_segment,omitempty"`
+	EndSegment    int32                  `protobuf:"varint,9,opt,name=end_segment,json=endSegment,proto3" json:"end_segment,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ASRChapter) Reset() {
+	*x = ASRChapter{}
+	mi := &file_asr_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ASRChapter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ASRChapter) ProtoMessage() {}
+
+func (x *ASRChapter) ProtoReflect() protoreflect.Message {
+	mi := &file_asr_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ASRChapter.ProtoReflect.Descriptor instead.
+func (*ASRChapter) Descriptor() ([]byte, []int) {
+	return file_asr_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ASRChapter) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ASRChapter) GetMaterialId() string {
+	if x != nil {
+		return x.MaterialId
+	}
+	return ""
+}
+
+func (x *ASRChapter) GetChapterIndex() int32 {
+	if x != nil {
+		return x.ChapterIndex
+	}
+	return 0
+}
+
+func (x *ASRChapter) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *ASRChapter) GetSummary() string {
+	if x != nil {
+		return x.Summary
+	}
+	return ""
+}
+
+func (x *ASRChapter) GetStartTime() float32 {
+	if x != nil {
+		return x.StartTime
+	}
+	return 0
+}
+
+func (x *ASRChapter) GetEndTime() float32 {
+	if x != nil {
+		return x.EndTime
+	}
+	return 0
+}
+
+func (x *ASRChapter) GetStartSegment() int32 {
+	if x != nil {
+		return x.StartSegment
+	}
+	return 0
+}
+
+func (x *ASRChapter) GetEndSegment() int32 {
+	if x != nil {
+		return x.EndSegment
+	}
+	return 0
+}
+
+// 章节查询响应
+type GetChaptersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Chapters      []*ASRChapter          `protobuf:"bytes,3,rep,name=chapters,proto3" json:"chapters,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetChaptersResponse) Reset() {
+	*x = GetChaptersResponse{}
+	mi := &file_asr_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetChaptersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetChaptersResponse) ProtoMessage() {}
+
+func (x *GetChaptersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_asr_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetChaptersResponse.ProtoReflect.Descriptor instead.
+func (*GetChaptersResponse) Descriptor() ([]byte, []int) {
+	return file_asr_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *GetChaptersResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *GetChaptersResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *GetChaptersResponse) GetChapters() []*ASRChapter {
+	if x != nil {
+		return x.Chapters
+	}
+	return nil
+}
+
+// 重新转写分段请求
+type RetranscribeSegmentsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MaterialId    string                 `protobuf:"bytes,1,opt,name=material_id,json=materialId,proto3" json:"material_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	VideoUrl      string                 `protobuf:"bytes,3,opt,name=video_url,json=videoUrl,proto3" json:"video_url,omitempty"`
+	SegmentIds    []string               `protobuf:"bytes,4,rep,name=segment_ids,json=segmentIds,proto3" json:"segment_ids,omitempty"` // 可选，默认为全部低置信度分段
+	Model         string                 `protobuf:"bytes,5,opt,name=model,proto3" json:"model,omitempty"`                             // 可选，默认为 ASR_RETRANSCRIBE_MODEL
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RetranscribeSegmentsRequest) Reset() {
+	*x = RetranscribeSegmentsRequest{}
+	mi := &file_asr_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RetranscribeSegmentsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RetranscribeSegmentsRequest) ProtoMessage() {}
+
+func (x *RetranscribeSegmentsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_asr_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RetranscribeSegmentsRequest.ProtoReflect.Descriptor instead.
+func (*RetranscribeSegmentsRequest) Descriptor() ([]byte, []int) {
+	return file_asr_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *RetranscribeSegmentsRequest) GetMaterialId() string {
+	if x != nil {
+		return x.MaterialId
+	}
+	return ""
+}
+
+func (x *RetranscribeSegmentsRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *RetranscribeSegmentsRequest) GetVideoUrl() string {
+	if x != nil {
+		return x.VideoUrl
+	}
+	return ""
+}
+
+func (x *RetranscribeSegmentsRequest) GetSegmentIds() []string {
+	if x != nil {
+		return x.SegmentIds
+	}
+	return nil
+}
+
+func (x *RetranscribeSegmentsRequest) GetModel() string {
+	if x != nil {
+		return x.Model
+	}
+	return ""
+}
+
+// 重新转写分段响应
+type RetranscribeSegmentsResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Success        bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message        string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Model          string                 `protobuf:"bytes,3,opt,name=model,proto3" json:"model,omitempty"`
+	Segments       []*ASRSegment          `protobuf:"bytes,4,rep,name=segments,proto3" json:"segments,omitempty"`                                    // 重新转写后的分段
+	RequestedCount int32                  `protobuf:"varint,5,opt,name=requested_count,json=requestedCount,proto3" json:"requested_count,omitempty"` // 重新转写的分段数
+	ImprovedCount  int32                  `protobuf:"varint,6,opt,name=improved_count,json=improvedCount,proto3" json:"improved_count,omitempty"`    // 结果更可信而被替换的分段数
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *RetranscribeSegmentsResponse) Reset() {
+	*x = RetranscribeSegmentsResponse{}
+	mi := &file_asr_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RetranscribeSegmentsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RetranscribeSegmentsResponse) ProtoMessage() {}
+
+func (x *RetranscribeSegmentsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_asr_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RetranscribeSegmentsResponse.ProtoReflect.Descriptor instead.
+func (*RetranscribeSegmentsResponse) Descriptor() ([]byte, []int) {
+	return file_asr_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *RetranscribeSegmentsResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *RetranscribeSegmentsResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *RetranscribeSegmentsResponse) GetModel() string {
+	if x != nil {
+		return x.Model
+	}
+	return ""
+}
+
+func (x *RetranscribeSegmentsResponse) GetSegments() []*ASRSegment {
+	if x != nil {
+		return x.Segments
+	}
+	return nil
+}
+
+func (x *RetranscribeSegmentsResponse) GetRequestedCount() int32 {
+	if x != nil {
+		return x.RequestedCount
+	}
+	return 0
+}
+
+func (x *RetranscribeSegmentsResponse) GetImprovedCount() int32 {
+	if x != nil {
+		return x.ImprovedCount
+	}
+	return 0
+}
+
+// 跨视频片段检索请求，collection_id 与 material_ids 至少提供一个
+type SearchMomentsRequest struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Query            string                 `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	UserId           string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	CollectionId     string                 `protobuf:"bytes,3,opt,name=collection_id,json=collectionId,proto3" json:"collection_id,omitempty"`                // 课程合集（bundle 资料）ID，检索其中的全部音视频
+	MaterialIds      []string               `protobuf:"bytes,4,rep,name=material_ids,json=materialIds,proto3" json:"material_ids,omitempty"`                   // 额外指定的音视频资料
+	Limit            int32                  `protobuf:"varint,5,opt,name=limit,proto3" json:"limit,omitempty"`                                                 // 返回的片段数，默认 10
+	PerMaterialLimit int32                  `protobuf:"varint,6,opt,name=per_material_limit,json=perMaterialLimit,proto3" json:"per_material_limit,omitempty"` // 单个视频最多返回的片段数，默认 3；为负数时不限制
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *SearchMomentsRequest) Reset() {
+	*x = SearchMomentsRequest{}
+	mi := &file_asr_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchMomentsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchMomentsRequest) ProtoMessage() {}
+
+func (x *SearchMomentsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_asr_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchMomentsRequest.ProtoReflect.Descriptor instead.
+func (*SearchMomentsRequest) Descriptor() ([]byte, []int) {
+	return file_asr_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *SearchMomentsRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *SearchMomentsRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *SearchMomentsRequest) GetCollectionId() string {
+	if x != nil {
+		return x.CollectionId
+	}
+	return ""
+}
+
+func (x *SearchMomentsRequest) GetMaterialIds() []string {
+	if x != nil {
+		return x.MaterialIds
+	}
+	return nil
+}
+
+func (x *SearchMomentsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *SearchMomentsRequest) GetPerMaterialLimit() int32 {
+	if x != nil {
+		return x.PerMaterialLimit
+	}
+	return 0
+}
+
+// 视频片段检索结果，start_time / end_time 为秒
+type VideoMoment struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MaterialId    string                 `protobuf:"bytes,1,opt,name=material_id,json=materialId,proto3" json:"material_id,omitempty"`
+	MaterialTitle string                 `protobuf:"bytes,2,opt,name=material_title,json=materialTitle,proto3" json:"material_title,omitempty"`
+	StartTime     float32                `protobuf:"fixed32,3,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	EndTime       float32                `protobuf:"fixed32,4,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`
+	Text          string                 `protobuf:"bytes,5,opt,name=text,proto3" json:"text,omitempty"`
+	Score         float32                `protobuf:"fixed32,6,opt,name=score,proto3" json:"score,omitempty"`
+	SegmentId     string                 `protobuf:"bytes,7,opt,name=segment_id,json=segmentId,proto3" json:"segment_id,omitempty"` // 片段起始分段的 ID
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VideoMoment) Reset() {
+	*x = VideoMoment{}
+	mi := &file_asr_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VideoMoment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VideoMoment) ProtoMessage() {}
+
+func (x *VideoMoment) ProtoReflect() protoreflect.Message {
+	mi := &file_asr_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VideoMoment.ProtoReflect.Descriptor instead.
+func (*VideoMoment) Descriptor() ([]byte, []int) {
+	return file_asr_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *VideoMoment) GetMaterialId() string {
+	if x != nil {
+		return x.MaterialId
+	}
+	return ""
+}
+
+func (x *VideoMoment) GetMaterialTitle() string {
+	if x != nil {
+		return x.MaterialTitle
+	}
+	return ""
+}
+
+func (x *VideoMoment) GetStartTime() float32 {
+	if x != nil {
+		return x.StartTime
+	}
+	return 0
+}
+
+func (x *VideoMoment) GetEndTime() float32 {
+	if x != nil {
+		return x.EndTime
+	}
+	return 0
+}
+
+func (x *VideoMoment) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *VideoMoment) GetScore() float32 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+func (x *VideoMoment) GetSegmentId() string {
+	if x != nil {
+		return x.SegmentId
+	}
+	return ""
+}
+
+// 跨视频片段检索响应，按 score 降序
+type SearchMomentsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Moments       []*VideoMoment         `protobuf:"bytes,3,rep,name=moments,proto3" json:"moments,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchMomentsResponse) Reset() {
+	*x = SearchMomentsResponse{}
+	mi := &file_asr_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchMomentsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchMomentsResponse) ProtoMessage() {}
+
+func (x *SearchMomentsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_asr_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchMomentsResponse.ProtoReflect.Descriptor instead.
+func (*SearchMomentsResponse) Descriptor() ([]byte, []int) {
+	return file_asr_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *SearchMomentsResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *SearchMomentsResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *SearchMomentsResponse) GetMoments() []*VideoMoment {
+	if x != nil {
+		return x.Moments
+	}
+	return nil
+}
+
+var File_asr_proto protoreflect.FileDescriptor
+
+const file_asr_proto_rawDesc = "" +
+	"\n" +
+	"\tasr.proto\x12\x03asr\x1a\x1fgoogle/protobuf/timestamp.proto\"\xa4\x01\n" +
+	"\x13ProcessVideoRequest\x12\x1f\n" +
+	"\vmaterial_id\x18\x01 \x01(\tR\n" +
+	"materialId\x12\x1b\n" +
+	"\tvideo_url\x18\x02 \x01(\tR\bvideoUrl\x12\x1d\n" +
+	"\n" +
+	"video_path\x18\x03 \x01(\tR\tvideoPath\x12\x17\n" +
+	"\auser_id\x18\x04 \x01(\tR\x06userId\x12\x17\n" +
+	"\atask_id\x18\x05 \x01(\tR\x06taskId\"\x90\x01\n" +
+	"\x14ProcessVideoResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12+\n" +
+	"\bsegments\x18\x03 \x03(\v2\x0f.asr.ASRSegmentR\bsegments\x12\x17\n" +
+	"\atask_id\x18\x04 \x01(\tR\x06taskId\"N\n" +
+	"\x12GetSegmentsRequest\x12\x1f\n" +
+	"\vmaterial_id\x18\x01 \x01(\tR\n" +
+	"materialId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\"v\n" +
+	"\x13GetSegmentsResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12+\n" +
+	"\bsegments\x18\x03 \x03(\v2\x0f.asr.ASRSegmentR\bsegments\"}\n" +
+	"\x15SearchSegmentsRequest\x12\x14\n" +
+	"\x05query\x18\x01 \x01(\tR\x05query\x12\x1f\n" +
+	"\vmaterial_id\x18\x02 \x01(\tR\n" +
+	"materialId\x12\x14\n" +
+	"\x05limit\x18\x03 \x01(\x05R\x05limit\x12\x17\n" +
+	"\auser_id\x18\x04 \x01(\tR\x06userId\"y\n" +
+	"\x16SearchSegmentsResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12+\n" +
+	"\bsegments\x18\x03 \x03(\v2\x0f.asr.ASRSegmentR\bsegments\"\x14\n" +
+	"\x12HealthCheckRequest\"G\n" +
+	"\x13HealthCheckResponse\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\tR\x06status\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"\x95\x03\n" +
+	"\n" +
+	"ASRSegment\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1f\n" +
+	"\vmaterial_id\x18\x02 \x01(\tR\n" +
+	"materialId\x12\x1d\n" +
+	"\n" +
+	"start_time\x18\x03 \x01(\x02R\tstartTime\x12\x19\n" +
+	"\bend_time\x18\x04 \x01(\x02R\aendTime\x12\x12\n" +
+	"\x04text\x18\x05 \x01(\tR\x04text\x12\x1e\n" +
+	"\n" +
+	"confidence\x18\x06 \x01(\x02R\n" +
+	"confidence\x12)\n" +
+	"\x10embedding_vector\x18\a \x01(\tR\x0fembeddingVector\x129\n" +
+	"\n" +
+	"created_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12%\n" +
+	"\x0elow_confidence\x18\f \x01(\bR\rlowConfidence\x12\x14\n" +
+	"\x05model\x18\r \x01(\tR\x05modelJ\x04\b\b\x10\tJ\x04\b\t\x10\n" +
+	"\"\x95\x01\n" +
+	"\x1aTranslateTranscriptRequest\x12\x1f\n" +
+	"\vmaterial_id\x18\x01 \x01(\tR\n" +
+	"materialId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12'\n" +
+	"\x0ftarget_language\x18\x03 \x01(\tR\x0etargetLanguage\x12\x14\n" +
+	"\x05force\x18\x04 \x01(\bR\x05force\"g\n" +
+	"\x11TranslatedSegment\x12)\n" +
+	"\asegment\x18\x01 \x01(\v2\x0f.asr.ASRSegmentR\asegment\x12'\n" +
+	"\x0ftranslated_text\x18\x02 \x01(\tR\x0etranslatedText\"\xd9\x01\n" +
+	"\x1bTranslateTranscriptResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12'\n" +
+	"\x0ftarget_language\x18\x03 \x01(\tR\x0etargetLanguage\x122\n" +
+	"\bsegments\x18\x04 \x03(\v2\x16.asr.TranslatedSegmentR\bsegments\x12)\n" +
+	"\x10translated_count\x18\x05 \x01(\x05R\x0ftranslatedCount\"n\n" +
+	"\x12GetChaptersRequest\x12\x1f\n" +
+	"\vmaterial_id\x18\x01 \x01(\tR\n" +
+	"materialId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x1e\n" +
+	"\n" +
+	"regenerate\x18\x03 \x01(\bR\n" +
+	"regenerate\"\x92\x02\n" +
+	"\n" +
+	"ASRChapter\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1f\n" +
+	"\vmaterial_id\x18\x02 \x01(\tR\n" +
+	"materialId\x12#\n" +
+	"\rchapter_index\x18\x03 \x01(\x05R\fchapterIndex\x12\x14\n" +
+	"\x05title\x18\x04 \x01(\tR\x05title\x12\x18\n" +
+	"\asummary\x18\x05 \x01(\tR\asummary\x12\x1d\n" +
+	"\n" +
+	"start_time\x18\x06 \x01(\x02R\tstartTime\x12\x19\n" +
+	"\bend_time\x18\a \x01(\x02R\aendTime\x12#\n" +
+	"\rstart_segment\x18\b \x01(\x05R\fstartSegment\x12\x1f\n" +
+	"\vend_segment\x18\t \x01(\x05R\n" +
+	"endSegment\"v\n" +
+	"\x13GetChaptersResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12+\n" +
+	"\bchapters\x18\x03 \x03(\v2\x0f.asr.ASRChapterR\bchapters\"\xab\x01\n" +
+	"\x1bRetranscribeSegmentsRequest\x12\x1f\n" +
+	"\vmaterial_id\x18\x01 \x01(\tR\n" +
+	"materialId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x1b\n" +
+	"\tvideo_url\x18\x03 \x01(\tR\bvideoUrl\x12\x1f\n" +
+	"\vsegment_ids\x18\x04 \x03(\tR\n" +
+	"segmentIds\x12\x14\n" +
+	"\x05model\x18\x05 \x01(\tR\x05model\"\xe5\x01\n" +
+	"\x1cRetranscribeSegmentsResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12\x14\n" +
+	"\x05model\x18\x03 \x01(\tR\x05model\x12+\n" +
+	"\bsegments\x18\x04 \x03(\v2\x0f.asr.ASRSegmentR\bsegments\x12'\n" +
+	"\x0frequested_count\x18\x05 \x01(\x05R\x0erequestedCount\x12%\n" +
+	"\x0eimproved_count\x18\x06 \x01(\x05R\rimprovedCount\"\xd1\x01\n" +
+	"\x14SearchMomentsRequest\x12\x14\n" +
+	"\x05query\x18\x01 \x01(\tR\x05query\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12#\n" +
+	"\rcollection_id\x18\x03 \x01(\tR\fcollectionId\x12!\n" +
+	"\fmaterial_ids\x18\x04 \x03(\tR\vmaterialIds\x12\x14\n" +
+	"\x05limit\x18\x05 \x01(\x05R\x05limit\x12,\n" +
+	"\x12per_material_limit\x18\x06 \x01(\x05R\x10perMaterialLimit\"\xd8\x01\n" +
+	"\vVideoMoment\x12\x1f\n" +
+	"\vmaterial_id\x18\x01 \x01(\tR\n" +
+	"materialId\x12%\n" +
+	"\x0ematerial_title\x18\x02 \x01(\tR\rmaterialTitle\x12\x1d\n" +
+	"\n" +
+	"start_time\x18\x03 \x01(\x02R\tstartTime\x12\x19\n" +
+	"\bend_time\x18\x04 \x01(\x02R\aendTime\x12\x12\n" +
+	"\x04text\x18\x05 \x01(\tR\x04text\x12\x14\n" +
+	"\x05score\x18\x06 \x01(\x02R\x05score\x12\x1d\n" +
+	"\n" +
+	"segment_id\x18\a \x01(\tR\tsegmentId\"w\n" +
+	"\x15SearchMomentsResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12*\n" +
+	"\amoments\x18\x03 \x03(\v2\x10.asr.VideoMomentR\amoments2\xe1\x04\n" +
 	"\n" +
 	"ASRService\x12C\n" +
 	"\fProcessVideo\x12\x18.asr.ProcessVideoRequest\x1a\x19.asr.ProcessVideoResponse\x12@\n" +
 	"\vGetSegments\x12\x17.asr.GetSegmentsRequest\x1a\x18.asr.GetSegmentsResponse\x12I\n" +
-	"\x0eSearchSegments\x12\x1a.asr.SearchSegmentsRequest\x1a\x1b.asr.SearchSegmentsResponse\x12@\n" +
+	"\x0eSearchSegments\x12\x1a.asr.SearchSegmentsRequest\x1a\x1b.asr.SearchSegmentsResponse\x12X\n" +
+	"\x13TranslateTranscript\x12\x1f.asr.TranslateTranscriptRequest\x1a .asr.TranslateTranscriptResponse\x12@\n" +
+	"\vGetChapters\x12\x17.asr.GetChaptersRequest\x1a\x18.asr.GetChaptersResponse\x12F\n" +
+	"\rSearchMoments\x12\x19.asr.SearchMomentsRequest\x1a\x1a.asr.SearchMomentsResponse\x12[\n" +
+	"\x14RetranscribeSegments\x12 .asr.RetranscribeSegmentsRequest\x1a!.asr.RetranscribeSegmentsResponse\x12@\n" +
 	"\vHealthCheck\x12\x17.asr.HealthCheckRequest\x1a\x18.asr.HealthCheckResponseB)Z'github.com/RigelNana/arkstudy/proto/asrb\x06proto3"
 
 var (
@@ -641,35 +1619,62 @@ func file_asr_proto_rawDescGZIP() []byte {
 	return file_asr_proto_rawDescData
 }
 
-var file_asr_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
+var file_asr_proto_msgTypes = make([]protoimpl.MessageInfo, 20)
 var file_asr_proto_goTypes = []any{
-	(*ProcessVideoRequest)(nil),    // 0: asr.ProcessVideoRequest
-	(*ProcessVideoResponse)(nil),   // 1: asr.ProcessVideoResponse
-	(*GetSegmentsRequest)(nil),     // 2: asr.GetSegmentsRequest
-	(*GetSegmentsResponse)(nil),    // 3: asr.GetSegmentsResponse
-	(*SearchSegmentsRequest)(nil),  // 4: asr.SearchSegmentsRequest
-	(*SearchSegmentsResponse)(nil), // 5: asr.SearchSegmentsResponse
-	(*HealthCheckRequest)(nil),     // 6: asr.HealthCheckRequest
-	(*HealthCheckResponse)(nil),    // 7: asr.HealthCheckResponse
-	(*ASRSegment)(nil),             // 8: asr.ASRSegment
+	(*ProcessVideoRequest)(nil),          // 0: asr.ProcessVideoRequest
+	(*ProcessVideoResponse)(nil),         // 1: asr.ProcessVideoResponse
+	(*GetSegmentsRequest)(nil),           // 2: asr.GetSegmentsRequest
+	(*GetSegmentsResponse)(nil),          // 3: asr.GetSegmentsResponse
+	(*SearchSegmentsRequest)(nil),        // 4: asr.SearchSegmentsRequest
+	(*SearchSegmentsResponse)(nil),       // 5: asr.SearchSegmentsResponse
+	(*HealthCheckRequest)(nil),           // 6: asr.HealthCheckRequest
+	(*HealthCheckResponse)(nil),          // 7: asr.HealthCheckResponse
+	(*ASRSegment)(nil),                   // 8: asr.ASRSegment
+	(*TranslateTranscriptRequest)(nil),   // 9: asr.TranslateTranscriptRequest
+	(*TranslatedSegment)(nil),            // 10: asr.TranslatedSegment
+	(*TranslateTranscriptResponse)(nil),  // 11: asr.TranslateTranscriptResponse
+	(*GetChaptersRequest)(nil),           // 12: asr.GetChaptersRequest
+	(*ASRChapter)(nil),                   // 13: asr.ASRChapter
+	(*GetChaptersResponse)(nil),          // 14: asr.GetChaptersResponse
+	(*RetranscribeSegmentsRequest)(nil),  // 15: asr.RetranscribeSegmentsRequest
+	(*RetranscribeSegmentsResponse)(nil), // 16: asr.RetranscribeSegmentsResponse
+	(*SearchMomentsRequest)(nil),         // 17: asr.SearchMomentsRequest
+	(*VideoMoment)(nil),                  // 18: asr.VideoMoment
+	(*SearchMomentsResponse)(nil),        // 19: asr.SearchMomentsResponse
+	(*timestamppb.Timestamp)(nil),        // 20: google.protobuf.Timestamp
 }
 var file_asr_proto_depIdxs = []int32{
-	8, // 0: asr.ProcessVideoResponse.segments:type_name -> asr.ASRSegment
-	8, // 1: asr.GetSegmentsResponse.segments:type_name -> asr.ASRSegment
-	8, // 2: asr.SearchSegmentsResponse.segments:type_name -> asr.ASRSegment
-	0, // 3: asr.ASRService.ProcessVideo:input_type -> asr.ProcessVideoRequest
-	2, // 4: asr.ASRService.GetSegments:input_type -> asr.GetSegmentsRequest
-	4, // 5: asr.ASRService.SearchSegments:input_type -> asr.SearchSegmentsRequest
-	6, // 6: asr.ASRService.HealthCheck:input_type -> asr.HealthCheckRequest
-	1, // 7: asr.ASRService.ProcessVideo:output_type -> asr.ProcessVideoResponse
-	3, // 8: asr.ASRService.GetSegments:output_type -> asr.GetSegmentsResponse
-	5, // 9: asr.ASRService.SearchSegments:output_type -> asr.SearchSegmentsResponse
-	7, // 10: asr.ASRService.HealthCheck:output_type -> asr.HealthCheckResponse
-	7, // [7:11] is the sub-list for method output_type
-	3, // [3:7] is the sub-list for method input_type
-	3, // [3:3] is the sub-list for extension type_name
-	3, // [3:3] is the sub-list for extension extendee
-	0, // [0:3] is the sub-list for field type_name
+	8,  // 0: asr.ProcessVideoResponse.segments:type_name -> asr.ASRSegment
+	8,  // 1: asr.GetSegmentsResponse.segments:type_name -> asr.ASRSegment
+	8,  // 2: asr.SearchSegmentsResponse.segments:type_name -> asr.ASRSegment
+	20, // 3: asr.ASRSegment.created_at:type_name -> google.protobuf.Timestamp
+	20, // 4: asr.ASRSegment.updated_at:type_name -> google.protobuf.Timestamp
+	8,  // 5: asr.TranslatedSegment.segment:type_name -> asr.ASRSegment
+	10, // 6: asr.TranslateTranscriptResponse.segments:type_name -> asr.TranslatedSegment
+	13, // 7: asr.GetChaptersResponse.chapters:type_name -> asr.ASRChapter
+	8,  // 8: asr.RetranscribeSegmentsResponse.segments:type_name -> asr.ASRSegment
+	18, // 9: asr.SearchMomentsResponse.moments:type_name -> asr.VideoMoment
+	0,  // 10: asr.ASRService.ProcessVideo:input_type -> asr.ProcessVideoRequest
+	2,  // 11: asr.ASRService.GetSegments:input_type -> asr.GetSegmentsRequest
+	4,  // 12: asr.ASRService.SearchSegments:input_type -> asr.SearchSegmentsRequest
+	9,  // 13: asr.ASRService.TranslateTranscript:input_type -> asr.TranslateTranscriptRequest
+	12, // 14: asr.ASRService.GetChapters:input_type -> asr.GetChaptersRequest
+	17, // 15: asr.ASRService.SearchMoments:input_type -> asr.SearchMomentsRequest
+	15, // 16: asr.ASRService.RetranscribeSegments:input_type -> asr.RetranscribeSegmentsRequest
+	6,  // 17: asr.ASRService.HealthCheck:input_type -> asr.HealthCheckRequest
+	1,  // 18: asr.ASRService.ProcessVideo:output_type -> asr.ProcessVideoResponse
+	3,  // 19: asr.ASRService.GetSegments:output_type -> asr.GetSegmentsResponse
+	5,  // 20: asr.ASRService.SearchSegments:output_type -> asr.SearchSegmentsResponse
+	11, // 21: asr.ASRService.TranslateTranscript:output_type -> asr.TranslateTranscriptResponse
+	14, // 22: asr.ASRService.GetChapters:output_type -> asr.GetChaptersResponse
+	19, // 23: asr.ASRService.SearchMoments:output_type -> asr.SearchMomentsResponse
+	16, // 24: asr.ASRService.RetranscribeSegments:output_type -> asr.RetranscribeSegmentsResponse
+	7,  // 25: asr.ASRService.HealthCheck:output_type -> asr.HealthCheckResponse
+	18, // [18:26] is the sub-list for method output_type
+	10, // [10:18] is the sub-list for method input_type
+	10, // [10:10] is the sub-list for extension type_name
+	10, // [10:10] is the sub-list for extension extendee
+	0,  // [0:10] is the sub-list for field type_name
 }
 
 func init() { file_asr_proto_init() }
@@ -683,7 +1688,7 @@ func file_asr_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_asr_proto_rawDesc), len(file_asr_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   9,
+			NumMessages:   20,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -19,10 +19,14 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	ASRService_ProcessVideo_FullMethodName   = "/asr.ASRService/ProcessVideo"
-	ASRService_GetSegments_FullMethodName    = "/asr.ASRService/GetSegments"
-	ASRService_SearchSegments_FullMethodName = "/asr.ASRService/SearchSegments"
-	ASRService_HealthCheck_FullMethodName    = "/asr.ASRService/HealthCheck"
+	ASRService_ProcessVideo_FullMethodName         = "/asr.ASRService/ProcessVideo"
+	ASRService_GetSegments_FullMethodName          = "/asr.ASRService/GetSegments"
+	ASRService_SearchSegments_FullMethodName       = "/asr.ASRService/SearchSegments"
+	ASRService_TranslateTranscript_FullMethodName  = "/asr.ASRService/TranslateTranscript"
+	ASRService_GetChapters_FullMethodName          = "/asr.ASRService/GetChapters"
+	ASRService_SearchMoments_FullMethodName        = "/asr.ASRService/SearchMoments"
+	ASRService_RetranscribeSegments_FullMethodName = "/asr.ASRService/RetranscribeSegments"
+	ASRService_HealthCheck_FullMethodName          = "/asr.ASRService/HealthCheck"
 )
 
 // ASRServiceClient is the client API for ASRService service.
@@ -37,6 +41,14 @@ type ASRServiceClient interface {
 	GetSegments(ctx context.Context, in *GetSegmentsRequest, opts ...grpc.CallOption) (*GetSegmentsResponse, error)
 	// 搜索ASR分段
 	SearchSegments(ctx context.Context, in *SearchSegmentsRequest, opts ...grpc.CallOption) (*SearchSegmentsResponse, error)
+	// 将材料的转写文本翻译为目标语言（双语字幕）
+	TranslateTranscript(ctx context.Context, in *TranslateTranscriptRequest, opts ...grpc.CallOption) (*TranslateTranscriptResponse, error)
+	// 获取材料的章节大纲，regenerate 为 true 或尚未生成时重新检测
+	GetChapters(ctx context.Context, in *GetChaptersRequest, opts ...grpc.CallOption) (*GetChaptersResponse, error)
+	// 在课程（bundle 资料）或指定的多个视频中检索讲解片段，按语义相似度合并排序
+	SearchMoments(ctx context.Context, in *SearchMomentsRequest, opts ...grpc.CallOption) (*SearchMomentsResponse, error)
+	// 使用更大的模型重新转写低置信度（或指定的）分段，仅截取这些分段的音频
+	RetranscribeSegments(ctx context.Context, in *RetranscribeSegmentsRequest, opts ...grpc.CallOption) (*RetranscribeSegmentsResponse, error)
 	// 健康检查
 	HealthCheck(ctx context.Context, in *HealthCheckRequest, opts ...grpc.CallOption) (*HealthCheckResponse, error)
 }
@@ -79,6 +91,46 @@ func (c *aSRServiceClient) SearchSegments(ctx context.Context, in *SearchSegment
 	return out, nil
 }
 
+func (c *aSRServiceClient) TranslateTranscript(ctx context.Context, in *TranslateTranscriptRequest, opts ...grpc.CallOption) (*TranslateTranscriptResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TranslateTranscriptResponse)
+	err := c.cc.Invoke(ctx, ASRService_TranslateTranscript_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aSRServiceClient) GetChapters(ctx context.Context, in *GetChaptersRequest, opts ...grpc.CallOption) (*GetChaptersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetChaptersResponse)
+	err := c.cc.Invoke(ctx, ASRService_GetChapters_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aSRServiceClient) SearchMoments(ctx context.Context, in *SearchMomentsRequest, opts ...grpc.CallOption) (*SearchMomentsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SearchMomentsResponse)
+	err := c.cc.Invoke(ctx, ASRService_SearchMoments_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aSRServiceClient) RetranscribeSegments(ctx context.Context, in *RetranscribeSegmentsRequest, opts ...grpc.CallOption) (*RetranscribeSegmentsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RetranscribeSegmentsResponse)
+	err := c.cc.Invoke(ctx, ASRService_RetranscribeSegments_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *aSRServiceClient) HealthCheck(ctx context.Context, in *HealthCheckRequest, opts ...grpc.CallOption) (*HealthCheckResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(HealthCheckResponse)
@@ -101,6 +153,14 @@ type ASRServiceServer interface {
 	GetSegments(context.Context, *GetSegmentsRequest) (*GetSegmentsResponse, error)
 	// 搜索ASR分段
 	SearchSegments(context.Context, *SearchSegmentsRequest) (*SearchSegmentsResponse, error)
+	// 将材料的转写文本翻译为目标语言（双语字幕）
+	TranslateTranscript(context.Context, *TranslateTranscriptRequest) (*TranslateTranscriptResponse, error)
+	// 获取材料的章节大纲，regenerate 为 true 或尚未生成时重新检测
+	GetChapters(context.Context, *GetChaptersRequest) (*GetChaptersResponse, error)
+	// 在课程（bundle 资料）或指定的多个视频中检索讲解片段，按语义相似度合并排序
+	SearchMoments(context.Context, *SearchMomentsRequest) (*SearchMomentsResponse, error)
+	// 使用更大的模型重新转写低置信度（或指定的）分段，仅截取这些分段的音频
+	RetranscribeSegments(context.Context, *RetranscribeSegmentsRequest) (*RetranscribeSegmentsResponse, error)
 	// 健康检查
 	HealthCheck(context.Context, *HealthCheckRequest) (*HealthCheckResponse, error)
 	mustEmbedUnimplementedASRServiceServer()
@@ -122,6 +182,18 @@ func (UnimplementedASRServiceServer) GetSegments(context.Context, *GetSegmentsRe
 func (UnimplementedASRServiceServer) SearchSegments(context.Context, *SearchSegmentsRequest) (*SearchSegmentsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SearchSegments not implemented")
 }
+func (UnimplementedASRServiceServer) TranslateTranscript(context.Context, *TranslateTranscriptRequest) (*TranslateTranscriptResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TranslateTranscript not implemented")
+}
+func (UnimplementedASRServiceServer) GetChapters(context.Context, *GetChaptersRequest) (*GetChaptersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetChapters not implemented")
+}
+func (UnimplementedASRServiceServer) SearchMoments(context.Context, *SearchMomentsRequest) (*SearchMomentsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchMoments not implemented")
+}
+func (UnimplementedASRServiceServer) RetranscribeSegments(context.Context, *RetranscribeSegmentsRequest) (*RetranscribeSegmentsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RetranscribeSegments not implemented")
+}
 func (UnimplementedASRServiceServer) HealthCheck(context.Context, *HealthCheckRequest) (*HealthCheckResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method HealthCheck not implemented")
 }
@@ -200,6 +272,78 @@ func _ASRService_SearchSegments_Handler(srv interface{}, ctx context.Context, de
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ASRService_TranslateTranscript_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TranslateTranscriptRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ASRServiceServer).TranslateTranscript(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ASRService_TranslateTranscript_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ASRServiceServer).TranslateTranscript(ctx, req.(*TranslateTranscriptRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ASRService_GetChapters_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetChaptersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ASRServiceServer).GetChapters(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ASRService_GetChapters_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ASRServiceServer).GetChapters(ctx, req.(*GetChaptersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ASRService_SearchMoments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchMomentsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ASRServiceServer).SearchMoments(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ASRService_SearchMoments_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ASRServiceServer).SearchMoments(ctx, req.(*SearchMomentsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ASRService_RetranscribeSegments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RetranscribeSegmentsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ASRServiceServer).RetranscribeSegments(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ASRService_RetranscribeSegments_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ASRServiceServer).RetranscribeSegments(ctx, req.(*RetranscribeSegmentsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _ASRService_HealthCheck_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(HealthCheckRequest)
 	if err := dec(in); err != nil {
@@ -237,6 +381,22 @@ var ASRService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "SearchSegments",
 			Handler:    _ASRService_SearchSegments_Handler,
 		},
+		{
+			MethodName: "TranslateTranscript",
+			Handler:    _ASRService_TranslateTranscript_Handler,
+		},
+		{
+			MethodName: "GetChapters",
+			Handler:    _ASRService_GetChapters_Handler,
+		},
+		{
+			MethodName: "SearchMoments",
+			Handler:    _ASRService_SearchMoments_Handler,
+		},
+		{
+			MethodName: "RetranscribeSegments",
+			Handler:    _ASRService_RetranscribeSegments_Handler,
+		},
 		{
 			MethodName: "HealthCheck",
 			Handler:    _ASRService_HealthCheck_Handler,
@@ -569,6 +569,157 @@ func (x *EmbeddingResponse) GetEmbeddingId() string {
 	return ""
 }
 
+type EmbedTextsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Texts []string               `protobuf:"bytes,1,rep,name=texts,proto3" json:"texts,omitempty"`
+	// 可选：文本语言（ISO 639-1），用于选择向量模型
+	Language      string `protobuf:"bytes,2,opt,name=language,proto3" json:"language,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EmbedTextsRequest) Reset() {
+	*x = EmbedTextsRequest{}
+	mi := &file_llm_llm_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EmbedTextsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EmbedTextsRequest) ProtoMessage() {}
+
+func (x *EmbedTextsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_llm_llm_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EmbedTextsRequest.ProtoReflect.Descriptor instead.
+func (*EmbedTextsRequest) Descriptor() ([]byte, []int) {
+	return file_llm_llm_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *EmbedTextsRequest) GetTexts() []string {
+	if x != nil {
+		return x.Texts
+	}
+	return nil
+}
+
+func (x *EmbedTextsRequest) GetLanguage() string {
+	if x != nil {
+		return x.Language
+	}
+	return ""
+}
+
+type TextEmbedding struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Values        []float32              `protobuf:"fixed32,1,rep,packed,name=values,proto3" json:"values,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TextEmbedding) Reset() {
+	*x = TextEmbedding{}
+	mi := &file_llm_llm_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TextEmbedding) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TextEmbedding) ProtoMessage() {}
+
+func (x *TextEmbedding) ProtoReflect() protoreflect.Message {
+	mi := &file_llm_llm_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TextEmbedding.ProtoReflect.Descriptor instead.
+func (*TextEmbedding) Descriptor() ([]byte, []int) {
+	return file_llm_llm_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *TextEmbedding) GetValues() []float32 {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+type EmbedTextsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// 与 texts 一一对应
+	Embeddings []*TextEmbedding `protobuf:"bytes,1,rep,name=embeddings,proto3" json:"embeddings,omitempty"`
+	// 生成向量的模型，不同模型的向量不可比较
+	Model         string `protobuf:"bytes,2,opt,name=model,proto3" json:"model,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EmbedTextsResponse) Reset() {
+	*x = EmbedTextsResponse{}
+	mi := &file_llm_llm_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EmbedTextsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EmbedTextsResponse) ProtoMessage() {}
+
+func (x *EmbedTextsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_llm_llm_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EmbedTextsResponse.ProtoReflect.Descriptor instead.
+func (*EmbedTextsResponse) Descriptor() ([]byte, []int) {
+	return file_llm_llm_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *EmbedTextsResponse) GetEmbeddings() []*TextEmbedding {
+	if x != nil {
+		return x.Embeddings
+	}
+	return nil
+}
+
+func (x *EmbedTextsResponse) GetModel() string {
+	if x != nil {
+		return x.Model
+	}
+	return ""
+}
+
 // 批量分片入库
 type UpsertChunkItem struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
@@ -584,21 +735,594 @@ type UpsertChunkItem struct {
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UpsertChunkItem) Reset() {
-	*x = UpsertChunkItem{}
-	mi := &file_llm_llm_proto_msgTypes[9]
+func (x *UpsertChunkItem) Reset() {
+	*x = UpsertChunkItem{}
+	mi := &file_llm_llm_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpsertChunkItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpsertChunkItem) ProtoMessage() {}
+
+func (x *UpsertChunkItem) ProtoReflect() protoreflect.Message {
+	mi := &file_llm_llm_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpsertChunkItem.ProtoReflect.Descriptor instead.
+func (*UpsertChunkItem) Descriptor() ([]byte, []int) {
+	return file_llm_llm_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *UpsertChunkItem) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+func (x *UpsertChunkItem) GetTimecode() string {
+	if x != nil {
+		return x.Timecode
+	}
+	return ""
+}
+
+func (x *UpsertChunkItem) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *UpsertChunkItem) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+type UpsertChunksRequest struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	UserId     string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	MaterialId string                 `protobuf:"bytes,2,opt,name=material_id,json=materialId,proto3" json:"material_id,omitempty"`
+	Chunks     []*UpsertChunkItem     `protobuf:"bytes,3,rep,name=chunks,proto3" json:"chunks,omitempty"`
+	// 为 true 时先删除该资料已有的分片再写入，用于资料重新处理后重建
+	Replace       bool `protobuf:"varint,4,opt,name=replace,proto3" json:"replace,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpsertChunksRequest) Reset() {
+	*x = UpsertChunksRequest{}
+	mi := &file_llm_llm_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpsertChunksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpsertChunksRequest) ProtoMessage() {}
+
+func (x *UpsertChunksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_llm_llm_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpsertChunksRequest.ProtoReflect.Descriptor instead.
+func (*UpsertChunksRequest) Descriptor() ([]byte, []int) {
+	return file_llm_llm_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *UpsertChunksRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *UpsertChunksRequest) GetMaterialId() string {
+	if x != nil {
+		return x.MaterialId
+	}
+	return ""
+}
+
+func (x *UpsertChunksRequest) GetChunks() []*UpsertChunkItem {
+	if x != nil {
+		return x.Chunks
+	}
+	return nil
+}
+
+func (x *UpsertChunksRequest) GetReplace() bool {
+	if x != nil {
+		return x.Replace
+	}
+	return false
+}
+
+type UpsertChunksResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Inserted      int32                  `protobuf:"varint,1,opt,name=inserted,proto3" json:"inserted,omitempty"` // 实际入库的分片数
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpsertChunksResponse) Reset() {
+	*x = UpsertChunksResponse{}
+	mi := &file_llm_llm_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpsertChunksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpsertChunksResponse) ProtoMessage() {}
+
+func (x *UpsertChunksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_llm_llm_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpsertChunksResponse.ProtoReflect.Descriptor instead.
+func (*UpsertChunksResponse) Descriptor() ([]byte, []int) {
+	return file_llm_llm_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *UpsertChunksResponse) GetInserted() int32 {
+	if x != nil {
+		return x.Inserted
+	}
+	return 0
+}
+
+type DeleteChunksRequest struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	UserId     string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	MaterialId string                 `protobuf:"bytes,2,opt,name=material_id,json=materialId,proto3" json:"material_id,omitempty"`
+	// 可选：只删除分片元数据 material_version 等于该值的分片，为空时删除该资料的全部分片
+	MaterialVersion string `protobuf:"bytes,3,opt,name=material_version,json=materialVersion,proto3" json:"material_version,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *DeleteChunksRequest) Reset() {
+	*x = DeleteChunksRequest{}
+	mi := &file_llm_llm_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteChunksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteChunksRequest) ProtoMessage() {}
+
+func (x *DeleteChunksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_llm_llm_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteChunksRequest.ProtoReflect.Descriptor instead.
+func (*DeleteChunksRequest) Descriptor() ([]byte, []int) {
+	return file_llm_llm_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *DeleteChunksRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *DeleteChunksRequest) GetMaterialId() string {
+	if x != nil {
+		return x.MaterialId
+	}
+	return ""
+}
+
+func (x *DeleteChunksRequest) GetMaterialVersion() string {
+	if x != nil {
+		return x.MaterialVersion
+	}
+	return ""
+}
+
+type DeleteChunksResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Deleted       int32                  `protobuf:"varint,1,opt,name=deleted,proto3" json:"deleted,omitempty"` // 实际删除的分片数
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteChunksResponse) Reset() {
+	*x = DeleteChunksResponse{}
+	mi := &file_llm_llm_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteChunksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteChunksResponse) ProtoMessage() {}
+
+func (x *DeleteChunksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_llm_llm_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteChunksResponse.ProtoReflect.Descriptor instead.
+func (*DeleteChunksResponse) Descriptor() ([]byte, []int) {
+	return file_llm_llm_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *DeleteChunksResponse) GetDeleted() int32 {
+	if x != nil {
+		return x.Deleted
+	}
+	return 0
+}
+
+// 会话历史
+type ChatTurn struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	SessionId     string                 `protobuf:"bytes,2,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	Question      string                 `protobuf:"bytes,3,opt,name=question,proto3" json:"question,omitempty"`
+	Answer        string                 `protobuf:"bytes,4,opt,name=answer,proto3" json:"answer,omitempty"`
+	Sources       []*SourceReference     `protobuf:"bytes,5,rep,name=sources,proto3" json:"sources,omitempty"`
+	LatencyMs     int64                  `protobuf:"varint,6,opt,name=latency_ms,json=latencyMs,proto3" json:"latency_ms,omitempty"` // 从收到问题到答案生成完毕的耗时
+	Model         string                 `protobuf:"bytes,7,opt,name=model,proto3" json:"model,omitempty"`                           // 生成答案的模型，未接入外部模型时为空
+	CreatedAt     string                 `protobuf:"bytes,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`  // RFC3339
+	UserId        string                 `protobuf:"bytes,9,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`           // 提问的用户，共享会话中用于区分成员
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChatTurn) Reset() {
+	*x = ChatTurn{}
+	mi := &file_llm_llm_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChatTurn) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChatTurn) ProtoMessage() {}
+
+func (x *ChatTurn) ProtoReflect() protoreflect.Message {
+	mi := &file_llm_llm_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChatTurn.ProtoReflect.Descriptor instead.
+func (*ChatTurn) Descriptor() ([]byte, []int) {
+	return file_llm_llm_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *ChatTurn) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ChatTurn) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *ChatTurn) GetQuestion() string {
+	if x != nil {
+		return x.Question
+	}
+	return ""
+}
+
+func (x *ChatTurn) GetAnswer() string {
+	if x != nil {
+		return x.Answer
+	}
+	return ""
+}
+
+func (x *ChatTurn) GetSources() []*SourceReference {
+	if x != nil {
+		return x.Sources
+	}
+	return nil
+}
+
+func (x *ChatTurn) GetLatencyMs() int64 {
+	if x != nil {
+		return x.LatencyMs
+	}
+	return 0
+}
+
+func (x *ChatTurn) GetModel() string {
+	if x != nil {
+		return x.Model
+	}
+	return ""
+}
+
+func (x *ChatTurn) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *ChatTurn) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type SessionHistoryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SessionId     string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // 仅返回属于该用户的轮次；共享会话的成员可获取所有成员的轮次
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SessionHistoryRequest) Reset() {
+	*x = SessionHistoryRequest{}
+	mi := &file_llm_llm_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SessionHistoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SessionHistoryRequest) ProtoMessage() {}
+
+func (x *SessionHistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_llm_llm_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SessionHistoryRequest.ProtoReflect.Descriptor instead.
+func (*SessionHistoryRequest) Descriptor() ([]byte, []int) {
+	return file_llm_llm_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *SessionHistoryRequest) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *SessionHistoryRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type SessionHistoryResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Turns         []*ChatTurn            `protobuf:"bytes,3,rep,name=turns,proto3" json:"turns,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SessionHistoryResponse) Reset() {
+	*x = SessionHistoryResponse{}
+	mi := &file_llm_llm_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SessionHistoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SessionHistoryResponse) ProtoMessage() {}
+
+func (x *SessionHistoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_llm_llm_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SessionHistoryResponse.ProtoReflect.Descriptor instead.
+func (*SessionHistoryResponse) Descriptor() ([]byte, []int) {
+	return file_llm_llm_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *SessionHistoryResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *SessionHistoryResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *SessionHistoryResponse) GetTurns() []*ChatTurn {
+	if x != nil {
+		return x.Turns
+	}
+	return nil
+}
+
+// 共享会话
+// 访问控制（ACL）保存在 llm-service：共享会话的提问与历史只对成员开放，不合法的请求以 gRPC 状态码
+// NOT_FOUND / PERMISSION_DENIED / INVALID_ARGUMENT 返回；未共享的会话仍按 user_id 隔离
+type SessionMember struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Role          string                 `protobuf:"bytes,2,opt,name=role,proto3" json:"role,omitempty"`                         // owner | participant | viewer
+	JoinedAt      string                 `protobuf:"bytes,3,opt,name=joined_at,json=joinedAt,proto3" json:"joined_at,omitempty"` // RFC3339
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SessionMember) Reset() {
+	*x = SessionMember{}
+	mi := &file_llm_llm_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SessionMember) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SessionMember) ProtoMessage() {}
+
+func (x *SessionMember) ProtoReflect() protoreflect.Message {
+	mi := &file_llm_llm_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SessionMember.ProtoReflect.Descriptor instead.
+func (*SessionMember) Descriptor() ([]byte, []int) {
+	return file_llm_llm_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *SessionMember) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *SessionMember) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+func (x *SessionMember) GetJoinedAt() string {
+	if x != nil {
+		return x.JoinedAt
+	}
+	return ""
+}
+
+type SharedSession struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SessionId     string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	OwnerId       string                 `protobuf:"bytes,2,opt,name=owner_id,json=ownerId,proto3" json:"owner_id,omitempty"`
+	Title         string                 `protobuf:"bytes,3,opt,name=title,proto3" json:"title,omitempty"`
+	JoinCode      string                 `protobuf:"bytes,4,opt,name=join_code,json=joinCode,proto3" json:"join_code,omitempty"`          // 加入会话所需的邀请码，仅返回给 owner
+	DefaultRole   string                 `protobuf:"bytes,5,opt,name=default_role,json=defaultRole,proto3" json:"default_role,omitempty"` // 通过邀请码加入的成员角色：participant | viewer
+	Members       []*SessionMember       `protobuf:"bytes,6,rep,name=members,proto3" json:"members,omitempty"`
+	CreatedAt     string                 `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"` // RFC3339
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SharedSession) Reset() {
+	*x = SharedSession{}
+	mi := &file_llm_llm_proto_msgTypes[21]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UpsertChunkItem) String() string {
+func (x *SharedSession) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpsertChunkItem) ProtoMessage() {}
+func (*SharedSession) ProtoMessage() {}
 
-func (x *UpsertChunkItem) ProtoReflect() protoreflect.Message {
-	mi := &file_llm_llm_proto_msgTypes[9]
+func (x *SharedSession) ProtoReflect() protoreflect.Message {
+	mi := &file_llm_llm_proto_msgTypes[21]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -609,63 +1333,85 @@ func (x *UpsertChunkItem) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpsertChunkItem.ProtoReflect.Descriptor instead.
-func (*UpsertChunkItem) Descriptor() ([]byte, []int) {
-	return file_llm_llm_proto_rawDescGZIP(), []int{9}
+// Deprecated: Use SharedSession.ProtoReflect.Descriptor instead.
+func (*SharedSession) Descriptor() ([]byte, []int) {
+	return file_llm_llm_proto_rawDescGZIP(), []int{21}
 }
 
-func (x *UpsertChunkItem) GetContent() string {
+func (x *SharedSession) GetSessionId() string {
 	if x != nil {
-		return x.Content
+		return x.SessionId
 	}
 	return ""
 }
 
-func (x *UpsertChunkItem) GetTimecode() string {
+func (x *SharedSession) GetOwnerId() string {
 	if x != nil {
-		return x.Timecode
+		return x.OwnerId
 	}
 	return ""
 }
 
-func (x *UpsertChunkItem) GetPage() int32 {
+func (x *SharedSession) GetTitle() string {
 	if x != nil {
-		return x.Page
+		return x.Title
 	}
-	return 0
+	return ""
 }
 
-func (x *UpsertChunkItem) GetMetadata() map[string]string {
+func (x *SharedSession) GetJoinCode() string {
 	if x != nil {
-		return x.Metadata
+		return x.JoinCode
+	}
+	return ""
+}
+
+func (x *SharedSession) GetDefaultRole() string {
+	if x != nil {
+		return x.DefaultRole
+	}
+	return ""
+}
+
+func (x *SharedSession) GetMembers() []*SessionMember {
+	if x != nil {
+		return x.Members
 	}
 	return nil
 }
 
-type UpsertChunksRequest struct {
+func (x *SharedSession) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+type CreateSharedSessionRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	MaterialId    string                 `protobuf:"bytes,2,opt,name=material_id,json=materialId,proto3" json:"material_id,omitempty"`
-	Chunks        []*UpsertChunkItem     `protobuf:"bytes,3,rep,name=chunks,proto3" json:"chunks,omitempty"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // 创建者，成为会话 owner
+	Title         string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	SessionId     string                 `protobuf:"bytes,3,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"` // 可选：共享已有的私有会话，为空时新建会话
+	DefaultRole   string                 `protobuf:"bytes,4,opt,name=default_role,json=defaultRole,proto3" json:"default_role,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UpsertChunksRequest) Reset() {
-	*x = UpsertChunksRequest{}
-	mi := &file_llm_llm_proto_msgTypes[10]
+func (x *CreateSharedSessionRequest) Reset() {
+	*x = CreateSharedSessionRequest{}
+	mi := &file_llm_llm_proto_msgTypes[22]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UpsertChunksRequest) String() string {
+func (x *CreateSharedSessionRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpsertChunksRequest) ProtoMessage() {}
+func (*CreateSharedSessionRequest) ProtoMessage() {}
 
-func (x *UpsertChunksRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_llm_llm_proto_msgTypes[10]
+func (x *CreateSharedSessionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_llm_llm_proto_msgTypes[22]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -676,54 +1422,63 @@ func (x *UpsertChunksRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpsertChunksRequest.ProtoReflect.Descriptor instead.
-func (*UpsertChunksRequest) Descriptor() ([]byte, []int) {
-	return file_llm_llm_proto_rawDescGZIP(), []int{10}
+// Deprecated: Use CreateSharedSessionRequest.ProtoReflect.Descriptor instead.
+func (*CreateSharedSessionRequest) Descriptor() ([]byte, []int) {
+	return file_llm_llm_proto_rawDescGZIP(), []int{22}
 }
 
-func (x *UpsertChunksRequest) GetUserId() string {
+func (x *CreateSharedSessionRequest) GetUserId() string {
 	if x != nil {
 		return x.UserId
 	}
 	return ""
 }
 
-func (x *UpsertChunksRequest) GetMaterialId() string {
+func (x *CreateSharedSessionRequest) GetTitle() string {
 	if x != nil {
-		return x.MaterialId
+		return x.Title
 	}
 	return ""
 }
 
-func (x *UpsertChunksRequest) GetChunks() []*UpsertChunkItem {
+func (x *CreateSharedSessionRequest) GetSessionId() string {
 	if x != nil {
-		return x.Chunks
+		return x.SessionId
 	}
-	return nil
+	return ""
 }
 
-type UpsertChunksResponse struct {
+func (x *CreateSharedSessionRequest) GetDefaultRole() string {
+	if x != nil {
+		return x.DefaultRole
+	}
+	return ""
+}
+
+type JoinSharedSessionRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Inserted      int32                  `protobuf:"varint,1,opt,name=inserted,proto3" json:"inserted,omitempty"` // 实际入库的分片数
+	SessionId     string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	JoinCode      string                 `protobuf:"bytes,3,opt,name=join_code,json=joinCode,proto3" json:"join_code,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UpsertChunksResponse) Reset() {
-	*x = UpsertChunksResponse{}
-	mi := &file_llm_llm_proto_msgTypes[11]
+func (x *JoinSharedSessionRequest) Reset() {
+	*x = JoinSharedSessionRequest{}
+	mi := &file_llm_llm_proto_msgTypes[23]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UpsertChunksResponse) String() string {
+func (x *JoinSharedSessionRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpsertChunksResponse) ProtoMessage() {}
+func (*JoinSharedSessionRequest) ProtoMessage() {}
 
-func (x *UpsertChunksResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_llm_llm_proto_msgTypes[11]
+func (x *JoinSharedSessionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_llm_llm_proto_msgTypes[23]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -734,16 +1489,218 @@ func (x *UpsertChunksResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpsertChunksResponse.ProtoReflect.Descriptor instead.
-func (*UpsertChunksResponse) Descriptor() ([]byte, []int) {
-	return file_llm_llm_proto_rawDescGZIP(), []int{11}
+// Deprecated: Use JoinSharedSessionRequest.ProtoReflect.Descriptor instead.
+func (*JoinSharedSessionRequest) Descriptor() ([]byte, []int) {
+	return file_llm_llm_proto_rawDescGZIP(), []int{23}
 }
 
-func (x *UpsertChunksResponse) GetInserted() int32 {
+func (x *JoinSharedSessionRequest) GetSessionId() string {
 	if x != nil {
-		return x.Inserted
+		return x.SessionId
 	}
-	return 0
+	return ""
+}
+
+func (x *JoinSharedSessionRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *JoinSharedSessionRequest) GetJoinCode() string {
+	if x != nil {
+		return x.JoinCode
+	}
+	return ""
+}
+
+type GetSharedSessionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SessionId     string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // 请求者，须为会话成员
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSharedSessionRequest) Reset() {
+	*x = GetSharedSessionRequest{}
+	mi := &file_llm_llm_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSharedSessionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSharedSessionRequest) ProtoMessage() {}
+
+func (x *GetSharedSessionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_llm_llm_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSharedSessionRequest.ProtoReflect.Descriptor instead.
+func (*GetSharedSessionRequest) Descriptor() ([]byte, []int) {
+	return file_llm_llm_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *GetSharedSessionRequest) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *GetSharedSessionRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type SetSessionMemberRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SessionId     string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // 操作者，须为会话 owner
+	MemberId      string                 `protobuf:"bytes,3,opt,name=member_id,json=memberId,proto3" json:"member_id,omitempty"`
+	Role          string                 `protobuf:"bytes,4,opt,name=role,proto3" json:"role,omitempty"` // participant | viewer，为空时移除该成员
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetSessionMemberRequest) Reset() {
+	*x = SetSessionMemberRequest{}
+	mi := &file_llm_llm_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetSessionMemberRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetSessionMemberRequest) ProtoMessage() {}
+
+func (x *SetSessionMemberRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_llm_llm_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetSessionMemberRequest.ProtoReflect.Descriptor instead.
+func (*SetSessionMemberRequest) Descriptor() ([]byte, []int) {
+	return file_llm_llm_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *SetSessionMemberRequest) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *SetSessionMemberRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *SetSessionMemberRequest) GetMemberId() string {
+	if x != nil {
+		return x.MemberId
+	}
+	return ""
+}
+
+func (x *SetSessionMemberRequest) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+type SharedSessionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Session       *SharedSession         `protobuf:"bytes,3,opt,name=session,proto3" json:"session,omitempty"`
+	Role          string                 `protobuf:"bytes,4,opt,name=role,proto3" json:"role,omitempty"` // 请求者在会话中的角色
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SharedSessionResponse) Reset() {
+	*x = SharedSessionResponse{}
+	mi := &file_llm_llm_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SharedSessionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SharedSessionResponse) ProtoMessage() {}
+
+func (x *SharedSessionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_llm_llm_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SharedSessionResponse.ProtoReflect.Descriptor instead.
+func (*SharedSessionResponse) Descriptor() ([]byte, []int) {
+	return file_llm_llm_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *SharedSessionResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *SharedSessionResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *SharedSessionResponse) GetSession() *SharedSession {
+	if x != nil {
+		return x.Session
+	}
+	return nil
+}
+
+func (x *SharedSessionResponse) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
 }
 
 var File_llm_llm_proto protoreflect.FileDescriptor
@@ -805,7 +1762,17 @@ const file_llm_llm_proto_rawDesc = "" +
 	"\fcontent_type\x18\x03 \x01(\tR\vcontentType\"T\n" +
 	"\x11EmbeddingResponse\x12\x1c\n" +
 	"\tembedding\x18\x01 \x03(\x02R\tembedding\x12!\n" +
-	"\fembedding_id\x18\x02 \x01(\tR\vembeddingId\"\xd8\x01\n" +
+	"\fembedding_id\x18\x02 \x01(\tR\vembeddingId\"E\n" +
+	"\x11EmbedTextsRequest\x12\x14\n" +
+	"\x05texts\x18\x01 \x03(\tR\x05texts\x12\x1a\n" +
+	"\blanguage\x18\x02 \x01(\tR\blanguage\"'\n" +
+	"\rTextEmbedding\x12\x16\n" +
+	"\x06values\x18\x01 \x03(\x02R\x06values\"^\n" +
+	"\x12EmbedTextsResponse\x122\n" +
+	"\n" +
+	"embeddings\x18\x01 \x03(\v2\x12.llm.TextEmbeddingR\n" +
+	"embeddings\x12\x14\n" +
+	"\x05model\x18\x02 \x01(\tR\x05model\"\xd8\x01\n" +
 	"\x0fUpsertChunkItem\x12\x18\n" +
 	"\acontent\x18\x01 \x01(\tR\acontent\x12\x1a\n" +
 	"\btimecode\x18\x02 \x01(\tR\btimecode\x12\x12\n" +
@@ -813,21 +1780,98 @@ const file_llm_llm_proto_rawDesc = "" +
 	"\bmetadata\x18\x04 \x03(\v2\".llm.UpsertChunkItem.MetadataEntryR\bmetadata\x1a;\n" +
 	"\rMetadataEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"}\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x97\x01\n" +
 	"\x13UpsertChunksRequest\x12\x17\n" +
 	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1f\n" +
 	"\vmaterial_id\x18\x02 \x01(\tR\n" +
 	"materialId\x12,\n" +
-	"\x06chunks\x18\x03 \x03(\v2\x14.llm.UpsertChunkItemR\x06chunks\"2\n" +
+	"\x06chunks\x18\x03 \x03(\v2\x14.llm.UpsertChunkItemR\x06chunks\x12\x18\n" +
+	"\areplace\x18\x04 \x01(\bR\areplace\"2\n" +
 	"\x14UpsertChunksResponse\x12\x1a\n" +
-	"\binserted\x18\x01 \x01(\x05R\binserted2\xcb\x02\n" +
+	"\binserted\x18\x01 \x01(\x05R\binserted\"z\n" +
+	"\x13DeleteChunksRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1f\n" +
+	"\vmaterial_id\x18\x02 \x01(\tR\n" +
+	"materialId\x12)\n" +
+	"\x10material_version\x18\x03 \x01(\tR\x0fmaterialVersion\"0\n" +
+	"\x14DeleteChunksResponse\x12\x18\n" +
+	"\adeleted\x18\x01 \x01(\x05R\adeleted\"\x8a\x02\n" +
+	"\bChatTurn\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x02 \x01(\tR\tsessionId\x12\x1a\n" +
+	"\bquestion\x18\x03 \x01(\tR\bquestion\x12\x16\n" +
+	"\x06answer\x18\x04 \x01(\tR\x06answer\x12.\n" +
+	"\asources\x18\x05 \x03(\v2\x14.llm.SourceReferenceR\asources\x12\x1d\n" +
+	"\n" +
+	"latency_ms\x18\x06 \x01(\x03R\tlatencyMs\x12\x14\n" +
+	"\x05model\x18\a \x01(\tR\x05model\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\b \x01(\tR\tcreatedAt\x12\x17\n" +
+	"\auser_id\x18\t \x01(\tR\x06userId\"O\n" +
+	"\x15SessionHistoryRequest\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x01 \x01(\tR\tsessionId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\"q\n" +
+	"\x16SessionHistoryResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12#\n" +
+	"\x05turns\x18\x03 \x03(\v2\r.llm.ChatTurnR\x05turns\"Y\n" +
+	"\rSessionMember\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x12\n" +
+	"\x04role\x18\x02 \x01(\tR\x04role\x12\x1b\n" +
+	"\tjoined_at\x18\x03 \x01(\tR\bjoinedAt\"\xec\x01\n" +
+	"\rSharedSession\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x01 \x01(\tR\tsessionId\x12\x19\n" +
+	"\bowner_id\x18\x02 \x01(\tR\aownerId\x12\x14\n" +
+	"\x05title\x18\x03 \x01(\tR\x05title\x12\x1b\n" +
+	"\tjoin_code\x18\x04 \x01(\tR\bjoinCode\x12!\n" +
+	"\fdefault_role\x18\x05 \x01(\tR\vdefaultRole\x12,\n" +
+	"\amembers\x18\x06 \x03(\v2\x12.llm.SessionMemberR\amembers\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\a \x01(\tR\tcreatedAt\"\x8d\x01\n" +
+	"\x1aCreateSharedSessionRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x03 \x01(\tR\tsessionId\x12!\n" +
+	"\fdefault_role\x18\x04 \x01(\tR\vdefaultRole\"o\n" +
+	"\x18JoinSharedSessionRequest\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x01 \x01(\tR\tsessionId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x1b\n" +
+	"\tjoin_code\x18\x03 \x01(\tR\bjoinCode\"Q\n" +
+	"\x17GetSharedSessionRequest\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x01 \x01(\tR\tsessionId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\"\x82\x01\n" +
+	"\x17SetSessionMemberRequest\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x01 \x01(\tR\tsessionId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x1b\n" +
+	"\tmember_id\x18\x03 \x01(\tR\bmemberId\x12\x12\n" +
+	"\x04role\x18\x04 \x01(\tR\x04role\"\x8d\x01\n" +
+	"\x15SharedSessionResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12,\n" +
+	"\asession\x18\x03 \x01(\v2\x12.llm.SharedSessionR\asession\x12\x12\n" +
+	"\x04role\x18\x04 \x01(\tR\x04role2\xdd\x06\n" +
 	"\n" +
 	"LLMService\x12:\n" +
 	"\vAskQuestion\x12\x14.llm.QuestionRequest\x1a\x15.llm.QuestionResponse\x12<\n" +
 	"\x11AskQuestionStream\x12\x14.llm.QuestionRequest\x1a\x0f.llm.TokenChunk0\x01\x129\n" +
 	"\x0eSemanticSearch\x12\x12.llm.SearchRequest\x1a\x13.llm.SearchResponse\x12C\n" +
-	"\x12GenerateEmbeddings\x12\x15.llm.EmbeddingRequest\x1a\x16.llm.EmbeddingResponse\x12C\n" +
-	"\fUpsertChunks\x12\x18.llm.UpsertChunksRequest\x1a\x19.llm.UpsertChunksResponseB)Z'github.com/RigelNana/arkstudy/proto/llmb\x06proto3"
+	"\x12GenerateEmbeddings\x12\x15.llm.EmbeddingRequest\x1a\x16.llm.EmbeddingResponse\x12=\n" +
+	"\n" +
+	"EmbedTexts\x12\x16.llm.EmbedTextsRequest\x1a\x17.llm.EmbedTextsResponse\x12C\n" +
+	"\fUpsertChunks\x12\x18.llm.UpsertChunksRequest\x1a\x19.llm.UpsertChunksResponse\x12C\n" +
+	"\fDeleteChunks\x12\x18.llm.DeleteChunksRequest\x1a\x19.llm.DeleteChunksResponse\x12L\n" +
+	"\x11GetSessionHistory\x12\x1a.llm.SessionHistoryRequest\x1a\x1b.llm.SessionHistoryResponse\x12R\n" +
+	"\x13CreateSharedSession\x12\x1f.llm.CreateSharedSessionRequest\x1a\x1a.llm.SharedSessionResponse\x12N\n" +
+	"\x11JoinSharedSession\x12\x1d.llm.JoinSharedSessionRequest\x1a\x1a.llm.SharedSessionResponse\x12L\n" +
+	"\x10GetSharedSession\x12\x1c.llm.GetSharedSessionRequest\x1a\x1a.llm.SharedSessionResponse\x12L\n" +
+	"\x10SetSessionMember\x12\x1c.llm.SetSessionMemberRequest\x1a\x1a.llm.SharedSessionResponseB)Z'github.com/RigelNana/arkstudy/proto/llmb\x06proto3"
 
 var (
 	file_llm_llm_proto_rawDescOnce sync.Once
@@ -841,50 +1885,84 @@ func file_llm_llm_proto_rawDescGZIP() []byte {
 	return file_llm_llm_proto_rawDescData
 }
 
-var file_llm_llm_proto_msgTypes = make([]protoimpl.MessageInfo, 17)
+var file_llm_llm_proto_msgTypes = make([]protoimpl.MessageInfo, 32)
 var file_llm_llm_proto_goTypes = []any{
-	(*QuestionRequest)(nil),      // 0: llm.QuestionRequest
-	(*SourceReference)(nil),      // 1: llm.SourceReference
-	(*QuestionResponse)(nil),     // 2: llm.QuestionResponse
-	(*TokenChunk)(nil),           // 3: llm.TokenChunk
-	(*SearchRequest)(nil),        // 4: llm.SearchRequest
-	(*SearchResult)(nil),         // 5: llm.SearchResult
-	(*SearchResponse)(nil),       // 6: llm.SearchResponse
-	(*EmbeddingRequest)(nil),     // 7: llm.EmbeddingRequest
-	(*EmbeddingResponse)(nil),    // 8: llm.EmbeddingResponse
-	(*UpsertChunkItem)(nil),      // 9: llm.UpsertChunkItem
-	(*UpsertChunksRequest)(nil),  // 10: llm.UpsertChunksRequest
-	(*UpsertChunksResponse)(nil), // 11: llm.UpsertChunksResponse
-	nil,                          // 12: llm.QuestionRequest.ContextEntry
-	nil,                          // 13: llm.QuestionResponse.MetadataEntry
-	nil,                          // 14: llm.TokenChunk.MetadataEntry
-	nil,                          // 15: llm.SearchResult.MetadataEntry
-	nil,                          // 16: llm.UpsertChunkItem.MetadataEntry
+	(*QuestionRequest)(nil),            // 0: llm.QuestionRequest
+	(*SourceReference)(nil),            // 1: llm.SourceReference
+	(*QuestionResponse)(nil),           // 2: llm.QuestionResponse
+	(*TokenChunk)(nil),                 // 3: llm.TokenChunk
+	(*SearchRequest)(nil),              // 4: llm.SearchRequest
+	(*SearchResult)(nil),               // 5: llm.SearchResult
+	(*SearchResponse)(nil),             // 6: llm.SearchResponse
+	(*EmbeddingRequest)(nil),           // 7: llm.EmbeddingRequest
+	(*EmbeddingResponse)(nil),          // 8: llm.EmbeddingResponse
+	(*EmbedTextsRequest)(nil),          // 9: llm.EmbedTextsRequest
+	(*TextEmbedding)(nil),              // 10: llm.TextEmbedding
+	(*EmbedTextsResponse)(nil),         // 11: llm.EmbedTextsResponse
+	(*UpsertChunkItem)(nil),            // 12: llm.UpsertChunkItem
+	(*UpsertChunksRequest)(nil),        // 13: llm.UpsertChunksRequest
+	(*UpsertChunksResponse)(nil),       // 14: llm.UpsertChunksResponse
+	(*DeleteChunksRequest)(nil),        // 15: llm.DeleteChunksRequest
+	(*DeleteChunksResponse)(nil),       // 16: llm.DeleteChunksResponse
+	(*ChatTurn)(nil),                   // 17: llm.ChatTurn
+	(*SessionHistoryRequest)(nil),      // 18: llm.SessionHistoryRequest
+	(*SessionHistoryResponse)(nil),     // 19: llm.SessionHistoryResponse
+	(*SessionMember)(nil),              // 20: llm.SessionMember
+	(*SharedSession)(nil),              // 21: llm.SharedSession
+	(*CreateSharedSessionRequest)(nil), // 22: llm.CreateSharedSessionRequest
+	(*JoinSharedSessionRequest)(nil),   // 23: llm.JoinSharedSessionRequest
+	(*GetSharedSessionRequest)(nil),    // 24: llm.GetSharedSessionRequest
+	(*SetSessionMemberRequest)(nil),    // 25: llm.SetSessionMemberRequest
+	(*SharedSessionResponse)(nil),      // 26: llm.SharedSessionResponse
+	nil,                                // 27: llm.QuestionRequest.ContextEntry
+	nil,                                // 28: llm.QuestionResponse.MetadataEntry
+	nil,                                // 29: llm.TokenChunk.MetadataEntry
+	nil,                                // 30: llm.SearchResult.MetadataEntry
+	nil,                                // 31: llm.UpsertChunkItem.MetadataEntry
 }
 var file_llm_llm_proto_depIdxs = []int32{
-	12, // 0: llm.QuestionRequest.context:type_name -> llm.QuestionRequest.ContextEntry
+	27, // 0: llm.QuestionRequest.context:type_name -> llm.QuestionRequest.ContextEntry
 	1,  // 1: llm.QuestionResponse.sources:type_name -> llm.SourceReference
-	13, // 2: llm.QuestionResponse.metadata:type_name -> llm.QuestionResponse.MetadataEntry
-	14, // 3: llm.TokenChunk.metadata:type_name -> llm.TokenChunk.MetadataEntry
-	15, // 4: llm.SearchResult.metadata:type_name -> llm.SearchResult.MetadataEntry
+	28, // 2: llm.QuestionResponse.metadata:type_name -> llm.QuestionResponse.MetadataEntry
+	29, // 3: llm.TokenChunk.metadata:type_name -> llm.TokenChunk.MetadataEntry
+	30, // 4: llm.SearchResult.metadata:type_name -> llm.SearchResult.MetadataEntry
 	5,  // 5: llm.SearchResponse.results:type_name -> llm.SearchResult
-	16, // 6: llm.UpsertChunkItem.metadata:type_name -> llm.UpsertChunkItem.MetadataEntry
-	9,  // 7: llm.UpsertChunksRequest.chunks:type_name -> llm.UpsertChunkItem
-	0,  // 8: llm.LLMService.AskQuestion:input_type -> llm.QuestionRequest
-	0,  // 9: llm.LLMService.AskQuestionStream:input_type -> llm.QuestionRequest
-	4,  // 10: llm.LLMService.SemanticSearch:input_type -> llm.SearchRequest
-	7,  // 11: llm.LLMService.GenerateEmbeddings:input_type -> llm.EmbeddingRequest
-	10, // 12: llm.LLMService.UpsertChunks:input_type -> llm.UpsertChunksRequest
-	2,  // 13: llm.LLMService.AskQuestion:output_type -> llm.QuestionResponse
-	3,  // 14: llm.LLMService.AskQuestionStream:output_type -> llm.TokenChunk
-	6,  // 15: llm.LLMService.SemanticSearch:output_type -> llm.SearchResponse
-	8,  // 16: llm.LLMService.GenerateEmbeddings:output_type -> llm.EmbeddingResponse
-	11, // 17: llm.LLMService.UpsertChunks:output_type -> llm.UpsertChunksResponse
-	13, // [13:18] is the sub-list for method output_type
-	8,  // [8:13] is the sub-list for method input_type
-	8,  // [8:8] is the sub-list for extension type_name
-	8,  // [8:8] is the sub-list for extension extendee
-	0,  // [0:8] is the sub-list for field type_name
+	10, // 6: llm.EmbedTextsResponse.embeddings:type_name -> llm.TextEmbedding
+	31, // 7: llm.UpsertChunkItem.metadata:type_name -> llm.UpsertChunkItem.MetadataEntry
+	12, // 8: llm.UpsertChunksRequest.chunks:type_name -> llm.UpsertChunkItem
+	1,  // 9: llm.ChatTurn.sources:type_name -> llm.SourceReference
+	17, // 10: llm.SessionHistoryResponse.turns:type_name -> llm.ChatTurn
+	20, // 11: llm.SharedSession.members:type_name -> llm.SessionMember
+	21, // 12: llm.SharedSessionResponse.session:type_name -> llm.SharedSession
+	0,  // 13: llm.LLMService.AskQuestion:input_type -> llm.QuestionRequest
+	0,  // 14: llm.LLMService.AskQuestionStream:input_type -> llm.QuestionRequest
+	4,  // 15: llm.LLMService.SemanticSearch:input_type -> llm.SearchRequest
+	7,  // 16: llm.LLMService.GenerateEmbeddings:input_type -> llm.EmbeddingRequest
+	9,  // 17: llm.LLMService.EmbedTexts:input_type -> llm.EmbedTextsRequest
+	13, // 18: llm.LLMService.UpsertChunks:input_type -> llm.UpsertChunksRequest
+	15, // 19: llm.LLMService.DeleteChunks:input_type -> llm.DeleteChunksRequest
+	18, // 20: llm.LLMService.GetSessionHistory:input_type -> llm.SessionHistoryRequest
+	22, // 21: llm.LLMService.CreateSharedSession:input_type -> llm.CreateSharedSessionRequest
+	23, // 22: llm.LLMService.JoinSharedSession:input_type -> llm.JoinSharedSessionRequest
+	24, // 23: llm.LLMService.GetSharedSession:input_type -> llm.GetSharedSessionRequest
+	25, // 24: llm.LLMService.SetSessionMember:input_type -> llm.SetSessionMemberRequest
+	2,  // 25: llm.LLMService.AskQuestion:output_type -> llm.QuestionResponse
+	3,  // 26: llm.LLMService.AskQuestionStream:output_type -> llm.TokenChunk
+	6,  // 27: llm.LLMService.SemanticSearch:output_type -> llm.SearchResponse
+	8,  // 28: llm.LLMService.GenerateEmbeddings:output_type -> llm.EmbeddingResponse
+	11, // 29: llm.LLMService.EmbedTexts:output_type -> llm.EmbedTextsResponse
+	14, // 30: llm.LLMService.UpsertChunks:output_type -> llm.UpsertChunksResponse
+	16, // 31: llm.LLMService.DeleteChunks:output_type -> llm.DeleteChunksResponse
+	19, // 32: llm.LLMService.GetSessionHistory:output_type -> llm.SessionHistoryResponse
+	26, // 33: llm.LLMService.CreateSharedSession:output_type -> llm.SharedSessionResponse
+	26, // 34: llm.LLMService.JoinSharedSession:output_type -> llm.SharedSessionResponse
+	26, // 35: llm.LLMService.GetSharedSession:output_type -> llm.SharedSessionResponse
+	26, // 36: llm.LLMService.SetSessionMember:output_type -> llm.SharedSessionResponse
+	25, // [25:37] is the sub-list for method output_type
+	13, // [13:25] is the sub-list for method input_type
+	13, // [13:13] is the sub-list for extension type_name
+	13, // [13:13] is the sub-list for extension extendee
+	0,  // [0:13] is the sub-list for field type_name
 }
 
 func init() { file_llm_llm_proto_init() }
@@ -898,7 +1976,7 @@ func file_llm_llm_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_llm_llm_proto_rawDesc), len(file_llm_llm_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   17,
+			NumMessages:   32,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
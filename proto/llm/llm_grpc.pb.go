@@ -19,11 +19,18 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	LLMService_AskQuestion_FullMethodName        = "/llm.LLMService/AskQuestion"
-	LLMService_AskQuestionStream_FullMethodName  = "/llm.LLMService/AskQuestionStream"
-	LLMService_SemanticSearch_FullMethodName     = "/llm.LLMService/SemanticSearch"
-	LLMService_GenerateEmbeddings_FullMethodName = "/llm.LLMService/GenerateEmbeddings"
-	LLMService_UpsertChunks_FullMethodName       = "/llm.LLMService/UpsertChunks"
+	LLMService_AskQuestion_FullMethodName         = "/llm.LLMService/AskQuestion"
+	LLMService_AskQuestionStream_FullMethodName   = "/llm.LLMService/AskQuestionStream"
+	LLMService_SemanticSearch_FullMethodName      = "/llm.LLMService/SemanticSearch"
+	LLMService_GenerateEmbeddings_FullMethodName  = "/llm.LLMService/GenerateEmbeddings"
+	LLMService_EmbedTexts_FullMethodName          = "/llm.LLMService/EmbedTexts"
+	LLMService_UpsertChunks_FullMethodName        = "/llm.LLMService/UpsertChunks"
+	LLMService_DeleteChunks_FullMethodName        = "/llm.LLMService/DeleteChunks"
+	LLMService_GetSessionHistory_FullMethodName   = "/llm.LLMService/GetSessionHistory"
+	LLMService_CreateSharedSession_FullMethodName = "/llm.LLMService/CreateSharedSession"
+	LLMService_JoinSharedSession_FullMethodName   = "/llm.LLMService/JoinSharedSession"
+	LLMService_GetSharedSession_FullMethodName    = "/llm.LLMService/GetSharedSession"
+	LLMService_SetSessionMember_FullMethodName    = "/llm.LLMService/SetSessionMember"
 )
 
 // LLMServiceClient is the client API for LLMService service.
@@ -38,8 +45,20 @@ type LLMServiceClient interface {
 	SemanticSearch(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (*SearchResponse, error)
 	// MVP 第一阶段：生成向量嵌入
 	GenerateEmbeddings(ctx context.Context, in *EmbeddingRequest, opts ...grpc.CallOption) (*EmbeddingResponse, error)
+	// 只计算向量、不入库，用于比较任意文本（如主观题作答查重）
+	EmbedTexts(ctx context.Context, in *EmbedTextsRequest, opts ...grpc.CallOption) (*EmbedTextsResponse, error)
 	// 批量分片入库（更高吞吐）
 	UpsertChunks(ctx context.Context, in *UpsertChunksRequest, opts ...grpc.CallOption) (*UpsertChunksResponse, error)
+	// 删除资料的分片，可按资料版本删除
+	DeleteChunks(ctx context.Context, in *DeleteChunksRequest, opts ...grpc.CallOption) (*DeleteChunksResponse, error)
+	// 会话历史：按时间顺序返回某会话的全部问答轮次（用于导出）
+	GetSessionHistory(ctx context.Context, in *SessionHistoryRequest, opts ...grpc.CallOption) (*SessionHistoryResponse, error)
+	// 共享会话：多个用户加入同一 AI 会话，成员分为 owner / participant / viewer（只读，不能提问）
+	CreateSharedSession(ctx context.Context, in *CreateSharedSessionRequest, opts ...grpc.CallOption) (*SharedSessionResponse, error)
+	JoinSharedSession(ctx context.Context, in *JoinSharedSessionRequest, opts ...grpc.CallOption) (*SharedSessionResponse, error)
+	GetSharedSession(ctx context.Context, in *GetSharedSessionRequest, opts ...grpc.CallOption) (*SharedSessionResponse, error)
+	// 会话 owner 修改成员角色或移除成员
+	SetSessionMember(ctx context.Context, in *SetSessionMemberRequest, opts ...grpc.CallOption) (*SharedSessionResponse, error)
 }
 
 type lLMServiceClient struct {
@@ -99,6 +118,16 @@ func (c *lLMServiceClient) GenerateEmbeddings(ctx context.Context, in *Embedding
 	return out, nil
 }
 
+func (c *lLMServiceClient) EmbedTexts(ctx context.Context, in *EmbedTextsRequest, opts ...grpc.CallOption) (*EmbedTextsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(EmbedTextsResponse)
+	err := c.cc.Invoke(ctx, LLMService_EmbedTexts_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *lLMServiceClient) UpsertChunks(ctx context.Context, in *UpsertChunksRequest, opts ...grpc.CallOption) (*UpsertChunksResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(UpsertChunksResponse)
@@ -109,6 +138,66 @@ func (c *lLMServiceClient) UpsertChunks(ctx context.Context, in *UpsertChunksReq
 	return out, nil
 }
 
+func (c *lLMServiceClient) DeleteChunks(ctx context.Context, in *DeleteChunksRequest, opts ...grpc.CallOption) (*DeleteChunksResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteChunksResponse)
+	err := c.cc.Invoke(ctx, LLMService_DeleteChunks_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lLMServiceClient) GetSessionHistory(ctx context.Context, in *SessionHistoryRequest, opts ...grpc.CallOption) (*SessionHistoryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SessionHistoryResponse)
+	err := c.cc.Invoke(ctx, LLMService_GetSessionHistory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lLMServiceClient) CreateSharedSession(ctx context.Context, in *CreateSharedSessionRequest, opts ...grpc.CallOption) (*SharedSessionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SharedSessionResponse)
+	err := c.cc.Invoke(ctx, LLMService_CreateSharedSession_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lLMServiceClient) JoinSharedSession(ctx context.Context, in *JoinSharedSessionRequest, opts ...grpc.CallOption) (*SharedSessionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SharedSessionResponse)
+	err := c.cc.Invoke(ctx, LLMService_JoinSharedSession_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lLMServiceClient) GetSharedSession(ctx context.Context, in *GetSharedSessionRequest, opts ...grpc.CallOption) (*SharedSessionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SharedSessionResponse)
+	err := c.cc.Invoke(ctx, LLMService_GetSharedSession_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lLMServiceClient) SetSessionMember(ctx context.Context, in *SetSessionMemberRequest, opts ...grpc.CallOption) (*SharedSessionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SharedSessionResponse)
+	err := c.cc.Invoke(ctx, LLMService_SetSessionMember_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // LLMServiceServer is the server API for LLMService service.
 // All implementations must embed UnimplementedLLMServiceServer
 // for forward compatibility.
@@ -121,8 +210,20 @@ type LLMServiceServer interface {
 	SemanticSearch(context.Context, *SearchRequest) (*SearchResponse, error)
 	// MVP 第一阶段：生成向量嵌入
 	GenerateEmbeddings(context.Context, *EmbeddingRequest) (*EmbeddingResponse, error)
+	// 只计算向量、不入库，用于比较任意文本（如主观题作答查重）
+	EmbedTexts(context.Context, *EmbedTextsRequest) (*EmbedTextsResponse, error)
 	// 批量分片入库（更高吞吐）
 	UpsertChunks(context.Context, *UpsertChunksRequest) (*UpsertChunksResponse, error)
+	// 删除资料的分片，可按资料版本删除
+	DeleteChunks(context.Context, *DeleteChunksRequest) (*DeleteChunksResponse, error)
+	// 会话历史：按时间顺序返回某会话的全部问答轮次（用于导出）
+	GetSessionHistory(context.Context, *SessionHistoryRequest) (*SessionHistoryResponse, error)
+	// 共享会话：多个用户加入同一 AI 会话，成员分为 owner / participant / viewer（只读，不能提问）
+	CreateSharedSession(context.Context, *CreateSharedSessionRequest) (*SharedSessionResponse, error)
+	JoinSharedSession(context.Context, *JoinSharedSessionRequest) (*SharedSessionResponse, error)
+	GetSharedSession(context.Context, *GetSharedSessionRequest) (*SharedSessionResponse, error)
+	// 会话 owner 修改成员角色或移除成员
+	SetSessionMember(context.Context, *SetSessionMemberRequest) (*SharedSessionResponse, error)
 	mustEmbedUnimplementedLLMServiceServer()
 }
 
@@ -145,9 +246,30 @@ func (UnimplementedLLMServiceServer) SemanticSearch(context.Context, *SearchRequ
 func (UnimplementedLLMServiceServer) GenerateEmbeddings(context.Context, *EmbeddingRequest) (*EmbeddingResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GenerateEmbeddings not implemented")
 }
+func (UnimplementedLLMServiceServer) EmbedTexts(context.Context, *EmbedTextsRequest) (*EmbedTextsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EmbedTexts not implemented")
+}
 func (UnimplementedLLMServiceServer) UpsertChunks(context.Context, *UpsertChunksRequest) (*UpsertChunksResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method UpsertChunks not implemented")
 }
+func (UnimplementedLLMServiceServer) DeleteChunks(context.Context, *DeleteChunksRequest) (*DeleteChunksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteChunks not implemented")
+}
+func (UnimplementedLLMServiceServer) GetSessionHistory(context.Context, *SessionHistoryRequest) (*SessionHistoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSessionHistory not implemented")
+}
+func (UnimplementedLLMServiceServer) CreateSharedSession(context.Context, *CreateSharedSessionRequest) (*SharedSessionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateSharedSession not implemented")
+}
+func (UnimplementedLLMServiceServer) JoinSharedSession(context.Context, *JoinSharedSessionRequest) (*SharedSessionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method JoinSharedSession not implemented")
+}
+func (UnimplementedLLMServiceServer) GetSharedSession(context.Context, *GetSharedSessionRequest) (*SharedSessionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSharedSession not implemented")
+}
+func (UnimplementedLLMServiceServer) SetSessionMember(context.Context, *SetSessionMemberRequest) (*SharedSessionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetSessionMember not implemented")
+}
 func (UnimplementedLLMServiceServer) mustEmbedUnimplementedLLMServiceServer() {}
 func (UnimplementedLLMServiceServer) testEmbeddedByValue()                    {}
 
@@ -234,6 +356,24 @@ func _LLMService_GenerateEmbeddings_Handler(srv interface{}, ctx context.Context
 	return interceptor(ctx, in, info, handler)
 }
 
+func _LLMService_EmbedTexts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EmbedTextsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LLMServiceServer).EmbedTexts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LLMService_EmbedTexts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LLMServiceServer).EmbedTexts(ctx, req.(*EmbedTextsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _LLMService_UpsertChunks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(UpsertChunksRequest)
 	if err := dec(in); err != nil {
@@ -252,6 +392,114 @@ func _LLMService_UpsertChunks_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _LLMService_DeleteChunks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteChunksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LLMServiceServer).DeleteChunks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LLMService_DeleteChunks_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LLMServiceServer).DeleteChunks(ctx, req.(*DeleteChunksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LLMService_GetSessionHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SessionHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LLMServiceServer).GetSessionHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LLMService_GetSessionHistory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LLMServiceServer).GetSessionHistory(ctx, req.(*SessionHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LLMService_CreateSharedSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateSharedSessionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LLMServiceServer).CreateSharedSession(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LLMService_CreateSharedSession_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LLMServiceServer).CreateSharedSession(ctx, req.(*CreateSharedSessionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LLMService_JoinSharedSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(JoinSharedSessionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LLMServiceServer).JoinSharedSession(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LLMService_JoinSharedSession_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LLMServiceServer).JoinSharedSession(ctx, req.(*JoinSharedSessionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LLMService_GetSharedSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSharedSessionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LLMServiceServer).GetSharedSession(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LLMService_GetSharedSession_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LLMServiceServer).GetSharedSession(ctx, req.(*GetSharedSessionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LLMService_SetSessionMember_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetSessionMemberRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LLMServiceServer).SetSessionMember(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LLMService_SetSessionMember_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LLMServiceServer).SetSessionMember(ctx, req.(*SetSessionMemberRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // LLMService_ServiceDesc is the grpc.ServiceDesc for LLMService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -271,10 +519,38 @@ var LLMService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GenerateEmbeddings",
 			Handler:    _LLMService_GenerateEmbeddings_Handler,
 		},
+		{
+			MethodName: "EmbedTexts",
+			Handler:    _LLMService_EmbedTexts_Handler,
+		},
 		{
 			MethodName: "UpsertChunks",
 			Handler:    _LLMService_UpsertChunks_Handler,
 		},
+		{
+			MethodName: "DeleteChunks",
+			Handler:    _LLMService_DeleteChunks_Handler,
+		},
+		{
+			MethodName: "GetSessionHistory",
+			Handler:    _LLMService_GetSessionHistory_Handler,
+		},
+		{
+			MethodName: "CreateSharedSession",
+			Handler:    _LLMService_CreateSharedSession_Handler,
+		},
+		{
+			MethodName: "JoinSharedSession",
+			Handler:    _LLMService_JoinSharedSession_Handler,
+		},
+		{
+			MethodName: "GetSharedSession",
+			Handler:    _LLMService_GetSharedSession_Handler,
+		},
+		{
+			MethodName: "SetSessionMember",
+			Handler:    _LLMService_SetSessionMember_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -9,6 +9,7 @@ package material
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -79,6 +80,8 @@ const (
 	ProcessingStatus_PROCESSING ProcessingStatus = 1
 	ProcessingStatus_COMPLETED  ProcessingStatus = 2
 	ProcessingStatus_FAILED     ProcessingStatus = 3
+	ProcessingStatus_QUEUED     ProcessingStatus = 4 // 用户同时处理的任务数已达上限，排队等待派发
+	ProcessingStatus_SCHEDULED  ProcessingStatus = 5 // 大任务（如长视频转写）延后到处理时段开始时派发，见 ProcessingResult.scheduled_for
 )
 
 // Enum value maps for ProcessingStatus.
@@ -88,12 +91,16 @@ var (
 		1: "PROCESSING",
 		2: "COMPLETED",
 		3: "FAILED",
+		4: "QUEUED",
+		5: "SCHEDULED",
 	}
 	ProcessingStatus_value = map[string]int32{
 		"PENDING":    0,
 		"PROCESSING": 1,
 		"COMPLETED":  2,
 		"FAILED":     3,
+		"QUEUED":     4,
+		"SCHEDULED":  5,
 	}
 )
 
@@ -133,7 +140,12 @@ type MaterialInfo struct {
 	FileType         string                 `protobuf:"bytes,5,opt,name=file_type,json=fileType,proto3" json:"file_type,omitempty"`
 	SizeBytes        int64                  `protobuf:"varint,6,opt,name=size_bytes,json=sizeBytes,proto3" json:"size_bytes,omitempty"`
 	Status           string                 `protobuf:"bytes,7,opt,name=status,proto3" json:"status,omitempty"`
-	CreatedAt        string                 `protobuf:"bytes,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	CreatedAt        *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	ParentId         string                 `protobuf:"bytes,9,opt,name=parent_id,json=parentId,proto3" json:"parent_id,omitempty"`           // 由压缩包展开的子资料所属的 bundle 资料 ID
+	Media            *MediaInfo             `protobuf:"bytes,11,opt,name=media,proto3" json:"media,omitempty"`                                // 音视频元数据，非音视频或尚未探测时为空
+	Document         *DocumentInfo          `protobuf:"bytes,12,opt,name=document,proto3" json:"document,omitempty"`                          // PDF 文档信息，非 PDF 或尚未读取时为空
+	Language         string                 `protobuf:"bytes,13,opt,name=language,proto3" json:"language,omitempty"`                          // 提取文本的主要语言（ISO 639-1），尚未检测时为空
+	ContentHash      string                 `protobuf:"bytes,14,opt,name=content_hash,json=contentHash,proto3" json:"content_hash,omitempty"` // 文件内容的 SHA-256（十六进制）
 	unknownFields    protoimpl.UnknownFields
 	sizeCache        protoimpl.SizeCache
 }
@@ -217,27 +229,295 @@ func (x *MaterialInfo) GetStatus() string {
 	return ""
 }
 
-func (x *MaterialInfo) GetCreatedAt() string {
+func (x *MaterialInfo) GetCreatedAt() *timestamppb.Timestamp {
 	if x != nil {
 		return x.CreatedAt
 	}
+	return nil
+}
+
+func (x *MaterialInfo) GetParentId() string {
+	if x != nil {
+		return x.ParentId
+	}
+	return ""
+}
+
+func (x *MaterialInfo) GetMedia() *MediaInfo {
+	if x != nil {
+		return x.Media
+	}
+	return nil
+}
+
+func (x *MaterialInfo) GetDocument() *DocumentInfo {
+	if x != nil {
+		return x.Document
+	}
+	return nil
+}
+
+func (x *MaterialInfo) GetLanguage() string {
+	if x != nil {
+		return x.Language
+	}
+	return ""
+}
+
+func (x *MaterialInfo) GetContentHash() string {
+	if x != nil {
+		return x.ContentHash
+	}
+	return ""
+}
+
+// 上传后由 ffprobe 探测的音视频元数据，纯音频没有分辨率与视频编码
+type MediaInfo struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	DurationSeconds float64                `protobuf:"fixed64,1,opt,name=duration_seconds,json=durationSeconds,proto3" json:"duration_seconds,omitempty"`
+	Width           int32                  `protobuf:"varint,2,opt,name=width,proto3" json:"width,omitempty"`
+	Height          int32                  `protobuf:"varint,3,opt,name=height,proto3" json:"height,omitempty"`
+	VideoCodec      string                 `protobuf:"bytes,4,opt,name=video_codec,json=videoCodec,proto3" json:"video_codec,omitempty"`
+	AudioCodec      string                 `protobuf:"bytes,5,opt,name=audio_codec,json=audioCodec,proto3" json:"audio_codec,omitempty"`
+	FormatName      string                 `protobuf:"bytes,6,opt,name=format_name,json=formatName,proto3" json:"format_name,omitempty"`
+	ProbedAt        *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=probed_at,json=probedAt,proto3" json:"probed_at,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *MediaInfo) Reset() {
+	*x = MediaInfo{}
+	mi := &file_proto_material_material_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MediaInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MediaInfo) ProtoMessage() {}
+
+func (x *MediaInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_material_material_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MediaInfo.ProtoReflect.Descriptor instead.
+func (*MediaInfo) Descriptor() ([]byte, []int) {
+	return file_proto_material_material_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *MediaInfo) GetDurationSeconds() float64 {
+	if x != nil {
+		return x.DurationSeconds
+	}
+	return 0
+}
+
+func (x *MediaInfo) GetWidth() int32 {
+	if x != nil {
+		return x.Width
+	}
+	return 0
+}
+
+func (x *MediaInfo) GetHeight() int32 {
+	if x != nil {
+		return x.Height
+	}
+	return 0
+}
+
+func (x *MediaInfo) GetVideoCodec() string {
+	if x != nil {
+		return x.VideoCodec
+	}
+	return ""
+}
+
+func (x *MediaInfo) GetAudioCodec() string {
+	if x != nil {
+		return x.AudioCodec
+	}
+	return ""
+}
+
+func (x *MediaInfo) GetFormatName() string {
+	if x != nil {
+		return x.FormatName
+	}
+	return ""
+}
+
+func (x *MediaInfo) GetProbedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ProbedAt
+	}
+	return nil
+}
+
+// 上传后由 pdfcpu 读取的 PDF 文档信息，标题与作者取自文档信息字典，可能为空
+type DocumentInfo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PageCount     int32                  `protobuf:"varint,1,opt,name=page_count,json=pageCount,proto3" json:"page_count,omitempty"`
+	Title         string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Author        string                 `protobuf:"bytes,3,opt,name=author,proto3" json:"author,omitempty"`
+	Toc           []*TocEntry            `protobuf:"bytes,4,rep,name=toc,proto3" json:"toc,omitempty"` // 书签目录，按阅读顺序展开
+	ProbedAt      *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=probed_at,json=probedAt,proto3" json:"probed_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DocumentInfo) Reset() {
+	*x = DocumentInfo{}
+	mi := &file_proto_material_material_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DocumentInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DocumentInfo) ProtoMessage() {}
+
+func (x *DocumentInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_material_material_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DocumentInfo.ProtoReflect.Descriptor instead.
+func (*DocumentInfo) Descriptor() ([]byte, []int) {
+	return file_proto_material_material_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *DocumentInfo) GetPageCount() int32 {
+	if x != nil {
+		return x.PageCount
+	}
+	return 0
+}
+
+func (x *DocumentInfo) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *DocumentInfo) GetAuthor() string {
+	if x != nil {
+		return x.Author
+	}
+	return ""
+}
+
+func (x *DocumentInfo) GetToc() []*TocEntry {
+	if x != nil {
+		return x.Toc
+	}
+	return nil
+}
+
+func (x *DocumentInfo) GetProbedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ProbedAt
+	}
+	return nil
+}
+
+type TocEntry struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Title         string                 `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"`
+	Page          int32                  `protobuf:"varint,2,opt,name=page,proto3" json:"page,omitempty"`   // 从 1 开始，书签未指向页面时为 0
+	Level         int32                  `protobuf:"varint,3,opt,name=level,proto3" json:"level,omitempty"` // 从 1 开始
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TocEntry) Reset() {
+	*x = TocEntry{}
+	mi := &file_proto_material_material_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TocEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TocEntry) ProtoMessage() {}
+
+func (x *TocEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_material_material_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TocEntry.ProtoReflect.Descriptor instead.
+func (*TocEntry) Descriptor() ([]byte, []int) {
+	return file_proto_material_material_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *TocEntry) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
 	return ""
 }
 
+func (x *TocEntry) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *TocEntry) GetLevel() int32 {
+	if x != nil {
+		return x.Level
+	}
+	return 0
+}
+
 type UploadMaterialRequest struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	// Types that are valid to be assigned to Data:
 	//
 	//	*UploadMaterialRequest_Metadata
 	//	*UploadMaterialRequest_ChunkData
-	Data          isUploadMaterialRequest_Data `protobuf_oneof:"data"`
+	Data isUploadMaterialRequest_Data `protobuf_oneof:"data"`
+	// 与已有资料重复时的处理方式，只在第一条消息中读取：warn（默认）照常保存并返回重复的资料，
+	// link 不保存新文件，返回最匹配的已有资料
+	OnDuplicate   string `protobuf:"bytes,3,opt,name=on_duplicate,json=onDuplicate,proto3" json:"on_duplicate,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *UploadMaterialRequest) Reset() {
 	*x = UploadMaterialRequest{}
-	mi := &file_proto_material_material_proto_msgTypes[1]
+	mi := &file_proto_material_material_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -249,7 +529,7 @@ func (x *UploadMaterialRequest) String() string {
 func (*UploadMaterialRequest) ProtoMessage() {}
 
 func (x *UploadMaterialRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_material_material_proto_msgTypes[1]
+	mi := &file_proto_material_material_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -262,7 +542,7 @@ func (x *UploadMaterialRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UploadMaterialRequest.ProtoReflect.Descriptor instead.
 func (*UploadMaterialRequest) Descriptor() ([]byte, []int) {
-	return file_proto_material_material_proto_rawDescGZIP(), []int{1}
+	return file_proto_material_material_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *UploadMaterialRequest) GetData() isUploadMaterialRequest_Data {
@@ -290,6 +570,13 @@ func (x *UploadMaterialRequest) GetChunkData() []byte {
 	return nil
 }
 
+func (x *UploadMaterialRequest) GetOnDuplicate() string {
+	if x != nil {
+		return x.OnDuplicate
+	}
+	return ""
+}
+
 type isUploadMaterialRequest_Data interface {
 	isUploadMaterialRequest_Data()
 }
@@ -310,14 +597,16 @@ type UploadMaterialResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
 	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
-	MaterialId    string                 `protobuf:"bytes,3,opt,name=material_id,json=materialId,proto3" json:"material_id,omitempty"`
+	MaterialId    string                 `protobuf:"bytes,3,opt,name=material_id,json=materialId,proto3" json:"material_id,omitempty"` // linked 为 true 时为已有资料的 ID
+	Duplicates    []*DuplicateMaterial   `protobuf:"bytes,4,rep,name=duplicates,proto3" json:"duplicates,omitempty"`
+	Linked        bool                   `protobuf:"varint,5,opt,name=linked,proto3" json:"linked,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *UploadMaterialResponse) Reset() {
 	*x = UploadMaterialResponse{}
-	mi := &file_proto_material_material_proto_msgTypes[2]
+	mi := &file_proto_material_material_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -329,7 +618,7 @@ func (x *UploadMaterialResponse) String() string {
 func (*UploadMaterialResponse) ProtoMessage() {}
 
 func (x *UploadMaterialResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_material_material_proto_msgTypes[2]
+	mi := &file_proto_material_material_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -342,7 +631,7 @@ func (x *UploadMaterialResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UploadMaterialResponse.ProtoReflect.Descriptor instead.
 func (*UploadMaterialResponse) Descriptor() ([]byte, []int) {
-	return file_proto_material_material_proto_rawDescGZIP(), []int{2}
+	return file_proto_material_material_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *UploadMaterialResponse) GetSuccess() bool {
@@ -366,29 +655,45 @@ func (x *UploadMaterialResponse) GetMaterialId() string {
 	return ""
 }
 
-type DeleteMaterialRequest struct {
+func (x *UploadMaterialResponse) GetDuplicates() []*DuplicateMaterial {
+	if x != nil {
+		return x.Duplicates
+	}
+	return nil
+}
+
+func (x *UploadMaterialResponse) GetLinked() bool {
+	if x != nil {
+		return x.Linked
+	}
+	return false
+}
+
+// 内容重复的资料。match 为 content_hash（文件完全相同）或 simhash（提取文本相近）
+type DuplicateMaterial struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	MaterialId    string                 `protobuf:"bytes,1,opt,name=material_id,json=materialId,proto3" json:"material_id,omitempty"`
-	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Material      *MaterialInfo          `protobuf:"bytes,1,opt,name=material,proto3" json:"material,omitempty"`
+	Match         string                 `protobuf:"bytes,2,opt,name=match,proto3" json:"match,omitempty"`
+	Distance      int32                  `protobuf:"varint,3,opt,name=distance,proto3" json:"distance,omitempty"` // SimHash 汉明距离，content_hash 时为 0
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DeleteMaterialRequest) Reset() {
-	*x = DeleteMaterialRequest{}
-	mi := &file_proto_material_material_proto_msgTypes[3]
+func (x *DuplicateMaterial) Reset() {
+	*x = DuplicateMaterial{}
+	mi := &file_proto_material_material_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DeleteMaterialRequest) String() string {
+func (x *DuplicateMaterial) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteMaterialRequest) ProtoMessage() {}
+func (*DuplicateMaterial) ProtoMessage() {}
 
-func (x *DeleteMaterialRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_material_material_proto_msgTypes[3]
+func (x *DuplicateMaterial) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_material_material_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -399,48 +704,3473 @@ func (x *DeleteMaterialRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteMaterialRequest.ProtoReflect.Descriptor instead.
-func (*DeleteMaterialRequest) Descriptor() ([]byte, []int) {
-	return file_proto_material_material_proto_rawDescGZIP(), []int{3}
+// Deprecated: Use DuplicateMaterial.ProtoReflect.Descriptor instead.
+func (*DuplicateMaterial) Descriptor() ([]byte, []int) {
+	return file_proto_material_material_proto_rawDescGZIP(), []int{6}
 }
 
-func (x *DeleteMaterialRequest) GetMaterialId() string {
+func (x *DuplicateMaterial) GetMaterial() *MaterialInfo {
 	if x != nil {
-		return x.MaterialId
+		return x.Material
 	}
-	return ""
+	return nil
 }
 
-func (x *DeleteMaterialRequest) GetUserId() string {
+func (x *DuplicateMaterial) GetMatch() string {
 	if x != nil {
-		return x.UserId
+		return x.Match
 	}
 	return ""
 }
 
-type DeleteMaterialResponse struct {
+func (x *DuplicateMaterial) GetDistance() int32 {
+	if x != nil {
+		return x.Distance
+	}
+	return 0
+}
+
+type ListDuplicateMaterialsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
-	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	MaterialId    string                 `protobuf:"bytes,1,opt,name=material_id,json=materialId,proto3" json:"material_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DeleteMaterialResponse) Reset() {
-	*x = DeleteMaterialResponse{}
-	mi := &file_proto_material_material_proto_msgTypes[4]
+func (x *ListDuplicateMaterialsRequest) Reset() {
+	*x = ListDuplicateMaterialsRequest{}
+	mi := &file_proto_material_material_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DeleteMaterialResponse) String() string {
+func (x *ListDuplicateMaterialsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteMaterialResponse) ProtoMessage() {}
+func (*ListDuplicateMaterialsRequest) ProtoMessage() {}
+
+func (x *ListDuplicateMaterialsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_material_material_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDuplicateMaterialsRequest.ProtoReflect.Descriptor instead.
+func (*ListDuplicateMaterialsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_material_material_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ListDuplicateMaterialsRequest) GetMaterialId() string {
+	if x != nil {
+		return x.MaterialId
+	}
+	return ""
+}
+
+func (x *ListDuplicateMaterialsRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type ListDuplicateMaterialsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Duplicates    []*DuplicateMaterial   `protobuf:"bytes,3,rep,name=duplicates,proto3" json:"duplicates,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDuplicateMaterialsResponse) Reset() {
+	*x = ListDuplicateMaterialsResponse{}
+	mi := &file_proto_material_material_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDuplicateMaterialsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDuplicateMaterialsResponse) ProtoMessage() {}
+
+func (x *ListDuplicateMaterialsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_material_material_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDuplicateMaterialsResponse.ProtoReflect.Descriptor instead.
+func (*ListDuplicateMaterialsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_material_material_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ListDuplicateMaterialsResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *ListDuplicateMaterialsResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *ListDuplicateMaterialsResponse) GetDuplicates() []*DuplicateMaterial {
+	if x != nil {
+		return x.Duplicates
+	}
+	return nil
+}
+
+type CreateClipRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Title         string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`                                // 可选，缺省取页面标题或正文开头
+	Content       string                 `protobuf:"bytes,3,opt,name=content,proto3" json:"content,omitempty"`                            // 选中的文本或 HTML 片段
+	ContentType   string                 `protobuf:"bytes,4,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"` // text（默认）或 html
+	SourceUrl     string                 `protobuf:"bytes,5,opt,name=source_url,json=sourceUrl,proto3" json:"source_url,omitempty"`
+	PageTitle     string                 `protobuf:"bytes,6,opt,name=page_title,json=pageTitle,proto3" json:"page_title,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateClipRequest) Reset() {
+	*x = CreateClipRequest{}
+	mi := &file_proto_material_material_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateClipRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateClipRequest) ProtoMessage() {}
+
+func (x *CreateClipRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_material_material_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateClipRequest.ProtoReflect.Descriptor instead.
+func (*CreateClipRequest) Descriptor() ([]byte, []int) {
+	return file_proto_material_material_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *CreateClipRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *CreateClipRequest) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *CreateClipRequest) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+func (x *CreateClipRequest) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+func (x *CreateClipRequest) GetSourceUrl() string {
+	if x != nil {
+		return x.SourceUrl
+	}
+	return ""
+}
+
+func (x *CreateClipRequest) GetPageTitle() string {
+	if x != nil {
+		return x.PageTitle
+	}
+	return ""
+}
+
+type CreateClipResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Material      *MaterialInfo          `protobuf:"bytes,3,opt,name=material,proto3" json:"material,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateClipResponse) Reset() {
+	*x = CreateClipResponse{}
+	mi := &file_proto_material_material_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateClipResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateClipResponse) ProtoMessage() {}
+
+func (x *CreateClipResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_material_material_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateClipResponse.ProtoReflect.Descriptor instead.
+func (*CreateClipResponse) Descriptor() ([]byte, []int) {
+	return file_proto_material_material_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *CreateClipResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *CreateClipResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *CreateClipResponse) GetMaterial() *MaterialInfo {
+	if x != nil {
+		return x.Material
+	}
+	return nil
+}
+
+type DeleteMaterialRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MaterialId    string                 `protobuf:"bytes,1,opt,name=material_id,json=materialId,proto3" json:"material_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteMaterialRequest) Reset() {
+	*x = DeleteMaterialRequest{}
+	mi := &file_proto_material_material_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteMaterialRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteMaterialRequest) ProtoMessage() {}
+
+func (x *DeleteMaterialRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_material_material_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteMaterialRequest.ProtoReflect.Descriptor instead.
+func (*DeleteMaterialRequest) Descriptor() ([]byte, []int) {
+	return file_proto_material_material_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *DeleteMaterialRequest) GetMaterialId() string {
+	if x != nil {
+		return x.MaterialId
+	}
+	return ""
+}
+
+func (x *DeleteMaterialRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type DeleteMaterialResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteMaterialResponse) Reset() {
+	*x = DeleteMaterialResponse{}
+	mi := &file_proto_material_material_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteMaterialResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteMaterialResponse) ProtoMessage() {}
+
+func (x *DeleteMaterialResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_material_material_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteMaterialResponse.ProtoReflect.Descriptor instead.
+func (*DeleteMaterialResponse) Descriptor() ([]byte, []int) {
+	return file_proto_material_material_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *DeleteMaterialResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *DeleteMaterialResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type ListMaterialsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Page          int32                  `protobuf:"varint,2,opt,name=page,proto3" json:"page,omitempty"`
+	PageSize      int32                  `protobuf:"varint,3,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListMaterialsRequest) Reset() {
+	*x = ListMaterialsRequest{}
+	mi := &file_proto_material_material_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListMaterialsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListMaterialsRequest) ProtoMessage() {}
+
+func (x *ListMaterialsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_material_material_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListMaterialsRequest.ProtoReflect.Descriptor instead.
+func (*ListMaterialsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_material_material_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ListMaterialsRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *ListMaterialsRequest) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *ListMaterialsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+type ListMaterialsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Materials     []*MaterialInfo        `protobuf:"bytes,1,rep,name=materials,proto3" json:"materials,omitempty"`
+	Total         int64                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListMaterialsResponse) Reset() {
+	*x = ListMaterialsResponse{}
+	mi := &file_proto_material_material_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListMaterialsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListMaterialsResponse) ProtoMessage() {}
+
+func (x *ListMaterialsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_material_material_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListMaterialsResponse.ProtoReflect.Descriptor instead.
+func (*ListMaterialsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_material_material_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ListMaterialsResponse) GetMaterials() []*MaterialInfo {
+	if x != nil {
+		return x.Materials
+	}
+	return nil
+}
+
+func (x *ListMaterialsResponse) GetTotal() int64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+type ListChildMaterialsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MaterialId    string                 `protobuf:"bytes,1,opt,name=material_id,json=materialId,proto3" json:"material_id,omitempty"` // bundle 资料 ID
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListChildMaterialsRequest) Reset() {
+	*x = ListChildMaterialsRequest{}
+	mi := &file_proto_material_material_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListChildMaterialsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListChildMaterialsRequest) ProtoMessage() {}
+
+func (x *ListChildMaterialsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_material_material_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListChildMaterialsRequest.ProtoReflect.Descriptor instead.
+func (*ListChildMaterialsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_material_material_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ListChildMaterialsRequest) GetMaterialId() string {
+	if x != nil {
+		return x.MaterialId
+	}
+	return ""
+}
+
+func (x *ListChildMaterialsRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type ListChildMaterialsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Materials     []*MaterialInfo        `protobuf:"bytes,3,rep,name=materials,proto3" json:"materials,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListChildMaterialsResponse) Reset() {
+	*x = ListChildMaterialsResponse{}
+	mi := &file_proto_material_material_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListChildMaterialsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListChildMaterialsResponse) ProtoMessage() {}
+
+func (x *ListChildMaterialsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_material_material_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListChildMaterialsResponse.ProtoReflect.Descriptor instead.
+func (*ListChildMaterialsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_material_material_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *ListChildMaterialsResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *ListChildMaterialsResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *ListChildMaterialsResponse) GetMaterials() []*MaterialInfo {
+	if x != nil {
+		return x.Materials
+	}
+	return nil
+}
+
+type GetMaterialRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MaterialId    string                 `protobuf:"bytes,1,opt,name=material_id,json=materialId,proto3" json:"material_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // 可选，提供时校验材料归属
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetMaterialRequest) Reset() {
+	*x = GetMaterialRequest{}
+	mi := &file_proto_material_material_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetMaterialRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetMaterialRequest) ProtoMessage() {}
+
+func (x *GetMaterialRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_material_material_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetMaterialRequest.ProtoReflect.Descriptor instead.
+func (*GetMaterialRequest) Descriptor() ([]byte, []int) {
+	return file_proto_material_material_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *GetMaterialRequest) GetMaterialId() string {
+	if x != nil {
+		return x.MaterialId
+	}
+	return ""
+}
+
+func (x *GetMaterialRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type GetMaterialResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Found         bool                   `protobuf:"varint,1,opt,name=found,proto3" json:"found,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Material      *MaterialInfo          `protobuf:"bytes,3,opt,name=material,proto3" json:"material,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetMaterialResponse) Reset() {
+	*x = GetMaterialResponse{}
+	mi := &file_proto_material_material_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetMaterialResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetMaterialResponse) ProtoMessage() {}
+
+func (x *GetMaterialResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_material_material_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetMaterialResponse.ProtoReflect.Descriptor instead.
+func (*GetMaterialResponse) Descriptor() ([]byte, []int) {
+	return file_proto_material_material_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *GetMaterialResponse) GetFound() bool {
+	if x != nil {
+		return x.Found
+	}
+	return false
+}
+
+func (x *GetMaterialResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *GetMaterialResponse) GetMaterial() *MaterialInfo {
+	if x != nil {
+		return x.Material
+	}
+	return nil
+}
+
+// 获取资料文件的预签名下载地址（用于媒体播放，支持 Range 请求）
+type GetMaterialURLRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MaterialId    string                 `protobuf:"bytes,1,opt,name=material_id,json=materialId,proto3" json:"material_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	ExpirySeconds int64                  `protobuf:"varint,3,opt,name=expiry_seconds,json=expirySeconds,proto3" json:"expiry_seconds,omitempty"` // 可选，默认 3600，最长 7 天
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetMaterialURLRequest) Reset() {
+	*x = GetMaterialURLRequest{}
+	mi := &file_proto_material_material_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetMaterialURLRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetMaterialURLRequest) ProtoMessage() {}
+
+func (x *GetMaterialURLRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_material_material_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetMaterialURLRequest.ProtoReflect.Descriptor instead.
+func (*GetMaterialURLRequest) Descriptor() ([]byte, []int) {
+	return file_proto_material_material_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *GetMaterialURLRequest) GetMaterialId() string {
+	if x != nil {
+		return x.MaterialId
+	}
+	return ""
+}
+
+func (x *GetMaterialURLRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *GetMaterialURLRequest) GetExpirySeconds() int64 {
+	if x != nil {
+		return x.ExpirySeconds
+	}
+	return 0
+}
+
+type GetMaterialURLResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Url           string                 `protobuf:"bytes,3,opt,name=url,proto3" json:"url,omitempty"`
+	ContentType   string                 `protobuf:"bytes,4,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"` // 文件实际的 MIME 类型，如 video/mp4
+	SizeBytes     int64                  `protobuf:"varint,5,opt,name=size_bytes,json=sizeBytes,proto3" json:"size_bytes,omitempty"`
+	ExpiresAt     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetMaterialURLResponse) Reset() {
+	*x = GetMaterialURLResponse{}
+	mi := &file_proto_material_material_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetMaterialURLResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetMaterialURLResponse) ProtoMessage() {}
+
+func (x *GetMaterialURLResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_material_material_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetMaterialURLResponse.ProtoReflect.Descriptor instead.
+func (*GetMaterialURLResponse) Descriptor() ([]byte, []int) {
+	return file_proto_material_material_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *GetMaterialURLResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *GetMaterialURLResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *GetMaterialURLResponse) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *GetMaterialURLResponse) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+func (x *GetMaterialURLResponse) GetSizeBytes() int64 {
+	if x != nil {
+		return x.SizeBytes
+	}
+	return 0
+}
+
+func (x *GetMaterialURLResponse) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+// 处理结果信息
+type ProcessingResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	MaterialId    string                 `protobuf:"bytes,2,opt,name=material_id,json=materialId,proto3" json:"material_id,omitempty"`
+	TaskId        string                 `protobuf:"bytes,3,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	Type          ProcessingType         `protobuf:"varint,4,opt,name=type,proto3,enum=material.ProcessingType" json:"type,omitempty"`
+	Status        ProcessingStatus       `protobuf:"varint,5,opt,name=status,proto3,enum=material.ProcessingStatus" json:"status,omitempty"`
+	Content       string                 `protobuf:"bytes,6,opt,name=content,proto3" json:"content,omitempty"`
+	Metadata      map[string]string      `protobuf:"bytes,7,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,13,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,14,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	ErrorMessage  string                 `protobuf:"bytes,10,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
+	RetryCount    int32                  `protobuf:"varint,11,opt,name=retry_count,json=retryCount,proto3" json:"retry_count,omitempty"`      // 手动重试次数
+	Progress      float32                `protobuf:"fixed32,12,opt,name=progress,proto3" json:"progress,omitempty"`                           // 处理进度 0~1，完成时为 1
+	Shadow        bool                   `protobuf:"varint,15,opt,name=shadow,proto3" json:"shadow,omitempty"`                                // 对比处理的结果，不替换资料当前的处理结果
+	ScheduledFor  *timestamppb.Timestamp `protobuf:"bytes,16,opt,name=scheduled_for,json=scheduledFor,proto3" json:"scheduled_for,omitempty"` // 延后处理的任务计划派发的时间
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProcessingResult) Reset() {
+	*x = ProcessingResult{}
+	mi := &file_proto_material_material_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProcessingResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProcessingResult) ProtoMessage() {}
+
+func (x *ProcessingResult) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_material_material_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProcessingResult.ProtoReflect.Descriptor instead.
+func (*ProcessingResult) Descriptor() ([]byte, []int) {
+	return file_proto_material_material_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *ProcessingResult) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ProcessingResult) GetMaterialId() string {
+	if x != nil {
+		return x.MaterialId
+	}
+	return ""
+}
+
+func (x *ProcessingResult) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
+func (x *ProcessingResult) GetType() ProcessingType {
+	if x != nil {
+		return x.Type
+	}
+	return ProcessingType_OCR
+}
+
+func (x *ProcessingResult) GetStatus() ProcessingStatus {
+	if x != nil {
+		return x.Status
+	}
+	return ProcessingStatus_PENDING
+}
+
+func (x *ProcessingResult) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+func (x *ProcessingResult) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *ProcessingResult) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *ProcessingResult) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *ProcessingResult) GetErrorMessage() string {
+	if x != nil {
+		return x.ErrorMessage
+	}
+	return ""
+}
+
+func (x *ProcessingResult) GetRetryCount() int32 {
+	if x != nil {
+		return x.RetryCount
+	}
+	return 0
+}
+
+func (x *ProcessingResult) GetProgress() float32 {
+	if x != nil {
+		return x.Progress
+	}
+	return 0
+}
+
+func (x *ProcessingResult) GetShadow() bool {
+	if x != nil {
+		return x.Shadow
+	}
+	return false
+}
+
+func (x *ProcessingResult) GetScheduledFor() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ScheduledFor
+	}
+	return nil
+}
+
+// 开始处理材料请求
+type ProcessMaterialRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MaterialId    string                 `protobuf:"bytes,1,opt,name=material_id,json=materialId,proto3" json:"material_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Type          ProcessingType         `protobuf:"varint,3,opt,name=type,proto3,enum=material.ProcessingType" json:"type,omitempty"`
+	Options       map[string]string      `protobuf:"bytes,4,rep,name=options,proto3" json:"options,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // 处理选项，如语言、模型等；force=true 时即使已有完成结果也重新处理
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProcessMaterialRequest) Reset() {
+	*x = ProcessMaterialRequest{}
+	mi := &file_proto_material_material_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProcessMaterialRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProcessMaterialRequest) ProtoMessage() {}
+
+func (x *ProcessMaterialRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_material_material_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProcessMaterialRequest.ProtoReflect.Descriptor instead.
+func (*ProcessMaterialRequest) Descriptor() ([]byte, []int) {
+	return file_proto_material_material_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *ProcessMaterialRequest) GetMaterialId() string {
+	if x != nil {
+		return x.MaterialId
+	}
+	return ""
+}
+
+func (x *ProcessMaterialRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *ProcessMaterialRequest) GetType() ProcessingType {
+	if x != nil {
+		return x.Type
+	}
+	return ProcessingType_OCR
+}
+
+func (x *ProcessMaterialRequest) GetOptions() map[string]string {
+	if x != nil {
+		return x.Options
+	}
+	return nil
+}
+
+// 用户 OCR / ASR 任务的并发用量，上限为 0 表示不限制
+type ProcessingLimit struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	InFlight      int32                  `protobuf:"varint,1,opt,name=in_flight,json=inFlight,proto3" json:"in_flight,omitempty"` // 等待或正在处理的任务数
+	Queued        int32                  `protobuf:"varint,2,opt,name=queued,proto3" json:"queued,omitempty"`                     // 排队中的任务数
+	MaxInFlight   int32                  `protobuf:"varint,3,opt,name=max_in_flight,json=maxInFlight,proto3" json:"max_in_flight,omitempty"`
+	MaxQueued     int32                  `protobuf:"varint,4,opt,name=max_queued,json=maxQueued,proto3" json:"max_queued,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProcessingLimit) Reset() {
+	*x = ProcessingLimit{}
+	mi := &file_proto_material_material_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProcessingLimit) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProcessingLimit) ProtoMessage() {}
+
+func (x *ProcessingLimit) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_material_material_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProcessingLimit.ProtoReflect.Descriptor instead.
+func (*ProcessingLimit) Descriptor() ([]byte, []int) {
+	return file_proto_material_material_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *ProcessingLimit) GetInFlight() int32 {
+	if x != nil {
+		return x.InFlight
+	}
+	return 0
+}
+
+func (x *ProcessingLimit) GetQueued() int32 {
+	if x != nil {
+		return x.Queued
+	}
+	return 0
+}
+
+func (x *ProcessingLimit) GetMaxInFlight() int32 {
+	if x != nil {
+		return x.MaxInFlight
+	}
+	return 0
+}
+
+func (x *ProcessingLimit) GetMaxQueued() int32 {
+	if x != nil {
+		return x.MaxQueued
+	}
+	return 0
+}
+
+// 开始处理材料响应
+type ProcessMaterialResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	TaskId        string                 `protobuf:"bytes,3,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	Result        *ProcessingResult      `protobuf:"bytes,4,opt,name=result,proto3" json:"result,omitempty"`
+	Limit         *ProcessingLimit       `protobuf:"bytes,5,opt,name=limit,proto3" json:"limit,omitempty"` // 任务进入排队或因超出上限被拒绝时返回当前用量
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProcessMaterialResponse) Reset() {
+	*x = ProcessMaterialResponse{}
+	mi := &file_proto_material_material_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProcessMaterialResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProcessMaterialResponse) ProtoMessage() {}
+
+func (x *ProcessMaterialResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_material_material_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProcessMaterialResponse.ProtoReflect.Descriptor instead.
+func (*ProcessMaterialResponse) Descriptor() ([]byte, []int) {
+	return file_proto_material_material_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *ProcessMaterialResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *ProcessMaterialResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *ProcessMaterialResponse) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
+func (x *ProcessMaterialResponse) GetResult() *ProcessingResult {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+func (x *ProcessMaterialResponse) GetLimit() *ProcessingLimit {
+	if x != nil {
+		return x.Limit
+	}
+	return nil
+}
+
+// 获取处理结果请求
+type GetProcessingResultRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MaterialId    string                 `protobuf:"bytes,1,opt,name=material_id,json=materialId,proto3" json:"material_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Type          ProcessingType         `protobuf:"varint,3,opt,name=type,proto3,enum=material.ProcessingType" json:"type,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetProcessingResultRequest) Reset() {
+	*x = GetProcessingResultRequest{}
+	mi := &file_proto_material_material_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetProcessingResultRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetProcessingResultRequest) ProtoMessage() {}
+
+func (x *GetProcessingResultRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_material_material_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetProcessingResultRequest.ProtoReflect.Descriptor instead.
+func (*GetProcessingResultRequest) Descriptor() ([]byte, []int) {
+	return file_proto_material_material_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *GetProcessingResultRequest) GetMaterialId() string {
+	if x != nil {
+		return x.MaterialId
+	}
+	return ""
+}
+
+func (x *GetProcessingResultRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *GetProcessingResultRequest) GetType() ProcessingType {
+	if x != nil {
+		return x.Type
+	}
+	return ProcessingType_OCR
+}
+
+// 获取处理结果响应
+type GetProcessingResultResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Found         bool                   `protobuf:"varint,1,opt,name=found,proto3" json:"found,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Result        *ProcessingResult      `protobuf:"bytes,3,opt,name=result,proto3" json:"result,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetProcessingResultResponse) Reset() {
+	*x = GetProcessingResultResponse{}
+	mi := &file_proto_material_material_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetProcessingResultResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetProcessingResultResponse) ProtoMessage() {}
+
+func (x *GetProcessingResultResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_material_material_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetProcessingResultResponse.ProtoReflect.Descriptor instead.
+func (*GetProcessingResultResponse) Descriptor() ([]byte, []int) {
+	return file_proto_material_material_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *GetProcessingResultResponse) GetFound() bool {
+	if x != nil {
+		return x.Found
+	}
+	return false
+}
+
+func (x *GetProcessingResultResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *GetProcessingResultResponse) GetResult() *ProcessingResult {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+// 列出处理结果请求
+type ListProcessingResultsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MaterialId    string                 `protobuf:"bytes,1,opt,name=material_id,json=materialId,proto3" json:"material_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Type          ProcessingType         `protobuf:"varint,3,opt,name=type,proto3,enum=material.ProcessingType" json:"type,omitempty"` // 可选，筛选特定类型
+	Page          int32                  `protobuf:"varint,4,opt,name=page,proto3" json:"page,omitempty"`
+	PageSize      int32                  `protobuf:"varint,5,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListProcessingResultsRequest) Reset() {
+	*x = ListProcessingResultsRequest{}
+	mi := &file_proto_material_material_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListProcessingResultsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListProcessingResultsRequest) ProtoMessage() {}
+
+func (x *ListProcessingResultsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_material_material_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListProcessingResultsRequest.ProtoReflect.Descriptor instead.
+func (*ListProcessingResultsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_material_material_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *ListProcessingResultsRequest) GetMaterialId() string {
+	if x != nil {
+		return x.MaterialId
+	}
+	return ""
+}
+
+func (x *ListProcessingResultsRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *ListProcessingResultsRequest) GetType() ProcessingType {
+	if x != nil {
+		return x.Type
+	}
+	return ProcessingType_OCR
+}
+
+func (x *ListProcessingResultsRequest) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *ListProcessingResultsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+// 列出处理结果响应
+type ListProcessingResultsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Results       []*ProcessingResult    `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	Total         int64                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListProcessingResultsResponse) Reset() {
+	*x = ListProcessingResultsResponse{}
+	mi := &file_proto_material_material_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListProcessingResultsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListProcessingResultsResponse) ProtoMessage() {}
+
+func (x *ListProcessingResultsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_material_material_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListProcessingResultsResponse.ProtoReflect.Descriptor instead.
+func (*ListProcessingResultsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_material_material_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *ListProcessingResultsResponse) GetResults() []*ProcessingResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+func (x *ListProcessingResultsResponse) GetTotal() int64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+// 更新处理结果请求 (供AI服务回调使用)
+type UpdateProcessingResultRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TaskId        string                 `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	Status        ProcessingStatus       `protobuf:"varint,2,opt,name=status,proto3,enum=material.ProcessingStatus" json:"status,omitempty"`
+	Content       string                 `protobuf:"bytes,3,opt,name=content,proto3" json:"content,omitempty"`
+	Metadata      map[string]string      `protobuf:"bytes,4,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	ErrorMessage  string                 `protobuf:"bytes,5,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateProcessingResultRequest) Reset() {
+	*x = UpdateProcessingResultRequest{}
+	mi := &file_proto_material_material_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateProcessingResultRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateProcessingResultRequest) ProtoMessage() {}
+
+func (x *UpdateProcessingResultRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_material_material_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateProcessingResultRequest.ProtoReflect.Descriptor instead.
+func (*UpdateProcessingResultRequest) Descriptor() ([]byte, []int) {
+	return file_proto_material_material_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *UpdateProcessingResultRequest) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
+func (x *UpdateProcessingResultRequest) GetStatus() ProcessingStatus {
+	if x != nil {
+		return x.Status
+	}
+	return ProcessingStatus_PENDING
+}
+
+func (x *UpdateProcessingResultRequest) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+func (x *UpdateProcessingResultRequest) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *UpdateProcessingResultRequest) GetErrorMessage() string {
+	if x != nil {
+		return x.ErrorMessage
+	}
+	return ""
+}
+
+// 更新处理结果响应
+type UpdateProcessingResultResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateProcessingResultResponse) Reset() {
+	*x = UpdateProcessingResultResponse{}
+	mi := &file_proto_material_material_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateProcessingResultResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateProcessingResultResponse) ProtoMessage() {}
+
+func (x *UpdateProcessingResultResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_material_material_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateProcessingResultResponse.ProtoReflect.Descriptor instead.
+func (*UpdateProcessingResultResponse) Descriptor() ([]byte, []int) {
+	return file_proto_material_material_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *UpdateProcessingResultResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *UpdateProcessingResultResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+// 更新处理进度请求 (供AI服务在处理过程中回调，只更新进度)
+type UpdateProcessingProgressRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TaskId        string                 `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	Progress      float32                `protobuf:"fixed32,2,opt,name=progress,proto3" json:"progress,omitempty"` // 0~1，超出范围会被截断
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateProcessingProgressRequest) Reset() {
+	*x = UpdateProcessingProgressRequest{}
+	mi := &file_proto_material_material_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateProcessingProgressRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateProcessingProgressRequest) ProtoMessage() {}
+
+func (x *UpdateProcessingProgressRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_material_material_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateProcessingProgressRequest.ProtoReflect.Descriptor instead.
+func (*UpdateProcessingProgressRequest) Descriptor() ([]byte, []int) {
+	return file_proto_material_material_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *UpdateProcessingProgressRequest) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
+func (x *UpdateProcessingProgressRequest) GetProgress() float32 {
+	if x != nil {
+		return x.Progress
+	}
+	return 0
+}
+
+// 更新处理进度响应
+type UpdateProcessingProgressResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateProcessingProgressResponse) Reset() {
+	*x = UpdateProcessingProgressResponse{}
+	mi := &file_proto_material_material_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateProcessingProgressResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateProcessingProgressResponse) ProtoMessage() {}
+
+func (x *UpdateProcessingProgressResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_material_material_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateProcessingProgressResponse.ProtoReflect.Descriptor instead.
+func (*UpdateProcessingProgressResponse) Descriptor() ([]byte, []int) {
+	return file_proto_material_material_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *UpdateProcessingProgressResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *UpdateProcessingProgressResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+// 重试失败任务请求
+type RetryProcessingTaskRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TaskId        string                 `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RetryProcessingTaskRequest) Reset() {
+	*x = RetryProcessingTaskRequest{}
+	mi := &file_proto_material_material_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RetryProcessingTaskRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RetryProcessingTaskRequest) ProtoMessage() {}
+
+func (x *RetryProcessingTaskRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_material_material_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RetryProcessingTaskRequest.ProtoReflect.Descriptor instead.
+func (*RetryProcessingTaskRequest) Descriptor() ([]byte, []int) {
+	return file_proto_material_material_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *RetryProcessingTaskRequest) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
+func (x *RetryProcessingTaskRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+// 重试失败任务响应
+type RetryProcessingTaskResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Result        *ProcessingResult      `protobuf:"bytes,3,opt,name=result,proto3" json:"result,omitempty"`
+	Limit         *ProcessingLimit       `protobuf:"bytes,4,opt,name=limit,proto3" json:"limit,omitempty"` // 同 ProcessMaterialResponse.limit
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RetryProcessingTaskResponse) Reset() {
+	*x = RetryProcessingTaskResponse{}
+	mi := &file_proto_material_material_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RetryProcessingTaskResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RetryProcessingTaskResponse) ProtoMessage() {}
+
+func (x *RetryProcessingTaskResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_material_material_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RetryProcessingTaskResponse.ProtoReflect.Descriptor instead.
+func (*RetryProcessingTaskResponse) Descriptor() ([]byte, []int) {
+	return file_proto_material_material_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *RetryProcessingTaskResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *RetryProcessingTaskResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *RetryProcessingTaskResponse) GetResult() *ProcessingResult {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+func (x *RetryProcessingTaskResponse) GetLimit() *ProcessingLimit {
+	if x != nil {
+		return x.Limit
+	}
+	return nil
+}
+
+// 对比同一资料的两次处理结果（如不同 OCR 引擎，或重新处理前后）。
+// task_id 为空时：base 取资料当前的处理结果，target 取除 base 外最近一次完成的同类型结果（含对比处理）
+type CompareProcessingResultsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MaterialId    string                 `protobuf:"bytes,1,opt,name=material_id,json=materialId,proto3" json:"material_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Type          ProcessingType         `protobuf:"varint,3,opt,name=type,proto3,enum=material.ProcessingType" json:"type,omitempty"`
+	BaseTaskId    string                 `protobuf:"bytes,4,opt,name=base_task_id,json=baseTaskId,proto3" json:"base_task_id,omitempty"`
+	TargetTaskId  string                 `protobuf:"bytes,5,opt,name=target_task_id,json=targetTaskId,proto3" json:"target_task_id,omitempty"`
+	ContextLines  int32                  `protobuf:"varint,6,opt,name=context_lines,json=contextLines,proto3" json:"context_lines,omitempty"` // 差异块前后保留的未改动行数，默认 3
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CompareProcessingResultsRequest) Reset() {
+	*x = CompareProcessingResultsRequest{}
+	mi := &file_proto_material_material_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CompareProcessingResultsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CompareProcessingResultsRequest) ProtoMessage() {}
+
+func (x *CompareProcessingResultsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_material_material_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CompareProcessingResultsRequest.ProtoReflect.Descriptor instead.
+func (*CompareProcessingResultsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_material_material_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *CompareProcessingResultsRequest) GetMaterialId() string {
+	if x != nil {
+		return x.MaterialId
+	}
+	return ""
+}
+
+func (x *CompareProcessingResultsRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *CompareProcessingResultsRequest) GetType() ProcessingType {
+	if x != nil {
+		return x.Type
+	}
+	return ProcessingType_OCR
+}
+
+func (x *CompareProcessingResultsRequest) GetBaseTaskId() string {
+	if x != nil {
+		return x.BaseTaskId
+	}
+	return ""
+}
+
+func (x *CompareProcessingResultsRequest) GetTargetTaskId() string {
+	if x != nil {
+		return x.TargetTaskId
+	}
+	return ""
+}
+
+func (x *CompareProcessingResultsRequest) GetContextLines() int32 {
+	if x != nil {
+		return x.ContextLines
+	}
+	return 0
+}
+
+// 单次处理结果的质量指标
+type ResultQuality struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TaskId        string                 `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	Engine        string                 `protobuf:"bytes,2,opt,name=engine,proto3" json:"engine,omitempty"` // 识别引擎，未记录时为空
+	Shadow        bool                   `protobuf:"varint,3,opt,name=shadow,proto3" json:"shadow,omitempty"`
+	Chars         int32                  `protobuf:"varint,4,opt,name=chars,proto3" json:"chars,omitempty"`
+	Lines         int32                  `protobuf:"varint,5,opt,name=lines,proto3" json:"lines,omitempty"`
+	Confidence    float32                `protobuf:"fixed32,6,opt,name=confidence,proto3" json:"confidence,omitempty"`                  // 引擎报告的平均置信度，未记录时为 0
+	DurationMs    int64                  `protobuf:"varint,7,opt,name=duration_ms,json=durationMs,proto3" json:"duration_ms,omitempty"` // 识别耗时，未记录时为 0
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResultQuality) Reset() {
+	*x = ResultQuality{}
+	mi := &file_proto_material_material_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResultQuality) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResultQuality) ProtoMessage() {}
+
+func (x *ResultQuality) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_material_material_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResultQuality.ProtoReflect.Descriptor instead.
+func (*ResultQuality) Descriptor() ([]byte, []int) {
+	return file_proto_material_material_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *ResultQuality) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
+func (x *ResultQuality) GetEngine() string {
+	if x != nil {
+		return x.Engine
+	}
+	return ""
+}
+
+func (x *ResultQuality) GetShadow() bool {
+	if x != nil {
+		return x.Shadow
+	}
+	return false
+}
+
+func (x *ResultQuality) GetChars() int32 {
+	if x != nil {
+		return x.Chars
+	}
+	return 0
+}
+
+func (x *ResultQuality) GetLines() int32 {
+	if x != nil {
+		return x.Lines
+	}
+	return 0
+}
+
+func (x *ResultQuality) GetConfidence() float32 {
+	if x != nil {
+		return x.Confidence
+	}
+	return 0
+}
+
+func (x *ResultQuality) GetDurationMs() int64 {
+	if x != nil {
+		return x.DurationMs
+	}
+	return 0
+}
+
+func (x *ResultQuality) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type DiffLine struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Op            string                 `protobuf:"bytes,1,opt,name=op,proto3" json:"op,omitempty"` // equal / insert / delete
+	Text          string                 `protobuf:"bytes,2,opt,name=text,proto3" json:"text,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DiffLine) Reset() {
+	*x = DiffLine{}
+	mi := &file_proto_material_material_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DiffLine) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DiffLine) ProtoMessage() {}
+
+func (x *DiffLine) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_material_material_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DiffLine.ProtoReflect.Descriptor instead.
+func (*DiffLine) Descriptor() ([]byte, []int) {
+	return file_proto_material_material_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *DiffLine) GetOp() string {
+	if x != nil {
+		return x.Op
+	}
+	return ""
+}
+
+func (x *DiffLine) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+// 差异块，行号从 1 开始
+type DiffHunk struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BaseStart     int32                  `protobuf:"varint,1,opt,name=base_start,json=baseStart,proto3" json:"base_start,omitempty"`
+	BaseLines     int32                  `protobuf:"varint,2,opt,name=base_lines,json=baseLines,proto3" json:"base_lines,omitempty"`
+	TargetStart   int32                  `protobuf:"varint,3,opt,name=target_start,json=targetStart,proto3" json:"target_start,omitempty"`
+	TargetLines   int32                  `protobuf:"varint,4,opt,name=target_lines,json=targetLines,proto3" json:"target_lines,omitempty"`
+	Lines         []*DiffLine            `protobuf:"bytes,5,rep,name=lines,proto3" json:"lines,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DiffHunk) Reset() {
+	*x = DiffHunk{}
+	mi := &file_proto_material_material_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DiffHunk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DiffHunk) ProtoMessage() {}
+
+func (x *DiffHunk) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_material_material_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DiffHunk.ProtoReflect.Descriptor instead.
+func (*DiffHunk) Descriptor() ([]byte, []int) {
+	return file_proto_material_material_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *DiffHunk) GetBaseStart() int32 {
+	if x != nil {
+		return x.BaseStart
+	}
+	return 0
+}
+
+func (x *DiffHunk) GetBaseLines() int32 {
+	if x != nil {
+		return x.BaseLines
+	}
+	return 0
+}
+
+func (x *DiffHunk) GetTargetStart() int32 {
+	if x != nil {
+		return x.TargetStart
+	}
+	return 0
+}
+
+func (x *DiffHunk) GetTargetLines() int32 {
+	if x != nil {
+		return x.TargetLines
+	}
+	return 0
+}
+
+func (x *DiffHunk) GetLines() []*DiffLine {
+	if x != nil {
+		return x.Lines
+	}
+	return nil
+}
+
+type CompareProcessingResultsResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Success        bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message        string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Base           *ResultQuality         `protobuf:"bytes,3,opt,name=base,proto3" json:"base,omitempty"`
+	Target         *ResultQuality         `protobuf:"bytes,4,opt,name=target,proto3" json:"target,omitempty"`
+	Similarity     float64                `protobuf:"fixed64,5,opt,name=similarity,proto3" json:"similarity,omitempty"` // 按行计算的相似度 0~1
+	LinesAdded     int32                  `protobuf:"varint,6,opt,name=lines_added,json=linesAdded,proto3" json:"lines_added,omitempty"`
+	LinesRemoved   int32                  `protobuf:"varint,7,opt,name=lines_removed,json=linesRemoved,proto3" json:"lines_removed,omitempty"`
+	LinesUnchanged int32                  `protobuf:"varint,8,opt,name=lines_unchanged,json=linesUnchanged,proto3" json:"lines_unchanged,omitempty"`
+	Hunks          []*DiffHunk            `protobuf:"bytes,9,rep,name=hunks,proto3" json:"hunks,omitempty"`
+	Truncated      bool                   `protobuf:"varint,10,opt,name=truncated,proto3" json:"truncated,omitempty"` // 差异过大时只返回统计信息，或差异块数超过上限
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *CompareProcessingResultsResponse) Reset() {
+	*x = CompareProcessingResultsResponse{}
+	mi := &file_proto_material_material_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CompareProcessingResultsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CompareProcessingResultsResponse) ProtoMessage() {}
+
+func (x *CompareProcessingResultsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_material_material_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CompareProcessingResultsResponse.ProtoReflect.Descriptor instead.
+func (*CompareProcessingResultsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_material_material_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *CompareProcessingResultsResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *CompareProcessingResultsResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *CompareProcessingResultsResponse) GetBase() *ResultQuality {
+	if x != nil {
+		return x.Base
+	}
+	return nil
+}
+
+func (x *CompareProcessingResultsResponse) GetTarget() *ResultQuality {
+	if x != nil {
+		return x.Target
+	}
+	return nil
+}
+
+func (x *CompareProcessingResultsResponse) GetSimilarity() float64 {
+	if x != nil {
+		return x.Similarity
+	}
+	return 0
+}
+
+func (x *CompareProcessingResultsResponse) GetLinesAdded() int32 {
+	if x != nil {
+		return x.LinesAdded
+	}
+	return 0
+}
+
+func (x *CompareProcessingResultsResponse) GetLinesRemoved() int32 {
+	if x != nil {
+		return x.LinesRemoved
+	}
+	return 0
+}
+
+func (x *CompareProcessingResultsResponse) GetLinesUnchanged() int32 {
+	if x != nil {
+		return x.LinesUnchanged
+	}
+	return 0
+}
+
+func (x *CompareProcessingResultsResponse) GetHunks() []*DiffHunk {
+	if x != nil {
+		return x.Hunks
+	}
+	return nil
+}
+
+func (x *CompareProcessingResultsResponse) GetTruncated() bool {
+	if x != nil {
+		return x.Truncated
+	}
+	return false
+}
+
+// 处理前预估用量与费用，不创建处理记录
+type EstimateProcessingRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MaterialId    string                 `protobuf:"bytes,1,opt,name=material_id,json=materialId,proto3" json:"material_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EstimateProcessingRequest) Reset() {
+	*x = EstimateProcessingRequest{}
+	mi := &file_proto_material_material_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EstimateProcessingRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EstimateProcessingRequest) ProtoMessage() {}
+
+func (x *EstimateProcessingRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_material_material_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EstimateProcessingRequest.ProtoReflect.Descriptor instead.
+func (*EstimateProcessingRequest) Descriptor() ([]byte, []int) {
+	return file_proto_material_material_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *EstimateProcessingRequest) GetMaterialId() string {
+	if x != nil {
+		return x.MaterialId
+	}
+	return ""
+}
+
+func (x *EstimateProcessingRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+// 单项处理的预估，文本量按识别结果估算，token 包含分片向量化（OCR / ASR）或 LLM 输入（LLM_ANALYSIS）
+type ProcessingEstimate struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          ProcessingType         `protobuf:"varint,1,opt,name=type,proto3,enum=material.ProcessingType" json:"type,omitempty"`
+	Pages         int32                  `protobuf:"varint,2,opt,name=pages,proto3" json:"pages,omitempty"`      // OCR 页数
+	Minutes       float64                `protobuf:"fixed64,3,opt,name=minutes,proto3" json:"minutes,omitempty"` // ASR 转写分钟数
+	Chars         int64                  `protobuf:"varint,4,opt,name=chars,proto3" json:"chars,omitempty"`
+	Tokens        int64                  `protobuf:"varint,5,opt,name=tokens,proto3" json:"tokens,omitempty"`
+	Cost          float64                `protobuf:"fixed64,6,opt,name=cost,proto3" json:"cost,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProcessingEstimate) Reset() {
+	*x = ProcessingEstimate{}
+	mi := &file_proto_material_material_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProcessingEstimate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProcessingEstimate) ProtoMessage() {}
+
+func (x *ProcessingEstimate) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_material_material_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProcessingEstimate.ProtoReflect.Descriptor instead.
+func (*ProcessingEstimate) Descriptor() ([]byte, []int) {
+	return file_proto_material_material_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *ProcessingEstimate) GetType() ProcessingType {
+	if x != nil {
+		return x.Type
+	}
+	return ProcessingType_OCR
+}
+
+func (x *ProcessingEstimate) GetPages() int32 {
+	if x != nil {
+		return x.Pages
+	}
+	return 0
+}
+
+func (x *ProcessingEstimate) GetMinutes() float64 {
+	if x != nil {
+		return x.Minutes
+	}
+	return 0
+}
+
+func (x *ProcessingEstimate) GetChars() int64 {
+	if x != nil {
+		return x.Chars
+	}
+	return 0
+}
+
+func (x *ProcessingEstimate) GetTokens() int64 {
+	if x != nil {
+		return x.Tokens
+	}
+	return 0
+}
+
+func (x *ProcessingEstimate) GetCost() float64 {
+	if x != nil {
+		return x.Cost
+	}
+	return 0
+}
+
+type EstimateProcessingResponse struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Success         bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message         string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	FileType        string                 `protobuf:"bytes,3,opt,name=file_type,json=fileType,proto3" json:"file_type,omitempty"`
+	SizeBytes       int64                  `protobuf:"varint,4,opt,name=size_bytes,json=sizeBytes,proto3" json:"size_bytes,omitempty"`
+	PageCount       int32                  `protobuf:"varint,5,opt,name=page_count,json=pageCount,proto3" json:"page_count,omitempty"`                    // PDF 页数，图片为 1
+	DurationSeconds float64                `protobuf:"fixed64,6,opt,name=duration_seconds,json=durationSeconds,proto3" json:"duration_seconds,omitempty"` // 音视频时长
+	Probed          bool                   `protobuf:"varint,7,opt,name=probed,proto3" json:"probed,omitempty"`                                           // false 表示未能读取文件元数据，页数或时长按文件大小估算
+	ProbeError      string                 `protobuf:"bytes,8,opt,name=probe_error,json=probeError,proto3" json:"probe_error,omitempty"`
+	Estimates       []*ProcessingEstimate  `protobuf:"bytes,9,rep,name=estimates,proto3" json:"estimates,omitempty"` // 资料类型适用的处理项
+	TotalCost       float64                `protobuf:"fixed64,10,opt,name=total_cost,json=totalCost,proto3" json:"total_cost,omitempty"`
+	Currency        string                 `protobuf:"bytes,11,opt,name=currency,proto3" json:"currency,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *EstimateProcessingResponse) Reset() {
+	*x = EstimateProcessingResponse{}
+	mi := &file_proto_material_material_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EstimateProcessingResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EstimateProcessingResponse) ProtoMessage() {}
+
+func (x *EstimateProcessingResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_material_material_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EstimateProcessingResponse.ProtoReflect.Descriptor instead.
+func (*EstimateProcessingResponse) Descriptor() ([]byte, []int) {
+	return file_proto_material_material_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *EstimateProcessingResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *EstimateProcessingResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *EstimateProcessingResponse) GetFileType() string {
+	if x != nil {
+		return x.FileType
+	}
+	return ""
+}
+
+func (x *EstimateProcessingResponse) GetSizeBytes() int64 {
+	if x != nil {
+		return x.SizeBytes
+	}
+	return 0
+}
+
+func (x *EstimateProcessingResponse) GetPageCount() int32 {
+	if x != nil {
+		return x.PageCount
+	}
+	return 0
+}
+
+func (x *EstimateProcessingResponse) GetDurationSeconds() float64 {
+	if x != nil {
+		return x.DurationSeconds
+	}
+	return 0
+}
+
+func (x *EstimateProcessingResponse) GetProbed() bool {
+	if x != nil {
+		return x.Probed
+	}
+	return false
+}
+
+func (x *EstimateProcessingResponse) GetProbeError() string {
+	if x != nil {
+		return x.ProbeError
+	}
+	return ""
+}
+
+func (x *EstimateProcessingResponse) GetEstimates() []*ProcessingEstimate {
+	if x != nil {
+		return x.Estimates
+	}
+	return nil
+}
+
+func (x *EstimateProcessingResponse) GetTotalCost() float64 {
+	if x != nil {
+		return x.TotalCost
+	}
+	return 0
+}
+
+func (x *EstimateProcessingResponse) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+// 派生内容状态
+type DerivedArtifact struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MaterialId    string                 `protobuf:"bytes,1,opt,name=material_id,json=materialId,proto3" json:"material_id,omitempty"`
+	Kind          string                 `protobuf:"bytes,2,opt,name=kind,proto3" json:"kind,omitempty"`                                       // chunks / summary / quiz
+	Status        string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`                                   // fresh / stale / regenerating / failed
+	SourceTaskId  string                 `protobuf:"bytes,4,opt,name=source_task_id,json=sourceTaskId,proto3" json:"source_task_id,omitempty"` // 派生内容所依据的处理任务
+	StaleReason   string                 `protobuf:"bytes,5,opt,name=stale_reason,json=staleReason,proto3" json:"stale_reason,omitempty"`
+	StaleSince    *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=stale_since,json=staleSince,proto3" json:"stale_since,omitempty"`
+	RegeneratedAt *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=regenerated_at,json=regeneratedAt,proto3" json:"regenerated_at,omitempty"`
+	ErrorMessage  string                 `protobuf:"bytes,8,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DerivedArtifact) Reset() {
+	*x = DerivedArtifact{}
+	mi := &file_proto_material_material_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DerivedArtifact) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DerivedArtifact) ProtoMessage() {}
+
+func (x *DerivedArtifact) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_material_material_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DerivedArtifact.ProtoReflect.Descriptor instead.
+func (*DerivedArtifact) Descriptor() ([]byte, []int) {
+	return file_proto_material_material_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *DerivedArtifact) GetMaterialId() string {
+	if x != nil {
+		return x.MaterialId
+	}
+	return ""
+}
+
+func (x *DerivedArtifact) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *DerivedArtifact) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *DerivedArtifact) GetSourceTaskId() string {
+	if x != nil {
+		return x.SourceTaskId
+	}
+	return ""
+}
+
+func (x *DerivedArtifact) GetStaleReason() string {
+	if x != nil {
+		return x.StaleReason
+	}
+	return ""
+}
+
+func (x *DerivedArtifact) GetStaleSince() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StaleSince
+	}
+	return nil
+}
+
+func (x *DerivedArtifact) GetRegeneratedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.RegeneratedAt
+	}
+	return nil
+}
+
+func (x *DerivedArtifact) GetErrorMessage() string {
+	if x != nil {
+		return x.ErrorMessage
+	}
+	return ""
+}
+
+func (x *DerivedArtifact) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+type ListDerivedArtifactsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MaterialId    string                 `protobuf:"bytes,1,opt,name=material_id,json=materialId,proto3" json:"material_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDerivedArtifactsRequest) Reset() {
+	*x = ListDerivedArtifactsRequest{}
+	mi := &file_proto_material_material_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDerivedArtifactsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDerivedArtifactsRequest) ProtoMessage() {}
+
+func (x *ListDerivedArtifactsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_material_material_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDerivedArtifactsRequest.ProtoReflect.Descriptor instead.
+func (*ListDerivedArtifactsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_material_material_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *ListDerivedArtifactsRequest) GetMaterialId() string {
+	if x != nil {
+		return x.MaterialId
+	}
+	return ""
+}
+
+func (x *ListDerivedArtifactsRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type ListDerivedArtifactsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Artifacts     []*DerivedArtifact     `protobuf:"bytes,3,rep,name=artifacts,proto3" json:"artifacts,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDerivedArtifactsResponse) Reset() {
+	*x = ListDerivedArtifactsResponse{}
+	mi := &file_proto_material_material_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDerivedArtifactsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDerivedArtifactsResponse) ProtoMessage() {}
+
+func (x *ListDerivedArtifactsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_material_material_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDerivedArtifactsResponse.ProtoReflect.Descriptor instead.
+func (*ListDerivedArtifactsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_material_material_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *ListDerivedArtifactsResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *ListDerivedArtifactsResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *ListDerivedArtifactsResponse) GetArtifacts() []*DerivedArtifact {
+	if x != nil {
+		return x.Artifacts
+	}
+	return nil
+}
+
+// 重建派生内容请求，kinds 为空时重建所有 stale / failed 的派生内容
+type RegenerateDerivedRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MaterialId    string                 `protobuf:"bytes,1,opt,name=material_id,json=materialId,proto3" json:"material_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Kinds         []string               `protobuf:"bytes,3,rep,name=kinds,proto3" json:"kinds,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RegenerateDerivedRequest) Reset() {
+	*x = RegenerateDerivedRequest{}
+	mi := &file_proto_material_material_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegenerateDerivedRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegenerateDerivedRequest) ProtoMessage() {}
+
+func (x *RegenerateDerivedRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_material_material_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegenerateDerivedRequest.ProtoReflect.Descriptor instead.
+func (*RegenerateDerivedRequest) Descriptor() ([]byte, []int) {
+	return file_proto_material_material_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *RegenerateDerivedRequest) GetMaterialId() string {
+	if x != nil {
+		return x.MaterialId
+	}
+	return ""
+}
+
+func (x *RegenerateDerivedRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *RegenerateDerivedRequest) GetKinds() []string {
+	if x != nil {
+		return x.Kinds
+	}
+	return nil
+}
+
+type RegenerateDerivedResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Artifacts     []*DerivedArtifact     `protobuf:"bytes,3,rep,name=artifacts,proto3" json:"artifacts,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RegenerateDerivedResponse) Reset() {
+	*x = RegenerateDerivedResponse{}
+	mi := &file_proto_material_material_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegenerateDerivedResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegenerateDerivedResponse) ProtoMessage() {}
+
+func (x *RegenerateDerivedResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_material_material_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegenerateDerivedResponse.ProtoReflect.Descriptor instead.
+func (*RegenerateDerivedResponse) Descriptor() ([]byte, []int) {
+	return file_proto_material_material_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *RegenerateDerivedResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *RegenerateDerivedResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *RegenerateDerivedResponse) GetArtifacts() []*DerivedArtifact {
+	if x != nil {
+		return x.Artifacts
+	}
+	return nil
+}
+
+// 更新派生内容状态请求 (供负责重建的下游服务回调使用)
+type UpdateDerivedArtifactRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MaterialId    string                 `protobuf:"bytes,1,opt,name=material_id,json=materialId,proto3" json:"material_id,omitempty"`
+	Kind          string                 `protobuf:"bytes,2,opt,name=kind,proto3" json:"kind,omitempty"`
+	Status        string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`                                   // fresh / failed
+	SourceTaskId  string                 `protobuf:"bytes,4,opt,name=source_task_id,json=sourceTaskId,proto3" json:"source_task_id,omitempty"` // 重建所依据的处理任务，可选
+	ErrorMessage  string                 `protobuf:"bytes,5,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateDerivedArtifactRequest) Reset() {
+	*x = UpdateDerivedArtifactRequest{}
+	mi := &file_proto_material_material_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateDerivedArtifactRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateDerivedArtifactRequest) ProtoMessage() {}
+
+func (x *UpdateDerivedArtifactRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_material_material_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateDerivedArtifactRequest.ProtoReflect.Descriptor instead.
+func (*UpdateDerivedArtifactRequest) Descriptor() ([]byte, []int) {
+	return file_proto_material_material_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *UpdateDerivedArtifactRequest) GetMaterialId() string {
+	if x != nil {
+		return x.MaterialId
+	}
+	return ""
+}
+
+func (x *UpdateDerivedArtifactRequest) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *UpdateDerivedArtifactRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *UpdateDerivedArtifactRequest) GetSourceTaskId() string {
+	if x != nil {
+		return x.SourceTaskId
+	}
+	return ""
+}
+
+func (x *UpdateDerivedArtifactRequest) GetErrorMessage() string {
+	if x != nil {
+		return x.ErrorMessage
+	}
+	return ""
+}
+
+type UpdateDerivedArtifactResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateDerivedArtifactResponse) Reset() {
+	*x = UpdateDerivedArtifactResponse{}
+	mi := &file_proto_material_material_proto_msgTypes[49]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateDerivedArtifactResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateDerivedArtifactResponse) ProtoMessage() {}
+
+func (x *UpdateDerivedArtifactResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_material_material_proto_msgTypes[49]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateDerivedArtifactResponse.ProtoReflect.Descriptor instead.
+func (*UpdateDerivedArtifactResponse) Descriptor() ([]byte, []int) {
+	return file_proto_material_material_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *UpdateDerivedArtifactResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *UpdateDerivedArtifactResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+// 资料批注
+type Annotation struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	MaterialId    string                 `protobuf:"bytes,2,opt,name=material_id,json=materialId,proto3" json:"material_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Kind          string                 `protobuf:"bytes,4,opt,name=kind,proto3" json:"kind,omitempty"`                               // comment / highlight
+	AnchorType    string                 `protobuf:"bytes,5,opt,name=anchor_type,json=anchorType,proto3" json:"anchor_type,omitempty"` // page / time，为空表示针对整份资料
+	Page          int32                  `protobuf:"varint,6,opt,name=page,proto3" json:"page,omitempty"`                              // anchor_type 为 page 时的页码（从 1 开始）
+	StartTime     float64                `protobuf:"fixed64,7,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`  // anchor_type 为 time 时的起止时间（秒）
+	EndTime       float64                `protobuf:"fixed64,8,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`
+	Quote         string                 `protobuf:"bytes,9,opt,name=quote,proto3" json:"quote,omitempty"`      // 高亮或评论所引用的原文
+	Content       string                 `protobuf:"bytes,10,opt,name=content,proto3" json:"content,omitempty"` // 评论内容
+	Color         string                 `protobuf:"bytes,11,opt,name=color,proto3" json:"color,omitempty"`     // 高亮颜色
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,14,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,15,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Annotation) Reset() {
+	*x = Annotation{}
+	mi := &file_proto_material_material_proto_msgTypes[50]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Annotation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Annotation) ProtoMessage() {}
+
+func (x *Annotation) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_material_material_proto_msgTypes[50]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Annotation.ProtoReflect.Descriptor instead.
+func (*Annotation) Descriptor() ([]byte, []int) {
+	return file_proto_material_material_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *Annotation) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Annotation) GetMaterialId() string {
+	if x != nil {
+		return x.MaterialId
+	}
+	return ""
+}
+
+func (x *Annotation) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *Annotation) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *Annotation) GetAnchorType() string {
+	if x != nil {
+		return x.AnchorType
+	}
+	return ""
+}
+
+func (x *Annotation) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *Annotation) GetStartTime() float64 {
+	if x != nil {
+		return x.StartTime
+	}
+	return 0
+}
+
+func (x *Annotation) GetEndTime() float64 {
+	if x != nil {
+		return x.EndTime
+	}
+	return 0
+}
+
+func (x *Annotation) GetQuote() string {
+	if x != nil {
+		return x.Quote
+	}
+	return ""
+}
+
+func (x *Annotation) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+func (x *Annotation) GetColor() string {
+	if x != nil {
+		return x.Color
+	}
+	return ""
+}
+
+func (x *Annotation) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Annotation) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+type CreateAnnotationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	MaterialId    string                 `protobuf:"bytes,2,opt,name=material_id,json=materialId,proto3" json:"material_id,omitempty"`
+	Kind          string                 `protobuf:"bytes,3,opt,name=kind,proto3" json:"kind,omitempty"`
+	AnchorType    string                 `protobuf:"bytes,4,opt,name=anchor_type,json=anchorType,proto3" json:"anchor_type,omitempty"`
+	Page          int32                  `protobuf:"varint,5,opt,name=page,proto3" json:"page,omitempty"`
+	StartTime     float64                `protobuf:"fixed64,6,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	EndTime       float64                `protobuf:"fixed64,7,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`
+	Quote         string                 `protobuf:"bytes,8,opt,name=quote,proto3" json:"quote,omitempty"`
+	Content       string                 `protobuf:"bytes,9,opt,name=content,proto3" json:"content,omitempty"`
+	Color         string                 `protobuf:"bytes,10,opt,name=color,proto3" json:"color,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateAnnotationRequest) Reset() {
+	*x = CreateAnnotationRequest{}
+	mi := &file_proto_material_material_proto_msgTypes[51]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateAnnotationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateAnnotationRequest) ProtoMessage() {}
+
+func (x *CreateAnnotationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_material_material_proto_msgTypes[51]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateAnnotationRequest.ProtoReflect.Descriptor instead.
+func (*CreateAnnotationRequest) Descriptor() ([]byte, []int) {
+	return file_proto_material_material_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *CreateAnnotationRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *CreateAnnotationRequest) GetMaterialId() string {
+	if x != nil {
+		return x.MaterialId
+	}
+	return ""
+}
+
+func (x *CreateAnnotationRequest) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *CreateAnnotationRequest) GetAnchorType() string {
+	if x != nil {
+		return x.AnchorType
+	}
+	return ""
+}
+
+func (x *CreateAnnotationRequest) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *CreateAnnotationRequest) GetStartTime() float64 {
+	if x != nil {
+		return x.StartTime
+	}
+	return 0
+}
+
+func (x *CreateAnnotationRequest) GetEndTime() float64 {
+	if x != nil {
+		return x.EndTime
+	}
+	return 0
+}
+
+func (x *CreateAnnotationRequest) GetQuote() string {
+	if x != nil {
+		return x.Quote
+	}
+	return ""
+}
+
+func (x *CreateAnnotationRequest) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+func (x *CreateAnnotationRequest) GetColor() string {
+	if x != nil {
+		return x.Color
+	}
+	return ""
+}
+
+// 更新批注请求，锚点创建后不可修改；content / color 为空时保持不变
+type UpdateAnnotationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Content       string                 `protobuf:"bytes,3,opt,name=content,proto3" json:"content,omitempty"`
+	Color         string                 `protobuf:"bytes,4,opt,name=color,proto3" json:"color,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateAnnotationRequest) Reset() {
+	*x = UpdateAnnotationRequest{}
+	mi := &file_proto_material_material_proto_msgTypes[52]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateAnnotationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateAnnotationRequest) ProtoMessage() {}
+
+func (x *UpdateAnnotationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_material_material_proto_msgTypes[52]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateAnnotationRequest.ProtoReflect.Descriptor instead.
+func (*UpdateAnnotationRequest) Descriptor() ([]byte, []int) {
+	return file_proto_material_material_proto_rawDescGZIP(), []int{52}
+}
+
+func (x *UpdateAnnotationRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UpdateAnnotationRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *UpdateAnnotationRequest) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+func (x *UpdateAnnotationRequest) GetColor() string {
+	if x != nil {
+		return x.Color
+	}
+	return ""
+}
+
+type AnnotationResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Annotation    *Annotation            `protobuf:"bytes,3,opt,name=annotation,proto3" json:"annotation,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AnnotationResponse) Reset() {
+	*x = AnnotationResponse{}
+	mi := &file_proto_material_material_proto_msgTypes[53]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AnnotationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AnnotationResponse) ProtoMessage() {}
+
+func (x *AnnotationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_material_material_proto_msgTypes[53]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AnnotationResponse.ProtoReflect.Descriptor instead.
+func (*AnnotationResponse) Descriptor() ([]byte, []int) {
+	return file_proto_material_material_proto_rawDescGZIP(), []int{53}
+}
+
+func (x *AnnotationResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *AnnotationResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *AnnotationResponse) GetAnnotation() *Annotation {
+	if x != nil {
+		return x.Annotation
+	}
+	return nil
+}
+
+type DeleteAnnotationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteAnnotationRequest) Reset() {
+	*x = DeleteAnnotationRequest{}
+	mi := &file_proto_material_material_proto_msgTypes[54]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteAnnotationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteAnnotationRequest) ProtoMessage() {}
+
+func (x *DeleteAnnotationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_material_material_proto_msgTypes[54]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteAnnotationRequest.ProtoReflect.Descriptor instead.
+func (*DeleteAnnotationRequest) Descriptor() ([]byte, []int) {
+	return file_proto_material_material_proto_rawDescGZIP(), []int{54}
+}
+
+func (x *DeleteAnnotationRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *DeleteAnnotationRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type DeleteAnnotationResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteAnnotationResponse) Reset() {
+	*x = DeleteAnnotationResponse{}
+	mi := &file_proto_material_material_proto_msgTypes[55]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteAnnotationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteAnnotationResponse) ProtoMessage() {}
 
-func (x *DeleteMaterialResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_material_material_proto_msgTypes[4]
+func (x *DeleteAnnotationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_material_material_proto_msgTypes[55]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -451,49 +4181,53 @@ func (x *DeleteMaterialResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteMaterialResponse.ProtoReflect.Descriptor instead.
-func (*DeleteMaterialResponse) Descriptor() ([]byte, []int) {
-	return file_proto_material_material_proto_rawDescGZIP(), []int{4}
+// Deprecated: Use DeleteAnnotationResponse.ProtoReflect.Descriptor instead.
+func (*DeleteAnnotationResponse) Descriptor() ([]byte, []int) {
+	return file_proto_material_material_proto_rawDescGZIP(), []int{55}
 }
 
-func (x *DeleteMaterialResponse) GetSuccess() bool {
+func (x *DeleteAnnotationResponse) GetSuccess() bool {
 	if x != nil {
 		return x.Success
 	}
 	return false
 }
 
-func (x *DeleteMaterialResponse) GetMessage() string {
+func (x *DeleteAnnotationResponse) GetMessage() string {
 	if x != nil {
 		return x.Message
 	}
 	return ""
 }
 
-type ListMaterialsRequest struct {
+// 列出用户在若干资料上的批注，page / 时间范围为可选的锚点过滤条件
+type ListAnnotationsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	Page          int32                  `protobuf:"varint,2,opt,name=page,proto3" json:"page,omitempty"`
-	PageSize      int32                  `protobuf:"varint,3,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	MaterialIds   []string               `protobuf:"bytes,2,rep,name=material_ids,json=materialIds,proto3" json:"material_ids,omitempty"`
+	Kind          string                 `protobuf:"bytes,3,opt,name=kind,proto3" json:"kind,omitempty"`
+	Page          int32                  `protobuf:"varint,4,opt,name=page,proto3" json:"page,omitempty"`                             // 只返回该页的批注
+	StartTime     float64                `protobuf:"fixed64,5,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"` // 只返回与 [start_time, end_time] 重叠的时间批注，end_time 为 0 表示不限
+	EndTime       float64                `protobuf:"fixed64,6,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListMaterialsRequest) Reset() {
-	*x = ListMaterialsRequest{}
-	mi := &file_proto_material_material_proto_msgTypes[5]
+func (x *ListAnnotationsRequest) Reset() {
+	*x = ListAnnotationsRequest{}
+	mi := &file_proto_material_material_proto_msgTypes[56]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListMaterialsRequest) String() string {
+func (x *ListAnnotationsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListMaterialsRequest) ProtoMessage() {}
+func (*ListAnnotationsRequest) ProtoMessage() {}
 
-func (x *ListMaterialsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_material_material_proto_msgTypes[5]
+func (x *ListAnnotationsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_material_material_proto_msgTypes[56]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -504,55 +4238,77 @@ func (x *ListMaterialsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListMaterialsRequest.ProtoReflect.Descriptor instead.
-func (*ListMaterialsRequest) Descriptor() ([]byte, []int) {
-	return file_proto_material_material_proto_rawDescGZIP(), []int{5}
+// Deprecated: Use ListAnnotationsRequest.ProtoReflect.Descriptor instead.
+func (*ListAnnotationsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_material_material_proto_rawDescGZIP(), []int{56}
 }
 
-func (x *ListMaterialsRequest) GetUserId() string {
+func (x *ListAnnotationsRequest) GetUserId() string {
 	if x != nil {
 		return x.UserId
 	}
 	return ""
 }
 
-func (x *ListMaterialsRequest) GetPage() int32 {
+func (x *ListAnnotationsRequest) GetMaterialIds() []string {
+	if x != nil {
+		return x.MaterialIds
+	}
+	return nil
+}
+
+func (x *ListAnnotationsRequest) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *ListAnnotationsRequest) GetPage() int32 {
 	if x != nil {
 		return x.Page
 	}
 	return 0
 }
 
-func (x *ListMaterialsRequest) GetPageSize() int32 {
+func (x *ListAnnotationsRequest) GetStartTime() float64 {
 	if x != nil {
-		return x.PageSize
+		return x.StartTime
 	}
 	return 0
 }
 
-type ListMaterialsResponse struct {
+func (x *ListAnnotationsRequest) GetEndTime() float64 {
+	if x != nil {
+		return x.EndTime
+	}
+	return 0
+}
+
+type ListAnnotationsResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Materials     []*MaterialInfo        `protobuf:"bytes,1,rep,name=materials,proto3" json:"materials,omitempty"`
-	Total         int64                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Annotations   []*Annotation          `protobuf:"bytes,3,rep,name=annotations,proto3" json:"annotations,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListMaterialsResponse) Reset() {
-	*x = ListMaterialsResponse{}
-	mi := &file_proto_material_material_proto_msgTypes[6]
+func (x *ListAnnotationsResponse) Reset() {
+	*x = ListAnnotationsResponse{}
+	mi := &file_proto_material_material_proto_msgTypes[57]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListMaterialsResponse) String() string {
+func (x *ListAnnotationsResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListMaterialsResponse) ProtoMessage() {}
+func (*ListAnnotationsResponse) ProtoMessage() {}
 
-func (x *ListMaterialsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_material_material_proto_msgTypes[6]
+func (x *ListAnnotationsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_material_material_proto_msgTypes[57]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -563,57 +4319,64 @@ func (x *ListMaterialsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListMaterialsResponse.ProtoReflect.Descriptor instead.
-func (*ListMaterialsResponse) Descriptor() ([]byte, []int) {
-	return file_proto_material_material_proto_rawDescGZIP(), []int{6}
+// Deprecated: Use ListAnnotationsResponse.ProtoReflect.Descriptor instead.
+func (*ListAnnotationsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_material_material_proto_rawDescGZIP(), []int{57}
 }
 
-func (x *ListMaterialsResponse) GetMaterials() []*MaterialInfo {
+func (x *ListAnnotationsResponse) GetSuccess() bool {
 	if x != nil {
-		return x.Materials
+		return x.Success
 	}
-	return nil
+	return false
 }
 
-func (x *ListMaterialsResponse) GetTotal() int64 {
+func (x *ListAnnotationsResponse) GetMessage() string {
 	if x != nil {
-		return x.Total
+		return x.Message
 	}
-	return 0
+	return ""
 }
 
-// 处理结果信息
-type ProcessingResult struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	MaterialId    string                 `protobuf:"bytes,2,opt,name=material_id,json=materialId,proto3" json:"material_id,omitempty"`
-	TaskId        string                 `protobuf:"bytes,3,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
-	Type          ProcessingType         `protobuf:"varint,4,opt,name=type,proto3,enum=material.ProcessingType" json:"type,omitempty"`
-	Status        ProcessingStatus       `protobuf:"varint,5,opt,name=status,proto3,enum=material.ProcessingStatus" json:"status,omitempty"`
-	Content       string                 `protobuf:"bytes,6,opt,name=content,proto3" json:"content,omitempty"`
-	Metadata      map[string]string      `protobuf:"bytes,7,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
-	CreatedAt     string                 `protobuf:"bytes,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
-	UpdatedAt     string                 `protobuf:"bytes,9,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
-	ErrorMessage  string                 `protobuf:"bytes,10,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *ListAnnotationsResponse) GetAnnotations() []*Annotation {
+	if x != nil {
+		return x.Annotations
+	}
+	return nil
 }
 
-func (x *ProcessingResult) Reset() {
-	*x = ProcessingResult{}
-	mi := &file_proto_material_material_proto_msgTypes[7]
+// 可续传的上传会话
+type UploadSession struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Id               string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	UserId           string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Title            string                 `protobuf:"bytes,3,opt,name=title,proto3" json:"title,omitempty"`
+	OriginalFilename string                 `protobuf:"bytes,4,opt,name=original_filename,json=originalFilename,proto3" json:"original_filename,omitempty"`
+	Size             int64                  `protobuf:"varint,5,opt,name=size,proto3" json:"size,omitempty"`                              // 文件总字节数
+	Offset           int64                  `protobuf:"varint,6,opt,name=offset,proto3" json:"offset,omitempty"`                          // 已接收的字节数
+	Status           string                 `protobuf:"bytes,7,opt,name=status,proto3" json:"status,omitempty"`                           // active / completed
+	MaterialId       string                 `protobuf:"bytes,8,opt,name=material_id,json=materialId,proto3" json:"material_id,omitempty"` // completed 时为创建的资料 ID（与已有资料重复且 on_duplicate 为 link 时为已有资料）
+	ExpiresAt        *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`    // 每次追加后顺延，过期未完成的会话由 janitor 清理
+	CreatedAt        *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *UploadSession) Reset() {
+	*x = UploadSession{}
+	mi := &file_proto_material_material_proto_msgTypes[58]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ProcessingResult) String() string {
+func (x *UploadSession) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ProcessingResult) ProtoMessage() {}
+func (*UploadSession) ProtoMessage() {}
 
-func (x *ProcessingResult) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_material_material_proto_msgTypes[7]
+func (x *UploadSession) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_material_material_proto_msgTypes[58]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -624,107 +4387,107 @@ func (x *ProcessingResult) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ProcessingResult.ProtoReflect.Descriptor instead.
-func (*ProcessingResult) Descriptor() ([]byte, []int) {
-	return file_proto_material_material_proto_rawDescGZIP(), []int{7}
+// Deprecated: Use UploadSession.ProtoReflect.Descriptor instead.
+func (*UploadSession) Descriptor() ([]byte, []int) {
+	return file_proto_material_material_proto_rawDescGZIP(), []int{58}
 }
 
-func (x *ProcessingResult) GetId() string {
+func (x *UploadSession) GetId() string {
 	if x != nil {
 		return x.Id
 	}
 	return ""
 }
 
-func (x *ProcessingResult) GetMaterialId() string {
+func (x *UploadSession) GetUserId() string {
 	if x != nil {
-		return x.MaterialId
+		return x.UserId
 	}
 	return ""
 }
 
-func (x *ProcessingResult) GetTaskId() string {
+func (x *UploadSession) GetTitle() string {
 	if x != nil {
-		return x.TaskId
+		return x.Title
 	}
 	return ""
 }
 
-func (x *ProcessingResult) GetType() ProcessingType {
+func (x *UploadSession) GetOriginalFilename() string {
 	if x != nil {
-		return x.Type
+		return x.OriginalFilename
 	}
-	return ProcessingType_OCR
+	return ""
 }
 
-func (x *ProcessingResult) GetStatus() ProcessingStatus {
+func (x *UploadSession) GetSize() int64 {
 	if x != nil {
-		return x.Status
+		return x.Size
 	}
-	return ProcessingStatus_PENDING
+	return 0
 }
 
-func (x *ProcessingResult) GetContent() string {
+func (x *UploadSession) GetOffset() int64 {
 	if x != nil {
-		return x.Content
+		return x.Offset
 	}
-	return ""
+	return 0
 }
 
-func (x *ProcessingResult) GetMetadata() map[string]string {
+func (x *UploadSession) GetStatus() string {
 	if x != nil {
-		return x.Metadata
+		return x.Status
 	}
-	return nil
+	return ""
 }
 
-func (x *ProcessingResult) GetCreatedAt() string {
+func (x *UploadSession) GetMaterialId() string {
 	if x != nil {
-		return x.CreatedAt
+		return x.MaterialId
 	}
 	return ""
 }
 
-func (x *ProcessingResult) GetUpdatedAt() string {
+func (x *UploadSession) GetExpiresAt() *timestamppb.Timestamp {
 	if x != nil {
-		return x.UpdatedAt
+		return x.ExpiresAt
 	}
-	return ""
+	return nil
 }
 
-func (x *ProcessingResult) GetErrorMessage() string {
+func (x *UploadSession) GetCreatedAt() *timestamppb.Timestamp {
 	if x != nil {
-		return x.ErrorMessage
+		return x.CreatedAt
 	}
-	return ""
+	return nil
 }
 
-// 开始处理材料请求
-type ProcessMaterialRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	MaterialId    string                 `protobuf:"bytes,1,opt,name=material_id,json=materialId,proto3" json:"material_id,omitempty"`
-	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	Type          ProcessingType         `protobuf:"varint,3,opt,name=type,proto3,enum=material.ProcessingType" json:"type,omitempty"`
-	Options       map[string]string      `protobuf:"bytes,4,rep,name=options,proto3" json:"options,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // 处理选项，如语言、模型等
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+type CreateUploadSessionRequest struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	UserId           string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Title            string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	OriginalFilename string                 `protobuf:"bytes,3,opt,name=original_filename,json=originalFilename,proto3" json:"original_filename,omitempty"`
+	Size             int64                  `protobuf:"varint,4,opt,name=size,proto3" json:"size,omitempty"`
+	OnDuplicate      string                 `protobuf:"bytes,5,opt,name=on_duplicate,json=onDuplicate,proto3" json:"on_duplicate,omitempty"` // 同 UploadMaterialRequest.on_duplicate，在会话完成创建资料时使用
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
 }
 
-func (x *ProcessMaterialRequest) Reset() {
-	*x = ProcessMaterialRequest{}
-	mi := &file_proto_material_material_proto_msgTypes[8]
+func (x *CreateUploadSessionRequest) Reset() {
+	*x = CreateUploadSessionRequest{}
+	mi := &file_proto_material_material_proto_msgTypes[59]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ProcessMaterialRequest) String() string {
+func (x *CreateUploadSessionRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ProcessMaterialRequest) ProtoMessage() {}
+func (*CreateUploadSessionRequest) ProtoMessage() {}
 
-func (x *ProcessMaterialRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_material_material_proto_msgTypes[8]
+func (x *CreateUploadSessionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_material_material_proto_msgTypes[59]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -735,133 +4498,69 @@ func (x *ProcessMaterialRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ProcessMaterialRequest.ProtoReflect.Descriptor instead.
-func (*ProcessMaterialRequest) Descriptor() ([]byte, []int) {
-	return file_proto_material_material_proto_rawDescGZIP(), []int{8}
-}
-
-func (x *ProcessMaterialRequest) GetMaterialId() string {
-	if x != nil {
-		return x.MaterialId
-	}
-	return ""
+// Deprecated: Use CreateUploadSessionRequest.ProtoReflect.Descriptor instead.
+func (*CreateUploadSessionRequest) Descriptor() ([]byte, []int) {
+	return file_proto_material_material_proto_rawDescGZIP(), []int{59}
 }
 
-func (x *ProcessMaterialRequest) GetUserId() string {
+func (x *CreateUploadSessionRequest) GetUserId() string {
 	if x != nil {
 		return x.UserId
 	}
 	return ""
 }
 
-func (x *ProcessMaterialRequest) GetType() ProcessingType {
-	if x != nil {
-		return x.Type
-	}
-	return ProcessingType_OCR
-}
-
-func (x *ProcessMaterialRequest) GetOptions() map[string]string {
-	if x != nil {
-		return x.Options
-	}
-	return nil
-}
-
-// 开始处理材料响应
-type ProcessMaterialResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
-	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
-	TaskId        string                 `protobuf:"bytes,3,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
-	Result        *ProcessingResult      `protobuf:"bytes,4,opt,name=result,proto3" json:"result,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
-}
-
-func (x *ProcessMaterialResponse) Reset() {
-	*x = ProcessMaterialResponse{}
-	mi := &file_proto_material_material_proto_msgTypes[9]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
-}
-
-func (x *ProcessMaterialResponse) String() string {
-	return protoimpl.X.MessageStringOf(x)
-}
-
-func (*ProcessMaterialResponse) ProtoMessage() {}
-
-func (x *ProcessMaterialResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_material_material_proto_msgTypes[9]
-	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
-	}
-	return mi.MessageOf(x)
-}
-
-// Deprecated: Use ProcessMaterialResponse.ProtoReflect.Descriptor instead.
-func (*ProcessMaterialResponse) Descriptor() ([]byte, []int) {
-	return file_proto_material_material_proto_rawDescGZIP(), []int{9}
-}
-
-func (x *ProcessMaterialResponse) GetSuccess() bool {
+func (x *CreateUploadSessionRequest) GetTitle() string {
 	if x != nil {
-		return x.Success
+		return x.Title
 	}
-	return false
+	return ""
 }
 
-func (x *ProcessMaterialResponse) GetMessage() string {
+func (x *CreateUploadSessionRequest) GetOriginalFilename() string {
 	if x != nil {
-		return x.Message
+		return x.OriginalFilename
 	}
 	return ""
 }
 
-func (x *ProcessMaterialResponse) GetTaskId() string {
+func (x *CreateUploadSessionRequest) GetSize() int64 {
 	if x != nil {
-		return x.TaskId
+		return x.Size
 	}
-	return ""
+	return 0
 }
 
-func (x *ProcessMaterialResponse) GetResult() *ProcessingResult {
+func (x *CreateUploadSessionRequest) GetOnDuplicate() string {
 	if x != nil {
-		return x.Result
+		return x.OnDuplicate
 	}
-	return nil
+	return ""
 }
 
-// 获取处理结果请求
-type GetProcessingResultRequest struct {
+type GetUploadSessionRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	MaterialId    string                 `protobuf:"bytes,1,opt,name=material_id,json=materialId,proto3" json:"material_id,omitempty"`
+	SessionId     string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
 	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	Type          ProcessingType         `protobuf:"varint,3,opt,name=type,proto3,enum=material.ProcessingType" json:"type,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetProcessingResultRequest) Reset() {
-	*x = GetProcessingResultRequest{}
-	mi := &file_proto_material_material_proto_msgTypes[10]
+func (x *GetUploadSessionRequest) Reset() {
+	*x = GetUploadSessionRequest{}
+	mi := &file_proto_material_material_proto_msgTypes[60]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetProcessingResultRequest) String() string {
+func (x *GetUploadSessionRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetProcessingResultRequest) ProtoMessage() {}
+func (*GetUploadSessionRequest) ProtoMessage() {}
 
-func (x *GetProcessingResultRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_material_material_proto_msgTypes[10]
+func (x *GetUploadSessionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_material_material_proto_msgTypes[60]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -872,57 +4571,51 @@ func (x *GetProcessingResultRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetProcessingResultRequest.ProtoReflect.Descriptor instead.
-func (*GetProcessingResultRequest) Descriptor() ([]byte, []int) {
-	return file_proto_material_material_proto_rawDescGZIP(), []int{10}
-}
-
-func (x *GetProcessingResultRequest) GetMaterialId() string {
-	if x != nil {
-		return x.MaterialId
-	}
-	return ""
+// Deprecated: Use GetUploadSessionRequest.ProtoReflect.Descriptor instead.
+func (*GetUploadSessionRequest) Descriptor() ([]byte, []int) {
+	return file_proto_material_material_proto_rawDescGZIP(), []int{60}
 }
 
-func (x *GetProcessingResultRequest) GetUserId() string {
+func (x *GetUploadSessionRequest) GetSessionId() string {
 	if x != nil {
-		return x.UserId
+		return x.SessionId
 	}
 	return ""
 }
 
-func (x *GetProcessingResultRequest) GetType() ProcessingType {
+func (x *GetUploadSessionRequest) GetUserId() string {
 	if x != nil {
-		return x.Type
+		return x.UserId
 	}
-	return ProcessingType_OCR
+	return ""
 }
 
-// 获取处理结果响应
-type GetProcessingResultResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Found         bool                   `protobuf:"varint,1,opt,name=found,proto3" json:"found,omitempty"`
-	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
-	Result        *ProcessingResult      `protobuf:"bytes,3,opt,name=result,proto3" json:"result,omitempty"`
+type AppendUploadSessionRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Data:
+	//
+	//	*AppendUploadSessionRequest_Target
+	//	*AppendUploadSessionRequest_ChunkData
+	Data          isAppendUploadSessionRequest_Data `protobuf_oneof:"data"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetProcessingResultResponse) Reset() {
-	*x = GetProcessingResultResponse{}
-	mi := &file_proto_material_material_proto_msgTypes[11]
+func (x *AppendUploadSessionRequest) Reset() {
+	*x = AppendUploadSessionRequest{}
+	mi := &file_proto_material_material_proto_msgTypes[61]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetProcessingResultResponse) String() string {
+func (x *AppendUploadSessionRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetProcessingResultResponse) ProtoMessage() {}
+func (*AppendUploadSessionRequest) ProtoMessage() {}
 
-func (x *GetProcessingResultResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_material_material_proto_msgTypes[11]
+func (x *AppendUploadSessionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_material_material_proto_msgTypes[61]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -933,59 +4626,77 @@ func (x *GetProcessingResultResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetProcessingResultResponse.ProtoReflect.Descriptor instead.
-func (*GetProcessingResultResponse) Descriptor() ([]byte, []int) {
-	return file_proto_material_material_proto_rawDescGZIP(), []int{11}
+// Deprecated: Use AppendUploadSessionRequest.ProtoReflect.Descriptor instead.
+func (*AppendUploadSessionRequest) Descriptor() ([]byte, []int) {
+	return file_proto_material_material_proto_rawDescGZIP(), []int{61}
 }
 
-func (x *GetProcessingResultResponse) GetFound() bool {
+func (x *AppendUploadSessionRequest) GetData() isAppendUploadSessionRequest_Data {
 	if x != nil {
-		return x.Found
+		return x.Data
 	}
-	return false
+	return nil
 }
 
-func (x *GetProcessingResultResponse) GetMessage() string {
+func (x *AppendUploadSessionRequest) GetTarget() *UploadSessionTarget {
 	if x != nil {
-		return x.Message
+		if x, ok := x.Data.(*AppendUploadSessionRequest_Target); ok {
+			return x.Target
+		}
 	}
-	return ""
+	return nil
 }
 
-func (x *GetProcessingResultResponse) GetResult() *ProcessingResult {
+func (x *AppendUploadSessionRequest) GetChunkData() []byte {
 	if x != nil {
-		return x.Result
+		if x, ok := x.Data.(*AppendUploadSessionRequest_ChunkData); ok {
+			return x.ChunkData
+		}
 	}
 	return nil
 }
 
-// 列出处理结果请求
-type ListProcessingResultsRequest struct {
+type isAppendUploadSessionRequest_Data interface {
+	isAppendUploadSessionRequest_Data()
+}
+
+type AppendUploadSessionRequest_Target struct {
+	Target *UploadSessionTarget `protobuf:"bytes,1,opt,name=target,proto3,oneof"`
+}
+
+type AppendUploadSessionRequest_ChunkData struct {
+	ChunkData []byte `protobuf:"bytes,2,opt,name=chunk_data,json=chunkData,proto3,oneof"`
+}
+
+func (*AppendUploadSessionRequest_Target) isAppendUploadSessionRequest_Data() {}
+
+func (*AppendUploadSessionRequest_ChunkData) isAppendUploadSessionRequest_Data() {}
+
+// 追加的目标会话，offset 必须等于会话已接收的字节数
+type UploadSessionTarget struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	MaterialId    string                 `protobuf:"bytes,1,opt,name=material_id,json=materialId,proto3" json:"material_id,omitempty"`
+	SessionId     string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
 	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	Type          ProcessingType         `protobuf:"varint,3,opt,name=type,proto3,enum=material.ProcessingType" json:"type,omitempty"` // 可选，筛选特定类型
-	Page          int32                  `protobuf:"varint,4,opt,name=page,proto3" json:"page,omitempty"`
-	PageSize      int32                  `protobuf:"varint,5,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	Offset        int64                  `protobuf:"varint,3,opt,name=offset,proto3" json:"offset,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListProcessingResultsRequest) Reset() {
-	*x = ListProcessingResultsRequest{}
-	mi := &file_proto_material_material_proto_msgTypes[12]
+func (x *UploadSessionTarget) Reset() {
+	*x = UploadSessionTarget{}
+	mi := &file_proto_material_material_proto_msgTypes[62]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListProcessingResultsRequest) String() string {
+func (x *UploadSessionTarget) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListProcessingResultsRequest) ProtoMessage() {}
+func (*UploadSessionTarget) ProtoMessage() {}
 
-func (x *ListProcessingResultsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_material_material_proto_msgTypes[12]
+func (x *UploadSessionTarget) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_material_material_proto_msgTypes[62]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -996,70 +4707,58 @@ func (x *ListProcessingResultsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListProcessingResultsRequest.ProtoReflect.Descriptor instead.
-func (*ListProcessingResultsRequest) Descriptor() ([]byte, []int) {
-	return file_proto_material_material_proto_rawDescGZIP(), []int{12}
+// Deprecated: Use UploadSessionTarget.ProtoReflect.Descriptor instead.
+func (*UploadSessionTarget) Descriptor() ([]byte, []int) {
+	return file_proto_material_material_proto_rawDescGZIP(), []int{62}
 }
 
-func (x *ListProcessingResultsRequest) GetMaterialId() string {
+func (x *UploadSessionTarget) GetSessionId() string {
 	if x != nil {
-		return x.MaterialId
+		return x.SessionId
 	}
 	return ""
 }
 
-func (x *ListProcessingResultsRequest) GetUserId() string {
+func (x *UploadSessionTarget) GetUserId() string {
 	if x != nil {
 		return x.UserId
 	}
 	return ""
 }
 
-func (x *ListProcessingResultsRequest) GetType() ProcessingType {
-	if x != nil {
-		return x.Type
-	}
-	return ProcessingType_OCR
-}
-
-func (x *ListProcessingResultsRequest) GetPage() int32 {
-	if x != nil {
-		return x.Page
-	}
-	return 0
-}
-
-func (x *ListProcessingResultsRequest) GetPageSize() int32 {
+func (x *UploadSessionTarget) GetOffset() int64 {
 	if x != nil {
-		return x.PageSize
+		return x.Offset
 	}
 	return 0
 }
 
-// 列出处理结果响应
-type ListProcessingResultsResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Results       []*ProcessingResult    `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
-	Total         int64                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+type UploadSessionResponse struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Session   *UploadSession         `protobuf:"bytes,1,opt,name=session,proto3" json:"session,omitempty"`
+	Completed bool                   `protobuf:"varint,4,opt,name=completed,proto3" json:"completed,omitempty"` // 本次追加使会话完成并创建了资料
+	// completed 时与 UploadMaterialResponse 中的含义相同
+	Duplicates    []*DuplicateMaterial `protobuf:"bytes,2,rep,name=duplicates,proto3" json:"duplicates,omitempty"`
+	Linked        bool                 `protobuf:"varint,3,opt,name=linked,proto3" json:"linked,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListProcessingResultsResponse) Reset() {
-	*x = ListProcessingResultsResponse{}
-	mi := &file_proto_material_material_proto_msgTypes[13]
+func (x *UploadSessionResponse) Reset() {
+	*x = UploadSessionResponse{}
+	mi := &file_proto_material_material_proto_msgTypes[63]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListProcessingResultsResponse) String() string {
+func (x *UploadSessionResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListProcessingResultsResponse) ProtoMessage() {}
+func (*UploadSessionResponse) ProtoMessage() {}
 
-func (x *ListProcessingResultsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_material_material_proto_msgTypes[13]
+func (x *UploadSessionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_material_material_proto_msgTypes[63]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1070,52 +4769,62 @@ func (x *ListProcessingResultsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListProcessingResultsResponse.ProtoReflect.Descriptor instead.
-func (*ListProcessingResultsResponse) Descriptor() ([]byte, []int) {
-	return file_proto_material_material_proto_rawDescGZIP(), []int{13}
+// Deprecated: Use UploadSessionResponse.ProtoReflect.Descriptor instead.
+func (*UploadSessionResponse) Descriptor() ([]byte, []int) {
+	return file_proto_material_material_proto_rawDescGZIP(), []int{63}
 }
 
-func (x *ListProcessingResultsResponse) GetResults() []*ProcessingResult {
+func (x *UploadSessionResponse) GetSession() *UploadSession {
 	if x != nil {
-		return x.Results
+		return x.Session
 	}
 	return nil
 }
 
-func (x *ListProcessingResultsResponse) GetTotal() int64 {
+func (x *UploadSessionResponse) GetCompleted() bool {
 	if x != nil {
-		return x.Total
+		return x.Completed
 	}
-	return 0
+	return false
 }
 
-// 更新处理结果请求 (供AI服务回调使用)
-type UpdateProcessingResultRequest struct {
+func (x *UploadSessionResponse) GetDuplicates() []*DuplicateMaterial {
+	if x != nil {
+		return x.Duplicates
+	}
+	return nil
+}
+
+func (x *UploadSessionResponse) GetLinked() bool {
+	if x != nil {
+		return x.Linked
+	}
+	return false
+}
+
+type DeleteUploadSessionRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	TaskId        string                 `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
-	Status        ProcessingStatus       `protobuf:"varint,2,opt,name=status,proto3,enum=material.ProcessingStatus" json:"status,omitempty"`
-	Content       string                 `protobuf:"bytes,3,opt,name=content,proto3" json:"content,omitempty"`
-	Metadata      map[string]string      `protobuf:"bytes,4,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
-	ErrorMessage  string                 `protobuf:"bytes,5,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
+	SessionId     string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UpdateProcessingResultRequest) Reset() {
-	*x = UpdateProcessingResultRequest{}
-	mi := &file_proto_material_material_proto_msgTypes[14]
+func (x *DeleteUploadSessionRequest) Reset() {
+	*x = DeleteUploadSessionRequest{}
+	mi := &file_proto_material_material_proto_msgTypes[64]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UpdateProcessingResultRequest) String() string {
+func (x *DeleteUploadSessionRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpdateProcessingResultRequest) ProtoMessage() {}
+func (*DeleteUploadSessionRequest) ProtoMessage() {}
 
-func (x *UpdateProcessingResultRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_material_material_proto_msgTypes[14]
+func (x *DeleteUploadSessionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_material_material_proto_msgTypes[64]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1126,48 +4835,26 @@ func (x *UpdateProcessingResultRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpdateProcessingResultRequest.ProtoReflect.Descriptor instead.
-func (*UpdateProcessingResultRequest) Descriptor() ([]byte, []int) {
-	return file_proto_material_material_proto_rawDescGZIP(), []int{14}
-}
-
-func (x *UpdateProcessingResultRequest) GetTaskId() string {
-	if x != nil {
-		return x.TaskId
-	}
-	return ""
-}
-
-func (x *UpdateProcessingResultRequest) GetStatus() ProcessingStatus {
-	if x != nil {
-		return x.Status
-	}
-	return ProcessingStatus_PENDING
+// Deprecated: Use DeleteUploadSessionRequest.ProtoReflect.Descriptor instead.
+func (*DeleteUploadSessionRequest) Descriptor() ([]byte, []int) {
+	return file_proto_material_material_proto_rawDescGZIP(), []int{64}
 }
 
-func (x *UpdateProcessingResultRequest) GetContent() string {
+func (x *DeleteUploadSessionRequest) GetSessionId() string {
 	if x != nil {
-		return x.Content
+		return x.SessionId
 	}
 	return ""
 }
 
-func (x *UpdateProcessingResultRequest) GetMetadata() map[string]string {
-	if x != nil {
-		return x.Metadata
-	}
-	return nil
-}
-
-func (x *UpdateProcessingResultRequest) GetErrorMessage() string {
+func (x *DeleteUploadSessionRequest) GetUserId() string {
 	if x != nil {
-		return x.ErrorMessage
+		return x.UserId
 	}
 	return ""
 }
 
-// 更新处理结果响应
-type UpdateProcessingResultResponse struct {
+type DeleteUploadSessionResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
 	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
@@ -1175,21 +4862,21 @@ type UpdateProcessingResultResponse struct {
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UpdateProcessingResultResponse) Reset() {
-	*x = UpdateProcessingResultResponse{}
-	mi := &file_proto_material_material_proto_msgTypes[15]
+func (x *DeleteUploadSessionResponse) Reset() {
+	*x = DeleteUploadSessionResponse{}
+	mi := &file_proto_material_material_proto_msgTypes[65]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UpdateProcessingResultResponse) String() string {
+func (x *DeleteUploadSessionResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpdateProcessingResultResponse) ProtoMessage() {}
+func (*DeleteUploadSessionResponse) ProtoMessage() {}
 
-func (x *UpdateProcessingResultResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_material_material_proto_msgTypes[15]
+func (x *DeleteUploadSessionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_material_material_proto_msgTypes[65]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1200,19 +4887,19 @@ func (x *UpdateProcessingResultResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpdateProcessingResultResponse.ProtoReflect.Descriptor instead.
-func (*UpdateProcessingResultResponse) Descriptor() ([]byte, []int) {
-	return file_proto_material_material_proto_rawDescGZIP(), []int{15}
+// Deprecated: Use DeleteUploadSessionResponse.ProtoReflect.Descriptor instead.
+func (*DeleteUploadSessionResponse) Descriptor() ([]byte, []int) {
+	return file_proto_material_material_proto_rawDescGZIP(), []int{65}
 }
 
-func (x *UpdateProcessingResultResponse) GetSuccess() bool {
+func (x *DeleteUploadSessionResponse) GetSuccess() bool {
 	if x != nil {
 		return x.Success
 	}
 	return false
 }
 
-func (x *UpdateProcessingResultResponse) GetMessage() string {
+func (x *DeleteUploadSessionResponse) GetMessage() string {
 	if x != nil {
 		return x.Message
 	}
@@ -1223,7 +4910,7 @@ var File_proto_material_material_proto protoreflect.FileDescriptor
 
 const file_proto_material_material_proto_rawDesc = "" +
 	"\n" +
-	"\x1dproto/material/material.proto\x12\bmaterial\"\xed\x01\n" +
+	"\x1dproto/material/material.proto\x12\bmaterial\x1a\x1fgoogle/protobuf/timestamp.proto\"\xca\x03\n" +
 	"\fMaterialInfo\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
 	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x14\n" +
@@ -1232,19 +4919,79 @@ const file_proto_material_material_proto_rawDesc = "" +
 	"\tfile_type\x18\x05 \x01(\tR\bfileType\x12\x1d\n" +
 	"\n" +
 	"size_bytes\x18\x06 \x01(\x03R\tsizeBytes\x12\x16\n" +
-	"\x06status\x18\a \x01(\tR\x06status\x12\x1d\n" +
+	"\x06status\x18\a \x01(\tR\x06status\x129\n" +
 	"\n" +
-	"created_at\x18\b \x01(\tR\tcreatedAt\"v\n" +
+	"created_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12\x1b\n" +
+	"\tparent_id\x18\t \x01(\tR\bparentId\x12)\n" +
+	"\x05media\x18\v \x01(\v2\x13.material.MediaInfoR\x05media\x122\n" +
+	"\bdocument\x18\f \x01(\v2\x16.material.DocumentInfoR\bdocument\x12\x1a\n" +
+	"\blanguage\x18\r \x01(\tR\blanguage\x12!\n" +
+	"\fcontent_hash\x18\x0e \x01(\tR\vcontentHashJ\x04\b\b\x10\t\"\x80\x02\n" +
+	"\tMediaInfo\x12)\n" +
+	"\x10duration_seconds\x18\x01 \x01(\x01R\x0fdurationSeconds\x12\x14\n" +
+	"\x05width\x18\x02 \x01(\x05R\x05width\x12\x16\n" +
+	"\x06height\x18\x03 \x01(\x05R\x06height\x12\x1f\n" +
+	"\vvideo_codec\x18\x04 \x01(\tR\n" +
+	"videoCodec\x12\x1f\n" +
+	"\vaudio_codec\x18\x05 \x01(\tR\n" +
+	"audioCodec\x12\x1f\n" +
+	"\vformat_name\x18\x06 \x01(\tR\n" +
+	"formatName\x127\n" +
+	"\tprobed_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\bprobedAt\"\xba\x01\n" +
+	"\fDocumentInfo\x12\x1d\n" +
+	"\n" +
+	"page_count\x18\x01 \x01(\x05R\tpageCount\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12\x16\n" +
+	"\x06author\x18\x03 \x01(\tR\x06author\x12$\n" +
+	"\x03toc\x18\x04 \x03(\v2\x12.material.TocEntryR\x03toc\x127\n" +
+	"\tprobed_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\bprobedAt\"J\n" +
+	"\bTocEntry\x12\x14\n" +
+	"\x05title\x18\x01 \x01(\tR\x05title\x12\x12\n" +
+	"\x04page\x18\x02 \x01(\x05R\x04page\x12\x14\n" +
+	"\x05level\x18\x03 \x01(\x05R\x05level\"\x99\x01\n" +
 	"\x15UploadMaterialRequest\x124\n" +
 	"\bmetadata\x18\x01 \x01(\v2\x16.material.MaterialInfoH\x00R\bmetadata\x12\x1f\n" +
 	"\n" +
-	"chunk_data\x18\x02 \x01(\fH\x00R\tchunkDataB\x06\n" +
-	"\x04data\"m\n" +
+	"chunk_data\x18\x02 \x01(\fH\x00R\tchunkData\x12!\n" +
+	"\fon_duplicate\x18\x03 \x01(\tR\vonDuplicateB\x06\n" +
+	"\x04data\"\xc2\x01\n" +
 	"\x16UploadMaterialResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
 	"\amessage\x18\x02 \x01(\tR\amessage\x12\x1f\n" +
 	"\vmaterial_id\x18\x03 \x01(\tR\n" +
-	"materialId\"Q\n" +
+	"materialId\x12;\n" +
+	"\n" +
+	"duplicates\x18\x04 \x03(\v2\x1b.material.DuplicateMaterialR\n" +
+	"duplicates\x12\x16\n" +
+	"\x06linked\x18\x05 \x01(\bR\x06linked\"y\n" +
+	"\x11DuplicateMaterial\x122\n" +
+	"\bmaterial\x18\x01 \x01(\v2\x16.material.MaterialInfoR\bmaterial\x12\x14\n" +
+	"\x05match\x18\x02 \x01(\tR\x05match\x12\x1a\n" +
+	"\bdistance\x18\x03 \x01(\x05R\bdistance\"Y\n" +
+	"\x1dListDuplicateMaterialsRequest\x12\x1f\n" +
+	"\vmaterial_id\x18\x01 \x01(\tR\n" +
+	"materialId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\"\x91\x01\n" +
+	"\x1eListDuplicateMaterialsResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12;\n" +
+	"\n" +
+	"duplicates\x18\x03 \x03(\v2\x1b.material.DuplicateMaterialR\n" +
+	"duplicates\"\xbd\x01\n" +
+	"\x11CreateClipRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12\x18\n" +
+	"\acontent\x18\x03 \x01(\tR\acontent\x12!\n" +
+	"\fcontent_type\x18\x04 \x01(\tR\vcontentType\x12\x1d\n" +
+	"\n" +
+	"source_url\x18\x05 \x01(\tR\tsourceUrl\x12\x1d\n" +
+	"\n" +
+	"page_title\x18\x06 \x01(\tR\tpageTitle\"|\n" +
+	"\x12CreateClipResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x122\n" +
+	"\bmaterial\x18\x03 \x01(\v2\x16.material.MaterialInfoR\bmaterial\"Q\n" +
 	"\x15DeleteMaterialRequest\x12\x1f\n" +
 	"\vmaterial_id\x18\x01 \x01(\tR\n" +
 	"materialId\x12\x17\n" +
@@ -1258,7 +5005,37 @@ const file_proto_material_material_proto_rawDesc = "" +
 	"\tpage_size\x18\x03 \x01(\x05R\bpageSize\"c\n" +
 	"\x15ListMaterialsResponse\x124\n" +
 	"\tmaterials\x18\x01 \x03(\v2\x16.material.MaterialInfoR\tmaterials\x12\x14\n" +
-	"\x05total\x18\x02 \x01(\x03R\x05total\"\xbe\x03\n" +
+	"\x05total\x18\x02 \x01(\x03R\x05total\"U\n" +
+	"\x19ListChildMaterialsRequest\x12\x1f\n" +
+	"\vmaterial_id\x18\x01 \x01(\tR\n" +
+	"materialId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\"\x86\x01\n" +
+	"\x1aListChildMaterialsResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x124\n" +
+	"\tmaterials\x18\x03 \x03(\v2\x16.material.MaterialInfoR\tmaterials\"N\n" +
+	"\x12GetMaterialRequest\x12\x1f\n" +
+	"\vmaterial_id\x18\x01 \x01(\tR\n" +
+	"materialId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\"y\n" +
+	"\x13GetMaterialResponse\x12\x14\n" +
+	"\x05found\x18\x01 \x01(\bR\x05found\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x122\n" +
+	"\bmaterial\x18\x03 \x01(\v2\x16.material.MaterialInfoR\bmaterial\"x\n" +
+	"\x15GetMaterialURLRequest\x12\x1f\n" +
+	"\vmaterial_id\x18\x01 \x01(\tR\n" +
+	"materialId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12%\n" +
+	"\x0eexpiry_seconds\x18\x03 \x01(\x03R\rexpirySeconds\"\xe1\x01\n" +
+	"\x16GetMaterialURLResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12\x10\n" +
+	"\x03url\x18\x03 \x01(\tR\x03url\x12!\n" +
+	"\fcontent_type\x18\x04 \x01(\tR\vcontentType\x12\x1d\n" +
+	"\n" +
+	"size_bytes\x18\x05 \x01(\x03R\tsizeBytes\x129\n" +
+	"\n" +
+	"expires_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAtJ\x04\b\x06\x10\a\"\x98\x05\n" +
 	"\x10ProcessingResult\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1f\n" +
 	"\vmaterial_id\x18\x02 \x01(\tR\n" +
@@ -1267,16 +5044,22 @@ const file_proto_material_material_proto_rawDesc = "" +
 	"\x04type\x18\x04 \x01(\x0e2\x18.material.ProcessingTypeR\x04type\x122\n" +
 	"\x06status\x18\x05 \x01(\x0e2\x1a.material.ProcessingStatusR\x06status\x12\x18\n" +
 	"\acontent\x18\x06 \x01(\tR\acontent\x12D\n" +
-	"\bmetadata\x18\a \x03(\v2(.material.ProcessingResult.MetadataEntryR\bmetadata\x12\x1d\n" +
+	"\bmetadata\x18\a \x03(\v2(.material.ProcessingResult.MetadataEntryR\bmetadata\x129\n" +
 	"\n" +
-	"created_at\x18\b \x01(\tR\tcreatedAt\x12\x1d\n" +
+	"created_at\x18\r \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
 	"\n" +
-	"updated_at\x18\t \x01(\tR\tupdatedAt\x12#\n" +
+	"updated_at\x18\x0e \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12#\n" +
 	"\rerror_message\x18\n" +
-	" \x01(\tR\ferrorMessage\x1a;\n" +
+	" \x01(\tR\ferrorMessage\x12\x1f\n" +
+	"\vretry_count\x18\v \x01(\x05R\n" +
+	"retryCount\x12\x1a\n" +
+	"\bprogress\x18\f \x01(\x02R\bprogress\x12\x16\n" +
+	"\x06shadow\x18\x0f \x01(\bR\x06shadow\x12?\n" +
+	"\rscheduled_for\x18\x10 \x01(\v2\x1a.google.protobuf.TimestampR\fscheduledFor\x1a;\n" +
 	"\rMetadataEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x85\x02\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01J\x04\b\b\x10\tJ\x04\b\t\x10\n" +
+	"\"\x85\x02\n" +
 	"\x16ProcessMaterialRequest\x12\x1f\n" +
 	"\vmaterial_id\x18\x01 \x01(\tR\n" +
 	"materialId\x12\x17\n" +
@@ -1285,12 +5068,19 @@ const file_proto_material_material_proto_rawDesc = "" +
 	"\aoptions\x18\x04 \x03(\v2-.material.ProcessMaterialRequest.OptionsEntryR\aoptions\x1a:\n" +
 	"\fOptionsEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x9a\x01\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x89\x01\n" +
+	"\x0fProcessingLimit\x12\x1b\n" +
+	"\tin_flight\x18\x01 \x01(\x05R\binFlight\x12\x16\n" +
+	"\x06queued\x18\x02 \x01(\x05R\x06queued\x12\"\n" +
+	"\rmax_in_flight\x18\x03 \x01(\x05R\vmaxInFlight\x12\x1d\n" +
+	"\n" +
+	"max_queued\x18\x04 \x01(\x05R\tmaxQueued\"\xcb\x01\n" +
 	"\x17ProcessMaterialResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
 	"\amessage\x18\x02 \x01(\tR\amessage\x12\x17\n" +
 	"\atask_id\x18\x03 \x01(\tR\x06taskId\x122\n" +
-	"\x06result\x18\x04 \x01(\v2\x1a.material.ProcessingResultR\x06result\"\x84\x01\n" +
+	"\x06result\x18\x04 \x01(\v2\x1a.material.ProcessingResultR\x06result\x12/\n" +
+	"\x05limit\x18\x05 \x01(\v2\x19.material.ProcessingLimitR\x05limit\"\x84\x01\n" +
 	"\x1aGetProcessingResultRequest\x12\x1f\n" +
 	"\vmaterial_id\x18\x01 \x01(\tR\n" +
 	"materialId\x12\x17\n" +
@@ -1321,26 +5111,296 @@ const file_proto_material_material_proto_rawDesc = "" +
 	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"T\n" +
 	"\x1eUpdateProcessingResultResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"V\n" +
+	"\x1fUpdateProcessingProgressRequest\x12\x17\n" +
+	"\atask_id\x18\x01 \x01(\tR\x06taskId\x12\x1a\n" +
+	"\bprogress\x18\x02 \x01(\x02R\bprogress\"V\n" +
+	" UpdateProcessingProgressResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"N\n" +
+	"\x1aRetryProcessingTaskRequest\x12\x17\n" +
+	"\atask_id\x18\x01 \x01(\tR\x06taskId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\"\xb6\x01\n" +
+	"\x1bRetryProcessingTaskResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x122\n" +
+	"\x06result\x18\x03 \x01(\v2\x1a.material.ProcessingResultR\x06result\x12/\n" +
+	"\x05limit\x18\x04 \x01(\v2\x19.material.ProcessingLimitR\x05limit\"\xf6\x01\n" +
+	"\x1fCompareProcessingResultsRequest\x12\x1f\n" +
+	"\vmaterial_id\x18\x01 \x01(\tR\n" +
+	"materialId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12,\n" +
+	"\x04type\x18\x03 \x01(\x0e2\x18.material.ProcessingTypeR\x04type\x12 \n" +
+	"\fbase_task_id\x18\x04 \x01(\tR\n" +
+	"baseTaskId\x12$\n" +
+	"\x0etarget_task_id\x18\x05 \x01(\tR\ftargetTaskId\x12#\n" +
+	"\rcontext_lines\x18\x06 \x01(\x05R\fcontextLines\"\x80\x02\n" +
+	"\rResultQuality\x12\x17\n" +
+	"\atask_id\x18\x01 \x01(\tR\x06taskId\x12\x16\n" +
+	"\x06engine\x18\x02 \x01(\tR\x06engine\x12\x16\n" +
+	"\x06shadow\x18\x03 \x01(\bR\x06shadow\x12\x14\n" +
+	"\x05chars\x18\x04 \x01(\x05R\x05chars\x12\x14\n" +
+	"\x05lines\x18\x05 \x01(\x05R\x05lines\x12\x1e\n" +
+	"\n" +
+	"confidence\x18\x06 \x01(\x02R\n" +
+	"confidence\x12\x1f\n" +
+	"\vduration_ms\x18\a \x01(\x03R\n" +
+	"durationMs\x129\n" +
+	"\n" +
+	"created_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\".\n" +
+	"\bDiffLine\x12\x0e\n" +
+	"\x02op\x18\x01 \x01(\tR\x02op\x12\x12\n" +
+	"\x04text\x18\x02 \x01(\tR\x04text\"\xb8\x01\n" +
+	"\bDiffHunk\x12\x1d\n" +
+	"\n" +
+	"base_start\x18\x01 \x01(\x05R\tbaseStart\x12\x1d\n" +
+	"\n" +
+	"base_lines\x18\x02 \x01(\x05R\tbaseLines\x12!\n" +
+	"\ftarget_start\x18\x03 \x01(\x05R\vtargetStart\x12!\n" +
+	"\ftarget_lines\x18\x04 \x01(\x05R\vtargetLines\x12(\n" +
+	"\x05lines\x18\x05 \x03(\v2\x12.material.DiffLineR\x05lines\"\x8b\x03\n" +
+	" CompareProcessingResultsResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12+\n" +
+	"\x04base\x18\x03 \x01(\v2\x17.material.ResultQualityR\x04base\x12/\n" +
+	"\x06target\x18\x04 \x01(\v2\x17.material.ResultQualityR\x06target\x12\x1e\n" +
+	"\n" +
+	"similarity\x18\x05 \x01(\x01R\n" +
+	"similarity\x12\x1f\n" +
+	"\vlines_added\x18\x06 \x01(\x05R\n" +
+	"linesAdded\x12#\n" +
+	"\rlines_removed\x18\a \x01(\x05R\flinesRemoved\x12'\n" +
+	"\x0flines_unchanged\x18\b \x01(\x05R\x0elinesUnchanged\x12(\n" +
+	"\x05hunks\x18\t \x03(\v2\x12.material.DiffHunkR\x05hunks\x12\x1c\n" +
+	"\ttruncated\x18\n" +
+	" \x01(\bR\ttruncated\"U\n" +
+	"\x19EstimateProcessingRequest\x12\x1f\n" +
+	"\vmaterial_id\x18\x01 \x01(\tR\n" +
+	"materialId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\"\xb4\x01\n" +
+	"\x12ProcessingEstimate\x12,\n" +
+	"\x04type\x18\x01 \x01(\x0e2\x18.material.ProcessingTypeR\x04type\x12\x14\n" +
+	"\x05pages\x18\x02 \x01(\x05R\x05pages\x12\x18\n" +
+	"\aminutes\x18\x03 \x01(\x01R\aminutes\x12\x14\n" +
+	"\x05chars\x18\x04 \x01(\x03R\x05chars\x12\x16\n" +
+	"\x06tokens\x18\x05 \x01(\x03R\x06tokens\x12\x12\n" +
+	"\x04cost\x18\x06 \x01(\x01R\x04cost\"\x86\x03\n" +
+	"\x1aEstimateProcessingResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12\x1b\n" +
+	"\tfile_type\x18\x03 \x01(\tR\bfileType\x12\x1d\n" +
+	"\n" +
+	"size_bytes\x18\x04 \x01(\x03R\tsizeBytes\x12\x1d\n" +
+	"\n" +
+	"page_count\x18\x05 \x01(\x05R\tpageCount\x12)\n" +
+	"\x10duration_seconds\x18\x06 \x01(\x01R\x0fdurationSeconds\x12\x16\n" +
+	"\x06probed\x18\a \x01(\bR\x06probed\x12\x1f\n" +
+	"\vprobe_error\x18\b \x01(\tR\n" +
+	"probeError\x12:\n" +
+	"\testimates\x18\t \x03(\v2\x1c.material.ProcessingEstimateR\testimates\x12\x1d\n" +
+	"\n" +
+	"total_cost\x18\n" +
+	" \x01(\x01R\ttotalCost\x12\x1a\n" +
+	"\bcurrency\x18\v \x01(\tR\bcurrency\"\x99\x03\n" +
+	"\x0fDerivedArtifact\x12\x1f\n" +
+	"\vmaterial_id\x18\x01 \x01(\tR\n" +
+	"materialId\x12\x12\n" +
+	"\x04kind\x18\x02 \x01(\tR\x04kind\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\x12$\n" +
+	"\x0esource_task_id\x18\x04 \x01(\tR\fsourceTaskId\x12!\n" +
+	"\fstale_reason\x18\x05 \x01(\tR\vstaleReason\x12;\n" +
+	"\vstale_since\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"staleSince\x12A\n" +
+	"\x0eregenerated_at\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\rregeneratedAt\x12#\n" +
+	"\rerror_message\x18\b \x01(\tR\ferrorMessage\x129\n" +
+	"\n" +
+	"updated_at\x18\f \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAtJ\x04\b\x06\x10\aJ\x04\b\a\x10\bJ\x04\b\t\x10\n" +
+	"\"W\n" +
+	"\x1bListDerivedArtifactsRequest\x12\x1f\n" +
+	"\vmaterial_id\x18\x01 \x01(\tR\n" +
+	"materialId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\"\x8b\x01\n" +
+	"\x1cListDerivedArtifactsResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x127\n" +
+	"\tartifacts\x18\x03 \x03(\v2\x19.material.DerivedArtifactR\tartifacts\"j\n" +
+	"\x18RegenerateDerivedRequest\x12\x1f\n" +
+	"\vmaterial_id\x18\x01 \x01(\tR\n" +
+	"materialId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x14\n" +
+	"\x05kinds\x18\x03 \x03(\tR\x05kinds\"\x88\x01\n" +
+	"\x19RegenerateDerivedResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x127\n" +
+	"\tartifacts\x18\x03 \x03(\v2\x19.material.DerivedArtifactR\tartifacts\"\xb6\x01\n" +
+	"\x1cUpdateDerivedArtifactRequest\x12\x1f\n" +
+	"\vmaterial_id\x18\x01 \x01(\tR\n" +
+	"materialId\x12\x12\n" +
+	"\x04kind\x18\x02 \x01(\tR\x04kind\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\x12$\n" +
+	"\x0esource_task_id\x18\x04 \x01(\tR\fsourceTaskId\x12#\n" +
+	"\rerror_message\x18\x05 \x01(\tR\ferrorMessage\"S\n" +
+	"\x1dUpdateDerivedArtifactResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"\xa1\x03\n" +
+	"\n" +
+	"Annotation\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1f\n" +
+	"\vmaterial_id\x18\x02 \x01(\tR\n" +
+	"materialId\x12\x17\n" +
+	"\auser_id\x18\x03 \x01(\tR\x06userId\x12\x12\n" +
+	"\x04kind\x18\x04 \x01(\tR\x04kind\x12\x1f\n" +
+	"\vanchor_type\x18\x05 \x01(\tR\n" +
+	"anchorType\x12\x12\n" +
+	"\x04page\x18\x06 \x01(\x05R\x04page\x12\x1d\n" +
+	"\n" +
+	"start_time\x18\a \x01(\x01R\tstartTime\x12\x19\n" +
+	"\bend_time\x18\b \x01(\x01R\aendTime\x12\x14\n" +
+	"\x05quote\x18\t \x01(\tR\x05quote\x12\x18\n" +
+	"\acontent\x18\n" +
+	" \x01(\tR\acontent\x12\x14\n" +
+	"\x05color\x18\v \x01(\tR\x05color\x129\n" +
+	"\n" +
+	"created_at\x18\x0e \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\x0f \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAtJ\x04\b\f\x10\rJ\x04\b\r\x10\x0e\"\x9c\x02\n" +
+	"\x17CreateAnnotationRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1f\n" +
+	"\vmaterial_id\x18\x02 \x01(\tR\n" +
+	"materialId\x12\x12\n" +
+	"\x04kind\x18\x03 \x01(\tR\x04kind\x12\x1f\n" +
+	"\vanchor_type\x18\x04 \x01(\tR\n" +
+	"anchorType\x12\x12\n" +
+	"\x04page\x18\x05 \x01(\x05R\x04page\x12\x1d\n" +
+	"\n" +
+	"start_time\x18\x06 \x01(\x01R\tstartTime\x12\x19\n" +
+	"\bend_time\x18\a \x01(\x01R\aendTime\x12\x14\n" +
+	"\x05quote\x18\b \x01(\tR\x05quote\x12\x18\n" +
+	"\acontent\x18\t \x01(\tR\acontent\x12\x14\n" +
+	"\x05color\x18\n" +
+	" \x01(\tR\x05color\"r\n" +
+	"\x17UpdateAnnotationRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x18\n" +
+	"\acontent\x18\x03 \x01(\tR\acontent\x12\x14\n" +
+	"\x05color\x18\x04 \x01(\tR\x05color\"~\n" +
+	"\x12AnnotationResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x124\n" +
+	"\n" +
+	"annotation\x18\x03 \x01(\v2\x14.material.AnnotationR\n" +
+	"annotation\"B\n" +
+	"\x17DeleteAnnotationRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\"N\n" +
+	"\x18DeleteAnnotationResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"\xb6\x01\n" +
+	"\x16ListAnnotationsRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12!\n" +
+	"\fmaterial_ids\x18\x02 \x03(\tR\vmaterialIds\x12\x12\n" +
+	"\x04kind\x18\x03 \x01(\tR\x04kind\x12\x12\n" +
+	"\x04page\x18\x04 \x01(\x05R\x04page\x12\x1d\n" +
+	"\n" +
+	"start_time\x18\x05 \x01(\x01R\tstartTime\x12\x19\n" +
+	"\bend_time\x18\x06 \x01(\x01R\aendTime\"\x85\x01\n" +
+	"\x17ListAnnotationsResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x126\n" +
+	"\vannotations\x18\x03 \x03(\v2\x14.material.AnnotationR\vannotations\"\xd6\x02\n" +
+	"\rUploadSession\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x14\n" +
+	"\x05title\x18\x03 \x01(\tR\x05title\x12+\n" +
+	"\x11original_filename\x18\x04 \x01(\tR\x10originalFilename\x12\x12\n" +
+	"\x04size\x18\x05 \x01(\x03R\x04size\x12\x16\n" +
+	"\x06offset\x18\x06 \x01(\x03R\x06offset\x12\x16\n" +
+	"\x06status\x18\a \x01(\tR\x06status\x12\x1f\n" +
+	"\vmaterial_id\x18\b \x01(\tR\n" +
+	"materialId\x129\n" +
+	"\n" +
+	"expires_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\x129\n" +
+	"\n" +
+	"created_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"\xaf\x01\n" +
+	"\x1aCreateUploadSessionRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12+\n" +
+	"\x11original_filename\x18\x03 \x01(\tR\x10originalFilename\x12\x12\n" +
+	"\x04size\x18\x04 \x01(\x03R\x04size\x12!\n" +
+	"\fon_duplicate\x18\x05 \x01(\tR\vonDuplicate\"Q\n" +
+	"\x17GetUploadSessionRequest\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x01 \x01(\tR\tsessionId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\"~\n" +
+	"\x1aAppendUploadSessionRequest\x127\n" +
+	"\x06target\x18\x01 \x01(\v2\x1d.material.UploadSessionTargetH\x00R\x06target\x12\x1f\n" +
+	"\n" +
+	"chunk_data\x18\x02 \x01(\fH\x00R\tchunkDataB\x06\n" +
+	"\x04data\"e\n" +
+	"\x13UploadSessionTarget\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x01 \x01(\tR\tsessionId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x16\n" +
+	"\x06offset\x18\x03 \x01(\x03R\x06offset\"\xbd\x01\n" +
+	"\x15UploadSessionResponse\x121\n" +
+	"\asession\x18\x01 \x01(\v2\x17.material.UploadSessionR\asession\x12\x1c\n" +
+	"\tcompleted\x18\x04 \x01(\bR\tcompleted\x12;\n" +
+	"\n" +
+	"duplicates\x18\x02 \x03(\v2\x1b.material.DuplicateMaterialR\n" +
+	"duplicates\x12\x16\n" +
+	"\x06linked\x18\x03 \x01(\bR\x06linked\"T\n" +
+	"\x1aDeleteUploadSessionRequest\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x01 \x01(\tR\tsessionId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\"Q\n" +
+	"\x1bDeleteUploadSessionResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
 	"\amessage\x18\x02 \x01(\tR\amessage*4\n" +
 	"\x0eProcessingType\x12\a\n" +
 	"\x03OCR\x10\x00\x12\a\n" +
 	"\x03ASR\x10\x01\x12\x10\n" +
-	"\fLLM_ANALYSIS\x10\x02*J\n" +
+	"\fLLM_ANALYSIS\x10\x02*e\n" +
 	"\x10ProcessingStatus\x12\v\n" +
 	"\aPENDING\x10\x00\x12\x0e\n" +
 	"\n" +
 	"PROCESSING\x10\x01\x12\r\n" +
 	"\tCOMPLETED\x10\x02\x12\n" +
 	"\n" +
-	"\x06FAILED\x10\x032\xa2\x05\n" +
+	"\x06FAILED\x10\x03\x12\n" +
+	"\n" +
+	"\x06QUEUED\x10\x04\x12\r\n" +
+	"\tSCHEDULED\x10\x052\x8b\x14\n" +
 	"\x0fMaterialService\x12U\n" +
 	"\x0eUploadMaterial\x12\x1f.material.UploadMaterialRequest\x1a .material.UploadMaterialResponse(\x01\x12S\n" +
-	"\x0eDeleteMaterial\x12\x1f.material.DeleteMaterialRequest\x1a .material.DeleteMaterialResponse\x12P\n" +
-	"\rListMaterials\x12\x1e.material.ListMaterialsRequest\x1a\x1f.material.ListMaterialsResponse\x12V\n" +
+	"\x0eDeleteMaterial\x12\x1f.material.DeleteMaterialRequest\x1a .material.DeleteMaterialResponse\x12G\n" +
+	"\n" +
+	"CreateClip\x12\x1b.material.CreateClipRequest\x1a\x1c.material.CreateClipResponse\x12P\n" +
+	"\rListMaterials\x12\x1e.material.ListMaterialsRequest\x1a\x1f.material.ListMaterialsResponse\x12J\n" +
+	"\vGetMaterial\x12\x1c.material.GetMaterialRequest\x1a\x1d.material.GetMaterialResponse\x12S\n" +
+	"\x0eGetMaterialURL\x12\x1f.material.GetMaterialURLRequest\x1a .material.GetMaterialURLResponse\x12_\n" +
+	"\x12ListChildMaterials\x12#.material.ListChildMaterialsRequest\x1a$.material.ListChildMaterialsResponse\x12k\n" +
+	"\x16ListDuplicateMaterials\x12'.material.ListDuplicateMaterialsRequest\x1a(.material.ListDuplicateMaterialsResponse\x12V\n" +
 	"\x0fProcessMaterial\x12 .material.ProcessMaterialRequest\x1a!.material.ProcessMaterialResponse\x12b\n" +
 	"\x13GetProcessingResult\x12$.material.GetProcessingResultRequest\x1a%.material.GetProcessingResultResponse\x12h\n" +
 	"\x15ListProcessingResults\x12&.material.ListProcessingResultsRequest\x1a'.material.ListProcessingResultsResponse\x12k\n" +
-	"\x16UpdateProcessingResult\x12'.material.UpdateProcessingResultRequest\x1a(.material.UpdateProcessingResultResponseB.Z,github.com/RigelNana/arkstudy/proto/materialb\x06proto3"
+	"\x16UpdateProcessingResult\x12'.material.UpdateProcessingResultRequest\x1a(.material.UpdateProcessingResultResponse\x12b\n" +
+	"\x13RetryProcessingTask\x12$.material.RetryProcessingTaskRequest\x1a%.material.RetryProcessingTaskResponse\x12q\n" +
+	"\x18UpdateProcessingProgress\x12).material.UpdateProcessingProgressRequest\x1a*.material.UpdateProcessingProgressResponse\x12q\n" +
+	"\x18CompareProcessingResults\x12).material.CompareProcessingResultsRequest\x1a*.material.CompareProcessingResultsResponse\x12_\n" +
+	"\x12EstimateProcessing\x12#.material.EstimateProcessingRequest\x1a$.material.EstimateProcessingResponse\x12e\n" +
+	"\x14ListDerivedArtifacts\x12%.material.ListDerivedArtifactsRequest\x1a&.material.ListDerivedArtifactsResponse\x12\\\n" +
+	"\x11RegenerateDerived\x12\".material.RegenerateDerivedRequest\x1a#.material.RegenerateDerivedResponse\x12h\n" +
+	"\x15UpdateDerivedArtifact\x12&.material.UpdateDerivedArtifactRequest\x1a'.material.UpdateDerivedArtifactResponse\x12S\n" +
+	"\x10CreateAnnotation\x12!.material.CreateAnnotationRequest\x1a\x1c.material.AnnotationResponse\x12S\n" +
+	"\x10UpdateAnnotation\x12!.material.UpdateAnnotationRequest\x1a\x1c.material.AnnotationResponse\x12Y\n" +
+	"\x10DeleteAnnotation\x12!.material.DeleteAnnotationRequest\x1a\".material.DeleteAnnotationResponse\x12V\n" +
+	"\x0fListAnnotations\x12 .material.ListAnnotationsRequest\x1a!.material.ListAnnotationsResponse\x12\\\n" +
+	"\x13CreateUploadSession\x12$.material.CreateUploadSessionRequest\x1a\x1f.material.UploadSessionResponse\x12V\n" +
+	"\x10GetUploadSession\x12!.material.GetUploadSessionRequest\x1a\x1f.material.UploadSessionResponse\x12^\n" +
+	"\x13AppendUploadSession\x12$.material.AppendUploadSessionRequest\x1a\x1f.material.UploadSessionResponse(\x01\x12b\n" +
+	"\x13DeleteUploadSession\x12$.material.DeleteUploadSessionRequest\x1a%.material.DeleteUploadSessionResponseB.Z,github.com/RigelNana/arkstudy/proto/materialb\x06proto3"
 
 var (
 	file_proto_material_material_proto_rawDescOnce sync.Once
@@ -1355,64 +5415,196 @@ func file_proto_material_material_proto_rawDescGZIP() []byte {
 }
 
 var file_proto_material_material_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
-var file_proto_material_material_proto_msgTypes = make([]protoimpl.MessageInfo, 19)
+var file_proto_material_material_proto_msgTypes = make([]protoimpl.MessageInfo, 69)
 var file_proto_material_material_proto_goTypes = []any{
-	(ProcessingType)(0),                    // 0: material.ProcessingType
-	(ProcessingStatus)(0),                  // 1: material.ProcessingStatus
-	(*MaterialInfo)(nil),                   // 2: material.MaterialInfo
-	(*UploadMaterialRequest)(nil),          // 3: material.UploadMaterialRequest
-	(*UploadMaterialResponse)(nil),         // 4: material.UploadMaterialResponse
-	(*DeleteMaterialRequest)(nil),          // 5: material.DeleteMaterialRequest
-	(*DeleteMaterialResponse)(nil),         // 6: material.DeleteMaterialResponse
-	(*ListMaterialsRequest)(nil),           // 7: material.ListMaterialsRequest
-	(*ListMaterialsResponse)(nil),          // 8: material.ListMaterialsResponse
-	(*ProcessingResult)(nil),               // 9: material.ProcessingResult
-	(*ProcessMaterialRequest)(nil),         // 10: material.ProcessMaterialRequest
-	(*ProcessMaterialResponse)(nil),        // 11: material.ProcessMaterialResponse
-	(*GetProcessingResultRequest)(nil),     // 12: material.GetProcessingResultRequest
-	(*GetProcessingResultResponse)(nil),    // 13: material.GetProcessingResultResponse
-	(*ListProcessingResultsRequest)(nil),   // 14: material.ListProcessingResultsRequest
-	(*ListProcessingResultsResponse)(nil),  // 15: material.ListProcessingResultsResponse
-	(*UpdateProcessingResultRequest)(nil),  // 16: material.UpdateProcessingResultRequest
-	(*UpdateProcessingResultResponse)(nil), // 17: material.UpdateProcessingResultResponse
-	nil,                                    // 18: material.ProcessingResult.MetadataEntry
-	nil,                                    // 19: material.ProcessMaterialRequest.OptionsEntry
-	nil,                                    // 20: material.UpdateProcessingResultRequest.MetadataEntry
+	(ProcessingType)(0),                      // 0: material.ProcessingType
+	(ProcessingStatus)(0),                    // 1: material.ProcessingStatus
+	(*MaterialInfo)(nil),                     // 2: material.MaterialInfo
+	(*MediaInfo)(nil),                        // 3: material.MediaInfo
+	(*DocumentInfo)(nil),                     // 4: material.DocumentInfo
+	(*TocEntry)(nil),                         // 5: material.TocEntry
+	(*UploadMaterialRequest)(nil),            // 6: material.UploadMaterialRequest
+	(*UploadMaterialResponse)(nil),           // 7: material.UploadMaterialResponse
+	(*DuplicateMaterial)(nil),                // 8: material.DuplicateMaterial
+	(*ListDuplicateMaterialsRequest)(nil),    // 9: material.ListDuplicateMaterialsRequest
+	(*ListDuplicateMaterialsResponse)(nil),   // 10: material.ListDuplicateMaterialsResponse
+	(*CreateClipRequest)(nil),                // 11: material.CreateClipRequest
+	(*CreateClipResponse)(nil),               // 12: material.CreateClipResponse
+	(*DeleteMaterialRequest)(nil),            // 13: material.DeleteMaterialRequest
+	(*DeleteMaterialResponse)(nil),           // 14: material.DeleteMaterialResponse
+	(*ListMaterialsRequest)(nil),             // 15: material.ListMaterialsRequest
+	(*ListMaterialsResponse)(nil),            // 16: material.ListMaterialsResponse
+	(*ListChildMaterialsRequest)(nil),        // 17: material.ListChildMaterialsRequest
+	(*ListChildMaterialsResponse)(nil),       // 18: material.ListChildMaterialsResponse
+	(*GetMaterialRequest)(nil),               // 19: material.GetMaterialRequest
+	(*GetMaterialResponse)(nil),              // 20: material.GetMaterialResponse
+	(*GetMaterialURLRequest)(nil),            // 21: material.GetMaterialURLRequest
+	(*GetMaterialURLResponse)(nil),           // 22: material.GetMaterialURLResponse
+	(*ProcessingResult)(nil),                 // 23: material.ProcessingResult
+	(*ProcessMaterialRequest)(nil),           // 24: material.ProcessMaterialRequest
+	(*ProcessingLimit)(nil),                  // 25: material.ProcessingLimit
+	(*ProcessMaterialResponse)(nil),          // 26: material.ProcessMaterialResponse
+	(*GetProcessingResultRequest)(nil),       // 27: material.GetProcessingResultRequest
+	(*GetProcessingResultResponse)(nil),      // 28: material.GetProcessingResultResponse
+	(*ListProcessingResultsRequest)(nil),     // 29: material.ListProcessingResultsRequest
+	(*ListProcessingResultsResponse)(nil),    // 30: material.ListProcessingResultsResponse
+	(*UpdateProcessingResultRequest)(nil),    // 31: material.UpdateProcessingResultRequest
+	(*UpdateProcessingResultResponse)(nil),   // 32: material.UpdateProcessingResultResponse
+	(*UpdateProcessingProgressRequest)(nil),  // 33: material.UpdateProcessingProgressRequest
+	(*UpdateProcessingProgressResponse)(nil), // 34: material.UpdateProcessingProgressResponse
+	(*RetryProcessingTaskRequest)(nil),       // 35: material.RetryProcessingTaskRequest
+	(*RetryProcessingTaskResponse)(nil),      // 36: material.RetryProcessingTaskResponse
+	(*CompareProcessingResultsRequest)(nil),  // 37: material.CompareProcessingResultsRequest
+	(*ResultQuality)(nil),                    // 38: material.ResultQuality
+	(*DiffLine)(nil),                         // 39: material.DiffLine
+	(*DiffHunk)(nil),                         // 40: material.DiffHunk
+	(*CompareProcessingResultsResponse)(nil), // 41: material.CompareProcessingResultsResponse
+	(*EstimateProcessingRequest)(nil),        // 42: material.EstimateProcessingRequest
+	(*ProcessingEstimate)(nil),               // 43: material.ProcessingEstimate
+	(*EstimateProcessingResponse)(nil),       // 44: material.EstimateProcessingResponse
+	(*DerivedArtifact)(nil),                  // 45: material.DerivedArtifact
+	(*ListDerivedArtifactsRequest)(nil),      // 46: material.ListDerivedArtifactsRequest
+	(*ListDerivedArtifactsResponse)(nil),     // 47: material.ListDerivedArtifactsResponse
+	(*RegenerateDerivedRequest)(nil),         // 48: material.RegenerateDerivedRequest
+	(*RegenerateDerivedResponse)(nil),        // 49: material.RegenerateDerivedResponse
+	(*UpdateDerivedArtifactRequest)(nil),     // 50: material.UpdateDerivedArtifactRequest
+	(*UpdateDerivedArtifactResponse)(nil),    // 51: material.UpdateDerivedArtifactResponse
+	(*Annotation)(nil),                       // 52: material.Annotation
+	(*CreateAnnotationRequest)(nil),          // 53: material.CreateAnnotationRequest
+	(*UpdateAnnotationRequest)(nil),          // 54: material.UpdateAnnotationRequest
+	(*AnnotationResponse)(nil),               // 55: material.AnnotationResponse
+	(*DeleteAnnotationRequest)(nil),          // 56: material.DeleteAnnotationRequest
+	(*DeleteAnnotationResponse)(nil),         // 57: material.DeleteAnnotationResponse
+	(*ListAnnotationsRequest)(nil),           // 58: material.ListAnnotationsRequest
+	(*ListAnnotationsResponse)(nil),          // 59: material.ListAnnotationsResponse
+	(*UploadSession)(nil),                    // 60: material.UploadSession
+	(*CreateUploadSessionRequest)(nil),       // 61: material.CreateUploadSessionRequest
+	(*GetUploadSessionRequest)(nil),          // 62: material.GetUploadSessionRequest
+	(*AppendUploadSessionRequest)(nil),       // 63: material.AppendUploadSessionRequest
+	(*UploadSessionTarget)(nil),              // 64: material.UploadSessionTarget
+	(*UploadSessionResponse)(nil),            // 65: material.UploadSessionResponse
+	(*DeleteUploadSessionRequest)(nil),       // 66: material.DeleteUploadSessionRequest
+	(*DeleteUploadSessionResponse)(nil),      // 67: material.DeleteUploadSessionResponse
+	nil,                                      // 68: material.ProcessingResult.MetadataEntry
+	nil,                                      // 69: material.ProcessMaterialRequest.OptionsEntry
+	nil,                                      // 70: material.UpdateProcessingResultRequest.MetadataEntry
+	(*timestamppb.Timestamp)(nil),            // 71: google.protobuf.Timestamp
 }
 var file_proto_material_material_proto_depIdxs = []int32{
-	2,  // 0: material.UploadMaterialRequest.metadata:type_name -> material.MaterialInfo
-	2,  // 1: material.ListMaterialsResponse.materials:type_name -> material.MaterialInfo
-	0,  // 2: material.ProcessingResult.type:type_name -> material.ProcessingType
-	1,  // 3: material.ProcessingResult.status:type_name -> material.ProcessingStatus
-	18, // 4: material.ProcessingResult.metadata:type_name -> material.ProcessingResult.MetadataEntry
-	0,  // 5: material.ProcessMaterialRequest.type:type_name -> material.ProcessingType
-	19, // 6: material.ProcessMaterialRequest.options:type_name -> material.ProcessMaterialRequest.OptionsEntry
-	9,  // 7: material.ProcessMaterialResponse.result:type_name -> material.ProcessingResult
-	0,  // 8: material.GetProcessingResultRequest.type:type_name -> material.ProcessingType
-	9,  // 9: material.GetProcessingResultResponse.result:type_name -> material.ProcessingResult
-	0,  // 10: material.ListProcessingResultsRequest.type:type_name -> material.ProcessingType
-	9,  // 11: material.ListProcessingResultsResponse.results:type_name -> material.ProcessingResult
-	1,  // 12: material.UpdateProcessingResultRequest.status:type_name -> material.ProcessingStatus
-	20, // 13: material.UpdateProcessingResultRequest.metadata:type_name -> material.UpdateProcessingResultRequest.MetadataEntry
-	3,  // 14: material.MaterialService.UploadMaterial:input_type -> material.UploadMaterialRequest
-	5,  // 15: material.MaterialService.DeleteMaterial:input_type -> material.DeleteMaterialRequest
-	7,  // 16: material.MaterialService.ListMaterials:input_type -> material.ListMaterialsRequest
-	10, // 17: material.MaterialService.ProcessMaterial:input_type -> material.ProcessMaterialRequest
-	12, // 18: material.MaterialService.GetProcessingResult:input_type -> material.GetProcessingResultRequest
-	14, // 19: material.MaterialService.ListProcessingResults:input_type -> material.ListProcessingResultsRequest
-	16, // 20: material.MaterialService.UpdateProcessingResult:input_type -> material.UpdateProcessingResultRequest
-	4,  // 21: material.MaterialService.UploadMaterial:output_type -> material.UploadMaterialResponse
-	6,  // 22: material.MaterialService.DeleteMaterial:output_type -> material.DeleteMaterialResponse
-	8,  // 23: material.MaterialService.ListMaterials:output_type -> material.ListMaterialsResponse
-	11, // 24: material.MaterialService.ProcessMaterial:output_type -> material.ProcessMaterialResponse
-	13, // 25: material.MaterialService.GetProcessingResult:output_type -> material.GetProcessingResultResponse
-	15, // 26: material.MaterialService.ListProcessingResults:output_type -> material.ListProcessingResultsResponse
-	17, // 27: material.MaterialService.UpdateProcessingResult:output_type -> material.UpdateProcessingResultResponse
-	21, // [21:28] is the sub-list for method output_type
-	14, // [14:21] is the sub-list for method input_type
-	14, // [14:14] is the sub-list for extension type_name
-	14, // [14:14] is the sub-list for extension extendee
-	0,  // [0:14] is the sub-list for field type_name
+	71, // 0: material.MaterialInfo.created_at:type_name -> google.protobuf.Timestamp
+	3,  // 1: material.MaterialInfo.media:type_name -> material.MediaInfo
+	4,  // 2: material.MaterialInfo.document:type_name -> material.DocumentInfo
+	71, // 3: material.MediaInfo.probed_at:type_name -> google.protobuf.Timestamp
+	5,  // 4: material.DocumentInfo.toc:type_name -> material.TocEntry
+	71, // 5: material.DocumentInfo.probed_at:type_name -> google.protobuf.Timestamp
+	2,  // 6: material.UploadMaterialRequest.metadata:type_name -> material.MaterialInfo
+	8,  // 7: material.UploadMaterialResponse.duplicates:type_name -> material.DuplicateMaterial
+	2,  // 8: material.DuplicateMaterial.material:type_name -> material.MaterialInfo
+	8,  // 9: material.ListDuplicateMaterialsResponse.duplicates:type_name -> material.DuplicateMaterial
+	2,  // 10: material.CreateClipResponse.material:type_name -> material.MaterialInfo
+	2,  // 11: material.ListMaterialsResponse.materials:type_name -> material.MaterialInfo
+	2,  // 12: material.ListChildMaterialsResponse.materials:type_name -> material.MaterialInfo
+	2,  // 13: material.GetMaterialResponse.material:type_name -> material.MaterialInfo
+	71, // 14: material.GetMaterialURLResponse.expires_at:type_name -> google.protobuf.Timestamp
+	0,  // 15: material.ProcessingResult.type:type_name -> material.ProcessingType
+	1,  // 16: material.ProcessingResult.status:type_name -> material.ProcessingStatus
+	68, // 17: material.ProcessingResult.metadata:type_name -> material.ProcessingResult.MetadataEntry
+	71, // 18: material.ProcessingResult.created_at:type_name -> google.protobuf.Timestamp
+	71, // 19: material.ProcessingResult.updated_at:type_name -> google.protobuf.Timestamp
+	71, // 20: material.ProcessingResult.scheduled_for:type_name -> google.protobuf.Timestamp
+	0,  // 21: material.ProcessMaterialRequest.type:type_name -> material.ProcessingType
+	69, // 22: material.ProcessMaterialRequest.options:type_name -> material.ProcessMaterialRequest.OptionsEntry
+	23, // 23: material.ProcessMaterialResponse.result:type_name -> material.ProcessingResult
+	25, // 24: material.ProcessMaterialResponse.limit:type_name -> material.ProcessingLimit
+	0,  // 25: material.GetProcessingResultRequest.type:type_name -> material.ProcessingType
+	23, // 26: material.GetProcessingResultResponse.result:type_name -> material.ProcessingResult
+	0,  // 27: material.ListProcessingResultsRequest.type:type_name -> material.ProcessingType
+	23, // 28: material.ListProcessingResultsResponse.results:type_name -> material.ProcessingResult
+	1,  // 29: material.UpdateProcessingResultRequest.status:type_name -> material.ProcessingStatus
+	70, // 30: material.UpdateProcessingResultRequest.metadata:type_name -> material.UpdateProcessingResultRequest.MetadataEntry
+	23, // 31: material.RetryProcessingTaskResponse.result:type_name -> material.ProcessingResult
+	25, // 32: material.RetryProcessingTaskResponse.limit:type_name -> material.ProcessingLimit
+	0,  // 33: material.CompareProcessingResultsRequest.type:type_name -> material.ProcessingType
+	71, // 34: material.ResultQuality.created_at:type_name -> google.protobuf.Timestamp
+	39, // 35: material.DiffHunk.lines:type_name -> material.DiffLine
+	38, // 36: material.CompareProcessingResultsResponse.base:type_name -> material.ResultQuality
+	38, // 37: material.CompareProcessingResultsResponse.target:type_name -> material.ResultQuality
+	40, // 38: material.CompareProcessingResultsResponse.hunks:type_name -> material.DiffHunk
+	0,  // 39: material.ProcessingEstimate.type:type_name -> material.ProcessingType
+	43, // 40: material.EstimateProcessingResponse.estimates:type_name -> material.ProcessingEstimate
+	71, // 41: material.DerivedArtifact.stale_since:type_name -> google.protobuf.Timestamp
+	71, // 42: material.DerivedArtifact.regenerated_at:type_name -> google.protobuf.Timestamp
+	71, // 43: material.DerivedArtifact.updated_at:type_name -> google.protobuf.Timestamp
+	45, // 44: material.ListDerivedArtifactsResponse.artifacts:type_name -> material.DerivedArtifact
+	45, // 45: material.RegenerateDerivedResponse.artifacts:type_name -> material.DerivedArtifact
+	71, // 46: material.Annotation.created_at:type_name -> google.protobuf.Timestamp
+	71, // 47: material.Annotation.updated_at:type_name -> google.protobuf.Timestamp
+	52, // 48: material.AnnotationResponse.annotation:type_name -> material.Annotation
+	52, // 49: material.ListAnnotationsResponse.annotations:type_name -> material.Annotation
+	71, // 50: material.UploadSession.expires_at:type_name -> google.protobuf.Timestamp
+	71, // 51: material.UploadSession.created_at:type_name -> google.protobuf.Timestamp
+	64, // 52: material.AppendUploadSessionRequest.target:type_name -> material.UploadSessionTarget
+	60, // 53: material.UploadSessionResponse.session:type_name -> material.UploadSession
+	8,  // 54: material.UploadSessionResponse.duplicates:type_name -> material.DuplicateMaterial
+	6,  // 55: material.MaterialService.UploadMaterial:input_type -> material.UploadMaterialRequest
+	13, // 56: material.MaterialService.DeleteMaterial:input_type -> material.DeleteMaterialRequest
+	11, // 57: material.MaterialService.CreateClip:input_type -> material.CreateClipRequest
+	15, // 58: material.MaterialService.ListMaterials:input_type -> material.ListMaterialsRequest
+	19, // 59: material.MaterialService.GetMaterial:input_type -> material.GetMaterialRequest
+	21, // 60: material.MaterialService.GetMaterialURL:input_type -> material.GetMaterialURLRequest
+	17, // 61: material.MaterialService.ListChildMaterials:input_type -> material.ListChildMaterialsRequest
+	9,  // 62: material.MaterialService.ListDuplicateMaterials:input_type -> material.ListDuplicateMaterialsRequest
+	24, // 63: material.MaterialService.ProcessMaterial:input_type -> material.ProcessMaterialRequest
+	27, // 64: material.MaterialService.GetProcessingResult:input_type -> material.GetProcessingResultRequest
+	29, // 65: material.MaterialService.ListProcessingResults:input_type -> material.ListProcessingResultsRequest
+	31, // 66: material.MaterialService.UpdateProcessingResult:input_type -> material.UpdateProcessingResultRequest
+	35, // 67: material.MaterialService.RetryProcessingTask:input_type -> material.RetryProcessingTaskRequest
+	33, // 68: material.MaterialService.UpdateProcessingProgress:input_type -> material.UpdateProcessingProgressRequest
+	37, // 69: material.MaterialService.CompareProcessingResults:input_type -> material.CompareProcessingResultsRequest
+	42, // 70: material.MaterialService.EstimateProcessing:input_type -> material.EstimateProcessingRequest
+	46, // 71: material.MaterialService.ListDerivedArtifacts:input_type -> material.ListDerivedArtifactsRequest
+	48, // 72: material.MaterialService.RegenerateDerived:input_type -> material.RegenerateDerivedRequest
+	50, // 73: material.MaterialService.UpdateDerivedArtifact:input_type -> material.UpdateDerivedArtifactRequest
+	53, // 74: material.MaterialService.CreateAnnotation:input_type -> material.CreateAnnotationRequest
+	54, // 75: material.MaterialService.UpdateAnnotation:input_type -> material.UpdateAnnotationRequest
+	56, // 76: material.MaterialService.DeleteAnnotation:input_type -> material.DeleteAnnotationRequest
+	58, // 77: material.MaterialService.ListAnnotations:input_type -> material.ListAnnotationsRequest
+	61, // 78: material.MaterialService.CreateUploadSession:input_type -> material.CreateUploadSessionRequest
+	62, // 79: material.MaterialService.GetUploadSession:input_type -> material.GetUploadSessionRequest
+	63, // 80: material.MaterialService.AppendUploadSession:input_type -> material.AppendUploadSessionRequest
+	66, // 81: material.MaterialService.DeleteUploadSession:input_type -> material.DeleteUploadSessionRequest
+	7,  // 82: material.MaterialService.UploadMaterial:output_type -> material.UploadMaterialResponse
+	14, // 83: material.MaterialService.DeleteMaterial:output_type -> material.DeleteMaterialResponse
+	12, // 84: material.MaterialService.CreateClip:output_type -> material.CreateClipResponse
+	16, // 85: material.MaterialService.ListMaterials:output_type -> material.ListMaterialsResponse
+	20, // 86: material.MaterialService.GetMaterial:output_type -> material.GetMaterialResponse
+	22, // 87: material.MaterialService.GetMaterialURL:output_type -> material.GetMaterialURLResponse
+	18, // 88: material.MaterialService.ListChildMaterials:output_type -> material.ListChildMaterialsResponse
+	10, // 89: material.MaterialService.ListDuplicateMaterials:output_type -> material.ListDuplicateMaterialsResponse
+	26, // 90: material.MaterialService.ProcessMaterial:output_type -> material.ProcessMaterialResponse
+	28, // 91: material.MaterialService.GetProcessingResult:output_type -> material.GetProcessingResultResponse
+	30, // 92: material.MaterialService.ListProcessingResults:output_type -> material.ListProcessingResultsResponse
+	32, // 93: material.MaterialService.UpdateProcessingResult:output_type -> material.UpdateProcessingResultResponse
+	36, // 94: material.MaterialService.RetryProcessingTask:output_type -> material.RetryProcessingTaskResponse
+	34, // 95: material.MaterialService.UpdateProcessingProgress:output_type -> material.UpdateProcessingProgressResponse
+	41, // 96: material.MaterialService.CompareProcessingResults:output_type -> material.CompareProcessingResultsResponse
+	44, // 97: material.MaterialService.EstimateProcessing:output_type -> material.EstimateProcessingResponse
+	47, // 98: material.MaterialService.ListDerivedArtifacts:output_type -> material.ListDerivedArtifactsResponse
+	49, // 99: material.MaterialService.RegenerateDerived:output_type -> material.RegenerateDerivedResponse
+	51, // 100: material.MaterialService.UpdateDerivedArtifact:output_type -> material.UpdateDerivedArtifactResponse
+	55, // 101: material.MaterialService.CreateAnnotation:output_type -> material.AnnotationResponse
+	55, // 102: material.MaterialService.UpdateAnnotation:output_type -> material.AnnotationResponse
+	57, // 103: material.MaterialService.DeleteAnnotation:output_type -> material.DeleteAnnotationResponse
+	59, // 104: material.MaterialService.ListAnnotations:output_type -> material.ListAnnotationsResponse
+	65, // 105: material.MaterialService.CreateUploadSession:output_type -> material.UploadSessionResponse
+	65, // 106: material.MaterialService.GetUploadSession:output_type -> material.UploadSessionResponse
+	65, // 107: material.MaterialService.AppendUploadSession:output_type -> material.UploadSessionResponse
+	67, // 108: material.MaterialService.DeleteUploadSession:output_type -> material.DeleteUploadSessionResponse
+	82, // [82:109] is the sub-list for method output_type
+	55, // [55:82] is the sub-list for method input_type
+	55, // [55:55] is the sub-list for extension type_name
+	55, // [55:55] is the sub-list for extension extendee
+	0,  // [0:55] is the sub-list for field type_name
 }
 
 func init() { file_proto_material_material_proto_init() }
@@ -1420,17 +5612,21 @@ func file_proto_material_material_proto_init() {
 	if File_proto_material_material_proto != nil {
 		return
 	}
-	file_proto_material_material_proto_msgTypes[1].OneofWrappers = []any{
+	file_proto_material_material_proto_msgTypes[4].OneofWrappers = []any{
 		(*UploadMaterialRequest_Metadata)(nil),
 		(*UploadMaterialRequest_ChunkData)(nil),
 	}
+	file_proto_material_material_proto_msgTypes[61].OneofWrappers = []any{
+		(*AppendUploadSessionRequest_Target)(nil),
+		(*AppendUploadSessionRequest_ChunkData)(nil),
+	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_material_material_proto_rawDesc), len(file_proto_material_material_proto_rawDesc)),
 			NumEnums:      2,
-			NumMessages:   19,
+			NumMessages:   69,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
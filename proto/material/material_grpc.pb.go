@@ -19,13 +19,33 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	MaterialService_UploadMaterial_FullMethodName         = "/material.MaterialService/UploadMaterial"
-	MaterialService_DeleteMaterial_FullMethodName         = "/material.MaterialService/DeleteMaterial"
-	MaterialService_ListMaterials_FullMethodName          = "/material.MaterialService/ListMaterials"
-	MaterialService_ProcessMaterial_FullMethodName        = "/material.MaterialService/ProcessMaterial"
-	MaterialService_GetProcessingResult_FullMethodName    = "/material.MaterialService/GetProcessingResult"
-	MaterialService_ListProcessingResults_FullMethodName  = "/material.MaterialService/ListProcessingResults"
-	MaterialService_UpdateProcessingResult_FullMethodName = "/material.MaterialService/UpdateProcessingResult"
+	MaterialService_UploadMaterial_FullMethodName           = "/material.MaterialService/UploadMaterial"
+	MaterialService_DeleteMaterial_FullMethodName           = "/material.MaterialService/DeleteMaterial"
+	MaterialService_CreateClip_FullMethodName               = "/material.MaterialService/CreateClip"
+	MaterialService_ListMaterials_FullMethodName            = "/material.MaterialService/ListMaterials"
+	MaterialService_GetMaterial_FullMethodName              = "/material.MaterialService/GetMaterial"
+	MaterialService_GetMaterialURL_FullMethodName           = "/material.MaterialService/GetMaterialURL"
+	MaterialService_ListChildMaterials_FullMethodName       = "/material.MaterialService/ListChildMaterials"
+	MaterialService_ListDuplicateMaterials_FullMethodName   = "/material.MaterialService/ListDuplicateMaterials"
+	MaterialService_ProcessMaterial_FullMethodName          = "/material.MaterialService/ProcessMaterial"
+	MaterialService_GetProcessingResult_FullMethodName      = "/material.MaterialService/GetProcessingResult"
+	MaterialService_ListProcessingResults_FullMethodName    = "/material.MaterialService/ListProcessingResults"
+	MaterialService_UpdateProcessingResult_FullMethodName   = "/material.MaterialService/UpdateProcessingResult"
+	MaterialService_RetryProcessingTask_FullMethodName      = "/material.MaterialService/RetryProcessingTask"
+	MaterialService_UpdateProcessingProgress_FullMethodName = "/material.MaterialService/UpdateProcessingProgress"
+	MaterialService_CompareProcessingResults_FullMethodName = "/material.MaterialService/CompareProcessingResults"
+	MaterialService_EstimateProcessing_FullMethodName       = "/material.MaterialService/EstimateProcessing"
+	MaterialService_ListDerivedArtifacts_FullMethodName     = "/material.MaterialService/ListDerivedArtifacts"
+	MaterialService_RegenerateDerived_FullMethodName        = "/material.MaterialService/RegenerateDerived"
+	MaterialService_UpdateDerivedArtifact_FullMethodName    = "/material.MaterialService/UpdateDerivedArtifact"
+	MaterialService_CreateAnnotation_FullMethodName         = "/material.MaterialService/CreateAnnotation"
+	MaterialService_UpdateAnnotation_FullMethodName         = "/material.MaterialService/UpdateAnnotation"
+	MaterialService_DeleteAnnotation_FullMethodName         = "/material.MaterialService/DeleteAnnotation"
+	MaterialService_ListAnnotations_FullMethodName          = "/material.MaterialService/ListAnnotations"
+	MaterialService_CreateUploadSession_FullMethodName      = "/material.MaterialService/CreateUploadSession"
+	MaterialService_GetUploadSession_FullMethodName         = "/material.MaterialService/GetUploadSession"
+	MaterialService_AppendUploadSession_FullMethodName      = "/material.MaterialService/AppendUploadSession"
+	MaterialService_DeleteUploadSession_FullMethodName      = "/material.MaterialService/DeleteUploadSession"
 )
 
 // MaterialServiceClient is the client API for MaterialService service.
@@ -34,12 +54,41 @@ const (
 type MaterialServiceClient interface {
 	UploadMaterial(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[UploadMaterialRequest, UploadMaterialResponse], error)
 	DeleteMaterial(ctx context.Context, in *DeleteMaterialRequest, opts ...grpc.CallOption) (*DeleteMaterialResponse, error)
+	// 保存浏览器扩展提交的网页摘录，创建 clip 类型资料
+	CreateClip(ctx context.Context, in *CreateClipRequest, opts ...grpc.CallOption) (*CreateClipResponse, error)
 	ListMaterials(ctx context.Context, in *ListMaterialsRequest, opts ...grpc.CallOption) (*ListMaterialsResponse, error)
+	GetMaterial(ctx context.Context, in *GetMaterialRequest, opts ...grpc.CallOption) (*GetMaterialResponse, error)
+	GetMaterialURL(ctx context.Context, in *GetMaterialURLRequest, opts ...grpc.CallOption) (*GetMaterialURLResponse, error)
+	// 列出压缩包（bundle）资料展开出的子资料
+	ListChildMaterials(ctx context.Context, in *ListChildMaterialsRequest, opts ...grpc.CallOption) (*ListChildMaterialsResponse, error)
+	// 列出与资料内容相同（内容哈希）或相近（SimHash）的本人资料
+	ListDuplicateMaterials(ctx context.Context, in *ListDuplicateMaterialsRequest, opts ...grpc.CallOption) (*ListDuplicateMaterialsResponse, error)
 	// AI 处理相关服务
 	ProcessMaterial(ctx context.Context, in *ProcessMaterialRequest, opts ...grpc.CallOption) (*ProcessMaterialResponse, error)
 	GetProcessingResult(ctx context.Context, in *GetProcessingResultRequest, opts ...grpc.CallOption) (*GetProcessingResultResponse, error)
 	ListProcessingResults(ctx context.Context, in *ListProcessingResultsRequest, opts ...grpc.CallOption) (*ListProcessingResultsResponse, error)
 	UpdateProcessingResult(ctx context.Context, in *UpdateProcessingResultRequest, opts ...grpc.CallOption) (*UpdateProcessingResultResponse, error)
+	RetryProcessingTask(ctx context.Context, in *RetryProcessingTaskRequest, opts ...grpc.CallOption) (*RetryProcessingTaskResponse, error)
+	UpdateProcessingProgress(ctx context.Context, in *UpdateProcessingProgressRequest, opts ...grpc.CallOption) (*UpdateProcessingProgressResponse, error)
+	CompareProcessingResults(ctx context.Context, in *CompareProcessingResultsRequest, opts ...grpc.CallOption) (*CompareProcessingResultsResponse, error)
+	EstimateProcessing(ctx context.Context, in *EstimateProcessingRequest, opts ...grpc.CallOption) (*EstimateProcessingResponse, error)
+	// 派生内容（向量分片、摘要、题目）新鲜度与重建
+	ListDerivedArtifacts(ctx context.Context, in *ListDerivedArtifactsRequest, opts ...grpc.CallOption) (*ListDerivedArtifactsResponse, error)
+	RegenerateDerived(ctx context.Context, in *RegenerateDerivedRequest, opts ...grpc.CallOption) (*RegenerateDerivedResponse, error)
+	UpdateDerivedArtifact(ctx context.Context, in *UpdateDerivedArtifactRequest, opts ...grpc.CallOption) (*UpdateDerivedArtifactResponse, error)
+	// 批注：按页码或时间锚定的评论与高亮
+	CreateAnnotation(ctx context.Context, in *CreateAnnotationRequest, opts ...grpc.CallOption) (*AnnotationResponse, error)
+	UpdateAnnotation(ctx context.Context, in *UpdateAnnotationRequest, opts ...grpc.CallOption) (*AnnotationResponse, error)
+	DeleteAnnotation(ctx context.Context, in *DeleteAnnotationRequest, opts ...grpc.CallOption) (*DeleteAnnotationResponse, error)
+	ListAnnotations(ctx context.Context, in *ListAnnotationsRequest, opts ...grpc.CallOption) (*ListAnnotationsResponse, error)
+	// 可续传的上传会话：客户端按偏移量分多次追加文件内容，写满 size 后创建资料（gateway 的 tus 接口基于此实现）。
+	// 失败时返回 gRPC 状态码：会话不存在或不属于该用户为 NOT_FOUND，已过期为 FAILED_PRECONDITION，
+	// 偏移量与已接收的字节数不符为 ABORTED，参数错误或内容超出 size 为 INVALID_ARGUMENT
+	CreateUploadSession(ctx context.Context, in *CreateUploadSessionRequest, opts ...grpc.CallOption) (*UploadSessionResponse, error)
+	GetUploadSession(ctx context.Context, in *GetUploadSessionRequest, opts ...grpc.CallOption) (*UploadSessionResponse, error)
+	// 第一条消息为会话与偏移量，之后为文件分块。客户端结束发送时保存已收到的内容，中途断开的请求因此不必重传已送达的部分
+	AppendUploadSession(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[AppendUploadSessionRequest, UploadSessionResponse], error)
+	DeleteUploadSession(ctx context.Context, in *DeleteUploadSessionRequest, opts ...grpc.CallOption) (*DeleteUploadSessionResponse, error)
 }
 
 type materialServiceClient struct {
@@ -73,6 +122,16 @@ func (c *materialServiceClient) DeleteMaterial(ctx context.Context, in *DeleteMa
 	return out, nil
 }
 
+func (c *materialServiceClient) CreateClip(ctx context.Context, in *CreateClipRequest, opts ...grpc.CallOption) (*CreateClipResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateClipResponse)
+	err := c.cc.Invoke(ctx, MaterialService_CreateClip_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *materialServiceClient) ListMaterials(ctx context.Context, in *ListMaterialsRequest, opts ...grpc.CallOption) (*ListMaterialsResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(ListMaterialsResponse)
@@ -83,6 +142,46 @@ func (c *materialServiceClient) ListMaterials(ctx context.Context, in *ListMater
 	return out, nil
 }
 
+func (c *materialServiceClient) GetMaterial(ctx context.Context, in *GetMaterialRequest, opts ...grpc.CallOption) (*GetMaterialResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetMaterialResponse)
+	err := c.cc.Invoke(ctx, MaterialService_GetMaterial_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *materialServiceClient) GetMaterialURL(ctx context.Context, in *GetMaterialURLRequest, opts ...grpc.CallOption) (*GetMaterialURLResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetMaterialURLResponse)
+	err := c.cc.Invoke(ctx, MaterialService_GetMaterialURL_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *materialServiceClient) ListChildMaterials(ctx context.Context, in *ListChildMaterialsRequest, opts ...grpc.CallOption) (*ListChildMaterialsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListChildMaterialsResponse)
+	err := c.cc.Invoke(ctx, MaterialService_ListChildMaterials_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *materialServiceClient) ListDuplicateMaterials(ctx context.Context, in *ListDuplicateMaterialsRequest, opts ...grpc.CallOption) (*ListDuplicateMaterialsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListDuplicateMaterialsResponse)
+	err := c.cc.Invoke(ctx, MaterialService_ListDuplicateMaterials_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *materialServiceClient) ProcessMaterial(ctx context.Context, in *ProcessMaterialRequest, opts ...grpc.CallOption) (*ProcessMaterialResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(ProcessMaterialResponse)
@@ -123,18 +222,200 @@ func (c *materialServiceClient) UpdateProcessingResult(ctx context.Context, in *
 	return out, nil
 }
 
+func (c *materialServiceClient) RetryProcessingTask(ctx context.Context, in *RetryProcessingTaskRequest, opts ...grpc.CallOption) (*RetryProcessingTaskResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RetryProcessingTaskResponse)
+	err := c.cc.Invoke(ctx, MaterialService_RetryProcessingTask_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *materialServiceClient) UpdateProcessingProgress(ctx context.Context, in *UpdateProcessingProgressRequest, opts ...grpc.CallOption) (*UpdateProcessingProgressResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpdateProcessingProgressResponse)
+	err := c.cc.Invoke(ctx, MaterialService_UpdateProcessingProgress_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *materialServiceClient) CompareProcessingResults(ctx context.Context, in *CompareProcessingResultsRequest, opts ...grpc.CallOption) (*CompareProcessingResultsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CompareProcessingResultsResponse)
+	err := c.cc.Invoke(ctx, MaterialService_CompareProcessingResults_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *materialServiceClient) EstimateProcessing(ctx context.Context, in *EstimateProcessingRequest, opts ...grpc.CallOption) (*EstimateProcessingResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(EstimateProcessingResponse)
+	err := c.cc.Invoke(ctx, MaterialService_EstimateProcessing_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *materialServiceClient) ListDerivedArtifacts(ctx context.Context, in *ListDerivedArtifactsRequest, opts ...grpc.CallOption) (*ListDerivedArtifactsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListDerivedArtifactsResponse)
+	err := c.cc.Invoke(ctx, MaterialService_ListDerivedArtifacts_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *materialServiceClient) RegenerateDerived(ctx context.Context, in *RegenerateDerivedRequest, opts ...grpc.CallOption) (*RegenerateDerivedResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RegenerateDerivedResponse)
+	err := c.cc.Invoke(ctx, MaterialService_RegenerateDerived_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *materialServiceClient) UpdateDerivedArtifact(ctx context.Context, in *UpdateDerivedArtifactRequest, opts ...grpc.CallOption) (*UpdateDerivedArtifactResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpdateDerivedArtifactResponse)
+	err := c.cc.Invoke(ctx, MaterialService_UpdateDerivedArtifact_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *materialServiceClient) CreateAnnotation(ctx context.Context, in *CreateAnnotationRequest, opts ...grpc.CallOption) (*AnnotationResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AnnotationResponse)
+	err := c.cc.Invoke(ctx, MaterialService_CreateAnnotation_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *materialServiceClient) UpdateAnnotation(ctx context.Context, in *UpdateAnnotationRequest, opts ...grpc.CallOption) (*AnnotationResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AnnotationResponse)
+	err := c.cc.Invoke(ctx, MaterialService_UpdateAnnotation_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *materialServiceClient) DeleteAnnotation(ctx context.Context, in *DeleteAnnotationRequest, opts ...grpc.CallOption) (*DeleteAnnotationResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteAnnotationResponse)
+	err := c.cc.Invoke(ctx, MaterialService_DeleteAnnotation_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *materialServiceClient) ListAnnotations(ctx context.Context, in *ListAnnotationsRequest, opts ...grpc.CallOption) (*ListAnnotationsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListAnnotationsResponse)
+	err := c.cc.Invoke(ctx, MaterialService_ListAnnotations_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *materialServiceClient) CreateUploadSession(ctx context.Context, in *CreateUploadSessionRequest, opts ...grpc.CallOption) (*UploadSessionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UploadSessionResponse)
+	err := c.cc.Invoke(ctx, MaterialService_CreateUploadSession_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *materialServiceClient) GetUploadSession(ctx context.Context, in *GetUploadSessionRequest, opts ...grpc.CallOption) (*UploadSessionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UploadSessionResponse)
+	err := c.cc.Invoke(ctx, MaterialService_GetUploadSession_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *materialServiceClient) AppendUploadSession(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[AppendUploadSessionRequest, UploadSessionResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &MaterialService_ServiceDesc.Streams[1], MaterialService_AppendUploadSession_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[AppendUploadSessionRequest, UploadSessionResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type MaterialService_AppendUploadSessionClient = grpc.ClientStreamingClient[AppendUploadSessionRequest, UploadSessionResponse]
+
+func (c *materialServiceClient) DeleteUploadSession(ctx context.Context, in *DeleteUploadSessionRequest, opts ...grpc.CallOption) (*DeleteUploadSessionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteUploadSessionResponse)
+	err := c.cc.Invoke(ctx, MaterialService_DeleteUploadSession_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // MaterialServiceServer is the server API for MaterialService service.
 // All implementations must embed UnimplementedMaterialServiceServer
 // for forward compatibility.
 type MaterialServiceServer interface {
 	UploadMaterial(grpc.ClientStreamingServer[UploadMaterialRequest, UploadMaterialResponse]) error
 	DeleteMaterial(context.Context, *DeleteMaterialRequest) (*DeleteMaterialResponse, error)
+	// 保存浏览器扩展提交的网页摘录，创建 clip 类型资料
+	CreateClip(context.Context, *CreateClipRequest) (*CreateClipResponse, error)
 	ListMaterials(context.Context, *ListMaterialsRequest) (*ListMaterialsResponse, error)
+	GetMaterial(context.Context, *GetMaterialRequest) (*GetMaterialResponse, error)
+	GetMaterialURL(context.Context, *GetMaterialURLRequest) (*GetMaterialURLResponse, error)
+	// 列出压缩包（bundle）资料展开出的子资料
+	ListChildMaterials(context.Context, *ListChildMaterialsRequest) (*ListChildMaterialsResponse, error)
+	// 列出与资料内容相同（内容哈希）或相近（SimHash）的本人资料
+	ListDuplicateMaterials(context.Context, *ListDuplicateMaterialsRequest) (*ListDuplicateMaterialsResponse, error)
 	// AI 处理相关服务
 	ProcessMaterial(context.Context, *ProcessMaterialRequest) (*ProcessMaterialResponse, error)
 	GetProcessingResult(context.Context, *GetProcessingResultRequest) (*GetProcessingResultResponse, error)
 	ListProcessingResults(context.Context, *ListProcessingResultsRequest) (*ListProcessingResultsResponse, error)
 	UpdateProcessingResult(context.Context, *UpdateProcessingResultRequest) (*UpdateProcessingResultResponse, error)
+	RetryProcessingTask(context.Context, *RetryProcessingTaskRequest) (*RetryProcessingTaskResponse, error)
+	UpdateProcessingProgress(context.Context, *UpdateProcessingProgressRequest) (*UpdateProcessingProgressResponse, error)
+	CompareProcessingResults(context.Context, *CompareProcessingResultsRequest) (*CompareProcessingResultsResponse, error)
+	EstimateProcessing(context.Context, *EstimateProcessingRequest) (*EstimateProcessingResponse, error)
+	// 派生内容（向量分片、摘要、题目）新鲜度与重建
+	ListDerivedArtifacts(context.Context, *ListDerivedArtifactsRequest) (*ListDerivedArtifactsResponse, error)
+	RegenerateDerived(context.Context, *RegenerateDerivedRequest) (*RegenerateDerivedResponse, error)
+	UpdateDerivedArtifact(context.Context, *UpdateDerivedArtifactRequest) (*UpdateDerivedArtifactResponse, error)
+	// 批注：按页码或时间锚定的评论与高亮
+	CreateAnnotation(context.Context, *CreateAnnotationRequest) (*AnnotationResponse, error)
+	UpdateAnnotation(context.Context, *UpdateAnnotationRequest) (*AnnotationResponse, error)
+	DeleteAnnotation(context.Context, *DeleteAnnotationRequest) (*DeleteAnnotationResponse, error)
+	ListAnnotations(context.Context, *ListAnnotationsRequest) (*ListAnnotationsResponse, error)
+	// 可续传的上传会话：客户端按偏移量分多次追加文件内容，写满 size 后创建资料（gateway 的 tus 接口基于此实现）。
+	// 失败时返回 gRPC 状态码：会话不存在或不属于该用户为 NOT_FOUND，已过期为 FAILED_PRECONDITION，
+	// 偏移量与已接收的字节数不符为 ABORTED，参数错误或内容超出 size 为 INVALID_ARGUMENT
+	CreateUploadSession(context.Context, *CreateUploadSessionRequest) (*UploadSessionResponse, error)
+	GetUploadSession(context.Context, *GetUploadSessionRequest) (*UploadSessionResponse, error)
+	// 第一条消息为会话与偏移量，之后为文件分块。客户端结束发送时保存已收到的内容，中途断开的请求因此不必重传已送达的部分
+	AppendUploadSession(grpc.ClientStreamingServer[AppendUploadSessionRequest, UploadSessionResponse]) error
+	DeleteUploadSession(context.Context, *DeleteUploadSessionRequest) (*DeleteUploadSessionResponse, error)
 	mustEmbedUnimplementedMaterialServiceServer()
 }
 
@@ -151,9 +432,24 @@ func (UnimplementedMaterialServiceServer) UploadMaterial(grpc.ClientStreamingSer
 func (UnimplementedMaterialServiceServer) DeleteMaterial(context.Context, *DeleteMaterialRequest) (*DeleteMaterialResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DeleteMaterial not implemented")
 }
+func (UnimplementedMaterialServiceServer) CreateClip(context.Context, *CreateClipRequest) (*CreateClipResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateClip not implemented")
+}
 func (UnimplementedMaterialServiceServer) ListMaterials(context.Context, *ListMaterialsRequest) (*ListMaterialsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListMaterials not implemented")
 }
+func (UnimplementedMaterialServiceServer) GetMaterial(context.Context, *GetMaterialRequest) (*GetMaterialResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMaterial not implemented")
+}
+func (UnimplementedMaterialServiceServer) GetMaterialURL(context.Context, *GetMaterialURLRequest) (*GetMaterialURLResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMaterialURL not implemented")
+}
+func (UnimplementedMaterialServiceServer) ListChildMaterials(context.Context, *ListChildMaterialsRequest) (*ListChildMaterialsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListChildMaterials not implemented")
+}
+func (UnimplementedMaterialServiceServer) ListDuplicateMaterials(context.Context, *ListDuplicateMaterialsRequest) (*ListDuplicateMaterialsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListDuplicateMaterials not implemented")
+}
 func (UnimplementedMaterialServiceServer) ProcessMaterial(context.Context, *ProcessMaterialRequest) (*ProcessMaterialResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ProcessMaterial not implemented")
 }
@@ -166,6 +462,51 @@ func (UnimplementedMaterialServiceServer) ListProcessingResults(context.Context,
 func (UnimplementedMaterialServiceServer) UpdateProcessingResult(context.Context, *UpdateProcessingResultRequest) (*UpdateProcessingResultResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method UpdateProcessingResult not implemented")
 }
+func (UnimplementedMaterialServiceServer) RetryProcessingTask(context.Context, *RetryProcessingTaskRequest) (*RetryProcessingTaskResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RetryProcessingTask not implemented")
+}
+func (UnimplementedMaterialServiceServer) UpdateProcessingProgress(context.Context, *UpdateProcessingProgressRequest) (*UpdateProcessingProgressResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateProcessingProgress not implemented")
+}
+func (UnimplementedMaterialServiceServer) CompareProcessingResults(context.Context, *CompareProcessingResultsRequest) (*CompareProcessingResultsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CompareProcessingResults not implemented")
+}
+func (UnimplementedMaterialServiceServer) EstimateProcessing(context.Context, *EstimateProcessingRequest) (*EstimateProcessingResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EstimateProcessing not implemented")
+}
+func (UnimplementedMaterialServiceServer) ListDerivedArtifacts(context.Context, *ListDerivedArtifactsRequest) (*ListDerivedArtifactsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListDerivedArtifacts not implemented")
+}
+func (UnimplementedMaterialServiceServer) RegenerateDerived(context.Context, *RegenerateDerivedRequest) (*RegenerateDerivedResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RegenerateDerived not implemented")
+}
+func (UnimplementedMaterialServiceServer) UpdateDerivedArtifact(context.Context, *UpdateDerivedArtifactRequest) (*UpdateDerivedArtifactResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateDerivedArtifact not implemented")
+}
+func (UnimplementedMaterialServiceServer) CreateAnnotation(context.Context, *CreateAnnotationRequest) (*AnnotationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateAnnotation not implemented")
+}
+func (UnimplementedMaterialServiceServer) UpdateAnnotation(context.Context, *UpdateAnnotationRequest) (*AnnotationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateAnnotation not implemented")
+}
+func (UnimplementedMaterialServiceServer) DeleteAnnotation(context.Context, *DeleteAnnotationRequest) (*DeleteAnnotationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteAnnotation not implemented")
+}
+func (UnimplementedMaterialServiceServer) ListAnnotations(context.Context, *ListAnnotationsRequest) (*ListAnnotationsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAnnotations not implemented")
+}
+func (UnimplementedMaterialServiceServer) CreateUploadSession(context.Context, *CreateUploadSessionRequest) (*UploadSessionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateUploadSession not implemented")
+}
+func (UnimplementedMaterialServiceServer) GetUploadSession(context.Context, *GetUploadSessionRequest) (*UploadSessionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUploadSession not implemented")
+}
+func (UnimplementedMaterialServiceServer) AppendUploadSession(grpc.ClientStreamingServer[AppendUploadSessionRequest, UploadSessionResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method AppendUploadSession not implemented")
+}
+func (UnimplementedMaterialServiceServer) DeleteUploadSession(context.Context, *DeleteUploadSessionRequest) (*DeleteUploadSessionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteUploadSession not implemented")
+}
 func (UnimplementedMaterialServiceServer) mustEmbedUnimplementedMaterialServiceServer() {}
 func (UnimplementedMaterialServiceServer) testEmbeddedByValue()                         {}
 
@@ -212,6 +553,24 @@ func _MaterialService_DeleteMaterial_Handler(srv interface{}, ctx context.Contex
 	return interceptor(ctx, in, info, handler)
 }
 
+func _MaterialService_CreateClip_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateClipRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MaterialServiceServer).CreateClip(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MaterialService_CreateClip_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MaterialServiceServer).CreateClip(ctx, req.(*CreateClipRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _MaterialService_ListMaterials_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ListMaterialsRequest)
 	if err := dec(in); err != nil {
@@ -230,6 +589,78 @@ func _MaterialService_ListMaterials_Handler(srv interface{}, ctx context.Context
 	return interceptor(ctx, in, info, handler)
 }
 
+func _MaterialService_GetMaterial_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetMaterialRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MaterialServiceServer).GetMaterial(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MaterialService_GetMaterial_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MaterialServiceServer).GetMaterial(ctx, req.(*GetMaterialRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MaterialService_GetMaterialURL_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetMaterialURLRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MaterialServiceServer).GetMaterialURL(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MaterialService_GetMaterialURL_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MaterialServiceServer).GetMaterialURL(ctx, req.(*GetMaterialURLRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MaterialService_ListChildMaterials_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListChildMaterialsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MaterialServiceServer).ListChildMaterials(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MaterialService_ListChildMaterials_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MaterialServiceServer).ListChildMaterials(ctx, req.(*ListChildMaterialsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MaterialService_ListDuplicateMaterials_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListDuplicateMaterialsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MaterialServiceServer).ListDuplicateMaterials(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MaterialService_ListDuplicateMaterials_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MaterialServiceServer).ListDuplicateMaterials(ctx, req.(*ListDuplicateMaterialsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _MaterialService_ProcessMaterial_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ProcessMaterialRequest)
 	if err := dec(in); err != nil {
@@ -302,6 +733,265 @@ func _MaterialService_UpdateProcessingResult_Handler(srv interface{}, ctx contex
 	return interceptor(ctx, in, info, handler)
 }
 
+func _MaterialService_RetryProcessingTask_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RetryProcessingTaskRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MaterialServiceServer).RetryProcessingTask(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MaterialService_RetryProcessingTask_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MaterialServiceServer).RetryProcessingTask(ctx, req.(*RetryProcessingTaskRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MaterialService_UpdateProcessingProgress_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateProcessingProgressRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MaterialServiceServer).UpdateProcessingProgress(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MaterialService_UpdateProcessingProgress_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MaterialServiceServer).UpdateProcessingProgress(ctx, req.(*UpdateProcessingProgressRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MaterialService_CompareProcessingResults_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CompareProcessingResultsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MaterialServiceServer).CompareProcessingResults(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MaterialService_CompareProcessingResults_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MaterialServiceServer).CompareProcessingResults(ctx, req.(*CompareProcessingResultsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MaterialService_EstimateProcessing_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EstimateProcessingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MaterialServiceServer).EstimateProcessing(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MaterialService_EstimateProcessing_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MaterialServiceServer).EstimateProcessing(ctx, req.(*EstimateProcessingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MaterialService_ListDerivedArtifacts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListDerivedArtifactsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MaterialServiceServer).ListDerivedArtifacts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MaterialService_ListDerivedArtifacts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MaterialServiceServer).ListDerivedArtifacts(ctx, req.(*ListDerivedArtifactsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MaterialService_RegenerateDerived_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegenerateDerivedRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MaterialServiceServer).RegenerateDerived(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MaterialService_RegenerateDerived_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MaterialServiceServer).RegenerateDerived(ctx, req.(*RegenerateDerivedRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MaterialService_UpdateDerivedArtifact_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateDerivedArtifactRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MaterialServiceServer).UpdateDerivedArtifact(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MaterialService_UpdateDerivedArtifact_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MaterialServiceServer).UpdateDerivedArtifact(ctx, req.(*UpdateDerivedArtifactRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MaterialService_CreateAnnotation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateAnnotationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MaterialServiceServer).CreateAnnotation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MaterialService_CreateAnnotation_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MaterialServiceServer).CreateAnnotation(ctx, req.(*CreateAnnotationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MaterialService_UpdateAnnotation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateAnnotationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MaterialServiceServer).UpdateAnnotation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MaterialService_UpdateAnnotation_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MaterialServiceServer).UpdateAnnotation(ctx, req.(*UpdateAnnotationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MaterialService_DeleteAnnotation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteAnnotationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MaterialServiceServer).DeleteAnnotation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MaterialService_DeleteAnnotation_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MaterialServiceServer).DeleteAnnotation(ctx, req.(*DeleteAnnotationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MaterialService_ListAnnotations_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAnnotationsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MaterialServiceServer).ListAnnotations(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MaterialService_ListAnnotations_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MaterialServiceServer).ListAnnotations(ctx, req.(*ListAnnotationsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MaterialService_CreateUploadSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateUploadSessionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MaterialServiceServer).CreateUploadSession(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MaterialService_CreateUploadSession_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MaterialServiceServer).CreateUploadSession(ctx, req.(*CreateUploadSessionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MaterialService_GetUploadSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetUploadSessionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MaterialServiceServer).GetUploadSession(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MaterialService_GetUploadSession_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MaterialServiceServer).GetUploadSession(ctx, req.(*GetUploadSessionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MaterialService_AppendUploadSession_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(MaterialServiceServer).AppendUploadSession(&grpc.GenericServerStream[AppendUploadSessionRequest, UploadSessionResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type MaterialService_AppendUploadSessionServer = grpc.ClientStreamingServer[AppendUploadSessionRequest, UploadSessionResponse]
+
+func _MaterialService_DeleteUploadSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteUploadSessionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MaterialServiceServer).DeleteUploadSession(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MaterialService_DeleteUploadSession_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MaterialServiceServer).DeleteUploadSession(ctx, req.(*DeleteUploadSessionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // MaterialService_ServiceDesc is the grpc.ServiceDesc for MaterialService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -313,10 +1003,30 @@ var MaterialService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "DeleteMaterial",
 			Handler:    _MaterialService_DeleteMaterial_Handler,
 		},
+		{
+			MethodName: "CreateClip",
+			Handler:    _MaterialService_CreateClip_Handler,
+		},
 		{
 			MethodName: "ListMaterials",
 			Handler:    _MaterialService_ListMaterials_Handler,
 		},
+		{
+			MethodName: "GetMaterial",
+			Handler:    _MaterialService_GetMaterial_Handler,
+		},
+		{
+			MethodName: "GetMaterialURL",
+			Handler:    _MaterialService_GetMaterialURL_Handler,
+		},
+		{
+			MethodName: "ListChildMaterials",
+			Handler:    _MaterialService_ListChildMaterials_Handler,
+		},
+		{
+			MethodName: "ListDuplicateMaterials",
+			Handler:    _MaterialService_ListDuplicateMaterials_Handler,
+		},
 		{
 			MethodName: "ProcessMaterial",
 			Handler:    _MaterialService_ProcessMaterial_Handler,
@@ -333,6 +1043,62 @@ var MaterialService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "UpdateProcessingResult",
 			Handler:    _MaterialService_UpdateProcessingResult_Handler,
 		},
+		{
+			MethodName: "RetryProcessingTask",
+			Handler:    _MaterialService_RetryProcessingTask_Handler,
+		},
+		{
+			MethodName: "UpdateProcessingProgress",
+			Handler:    _MaterialService_UpdateProcessingProgress_Handler,
+		},
+		{
+			MethodName: "CompareProcessingResults",
+			Handler:    _MaterialService_CompareProcessingResults_Handler,
+		},
+		{
+			MethodName: "EstimateProcessing",
+			Handler:    _MaterialService_EstimateProcessing_Handler,
+		},
+		{
+			MethodName: "ListDerivedArtifacts",
+			Handler:    _MaterialService_ListDerivedArtifacts_Handler,
+		},
+		{
+			MethodName: "RegenerateDerived",
+			Handler:    _MaterialService_RegenerateDerived_Handler,
+		},
+		{
+			MethodName: "UpdateDerivedArtifact",
+			Handler:    _MaterialService_UpdateDerivedArtifact_Handler,
+		},
+		{
+			MethodName: "CreateAnnotation",
+			Handler:    _MaterialService_CreateAnnotation_Handler,
+		},
+		{
+			MethodName: "UpdateAnnotation",
+			Handler:    _MaterialService_UpdateAnnotation_Handler,
+		},
+		{
+			MethodName: "DeleteAnnotation",
+			Handler:    _MaterialService_DeleteAnnotation_Handler,
+		},
+		{
+			MethodName: "ListAnnotations",
+			Handler:    _MaterialService_ListAnnotations_Handler,
+		},
+		{
+			MethodName: "CreateUploadSession",
+			Handler:    _MaterialService_CreateUploadSession_Handler,
+		},
+		{
+			MethodName: "GetUploadSession",
+			Handler:    _MaterialService_GetUploadSession_Handler,
+		},
+		{
+			MethodName: "DeleteUploadSession",
+			Handler:    _MaterialService_DeleteUploadSession_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -340,6 +1106,11 @@ var MaterialService_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _MaterialService_UploadMaterial_Handler,
 			ClientStreams: true,
 		},
+		{
+			StreamName:    "AppendUploadSession",
+			Handler:       _MaterialService_AppendUploadSession_Handler,
+			ClientStreams: true,
+		},
 	},
 	Metadata: "proto/material/material.proto",
 }
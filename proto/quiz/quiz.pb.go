@@ -9,6 +9,7 @@ package quiz
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -136,8 +137,28 @@ type GenerateQuizRequest struct {
 	Difficulty      DifficultyLevel        `protobuf:"varint,4,opt,name=difficulty,proto3,enum=quiz.DifficultyLevel" json:"difficulty,omitempty"`       // 难度级别
 	Count           int32                  `protobuf:"varint,5,opt,name=count,proto3" json:"count,omitempty"`                                           // 生成题目数量
 	KnowledgePoints []string               `protobuf:"bytes,6,rep,name=knowledge_points,json=knowledgePoints,proto3" json:"knowledge_points,omitempty"` // 指定知识点
-	unknownFields   protoimpl.UnknownFields
-	sizeCache       protoimpl.SizeCache
+	// 基于音视频转写出题：为 true 或指定了时间范围时使用 asr-service 的转写分段而非材料分片，
+	// 题目引用带转写时间码；start_time / end_time 为秒，end_time 为 0 表示到结尾
+	FromTranscript bool    `protobuf:"varint,7,opt,name=from_transcript,json=fromTranscript,proto3" json:"from_transcript,omitempty"`
+	StartTime      float32 `protobuf:"fixed32,8,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	EndTime        float32 `protobuf:"fixed32,9,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`
+	// 基于选中段落出题：selection_text 非空时只使用该段文本，跳过材料检索与转写；
+	// selection_page 为选段所在页码（可选），用于题目引用
+	SelectionText string `protobuf:"bytes,10,opt,name=selection_text,json=selectionText,proto3" json:"selection_text,omitempty"`
+	SelectionPage int32  `protobuf:"varint,11,opt,name=selection_page,json=selectionPage,proto3" json:"selection_page,omitempty"`
+	// 题目语言（ISO 639-1，如 zh、en），为空时使用材料检测到的语言
+	Language string `protobuf:"bytes,12,opt,name=language,proto3" json:"language,omitempty"`
+	// 在课程中出题：题目以草稿保存，经审核通过后才对学生可见；指定 reviewer_id 时直接提交该审核人审核
+	CourseId   string `protobuf:"bytes,13,opt,name=course_id,json=courseId,proto3" json:"course_id,omitempty"`
+	ReviewerId string `protobuf:"bytes,14,opt,name=reviewer_id,json=reviewerId,proto3" json:"reviewer_id,omitempty"`
+	// 基于多份材料出题（如单元测验）：与 material_id 合并去重，或指定课程合集（bundle 资料）使用其中全部材料；
+	// 各材料均衡抽取片段，题目的 material_id 与引用标注其出处。不支持与选段、转写时间范围同时使用
+	MaterialIds  []string `protobuf:"bytes,15,rep,name=material_ids,json=materialIds,proto3" json:"material_ids,omitempty"`
+	CollectionId string   `protobuf:"bytes,16,opt,name=collection_id,json=collectionId,proto3" json:"collection_id,omitempty"`
+	// 组卷蓝图：按槽位生成指定题型、知识点与难度的题目，指定时忽略 types、difficulty、count 与 knowledge_points
+	Blueprint     []*BlueprintSlot `protobuf:"bytes,17,rep,name=blueprint,proto3" json:"blueprint,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *GenerateQuizRequest) Reset() {
@@ -212,19 +233,321 @@ func (x *GenerateQuizRequest) GetKnowledgePoints() []string {
 	return nil
 }
 
-// 生成题目响应
-type GenerateQuizResponse struct {
+func (x *GenerateQuizRequest) GetFromTranscript() bool {
+	if x != nil {
+		return x.FromTranscript
+	}
+	return false
+}
+
+func (x *GenerateQuizRequest) GetStartTime() float32 {
+	if x != nil {
+		return x.StartTime
+	}
+	return 0
+}
+
+func (x *GenerateQuizRequest) GetEndTime() float32 {
+	if x != nil {
+		return x.EndTime
+	}
+	return 0
+}
+
+func (x *GenerateQuizRequest) GetSelectionText() string {
+	if x != nil {
+		return x.SelectionText
+	}
+	return ""
+}
+
+func (x *GenerateQuizRequest) GetSelectionPage() int32 {
+	if x != nil {
+		return x.SelectionPage
+	}
+	return 0
+}
+
+func (x *GenerateQuizRequest) GetLanguage() string {
+	if x != nil {
+		return x.Language
+	}
+	return ""
+}
+
+func (x *GenerateQuizRequest) GetCourseId() string {
+	if x != nil {
+		return x.CourseId
+	}
+	return ""
+}
+
+func (x *GenerateQuizRequest) GetReviewerId() string {
+	if x != nil {
+		return x.ReviewerId
+	}
+	return ""
+}
+
+func (x *GenerateQuizRequest) GetMaterialIds() []string {
+	if x != nil {
+		return x.MaterialIds
+	}
+	return nil
+}
+
+func (x *GenerateQuizRequest) GetCollectionId() string {
+	if x != nil {
+		return x.CollectionId
+	}
+	return ""
+}
+
+func (x *GenerateQuizRequest) GetBlueprint() []*BlueprintSlot {
+	if x != nil {
+		return x.Blueprint
+	}
+	return nil
+}
+
+// 组卷蓝图槽位，如“知识点 A 的中等难度选择题 3 道”
+type BlueprintSlot struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Type           QuestionType           `protobuf:"varint,1,opt,name=type,proto3,enum=quiz.QuestionType" json:"type,omitempty"`
+	KnowledgePoint string                 `protobuf:"bytes,2,opt,name=knowledge_point,json=knowledgePoint,proto3" json:"knowledge_point,omitempty"` // 为空时不限知识点
+	Difficulty     DifficultyLevel        `protobuf:"varint,3,opt,name=difficulty,proto3,enum=quiz.DifficultyLevel" json:"difficulty,omitempty"`
+	Count          int32                  `protobuf:"varint,4,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *BlueprintSlot) Reset() {
+	*x = BlueprintSlot{}
+	mi := &file_quiz_quiz_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BlueprintSlot) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BlueprintSlot) ProtoMessage() {}
+
+func (x *BlueprintSlot) ProtoReflect() protoreflect.Message {
+	mi := &file_quiz_quiz_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BlueprintSlot.ProtoReflect.Descriptor instead.
+func (*BlueprintSlot) Descriptor() ([]byte, []int) {
+	return file_quiz_quiz_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *BlueprintSlot) GetType() QuestionType {
+	if x != nil {
+		return x.Type
+	}
+	return QuestionType_MULTIPLE_CHOICE
+}
+
+func (x *BlueprintSlot) GetKnowledgePoint() string {
+	if x != nil {
+		return x.KnowledgePoint
+	}
+	return ""
+}
+
+func (x *BlueprintSlot) GetDifficulty() DifficultyLevel {
+	if x != nil {
+		return x.Difficulty
+	}
+	return DifficultyLevel_EASY
+}
+
+func (x *BlueprintSlot) GetCount() int32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+// 蓝图槽位的完成情况
+type BlueprintSlotReport struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	SlotIndex   int32                  `protobuf:"varint,1,opt,name=slot_index,json=slotIndex,proto3" json:"slot_index,omitempty"` // 在 blueprint 中的下标
+	Slot        *BlueprintSlot         `protobuf:"bytes,2,opt,name=slot,proto3" json:"slot,omitempty"`
+	Filled      int32                  `protobuf:"varint,3,opt,name=filled,proto3" json:"filled,omitempty"` // 通过校验并保存的题目数
+	QuestionIds []string               `protobuf:"bytes,4,rep,name=question_ids,json=questionIds,proto3" json:"question_ids,omitempty"`
+	// 未通过校验而丢弃的题目数，按原因：difficulty_mismatch / knowledge_point_mismatch / incomplete / duplicate
+	Rejected      map[string]int32 `protobuf:"bytes,5,rep,name=rejected,proto3" json:"rejected,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	Message       string           `protobuf:"bytes,6,opt,name=message,proto3" json:"message,omitempty"` // 未满足时的说明
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BlueprintSlotReport) Reset() {
+	*x = BlueprintSlotReport{}
+	mi := &file_quiz_quiz_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BlueprintSlotReport) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BlueprintSlotReport) ProtoMessage() {}
+
+func (x *BlueprintSlotReport) ProtoReflect() protoreflect.Message {
+	mi := &file_quiz_quiz_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BlueprintSlotReport.ProtoReflect.Descriptor instead.
+func (*BlueprintSlotReport) Descriptor() ([]byte, []int) {
+	return file_quiz_quiz_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *BlueprintSlotReport) GetSlotIndex() int32 {
+	if x != nil {
+		return x.SlotIndex
+	}
+	return 0
+}
+
+func (x *BlueprintSlotReport) GetSlot() *BlueprintSlot {
+	if x != nil {
+		return x.Slot
+	}
+	return nil
+}
+
+func (x *BlueprintSlotReport) GetFilled() int32 {
+	if x != nil {
+		return x.Filled
+	}
+	return 0
+}
+
+func (x *BlueprintSlotReport) GetQuestionIds() []string {
+	if x != nil {
+		return x.QuestionIds
+	}
+	return nil
+}
+
+func (x *BlueprintSlotReport) GetRejected() map[string]int32 {
+	if x != nil {
+		return x.Rejected
+	}
+	return nil
+}
+
+func (x *BlueprintSlotReport) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+// 蓝图完成报告
+type BlueprintReport struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
-	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
-	Questions     []*Question            `protobuf:"bytes,3,rep,name=questions,proto3" json:"questions,omitempty"`
+	Satisfied     bool                   `protobuf:"varint,1,opt,name=satisfied,proto3" json:"satisfied,omitempty"` // 全部槽位均已满足
+	Requested     int32                  `protobuf:"varint,2,opt,name=requested,proto3" json:"requested,omitempty"`
+	Filled        int32                  `protobuf:"varint,3,opt,name=filled,proto3" json:"filled,omitempty"`
+	Slots         []*BlueprintSlotReport `protobuf:"bytes,4,rep,name=slots,proto3" json:"slots,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
+func (x *BlueprintReport) Reset() {
+	*x = BlueprintReport{}
+	mi := &file_quiz_quiz_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BlueprintReport) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BlueprintReport) ProtoMessage() {}
+
+func (x *BlueprintReport) ProtoReflect() protoreflect.Message {
+	mi := &file_quiz_quiz_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BlueprintReport.ProtoReflect.Descriptor instead.
+func (*BlueprintReport) Descriptor() ([]byte, []int) {
+	return file_quiz_quiz_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *BlueprintReport) GetSatisfied() bool {
+	if x != nil {
+		return x.Satisfied
+	}
+	return false
+}
+
+func (x *BlueprintReport) GetRequested() int32 {
+	if x != nil {
+		return x.Requested
+	}
+	return 0
+}
+
+func (x *BlueprintReport) GetFilled() int32 {
+	if x != nil {
+		return x.Filled
+	}
+	return 0
+}
+
+func (x *BlueprintReport) GetSlots() []*BlueprintSlotReport {
+	if x != nil {
+		return x.Slots
+	}
+	return nil
+}
+
+// 生成题目响应
+type GenerateQuizResponse struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Success         bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message         string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Questions       []*Question            `protobuf:"bytes,3,rep,name=questions,proto3" json:"questions,omitempty"`
+	BlueprintReport *BlueprintReport       `protobuf:"bytes,4,opt,name=blueprint_report,json=blueprintReport,proto3" json:"blueprint_report,omitempty"` // 按蓝图出题时返回
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
 func (x *GenerateQuizResponse) Reset() {
 	*x = GenerateQuizResponse{}
-	mi := &file_quiz_quiz_proto_msgTypes[1]
+	mi := &file_quiz_quiz_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -236,7 +559,7 @@ func (x *GenerateQuizResponse) String() string {
 func (*GenerateQuizResponse) ProtoMessage() {}
 
 func (x *GenerateQuizResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_quiz_quiz_proto_msgTypes[1]
+	mi := &file_quiz_quiz_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -249,7 +572,7 @@ func (x *GenerateQuizResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GenerateQuizResponse.ProtoReflect.Descriptor instead.
 func (*GenerateQuizResponse) Descriptor() ([]byte, []int) {
-	return file_quiz_quiz_proto_rawDescGZIP(), []int{1}
+	return file_quiz_quiz_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *GenerateQuizResponse) GetSuccess() bool {
@@ -273,26 +596,46 @@ func (x *GenerateQuizResponse) GetQuestions() []*Question {
 	return nil
 }
 
+func (x *GenerateQuizResponse) GetBlueprintReport() *BlueprintReport {
+	if x != nil {
+		return x.BlueprintReport
+	}
+	return nil
+}
+
 // 题目结构
 type Question struct {
-	state           protoimpl.MessageState `protogen:"open.v1"`
-	QuestionId      string                 `protobuf:"bytes,1,opt,name=question_id,json=questionId,proto3" json:"question_id,omitempty"`
-	Type            QuestionType           `protobuf:"varint,2,opt,name=type,proto3,enum=quiz.QuestionType" json:"type,omitempty"`
-	Content         string                 `protobuf:"bytes,3,opt,name=content,proto3" json:"content,omitempty"`                                  // 题目内容
-	Options         []string               `protobuf:"bytes,4,rep,name=options,proto3" json:"options,omitempty"`                                  // 选项（选择题用）
-	CorrectAnswer   string                 `protobuf:"bytes,5,opt,name=correct_answer,json=correctAnswer,proto3" json:"correct_answer,omitempty"` // 正确答案
-	Explanation     string                 `protobuf:"bytes,6,opt,name=explanation,proto3" json:"explanation,omitempty"`                          // 解析
-	Difficulty      DifficultyLevel        `protobuf:"varint,7,opt,name=difficulty,proto3,enum=quiz.DifficultyLevel" json:"difficulty,omitempty"`
-	KnowledgePoints []string               `protobuf:"bytes,8,rep,name=knowledge_points,json=knowledgePoints,proto3" json:"knowledge_points,omitempty"` // 关联知识点
-	MaterialId      string                 `protobuf:"bytes,9,opt,name=material_id,json=materialId,proto3" json:"material_id,omitempty"`                // 来源材料
-	CreatedAt       string                 `protobuf:"bytes,10,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
-	unknownFields   protoimpl.UnknownFields
-	sizeCache       protoimpl.SizeCache
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	QuestionId          string                 `protobuf:"bytes,1,opt,name=question_id,json=questionId,proto3" json:"question_id,omitempty"`
+	Type                QuestionType           `protobuf:"varint,2,opt,name=type,proto3,enum=quiz.QuestionType" json:"type,omitempty"`
+	Content             string                 `protobuf:"bytes,3,opt,name=content,proto3" json:"content,omitempty"`                                  // 题目内容
+	Options             []string               `protobuf:"bytes,4,rep,name=options,proto3" json:"options,omitempty"`                                  // 选项（选择题用）
+	CorrectAnswer       string                 `protobuf:"bytes,5,opt,name=correct_answer,json=correctAnswer,proto3" json:"correct_answer,omitempty"` // 正确答案
+	Explanation         string                 `protobuf:"bytes,6,opt,name=explanation,proto3" json:"explanation,omitempty"`                          // 解析
+	Difficulty          DifficultyLevel        `protobuf:"varint,7,opt,name=difficulty,proto3,enum=quiz.DifficultyLevel" json:"difficulty,omitempty"`
+	KnowledgePoints     []string               `protobuf:"bytes,8,rep,name=knowledge_points,json=knowledgePoints,proto3" json:"knowledge_points,omitempty"` // 关联知识点
+	MaterialId          string                 `protobuf:"bytes,9,opt,name=material_id,json=materialId,proto3" json:"material_id,omitempty"`                // 来源材料
+	CreatedAt           *timestamppb.Timestamp `protobuf:"bytes,18,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	AnswerAliases       []string               `protobuf:"bytes,11,rep,name=answer_aliases,json=answerAliases,proto3" json:"answer_aliases,omitempty"`                                              // 同义答案（填空题用）
+	NumericTolerance    float64                `protobuf:"fixed64,12,opt,name=numeric_tolerance,json=numericTolerance,proto3" json:"numeric_tolerance,omitempty"`                                   // 数值答案允许误差（填空题用）
+	Parts               []*QuestionPart        `protobuf:"bytes,13,rep,name=parts,proto3" json:"parts,omitempty"`                                                                                   // 多空题的分项答案
+	Citations           []*QuestionCitation    `protobuf:"bytes,14,rep,name=citations,proto3" json:"citations,omitempty"`                                                                           // 出题依据的材料片段
+	RequestedDifficulty DifficultyLevel        `protobuf:"varint,15,opt,name=requested_difficulty,json=requestedDifficulty,proto3,enum=quiz.DifficultyLevel" json:"requested_difficulty,omitempty"` // 出题请求指定的难度
+	EstimatedDifficulty DifficultyLevel        `protobuf:"varint,16,opt,name=estimated_difficulty,json=estimatedDifficulty,proto3,enum=quiz.DifficultyLevel" json:"estimated_difficulty,omitempty"` // 按题目文本估计的难度
+	DifficultySource    string                 `protobuf:"bytes,17,opt,name=difficulty_source,json=difficultySource,proto3" json:"difficulty_source,omitempty"`                                     // 难度估计方式：heuristic / llm，未估计时为空
+	CourseId            string                 `protobuf:"bytes,19,opt,name=course_id,json=courseId,proto3" json:"course_id,omitempty"`                                                             // 所属课程，个人出题时为空
+	ReviewStatus        string                 `protobuf:"bytes,20,opt,name=review_status,json=reviewStatus,proto3" json:"review_status,omitempty"`                                                 // draft/pending_review/approved/rejected，个人出题时为空
+	ReviewerId          string                 `protobuf:"bytes,21,opt,name=reviewer_id,json=reviewerId,proto3" json:"reviewer_id,omitempty"`
+	ReviewComment       string                 `protobuf:"bytes,22,opt,name=review_comment,json=reviewComment,proto3" json:"review_comment,omitempty"` // 审核意见，驳回时为驳回原因
+	ReviewedAt          *timestamppb.Timestamp `protobuf:"bytes,23,opt,name=reviewed_at,json=reviewedAt,proto3" json:"reviewed_at,omitempty"`
+	Version             int32                  `protobuf:"varint,24,opt,name=version,proto3" json:"version,omitempty"` // 当前版本号，每次编辑加 1
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
 }
 
 func (x *Question) Reset() {
 	*x = Question{}
-	mi := &file_quiz_quiz_proto_msgTypes[2]
+	mi := &file_quiz_quiz_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -304,7 +647,7 @@ func (x *Question) String() string {
 func (*Question) ProtoMessage() {}
 
 func (x *Question) ProtoReflect() protoreflect.Message {
-	mi := &file_quiz_quiz_proto_msgTypes[2]
+	mi := &file_quiz_quiz_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -317,7 +660,7 @@ func (x *Question) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Question.ProtoReflect.Descriptor instead.
 func (*Question) Descriptor() ([]byte, []int) {
-	return file_quiz_quiz_proto_rawDescGZIP(), []int{2}
+	return file_quiz_quiz_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *Question) GetQuestionId() string {
@@ -359,60 +702,4850 @@ func (x *Question) GetExplanation() string {
 	if x != nil {
 		return x.Explanation
 	}
-	return ""
+	return ""
+}
+
+func (x *Question) GetDifficulty() DifficultyLevel {
+	if x != nil {
+		return x.Difficulty
+	}
+	return DifficultyLevel_EASY
+}
+
+func (x *Question) GetKnowledgePoints() []string {
+	if x != nil {
+		return x.KnowledgePoints
+	}
+	return nil
+}
+
+func (x *Question) GetMaterialId() string {
+	if x != nil {
+		return x.MaterialId
+	}
+	return ""
+}
+
+func (x *Question) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Question) GetAnswerAliases() []string {
+	if x != nil {
+		return x.AnswerAliases
+	}
+	return nil
+}
+
+func (x *Question) GetNumericTolerance() float64 {
+	if x != nil {
+		return x.NumericTolerance
+	}
+	return 0
+}
+
+func (x *Question) GetParts() []*QuestionPart {
+	if x != nil {
+		return x.Parts
+	}
+	return nil
+}
+
+func (x *Question) GetCitations() []*QuestionCitation {
+	if x != nil {
+		return x.Citations
+	}
+	return nil
+}
+
+func (x *Question) GetRequestedDifficulty() DifficultyLevel {
+	if x != nil {
+		return x.RequestedDifficulty
+	}
+	return DifficultyLevel_EASY
+}
+
+func (x *Question) GetEstimatedDifficulty() DifficultyLevel {
+	if x != nil {
+		return x.EstimatedDifficulty
+	}
+	return DifficultyLevel_EASY
+}
+
+func (x *Question) GetDifficultySource() string {
+	if x != nil {
+		return x.DifficultySource
+	}
+	return ""
+}
+
+func (x *Question) GetCourseId() string {
+	if x != nil {
+		return x.CourseId
+	}
+	return ""
+}
+
+func (x *Question) GetReviewStatus() string {
+	if x != nil {
+		return x.ReviewStatus
+	}
+	return ""
+}
+
+func (x *Question) GetReviewerId() string {
+	if x != nil {
+		return x.ReviewerId
+	}
+	return ""
+}
+
+func (x *Question) GetReviewComment() string {
+	if x != nil {
+		return x.ReviewComment
+	}
+	return ""
+}
+
+func (x *Question) GetReviewedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ReviewedAt
+	}
+	return nil
+}
+
+func (x *Question) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+// 题目引用的材料片段，便于教师对照原文核对、前端展示"出自第 12 页"
+type QuestionCitation struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ChunkId       string                 `protobuf:"bytes,1,opt,name=chunk_id,json=chunkId,proto3" json:"chunk_id,omitempty"` // llm-service 中的分片 ID，内存检索模式下可能为空
+	MaterialId    string                 `protobuf:"bytes,2,opt,name=material_id,json=materialId,proto3" json:"material_id,omitempty"`
+	Page          int32                  `protobuf:"varint,3,opt,name=page,proto3" json:"page,omitempty"`                                       // 文档页码，未知时为 0
+	Timecode      string                 `protobuf:"bytes,4,opt,name=timecode,proto3" json:"timecode,omitempty"`                                // 音视频时间码，如 "00:00:05-00:00:12"
+	Snippet       string                 `protobuf:"bytes,5,opt,name=snippet,proto3" json:"snippet,omitempty"`                                  // 片段开头，便于核对
+	MaterialTitle string                 `protobuf:"bytes,6,opt,name=material_title,json=materialTitle,proto3" json:"material_title,omitempty"` // 来源材料标题，多材料出题时提供
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *QuestionCitation) Reset() {
+	*x = QuestionCitation{}
+	mi := &file_quiz_quiz_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QuestionCitation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QuestionCitation) ProtoMessage() {}
+
+func (x *QuestionCitation) ProtoReflect() protoreflect.Message {
+	mi := &file_quiz_quiz_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QuestionCitation.ProtoReflect.Descriptor instead.
+func (*QuestionCitation) Descriptor() ([]byte, []int) {
+	return file_quiz_quiz_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *QuestionCitation) GetChunkId() string {
+	if x != nil {
+		return x.ChunkId
+	}
+	return ""
+}
+
+func (x *QuestionCitation) GetMaterialId() string {
+	if x != nil {
+		return x.MaterialId
+	}
+	return ""
+}
+
+func (x *QuestionCitation) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *QuestionCitation) GetTimecode() string {
+	if x != nil {
+		return x.Timecode
+	}
+	return ""
+}
+
+func (x *QuestionCitation) GetSnippet() string {
+	if x != nil {
+		return x.Snippet
+	}
+	return ""
+}
+
+func (x *QuestionCitation) GetMaterialTitle() string {
+	if x != nil {
+		return x.MaterialTitle
+	}
+	return ""
+}
+
+// 题目分项（多空题的每个空）
+type QuestionPart struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Label            string                 `protobuf:"bytes,1,opt,name=label,proto3" json:"label,omitempty"`
+	CorrectAnswer    string                 `protobuf:"bytes,2,opt,name=correct_answer,json=correctAnswer,proto3" json:"correct_answer,omitempty"`
+	AnswerAliases    []string               `protobuf:"bytes,3,rep,name=answer_aliases,json=answerAliases,proto3" json:"answer_aliases,omitempty"`
+	NumericTolerance float64                `protobuf:"fixed64,4,opt,name=numeric_tolerance,json=numericTolerance,proto3" json:"numeric_tolerance,omitempty"`
+	Weight           float32                `protobuf:"fixed32,5,opt,name=weight,proto3" json:"weight,omitempty"` // 分项权重
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *QuestionPart) Reset() {
+	*x = QuestionPart{}
+	mi := &file_quiz_quiz_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QuestionPart) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QuestionPart) ProtoMessage() {}
+
+func (x *QuestionPart) ProtoReflect() protoreflect.Message {
+	mi := &file_quiz_quiz_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QuestionPart.ProtoReflect.Descriptor instead.
+func (*QuestionPart) Descriptor() ([]byte, []int) {
+	return file_quiz_quiz_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *QuestionPart) GetLabel() string {
+	if x != nil {
+		return x.Label
+	}
+	return ""
+}
+
+func (x *QuestionPart) GetCorrectAnswer() string {
+	if x != nil {
+		return x.CorrectAnswer
+	}
+	return ""
+}
+
+func (x *QuestionPart) GetAnswerAliases() []string {
+	if x != nil {
+		return x.AnswerAliases
+	}
+	return nil
+}
+
+func (x *QuestionPart) GetNumericTolerance() float64 {
+	if x != nil {
+		return x.NumericTolerance
+	}
+	return 0
+}
+
+func (x *QuestionPart) GetWeight() float32 {
+	if x != nil {
+		return x.Weight
+	}
+	return 0
+}
+
+// 获取题目请求
+type GetQuizRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	QuestionId    string                 `protobuf:"bytes,1,opt,name=question_id,json=questionId,proto3" json:"question_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // 未通过审核的课程题目仅创建者与审核人可查看
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetQuizRequest) Reset() {
+	*x = GetQuizRequest{}
+	mi := &file_quiz_quiz_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetQuizRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetQuizRequest) ProtoMessage() {}
+
+func (x *GetQuizRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_quiz_quiz_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetQuizRequest.ProtoReflect.Descriptor instead.
+func (*GetQuizRequest) Descriptor() ([]byte, []int) {
+	return file_quiz_quiz_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *GetQuizRequest) GetQuestionId() string {
+	if x != nil {
+		return x.QuestionId
+	}
+	return ""
+}
+
+func (x *GetQuizRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+// 获取题目响应
+type GetQuizResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Question      *Question              `protobuf:"bytes,3,opt,name=question,proto3" json:"question,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetQuizResponse) Reset() {
+	*x = GetQuizResponse{}
+	mi := &file_quiz_quiz_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetQuizResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetQuizResponse) ProtoMessage() {}
+
+func (x *GetQuizResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_quiz_quiz_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetQuizResponse.ProtoReflect.Descriptor instead.
+func (*GetQuizResponse) Descriptor() ([]byte, []int) {
+	return file_quiz_quiz_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GetQuizResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *GetQuizResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *GetQuizResponse) GetQuestion() *Question {
+	if x != nil {
+		return x.Question
+	}
+	return nil
+}
+
+// 题目列表请求
+type ListQuizzesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	MaterialId    string                 `protobuf:"bytes,2,opt,name=material_id,json=materialId,proto3" json:"material_id,omitempty"`
+	Type          QuestionType           `protobuf:"varint,3,opt,name=type,proto3,enum=quiz.QuestionType" json:"type,omitempty"`
+	Difficulty    DifficultyLevel        `protobuf:"varint,4,opt,name=difficulty,proto3,enum=quiz.DifficultyLevel" json:"difficulty,omitempty"`
+	Page          int32                  `protobuf:"varint,5,opt,name=page,proto3" json:"page,omitempty"`
+	PageSize      int32                  `protobuf:"varint,6,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	CourseId      string                 `protobuf:"bytes,7,opt,name=course_id,json=courseId,proto3" json:"course_id,omitempty"` // 指定时列出课程题目，学生只能看到审核通过的题目
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListQuizzesRequest) Reset() {
+	*x = ListQuizzesRequest{}
+	mi := &file_quiz_quiz_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListQuizzesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListQuizzesRequest) ProtoMessage() {}
+
+func (x *ListQuizzesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_quiz_quiz_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListQuizzesRequest.ProtoReflect.Descriptor instead.
+func (*ListQuizzesRequest) Descriptor() ([]byte, []int) {
+	return file_quiz_quiz_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ListQuizzesRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *ListQuizzesRequest) GetMaterialId() string {
+	if x != nil {
+		return x.MaterialId
+	}
+	return ""
+}
+
+func (x *ListQuizzesRequest) GetType() QuestionType {
+	if x != nil {
+		return x.Type
+	}
+	return QuestionType_MULTIPLE_CHOICE
+}
+
+func (x *ListQuizzesRequest) GetDifficulty() DifficultyLevel {
+	if x != nil {
+		return x.Difficulty
+	}
+	return DifficultyLevel_EASY
+}
+
+func (x *ListQuizzesRequest) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *ListQuizzesRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListQuizzesRequest) GetCourseId() string {
+	if x != nil {
+		return x.CourseId
+	}
+	return ""
+}
+
+// 题目列表响应
+type ListQuizzesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Questions     []*Question            `protobuf:"bytes,3,rep,name=questions,proto3" json:"questions,omitempty"`
+	Total         int32                  `protobuf:"varint,4,opt,name=total,proto3" json:"total,omitempty"`
+	Page          int32                  `protobuf:"varint,5,opt,name=page,proto3" json:"page,omitempty"`
+	PageSize      int32                  `protobuf:"varint,6,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListQuizzesResponse) Reset() {
+	*x = ListQuizzesResponse{}
+	mi := &file_quiz_quiz_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListQuizzesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListQuizzesResponse) ProtoMessage() {}
+
+func (x *ListQuizzesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_quiz_quiz_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListQuizzesResponse.ProtoReflect.Descriptor instead.
+func (*ListQuizzesResponse) Descriptor() ([]byte, []int) {
+	return file_quiz_quiz_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ListQuizzesResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *ListQuizzesResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *ListQuizzesResponse) GetQuestions() []*Question {
+	if x != nil {
+		return x.Questions
+	}
+	return nil
+}
+
+func (x *ListQuizzesResponse) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *ListQuizzesResponse) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *ListQuizzesResponse) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+// 提交答案请求
+type SubmitAnswerRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	QuestionId    string                 `protobuf:"bytes,1,opt,name=question_id,json=questionId,proto3" json:"question_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Answer        string                 `protobuf:"bytes,3,opt,name=answer,proto3" json:"answer,omitempty"`                                 // 用户答案
+	PartAnswers   []string               `protobuf:"bytes,4,rep,name=part_answers,json=partAnswers,proto3" json:"part_answers,omitempty"`    // 多空题的分项答案，按空位顺序
+	TimeSpentMs   int64                  `protobuf:"varint,5,opt,name=time_spent_ms,json=timeSpentMs,proto3" json:"time_spent_ms,omitempty"` // 作答耗时（毫秒）
+	AttemptId     string                 `protobuf:"bytes,6,opt,name=attempt_id,json=attemptId,proto3" json:"attempt_id,omitempty"`          // StartQuestionAttempt 返回的会话ID，答案按该会话的选项顺序解读
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubmitAnswerRequest) Reset() {
+	*x = SubmitAnswerRequest{}
+	mi := &file_quiz_quiz_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubmitAnswerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubmitAnswerRequest) ProtoMessage() {}
+
+func (x *SubmitAnswerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_quiz_quiz_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubmitAnswerRequest.ProtoReflect.Descriptor instead.
+func (*SubmitAnswerRequest) Descriptor() ([]byte, []int) {
+	return file_quiz_quiz_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *SubmitAnswerRequest) GetQuestionId() string {
+	if x != nil {
+		return x.QuestionId
+	}
+	return ""
+}
+
+func (x *SubmitAnswerRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *SubmitAnswerRequest) GetAnswer() string {
+	if x != nil {
+		return x.Answer
+	}
+	return ""
+}
+
+func (x *SubmitAnswerRequest) GetPartAnswers() []string {
+	if x != nil {
+		return x.PartAnswers
+	}
+	return nil
+}
+
+func (x *SubmitAnswerRequest) GetTimeSpentMs() int64 {
+	if x != nil {
+		return x.TimeSpentMs
+	}
+	return 0
+}
+
+func (x *SubmitAnswerRequest) GetAttemptId() string {
+	if x != nil {
+		return x.AttemptId
+	}
+	return ""
+}
+
+// 提交答案响应
+type SubmitAnswerResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	IsCorrect     bool                   `protobuf:"varint,3,opt,name=is_correct,json=isCorrect,proto3" json:"is_correct,omitempty"`               // 是否正确
+	Score         float32                `protobuf:"fixed32,4,opt,name=score,proto3" json:"score,omitempty"`                                       // 得分
+	CorrectAnswer string                 `protobuf:"bytes,5,opt,name=correct_answer,json=correctAnswer,proto3" json:"correct_answer,omitempty"`    // 正确答案
+	Explanation   string                 `protobuf:"bytes,6,opt,name=explanation,proto3" json:"explanation,omitempty"`                             // 解析
+	BlankMatch    *FillBlankMatch        `protobuf:"bytes,7,opt,name=blank_match,json=blankMatch,proto3" json:"blank_match,omitempty"`             // 填空题匹配详情
+	PartResults   []*PartResult          `protobuf:"bytes,8,rep,name=part_results,json=partResults,proto3" json:"part_results,omitempty"`          // 多空题分项评分
+	RawScore      float32                `protobuf:"fixed32,9,opt,name=raw_score,json=rawScore,proto3" json:"raw_score,omitempty"`                 // 扣除重复作答惩罚前的得分
+	PassThreshold float32                `protobuf:"fixed32,10,opt,name=pass_threshold,json=passThreshold,proto3" json:"pass_threshold,omitempty"` // 生效的及格线
+	AttemptNumber int32                  `protobuf:"varint,11,opt,name=attempt_number,json=attemptNumber,proto3" json:"attempt_number,omitempty"`  // 本次为第几次作答
+	AttemptId     string                 `protobuf:"bytes,12,opt,name=attempt_id,json=attemptId,proto3" json:"attempt_id,omitempty"`               // 通过作答会话提交时，correct_answer 为该会话选项顺序下的答案
+	Similarity    *SimilarityCheck       `protobuf:"bytes,13,opt,name=similarity,proto3" json:"similarity,omitempty"`                              // 论述题查重结果，未查重时为空
+	GradingStatus string                 `protobuf:"bytes,14,opt,name=grading_status,json=gradingStatus,proto3" json:"grading_status,omitempty"`   // 主观题为 pending_review，score 为 AI 建议得分，教师复核后可能调整
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubmitAnswerResponse) Reset() {
+	*x = SubmitAnswerResponse{}
+	mi := &file_quiz_quiz_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubmitAnswerResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubmitAnswerResponse) ProtoMessage() {}
+
+func (x *SubmitAnswerResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_quiz_quiz_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubmitAnswerResponse.ProtoReflect.Descriptor instead.
+func (*SubmitAnswerResponse) Descriptor() ([]byte, []int) {
+	return file_quiz_quiz_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *SubmitAnswerResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *SubmitAnswerResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *SubmitAnswerResponse) GetIsCorrect() bool {
+	if x != nil {
+		return x.IsCorrect
+	}
+	return false
+}
+
+func (x *SubmitAnswerResponse) GetScore() float32 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+func (x *SubmitAnswerResponse) GetCorrectAnswer() string {
+	if x != nil {
+		return x.CorrectAnswer
+	}
+	return ""
+}
+
+func (x *SubmitAnswerResponse) GetExplanation() string {
+	if x != nil {
+		return x.Explanation
+	}
+	return ""
+}
+
+func (x *SubmitAnswerResponse) GetBlankMatch() *FillBlankMatch {
+	if x != nil {
+		return x.BlankMatch
+	}
+	return nil
+}
+
+func (x *SubmitAnswerResponse) GetPartResults() []*PartResult {
+	if x != nil {
+		return x.PartResults
+	}
+	return nil
+}
+
+func (x *SubmitAnswerResponse) GetRawScore() float32 {
+	if x != nil {
+		return x.RawScore
+	}
+	return 0
+}
+
+func (x *SubmitAnswerResponse) GetPassThreshold() float32 {
+	if x != nil {
+		return x.PassThreshold
+	}
+	return 0
+}
+
+func (x *SubmitAnswerResponse) GetAttemptNumber() int32 {
+	if x != nil {
+		return x.AttemptNumber
+	}
+	return 0
+}
+
+func (x *SubmitAnswerResponse) GetAttemptId() string {
+	if x != nil {
+		return x.AttemptId
+	}
+	return ""
+}
+
+func (x *SubmitAnswerResponse) GetSimilarity() *SimilarityCheck {
+	if x != nil {
+		return x.Similarity
+	}
+	return nil
+}
+
+func (x *SubmitAnswerResponse) GetGradingStatus() string {
+	if x != nil {
+		return x.GradingStatus
+	}
+	return ""
+}
+
+// 论述题查重结果，相似度为作答与材料片段、其他学生作答的最高余弦相似度
+type SimilarityCheck struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	MaterialSimilarity float32                `protobuf:"fixed32,1,opt,name=material_similarity,json=materialSimilarity,proto3" json:"material_similarity,omitempty"`
+	MaterialChunkId    string                 `protobuf:"bytes,2,opt,name=material_chunk_id,json=materialChunkId,proto3" json:"material_chunk_id,omitempty"` // 最相近的材料片段
+	PeerSimilarity     float32                `protobuf:"fixed32,3,opt,name=peer_similarity,json=peerSimilarity,proto3" json:"peer_similarity,omitempty"`
+	PeersCompared      int32                  `protobuf:"varint,4,opt,name=peers_compared,json=peersCompared,proto3" json:"peers_compared,omitempty"` // 参与比较的其他学生作答数
+	Flagged            bool                   `protobuf:"varint,5,opt,name=flagged,proto3" json:"flagged,omitempty"`                                  // 相似度超过阈值，疑似抄袭
+	Reasons            []string               `protobuf:"bytes,6,rep,name=reasons,proto3" json:"reasons,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *SimilarityCheck) Reset() {
+	*x = SimilarityCheck{}
+	mi := &file_quiz_quiz_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SimilarityCheck) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SimilarityCheck) ProtoMessage() {}
+
+func (x *SimilarityCheck) ProtoReflect() protoreflect.Message {
+	mi := &file_quiz_quiz_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SimilarityCheck.ProtoReflect.Descriptor instead.
+func (*SimilarityCheck) Descriptor() ([]byte, []int) {
+	return file_quiz_quiz_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *SimilarityCheck) GetMaterialSimilarity() float32 {
+	if x != nil {
+		return x.MaterialSimilarity
+	}
+	return 0
+}
+
+func (x *SimilarityCheck) GetMaterialChunkId() string {
+	if x != nil {
+		return x.MaterialChunkId
+	}
+	return ""
+}
+
+func (x *SimilarityCheck) GetPeerSimilarity() float32 {
+	if x != nil {
+		return x.PeerSimilarity
+	}
+	return 0
+}
+
+func (x *SimilarityCheck) GetPeersCompared() int32 {
+	if x != nil {
+		return x.PeersCompared
+	}
+	return 0
+}
+
+func (x *SimilarityCheck) GetFlagged() bool {
+	if x != nil {
+		return x.Flagged
+	}
+	return false
+}
+
+func (x *SimilarityCheck) GetReasons() []string {
+	if x != nil {
+		return x.Reasons
+	}
+	return nil
+}
+
+// 分项评分结果
+type PartResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Index         int32                  `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	Answer        string                 `protobuf:"bytes,2,opt,name=answer,proto3" json:"answer,omitempty"`
+	IsCorrect     bool                   `protobuf:"varint,3,opt,name=is_correct,json=isCorrect,proto3" json:"is_correct,omitempty"`
+	Score         float32                `protobuf:"fixed32,4,opt,name=score,proto3" json:"score,omitempty"`
+	Weight        float32                `protobuf:"fixed32,5,opt,name=weight,proto3" json:"weight,omitempty"`
+	CorrectAnswer string                 `protobuf:"bytes,6,opt,name=correct_answer,json=correctAnswer,proto3" json:"correct_answer,omitempty"`
+	MatchType     string                 `protobuf:"bytes,7,opt,name=match_type,json=matchType,proto3" json:"match_type,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PartResult) Reset() {
+	*x = PartResult{}
+	mi := &file_quiz_quiz_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PartResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PartResult) ProtoMessage() {}
+
+func (x *PartResult) ProtoReflect() protoreflect.Message {
+	mi := &file_quiz_quiz_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PartResult.ProtoReflect.Descriptor instead.
+func (*PartResult) Descriptor() ([]byte, []int) {
+	return file_quiz_quiz_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *PartResult) GetIndex() int32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *PartResult) GetAnswer() string {
+	if x != nil {
+		return x.Answer
+	}
+	return ""
+}
+
+func (x *PartResult) GetIsCorrect() bool {
+	if x != nil {
+		return x.IsCorrect
+	}
+	return false
+}
+
+func (x *PartResult) GetScore() float32 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+func (x *PartResult) GetWeight() float32 {
+	if x != nil {
+		return x.Weight
+	}
+	return 0
+}
+
+func (x *PartResult) GetCorrectAnswer() string {
+	if x != nil {
+		return x.CorrectAnswer
+	}
+	return ""
+}
+
+func (x *PartResult) GetMatchType() string {
+	if x != nil {
+		return x.MatchType
+	}
+	return ""
+}
+
+// 填空题匹配详情
+type FillBlankMatch struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Matched          bool                   `protobuf:"varint,1,opt,name=matched,proto3" json:"matched,omitempty"`
+	MatchType        string                 `protobuf:"bytes,2,opt,name=match_type,json=matchType,proto3" json:"match_type,omitempty"`                      // exact/normalized/alias/numeric/none
+	MatchedAnswer    string                 `protobuf:"bytes,3,opt,name=matched_answer,json=matchedAnswer,proto3" json:"matched_answer,omitempty"`          // 命中的标准答案或同义答案
+	NormalizedAnswer string                 `protobuf:"bytes,4,opt,name=normalized_answer,json=normalizedAnswer,proto3" json:"normalized_answer,omitempty"` // 归一化后的用户答案
+	NumericDiff      float64                `protobuf:"fixed64,5,opt,name=numeric_diff,json=numericDiff,proto3" json:"numeric_diff,omitempty"`              // 数值匹配时的误差
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *FillBlankMatch) Reset() {
+	*x = FillBlankMatch{}
+	mi := &file_quiz_quiz_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FillBlankMatch) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FillBlankMatch) ProtoMessage() {}
+
+func (x *FillBlankMatch) ProtoReflect() protoreflect.Message {
+	mi := &file_quiz_quiz_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FillBlankMatch.ProtoReflect.Descriptor instead.
+func (*FillBlankMatch) Descriptor() ([]byte, []int) {
+	return file_quiz_quiz_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *FillBlankMatch) GetMatched() bool {
+	if x != nil {
+		return x.Matched
+	}
+	return false
+}
+
+func (x *FillBlankMatch) GetMatchType() string {
+	if x != nil {
+		return x.MatchType
+	}
+	return ""
+}
+
+func (x *FillBlankMatch) GetMatchedAnswer() string {
+	if x != nil {
+		return x.MatchedAnswer
+	}
+	return ""
+}
+
+func (x *FillBlankMatch) GetNormalizedAnswer() string {
+	if x != nil {
+		return x.NormalizedAnswer
+	}
+	return ""
+}
+
+func (x *FillBlankMatch) GetNumericDiff() float64 {
+	if x != nil {
+		return x.NumericDiff
+	}
+	return 0
+}
+
+// 用户答题记录
+type UserAnswer struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	AnswerId        string                 `protobuf:"bytes,1,opt,name=answer_id,json=answerId,proto3" json:"answer_id,omitempty"`
+	QuestionId      string                 `protobuf:"bytes,2,opt,name=question_id,json=questionId,proto3" json:"question_id,omitempty"`
+	UserId          string                 `protobuf:"bytes,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Answer          string                 `protobuf:"bytes,4,opt,name=answer,proto3" json:"answer,omitempty"`
+	IsCorrect       bool                   `protobuf:"varint,5,opt,name=is_correct,json=isCorrect,proto3" json:"is_correct,omitempty"`
+	Score           float32                `protobuf:"fixed32,6,opt,name=score,proto3" json:"score,omitempty"`
+	AnsweredAt      *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=answered_at,json=answeredAt,proto3" json:"answered_at,omitempty"`
+	PartResults     []*PartResult          `protobuf:"bytes,8,rep,name=part_results,json=partResults,proto3" json:"part_results,omitempty"`
+	TimeSpentMs     int64                  `protobuf:"varint,9,opt,name=time_spent_ms,json=timeSpentMs,proto3" json:"time_spent_ms,omitempty"`
+	QuestionVersion int32                  `protobuf:"varint,11,opt,name=question_version,json=questionVersion,proto3" json:"question_version,omitempty"` // 作答时的题目版本，早于版本功能的记录为 0
+	AttemptId       string                 `protobuf:"bytes,12,opt,name=attempt_id,json=attemptId,proto3" json:"attempt_id,omitempty"`                    // 通过作答会话提交时的会话ID
+	OptionOrder     []int32                `protobuf:"varint,13,rep,packed,name=option_order,json=optionOrder,proto3" json:"option_order,omitempty"`      // 作答时的选项排列，第 i 个展示的选项为原第 option_order[i] 个选项；answer 已换算为原选项顺序
+	Similarity      *SimilarityCheck       `protobuf:"bytes,14,opt,name=similarity,proto3" json:"similarity,omitempty"`                                   // 论述题查重结果
+	GradingStatus   string                 `protobuf:"bytes,15,opt,name=grading_status,json=gradingStatus,proto3" json:"grading_status,omitempty"`        // 主观题的人工评分状态：pending_review 或 graded，客观题为空
+	SuggestedScore  float32                `protobuf:"fixed32,16,opt,name=suggested_score,json=suggestedScore,proto3" json:"suggested_score,omitempty"`   // AI 建议的原始得分
+	AiFeedback      string                 `protobuf:"bytes,17,opt,name=ai_feedback,json=aiFeedback,proto3" json:"ai_feedback,omitempty"`                 // AI 评语
+	GradedBy        string                 `protobuf:"bytes,18,opt,name=graded_by,json=gradedBy,proto3" json:"graded_by,omitempty"`
+	GradedAt        *timestamppb.Timestamp `protobuf:"bytes,19,opt,name=graded_at,json=gradedAt,proto3" json:"graded_at,omitempty"`
+	GradingComment  string                 `protobuf:"bytes,20,opt,name=grading_comment,json=gradingComment,proto3" json:"grading_comment,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *UserAnswer) Reset() {
+	*x = UserAnswer{}
+	mi := &file_quiz_quiz_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserAnswer) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserAnswer) ProtoMessage() {}
+
+func (x *UserAnswer) ProtoReflect() protoreflect.Message {
+	mi := &file_quiz_quiz_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserAnswer.ProtoReflect.Descriptor instead.
+func (*UserAnswer) Descriptor() ([]byte, []int) {
+	return file_quiz_quiz_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *UserAnswer) GetAnswerId() string {
+	if x != nil {
+		return x.AnswerId
+	}
+	return ""
+}
+
+func (x *UserAnswer) GetQuestionId() string {
+	if x != nil {
+		return x.QuestionId
+	}
+	return ""
+}
+
+func (x *UserAnswer) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *UserAnswer) GetAnswer() string {
+	if x != nil {
+		return x.Answer
+	}
+	return ""
+}
+
+func (x *UserAnswer) GetIsCorrect() bool {
+	if x != nil {
+		return x.IsCorrect
+	}
+	return false
+}
+
+func (x *UserAnswer) GetScore() float32 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+func (x *UserAnswer) GetAnsweredAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.AnsweredAt
+	}
+	return nil
+}
+
+func (x *UserAnswer) GetPartResults() []*PartResult {
+	if x != nil {
+		return x.PartResults
+	}
+	return nil
+}
+
+func (x *UserAnswer) GetTimeSpentMs() int64 {
+	if x != nil {
+		return x.TimeSpentMs
+	}
+	return 0
+}
+
+func (x *UserAnswer) GetQuestionVersion() int32 {
+	if x != nil {
+		return x.QuestionVersion
+	}
+	return 0
+}
+
+func (x *UserAnswer) GetAttemptId() string {
+	if x != nil {
+		return x.AttemptId
+	}
+	return ""
+}
+
+func (x *UserAnswer) GetOptionOrder() []int32 {
+	if x != nil {
+		return x.OptionOrder
+	}
+	return nil
+}
+
+func (x *UserAnswer) GetSimilarity() *SimilarityCheck {
+	if x != nil {
+		return x.Similarity
+	}
+	return nil
+}
+
+func (x *UserAnswer) GetGradingStatus() string {
+	if x != nil {
+		return x.GradingStatus
+	}
+	return ""
+}
+
+func (x *UserAnswer) GetSuggestedScore() float32 {
+	if x != nil {
+		return x.SuggestedScore
+	}
+	return 0
+}
+
+func (x *UserAnswer) GetAiFeedback() string {
+	if x != nil {
+		return x.AiFeedback
+	}
+	return ""
+}
+
+func (x *UserAnswer) GetGradedBy() string {
+	if x != nil {
+		return x.GradedBy
+	}
+	return ""
+}
+
+func (x *UserAnswer) GetGradedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.GradedAt
+	}
+	return nil
+}
+
+func (x *UserAnswer) GetGradingComment() string {
+	if x != nil {
+		return x.GradingComment
+	}
+	return ""
+}
+
+// 获取用户答题历史请求
+type GetUserQuizHistoryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Page          int32                  `protobuf:"varint,2,opt,name=page,proto3" json:"page,omitempty"`
+	PageSize      int32                  `protobuf:"varint,3,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUserQuizHistoryRequest) Reset() {
+	*x = GetUserQuizHistoryRequest{}
+	mi := &file_quiz_quiz_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUserQuizHistoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUserQuizHistoryRequest) ProtoMessage() {}
+
+func (x *GetUserQuizHistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_quiz_quiz_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUserQuizHistoryRequest.ProtoReflect.Descriptor instead.
+func (*GetUserQuizHistoryRequest) Descriptor() ([]byte, []int) {
+	return file_quiz_quiz_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *GetUserQuizHistoryRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *GetUserQuizHistoryRequest) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *GetUserQuizHistoryRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+// 获取用户答题历史响应
+type GetUserQuizHistoryResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Answers       []*UserAnswer          `protobuf:"bytes,3,rep,name=answers,proto3" json:"answers,omitempty"`
+	Total         int32                  `protobuf:"varint,4,opt,name=total,proto3" json:"total,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUserQuizHistoryResponse) Reset() {
+	*x = GetUserQuizHistoryResponse{}
+	mi := &file_quiz_quiz_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUserQuizHistoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUserQuizHistoryResponse) ProtoMessage() {}
+
+func (x *GetUserQuizHistoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_quiz_quiz_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUserQuizHistoryResponse.ProtoReflect.Descriptor instead.
+func (*GetUserQuizHistoryResponse) Descriptor() ([]byte, []int) {
+	return file_quiz_quiz_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *GetUserQuizHistoryResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *GetUserQuizHistoryResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *GetUserQuizHistoryResponse) GetAnswers() []*UserAnswer {
+	if x != nil {
+		return x.Answers
+	}
+	return nil
+}
+
+func (x *GetUserQuizHistoryResponse) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+// 知识点统计
+type KnowledgePointStats struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	KnowledgePoint string                 `protobuf:"bytes,1,opt,name=knowledge_point,json=knowledgePoint,proto3" json:"knowledge_point,omitempty"`
+	TotalQuestions int32                  `protobuf:"varint,2,opt,name=total_questions,json=totalQuestions,proto3" json:"total_questions,omitempty"`                        // 总题数
+	CorrectAnswers int32                  `protobuf:"varint,3,opt,name=correct_answers,json=correctAnswers,proto3" json:"correct_answers,omitempty"`                        // 正确题数
+	AccuracyRate   float32                `protobuf:"fixed32,4,opt,name=accuracy_rate,json=accuracyRate,proto3" json:"accuracy_rate,omitempty"`                             // 正确率
+	AvgDifficulty  DifficultyLevel        `protobuf:"varint,5,opt,name=avg_difficulty,json=avgDifficulty,proto3,enum=quiz.DifficultyLevel" json:"avg_difficulty,omitempty"` // 平均难度
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *KnowledgePointStats) Reset() {
+	*x = KnowledgePointStats{}
+	mi := &file_quiz_quiz_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *KnowledgePointStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KnowledgePointStats) ProtoMessage() {}
+
+func (x *KnowledgePointStats) ProtoReflect() protoreflect.Message {
+	mi := &file_quiz_quiz_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KnowledgePointStats.ProtoReflect.Descriptor instead.
+func (*KnowledgePointStats) Descriptor() ([]byte, []int) {
+	return file_quiz_quiz_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *KnowledgePointStats) GetKnowledgePoint() string {
+	if x != nil {
+		return x.KnowledgePoint
+	}
+	return ""
+}
+
+func (x *KnowledgePointStats) GetTotalQuestions() int32 {
+	if x != nil {
+		return x.TotalQuestions
+	}
+	return 0
+}
+
+func (x *KnowledgePointStats) GetCorrectAnswers() int32 {
+	if x != nil {
+		return x.CorrectAnswers
+	}
+	return 0
+}
+
+func (x *KnowledgePointStats) GetAccuracyRate() float32 {
+	if x != nil {
+		return x.AccuracyRate
+	}
+	return 0
+}
+
+func (x *KnowledgePointStats) GetAvgDifficulty() DifficultyLevel {
+	if x != nil {
+		return x.AvgDifficulty
+	}
+	return DifficultyLevel_EASY
+}
+
+// 获取知识点掌握度统计请求
+type GetKnowledgeStatsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	MaterialId    string                 `protobuf:"bytes,2,opt,name=material_id,json=materialId,proto3" json:"material_id,omitempty"` // 可选，指定材料
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetKnowledgeStatsRequest) Reset() {
+	*x = GetKnowledgeStatsRequest{}
+	mi := &file_quiz_quiz_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetKnowledgeStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetKnowledgeStatsRequest) ProtoMessage() {}
+
+func (x *GetKnowledgeStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_quiz_quiz_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetKnowledgeStatsRequest.ProtoReflect.Descriptor instead.
+func (*GetKnowledgeStatsRequest) Descriptor() ([]byte, []int) {
+	return file_quiz_quiz_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *GetKnowledgeStatsRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *GetKnowledgeStatsRequest) GetMaterialId() string {
+	if x != nil {
+		return x.MaterialId
+	}
+	return ""
+}
+
+// 获取知识点掌握度统计响应
+type GetKnowledgeStatsResponse struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Success         bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message         string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Stats           []*KnowledgePointStats `protobuf:"bytes,3,rep,name=stats,proto3" json:"stats,omitempty"`
+	OverallAccuracy float32                `protobuf:"fixed32,4,opt,name=overall_accuracy,json=overallAccuracy,proto3" json:"overall_accuracy,omitempty"` // 总体正确率
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *GetKnowledgeStatsResponse) Reset() {
+	*x = GetKnowledgeStatsResponse{}
+	mi := &file_quiz_quiz_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetKnowledgeStatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetKnowledgeStatsResponse) ProtoMessage() {}
+
+func (x *GetKnowledgeStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_quiz_quiz_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetKnowledgeStatsResponse.ProtoReflect.Descriptor instead.
+func (*GetKnowledgeStatsResponse) Descriptor() ([]byte, []int) {
+	return file_quiz_quiz_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *GetKnowledgeStatsResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *GetKnowledgeStatsResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *GetKnowledgeStatsResponse) GetStats() []*KnowledgePointStats {
+	if x != nil {
+		return x.Stats
+	}
+	return nil
+}
+
+func (x *GetKnowledgeStatsResponse) GetOverallAccuracy() float32 {
+	if x != nil {
+		return x.OverallAccuracy
+	}
+	return 0
+}
+
+// 题目作答分析
+type QuestionAnalytics struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	QuestionId          string                 `protobuf:"bytes,1,opt,name=question_id,json=questionId,proto3" json:"question_id,omitempty"`
+	MaterialId          string                 `protobuf:"bytes,2,opt,name=material_id,json=materialId,proto3" json:"material_id,omitempty"`
+	AttemptCount        int32                  `protobuf:"varint,3,opt,name=attempt_count,json=attemptCount,proto3" json:"attempt_count,omitempty"`                                                 // 作答次数
+	CorrectCount        int32                  `protobuf:"varint,4,opt,name=correct_count,json=correctCount,proto3" json:"correct_count,omitempty"`                                                 // 答对次数
+	SuccessRate         float32                `protobuf:"fixed32,5,opt,name=success_rate,json=successRate,proto3" json:"success_rate,omitempty"`                                                   // 正确率
+	AvgScore            float32                `protobuf:"fixed32,6,opt,name=avg_score,json=avgScore,proto3" json:"avg_score,omitempty"`                                                            // 平均得分
+	AvgTimeMs           int64                  `protobuf:"varint,7,opt,name=avg_time_ms,json=avgTimeMs,proto3" json:"avg_time_ms,omitempty"`                                                        // 平均耗时（毫秒）
+	OriginalDifficulty  DifficultyLevel        `protobuf:"varint,8,opt,name=original_difficulty,json=originalDifficulty,proto3,enum=quiz.DifficultyLevel" json:"original_difficulty,omitempty"`     // 出题时标注的难度
+	CurrentDifficulty   DifficultyLevel        `protobuf:"varint,9,opt,name=current_difficulty,json=currentDifficulty,proto3,enum=quiz.DifficultyLevel" json:"current_difficulty,omitempty"`        // 当前存储的难度
+	SuggestedDifficulty DifficultyLevel        `protobuf:"varint,10,opt,name=suggested_difficulty,json=suggestedDifficulty,proto3,enum=quiz.DifficultyLevel" json:"suggested_difficulty,omitempty"` // 按正确率推算的难度
+	LastCalibratedAt    *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=last_calibrated_at,json=lastCalibratedAt,proto3" json:"last_calibrated_at,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *QuestionAnalytics) Reset() {
+	*x = QuestionAnalytics{}
+	mi := &file_quiz_quiz_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QuestionAnalytics) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QuestionAnalytics) ProtoMessage() {}
+
+func (x *QuestionAnalytics) ProtoReflect() protoreflect.Message {
+	mi := &file_quiz_quiz_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QuestionAnalytics.ProtoReflect.Descriptor instead.
+func (*QuestionAnalytics) Descriptor() ([]byte, []int) {
+	return file_quiz_quiz_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *QuestionAnalytics) GetQuestionId() string {
+	if x != nil {
+		return x.QuestionId
+	}
+	return ""
+}
+
+func (x *QuestionAnalytics) GetMaterialId() string {
+	if x != nil {
+		return x.MaterialId
+	}
+	return ""
+}
+
+func (x *QuestionAnalytics) GetAttemptCount() int32 {
+	if x != nil {
+		return x.AttemptCount
+	}
+	return 0
+}
+
+func (x *QuestionAnalytics) GetCorrectCount() int32 {
+	if x != nil {
+		return x.CorrectCount
+	}
+	return 0
+}
+
+func (x *QuestionAnalytics) GetSuccessRate() float32 {
+	if x != nil {
+		return x.SuccessRate
+	}
+	return 0
+}
+
+func (x *QuestionAnalytics) GetAvgScore() float32 {
+	if x != nil {
+		return x.AvgScore
+	}
+	return 0
+}
+
+func (x *QuestionAnalytics) GetAvgTimeMs() int64 {
+	if x != nil {
+		return x.AvgTimeMs
+	}
+	return 0
+}
+
+func (x *QuestionAnalytics) GetOriginalDifficulty() DifficultyLevel {
+	if x != nil {
+		return x.OriginalDifficulty
+	}
+	return DifficultyLevel_EASY
+}
+
+func (x *QuestionAnalytics) GetCurrentDifficulty() DifficultyLevel {
+	if x != nil {
+		return x.CurrentDifficulty
+	}
+	return DifficultyLevel_EASY
+}
+
+func (x *QuestionAnalytics) GetSuggestedDifficulty() DifficultyLevel {
+	if x != nil {
+		return x.SuggestedDifficulty
+	}
+	return DifficultyLevel_EASY
+}
+
+func (x *QuestionAnalytics) GetLastCalibratedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastCalibratedAt
+	}
+	return nil
+}
+
+// 获取题库作答分析请求
+type GetQuestionAnalyticsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	QuestionId    string                 `protobuf:"bytes,1,opt,name=question_id,json=questionId,proto3" json:"question_id,omitempty"`     // 可选，指定单题
+	MaterialId    string                 `protobuf:"bytes,2,opt,name=material_id,json=materialId,proto3" json:"material_id,omitempty"`     // 可选，指定材料
+	MinAttempts   int32                  `protobuf:"varint,3,opt,name=min_attempts,json=minAttempts,proto3" json:"min_attempts,omitempty"` // 可选，最少作答次数
+	Page          int32                  `protobuf:"varint,4,opt,name=page,proto3" json:"page,omitempty"`
+	PageSize      int32                  `protobuf:"varint,5,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	UserId        string                 `protobuf:"bytes,6,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // 调用者，非教师/管理员只能查看本人创建的题目
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetQuestionAnalyticsRequest) Reset() {
+	*x = GetQuestionAnalyticsRequest{}
+	mi := &file_quiz_quiz_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetQuestionAnalyticsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetQuestionAnalyticsRequest) ProtoMessage() {}
+
+func (x *GetQuestionAnalyticsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_quiz_quiz_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetQuestionAnalyticsRequest.ProtoReflect.Descriptor instead.
+func (*GetQuestionAnalyticsRequest) Descriptor() ([]byte, []int) {
+	return file_quiz_quiz_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *GetQuestionAnalyticsRequest) GetQuestionId() string {
+	if x != nil {
+		return x.QuestionId
+	}
+	return ""
+}
+
+func (x *GetQuestionAnalyticsRequest) GetMaterialId() string {
+	if x != nil {
+		return x.MaterialId
+	}
+	return ""
+}
+
+func (x *GetQuestionAnalyticsRequest) GetMinAttempts() int32 {
+	if x != nil {
+		return x.MinAttempts
+	}
+	return 0
+}
+
+func (x *GetQuestionAnalyticsRequest) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *GetQuestionAnalyticsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *GetQuestionAnalyticsRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+// 获取题库作答分析响应
+type GetQuestionAnalyticsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Analytics     []*QuestionAnalytics   `protobuf:"bytes,3,rep,name=analytics,proto3" json:"analytics,omitempty"`
+	Total         int32                  `protobuf:"varint,4,opt,name=total,proto3" json:"total,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetQuestionAnalyticsResponse) Reset() {
+	*x = GetQuestionAnalyticsResponse{}
+	mi := &file_quiz_quiz_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetQuestionAnalyticsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetQuestionAnalyticsResponse) ProtoMessage() {}
+
+func (x *GetQuestionAnalyticsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_quiz_quiz_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetQuestionAnalyticsResponse.ProtoReflect.Descriptor instead.
+func (*GetQuestionAnalyticsResponse) Descriptor() ([]byte, []int) {
+	return file_quiz_quiz_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *GetQuestionAnalyticsResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *GetQuestionAnalyticsResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *GetQuestionAnalyticsResponse) GetAnalytics() []*QuestionAnalytics {
+	if x != nil {
+		return x.Analytics
+	}
+	return nil
+}
+
+func (x *GetQuestionAnalyticsResponse) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+// 错题条目
+type MistakeItem struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Question      *Question              `protobuf:"bytes,1,opt,name=question,proto3" json:"question,omitempty"`
+	WrongCount    int32                  `protobuf:"varint,2,opt,name=wrong_count,json=wrongCount,proto3" json:"wrong_count,omitempty"`          // 答错次数
+	CorrectStreak int32                  `protobuf:"varint,3,opt,name=correct_streak,json=correctStreak,proto3" json:"correct_streak,omitempty"` // 连续答对次数
+	Mastered      bool                   `protobuf:"varint,4,opt,name=mastered,proto3" json:"mastered,omitempty"`                                // 是否已掌握
+	LastWrongAt   *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=last_wrong_at,json=lastWrongAt,proto3" json:"last_wrong_at,omitempty"`
+	LastAttemptAt *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=last_attempt_at,json=lastAttemptAt,proto3" json:"last_attempt_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MistakeItem) Reset() {
+	*x = MistakeItem{}
+	mi := &file_quiz_quiz_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MistakeItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MistakeItem) ProtoMessage() {}
+
+func (x *MistakeItem) ProtoReflect() protoreflect.Message {
+	mi := &file_quiz_quiz_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MistakeItem.ProtoReflect.Descriptor instead.
+func (*MistakeItem) Descriptor() ([]byte, []int) {
+	return file_quiz_quiz_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *MistakeItem) GetQuestion() *Question {
+	if x != nil {
+		return x.Question
+	}
+	return nil
+}
+
+func (x *MistakeItem) GetWrongCount() int32 {
+	if x != nil {
+		return x.WrongCount
+	}
+	return 0
+}
+
+func (x *MistakeItem) GetCorrectStreak() int32 {
+	if x != nil {
+		return x.CorrectStreak
+	}
+	return 0
+}
+
+func (x *MistakeItem) GetMastered() bool {
+	if x != nil {
+		return x.Mastered
+	}
+	return false
+}
+
+func (x *MistakeItem) GetLastWrongAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastWrongAt
+	}
+	return nil
+}
+
+func (x *MistakeItem) GetLastAttemptAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastAttemptAt
+	}
+	return nil
+}
+
+// 按知识点分组的错题
+type MistakeGroup struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	KnowledgePoint string                 `protobuf:"bytes,1,opt,name=knowledge_point,json=knowledgePoint,proto3" json:"knowledge_point,omitempty"`
+	Items          []*MistakeItem         `protobuf:"bytes,2,rep,name=items,proto3" json:"items,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *MistakeGroup) Reset() {
+	*x = MistakeGroup{}
+	mi := &file_quiz_quiz_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MistakeGroup) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MistakeGroup) ProtoMessage() {}
+
+func (x *MistakeGroup) ProtoReflect() protoreflect.Message {
+	mi := &file_quiz_quiz_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MistakeGroup.ProtoReflect.Descriptor instead.
+func (*MistakeGroup) Descriptor() ([]byte, []int) {
+	return file_quiz_quiz_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *MistakeGroup) GetKnowledgePoint() string {
+	if x != nil {
+		return x.KnowledgePoint
+	}
+	return ""
+}
+
+func (x *MistakeGroup) GetItems() []*MistakeItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+// 获取错题本请求
+type ListMistakesRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	UserId          string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	MaterialId      string                 `protobuf:"bytes,2,opt,name=material_id,json=materialId,proto3" json:"material_id,omitempty"`                 // 可选，指定材料
+	IncludeMastered bool                   `protobuf:"varint,3,opt,name=include_mastered,json=includeMastered,proto3" json:"include_mastered,omitempty"` // 是否包含已掌握的题目
+	Page            int32                  `protobuf:"varint,4,opt,name=page,proto3" json:"page,omitempty"`
+	PageSize        int32                  `protobuf:"varint,5,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ListMistakesRequest) Reset() {
+	*x = ListMistakesRequest{}
+	mi := &file_quiz_quiz_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListMistakesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListMistakesRequest) ProtoMessage() {}
+
+func (x *ListMistakesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_quiz_quiz_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListMistakesRequest.ProtoReflect.Descriptor instead.
+func (*ListMistakesRequest) Descriptor() ([]byte, []int) {
+	return file_quiz_quiz_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *ListMistakesRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *ListMistakesRequest) GetMaterialId() string {
+	if x != nil {
+		return x.MaterialId
+	}
+	return ""
+}
+
+func (x *ListMistakesRequest) GetIncludeMastered() bool {
+	if x != nil {
+		return x.IncludeMastered
+	}
+	return false
+}
+
+func (x *ListMistakesRequest) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *ListMistakesRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+// 获取错题本响应
+type ListMistakesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Groups        []*MistakeGroup        `protobuf:"bytes,3,rep,name=groups,proto3" json:"groups,omitempty"`
+	Total         int32                  `protobuf:"varint,4,opt,name=total,proto3" json:"total,omitempty"` // 错题总数（不重复计算）
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListMistakesResponse) Reset() {
+	*x = ListMistakesResponse{}
+	mi := &file_quiz_quiz_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListMistakesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListMistakesResponse) ProtoMessage() {}
+
+func (x *ListMistakesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_quiz_quiz_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListMistakesResponse.ProtoReflect.Descriptor instead.
+func (*ListMistakesResponse) Descriptor() ([]byte, []int) {
+	return file_quiz_quiz_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *ListMistakesResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *ListMistakesResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *ListMistakesResponse) GetGroups() []*MistakeGroup {
+	if x != nil {
+		return x.Groups
+	}
+	return nil
+}
+
+func (x *ListMistakesResponse) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+// 获取重练题目请求
+type GetRetryQuestionsRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	UserId         string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	MaterialId     string                 `protobuf:"bytes,2,opt,name=material_id,json=materialId,proto3" json:"material_id,omitempty"`             // 可选，指定材料
+	KnowledgePoint string                 `protobuf:"bytes,3,opt,name=knowledge_point,json=knowledgePoint,proto3" json:"knowledge_point,omitempty"` // 可选，指定知识点
+	Count          int32                  `protobuf:"varint,4,opt,name=count,proto3" json:"count,omitempty"`                                        // 返回题目数量
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *GetRetryQuestionsRequest) Reset() {
+	*x = GetRetryQuestionsRequest{}
+	mi := &file_quiz_quiz_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRetryQuestionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRetryQuestionsRequest) ProtoMessage() {}
+
+func (x *GetRetryQuestionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_quiz_quiz_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRetryQuestionsRequest.ProtoReflect.Descriptor instead.
+func (*GetRetryQuestionsRequest) Descriptor() ([]byte, []int) {
+	return file_quiz_quiz_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *GetRetryQuestionsRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *GetRetryQuestionsRequest) GetMaterialId() string {
+	if x != nil {
+		return x.MaterialId
+	}
+	return ""
+}
+
+func (x *GetRetryQuestionsRequest) GetKnowledgePoint() string {
+	if x != nil {
+		return x.KnowledgePoint
+	}
+	return ""
+}
+
+func (x *GetRetryQuestionsRequest) GetCount() int32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+// 获取重练题目响应
+type GetRetryQuestionsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Items         []*MistakeItem         `protobuf:"bytes,3,rep,name=items,proto3" json:"items,omitempty"`
+	Remaining     int32                  `protobuf:"varint,4,opt,name=remaining,proto3" json:"remaining,omitempty"` // 尚未掌握的错题总数
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRetryQuestionsResponse) Reset() {
+	*x = GetRetryQuestionsResponse{}
+	mi := &file_quiz_quiz_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRetryQuestionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRetryQuestionsResponse) ProtoMessage() {}
+
+func (x *GetRetryQuestionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_quiz_quiz_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRetryQuestionsResponse.ProtoReflect.Descriptor instead.
+func (*GetRetryQuestionsResponse) Descriptor() ([]byte, []int) {
+	return file_quiz_quiz_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *GetRetryQuestionsResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *GetRetryQuestionsResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *GetRetryQuestionsResponse) GetItems() []*MistakeItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *GetRetryQuestionsResponse) GetRemaining() int32 {
+	if x != nil {
+		return x.Remaining
+	}
+	return 0
+}
+
+// 评分策略
+type GradingPolicy struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	ScopeType         string                 `protobuf:"bytes,1,opt,name=scope_type,json=scopeType,proto3" json:"scope_type,omitempty"`                           // global/course/material
+	ScopeId           string                 `protobuf:"bytes,2,opt,name=scope_id,json=scopeId,proto3" json:"scope_id,omitempty"`                                 // 材料ID或课程ID，全局策略为空
+	PassThreshold     float32                `protobuf:"fixed32,3,opt,name=pass_threshold,json=passThreshold,proto3" json:"pass_threshold,omitempty"`             // 及格线（0~1）
+	PartialCreditMode string                 `protobuf:"bytes,4,opt,name=partial_credit_mode,json=partialCreditMode,proto3" json:"partial_credit_mode,omitempty"` // weighted/equal/all_or_nothing
+	RetryPenalty      float32                `protobuf:"fixed32,5,opt,name=retry_penalty,json=retryPenalty,proto3" json:"retry_penalty,omitempty"`                // 每次重复作答扣减的得分比例
+	MaxRetryPenalty   float32                `protobuf:"fixed32,6,opt,name=max_retry_penalty,json=maxRetryPenalty,proto3" json:"max_retry_penalty,omitempty"`     // 累计扣减上限
+	UpdatedBy         string                 `protobuf:"bytes,7,opt,name=updated_by,json=updatedBy,proto3" json:"updated_by,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *GradingPolicy) Reset() {
+	*x = GradingPolicy{}
+	mi := &file_quiz_quiz_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GradingPolicy) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GradingPolicy) ProtoMessage() {}
+
+func (x *GradingPolicy) ProtoReflect() protoreflect.Message {
+	mi := &file_quiz_quiz_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GradingPolicy.ProtoReflect.Descriptor instead.
+func (*GradingPolicy) Descriptor() ([]byte, []int) {
+	return file_quiz_quiz_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *GradingPolicy) GetScopeType() string {
+	if x != nil {
+		return x.ScopeType
+	}
+	return ""
+}
+
+func (x *GradingPolicy) GetScopeId() string {
+	if x != nil {
+		return x.ScopeId
+	}
+	return ""
+}
+
+func (x *GradingPolicy) GetPassThreshold() float32 {
+	if x != nil {
+		return x.PassThreshold
+	}
+	return 0
+}
+
+func (x *GradingPolicy) GetPartialCreditMode() string {
+	if x != nil {
+		return x.PartialCreditMode
+	}
+	return ""
+}
+
+func (x *GradingPolicy) GetRetryPenalty() float32 {
+	if x != nil {
+		return x.RetryPenalty
+	}
+	return 0
+}
+
+func (x *GradingPolicy) GetMaxRetryPenalty() float32 {
+	if x != nil {
+		return x.MaxRetryPenalty
+	}
+	return 0
+}
+
+func (x *GradingPolicy) GetUpdatedBy() string {
+	if x != nil {
+		return x.UpdatedBy
+	}
+	return ""
+}
+
+// 获取评分策略请求
+type GetGradingPolicyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MaterialId    string                 `protobuf:"bytes,1,opt,name=material_id,json=materialId,proto3" json:"material_id,omitempty"` // 可选，为空时返回全局策略
+	CourseId      string                 `protobuf:"bytes,2,opt,name=course_id,json=courseId,proto3" json:"course_id,omitempty"`       // 可选，材料未配置策略时使用课程策略
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetGradingPolicyRequest) Reset() {
+	*x = GetGradingPolicyRequest{}
+	mi := &file_quiz_quiz_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetGradingPolicyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetGradingPolicyRequest) ProtoMessage() {}
+
+func (x *GetGradingPolicyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_quiz_quiz_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetGradingPolicyRequest.ProtoReflect.Descriptor instead.
+func (*GetGradingPolicyRequest) Descriptor() ([]byte, []int) {
+	return file_quiz_quiz_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *GetGradingPolicyRequest) GetMaterialId() string {
+	if x != nil {
+		return x.MaterialId
+	}
+	return ""
+}
+
+func (x *GetGradingPolicyRequest) GetCourseId() string {
+	if x != nil {
+		return x.CourseId
+	}
+	return ""
+}
+
+// 获取评分策略响应
+type GetGradingPolicyResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Policy        *GradingPolicy         `protobuf:"bytes,3,opt,name=policy,proto3" json:"policy,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetGradingPolicyResponse) Reset() {
+	*x = GetGradingPolicyResponse{}
+	mi := &file_quiz_quiz_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetGradingPolicyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetGradingPolicyResponse) ProtoMessage() {}
+
+func (x *GetGradingPolicyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_quiz_quiz_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetGradingPolicyResponse.ProtoReflect.Descriptor instead.
+func (*GetGradingPolicyResponse) Descriptor() ([]byte, []int) {
+	return file_quiz_quiz_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *GetGradingPolicyResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *GetGradingPolicyResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *GetGradingPolicyResponse) GetPolicy() *GradingPolicy {
+	if x != nil {
+		return x.Policy
+	}
+	return nil
+}
+
+// 设置评分策略请求
+type SetGradingPolicyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Policy        *GradingPolicy         `protobuf:"bytes,2,opt,name=policy,proto3" json:"policy,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetGradingPolicyRequest) Reset() {
+	*x = SetGradingPolicyRequest{}
+	mi := &file_quiz_quiz_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetGradingPolicyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetGradingPolicyRequest) ProtoMessage() {}
+
+func (x *SetGradingPolicyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_quiz_quiz_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetGradingPolicyRequest.ProtoReflect.Descriptor instead.
+func (*SetGradingPolicyRequest) Descriptor() ([]byte, []int) {
+	return file_quiz_quiz_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *SetGradingPolicyRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *SetGradingPolicyRequest) GetPolicy() *GradingPolicy {
+	if x != nil {
+		return x.Policy
+	}
+	return nil
+}
+
+// 设置评分策略响应
+type SetGradingPolicyResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Policy        *GradingPolicy         `protobuf:"bytes,3,opt,name=policy,proto3" json:"policy,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetGradingPolicyResponse) Reset() {
+	*x = SetGradingPolicyResponse{}
+	mi := &file_quiz_quiz_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetGradingPolicyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetGradingPolicyResponse) ProtoMessage() {}
+
+func (x *SetGradingPolicyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_quiz_quiz_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetGradingPolicyResponse.ProtoReflect.Descriptor instead.
+func (*SetGradingPolicyResponse) Descriptor() ([]byte, []int) {
+	return file_quiz_quiz_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *SetGradingPolicyResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *SetGradingPolicyResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *SetGradingPolicyResponse) GetPolicy() *GradingPolicy {
+	if x != nil {
+		return x.Policy
+	}
+	return nil
+}
+
+// 题目分享
+type QuizShare struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ShareId        string                 `protobuf:"bytes,1,opt,name=share_id,json=shareId,proto3" json:"share_id,omitempty"`
+	OwnerId        string                 `protobuf:"bytes,2,opt,name=owner_id,json=ownerId,proto3" json:"owner_id,omitempty"`
+	Title          string                 `protobuf:"bytes,3,opt,name=title,proto3" json:"title,omitempty"`
+	MaterialId     string                 `protobuf:"bytes,4,opt,name=material_id,json=materialId,proto3" json:"material_id,omitempty"`
+	QuestionIds    []string               `protobuf:"bytes,5,rep,name=question_ids,json=questionIds,proto3" json:"question_ids,omitempty"`
+	AllowAnonymous bool                   `protobuf:"varint,6,opt,name=allow_anonymous,json=allowAnonymous,proto3" json:"allow_anonymous,omitempty"` // 是否允许未登录访客填写昵称作答
+	ExpiresAt      *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	Revoked        bool                   `protobuf:"varint,8,opt,name=revoked,proto3" json:"revoked,omitempty"`
+	CreatedAt      *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	AttemptCount   int32                  `protobuf:"varint,10,opt,name=attempt_count,json=attemptCount,proto3" json:"attempt_count,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *QuizShare) Reset() {
+	*x = QuizShare{}
+	mi := &file_quiz_quiz_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QuizShare) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QuizShare) ProtoMessage() {}
+
+func (x *QuizShare) ProtoReflect() protoreflect.Message {
+	mi := &file_quiz_quiz_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QuizShare.ProtoReflect.Descriptor instead.
+func (*QuizShare) Descriptor() ([]byte, []int) {
+	return file_quiz_quiz_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *QuizShare) GetShareId() string {
+	if x != nil {
+		return x.ShareId
+	}
+	return ""
+}
+
+func (x *QuizShare) GetOwnerId() string {
+	if x != nil {
+		return x.OwnerId
+	}
+	return ""
+}
+
+func (x *QuizShare) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *QuizShare) GetMaterialId() string {
+	if x != nil {
+		return x.MaterialId
+	}
+	return ""
+}
+
+func (x *QuizShare) GetQuestionIds() []string {
+	if x != nil {
+		return x.QuestionIds
+	}
+	return nil
+}
+
+func (x *QuizShare) GetAllowAnonymous() bool {
+	if x != nil {
+		return x.AllowAnonymous
+	}
+	return false
+}
+
+func (x *QuizShare) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+func (x *QuizShare) GetRevoked() bool {
+	if x != nil {
+		return x.Revoked
+	}
+	return false
+}
+
+func (x *QuizShare) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *QuizShare) GetAttemptCount() int32 {
+	if x != nil {
+		return x.AttemptCount
+	}
+	return 0
+}
+
+// 创建分享请求，question_ids 为空时分享 material_id 下本人创建的全部题目
+type CreateQuizShareRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	UserId         string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Title          string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	MaterialId     string                 `protobuf:"bytes,3,opt,name=material_id,json=materialId,proto3" json:"material_id,omitempty"`
+	QuestionIds    []string               `protobuf:"bytes,4,rep,name=question_ids,json=questionIds,proto3" json:"question_ids,omitempty"`
+	AllowAnonymous bool                   `protobuf:"varint,5,opt,name=allow_anonymous,json=allowAnonymous,proto3" json:"allow_anonymous,omitempty"`
+	TtlSeconds     int64                  `protobuf:"varint,6,opt,name=ttl_seconds,json=ttlSeconds,proto3" json:"ttl_seconds,omitempty"` // 有效期，0 表示使用默认有效期
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *CreateQuizShareRequest) Reset() {
+	*x = CreateQuizShareRequest{}
+	mi := &file_quiz_quiz_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateQuizShareRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateQuizShareRequest) ProtoMessage() {}
+
+func (x *CreateQuizShareRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_quiz_quiz_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateQuizShareRequest.ProtoReflect.Descriptor instead.
+func (*CreateQuizShareRequest) Descriptor() ([]byte, []int) {
+	return file_quiz_quiz_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *CreateQuizShareRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *CreateQuizShareRequest) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *CreateQuizShareRequest) GetMaterialId() string {
+	if x != nil {
+		return x.MaterialId
+	}
+	return ""
+}
+
+func (x *CreateQuizShareRequest) GetQuestionIds() []string {
+	if x != nil {
+		return x.QuestionIds
+	}
+	return nil
+}
+
+func (x *CreateQuizShareRequest) GetAllowAnonymous() bool {
+	if x != nil {
+		return x.AllowAnonymous
+	}
+	return false
+}
+
+func (x *CreateQuizShareRequest) GetTtlSeconds() int64 {
+	if x != nil {
+		return x.TtlSeconds
+	}
+	return 0
+}
+
+type QuizShareResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Share         *QuizShare             `protobuf:"bytes,3,opt,name=share,proto3" json:"share,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *QuizShareResponse) Reset() {
+	*x = QuizShareResponse{}
+	mi := &file_quiz_quiz_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QuizShareResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QuizShareResponse) ProtoMessage() {}
+
+func (x *QuizShareResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_quiz_quiz_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QuizShareResponse.ProtoReflect.Descriptor instead.
+func (*QuizShareResponse) Descriptor() ([]byte, []int) {
+	return file_quiz_quiz_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *QuizShareResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *QuizShareResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *QuizShareResponse) GetShare() *QuizShare {
+	if x != nil {
+		return x.Share
+	}
+	return nil
+}
+
+// 查看分享请求，user_id 为空时为公开访问：分享须在有效期内，题目不含答案与解析
+type GetQuizShareRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ShareId       string                 `protobuf:"bytes,1,opt,name=share_id,json=shareId,proto3" json:"share_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetQuizShareRequest) Reset() {
+	*x = GetQuizShareRequest{}
+	mi := &file_quiz_quiz_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetQuizShareRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetQuizShareRequest) ProtoMessage() {}
+
+func (x *GetQuizShareRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_quiz_quiz_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetQuizShareRequest.ProtoReflect.Descriptor instead.
+func (*GetQuizShareRequest) Descriptor() ([]byte, []int) {
+	return file_quiz_quiz_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *GetQuizShareRequest) GetShareId() string {
+	if x != nil {
+		return x.ShareId
+	}
+	return ""
+}
+
+func (x *GetQuizShareRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type GetQuizShareResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Share         *QuizShare             `protobuf:"bytes,3,opt,name=share,proto3" json:"share,omitempty"`
+	Questions     []*Question            `protobuf:"bytes,4,rep,name=questions,proto3" json:"questions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetQuizShareResponse) Reset() {
+	*x = GetQuizShareResponse{}
+	mi := &file_quiz_quiz_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetQuizShareResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetQuizShareResponse) ProtoMessage() {}
+
+func (x *GetQuizShareResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_quiz_quiz_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetQuizShareResponse.ProtoReflect.Descriptor instead.
+func (*GetQuizShareResponse) Descriptor() ([]byte, []int) {
+	return file_quiz_quiz_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *GetQuizShareResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *GetQuizShareResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *GetQuizShareResponse) GetShare() *QuizShare {
+	if x != nil {
+		return x.Share
+	}
+	return nil
+}
+
+func (x *GetQuizShareResponse) GetQuestions() []*Question {
+	if x != nil {
+		return x.Questions
+	}
+	return nil
+}
+
+type ListQuizSharesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Page          int32                  `protobuf:"varint,2,opt,name=page,proto3" json:"page,omitempty"`
+	PageSize      int32                  `protobuf:"varint,3,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListQuizSharesRequest) Reset() {
+	*x = ListQuizSharesRequest{}
+	mi := &file_quiz_quiz_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListQuizSharesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListQuizSharesRequest) ProtoMessage() {}
+
+func (x *ListQuizSharesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_quiz_quiz_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListQuizSharesRequest.ProtoReflect.Descriptor instead.
+func (*ListQuizSharesRequest) Descriptor() ([]byte, []int) {
+	return file_quiz_quiz_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *ListQuizSharesRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *ListQuizSharesRequest) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *ListQuizSharesRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+type ListQuizSharesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Shares        []*QuizShare           `protobuf:"bytes,3,rep,name=shares,proto3" json:"shares,omitempty"`
+	Total         int32                  `protobuf:"varint,4,opt,name=total,proto3" json:"total,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListQuizSharesResponse) Reset() {
+	*x = ListQuizSharesResponse{}
+	mi := &file_quiz_quiz_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListQuizSharesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListQuizSharesResponse) ProtoMessage() {}
+
+func (x *ListQuizSharesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_quiz_quiz_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListQuizSharesResponse.ProtoReflect.Descriptor instead.
+func (*ListQuizSharesResponse) Descriptor() ([]byte, []int) {
+	return file_quiz_quiz_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *ListQuizSharesResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *ListQuizSharesResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *ListQuizSharesResponse) GetShares() []*QuizShare {
+	if x != nil {
+		return x.Shares
+	}
+	return nil
+}
+
+func (x *ListQuizSharesResponse) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+type RevokeQuizShareRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ShareId       string                 `protobuf:"bytes,1,opt,name=share_id,json=shareId,proto3" json:"share_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeQuizShareRequest) Reset() {
+	*x = RevokeQuizShareRequest{}
+	mi := &file_quiz_quiz_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeQuizShareRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeQuizShareRequest) ProtoMessage() {}
+
+func (x *RevokeQuizShareRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_quiz_quiz_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeQuizShareRequest.ProtoReflect.Descriptor instead.
+func (*RevokeQuizShareRequest) Descriptor() ([]byte, []int) {
+	return file_quiz_quiz_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *RevokeQuizShareRequest) GetShareId() string {
+	if x != nil {
+		return x.ShareId
+	}
+	return ""
+}
+
+func (x *RevokeQuizShareRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+// 分享作答中的一题
+type QuizShareAnswer struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	QuestionId    string                 `protobuf:"bytes,1,opt,name=question_id,json=questionId,proto3" json:"question_id,omitempty"`
+	Answer        string                 `protobuf:"bytes,2,opt,name=answer,proto3" json:"answer,omitempty"`
+	PartAnswers   []string               `protobuf:"bytes,3,rep,name=part_answers,json=partAnswers,proto3" json:"part_answers,omitempty"`
+	TimeSpentMs   int64                  `protobuf:"varint,4,opt,name=time_spent_ms,json=timeSpentMs,proto3" json:"time_spent_ms,omitempty"` // 本题作答耗时（毫秒），由前端上报
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *QuizShareAnswer) Reset() {
+	*x = QuizShareAnswer{}
+	mi := &file_quiz_quiz_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QuizShareAnswer) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QuizShareAnswer) ProtoMessage() {}
+
+func (x *QuizShareAnswer) ProtoReflect() protoreflect.Message {
+	mi := &file_quiz_quiz_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QuizShareAnswer.ProtoReflect.Descriptor instead.
+func (*QuizShareAnswer) Descriptor() ([]byte, []int) {
+	return file_quiz_quiz_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *QuizShareAnswer) GetQuestionId() string {
+	if x != nil {
+		return x.QuestionId
+	}
+	return ""
+}
+
+func (x *QuizShareAnswer) GetAnswer() string {
+	if x != nil {
+		return x.Answer
+	}
+	return ""
+}
+
+func (x *QuizShareAnswer) GetPartAnswers() []string {
+	if x != nil {
+		return x.PartAnswers
+	}
+	return nil
+}
+
+func (x *QuizShareAnswer) GetTimeSpentMs() int64 {
+	if x != nil {
+		return x.TimeSpentMs
+	}
+	return 0
+}
+
+// 分享作答中一题的评分
+type QuizShareAnswerResult struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	QuestionId      string                 `protobuf:"bytes,1,opt,name=question_id,json=questionId,proto3" json:"question_id,omitempty"`
+	Answer          string                 `protobuf:"bytes,2,opt,name=answer,proto3" json:"answer,omitempty"`
+	IsCorrect       bool                   `protobuf:"varint,3,opt,name=is_correct,json=isCorrect,proto3" json:"is_correct,omitempty"`
+	Score           float32                `protobuf:"fixed32,4,opt,name=score,proto3" json:"score,omitempty"`
+	QuestionVersion int32                  `protobuf:"varint,5,opt,name=question_version,json=questionVersion,proto3" json:"question_version,omitempty"` // 作答时的题目版本
+	TimeSpentMs     int64                  `protobuf:"varint,6,opt,name=time_spent_ms,json=timeSpentMs,proto3" json:"time_spent_ms,omitempty"`
+	Pending         bool                   `protobuf:"varint,7,opt,name=pending,proto3" json:"pending,omitempty"` // 匿名作答的主观题不评分，待分享者评阅
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *QuizShareAnswerResult) Reset() {
+	*x = QuizShareAnswerResult{}
+	mi := &file_quiz_quiz_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QuizShareAnswerResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QuizShareAnswerResult) ProtoMessage() {}
+
+func (x *QuizShareAnswerResult) ProtoReflect() protoreflect.Message {
+	mi := &file_quiz_quiz_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QuizShareAnswerResult.ProtoReflect.Descriptor instead.
+func (*QuizShareAnswerResult) Descriptor() ([]byte, []int) {
+	return file_quiz_quiz_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *QuizShareAnswerResult) GetQuestionId() string {
+	if x != nil {
+		return x.QuestionId
+	}
+	return ""
+}
+
+func (x *QuizShareAnswerResult) GetAnswer() string {
+	if x != nil {
+		return x.Answer
+	}
+	return ""
+}
+
+func (x *QuizShareAnswerResult) GetIsCorrect() bool {
+	if x != nil {
+		return x.IsCorrect
+	}
+	return false
+}
+
+func (x *QuizShareAnswerResult) GetScore() float32 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+func (x *QuizShareAnswerResult) GetQuestionVersion() int32 {
+	if x != nil {
+		return x.QuestionVersion
+	}
+	return 0
+}
+
+func (x *QuizShareAnswerResult) GetTimeSpentMs() int64 {
+	if x != nil {
+		return x.TimeSpentMs
+	}
+	return 0
+}
+
+func (x *QuizShareAnswerResult) GetPending() bool {
+	if x != nil {
+		return x.Pending
+	}
+	return false
+}
+
+// 分享作答记录，匿名作答时 user_id 为空
+type QuizShareAttempt struct {
+	state          protoimpl.MessageState   `protogen:"open.v1"`
+	AttemptId      string                   `protobuf:"bytes,1,opt,name=attempt_id,json=attemptId,proto3" json:"attempt_id,omitempty"`
+	ShareId        string                   `protobuf:"bytes,2,opt,name=share_id,json=shareId,proto3" json:"share_id,omitempty"`
+	UserId         string                   `protobuf:"bytes,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Nickname       string                   `protobuf:"bytes,4,opt,name=nickname,proto3" json:"nickname,omitempty"`
+	Score          float32                  `protobuf:"fixed32,5,opt,name=score,proto3" json:"score,omitempty"` // 各题平均得分（0~1）
+	CorrectCount   int32                    `protobuf:"varint,6,opt,name=correct_count,json=correctCount,proto3" json:"correct_count,omitempty"`
+	TotalQuestions int32                    `protobuf:"varint,7,opt,name=total_questions,json=totalQuestions,proto3" json:"total_questions,omitempty"`
+	Results        []*QuizShareAnswerResult `protobuf:"bytes,8,rep,name=results,proto3" json:"results,omitempty"`
+	SubmittedAt    *timestamppb.Timestamp   `protobuf:"bytes,9,opt,name=submitted_at,json=submittedAt,proto3" json:"submitted_at,omitempty"`
+	Telemetry      *AttemptTelemetry        `protobuf:"bytes,10,opt,name=telemetry,proto3" json:"telemetry,omitempty"` // 仅创建者查看作答记录时返回
+	Flags          []*AttemptFlag           `protobuf:"bytes,11,rep,name=flags,proto3" json:"flags,omitempty"`         // 异常作答标记，仅创建者查看作答记录时返回
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *QuizShareAttempt) Reset() {
+	*x = QuizShareAttempt{}
+	mi := &file_quiz_quiz_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QuizShareAttempt) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QuizShareAttempt) ProtoMessage() {}
+
+func (x *QuizShareAttempt) ProtoReflect() protoreflect.Message {
+	mi := &file_quiz_quiz_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QuizShareAttempt.ProtoReflect.Descriptor instead.
+func (*QuizShareAttempt) Descriptor() ([]byte, []int) {
+	return file_quiz_quiz_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *QuizShareAttempt) GetAttemptId() string {
+	if x != nil {
+		return x.AttemptId
+	}
+	return ""
+}
+
+func (x *QuizShareAttempt) GetShareId() string {
+	if x != nil {
+		return x.ShareId
+	}
+	return ""
+}
+
+func (x *QuizShareAttempt) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *QuizShareAttempt) GetNickname() string {
+	if x != nil {
+		return x.Nickname
+	}
+	return ""
+}
+
+func (x *QuizShareAttempt) GetScore() float32 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+func (x *QuizShareAttempt) GetCorrectCount() int32 {
+	if x != nil {
+		return x.CorrectCount
+	}
+	return 0
+}
+
+func (x *QuizShareAttempt) GetTotalQuestions() int32 {
+	if x != nil {
+		return x.TotalQuestions
+	}
+	return 0
+}
+
+func (x *QuizShareAttempt) GetResults() []*QuizShareAnswerResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+func (x *QuizShareAttempt) GetSubmittedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.SubmittedAt
+	}
+	return nil
+}
+
+func (x *QuizShareAttempt) GetTelemetry() *AttemptTelemetry {
+	if x != nil {
+		return x.Telemetry
+	}
+	return nil
+}
+
+func (x *QuizShareAttempt) GetFlags() []*AttemptFlag {
+	if x != nil {
+		return x.Flags
+	}
+	return nil
+}
+
+// 作答过程的前端埋点，时间均为距开始作答的毫秒数
+type AttemptTelemetry struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DurationMs    int64                  `protobuf:"varint,1,opt,name=duration_ms,json=durationMs,proto3" json:"duration_ms,omitempty"` // 开始作答到提交的总耗时
+	FocusEvents   []*FocusEvent          `protobuf:"bytes,2,rep,name=focus_events,json=focusEvents,proto3" json:"focus_events,omitempty"`
+	PasteEvents   []*PasteEvent          `protobuf:"bytes,3,rep,name=paste_events,json=pasteEvents,proto3" json:"paste_events,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AttemptTelemetry) Reset() {
+	*x = AttemptTelemetry{}
+	mi := &file_quiz_quiz_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AttemptTelemetry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AttemptTelemetry) ProtoMessage() {}
+
+func (x *AttemptTelemetry) ProtoReflect() protoreflect.Message {
+	mi := &file_quiz_quiz_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AttemptTelemetry.ProtoReflect.Descriptor instead.
+func (*AttemptTelemetry) Descriptor() ([]byte, []int) {
+	return file_quiz_quiz_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *AttemptTelemetry) GetDurationMs() int64 {
+	if x != nil {
+		return x.DurationMs
+	}
+	return 0
+}
+
+func (x *AttemptTelemetry) GetFocusEvents() []*FocusEvent {
+	if x != nil {
+		return x.FocusEvents
+	}
+	return nil
+}
+
+func (x *AttemptTelemetry) GetPasteEvents() []*PasteEvent {
+	if x != nil {
+		return x.PasteEvents
+	}
+	return nil
+}
+
+// 一次离开作答页面（切换标签页、窗口失焦等）
+type FocusEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	QuestionId    string                 `protobuf:"bytes,1,opt,name=question_id,json=questionId,proto3" json:"question_id,omitempty"` // 离开时所在的题目
+	OffsetMs      int64                  `protobuf:"varint,2,opt,name=offset_ms,json=offsetMs,proto3" json:"offset_ms,omitempty"`
+	AwayMs        int64                  `protobuf:"varint,3,opt,name=away_ms,json=awayMs,proto3" json:"away_ms,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FocusEvent) Reset() {
+	*x = FocusEvent{}
+	mi := &file_quiz_quiz_proto_msgTypes[49]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FocusEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FocusEvent) ProtoMessage() {}
+
+func (x *FocusEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_quiz_quiz_proto_msgTypes[49]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FocusEvent.ProtoReflect.Descriptor instead.
+func (*FocusEvent) Descriptor() ([]byte, []int) {
+	return file_quiz_quiz_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *FocusEvent) GetQuestionId() string {
+	if x != nil {
+		return x.QuestionId
+	}
+	return ""
+}
+
+func (x *FocusEvent) GetOffsetMs() int64 {
+	if x != nil {
+		return x.OffsetMs
+	}
+	return 0
+}
+
+func (x *FocusEvent) GetAwayMs() int64 {
+	if x != nil {
+		return x.AwayMs
+	}
+	return 0
+}
+
+// 一次向答案粘贴内容
+type PasteEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	QuestionId    string                 `protobuf:"bytes,1,opt,name=question_id,json=questionId,proto3" json:"question_id,omitempty"`
+	OffsetMs      int64                  `protobuf:"varint,2,opt,name=offset_ms,json=offsetMs,proto3" json:"offset_ms,omitempty"`
+	Length        int32                  `protobuf:"varint,3,opt,name=length,proto3" json:"length,omitempty"` // 粘贴的字符数
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PasteEvent) Reset() {
+	*x = PasteEvent{}
+	mi := &file_quiz_quiz_proto_msgTypes[50]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PasteEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PasteEvent) ProtoMessage() {}
+
+func (x *PasteEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_quiz_quiz_proto_msgTypes[50]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PasteEvent.ProtoReflect.Descriptor instead.
+func (*PasteEvent) Descriptor() ([]byte, []int) {
+	return file_quiz_quiz_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *PasteEvent) GetQuestionId() string {
+	if x != nil {
+		return x.QuestionId
+	}
+	return ""
+}
+
+func (x *PasteEvent) GetOffsetMs() int64 {
+	if x != nil {
+		return x.OffsetMs
+	}
+	return 0
+}
+
+func (x *PasteEvent) GetLength() int32 {
+	if x != nil {
+		return x.Length
+	}
+	return 0
+}
+
+// 异常作答标记，type 为 focus_loss / long_focus_loss / paste / fast_answer，question_id 为空时针对整份作答
+type AttemptFlag struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          string                 `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	QuestionId    string                 `protobuf:"bytes,2,opt,name=question_id,json=questionId,proto3" json:"question_id,omitempty"`
+	Detail        string                 `protobuf:"bytes,3,opt,name=detail,proto3" json:"detail,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AttemptFlag) Reset() {
+	*x = AttemptFlag{}
+	mi := &file_quiz_quiz_proto_msgTypes[51]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AttemptFlag) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AttemptFlag) ProtoMessage() {}
+
+func (x *AttemptFlag) ProtoReflect() protoreflect.Message {
+	mi := &file_quiz_quiz_proto_msgTypes[51]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AttemptFlag.ProtoReflect.Descriptor instead.
+func (*AttemptFlag) Descriptor() ([]byte, []int) {
+	return file_quiz_quiz_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *AttemptFlag) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *AttemptFlag) GetQuestionId() string {
+	if x != nil {
+		return x.QuestionId
+	}
+	return ""
+}
+
+func (x *AttemptFlag) GetDetail() string {
+	if x != nil {
+		return x.Detail
+	}
+	return ""
+}
+
+// 提交分享作答请求，未作答的题目按 0 分计
+type SubmitQuizShareAttemptRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ShareId       string                 `protobuf:"bytes,1,opt,name=share_id,json=shareId,proto3" json:"share_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // 登录用户作答时非空
+	Nickname      string                 `protobuf:"bytes,3,opt,name=nickname,proto3" json:"nickname,omitempty"`           // 匿名作答时必填
+	Answers       []*QuizShareAnswer     `protobuf:"bytes,4,rep,name=answers,proto3" json:"answers,omitempty"`
+	Telemetry     *AttemptTelemetry      `protobuf:"bytes,5,opt,name=telemetry,proto3" json:"telemetry,omitempty"` // 前端上报的作答埋点，可选
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubmitQuizShareAttemptRequest) Reset() {
+	*x = SubmitQuizShareAttemptRequest{}
+	mi := &file_quiz_quiz_proto_msgTypes[52]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubmitQuizShareAttemptRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubmitQuizShareAttemptRequest) ProtoMessage() {}
+
+func (x *SubmitQuizShareAttemptRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_quiz_quiz_proto_msgTypes[52]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubmitQuizShareAttemptRequest.ProtoReflect.Descriptor instead.
+func (*SubmitQuizShareAttemptRequest) Descriptor() ([]byte, []int) {
+	return file_quiz_quiz_proto_rawDescGZIP(), []int{52}
+}
+
+func (x *SubmitQuizShareAttemptRequest) GetShareId() string {
+	if x != nil {
+		return x.ShareId
+	}
+	return ""
+}
+
+func (x *SubmitQuizShareAttemptRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *SubmitQuizShareAttemptRequest) GetNickname() string {
+	if x != nil {
+		return x.Nickname
+	}
+	return ""
+}
+
+func (x *SubmitQuizShareAttemptRequest) GetAnswers() []*QuizShareAnswer {
+	if x != nil {
+		return x.Answers
+	}
+	return nil
+}
+
+func (x *SubmitQuizShareAttemptRequest) GetTelemetry() *AttemptTelemetry {
+	if x != nil {
+		return x.Telemetry
+	}
+	return nil
+}
+
+type SubmitQuizShareAttemptResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Attempt       *QuizShareAttempt      `protobuf:"bytes,3,opt,name=attempt,proto3" json:"attempt,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubmitQuizShareAttemptResponse) Reset() {
+	*x = SubmitQuizShareAttemptResponse{}
+	mi := &file_quiz_quiz_proto_msgTypes[53]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubmitQuizShareAttemptResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubmitQuizShareAttemptResponse) ProtoMessage() {}
+
+func (x *SubmitQuizShareAttemptResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_quiz_quiz_proto_msgTypes[53]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubmitQuizShareAttemptResponse.ProtoReflect.Descriptor instead.
+func (*SubmitQuizShareAttemptResponse) Descriptor() ([]byte, []int) {
+	return file_quiz_quiz_proto_rawDescGZIP(), []int{53}
+}
+
+func (x *SubmitQuizShareAttemptResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *SubmitQuizShareAttemptResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *SubmitQuizShareAttemptResponse) GetAttempt() *QuizShareAttempt {
+	if x != nil {
+		return x.Attempt
+	}
+	return nil
+}
+
+// 查看分享的作答记录，仅创建者可查看
+type ListQuizShareAttemptsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ShareId       string                 `protobuf:"bytes,1,opt,name=share_id,json=shareId,proto3" json:"share_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Page          int32                  `protobuf:"varint,3,opt,name=page,proto3" json:"page,omitempty"`
+	PageSize      int32                  `protobuf:"varint,4,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	FlaggedOnly   bool                   `protobuf:"varint,5,opt,name=flagged_only,json=flaggedOnly,proto3" json:"flagged_only,omitempty"` // 只返回有异常标记的作答
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListQuizShareAttemptsRequest) Reset() {
+	*x = ListQuizShareAttemptsRequest{}
+	mi := &file_quiz_quiz_proto_msgTypes[54]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListQuizShareAttemptsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListQuizShareAttemptsRequest) ProtoMessage() {}
+
+func (x *ListQuizShareAttemptsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_quiz_quiz_proto_msgTypes[54]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListQuizShareAttemptsRequest.ProtoReflect.Descriptor instead.
+func (*ListQuizShareAttemptsRequest) Descriptor() ([]byte, []int) {
+	return file_quiz_quiz_proto_rawDescGZIP(), []int{54}
+}
+
+func (x *ListQuizShareAttemptsRequest) GetShareId() string {
+	if x != nil {
+		return x.ShareId
+	}
+	return ""
+}
+
+func (x *ListQuizShareAttemptsRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *ListQuizShareAttemptsRequest) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *ListQuizShareAttemptsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListQuizShareAttemptsRequest) GetFlaggedOnly() bool {
+	if x != nil {
+		return x.FlaggedOnly
+	}
+	return false
+}
+
+type ListQuizShareAttemptsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Attempts      []*QuizShareAttempt    `protobuf:"bytes,3,rep,name=attempts,proto3" json:"attempts,omitempty"`
+	Total         int32                  `protobuf:"varint,4,opt,name=total,proto3" json:"total,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListQuizShareAttemptsResponse) Reset() {
+	*x = ListQuizShareAttemptsResponse{}
+	mi := &file_quiz_quiz_proto_msgTypes[55]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListQuizShareAttemptsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListQuizShareAttemptsResponse) ProtoMessage() {}
+
+func (x *ListQuizShareAttemptsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_quiz_quiz_proto_msgTypes[55]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListQuizShareAttemptsResponse.ProtoReflect.Descriptor instead.
+func (*ListQuizShareAttemptsResponse) Descriptor() ([]byte, []int) {
+	return file_quiz_quiz_proto_rawDescGZIP(), []int{55}
+}
+
+func (x *ListQuizShareAttemptsResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *ListQuizShareAttemptsResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *ListQuizShareAttemptsResponse) GetAttempts() []*QuizShareAttempt {
+	if x != nil {
+		return x.Attempts
+	}
+	return nil
+}
+
+func (x *ListQuizShareAttemptsResponse) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+// 提交审核并指定审核人，也可改派待审核题目的审核人；只能提交本人创建的课程题目
+type AssignQuestionReviewerRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	QuestionIds   []string               `protobuf:"bytes,2,rep,name=question_ids,json=questionIds,proto3" json:"question_ids,omitempty"`
+	ReviewerId    string                 `protobuf:"bytes,3,opt,name=reviewer_id,json=reviewerId,proto3" json:"reviewer_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AssignQuestionReviewerRequest) Reset() {
+	*x = AssignQuestionReviewerRequest{}
+	mi := &file_quiz_quiz_proto_msgTypes[56]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AssignQuestionReviewerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AssignQuestionReviewerRequest) ProtoMessage() {}
+
+func (x *AssignQuestionReviewerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_quiz_quiz_proto_msgTypes[56]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AssignQuestionReviewerRequest.ProtoReflect.Descriptor instead.
+func (*AssignQuestionReviewerRequest) Descriptor() ([]byte, []int) {
+	return file_quiz_quiz_proto_rawDescGZIP(), []int{56}
+}
+
+func (x *AssignQuestionReviewerRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *AssignQuestionReviewerRequest) GetQuestionIds() []string {
+	if x != nil {
+		return x.QuestionIds
+	}
+	return nil
+}
+
+func (x *AssignQuestionReviewerRequest) GetReviewerId() string {
+	if x != nil {
+		return x.ReviewerId
+	}
+	return ""
+}
+
+// 审核单道题目，驳回时 comment 必填
+type ReviewQuestionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // 审核人
+	QuestionId    string                 `protobuf:"bytes,2,opt,name=question_id,json=questionId,proto3" json:"question_id,omitempty"`
+	Approve       bool                   `protobuf:"varint,3,opt,name=approve,proto3" json:"approve,omitempty"`
+	Comment       string                 `protobuf:"bytes,4,opt,name=comment,proto3" json:"comment,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReviewQuestionRequest) Reset() {
+	*x = ReviewQuestionRequest{}
+	mi := &file_quiz_quiz_proto_msgTypes[57]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReviewQuestionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReviewQuestionRequest) ProtoMessage() {}
+
+func (x *ReviewQuestionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_quiz_quiz_proto_msgTypes[57]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReviewQuestionRequest.ProtoReflect.Descriptor instead.
+func (*ReviewQuestionRequest) Descriptor() ([]byte, []int) {
+	return file_quiz_quiz_proto_rawDescGZIP(), []int{57}
+}
+
+func (x *ReviewQuestionRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *ReviewQuestionRequest) GetQuestionId() string {
+	if x != nil {
+		return x.QuestionId
+	}
+	return ""
+}
+
+func (x *ReviewQuestionRequest) GetApprove() bool {
+	if x != nil {
+		return x.Approve
+	}
+	return false
+}
+
+func (x *ReviewQuestionRequest) GetComment() string {
+	if x != nil {
+		return x.Comment
+	}
+	return ""
+}
+
+// 批量通过，question_ids 为空时通过审核人在 course_id 下待审核的全部题目
+type BulkApproveQuestionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // 审核人
+	CourseId      string                 `protobuf:"bytes,2,opt,name=course_id,json=courseId,proto3" json:"course_id,omitempty"`
+	QuestionIds   []string               `protobuf:"bytes,3,rep,name=question_ids,json=questionIds,proto3" json:"question_ids,omitempty"`
+	Comment       string                 `protobuf:"bytes,4,opt,name=comment,proto3" json:"comment,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BulkApproveQuestionsRequest) Reset() {
+	*x = BulkApproveQuestionsRequest{}
+	mi := &file_quiz_quiz_proto_msgTypes[58]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BulkApproveQuestionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BulkApproveQuestionsRequest) ProtoMessage() {}
+
+func (x *BulkApproveQuestionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_quiz_quiz_proto_msgTypes[58]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BulkApproveQuestionsRequest.ProtoReflect.Descriptor instead.
+func (*BulkApproveQuestionsRequest) Descriptor() ([]byte, []int) {
+	return file_quiz_quiz_proto_rawDescGZIP(), []int{58}
+}
+
+func (x *BulkApproveQuestionsRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *BulkApproveQuestionsRequest) GetCourseId() string {
+	if x != nil {
+		return x.CourseId
+	}
+	return ""
+}
+
+func (x *BulkApproveQuestionsRequest) GetQuestionIds() []string {
+	if x != nil {
+		return x.QuestionIds
+	}
+	return nil
+}
+
+func (x *BulkApproveQuestionsRequest) GetComment() string {
+	if x != nil {
+		return x.Comment
+	}
+	return ""
+}
+
+// 未处理的题目及原因
+type ReviewSkip struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	QuestionId    string                 `protobuf:"bytes,1,opt,name=question_id,json=questionId,proto3" json:"question_id,omitempty"`
+	Reason        string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReviewSkip) Reset() {
+	*x = ReviewSkip{}
+	mi := &file_quiz_quiz_proto_msgTypes[59]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReviewSkip) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReviewSkip) ProtoMessage() {}
+
+func (x *ReviewSkip) ProtoReflect() protoreflect.Message {
+	mi := &file_quiz_quiz_proto_msgTypes[59]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReviewSkip.ProtoReflect.Descriptor instead.
+func (*ReviewSkip) Descriptor() ([]byte, []int) {
+	return file_quiz_quiz_proto_rawDescGZIP(), []int{59}
+}
+
+func (x *ReviewSkip) GetQuestionId() string {
+	if x != nil {
+		return x.QuestionId
+	}
+	return ""
+}
+
+func (x *ReviewSkip) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type ReviewQuestionsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Questions     []*Question            `protobuf:"bytes,3,rep,name=questions,proto3" json:"questions,omitempty"` // 处理后的题目
+	Skipped       []*ReviewSkip          `protobuf:"bytes,4,rep,name=skipped,proto3" json:"skipped,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReviewQuestionsResponse) Reset() {
+	*x = ReviewQuestionsResponse{}
+	mi := &file_quiz_quiz_proto_msgTypes[60]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReviewQuestionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReviewQuestionsResponse) ProtoMessage() {}
+
+func (x *ReviewQuestionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_quiz_quiz_proto_msgTypes[60]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReviewQuestionsResponse.ProtoReflect.Descriptor instead.
+func (*ReviewQuestionsResponse) Descriptor() ([]byte, []int) {
+	return file_quiz_quiz_proto_rawDescGZIP(), []int{60}
+}
+
+func (x *ReviewQuestionsResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *ReviewQuestionsResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *ReviewQuestionsResponse) GetQuestions() []*Question {
+	if x != nil {
+		return x.Questions
+	}
+	return nil
+}
+
+func (x *ReviewQuestionsResponse) GetSkipped() []*ReviewSkip {
+	if x != nil {
+		return x.Skipped
+	}
+	return nil
+}
+
+// 审核人的审核队列，status 为空时只返回待审核的题目
+type ListReviewQueueRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	CourseId      string                 `protobuf:"bytes,2,opt,name=course_id,json=courseId,proto3" json:"course_id,omitempty"`
+	Status        string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
+	Page          int32                  `protobuf:"varint,4,opt,name=page,proto3" json:"page,omitempty"`
+	PageSize      int32                  `protobuf:"varint,5,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListReviewQueueRequest) Reset() {
+	*x = ListReviewQueueRequest{}
+	mi := &file_quiz_quiz_proto_msgTypes[61]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListReviewQueueRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListReviewQueueRequest) ProtoMessage() {}
+
+func (x *ListReviewQueueRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_quiz_quiz_proto_msgTypes[61]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListReviewQueueRequest.ProtoReflect.Descriptor instead.
+func (*ListReviewQueueRequest) Descriptor() ([]byte, []int) {
+	return file_quiz_quiz_proto_rawDescGZIP(), []int{61}
+}
+
+func (x *ListReviewQueueRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *ListReviewQueueRequest) GetCourseId() string {
+	if x != nil {
+		return x.CourseId
+	}
+	return ""
+}
+
+func (x *ListReviewQueueRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ListReviewQueueRequest) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *ListReviewQueueRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+type ListReviewQueueResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Questions     []*Question            `protobuf:"bytes,3,rep,name=questions,proto3" json:"questions,omitempty"`
+	Total         int32                  `protobuf:"varint,4,opt,name=total,proto3" json:"total,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListReviewQueueResponse) Reset() {
+	*x = ListReviewQueueResponse{}
+	mi := &file_quiz_quiz_proto_msgTypes[62]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListReviewQueueResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListReviewQueueResponse) ProtoMessage() {}
+
+func (x *ListReviewQueueResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_quiz_quiz_proto_msgTypes[62]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListReviewQueueResponse.ProtoReflect.Descriptor instead.
+func (*ListReviewQueueResponse) Descriptor() ([]byte, []int) {
+	return file_quiz_quiz_proto_rawDescGZIP(), []int{62}
+}
+
+func (x *ListReviewQueueResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *ListReviewQueueResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *ListReviewQueueResponse) GetQuestions() []*Question {
+	if x != nil {
+		return x.Questions
+	}
+	return nil
+}
+
+func (x *ListReviewQueueResponse) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+// 编辑题目，整体替换可编辑字段（题型与出题依据不可编辑）；expected_version 非 0 时须与当前版本一致
+type UpdateQuestionRequest struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	UserId           string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	QuestionId       string                 `protobuf:"bytes,2,opt,name=question_id,json=questionId,proto3" json:"question_id,omitempty"`
+	ExpectedVersion  int32                  `protobuf:"varint,3,opt,name=expected_version,json=expectedVersion,proto3" json:"expected_version,omitempty"`
+	Content          string                 `protobuf:"bytes,4,opt,name=content,proto3" json:"content,omitempty"`
+	Options          []string               `protobuf:"bytes,5,rep,name=options,proto3" json:"options,omitempty"`
+	CorrectAnswer    string                 `protobuf:"bytes,6,opt,name=correct_answer,json=correctAnswer,proto3" json:"correct_answer,omitempty"`
+	AnswerAliases    []string               `protobuf:"bytes,7,rep,name=answer_aliases,json=answerAliases,proto3" json:"answer_aliases,omitempty"`
+	NumericTolerance float64                `protobuf:"fixed64,8,opt,name=numeric_tolerance,json=numericTolerance,proto3" json:"numeric_tolerance,omitempty"`
+	Parts            []*QuestionPart        `protobuf:"bytes,9,rep,name=parts,proto3" json:"parts,omitempty"`
+	Explanation      string                 `protobuf:"bytes,10,opt,name=explanation,proto3" json:"explanation,omitempty"`
+	Difficulty       DifficultyLevel        `protobuf:"varint,11,opt,name=difficulty,proto3,enum=quiz.DifficultyLevel" json:"difficulty,omitempty"`
+	KnowledgePoints  []string               `protobuf:"bytes,12,rep,name=knowledge_points,json=knowledgePoints,proto3" json:"knowledge_points,omitempty"`
+	ChangeNote       string                 `protobuf:"bytes,13,opt,name=change_note,json=changeNote,proto3" json:"change_note,omitempty"` // 修改说明，记录在新版本中
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *UpdateQuestionRequest) Reset() {
+	*x = UpdateQuestionRequest{}
+	mi := &file_quiz_quiz_proto_msgTypes[63]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateQuestionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateQuestionRequest) ProtoMessage() {}
+
+func (x *UpdateQuestionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_quiz_quiz_proto_msgTypes[63]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateQuestionRequest.ProtoReflect.Descriptor instead.
+func (*UpdateQuestionRequest) Descriptor() ([]byte, []int) {
+	return file_quiz_quiz_proto_rawDescGZIP(), []int{63}
+}
+
+func (x *UpdateQuestionRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *UpdateQuestionRequest) GetQuestionId() string {
+	if x != nil {
+		return x.QuestionId
+	}
+	return ""
+}
+
+func (x *UpdateQuestionRequest) GetExpectedVersion() int32 {
+	if x != nil {
+		return x.ExpectedVersion
+	}
+	return 0
+}
+
+func (x *UpdateQuestionRequest) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+func (x *UpdateQuestionRequest) GetOptions() []string {
+	if x != nil {
+		return x.Options
+	}
+	return nil
+}
+
+func (x *UpdateQuestionRequest) GetCorrectAnswer() string {
+	if x != nil {
+		return x.CorrectAnswer
+	}
+	return ""
+}
+
+func (x *UpdateQuestionRequest) GetAnswerAliases() []string {
+	if x != nil {
+		return x.AnswerAliases
+	}
+	return nil
+}
+
+func (x *UpdateQuestionRequest) GetNumericTolerance() float64 {
+	if x != nil {
+		return x.NumericTolerance
+	}
+	return 0
+}
+
+func (x *UpdateQuestionRequest) GetParts() []*QuestionPart {
+	if x != nil {
+		return x.Parts
+	}
+	return nil
+}
+
+func (x *UpdateQuestionRequest) GetExplanation() string {
+	if x != nil {
+		return x.Explanation
+	}
+	return ""
+}
+
+func (x *UpdateQuestionRequest) GetDifficulty() DifficultyLevel {
+	if x != nil {
+		return x.Difficulty
+	}
+	return DifficultyLevel_EASY
+}
+
+func (x *UpdateQuestionRequest) GetKnowledgePoints() []string {
+	if x != nil {
+		return x.KnowledgePoints
+	}
+	return nil
+}
+
+func (x *UpdateQuestionRequest) GetChangeNote() string {
+	if x != nil {
+		return x.ChangeNote
+	}
+	return ""
+}
+
+type UpdateQuestionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Question      *Question              `protobuf:"bytes,3,opt,name=question,proto3" json:"question,omitempty"`
+	ChangedFields []string               `protobuf:"bytes,4,rep,name=changed_fields,json=changedFields,proto3" json:"changed_fields,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateQuestionResponse) Reset() {
+	*x = UpdateQuestionResponse{}
+	mi := &file_quiz_quiz_proto_msgTypes[64]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateQuestionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateQuestionResponse) ProtoMessage() {}
+
+func (x *UpdateQuestionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_quiz_quiz_proto_msgTypes[64]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateQuestionResponse.ProtoReflect.Descriptor instead.
+func (*UpdateQuestionResponse) Descriptor() ([]byte, []int) {
+	return file_quiz_quiz_proto_rawDescGZIP(), []int{64}
+}
+
+func (x *UpdateQuestionResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *UpdateQuestionResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *UpdateQuestionResponse) GetQuestion() *Question {
+	if x != nil {
+		return x.Question
+	}
+	return nil
+}
+
+func (x *UpdateQuestionResponse) GetChangedFields() []string {
+	if x != nil {
+		return x.ChangedFields
+	}
+	return nil
+}
+
+// 题目的不可变版本
+type QuestionVersion struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	QuestionId       string                 `protobuf:"bytes,1,opt,name=question_id,json=questionId,proto3" json:"question_id,omitempty"`
+	Version          int32                  `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+	Type             QuestionType           `protobuf:"varint,3,opt,name=type,proto3,enum=quiz.QuestionType" json:"type,omitempty"`
+	Content          string                 `protobuf:"bytes,4,opt,name=content,proto3" json:"content,omitempty"`
+	Options          []string               `protobuf:"bytes,5,rep,name=options,proto3" json:"options,omitempty"`
+	CorrectAnswer    string                 `protobuf:"bytes,6,opt,name=correct_answer,json=correctAnswer,proto3" json:"correct_answer,omitempty"`
+	AnswerAliases    []string               `protobuf:"bytes,7,rep,name=answer_aliases,json=answerAliases,proto3" json:"answer_aliases,omitempty"`
+	NumericTolerance float64                `protobuf:"fixed64,8,opt,name=numeric_tolerance,json=numericTolerance,proto3" json:"numeric_tolerance,omitempty"`
+	Parts            []*QuestionPart        `protobuf:"bytes,9,rep,name=parts,proto3" json:"parts,omitempty"`
+	Explanation      string                 `protobuf:"bytes,10,opt,name=explanation,proto3" json:"explanation,omitempty"`
+	Difficulty       DifficultyLevel        `protobuf:"varint,11,opt,name=difficulty,proto3,enum=quiz.DifficultyLevel" json:"difficulty,omitempty"`
+	KnowledgePoints  []string               `protobuf:"bytes,12,rep,name=knowledge_points,json=knowledgePoints,proto3" json:"knowledge_points,omitempty"`
+	EditorId         string                 `protobuf:"bytes,13,opt,name=editor_id,json=editorId,proto3" json:"editor_id,omitempty"`
+	ChangedFields    []string               `protobuf:"bytes,14,rep,name=changed_fields,json=changedFields,proto3" json:"changed_fields,omitempty"` // 相对上一版本修改的字段，首个版本为空
+	ChangeNote       string                 `protobuf:"bytes,15,opt,name=change_note,json=changeNote,proto3" json:"change_note,omitempty"`
+	CreatedAt        *timestamppb.Timestamp `protobuf:"bytes,16,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *QuestionVersion) Reset() {
+	*x = QuestionVersion{}
+	mi := &file_quiz_quiz_proto_msgTypes[65]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QuestionVersion) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QuestionVersion) ProtoMessage() {}
+
+func (x *QuestionVersion) ProtoReflect() protoreflect.Message {
+	mi := &file_quiz_quiz_proto_msgTypes[65]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QuestionVersion.ProtoReflect.Descriptor instead.
+func (*QuestionVersion) Descriptor() ([]byte, []int) {
+	return file_quiz_quiz_proto_rawDescGZIP(), []int{65}
+}
+
+func (x *QuestionVersion) GetQuestionId() string {
+	if x != nil {
+		return x.QuestionId
+	}
+	return ""
+}
+
+func (x *QuestionVersion) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *QuestionVersion) GetType() QuestionType {
+	if x != nil {
+		return x.Type
+	}
+	return QuestionType_MULTIPLE_CHOICE
+}
+
+func (x *QuestionVersion) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+func (x *QuestionVersion) GetOptions() []string {
+	if x != nil {
+		return x.Options
+	}
+	return nil
+}
+
+func (x *QuestionVersion) GetCorrectAnswer() string {
+	if x != nil {
+		return x.CorrectAnswer
+	}
+	return ""
+}
+
+func (x *QuestionVersion) GetAnswerAliases() []string {
+	if x != nil {
+		return x.AnswerAliases
+	}
+	return nil
+}
+
+func (x *QuestionVersion) GetNumericTolerance() float64 {
+	if x != nil {
+		return x.NumericTolerance
+	}
+	return 0
+}
+
+func (x *QuestionVersion) GetParts() []*QuestionPart {
+	if x != nil {
+		return x.Parts
+	}
+	return nil
+}
+
+func (x *QuestionVersion) GetExplanation() string {
+	if x != nil {
+		return x.Explanation
+	}
+	return ""
+}
+
+func (x *QuestionVersion) GetDifficulty() DifficultyLevel {
+	if x != nil {
+		return x.Difficulty
+	}
+	return DifficultyLevel_EASY
+}
+
+func (x *QuestionVersion) GetKnowledgePoints() []string {
+	if x != nil {
+		return x.KnowledgePoints
+	}
+	return nil
 }
 
-func (x *Question) GetDifficulty() DifficultyLevel {
+func (x *QuestionVersion) GetEditorId() string {
 	if x != nil {
-		return x.Difficulty
+		return x.EditorId
 	}
-	return DifficultyLevel_EASY
+	return ""
 }
 
-func (x *Question) GetKnowledgePoints() []string {
+func (x *QuestionVersion) GetChangedFields() []string {
 	if x != nil {
-		return x.KnowledgePoints
+		return x.ChangedFields
 	}
 	return nil
 }
 
-func (x *Question) GetMaterialId() string {
+func (x *QuestionVersion) GetChangeNote() string {
 	if x != nil {
-		return x.MaterialId
+		return x.ChangeNote
 	}
 	return ""
 }
 
-func (x *Question) GetCreatedAt() string {
+func (x *QuestionVersion) GetCreatedAt() *timestamppb.Timestamp {
 	if x != nil {
 		return x.CreatedAt
 	}
-	return ""
+	return nil
 }
 
-// 获取题目请求
-type GetQuizRequest struct {
+// 获取版本历史，仅创建者与审核人可查看
+type ListQuestionVersionsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	QuestionId    string                 `protobuf:"bytes,1,opt,name=question_id,json=questionId,proto3" json:"question_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetQuizRequest) Reset() {
-	*x = GetQuizRequest{}
-	mi := &file_quiz_quiz_proto_msgTypes[3]
+func (x *ListQuestionVersionsRequest) Reset() {
+	*x = ListQuestionVersionsRequest{}
+	mi := &file_quiz_quiz_proto_msgTypes[66]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetQuizRequest) String() string {
+func (x *ListQuestionVersionsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetQuizRequest) ProtoMessage() {}
+func (*ListQuestionVersionsRequest) ProtoMessage() {}
 
-func (x *GetQuizRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_quiz_quiz_proto_msgTypes[3]
+func (x *ListQuestionVersionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_quiz_quiz_proto_msgTypes[66]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -423,43 +5556,49 @@ func (x *GetQuizRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetQuizRequest.ProtoReflect.Descriptor instead.
-func (*GetQuizRequest) Descriptor() ([]byte, []int) {
-	return file_quiz_quiz_proto_rawDescGZIP(), []int{3}
+// Deprecated: Use ListQuestionVersionsRequest.ProtoReflect.Descriptor instead.
+func (*ListQuestionVersionsRequest) Descriptor() ([]byte, []int) {
+	return file_quiz_quiz_proto_rawDescGZIP(), []int{66}
 }
 
-func (x *GetQuizRequest) GetQuestionId() string {
+func (x *ListQuestionVersionsRequest) GetQuestionId() string {
 	if x != nil {
 		return x.QuestionId
 	}
 	return ""
 }
 
-// 获取题目响应
-type GetQuizResponse struct {
+func (x *ListQuestionVersionsRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type ListQuestionVersionsResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
 	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
-	Question      *Question              `protobuf:"bytes,3,opt,name=question,proto3" json:"question,omitempty"`
+	Versions      []*QuestionVersion     `protobuf:"bytes,3,rep,name=versions,proto3" json:"versions,omitempty"` // 最新的在前
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetQuizResponse) Reset() {
-	*x = GetQuizResponse{}
-	mi := &file_quiz_quiz_proto_msgTypes[4]
+func (x *ListQuestionVersionsResponse) Reset() {
+	*x = ListQuestionVersionsResponse{}
+	mi := &file_quiz_quiz_proto_msgTypes[67]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetQuizResponse) String() string {
+func (x *ListQuestionVersionsResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetQuizResponse) ProtoMessage() {}
+func (*ListQuestionVersionsResponse) ProtoMessage() {}
 
-func (x *GetQuizResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_quiz_quiz_proto_msgTypes[4]
+func (x *ListQuestionVersionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_quiz_quiz_proto_msgTypes[67]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -470,60 +5609,57 @@ func (x *GetQuizResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetQuizResponse.ProtoReflect.Descriptor instead.
-func (*GetQuizResponse) Descriptor() ([]byte, []int) {
-	return file_quiz_quiz_proto_rawDescGZIP(), []int{4}
+// Deprecated: Use ListQuestionVersionsResponse.ProtoReflect.Descriptor instead.
+func (*ListQuestionVersionsResponse) Descriptor() ([]byte, []int) {
+	return file_quiz_quiz_proto_rawDescGZIP(), []int{67}
 }
 
-func (x *GetQuizResponse) GetSuccess() bool {
+func (x *ListQuestionVersionsResponse) GetSuccess() bool {
 	if x != nil {
 		return x.Success
 	}
 	return false
 }
 
-func (x *GetQuizResponse) GetMessage() string {
+func (x *ListQuestionVersionsResponse) GetMessage() string {
 	if x != nil {
 		return x.Message
 	}
 	return ""
 }
 
-func (x *GetQuizResponse) GetQuestion() *Question {
+func (x *ListQuestionVersionsResponse) GetVersions() []*QuestionVersion {
 	if x != nil {
-		return x.Question
+		return x.Versions
 	}
 	return nil
 }
 
-// 题目列表请求
-type ListQuizzesRequest struct {
+// 获取指定版本，作答过该版本的用户也可查看
+type GetQuestionVersionRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	MaterialId    string                 `protobuf:"bytes,2,opt,name=material_id,json=materialId,proto3" json:"material_id,omitempty"`
-	Type          QuestionType           `protobuf:"varint,3,opt,name=type,proto3,enum=quiz.QuestionType" json:"type,omitempty"`
-	Difficulty    DifficultyLevel        `protobuf:"varint,4,opt,name=difficulty,proto3,enum=quiz.DifficultyLevel" json:"difficulty,omitempty"`
-	Page          int32                  `protobuf:"varint,5,opt,name=page,proto3" json:"page,omitempty"`
-	PageSize      int32                  `protobuf:"varint,6,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	QuestionId    string                 `protobuf:"bytes,1,opt,name=question_id,json=questionId,proto3" json:"question_id,omitempty"`
+	Version       int32                  `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+	UserId        string                 `protobuf:"bytes,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListQuizzesRequest) Reset() {
-	*x = ListQuizzesRequest{}
-	mi := &file_quiz_quiz_proto_msgTypes[5]
+func (x *GetQuestionVersionRequest) Reset() {
+	*x = GetQuestionVersionRequest{}
+	mi := &file_quiz_quiz_proto_msgTypes[68]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListQuizzesRequest) String() string {
+func (x *GetQuestionVersionRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListQuizzesRequest) ProtoMessage() {}
+func (*GetQuestionVersionRequest) ProtoMessage() {}
 
-func (x *ListQuizzesRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_quiz_quiz_proto_msgTypes[5]
+func (x *GetQuestionVersionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_quiz_quiz_proto_msgTypes[68]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -534,81 +5670,56 @@ func (x *ListQuizzesRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListQuizzesRequest.ProtoReflect.Descriptor instead.
-func (*ListQuizzesRequest) Descriptor() ([]byte, []int) {
-	return file_quiz_quiz_proto_rawDescGZIP(), []int{5}
-}
-
-func (x *ListQuizzesRequest) GetUserId() string {
-	if x != nil {
-		return x.UserId
-	}
-	return ""
+// Deprecated: Use GetQuestionVersionRequest.ProtoReflect.Descriptor instead.
+func (*GetQuestionVersionRequest) Descriptor() ([]byte, []int) {
+	return file_quiz_quiz_proto_rawDescGZIP(), []int{68}
 }
 
-func (x *ListQuizzesRequest) GetMaterialId() string {
+func (x *GetQuestionVersionRequest) GetQuestionId() string {
 	if x != nil {
-		return x.MaterialId
+		return x.QuestionId
 	}
 	return ""
 }
 
-func (x *ListQuizzesRequest) GetType() QuestionType {
-	if x != nil {
-		return x.Type
-	}
-	return QuestionType_MULTIPLE_CHOICE
-}
-
-func (x *ListQuizzesRequest) GetDifficulty() DifficultyLevel {
-	if x != nil {
-		return x.Difficulty
-	}
-	return DifficultyLevel_EASY
-}
-
-func (x *ListQuizzesRequest) GetPage() int32 {
+func (x *GetQuestionVersionRequest) GetVersion() int32 {
 	if x != nil {
-		return x.Page
+		return x.Version
 	}
 	return 0
 }
 
-func (x *ListQuizzesRequest) GetPageSize() int32 {
+func (x *GetQuestionVersionRequest) GetUserId() string {
 	if x != nil {
-		return x.PageSize
+		return x.UserId
 	}
-	return 0
+	return ""
 }
 
-// 题目列表响应
-type ListQuizzesResponse struct {
+type GetQuestionVersionResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
 	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
-	Questions     []*Question            `protobuf:"bytes,3,rep,name=questions,proto3" json:"questions,omitempty"`
-	Total         int32                  `protobuf:"varint,4,opt,name=total,proto3" json:"total,omitempty"`
-	Page          int32                  `protobuf:"varint,5,opt,name=page,proto3" json:"page,omitempty"`
-	PageSize      int32                  `protobuf:"varint,6,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	Version       *QuestionVersion       `protobuf:"bytes,3,opt,name=version,proto3" json:"version,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListQuizzesResponse) Reset() {
-	*x = ListQuizzesResponse{}
-	mi := &file_quiz_quiz_proto_msgTypes[6]
+func (x *GetQuestionVersionResponse) Reset() {
+	*x = GetQuestionVersionResponse{}
+	mi := &file_quiz_quiz_proto_msgTypes[69]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListQuizzesResponse) String() string {
+func (x *GetQuestionVersionResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListQuizzesResponse) ProtoMessage() {}
+func (*GetQuestionVersionResponse) ProtoMessage() {}
 
-func (x *ListQuizzesResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_quiz_quiz_proto_msgTypes[6]
+func (x *GetQuestionVersionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_quiz_quiz_proto_msgTypes[69]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -619,78 +5730,56 @@ func (x *ListQuizzesResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListQuizzesResponse.ProtoReflect.Descriptor instead.
-func (*ListQuizzesResponse) Descriptor() ([]byte, []int) {
-	return file_quiz_quiz_proto_rawDescGZIP(), []int{6}
+// Deprecated: Use GetQuestionVersionResponse.ProtoReflect.Descriptor instead.
+func (*GetQuestionVersionResponse) Descriptor() ([]byte, []int) {
+	return file_quiz_quiz_proto_rawDescGZIP(), []int{69}
 }
 
-func (x *ListQuizzesResponse) GetSuccess() bool {
+func (x *GetQuestionVersionResponse) GetSuccess() bool {
 	if x != nil {
 		return x.Success
 	}
 	return false
 }
 
-func (x *ListQuizzesResponse) GetMessage() string {
+func (x *GetQuestionVersionResponse) GetMessage() string {
 	if x != nil {
 		return x.Message
 	}
 	return ""
 }
 
-func (x *ListQuizzesResponse) GetQuestions() []*Question {
+func (x *GetQuestionVersionResponse) GetVersion() *QuestionVersion {
 	if x != nil {
-		return x.Questions
+		return x.Version
 	}
 	return nil
 }
 
-func (x *ListQuizzesResponse) GetTotal() int32 {
-	if x != nil {
-		return x.Total
-	}
-	return 0
-}
-
-func (x *ListQuizzesResponse) GetPage() int32 {
-	if x != nil {
-		return x.Page
-	}
-	return 0
-}
-
-func (x *ListQuizzesResponse) GetPageSize() int32 {
-	if x != nil {
-		return x.PageSize
-	}
-	return 0
-}
-
-// 提交答案请求
-type SubmitAnswerRequest struct {
+// 开始作答请求
+type StartQuestionAttemptRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	QuestionId    string                 `protobuf:"bytes,1,opt,name=question_id,json=questionId,proto3" json:"question_id,omitempty"`
 	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	Answer        string                 `protobuf:"bytes,3,opt,name=answer,proto3" json:"answer,omitempty"` // 用户答案
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *SubmitAnswerRequest) Reset() {
-	*x = SubmitAnswerRequest{}
-	mi := &file_quiz_quiz_proto_msgTypes[7]
+func (x *StartQuestionAttemptRequest) Reset() {
+	*x = StartQuestionAttemptRequest{}
+	mi := &file_quiz_quiz_proto_msgTypes[70]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SubmitAnswerRequest) String() string {
+func (x *StartQuestionAttemptRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SubmitAnswerRequest) ProtoMessage() {}
+func (*StartQuestionAttemptRequest) ProtoMessage() {}
 
-func (x *SubmitAnswerRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_quiz_quiz_proto_msgTypes[7]
+func (x *StartQuestionAttemptRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_quiz_quiz_proto_msgTypes[70]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -701,60 +5790,52 @@ func (x *SubmitAnswerRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SubmitAnswerRequest.ProtoReflect.Descriptor instead.
-func (*SubmitAnswerRequest) Descriptor() ([]byte, []int) {
-	return file_quiz_quiz_proto_rawDescGZIP(), []int{7}
+// Deprecated: Use StartQuestionAttemptRequest.ProtoReflect.Descriptor instead.
+func (*StartQuestionAttemptRequest) Descriptor() ([]byte, []int) {
+	return file_quiz_quiz_proto_rawDescGZIP(), []int{70}
 }
 
-func (x *SubmitAnswerRequest) GetQuestionId() string {
+func (x *StartQuestionAttemptRequest) GetQuestionId() string {
 	if x != nil {
 		return x.QuestionId
 	}
 	return ""
 }
 
-func (x *SubmitAnswerRequest) GetUserId() string {
+func (x *StartQuestionAttemptRequest) GetUserId() string {
 	if x != nil {
 		return x.UserId
 	}
 	return ""
 }
 
-func (x *SubmitAnswerRequest) GetAnswer() string {
-	if x != nil {
-		return x.Answer
-	}
-	return ""
-}
-
-// 提交答案响应
-type SubmitAnswerResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
-	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
-	IsCorrect     bool                   `protobuf:"varint,3,opt,name=is_correct,json=isCorrect,proto3" json:"is_correct,omitempty"`            // 是否正确
-	Score         float32                `protobuf:"fixed32,4,opt,name=score,proto3" json:"score,omitempty"`                                    // 得分
-	CorrectAnswer string                 `protobuf:"bytes,5,opt,name=correct_answer,json=correctAnswer,proto3" json:"correct_answer,omitempty"` // 正确答案
-	Explanation   string                 `protobuf:"bytes,6,opt,name=explanation,proto3" json:"explanation,omitempty"`                          // 解析
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+// 开始作答响应，question 已按会话的选项顺序排列，并去掉答案与解析
+type StartQuestionAttemptResponse struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Success         bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message         string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	AttemptId       string                 `protobuf:"bytes,3,opt,name=attempt_id,json=attemptId,proto3" json:"attempt_id,omitempty"`
+	Question        *Question              `protobuf:"bytes,4,opt,name=question,proto3" json:"question,omitempty"`
+	OptionsShuffled bool                   `protobuf:"varint,5,opt,name=options_shuffled,json=optionsShuffled,proto3" json:"options_shuffled,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
-func (x *SubmitAnswerResponse) Reset() {
-	*x = SubmitAnswerResponse{}
-	mi := &file_quiz_quiz_proto_msgTypes[8]
+func (x *StartQuestionAttemptResponse) Reset() {
+	*x = StartQuestionAttemptResponse{}
+	mi := &file_quiz_quiz_proto_msgTypes[71]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SubmitAnswerResponse) String() string {
+func (x *StartQuestionAttemptResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SubmitAnswerResponse) ProtoMessage() {}
+func (*StartQuestionAttemptResponse) ProtoMessage() {}
 
-func (x *SubmitAnswerResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_quiz_quiz_proto_msgTypes[8]
+func (x *StartQuestionAttemptResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_quiz_quiz_proto_msgTypes[71]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -765,82 +5846,75 @@ func (x *SubmitAnswerResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SubmitAnswerResponse.ProtoReflect.Descriptor instead.
-func (*SubmitAnswerResponse) Descriptor() ([]byte, []int) {
-	return file_quiz_quiz_proto_rawDescGZIP(), []int{8}
+// Deprecated: Use StartQuestionAttemptResponse.ProtoReflect.Descriptor instead.
+func (*StartQuestionAttemptResponse) Descriptor() ([]byte, []int) {
+	return file_quiz_quiz_proto_rawDescGZIP(), []int{71}
 }
 
-func (x *SubmitAnswerResponse) GetSuccess() bool {
+func (x *StartQuestionAttemptResponse) GetSuccess() bool {
 	if x != nil {
 		return x.Success
 	}
 	return false
 }
 
-func (x *SubmitAnswerResponse) GetMessage() string {
+func (x *StartQuestionAttemptResponse) GetMessage() string {
 	if x != nil {
 		return x.Message
 	}
 	return ""
 }
 
-func (x *SubmitAnswerResponse) GetIsCorrect() bool {
-	if x != nil {
-		return x.IsCorrect
-	}
-	return false
-}
-
-func (x *SubmitAnswerResponse) GetScore() float32 {
+func (x *StartQuestionAttemptResponse) GetAttemptId() string {
 	if x != nil {
-		return x.Score
+		return x.AttemptId
 	}
-	return 0
+	return ""
 }
 
-func (x *SubmitAnswerResponse) GetCorrectAnswer() string {
+func (x *StartQuestionAttemptResponse) GetQuestion() *Question {
 	if x != nil {
-		return x.CorrectAnswer
+		return x.Question
 	}
-	return ""
+	return nil
 }
 
-func (x *SubmitAnswerResponse) GetExplanation() string {
+func (x *StartQuestionAttemptResponse) GetOptionsShuffled() bool {
 	if x != nil {
-		return x.Explanation
+		return x.OptionsShuffled
 	}
-	return ""
+	return false
 }
 
-// 用户答题记录
-type UserAnswer struct {
+// 教师的评分队列：本人创建或审核的题目下待复核的主观题作答，按提交时间先后排列
+type ListGradingQueueRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	AnswerId      string                 `protobuf:"bytes,1,opt,name=answer_id,json=answerId,proto3" json:"answer_id,omitempty"`
-	QuestionId    string                 `protobuf:"bytes,2,opt,name=question_id,json=questionId,proto3" json:"question_id,omitempty"`
-	UserId        string                 `protobuf:"bytes,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	Answer        string                 `protobuf:"bytes,4,opt,name=answer,proto3" json:"answer,omitempty"`
-	IsCorrect     bool                   `protobuf:"varint,5,opt,name=is_correct,json=isCorrect,proto3" json:"is_correct,omitempty"`
-	Score         float32                `protobuf:"fixed32,6,opt,name=score,proto3" json:"score,omitempty"`
-	AnsweredAt    string                 `protobuf:"bytes,7,opt,name=answered_at,json=answeredAt,proto3" json:"answered_at,omitempty"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	MaterialId    string                 `protobuf:"bytes,2,opt,name=material_id,json=materialId,proto3" json:"material_id,omitempty"`
+	CourseId      string                 `protobuf:"bytes,3,opt,name=course_id,json=courseId,proto3" json:"course_id,omitempty"`
+	QuestionId    string                 `protobuf:"bytes,4,opt,name=question_id,json=questionId,proto3" json:"question_id,omitempty"`
+	FlaggedOnly   bool                   `protobuf:"varint,5,opt,name=flagged_only,json=flaggedOnly,proto3" json:"flagged_only,omitempty"` // 只看查重标记的作答
+	Page          int32                  `protobuf:"varint,6,opt,name=page,proto3" json:"page,omitempty"`
+	PageSize      int32                  `protobuf:"varint,7,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UserAnswer) Reset() {
-	*x = UserAnswer{}
-	mi := &file_quiz_quiz_proto_msgTypes[9]
+func (x *ListGradingQueueRequest) Reset() {
+	*x = ListGradingQueueRequest{}
+	mi := &file_quiz_quiz_proto_msgTypes[72]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UserAnswer) String() string {
+func (x *ListGradingQueueRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UserAnswer) ProtoMessage() {}
+func (*ListGradingQueueRequest) ProtoMessage() {}
 
-func (x *UserAnswer) ProtoReflect() protoreflect.Message {
-	mi := &file_quiz_quiz_proto_msgTypes[9]
+func (x *ListGradingQueueRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_quiz_quiz_proto_msgTypes[72]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -851,85 +5925,83 @@ func (x *UserAnswer) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UserAnswer.ProtoReflect.Descriptor instead.
-func (*UserAnswer) Descriptor() ([]byte, []int) {
-	return file_quiz_quiz_proto_rawDescGZIP(), []int{9}
+// Deprecated: Use ListGradingQueueRequest.ProtoReflect.Descriptor instead.
+func (*ListGradingQueueRequest) Descriptor() ([]byte, []int) {
+	return file_quiz_quiz_proto_rawDescGZIP(), []int{72}
 }
 
-func (x *UserAnswer) GetAnswerId() string {
+func (x *ListGradingQueueRequest) GetUserId() string {
 	if x != nil {
-		return x.AnswerId
+		return x.UserId
 	}
 	return ""
 }
 
-func (x *UserAnswer) GetQuestionId() string {
+func (x *ListGradingQueueRequest) GetMaterialId() string {
 	if x != nil {
-		return x.QuestionId
+		return x.MaterialId
 	}
 	return ""
 }
 
-func (x *UserAnswer) GetUserId() string {
+func (x *ListGradingQueueRequest) GetCourseId() string {
 	if x != nil {
-		return x.UserId
+		return x.CourseId
 	}
 	return ""
 }
 
-func (x *UserAnswer) GetAnswer() string {
+func (x *ListGradingQueueRequest) GetQuestionId() string {
 	if x != nil {
-		return x.Answer
+		return x.QuestionId
 	}
 	return ""
 }
 
-func (x *UserAnswer) GetIsCorrect() bool {
+func (x *ListGradingQueueRequest) GetFlaggedOnly() bool {
 	if x != nil {
-		return x.IsCorrect
+		return x.FlaggedOnly
 	}
 	return false
 }
 
-func (x *UserAnswer) GetScore() float32 {
+func (x *ListGradingQueueRequest) GetPage() int32 {
 	if x != nil {
-		return x.Score
+		return x.Page
 	}
 	return 0
 }
 
-func (x *UserAnswer) GetAnsweredAt() string {
+func (x *ListGradingQueueRequest) GetPageSize() int32 {
 	if x != nil {
-		return x.AnsweredAt
+		return x.PageSize
 	}
-	return ""
+	return 0
 }
 
-// 获取用户答题历史请求
-type GetUserQuizHistoryRequest struct {
+type GradingQueueItem struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	Page          int32                  `protobuf:"varint,2,opt,name=page,proto3" json:"page,omitempty"`
-	PageSize      int32                  `protobuf:"varint,3,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	Answer        *UserAnswer            `protobuf:"bytes,1,opt,name=answer,proto3" json:"answer,omitempty"`
+	Question      *Question              `protobuf:"bytes,2,opt,name=question,proto3" json:"question,omitempty"` // 含参考答案，供教师对照
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetUserQuizHistoryRequest) Reset() {
-	*x = GetUserQuizHistoryRequest{}
-	mi := &file_quiz_quiz_proto_msgTypes[10]
+func (x *GradingQueueItem) Reset() {
+	*x = GradingQueueItem{}
+	mi := &file_quiz_quiz_proto_msgTypes[73]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetUserQuizHistoryRequest) String() string {
+func (x *GradingQueueItem) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetUserQuizHistoryRequest) ProtoMessage() {}
-
-func (x *GetUserQuizHistoryRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_quiz_quiz_proto_msgTypes[10]
+func (*GradingQueueItem) ProtoMessage() {}
+
+func (x *GradingQueueItem) ProtoReflect() protoreflect.Message {
+	mi := &file_quiz_quiz_proto_msgTypes[73]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -940,58 +6012,50 @@ func (x *GetUserQuizHistoryRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetUserQuizHistoryRequest.ProtoReflect.Descriptor instead.
-func (*GetUserQuizHistoryRequest) Descriptor() ([]byte, []int) {
-	return file_quiz_quiz_proto_rawDescGZIP(), []int{10}
-}
-
-func (x *GetUserQuizHistoryRequest) GetUserId() string {
-	if x != nil {
-		return x.UserId
-	}
-	return ""
+// Deprecated: Use GradingQueueItem.ProtoReflect.Descriptor instead.
+func (*GradingQueueItem) Descriptor() ([]byte, []int) {
+	return file_quiz_quiz_proto_rawDescGZIP(), []int{73}
 }
 
-func (x *GetUserQuizHistoryRequest) GetPage() int32 {
+func (x *GradingQueueItem) GetAnswer() *UserAnswer {
 	if x != nil {
-		return x.Page
+		return x.Answer
 	}
-	return 0
+	return nil
 }
 
-func (x *GetUserQuizHistoryRequest) GetPageSize() int32 {
+func (x *GradingQueueItem) GetQuestion() *Question {
 	if x != nil {
-		return x.PageSize
+		return x.Question
 	}
-	return 0
+	return nil
 }
 
-// 获取用户答题历史响应
-type GetUserQuizHistoryResponse struct {
+type ListGradingQueueResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
 	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
-	Answers       []*UserAnswer          `protobuf:"bytes,3,rep,name=answers,proto3" json:"answers,omitempty"`
+	Items         []*GradingQueueItem    `protobuf:"bytes,3,rep,name=items,proto3" json:"items,omitempty"`
 	Total         int32                  `protobuf:"varint,4,opt,name=total,proto3" json:"total,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetUserQuizHistoryResponse) Reset() {
-	*x = GetUserQuizHistoryResponse{}
-	mi := &file_quiz_quiz_proto_msgTypes[11]
+func (x *ListGradingQueueResponse) Reset() {
+	*x = ListGradingQueueResponse{}
+	mi := &file_quiz_quiz_proto_msgTypes[74]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetUserQuizHistoryResponse) String() string {
+func (x *ListGradingQueueResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetUserQuizHistoryResponse) ProtoMessage() {}
+func (*ListGradingQueueResponse) ProtoMessage() {}
 
-func (x *GetUserQuizHistoryResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_quiz_quiz_proto_msgTypes[11]
+func (x *ListGradingQueueResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_quiz_quiz_proto_msgTypes[74]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1002,66 +6066,65 @@ func (x *GetUserQuizHistoryResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetUserQuizHistoryResponse.ProtoReflect.Descriptor instead.
-func (*GetUserQuizHistoryResponse) Descriptor() ([]byte, []int) {
-	return file_quiz_quiz_proto_rawDescGZIP(), []int{11}
+// Deprecated: Use ListGradingQueueResponse.ProtoReflect.Descriptor instead.
+func (*ListGradingQueueResponse) Descriptor() ([]byte, []int) {
+	return file_quiz_quiz_proto_rawDescGZIP(), []int{74}
 }
 
-func (x *GetUserQuizHistoryResponse) GetSuccess() bool {
+func (x *ListGradingQueueResponse) GetSuccess() bool {
 	if x != nil {
 		return x.Success
 	}
 	return false
 }
 
-func (x *GetUserQuizHistoryResponse) GetMessage() string {
+func (x *ListGradingQueueResponse) GetMessage() string {
 	if x != nil {
 		return x.Message
 	}
 	return ""
 }
 
-func (x *GetUserQuizHistoryResponse) GetAnswers() []*UserAnswer {
+func (x *ListGradingQueueResponse) GetItems() []*GradingQueueItem {
 	if x != nil {
-		return x.Answers
+		return x.Items
 	}
 	return nil
 }
 
-func (x *GetUserQuizHistoryResponse) GetTotal() int32 {
+func (x *ListGradingQueueResponse) GetTotal() int32 {
 	if x != nil {
 		return x.Total
 	}
 	return 0
 }
 
-// 知识点统计
-type KnowledgePointStats struct {
-	state          protoimpl.MessageState `protogen:"open.v1"`
-	KnowledgePoint string                 `protobuf:"bytes,1,opt,name=knowledge_point,json=knowledgePoint,proto3" json:"knowledge_point,omitempty"`
-	TotalQuestions int32                  `protobuf:"varint,2,opt,name=total_questions,json=totalQuestions,proto3" json:"total_questions,omitempty"`                        // 总题数
-	CorrectAnswers int32                  `protobuf:"varint,3,opt,name=correct_answers,json=correctAnswers,proto3" json:"correct_answers,omitempty"`                        // 正确题数
-	AccuracyRate   float32                `protobuf:"fixed32,4,opt,name=accuracy_rate,json=accuracyRate,proto3" json:"accuracy_rate,omitempty"`                             // 正确率
-	AvgDifficulty  DifficultyLevel        `protobuf:"varint,5,opt,name=avg_difficulty,json=avgDifficulty,proto3,enum=quiz.DifficultyLevel" json:"avg_difficulty,omitempty"` // 平均难度
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
+// 单条作答的评分：accept 为 true 时采纳 AI 建议得分，否则以 score（0-1，扣除重复作答惩罚前）为准
+type AnswerGrade struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AnswerId      string                 `protobuf:"bytes,1,opt,name=answer_id,json=answerId,proto3" json:"answer_id,omitempty"`
+	Accept        bool                   `protobuf:"varint,2,opt,name=accept,proto3" json:"accept,omitempty"`
+	Score         float32                `protobuf:"fixed32,3,opt,name=score,proto3" json:"score,omitempty"`
+	Comment       string                 `protobuf:"bytes,4,opt,name=comment,proto3" json:"comment,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *KnowledgePointStats) Reset() {
-	*x = KnowledgePointStats{}
-	mi := &file_quiz_quiz_proto_msgTypes[12]
+func (x *AnswerGrade) Reset() {
+	*x = AnswerGrade{}
+	mi := &file_quiz_quiz_proto_msgTypes[75]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *KnowledgePointStats) String() string {
+func (x *AnswerGrade) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*KnowledgePointStats) ProtoMessage() {}
+func (*AnswerGrade) ProtoMessage() {}
 
-func (x *KnowledgePointStats) ProtoReflect() protoreflect.Message {
-	mi := &file_quiz_quiz_proto_msgTypes[12]
+func (x *AnswerGrade) ProtoReflect() protoreflect.Message {
+	mi := &file_quiz_quiz_proto_msgTypes[75]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1072,70 +6135,115 @@ func (x *KnowledgePointStats) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use KnowledgePointStats.ProtoReflect.Descriptor instead.
-func (*KnowledgePointStats) Descriptor() ([]byte, []int) {
-	return file_quiz_quiz_proto_rawDescGZIP(), []int{12}
+// Deprecated: Use AnswerGrade.ProtoReflect.Descriptor instead.
+func (*AnswerGrade) Descriptor() ([]byte, []int) {
+	return file_quiz_quiz_proto_rawDescGZIP(), []int{75}
 }
 
-func (x *KnowledgePointStats) GetKnowledgePoint() string {
+func (x *AnswerGrade) GetAnswerId() string {
 	if x != nil {
-		return x.KnowledgePoint
+		return x.AnswerId
 	}
 	return ""
 }
 
-func (x *KnowledgePointStats) GetTotalQuestions() int32 {
+func (x *AnswerGrade) GetAccept() bool {
 	if x != nil {
-		return x.TotalQuestions
+		return x.Accept
 	}
-	return 0
+	return false
 }
 
-func (x *KnowledgePointStats) GetCorrectAnswers() int32 {
+func (x *AnswerGrade) GetScore() float32 {
 	if x != nil {
-		return x.CorrectAnswers
+		return x.Score
 	}
 	return 0
 }
 
-func (x *KnowledgePointStats) GetAccuracyRate() float32 {
+func (x *AnswerGrade) GetComment() string {
 	if x != nil {
-		return x.AccuracyRate
+		return x.Comment
 	}
-	return 0
+	return ""
 }
 
-func (x *KnowledgePointStats) GetAvgDifficulty() DifficultyLevel {
+type GradeAnswersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Grades        []*AnswerGrade         `protobuf:"bytes,2,rep,name=grades,proto3" json:"grades,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GradeAnswersRequest) Reset() {
+	*x = GradeAnswersRequest{}
+	mi := &file_quiz_quiz_proto_msgTypes[76]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GradeAnswersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GradeAnswersRequest) ProtoMessage() {}
+
+func (x *GradeAnswersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_quiz_quiz_proto_msgTypes[76]
 	if x != nil {
-		return x.AvgDifficulty
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return DifficultyLevel_EASY
+	return mi.MessageOf(x)
 }
 
-// 获取知识点掌握度统计请求
-type GetKnowledgeStatsRequest struct {
+// Deprecated: Use GradeAnswersRequest.ProtoReflect.Descriptor instead.
+func (*GradeAnswersRequest) Descriptor() ([]byte, []int) {
+	return file_quiz_quiz_proto_rawDescGZIP(), []int{76}
+}
+
+func (x *GradeAnswersRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *GradeAnswersRequest) GetGrades() []*AnswerGrade {
+	if x != nil {
+		return x.Grades
+	}
+	return nil
+}
+
+// 未处理的作答及原因
+type GradingSkip struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	MaterialId    string                 `protobuf:"bytes,2,opt,name=material_id,json=materialId,proto3" json:"material_id,omitempty"` // 可选，指定材料
+	AnswerId      string                 `protobuf:"bytes,1,opt,name=answer_id,json=answerId,proto3" json:"answer_id,omitempty"`
+	Reason        string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetKnowledgeStatsRequest) Reset() {
-	*x = GetKnowledgeStatsRequest{}
-	mi := &file_quiz_quiz_proto_msgTypes[13]
+func (x *GradingSkip) Reset() {
+	*x = GradingSkip{}
+	mi := &file_quiz_quiz_proto_msgTypes[77]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetKnowledgeStatsRequest) String() string {
+func (x *GradingSkip) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetKnowledgeStatsRequest) ProtoMessage() {}
+func (*GradingSkip) ProtoMessage() {}
 
-func (x *GetKnowledgeStatsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_quiz_quiz_proto_msgTypes[13]
+func (x *GradingSkip) ProtoReflect() protoreflect.Message {
+	mi := &file_quiz_quiz_proto_msgTypes[77]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1146,51 +6254,50 @@ func (x *GetKnowledgeStatsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetKnowledgeStatsRequest.ProtoReflect.Descriptor instead.
-func (*GetKnowledgeStatsRequest) Descriptor() ([]byte, []int) {
-	return file_quiz_quiz_proto_rawDescGZIP(), []int{13}
+// Deprecated: Use GradingSkip.ProtoReflect.Descriptor instead.
+func (*GradingSkip) Descriptor() ([]byte, []int) {
+	return file_quiz_quiz_proto_rawDescGZIP(), []int{77}
 }
 
-func (x *GetKnowledgeStatsRequest) GetUserId() string {
+func (x *GradingSkip) GetAnswerId() string {
 	if x != nil {
-		return x.UserId
+		return x.AnswerId
 	}
 	return ""
 }
 
-func (x *GetKnowledgeStatsRequest) GetMaterialId() string {
+func (x *GradingSkip) GetReason() string {
 	if x != nil {
-		return x.MaterialId
+		return x.Reason
 	}
 	return ""
 }
 
-// 获取知识点掌握度统计响应
-type GetKnowledgeStatsResponse struct {
-	state           protoimpl.MessageState `protogen:"open.v1"`
-	Success         bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
-	Message         string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
-	Stats           []*KnowledgePointStats `protobuf:"bytes,3,rep,name=stats,proto3" json:"stats,omitempty"`
-	OverallAccuracy float32                `protobuf:"fixed32,4,opt,name=overall_accuracy,json=overallAccuracy,proto3" json:"overall_accuracy,omitempty"` // 总体正确率
-	unknownFields   protoimpl.UnknownFields
-	sizeCache       protoimpl.SizeCache
+type GradeAnswersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Answers       []*UserAnswer          `protobuf:"bytes,3,rep,name=answers,proto3" json:"answers,omitempty"` // 评分后的作答
+	Skipped       []*GradingSkip         `protobuf:"bytes,4,rep,name=skipped,proto3" json:"skipped,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetKnowledgeStatsResponse) Reset() {
-	*x = GetKnowledgeStatsResponse{}
-	mi := &file_quiz_quiz_proto_msgTypes[14]
+func (x *GradeAnswersResponse) Reset() {
+	*x = GradeAnswersResponse{}
+	mi := &file_quiz_quiz_proto_msgTypes[78]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetKnowledgeStatsResponse) String() string {
+func (x *GradeAnswersResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetKnowledgeStatsResponse) ProtoMessage() {}
+func (*GradeAnswersResponse) ProtoMessage() {}
 
-func (x *GetKnowledgeStatsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_quiz_quiz_proto_msgTypes[14]
+func (x *GradeAnswersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_quiz_quiz_proto_msgTypes[78]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1201,44 +6308,44 @@ func (x *GetKnowledgeStatsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetKnowledgeStatsResponse.ProtoReflect.Descriptor instead.
-func (*GetKnowledgeStatsResponse) Descriptor() ([]byte, []int) {
-	return file_quiz_quiz_proto_rawDescGZIP(), []int{14}
+// Deprecated: Use GradeAnswersResponse.ProtoReflect.Descriptor instead.
+func (*GradeAnswersResponse) Descriptor() ([]byte, []int) {
+	return file_quiz_quiz_proto_rawDescGZIP(), []int{78}
 }
 
-func (x *GetKnowledgeStatsResponse) GetSuccess() bool {
+func (x *GradeAnswersResponse) GetSuccess() bool {
 	if x != nil {
 		return x.Success
 	}
 	return false
 }
 
-func (x *GetKnowledgeStatsResponse) GetMessage() string {
+func (x *GradeAnswersResponse) GetMessage() string {
 	if x != nil {
 		return x.Message
 	}
 	return ""
 }
 
-func (x *GetKnowledgeStatsResponse) GetStats() []*KnowledgePointStats {
+func (x *GradeAnswersResponse) GetAnswers() []*UserAnswer {
 	if x != nil {
-		return x.Stats
+		return x.Answers
 	}
 	return nil
 }
 
-func (x *GetKnowledgeStatsResponse) GetOverallAccuracy() float32 {
+func (x *GradeAnswersResponse) GetSkipped() []*GradingSkip {
 	if x != nil {
-		return x.OverallAccuracy
+		return x.Skipped
 	}
-	return 0
+	return nil
 }
 
 var File_quiz_quiz_proto protoreflect.FileDescriptor
 
 const file_quiz_quiz_proto_rawDesc = "" +
 	"\n" +
-	"\x0fquiz/quiz.proto\x12\x04quiz\"\xf1\x01\n" +
+	"\x0fquiz/quiz.proto\x12\x04quiz\x1a\x1fgoogle/protobuf/timestamp.proto\"\xf7\x04\n" +
 	"\x13GenerateQuizRequest\x12\x1f\n" +
 	"\vmaterial_id\x18\x01 \x01(\tR\n" +
 	"materialId\x12\x17\n" +
@@ -1248,11 +6355,49 @@ const file_quiz_quiz_proto_rawDesc = "" +
 	"difficulty\x18\x04 \x01(\x0e2\x15.quiz.DifficultyLevelR\n" +
 	"difficulty\x12\x14\n" +
 	"\x05count\x18\x05 \x01(\x05R\x05count\x12)\n" +
-	"\x10knowledge_points\x18\x06 \x03(\tR\x0fknowledgePoints\"x\n" +
+	"\x10knowledge_points\x18\x06 \x03(\tR\x0fknowledgePoints\x12'\n" +
+	"\x0ffrom_transcript\x18\a \x01(\bR\x0efromTranscript\x12\x1d\n" +
+	"\n" +
+	"start_time\x18\b \x01(\x02R\tstartTime\x12\x19\n" +
+	"\bend_time\x18\t \x01(\x02R\aendTime\x12%\n" +
+	"\x0eselection_text\x18\n" +
+	" \x01(\tR\rselectionText\x12%\n" +
+	"\x0eselection_page\x18\v \x01(\x05R\rselectionPage\x12\x1a\n" +
+	"\blanguage\x18\f \x01(\tR\blanguage\x12\x1b\n" +
+	"\tcourse_id\x18\r \x01(\tR\bcourseId\x12\x1f\n" +
+	"\vreviewer_id\x18\x0e \x01(\tR\n" +
+	"reviewerId\x12!\n" +
+	"\fmaterial_ids\x18\x0f \x03(\tR\vmaterialIds\x12#\n" +
+	"\rcollection_id\x18\x10 \x01(\tR\fcollectionId\x121\n" +
+	"\tblueprint\x18\x11 \x03(\v2\x13.quiz.BlueprintSlotR\tblueprint\"\xad\x01\n" +
+	"\rBlueprintSlot\x12&\n" +
+	"\x04type\x18\x01 \x01(\x0e2\x12.quiz.QuestionTypeR\x04type\x12'\n" +
+	"\x0fknowledge_point\x18\x02 \x01(\tR\x0eknowledgePoint\x125\n" +
+	"\n" +
+	"difficulty\x18\x03 \x01(\x0e2\x15.quiz.DifficultyLevelR\n" +
+	"difficulty\x12\x14\n" +
+	"\x05count\x18\x04 \x01(\x05R\x05count\"\xb4\x02\n" +
+	"\x13BlueprintSlotReport\x12\x1d\n" +
+	"\n" +
+	"slot_index\x18\x01 \x01(\x05R\tslotIndex\x12'\n" +
+	"\x04slot\x18\x02 \x01(\v2\x13.quiz.BlueprintSlotR\x04slot\x12\x16\n" +
+	"\x06filled\x18\x03 \x01(\x05R\x06filled\x12!\n" +
+	"\fquestion_ids\x18\x04 \x03(\tR\vquestionIds\x12C\n" +
+	"\brejected\x18\x05 \x03(\v2'.quiz.BlueprintSlotReport.RejectedEntryR\brejected\x12\x18\n" +
+	"\amessage\x18\x06 \x01(\tR\amessage\x1a;\n" +
+	"\rRejectedEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x05R\x05value:\x028\x01\"\x96\x01\n" +
+	"\x0fBlueprintReport\x12\x1c\n" +
+	"\tsatisfied\x18\x01 \x01(\bR\tsatisfied\x12\x1c\n" +
+	"\trequested\x18\x02 \x01(\x05R\trequested\x12\x16\n" +
+	"\x06filled\x18\x03 \x01(\x05R\x06filled\x12/\n" +
+	"\x05slots\x18\x04 \x03(\v2\x19.quiz.BlueprintSlotReportR\x05slots\"\xba\x01\n" +
 	"\x14GenerateQuizResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
 	"\amessage\x18\x02 \x01(\tR\amessage\x12,\n" +
-	"\tquestions\x18\x03 \x03(\v2\x0e.quiz.QuestionR\tquestions\"\xf2\x02\n" +
+	"\tquestions\x18\x03 \x03(\v2\x0e.quiz.QuestionR\tquestions\x12@\n" +
+	"\x10blueprint_report\x18\x04 \x01(\v2\x15.quiz.BlueprintReportR\x0fblueprintReport\"\xea\a\n" +
 	"\bQuestion\x12\x1f\n" +
 	"\vquestion_id\x18\x01 \x01(\tR\n" +
 	"questionId\x12&\n" +
@@ -1266,17 +6411,47 @@ const file_quiz_quiz_proto_rawDesc = "" +
 	"difficulty\x12)\n" +
 	"\x10knowledge_points\x18\b \x03(\tR\x0fknowledgePoints\x12\x1f\n" +
 	"\vmaterial_id\x18\t \x01(\tR\n" +
-	"materialId\x12\x1d\n" +
+	"materialId\x129\n" +
 	"\n" +
-	"created_at\x18\n" +
-	" \x01(\tR\tcreatedAt\"1\n" +
+	"created_at\x18\x12 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12%\n" +
+	"\x0eanswer_aliases\x18\v \x03(\tR\ranswerAliases\x12+\n" +
+	"\x11numeric_tolerance\x18\f \x01(\x01R\x10numericTolerance\x12(\n" +
+	"\x05parts\x18\r \x03(\v2\x12.quiz.QuestionPartR\x05parts\x124\n" +
+	"\tcitations\x18\x0e \x03(\v2\x16.quiz.QuestionCitationR\tcitations\x12H\n" +
+	"\x14requested_difficulty\x18\x0f \x01(\x0e2\x15.quiz.DifficultyLevelR\x13requestedDifficulty\x12H\n" +
+	"\x14estimated_difficulty\x18\x10 \x01(\x0e2\x15.quiz.DifficultyLevelR\x13estimatedDifficulty\x12+\n" +
+	"\x11difficulty_source\x18\x11 \x01(\tR\x10difficultySource\x12\x1b\n" +
+	"\tcourse_id\x18\x13 \x01(\tR\bcourseId\x12#\n" +
+	"\rreview_status\x18\x14 \x01(\tR\freviewStatus\x12\x1f\n" +
+	"\vreviewer_id\x18\x15 \x01(\tR\n" +
+	"reviewerId\x12%\n" +
+	"\x0ereview_comment\x18\x16 \x01(\tR\rreviewComment\x12;\n" +
+	"\vreviewed_at\x18\x17 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"reviewedAt\x12\x18\n" +
+	"\aversion\x18\x18 \x01(\x05R\aversionJ\x04\b\n" +
+	"\x10\v\"\xbf\x01\n" +
+	"\x10QuestionCitation\x12\x19\n" +
+	"\bchunk_id\x18\x01 \x01(\tR\achunkId\x12\x1f\n" +
+	"\vmaterial_id\x18\x02 \x01(\tR\n" +
+	"materialId\x12\x12\n" +
+	"\x04page\x18\x03 \x01(\x05R\x04page\x12\x1a\n" +
+	"\btimecode\x18\x04 \x01(\tR\btimecode\x12\x18\n" +
+	"\asnippet\x18\x05 \x01(\tR\asnippet\x12%\n" +
+	"\x0ematerial_title\x18\x06 \x01(\tR\rmaterialTitle\"\xb7\x01\n" +
+	"\fQuestionPart\x12\x14\n" +
+	"\x05label\x18\x01 \x01(\tR\x05label\x12%\n" +
+	"\x0ecorrect_answer\x18\x02 \x01(\tR\rcorrectAnswer\x12%\n" +
+	"\x0eanswer_aliases\x18\x03 \x03(\tR\ranswerAliases\x12+\n" +
+	"\x11numeric_tolerance\x18\x04 \x01(\x01R\x10numericTolerance\x12\x16\n" +
+	"\x06weight\x18\x05 \x01(\x02R\x06weight\"J\n" +
 	"\x0eGetQuizRequest\x12\x1f\n" +
 	"\vquestion_id\x18\x01 \x01(\tR\n" +
-	"questionId\"q\n" +
+	"questionId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\"q\n" +
 	"\x0fGetQuizResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
 	"\amessage\x18\x02 \x01(\tR\amessage\x12*\n" +
-	"\bquestion\x18\x03 \x01(\v2\x0e.quiz.QuestionR\bquestion\"\xde\x01\n" +
+	"\bquestion\x18\x03 \x01(\v2\x0e.quiz.QuestionR\bquestion\"\xfb\x01\n" +
 	"\x12ListQuizzesRequest\x12\x17\n" +
 	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1f\n" +
 	"\vmaterial_id\x18\x02 \x01(\tR\n" +
@@ -1286,19 +6461,24 @@ const file_quiz_quiz_proto_rawDesc = "" +
 	"difficulty\x18\x04 \x01(\x0e2\x15.quiz.DifficultyLevelR\n" +
 	"difficulty\x12\x12\n" +
 	"\x04page\x18\x05 \x01(\x05R\x04page\x12\x1b\n" +
-	"\tpage_size\x18\x06 \x01(\x05R\bpageSize\"\xbe\x01\n" +
+	"\tpage_size\x18\x06 \x01(\x05R\bpageSize\x12\x1b\n" +
+	"\tcourse_id\x18\a \x01(\tR\bcourseId\"\xbe\x01\n" +
 	"\x13ListQuizzesResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
 	"\amessage\x18\x02 \x01(\tR\amessage\x12,\n" +
 	"\tquestions\x18\x03 \x03(\v2\x0e.quiz.QuestionR\tquestions\x12\x14\n" +
 	"\x05total\x18\x04 \x01(\x05R\x05total\x12\x12\n" +
 	"\x04page\x18\x05 \x01(\x05R\x04page\x12\x1b\n" +
-	"\tpage_size\x18\x06 \x01(\x05R\bpageSize\"g\n" +
+	"\tpage_size\x18\x06 \x01(\x05R\bpageSize\"\xcd\x01\n" +
 	"\x13SubmitAnswerRequest\x12\x1f\n" +
 	"\vquestion_id\x18\x01 \x01(\tR\n" +
 	"questionId\x12\x17\n" +
 	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x16\n" +
-	"\x06answer\x18\x03 \x01(\tR\x06answer\"\xc8\x01\n" +
+	"\x06answer\x18\x03 \x01(\tR\x06answer\x12!\n" +
+	"\fpart_answers\x18\x04 \x03(\tR\vpartAnswers\x12\"\n" +
+	"\rtime_spent_ms\x18\x05 \x01(\x03R\vtimeSpentMs\x12\x1d\n" +
+	"\n" +
+	"attempt_id\x18\x06 \x01(\tR\tattemptId\"\x9c\x04\n" +
 	"\x14SubmitAnswerResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
 	"\amessage\x18\x02 \x01(\tR\amessage\x12\x1d\n" +
@@ -1306,7 +6486,45 @@ const file_quiz_quiz_proto_rawDesc = "" +
 	"is_correct\x18\x03 \x01(\bR\tisCorrect\x12\x14\n" +
 	"\x05score\x18\x04 \x01(\x02R\x05score\x12%\n" +
 	"\x0ecorrect_answer\x18\x05 \x01(\tR\rcorrectAnswer\x12 \n" +
-	"\vexplanation\x18\x06 \x01(\tR\vexplanation\"\xd1\x01\n" +
+	"\vexplanation\x18\x06 \x01(\tR\vexplanation\x125\n" +
+	"\vblank_match\x18\a \x01(\v2\x14.quiz.FillBlankMatchR\n" +
+	"blankMatch\x123\n" +
+	"\fpart_results\x18\b \x03(\v2\x10.quiz.PartResultR\vpartResults\x12\x1b\n" +
+	"\traw_score\x18\t \x01(\x02R\brawScore\x12%\n" +
+	"\x0epass_threshold\x18\n" +
+	" \x01(\x02R\rpassThreshold\x12%\n" +
+	"\x0eattempt_number\x18\v \x01(\x05R\rattemptNumber\x12\x1d\n" +
+	"\n" +
+	"attempt_id\x18\f \x01(\tR\tattemptId\x125\n" +
+	"\n" +
+	"similarity\x18\r \x01(\v2\x15.quiz.SimilarityCheckR\n" +
+	"similarity\x12%\n" +
+	"\x0egrading_status\x18\x0e \x01(\tR\rgradingStatus\"\xf2\x01\n" +
+	"\x0fSimilarityCheck\x12/\n" +
+	"\x13material_similarity\x18\x01 \x01(\x02R\x12materialSimilarity\x12*\n" +
+	"\x11material_chunk_id\x18\x02 \x01(\tR\x0fmaterialChunkId\x12'\n" +
+	"\x0fpeer_similarity\x18\x03 \x01(\x02R\x0epeerSimilarity\x12%\n" +
+	"\x0epeers_compared\x18\x04 \x01(\x05R\rpeersCompared\x12\x18\n" +
+	"\aflagged\x18\x05 \x01(\bR\aflagged\x12\x18\n" +
+	"\areasons\x18\x06 \x03(\tR\areasons\"\xcd\x01\n" +
+	"\n" +
+	"PartResult\x12\x14\n" +
+	"\x05index\x18\x01 \x01(\x05R\x05index\x12\x16\n" +
+	"\x06answer\x18\x02 \x01(\tR\x06answer\x12\x1d\n" +
+	"\n" +
+	"is_correct\x18\x03 \x01(\bR\tisCorrect\x12\x14\n" +
+	"\x05score\x18\x04 \x01(\x02R\x05score\x12\x16\n" +
+	"\x06weight\x18\x05 \x01(\x02R\x06weight\x12%\n" +
+	"\x0ecorrect_answer\x18\x06 \x01(\tR\rcorrectAnswer\x12\x1d\n" +
+	"\n" +
+	"match_type\x18\a \x01(\tR\tmatchType\"\xc0\x01\n" +
+	"\x0eFillBlankMatch\x12\x18\n" +
+	"\amatched\x18\x01 \x01(\bR\amatched\x12\x1d\n" +
+	"\n" +
+	"match_type\x18\x02 \x01(\tR\tmatchType\x12%\n" +
+	"\x0ematched_answer\x18\x03 \x01(\tR\rmatchedAnswer\x12+\n" +
+	"\x11normalized_answer\x18\x04 \x01(\tR\x10normalizedAnswer\x12!\n" +
+	"\fnumeric_diff\x18\x05 \x01(\x01R\vnumericDiff\"\xe0\x05\n" +
 	"\n" +
 	"UserAnswer\x12\x1b\n" +
 	"\tanswer_id\x18\x01 \x01(\tR\banswerId\x12\x1f\n" +
@@ -1316,9 +6534,26 @@ const file_quiz_quiz_proto_rawDesc = "" +
 	"\x06answer\x18\x04 \x01(\tR\x06answer\x12\x1d\n" +
 	"\n" +
 	"is_correct\x18\x05 \x01(\bR\tisCorrect\x12\x14\n" +
-	"\x05score\x18\x06 \x01(\x02R\x05score\x12\x1f\n" +
-	"\vanswered_at\x18\a \x01(\tR\n" +
-	"answeredAt\"e\n" +
+	"\x05score\x18\x06 \x01(\x02R\x05score\x12;\n" +
+	"\vanswered_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"answeredAt\x123\n" +
+	"\fpart_results\x18\b \x03(\v2\x10.quiz.PartResultR\vpartResults\x12\"\n" +
+	"\rtime_spent_ms\x18\t \x01(\x03R\vtimeSpentMs\x12)\n" +
+	"\x10question_version\x18\v \x01(\x05R\x0fquestionVersion\x12\x1d\n" +
+	"\n" +
+	"attempt_id\x18\f \x01(\tR\tattemptId\x12!\n" +
+	"\foption_order\x18\r \x03(\x05R\voptionOrder\x125\n" +
+	"\n" +
+	"similarity\x18\x0e \x01(\v2\x15.quiz.SimilarityCheckR\n" +
+	"similarity\x12%\n" +
+	"\x0egrading_status\x18\x0f \x01(\tR\rgradingStatus\x12'\n" +
+	"\x0fsuggested_score\x18\x10 \x01(\x02R\x0esuggestedScore\x12\x1f\n" +
+	"\vai_feedback\x18\x11 \x01(\tR\n" +
+	"aiFeedback\x12\x1b\n" +
+	"\tgraded_by\x18\x12 \x01(\tR\bgradedBy\x127\n" +
+	"\tgraded_at\x18\x13 \x01(\v2\x1a.google.protobuf.TimestampR\bgradedAt\x12'\n" +
+	"\x0fgrading_comment\x18\x14 \x01(\tR\x0egradingCommentJ\x04\b\a\x10\b\"e\n" +
 	"\x19GetUserQuizHistoryRequest\x12\x17\n" +
 	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x12\n" +
 	"\x04page\x18\x02 \x01(\x05R\x04page\x12\x1b\n" +
@@ -1342,7 +6577,362 @@ const file_quiz_quiz_proto_rawDesc = "" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
 	"\amessage\x18\x02 \x01(\tR\amessage\x12/\n" +
 	"\x05stats\x18\x03 \x03(\v2\x19.quiz.KnowledgePointStatsR\x05stats\x12)\n" +
-	"\x10overall_accuracy\x18\x04 \x01(\x02R\x0foverallAccuracy*`\n" +
+	"\x10overall_accuracy\x18\x04 \x01(\x02R\x0foverallAccuracy\"\xa7\x04\n" +
+	"\x11QuestionAnalytics\x12\x1f\n" +
+	"\vquestion_id\x18\x01 \x01(\tR\n" +
+	"questionId\x12\x1f\n" +
+	"\vmaterial_id\x18\x02 \x01(\tR\n" +
+	"materialId\x12#\n" +
+	"\rattempt_count\x18\x03 \x01(\x05R\fattemptCount\x12#\n" +
+	"\rcorrect_count\x18\x04 \x01(\x05R\fcorrectCount\x12!\n" +
+	"\fsuccess_rate\x18\x05 \x01(\x02R\vsuccessRate\x12\x1b\n" +
+	"\tavg_score\x18\x06 \x01(\x02R\bavgScore\x12\x1e\n" +
+	"\vavg_time_ms\x18\a \x01(\x03R\tavgTimeMs\x12F\n" +
+	"\x13original_difficulty\x18\b \x01(\x0e2\x15.quiz.DifficultyLevelR\x12originalDifficulty\x12D\n" +
+	"\x12current_difficulty\x18\t \x01(\x0e2\x15.quiz.DifficultyLevelR\x11currentDifficulty\x12H\n" +
+	"\x14suggested_difficulty\x18\n" +
+	" \x01(\x0e2\x15.quiz.DifficultyLevelR\x13suggestedDifficulty\x12H\n" +
+	"\x12last_calibrated_at\x18\f \x01(\v2\x1a.google.protobuf.TimestampR\x10lastCalibratedAtJ\x04\b\v\x10\f\"\xcc\x01\n" +
+	"\x1bGetQuestionAnalyticsRequest\x12\x1f\n" +
+	"\vquestion_id\x18\x01 \x01(\tR\n" +
+	"questionId\x12\x1f\n" +
+	"\vmaterial_id\x18\x02 \x01(\tR\n" +
+	"materialId\x12!\n" +
+	"\fmin_attempts\x18\x03 \x01(\x05R\vminAttempts\x12\x12\n" +
+	"\x04page\x18\x04 \x01(\x05R\x04page\x12\x1b\n" +
+	"\tpage_size\x18\x05 \x01(\x05R\bpageSize\x12\x17\n" +
+	"\auser_id\x18\x06 \x01(\tR\x06userId\"\x9f\x01\n" +
+	"\x1cGetQuestionAnalyticsResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x125\n" +
+	"\tanalytics\x18\x03 \x03(\v2\x17.quiz.QuestionAnalyticsR\tanalytics\x12\x14\n" +
+	"\x05total\x18\x04 \x01(\x05R\x05total\"\xad\x02\n" +
+	"\vMistakeItem\x12*\n" +
+	"\bquestion\x18\x01 \x01(\v2\x0e.quiz.QuestionR\bquestion\x12\x1f\n" +
+	"\vwrong_count\x18\x02 \x01(\x05R\n" +
+	"wrongCount\x12%\n" +
+	"\x0ecorrect_streak\x18\x03 \x01(\x05R\rcorrectStreak\x12\x1a\n" +
+	"\bmastered\x18\x04 \x01(\bR\bmastered\x12>\n" +
+	"\rlast_wrong_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\vlastWrongAt\x12B\n" +
+	"\x0flast_attempt_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\rlastAttemptAtJ\x04\b\x05\x10\x06J\x04\b\x06\x10\a\"`\n" +
+	"\fMistakeGroup\x12'\n" +
+	"\x0fknowledge_point\x18\x01 \x01(\tR\x0eknowledgePoint\x12'\n" +
+	"\x05items\x18\x02 \x03(\v2\x11.quiz.MistakeItemR\x05items\"\xab\x01\n" +
+	"\x13ListMistakesRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1f\n" +
+	"\vmaterial_id\x18\x02 \x01(\tR\n" +
+	"materialId\x12)\n" +
+	"\x10include_mastered\x18\x03 \x01(\bR\x0fincludeMastered\x12\x12\n" +
+	"\x04page\x18\x04 \x01(\x05R\x04page\x12\x1b\n" +
+	"\tpage_size\x18\x05 \x01(\x05R\bpageSize\"\x8c\x01\n" +
+	"\x14ListMistakesResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12*\n" +
+	"\x06groups\x18\x03 \x03(\v2\x12.quiz.MistakeGroupR\x06groups\x12\x14\n" +
+	"\x05total\x18\x04 \x01(\x05R\x05total\"\x93\x01\n" +
+	"\x18GetRetryQuestionsRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1f\n" +
+	"\vmaterial_id\x18\x02 \x01(\tR\n" +
+	"materialId\x12'\n" +
+	"\x0fknowledge_point\x18\x03 \x01(\tR\x0eknowledgePoint\x12\x14\n" +
+	"\x05count\x18\x04 \x01(\x05R\x05count\"\x96\x01\n" +
+	"\x19GetRetryQuestionsResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12'\n" +
+	"\x05items\x18\x03 \x03(\v2\x11.quiz.MistakeItemR\x05items\x12\x1c\n" +
+	"\tremaining\x18\x04 \x01(\x05R\tremaining\"\x90\x02\n" +
+	"\rGradingPolicy\x12\x1d\n" +
+	"\n" +
+	"scope_type\x18\x01 \x01(\tR\tscopeType\x12\x19\n" +
+	"\bscope_id\x18\x02 \x01(\tR\ascopeId\x12%\n" +
+	"\x0epass_threshold\x18\x03 \x01(\x02R\rpassThreshold\x12.\n" +
+	"\x13partial_credit_mode\x18\x04 \x01(\tR\x11partialCreditMode\x12#\n" +
+	"\rretry_penalty\x18\x05 \x01(\x02R\fretryPenalty\x12*\n" +
+	"\x11max_retry_penalty\x18\x06 \x01(\x02R\x0fmaxRetryPenalty\x12\x1d\n" +
+	"\n" +
+	"updated_by\x18\a \x01(\tR\tupdatedBy\"W\n" +
+	"\x17GetGradingPolicyRequest\x12\x1f\n" +
+	"\vmaterial_id\x18\x01 \x01(\tR\n" +
+	"materialId\x12\x1b\n" +
+	"\tcourse_id\x18\x02 \x01(\tR\bcourseId\"{\n" +
+	"\x18GetGradingPolicyResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12+\n" +
+	"\x06policy\x18\x03 \x01(\v2\x13.quiz.GradingPolicyR\x06policy\"_\n" +
+	"\x17SetGradingPolicyRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12+\n" +
+	"\x06policy\x18\x02 \x01(\v2\x13.quiz.GradingPolicyR\x06policy\"{\n" +
+	"\x18SetGradingPolicyResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12+\n" +
+	"\x06policy\x18\x03 \x01(\v2\x13.quiz.GradingPolicyR\x06policy\"\xf9\x02\n" +
+	"\tQuizShare\x12\x19\n" +
+	"\bshare_id\x18\x01 \x01(\tR\ashareId\x12\x19\n" +
+	"\bowner_id\x18\x02 \x01(\tR\aownerId\x12\x14\n" +
+	"\x05title\x18\x03 \x01(\tR\x05title\x12\x1f\n" +
+	"\vmaterial_id\x18\x04 \x01(\tR\n" +
+	"materialId\x12!\n" +
+	"\fquestion_ids\x18\x05 \x03(\tR\vquestionIds\x12'\n" +
+	"\x0fallow_anonymous\x18\x06 \x01(\bR\x0eallowAnonymous\x129\n" +
+	"\n" +
+	"expires_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\x12\x18\n" +
+	"\arevoked\x18\b \x01(\bR\arevoked\x129\n" +
+	"\n" +
+	"created_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12#\n" +
+	"\rattempt_count\x18\n" +
+	" \x01(\x05R\fattemptCount\"\xd5\x01\n" +
+	"\x16CreateQuizShareRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12\x1f\n" +
+	"\vmaterial_id\x18\x03 \x01(\tR\n" +
+	"materialId\x12!\n" +
+	"\fquestion_ids\x18\x04 \x03(\tR\vquestionIds\x12'\n" +
+	"\x0fallow_anonymous\x18\x05 \x01(\bR\x0eallowAnonymous\x12\x1f\n" +
+	"\vttl_seconds\x18\x06 \x01(\x03R\n" +
+	"ttlSeconds\"n\n" +
+	"\x11QuizShareResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12%\n" +
+	"\x05share\x18\x03 \x01(\v2\x0f.quiz.QuizShareR\x05share\"I\n" +
+	"\x13GetQuizShareRequest\x12\x19\n" +
+	"\bshare_id\x18\x01 \x01(\tR\ashareId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\"\x9f\x01\n" +
+	"\x14GetQuizShareResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12%\n" +
+	"\x05share\x18\x03 \x01(\v2\x0f.quiz.QuizShareR\x05share\x12,\n" +
+	"\tquestions\x18\x04 \x03(\v2\x0e.quiz.QuestionR\tquestions\"a\n" +
+	"\x15ListQuizSharesRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x12\n" +
+	"\x04page\x18\x02 \x01(\x05R\x04page\x12\x1b\n" +
+	"\tpage_size\x18\x03 \x01(\x05R\bpageSize\"\x8b\x01\n" +
+	"\x16ListQuizSharesResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12'\n" +
+	"\x06shares\x18\x03 \x03(\v2\x0f.quiz.QuizShareR\x06shares\x12\x14\n" +
+	"\x05total\x18\x04 \x01(\x05R\x05total\"L\n" +
+	"\x16RevokeQuizShareRequest\x12\x19\n" +
+	"\bshare_id\x18\x01 \x01(\tR\ashareId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\"\x91\x01\n" +
+	"\x0fQuizShareAnswer\x12\x1f\n" +
+	"\vquestion_id\x18\x01 \x01(\tR\n" +
+	"questionId\x12\x16\n" +
+	"\x06answer\x18\x02 \x01(\tR\x06answer\x12!\n" +
+	"\fpart_answers\x18\x03 \x03(\tR\vpartAnswers\x12\"\n" +
+	"\rtime_spent_ms\x18\x04 \x01(\x03R\vtimeSpentMs\"\xee\x01\n" +
+	"\x15QuizShareAnswerResult\x12\x1f\n" +
+	"\vquestion_id\x18\x01 \x01(\tR\n" +
+	"questionId\x12\x16\n" +
+	"\x06answer\x18\x02 \x01(\tR\x06answer\x12\x1d\n" +
+	"\n" +
+	"is_correct\x18\x03 \x01(\bR\tisCorrect\x12\x14\n" +
+	"\x05score\x18\x04 \x01(\x02R\x05score\x12)\n" +
+	"\x10question_version\x18\x05 \x01(\x05R\x0fquestionVersion\x12\"\n" +
+	"\rtime_spent_ms\x18\x06 \x01(\x03R\vtimeSpentMs\x12\x18\n" +
+	"\apending\x18\a \x01(\bR\apending\"\xba\x03\n" +
+	"\x10QuizShareAttempt\x12\x1d\n" +
+	"\n" +
+	"attempt_id\x18\x01 \x01(\tR\tattemptId\x12\x19\n" +
+	"\bshare_id\x18\x02 \x01(\tR\ashareId\x12\x17\n" +
+	"\auser_id\x18\x03 \x01(\tR\x06userId\x12\x1a\n" +
+	"\bnickname\x18\x04 \x01(\tR\bnickname\x12\x14\n" +
+	"\x05score\x18\x05 \x01(\x02R\x05score\x12#\n" +
+	"\rcorrect_count\x18\x06 \x01(\x05R\fcorrectCount\x12'\n" +
+	"\x0ftotal_questions\x18\a \x01(\x05R\x0etotalQuestions\x125\n" +
+	"\aresults\x18\b \x03(\v2\x1b.quiz.QuizShareAnswerResultR\aresults\x12=\n" +
+	"\fsubmitted_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\vsubmittedAt\x124\n" +
+	"\ttelemetry\x18\n" +
+	" \x01(\v2\x16.quiz.AttemptTelemetryR\ttelemetry\x12'\n" +
+	"\x05flags\x18\v \x03(\v2\x11.quiz.AttemptFlagR\x05flags\"\x9d\x01\n" +
+	"\x10AttemptTelemetry\x12\x1f\n" +
+	"\vduration_ms\x18\x01 \x01(\x03R\n" +
+	"durationMs\x123\n" +
+	"\ffocus_events\x18\x02 \x03(\v2\x10.quiz.FocusEventR\vfocusEvents\x123\n" +
+	"\fpaste_events\x18\x03 \x03(\v2\x10.quiz.PasteEventR\vpasteEvents\"c\n" +
+	"\n" +
+	"FocusEvent\x12\x1f\n" +
+	"\vquestion_id\x18\x01 \x01(\tR\n" +
+	"questionId\x12\x1b\n" +
+	"\toffset_ms\x18\x02 \x01(\x03R\boffsetMs\x12\x17\n" +
+	"\aaway_ms\x18\x03 \x01(\x03R\x06awayMs\"b\n" +
+	"\n" +
+	"PasteEvent\x12\x1f\n" +
+	"\vquestion_id\x18\x01 \x01(\tR\n" +
+	"questionId\x12\x1b\n" +
+	"\toffset_ms\x18\x02 \x01(\x03R\boffsetMs\x12\x16\n" +
+	"\x06length\x18\x03 \x01(\x05R\x06length\"Z\n" +
+	"\vAttemptFlag\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\tR\x04type\x12\x1f\n" +
+	"\vquestion_id\x18\x02 \x01(\tR\n" +
+	"questionId\x12\x16\n" +
+	"\x06detail\x18\x03 \x01(\tR\x06detail\"\xd6\x01\n" +
+	"\x1dSubmitQuizShareAttemptRequest\x12\x19\n" +
+	"\bshare_id\x18\x01 \x01(\tR\ashareId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x1a\n" +
+	"\bnickname\x18\x03 \x01(\tR\bnickname\x12/\n" +
+	"\aanswers\x18\x04 \x03(\v2\x15.quiz.QuizShareAnswerR\aanswers\x124\n" +
+	"\ttelemetry\x18\x05 \x01(\v2\x16.quiz.AttemptTelemetryR\ttelemetry\"\x86\x01\n" +
+	"\x1eSubmitQuizShareAttemptResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x120\n" +
+	"\aattempt\x18\x03 \x01(\v2\x16.quiz.QuizShareAttemptR\aattempt\"\xa6\x01\n" +
+	"\x1cListQuizShareAttemptsRequest\x12\x19\n" +
+	"\bshare_id\x18\x01 \x01(\tR\ashareId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x12\n" +
+	"\x04page\x18\x03 \x01(\x05R\x04page\x12\x1b\n" +
+	"\tpage_size\x18\x04 \x01(\x05R\bpageSize\x12!\n" +
+	"\fflagged_only\x18\x05 \x01(\bR\vflaggedOnly\"\x9d\x01\n" +
+	"\x1dListQuizShareAttemptsResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x122\n" +
+	"\battempts\x18\x03 \x03(\v2\x16.quiz.QuizShareAttemptR\battempts\x12\x14\n" +
+	"\x05total\x18\x04 \x01(\x05R\x05total\"|\n" +
+	"\x1dAssignQuestionReviewerRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12!\n" +
+	"\fquestion_ids\x18\x02 \x03(\tR\vquestionIds\x12\x1f\n" +
+	"\vreviewer_id\x18\x03 \x01(\tR\n" +
+	"reviewerId\"\x85\x01\n" +
+	"\x15ReviewQuestionRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1f\n" +
+	"\vquestion_id\x18\x02 \x01(\tR\n" +
+	"questionId\x12\x18\n" +
+	"\aapprove\x18\x03 \x01(\bR\aapprove\x12\x18\n" +
+	"\acomment\x18\x04 \x01(\tR\acomment\"\x90\x01\n" +
+	"\x1bBulkApproveQuestionsRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1b\n" +
+	"\tcourse_id\x18\x02 \x01(\tR\bcourseId\x12!\n" +
+	"\fquestion_ids\x18\x03 \x03(\tR\vquestionIds\x12\x18\n" +
+	"\acomment\x18\x04 \x01(\tR\acomment\"E\n" +
+	"\n" +
+	"ReviewSkip\x12\x1f\n" +
+	"\vquestion_id\x18\x01 \x01(\tR\n" +
+	"questionId\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\"\xa7\x01\n" +
+	"\x17ReviewQuestionsResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12,\n" +
+	"\tquestions\x18\x03 \x03(\v2\x0e.quiz.QuestionR\tquestions\x12*\n" +
+	"\askipped\x18\x04 \x03(\v2\x10.quiz.ReviewSkipR\askipped\"\x97\x01\n" +
+	"\x16ListReviewQueueRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1b\n" +
+	"\tcourse_id\x18\x02 \x01(\tR\bcourseId\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\x12\x12\n" +
+	"\x04page\x18\x04 \x01(\x05R\x04page\x12\x1b\n" +
+	"\tpage_size\x18\x05 \x01(\x05R\bpageSize\"\x91\x01\n" +
+	"\x17ListReviewQueueResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12,\n" +
+	"\tquestions\x18\x03 \x03(\v2\x0e.quiz.QuestionR\tquestions\x12\x14\n" +
+	"\x05total\x18\x04 \x01(\x05R\x05total\"\xfa\x03\n" +
+	"\x15UpdateQuestionRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1f\n" +
+	"\vquestion_id\x18\x02 \x01(\tR\n" +
+	"questionId\x12)\n" +
+	"\x10expected_version\x18\x03 \x01(\x05R\x0fexpectedVersion\x12\x18\n" +
+	"\acontent\x18\x04 \x01(\tR\acontent\x12\x18\n" +
+	"\aoptions\x18\x05 \x03(\tR\aoptions\x12%\n" +
+	"\x0ecorrect_answer\x18\x06 \x01(\tR\rcorrectAnswer\x12%\n" +
+	"\x0eanswer_aliases\x18\a \x03(\tR\ranswerAliases\x12+\n" +
+	"\x11numeric_tolerance\x18\b \x01(\x01R\x10numericTolerance\x12(\n" +
+	"\x05parts\x18\t \x03(\v2\x12.quiz.QuestionPartR\x05parts\x12 \n" +
+	"\vexplanation\x18\n" +
+	" \x01(\tR\vexplanation\x125\n" +
+	"\n" +
+	"difficulty\x18\v \x01(\x0e2\x15.quiz.DifficultyLevelR\n" +
+	"difficulty\x12)\n" +
+	"\x10knowledge_points\x18\f \x03(\tR\x0fknowledgePoints\x12\x1f\n" +
+	"\vchange_note\x18\r \x01(\tR\n" +
+	"changeNote\"\x9f\x01\n" +
+	"\x16UpdateQuestionResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12*\n" +
+	"\bquestion\x18\x03 \x01(\v2\x0e.quiz.QuestionR\bquestion\x12%\n" +
+	"\x0echanged_fields\x18\x04 \x03(\tR\rchangedFields\"\xf1\x04\n" +
+	"\x0fQuestionVersion\x12\x1f\n" +
+	"\vquestion_id\x18\x01 \x01(\tR\n" +
+	"questionId\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\x05R\aversion\x12&\n" +
+	"\x04type\x18\x03 \x01(\x0e2\x12.quiz.QuestionTypeR\x04type\x12\x18\n" +
+	"\acontent\x18\x04 \x01(\tR\acontent\x12\x18\n" +
+	"\aoptions\x18\x05 \x03(\tR\aoptions\x12%\n" +
+	"\x0ecorrect_answer\x18\x06 \x01(\tR\rcorrectAnswer\x12%\n" +
+	"\x0eanswer_aliases\x18\a \x03(\tR\ranswerAliases\x12+\n" +
+	"\x11numeric_tolerance\x18\b \x01(\x01R\x10numericTolerance\x12(\n" +
+	"\x05parts\x18\t \x03(\v2\x12.quiz.QuestionPartR\x05parts\x12 \n" +
+	"\vexplanation\x18\n" +
+	" \x01(\tR\vexplanation\x125\n" +
+	"\n" +
+	"difficulty\x18\v \x01(\x0e2\x15.quiz.DifficultyLevelR\n" +
+	"difficulty\x12)\n" +
+	"\x10knowledge_points\x18\f \x03(\tR\x0fknowledgePoints\x12\x1b\n" +
+	"\teditor_id\x18\r \x01(\tR\beditorId\x12%\n" +
+	"\x0echanged_fields\x18\x0e \x03(\tR\rchangedFields\x12\x1f\n" +
+	"\vchange_note\x18\x0f \x01(\tR\n" +
+	"changeNote\x129\n" +
+	"\n" +
+	"created_at\x18\x10 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"W\n" +
+	"\x1bListQuestionVersionsRequest\x12\x1f\n" +
+	"\vquestion_id\x18\x01 \x01(\tR\n" +
+	"questionId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\"\x85\x01\n" +
+	"\x1cListQuestionVersionsResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x121\n" +
+	"\bversions\x18\x03 \x03(\v2\x15.quiz.QuestionVersionR\bversions\"o\n" +
+	"\x19GetQuestionVersionRequest\x12\x1f\n" +
+	"\vquestion_id\x18\x01 \x01(\tR\n" +
+	"questionId\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\x05R\aversion\x12\x17\n" +
+	"\auser_id\x18\x03 \x01(\tR\x06userId\"\x81\x01\n" +
+	"\x1aGetQuestionVersionResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12/\n" +
+	"\aversion\x18\x03 \x01(\v2\x15.quiz.QuestionVersionR\aversion\"W\n" +
+	"\x1bStartQuestionAttemptRequest\x12\x1f\n" +
+	"\vquestion_id\x18\x01 \x01(\tR\n" +
+	"questionId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\"\xc8\x01\n" +
+	"\x1cStartQuestionAttemptResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12\x1d\n" +
+	"\n" +
+	"attempt_id\x18\x03 \x01(\tR\tattemptId\x12*\n" +
+	"\bquestion\x18\x04 \x01(\v2\x0e.quiz.QuestionR\bquestion\x12)\n" +
+	"\x10options_shuffled\x18\x05 \x01(\bR\x0foptionsShuffled\"\xe5\x01\n" +
+	"\x17ListGradingQueueRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1f\n" +
+	"\vmaterial_id\x18\x02 \x01(\tR\n" +
+	"materialId\x12\x1b\n" +
+	"\tcourse_id\x18\x03 \x01(\tR\bcourseId\x12\x1f\n" +
+	"\vquestion_id\x18\x04 \x01(\tR\n" +
+	"questionId\x12!\n" +
+	"\fflagged_only\x18\x05 \x01(\bR\vflaggedOnly\x12\x12\n" +
+	"\x04page\x18\x06 \x01(\x05R\x04page\x12\x1b\n" +
+	"\tpage_size\x18\a \x01(\x05R\bpageSize\"h\n" +
+	"\x10GradingQueueItem\x12(\n" +
+	"\x06answer\x18\x01 \x01(\v2\x10.quiz.UserAnswerR\x06answer\x12*\n" +
+	"\bquestion\x18\x02 \x01(\v2\x0e.quiz.QuestionR\bquestion\"\x92\x01\n" +
+	"\x18ListGradingQueueResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12,\n" +
+	"\x05items\x18\x03 \x03(\v2\x16.quiz.GradingQueueItemR\x05items\x12\x14\n" +
+	"\x05total\x18\x04 \x01(\x05R\x05total\"r\n" +
+	"\vAnswerGrade\x12\x1b\n" +
+	"\tanswer_id\x18\x01 \x01(\tR\banswerId\x12\x16\n" +
+	"\x06accept\x18\x02 \x01(\bR\x06accept\x12\x14\n" +
+	"\x05score\x18\x03 \x01(\x02R\x05score\x12\x18\n" +
+	"\acomment\x18\x04 \x01(\tR\acomment\"Y\n" +
+	"\x13GradeAnswersRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12)\n" +
+	"\x06grades\x18\x02 \x03(\v2\x11.quiz.AnswerGradeR\x06grades\"B\n" +
+	"\vGradingSkip\x12\x1b\n" +
+	"\tanswer_id\x18\x01 \x01(\tR\banswerId\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\"\xa3\x01\n" +
+	"\x14GradeAnswersResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12*\n" +
+	"\aanswers\x18\x03 \x03(\v2\x10.quiz.UserAnswerR\aanswers\x12+\n" +
+	"\askipped\x18\x04 \x03(\v2\x11.quiz.GradingSkipR\askipped*`\n" +
 	"\fQuestionType\x12\x13\n" +
 	"\x0fMULTIPLE_CHOICE\x10\x00\x12\x0e\n" +
 	"\n" +
@@ -1355,14 +6945,35 @@ const file_quiz_quiz_proto_rawDesc = "" +
 	"\x04EASY\x10\x00\x12\n" +
 	"\n" +
 	"\x06MEDIUM\x10\x01\x12\b\n" +
-	"\x04HARD\x10\x022\xc6\x03\n" +
+	"\x04HARD\x10\x022\xab\x11\n" +
 	"\vQuizService\x12E\n" +
 	"\fGenerateQuiz\x12\x19.quiz.GenerateQuizRequest\x1a\x1a.quiz.GenerateQuizResponse\x126\n" +
 	"\aGetQuiz\x12\x14.quiz.GetQuizRequest\x1a\x15.quiz.GetQuizResponse\x12B\n" +
 	"\vListQuizzes\x12\x18.quiz.ListQuizzesRequest\x1a\x19.quiz.ListQuizzesResponse\x12E\n" +
 	"\fSubmitAnswer\x12\x19.quiz.SubmitAnswerRequest\x1a\x1a.quiz.SubmitAnswerResponse\x12W\n" +
 	"\x12GetUserQuizHistory\x12\x1f.quiz.GetUserQuizHistoryRequest\x1a .quiz.GetUserQuizHistoryResponse\x12T\n" +
-	"\x11GetKnowledgeStats\x12\x1e.quiz.GetKnowledgeStatsRequest\x1a\x1f.quiz.GetKnowledgeStatsResponseB*Z(github.com/RigelNana/arkstudy/proto/quizb\x06proto3"
+	"\x11GetKnowledgeStats\x12\x1e.quiz.GetKnowledgeStatsRequest\x1a\x1f.quiz.GetKnowledgeStatsResponse\x12]\n" +
+	"\x14GetQuestionAnalytics\x12!.quiz.GetQuestionAnalyticsRequest\x1a\".quiz.GetQuestionAnalyticsResponse\x12E\n" +
+	"\fListMistakes\x12\x19.quiz.ListMistakesRequest\x1a\x1a.quiz.ListMistakesResponse\x12T\n" +
+	"\x11GetRetryQuestions\x12\x1e.quiz.GetRetryQuestionsRequest\x1a\x1f.quiz.GetRetryQuestionsResponse\x12Q\n" +
+	"\x10GetGradingPolicy\x12\x1d.quiz.GetGradingPolicyRequest\x1a\x1e.quiz.GetGradingPolicyResponse\x12Q\n" +
+	"\x10SetGradingPolicy\x12\x1d.quiz.SetGradingPolicyRequest\x1a\x1e.quiz.SetGradingPolicyResponse\x12H\n" +
+	"\x0fCreateQuizShare\x12\x1c.quiz.CreateQuizShareRequest\x1a\x17.quiz.QuizShareResponse\x12E\n" +
+	"\fGetQuizShare\x12\x19.quiz.GetQuizShareRequest\x1a\x1a.quiz.GetQuizShareResponse\x12K\n" +
+	"\x0eListQuizShares\x12\x1b.quiz.ListQuizSharesRequest\x1a\x1c.quiz.ListQuizSharesResponse\x12H\n" +
+	"\x0fRevokeQuizShare\x12\x1c.quiz.RevokeQuizShareRequest\x1a\x17.quiz.QuizShareResponse\x12c\n" +
+	"\x16SubmitQuizShareAttempt\x12#.quiz.SubmitQuizShareAttemptRequest\x1a$.quiz.SubmitQuizShareAttemptResponse\x12`\n" +
+	"\x15ListQuizShareAttempts\x12\".quiz.ListQuizShareAttemptsRequest\x1a#.quiz.ListQuizShareAttemptsResponse\x12\\\n" +
+	"\x16AssignQuestionReviewer\x12#.quiz.AssignQuestionReviewerRequest\x1a\x1d.quiz.ReviewQuestionsResponse\x12L\n" +
+	"\x0eReviewQuestion\x12\x1b.quiz.ReviewQuestionRequest\x1a\x1d.quiz.ReviewQuestionsResponse\x12X\n" +
+	"\x14BulkApproveQuestions\x12!.quiz.BulkApproveQuestionsRequest\x1a\x1d.quiz.ReviewQuestionsResponse\x12N\n" +
+	"\x0fListReviewQueue\x12\x1c.quiz.ListReviewQueueRequest\x1a\x1d.quiz.ListReviewQueueResponse\x12K\n" +
+	"\x0eUpdateQuestion\x12\x1b.quiz.UpdateQuestionRequest\x1a\x1c.quiz.UpdateQuestionResponse\x12]\n" +
+	"\x14ListQuestionVersions\x12!.quiz.ListQuestionVersionsRequest\x1a\".quiz.ListQuestionVersionsResponse\x12W\n" +
+	"\x12GetQuestionVersion\x12\x1f.quiz.GetQuestionVersionRequest\x1a .quiz.GetQuestionVersionResponse\x12]\n" +
+	"\x14StartQuestionAttempt\x12!.quiz.StartQuestionAttemptRequest\x1a\".quiz.StartQuestionAttemptResponse\x12Q\n" +
+	"\x10ListGradingQueue\x12\x1d.quiz.ListGradingQueueRequest\x1a\x1e.quiz.ListGradingQueueResponse\x12E\n" +
+	"\fGradeAnswers\x12\x19.quiz.GradeAnswersRequest\x1a\x1a.quiz.GradeAnswersResponseB*Z(github.com/RigelNana/arkstudy/proto/quizb\x06proto3"
 
 var (
 	file_quiz_quiz_proto_rawDescOnce sync.Once
@@ -1377,56 +6988,233 @@ func file_quiz_quiz_proto_rawDescGZIP() []byte {
 }
 
 var file_quiz_quiz_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
-var file_quiz_quiz_proto_msgTypes = make([]protoimpl.MessageInfo, 15)
+var file_quiz_quiz_proto_msgTypes = make([]protoimpl.MessageInfo, 80)
 var file_quiz_quiz_proto_goTypes = []any{
-	(QuestionType)(0),                  // 0: quiz.QuestionType
-	(DifficultyLevel)(0),               // 1: quiz.DifficultyLevel
-	(*GenerateQuizRequest)(nil),        // 2: quiz.GenerateQuizRequest
-	(*GenerateQuizResponse)(nil),       // 3: quiz.GenerateQuizResponse
-	(*Question)(nil),                   // 4: quiz.Question
-	(*GetQuizRequest)(nil),             // 5: quiz.GetQuizRequest
-	(*GetQuizResponse)(nil),            // 6: quiz.GetQuizResponse
-	(*ListQuizzesRequest)(nil),         // 7: quiz.ListQuizzesRequest
-	(*ListQuizzesResponse)(nil),        // 8: quiz.ListQuizzesResponse
-	(*SubmitAnswerRequest)(nil),        // 9: quiz.SubmitAnswerRequest
-	(*SubmitAnswerResponse)(nil),       // 10: quiz.SubmitAnswerResponse
-	(*UserAnswer)(nil),                 // 11: quiz.UserAnswer
-	(*GetUserQuizHistoryRequest)(nil),  // 12: quiz.GetUserQuizHistoryRequest
-	(*GetUserQuizHistoryResponse)(nil), // 13: quiz.GetUserQuizHistoryResponse
-	(*KnowledgePointStats)(nil),        // 14: quiz.KnowledgePointStats
-	(*GetKnowledgeStatsRequest)(nil),   // 15: quiz.GetKnowledgeStatsRequest
-	(*GetKnowledgeStatsResponse)(nil),  // 16: quiz.GetKnowledgeStatsResponse
+	(QuestionType)(0),                      // 0: quiz.QuestionType
+	(DifficultyLevel)(0),                   // 1: quiz.DifficultyLevel
+	(*GenerateQuizRequest)(nil),            // 2: quiz.GenerateQuizRequest
+	(*BlueprintSlot)(nil),                  // 3: quiz.BlueprintSlot
+	(*BlueprintSlotReport)(nil),            // 4: quiz.BlueprintSlotReport
+	(*BlueprintReport)(nil),                // 5: quiz.BlueprintReport
+	(*GenerateQuizResponse)(nil),           // 6: quiz.GenerateQuizResponse
+	(*Question)(nil),                       // 7: quiz.Question
+	(*QuestionCitation)(nil),               // 8: quiz.QuestionCitation
+	(*QuestionPart)(nil),                   // 9: quiz.QuestionPart
+	(*GetQuizRequest)(nil),                 // 10: quiz.GetQuizRequest
+	(*GetQuizResponse)(nil),                // 11: quiz.GetQuizResponse
+	(*ListQuizzesRequest)(nil),             // 12: quiz.ListQuizzesRequest
+	(*ListQuizzesResponse)(nil),            // 13: quiz.ListQuizzesResponse
+	(*SubmitAnswerRequest)(nil),            // 14: quiz.SubmitAnswerRequest
+	(*SubmitAnswerResponse)(nil),           // 15: quiz.SubmitAnswerResponse
+	(*SimilarityCheck)(nil),                // 16: quiz.SimilarityCheck
+	(*PartResult)(nil),                     // 17: quiz.PartResult
+	(*FillBlankMatch)(nil),                 // 18: quiz.FillBlankMatch
+	(*UserAnswer)(nil),                     // 19: quiz.UserAnswer
+	(*GetUserQuizHistoryRequest)(nil),      // 20: quiz.GetUserQuizHistoryRequest
+	(*GetUserQuizHistoryResponse)(nil),     // 21: quiz.GetUserQuizHistoryResponse
+	(*KnowledgePointStats)(nil),            // 22: quiz.KnowledgePointStats
+	(*GetKnowledgeStatsRequest)(nil),       // 23: quiz.GetKnowledgeStatsRequest
+	(*GetKnowledgeStatsResponse)(nil),      // 24: quiz.GetKnowledgeStatsResponse
+	(*QuestionAnalytics)(nil),              // 25: quiz.QuestionAnalytics
+	(*GetQuestionAnalyticsRequest)(nil),    // 26: quiz.GetQuestionAnalyticsRequest
+	(*GetQuestionAnalyticsResponse)(nil),   // 27: quiz.GetQuestionAnalyticsResponse
+	(*MistakeItem)(nil),                    // 28: quiz.MistakeItem
+	(*MistakeGroup)(nil),                   // 29: quiz.MistakeGroup
+	(*ListMistakesRequest)(nil),            // 30: quiz.ListMistakesRequest
+	(*ListMistakesResponse)(nil),           // 31: quiz.ListMistakesResponse
+	(*GetRetryQuestionsRequest)(nil),       // 32: quiz.GetRetryQuestionsRequest
+	(*GetRetryQuestionsResponse)(nil),      // 33: quiz.GetRetryQuestionsResponse
+	(*GradingPolicy)(nil),                  // 34: quiz.GradingPolicy
+	(*GetGradingPolicyRequest)(nil),        // 35: quiz.GetGradingPolicyRequest
+	(*GetGradingPolicyResponse)(nil),       // 36: quiz.GetGradingPolicyResponse
+	(*SetGradingPolicyRequest)(nil),        // 37: quiz.SetGradingPolicyRequest
+	(*SetGradingPolicyResponse)(nil),       // 38: quiz.SetGradingPolicyResponse
+	(*QuizShare)(nil),                      // 39: quiz.QuizShare
+	(*CreateQuizShareRequest)(nil),         // 40: quiz.CreateQuizShareRequest
+	(*QuizShareResponse)(nil),              // 41: quiz.QuizShareResponse
+	(*GetQuizShareRequest)(nil),            // 42: quiz.GetQuizShareRequest
+	(*GetQuizShareResponse)(nil),           // 43: quiz.GetQuizShareResponse
+	(*ListQuizSharesRequest)(nil),          // 44: quiz.ListQuizSharesRequest
+	(*ListQuizSharesResponse)(nil),         // 45: quiz.ListQuizSharesResponse
+	(*RevokeQuizShareRequest)(nil),         // 46: quiz.RevokeQuizShareRequest
+	(*QuizShareAnswer)(nil),                // 47: quiz.QuizShareAnswer
+	(*QuizShareAnswerResult)(nil),          // 48: quiz.QuizShareAnswerResult
+	(*QuizShareAttempt)(nil),               // 49: quiz.QuizShareAttempt
+	(*AttemptTelemetry)(nil),               // 50: quiz.AttemptTelemetry
+	(*FocusEvent)(nil),                     // 51: quiz.FocusEvent
+	(*PasteEvent)(nil),                     // 52: quiz.PasteEvent
+	(*AttemptFlag)(nil),                    // 53: quiz.AttemptFlag
+	(*SubmitQuizShareAttemptRequest)(nil),  // 54: quiz.SubmitQuizShareAttemptRequest
+	(*SubmitQuizShareAttemptResponse)(nil), // 55: quiz.SubmitQuizShareAttemptResponse
+	(*ListQuizShareAttemptsRequest)(nil),   // 56: quiz.ListQuizShareAttemptsRequest
+	(*ListQuizShareAttemptsResponse)(nil),  // 57: quiz.ListQuizShareAttemptsResponse
+	(*AssignQuestionReviewerRequest)(nil),  // 58: quiz.AssignQuestionReviewerRequest
+	(*ReviewQuestionRequest)(nil),          // 59: quiz.ReviewQuestionRequest
+	(*BulkApproveQuestionsRequest)(nil),    // 60: quiz.BulkApproveQuestionsRequest
+	(*ReviewSkip)(nil),                     // 61: quiz.ReviewSkip
+	(*ReviewQuestionsResponse)(nil),        // 62: quiz.ReviewQuestionsResponse
+	(*ListReviewQueueRequest)(nil),         // 63: quiz.ListReviewQueueRequest
+	(*ListReviewQueueResponse)(nil),        // 64: quiz.ListReviewQueueResponse
+	(*UpdateQuestionRequest)(nil),          // 65: quiz.UpdateQuestionRequest
+	(*UpdateQuestionResponse)(nil),         // 66: quiz.UpdateQuestionResponse
+	(*QuestionVersion)(nil),                // 67: quiz.QuestionVersion
+	(*ListQuestionVersionsRequest)(nil),    // 68: quiz.ListQuestionVersionsRequest
+	(*ListQuestionVersionsResponse)(nil),   // 69: quiz.ListQuestionVersionsResponse
+	(*GetQuestionVersionRequest)(nil),      // 70: quiz.GetQuestionVersionRequest
+	(*GetQuestionVersionResponse)(nil),     // 71: quiz.GetQuestionVersionResponse
+	(*StartQuestionAttemptRequest)(nil),    // 72: quiz.StartQuestionAttemptRequest
+	(*StartQuestionAttemptResponse)(nil),   // 73: quiz.StartQuestionAttemptResponse
+	(*ListGradingQueueRequest)(nil),        // 74: quiz.ListGradingQueueRequest
+	(*GradingQueueItem)(nil),               // 75: quiz.GradingQueueItem
+	(*ListGradingQueueResponse)(nil),       // 76: quiz.ListGradingQueueResponse
+	(*AnswerGrade)(nil),                    // 77: quiz.AnswerGrade
+	(*GradeAnswersRequest)(nil),            // 78: quiz.GradeAnswersRequest
+	(*GradingSkip)(nil),                    // 79: quiz.GradingSkip
+	(*GradeAnswersResponse)(nil),           // 80: quiz.GradeAnswersResponse
+	nil,                                    // 81: quiz.BlueprintSlotReport.RejectedEntry
+	(*timestamppb.Timestamp)(nil),          // 82: google.protobuf.Timestamp
 }
 var file_quiz_quiz_proto_depIdxs = []int32{
-	0,  // 0: quiz.GenerateQuizRequest.types:type_name -> quiz.QuestionType
-	1,  // 1: quiz.GenerateQuizRequest.difficulty:type_name -> quiz.DifficultyLevel
-	4,  // 2: quiz.GenerateQuizResponse.questions:type_name -> quiz.Question
-	0,  // 3: quiz.Question.type:type_name -> quiz.QuestionType
-	1,  // 4: quiz.Question.difficulty:type_name -> quiz.DifficultyLevel
-	4,  // 5: quiz.GetQuizResponse.question:type_name -> quiz.Question
-	0,  // 6: quiz.ListQuizzesRequest.type:type_name -> quiz.QuestionType
-	1,  // 7: quiz.ListQuizzesRequest.difficulty:type_name -> quiz.DifficultyLevel
-	4,  // 8: quiz.ListQuizzesResponse.questions:type_name -> quiz.Question
-	11, // 9: quiz.GetUserQuizHistoryResponse.answers:type_name -> quiz.UserAnswer
-	1,  // 10: quiz.KnowledgePointStats.avg_difficulty:type_name -> quiz.DifficultyLevel
-	14, // 11: quiz.GetKnowledgeStatsResponse.stats:type_name -> quiz.KnowledgePointStats
-	2,  // 12: quiz.QuizService.GenerateQuiz:input_type -> quiz.GenerateQuizRequest
-	5,  // 13: quiz.QuizService.GetQuiz:input_type -> quiz.GetQuizRequest
-	7,  // 14: quiz.QuizService.ListQuizzes:input_type -> quiz.ListQuizzesRequest
-	9,  // 15: quiz.QuizService.SubmitAnswer:input_type -> quiz.SubmitAnswerRequest
-	12, // 16: quiz.QuizService.GetUserQuizHistory:input_type -> quiz.GetUserQuizHistoryRequest
-	15, // 17: quiz.QuizService.GetKnowledgeStats:input_type -> quiz.GetKnowledgeStatsRequest
-	3,  // 18: quiz.QuizService.GenerateQuiz:output_type -> quiz.GenerateQuizResponse
-	6,  // 19: quiz.QuizService.GetQuiz:output_type -> quiz.GetQuizResponse
-	8,  // 20: quiz.QuizService.ListQuizzes:output_type -> quiz.ListQuizzesResponse
-	10, // 21: quiz.QuizService.SubmitAnswer:output_type -> quiz.SubmitAnswerResponse
-	13, // 22: quiz.QuizService.GetUserQuizHistory:output_type -> quiz.GetUserQuizHistoryResponse
-	16, // 23: quiz.QuizService.GetKnowledgeStats:output_type -> quiz.GetKnowledgeStatsResponse
-	18, // [18:24] is the sub-list for method output_type
-	12, // [12:18] is the sub-list for method input_type
-	12, // [12:12] is the sub-list for extension type_name
-	12, // [12:12] is the sub-list for extension extendee
-	0,  // [0:12] is the sub-list for field type_name
+	0,   // 0: quiz.GenerateQuizRequest.types:type_name -> quiz.QuestionType
+	1,   // 1: quiz.GenerateQuizRequest.difficulty:type_name -> quiz.DifficultyLevel
+	3,   // 2: quiz.GenerateQuizRequest.blueprint:type_name -> quiz.BlueprintSlot
+	0,   // 3: quiz.BlueprintSlot.type:type_name -> quiz.QuestionType
+	1,   // 4: quiz.BlueprintSlot.difficulty:type_name -> quiz.DifficultyLevel
+	3,   // 5: quiz.BlueprintSlotReport.slot:type_name -> quiz.BlueprintSlot
+	81,  // 6: quiz.BlueprintSlotReport.rejected:type_name -> quiz.BlueprintSlotReport.RejectedEntry
+	4,   // 7: quiz.BlueprintReport.slots:type_name -> quiz.BlueprintSlotReport
+	7,   // 8: quiz.GenerateQuizResponse.questions:type_name -> quiz.Question
+	5,   // 9: quiz.GenerateQuizResponse.blueprint_report:type_name -> quiz.BlueprintReport
+	0,   // 10: quiz.Question.type:type_name -> quiz.QuestionType
+	1,   // 11: quiz.Question.difficulty:type_name -> quiz.DifficultyLevel
+	82,  // 12: quiz.Question.created_at:type_name -> google.protobuf.Timestamp
+	9,   // 13: quiz.Question.parts:type_name -> quiz.QuestionPart
+	8,   // 14: quiz.Question.citations:type_name -> quiz.QuestionCitation
+	1,   // 15: quiz.Question.requested_difficulty:type_name -> quiz.DifficultyLevel
+	1,   // 16: quiz.Question.estimated_difficulty:type_name -> quiz.DifficultyLevel
+	82,  // 17: quiz.Question.reviewed_at:type_name -> google.protobuf.Timestamp
+	7,   // 18: quiz.GetQuizResponse.question:type_name -> quiz.Question
+	0,   // 19: quiz.ListQuizzesRequest.type:type_name -> quiz.QuestionType
+	1,   // 20: quiz.ListQuizzesRequest.difficulty:type_name -> quiz.DifficultyLevel
+	7,   // 21: quiz.ListQuizzesResponse.questions:type_name -> quiz.Question
+	18,  // 22: quiz.SubmitAnswerResponse.blank_match:type_name -> quiz.FillBlankMatch
+	17,  // 23: quiz.SubmitAnswerResponse.part_results:type_name -> quiz.PartResult
+	16,  // 24: quiz.SubmitAnswerResponse.similarity:type_name -> quiz.SimilarityCheck
+	82,  // 25: quiz.UserAnswer.answered_at:type_name -> google.protobuf.Timestamp
+	17,  // 26: quiz.UserAnswer.part_results:type_name -> quiz.PartResult
+	16,  // 27: quiz.UserAnswer.similarity:type_name -> quiz.SimilarityCheck
+	82,  // 28: quiz.UserAnswer.graded_at:type_name -> google.protobuf.Timestamp
+	19,  // 29: quiz.GetUserQuizHistoryResponse.answers:type_name -> quiz.UserAnswer
+	1,   // 30: quiz.KnowledgePointStats.avg_difficulty:type_name -> quiz.DifficultyLevel
+	22,  // 31: quiz.GetKnowledgeStatsResponse.stats:type_name -> quiz.KnowledgePointStats
+	1,   // 32: quiz.QuestionAnalytics.original_difficulty:type_name -> quiz.DifficultyLevel
+	1,   // 33: quiz.QuestionAnalytics.current_difficulty:type_name -> quiz.DifficultyLevel
+	1,   // 34: quiz.QuestionAnalytics.suggested_difficulty:type_name -> quiz.DifficultyLevel
+	82,  // 35: quiz.QuestionAnalytics.last_calibrated_at:type_name -> google.protobuf.Timestamp
+	25,  // 36: quiz.GetQuestionAnalyticsResponse.analytics:type_name -> quiz.QuestionAnalytics
+	7,   // 37: quiz.MistakeItem.question:type_name -> quiz.Question
+	82,  // 38: quiz.MistakeItem.last_wrong_at:type_name -> google.protobuf.Timestamp
+	82,  // 39: quiz.MistakeItem.last_attempt_at:type_name -> google.protobuf.Timestamp
+	28,  // 40: quiz.MistakeGroup.items:type_name -> quiz.MistakeItem
+	29,  // 41: quiz.ListMistakesResponse.groups:type_name -> quiz.MistakeGroup
+	28,  // 42: quiz.GetRetryQuestionsResponse.items:type_name -> quiz.MistakeItem
+	34,  // 43: quiz.GetGradingPolicyResponse.policy:type_name -> quiz.GradingPolicy
+	34,  // 44: quiz.SetGradingPolicyRequest.policy:type_name -> quiz.GradingPolicy
+	34,  // 45: quiz.SetGradingPolicyResponse.policy:type_name -> quiz.GradingPolicy
+	82,  // 46: quiz.QuizShare.expires_at:type_name -> google.protobuf.Timestamp
+	82,  // 47: quiz.QuizShare.created_at:type_name -> google.protobuf.Timestamp
+	39,  // 48: quiz.QuizShareResponse.share:type_name -> quiz.QuizShare
+	39,  // 49: quiz.GetQuizShareResponse.share:type_name -> quiz.QuizShare
+	7,   // 50: quiz.GetQuizShareResponse.questions:type_name -> quiz.Question
+	39,  // 51: quiz.ListQuizSharesResponse.shares:type_name -> quiz.QuizShare
+	48,  // 52: quiz.QuizShareAttempt.results:type_name -> quiz.QuizShareAnswerResult
+	82,  // 53: quiz.QuizShareAttempt.submitted_at:type_name -> google.protobuf.Timestamp
+	50,  // 54: quiz.QuizShareAttempt.telemetry:type_name -> quiz.AttemptTelemetry
+	53,  // 55: quiz.QuizShareAttempt.flags:type_name -> quiz.AttemptFlag
+	51,  // 56: quiz.AttemptTelemetry.focus_events:type_name -> quiz.FocusEvent
+	52,  // 57: quiz.AttemptTelemetry.paste_events:type_name -> quiz.PasteEvent
+	47,  // 58: quiz.SubmitQuizShareAttemptRequest.answers:type_name -> quiz.QuizShareAnswer
+	50,  // 59: quiz.SubmitQuizShareAttemptRequest.telemetry:type_name -> quiz.AttemptTelemetry
+	49,  // 60: quiz.SubmitQuizShareAttemptResponse.attempt:type_name -> quiz.QuizShareAttempt
+	49,  // 61: quiz.ListQuizShareAttemptsResponse.attempts:type_name -> quiz.QuizShareAttempt
+	7,   // 62: quiz.ReviewQuestionsResponse.questions:type_name -> quiz.Question
+	61,  // 63: quiz.ReviewQuestionsResponse.skipped:type_name -> quiz.ReviewSkip
+	7,   // 64: quiz.ListReviewQueueResponse.questions:type_name -> quiz.Question
+	9,   // 65: quiz.UpdateQuestionRequest.parts:type_name -> quiz.QuestionPart
+	1,   // 66: quiz.UpdateQuestionRequest.difficulty:type_name -> quiz.DifficultyLevel
+	7,   // 67: quiz.UpdateQuestionResponse.question:type_name -> quiz.Question
+	0,   // 68: quiz.QuestionVersion.type:type_name -> quiz.QuestionType
+	9,   // 69: quiz.QuestionVersion.parts:type_name -> quiz.QuestionPart
+	1,   // 70: quiz.QuestionVersion.difficulty:type_name -> quiz.DifficultyLevel
+	82,  // 71: quiz.QuestionVersion.created_at:type_name -> google.protobuf.Timestamp
+	67,  // 72: quiz.ListQuestionVersionsResponse.versions:type_name -> quiz.QuestionVersion
+	67,  // 73: quiz.GetQuestionVersionResponse.version:type_name -> quiz.QuestionVersion
+	7,   // 74: quiz.StartQuestionAttemptResponse.question:type_name -> quiz.Question
+	19,  // 75: quiz.GradingQueueItem.answer:type_name -> quiz.UserAnswer
+	7,   // 76: quiz.GradingQueueItem.question:type_name -> quiz.Question
+	75,  // 77: quiz.ListGradingQueueResponse.items:type_name -> quiz.GradingQueueItem
+	77,  // 78: quiz.GradeAnswersRequest.grades:type_name -> quiz.AnswerGrade
+	19,  // 79: quiz.GradeAnswersResponse.answers:type_name -> quiz.UserAnswer
+	79,  // 80: quiz.GradeAnswersResponse.skipped:type_name -> quiz.GradingSkip
+	2,   // 81: quiz.QuizService.GenerateQuiz:input_type -> quiz.GenerateQuizRequest
+	10,  // 82: quiz.QuizService.GetQuiz:input_type -> quiz.GetQuizRequest
+	12,  // 83: quiz.QuizService.ListQuizzes:input_type -> quiz.ListQuizzesRequest
+	14,  // 84: quiz.QuizService.SubmitAnswer:input_type -> quiz.SubmitAnswerRequest
+	20,  // 85: quiz.QuizService.GetUserQuizHistory:input_type -> quiz.GetUserQuizHistoryRequest
+	23,  // 86: quiz.QuizService.GetKnowledgeStats:input_type -> quiz.GetKnowledgeStatsRequest
+	26,  // 87: quiz.QuizService.GetQuestionAnalytics:input_type -> quiz.GetQuestionAnalyticsRequest
+	30,  // 88: quiz.QuizService.ListMistakes:input_type -> quiz.ListMistakesRequest
+	32,  // 89: quiz.QuizService.GetRetryQuestions:input_type -> quiz.GetRetryQuestionsRequest
+	35,  // 90: quiz.QuizService.GetGradingPolicy:input_type -> quiz.GetGradingPolicyRequest
+	37,  // 91: quiz.QuizService.SetGradingPolicy:input_type -> quiz.SetGradingPolicyRequest
+	40,  // 92: quiz.QuizService.CreateQuizShare:input_type -> quiz.CreateQuizShareRequest
+	42,  // 93: quiz.QuizService.GetQuizShare:input_type -> quiz.GetQuizShareRequest
+	44,  // 94: quiz.QuizService.ListQuizShares:input_type -> quiz.ListQuizSharesRequest
+	46,  // 95: quiz.QuizService.RevokeQuizShare:input_type -> quiz.RevokeQuizShareRequest
+	54,  // 96: quiz.QuizService.SubmitQuizShareAttempt:input_type -> quiz.SubmitQuizShareAttemptRequest
+	56,  // 97: quiz.QuizService.ListQuizShareAttempts:input_type -> quiz.ListQuizShareAttemptsRequest
+	58,  // 98: quiz.QuizService.AssignQuestionReviewer:input_type -> quiz.AssignQuestionReviewerRequest
+	59,  // 99: quiz.QuizService.ReviewQuestion:input_type -> quiz.ReviewQuestionRequest
+	60,  // 100: quiz.QuizService.BulkApproveQuestions:input_type -> quiz.BulkApproveQuestionsRequest
+	63,  // 101: quiz.QuizService.ListReviewQueue:input_type -> quiz.ListReviewQueueRequest
+	65,  // 102: quiz.QuizService.UpdateQuestion:input_type -> quiz.UpdateQuestionRequest
+	68,  // 103: quiz.QuizService.ListQuestionVersions:input_type -> quiz.ListQuestionVersionsRequest
+	70,  // 104: quiz.QuizService.GetQuestionVersion:input_type -> quiz.GetQuestionVersionRequest
+	72,  // 105: quiz.QuizService.StartQuestionAttempt:input_type -> quiz.StartQuestionAttemptRequest
+	74,  // 106: quiz.QuizService.ListGradingQueue:input_type -> quiz.ListGradingQueueRequest
+	78,  // 107: quiz.QuizService.GradeAnswers:input_type -> quiz.GradeAnswersRequest
+	6,   // 108: quiz.QuizService.GenerateQuiz:output_type -> quiz.GenerateQuizResponse
+	11,  // 109: quiz.QuizService.GetQuiz:output_type -> quiz.GetQuizResponse
+	13,  // 110: quiz.QuizService.ListQuizzes:output_type -> quiz.ListQuizzesResponse
+	15,  // 111: quiz.QuizService.SubmitAnswer:output_type -> quiz.SubmitAnswerResponse
+	21,  // 112: quiz.QuizService.GetUserQuizHistory:output_type -> quiz.GetUserQuizHistoryResponse
+	24,  // 113: quiz.QuizService.GetKnowledgeStats:output_type -> quiz.GetKnowledgeStatsResponse
+	27,  // 114: quiz.QuizService.GetQuestionAnalytics:output_type -> quiz.GetQuestionAnalyticsResponse
+	31,  // 115: quiz.QuizService.ListMistakes:output_type -> quiz.ListMistakesResponse
+	33,  // 116: quiz.QuizService.GetRetryQuestions:output_type -> quiz.GetRetryQuestionsResponse
+	36,  // 117: quiz.QuizService.GetGradingPolicy:output_type -> quiz.GetGradingPolicyResponse
+	38,  // 118: quiz.QuizService.SetGradingPolicy:output_type -> quiz.SetGradingPolicyResponse
+	41,  // 119: quiz.QuizService.CreateQuizShare:output_type -> quiz.QuizShareResponse
+	43,  // 120: quiz.QuizService.GetQuizShare:output_type -> quiz.GetQuizShareResponse
+	45,  // 121: quiz.QuizService.ListQuizShares:output_type -> quiz.ListQuizSharesResponse
+	41,  // 122: quiz.QuizService.RevokeQuizShare:output_type -> quiz.QuizShareResponse
+	55,  // 123: quiz.QuizService.SubmitQuizShareAttempt:output_type -> quiz.SubmitQuizShareAttemptResponse
+	57,  // 124: quiz.QuizService.ListQuizShareAttempts:output_type -> quiz.ListQuizShareAttemptsResponse
+	62,  // 125: quiz.QuizService.AssignQuestionReviewer:output_type -> quiz.ReviewQuestionsResponse
+	62,  // 126: quiz.QuizService.ReviewQuestion:output_type -> quiz.ReviewQuestionsResponse
+	62,  // 127: quiz.QuizService.BulkApproveQuestions:output_type -> quiz.ReviewQuestionsResponse
+	64,  // 128: quiz.QuizService.ListReviewQueue:output_type -> quiz.ListReviewQueueResponse
+	66,  // 129: quiz.QuizService.UpdateQuestion:output_type -> quiz.UpdateQuestionResponse
+	69,  // 130: quiz.QuizService.ListQuestionVersions:output_type -> quiz.ListQuestionVersionsResponse
+	71,  // 131: quiz.QuizService.GetQuestionVersion:output_type -> quiz.GetQuestionVersionResponse
+	73,  // 132: quiz.QuizService.StartQuestionAttempt:output_type -> quiz.StartQuestionAttemptResponse
+	76,  // 133: quiz.QuizService.ListGradingQueue:output_type -> quiz.ListGradingQueueResponse
+	80,  // 134: quiz.QuizService.GradeAnswers:output_type -> quiz.GradeAnswersResponse
+	108, // [108:135] is the sub-list for method output_type
+	81,  // [81:108] is the sub-list for method input_type
+	81,  // [81:81] is the sub-list for extension type_name
+	81,  // [81:81] is the sub-list for extension extendee
+	0,   // [0:81] is the sub-list for field type_name
 }
 
 func init() { file_quiz_quiz_proto_init() }
@@ -1440,7 +7228,7 @@ func file_quiz_quiz_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_quiz_quiz_proto_rawDesc), len(file_quiz_quiz_proto_rawDesc)),
 			NumEnums:      2,
-			NumMessages:   15,
+			NumMessages:   80,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -19,12 +19,33 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	QuizService_GenerateQuiz_FullMethodName       = "/quiz.QuizService/GenerateQuiz"
-	QuizService_GetQuiz_FullMethodName            = "/quiz.QuizService/GetQuiz"
-	QuizService_ListQuizzes_FullMethodName        = "/quiz.QuizService/ListQuizzes"
-	QuizService_SubmitAnswer_FullMethodName       = "/quiz.QuizService/SubmitAnswer"
-	QuizService_GetUserQuizHistory_FullMethodName = "/quiz.QuizService/GetUserQuizHistory"
-	QuizService_GetKnowledgeStats_FullMethodName  = "/quiz.QuizService/GetKnowledgeStats"
+	QuizService_GenerateQuiz_FullMethodName           = "/quiz.QuizService/GenerateQuiz"
+	QuizService_GetQuiz_FullMethodName                = "/quiz.QuizService/GetQuiz"
+	QuizService_ListQuizzes_FullMethodName            = "/quiz.QuizService/ListQuizzes"
+	QuizService_SubmitAnswer_FullMethodName           = "/quiz.QuizService/SubmitAnswer"
+	QuizService_GetUserQuizHistory_FullMethodName     = "/quiz.QuizService/GetUserQuizHistory"
+	QuizService_GetKnowledgeStats_FullMethodName      = "/quiz.QuizService/GetKnowledgeStats"
+	QuizService_GetQuestionAnalytics_FullMethodName   = "/quiz.QuizService/GetQuestionAnalytics"
+	QuizService_ListMistakes_FullMethodName           = "/quiz.QuizService/ListMistakes"
+	QuizService_GetRetryQuestions_FullMethodName      = "/quiz.QuizService/GetRetryQuestions"
+	QuizService_GetGradingPolicy_FullMethodName       = "/quiz.QuizService/GetGradingPolicy"
+	QuizService_SetGradingPolicy_FullMethodName       = "/quiz.QuizService/SetGradingPolicy"
+	QuizService_CreateQuizShare_FullMethodName        = "/quiz.QuizService/CreateQuizShare"
+	QuizService_GetQuizShare_FullMethodName           = "/quiz.QuizService/GetQuizShare"
+	QuizService_ListQuizShares_FullMethodName         = "/quiz.QuizService/ListQuizShares"
+	QuizService_RevokeQuizShare_FullMethodName        = "/quiz.QuizService/RevokeQuizShare"
+	QuizService_SubmitQuizShareAttempt_FullMethodName = "/quiz.QuizService/SubmitQuizShareAttempt"
+	QuizService_ListQuizShareAttempts_FullMethodName  = "/quiz.QuizService/ListQuizShareAttempts"
+	QuizService_AssignQuestionReviewer_FullMethodName = "/quiz.QuizService/AssignQuestionReviewer"
+	QuizService_ReviewQuestion_FullMethodName         = "/quiz.QuizService/ReviewQuestion"
+	QuizService_BulkApproveQuestions_FullMethodName   = "/quiz.QuizService/BulkApproveQuestions"
+	QuizService_ListReviewQueue_FullMethodName        = "/quiz.QuizService/ListReviewQueue"
+	QuizService_UpdateQuestion_FullMethodName         = "/quiz.QuizService/UpdateQuestion"
+	QuizService_ListQuestionVersions_FullMethodName   = "/quiz.QuizService/ListQuestionVersions"
+	QuizService_GetQuestionVersion_FullMethodName     = "/quiz.QuizService/GetQuestionVersion"
+	QuizService_StartQuestionAttempt_FullMethodName   = "/quiz.QuizService/StartQuestionAttempt"
+	QuizService_ListGradingQueue_FullMethodName       = "/quiz.QuizService/ListGradingQueue"
+	QuizService_GradeAnswers_FullMethodName           = "/quiz.QuizService/GradeAnswers"
 )
 
 // QuizServiceClient is the client API for QuizService service.
@@ -45,6 +66,37 @@ type QuizServiceClient interface {
 	GetUserQuizHistory(ctx context.Context, in *GetUserQuizHistoryRequest, opts ...grpc.CallOption) (*GetUserQuizHistoryResponse, error)
 	// 获取知识点掌握度统计
 	GetKnowledgeStats(ctx context.Context, in *GetKnowledgeStatsRequest, opts ...grpc.CallOption) (*GetKnowledgeStatsResponse, error)
+	// 获取题库作答分析（难度校准）
+	GetQuestionAnalytics(ctx context.Context, in *GetQuestionAnalyticsRequest, opts ...grpc.CallOption) (*GetQuestionAnalyticsResponse, error)
+	// 获取错题本（按知识点分组）
+	ListMistakes(ctx context.Context, in *ListMistakesRequest, opts ...grpc.CallOption) (*ListMistakesResponse, error)
+	// 获取待重练的错题
+	GetRetryQuestions(ctx context.Context, in *GetRetryQuestionsRequest, opts ...grpc.CallOption) (*GetRetryQuestionsResponse, error)
+	// 获取生效的评分策略
+	GetGradingPolicy(ctx context.Context, in *GetGradingPolicyRequest, opts ...grpc.CallOption) (*GetGradingPolicyResponse, error)
+	// 设置评分策略（全局或按材料）
+	SetGradingPolicy(ctx context.Context, in *SetGradingPolicyRequest, opts ...grpc.CallOption) (*SetGradingPolicyResponse, error)
+	// 公开分享：创建、查看、撤销分享，匿名或登录作答，创建者查看作答结果
+	CreateQuizShare(ctx context.Context, in *CreateQuizShareRequest, opts ...grpc.CallOption) (*QuizShareResponse, error)
+	GetQuizShare(ctx context.Context, in *GetQuizShareRequest, opts ...grpc.CallOption) (*GetQuizShareResponse, error)
+	ListQuizShares(ctx context.Context, in *ListQuizSharesRequest, opts ...grpc.CallOption) (*ListQuizSharesResponse, error)
+	RevokeQuizShare(ctx context.Context, in *RevokeQuizShareRequest, opts ...grpc.CallOption) (*QuizShareResponse, error)
+	SubmitQuizShareAttempt(ctx context.Context, in *SubmitQuizShareAttemptRequest, opts ...grpc.CallOption) (*SubmitQuizShareAttemptResponse, error)
+	ListQuizShareAttempts(ctx context.Context, in *ListQuizShareAttemptsRequest, opts ...grpc.CallOption) (*ListQuizShareAttemptsResponse, error)
+	// 课程题目审核：创建者提交审核并指定审核人，审核人逐题或批量审核
+	AssignQuestionReviewer(ctx context.Context, in *AssignQuestionReviewerRequest, opts ...grpc.CallOption) (*ReviewQuestionsResponse, error)
+	ReviewQuestion(ctx context.Context, in *ReviewQuestionRequest, opts ...grpc.CallOption) (*ReviewQuestionsResponse, error)
+	BulkApproveQuestions(ctx context.Context, in *BulkApproveQuestionsRequest, opts ...grpc.CallOption) (*ReviewQuestionsResponse, error)
+	ListReviewQueue(ctx context.Context, in *ListReviewQueueRequest, opts ...grpc.CallOption) (*ListReviewQueueResponse, error)
+	// 题目编辑与版本历史：每次编辑保存不可变版本，答题记录引用作答时的版本
+	UpdateQuestion(ctx context.Context, in *UpdateQuestionRequest, opts ...grpc.CallOption) (*UpdateQuestionResponse, error)
+	ListQuestionVersions(ctx context.Context, in *ListQuestionVersionsRequest, opts ...grpc.CallOption) (*ListQuestionVersionsResponse, error)
+	GetQuestionVersion(ctx context.Context, in *GetQuestionVersionRequest, opts ...grpc.CallOption) (*GetQuestionVersionResponse, error)
+	// 开始作答：选择题按会话打乱选项顺序，提交答案时携带 attempt_id 按打乱后的答案键评分
+	StartQuestionAttempt(ctx context.Context, in *StartQuestionAttemptRequest, opts ...grpc.CallOption) (*StartQuestionAttemptResponse, error)
+	// 主观题人工评分：简答题与论述题的作答附 AI 建议得分进入评分队列，教师批量确认或改分
+	ListGradingQueue(ctx context.Context, in *ListGradingQueueRequest, opts ...grpc.CallOption) (*ListGradingQueueResponse, error)
+	GradeAnswers(ctx context.Context, in *GradeAnswersRequest, opts ...grpc.CallOption) (*GradeAnswersResponse, error)
 }
 
 type quizServiceClient struct {
@@ -115,6 +167,216 @@ func (c *quizServiceClient) GetKnowledgeStats(ctx context.Context, in *GetKnowle
 	return out, nil
 }
 
+func (c *quizServiceClient) GetQuestionAnalytics(ctx context.Context, in *GetQuestionAnalyticsRequest, opts ...grpc.CallOption) (*GetQuestionAnalyticsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetQuestionAnalyticsResponse)
+	err := c.cc.Invoke(ctx, QuizService_GetQuestionAnalytics_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *quizServiceClient) ListMistakes(ctx context.Context, in *ListMistakesRequest, opts ...grpc.CallOption) (*ListMistakesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListMistakesResponse)
+	err := c.cc.Invoke(ctx, QuizService_ListMistakes_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *quizServiceClient) GetRetryQuestions(ctx context.Context, in *GetRetryQuestionsRequest, opts ...grpc.CallOption) (*GetRetryQuestionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetRetryQuestionsResponse)
+	err := c.cc.Invoke(ctx, QuizService_GetRetryQuestions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *quizServiceClient) GetGradingPolicy(ctx context.Context, in *GetGradingPolicyRequest, opts ...grpc.CallOption) (*GetGradingPolicyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetGradingPolicyResponse)
+	err := c.cc.Invoke(ctx, QuizService_GetGradingPolicy_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *quizServiceClient) SetGradingPolicy(ctx context.Context, in *SetGradingPolicyRequest, opts ...grpc.CallOption) (*SetGradingPolicyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetGradingPolicyResponse)
+	err := c.cc.Invoke(ctx, QuizService_SetGradingPolicy_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *quizServiceClient) CreateQuizShare(ctx context.Context, in *CreateQuizShareRequest, opts ...grpc.CallOption) (*QuizShareResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(QuizShareResponse)
+	err := c.cc.Invoke(ctx, QuizService_CreateQuizShare_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *quizServiceClient) GetQuizShare(ctx context.Context, in *GetQuizShareRequest, opts ...grpc.CallOption) (*GetQuizShareResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetQuizShareResponse)
+	err := c.cc.Invoke(ctx, QuizService_GetQuizShare_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *quizServiceClient) ListQuizShares(ctx context.Context, in *ListQuizSharesRequest, opts ...grpc.CallOption) (*ListQuizSharesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListQuizSharesResponse)
+	err := c.cc.Invoke(ctx, QuizService_ListQuizShares_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *quizServiceClient) RevokeQuizShare(ctx context.Context, in *RevokeQuizShareRequest, opts ...grpc.CallOption) (*QuizShareResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(QuizShareResponse)
+	err := c.cc.Invoke(ctx, QuizService_RevokeQuizShare_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *quizServiceClient) SubmitQuizShareAttempt(ctx context.Context, in *SubmitQuizShareAttemptRequest, opts ...grpc.CallOption) (*SubmitQuizShareAttemptResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SubmitQuizShareAttemptResponse)
+	err := c.cc.Invoke(ctx, QuizService_SubmitQuizShareAttempt_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *quizServiceClient) ListQuizShareAttempts(ctx context.Context, in *ListQuizShareAttemptsRequest, opts ...grpc.CallOption) (*ListQuizShareAttemptsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListQuizShareAttemptsResponse)
+	err := c.cc.Invoke(ctx, QuizService_ListQuizShareAttempts_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *quizServiceClient) AssignQuestionReviewer(ctx context.Context, in *AssignQuestionReviewerRequest, opts ...grpc.CallOption) (*ReviewQuestionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReviewQuestionsResponse)
+	err := c.cc.Invoke(ctx, QuizService_AssignQuestionReviewer_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *quizServiceClient) ReviewQuestion(ctx context.Context, in *ReviewQuestionRequest, opts ...grpc.CallOption) (*ReviewQuestionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReviewQuestionsResponse)
+	err := c.cc.Invoke(ctx, QuizService_ReviewQuestion_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *quizServiceClient) BulkApproveQuestions(ctx context.Context, in *BulkApproveQuestionsRequest, opts ...grpc.CallOption) (*ReviewQuestionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReviewQuestionsResponse)
+	err := c.cc.Invoke(ctx, QuizService_BulkApproveQuestions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *quizServiceClient) ListReviewQueue(ctx context.Context, in *ListReviewQueueRequest, opts ...grpc.CallOption) (*ListReviewQueueResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListReviewQueueResponse)
+	err := c.cc.Invoke(ctx, QuizService_ListReviewQueue_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *quizServiceClient) UpdateQuestion(ctx context.Context, in *UpdateQuestionRequest, opts ...grpc.CallOption) (*UpdateQuestionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpdateQuestionResponse)
+	err := c.cc.Invoke(ctx, QuizService_UpdateQuestion_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *quizServiceClient) ListQuestionVersions(ctx context.Context, in *ListQuestionVersionsRequest, opts ...grpc.CallOption) (*ListQuestionVersionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListQuestionVersionsResponse)
+	err := c.cc.Invoke(ctx, QuizService_ListQuestionVersions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *quizServiceClient) GetQuestionVersion(ctx context.Context, in *GetQuestionVersionRequest, opts ...grpc.CallOption) (*GetQuestionVersionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetQuestionVersionResponse)
+	err := c.cc.Invoke(ctx, QuizService_GetQuestionVersion_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *quizServiceClient) StartQuestionAttempt(ctx context.Context, in *StartQuestionAttemptRequest, opts ...grpc.CallOption) (*StartQuestionAttemptResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StartQuestionAttemptResponse)
+	err := c.cc.Invoke(ctx, QuizService_StartQuestionAttempt_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *quizServiceClient) ListGradingQueue(ctx context.Context, in *ListGradingQueueRequest, opts ...grpc.CallOption) (*ListGradingQueueResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListGradingQueueResponse)
+	err := c.cc.Invoke(ctx, QuizService_ListGradingQueue_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *quizServiceClient) GradeAnswers(ctx context.Context, in *GradeAnswersRequest, opts ...grpc.CallOption) (*GradeAnswersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GradeAnswersResponse)
+	err := c.cc.Invoke(ctx, QuizService_GradeAnswers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // QuizServiceServer is the server API for QuizService service.
 // All implementations must embed UnimplementedQuizServiceServer
 // for forward compatibility.
@@ -133,6 +395,37 @@ type QuizServiceServer interface {
 	GetUserQuizHistory(context.Context, *GetUserQuizHistoryRequest) (*GetUserQuizHistoryResponse, error)
 	// 获取知识点掌握度统计
 	GetKnowledgeStats(context.Context, *GetKnowledgeStatsRequest) (*GetKnowledgeStatsResponse, error)
+	// 获取题库作答分析（难度校准）
+	GetQuestionAnalytics(context.Context, *GetQuestionAnalyticsRequest) (*GetQuestionAnalyticsResponse, error)
+	// 获取错题本（按知识点分组）
+	ListMistakes(context.Context, *ListMistakesRequest) (*ListMistakesResponse, error)
+	// 获取待重练的错题
+	GetRetryQuestions(context.Context, *GetRetryQuestionsRequest) (*GetRetryQuestionsResponse, error)
+	// 获取生效的评分策略
+	GetGradingPolicy(context.Context, *GetGradingPolicyRequest) (*GetGradingPolicyResponse, error)
+	// 设置评分策略（全局或按材料）
+	SetGradingPolicy(context.Context, *SetGradingPolicyRequest) (*SetGradingPolicyResponse, error)
+	// 公开分享：创建、查看、撤销分享，匿名或登录作答，创建者查看作答结果
+	CreateQuizShare(context.Context, *CreateQuizShareRequest) (*QuizShareResponse, error)
+	GetQuizShare(context.Context, *GetQuizShareRequest) (*GetQuizShareResponse, error)
+	ListQuizShares(context.Context, *ListQuizSharesRequest) (*ListQuizSharesResponse, error)
+	RevokeQuizShare(context.Context, *RevokeQuizShareRequest) (*QuizShareResponse, error)
+	SubmitQuizShareAttempt(context.Context, *SubmitQuizShareAttemptRequest) (*SubmitQuizShareAttemptResponse, error)
+	ListQuizShareAttempts(context.Context, *ListQuizShareAttemptsRequest) (*ListQuizShareAttemptsResponse, error)
+	// 课程题目审核：创建者提交审核并指定审核人，审核人逐题或批量审核
+	AssignQuestionReviewer(context.Context, *AssignQuestionReviewerRequest) (*ReviewQuestionsResponse, error)
+	ReviewQuestion(context.Context, *ReviewQuestionRequest) (*ReviewQuestionsResponse, error)
+	BulkApproveQuestions(context.Context, *BulkApproveQuestionsRequest) (*ReviewQuestionsResponse, error)
+	ListReviewQueue(context.Context, *ListReviewQueueRequest) (*ListReviewQueueResponse, error)
+	// 题目编辑与版本历史：每次编辑保存不可变版本，答题记录引用作答时的版本
+	UpdateQuestion(context.Context, *UpdateQuestionRequest) (*UpdateQuestionResponse, error)
+	ListQuestionVersions(context.Context, *ListQuestionVersionsRequest) (*ListQuestionVersionsResponse, error)
+	GetQuestionVersion(context.Context, *GetQuestionVersionRequest) (*GetQuestionVersionResponse, error)
+	// 开始作答：选择题按会话打乱选项顺序，提交答案时携带 attempt_id 按打乱后的答案键评分
+	StartQuestionAttempt(context.Context, *StartQuestionAttemptRequest) (*StartQuestionAttemptResponse, error)
+	// 主观题人工评分：简答题与论述题的作答附 AI 建议得分进入评分队列，教师批量确认或改分
+	ListGradingQueue(context.Context, *ListGradingQueueRequest) (*ListGradingQueueResponse, error)
+	GradeAnswers(context.Context, *GradeAnswersRequest) (*GradeAnswersResponse, error)
 	mustEmbedUnimplementedQuizServiceServer()
 }
 
@@ -161,6 +454,69 @@ func (UnimplementedQuizServiceServer) GetUserQuizHistory(context.Context, *GetUs
 func (UnimplementedQuizServiceServer) GetKnowledgeStats(context.Context, *GetKnowledgeStatsRequest) (*GetKnowledgeStatsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetKnowledgeStats not implemented")
 }
+func (UnimplementedQuizServiceServer) GetQuestionAnalytics(context.Context, *GetQuestionAnalyticsRequest) (*GetQuestionAnalyticsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetQuestionAnalytics not implemented")
+}
+func (UnimplementedQuizServiceServer) ListMistakes(context.Context, *ListMistakesRequest) (*ListMistakesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListMistakes not implemented")
+}
+func (UnimplementedQuizServiceServer) GetRetryQuestions(context.Context, *GetRetryQuestionsRequest) (*GetRetryQuestionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRetryQuestions not implemented")
+}
+func (UnimplementedQuizServiceServer) GetGradingPolicy(context.Context, *GetGradingPolicyRequest) (*GetGradingPolicyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetGradingPolicy not implemented")
+}
+func (UnimplementedQuizServiceServer) SetGradingPolicy(context.Context, *SetGradingPolicyRequest) (*SetGradingPolicyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetGradingPolicy not implemented")
+}
+func (UnimplementedQuizServiceServer) CreateQuizShare(context.Context, *CreateQuizShareRequest) (*QuizShareResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateQuizShare not implemented")
+}
+func (UnimplementedQuizServiceServer) GetQuizShare(context.Context, *GetQuizShareRequest) (*GetQuizShareResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetQuizShare not implemented")
+}
+func (UnimplementedQuizServiceServer) ListQuizShares(context.Context, *ListQuizSharesRequest) (*ListQuizSharesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListQuizShares not implemented")
+}
+func (UnimplementedQuizServiceServer) RevokeQuizShare(context.Context, *RevokeQuizShareRequest) (*QuizShareResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeQuizShare not implemented")
+}
+func (UnimplementedQuizServiceServer) SubmitQuizShareAttempt(context.Context, *SubmitQuizShareAttemptRequest) (*SubmitQuizShareAttemptResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SubmitQuizShareAttempt not implemented")
+}
+func (UnimplementedQuizServiceServer) ListQuizShareAttempts(context.Context, *ListQuizShareAttemptsRequest) (*ListQuizShareAttemptsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListQuizShareAttempts not implemented")
+}
+func (UnimplementedQuizServiceServer) AssignQuestionReviewer(context.Context, *AssignQuestionReviewerRequest) (*ReviewQuestionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AssignQuestionReviewer not implemented")
+}
+func (UnimplementedQuizServiceServer) ReviewQuestion(context.Context, *ReviewQuestionRequest) (*ReviewQuestionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReviewQuestion not implemented")
+}
+func (UnimplementedQuizServiceServer) BulkApproveQuestions(context.Context, *BulkApproveQuestionsRequest) (*ReviewQuestionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BulkApproveQuestions not implemented")
+}
+func (UnimplementedQuizServiceServer) ListReviewQueue(context.Context, *ListReviewQueueRequest) (*ListReviewQueueResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListReviewQueue not implemented")
+}
+func (UnimplementedQuizServiceServer) UpdateQuestion(context.Context, *UpdateQuestionRequest) (*UpdateQuestionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateQuestion not implemented")
+}
+func (UnimplementedQuizServiceServer) ListQuestionVersions(context.Context, *ListQuestionVersionsRequest) (*ListQuestionVersionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListQuestionVersions not implemented")
+}
+func (UnimplementedQuizServiceServer) GetQuestionVersion(context.Context, *GetQuestionVersionRequest) (*GetQuestionVersionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetQuestionVersion not implemented")
+}
+func (UnimplementedQuizServiceServer) StartQuestionAttempt(context.Context, *StartQuestionAttemptRequest) (*StartQuestionAttemptResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StartQuestionAttempt not implemented")
+}
+func (UnimplementedQuizServiceServer) ListGradingQueue(context.Context, *ListGradingQueueRequest) (*ListGradingQueueResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListGradingQueue not implemented")
+}
+func (UnimplementedQuizServiceServer) GradeAnswers(context.Context, *GradeAnswersRequest) (*GradeAnswersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GradeAnswers not implemented")
+}
 func (UnimplementedQuizServiceServer) mustEmbedUnimplementedQuizServiceServer() {}
 func (UnimplementedQuizServiceServer) testEmbeddedByValue()                     {}
 
@@ -290,6 +646,384 @@ func _QuizService_GetKnowledgeStats_Handler(srv interface{}, ctx context.Context
 	return interceptor(ctx, in, info, handler)
 }
 
+func _QuizService_GetQuestionAnalytics_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetQuestionAnalyticsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QuizServiceServer).GetQuestionAnalytics(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: QuizService_GetQuestionAnalytics_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QuizServiceServer).GetQuestionAnalytics(ctx, req.(*GetQuestionAnalyticsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _QuizService_ListMistakes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListMistakesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QuizServiceServer).ListMistakes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: QuizService_ListMistakes_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QuizServiceServer).ListMistakes(ctx, req.(*ListMistakesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _QuizService_GetRetryQuestions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRetryQuestionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QuizServiceServer).GetRetryQuestions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: QuizService_GetRetryQuestions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QuizServiceServer).GetRetryQuestions(ctx, req.(*GetRetryQuestionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _QuizService_GetGradingPolicy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetGradingPolicyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QuizServiceServer).GetGradingPolicy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: QuizService_GetGradingPolicy_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QuizServiceServer).GetGradingPolicy(ctx, req.(*GetGradingPolicyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _QuizService_SetGradingPolicy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetGradingPolicyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QuizServiceServer).SetGradingPolicy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: QuizService_SetGradingPolicy_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QuizServiceServer).SetGradingPolicy(ctx, req.(*SetGradingPolicyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _QuizService_CreateQuizShare_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateQuizShareRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QuizServiceServer).CreateQuizShare(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: QuizService_CreateQuizShare_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QuizServiceServer).CreateQuizShare(ctx, req.(*CreateQuizShareRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _QuizService_GetQuizShare_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetQuizShareRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QuizServiceServer).GetQuizShare(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: QuizService_GetQuizShare_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QuizServiceServer).GetQuizShare(ctx, req.(*GetQuizShareRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _QuizService_ListQuizShares_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListQuizSharesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QuizServiceServer).ListQuizShares(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: QuizService_ListQuizShares_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QuizServiceServer).ListQuizShares(ctx, req.(*ListQuizSharesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _QuizService_RevokeQuizShare_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeQuizShareRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QuizServiceServer).RevokeQuizShare(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: QuizService_RevokeQuizShare_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QuizServiceServer).RevokeQuizShare(ctx, req.(*RevokeQuizShareRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _QuizService_SubmitQuizShareAttempt_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SubmitQuizShareAttemptRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QuizServiceServer).SubmitQuizShareAttempt(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: QuizService_SubmitQuizShareAttempt_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QuizServiceServer).SubmitQuizShareAttempt(ctx, req.(*SubmitQuizShareAttemptRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _QuizService_ListQuizShareAttempts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListQuizShareAttemptsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QuizServiceServer).ListQuizShareAttempts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: QuizService_ListQuizShareAttempts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QuizServiceServer).ListQuizShareAttempts(ctx, req.(*ListQuizShareAttemptsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _QuizService_AssignQuestionReviewer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AssignQuestionReviewerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QuizServiceServer).AssignQuestionReviewer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: QuizService_AssignQuestionReviewer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QuizServiceServer).AssignQuestionReviewer(ctx, req.(*AssignQuestionReviewerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _QuizService_ReviewQuestion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReviewQuestionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QuizServiceServer).ReviewQuestion(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: QuizService_ReviewQuestion_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QuizServiceServer).ReviewQuestion(ctx, req.(*ReviewQuestionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _QuizService_BulkApproveQuestions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BulkApproveQuestionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QuizServiceServer).BulkApproveQuestions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: QuizService_BulkApproveQuestions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QuizServiceServer).BulkApproveQuestions(ctx, req.(*BulkApproveQuestionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _QuizService_ListReviewQueue_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListReviewQueueRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QuizServiceServer).ListReviewQueue(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: QuizService_ListReviewQueue_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QuizServiceServer).ListReviewQueue(ctx, req.(*ListReviewQueueRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _QuizService_UpdateQuestion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateQuestionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QuizServiceServer).UpdateQuestion(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: QuizService_UpdateQuestion_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QuizServiceServer).UpdateQuestion(ctx, req.(*UpdateQuestionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _QuizService_ListQuestionVersions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListQuestionVersionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QuizServiceServer).ListQuestionVersions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: QuizService_ListQuestionVersions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QuizServiceServer).ListQuestionVersions(ctx, req.(*ListQuestionVersionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _QuizService_GetQuestionVersion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetQuestionVersionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QuizServiceServer).GetQuestionVersion(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: QuizService_GetQuestionVersion_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QuizServiceServer).GetQuestionVersion(ctx, req.(*GetQuestionVersionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _QuizService_StartQuestionAttempt_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StartQuestionAttemptRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QuizServiceServer).StartQuestionAttempt(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: QuizService_StartQuestionAttempt_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QuizServiceServer).StartQuestionAttempt(ctx, req.(*StartQuestionAttemptRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _QuizService_ListGradingQueue_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListGradingQueueRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QuizServiceServer).ListGradingQueue(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: QuizService_ListGradingQueue_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QuizServiceServer).ListGradingQueue(ctx, req.(*ListGradingQueueRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _QuizService_GradeAnswers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GradeAnswersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QuizServiceServer).GradeAnswers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: QuizService_GradeAnswers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QuizServiceServer).GradeAnswers(ctx, req.(*GradeAnswersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // QuizService_ServiceDesc is the grpc.ServiceDesc for QuizService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -321,6 +1055,90 @@ var QuizService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetKnowledgeStats",
 			Handler:    _QuizService_GetKnowledgeStats_Handler,
 		},
+		{
+			MethodName: "GetQuestionAnalytics",
+			Handler:    _QuizService_GetQuestionAnalytics_Handler,
+		},
+		{
+			MethodName: "ListMistakes",
+			Handler:    _QuizService_ListMistakes_Handler,
+		},
+		{
+			MethodName: "GetRetryQuestions",
+			Handler:    _QuizService_GetRetryQuestions_Handler,
+		},
+		{
+			MethodName: "GetGradingPolicy",
+			Handler:    _QuizService_GetGradingPolicy_Handler,
+		},
+		{
+			MethodName: "SetGradingPolicy",
+			Handler:    _QuizService_SetGradingPolicy_Handler,
+		},
+		{
+			MethodName: "CreateQuizShare",
+			Handler:    _QuizService_CreateQuizShare_Handler,
+		},
+		{
+			MethodName: "GetQuizShare",
+			Handler:    _QuizService_GetQuizShare_Handler,
+		},
+		{
+			MethodName: "ListQuizShares",
+			Handler:    _QuizService_ListQuizShares_Handler,
+		},
+		{
+			MethodName: "RevokeQuizShare",
+			Handler:    _QuizService_RevokeQuizShare_Handler,
+		},
+		{
+			MethodName: "SubmitQuizShareAttempt",
+			Handler:    _QuizService_SubmitQuizShareAttempt_Handler,
+		},
+		{
+			MethodName: "ListQuizShareAttempts",
+			Handler:    _QuizService_ListQuizShareAttempts_Handler,
+		},
+		{
+			MethodName: "AssignQuestionReviewer",
+			Handler:    _QuizService_AssignQuestionReviewer_Handler,
+		},
+		{
+			MethodName: "ReviewQuestion",
+			Handler:    _QuizService_ReviewQuestion_Handler,
+		},
+		{
+			MethodName: "BulkApproveQuestions",
+			Handler:    _QuizService_BulkApproveQuestions_Handler,
+		},
+		{
+			MethodName: "ListReviewQueue",
+			Handler:    _QuizService_ListReviewQueue_Handler,
+		},
+		{
+			MethodName: "UpdateQuestion",
+			Handler:    _QuizService_UpdateQuestion_Handler,
+		},
+		{
+			MethodName: "ListQuestionVersions",
+			Handler:    _QuizService_ListQuestionVersions_Handler,
+		},
+		{
+			MethodName: "GetQuestionVersion",
+			Handler:    _QuizService_GetQuestionVersion_Handler,
+		},
+		{
+			MethodName: "StartQuestionAttempt",
+			Handler:    _QuizService_StartQuestionAttempt_Handler,
+		},
+		{
+			MethodName: "ListGradingQueue",
+			Handler:    _QuizService_ListGradingQueue_Handler,
+		},
+		{
+			MethodName: "GradeAnswers",
+			Handler:    _QuizService_GradeAnswers_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "quiz/quiz.proto",
@@ -0,0 +1,1121 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.9
+// 	protoc        v3.19.6
+// source: proto/study/study.proto
+
+package study
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type StudySession struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Id              string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	UserId          string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	MaterialId      string                 `protobuf:"bytes,3,opt,name=material_id,json=materialId,proto3" json:"material_id,omitempty"`
+	Status          string                 `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`                         // active / ended
+	StartedAt       int64                  `protobuf:"varint,5,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"` // Unix 秒
+	LastHeartbeatAt int64                  `protobuf:"varint,6,opt,name=last_heartbeat_at,json=lastHeartbeatAt,proto3" json:"last_heartbeat_at,omitempty"`
+	EndedAt         int64                  `protobuf:"varint,7,opt,name=ended_at,json=endedAt,proto3" json:"ended_at,omitempty"`                         // 未结束时为 0
+	DurationSeconds int64                  `protobuf:"varint,8,opt,name=duration_seconds,json=durationSeconds,proto3" json:"duration_seconds,omitempty"` // 已计入的有效学习时长
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *StudySession) Reset() {
+	*x = StudySession{}
+	mi := &file_proto_study_study_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StudySession) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StudySession) ProtoMessage() {}
+
+func (x *StudySession) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_study_study_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StudySession.ProtoReflect.Descriptor instead.
+func (*StudySession) Descriptor() ([]byte, []int) {
+	return file_proto_study_study_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *StudySession) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *StudySession) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *StudySession) GetMaterialId() string {
+	if x != nil {
+		return x.MaterialId
+	}
+	return ""
+}
+
+func (x *StudySession) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *StudySession) GetStartedAt() int64 {
+	if x != nil {
+		return x.StartedAt
+	}
+	return 0
+}
+
+func (x *StudySession) GetLastHeartbeatAt() int64 {
+	if x != nil {
+		return x.LastHeartbeatAt
+	}
+	return 0
+}
+
+func (x *StudySession) GetEndedAt() int64 {
+	if x != nil {
+		return x.EndedAt
+	}
+	return 0
+}
+
+func (x *StudySession) GetDurationSeconds() int64 {
+	if x != nil {
+		return x.DurationSeconds
+	}
+	return 0
+}
+
+type StartSessionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	MaterialId    string                 `protobuf:"bytes,2,opt,name=material_id,json=materialId,proto3" json:"material_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StartSessionRequest) Reset() {
+	*x = StartSessionRequest{}
+	mi := &file_proto_study_study_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StartSessionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StartSessionRequest) ProtoMessage() {}
+
+func (x *StartSessionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_study_study_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StartSessionRequest.ProtoReflect.Descriptor instead.
+func (*StartSessionRequest) Descriptor() ([]byte, []int) {
+	return file_proto_study_study_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *StartSessionRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *StartSessionRequest) GetMaterialId() string {
+	if x != nil {
+		return x.MaterialId
+	}
+	return ""
+}
+
+type SessionActionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SessionId     string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SessionActionRequest) Reset() {
+	*x = SessionActionRequest{}
+	mi := &file_proto_study_study_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SessionActionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SessionActionRequest) ProtoMessage() {}
+
+func (x *SessionActionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_study_study_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SessionActionRequest.ProtoReflect.Descriptor instead.
+func (*SessionActionRequest) Descriptor() ([]byte, []int) {
+	return file_proto_study_study_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *SessionActionRequest) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *SessionActionRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type SessionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Session       *StudySession          `protobuf:"bytes,3,opt,name=session,proto3" json:"session,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SessionResponse) Reset() {
+	*x = SessionResponse{}
+	mi := &file_proto_study_study_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SessionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SessionResponse) ProtoMessage() {}
+
+func (x *SessionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_study_study_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SessionResponse.ProtoReflect.Descriptor instead.
+func (*SessionResponse) Descriptor() ([]byte, []int) {
+	return file_proto_study_study_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *SessionResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *SessionResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *SessionResponse) GetSession() *StudySession {
+	if x != nil {
+		return x.Session
+	}
+	return nil
+}
+
+// period: daily / weekly
+type StudyGoal struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Period        string                 `protobuf:"bytes,1,opt,name=period,proto3" json:"period,omitempty"`
+	TargetMinutes int32                  `protobuf:"varint,2,opt,name=target_minutes,json=targetMinutes,proto3" json:"target_minutes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StudyGoal) Reset() {
+	*x = StudyGoal{}
+	mi := &file_proto_study_study_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StudyGoal) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StudyGoal) ProtoMessage() {}
+
+func (x *StudyGoal) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_study_study_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StudyGoal.ProtoReflect.Descriptor instead.
+func (*StudyGoal) Descriptor() ([]byte, []int) {
+	return file_proto_study_study_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *StudyGoal) GetPeriod() string {
+	if x != nil {
+		return x.Period
+	}
+	return ""
+}
+
+func (x *StudyGoal) GetTargetMinutes() int32 {
+	if x != nil {
+		return x.TargetMinutes
+	}
+	return 0
+}
+
+type SetGoalRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Period        string                 `protobuf:"bytes,2,opt,name=period,proto3" json:"period,omitempty"`
+	TargetMinutes int32                  `protobuf:"varint,3,opt,name=target_minutes,json=targetMinutes,proto3" json:"target_minutes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetGoalRequest) Reset() {
+	*x = SetGoalRequest{}
+	mi := &file_proto_study_study_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetGoalRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetGoalRequest) ProtoMessage() {}
+
+func (x *SetGoalRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_study_study_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetGoalRequest.ProtoReflect.Descriptor instead.
+func (*SetGoalRequest) Descriptor() ([]byte, []int) {
+	return file_proto_study_study_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *SetGoalRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *SetGoalRequest) GetPeriod() string {
+	if x != nil {
+		return x.Period
+	}
+	return ""
+}
+
+func (x *SetGoalRequest) GetTargetMinutes() int32 {
+	if x != nil {
+		return x.TargetMinutes
+	}
+	return 0
+}
+
+type GoalResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Goal          *StudyGoal             `protobuf:"bytes,3,opt,name=goal,proto3" json:"goal,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GoalResponse) Reset() {
+	*x = GoalResponse{}
+	mi := &file_proto_study_study_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GoalResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GoalResponse) ProtoMessage() {}
+
+func (x *GoalResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_study_study_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GoalResponse.ProtoReflect.Descriptor instead.
+func (*GoalResponse) Descriptor() ([]byte, []int) {
+	return file_proto_study_study_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GoalResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *GoalResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *GoalResponse) GetGoal() *StudyGoal {
+	if x != nil {
+		return x.Goal
+	}
+	return nil
+}
+
+type ListGoalsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListGoalsRequest) Reset() {
+	*x = ListGoalsRequest{}
+	mi := &file_proto_study_study_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListGoalsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListGoalsRequest) ProtoMessage() {}
+
+func (x *ListGoalsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_study_study_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListGoalsRequest.ProtoReflect.Descriptor instead.
+func (*ListGoalsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_study_study_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ListGoalsRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type ListGoalsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Goals         []*StudyGoal           `protobuf:"bytes,3,rep,name=goals,proto3" json:"goals,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListGoalsResponse) Reset() {
+	*x = ListGoalsResponse{}
+	mi := &file_proto_study_study_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListGoalsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListGoalsResponse) ProtoMessage() {}
+
+func (x *ListGoalsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_study_study_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListGoalsResponse.ProtoReflect.Descriptor instead.
+func (*ListGoalsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_study_study_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ListGoalsResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *ListGoalsResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *ListGoalsResponse) GetGoals() []*StudyGoal {
+	if x != nil {
+		return x.Goals
+	}
+	return nil
+}
+
+type DeleteGoalRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Period        string                 `protobuf:"bytes,2,opt,name=period,proto3" json:"period,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteGoalRequest) Reset() {
+	*x = DeleteGoalRequest{}
+	mi := &file_proto_study_study_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteGoalRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteGoalRequest) ProtoMessage() {}
+
+func (x *DeleteGoalRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_study_study_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteGoalRequest.ProtoReflect.Descriptor instead.
+func (*DeleteGoalRequest) Descriptor() ([]byte, []int) {
+	return file_proto_study_study_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *DeleteGoalRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *DeleteGoalRequest) GetPeriod() string {
+	if x != nil {
+		return x.Period
+	}
+	return ""
+}
+
+type DeleteGoalResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteGoalResponse) Reset() {
+	*x = DeleteGoalResponse{}
+	mi := &file_proto_study_study_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteGoalResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteGoalResponse) ProtoMessage() {}
+
+func (x *DeleteGoalResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_study_study_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteGoalResponse.ProtoReflect.Descriptor instead.
+func (*DeleteGoalResponse) Descriptor() ([]byte, []int) {
+	return file_proto_study_study_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *DeleteGoalResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *DeleteGoalResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type DailyStudy struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Date          string                 `protobuf:"bytes,1,opt,name=date,proto3" json:"date,omitempty"`
+	Seconds       int64                  `protobuf:"varint,2,opt,name=seconds,proto3" json:"seconds,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DailyStudy) Reset() {
+	*x = DailyStudy{}
+	mi := &file_proto_study_study_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DailyStudy) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DailyStudy) ProtoMessage() {}
+
+func (x *DailyStudy) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_study_study_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DailyStudy.ProtoReflect.Descriptor instead.
+func (*DailyStudy) Descriptor() ([]byte, []int) {
+	return file_proto_study_study_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *DailyStudy) GetDate() string {
+	if x != nil {
+		return x.Date
+	}
+	return ""
+}
+
+func (x *DailyStudy) GetSeconds() int64 {
+	if x != nil {
+		return x.Seconds
+	}
+	return 0
+}
+
+// 目标完成情况，streak 的单位与 period 一致（天 / 周）
+type GoalProgress struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Period          string                 `protobuf:"bytes,1,opt,name=period,proto3" json:"period,omitempty"`
+	TargetMinutes   int32                  `protobuf:"varint,2,opt,name=target_minutes,json=targetMinutes,proto3" json:"target_minutes,omitempty"`
+	ProgressSeconds int64                  `protobuf:"varint,3,opt,name=progress_seconds,json=progressSeconds,proto3" json:"progress_seconds,omitempty"` // 当前周期内已学习时长
+	Achieved        bool                   `protobuf:"varint,4,opt,name=achieved,proto3" json:"achieved,omitempty"`
+	CurrentStreak   int32                  `protobuf:"varint,5,opt,name=current_streak,json=currentStreak,proto3" json:"current_streak,omitempty"`
+	LongestStreak   int32                  `protobuf:"varint,6,opt,name=longest_streak,json=longestStreak,proto3" json:"longest_streak,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *GoalProgress) Reset() {
+	*x = GoalProgress{}
+	mi := &file_proto_study_study_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GoalProgress) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GoalProgress) ProtoMessage() {}
+
+func (x *GoalProgress) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_study_study_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GoalProgress.ProtoReflect.Descriptor instead.
+func (*GoalProgress) Descriptor() ([]byte, []int) {
+	return file_proto_study_study_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *GoalProgress) GetPeriod() string {
+	if x != nil {
+		return x.Period
+	}
+	return ""
+}
+
+func (x *GoalProgress) GetTargetMinutes() int32 {
+	if x != nil {
+		return x.TargetMinutes
+	}
+	return 0
+}
+
+func (x *GoalProgress) GetProgressSeconds() int64 {
+	if x != nil {
+		return x.ProgressSeconds
+	}
+	return 0
+}
+
+func (x *GoalProgress) GetAchieved() bool {
+	if x != nil {
+		return x.Achieved
+	}
+	return false
+}
+
+func (x *GoalProgress) GetCurrentStreak() int32 {
+	if x != nil {
+		return x.CurrentStreak
+	}
+	return 0
+}
+
+func (x *GoalProgress) GetLongestStreak() int32 {
+	if x != nil {
+		return x.LongestStreak
+	}
+	return 0
+}
+
+type GetStudyStatsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Days          int32                  `protobuf:"varint,2,opt,name=days,proto3" json:"days,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetStudyStatsRequest) Reset() {
+	*x = GetStudyStatsRequest{}
+	mi := &file_proto_study_study_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStudyStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStudyStatsRequest) ProtoMessage() {}
+
+func (x *GetStudyStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_study_study_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStudyStatsRequest.ProtoReflect.Descriptor instead.
+func (*GetStudyStatsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_study_study_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *GetStudyStatsRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *GetStudyStatsRequest) GetDays() int32 {
+	if x != nil {
+		return x.Days
+	}
+	return 0
+}
+
+type GetStudyStatsResponse struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Success         bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message         string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	TodaySeconds    int64                  `protobuf:"varint,3,opt,name=today_seconds,json=todaySeconds,proto3" json:"today_seconds,omitempty"`
+	WeekSeconds     int64                  `protobuf:"varint,4,opt,name=week_seconds,json=weekSeconds,proto3" json:"week_seconds,omitempty"`
+	TotalSeconds    int64                  `protobuf:"varint,5,opt,name=total_seconds,json=totalSeconds,proto3" json:"total_seconds,omitempty"` // 统计窗口内的总时长
+	Daily           []*DailyStudy          `protobuf:"bytes,6,rep,name=daily,proto3" json:"daily,omitempty"`                                    // 最近 days 天，按日期升序，无学习的日期为 0
+	Goals           []*GoalProgress        `protobuf:"bytes,7,rep,name=goals,proto3" json:"goals,omitempty"`
+	StudyStreakDays int32                  `protobuf:"varint,8,opt,name=study_streak_days,json=studyStreakDays,proto3" json:"study_streak_days,omitempty"` // 连续有学习记录的天数
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *GetStudyStatsResponse) Reset() {
+	*x = GetStudyStatsResponse{}
+	mi := &file_proto_study_study_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStudyStatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStudyStatsResponse) ProtoMessage() {}
+
+func (x *GetStudyStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_study_study_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStudyStatsResponse.ProtoReflect.Descriptor instead.
+func (*GetStudyStatsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_study_study_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *GetStudyStatsResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *GetStudyStatsResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *GetStudyStatsResponse) GetTodaySeconds() int64 {
+	if x != nil {
+		return x.TodaySeconds
+	}
+	return 0
+}
+
+func (x *GetStudyStatsResponse) GetWeekSeconds() int64 {
+	if x != nil {
+		return x.WeekSeconds
+	}
+	return 0
+}
+
+func (x *GetStudyStatsResponse) GetTotalSeconds() int64 {
+	if x != nil {
+		return x.TotalSeconds
+	}
+	return 0
+}
+
+func (x *GetStudyStatsResponse) GetDaily() []*DailyStudy {
+	if x != nil {
+		return x.Daily
+	}
+	return nil
+}
+
+func (x *GetStudyStatsResponse) GetGoals() []*GoalProgress {
+	if x != nil {
+		return x.Goals
+	}
+	return nil
+}
+
+func (x *GetStudyStatsResponse) GetStudyStreakDays() int32 {
+	if x != nil {
+		return x.StudyStreakDays
+	}
+	return 0
+}
+
+var File_proto_study_study_proto protoreflect.FileDescriptor
+
+const file_proto_study_study_proto_rawDesc = "" +
+	"\n" +
+	"\x17proto/study/study.proto\x12\x05study\"\x81\x02\n" +
+	"\fStudySession\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x1f\n" +
+	"\vmaterial_id\x18\x03 \x01(\tR\n" +
+	"materialId\x12\x16\n" +
+	"\x06status\x18\x04 \x01(\tR\x06status\x12\x1d\n" +
+	"\n" +
+	"started_at\x18\x05 \x01(\x03R\tstartedAt\x12*\n" +
+	"\x11last_heartbeat_at\x18\x06 \x01(\x03R\x0flastHeartbeatAt\x12\x19\n" +
+	"\bended_at\x18\a \x01(\x03R\aendedAt\x12)\n" +
+	"\x10duration_seconds\x18\b \x01(\x03R\x0fdurationSeconds\"O\n" +
+	"\x13StartSessionRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1f\n" +
+	"\vmaterial_id\x18\x02 \x01(\tR\n" +
+	"materialId\"N\n" +
+	"\x14SessionActionRequest\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x01 \x01(\tR\tsessionId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\"t\n" +
+	"\x0fSessionResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12-\n" +
+	"\asession\x18\x03 \x01(\v2\x13.study.StudySessionR\asession\"J\n" +
+	"\tStudyGoal\x12\x16\n" +
+	"\x06period\x18\x01 \x01(\tR\x06period\x12%\n" +
+	"\x0etarget_minutes\x18\x02 \x01(\x05R\rtargetMinutes\"h\n" +
+	"\x0eSetGoalRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x16\n" +
+	"\x06period\x18\x02 \x01(\tR\x06period\x12%\n" +
+	"\x0etarget_minutes\x18\x03 \x01(\x05R\rtargetMinutes\"h\n" +
+	"\fGoalResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12$\n" +
+	"\x04goal\x18\x03 \x01(\v2\x10.study.StudyGoalR\x04goal\"+\n" +
+	"\x10ListGoalsRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\"o\n" +
+	"\x11ListGoalsResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12&\n" +
+	"\x05goals\x18\x03 \x03(\v2\x10.study.StudyGoalR\x05goals\"D\n" +
+	"\x11DeleteGoalRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x16\n" +
+	"\x06period\x18\x02 \x01(\tR\x06period\"H\n" +
+	"\x12DeleteGoalResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\":\n" +
+	"\n" +
+	"DailyStudy\x12\x12\n" +
+	"\x04date\x18\x01 \x01(\tR\x04date\x12\x18\n" +
+	"\aseconds\x18\x02 \x01(\x03R\aseconds\"\xe2\x01\n" +
+	"\fGoalProgress\x12\x16\n" +
+	"\x06period\x18\x01 \x01(\tR\x06period\x12%\n" +
+	"\x0etarget_minutes\x18\x02 \x01(\x05R\rtargetMinutes\x12)\n" +
+	"\x10progress_seconds\x18\x03 \x01(\x03R\x0fprogressSeconds\x12\x1a\n" +
+	"\bachieved\x18\x04 \x01(\bR\bachieved\x12%\n" +
+	"\x0ecurrent_streak\x18\x05 \x01(\x05R\rcurrentStreak\x12%\n" +
+	"\x0elongest_streak\x18\x06 \x01(\x05R\rlongestStreak\"C\n" +
+	"\x14GetStudyStatsRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x12\n" +
+	"\x04days\x18\x02 \x01(\x05R\x04days\"\xb8\x02\n" +
+	"\x15GetStudyStatsResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12#\n" +
+	"\rtoday_seconds\x18\x03 \x01(\x03R\ftodaySeconds\x12!\n" +
+	"\fweek_seconds\x18\x04 \x01(\x03R\vweekSeconds\x12#\n" +
+	"\rtotal_seconds\x18\x05 \x01(\x03R\ftotalSeconds\x12'\n" +
+	"\x05daily\x18\x06 \x03(\v2\x11.study.DailyStudyR\x05daily\x12)\n" +
+	"\x05goals\x18\a \x03(\v2\x13.study.GoalProgressR\x05goals\x12*\n" +
+	"\x11study_streak_days\x18\b \x01(\x05R\x0fstudyStreakDays2\xde\x03\n" +
+	"\fStudyService\x12B\n" +
+	"\fStartSession\x12\x1a.study.StartSessionRequest\x1a\x16.study.SessionResponse\x12@\n" +
+	"\tHeartbeat\x12\x1b.study.SessionActionRequest\x1a\x16.study.SessionResponse\x12B\n" +
+	"\vStopSession\x12\x1b.study.SessionActionRequest\x1a\x16.study.SessionResponse\x125\n" +
+	"\aSetGoal\x12\x15.study.SetGoalRequest\x1a\x13.study.GoalResponse\x12>\n" +
+	"\tListGoals\x12\x17.study.ListGoalsRequest\x1a\x18.study.ListGoalsResponse\x12A\n" +
+	"\n" +
+	"DeleteGoal\x12\x18.study.DeleteGoalRequest\x1a\x19.study.DeleteGoalResponse\x12J\n" +
+	"\rGetStudyStats\x12\x1b.study.GetStudyStatsRequest\x1a\x1c.study.GetStudyStatsResponseB+Z)github.com/RigelNana/arkstudy/proto/studyb\x06proto3"
+
+var (
+	file_proto_study_study_proto_rawDescOnce sync.Once
+	file_proto_study_study_proto_rawDescData []byte
+)
+
+func file_proto_study_study_proto_rawDescGZIP() []byte {
+	file_proto_study_study_proto_rawDescOnce.Do(func() {
+		file_proto_study_study_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_proto_study_study_proto_rawDesc), len(file_proto_study_study_proto_rawDesc)))
+	})
+	return file_proto_study_study_proto_rawDescData
+}
+
+var file_proto_study_study_proto_msgTypes = make([]protoimpl.MessageInfo, 15)
+var file_proto_study_study_proto_goTypes = []any{
+	(*StudySession)(nil),          // 0: study.StudySession
+	(*StartSessionRequest)(nil),   // 1: study.StartSessionRequest
+	(*SessionActionRequest)(nil),  // 2: study.SessionActionRequest
+	(*SessionResponse)(nil),       // 3: study.SessionResponse
+	(*StudyGoal)(nil),             // 4: study.StudyGoal
+	(*SetGoalRequest)(nil),        // 5: study.SetGoalRequest
+	(*GoalResponse)(nil),          // 6: study.GoalResponse
+	(*ListGoalsRequest)(nil),      // 7: study.ListGoalsRequest
+	(*ListGoalsResponse)(nil),     // 8: study.ListGoalsResponse
+	(*DeleteGoalRequest)(nil),     // 9: study.DeleteGoalRequest
+	(*DeleteGoalResponse)(nil),    // 10: study.DeleteGoalResponse
+	(*DailyStudy)(nil),            // 11: study.DailyStudy
+	(*GoalProgress)(nil),          // 12: study.GoalProgress
+	(*GetStudyStatsRequest)(nil),  // 13: study.GetStudyStatsRequest
+	(*GetStudyStatsResponse)(nil), // 14: study.GetStudyStatsResponse
+}
+var file_proto_study_study_proto_depIdxs = []int32{
+	0,  // 0: study.SessionResponse.session:type_name -> study.StudySession
+	4,  // 1: study.GoalResponse.goal:type_name -> study.StudyGoal
+	4,  // 2: study.ListGoalsResponse.goals:type_name -> study.StudyGoal
+	11, // 3: study.GetStudyStatsResponse.daily:type_name -> study.DailyStudy
+	12, // 4: study.GetStudyStatsResponse.goals:type_name -> study.GoalProgress
+	1,  // 5: study.StudyService.StartSession:input_type -> study.StartSessionRequest
+	2,  // 6: study.StudyService.Heartbeat:input_type -> study.SessionActionRequest
+	2,  // 7: study.StudyService.StopSession:input_type -> study.SessionActionRequest
+	5,  // 8: study.StudyService.SetGoal:input_type -> study.SetGoalRequest
+	7,  // 9: study.StudyService.ListGoals:input_type -> study.ListGoalsRequest
+	9,  // 10: study.StudyService.DeleteGoal:input_type -> study.DeleteGoalRequest
+	13, // 11: study.StudyService.GetStudyStats:input_type -> study.GetStudyStatsRequest
+	3,  // 12: study.StudyService.StartSession:output_type -> study.SessionResponse
+	3,  // 13: study.StudyService.Heartbeat:output_type -> study.SessionResponse
+	3,  // 14: study.StudyService.StopSession:output_type -> study.SessionResponse
+	6,  // 15: study.StudyService.SetGoal:output_type -> study.GoalResponse
+	8,  // 16: study.StudyService.ListGoals:output_type -> study.ListGoalsResponse
+	10, // 17: study.StudyService.DeleteGoal:output_type -> study.DeleteGoalResponse
+	14, // 18: study.StudyService.GetStudyStats:output_type -> study.GetStudyStatsResponse
+	12, // [12:19] is the sub-list for method output_type
+	5,  // [5:12] is the sub-list for method input_type
+	5,  // [5:5] is the sub-list for extension type_name
+	5,  // [5:5] is the sub-list for extension extendee
+	0,  // [0:5] is the sub-list for field type_name
+}
+
+func init() { file_proto_study_study_proto_init() }
+func file_proto_study_study_proto_init() {
+	if File_proto_study_study_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_study_study_proto_rawDesc), len(file_proto_study_study_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   15,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_proto_study_study_proto_goTypes,
+		DependencyIndexes: file_proto_study_study_proto_depIdxs,
+		MessageInfos:      file_proto_study_study_proto_msgTypes,
+	}.Build()
+	File_proto_study_study_proto = out.File
+	file_proto_study_study_proto_goTypes = nil
+	file_proto_study_study_proto_depIdxs = nil
+}
@@ -0,0 +1,353 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v3.19.6
+// source: proto/study/study.proto
+
+package study
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	StudyService_StartSession_FullMethodName  = "/study.StudyService/StartSession"
+	StudyService_Heartbeat_FullMethodName     = "/study.StudyService/Heartbeat"
+	StudyService_StopSession_FullMethodName   = "/study.StudyService/StopSession"
+	StudyService_SetGoal_FullMethodName       = "/study.StudyService/SetGoal"
+	StudyService_ListGoals_FullMethodName     = "/study.StudyService/ListGoals"
+	StudyService_DeleteGoal_FullMethodName    = "/study.StudyService/DeleteGoal"
+	StudyService_GetStudyStats_FullMethodName = "/study.StudyService/GetStudyStats"
+)
+
+// StudyServiceClient is the client API for StudyService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// 学习时长记录与目标：前端开始学习时创建会话，之后定期发送心跳，离开时结束会话
+type StudyServiceClient interface {
+	StartSession(ctx context.Context, in *StartSessionRequest, opts ...grpc.CallOption) (*SessionResponse, error)
+	Heartbeat(ctx context.Context, in *SessionActionRequest, opts ...grpc.CallOption) (*SessionResponse, error)
+	StopSession(ctx context.Context, in *SessionActionRequest, opts ...grpc.CallOption) (*SessionResponse, error)
+	SetGoal(ctx context.Context, in *SetGoalRequest, opts ...grpc.CallOption) (*GoalResponse, error)
+	ListGoals(ctx context.Context, in *ListGoalsRequest, opts ...grpc.CallOption) (*ListGoalsResponse, error)
+	DeleteGoal(ctx context.Context, in *DeleteGoalRequest, opts ...grpc.CallOption) (*DeleteGoalResponse, error)
+	GetStudyStats(ctx context.Context, in *GetStudyStatsRequest, opts ...grpc.CallOption) (*GetStudyStatsResponse, error)
+}
+
+type studyServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewStudyServiceClient(cc grpc.ClientConnInterface) StudyServiceClient {
+	return &studyServiceClient{cc}
+}
+
+func (c *studyServiceClient) StartSession(ctx context.Context, in *StartSessionRequest, opts ...grpc.CallOption) (*SessionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SessionResponse)
+	err := c.cc.Invoke(ctx, StudyService_StartSession_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *studyServiceClient) Heartbeat(ctx context.Context, in *SessionActionRequest, opts ...grpc.CallOption) (*SessionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SessionResponse)
+	err := c.cc.Invoke(ctx, StudyService_Heartbeat_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *studyServiceClient) StopSession(ctx context.Context, in *SessionActionRequest, opts ...grpc.CallOption) (*SessionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SessionResponse)
+	err := c.cc.Invoke(ctx, StudyService_StopSession_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *studyServiceClient) SetGoal(ctx context.Context, in *SetGoalRequest, opts ...grpc.CallOption) (*GoalResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GoalResponse)
+	err := c.cc.Invoke(ctx, StudyService_SetGoal_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *studyServiceClient) ListGoals(ctx context.Context, in *ListGoalsRequest, opts ...grpc.CallOption) (*ListGoalsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListGoalsResponse)
+	err := c.cc.Invoke(ctx, StudyService_ListGoals_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *studyServiceClient) DeleteGoal(ctx context.Context, in *DeleteGoalRequest, opts ...grpc.CallOption) (*DeleteGoalResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteGoalResponse)
+	err := c.cc.Invoke(ctx, StudyService_DeleteGoal_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *studyServiceClient) GetStudyStats(ctx context.Context, in *GetStudyStatsRequest, opts ...grpc.CallOption) (*GetStudyStatsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetStudyStatsResponse)
+	err := c.cc.Invoke(ctx, StudyService_GetStudyStats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// StudyServiceServer is the server API for StudyService service.
+// All implementations must embed UnimplementedStudyServiceServer
+// for forward compatibility.
+//
+// 学习时长记录与目标：前端开始学习时创建会话，之后定期发送心跳，离开时结束会话
+type StudyServiceServer interface {
+	StartSession(context.Context, *StartSessionRequest) (*SessionResponse, error)
+	Heartbeat(context.Context, *SessionActionRequest) (*SessionResponse, error)
+	StopSession(context.Context, *SessionActionRequest) (*SessionResponse, error)
+	SetGoal(context.Context, *SetGoalRequest) (*GoalResponse, error)
+	ListGoals(context.Context, *ListGoalsRequest) (*ListGoalsResponse, error)
+	DeleteGoal(context.Context, *DeleteGoalRequest) (*DeleteGoalResponse, error)
+	GetStudyStats(context.Context, *GetStudyStatsRequest) (*GetStudyStatsResponse, error)
+	mustEmbedUnimplementedStudyServiceServer()
+}
+
+// UnimplementedStudyServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedStudyServiceServer struct{}
+
+func (UnimplementedStudyServiceServer) StartSession(context.Context, *StartSessionRequest) (*SessionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StartSession not implemented")
+}
+func (UnimplementedStudyServiceServer) Heartbeat(context.Context, *SessionActionRequest) (*SessionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Heartbeat not implemented")
+}
+func (UnimplementedStudyServiceServer) StopSession(context.Context, *SessionActionRequest) (*SessionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StopSession not implemented")
+}
+func (UnimplementedStudyServiceServer) SetGoal(context.Context, *SetGoalRequest) (*GoalResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetGoal not implemented")
+}
+func (UnimplementedStudyServiceServer) ListGoals(context.Context, *ListGoalsRequest) (*ListGoalsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListGoals not implemented")
+}
+func (UnimplementedStudyServiceServer) DeleteGoal(context.Context, *DeleteGoalRequest) (*DeleteGoalResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteGoal not implemented")
+}
+func (UnimplementedStudyServiceServer) GetStudyStats(context.Context, *GetStudyStatsRequest) (*GetStudyStatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStudyStats not implemented")
+}
+func (UnimplementedStudyServiceServer) mustEmbedUnimplementedStudyServiceServer() {}
+func (UnimplementedStudyServiceServer) testEmbeddedByValue()                      {}
+
+// UnsafeStudyServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to StudyServiceServer will
+// result in compilation errors.
+type UnsafeStudyServiceServer interface {
+	mustEmbedUnimplementedStudyServiceServer()
+}
+
+func RegisterStudyServiceServer(s grpc.ServiceRegistrar, srv StudyServiceServer) {
+	// If the following call pancis, it indicates UnimplementedStudyServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&StudyService_ServiceDesc, srv)
+}
+
+func _StudyService_StartSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StartSessionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StudyServiceServer).StartSession(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: StudyService_StartSession_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StudyServiceServer).StartSession(ctx, req.(*StartSessionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StudyService_Heartbeat_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SessionActionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StudyServiceServer).Heartbeat(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: StudyService_Heartbeat_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StudyServiceServer).Heartbeat(ctx, req.(*SessionActionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StudyService_StopSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SessionActionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StudyServiceServer).StopSession(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: StudyService_StopSession_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StudyServiceServer).StopSession(ctx, req.(*SessionActionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StudyService_SetGoal_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetGoalRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StudyServiceServer).SetGoal(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: StudyService_SetGoal_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StudyServiceServer).SetGoal(ctx, req.(*SetGoalRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StudyService_ListGoals_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListGoalsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StudyServiceServer).ListGoals(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: StudyService_ListGoals_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StudyServiceServer).ListGoals(ctx, req.(*ListGoalsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StudyService_DeleteGoal_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteGoalRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StudyServiceServer).DeleteGoal(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: StudyService_DeleteGoal_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StudyServiceServer).DeleteGoal(ctx, req.(*DeleteGoalRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StudyService_GetStudyStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStudyStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StudyServiceServer).GetStudyStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: StudyService_GetStudyStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StudyServiceServer).GetStudyStats(ctx, req.(*GetStudyStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// StudyService_ServiceDesc is the grpc.ServiceDesc for StudyService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var StudyService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "study.StudyService",
+	HandlerType: (*StudyServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "StartSession",
+			Handler:    _StudyService_StartSession_Handler,
+		},
+		{
+			MethodName: "Heartbeat",
+			Handler:    _StudyService_Heartbeat_Handler,
+		},
+		{
+			MethodName: "StopSession",
+			Handler:    _StudyService_StopSession_Handler,
+		},
+		{
+			MethodName: "SetGoal",
+			Handler:    _StudyService_SetGoal_Handler,
+		},
+		{
+			MethodName: "ListGoals",
+			Handler:    _StudyService_ListGoals_Handler,
+		},
+		{
+			MethodName: "DeleteGoal",
+			Handler:    _StudyService_DeleteGoal_Handler,
+		},
+		{
+			MethodName: "GetStudyStats",
+			Handler:    _StudyService_GetStudyStats_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "proto/study/study.proto",
+}
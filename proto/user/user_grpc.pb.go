@@ -19,11 +19,17 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	UserService_CreateUser_FullMethodName        = "/user.UserService/CreateUser"
-	UserService_GetUserByID_FullMethodName       = "/user.UserService/GetUserByID"
-	UserService_GetUserByUsername_FullMethodName = "/user.UserService/GetUserByUsername"
-	UserService_GetUserByEmail_FullMethodName    = "/user.UserService/GetUserByEmail"
-	UserService_ListUsers_FullMethodName         = "/user.UserService/ListUsers"
+	UserService_CreateUser_FullMethodName          = "/user.UserService/CreateUser"
+	UserService_GetUserByID_FullMethodName         = "/user.UserService/GetUserByID"
+	UserService_GetUserByUsername_FullMethodName   = "/user.UserService/GetUserByUsername"
+	UserService_GetUserByEmail_FullMethodName      = "/user.UserService/GetUserByEmail"
+	UserService_ListUsers_FullMethodName           = "/user.UserService/ListUsers"
+	UserService_ListUserActivity_FullMethodName    = "/user.UserService/ListUserActivity"
+	UserService_ListMaterialAccess_FullMethodName  = "/user.UserService/ListMaterialAccess"
+	UserService_ListTasks_FullMethodName           = "/user.UserService/ListTasks"
+	UserService_SetDigestPreference_FullMethodName = "/user.UserService/SetDigestPreference"
+	UserService_SetLocalePreference_FullMethodName = "/user.UserService/SetLocalePreference"
+	UserService_DeleteUser_FullMethodName          = "/user.UserService/DeleteUser"
 )
 
 // UserServiceClient is the client API for UserService service.
@@ -35,6 +41,13 @@ type UserServiceClient interface {
 	GetUserByUsername(ctx context.Context, in *GetUserByUsernameRequest, opts ...grpc.CallOption) (*GetUserResponse, error)
 	GetUserByEmail(ctx context.Context, in *GetUserByEmailRequest, opts ...grpc.CallOption) (*GetUserResponse, error)
 	ListUsers(ctx context.Context, in *ListUsersRequest, opts ...grpc.CallOption) (*ListUsersResponse, error)
+	ListUserActivity(ctx context.Context, in *ListUserActivityRequest, opts ...grpc.CallOption) (*ListUserActivityResponse, error)
+	ListMaterialAccess(ctx context.Context, in *ListMaterialAccessRequest, opts ...grpc.CallOption) (*ListMaterialAccessResponse, error)
+	ListTasks(ctx context.Context, in *ListTasksRequest, opts ...grpc.CallOption) (*ListTasksResponse, error)
+	SetDigestPreference(ctx context.Context, in *SetDigestPreferenceRequest, opts ...grpc.CallOption) (*GetUserResponse, error)
+	SetLocalePreference(ctx context.Context, in *SetLocalePreferenceRequest, opts ...grpc.CallOption) (*GetUserResponse, error)
+	// 彻底删除用户，仅供 auth-service 在批量开通中途失败时回滚刚创建的用户
+	DeleteUser(ctx context.Context, in *DeleteUserRequest, opts ...grpc.CallOption) (*DeleteUserResponse, error)
 }
 
 type userServiceClient struct {
@@ -95,6 +108,66 @@ func (c *userServiceClient) ListUsers(ctx context.Context, in *ListUsersRequest,
 	return out, nil
 }
 
+func (c *userServiceClient) ListUserActivity(ctx context.Context, in *ListUserActivityRequest, opts ...grpc.CallOption) (*ListUserActivityResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListUserActivityResponse)
+	err := c.cc.Invoke(ctx, UserService_ListUserActivity_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) ListMaterialAccess(ctx context.Context, in *ListMaterialAccessRequest, opts ...grpc.CallOption) (*ListMaterialAccessResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListMaterialAccessResponse)
+	err := c.cc.Invoke(ctx, UserService_ListMaterialAccess_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) ListTasks(ctx context.Context, in *ListTasksRequest, opts ...grpc.CallOption) (*ListTasksResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListTasksResponse)
+	err := c.cc.Invoke(ctx, UserService_ListTasks_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) SetDigestPreference(ctx context.Context, in *SetDigestPreferenceRequest, opts ...grpc.CallOption) (*GetUserResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetUserResponse)
+	err := c.cc.Invoke(ctx, UserService_SetDigestPreference_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) SetLocalePreference(ctx context.Context, in *SetLocalePreferenceRequest, opts ...grpc.CallOption) (*GetUserResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetUserResponse)
+	err := c.cc.Invoke(ctx, UserService_SetLocalePreference_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) DeleteUser(ctx context.Context, in *DeleteUserRequest, opts ...grpc.CallOption) (*DeleteUserResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteUserResponse)
+	err := c.cc.Invoke(ctx, UserService_DeleteUser_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // UserServiceServer is the server API for UserService service.
 // All implementations must embed UnimplementedUserServiceServer
 // for forward compatibility.
@@ -104,6 +177,13 @@ type UserServiceServer interface {
 	GetUserByUsername(context.Context, *GetUserByUsernameRequest) (*GetUserResponse, error)
 	GetUserByEmail(context.Context, *GetUserByEmailRequest) (*GetUserResponse, error)
 	ListUsers(context.Context, *ListUsersRequest) (*ListUsersResponse, error)
+	ListUserActivity(context.Context, *ListUserActivityRequest) (*ListUserActivityResponse, error)
+	ListMaterialAccess(context.Context, *ListMaterialAccessRequest) (*ListMaterialAccessResponse, error)
+	ListTasks(context.Context, *ListTasksRequest) (*ListTasksResponse, error)
+	SetDigestPreference(context.Context, *SetDigestPreferenceRequest) (*GetUserResponse, error)
+	SetLocalePreference(context.Context, *SetLocalePreferenceRequest) (*GetUserResponse, error)
+	// 彻底删除用户，仅供 auth-service 在批量开通中途失败时回滚刚创建的用户
+	DeleteUser(context.Context, *DeleteUserRequest) (*DeleteUserResponse, error)
 	mustEmbedUnimplementedUserServiceServer()
 }
 
@@ -129,6 +209,24 @@ func (UnimplementedUserServiceServer) GetUserByEmail(context.Context, *GetUserBy
 func (UnimplementedUserServiceServer) ListUsers(context.Context, *ListUsersRequest) (*ListUsersResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListUsers not implemented")
 }
+func (UnimplementedUserServiceServer) ListUserActivity(context.Context, *ListUserActivityRequest) (*ListUserActivityResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListUserActivity not implemented")
+}
+func (UnimplementedUserServiceServer) ListMaterialAccess(context.Context, *ListMaterialAccessRequest) (*ListMaterialAccessResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListMaterialAccess not implemented")
+}
+func (UnimplementedUserServiceServer) ListTasks(context.Context, *ListTasksRequest) (*ListTasksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTasks not implemented")
+}
+func (UnimplementedUserServiceServer) SetDigestPreference(context.Context, *SetDigestPreferenceRequest) (*GetUserResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetDigestPreference not implemented")
+}
+func (UnimplementedUserServiceServer) SetLocalePreference(context.Context, *SetLocalePreferenceRequest) (*GetUserResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetLocalePreference not implemented")
+}
+func (UnimplementedUserServiceServer) DeleteUser(context.Context, *DeleteUserRequest) (*DeleteUserResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteUser not implemented")
+}
 func (UnimplementedUserServiceServer) mustEmbedUnimplementedUserServiceServer() {}
 func (UnimplementedUserServiceServer) testEmbeddedByValue()                     {}
 
@@ -240,6 +338,114 @@ func _UserService_ListUsers_Handler(srv interface{}, ctx context.Context, dec fu
 	return interceptor(ctx, in, info, handler)
 }
 
+func _UserService_ListUserActivity_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListUserActivityRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).ListUserActivity(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_ListUserActivity_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).ListUserActivity(ctx, req.(*ListUserActivityRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_ListMaterialAccess_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListMaterialAccessRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).ListMaterialAccess(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_ListMaterialAccess_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).ListMaterialAccess(ctx, req.(*ListMaterialAccessRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_ListTasks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTasksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).ListTasks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_ListTasks_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).ListTasks(ctx, req.(*ListTasksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_SetDigestPreference_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetDigestPreferenceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).SetDigestPreference(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_SetDigestPreference_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).SetDigestPreference(ctx, req.(*SetDigestPreferenceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_SetLocalePreference_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetLocalePreferenceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).SetLocalePreference(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_SetLocalePreference_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).SetLocalePreference(ctx, req.(*SetLocalePreferenceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_DeleteUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).DeleteUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_DeleteUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).DeleteUser(ctx, req.(*DeleteUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // UserService_ServiceDesc is the grpc.ServiceDesc for UserService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -267,6 +473,30 @@ var UserService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ListUsers",
 			Handler:    _UserService_ListUsers_Handler,
 		},
+		{
+			MethodName: "ListUserActivity",
+			Handler:    _UserService_ListUserActivity_Handler,
+		},
+		{
+			MethodName: "ListMaterialAccess",
+			Handler:    _UserService_ListMaterialAccess_Handler,
+		},
+		{
+			MethodName: "ListTasks",
+			Handler:    _UserService_ListTasks_Handler,
+		},
+		{
+			MethodName: "SetDigestPreference",
+			Handler:    _UserService_SetDigestPreference_Handler,
+		},
+		{
+			MethodName: "SetLocalePreference",
+			Handler:    _UserService_SetLocalePreference_Handler,
+		},
+		{
+			MethodName: "DeleteUser",
+			Handler:    _UserService_DeleteUser_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/user/user.proto",
@@ -3,7 +3,10 @@ package config
 import (
 	"log"
 	"os"
+	"strconv"
+	"time"
 
+	"github.com/RigelNana/arkstudy/pkg/registry"
 	"github.com/joho/godotenv"
 )
 
@@ -25,14 +28,43 @@ type Config struct {
 	OpenAIBaseURL string
 	OpenAIModel   string
 
+	// Chat model used for transcript post-processing (translation etc.)
+	ChatModel            string
+	TranslationBatchSize int
+
+	// Run chapter detection after each successful transcription
+	ChapterDetectionEnabled bool
+
+	// Transcription cost guardrail: audio longer than MaxAudioMinutes is rejected before
+	// it is sent to Whisper (0 disables); CostPerMinute is used for the estimate in errors and logs
+	MaxAudioMinutes float64
+	CostPerMinute   float64
+
+	// Segments whose normalized confidence falls below LowConfidenceThreshold are flagged
+	// for re-transcription with RetranscribeModel
+	LowConfidenceThreshold float64
+	RetranscribeModel      string
+
 	// FFmpeg config
-	FFmpegBinaryPath string
-	TempDir          string
-	AudioFormat      string
+	FFmpegBinaryPath    string
+	TempDir             string
+	AudioFormat         string
+	FFmpegTimeout       time.Duration // hard limit for a single ffmpeg run
+	FFmpegMaxOutputSize int64         // max bytes ffmpeg may write, 0 disables the limit
+
+	// Temp file janitor: job entries under TempDir older than TempFileTTL are removed
+	TempFileTTL         time.Duration
+	TempCleanupInterval time.Duration
 
 	// Storage config
 	MaxFileSize    string
 	AllowedFormats string
+
+	// Material service config, used to verify material references
+	MaterialServiceAddr string
+
+	// LLM service config, its vector store indexes transcript segments for moment search
+	LLMServiceAddr string
 }
 
 func LoadConfig() *Config {
@@ -51,7 +83,7 @@ func LoadConfig() *Config {
 
 		// Server
 		Port:     getEnv("ASR_PORT", "50057"),
-		GRPCPort: getEnv("GRPC_PORT", "50057"),
+		GRPCPort: registry.ASR.ListenPort(),
 		Host:     getEnv("ASR_HOST", "0.0.0.0"),
 
 		// OpenAI
@@ -59,14 +91,40 @@ func LoadConfig() *Config {
 		OpenAIBaseURL: getEnv("OPENAI_BASE_URL", "https://api.openai.com/v1"),
 		OpenAIModel:   getEnv("OPENAI_MODEL", "whisper-1"),
 
+		// Transcript post-processing
+		ChatModel:            getEnv("OPENAI_CHAT_MODEL", "gpt-3.5-turbo"),
+		TranslationBatchSize: int(getEnvInt64("TRANSLATION_BATCH_SIZE", 20)),
+
+		ChapterDetectionEnabled: getEnv("CHAPTER_DETECTION_ENABLED", "true") == "true",
+
+		// Transcription cost guardrail
+		MaxAudioMinutes: getEnvFloat("ASR_MAX_AUDIO_MINUTES", 90),
+		CostPerMinute:   getEnvFloat("ASR_COST_PER_MINUTE", 0.006),
+
+		// Confidence post-processing
+		LowConfidenceThreshold: getEnvFloat("ASR_LOW_CONFIDENCE_THRESHOLD", 0.5),
+		RetranscribeModel:      getEnv("ASR_RETRANSCRIBE_MODEL", "whisper-large-v3"),
+
 		// FFmpeg
-		FFmpegBinaryPath: getEnv("FFMPEG_BINARY_PATH", "ffmpeg"),
-		TempDir:          getEnv("TEMP_DIR", "/tmp/asr"),
-		AudioFormat:      getEnv("AUDIO_FORMAT", "wav"),
+		FFmpegBinaryPath:    getEnv("FFMPEG_BINARY_PATH", "ffmpeg"),
+		TempDir:             getEnv("TEMP_DIR", "/tmp/asr"),
+		AudioFormat:         getEnv("AUDIO_FORMAT", "wav"),
+		FFmpegTimeout:       getEnvPositiveDuration("FFMPEG_TIMEOUT", 10*time.Minute),
+		FFmpegMaxOutputSize: getEnvInt64("FFMPEG_MAX_OUTPUT_SIZE", 26214400), // 25MB, Whisper upload limit
+
+		// Temp file janitor
+		TempFileTTL:         getEnvDuration("TEMP_FILE_TTL", 6*time.Hour),
+		TempCleanupInterval: getEnvDuration("TEMP_CLEANUP_INTERVAL", 30*time.Minute),
 
 		// Storage
 		MaxFileSize:    getEnv("MAX_FILE_SIZE", "104857600"), // 100MB
 		AllowedFormats: getEnv("ALLOWED_FORMATS", "mp4,avi,mov,mkv,webm"),
+
+		// Material service
+		MaterialServiceAddr: registry.Material.Addr(),
+
+		// LLM service
+		LLMServiceAddr: registry.LLM.Addr(),
 	}
 }
 
@@ -76,3 +134,42 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if d, err := time.ParseDuration(value); err == nil {
+			return d
+		}
+		log.Printf("Invalid duration for %s: %q, using default %s", key, value, defaultValue)
+	}
+	return defaultValue
+}
+
+// getEnvPositiveDuration is getEnvDuration for settings where zero or a negative value makes no sense
+func getEnvPositiveDuration(key string, defaultValue time.Duration) time.Duration {
+	if d := getEnvDuration(key, defaultValue); d > 0 {
+		return d
+	}
+	log.Printf("%s must be positive, using default %s", key, defaultValue)
+	return defaultValue
+}
+
+func getEnvFloat(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if f, err := strconv.ParseFloat(value, 64); err == nil && f >= 0 {
+			return f
+		}
+		log.Printf("Invalid number for %s: %q, using default %g", key, value, defaultValue)
+	}
+	return defaultValue
+}
+
+func getEnvInt64(key string, defaultValue int64) int64 {
+	if value := os.Getenv(key); value != "" {
+		if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return n
+		}
+		log.Printf("Invalid integer for %s: %q, using default %d", key, value, defaultValue)
+	}
+	return defaultValue
+}
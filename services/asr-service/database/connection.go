@@ -3,6 +3,7 @@ package database
 import (
 	"log"
 
+	"github.com/RigelNana/arkstudy/pkg/dbx"
 	"github.com/RigelNana/arkstudy/services/asr-service/config"
 	"github.com/RigelNana/arkstudy/services/asr-service/models"
 
@@ -20,11 +21,22 @@ func InitDB() *gorm.DB {
 	if err != nil {
 		panic("failed to connect database: " + err.Error())
 	}
+	// Audit columns, optimistic locking and soft-delete enforcement
+	if err := db.Use(dbx.Plugin{}); err != nil {
+		panic("failed to register dbx plugin: " + err.Error())
+	}
 
 	// Auto migrate the schema
-	err = db.AutoMigrate(&models.ASRSegment{})
+	err = db.AutoMigrate(&models.ASRSegment{}, &models.ASRSegmentTranslation{}, &models.ASRChapter{})
 	if err != nil {
-		log.Printf("failed to migrate ASRSegment table: %v", err)
+		log.Printf("failed to migrate ASR tables: %v", err)
+	}
+
+	// Segments stored before confidence normalization hold the raw avg_logprob in confidence
+	if err := db.Exec(`UPDATE asr_segments SET avg_logprob = confidence, confidence = exp(confidence),
+		low_confidence = exp(confidence) < ? WHERE avg_logprob IS NULL AND confidence IS NOT NULL`,
+		config.LowConfidenceThreshold).Error; err != nil {
+		log.Printf("failed to normalize legacy ASR confidence: %v", err)
 	}
 
 	// Enable vector extension if needed
@@ -32,7 +32,7 @@ func (h *ASRHandler) ProcessVideo(c *gin.Context) {
 		return
 	}
 
-	response, err := h.asrService.ProcessVideo(&req)
+	response, err := h.asrService.ProcessVideo(c.Request.Context(), &req)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"success": false,
@@ -2,116 +2,208 @@ package grpc
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"strings"
 
+	"github.com/RigelNana/arkstudy/pkg/langdetect"
 	"github.com/RigelNana/arkstudy/proto/asr"
 	"github.com/RigelNana/arkstudy/services/asr-service/models"
 	"github.com/RigelNana/arkstudy/services/asr-service/service"
 	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 // ASRServer implements the ASR gRPC service
 type ASRServer struct {
 	asr.UnimplementedASRServiceServer
-	asrService *service.ASRService
+	asrService     *service.ASRService
+	materialClient *service.MaterialClient
+	momentIndex    *service.MomentIndex
 }
 
 // NewASRServer creates a new ASR gRPC server
-func NewASRServer(asrService *service.ASRService) *ASRServer {
+func NewASRServer(asrService *service.ASRService, materialClient *service.MaterialClient, momentIndex *service.MomentIndex) *ASRServer {
 	return &ASRServer{
-		asrService: asrService,
+		asrService:     asrService,
+		materialClient: materialClient,
+		momentIndex:    momentIndex,
 	}
 }
 
 // ProcessVideo handles video processing for ASR
 func (s *ASRServer) ProcessVideo(ctx context.Context, req *asr.ProcessVideoRequest) (*asr.ProcessVideoResponse, error) {
-	log.Printf("Processing video for material ID: %d", req.MaterialId)
+	log.Printf("Processing video for material ID: %s", req.MaterialId)
+
+	userID, err := uuid.Parse(req.UserId)
+	if err != nil {
+		return &asr.ProcessVideoResponse{
+			Success: false,
+			Message: "invalid user_id",
+		}, nil
+	}
+
+	// Verify the material exists in material-service and belongs to the user
+	info, err := s.materialClient.VerifyMaterial(ctx, req.MaterialId, req.UserId)
+	if err != nil {
+		log.Printf("Material verification failed: %v", err)
+		return &asr.ProcessVideoResponse{
+			Success: false,
+			Message: err.Error(),
+		}, nil
+	}
+
+	// Reject recordings over the audio cap before downloading anything; a task
+	// re-dispatched by material-service is marked failed with the same message
+	if err := s.asrService.CheckDuration(info.GetMedia().GetDurationSeconds()); err != nil {
+		log.Printf("ASR for material %s rejected: %v", req.MaterialId, err)
+		s.reportResult(req.TaskId, nil, err)
+		return &asr.ProcessVideoResponse{
+			Success: false,
+			Message: err.Error(),
+			TaskId:  req.TaskId,
+		}, nil
+	}
+
+	taskID, err := s.startTask(ctx, req)
+	if errors.Is(err, service.ErrTaskScheduled) {
+		// Accepted, transcription runs when material-service re-dispatches the task
+		return &asr.ProcessVideoResponse{
+			Success: true,
+			Message: err.Error(),
+			TaskId:  taskID,
+		}, nil
+	}
+	if err != nil {
+		return &asr.ProcessVideoResponse{
+			Success: false,
+			Message: err.Error(),
+		}, nil
+	}
 
 	// Create ASR request
 	asrReq := &models.ASRRequest{
-		MaterialID: fmt.Sprintf("%d", req.MaterialId),
+		MaterialID: req.MaterialId,
 		VideoURL:   req.VideoUrl,
-		// Note: UserID is not provided in the proto, need to add it or use a default
-		UserID: uuid.New(), // Using a new UUID for now
+		UserID:     userID,
+		OnProgress: s.progressReporter(taskID),
 	}
 
-	response, err := s.asrService.ProcessVideo(asrReq)
+	response, err := s.asrService.ProcessVideo(ctx, asrReq)
+	s.reportResult(taskID, response, err)
+	if err == nil && response.Success {
+		s.indexSegmentsAsync(req.MaterialId, req.UserId, response.Segments)
+	}
 	if err != nil {
 		log.Printf("Error processing video: %v", err)
 		return &asr.ProcessVideoResponse{
 			Success: false,
 			Message: err.Error(),
+			TaskId:  taskID,
 		}, nil
 	}
 
-	// Convert segments to proto format
-	protoSegments := make([]*asr.ASRSegment, len(response.Segments))
-	for i, segment := range response.Segments {
-		confidence := float32(0.0)
-		if segment.Confidence != nil {
-			confidence = float32(*segment.Confidence)
+	return &asr.ProcessVideoResponse{
+		Success:  response.Success,
+		Message:  response.Message,
+		Segments: toProtoSegments(response.Segments),
+		TaskId:   taskID,
+	}, nil
+}
+
+// startTask resolves the material-service task for this request, registering one
+// when the caller did not supply it, and marks it as processing.
+// Failures are logged and do not block transcription, except when material-service
+// rejects the task because the user has reached the per-user processing limit, or
+// defers it to an off-peak window (the task ID is returned with ErrTaskScheduled).
+func (s *ASRServer) startTask(ctx context.Context, req *asr.ProcessVideoRequest) (string, error) {
+	taskID := req.TaskId
+	if taskID == "" {
+		var err error
+		taskID, err = s.materialClient.StartTask(ctx, req.MaterialId, req.UserId)
+		if errors.Is(err, service.ErrTaskScheduled) {
+			log.Printf("ASR task %s for material %s deferred: %v", taskID, req.MaterialId, err)
+			return taskID, err
+		}
+		if errors.Is(err, service.ErrProcessingLimit) {
+			log.Printf("ASR task for material %s rejected: %v", req.MaterialId, err)
+			return "", err
+		}
+		if err != nil {
+			log.Printf("Failed to register ASR task for material %s: %v", req.MaterialId, err)
+			return "", nil
 		}
+	}
 
-		protoSegments[i] = &asr.ASRSegment{
-			Id:               uint64(i + 1), // Use index as ID since UUID can't convert to uint64
-			MaterialId:       req.MaterialId,
-			StartTime:        float32(segment.StartTime),
-			EndTime:          float32(segment.EndTime),
-			Text:             segment.Text,
-			Confidence:       confidence,
-			EmbeddingVector:  "", // Convert from pq.Float64Array if needed
-			CreatedAt:        segment.CreatedAt.String(),
-			UpdatedAt:        segment.UpdatedAt.String(),
+	if err := s.materialClient.MarkProcessing(ctx, taskID); err != nil {
+		log.Printf("Failed to mark ASR task %s as processing: %v", taskID, err)
+	}
+	return taskID, nil
+}
+
+// progressReporter forwards stage progress to material-service; failures are only logged
+func (s *ASRServer) progressReporter(taskID string) func(float32) {
+	if taskID == "" {
+		return nil
+	}
+	return func(progress float32) {
+		if err := s.materialClient.ReportProgress(context.Background(), taskID, progress); err != nil {
+			log.Printf("Failed to report ASR progress for task %s: %v", taskID, err)
 		}
 	}
+}
 
-	return &asr.ProcessVideoResponse{
-		Success:  response.Success,
-		Message:  response.Message,
-		Segments: protoSegments,
-	}, nil
+// reportResult sends the completion callback to material-service.
+// A fresh context is used so the status is still recorded if the caller has gone away.
+func (s *ASRServer) reportResult(taskID string, response *models.ASRResponse, procErr error) {
+	if taskID == "" {
+		return
+	}
+	if err := s.materialClient.ReportResult(context.Background(), taskID, response, procErr); err != nil {
+		log.Printf("Failed to report ASR result for task %s: %v", taskID, err)
+		return
+	}
+	log.Printf("Reported ASR result for task %s", taskID)
+}
+
+// indexSegmentsAsync embeds the new transcript for moment search; failures are only logged
+func (s *ASRServer) indexSegmentsAsync(materialID, userID string, segments []models.ASRSegment) {
+	go func() {
+		inserted, err := s.momentIndex.IndexSegments(context.Background(), materialID, userID, segments)
+		if err != nil {
+			log.Printf("Failed to index transcript of material %s: %v", materialID, err)
+			return
+		}
+		log.Printf("Indexed %d transcript chunks of material %s", inserted, materialID)
+	}()
 }
 
 // GetSegments retrieves ASR segments for a material
 func (s *ASRServer) GetSegments(ctx context.Context, req *asr.GetSegmentsRequest) (*asr.GetSegmentsResponse, error) {
-	log.Printf("Getting segments for material ID: %d", req.MaterialId)
+	log.Printf("Getting segments for material ID: %s", req.MaterialId)
 
-	materialIDStr := fmt.Sprintf("%d", req.MaterialId)
-	segments, err := s.asrService.GetSegmentsByMaterialID(materialIDStr)
-	if err != nil {
-		log.Printf("Error getting segments: %v", err)
+	if _, err := s.materialClient.VerifyMaterial(ctx, req.MaterialId, req.UserId); err != nil {
+		log.Printf("Material verification failed: %v", err)
 		return &asr.GetSegmentsResponse{
 			Success: false,
 			Message: err.Error(),
 		}, nil
 	}
 
-	// Convert segments to proto format
-	protoSegments := make([]*asr.ASRSegment, len(segments))
-	for i, segment := range segments {
-		confidence := float32(0.0)
-		if segment.Confidence != nil {
-			confidence = float32(*segment.Confidence)
-		}
-
-		protoSegments[i] = &asr.ASRSegment{
-			Id:               uint64(i + 1), // Use index as ID since UUID can't convert to uint64
-			MaterialId:       req.MaterialId,
-			StartTime:        float32(segment.StartTime),
-			EndTime:          float32(segment.EndTime),
-			Text:             segment.Text,
-			Confidence:       confidence,
-			EmbeddingVector:  "", // Convert from pq.Float64Array if needed
-			CreatedAt:        segment.CreatedAt.String(),
-			UpdatedAt:        segment.UpdatedAt.String(),
-		}
+	segments, err := s.asrService.GetSegmentsByMaterialID(req.MaterialId)
+	if err != nil {
+		log.Printf("Error getting segments: %v", err)
+		return &asr.GetSegmentsResponse{
+			Success: false,
+			Message: err.Error(),
+		}, nil
 	}
 
 	return &asr.GetSegmentsResponse{
 		Success:  true,
 		Message:  "Segments retrieved successfully",
-		Segments: protoSegments,
+		Segments: toProtoSegments(segments),
 	}, nil
 }
 
@@ -119,15 +211,30 @@ func (s *ASRServer) GetSegments(ctx context.Context, req *asr.GetSegmentsRequest
 func (s *ASRServer) SearchSegments(ctx context.Context, req *asr.SearchSegmentsRequest) (*asr.SearchSegmentsResponse, error) {
 	log.Printf("Searching segments with query: %s", req.Query)
 
+	userID, err := uuid.Parse(req.UserId)
+	if err != nil {
+		return &asr.SearchSegmentsResponse{
+			Success: false,
+			Message: "invalid user_id",
+		}, nil
+	}
+
 	// Create search request
 	searchReq := &models.SearchASRRequest{
 		Query:  req.Query,
-		UserID: uuid.New(), // Using a new UUID for now, should come from context
-		TopK:   10,         // Default value
+		UserID: userID,
+		TopK:   10, // Default value
 	}
 
-	if req.MaterialId > 0 {
-		searchReq.MaterialID = fmt.Sprintf("%d", req.MaterialId)
+	if req.MaterialId != "" {
+		if _, err := s.materialClient.VerifyMaterial(ctx, req.MaterialId, req.UserId); err != nil {
+			log.Printf("Material verification failed: %v", err)
+			return &asr.SearchSegmentsResponse{
+				Success: false,
+				Message: err.Error(),
+			}, nil
+		}
+		searchReq.MaterialID = req.MaterialId
 	}
 
 	if req.Limit > 0 {
@@ -143,32 +250,277 @@ func (s *ASRServer) SearchSegments(ctx context.Context, req *asr.SearchSegmentsR
 		}, nil
 	}
 
-	// Convert segments to proto format
-	protoSegments := make([]*asr.ASRSegment, len(response.Results))
+	segments := make([]models.ASRSegment, len(response.Results))
 	for i, result := range response.Results {
-		segment := result.Segment
-		confidence := float32(0.0)
-		if segment.Confidence != nil {
-			confidence = float32(*segment.Confidence)
-		}
-
-		protoSegments[i] = &asr.ASRSegment{
-			Id:               uint64(i + 1), // Use index as ID since UUID can't convert to uint64
-			MaterialId:       req.MaterialId,
-			StartTime:        float32(segment.StartTime),
-			EndTime:          float32(segment.EndTime),
-			Text:             segment.Text,
-			Confidence:       confidence,
-			EmbeddingVector:  "", // Convert from pq.Float64Array if needed
-			CreatedAt:        segment.CreatedAt.String(),
-			UpdatedAt:        segment.UpdatedAt.String(),
-		}
+		segments[i] = result.Segment
 	}
 
 	return &asr.SearchSegmentsResponse{
 		Success:  response.Success,
 		Message:  response.Message,
-		Segments: protoSegments,
+		Segments: toProtoSegments(segments),
+	}, nil
+}
+
+// SearchMoments searches transcripts of every video in a collection (and/or the given materials)
+// and returns the best matching time ranges across all of them
+func (s *ASRServer) SearchMoments(ctx context.Context, req *asr.SearchMomentsRequest) (*asr.SearchMomentsResponse, error) {
+	log.Printf("Searching moments with query: %s, collection ID: %s", req.Query, req.CollectionId)
+
+	if strings.TrimSpace(req.Query) == "" {
+		return &asr.SearchMomentsResponse{
+			Success: false,
+			Message: "query is required",
+		}, nil
+	}
+	if req.CollectionId == "" && len(req.MaterialIds) == 0 {
+		return &asr.SearchMomentsResponse{
+			Success: false,
+			Message: "collection_id or material_ids is required",
+		}, nil
+	}
+
+	// Resolve the scope to verified materials, keeping titles for the results
+	titles := map[string]string{}
+	var materialIDs []string
+	addMaterial := func(id, title string) {
+		if _, ok := titles[id]; !ok {
+			materialIDs = append(materialIDs, id)
+		}
+		titles[id] = title
+	}
+	if req.CollectionId != "" {
+		media, err := s.materialClient.ListCollectionMedia(ctx, req.CollectionId, req.UserId)
+		if err != nil {
+			log.Printf("Collection lookup failed: %v", err)
+			return &asr.SearchMomentsResponse{
+				Success: false,
+				Message: err.Error(),
+			}, nil
+		}
+		for _, m := range media {
+			addMaterial(m.Id, m.Title)
+		}
+	}
+	for _, id := range req.MaterialIds {
+		m, err := s.materialClient.VerifyMaterial(ctx, id, req.UserId)
+		if err != nil {
+			log.Printf("Material verification failed: %v", err)
+			return &asr.SearchMomentsResponse{
+				Success: false,
+				Message: err.Error(),
+			}, nil
+		}
+		addMaterial(m.Id, m.Title)
+	}
+	if len(materialIDs) == 0 {
+		return &asr.SearchMomentsResponse{
+			Success: true,
+			Message: "Collection has no audio or video materials",
+		}, nil
+	}
+
+	limit := int(req.Limit)
+	if limit <= 0 {
+		limit = 10
+	}
+	perMaterial := int(req.PerMaterialLimit)
+	if perMaterial == 0 {
+		perMaterial = 3
+	}
+
+	moments, err := s.momentIndex.Search(ctx, req.Query, req.UserId, materialIDs, limit, perMaterial)
+	if err != nil {
+		log.Printf("Error searching moments: %v", err)
+		return &asr.SearchMomentsResponse{
+			Success: false,
+			Message: err.Error(),
+		}, nil
+	}
+
+	resp := &asr.SearchMomentsResponse{
+		Success: true,
+		Message: fmt.Sprintf("Found %d moments in %d materials", len(moments), len(materialIDs)),
+	}
+	for _, m := range moments {
+		resp.Moments = append(resp.Moments, &asr.VideoMoment{
+			MaterialId:    m.MaterialID,
+			MaterialTitle: titles[m.MaterialID],
+			StartTime:     float32(m.StartTime),
+			EndTime:       float32(m.EndTime),
+			Text:          m.Text,
+			Score:         m.Score,
+			SegmentId:     m.SegmentID,
+		})
+	}
+	return resp, nil
+}
+
+// TranslateTranscript translates a material's transcript into the requested language
+func (s *ASRServer) TranslateTranscript(ctx context.Context, req *asr.TranslateTranscriptRequest) (*asr.TranslateTranscriptResponse, error) {
+	log.Printf("Translating transcript for material ID: %s into %s", req.MaterialId, req.TargetLanguage)
+
+	if req.TargetLanguage == "" {
+		return &asr.TranslateTranscriptResponse{
+			Success: false,
+			Message: "target_language is required",
+		}, nil
+	}
+	if !langdetect.ValidTag(req.TargetLanguage) {
+		return &asr.TranslateTranscriptResponse{
+			Success: false,
+			Message: "target_language must be a language tag such as en or zh-CN",
+		}, nil
+	}
+
+	if _, err := s.materialClient.VerifyMaterial(ctx, req.MaterialId, req.UserId); err != nil {
+		log.Printf("Material verification failed: %v", err)
+		return &asr.TranslateTranscriptResponse{
+			Success: false,
+			Message: err.Error(),
+		}, nil
+	}
+
+	response, err := s.asrService.TranslateTranscript(ctx, &models.TranslateTranscriptRequest{
+		MaterialID:     req.MaterialId,
+		TargetLanguage: req.TargetLanguage,
+		Force:          req.Force,
+	})
+	if err != nil {
+		log.Printf("Error translating transcript: %v", err)
+		return &asr.TranslateTranscriptResponse{
+			Success: false,
+			Message: err.Error(),
+		}, nil
+	}
+
+	segments := make([]models.ASRSegment, len(response.Segments))
+	for i, translated := range response.Segments {
+		segments[i] = translated.Segment
+	}
+	protoSegments := toProtoSegments(segments)
+
+	translatedSegments := make([]*asr.TranslatedSegment, len(response.Segments))
+	for i, translated := range response.Segments {
+		translatedSegments[i] = &asr.TranslatedSegment{
+			Segment:        protoSegments[i],
+			TranslatedText: translated.TranslatedText,
+		}
+	}
+
+	return &asr.TranslateTranscriptResponse{
+		Success:         true,
+		Message:         "Transcript translated successfully",
+		TargetLanguage:  response.TargetLanguage,
+		Segments:        translatedSegments,
+		TranslatedCount: int32(response.TranslatedCount),
+	}, nil
+}
+
+// GetChapters returns the chapter outline of a material, detecting it on demand
+func (s *ASRServer) GetChapters(ctx context.Context, req *asr.GetChaptersRequest) (*asr.GetChaptersResponse, error) {
+	log.Printf("Getting chapters for material ID: %s", req.MaterialId)
+
+	if _, err := s.materialClient.VerifyMaterial(ctx, req.MaterialId, req.UserId); err != nil {
+		log.Printf("Material verification failed: %v", err)
+		return &asr.GetChaptersResponse{
+			Success: false,
+			Message: err.Error(),
+		}, nil
+	}
+
+	chapters, err := s.asrService.GetChapters(req.MaterialId)
+	if err == nil && (req.Regenerate || len(chapters) == 0) {
+		chapters, err = s.asrService.DetectChapters(ctx, req.MaterialId)
+	}
+	if err != nil {
+		log.Printf("Error getting chapters: %v", err)
+		return &asr.GetChaptersResponse{
+			Success: false,
+			Message: err.Error(),
+		}, nil
+	}
+
+	protoChapters := make([]*asr.ASRChapter, len(chapters))
+	for i, chapter := range chapters {
+		protoChapters[i] = &asr.ASRChapter{
+			Id:           chapter.ID.String(),
+			MaterialId:   chapter.MaterialID,
+			ChapterIndex: int32(chapter.ChapterIndex),
+			Title:        chapter.Title,
+			Summary:      chapter.Summary,
+			StartTime:    float32(chapter.StartTime),
+			EndTime:      float32(chapter.EndTime),
+			StartSegment: int32(chapter.StartSegment),
+			EndSegment:   int32(chapter.EndSegment),
+		}
+	}
+
+	return &asr.GetChaptersResponse{
+		Success:  true,
+		Message:  "Chapters retrieved successfully",
+		Chapters: protoChapters,
+	}, nil
+}
+
+// RetranscribeSegments re-transcribes low-confidence (or the given) segments with a larger model
+func (s *ASRServer) RetranscribeSegments(ctx context.Context, req *asr.RetranscribeSegmentsRequest) (*asr.RetranscribeSegmentsResponse, error) {
+	log.Printf("Re-transcribing segments for material ID: %s", req.MaterialId)
+
+	segmentIDs := make([]uuid.UUID, 0, len(req.SegmentIds))
+	for _, id := range req.SegmentIds {
+		segmentID, err := uuid.Parse(id)
+		if err != nil {
+			return &asr.RetranscribeSegmentsResponse{
+				Success: false,
+				Message: "invalid segment_id: " + id,
+			}, nil
+		}
+		segmentIDs = append(segmentIDs, segmentID)
+	}
+
+	if _, err := s.materialClient.VerifyMaterial(ctx, req.MaterialId, req.UserId); err != nil {
+		log.Printf("Material verification failed: %v", err)
+		return &asr.RetranscribeSegmentsResponse{
+			Success: false,
+			Message: err.Error(),
+		}, nil
+	}
+
+	response, err := s.asrService.RetranscribeSegments(ctx, &models.RetranscribeRequest{
+		MaterialID: req.MaterialId,
+		VideoURL:   req.VideoUrl,
+		SegmentIDs: segmentIDs,
+		Model:      req.Model,
+	})
+	if err != nil {
+		log.Printf("Error re-transcribing segments: %v", err)
+		return &asr.RetranscribeSegmentsResponse{
+			Success: false,
+			Message: err.Error(),
+		}, nil
+	}
+
+	// The moment index holds the old text, rebuild it from the full transcript
+	if response.ImprovedCount > 0 {
+		if segments, err := s.asrService.GetSegmentsByMaterialID(req.MaterialId); err != nil {
+			log.Printf("Failed to reload segments of material %s for indexing: %v", req.MaterialId, err)
+		} else {
+			s.indexSegmentsAsync(req.MaterialId, req.UserId, segments)
+		}
+	}
+
+	message := fmt.Sprintf("Re-transcribed %d segments, %d improved", response.RequestedCount, response.ImprovedCount)
+	if response.RequestedCount == 0 {
+		message = "No low-confidence segments to re-transcribe"
+	}
+	return &asr.RetranscribeSegmentsResponse{
+		Success:        true,
+		Message:        message,
+		Model:          response.Model,
+		Segments:       toProtoSegments(response.Segments),
+		RequestedCount: int32(response.RequestedCount),
+		ImprovedCount:  int32(response.ImprovedCount),
 	}, nil
 }
 
@@ -178,4 +530,30 @@ func (s *ASRServer) HealthCheck(ctx context.Context, req *asr.HealthCheckRequest
 		Status:  "healthy",
 		Message: "ASR service is running",
 	}, nil
-}
\ No newline at end of file
+}
+
+// toProtoSegments converts stored segments to proto format
+func toProtoSegments(segments []models.ASRSegment) []*asr.ASRSegment {
+	protoSegments := make([]*asr.ASRSegment, len(segments))
+	for i, segment := range segments {
+		confidence := float32(0.0)
+		if segment.Confidence != nil {
+			confidence = float32(*segment.Confidence)
+		}
+
+		protoSegments[i] = &asr.ASRSegment{
+			Id:              segment.ID.String(),
+			MaterialId:      segment.MaterialID,
+			StartTime:       float32(segment.StartTime),
+			EndTime:         float32(segment.EndTime),
+			Text:            segment.Text,
+			Confidence:      confidence,
+			EmbeddingVector: "", // Convert from pq.Float64Array if needed
+			CreatedAt:       timestamppb.New(segment.CreatedAt),
+			UpdatedAt:       timestamppb.New(segment.UpdatedAt),
+			LowConfidence:   segment.LowConfidence,
+			Model:           segment.Model,
+		}
+	}
+	return protoSegments
+}
@@ -4,8 +4,12 @@ import (
 	"log"
 	"net"
 
+	grpcDbx "github.com/RigelNana/arkstudy/pkg/dbx/grpc"
+	"github.com/RigelNana/arkstudy/pkg/discovery"
+	"github.com/RigelNana/arkstudy/pkg/interceptor"
 	"github.com/RigelNana/arkstudy/pkg/metrics"
 	grpcMetrics "github.com/RigelNana/arkstudy/pkg/metrics/grpc"
+	"github.com/RigelNana/arkstudy/pkg/registry"
 	"github.com/RigelNana/arkstudy/proto/asr"
 	"github.com/RigelNana/arkstudy/services/asr-service/config"
 	"github.com/RigelNana/arkstudy/services/asr-service/database"
@@ -16,32 +20,56 @@ import (
 )
 
 func main() {
-	// 启动 Prometheus metrics 服务器
-	metrics.StartMetricsServer("2112")
-	log.Printf("Prometheus metrics server started on :2112")
+	// 启动内部 HTTP 监听：/metrics 与 /healthz、/readyz，地址由 METRICS_ADDR 配置
+	log.Printf("Prometheus metrics server started on %s", metrics.StartMetricsServer())
 
 	// Load configuration
 	cfg := config.LoadConfig()
 
 	// Initialize database
 	db := database.InitDB()
+	if sqlDB, err := db.DB(); err == nil {
+		metrics.AddReadinessCheck("database", sqlDB.PingContext)
+	}
 	defer func() {
 		sqlDB, _ := db.DB()
 		sqlDB.Close()
 	}()
 
+	if err := registry.Validate(registry.ASR, registry.Material, registry.LLM); err != nil {
+		log.Fatalf("%v", err)
+	}
+
 	// Initialize ASR service
 	asrService := service.NewASRService(cfg)
 
 	// Create gRPC server
+	// panic 恢复、错误码规范化与慢请求日志（GRPC_SLOW_THRESHOLD）
+	interceptorOpts := interceptor.DefaultOptions("asr-service")
 	s := grpc.NewServer(
-		grpc.UnaryInterceptor(grpcMetrics.UnaryServerInterceptor("asr-service")),
-		grpc.StreamInterceptor(grpcMetrics.StreamServerInterceptor("asr-service")),
+		grpc.ChainUnaryInterceptor(grpcMetrics.UnaryServerInterceptor("asr-service"), interceptor.UnaryServerInterceptor(interceptorOpts), grpcDbx.UnaryServerInterceptor()),
+		grpc.ChainStreamInterceptor(grpcMetrics.StreamServerInterceptor("asr-service"), interceptor.StreamServerInterceptor(interceptorOpts)),
 	)
 
+	// Initialize material-service client for material reference checks
+	materialClient, err := service.NewMaterialClient(cfg.MaterialServiceAddr)
+	if err != nil {
+		log.Fatalf("Failed to create material client: %v", err)
+	}
+	defer materialClient.Close()
+
+	// Initialize llm-service client that indexes and searches transcript moments
+	momentIndex, err := service.NewMomentIndex(cfg.LLMServiceAddr)
+	if err != nil {
+		log.Fatalf("Failed to create moment index client: %v", err)
+	}
+	defer momentIndex.Close()
+
 	// Register ASR service
-	asrServer := grpcHandler.NewASRServer(asrService)
+	asrServer := grpcHandler.NewASRServer(asrService, materialClient, momentIndex)
 	asr.RegisterASRServiceServer(s, asrServer)
+	// 健康检查供客户端在多个副本间故障转移（见 pkg/discovery）
+	discovery.RegisterHealth(s)
 
 	// Listen on the configured port
 	lis, err := net.Listen("tcp", ":"+cfg.GRPCPort)
@@ -50,6 +78,7 @@ func main() {
 	}
 
 	log.Printf("ASR gRPC service starting on port %s", cfg.GRPCPort)
+	metrics.SetReady(true)
 	if err := s.Serve(lis); err != nil {
 		log.Fatalf("Failed to serve: %v", err)
 	}
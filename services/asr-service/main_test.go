@@ -26,7 +26,8 @@ func TestASRServiceIntegration(t *testing.T) {
 	// 准备一个测试请求 (这里使用一个虚拟的视频 URL)
 	// 在实际测试中，您可能需要上传一个文件到 MinIO 并获取其 URL
 	req := &asr.ProcessVideoRequest{
-		MaterialId: 1, // 使用一个示例 uint64 ID
+		MaterialId: "00000000-0000-0000-0000-000000000001", // 使用一个示例材料 UUID
+		UserId:     "00000000-0000-0000-0000-000000000001",
 		VideoUrl:   "https://example.com/video.mp4",
 	}
 
@@ -8,15 +8,18 @@ import (
 // ASRSegment represents a single transcribed segment from audio/video
 type ASRSegment struct {
 	Base
-	MaterialID   string          `gorm:"type:varchar(255);not null;index" json:"material_id"`
-	UserID       uuid.UUID       `gorm:"type:uuid;not null;index" json:"user_id"`
-	SegmentIndex int             `gorm:"not null" json:"segment_index"`
-	StartTime    float64         `gorm:"not null" json:"start_time"`
-	EndTime      float64         `gorm:"not null" json:"end_time"`
-	Text         string          `gorm:"type:text;not null" json:"text"`
-	Confidence   *float64        `gorm:"type:float" json:"confidence,omitempty"`
-	Embedding    pq.Float64Array `gorm:"type:float[]" json:"embedding,omitempty"`
-	Language     *string         `gorm:"type:varchar(10)" json:"language,omitempty"`
+	MaterialID    string          `gorm:"type:varchar(255);not null;index" json:"material_id"`
+	UserID        uuid.UUID       `gorm:"type:uuid;not null;index" json:"user_id"`
+	SegmentIndex  int             `gorm:"not null" json:"segment_index"`
+	StartTime     float64         `gorm:"not null" json:"start_time"`
+	EndTime       float64         `gorm:"not null" json:"end_time"`
+	Text          string          `gorm:"type:text;not null" json:"text"`
+	Confidence    *float64        `gorm:"type:float" json:"confidence,omitempty"`             // normalized 0-1, see segmentConfidence
+	AvgLogprob    *float64        `gorm:"type:float" json:"avg_logprob,omitempty"`            // raw Whisper avg_logprob
+	LowConfidence bool            `gorm:"not null;default:false;index" json:"low_confidence"` // worth re-transcribing with a larger model
+	Model         string          `gorm:"type:varchar(100)" json:"model,omitempty"`           // transcription model that produced Text
+	Embedding     pq.Float64Array `gorm:"type:float[]" json:"embedding,omitempty"`
+	Language      *string         `gorm:"type:varchar(10)" json:"language,omitempty"`
 }
 
 // TableName sets the table name for ASRSegment
@@ -24,12 +27,39 @@ func (ASRSegment) TableName() string {
 	return "asr_segments"
 }
 
+// RetranscribeRequest represents a request to re-transcribe segments of a material
+type RetranscribeRequest struct {
+	MaterialID string      `json:"material_id"`
+	VideoURL   string      `json:"video_url"`
+	SegmentIDs []uuid.UUID `json:"segment_ids,omitempty"` // defaults to the low-confidence segments
+	Model      string      `json:"model,omitempty"`       // defaults to RetranscribeModel
+}
+
+// RetranscribeResponse represents the result of a re-transcription
+type RetranscribeResponse struct {
+	MaterialID     string       `json:"material_id"`
+	Model          string       `json:"model"`
+	Segments       []ASRSegment `json:"segments"`        // the requested segments after re-transcription
+	RequestedCount int          `json:"requested_count"` // segments that were re-transcribed
+	ImprovedCount  int          `json:"improved_count"`  // segments replaced by a more confident transcription
+}
+
 // ASRRequest represents the request to process video/audio
 type ASRRequest struct {
 	MaterialID string    `json:"material_id" binding:"required"`
 	UserID     uuid.UUID `json:"user_id" binding:"required"`
 	VideoURL   string    `json:"video_url" binding:"required"`
 	Language   string    `json:"language,omitempty"` // Optional language hint
+
+	// OnProgress, when set, is called as the job moves through its stages (0-1)
+	OnProgress func(progress float32) `json:"-"`
+}
+
+// ReportProgress invokes OnProgress if one is registered
+func (r *ASRRequest) ReportProgress(progress float32) {
+	if r.OnProgress != nil {
+		r.OnProgress(progress)
+	}
 }
 
 // ASRResponse represents the response from ASR processing
@@ -0,0 +1,19 @@
+package models
+
+// ASRChapter is a topical section of a transcript, used for lecture outlines and video navigation
+type ASRChapter struct {
+	Base
+	MaterialID   string  `gorm:"type:varchar(255);not null;index" json:"material_id"`
+	ChapterIndex int     `gorm:"not null" json:"chapter_index"`
+	Title        string  `gorm:"type:varchar(255);not null" json:"title"`
+	Summary      string  `gorm:"type:text" json:"summary"`
+	StartTime    float64 `gorm:"not null" json:"start_time"`
+	EndTime      float64 `gorm:"not null" json:"end_time"`
+	StartSegment int     `gorm:"not null" json:"start_segment"` // segment_index of the first segment
+	EndSegment   int     `gorm:"not null" json:"end_segment"`   // segment_index of the last segment
+}
+
+// TableName sets the table name for ASRChapter
+func (ASRChapter) TableName() string {
+	return "asr_chapters"
+}
@@ -0,0 +1,39 @@
+package models
+
+import "github.com/google/uuid"
+
+// ASRSegmentTranslation stores a translated version of an ASR segment, kept
+// parallel to the original so clients can render bilingual subtitles
+type ASRSegmentTranslation struct {
+	Base
+	SegmentID  uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_segment_translation_lang" json:"segment_id"`
+	MaterialID string    `gorm:"type:varchar(255);not null;index" json:"material_id"`
+	Language   string    `gorm:"type:varchar(20);not null;uniqueIndex:idx_segment_translation_lang" json:"language"`
+	Text       string    `gorm:"type:text;not null" json:"text"`
+}
+
+// TableName sets the table name for ASRSegmentTranslation
+func (ASRSegmentTranslation) TableName() string {
+	return "asr_segment_translations"
+}
+
+// TranslatedSegment pairs an original segment with its translation
+type TranslatedSegment struct {
+	Segment        ASRSegment `json:"segment"`
+	TranslatedText string     `json:"translated_text"`
+}
+
+// TranslateTranscriptRequest represents a request to translate a material's transcript
+type TranslateTranscriptRequest struct {
+	MaterialID     string `json:"material_id"`
+	TargetLanguage string `json:"target_language"`
+	Force          bool   `json:"force"` // re-translate segments that already have a translation
+}
+
+// TranslateTranscriptResponse represents the bilingual transcript
+type TranslateTranscriptResponse struct {
+	MaterialID      string              `json:"material_id"`
+	TargetLanguage  string              `json:"target_language"`
+	Segments        []TranslatedSegment `json:"segments"`
+	TranslatedCount int                 `json:"translated_count"` // segments translated in this call
+}
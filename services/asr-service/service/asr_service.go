@@ -1,14 +1,15 @@
 package service
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
 	"time"
 
+	"github.com/RigelNana/arkstudy/pkg/featureflags"
 	"github.com/RigelNana/arkstudy/services/asr-service/config"
 	"github.com/RigelNana/arkstudy/services/asr-service/database"
 	"github.com/RigelNana/arkstudy/services/asr-service/models"
@@ -20,6 +21,8 @@ import (
 type ASRService struct {
 	config       *config.Config
 	openAIClient *openai.Client
+	// jobsRoot holds this instance's job directories, see newJobsRoot
+	jobsRoot string
 }
 
 func NewASRService(cfg *config.Config) *ASRService {
@@ -31,14 +34,19 @@ func NewASRService(cfg *config.Config) *ASRService {
 
 	client := openai.NewClientWithConfig(clientConfig)
 
-	return &ASRService{
+	s := &ASRService{
 		config:       cfg,
 		openAIClient: client,
+		jobsRoot:     newJobsRoot(cfg.TempDir),
 	}
+	s.cleanupStaleJobDirs()
+	go s.runTempJanitor()
+
+	return s
 }
 
 // ProcessVideo processes a video file to extract audio and perform ASR
-func (s *ASRService) ProcessVideo(req *models.ASRRequest) (*models.ASRResponse, error) {
+func (s *ASRService) ProcessVideo(ctx context.Context, req *models.ASRRequest) (*models.ASRResponse, error) {
 	response := &models.ASRResponse{
 		MaterialID:  req.MaterialID,
 		UserID:      req.UserID,
@@ -46,31 +54,48 @@ func (s *ASRService) ProcessVideo(req *models.ASRRequest) (*models.ASRResponse,
 		Success:     false,
 	}
 
+	// Each job gets its own scratch directory, removed on every exit path
+	jobDir, cleanup, err := s.newJobDir()
+	if err != nil {
+		response.Message = "Failed to prepare job directory: " + err.Error()
+		return response, err
+	}
+	defer cleanup()
+
 	// Step 1: Download video file (simulate for now)
-	videoPath := filepath.Join(s.config.TempDir, fmt.Sprintf("%s_%s.mp4", req.MaterialID, uuid.New().String()[:8]))
+	videoPath := filepath.Join(jobDir, "input.mp4")
 	if err := s.downloadVideo(req.VideoURL, videoPath); err != nil {
 		response.Message = "Failed to download video: " + err.Error()
 		return response, err
 	}
-	defer os.Remove(videoPath)
+	req.ReportProgress(0.2)
 
 	// Step 2: Extract audio using ffmpeg
-	audioPath := strings.Replace(videoPath, ".mp4", "."+s.config.AudioFormat, 1)
-	if err := s.extractAudio(videoPath, audioPath); err != nil {
+	audioPath := filepath.Join(jobDir, "audio."+s.config.AudioFormat)
+	if err := s.extractAudio(ctx, videoPath, audioPath); err != nil {
 		response.Message = "Failed to extract audio: " + err.Error()
 		return response, err
 	}
-	defer os.Remove(audioPath)
+	// Check the actual audio length before anything is sent to Whisper
+	if seconds, ok := s.extractedAudioSeconds(audioPath); ok {
+		if err := s.checkAudioMinutes(seconds, "extracted"); err != nil {
+			response.Message = err.Error()
+			return response, err
+		}
+	}
+	req.ReportProgress(0.4)
 
 	// Step 3: Transcribe audio using Whisper
-	whisperResponse, err := s.transcribeAudio(audioPath, req.Language)
+	whisperResponse, err := s.transcribeAudio(ctx, audioPath, s.config.OpenAIModel, req.Language)
 	if err != nil {
 		response.Message = "Failed to transcribe audio: " + err.Error()
 		return response, err
 	}
+	s.recordAudioUsage(req.MaterialID, whisperResponse.Duration)
+	req.ReportProgress(0.9)
 
 	// Step 4: Process segments and store in database
-	segments, err := s.processAndStoreSegments(whisperResponse, req.MaterialID, req.UserID)
+	segments, err := s.processAndStoreSegments(whisperResponse, s.config.OpenAIModel, req.MaterialID, req.UserID)
 	if err != nil {
 		response.Message = "Failed to store segments: " + err.Error()
 		return response, err
@@ -82,16 +107,16 @@ func (s *ASRService) ProcessVideo(req *models.ASRRequest) (*models.ASRResponse,
 	response.Success = true
 	response.Message = "ASR processing completed successfully"
 
+	// Step 5: Post-process into chapters in the background
+	if featureflags.Enabled(featureflags.ChapterDetection, s.config.ChapterDetectionEnabled) {
+		s.detectChaptersAsync(req.MaterialID)
+	}
+
 	return response, nil
 }
 
 // downloadVideo downloads video from URL (placeholder implementation)
 func (s *ASRService) downloadVideo(videoURL, outputPath string) error {
-	// Create temp directory if it doesn't exist
-	if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
-		return fmt.Errorf("failed to create temp directory: %w", err)
-	}
-
 	// For now, just create a placeholder file
 	// In real implementation, you would download from videoURL
 	file, err := os.Create(outputPath)
@@ -105,18 +130,16 @@ func (s *ASRService) downloadVideo(videoURL, outputPath string) error {
 }
 
 // extractAudio extracts audio from video using ffmpeg
-func (s *ASRService) extractAudio(videoPath, audioPath string) error {
-	cmd := exec.Command(s.config.FFmpegBinaryPath,
+func (s *ASRService) extractAudio(ctx context.Context, videoPath, audioPath string) error {
+	err := s.runFFmpeg(ctx, audioPath,
 		"-i", videoPath,
 		"-vn",                  // no video
 		"-acodec", "pcm_s16le", // audio codec
 		"-ar", "16000", // sample rate
 		"-ac", "1", // mono
 		"-f", s.config.AudioFormat,
-		audioPath,
 	)
-
-	if err := cmd.Run(); err != nil {
+	if err != nil {
 		return fmt.Errorf("ffmpeg extraction failed: %w", err)
 	}
 
@@ -124,8 +147,8 @@ func (s *ASRService) extractAudio(videoPath, audioPath string) error {
 	return nil
 }
 
-// transcribeAudio transcribes audio using OpenAI Whisper
-func (s *ASRService) transcribeAudio(audioPath, language string) (*models.WhisperResponse, error) {
+// transcribeAudio transcribes audio using the given OpenAI Whisper model
+func (s *ASRService) transcribeAudio(ctx context.Context, audioPath, model, language string) (*models.WhisperResponse, error) {
 	audioFile, err := os.Open(audioPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open audio file: %w", err)
@@ -133,7 +156,7 @@ func (s *ASRService) transcribeAudio(audioPath, language string) (*models.Whispe
 	defer audioFile.Close()
 
 	req := openai.AudioRequest{
-		Model:    s.config.OpenAIModel,
+		Model:    model,
 		FilePath: audioPath,
 		Format:   openai.AudioResponseFormatVerboseJSON,
 	}
@@ -142,7 +165,7 @@ func (s *ASRService) transcribeAudio(audioPath, language string) (*models.Whispe
 		req.Language = language
 	}
 
-	resp, err := s.openAIClient.CreateTranscription(nil, req)
+	resp, err := s.openAIClient.CreateTranscription(ctx, req)
 	if err != nil {
 		return nil, fmt.Errorf("whisper transcription failed: %w", err)
 	}
@@ -176,19 +199,28 @@ func (s *ASRService) transcribeAudio(audioPath, language string) (*models.Whispe
 }
 
 // processAndStoreSegments processes Whisper segments and stores them in database
-func (s *ASRService) processAndStoreSegments(whisperResp *models.WhisperResponse, materialID string, userID uuid.UUID) ([]models.ASRSegment, error) {
+func (s *ASRService) processAndStoreSegments(whisperResp *models.WhisperResponse, model, materialID string, userID uuid.UUID) ([]models.ASRSegment, error) {
 	var segments []models.ASRSegment
+	lowCount := 0
 
 	for i, seg := range whisperResp.Segments {
+		score := s.scoreSegments([]models.WhisperSegment{seg})
+		avgLogprob := seg.AvgLogprob
 		asrSegment := models.ASRSegment{
-			MaterialID:   materialID,
-			UserID:       userID,
-			SegmentIndex: i,
-			StartTime:    seg.Start,
-			EndTime:      seg.End,
-			Text:         strings.TrimSpace(seg.Text),
-			Confidence:   &seg.AvgLogprob, // Use avg_logprob as confidence
-			Language:     &whisperResp.Language,
+			MaterialID:    materialID,
+			UserID:        userID,
+			SegmentIndex:  i,
+			StartTime:     seg.Start,
+			EndTime:       seg.End,
+			Text:          strings.TrimSpace(seg.Text),
+			Confidence:    &score.Confidence,
+			AvgLogprob:    &avgLogprob,
+			LowConfidence: score.Low,
+			Model:         model,
+			Language:      &whisperResp.Language,
+		}
+		if score.Low {
+			lowCount++
 		}
 
 		// TODO: Generate embeddings for semantic search
@@ -202,7 +234,7 @@ func (s *ASRService) processAndStoreSegments(whisperResp *models.WhisperResponse
 		return nil, fmt.Errorf("failed to store segments in database: %w", err)
 	}
 
-	log.Printf("Stored %d ASR segments in database", len(segments))
+	log.Printf("Stored %d ASR segments in database, %d low confidence", len(segments), lowCount)
 	return segments, nil
 }
 
@@ -0,0 +1,246 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/RigelNana/arkstudy/pkg/dbx"
+	"github.com/RigelNana/arkstudy/services/asr-service/database"
+	"github.com/RigelNana/arkstudy/services/asr-service/models"
+
+	"github.com/sashabaranov/go-openai"
+	"gorm.io/gorm"
+)
+
+const (
+	// maxChapterInputBlocks caps how many transcript blocks are sent to the LLM;
+	// longer transcripts are merged into consecutive blocks first
+	maxChapterInputBlocks = 300
+	// maxChapterBlockRunes truncates each block's text in the prompt
+	maxChapterBlockRunes = 160
+	// fallbackChapterSeconds is the chapter length used when the LLM is unavailable
+	fallbackChapterSeconds = 600
+)
+
+// chapterBlock is a run of consecutive segments presented to the LLM as one line
+type chapterBlock struct {
+	ID    int     `json:"id"`
+	Start float64 `json:"start"`
+	Text  string  `json:"text"`
+
+	firstSegment int
+}
+
+type chapterCandidate struct {
+	StartBlock int    `json:"start_block"`
+	Title      string `json:"title"`
+	Summary    string `json:"summary"`
+}
+
+type chapterResult struct {
+	Chapters []chapterCandidate `json:"chapters"`
+}
+
+// GetChapters returns the stored chapters of a material in order
+func (s *ASRService) GetChapters(materialID string) ([]models.ASRChapter, error) {
+	var chapters []models.ASRChapter
+	if err := database.DB.Where("material_id = ?", materialID).
+		Order("chapter_index ASC").
+		Find(&chapters).Error; err != nil {
+		return nil, fmt.Errorf("failed to retrieve chapters: %w", err)
+	}
+	return chapters, nil
+}
+
+// DetectChapters clusters a material's segments into topical chapters and replaces any stored ones.
+// The LLM proposes chapter boundaries; if that fails the transcript is split into fixed-length parts.
+func (s *ASRService) DetectChapters(ctx context.Context, materialID string) ([]models.ASRChapter, error) {
+	segments, err := s.GetSegmentsByMaterialID(materialID)
+	if err != nil {
+		return nil, err
+	}
+	if len(segments) == 0 {
+		return nil, ErrNoSegments
+	}
+
+	blocks := buildChapterBlocks(segments)
+	candidates, err := s.proposeChapters(ctx, blocks)
+	if err != nil {
+		log.Printf("LLM chapter detection failed for material %s, using fixed-length chapters: %v", materialID, err)
+		candidates = fallbackChapters(blocks)
+	}
+
+	chapters := assembleChapters(materialID, segments, blocks, candidates)
+
+	// Chapters are derived data and are rebuilt from scratch, so old rows are removed for good
+	err = database.DB.WithContext(dbx.AllowHardDelete(ctx)).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Where("material_id = ?", materialID).Delete(&models.ASRChapter{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(&chapters).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to store chapters: %w", err)
+	}
+
+	log.Printf("Detected %d chapters for material %s", len(chapters), materialID)
+	return chapters, nil
+}
+
+// detectChaptersAsync runs chapter detection as a post-processing step after transcription
+func (s *ASRService) detectChaptersAsync(materialID string) {
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+		defer cancel()
+
+		if _, err := s.DetectChapters(ctx, materialID); err != nil {
+			log.Printf("Chapter detection failed for material %s: %v", materialID, err)
+		}
+	}()
+}
+
+// proposeChapters asks the chat model for chapter boundaries over the given blocks
+func (s *ASRService) proposeChapters(ctx context.Context, blocks []chapterBlock) ([]chapterCandidate, error) {
+	if s.config.OpenAIAPIKey == "" {
+		return nil, fmt.Errorf("OpenAI API key not configured")
+	}
+
+	payload, err := json.Marshal(map[string]interface{}{"blocks": blocks})
+	if err != nil {
+		return nil, err
+	}
+
+	systemPrompt := "You segment lecture transcripts into topical chapters for video navigation. " +
+		"The input is a list of transcript blocks with an id, a start time in seconds and text. " +
+		"Group consecutive blocks into chapters where the topic changes, typically 3 to 12 chapters. " +
+		"Write titles and one-sentence summaries in the transcript's language. " +
+		`Reply with JSON of the form {"chapters":[{"start_block":0,"title":"...","summary":"..."}]} ordered by start_block, the first starting at 0.`
+
+	resp, err := s.openAIClient.CreateChatCompletion(ctx, openai.ChatCompletionRequest{
+		Model:       s.config.ChatModel,
+		Temperature: 0.2,
+		ResponseFormat: &openai.ChatCompletionResponseFormat{
+			Type: openai.ChatCompletionResponseFormatTypeJSONObject,
+		},
+		Messages: []openai.ChatCompletionMessage{
+			{Role: openai.ChatMessageRoleSystem, Content: systemPrompt},
+			{Role: openai.ChatMessageRoleUser, Content: string(payload)},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("chat completion failed: %w", err)
+	}
+	if len(resp.Choices) == 0 {
+		return nil, fmt.Errorf("chat completion returned no choices")
+	}
+
+	var result chapterResult
+	if err := json.Unmarshal([]byte(resp.Choices[0].Message.Content), &result); err != nil {
+		return nil, fmt.Errorf("failed to parse chapter response: %w", err)
+	}
+
+	// Drop out-of-range or duplicate boundaries and make sure the first chapter starts at block 0
+	seen := make(map[int]bool)
+	var candidates []chapterCandidate
+	for _, c := range result.Chapters {
+		if c.StartBlock < 0 || c.StartBlock >= len(blocks) || seen[c.StartBlock] {
+			continue
+		}
+		c.Title = strings.TrimSpace(c.Title)
+		if c.Title == "" {
+			continue
+		}
+		seen[c.StartBlock] = true
+		candidates = append(candidates, c)
+	}
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("chat completion returned no usable chapters")
+	}
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].StartBlock < candidates[j].StartBlock })
+	candidates[0].StartBlock = 0
+
+	return candidates, nil
+}
+
+// buildChapterBlocks merges segments into at most maxChapterInputBlocks blocks
+func buildChapterBlocks(segments []models.ASRSegment) []chapterBlock {
+	perBlock := (len(segments) + maxChapterInputBlocks - 1) / maxChapterInputBlocks
+	if perBlock < 1 {
+		perBlock = 1
+	}
+
+	var blocks []chapterBlock
+	for start := 0; start < len(segments); start += perBlock {
+		end := start + perBlock
+		if end > len(segments) {
+			end = len(segments)
+		}
+
+		texts := make([]string, 0, end-start)
+		for _, segment := range segments[start:end] {
+			texts = append(texts, strings.TrimSpace(segment.Text))
+		}
+
+		blocks = append(blocks, chapterBlock{
+			ID:           len(blocks),
+			Start:        segments[start].StartTime,
+			Text:         truncateRunes(strings.Join(texts, " "), maxChapterBlockRunes),
+			firstSegment: start,
+		})
+	}
+	return blocks
+}
+
+// fallbackChapters splits blocks into fixed-length chapters titled after their opening text
+func fallbackChapters(blocks []chapterBlock) []chapterCandidate {
+	var candidates []chapterCandidate
+	nextStart := 0.0
+	for _, block := range blocks {
+		if len(candidates) > 0 && block.Start < nextStart {
+			continue
+		}
+		candidates = append(candidates, chapterCandidate{
+			StartBlock: block.ID,
+			Title:      truncateRunes(block.Text, 30),
+		})
+		nextStart = block.Start + fallbackChapterSeconds
+	}
+	return candidates
+}
+
+// assembleChapters converts block-based boundaries into chapters spanning segment and time ranges
+func assembleChapters(materialID string, segments []models.ASRSegment, blocks []chapterBlock, candidates []chapterCandidate) []models.ASRChapter {
+	chapters := make([]models.ASRChapter, len(candidates))
+	for i, c := range candidates {
+		first := blocks[c.StartBlock].firstSegment
+		last := len(segments) - 1
+		if i+1 < len(candidates) {
+			last = blocks[candidates[i+1].StartBlock].firstSegment - 1
+		}
+
+		chapters[i] = models.ASRChapter{
+			MaterialID:   materialID,
+			ChapterIndex: i,
+			Title:        truncateRunes(c.Title, 254),
+			Summary:      strings.TrimSpace(c.Summary),
+			StartTime:    segments[first].StartTime,
+			EndTime:      segments[last].EndTime,
+			StartSegment: segments[first].SegmentIndex,
+			EndSegment:   segments[last].SegmentIndex,
+		}
+	}
+	return chapters
+}
+
+func truncateRunes(s string, limit int) string {
+	runes := []rune(s)
+	if len(runes) <= limit {
+		return s
+	}
+	return string(runes[:limit]) + "…"
+}
@@ -0,0 +1,194 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/RigelNana/arkstudy/services/asr-service/database"
+	"github.com/RigelNana/arkstudy/services/asr-service/models"
+)
+
+// Whisper itself treats output with a gzip compression ratio above 2.4 as repetitive
+// (usually hallucinated) and retries it; segments above it are flagged regardless of logprob
+const maxCompressionRatio = 2.4
+
+// segmentConfidence converts Whisper's avg_logprob (mean token log-probability, <= 0) into
+// a 0-1 confidence, discounted by the probability that the segment contains no speech
+func segmentConfidence(seg models.WhisperSegment) float64 {
+	confidence := math.Exp(seg.AvgLogprob) * (1 - seg.NoSpeechProb)
+	return min(max(confidence, 0), 1)
+}
+
+// transcriptionScore aggregates the Whisper segments covering one stored segment
+type transcriptionScore struct {
+	Confidence float64
+	AvgLogprob float64
+	Low        bool
+}
+
+// scoreSegments weights each Whisper segment by its duration. Any repetitive segment marks the whole result as low.
+func (s *ASRService) scoreSegments(segs []models.WhisperSegment) transcriptionScore {
+	var score transcriptionScore
+	var total float64
+	for _, seg := range segs {
+		weight := max(seg.End-seg.Start, 0.01)
+		score.Confidence += segmentConfidence(seg) * weight
+		score.AvgLogprob += seg.AvgLogprob * weight
+		total += weight
+		if seg.CompressionRatio > maxCompressionRatio {
+			score.Low = true
+		}
+	}
+	if total > 0 {
+		score.Confidence /= total
+		score.AvgLogprob /= total
+	}
+	if score.Confidence < s.config.LowConfidenceThreshold {
+		score.Low = true
+	}
+	return score
+}
+
+// RetranscribeSegments re-transcribes the requested segments (by default the low-confidence ones)
+// with a larger model. Only the audio of those segments is sent; a segment is replaced only
+// when the new transcription is more confident, and its stale translations are removed.
+func (s *ASRService) RetranscribeSegments(ctx context.Context, req *models.RetranscribeRequest) (*models.RetranscribeResponse, error) {
+	model := strings.TrimSpace(req.Model)
+	if model == "" {
+		model = s.config.RetranscribeModel
+	}
+	response := &models.RetranscribeResponse{
+		MaterialID: req.MaterialID,
+		Model:      model,
+	}
+
+	query := database.DB.Where("material_id = ?", req.MaterialID)
+	if len(req.SegmentIDs) > 0 {
+		query = query.Where("id IN ?", req.SegmentIDs)
+	} else {
+		query = query.Where("low_confidence = ?", true)
+	}
+	var segments []models.ASRSegment
+	if err := query.Order("segment_index ASC").Find(&segments).Error; err != nil {
+		return nil, fmt.Errorf("failed to load segments: %w", err)
+	}
+	if len(segments) == 0 {
+		return response, nil
+	}
+
+	var seconds float64
+	for _, segment := range segments {
+		seconds += segment.EndTime - segment.StartTime
+	}
+	if err := s.checkAudioMinutes(seconds, "retranscribe"); err != nil {
+		return nil, err
+	}
+
+	jobDir, cleanup, err := s.newJobDir()
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	videoPath := filepath.Join(jobDir, "input.mp4")
+	if err := s.downloadVideo(req.VideoURL, videoPath); err != nil {
+		return nil, fmt.Errorf("failed to download video: %w", err)
+	}
+	audioPath := filepath.Join(jobDir, "audio."+s.config.AudioFormat)
+	if err := s.extractAudio(ctx, videoPath, audioPath); err != nil {
+		return nil, fmt.Errorf("failed to extract audio: %w", err)
+	}
+
+	var updated []string
+	var retranscribeErr error
+	for i := range segments {
+		segment := &segments[i]
+		improved, err := s.retranscribeSegment(ctx, jobDir, audioPath, model, segment)
+		if err != nil {
+			retranscribeErr = fmt.Errorf("failed to re-transcribe segment %d: %w", segment.SegmentIndex, err)
+			break
+		}
+		if improved {
+			updated = append(updated, segment.ID.String())
+		}
+	}
+
+	// Segments replaced before a failure are already stored, drop their translations either way
+	if len(updated) > 0 {
+		if err := database.DB.Where("segment_id IN ?", updated).
+			Delete(&models.ASRSegmentTranslation{}).Error; err != nil {
+			log.Printf("Failed to remove stale translations of material %s: %v", req.MaterialID, err)
+		}
+	}
+	if retranscribeErr != nil {
+		return nil, retranscribeErr
+	}
+	s.recordAudioUsage(req.MaterialID, seconds)
+
+	log.Printf("Re-transcribed %d segments of material %s with %s, %d improved",
+		len(segments), req.MaterialID, model, len(updated))
+
+	response.Segments = segments
+	response.RequestedCount = len(segments)
+	response.ImprovedCount = len(updated)
+	return response, nil
+}
+
+// retranscribeSegment cuts the segment's audio out of audioPath, transcribes it with model and
+// stores the result when it beats the current confidence. segment is updated in place.
+func (s *ASRService) retranscribeSegment(ctx context.Context, jobDir, audioPath, model string, segment *models.ASRSegment) (bool, error) {
+	clipPath := filepath.Join(jobDir, fmt.Sprintf("segment-%d.%s", segment.SegmentIndex, s.config.AudioFormat))
+	err := s.runFFmpeg(ctx, clipPath,
+		"-ss", strconv.FormatFloat(segment.StartTime, 'f', 3, 64),
+		"-t", strconv.FormatFloat(segment.EndTime-segment.StartTime, 'f', 3, 64),
+		"-i", audioPath,
+		"-c", "copy",
+		"-f", s.config.AudioFormat,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to cut segment audio: %w", err)
+	}
+
+	language := ""
+	if segment.Language != nil {
+		language = *segment.Language
+	}
+	whisperResp, err := s.transcribeAudio(ctx, clipPath, model, language)
+	if err != nil {
+		return false, err
+	}
+
+	// Whisper keeps the leading space of each segment where the language uses one
+	var text strings.Builder
+	for _, seg := range whisperResp.Segments {
+		text.WriteString(seg.Text)
+	}
+	if strings.TrimSpace(text.String()) == "" {
+		return false, nil
+	}
+	score := s.scoreSegments(whisperResp.Segments)
+	if segment.Confidence != nil && score.Confidence <= *segment.Confidence {
+		return false, nil
+	}
+
+	segment.Text = strings.TrimSpace(text.String())
+	segment.Confidence = &score.Confidence
+	segment.AvgLogprob = &score.AvgLogprob
+	segment.LowConfidence = score.Low
+	segment.Model = model
+	if err := database.DB.Model(segment).Updates(map[string]interface{}{
+		"text":           segment.Text,
+		"confidence":     score.Confidence,
+		"avg_logprob":    score.AvgLogprob,
+		"low_confidence": score.Low,
+		"model":          model,
+	}).Error; err != nil {
+		return false, fmt.Errorf("failed to store segment: %w", err)
+	}
+	return true, nil
+}
@@ -0,0 +1,175 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// jobDirPrefix names the per-job scratch directories created under the jobs root
+	jobDirPrefix = "job-"
+	// jobsDirName is the service-specific subdirectory of TempDir holding job directories
+	jobsDirName = "asr-service"
+	// maxStderrBytes caps how much ffmpeg stderr is kept for error messages
+	maxStderrBytes = 4096
+)
+
+var (
+	ErrFFmpegTimeout      = errors.New("ffmpeg timed out")
+	ErrOutputSizeExceeded = errors.New("ffmpeg output exceeded size limit")
+)
+
+// tailBuffer keeps only the last limit bytes written to it
+type tailBuffer struct {
+	buf   []byte
+	limit int
+}
+
+func (b *tailBuffer) Write(p []byte) (int, error) {
+	b.buf = append(b.buf, p...)
+	if len(b.buf) > b.limit {
+		b.buf = b.buf[len(b.buf)-b.limit:]
+	}
+	return len(p), nil
+}
+
+func (b *tailBuffer) String() string {
+	return strings.TrimSpace(string(b.buf))
+}
+
+// runFFmpeg runs ffmpeg with a hard timeout, writing to outputPath and refusing
+// outputs larger than maxOutputBytes. stderr is folded into the returned error.
+func (s *ASRService) runFFmpeg(ctx context.Context, outputPath string, args ...string) error {
+	ctx, cancel := context.WithTimeout(ctx, s.config.FFmpegTimeout)
+	defer cancel()
+
+	fullArgs := []string{"-hide_banner", "-nostdin", "-loglevel", "error", "-y"}
+	fullArgs = append(fullArgs, args...)
+	if s.config.FFmpegMaxOutputSize > 0 {
+		// -fs stops writing once the limit is reached; the size check below turns that into an error
+		fullArgs = append(fullArgs, "-fs", strconv.FormatInt(s.config.FFmpegMaxOutputSize, 10))
+	}
+	fullArgs = append(fullArgs, outputPath)
+
+	stderr := &tailBuffer{limit: maxStderrBytes}
+	cmd := exec.CommandContext(ctx, s.config.FFmpegBinaryPath, fullArgs...)
+	cmd.Stderr = stderr
+	// Don't let an orphaned child holding the pipes block Wait after the kill
+	cmd.WaitDelay = 5 * time.Second
+
+	if err := cmd.Run(); err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return fmt.Errorf("%w after %s", ErrFFmpegTimeout, s.config.FFmpegTimeout)
+		}
+		if msg := stderr.String(); msg != "" {
+			return fmt.Errorf("ffmpeg failed: %w: %s", err, msg)
+		}
+		return fmt.Errorf("ffmpeg failed: %w", err)
+	}
+
+	info, err := os.Stat(outputPath)
+	if err != nil {
+		return fmt.Errorf("ffmpeg produced no output: %w", err)
+	}
+	if s.config.FFmpegMaxOutputSize > 0 && info.Size() >= s.config.FFmpegMaxOutputSize {
+		return fmt.Errorf("%w (%d bytes)", ErrOutputSizeExceeded, s.config.FFmpegMaxOutputSize)
+	}
+
+	return nil
+}
+
+// newJobsRoot returns the directory for this instance's jobs: TempDir/asr-service/<hostname>.
+// TempDir may be shared with other processes or mounted into several replicas, so the
+// startup cleanup and the janitor only ever look inside this directory. A restarted
+// pod keeps its hostname and therefore finds the directories its previous run leaked.
+func newJobsRoot(tempDir string) string {
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		host = "local"
+	}
+	return filepath.Join(tempDir, jobsDirName, host)
+}
+
+// newJobDir creates an isolated scratch directory for a single ASR job.
+// The returned cleanup func removes it together with everything inside.
+func (s *ASRService) newJobDir() (string, func(), error) {
+	if err := os.MkdirAll(s.jobsRoot, 0755); err != nil {
+		return "", nil, fmt.Errorf("failed to create temp directory: %w", err)
+	}
+
+	dir, err := os.MkdirTemp(s.jobsRoot, jobDirPrefix+"*")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create job directory: %w", err)
+	}
+
+	cleanup := func() {
+		if err := os.RemoveAll(dir); err != nil {
+			log.Printf("Failed to remove job directory %s: %v", dir, err)
+		}
+	}
+	return dir, cleanup, nil
+}
+
+// cleanupStaleJobDirs removes job directories left behind by a previous crash of this instance
+func (s *ASRService) cleanupStaleJobDirs() {
+	entries, err := os.ReadDir(s.jobsRoot)
+	if err != nil {
+		return
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() || !strings.HasPrefix(entry.Name(), jobDirPrefix) {
+			continue
+		}
+		path := filepath.Join(s.jobsRoot, entry.Name())
+		if err := os.RemoveAll(path); err != nil {
+			log.Printf("Failed to remove stale job directory %s: %v", path, err)
+			continue
+		}
+		log.Printf("Removed stale job directory %s", path)
+	}
+}
+
+// runTempJanitor periodically removes expired entries under the jobs root. Job
+// directories normally clean up after themselves; this catches files leaked by
+// killed jobs. TempFileTTL must stay well above
+// the longest job (download + ffmpeg + transcription) so live jobs are never touched.
+func (s *ASRService) runTempJanitor() {
+	if s.config.TempCleanupInterval <= 0 || s.config.TempFileTTL <= 0 {
+		return
+	}
+	ticker := time.NewTicker(s.config.TempCleanupInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		s.cleanupExpiredTempFiles(time.Now().Add(-s.config.TempFileTTL))
+	}
+}
+
+// cleanupExpiredTempFiles removes jobs root entries last modified before cutoff
+func (s *ASRService) cleanupExpiredTempFiles(cutoff time.Time) {
+	entries, err := os.ReadDir(s.jobsRoot)
+	if err != nil {
+		return
+	}
+
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil || info.ModTime().After(cutoff) {
+			continue
+		}
+		path := filepath.Join(s.jobsRoot, entry.Name())
+		if err := os.RemoveAll(path); err != nil {
+			log.Printf("Failed to remove expired temp entry %s: %v", path, err)
+			continue
+		}
+		log.Printf("Removed expired temp entry %s", path)
+	}
+}
@@ -0,0 +1,63 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/RigelNana/arkstudy/pkg/metrics"
+)
+
+// ErrAudioTooLong is returned when a transcription would exceed the per-request audio cap
+var ErrAudioTooLong = errors.New("audio exceeds the per-request transcription limit")
+
+// Extracted audio is 16 kHz mono 16-bit PCM (see extractAudio), i.e. 32000 bytes per second
+// after the 44-byte WAV header
+const (
+	pcmBytesPerSecond = 16000 * 2
+	wavHeaderSize     = 44
+)
+
+// CheckDuration is the pre-flight check on the duration probed by material-service at upload.
+// A zero duration means it is unknown; the extracted audio is checked again before transcription.
+func (s *ASRService) CheckDuration(seconds float64) error {
+	return s.checkAudioMinutes(seconds, "preflight")
+}
+
+// checkAudioMinutes rejects audio longer than MaxAudioMinutes (0 disables the cap)
+func (s *ASRService) checkAudioMinutes(seconds float64, stage string) error {
+	limit := s.config.MaxAudioMinutes
+	minutes := seconds / 60
+	if limit <= 0 || minutes <= limit {
+		return nil
+	}
+	metrics.ASRRejectedTotal.WithLabelValues(stage).Inc()
+	return fmt.Errorf("%w: the audio is %.1f minutes long, the limit is %g minutes per transcription (estimated cost %s); split the recording into shorter parts",
+		ErrAudioTooLong, minutes, limit, s.estimateCost(minutes))
+}
+
+// estimateCost formats the transcription cost estimate for the given audio minutes
+func (s *ASRService) estimateCost(minutes float64) string {
+	return fmt.Sprintf("$%.2f", minutes*s.config.CostPerMinute)
+}
+
+// extractedAudioSeconds estimates the duration of the extracted audio from its size.
+// Only WAV output has a fixed byte rate; other formats report ok=false.
+func (s *ASRService) extractedAudioSeconds(audioPath string) (float64, bool) {
+	if s.config.AudioFormat != "wav" {
+		return 0, false
+	}
+	info, err := os.Stat(audioPath)
+	if err != nil {
+		return 0, false
+	}
+	return float64(max(info.Size()-wavHeaderSize, 0)) / pcmBytesPerSecond, true
+}
+
+// recordAudioUsage tracks the audio minutes billed for a finished transcription
+func (s *ASRService) recordAudioUsage(materialID string, seconds float64) {
+	minutes := seconds / 60
+	metrics.ASRAudioMinutes.Observe(minutes)
+	log.Printf("Transcribed %.1f audio minutes for material %s (estimated cost %s)", minutes, materialID, s.estimateCost(minutes))
+}
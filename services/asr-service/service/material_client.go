@@ -0,0 +1,247 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/RigelNana/arkstudy/pkg/discovery"
+	"github.com/RigelNana/arkstudy/pkg/registry"
+	"github.com/RigelNana/arkstudy/proto/material"
+	"github.com/RigelNana/arkstudy/services/asr-service/models"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+)
+
+var (
+	ErrInvalidMaterialID = errors.New("invalid material_id")
+	ErrMaterialNotFound  = errors.New("material not found")
+	// ErrProcessingLimit means the user already has too many OCR/ASR jobs in progress
+	ErrProcessingLimit = errors.New("processing limit reached")
+	// ErrTaskScheduled means material-service deferred the task to an off-peak processing window;
+	// it re-dispatches the task to asr-service with the same task ID when the window opens
+	ErrTaskScheduled = errors.New("task scheduled")
+)
+
+// callerDrivenOption asks material-service to register an ASR task without dispatching it
+const callerDrivenOption = "caller_driven"
+
+// MaterialClient verifies that ASR requests reference existing materials in material-service
+// and reports ASR task progress back into its ProcessingResult records
+type MaterialClient struct {
+	client material.MaterialServiceClient
+	conn   *grpc.ClientConn
+}
+
+// NewMaterialClient creates a gRPC client for material-service
+func NewMaterialClient(addr string) (*MaterialClient, error) {
+	conn, err := discovery.DialAddr(registry.Material.Name, addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to material service: %w", err)
+	}
+
+	return &MaterialClient{
+		client: material.NewMaterialServiceClient(conn),
+		conn:   conn,
+	}, nil
+}
+
+// VerifyMaterial checks that materialID is a valid UUID of an existing material;
+// when userID is non-empty the material must also belong to that user
+func (c *MaterialClient) VerifyMaterial(ctx context.Context, materialID, userID string) (*material.MaterialInfo, error) {
+	if _, err := uuid.Parse(materialID); err != nil {
+		return nil, ErrInvalidMaterialID
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	resp, err := c.client.GetMaterial(ctx, &material.GetMaterialRequest{
+		MaterialId: materialID,
+		UserId:     userID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query material service: %w", err)
+	}
+	if !resp.Found {
+		return nil, fmt.Errorf("%w: %s", ErrMaterialNotFound, resp.Message)
+	}
+
+	return resp.Material, nil
+}
+
+// ListCollectionMedia returns the audio and video materials inside a collection (bundle material)
+func (c *MaterialClient) ListCollectionMedia(ctx context.Context, collectionID, userID string) ([]*material.MaterialInfo, error) {
+	if _, err := uuid.Parse(collectionID); err != nil {
+		return nil, ErrInvalidMaterialID
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	resp, err := c.client.ListChildMaterials(ctx, &material.ListChildMaterialsRequest{
+		MaterialId: collectionID,
+		UserId:     userID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query material service: %w", err)
+	}
+	if !resp.Success {
+		return nil, fmt.Errorf("failed to list collection materials: %s", resp.Message)
+	}
+
+	var media []*material.MaterialInfo
+	for _, m := range resp.Materials {
+		if m.FileType == "video" || m.FileType == "audio" {
+			media = append(media, m)
+		}
+	}
+	return media, nil
+}
+
+// StartTask registers an ASR processing task for the material and returns its task ID.
+// The task is registered as caller-driven, so material-service records it without
+// dispatching it back to this service; the caller transcribes and reports the result.
+// When the task is deferred the task ID is returned together with ErrTaskScheduled
+func (c *MaterialClient) StartTask(ctx context.Context, materialID, userID string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	resp, err := c.client.ProcessMaterial(ctx, &material.ProcessMaterialRequest{
+		MaterialId: materialID,
+		UserId:     userID,
+		Type:       material.ProcessingType_ASR,
+		Options:    map[string]string{callerDrivenOption: "true"},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to register ASR task: %w", err)
+	}
+	if !resp.Success {
+		if resp.Limit != nil {
+			return "", fmt.Errorf("%w: %s", ErrProcessingLimit, resp.Message)
+		}
+		return "", fmt.Errorf("failed to register ASR task: %s", resp.Message)
+	}
+	if resp.Result.GetStatus() == material.ProcessingStatus_SCHEDULED {
+		return resp.TaskId, fmt.Errorf("%w: %s", ErrTaskScheduled, resp.Message)
+	}
+
+	return resp.TaskId, nil
+}
+
+// MarkProcessing moves the task into the processing state
+func (c *MaterialClient) MarkProcessing(ctx context.Context, taskID string) error {
+	return c.updateResult(ctx, &material.UpdateProcessingResultRequest{
+		TaskId: taskID,
+		Status: material.ProcessingStatus_PROCESSING,
+	})
+}
+
+// ReportProgress updates the task's progress (0-1) without touching its status
+func (c *MaterialClient) ReportProgress(ctx context.Context, taskID string, progress float32) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	resp, err := c.client.UpdateProcessingProgress(ctx, &material.UpdateProcessingProgressRequest{
+		TaskId:   taskID,
+		Progress: progress,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update progress: %w", err)
+	}
+	if !resp.Success {
+		return fmt.Errorf("failed to update progress: %s", resp.Message)
+	}
+	return nil
+}
+
+// ReportResult writes the final ASR outcome and summary stats into the task's ProcessingResult
+func (c *MaterialClient) ReportResult(ctx context.Context, taskID string, resp *models.ASRResponse, procErr error) error {
+	req := &material.UpdateProcessingResultRequest{
+		TaskId: taskID,
+		Status: material.ProcessingStatus_COMPLETED,
+	}
+
+	if procErr != nil || resp == nil || !resp.Success {
+		req.Status = material.ProcessingStatus_FAILED
+		switch {
+		case procErr != nil:
+			req.ErrorMessage = procErr.Error()
+		case resp != nil:
+			req.ErrorMessage = resp.Message
+		default:
+			req.ErrorMessage = "ASR processing failed"
+		}
+		return c.updateResult(ctx, req)
+	}
+
+	req.Content = transcriptText(resp.Segments)
+	req.Metadata = transcriptStats(resp)
+	return c.updateResult(ctx, req)
+}
+
+func (c *MaterialClient) updateResult(ctx context.Context, req *material.UpdateProcessingResultRequest) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	resp, err := c.client.UpdateProcessingResult(ctx, req)
+	if err != nil {
+		return fmt.Errorf("failed to update processing result: %w", err)
+	}
+	if !resp.Success {
+		return fmt.Errorf("failed to update processing result: %s", resp.Message)
+	}
+	return nil
+}
+
+// transcriptText joins segment texts into the full transcript
+func transcriptText(segments []models.ASRSegment) string {
+	texts := make([]string, 0, len(segments))
+	for _, segment := range segments {
+		if text := strings.TrimSpace(segment.Text); text != "" {
+			texts = append(texts, text)
+		}
+	}
+	return strings.Join(texts, "\n")
+}
+
+// transcriptStats builds the summary stats stored as ProcessingResult metadata
+func transcriptStats(resp *models.ASRResponse) map[string]string {
+	var chars int
+	var confidenceSum float64
+	var confidenceCount, lowCount int
+	for _, segment := range resp.Segments {
+		chars += utf8.RuneCountInString(strings.TrimSpace(segment.Text))
+		if segment.Confidence != nil {
+			confidenceSum += *segment.Confidence
+			confidenceCount++
+		}
+		if segment.LowConfidence {
+			lowCount++
+		}
+	}
+
+	stats := map[string]string{
+		"segment_count":        strconv.Itoa(len(resp.Segments)),
+		"duration_seconds":     strconv.FormatFloat(resp.TotalDuration, 'f', 2, 64),
+		"audio_minutes":        strconv.FormatFloat(resp.TotalDuration/60, 'f', 2, 64),
+		"char_count":           strconv.Itoa(chars),
+		"low_confidence_count": strconv.Itoa(lowCount),
+		"language":             resp.Language,
+		"processed_at":         resp.ProcessedAt,
+	}
+	if confidenceCount > 0 {
+		stats["avg_confidence"] = strconv.FormatFloat(confidenceSum/float64(confidenceCount), 'f', 4, 64)
+	}
+	return stats
+}
+
+// Close closes the underlying connection
+func (c *MaterialClient) Close() error {
+	return c.conn.Close()
+}
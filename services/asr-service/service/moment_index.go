@@ -0,0 +1,198 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/RigelNana/arkstudy/pkg/discovery"
+	"github.com/RigelNana/arkstudy/pkg/langdetect"
+	"github.com/RigelNana/arkstudy/pkg/registry"
+	"github.com/RigelNana/arkstudy/proto/llm"
+	"github.com/RigelNana/arkstudy/services/asr-service/models"
+
+	"google.golang.org/grpc"
+)
+
+const (
+	// maxMomentChunkBytes caps the text of one indexed window of consecutive segments
+	maxMomentChunkBytes = 500
+	// momentCandidateFactor widens the vector search so the per-material cap still leaves enough hits
+	momentCandidateFactor = 3
+	// maxMomentCandidates bounds the candidate pool requested from llm-service
+	maxMomentCandidates = 100
+)
+
+// Moment is a transcript window that matched a moment search
+type Moment struct {
+	MaterialID string
+	StartTime  float64
+	EndTime    float64
+	Text       string
+	Score      float32
+	SegmentID  string
+}
+
+// MomentIndex embeds transcript segments into llm-service's vector store (pgvector, ivfflat index)
+// tagged with their time range, and searches them across many materials at once
+type MomentIndex struct {
+	client llm.LLMServiceClient
+	conn   *grpc.ClientConn
+}
+
+// NewMomentIndex creates a gRPC client for llm-service
+func NewMomentIndex(addr string) (*MomentIndex, error) {
+	conn, err := discovery.DialAddr(registry.LLM.Name, addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to llm service: %w", err)
+	}
+
+	return &MomentIndex{
+		client: llm.NewLLMServiceClient(conn),
+		conn:   conn,
+	}, nil
+}
+
+// IndexSegments replaces the material's chunks with windows of consecutive segments.
+// Each chunk carries a timecode plus start_time / end_time metadata used to build jump links.
+func (m *MomentIndex) IndexSegments(ctx context.Context, materialID, userID string, segments []models.ASRSegment) (int32, error) {
+	windows := momentWindows(segments)
+	if len(windows) == 0 {
+		return 0, nil
+	}
+
+	// Tag every chunk with the transcript's dominant language so llm-service can pick the embedding model
+	texts := make([]string, len(windows))
+	for i, w := range windows {
+		texts[i] = w.Text
+	}
+	language := langdetect.Detect(strings.Join(texts, "\n"))
+
+	req := &llm.UpsertChunksRequest{UserId: userID, MaterialId: materialID, Replace: true}
+	for i, w := range windows {
+		req.Chunks = append(req.Chunks, &llm.UpsertChunkItem{
+			Content:  w.Text,
+			Timecode: formatTimecode(w.StartTime) + "-" + formatTimecode(w.EndTime),
+			Metadata: map[string]string{
+				"source":      "asr",
+				"source_type": "asr",
+				"chunk_index": strconv.Itoa(i),
+				"start_time":  strconv.FormatFloat(w.StartTime, 'f', 2, 64),
+				"end_time":    strconv.FormatFloat(w.EndTime, 'f', 2, 64),
+				"segment_id":  w.SegmentID,
+				"language":    language,
+			},
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 60*time.Second)
+	defer cancel()
+
+	resp, err := m.client.UpsertChunks(ctx, req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to index transcript segments: %w", err)
+	}
+	return resp.Inserted, nil
+}
+
+// Search runs one vector search over all materialIDs and merges the hits by score.
+// Chunks without a time range (e.g. OCR text) are ignored; perMaterial <= 0 disables the per-material cap.
+func (m *MomentIndex) Search(ctx context.Context, query, userID string, materialIDs []string, limit, perMaterial int) ([]Moment, error) {
+	topK := limit * momentCandidateFactor
+	if topK > maxMomentCandidates {
+		topK = maxMomentCandidates
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	resp, err := m.client.SemanticSearch(ctx, &llm.SearchRequest{
+		Query:       query,
+		UserId:      userID,
+		TopK:        int32(topK),
+		MaterialIds: materialIDs,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to search transcripts: %w", err)
+	}
+
+	var moments []Moment
+	for _, r := range resp.Results {
+		start, err := strconv.ParseFloat(r.Metadata["start_time"], 64)
+		if err != nil {
+			continue
+		}
+		end, _ := strconv.ParseFloat(r.Metadata["end_time"], 64)
+		moments = append(moments, Moment{
+			MaterialID: r.MaterialId,
+			StartTime:  start,
+			EndTime:    end,
+			Text:       r.Content,
+			Score:      r.SimilarityScore,
+			SegmentID:  r.Metadata["segment_id"],
+		})
+	}
+	return rankMoments(moments, limit, perMaterial), nil
+}
+
+// rankMoments orders moments by score and applies the per-material and total limits
+func rankMoments(moments []Moment, limit, perMaterial int) []Moment {
+	sort.SliceStable(moments, func(i, j int) bool { return moments[i].Score > moments[j].Score })
+
+	counts := map[string]int{}
+	out := make([]Moment, 0, limit)
+	for _, mo := range moments {
+		if len(out) >= limit {
+			break
+		}
+		if perMaterial > 0 && counts[mo.MaterialID] >= perMaterial {
+			continue
+		}
+		counts[mo.MaterialID]++
+		out = append(out, mo)
+	}
+	return out
+}
+
+// momentWindows groups consecutive segments into chunks of at most maxMomentChunkBytes
+func momentWindows(segments []models.ASRSegment) []Moment {
+	var windows []Moment
+	var cur *Moment
+	for _, seg := range segments {
+		text := strings.TrimSpace(seg.Text)
+		if text == "" {
+			continue
+		}
+		if cur != nil && len(cur.Text)+len(text)+1 > maxMomentChunkBytes {
+			windows = append(windows, *cur)
+			cur = nil
+		}
+		if cur == nil {
+			cur = &Moment{StartTime: seg.StartTime, Text: text, SegmentID: seg.ID.String()}
+		} else {
+			cur.Text += " " + text
+		}
+		cur.EndTime = seg.EndTime
+	}
+	if cur != nil {
+		windows = append(windows, *cur)
+	}
+	return windows
+}
+
+// formatTimecode renders seconds as HH:MM:SS
+func formatTimecode(seconds float64) string {
+	if seconds < 0 {
+		seconds = 0
+	}
+	total := int(seconds)
+	return fmt.Sprintf("%02d:%02d:%02d", total/3600, total%3600/60, total%60)
+}
+
+// Close closes the underlying connection
+func (m *MomentIndex) Close() error {
+	return m.conn.Close()
+}
@@ -0,0 +1,179 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/RigelNana/arkstudy/services/asr-service/database"
+	"github.com/RigelNana/arkstudy/services/asr-service/models"
+
+	"github.com/google/uuid"
+	"github.com/sashabaranov/go-openai"
+	"gorm.io/gorm/clause"
+)
+
+var ErrNoSegments = errors.New("no ASR segments found for material")
+
+// translationItem is the per-segment payload exchanged with the LLM
+type translationItem struct {
+	ID   int    `json:"id"`
+	Text string `json:"text"`
+}
+
+type translationBatch struct {
+	Translations []translationItem `json:"translations"`
+}
+
+// TranslateTranscript translates a material's segments into the target language in batches
+// and stores the translations next to the originals. Segments that already have a
+// translation in that language are reused unless Force is set.
+func (s *ASRService) TranslateTranscript(ctx context.Context, req *models.TranslateTranscriptRequest) (*models.TranslateTranscriptResponse, error) {
+	language := strings.TrimSpace(req.TargetLanguage)
+	if language == "" {
+		return nil, fmt.Errorf("target_language is required")
+	}
+
+	segments, err := s.GetSegmentsByMaterialID(req.MaterialID)
+	if err != nil {
+		return nil, err
+	}
+	if len(segments) == 0 {
+		return nil, ErrNoSegments
+	}
+
+	existing := make(map[uuid.UUID]string)
+	if !req.Force {
+		var translations []models.ASRSegmentTranslation
+		if err := database.DB.Where("material_id = ? AND language = ?", req.MaterialID, language).
+			Find(&translations).Error; err != nil {
+			return nil, fmt.Errorf("failed to load translations: %w", err)
+		}
+		for _, t := range translations {
+			existing[t.SegmentID] = t.Text
+		}
+	}
+
+	var pending []models.ASRSegment
+	for _, segment := range segments {
+		if _, ok := existing[segment.ID]; !ok {
+			pending = append(pending, segment)
+		}
+	}
+
+	batchSize := s.config.TranslationBatchSize
+	if batchSize <= 0 {
+		batchSize = 20
+	}
+
+	translatedCount := 0
+	for start := 0; start < len(pending); start += batchSize {
+		end := start + batchSize
+		if end > len(pending) {
+			end = len(pending)
+		}
+		batch := pending[start:end]
+
+		texts, err := s.translateBatch(ctx, batch, language)
+		if err != nil {
+			return nil, fmt.Errorf("failed to translate segments %d-%d: %w", start, end-1, err)
+		}
+
+		rows := make([]models.ASRSegmentTranslation, len(batch))
+		for i, segment := range batch {
+			rows[i] = models.ASRSegmentTranslation{
+				SegmentID:  segment.ID,
+				MaterialID: req.MaterialID,
+				Language:   language,
+				Text:       texts[i],
+			}
+			existing[segment.ID] = texts[i]
+		}
+
+		// Store each batch as soon as it's done so a later failure doesn't waste earlier work
+		if err := database.DB.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "segment_id"}, {Name: "language"}},
+			DoUpdates: clause.AssignmentColumns([]string{"text", "updated_at"}),
+		}).Create(&rows).Error; err != nil {
+			return nil, fmt.Errorf("failed to store translations: %w", err)
+		}
+		translatedCount += len(batch)
+	}
+
+	log.Printf("Translated %d segments of material %s into %s (%d reused)",
+		translatedCount, req.MaterialID, language, len(segments)-translatedCount)
+
+	response := &models.TranslateTranscriptResponse{
+		MaterialID:      req.MaterialID,
+		TargetLanguage:  language,
+		Segments:        make([]models.TranslatedSegment, len(segments)),
+		TranslatedCount: translatedCount,
+	}
+	for i, segment := range segments {
+		response.Segments[i] = models.TranslatedSegment{
+			Segment:        segment,
+			TranslatedText: existing[segment.ID],
+		}
+	}
+
+	return response, nil
+}
+
+// translateBatch asks the chat model to translate a batch of segments, returning texts in input order
+func (s *ASRService) translateBatch(ctx context.Context, segments []models.ASRSegment, language string) ([]string, error) {
+	items := make([]translationItem, len(segments))
+	for i, segment := range segments {
+		items[i] = translationItem{ID: i, Text: segment.Text}
+	}
+	payload, err := json.Marshal(map[string]interface{}{"segments": items})
+	if err != nil {
+		return nil, err
+	}
+
+	systemPrompt := fmt.Sprintf("You translate lecture transcript segments into %s. "+
+		"Translate each segment independently, keep technical terms accurate and do not merge or split segments. "+
+		`Reply with JSON of the form {"translations":[{"id":0,"text":"..."}]} containing every input id.`, language)
+
+	resp, err := s.openAIClient.CreateChatCompletion(ctx, openai.ChatCompletionRequest{
+		Model:       s.config.ChatModel,
+		Temperature: 0.2,
+		ResponseFormat: &openai.ChatCompletionResponseFormat{
+			Type: openai.ChatCompletionResponseFormatTypeJSONObject,
+		},
+		Messages: []openai.ChatCompletionMessage{
+			{Role: openai.ChatMessageRoleSystem, Content: systemPrompt},
+			{Role: openai.ChatMessageRoleUser, Content: string(payload)},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("chat completion failed: %w", err)
+	}
+	if len(resp.Choices) == 0 {
+		return nil, fmt.Errorf("chat completion returned no choices")
+	}
+
+	var result translationBatch
+	if err := json.Unmarshal([]byte(resp.Choices[0].Message.Content), &result); err != nil {
+		return nil, fmt.Errorf("failed to parse translation response: %w", err)
+	}
+
+	texts := make([]string, len(segments))
+	found := make([]bool, len(segments))
+	for _, item := range result.Translations {
+		if item.ID < 0 || item.ID >= len(segments) {
+			continue
+		}
+		texts[item.ID] = strings.TrimSpace(item.Text)
+		found[item.ID] = true
+	}
+	for i, ok := range found {
+		if !ok {
+			return nil, fmt.Errorf("translation missing for segment %d", i)
+		}
+	}
+
+	return texts, nil
+}
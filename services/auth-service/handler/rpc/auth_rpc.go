@@ -3,11 +3,14 @@ package rpc
 import (
 	"context"
 	"errors"
+	"time"
 
 	pb "github.com/RigelNana/arkstudy/proto/auth"
 	"github.com/RigelNana/arkstudy/services/auth-service/service"
 
 	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type AuthRPCServer struct {
@@ -27,7 +30,7 @@ func (s *AuthRPCServer) Register(ctx context.Context, in *pb.RegisterRequest) (*
 		return &pb.RegisterResponse{Success: false, Message: "invalid user_id format"}, nil
 	}
 	if err := s.svc.Register(userID, in.Password); err != nil {
-		return &pb.RegisterResponse{Success: false, Message: err.Error()}, nil
+		return &pb.RegisterResponse{Success: false, Message: err.Error(), Violations: policyViolations(err)}, nil
 	}
 	return &pb.RegisterResponse{Success: true, Message: "ok"}, nil
 }
@@ -40,22 +43,89 @@ func (s *AuthRPCServer) Login(ctx context.Context, in *pb.LoginRequest) (*pb.Log
 	if err != nil {
 		return &pb.LoginResponse{Success: false, Message: "invalid user_id format"}, nil
 	}
-	token, err := s.svc.Login(userID, in.Password)
+	result, err := s.svc.Login(userID, in.Password, service.Device{UserAgent: in.UserAgent, IP: in.Ip})
 	if err != nil {
 		return &pb.LoginResponse{Success: false, Message: err.Error()}, nil
 	}
-	return &pb.LoginResponse{Success: true, Token: token, Message: "ok"}, nil
+	return &pb.LoginResponse{
+		Success:                true,
+		Token:                  result.Token,
+		Message:                "ok",
+		SessionId:              result.SessionID.String(),
+		PasswordChangeRequired: result.PasswordChangeRequired,
+	}, nil
 }
 
 func (s *AuthRPCServer) ValidateToken(ctx context.Context, in *pb.ValidateTokenRequest) (*pb.ValidateTokenResponse, error) {
 	if in == nil || in.Token == "" {
 		return &pb.ValidateTokenResponse{Valid: false, Message: "missing token"}, nil
 	}
-	userID, err := s.svc.ValidateToken(in.Token)
+	identity, err := s.svc.ValidateToken(in.Token, in.Ip)
 	if err != nil {
 		return &pb.ValidateTokenResponse{Valid: false, Message: err.Error()}, nil
 	}
-	return &pb.ValidateTokenResponse{Valid: true, UserId: userID.String(), Message: "ok"}, nil
+	resp := &pb.ValidateTokenResponse{Valid: true, UserId: identity.UserID.String(), Message: "ok"}
+	if identity.SessionID != uuid.Nil {
+		resp.SessionId = identity.SessionID.String()
+	}
+	if identity.ImpersonatorID != uuid.Nil {
+		resp.ImpersonatorId = identity.ImpersonatorID.String()
+	}
+	return resp, nil
+}
+
+func (s *AuthRPCServer) ListSessions(ctx context.Context, in *pb.ListSessionsRequest) (*pb.ListSessionsResponse, error) {
+	uid, err := uuid.Parse(in.GetUserId())
+	if err != nil {
+		return &pb.ListSessionsResponse{Success: false, Message: "invalid user_id format"}, nil
+	}
+	sessions, err := s.svc.ListSessions(uid)
+	if err != nil {
+		return &pb.ListSessionsResponse{Success: false, Message: err.Error()}, nil
+	}
+	resp := &pb.ListSessionsResponse{Success: true, Message: "ok"}
+	for _, sess := range sessions {
+		resp.Sessions = append(resp.Sessions, &pb.Session{
+			SessionId:    sess.ID.String(),
+			UserAgent:    sess.UserAgent,
+			Ip:           sess.IP,
+			CreatedAt:    sess.CreatedAt.Format(time.RFC3339),
+			LastSeenAt:   sess.LastSeenAt.Format(time.RFC3339),
+			ExpiresAt:    sess.ExpiresAt.Format(time.RFC3339),
+			Current:      in.CurrentSessionId != "" && sess.ID.String() == in.CurrentSessionId,
+			Impersonated: sess.ImpersonatorID != nil,
+		})
+	}
+	return resp, nil
+}
+
+// RevokeSession session_id 为空时注销除 keep_session_id 外的全部会话
+func (s *AuthRPCServer) RevokeSession(ctx context.Context, in *pb.RevokeSessionRequest) (*pb.RevokeSessionResponse, error) {
+	uid, err := uuid.Parse(in.GetUserId())
+	if err != nil {
+		return &pb.RevokeSessionResponse{Success: false, Message: "invalid user_id format"}, nil
+	}
+	if in.SessionId == "" {
+		keep := uuid.Nil
+		if in.KeepSessionId != "" {
+			if keep, err = uuid.Parse(in.KeepSessionId); err != nil {
+				return &pb.RevokeSessionResponse{Success: false, Message: "invalid keep_session_id format"}, nil
+			}
+		}
+		n, err := s.svc.RevokeOtherSessions(uid, keep)
+		if err != nil {
+			return &pb.RevokeSessionResponse{Success: false, Message: err.Error()}, nil
+		}
+		return &pb.RevokeSessionResponse{Success: true, Message: "ok", Revoked: int32(n)}, nil
+	}
+	sid, err := uuid.Parse(in.SessionId)
+	if err != nil {
+		return &pb.RevokeSessionResponse{Success: false, Message: "invalid session_id format"}, nil
+	}
+	if err := s.svc.RevokeSession(uid, sid); err != nil {
+		return &pb.RevokeSessionResponse{Success: false, Message: err.Error()}, nil
+	}
+	return &pb.RevokeSessionResponse{Success: true, Message: "ok", Revoked: 1}, nil
 }
 
 func (s *AuthRPCServer) CheckPassword(ctx context.Context, in *pb.CheckPasswordRequest) (*pb.CheckPasswordResponse, error) {
@@ -72,3 +142,166 @@ func (s *AuthRPCServer) CheckPassword(ctx context.Context, in *pb.CheckPasswordR
 	}
 	return &pb.CheckPasswordResponse{Valid: valid}, nil
 }
+
+func (s *AuthRPCServer) GetJWKS(ctx context.Context, in *pb.GetJWKSRequest) (*pb.GetJWKSResponse, error) {
+	keys, err := s.svc.JWKS()
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	resp := &pb.GetJWKSResponse{}
+	for _, k := range keys {
+		resp.Keys = append(resp.Keys, &pb.JSONWebKey{Kid: k.Kid, Kty: k.Kty, Alg: k.Alg, Use: "sig", Crv: k.Crv, X: k.X, N: k.N, E: k.E})
+	}
+	return resp, nil
+}
+
+func (s *AuthRPCServer) ImpersonateUser(ctx context.Context, in *pb.ImpersonateUserRequest) (*pb.ImpersonateUserResponse, error) {
+	adminID, err := uuid.Parse(in.GetAdminUserId())
+	if err != nil {
+		return &pb.ImpersonateUserResponse{Success: false, Message: "invalid admin_user_id format"}, nil
+	}
+	targetID, err := uuid.Parse(in.GetTargetUserId())
+	if err != nil {
+		return &pb.ImpersonateUserResponse{Success: false, Message: "invalid target_user_id format"}, nil
+	}
+	grant, err := s.svc.ImpersonateUser(ctx, adminID, targetID, in.Reason, int(in.DurationMinutes), service.Device{UserAgent: in.UserAgent, IP: in.Ip})
+	if err != nil {
+		return &pb.ImpersonateUserResponse{Success: false, Message: err.Error()}, nil
+	}
+	return &pb.ImpersonateUserResponse{
+		Success:   true,
+		Message:   "ok",
+		Token:     grant.Token,
+		SessionId: grant.SessionID.String(),
+		ExpiresAt: grant.ExpiresAt.Format(time.RFC3339),
+		AuditId:   grant.AuditID.String(),
+	}, nil
+}
+
+func (s *AuthRPCServer) ListImpersonations(ctx context.Context, in *pb.ListImpersonationsRequest) (*pb.ListImpersonationsResponse, error) {
+	adminID, err := uuid.Parse(in.GetAdminUserId())
+	if err != nil {
+		return &pb.ListImpersonationsResponse{Success: false, Message: "invalid admin_user_id format"}, nil
+	}
+	var targetID, impersonatorID uuid.UUID
+	if in.TargetUserId != "" {
+		if targetID, err = uuid.Parse(in.TargetUserId); err != nil {
+			return &pb.ListImpersonationsResponse{Success: false, Message: "invalid target_user_id format"}, nil
+		}
+	}
+	if in.ImpersonatorId != "" {
+		if impersonatorID, err = uuid.Parse(in.ImpersonatorId); err != nil {
+			return &pb.ListImpersonationsResponse{Success: false, Message: "invalid impersonator_id format"}, nil
+		}
+	}
+	records, err := s.svc.ListImpersonations(ctx, adminID, targetID, impersonatorID, int(in.Limit))
+	if err != nil {
+		return &pb.ListImpersonationsResponse{Success: false, Message: err.Error()}, nil
+	}
+	resp := &pb.ListImpersonationsResponse{Success: true, Message: "ok"}
+	for _, r := range records {
+		resp.Impersonations = append(resp.Impersonations, &pb.Impersonation{
+			Id:             r.ID.String(),
+			ImpersonatorId: r.ImpersonatorID.String(),
+			TargetUserId:   r.TargetUserID.String(),
+			SessionId:      r.SessionID.String(),
+			Reason:         r.Reason,
+			Ip:             r.IP,
+			UserAgent:      r.UserAgent,
+			CreatedAt:      r.CreatedAt.Format(time.RFC3339),
+			ExpiresAt:      r.ExpiresAt.Format(time.RFC3339),
+			Revoked:        r.Revoked,
+		})
+	}
+	return resp, nil
+}
+
+func (s *AuthRPCServer) ProvisionUsers(ctx context.Context, in *pb.ProvisionUsersRequest) (*pb.ProvisionUsersResponse, error) {
+	adminID, err := uuid.Parse(in.GetAdminUserId())
+	if err != nil {
+		return &pb.ProvisionUsersResponse{Success: false, Message: "invalid admin_user_id format"}, nil
+	}
+	reqs := make([]service.ProvisionRequest, 0, len(in.Users))
+	for _, u := range in.Users {
+		reqs = append(reqs, service.ProvisionRequest{Username: u.Username, Email: u.Email, Role: u.Role, Description: u.Description})
+	}
+	results, err := s.svc.ProvisionUsers(ctx, adminID, reqs, in.DryRun)
+	if err != nil {
+		return &pb.ProvisionUsersResponse{Success: false, Message: err.Error()}, nil
+	}
+	resp := &pb.ProvisionUsersResponse{Success: true, Message: "ok"}
+	for i, r := range results {
+		pr := &pb.ProvisionResult{
+			Index:             int32(i),
+			Username:          r.Username,
+			Email:             r.Email,
+			Status:            r.Status,
+			Message:           r.Message,
+			TemporaryPassword: r.TemporaryPassword,
+		}
+		if r.UserID != uuid.Nil {
+			pr.UserId = r.UserID.String()
+		}
+		switch r.Status {
+		case service.ProvisionCreated:
+			resp.Created++
+		case service.ProvisionValid:
+		default:
+			resp.Failed++
+		}
+		resp.Results = append(resp.Results, pr)
+	}
+	return resp, nil
+}
+
+// UpdatePassword 校验当前密码后按密码策略更新密码
+func (s *AuthRPCServer) UpdatePassword(ctx context.Context, in *pb.UpdatePasswordRequest) (*pb.UpdatePasswordResponse, error) {
+	if in == nil || in.UserId == "" || in.CurrentPassword == "" || in.NewPassword == "" {
+		return &pb.UpdatePasswordResponse{Success: false, Message: "missing user_id or password"}, nil
+	}
+	uid, err := uuid.Parse(in.UserId)
+	if err != nil {
+		return &pb.UpdatePasswordResponse{Success: false, Message: "invalid user_id format"}, nil
+	}
+	valid, err := s.svc.CheckPassword(uid, in.CurrentPassword)
+	if err != nil || !valid {
+		return &pb.UpdatePasswordResponse{Success: false, Message: "invalid credentials"}, nil
+	}
+	if err := s.svc.UpdatePassword(uid, in.NewPassword); err != nil {
+		return &pb.UpdatePasswordResponse{Success: false, Message: err.Error(), Violations: policyViolations(err)}, nil
+	}
+	return &pb.UpdatePasswordResponse{Success: true, Message: "ok"}, nil
+}
+
+func (s *AuthRPCServer) CheckPasswordPolicy(ctx context.Context, in *pb.CheckPasswordPolicyRequest) (*pb.CheckPasswordPolicyResponse, error) {
+	policy := s.svc.PasswordPolicy()
+	violations := s.svc.CheckPasswordPolicy(ctx, in.GetPassword(), in.GetIdentities())
+	return &pb.CheckPasswordPolicyResponse{
+		Valid:      len(violations) == 0,
+		Violations: toPBViolations(violations),
+		Policy: &pb.PasswordPolicy{
+			MinLength:       int32(policy.MinLength),
+			MaxLength:       int32(policy.MaxLength),
+			RequiredClasses: policy.RequiredClasses,
+			BreachCheck:     policy.BreachCheck,
+		},
+		Strength: int32(policy.Strength(in.GetPassword())),
+	}, nil
+}
+
+// policyViolations 从密码策略错误中取出逐项原因，其他错误返回 nil
+func policyViolations(err error) []*pb.PasswordViolation {
+	var policyErr *service.PasswordPolicyError
+	if !errors.As(err, &policyErr) {
+		return nil
+	}
+	return toPBViolations(policyErr.Violations)
+}
+
+func toPBViolations(violations []service.Violation) []*pb.PasswordViolation {
+	out := make([]*pb.PasswordViolation, 0, len(violations))
+	for _, v := range violations {
+		out = append(out, &pb.PasswordViolation{Code: v.Code, Message: v.Message})
+	}
+	return out
+}
@@ -3,16 +3,19 @@ package main
 import (
 	"log"
 	"net"
-	"os"
 
+	"github.com/RigelNana/arkstudy/pkg/discovery"
+	"github.com/RigelNana/arkstudy/pkg/interceptor"
 	"github.com/RigelNana/arkstudy/pkg/metrics"
 	grpcMetrics "github.com/RigelNana/arkstudy/pkg/metrics/grpc"
+	"github.com/RigelNana/arkstudy/pkg/registry"
 	pb "github.com/RigelNana/arkstudy/proto/auth"
 	"github.com/RigelNana/arkstudy/services/auth-service/database"
 	"github.com/RigelNana/arkstudy/services/auth-service/handler/rpc"
 	"github.com/RigelNana/arkstudy/services/auth-service/models"
 	"github.com/RigelNana/arkstudy/services/auth-service/repository"
 	"github.com/RigelNana/arkstudy/services/auth-service/service"
+	"github.com/RigelNana/arkstudy/services/auth-service/utils"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/reflection"
@@ -20,41 +23,53 @@ import (
 )
 
 func autoMigrate(db *gorm.DB) {
-	if err := db.AutoMigrate(&models.Auth{}); err != nil {
+	if err := db.AutoMigrate(&models.Auth{}, &models.Session{}, &models.Impersonation{}); err != nil {
 		log.Fatalf("auto migrate failed: %v", err)
 	}
 }
 
 func main() {
-	// 启动 Prometheus metrics 服务器
-	metrics.StartMetricsServer("2112")
-	log.Printf("Prometheus metrics server started on :2112")
+	// 启动内部 HTTP 监听：/metrics 与 /healthz、/readyz，地址由 METRICS_ADDR 配置
+	log.Printf("Prometheus metrics server started on %s", metrics.StartMetricsServer())
+
+	// 签名密钥配置错误时直接退出
+	if err := utils.ValidateSigningKey(); err != nil {
+		log.Fatalf("%v", err)
+	}
 
 	db := database.InitDB()
+	if sqlDB, err := db.DB(); err == nil {
+		metrics.AddReadinessCheck("database", sqlDB.PingContext)
+	}
 	autoMigrate(db)
 
 	repo := repository.NewAuthRepository(db)
-	svc := service.NewAuthService(repo)
+	svc := service.NewAuthService(repo, repository.NewSessionRepository(db), repository.NewImpersonationRepository(db))
 
 	// 创建带监控的 gRPC 服务器
+	// panic 恢复、错误码规范化与慢请求日志（GRPC_SLOW_THRESHOLD）
+	interceptorOpts := interceptor.DefaultOptions("auth-service")
 	grpcServer := grpc.NewServer(
-		grpc.UnaryInterceptor(grpcMetrics.UnaryServerInterceptor("auth-service")),
-		grpc.StreamInterceptor(grpcMetrics.StreamServerInterceptor("auth-service")),
+		grpc.ChainUnaryInterceptor(grpcMetrics.UnaryServerInterceptor("auth-service"), interceptor.UnaryServerInterceptor(interceptorOpts)),
+		grpc.ChainStreamInterceptor(grpcMetrics.StreamServerInterceptor("auth-service"), interceptor.StreamServerInterceptor(interceptorOpts)),
 	)
 
 	pb.RegisterAuthServiceServer(grpcServer, rpc.NewAuthRPCServer(svc))
+	// 健康检查供客户端在多个副本间故障转移（见 pkg/discovery）
+	discovery.RegisterHealth(grpcServer)
 	// Enable server reflection for grpcui/insomnia
 	reflection.Register(grpcServer)
 
-	port := os.Getenv("GRPC_PORT")
-	if port == "" {
-		port = "50051"
+	if err := registry.Validate(registry.Auth, registry.User); err != nil {
+		log.Fatalf("%v", err)
 	}
+	port := registry.Auth.ListenPort()
 	lis, err := net.Listen("tcp", ":"+port)
 	if err != nil {
 		log.Fatalf("failed to listen: %v", err)
 	}
 	log.Printf("Auth gRPC server listening on %s", port)
+	metrics.SetReady(true)
 	if err := grpcServer.Serve(lis); err != nil {
 		log.Fatalf("grpc serve error: %v", err)
 	}
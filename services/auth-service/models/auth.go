@@ -1,10 +1,18 @@
 package models
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
 
 // Auth 仅存储与认证相关的敏感数据（方案B：不在此保存用户名/邮箱等用户资料）
 type Auth struct {
 	Base
 	UserID   uuid.UUID `gorm:"type:uuid;not null;uniqueIndex"`
 	Password string    `gorm:"not null"` // bcrypt hash
+	// TokensValidAfter 早于该时间签发的 token 视为已吊销（修改密码时更新）
+	TokensValidAfter *time.Time
+	// PasswordTemporary 管理员批量开通时生成的临时密码，用户修改密码前登录会提示修改
+	PasswordTemporary bool `gorm:"not null;default:false"`
 }
@@ -0,0 +1,19 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Impersonation 管理员代登录的审计记录，签发成功后写入，不提供删除
+type Impersonation struct {
+	Base
+	ImpersonatorID uuid.UUID `gorm:"type:uuid;not null;index"`
+	TargetUserID   uuid.UUID `gorm:"type:uuid;not null;index"`
+	SessionID      uuid.UUID `gorm:"type:uuid;not null"`
+	Reason         string    `gorm:"type:text;not null"`
+	IP             string    `gorm:"size:64"`
+	UserAgent      string    `gorm:"size:512"`
+	ExpiresAt      time.Time
+}
@@ -0,0 +1,21 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Session 一次登录签发的 token 对应的设备会话，ID 写入 token 的 jti
+type Session struct {
+	Base
+	UserID     uuid.UUID `gorm:"type:uuid;not null;index"`
+	UserAgent  string    `gorm:"size:512"`
+	IP         string    `gorm:"size:64"`
+	LastSeenAt time.Time
+	ExpiresAt  time.Time `gorm:"index"`
+	// RevokedAt 用户主动注销或修改密码时设置，之后该会话的 token 校验失败
+	RevokedAt *time.Time
+	// ImpersonatorID 管理员代登录时签发 token 的管理员
+	ImpersonatorID *uuid.UUID `gorm:"type:uuid;index"`
+}
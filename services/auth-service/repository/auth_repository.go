@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"time"
+
 	"github.com/RigelNana/arkstudy/services/auth-service/models"
 
 	"github.com/google/uuid"
@@ -11,7 +13,7 @@ import (
 // 目前 Auth 仅存储密码哈希，可根据需要扩展（例如加入用户关联、令牌等）
 type AuthRepository interface {
 	BaseRepository[models.Auth]
-	// UpdatePassword 按 user_id 更新密码哈希
+	// UpdatePassword 按 user_id 更新密码哈希、清除临时密码标记，并吊销此前签发的 token
 	UpdatePassword(userID uuid.UUID, newHashedPassword string) error
 	GetByUserID(userID uuid.UUID) (*models.Auth, error)
 }
@@ -26,9 +28,15 @@ func NewAuthRepository(db *gorm.DB) AuthRepository {
 	return &AuthRepositoryImpl{BaseRepositoryImpl: NewBaseRepository[models.Auth](db)}
 }
 
-// UpdatePassword 仅更新 password 字段，返回记录不存在错误
+// UpdatePassword 更新 password、password_temporary 与 tokens_valid_after 字段，返回记录不存在错误。
+// token 的 iat 精确到秒，吊销时间取整到秒，修改密码后同一秒内签发的新 token 仍然有效
 func (r *AuthRepositoryImpl) UpdatePassword(userID uuid.UUID, newHashedPassword string) error {
-	result := r.db.Model(&models.Auth{}).Where("user_id = ?", userID).Update("password", newHashedPassword)
+	validAfter := time.Now().Truncate(time.Second)
+	result := r.db.Model(&models.Auth{}).Where("user_id = ?", userID).Updates(map[string]interface{}{
+		"password":           newHashedPassword,
+		"password_temporary": false,
+		"tokens_valid_after": validAfter,
+	})
 	if result.Error != nil {
 		return result.Error
 	}
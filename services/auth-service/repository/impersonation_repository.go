@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"github.com/RigelNana/arkstudy/services/auth-service/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ImpersonationRepository 代登录审计记录数据访问接口
+type ImpersonationRepository interface {
+	BaseRepository[models.Impersonation]
+	// Search 按目标用户、管理员过滤（uuid.Nil 为不过滤），按时间倒序
+	Search(targetUserID, impersonatorID uuid.UUID, limit int) ([]*models.Impersonation, error)
+}
+
+type ImpersonationRepositoryImpl struct {
+	*BaseRepositoryImpl[models.Impersonation]
+}
+
+func NewImpersonationRepository(db *gorm.DB) ImpersonationRepository {
+	return &ImpersonationRepositoryImpl{BaseRepositoryImpl: NewBaseRepository[models.Impersonation](db)}
+}
+
+func (r *ImpersonationRepositoryImpl) Search(targetUserID, impersonatorID uuid.UUID, limit int) ([]*models.Impersonation, error) {
+	q := r.db.Model(&models.Impersonation{})
+	if targetUserID != uuid.Nil {
+		q = q.Where("target_user_id = ?", targetUserID)
+	}
+	if impersonatorID != uuid.Nil {
+		q = q.Where("impersonator_id = ?", impersonatorID)
+	}
+	var records []*models.Impersonation
+	err := q.Order("created_at DESC").Limit(limit).Find(&records).Error
+	return records, err
+}
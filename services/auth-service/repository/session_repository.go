@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/RigelNana/arkstudy/services/auth-service/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// SessionRepository 登录会话数据访问接口
+type SessionRepository interface {
+	BaseRepository[models.Session]
+	// ListActive 返回用户未过期、未注销的会话，按最近活动时间倒序
+	ListActive(userID uuid.UUID, now time.Time) ([]*models.Session, error)
+	// Touch 更新会话的最近活动时间与 IP
+	Touch(id uuid.UUID, ip string, at time.Time) error
+	// Revoke 注销用户的指定会话，会话不存在或已注销时返回 gorm.ErrRecordNotFound
+	Revoke(userID, id uuid.UUID, at time.Time) error
+	// RevokeAllExcept 注销用户除 keep 外的全部有效会话，keep 为 uuid.Nil 时全部注销，返回注销数量
+	RevokeAllExcept(userID, keep uuid.UUID, at time.Time) (int64, error)
+	// DeleteExpired 删除用户在 before 之前过期的会话
+	DeleteExpired(userID uuid.UUID, before time.Time) error
+}
+
+type SessionRepositoryImpl struct {
+	*BaseRepositoryImpl[models.Session]
+}
+
+func NewSessionRepository(db *gorm.DB) SessionRepository {
+	return &SessionRepositoryImpl{BaseRepositoryImpl: NewBaseRepository[models.Session](db)}
+}
+
+func (r *SessionRepositoryImpl) ListActive(userID uuid.UUID, now time.Time) ([]*models.Session, error) {
+	var sessions []*models.Session
+	err := r.db.Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, now).
+		Order("last_seen_at DESC").Find(&sessions).Error
+	return sessions, err
+}
+
+func (r *SessionRepositoryImpl) Touch(id uuid.UUID, ip string, at time.Time) error {
+	updates := map[string]interface{}{"last_seen_at": at}
+	if ip != "" {
+		updates["ip"] = ip
+	}
+	return r.db.Model(&models.Session{}).Where("id = ?", id).Updates(updates).Error
+}
+
+func (r *SessionRepositoryImpl) Revoke(userID, id uuid.UUID, at time.Time) error {
+	result := r.db.Model(&models.Session{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", id, userID).
+		Update("revoked_at", at)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *SessionRepositoryImpl) RevokeAllExcept(userID, keep uuid.UUID, at time.Time) (int64, error) {
+	q := r.db.Model(&models.Session{}).Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, at)
+	if keep != uuid.Nil {
+		q = q.Where("id <> ?", keep)
+	}
+	result := q.Update("revoked_at", at)
+	return result.RowsAffected, result.Error
+}
+
+func (r *SessionRepositoryImpl) DeleteExpired(userID uuid.UUID, before time.Time) error {
+	return r.db.Unscoped().Where("user_id = ? AND expires_at < ?", userID, before).Delete(&models.Session{}).Error
+}
@@ -6,7 +6,11 @@ import (
 	"log"
 	"os"
 	"strconv"
+	"time"
+	"unicode/utf8"
 
+	"github.com/RigelNana/arkstudy/pkg/discovery"
+	"github.com/RigelNana/arkstudy/pkg/registry"
 	"github.com/RigelNana/arkstudy/proto/user"
 
 	"github.com/RigelNana/arkstudy/services/auth-service/models"
@@ -15,59 +19,80 @@ import (
 
 	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
-	"google.golang.org/grpc"
+	"gorm.io/gorm"
 )
 
+// sessionTouchInterval 会话最近活动时间的更新粒度，避免每次校验 token 都写库
+const sessionTouchInterval = time.Minute
+
+// ErrSessionNotFound 会话不存在、已注销或不属于该用户
+var ErrSessionNotFound = errors.New("session not found")
+
+// Device 发起请求的设备信息，记录到登录会话
+type Device struct {
+	UserAgent string
+	IP        string
+}
+
+// LoginResult 登录结果，PasswordChangeRequired 表示仍在使用临时密码
+type LoginResult struct {
+	Token                  string
+	SessionID              uuid.UUID
+	PasswordChangeRequired bool
+}
+
+// TokenIdentity token 校验通过后的身份。SessionID 对早于会话管理签发的 token 为 uuid.Nil，
+// ImpersonatorID 仅代登录 token 非空
+type TokenIdentity struct {
+	UserID         uuid.UUID
+	SessionID      uuid.UUID
+	ImpersonatorID uuid.UUID
+}
+
 type AuthService interface {
 	Register(userID uuid.UUID, rawPassword string) error
-	Login(userID uuid.UUID, rawPassword string) (string, error)
-	ValidateToken(token string) (uuid.UUID, error)
+	// Login 校验密码后为本次登录创建会话并签发 token
+	Login(userID uuid.UUID, rawPassword string, device Device) (*LoginResult, error)
+	// ValidateToken 校验 token 并返回其身份，ip 记录为会话最近活动的 IP
+	ValidateToken(token, ip string) (*TokenIdentity, error)
+	ListSessions(userID uuid.UUID) ([]*models.Session, error)
+	// RevokeSession 注销用户的指定会话
+	RevokeSession(userID, sessionID uuid.UUID) error
+	// RevokeOtherSessions 注销除 keep 外的全部会话，返回注销数量
+	RevokeOtherSessions(userID, keep uuid.UUID) (int64, error)
+	// ImpersonateUser 管理员以目标用户身份签发限时 token，minutes 为 0 时使用默认时长
+	ImpersonateUser(ctx context.Context, adminID, targetID uuid.UUID, reason string, minutes int, device Device) (*ImpersonationGrant, error)
+	// ListImpersonations 查询代登录审计，targetID / impersonatorID 为 uuid.Nil 时不过滤
+	ListImpersonations(ctx context.Context, adminID, targetID, impersonatorID uuid.UUID, limit int) ([]ImpersonationRecord, error)
+	// ProvisionUsers 管理员批量开通账号，dryRun 时只校验
+	ProvisionUsers(ctx context.Context, adminID uuid.UUID, users []ProvisionRequest, dryRun bool) ([]ProvisionResult, error)
 	CheckPassword(userID uuid.UUID, rawPassword string) (bool, error)
+	// UpdatePassword 按密码策略校验新密码后保存，新密码与当前密码相同时拒绝
 	UpdatePassword(userID uuid.UUID, newPassword string) error
+	// CheckPasswordPolicy 按密码策略校验密码，不保存
+	CheckPasswordPolicy(ctx context.Context, password string, identities []string) []Violation
+	PasswordPolicy() *PasswordPolicy
+	JWKS() ([]utils.JWK, error)
 }
 
 type AuthServiceImpl struct {
 	repo               repository.AuthRepository
+	sessions           repository.SessionRepository
+	impersonations     repository.ImpersonationRepository
 	tokenExpireMinutes int
 	userClient         user.UserServiceClient
+	policy             *PasswordPolicy
 }
 
-func NewAuthService(repo repository.AuthRepository) AuthService {
+func NewAuthService(repo repository.AuthRepository, sessions repository.SessionRepository, impersonations repository.ImpersonationRepository) AuthService {
 	expireStr := os.Getenv("JWT_EXPIRE_MINUTES")
 	if expireStr == "" {
 		expireStr = "60"
 	}
 	minutes, _ := strconv.Atoi(expireStr)
 	// 建立 user-service gRPC 连接
-	userAddr := os.Getenv("USER_GRPC_ADDR")
-	log.Printf("USER_GRPC_ADDR environment variable: %s", userAddr)
-
-	if userAddr == "" {
-		// 尝试使用 K8s 服务发现
-		userHost := os.Getenv("ARKSTUDY_USER_SERVICE_SERVICE_HOST")
-		userPort := os.Getenv("ARKSTUDY_USER_SERVICE_SERVICE_PORT")
-		log.Printf("K8s service discovery - Host: %s, Port: %s", userHost, userPort)
-
-		if userHost != "" && userPort != "" {
-			userAddr = userHost + ":" + userPort
-		} else {
-			userAddr = "localhost:50052" // 单机默认端口
-		}
-	}
-
-	// 如果userAddr是服务名，则强制使用IP地址
-	if userAddr == "user-service:50052" {
-		userHost := os.Getenv("ARKSTUDY_USER_SERVICE_SERVICE_HOST")
-		userPort := os.Getenv("ARKSTUDY_USER_SERVICE_SERVICE_PORT")
-		if userHost != "" && userPort != "" {
-			userAddr = userHost + ":" + userPort
-			log.Printf("Switched from service name to IP address: %s", userAddr)
-		}
-	}
-
-	log.Printf("Final user-service address: %s", userAddr)
-	log.Printf("Attempting to connect to user-service at: %s", userAddr)
-	conn, err := grpc.Dial(userAddr, grpc.WithInsecure())
+	log.Printf("Connecting to user-service at: %s", discovery.Target(registry.User))
+	conn, err := discovery.Dial(registry.User)
 	var client user.UserServiceClient
 	if err != nil {
 		// 记录错误但不终止服务启动，后续Register会报错提示
@@ -76,9 +101,9 @@ func NewAuthService(repo repository.AuthRepository) AuthService {
 		client = nil
 	} else {
 		client = user.NewUserServiceClient(conn)
-		log.Printf("Successfully connected to user-service at: %s", userAddr)
+		log.Printf("Successfully created user-service client")
 	}
-	return &AuthServiceImpl{repo: repo, tokenExpireMinutes: minutes, userClient: client}
+	return &AuthServiceImpl{repo: repo, sessions: sessions, impersonations: impersonations, tokenExpireMinutes: minutes, userClient: client, policy: LoadPasswordPolicy()}
 }
 
 func (s *AuthServiceImpl) Register(userID uuid.UUID, rawPassword string) error {
@@ -106,6 +131,9 @@ func (s *AuthServiceImpl) Register(userID uuid.UUID, rawPassword string) error {
 	if err == nil { // 已存在
 		return errors.New("auth record already exists")
 	}
+	if violations := s.policy.Check(context.Background(), rawPassword, identities(resp.User)); len(violations) > 0 {
+		return &PasswordPolicyError{Violations: violations}
+	}
 	hash, err := bcrypt.GenerateFromPassword([]byte(rawPassword), bcrypt.DefaultCost)
 	if err != nil {
 		return err
@@ -114,33 +142,111 @@ func (s *AuthServiceImpl) Register(userID uuid.UUID, rawPassword string) error {
 	return s.repo.Create(entity)
 }
 
-func (s *AuthServiceImpl) Login(userID uuid.UUID, rawPassword string) (string, error) {
+func (s *AuthServiceImpl) Login(userID uuid.UUID, rawPassword string, device Device) (*LoginResult, error) {
 	authRec, err := s.getByUserID(userID)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 	if bcrypt.CompareHashAndPassword([]byte(authRec.Password), []byte(rawPassword)) != nil {
-		return "", errors.New("invalid credentials")
+		return nil, errors.New("invalid credentials")
+	}
+	now := time.Now()
+	// 顺带清理已过期的会话，保留一天便于排查
+	if err := s.sessions.DeleteExpired(userID, now.Add(-24*time.Hour)); err != nil {
+		log.Printf("delete expired sessions of user %s failed: %v", userID, err)
 	}
-	token, err := utils.GenerateToken(authRec.UserID.String(), s.tokenExpireMinutes)
+	session := &models.Session{
+		UserID:     userID,
+		UserAgent:  truncate(device.UserAgent, 512),
+		IP:         truncate(device.IP, 64),
+		LastSeenAt: now,
+		ExpiresAt:  now.Add(time.Duration(s.tokenExpireMinutes) * time.Minute),
+	}
+	if err := s.sessions.Create(session); err != nil {
+		return nil, err
+	}
+	token, err := utils.GenerateToken(authRec.UserID.String(), session.ID.String(), "", now, session.ExpiresAt)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	return token, nil
+	return &LoginResult{Token: token, SessionID: session.ID, PasswordChangeRequired: authRec.PasswordTemporary}, nil
 }
 
-func (s *AuthServiceImpl) ValidateToken(token string) (uuid.UUID, error) {
+func (s *AuthServiceImpl) ValidateToken(token, ip string) (*TokenIdentity, error) {
 	claims, err := utils.ParseToken(token)
 	if err != nil {
-		return uuid.Nil, err
+		return nil, err
 	}
 	id, err := uuid.Parse(claims.UserID)
 	if err != nil {
-		return uuid.Nil, err
+		return nil, err
+	}
+	// 吊销检查：修改密码前签发的 token 失效
+	authRec, err := s.getByUserID(id)
+	if err != nil {
+		return nil, errors.New("token user not found")
+	}
+	if authRec.TokensValidAfter != nil && (claims.IssuedAt == nil || claims.IssuedAt.Before(*authRec.TokensValidAfter)) {
+		return nil, errors.New("token revoked")
+	}
+	// 早于会话管理签发的 token 没有 jti，只做上面的检查
+	if claims.ID == "" {
+		if claims.Impersonator != "" {
+			return nil, errors.New("invalid session")
+		}
+		return &TokenIdentity{UserID: id}, nil
+	}
+	sessionID, err := uuid.Parse(claims.ID)
+	if err != nil {
+		return nil, errors.New("invalid session")
+	}
+	session, err := s.sessions.GetByID(sessionID)
+	if err != nil || session.UserID != id || session.RevokedAt != nil {
+		return nil, errors.New("session revoked")
+	}
+	identity := &TokenIdentity{UserID: id, SessionID: sessionID}
+	// 代登录身份以会话记录为准，与 token 声明不一致时拒绝
+	if session.ImpersonatorID != nil {
+		identity.ImpersonatorID = *session.ImpersonatorID
+	}
+	if claims.Impersonator != uuidString(identity.ImpersonatorID) {
+		return nil, errors.New("invalid session")
+	}
+	if now := time.Now(); now.Sub(session.LastSeenAt) >= sessionTouchInterval {
+		if err := s.sessions.Touch(sessionID, truncate(ip, 64), now); err != nil {
+			log.Printf("touch session %s failed: %v", sessionID, err)
+		}
+	}
+	return identity, nil
+}
+
+// uuidString uuid.Nil 返回空串
+func uuidString(id uuid.UUID) string {
+	if id == uuid.Nil {
+		return ""
+	}
+	return id.String()
+}
+
+func (s *AuthServiceImpl) ListSessions(userID uuid.UUID) ([]*models.Session, error) {
+	return s.sessions.ListActive(userID, time.Now())
+}
+
+func (s *AuthServiceImpl) RevokeSession(userID, sessionID uuid.UUID) error {
+	err := s.sessions.Revoke(userID, sessionID, time.Now())
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrSessionNotFound
 	}
-	return id, nil
+	return err
+}
+
+func (s *AuthServiceImpl) RevokeOtherSessions(userID, keep uuid.UUID) (int64, error) {
+	return s.sessions.RevokeAllExcept(userID, keep, time.Now())
 }
 
+// JWKS 本地验签使用的公钥
+func (s *AuthServiceImpl) JWKS() ([]utils.JWK, error) { return utils.PublicJWKs() }
+
 func (s *AuthServiceImpl) CheckPassword(userID uuid.UUID, rawPassword string) (bool, error) {
 	authRec, err := s.getByUserID(userID)
 	if err != nil {
@@ -153,11 +259,60 @@ func (s *AuthServiceImpl) CheckPassword(userID uuid.UUID, rawPassword string) (b
 }
 
 func (s *AuthServiceImpl) UpdatePassword(userID uuid.UUID, newPassword string) error {
+	authRec, err := s.getByUserID(userID)
+	if err != nil {
+		return err
+	}
+	var ids []string
+	if s.userClient != nil {
+		if resp, err := s.userClient.GetUserByID(context.Background(), &user.GetUserByIDRequest{Id: userID.String()}); err == nil && resp.Found {
+			ids = identities(resp.User)
+		}
+	}
+	violations := s.policy.Check(context.Background(), newPassword, ids)
+	if bcrypt.CompareHashAndPassword([]byte(authRec.Password), []byte(newPassword)) == nil {
+		violations = append(violations, Violation{Code: ViolationReused, Message: "new password must differ from the current password"})
+	}
+	if len(violations) > 0 {
+		return &PasswordPolicyError{Violations: violations}
+	}
 	hash, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
 	if err != nil {
 		return err
 	}
-	return s.repo.UpdatePassword(userID, string(hash))
+	if err := s.repo.UpdatePassword(userID, string(hash)); err != nil {
+		return err
+	}
+	// 此前签发的 token 已由 tokens_valid_after 吊销，同步注销会话使设备列表保持一致
+	if _, err := s.sessions.RevokeAllExcept(userID, uuid.Nil, time.Now()); err != nil {
+		log.Printf("revoke sessions of user %s after password change failed: %v", userID, err)
+	}
+	return nil
+}
+
+func (s *AuthServiceImpl) CheckPasswordPolicy(ctx context.Context, password string, identities []string) []Violation {
+	return s.policy.Check(ctx, password, identities)
+}
+
+func (s *AuthServiceImpl) PasswordPolicy() *PasswordPolicy { return s.policy }
+
+// identities 密码中不应包含的用户信息
+func identities(u *user.UserInfo) []string {
+	if u == nil {
+		return nil
+	}
+	return []string{u.Username, u.Email}
+}
+
+// truncate 按字节截断到 max，不截断多字节字符
+func truncate(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	for max > 0 && !utf8.RuneStart(s[max]) {
+		max--
+	}
+	return s[:max]
 }
 
 // internal helper
@@ -0,0 +1,158 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/RigelNana/arkstudy/proto/user"
+	"github.com/RigelNana/arkstudy/services/auth-service/models"
+	"github.com/RigelNana/arkstudy/services/auth-service/utils"
+
+	"github.com/google/uuid"
+)
+
+// RoleAdmin user-service 中管理员的角色
+const RoleAdmin = "admin"
+
+// 代登录原因的最短长度，要求写明工单号或问题描述
+const minImpersonationReason = 10
+
+// ErrPermissionDenied 非管理员发起代登录或查询审计
+var ErrPermissionDenied = errors.New("permission denied")
+
+// ImpersonationGrant 代登录签发结果
+type ImpersonationGrant struct {
+	Token     string
+	SessionID uuid.UUID
+	ExpiresAt time.Time
+	AuditID   uuid.UUID
+}
+
+// ImpersonationRecord 审计记录及其会话是否已被提前注销
+type ImpersonationRecord struct {
+	*models.Impersonation
+	Revoked bool
+}
+
+// impersonationLimits 代登录 token 时长，由 IMPERSONATION_DEFAULT_MINUTES（默认 15）、
+// IMPERSONATION_MAX_MINUTES（默认 30）配置
+func impersonationLimits() (time.Duration, time.Duration) {
+	def, max := envInt("IMPERSONATION_DEFAULT_MINUTES", 15), envInt("IMPERSONATION_MAX_MINUTES", 30)
+	if max <= 0 {
+		max = 30
+	}
+	if def <= 0 || def > max {
+		def = max
+	}
+	return time.Duration(def) * time.Minute, time.Duration(max) * time.Minute
+}
+
+// ImpersonateUser 校验 adminID 为管理员后以 targetID 的身份签发限时 token。
+// 代登录会话出现在目标用户的设备列表中，可被用户或管理员注销；审计记录写入失败时不签发
+func (s *AuthServiceImpl) ImpersonateUser(ctx context.Context, adminID, targetID uuid.UUID, reason string, minutes int, device Device) (*ImpersonationGrant, error) {
+	reason = strings.TrimSpace(reason)
+	if utf8.RuneCountInString(reason) < minImpersonationReason {
+		return nil, fmt.Errorf("reason must be at least %d characters", minImpersonationReason)
+	}
+	if adminID == targetID {
+		return nil, errors.New("cannot impersonate yourself")
+	}
+	if err := s.requireAdmin(ctx, adminID); err != nil {
+		log.Printf("impersonation audit: denied user %s impersonating %s: %v", adminID, targetID, err)
+		return nil, err
+	}
+	target, err := s.userClient.GetUserByID(ctx, &user.GetUserByIDRequest{Id: targetID.String()})
+	if err != nil {
+		return nil, errors.New("error calling user-service: " + err.Error())
+	}
+	if !target.Found {
+		return nil, errors.New("target user not found")
+	}
+	if target.User.GetRole() == RoleAdmin {
+		return nil, fmt.Errorf("%w: cannot impersonate an administrator", ErrPermissionDenied)
+	}
+
+	def, max := impersonationLimits()
+	ttl := def
+	if minutes > 0 {
+		ttl = min(time.Duration(minutes)*time.Minute, max)
+	}
+	now := time.Now()
+	session := &models.Session{
+		UserID:         targetID,
+		UserAgent:      truncate(device.UserAgent, 512),
+		IP:             truncate(device.IP, 64),
+		LastSeenAt:     now,
+		ExpiresAt:      now.Add(ttl),
+		ImpersonatorID: &adminID,
+	}
+	if err := s.sessions.Create(session); err != nil {
+		return nil, err
+	}
+	audit := &models.Impersonation{
+		ImpersonatorID: adminID,
+		TargetUserID:   targetID,
+		SessionID:      session.ID,
+		Reason:         reason,
+		IP:             session.IP,
+		UserAgent:      session.UserAgent,
+		ExpiresAt:      session.ExpiresAt,
+	}
+	if err := s.impersonations.Create(audit); err != nil {
+		if revokeErr := s.sessions.Revoke(targetID, session.ID, now); revokeErr != nil {
+			log.Printf("revoke unaudited impersonation session %s failed: %v", session.ID, revokeErr)
+		}
+		return nil, fmt.Errorf("write impersonation audit: %w", err)
+	}
+	token, err := utils.GenerateToken(targetID.String(), session.ID.String(), adminID.String(), now, session.ExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("impersonation audit: admin %s impersonating user %s, session=%s expires=%s reason=%q",
+		adminID, targetID, session.ID, session.ExpiresAt.Format(time.RFC3339), reason)
+	return &ImpersonationGrant{Token: token, SessionID: session.ID, ExpiresAt: session.ExpiresAt, AuditID: audit.ID}, nil
+}
+
+// ListImpersonations 查询代登录审计记录，仅管理员可用
+func (s *AuthServiceImpl) ListImpersonations(ctx context.Context, adminID, targetID, impersonatorID uuid.UUID, limit int) ([]ImpersonationRecord, error) {
+	if err := s.requireAdmin(ctx, adminID); err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = 50
+	}
+	limit = min(limit, 200)
+	records, err := s.impersonations.Search(targetID, impersonatorID, limit)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]ImpersonationRecord, 0, len(records))
+	for _, r := range records {
+		rec := ImpersonationRecord{Impersonation: r}
+		if sess, err := s.sessions.GetByID(r.SessionID); err == nil && sess.RevokedAt != nil {
+			rec.Revoked = true
+		}
+		out = append(out, rec)
+	}
+	return out, nil
+}
+
+// requireAdmin 通过 user-service 确认用户为管理员
+func (s *AuthServiceImpl) requireAdmin(ctx context.Context, userID uuid.UUID) error {
+	if s.userClient == nil {
+		return errors.New("user-service client not initialized")
+	}
+	resp, err := s.userClient.GetUserByID(ctx, &user.GetUserByIDRequest{Id: userID.String()})
+	if err != nil {
+		return errors.New("error calling user-service: " + err.Error())
+	}
+	if !resp.Found || resp.User.GetRole() != RoleAdmin {
+		return ErrPermissionDenied
+	}
+	return nil
+}
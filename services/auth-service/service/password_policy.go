@@ -0,0 +1,256 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// 密码不满足策略的原因
+const (
+	ViolationTooShort         = "too_short"
+	ViolationTooLong          = "too_long"
+	ViolationMissingLetter    = "missing_letter"
+	ViolationMissingLower     = "missing_lower"
+	ViolationMissingUpper     = "missing_upper"
+	ViolationMissingDigit     = "missing_digit"
+	ViolationMissingSymbol    = "missing_symbol"
+	ViolationContainsIdentity = "contains_identity"
+	ViolationBreached         = "breached"
+	ViolationReused           = "reused"
+)
+
+// 字符类别
+const (
+	ClassLetter = "letter"
+	ClassLower  = "lower"
+	ClassUpper  = "upper"
+	ClassDigit  = "digit"
+	ClassSymbol = "symbol"
+)
+
+// bcrypt 只使用密码的前 72 字节，更长的部分不参与校验
+const bcryptMaxBytes = 72
+
+// 身份信息（用户名、邮箱前缀）短于该长度时不检查密码是否包含它
+const minIdentityLen = 4
+
+var classViolations = map[string]Violation{
+	ClassLetter: {Code: ViolationMissingLetter, Message: "password must contain a letter"},
+	ClassLower:  {Code: ViolationMissingLower, Message: "password must contain a lowercase letter"},
+	ClassUpper:  {Code: ViolationMissingUpper, Message: "password must contain an uppercase letter"},
+	ClassDigit:  {Code: ViolationMissingDigit, Message: "password must contain a digit"},
+	ClassSymbol: {Code: ViolationMissingSymbol, Message: "password must contain a symbol"},
+}
+
+// Violation 密码不满足策略的一项原因
+type Violation struct {
+	Code    string
+	Message string
+}
+
+// PasswordPolicyError 密码不满足策略，Violations 提供逐项原因
+type PasswordPolicyError struct {
+	Violations []Violation
+}
+
+func (e *PasswordPolicyError) Error() string {
+	return "password does not meet policy"
+}
+
+// PasswordPolicy 密码策略，由环境变量配置（见 LoadPasswordPolicy）
+type PasswordPolicy struct {
+	MinLength       int
+	MaxLength       int
+	RequiredClasses []string
+	// BreachCheck 通过 k-anonymity 接口检查密码是否出现在已泄露密码库中：只发送 SHA-1 的前 5 位，
+	// 在返回的后缀列表中本地比对。接口不可用时放行，不阻塞注册
+	BreachCheck    bool
+	BreachAPI      string
+	BreachMinCount int
+	httpClient     *http.Client
+}
+
+// LoadPasswordPolicy 从环境变量读取密码策略：
+// PASSWORD_MIN_LENGTH（默认 8）、PASSWORD_MAX_LENGTH（默认 72）、
+// PASSWORD_REQUIRED_CLASSES（逗号分隔的 letter/lower/upper/digit/symbol，默认 letter,digit）、
+// PASSWORD_BREACH_CHECK（默认 true）、PASSWORD_BREACH_API（默认 Have I Been Pwned 的 range 接口）、
+// PASSWORD_BREACH_MIN_COUNT（泄露次数达到该值才拒绝，默认 1）
+func LoadPasswordPolicy() *PasswordPolicy {
+	p := &PasswordPolicy{
+		MinLength:      envInt("PASSWORD_MIN_LENGTH", 8),
+		MaxLength:      envInt("PASSWORD_MAX_LENGTH", bcryptMaxBytes),
+		BreachCheck:    os.Getenv("PASSWORD_BREACH_CHECK") != "false",
+		BreachAPI:      os.Getenv("PASSWORD_BREACH_API"),
+		BreachMinCount: envInt("PASSWORD_BREACH_MIN_COUNT", 1),
+		httpClient:     &http.Client{Timeout: 3 * time.Second},
+	}
+	if p.BreachAPI == "" {
+		p.BreachAPI = "https://api.pwnedpasswords.com/range/"
+	}
+	if p.MaxLength <= 0 || p.MaxLength > bcryptMaxBytes {
+		p.MaxLength = bcryptMaxBytes
+	}
+	if p.MinLength < 1 || p.MinLength > p.MaxLength {
+		log.Printf("invalid PASSWORD_MIN_LENGTH=%d, using 8", p.MinLength)
+		p.MinLength = 8
+	}
+
+	classes := os.Getenv("PASSWORD_REQUIRED_CLASSES")
+	if classes == "" {
+		classes = ClassLetter + "," + ClassDigit
+	}
+	for _, c := range strings.Split(classes, ",") {
+		c = strings.TrimSpace(strings.ToLower(c))
+		if c == "" || c == "none" {
+			continue
+		}
+		if _, ok := classViolations[c]; !ok {
+			log.Printf("ignoring unknown password class %q in PASSWORD_REQUIRED_CLASSES", c)
+			continue
+		}
+		p.RequiredClasses = append(p.RequiredClasses, c)
+	}
+	return p
+}
+
+// Check 返回 password 违反的全部策略项，identities 为用户名、邮箱等不应出现在密码中的信息
+func (p *PasswordPolicy) Check(ctx context.Context, password string, identities []string) []Violation {
+	var violations []Violation
+	if n := utf8.RuneCountInString(password); n < p.MinLength {
+		violations = append(violations, Violation{Code: ViolationTooShort, Message: fmt.Sprintf("password must be at least %d characters", p.MinLength)})
+	}
+	if len(password) > p.MaxLength {
+		violations = append(violations, Violation{Code: ViolationTooLong, Message: fmt.Sprintf("password must be at most %d bytes", p.MaxLength)})
+	}
+
+	present := passwordClasses(password)
+	for _, c := range p.RequiredClasses {
+		if !present[c] {
+			violations = append(violations, classViolations[c])
+		}
+	}
+
+	lower := strings.ToLower(password)
+	for _, id := range identities {
+		// 邮箱只比对 @ 之前的部分
+		id, _, _ = strings.Cut(strings.ToLower(strings.TrimSpace(id)), "@")
+		if utf8.RuneCountInString(id) >= minIdentityLen && strings.Contains(lower, id) {
+			violations = append(violations, Violation{Code: ViolationContainsIdentity, Message: "password must not contain your username or email"})
+			break
+		}
+	}
+
+	// 其余规则已不满足时无需再请求外部接口
+	if len(violations) == 0 && p.BreachCheck {
+		if count, err := p.breachCount(ctx, password); err != nil {
+			log.Printf("Warning: breached password check unavailable: %v", err)
+		} else if count >= p.BreachMinCount {
+			violations = append(violations, Violation{Code: ViolationBreached, Message: "password has appeared in a data breach, choose a different one"})
+		}
+	}
+	return violations
+}
+
+// Strength 粗略估计密码强度：0（很弱）~ 4（很强），只依据长度与字符类别
+func (p *PasswordPolicy) Strength(password string) int {
+	n := utf8.RuneCountInString(password)
+	if n < p.MinLength {
+		return 0
+	}
+	present := passwordClasses(password)
+	kinds := 0
+	for _, c := range []string{ClassLower, ClassUpper, ClassDigit, ClassSymbol} {
+		if present[c] {
+			kinds++
+		}
+	}
+	score := 1
+	if kinds >= 3 {
+		score++
+	}
+	if n >= 12 {
+		score++
+	}
+	if n >= 16 && kinds >= 2 {
+		score++
+	}
+	return score
+}
+
+// breachCount 查询密码在泄露库中出现的次数
+func (p *PasswordPolicy) breachCount(ctx context.Context, password string) (int, error) {
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+	prefix, suffix := hash[:5], hash[5:]
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.BreachAPI+prefix, nil)
+	if err != nil {
+		return 0, err
+	}
+	// 填充响应，避免通过响应大小推断前缀
+	req.Header.Set("Add-Padding", "true")
+	req.Header.Set("User-Agent", "arkstudy-auth-service")
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		s, countStr, ok := strings.Cut(strings.TrimSpace(scanner.Text()), ":")
+		if !ok || !strings.EqualFold(s, suffix) {
+			continue
+		}
+		// 填充项的次数为 0
+		count, err := strconv.Atoi(countStr)
+		if err != nil {
+			return 0, fmt.Errorf("bad count %q", countStr)
+		}
+		return count, nil
+	}
+	return 0, scanner.Err()
+}
+
+func passwordClasses(password string) map[string]bool {
+	present := map[string]bool{}
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			present[ClassLower], present[ClassLetter] = true, true
+		case unicode.IsUpper(r):
+			present[ClassUpper], present[ClassLetter] = true, true
+		case unicode.IsLetter(r):
+			present[ClassLetter] = true
+		case unicode.IsDigit(r):
+			present[ClassDigit] = true
+		default:
+			present[ClassSymbol] = true
+		}
+	}
+	return present
+}
+
+func envInt(key string, defaultValue int) int {
+	if v := os.Getenv(key); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			return n
+		}
+		log.Printf("invalid %s=%q, using %d", key, v, defaultValue)
+	}
+	return defaultValue
+}
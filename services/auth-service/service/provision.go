@@ -0,0 +1,238 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"net/mail"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/RigelNana/arkstudy/proto/user"
+	"github.com/RigelNana/arkstudy/services/auth-service/models"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// 批量开通的单个账号状态
+const (
+	ProvisionCreated = "created"
+	ProvisionValid   = "valid" // dry_run 校验通过
+	ProvisionExists  = "exists"
+	ProvisionInvalid = "invalid"
+	ProvisionFailed  = "failed"
+)
+
+// maxProvisionBatch 单次批量开通的账号上限，每个账号需要一次 bcrypt 与两次 user-service 调用
+const maxProvisionBatch = 500
+
+// 临时密码长度下限，实际长度不低于密码策略的最短长度
+const temporaryPasswordLen = 16
+
+// 临时密码字符集，去掉了易混淆的 0/O、1/l/I
+const (
+	tempLower  = "abcdefghijkmnopqrstuvwxyz"
+	tempUpper  = "ABCDEFGHJKLMNPQRSTUVWXYZ"
+	tempDigit  = "23456789"
+	tempSymbol = "!@#$%^&*-_=+?"
+)
+
+// provisionRoles 可批量开通的角色，管理员只能单独设置
+var provisionRoles = map[string]bool{"student": true, "teacher": true}
+
+// ProvisionRequest 待开通的账号，Role 为空时为 student
+type ProvisionRequest struct {
+	Username    string
+	Email       string
+	Role        string
+	Description string
+}
+
+// ProvisionResult 单个账号的开通结果，TemporaryPassword 仅 Status 为 created 时非空
+type ProvisionResult struct {
+	Username          string
+	Email             string
+	Status            string
+	Message           string
+	UserID            uuid.UUID
+	TemporaryPassword string
+}
+
+// ProvisionUsers 校验 adminID 为管理员后逐个开通账号：先校验全部行（必填项、批内重复、已存在），
+// 再依次创建 user-service 用户与认证记录。认证记录写入失败时删除刚创建的用户，
+// 单个账号失败不影响其他账号。临时密码由系统生成，用户首次登录后应修改
+func (s *AuthServiceImpl) ProvisionUsers(ctx context.Context, adminID uuid.UUID, reqs []ProvisionRequest, dryRun bool) ([]ProvisionResult, error) {
+	if len(reqs) == 0 {
+		return nil, errors.New("no users to provision")
+	}
+	if len(reqs) > maxProvisionBatch {
+		return nil, fmt.Errorf("at most %d users per batch", maxProvisionBatch)
+	}
+	if err := s.requireAdmin(ctx, adminID); err != nil {
+		log.Printf("provision audit: denied user %s provisioning %d users: %v", adminID, len(reqs), err)
+		return nil, err
+	}
+
+	results := make([]ProvisionResult, len(reqs))
+	seenUsernames, seenEmails := map[string]bool{}, map[string]bool{}
+	for i, r := range reqs {
+		r.Username, r.Email = strings.TrimSpace(r.Username), strings.TrimSpace(r.Email)
+		r.Role = strings.ToLower(strings.TrimSpace(r.Role))
+		if r.Role == "" {
+			r.Role = "student"
+		}
+		reqs[i] = r
+		res := &results[i]
+		res.Username, res.Email = r.Username, r.Email
+		if msg := validateProvisionRequest(r); msg != "" {
+			res.Status, res.Message = ProvisionInvalid, msg
+			continue
+		}
+		if seenUsernames[strings.ToLower(r.Username)] {
+			res.Status, res.Message = ProvisionInvalid, "duplicate username in batch"
+			continue
+		}
+		if seenEmails[strings.ToLower(r.Email)] {
+			res.Status, res.Message = ProvisionInvalid, "duplicate email in batch"
+			continue
+		}
+		seenUsernames[strings.ToLower(r.Username)], seenEmails[strings.ToLower(r.Email)] = true, true
+		if msg, err := s.provisionConflict(ctx, r); err != nil {
+			res.Status, res.Message = ProvisionFailed, err.Error()
+			continue
+		} else if msg != "" {
+			res.Status, res.Message = ProvisionExists, msg
+			continue
+		}
+		res.Status = ProvisionValid
+	}
+	if dryRun {
+		return results, nil
+	}
+
+	created := 0
+	for i, r := range reqs {
+		res := &results[i]
+		if res.Status != ProvisionValid {
+			continue
+		}
+		userID, password, err := s.provisionOne(ctx, r)
+		if err != nil {
+			res.Status, res.Message = ProvisionFailed, err.Error()
+			continue
+		}
+		res.Status, res.UserID, res.TemporaryPassword = ProvisionCreated, userID, password
+		created++
+	}
+	log.Printf("provision audit: admin %s provisioned %d of %d users", adminID, created, len(reqs))
+	return results, nil
+}
+
+// validateProvisionRequest 返回不合法的原因，合法时为空
+func validateProvisionRequest(r ProvisionRequest) string {
+	switch {
+	case r.Username == "":
+		return "username is required"
+	case utf8.RuneCountInString(r.Username) > 64:
+		return "username must be at most 64 characters"
+	case r.Email == "":
+		return "email is required"
+	case !provisionRoles[r.Role]:
+		return fmt.Sprintf("role %q cannot be provisioned", r.Role)
+	}
+	if addr, err := mail.ParseAddress(r.Email); err != nil || addr.Address != r.Email {
+		return "invalid email"
+	}
+	return ""
+}
+
+// provisionConflict 用户名或邮箱已被占用时返回原因
+func (s *AuthServiceImpl) provisionConflict(ctx context.Context, r ProvisionRequest) (string, error) {
+	byName, err := s.userClient.GetUserByUsername(ctx, &user.GetUserByUsernameRequest{Username: r.Username})
+	if err != nil {
+		return "", errors.New("error calling user-service: " + err.Error())
+	}
+	if byName.Found {
+		return "username already exists", nil
+	}
+	byEmail, err := s.userClient.GetUserByEmail(ctx, &user.GetUserByEmailRequest{Email: r.Email})
+	if err != nil {
+		return "", errors.New("error calling user-service: " + err.Error())
+	}
+	if byEmail.Found {
+		return "email already exists", nil
+	}
+	return "", nil
+}
+
+// provisionOne 创建用户与带临时密码的认证记录，后者失败时删除前者
+func (s *AuthServiceImpl) provisionOne(ctx context.Context, r ProvisionRequest) (uuid.UUID, string, error) {
+	password, err := s.temporaryPassword()
+	if err != nil {
+		return uuid.Nil, "", err
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return uuid.Nil, "", err
+	}
+	cu, err := s.userClient.CreateUser(ctx, &user.CreateUserRequest{Username: r.Username, Email: r.Email, Role: r.Role, Description: r.Description})
+	if err != nil {
+		return uuid.Nil, "", errors.New("error calling user-service: " + err.Error())
+	}
+	if !cu.Success {
+		return uuid.Nil, "", errors.New("create user failed: " + cu.Message)
+	}
+	userID, err := uuid.Parse(cu.User.GetId())
+	if err != nil {
+		return uuid.Nil, "", errors.New("user-service returned invalid user id")
+	}
+	if err := s.repo.Create(&models.Auth{UserID: userID, Password: string(hash), PasswordTemporary: true}); err != nil {
+		// 回滚已创建的用户，避免留下无法登录的账号
+		if resp, delErr := s.userClient.DeleteUser(context.WithoutCancel(ctx), &user.DeleteUserRequest{Id: userID.String()}); delErr != nil || !resp.Success {
+			log.Printf("rollback provisioned user %s (%s) failed: err=%v message=%s", userID, r.Username, delErr, resp.GetMessage())
+		}
+		return uuid.Nil, "", fmt.Errorf("create auth record: %w", err)
+	}
+	return userID, password, nil
+}
+
+// temporaryPassword 生成包含大小写字母、数字与符号的随机密码，满足任意字符类别要求；
+// 随机生成的密码不会出现在泄露库中，因此不做泄露检查
+func (s *AuthServiceImpl) temporaryPassword() (string, error) {
+	n := min(max(temporaryPasswordLen, s.policy.MinLength), s.policy.MaxLength)
+	sets := []string{tempLower, tempUpper, tempDigit, tempSymbol}
+	all := strings.Join(sets, "")
+	buf := make([]byte, n)
+	for i := range buf {
+		set := all
+		if i < len(sets) {
+			set = sets[i]
+		}
+		c, err := randomChar(set)
+		if err != nil {
+			return "", err
+		}
+		buf[i] = c
+	}
+	// 打乱顺序，避免前几位的类别固定
+	for i := len(buf) - 1; i > 0; i-- {
+		j, err := rand.Int(rand.Reader, big.NewInt(int64(i+1)))
+		if err != nil {
+			return "", err
+		}
+		buf[i], buf[j.Int64()] = buf[j.Int64()], buf[i]
+	}
+	return string(buf), nil
+}
+
+func randomChar(set string) (byte, error) {
+	i, err := rand.Int(rand.Reader, big.NewInt(int64(len(set))))
+	if err != nil {
+		return 0, err
+	}
+	return set[i.Int64()], nil
+}
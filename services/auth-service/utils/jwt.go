@@ -1,8 +1,19 @@
 package utils
 
 import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
 	"errors"
+	"fmt"
+	"math/big"
 	"os"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -10,34 +21,143 @@ import (
 
 type Claims struct {
 	UserID string `json:"user_id"`
+	// Impersonator 管理员代登录时为管理员的 user_id
+	Impersonator string `json:"impersonator,omitempty"`
 	jwt.RegisteredClaims
 }
 
-func GenerateToken(userID string, minutes int) (string, error) {
-	secret := os.Getenv("JWT_SECRET")
-	if secret == "" {
-		return "", errors.New("missing JWT_SECRET env")
+// JWK 公钥的 JSON Web Key 表示（RFC 7517），只包含验签需要的字段
+type JWK struct {
+	Kid string
+	Kty string // OKP（Ed25519）| RSA
+	Alg string // EdDSA | RS256
+	Crv string // OKP 的曲线，固定为 Ed25519
+	X   string // OKP 公钥，base64url
+	N   string // RSA 模数，base64url
+	E   string // RSA 指数，base64url
+}
+
+// signingKey 非对称签名密钥，配置后签发的 token 带 kid 头，gateway 可通过 JWKS 在本地验签
+type signingKey struct {
+	kid    string
+	method jwt.SigningMethod
+	key    crypto.Signer
+}
+
+var (
+	signingKeyOnce sync.Once
+	currentKey     *signingKey
+	signingKeyErr  error
+)
+
+// loadSigningKey 读取 JWT_SIGNING_KEY（PEM）或 JWT_SIGNING_KEY_FILE 指定的 PKCS#8 私钥，支持 Ed25519 与 RSA。
+// 未配置时返回 nil，继续使用 JWT_SECRET 的 HS256 签名（gateway 只能通过 ValidateToken 远程校验）
+func loadSigningKey() (*signingKey, error) {
+	signingKeyOnce.Do(func() {
+		raw := os.Getenv("JWT_SIGNING_KEY")
+		if raw == "" {
+			path := os.Getenv("JWT_SIGNING_KEY_FILE")
+			if path == "" {
+				return
+			}
+			b, err := os.ReadFile(path)
+			if err != nil {
+				signingKeyErr = fmt.Errorf("read JWT_SIGNING_KEY_FILE: %w", err)
+				return
+			}
+			raw = string(b)
+		}
+		currentKey, signingKeyErr = parseSigningKey([]byte(strings.ReplaceAll(raw, `\n`, "\n")))
+	})
+	return currentKey, signingKeyErr
+}
+
+func parseSigningKey(pemBytes []byte) (*signingKey, error) {
+	block, _ := pem.Decode(pemBytes)
+	if block == nil {
+		return nil, errors.New("JWT signing key is not PEM encoded")
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parse JWT signing key: %w", err)
+	}
+	var method jwt.SigningMethod
+	var signer crypto.Signer
+	switch k := parsed.(type) {
+	case ed25519.PrivateKey:
+		method, signer = jwt.SigningMethodEdDSA, k
+	case *rsa.PrivateKey:
+		if k.N.BitLen() < 2048 {
+			return nil, errors.New("RSA JWT signing key must be at least 2048 bits")
+		}
+		method, signer = jwt.SigningMethodRS256, k
+	default:
+		return nil, fmt.Errorf("unsupported JWT signing key type %T", parsed)
+	}
+	der, err := x509.MarshalPKIXPublicKey(signer.Public())
+	if err != nil {
+		return nil, err
 	}
-	exp := time.Now().Add(time.Duration(minutes) * time.Minute)
+	// kid 取公钥摘要，轮换密钥后自然变化
+	sum := sha256.Sum256(der)
+	return &signingKey{kid: base64.RawURLEncoding.EncodeToString(sum[:12]), method: method, key: signer}, nil
+}
+
+// ValidateSigningKey 启动时校验签名密钥配置，配置错误时直接退出而不是签发失败
+func ValidateSigningKey() error {
+	_, err := loadSigningKey()
+	return err
+}
+
+// GenerateToken 签发 token，sessionID 写入 jti，用于按设备注销；impersonator 非空时为代登录 token
+func GenerateToken(userID, sessionID, impersonator string, issuedAt, expiresAt time.Time) (string, error) {
 	claims := Claims{
-		UserID: userID,
+		UserID:       userID,
+		Impersonator: impersonator,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(exp),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ID:        sessionID,
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(issuedAt),
 		},
 	}
+
+	key, err := loadSigningKey()
+	if err != nil {
+		return "", err
+	}
+	if key != nil {
+		token := jwt.NewWithClaims(key.method, claims)
+		token.Header["kid"] = key.kid
+		return token.SignedString(key.key)
+	}
+
+	secret := os.Getenv("JWT_SECRET")
+	if secret == "" {
+		return "", errors.New("missing JWT_SECRET env")
+	}
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	return token.SignedString([]byte(secret))
 }
 
+// ParseToken 校验签名与有效期。配置签名密钥后仍接受 JWT_SECRET 签发的 HS256 token，直至其过期
 func ParseToken(tokenStr string) (*Claims, error) {
-	secret := os.Getenv("JWT_SECRET")
-	if secret == "" {
-		return nil, errors.New("missing JWT_SECRET env")
+	key, err := loadSigningKey()
+	if err != nil {
+		return nil, err
 	}
+	secret := os.Getenv("JWT_SECRET")
 	token, err := jwt.ParseWithClaims(tokenStr, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-		return []byte(secret), nil
-	})
+		if token.Method == jwt.SigningMethodHS256 {
+			if secret == "" {
+				return nil, errors.New("missing JWT_SECRET env")
+			}
+			return []byte(secret), nil
+		}
+		if key == nil || token.Method != key.method || token.Header["kid"] != key.kid {
+			return nil, errors.New("unknown signing key")
+		}
+		return key.key.Public(), nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg(), jwt.SigningMethodEdDSA.Alg(), jwt.SigningMethodRS256.Alg()}))
 	if err != nil {
 		return nil, err
 	}
@@ -46,3 +166,21 @@ func ParseToken(tokenStr string) (*Claims, error) {
 	}
 	return nil, errors.New("invalid token")
 }
+
+// PublicJWKs 返回验签公钥，未配置签名密钥时为空
+func PublicJWKs() ([]JWK, error) {
+	key, err := loadSigningKey()
+	if err != nil || key == nil {
+		return nil, err
+	}
+	jwk := JWK{Kid: key.kid, Alg: key.method.Alg()}
+	switch pub := key.key.Public().(type) {
+	case ed25519.PublicKey:
+		jwk.Kty, jwk.Crv, jwk.X = "OKP", "Ed25519", base64.RawURLEncoding.EncodeToString(pub)
+	case *rsa.PublicKey:
+		jwk.Kty = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+	}
+	return []JWK{jwk}, nil
+}
@@ -32,10 +32,63 @@ class Settings:
     openai_model: str | None = os.getenv("OPENAI_MODEL") or os.getenv("LLM_OPENAI_MODEL")
     # Embedding model (e.g., text-embedding-3-small/large, bge-m3, jina-embeddings, etc.)
     openai_embedding_model: str | None = os.getenv("OPENAI_EMBEDDING_MODEL") or os.getenv("LLM_OPENAI_EMBEDDING_MODEL")
+    # Per-language embedding models, e.g. "zh=bge-m3,ja=bge-m3". Chunks are tagged with the material's detected
+    # language (metadata "language", ISO 639-1); untagged chunks and unlisted languages use openai_embedding_model.
+    # All models must produce vectors of vector_dim.
+    embedding_models: str = os.getenv("LLM_EMBEDDING_MODELS", "")
 
     # Vector dimension for pgvector (must match the embedding model). Default 128 for built-in embedder.
     vector_dim: int = int(os.getenv("LLM_VECTOR_DIM", "128"))
 
+    # Answer cache for repeated questions (process-local, see app/services/answer_cache.py)
+    answer_cache_enabled: bool = os.getenv("LLM_ANSWER_CACHE_ENABLED", "true").lower() in ("1", "true", "yes")
+    answer_cache_ttl_seconds: int = int(os.getenv("LLM_ANSWER_CACHE_TTL_SECONDS", "600"))
+    answer_cache_max_entries: int = int(os.getenv("LLM_ANSWER_CACHE_MAX_ENTRIES", "1000"))
+
+    # Reranking stage after vector retrieval (gated by feature flag "reranker", see app/services/reranker.py)
+    # backend: "cross_encoder" (local sentence-transformers model), "llm" (chat model scoring) or "" (auto)
+    reranker_backend: str = os.getenv("LLM_RERANKER_BACKEND", "")
+    reranker_model: str = os.getenv("LLM_RERANKER_MODEL", "")
+    # candidates retrieved per requested result before reranking (top_k * multiplier, capped at 50)
+    rerank_candidate_multiplier: int = int(os.getenv("LLM_RERANK_CANDIDATE_MULTIPLIER", "3"))
+    rerank_timeout_seconds: float = float(os.getenv("LLM_RERANK_TIMEOUT_SECONDS", "10"))
+
+    # Content moderation for questions (input) and generated answers (output), see app/services/moderation.py
+    # backend: "openai" (/moderations endpoint), "classifier" (local transformers model), "keywords" (blocklist file) or "" (off)
+    moderation_backend: str = os.getenv("LLM_MODERATION_BACKEND", "")
+    # openai: moderation model (default omni-moderation-latest); classifier: HF model name
+    moderation_model: str = os.getenv("LLM_MODERATION_MODEL", "")
+    moderation_terms_path: str = os.getenv("LLM_MODERATION_TERMS_PATH", "")
+    # classifier label score at/above which text is flagged
+    moderation_threshold: float = float(os.getenv("LLM_MODERATION_THRESHOLD", "0.5"))
+    # per-stage policy: block | flag | off
+    moderation_input_policy: str = os.getenv("LLM_MODERATION_INPUT_POLICY", "block")
+    moderation_output_policy: str = os.getenv("LLM_MODERATION_OUTPUT_POLICY", "block")
+    # block content when the moderation backend fails or times out (default lets it through)
+    moderation_fail_closed: bool = os.getenv("LLM_MODERATION_FAIL_CLOSED", "false").lower() in ("1", "true", "yes")
+    moderation_timeout_seconds: float = float(os.getenv("LLM_MODERATION_TIMEOUT_SECONDS", "5"))
+    moderation_block_message: str = os.getenv(
+        "LLM_MODERATION_BLOCK_MESSAGE",
+        "Sorry, I can't help with that. This request or answer was blocked by the content policy.",
+    )
+
+    # Grounding check for student questions (see app/services/grounding.py): when no retrieved passage scores at
+    # or above the threshold, an explicit "not found in your materials" answer is returned instead of calling the model
+    grounding_enabled: bool = os.getenv("LLM_GROUNDING_ENABLED", "true").lower() in ("1", "true", "yes")
+    # minimum passage score (rerank score when reranked, else similarity); requests may override via context grounding_threshold
+    grounding_min_score: float = float(os.getenv("LLM_GROUNDING_MIN_SCORE", "0.3"))
+    grounding_not_found_message: str = os.getenv(
+        "LLM_GROUNDING_NOT_FOUND_MESSAGE",
+        "I couldn't find this in your materials, so I won't guess an answer.",
+    )
+
+    # Rolling summary of long sessions: turns that fall out of the prompt history window are folded
+    # into a per-session summary in the background (see app/services/session_summary.py)
+    session_summary_enabled: bool = os.getenv("LLM_SESSION_SUMMARY_ENABLED", "true").lower() in ("1", "true", "yes")
+    # summarize once this many turns are outside the window and not yet covered (fewer model calls, more lag)
+    session_summary_batch_turns: int = int(os.getenv("LLM_SESSION_SUMMARY_BATCH_TURNS", "4"))
+    session_summary_max_chars: int = int(os.getenv("LLM_SESSION_SUMMARY_MAX_CHARS", "2000"))
+
     # Database settings
     db_user: str = os.getenv("DB_USER", "postgres")
     db_password: str = os.getenv("DB_PASSWORD", "password")
@@ -55,6 +108,16 @@ class Settings:
     minio_secret_key: str = os.getenv("MINIO_SECRET_KEY", "minioadmin")
     minio_bucket_name: str = os.getenv("MINIO_BUCKET_NAME", "arkstudy")
 
+    def embedding_model_for(self, language: str | None) -> str | None:
+        """Embedding model configured for language, falling back to openai_embedding_model."""
+        lang = (language or "").strip().lower().replace("_", "-").split("-")[0]
+        if lang:
+            for pair in self.embedding_models.split(","):
+                key, sep, model = pair.partition("=")
+                if sep and key.strip().lower() == lang and model.strip():
+                    return model.strip()
+        return self.openai_embedding_model
+
     @property
     def database_url(self) -> str:
         """构建数据库连接URL"""
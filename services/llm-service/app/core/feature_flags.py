@@ -0,0 +1,141 @@
+"""Runtime feature flags, compatible with the Go package pkg/featureflags.
+
+Sources (highest priority first):
+- env FEATURE_<NAME>, e.g. FEATURE_RERANKER=true
+- FEATURE_FLAGS_PATH: a file of `name=value` lines, or a directory with one file per flag
+  (the layout of a mounted K8s ConfigMap), reloaded every FEATURE_FLAGS_REFRESH (default 30s)
+
+Values: true/false, on/off, 1/0, or a percentage such as "25%" for a stable per-key rollout.
+The percentage bucket uses the same FNV-1a hash as the Go implementation, so a user gets the
+same answer from every service.
+"""
+from __future__ import annotations
+
+import os
+import threading
+import time
+from typing import Dict, Optional
+
+# known flag names shared with pkg/featureflags
+RERANKER = "reranker"
+HYBRID_SEARCH = "hybrid_search"
+NEW_CHUNKER = "new_chunker"
+
+_ENV_PREFIX = "FEATURE_"
+
+_lock = threading.Lock()
+_file_vals: Dict[str, int] = {}
+_loaded_at = 0.0
+
+
+def _normalize(name: str) -> str:
+    return name.strip().lower().replace("-", "_").replace(".", "_")
+
+
+def _parse_value(raw: str) -> Optional[int]:
+    v = (raw or "").strip().lower()
+    if v in ("true", "on", "yes", "1", "enabled"):
+        return 100
+    if v in ("false", "off", "no", "0", "disabled"):
+        return 0
+    if v.endswith("%"):
+        try:
+            return max(0, min(100, int(v[:-1].strip())))
+        except ValueError:
+            return None
+    return None
+
+
+def _load_path(path: str) -> Dict[str, int]:
+    vals: Dict[str, int] = {}
+    if os.path.isdir(path):
+        for entry in os.listdir(path):
+            full = os.path.join(path, entry)
+            # entries starting with "." (e.g. ..data) are K8s internal symlinks
+            if entry.startswith(".") or os.path.isdir(full):
+                continue
+            with open(full, encoding="utf-8") as f:
+                p = _parse_value(f.read())
+            if p is not None:
+                vals[_normalize(entry)] = p
+        return vals
+    with open(path, encoding="utf-8") as f:
+        for line in f:
+            line = line.strip()
+            if not line or line.startswith("#") or "=" not in line:
+                continue
+            name, raw = line.split("=", 1)
+            p = _parse_value(raw)
+            if p is not None:
+                vals[_normalize(name)] = p
+    return vals
+
+
+def _refresh_seconds() -> float:
+    # accepts the Go duration forms used in deployments: "30s", "2m", "1h" (or plain seconds)
+    raw = (os.getenv("FEATURE_FLAGS_REFRESH") or "30s").strip().lower()
+    units = {"s": 1, "m": 60, "h": 3600}
+    try:
+        if raw and raw[-1] in units:
+            value = float(raw[:-1]) * units[raw[-1]]
+        else:
+            value = float(raw)
+    except ValueError:
+        return 30.0
+    return value if value > 0 else 30.0
+
+
+def _file_value(name: str) -> Optional[int]:
+    global _file_vals, _loaded_at
+    path = os.getenv("FEATURE_FLAGS_PATH")
+    if not path:
+        return None
+    refresh = _refresh_seconds()
+    with _lock:
+        if time.time() - _loaded_at > refresh:
+            try:
+                _file_vals = _load_path(path)
+            except OSError as e:
+                # keep the previous values so a transient read error does not flip flags off
+                print(f"[WARN] featureflags: failed to load {path}: {e}")
+            _loaded_at = time.time()
+        return _file_vals.get(name)
+
+
+def _lookup(name: str) -> Optional[int]:
+    name = _normalize(name)
+    raw = os.getenv(_ENV_PREFIX + name.upper())
+    if raw is not None:
+        p = _parse_value(raw)
+        if p is not None:
+            return p
+    return _file_value(name)
+
+
+def _fnv32a(data: bytes) -> int:
+    h = 0x811C9DC5
+    for b in data:
+        h ^= b
+        h = (h * 0x01000193) & 0xFFFFFFFF
+    return h
+
+
+def enabled(name: str, default: bool) -> bool:
+    """Whether the flag is on; percentage flags count as on only at 100%."""
+    p = _lookup(name)
+    if p is None:
+        return default
+    return p >= 100
+
+
+def enabled_for(name: str, key: str, default: bool) -> bool:
+    """Whether the flag is on for key (usually a user id); stable while the percentage is unchanged."""
+    p = _lookup(name)
+    if p is None:
+        return default
+    if p <= 0:
+        return False
+    if p >= 100:
+        return True
+    h = _fnv32a(_normalize(name).encode() + b"\x00" + (key or "").encode())
+    return h % 100 < p
@@ -41,6 +41,17 @@ class InMemoryVectorStore:
         self.items.append(item)
         return item
 
+    def remove_material(self, material_id: str, material_version: str | None = None) -> int:
+        """Drop a material's items; with material_version, only items tagged with that version."""
+        def matches(it: VectorItem) -> bool:
+            if it.material_id != material_id:
+                return False
+            return material_version is None or it.metadata.get("material_version") == material_version
+
+        before = len(self.items)
+        self.items = [it for it in self.items if not matches(it)]
+        return before - len(self.items)
+
     def search(self, query: str, top_k: int = 5) -> List[Tuple[VectorItem, float]]:
         qv = embed_text(query, dim=self.dim)
         # cosine similarity
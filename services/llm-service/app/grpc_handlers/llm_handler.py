@@ -1,16 +1,51 @@
 from __future__ import annotations
 
+import time
+
 import grpc
 
 from app.proto.llm import llm_pb2, llm_pb2_grpc
+from app.services.grounding import grounding_metadata
 from app.services.llm_service import LLMService
+from app.services.moderation import POLICY_BLOCK, STAGE_INPUT, STAGE_OUTPUT, moderation_metadata
+from app.services.shared_sessions import ERR_FORBIDDEN, ERR_INVALID, ERR_NOT_FOUND, ROLE_OWNER, SharedSessionError, SharedSessionRecord
+
+_SHARED_SESSION_STATUS = {
+    ERR_INVALID: grpc.StatusCode.INVALID_ARGUMENT,
+    ERR_NOT_FOUND: grpc.StatusCode.NOT_FOUND,
+    ERR_FORBIDDEN: grpc.StatusCode.PERMISSION_DENIED,
+}
+
+
+def _shared_session_pb(record: SharedSessionRecord, user_id: str) -> llm_pb2.SharedSession:
+    """The invite code is only disclosed to the owner."""
+    return llm_pb2.SharedSession(
+        session_id=record.session_id,
+        owner_id=record.owner_id,
+        title=record.title,
+        join_code=record.join_code if record.role_of(user_id) == ROLE_OWNER else "",
+        default_role=record.default_role,
+        members=[
+            llm_pb2.SessionMember(user_id=m.user_id, role=m.role, joined_at=m.joined_at.isoformat() + "Z")
+            for m in record.members
+        ],
+        created_at=record.created_at.isoformat() + "Z",
+    )
 
 
 class LLMServiceHandler(llm_pb2_grpc.LLMServiceServicer):
     def __init__(self) -> None:
         self.svc = LLMService()
 
+    async def _authorize_ask(self, request: llm_pb2.QuestionRequest, context: grpc.aio.ServicerContext) -> None:
+        """Viewers and non-members of a shared session cannot add turns to it."""
+        try:
+            await self.svc.shared_sessions.check_can_ask(request.context.get("session_id", ""), request.user_id)
+        except SharedSessionError as e:
+            await context.abort(_SHARED_SESSION_STATUS[e.kind], str(e))
+
     async def AskQuestion(self, request: llm_pb2.QuestionRequest, context: grpc.aio.ServicerContext) -> llm_pb2.QuestionResponse:
+        await self._authorize_ask(request, context)
         result = await self.svc.ask_question(
             question=request.question,
             user_id=request.user_id,
@@ -40,26 +75,92 @@ class LLMServiceHandler(llm_pb2_grpc.LLMServiceServicer):
         """Server-streaming tokens using OpenAI-compatible streaming.
         Fallback to single-shot answer if streaming model is not configured.
         """
+        await self._authorize_ask(request, context)
         # try streaming if available
         if getattr(self.svc, "_oa", None) and self.svc._oa.is_enabled():
+            started_at = time.monotonic()
+            input_verdict = await self.svc.moderation.check(
+                request.question, stage=STAGE_INPUT, user_id=request.user_id, session_id=request.context.get("session_id", "")
+            )
+            if input_verdict.blocked:
+                blocked = await self.svc.blocked_response(
+                    request.question, request.user_id, dict(request.context), input_verdict, started_at
+                )
+                yield llm_pb2.TokenChunk(
+                    content=blocked["answer"],
+                    is_final=True,
+                    metadata={str(k): str(v) for k, v in blocked["metadata"].items()},
+                )
+                return
+
             # prepare retrieval + memory context (token-aware)
-            hits = await self.svc.semantic_search(request.question, user_id=request.user_id, top_k=3)
-            messages, session_id, used_turns, used_tokens = await self.svc._build_messages(
+            retrieval = self.svc._retrieval_params(dict(request.context))
+            hits = await self.svc.semantic_search(
+                request.question,
+                user_id=request.user_id,
+                material_ids=list(request.material_ids) or None,
+                **retrieval,
+            )
+            grounding = self.svc.grounding_check(
+                request.question, hits, dict(request.context), list(request.material_ids)
+            )
+            if not grounding.grounded:
+                not_found = await self.svc.not_found_response(
+                    request.question, request.user_id, dict(request.context), hits, grounding, input_verdict, started_at
+                )
+                yield llm_pb2.TokenChunk(
+                    content=not_found["answer"],
+                    is_final=True,
+                    metadata={str(k): str(v) for k, v in not_found["metadata"].items()},
+                )
+                return
+            messages, session_id, used_turns, used_tokens, summarized_turns = await self.svc._build_messages(
                 request.question, user_id=request.user_id, context=dict(request.context), hits=hits
             )
 
+            # with a blocking output policy tokens are buffered and released only after the answer passes moderation
+            buffer_output = self.svc.moderation.policy(STAGE_OUTPUT) == POLICY_BLOCK
             final_parts: list[str] = []
             async for tok in self.svc._oa.achat_stream(messages):
                 final_parts.append(tok)
-                yield llm_pb2.TokenChunk(content=tok, is_final=False)
+                if not buffer_output:
+                    yield llm_pb2.TokenChunk(content=tok, is_final=False)
             final_answer = "".join(final_parts)
+            usage = self.svc._usage_metadata(messages, final_answer)
+            output_verdict = await self.svc.moderation.check(
+                final_answer, stage=STAGE_OUTPUT, user_id=request.user_id, session_id=session_id
+            )
+            if output_verdict.blocked:
+                final_answer = self.svc.moderation.block_message
+                hits = []
+            if buffer_output:
+                yield llm_pb2.TokenChunk(content=final_answer, is_final=False)
             # write memory best-effort
-            if session_id:
+            if session_id and not output_verdict.blocked:
                 try:
                     await self.svc._memory.append(session_id, role="user", content=request.question)
                     await self.svc._memory.append(session_id, role="assistant", content=final_answer)
                 except Exception:
                     pass
+            if session_id:
+                await self.svc.record_turn(
+                    session_id=session_id,
+                    user_id=request.user_id,
+                    question=request.question,
+                    answer=final_answer,
+                    sources=[
+                        {
+                            "material_id": h["material_id"],
+                            "content_snippet": h["content"][:120],
+                            "relevance_score": h.get("rerank_score", h["similarity_score"]),
+                        }
+                        for h in hits
+                    ],
+                    started_at=started_at,
+                    model=self.svc._answer_model(),
+                )
+                if not output_verdict.blocked:
+                    self.svc.schedule_summary(session_id, request.user_id, dict(request.context), used_turns)
             # final marker with session + usage metadata for clients to capture
             yield llm_pb2.TokenChunk(
                 content="",
@@ -68,6 +169,10 @@ class LLMServiceHandler(llm_pb2_grpc.LLMServiceServicer):
                     "session_id": session_id or "",
                     "used_history_turns": str(used_turns),
                     "used_history_tokens": str(used_tokens),
+                    "summarized_history_turns": str(summarized_turns),
+                    **{k: str(v) for k, v in usage.items()},
+                    **grounding_metadata(grounding),
+                    **moderation_metadata(input_verdict, output_verdict),
                 },
             )
             return
@@ -115,6 +220,16 @@ class LLMServiceHandler(llm_pb2_grpc.LLMServiceServicer):
         )
         return llm_pb2.EmbeddingResponse(embedding=list(res["embedding"]), embedding_id=res["embedding_id"])
 
+    async def EmbedTexts(self, request: llm_pb2.EmbedTextsRequest, context: grpc.aio.ServicerContext) -> llm_pb2.EmbedTextsResponse:
+        try:
+            vectors, model = await self.svc.embed_texts(list(request.texts), language=request.language or None)
+        except ValueError as e:
+            await context.abort(grpc.StatusCode.INVALID_ARGUMENT, str(e))
+        return llm_pb2.EmbedTextsResponse(
+            embeddings=[llm_pb2.TextEmbedding(values=v) for v in vectors],
+            model=model,
+        )
+
     async def UpsertChunks(self, request: llm_pb2.UpsertChunksRequest, context: grpc.aio.ServicerContext) -> llm_pb2.UpsertChunksResponse:
         # Batch vectorize and persist chunks for a given material
         items = []
@@ -125,5 +240,79 @@ class LLMServiceHandler(llm_pb2_grpc.LLMServiceServicer):
                 "page": ch.page,
                 "metadata": dict(ch.metadata),
             })
-        inserted = await self.svc.upsert_chunks(user_id=request.user_id, material_id=request.material_id, chunks=items)
+        inserted = await self.svc.upsert_chunks(
+            user_id=request.user_id, material_id=request.material_id, chunks=items, replace=request.replace
+        )
         return llm_pb2.UpsertChunksResponse(inserted=int(inserted))
+
+    async def DeleteChunks(self, request: llm_pb2.DeleteChunksRequest, context: grpc.aio.ServicerContext) -> llm_pb2.DeleteChunksResponse:
+        if not request.material_id:
+            await context.abort(grpc.StatusCode.INVALID_ARGUMENT, "material_id is required")
+        deleted = await self.svc.delete_chunks(
+            user_id=request.user_id, material_id=request.material_id, material_version=request.material_version
+        )
+        return llm_pb2.DeleteChunksResponse(deleted=int(deleted))
+
+    async def GetSessionHistory(self, request: llm_pb2.SessionHistoryRequest, context: grpc.aio.ServicerContext) -> llm_pb2.SessionHistoryResponse:
+        if not request.session_id or not request.user_id:
+            return llm_pb2.SessionHistoryResponse(success=False, message="session_id and user_id are required")
+        try:
+            turns = await self.svc.session_history(request.session_id, request.user_id)
+        except Exception as e:
+            return llm_pb2.SessionHistoryResponse(success=False, message=f"failed to load session history: {e}")
+        return llm_pb2.SessionHistoryResponse(
+            success=True,
+            turns=[
+                llm_pb2.ChatTurn(
+                    id=t.id,
+                    session_id=t.session_id,
+                    question=t.question,
+                    answer=t.answer,
+                    sources=[
+                        llm_pb2.SourceReference(
+                            material_id=str(s.get("material_id", "")),
+                            content_snippet=str(s.get("content_snippet", "")),
+                            relevance_score=float(s.get("relevance_score", 0.0)),
+                        )
+                        for s in t.sources
+                    ],
+                    latency_ms=int(t.latency_ms),
+                    model=t.model,
+                    created_at=t.created_at.isoformat() + "Z",
+                    user_id=t.user_id,
+                )
+                for t in turns
+            ],
+        )
+
+    async def _shared_session_response(self, op, user_id: str, context: grpc.aio.ServicerContext) -> llm_pb2.SharedSessionResponse:
+        try:
+            record = await op
+        except SharedSessionError as e:
+            await context.abort(_SHARED_SESSION_STATUS[e.kind], str(e))
+        return llm_pb2.SharedSessionResponse(
+            success=True,
+            session=_shared_session_pb(record, user_id),
+            role=record.role_of(user_id),
+        )
+
+    async def CreateSharedSession(self, request: llm_pb2.CreateSharedSessionRequest, context: grpc.aio.ServicerContext) -> llm_pb2.SharedSessionResponse:
+        op = self.svc.shared_sessions.create(
+            owner_id=request.user_id,
+            title=request.title,
+            session_id=request.session_id,
+            default_role=request.default_role,
+        )
+        return await self._shared_session_response(op, request.user_id, context)
+
+    async def JoinSharedSession(self, request: llm_pb2.JoinSharedSessionRequest, context: grpc.aio.ServicerContext) -> llm_pb2.SharedSessionResponse:
+        op = self.svc.shared_sessions.join(request.session_id, request.user_id, request.join_code)
+        return await self._shared_session_response(op, request.user_id, context)
+
+    async def GetSharedSession(self, request: llm_pb2.GetSharedSessionRequest, context: grpc.aio.ServicerContext) -> llm_pb2.SharedSessionResponse:
+        op = self.svc.shared_sessions.get(request.session_id, request.user_id)
+        return await self._shared_session_response(op, request.user_id, context)
+
+    async def SetSessionMember(self, request: llm_pb2.SetSessionMemberRequest, context: grpc.aio.ServicerContext) -> llm_pb2.SharedSessionResponse:
+        op = self.svc.shared_sessions.set_member(request.session_id, request.user_id, request.member_id, request.role)
+        return await self._shared_session_response(op, request.user_id, context)
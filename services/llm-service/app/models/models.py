@@ -161,3 +161,87 @@ class MaterialChunk(Base):
 
     def get_vector(self) -> List[float]:
         return _text_to_vector(self.vector_text)
+
+
+class ChatTurn(Base):
+    """One persisted Q&A turn of an AI study session (used for history export)"""
+    __tablename__ = "chat_turns"
+
+    id: Mapped[str] = mapped_column(String(32), primary_key=True)
+    session_id: Mapped[str] = mapped_column(String(64), index=True)
+    user_id: Mapped[str] = mapped_column(String(36), index=True)
+
+    question: Mapped[str] = mapped_column(Text)
+    answer: Mapped[str] = mapped_column(Text)
+    # [{material_id, content_snippet, relevance_score}]
+    sources: Mapped[List[Dict[str, Any]] | None] = mapped_column(JSON, nullable=True)
+
+    latency_ms: Mapped[int] = mapped_column(Integer, default=0)
+    model: Mapped[str] = mapped_column(String(64), default="")
+    created_at: Mapped[datetime] = mapped_column(DateTime, default=datetime.utcnow)
+
+    __table_args__ = (
+        Index('ix_chat_turns_session_created', 'session_id', 'created_at'),
+    )
+
+
+class ModerationEvent(Base):
+    """Audit record for content that moderation flagged or blocked"""
+    __tablename__ = "moderation_events"
+
+    id: Mapped[int] = mapped_column(Integer, primary_key=True, autoincrement=True)
+    user_id: Mapped[str] = mapped_column(String(36), index=True)
+    session_id: Mapped[str] = mapped_column(String(64), default="", index=True)
+
+    stage: Mapped[str] = mapped_column(String(16))  # input (user question) | output (generated answer)
+    action: Mapped[str] = mapped_column(String(16), index=True)  # flag | block
+    provider: Mapped[str] = mapped_column(String(32))
+    categories: Mapped[List[str] | None] = mapped_column(JSON, nullable=True)
+    scores: Mapped[Dict[str, float] | None] = mapped_column(JSON, nullable=True)
+    # truncated excerpt of the moderated text for reviewers
+    excerpt: Mapped[str] = mapped_column(Text, default="")
+    error: Mapped[str] = mapped_column(Text, default="")  # moderation backend failure (fail-closed blocks)
+
+    created_at: Mapped[datetime] = mapped_column(DateTime, default=datetime.utcnow, index=True)
+
+
+class ChatSessionSummary(Base):
+    """Rolling summary of the turns of a session that fell out of the prompt history window"""
+    __tablename__ = "chat_session_summaries"
+
+    session_id: Mapped[str] = mapped_column(String(64), primary_key=True)
+    user_id: Mapped[str] = mapped_column(String(36), index=True)
+
+    summary: Mapped[str] = mapped_column(Text, default="")
+    # number of turns folded into the summary so far
+    summarized_turns: Mapped[int] = mapped_column(Integer, default=0)
+    # created_at of the newest folded turn; later turns are not covered yet
+    covered_until: Mapped[datetime | None] = mapped_column(DateTime, nullable=True)
+
+    updated_at: Mapped[datetime] = mapped_column(DateTime, default=datetime.utcnow, onupdate=datetime.utcnow)
+
+
+class SharedChatSession(Base):
+    """An AI session opened to several members; sessions without a row stay private to the asking user"""
+    __tablename__ = "shared_chat_sessions"
+
+    session_id: Mapped[str] = mapped_column(String(64), primary_key=True)
+    owner_id: Mapped[str] = mapped_column(String(36), index=True)
+    title: Mapped[str] = mapped_column(String(255), default="")
+
+    # invite code required to join; members joining with it get default_role
+    join_code: Mapped[str] = mapped_column(String(32), unique=True)
+    default_role: Mapped[str] = mapped_column(String(16), default="participant")  # participant | viewer
+
+    created_at: Mapped[datetime] = mapped_column(DateTime, default=datetime.utcnow)
+
+
+class SharedSessionMember(Base):
+    """Membership (ACL entry) of a shared AI session"""
+    __tablename__ = "shared_session_members"
+
+    session_id: Mapped[str] = mapped_column(String(64), primary_key=True)
+    user_id: Mapped[str] = mapped_column(String(36), primary_key=True, index=True)
+
+    role: Mapped[str] = mapped_column(String(16))  # owner | participant | viewer (read-only)
+    joined_at: Mapped[datetime] = mapped_column(DateTime, default=datetime.utcnow)
@@ -24,7 +24,7 @@ _sym_db = _symbol_database.Default()
 
 
 
-DESCRIPTOR = _descriptor_pool.Default().AddSerializedFile(b'\n\rllm/llm.proto\x12\x03llm\"\xae\x01\n\x0fQuestionRequest\x12\x10\n\x08question\x18\x01 \x01(\t\x12\x0f\n\x07user_id\x18\x02 \x01(\t\x12\x14\n\x0cmaterial_ids\x18\x03 \x03(\t\x12\x32\n\x07\x63ontext\x18\x04 \x03(\x0b\x32!.llm.QuestionRequest.ContextEntry\x1a.\n\x0c\x43ontextEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12\r\n\x05value\x18\x02 \x01(\t:\x02\x38\x01\"X\n\x0fSourceReference\x12\x13\n\x0bmaterial_id\x18\x01 \x01(\t\x12\x17\n\x0f\x63ontent_snippet\x18\x02 \x01(\t\x12\x17\n\x0frelevance_score\x18\x03 \x01(\x02\"\xc5\x01\n\x10QuestionResponse\x12\x0e\n\x06\x61nswer\x18\x01 \x01(\t\x12\x12\n\nconfidence\x18\x02 \x01(\x02\x12%\n\x07sources\x18\x03 \x03(\x0b\x32\x14.llm.SourceReference\x12\x35\n\x08metadata\x18\x04 \x03(\x0b\x32#.llm.QuestionResponse.MetadataEntry\x1a/\n\rMetadataEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12\r\n\x05value\x18\x02 \x01(\t:\x02\x38\x01\"\x91\x01\n\nTokenChunk\x12\x0f\n\x07\x63ontent\x18\x01 \x01(\t\x12\x10\n\x08is_final\x18\x02 \x01(\x08\x12/\n\x08metadata\x18\x03 \x03(\x0b\x32\x1d.llm.TokenChunk.MetadataEntry\x1a/\n\rMetadataEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12\r\n\x05value\x18\x02 \x01(\t:\x02\x38\x01\"T\n\rSearchRequest\x12\r\n\x05query\x18\x01 \x01(\t\x12\x0f\n\x07user_id\x18\x02 \x01(\t\x12\r\n\x05top_k\x18\x03 \x01(\x05\x12\x14\n\x0cmaterial_ids\x18\x04 \x03(\t\"\xb2\x01\n\x0cSearchResult\x12\x13\n\x0bmaterial_id\x18\x01 \x01(\t\x12\x0f\n\x07\x63ontent\x18\x02 \x01(\t\x12\x18\n\x10similarity_score\x18\x03 \x01(\x02\x12\x31\n\x08metadata\x18\x04 \x03(\x0b\x32\x1f.llm.SearchResult.MetadataEntry\x1a/\n\rMetadataEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12\r\n\x05value\x18\x02 \x01(\t:\x02\x38\x01\"4\n\x0eSearchResponse\x12\"\n\x07results\x18\x01 \x03(\x0b\x32\x11.llm.SearchResult\"N\n\x10\x45mbeddingRequest\x12\x0f\n\x07\x63ontent\x18\x01 \x01(\t\x12\x13\n\x0bmaterial_id\x18\x02 \x01(\t\x12\x14\n\x0c\x63ontent_type\x18\x03 \x01(\t\"<\n\x11\x45mbeddingResponse\x12\x11\n\tembedding\x18\x01 \x03(\x02\x12\x14\n\x0c\x65mbedding_id\x18\x02 \x01(\t\"\xa9\x01\n\x0fUpsertChunkItem\x12\x0f\n\x07\x63ontent\x18\x01 \x01(\t\x12\x10\n\x08timecode\x18\x02 \x01(\t\x12\x0c\n\x04page\x18\x03 \x01(\x05\x12\x34\n\x08metadata\x18\x04 \x03(\x0b\x32\".llm.UpsertChunkItem.MetadataEntry\x1a/\n\rMetadataEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12\r\n\x05value\x18\x02 \x01(\t:\x02\x38\x01\"a\n\x13UpsertChunksRequest\x12\x0f\n\x07user_id\x18\x01 \x01(\t\x12\x13\n\x0bmaterial_id\x18\x02 \x01(\t\x12$\n\x06\x63hunks\x18\x03 \x03(\x0b\x32\x14.llm.UpsertChunkItem\"(\n\x14UpsertChunksResponse\x12\x10\n\x08inserted\x18\x01 \x01(\x05\x32\xcb\x02\n\nLLMService\x12:\n\x0b\x41skQuestion\x12\x14.llm.QuestionRequest\x1a\x15.llm.QuestionResponse\x12<\n\x11\x41skQuestionStream\x12\x14.llm.QuestionRequest\x1a\x0f.llm.TokenChunk0\x01\x12\x39\n\x0eSemanticSearch\x12\x12.llm.SearchRequest\x1a\x13.llm.SearchResponse\x12\x43\n\x12GenerateEmbeddings\x12\x15.llm.EmbeddingRequest\x1a\x16.llm.EmbeddingResponse\x12\x43\n\x0cUpsertChunks\x12\x18.llm.UpsertChunksRequest\x1a\x19.llm.UpsertChunksResponseB)Z\'github.com/RigelNana/arkstudy/proto/llmb\x06proto3')
+DESCRIPTOR = _descriptor_pool.Default().AddSerializedFile(b'\n\rllm/llm.proto\x12\x03llm\"\xae\x01\n\x0fQuestionRequest\x12\x10\n\x08question\x18\x01 \x01(\t\x12\x0f\n\x07user_id\x18\x02 \x01(\t\x12\x14\n\x0cmaterial_ids\x18\x03 \x03(\t\x12\x32\n\x07\x63ontext\x18\x04 \x03(\x0b\x32!.llm.QuestionRequest.ContextEntry\x1a.\n\x0c\x43ontextEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12\r\n\x05value\x18\x02 \x01(\t:\x02\x38\x01\"X\n\x0fSourceReference\x12\x13\n\x0bmaterial_id\x18\x01 \x01(\t\x12\x17\n\x0f\x63ontent_snippet\x18\x02 \x01(\t\x12\x17\n\x0frelevance_score\x18\x03 \x01(\x02\"\xc5\x01\n\x10QuestionResponse\x12\x0e\n\x06\x61nswer\x18\x01 \x01(\t\x12\x12\n\nconfidence\x18\x02 \x01(\x02\x12%\n\x07sources\x18\x03 \x03(\x0b\x32\x14.llm.SourceReference\x12\x35\n\x08metadata\x18\x04 \x03(\x0b\x32#.llm.QuestionResponse.MetadataEntry\x1a/\n\rMetadataEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12\r\n\x05value\x18\x02 \x01(\t:\x02\x38\x01\"\x91\x01\n\nTokenChunk\x12\x0f\n\x07\x63ontent\x18\x01 \x01(\t\x12\x10\n\x08is_final\x18\x02 \x01(\x08\x12/\n\x08metadata\x18\x03 \x03(\x0b\x32\x1d.llm.TokenChunk.MetadataEntry\x1a/\n\rMetadataEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12\r\n\x05value\x18\x02 \x01(\t:\x02\x38\x01\"T\n\rSearchRequest\x12\r\n\x05query\x18\x01 \x01(\t\x12\x0f\n\x07user_id\x18\x02 \x01(\t\x12\r\n\x05top_k\x18\x03 \x01(\x05\x12\x14\n\x0cmaterial_ids\x18\x04 \x03(\t\"\xb2\x01\n\x0cSearchResult\x12\x13\n\x0bmaterial_id\x18\x01 \x01(\t\x12\x0f\n\x07\x63ontent\x18\x02 \x01(\t\x12\x18\n\x10similarity_score\x18\x03 \x01(\x02\x12\x31\n\x08metadata\x18\x04 \x03(\x0b\x32\x1f.llm.SearchResult.MetadataEntry\x1a/\n\rMetadataEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12\r\n\x05value\x18\x02 \x01(\t:\x02\x38\x01\"4\n\x0eSearchResponse\x12\"\n\x07results\x18\x01 \x03(\x0b\x32\x11.llm.SearchResult\"N\n\x10\x45mbeddingRequest\x12\x0f\n\x07\x63ontent\x18\x01 \x01(\t\x12\x13\n\x0bmaterial_id\x18\x02 \x01(\t\x12\x14\n\x0c\x63ontent_type\x18\x03 \x01(\t\"<\n\x11\x45mbeddingResponse\x12\x11\n\tembedding\x18\x01 \x03(\x02\x12\x14\n\x0c\x65mbedding_id\x18\x02 \x01(\t\"4\n\x11\x45mbedTextsRequest\x12\r\n\x05texts\x18\x01 \x03(\t\x12\x10\n\x08language\x18\x02 \x01(\t\"\x1f\n\rTextEmbedding\x12\x0e\n\x06values\x18\x01 \x03(\x02\"K\n\x12\x45mbedTextsResponse\x12&\n\nembeddings\x18\x01 \x03(\x0b\x32\x12.llm.TextEmbedding\x12\r\n\x05model\x18\x02 \x01(\t\"\xa9\x01\n\x0fUpsertChunkItem\x12\x0f\n\x07\x63ontent\x18\x01 \x01(\t\x12\x10\n\x08timecode\x18\x02 \x01(\t\x12\x0c\n\x04page\x18\x03 \x01(\x05\x12\x34\n\x08metadata\x18\x04 \x03(\x0b\x32\".llm.UpsertChunkItem.MetadataEntry\x1a/\n\rMetadataEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12\r\n\x05value\x18\x02 \x01(\t:\x02\x38\x01\"r\n\x13UpsertChunksRequest\x12\x0f\n\x07user_id\x18\x01 \x01(\t\x12\x13\n\x0bmaterial_id\x18\x02 \x01(\t\x12$\n\x06\x63hunks\x18\x03 \x03(\x0b\x32\x14.llm.UpsertChunkItem\x12\x0f\n\x07replace\x18\x04 \x01(\x08\"(\n\x14UpsertChunksResponse\x12\x10\n\x08inserted\x18\x01 \x01(\x05\"U\n\x13\x44\x65leteChunksRequest\x12\x0f\n\x07user_id\x18\x01 \x01(\t\x12\x13\n\x0bmaterial_id\x18\x02 \x01(\t\x12\x18\n\x10material_version\x18\x03 \x01(\t\"\'\n\x14\x44\x65leteChunksResponse\x12\x0f\n\x07\x64\x65leted\x18\x01 \x01(\x05\"\xbb\x01\n\x08\x43hatTurn\x12\n\n\x02id\x18\x01 \x01(\t\x12\x12\n\nsession_id\x18\x02 \x01(\t\x12\x10\n\x08question\x18\x03 \x01(\t\x12\x0e\n\x06\x61nswer\x18\x04 \x01(\t\x12%\n\x07sources\x18\x05 \x03(\x0b\x32\x14.llm.SourceReference\x12\x12\n\nlatency_ms\x18\x06 \x01(\x03\x12\r\n\x05model\x18\x07 \x01(\t\x12\x12\n\ncreated_at\x18\x08 \x01(\t\x12\x0f\n\x07user_id\x18\t \x01(\t\"<\n\x15SessionHistoryRequest\x12\x12\n\nsession_id\x18\x01 \x01(\t\x12\x0f\n\x07user_id\x18\x02 \x01(\t\"X\n\x16SessionHistoryResponse\x12\x0f\n\x07success\x18\x01 \x01(\x08\x12\x0f\n\x07message\x18\x02 \x01(\t\x12\x1c\n\x05turns\x18\x03 \x03(\x0b\x32\r.llm.ChatTurn\"A\n\rSessionMember\x12\x0f\n\x07user_id\x18\x01 \x01(\t\x12\x0c\n\x04role\x18\x02 \x01(\t\x12\x11\n\tjoined_at\x18\x03 \x01(\t\"\xa6\x01\n\rSharedSession\x12\x12\n\nsession_id\x18\x01 \x01(\t\x12\x10\n\x08owner_id\x18\x02 \x01(\t\x12\r\n\x05title\x18\x03 \x01(\t\x12\x11\n\tjoin_code\x18\x04 \x01(\t\x12\x14\n\x0c\x64\x65\x66\x61ult_role\x18\x05 \x01(\t\x12#\n\x07members\x18\x06 \x03(\x0b\x32\x12.llm.SessionMember\x12\x12\n\ncreated_at\x18\x07 \x01(\t\"f\n\x1a\x43reateSharedSessionRequest\x12\x0f\n\x07user_id\x18\x01 \x01(\t\x12\r\n\x05title\x18\x02 \x01(\t\x12\x12\n\nsession_id\x18\x03 \x01(\t\x12\x14\n\x0c\x64\x65\x66\x61ult_role\x18\x04 \x01(\t\"R\n\x18JoinSharedSessionRequest\x12\x12\n\nsession_id\x18\x01 \x01(\t\x12\x0f\n\x07user_id\x18\x02 \x01(\t\x12\x11\n\tjoin_code\x18\x03 \x01(\t\">\n\x17GetSharedSessionRequest\x12\x12\n\nsession_id\x18\x01 \x01(\t\x12\x0f\n\x07user_id\x18\x02 \x01(\t\"_\n\x17SetSessionMemberRequest\x12\x12\n\nsession_id\x18\x01 \x01(\t\x12\x0f\n\x07user_id\x18\x02 \x01(\t\x12\x11\n\tmember_id\x18\x03 \x01(\t\x12\x0c\n\x04role\x18\x04 \x01(\t\"l\n\x15SharedSessionResponse\x12\x0f\n\x07success\x18\x01 \x01(\x08\x12\x0f\n\x07message\x18\x02 \x01(\t\x12#\n\x07session\x18\x03 \x01(\x0b\x32\x12.llm.SharedSession\x12\x0c\n\x04role\x18\x04 \x01(\t2\xdd\x06\n\nLLMService\x12:\n\x0b\x41skQuestion\x12\x14.llm.QuestionRequest\x1a\x15.llm.QuestionResponse\x12<\n\x11\x41skQuestionStream\x12\x14.llm.QuestionRequest\x1a\x0f.llm.TokenChunk0\x01\x12\x39\n\x0eSemanticSearch\x12\x12.llm.SearchRequest\x1a\x13.llm.SearchResponse\x12\x43\n\x12GenerateEmbeddings\x12\x15.llm.EmbeddingRequest\x1a\x16.llm.EmbeddingResponse\x12=\n\nEmbedTexts\x12\x16.llm.EmbedTextsRequest\x1a\x17.llm.EmbedTextsResponse\x12\x43\n\x0cUpsertChunks\x12\x18.llm.UpsertChunksRequest\x1a\x19.llm.UpsertChunksResponse\x12\x43\n\x0c\x44\x65leteChunks\x12\x18.llm.DeleteChunksRequest\x1a\x19.llm.DeleteChunksResponse\x12L\n\x11GetSessionHistory\x12\x1a.llm.SessionHistoryRequest\x1a\x1b.llm.SessionHistoryResponse\x12R\n\x13\x43reateSharedSession\x12\x1f.llm.CreateSharedSessionRequest\x1a\x1a.llm.SharedSessionResponse\x12N\n\x11JoinSharedSession\x12\x1d.llm.JoinSharedSessionRequest\x1a\x1a.llm.SharedSessionResponse\x12L\n\x10GetSharedSession\x12\x1c.llm.GetSharedSessionRequest\x1a\x1a.llm.SharedSessionResponse\x12L\n\x10SetSessionMember\x12\x1c.llm.SetSessionMemberRequest\x1a\x1a.llm.SharedSessionResponseB)Z\'github.com/RigelNana/arkstudy/proto/llmb\x06proto3')
 
 _globals = globals()
 _builder.BuildMessageAndEnumDescriptors(DESCRIPTOR, _globals)
@@ -68,14 +68,44 @@ if not _descriptor._USE_C_DESCRIPTORS:
   _globals['_EMBEDDINGREQUEST']._serialized_end=1036
   _globals['_EMBEDDINGRESPONSE']._serialized_start=1038
   _globals['_EMBEDDINGRESPONSE']._serialized_end=1098
-  _globals['_UPSERTCHUNKITEM']._serialized_start=1101
-  _globals['_UPSERTCHUNKITEM']._serialized_end=1270
+  _globals['_EMBEDTEXTSREQUEST']._serialized_start=1100
+  _globals['_EMBEDTEXTSREQUEST']._serialized_end=1152
+  _globals['_TEXTEMBEDDING']._serialized_start=1154
+  _globals['_TEXTEMBEDDING']._serialized_end=1185
+  _globals['_EMBEDTEXTSRESPONSE']._serialized_start=1187
+  _globals['_EMBEDTEXTSRESPONSE']._serialized_end=1262
+  _globals['_UPSERTCHUNKITEM']._serialized_start=1265
+  _globals['_UPSERTCHUNKITEM']._serialized_end=1434
   _globals['_UPSERTCHUNKITEM_METADATAENTRY']._serialized_start=440
   _globals['_UPSERTCHUNKITEM_METADATAENTRY']._serialized_end=487
-  _globals['_UPSERTCHUNKSREQUEST']._serialized_start=1272
-  _globals['_UPSERTCHUNKSREQUEST']._serialized_end=1369
-  _globals['_UPSERTCHUNKSRESPONSE']._serialized_start=1371
-  _globals['_UPSERTCHUNKSRESPONSE']._serialized_end=1411
-  _globals['_LLMSERVICE']._serialized_start=1414
-  _globals['_LLMSERVICE']._serialized_end=1745
+  _globals['_UPSERTCHUNKSREQUEST']._serialized_start=1436
+  _globals['_UPSERTCHUNKSREQUEST']._serialized_end=1550
+  _globals['_UPSERTCHUNKSRESPONSE']._serialized_start=1552
+  _globals['_UPSERTCHUNKSRESPONSE']._serialized_end=1592
+  _globals['_DELETECHUNKSREQUEST']._serialized_start=1594
+  _globals['_DELETECHUNKSREQUEST']._serialized_end=1679
+  _globals['_DELETECHUNKSRESPONSE']._serialized_start=1681
+  _globals['_DELETECHUNKSRESPONSE']._serialized_end=1720
+  _globals['_CHATTURN']._serialized_start=1723
+  _globals['_CHATTURN']._serialized_end=1910
+  _globals['_SESSIONHISTORYREQUEST']._serialized_start=1912
+  _globals['_SESSIONHISTORYREQUEST']._serialized_end=1972
+  _globals['_SESSIONHISTORYRESPONSE']._serialized_start=1974
+  _globals['_SESSIONHISTORYRESPONSE']._serialized_end=2062
+  _globals['_SESSIONMEMBER']._serialized_start=2064
+  _globals['_SESSIONMEMBER']._serialized_end=2129
+  _globals['_SHAREDSESSION']._serialized_start=2132
+  _globals['_SHAREDSESSION']._serialized_end=2298
+  _globals['_CREATESHAREDSESSIONREQUEST']._serialized_start=2300
+  _globals['_CREATESHAREDSESSIONREQUEST']._serialized_end=2402
+  _globals['_JOINSHAREDSESSIONREQUEST']._serialized_start=2404
+  _globals['_JOINSHAREDSESSIONREQUEST']._serialized_end=2486
+  _globals['_GETSHAREDSESSIONREQUEST']._serialized_start=2488
+  _globals['_GETSHAREDSESSIONREQUEST']._serialized_end=2550
+  _globals['_SETSESSIONMEMBERREQUEST']._serialized_start=2552
+  _globals['_SETSESSIONMEMBERREQUEST']._serialized_end=2647
+  _globals['_SHAREDSESSIONRESPONSE']._serialized_start=2649
+  _globals['_SHAREDSESSIONRESPONSE']._serialized_end=2757
+  _globals['_LLMSERVICE']._serialized_start=2760
+  _globals['_LLMSERVICE']._serialized_end=3621
 # @@protoc_insertion_point(module_scope)
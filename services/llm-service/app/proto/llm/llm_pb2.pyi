@@ -106,3 +106,147 @@ class EmbeddingResponse(_message.Message):
     embedding: _containers.RepeatedScalarFieldContainer[float]
     embedding_id: str
     def __init__(self, embedding: _Optional[_Iterable[float]] = ..., embedding_id: _Optional[str] = ...) -> None: ...
+
+class EmbedTextsRequest(_message.Message):
+    __slots__ = ("texts", "language")
+    TEXTS_FIELD_NUMBER: _ClassVar[int]
+    LANGUAGE_FIELD_NUMBER: _ClassVar[int]
+    texts: _containers.RepeatedScalarFieldContainer[str]
+    language: str
+    def __init__(self, texts: _Optional[_Iterable[str]] = ..., language: _Optional[str] = ...) -> None: ...
+
+class TextEmbedding(_message.Message):
+    __slots__ = ("values",)
+    VALUES_FIELD_NUMBER: _ClassVar[int]
+    values: _containers.RepeatedScalarFieldContainer[float]
+    def __init__(self, values: _Optional[_Iterable[float]] = ...) -> None: ...
+
+class EmbedTextsResponse(_message.Message):
+    __slots__ = ("embeddings", "model")
+    EMBEDDINGS_FIELD_NUMBER: _ClassVar[int]
+    MODEL_FIELD_NUMBER: _ClassVar[int]
+    embeddings: _containers.RepeatedCompositeFieldContainer[TextEmbedding]
+    model: str
+    def __init__(self, embeddings: _Optional[_Iterable[_Union[TextEmbedding, _Mapping]]] = ..., model: _Optional[str] = ...) -> None: ...
+
+class ChatTurn(_message.Message):
+    __slots__ = ("id", "session_id", "question", "answer", "sources", "latency_ms", "model", "created_at", "user_id")
+    ID_FIELD_NUMBER: _ClassVar[int]
+    SESSION_ID_FIELD_NUMBER: _ClassVar[int]
+    QUESTION_FIELD_NUMBER: _ClassVar[int]
+    ANSWER_FIELD_NUMBER: _ClassVar[int]
+    SOURCES_FIELD_NUMBER: _ClassVar[int]
+    LATENCY_MS_FIELD_NUMBER: _ClassVar[int]
+    MODEL_FIELD_NUMBER: _ClassVar[int]
+    CREATED_AT_FIELD_NUMBER: _ClassVar[int]
+    USER_ID_FIELD_NUMBER: _ClassVar[int]
+    id: str
+    session_id: str
+    question: str
+    answer: str
+    sources: _containers.RepeatedCompositeFieldContainer[SourceReference]
+    latency_ms: int
+    model: str
+    created_at: str
+    user_id: str
+    def __init__(self, id: _Optional[str] = ..., session_id: _Optional[str] = ..., question: _Optional[str] = ..., answer: _Optional[str] = ..., sources: _Optional[_Iterable[_Union[SourceReference, _Mapping]]] = ..., latency_ms: _Optional[int] = ..., model: _Optional[str] = ..., created_at: _Optional[str] = ..., user_id: _Optional[str] = ...) -> None: ...
+
+class SessionHistoryRequest(_message.Message):
+    __slots__ = ("session_id", "user_id")
+    SESSION_ID_FIELD_NUMBER: _ClassVar[int]
+    USER_ID_FIELD_NUMBER: _ClassVar[int]
+    session_id: str
+    user_id: str
+    def __init__(self, session_id: _Optional[str] = ..., user_id: _Optional[str] = ...) -> None: ...
+
+class SessionHistoryResponse(_message.Message):
+    __slots__ = ("success", "message", "turns")
+    SUCCESS_FIELD_NUMBER: _ClassVar[int]
+    MESSAGE_FIELD_NUMBER: _ClassVar[int]
+    TURNS_FIELD_NUMBER: _ClassVar[int]
+    success: bool
+    message: str
+    turns: _containers.RepeatedCompositeFieldContainer[ChatTurn]
+    def __init__(self, success: bool = ..., message: _Optional[str] = ..., turns: _Optional[_Iterable[_Union[ChatTurn, _Mapping]]] = ...) -> None: ...
+
+class SessionMember(_message.Message):
+    __slots__ = ("user_id", "role", "joined_at")
+    USER_ID_FIELD_NUMBER: _ClassVar[int]
+    ROLE_FIELD_NUMBER: _ClassVar[int]
+    JOINED_AT_FIELD_NUMBER: _ClassVar[int]
+    user_id: str
+    role: str
+    joined_at: str
+    def __init__(self, user_id: _Optional[str] = ..., role: _Optional[str] = ..., joined_at: _Optional[str] = ...) -> None: ...
+
+class SharedSession(_message.Message):
+    __slots__ = ("session_id", "owner_id", "title", "join_code", "default_role", "members", "created_at")
+    SESSION_ID_FIELD_NUMBER: _ClassVar[int]
+    OWNER_ID_FIELD_NUMBER: _ClassVar[int]
+    TITLE_FIELD_NUMBER: _ClassVar[int]
+    JOIN_CODE_FIELD_NUMBER: _ClassVar[int]
+    DEFAULT_ROLE_FIELD_NUMBER: _ClassVar[int]
+    MEMBERS_FIELD_NUMBER: _ClassVar[int]
+    CREATED_AT_FIELD_NUMBER: _ClassVar[int]
+    session_id: str
+    owner_id: str
+    title: str
+    join_code: str
+    default_role: str
+    members: _containers.RepeatedCompositeFieldContainer[SessionMember]
+    created_at: str
+    def __init__(self, session_id: _Optional[str] = ..., owner_id: _Optional[str] = ..., title: _Optional[str] = ..., join_code: _Optional[str] = ..., default_role: _Optional[str] = ..., members: _Optional[_Iterable[_Union[SessionMember, _Mapping]]] = ..., created_at: _Optional[str] = ...) -> None: ...
+
+class CreateSharedSessionRequest(_message.Message):
+    __slots__ = ("user_id", "title", "session_id", "default_role")
+    USER_ID_FIELD_NUMBER: _ClassVar[int]
+    TITLE_FIELD_NUMBER: _ClassVar[int]
+    SESSION_ID_FIELD_NUMBER: _ClassVar[int]
+    DEFAULT_ROLE_FIELD_NUMBER: _ClassVar[int]
+    user_id: str
+    title: str
+    session_id: str
+    default_role: str
+    def __init__(self, user_id: _Optional[str] = ..., title: _Optional[str] = ..., session_id: _Optional[str] = ..., default_role: _Optional[str] = ...) -> None: ...
+
+class JoinSharedSessionRequest(_message.Message):
+    __slots__ = ("session_id", "user_id", "join_code")
+    SESSION_ID_FIELD_NUMBER: _ClassVar[int]
+    USER_ID_FIELD_NUMBER: _ClassVar[int]
+    JOIN_CODE_FIELD_NUMBER: _ClassVar[int]
+    session_id: str
+    user_id: str
+    join_code: str
+    def __init__(self, session_id: _Optional[str] = ..., user_id: _Optional[str] = ..., join_code: _Optional[str] = ...) -> None: ...
+
+class GetSharedSessionRequest(_message.Message):
+    __slots__ = ("session_id", "user_id")
+    SESSION_ID_FIELD_NUMBER: _ClassVar[int]
+    USER_ID_FIELD_NUMBER: _ClassVar[int]
+    session_id: str
+    user_id: str
+    def __init__(self, session_id: _Optional[str] = ..., user_id: _Optional[str] = ...) -> None: ...
+
+class SetSessionMemberRequest(_message.Message):
+    __slots__ = ("session_id", "user_id", "member_id", "role")
+    SESSION_ID_FIELD_NUMBER: _ClassVar[int]
+    USER_ID_FIELD_NUMBER: _ClassVar[int]
+    MEMBER_ID_FIELD_NUMBER: _ClassVar[int]
+    ROLE_FIELD_NUMBER: _ClassVar[int]
+    session_id: str
+    user_id: str
+    member_id: str
+    role: str
+    def __init__(self, session_id: _Optional[str] = ..., user_id: _Optional[str] = ..., member_id: _Optional[str] = ..., role: _Optional[str] = ...) -> None: ...
+
+class SharedSessionResponse(_message.Message):
+    __slots__ = ("success", "message", "session", "role")
+    SUCCESS_FIELD_NUMBER: _ClassVar[int]
+    MESSAGE_FIELD_NUMBER: _ClassVar[int]
+    SESSION_FIELD_NUMBER: _ClassVar[int]
+    ROLE_FIELD_NUMBER: _ClassVar[int]
+    success: bool
+    message: str
+    session: SharedSession
+    role: str
+    def __init__(self, success: bool = ..., message: _Optional[str] = ..., session: _Optional[_Union[SharedSession, _Mapping]] = ..., role: _Optional[str] = ...) -> None: ...
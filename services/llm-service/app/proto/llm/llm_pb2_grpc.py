@@ -54,11 +54,46 @@ class LLMServiceStub(object):
                 request_serializer=llm_dot_llm__pb2.EmbeddingRequest.SerializeToString,
                 response_deserializer=llm_dot_llm__pb2.EmbeddingResponse.FromString,
                 _registered_method=True)
+        self.EmbedTexts = channel.unary_unary(
+                '/llm.LLMService/EmbedTexts',
+                request_serializer=llm_dot_llm__pb2.EmbedTextsRequest.SerializeToString,
+                response_deserializer=llm_dot_llm__pb2.EmbedTextsResponse.FromString,
+                _registered_method=True)
         self.UpsertChunks = channel.unary_unary(
                 '/llm.LLMService/UpsertChunks',
                 request_serializer=llm_dot_llm__pb2.UpsertChunksRequest.SerializeToString,
                 response_deserializer=llm_dot_llm__pb2.UpsertChunksResponse.FromString,
                 _registered_method=True)
+        self.DeleteChunks = channel.unary_unary(
+                '/llm.LLMService/DeleteChunks',
+                request_serializer=llm_dot_llm__pb2.DeleteChunksRequest.SerializeToString,
+                response_deserializer=llm_dot_llm__pb2.DeleteChunksResponse.FromString,
+                _registered_method=True)
+        self.GetSessionHistory = channel.unary_unary(
+                '/llm.LLMService/GetSessionHistory',
+                request_serializer=llm_dot_llm__pb2.SessionHistoryRequest.SerializeToString,
+                response_deserializer=llm_dot_llm__pb2.SessionHistoryResponse.FromString,
+                _registered_method=True)
+        self.CreateSharedSession = channel.unary_unary(
+                '/llm.LLMService/CreateSharedSession',
+                request_serializer=llm_dot_llm__pb2.CreateSharedSessionRequest.SerializeToString,
+                response_deserializer=llm_dot_llm__pb2.SharedSessionResponse.FromString,
+                _registered_method=True)
+        self.JoinSharedSession = channel.unary_unary(
+                '/llm.LLMService/JoinSharedSession',
+                request_serializer=llm_dot_llm__pb2.JoinSharedSessionRequest.SerializeToString,
+                response_deserializer=llm_dot_llm__pb2.SharedSessionResponse.FromString,
+                _registered_method=True)
+        self.GetSharedSession = channel.unary_unary(
+                '/llm.LLMService/GetSharedSession',
+                request_serializer=llm_dot_llm__pb2.GetSharedSessionRequest.SerializeToString,
+                response_deserializer=llm_dot_llm__pb2.SharedSessionResponse.FromString,
+                _registered_method=True)
+        self.SetSessionMember = channel.unary_unary(
+                '/llm.LLMService/SetSessionMember',
+                request_serializer=llm_dot_llm__pb2.SetSessionMemberRequest.SerializeToString,
+                response_deserializer=llm_dot_llm__pb2.SharedSessionResponse.FromString,
+                _registered_method=True)
 
 
 class LLMServiceServicer(object):
@@ -92,6 +127,13 @@ class LLMServiceServicer(object):
         context.set_details('Method not implemented!')
         raise NotImplementedError('Method not implemented!')
 
+    def EmbedTexts(self, request, context):
+        """只计算向量、不入库，用于比较任意文本（如主观题作答查重）
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
     def UpsertChunks(self, request, context):
         """批量分片入库（更高吞吐）
         """
@@ -99,6 +141,46 @@ class LLMServiceServicer(object):
         context.set_details('Method not implemented!')
         raise NotImplementedError('Method not implemented!')
 
+    def DeleteChunks(self, request, context):
+        """删除资料的分片，可按资料版本删除
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def GetSessionHistory(self, request, context):
+        """会话历史：按时间顺序返回某会话的全部问答轮次（用于导出）
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def CreateSharedSession(self, request, context):
+        """共享会话：多个用户加入同一 AI 会话，成员分为 owner / participant / viewer（只读，不能提问）
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def JoinSharedSession(self, request, context):
+        """Missing associated documentation comment in .proto file."""
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def GetSharedSession(self, request, context):
+        """Missing associated documentation comment in .proto file."""
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def SetSessionMember(self, request, context):
+        """会话 owner 修改成员角色或移除成员
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
 
 def add_LLMServiceServicer_to_server(servicer, server):
     rpc_method_handlers = {
@@ -122,11 +204,46 @@ def add_LLMServiceServicer_to_server(servicer, server):
                     request_deserializer=llm_dot_llm__pb2.EmbeddingRequest.FromString,
                     response_serializer=llm_dot_llm__pb2.EmbeddingResponse.SerializeToString,
             ),
+            'EmbedTexts': grpc.unary_unary_rpc_method_handler(
+                    servicer.EmbedTexts,
+                    request_deserializer=llm_dot_llm__pb2.EmbedTextsRequest.FromString,
+                    response_serializer=llm_dot_llm__pb2.EmbedTextsResponse.SerializeToString,
+            ),
             'UpsertChunks': grpc.unary_unary_rpc_method_handler(
                     servicer.UpsertChunks,
                     request_deserializer=llm_dot_llm__pb2.UpsertChunksRequest.FromString,
                     response_serializer=llm_dot_llm__pb2.UpsertChunksResponse.SerializeToString,
             ),
+            'DeleteChunks': grpc.unary_unary_rpc_method_handler(
+                    servicer.DeleteChunks,
+                    request_deserializer=llm_dot_llm__pb2.DeleteChunksRequest.FromString,
+                    response_serializer=llm_dot_llm__pb2.DeleteChunksResponse.SerializeToString,
+            ),
+            'GetSessionHistory': grpc.unary_unary_rpc_method_handler(
+                    servicer.GetSessionHistory,
+                    request_deserializer=llm_dot_llm__pb2.SessionHistoryRequest.FromString,
+                    response_serializer=llm_dot_llm__pb2.SessionHistoryResponse.SerializeToString,
+            ),
+            'CreateSharedSession': grpc.unary_unary_rpc_method_handler(
+                    servicer.CreateSharedSession,
+                    request_deserializer=llm_dot_llm__pb2.CreateSharedSessionRequest.FromString,
+                    response_serializer=llm_dot_llm__pb2.SharedSessionResponse.SerializeToString,
+            ),
+            'JoinSharedSession': grpc.unary_unary_rpc_method_handler(
+                    servicer.JoinSharedSession,
+                    request_deserializer=llm_dot_llm__pb2.JoinSharedSessionRequest.FromString,
+                    response_serializer=llm_dot_llm__pb2.SharedSessionResponse.SerializeToString,
+            ),
+            'GetSharedSession': grpc.unary_unary_rpc_method_handler(
+                    servicer.GetSharedSession,
+                    request_deserializer=llm_dot_llm__pb2.GetSharedSessionRequest.FromString,
+                    response_serializer=llm_dot_llm__pb2.SharedSessionResponse.SerializeToString,
+            ),
+            'SetSessionMember': grpc.unary_unary_rpc_method_handler(
+                    servicer.SetSessionMember,
+                    request_deserializer=llm_dot_llm__pb2.SetSessionMemberRequest.FromString,
+                    response_serializer=llm_dot_llm__pb2.SharedSessionResponse.SerializeToString,
+            ),
     }
     generic_handler = grpc.method_handlers_generic_handler(
             'llm.LLMService', rpc_method_handlers)
@@ -246,6 +363,33 @@ class LLMService(object):
             metadata,
             _registered_method=True)
 
+    @staticmethod
+    def EmbedTexts(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(
+            request,
+            target,
+            '/llm.LLMService/EmbedTexts',
+            llm_dot_llm__pb2.EmbedTextsRequest.SerializeToString,
+            llm_dot_llm__pb2.EmbedTextsResponse.FromString,
+            options,
+            channel_credentials,
+            insecure,
+            call_credentials,
+            compression,
+            wait_for_ready,
+            timeout,
+            metadata,
+            _registered_method=True)
+
     @staticmethod
     def UpsertChunks(request,
             target,
@@ -272,3 +416,165 @@ class LLMService(object):
             timeout,
             metadata,
             _registered_method=True)
+
+    @staticmethod
+    def DeleteChunks(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(
+            request,
+            target,
+            '/llm.LLMService/DeleteChunks',
+            llm_dot_llm__pb2.DeleteChunksRequest.SerializeToString,
+            llm_dot_llm__pb2.DeleteChunksResponse.FromString,
+            options,
+            channel_credentials,
+            insecure,
+            call_credentials,
+            compression,
+            wait_for_ready,
+            timeout,
+            metadata,
+            _registered_method=True)
+
+    @staticmethod
+    def GetSessionHistory(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(
+            request,
+            target,
+            '/llm.LLMService/GetSessionHistory',
+            llm_dot_llm__pb2.SessionHistoryRequest.SerializeToString,
+            llm_dot_llm__pb2.SessionHistoryResponse.FromString,
+            options,
+            channel_credentials,
+            insecure,
+            call_credentials,
+            compression,
+            wait_for_ready,
+            timeout,
+            metadata,
+            _registered_method=True)
+
+    @staticmethod
+    def CreateSharedSession(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(
+            request,
+            target,
+            '/llm.LLMService/CreateSharedSession',
+            llm_dot_llm__pb2.CreateSharedSessionRequest.SerializeToString,
+            llm_dot_llm__pb2.SharedSessionResponse.FromString,
+            options,
+            channel_credentials,
+            insecure,
+            call_credentials,
+            compression,
+            wait_for_ready,
+            timeout,
+            metadata,
+            _registered_method=True)
+
+    @staticmethod
+    def JoinSharedSession(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(
+            request,
+            target,
+            '/llm.LLMService/JoinSharedSession',
+            llm_dot_llm__pb2.JoinSharedSessionRequest.SerializeToString,
+            llm_dot_llm__pb2.SharedSessionResponse.FromString,
+            options,
+            channel_credentials,
+            insecure,
+            call_credentials,
+            compression,
+            wait_for_ready,
+            timeout,
+            metadata,
+            _registered_method=True)
+
+    @staticmethod
+    def GetSharedSession(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(
+            request,
+            target,
+            '/llm.LLMService/GetSharedSession',
+            llm_dot_llm__pb2.GetSharedSessionRequest.SerializeToString,
+            llm_dot_llm__pb2.SharedSessionResponse.FromString,
+            options,
+            channel_credentials,
+            insecure,
+            call_credentials,
+            compression,
+            wait_for_ready,
+            timeout,
+            metadata,
+            _registered_method=True)
+
+    @staticmethod
+    def SetSessionMember(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(
+            request,
+            target,
+            '/llm.LLMService/SetSessionMember',
+            llm_dot_llm__pb2.SetSessionMemberRequest.SerializeToString,
+            llm_dot_llm__pb2.SharedSessionResponse.FromString,
+            options,
+            channel_credentials,
+            insecure,
+            call_credentials,
+            compression,
+            wait_for_ready,
+            timeout,
+            metadata,
+            _registered_method=True)
@@ -0,0 +1,47 @@
+from __future__ import annotations
+
+from typing import List, Optional
+from sqlalchemy import select
+from sqlalchemy.ext.asyncio import AsyncSession
+
+from app.core.database import get_session_factory
+from app.models.models import ChatTurn
+
+
+class ChatTurnRepository:
+    def __init__(self, session: Optional[AsyncSession] = None):
+        self._external_session = session
+
+    async def _get_session(self) -> AsyncSession:
+        if self._external_session is not None:
+            return self._external_session
+        factory = get_session_factory()
+        if factory is None:
+            raise RuntimeError("Database not initialized: session factory is None")
+        return factory()
+
+    async def create(self, turn: ChatTurn) -> ChatTurn:
+        sess = await self._get_session()
+        close_needed = sess is not self._external_session
+        try:
+            sess.add(turn)
+            await sess.commit()
+            return turn
+        finally:
+            if close_needed:
+                await sess.close()
+
+    async def list_by_session(self, session_id: str, user_id: Optional[str]) -> List[ChatTurn]:
+        """Turns of a session owned by user_id (every user's turns when None), oldest first."""
+        sess = await self._get_session()
+        close_needed = sess is not self._external_session
+        try:
+            stmt = select(ChatTurn).where(ChatTurn.session_id == session_id)
+            if user_id is not None:
+                stmt = stmt.where(ChatTurn.user_id == user_id)
+            stmt = stmt.order_by(ChatTurn.created_at, ChatTurn.id)
+            res = await sess.execute(stmt)
+            return list(res.scalars().all())
+        finally:
+            if close_needed:
+                await sess.close()
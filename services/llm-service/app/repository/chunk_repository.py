@@ -2,7 +2,7 @@ from __future__ import annotations
 
 from typing import List, Optional
 import uuid
-from sqlalchemy import select
+from sqlalchemy import delete, select
 from sqlalchemy.ext.asyncio import AsyncSession
 
 from app.core.database import get_session_factory
@@ -42,3 +42,47 @@ class ChunkRepository:
         finally:
             if close_needed:
                 await sess.close()
+
+    async def create_many(self, rows: List[dict]) -> int:
+        """Insert rows of {material_id, content, vector, metadata, user_id?, vector_model?} in one transaction."""
+        sess = await self._get_session()
+        close_needed = sess is not self._external_session
+        try:
+            for row in rows:
+                metadata = row.get("metadata") or {}
+                obj = KnowledgeChunk(
+                    material_id=row["material_id"],
+                    content=row["content"],
+                    chunk_id=f"{row['material_id']}:{uuid.uuid4().hex[:8]}",
+                    user_id=row.get("user_id") or metadata.get("user_id", "unknown"),
+                    content_type=metadata.get("content_type", "text"),
+                    char_count=len(row["content"]),
+                    chunk_index=int(metadata.get("chunk_index") or 0),
+                    extra_metadata=metadata,
+                )
+                if row.get("vector_model"):
+                    obj.vector_model = row["vector_model"]
+                obj.set_vector(row["vector"])
+                sess.add(obj)
+            await sess.commit()
+            return len(rows)
+        finally:
+            if close_needed:
+                await sess.close()
+
+    async def delete_by_material(self, material_id: str, *, user_id: str | None = None, material_version: str | None = None) -> int:
+        """Delete a material's chunks, optionally only those owned by user_id and/or tagged with material_version."""
+        sess = await self._get_session()
+        close_needed = sess is not self._external_session
+        try:
+            stmt = delete(KnowledgeChunk).where(KnowledgeChunk.material_id == material_id)
+            if user_id:
+                stmt = stmt.where(KnowledgeChunk.user_id == user_id)
+            if material_version is not None:
+                stmt = stmt.where(KnowledgeChunk.extra_metadata["material_version"].as_string() == material_version)
+            res = await sess.execute(stmt)
+            await sess.commit()
+            return res.rowcount or 0
+        finally:
+            if close_needed:
+                await sess.close()
@@ -0,0 +1,31 @@
+from __future__ import annotations
+
+from typing import Optional
+from sqlalchemy.ext.asyncio import AsyncSession
+
+from app.core.database import get_session_factory
+from app.models.models import ModerationEvent
+
+
+class ModerationRepository:
+    def __init__(self, session: Optional[AsyncSession] = None):
+        self._external_session = session
+
+    async def _get_session(self) -> AsyncSession:
+        if self._external_session is not None:
+            return self._external_session
+        factory = get_session_factory()
+        if factory is None:
+            raise RuntimeError("Database not initialized: session factory is None")
+        return factory()
+
+    async def create(self, event: ModerationEvent) -> ModerationEvent:
+        sess = await self._get_session()
+        close_needed = sess is not self._external_session
+        try:
+            sess.add(event)
+            await sess.commit()
+            return event
+        finally:
+            if close_needed:
+                await sess.close()
@@ -0,0 +1,45 @@
+from __future__ import annotations
+
+from typing import Optional
+from sqlalchemy import select
+from sqlalchemy.ext.asyncio import AsyncSession
+
+from app.core.database import get_session_factory
+from app.models.models import ChatSessionSummary
+
+
+class SessionSummaryRepository:
+    def __init__(self, session: Optional[AsyncSession] = None):
+        self._external_session = session
+
+    async def _get_session(self) -> AsyncSession:
+        if self._external_session is not None:
+            return self._external_session
+        factory = get_session_factory()
+        if factory is None:
+            raise RuntimeError("Database not initialized: session factory is None")
+        return factory()
+
+    async def get(self, session_id: str, user_id: str) -> Optional[ChatSessionSummary]:
+        sess = await self._get_session()
+        close_needed = sess is not self._external_session
+        try:
+            stmt = select(ChatSessionSummary).where(
+                ChatSessionSummary.session_id == session_id, ChatSessionSummary.user_id == user_id
+            )
+            res = await sess.execute(stmt)
+            return res.scalars().first()
+        finally:
+            if close_needed:
+                await sess.close()
+
+    async def upsert(self, summary: ChatSessionSummary) -> ChatSessionSummary:
+        sess = await self._get_session()
+        close_needed = sess is not self._external_session
+        try:
+            merged = await sess.merge(summary)
+            await sess.commit()
+            return merged
+        finally:
+            if close_needed:
+                await sess.close()
@@ -0,0 +1,84 @@
+from __future__ import annotations
+
+from typing import List, Optional
+from sqlalchemy import delete, select
+from sqlalchemy.ext.asyncio import AsyncSession
+
+from app.core.database import get_session_factory
+from app.models.models import SharedChatSession, SharedSessionMember
+
+
+class SharedSessionRepository:
+    def __init__(self, session: Optional[AsyncSession] = None):
+        self._external_session = session
+
+    async def _get_session(self) -> AsyncSession:
+        if self._external_session is not None:
+            return self._external_session
+        factory = get_session_factory()
+        if factory is None:
+            raise RuntimeError("Database not initialized: session factory is None")
+        return factory()
+
+    async def get(self, session_id: str) -> Optional[SharedChatSession]:
+        sess = await self._get_session()
+        close_needed = sess is not self._external_session
+        try:
+            return await sess.get(SharedChatSession, session_id)
+        finally:
+            if close_needed:
+                await sess.close()
+
+    async def create(self, shared: SharedChatSession, owner: SharedSessionMember) -> SharedChatSession:
+        """Insert the session together with its owner membership."""
+        sess = await self._get_session()
+        close_needed = sess is not self._external_session
+        try:
+            sess.add(shared)
+            sess.add(owner)
+            await sess.commit()
+            return shared
+        finally:
+            if close_needed:
+                await sess.close()
+
+    async def list_members(self, session_id: str) -> List[SharedSessionMember]:
+        """Members in join order."""
+        sess = await self._get_session()
+        close_needed = sess is not self._external_session
+        try:
+            stmt = (
+                select(SharedSessionMember)
+                .where(SharedSessionMember.session_id == session_id)
+                .order_by(SharedSessionMember.joined_at, SharedSessionMember.user_id)
+            )
+            res = await sess.execute(stmt)
+            return list(res.scalars().all())
+        finally:
+            if close_needed:
+                await sess.close()
+
+    async def upsert_member(self, member: SharedSessionMember) -> SharedSessionMember:
+        sess = await self._get_session()
+        close_needed = sess is not self._external_session
+        try:
+            merged = await sess.merge(member)
+            await sess.commit()
+            return merged
+        finally:
+            if close_needed:
+                await sess.close()
+
+    async def delete_member(self, session_id: str, user_id: str) -> None:
+        sess = await self._get_session()
+        close_needed = sess is not self._external_session
+        try:
+            await sess.execute(
+                delete(SharedSessionMember).where(
+                    SharedSessionMember.session_id == session_id, SharedSessionMember.user_id == user_id
+                )
+            )
+            await sess.commit()
+        finally:
+            if close_needed:
+                await sess.close()
@@ -0,0 +1,106 @@
+from __future__ import annotations
+
+from collections import OrderedDict
+from dataclasses import dataclass, field
+from typing import Dict, List, Optional
+import asyncio
+import hashlib
+import json
+import re
+import time
+
+
+@dataclass
+class CachedAnswer:
+    answer: str
+    confidence: float
+    sources: List[Dict]
+    created_at: float = field(default_factory=time.time)
+    # scope of the cached entry, used for invalidation when materials change
+    user_id: str = ""
+    material_ids: List[str] = field(default_factory=list)
+
+
+_WS_RE = re.compile(r"\s+")
+_TRAILING_PUNCT = "?？!！。.,，;；~ "
+
+
+def normalize_question(question: str) -> str:
+    """Normalize a question so trivially different phrasings share a cache entry.
+
+    Lowercases, collapses whitespace and strips trailing punctuation, e.g.
+    "Summarize this chapter?" and "summarize  this chapter" map to the same key.
+    """
+    q = _WS_RE.sub(" ", (question or "").strip().lower())
+    return q.rstrip(_TRAILING_PUNCT)
+
+
+def make_cache_key(question: str, *, user_id: str, material_ids: List[str], model: str, params: Optional[Dict] = None) -> str:
+    """Cache key = (normalized question, material scope, model, retrieval params).
+
+    Without explicit material_ids retrieval is scoped to the user's materials, so the
+    user id becomes part of the scope; otherwise the sorted material ids are used.
+    """
+    scope = sorted(set(material_ids)) if material_ids else [f"user:{user_id}"]
+    raw = json.dumps([normalize_question(question), scope, model or "", params or {}], ensure_ascii=False, sort_keys=True)
+    return hashlib.sha256(raw.encode("utf-8")).hexdigest()
+
+
+class AnswerCache:
+    """Abstract answer cache interface.
+
+    Methods are awaitable so a shared backend (e.g., Redis) can be swapped in later.
+    """
+
+    async def get(self, key: str) -> Optional[CachedAnswer]:  # pragma: no cover - interface
+        raise NotImplementedError
+
+    async def set(self, key: str, value: CachedAnswer) -> None:  # pragma: no cover - interface
+        raise NotImplementedError
+
+    async def invalidate(self, *, user_id: str = "", material_id: str = "") -> int:  # pragma: no cover - interface
+        raise NotImplementedError
+
+
+class InMemoryAnswerCache(AnswerCache):
+    """Process-local TTL + LRU answer cache. Not shared across replicas."""
+
+    def __init__(self, ttl_seconds: int, max_entries: int) -> None:
+        self.ttl_seconds = ttl_seconds
+        self.max_entries = max_entries
+        self._data: "OrderedDict[str, CachedAnswer]" = OrderedDict()
+        self._lock = asyncio.Lock()
+
+    async def get(self, key: str) -> Optional[CachedAnswer]:
+        async with self._lock:
+            item = self._data.get(key)
+            if item is None:
+                return None
+            if time.time() - item.created_at > self.ttl_seconds:
+                del self._data[key]
+                return None
+            self._data.move_to_end(key)
+            return item
+
+    async def set(self, key: str, value: CachedAnswer) -> None:
+        if self.max_entries <= 0:
+            return
+        async with self._lock:
+            self._data[key] = value
+            self._data.move_to_end(key)
+            while len(self._data) > self.max_entries:
+                self._data.popitem(last=False)
+
+    async def invalidate(self, *, user_id: str = "", material_id: str = "") -> int:
+        """Drop entries whose scope covers the given material (or any entry of the user
+        that was scoped to all of the user's materials)."""
+        async with self._lock:
+            stale = [
+                k
+                for k, v in self._data.items()
+                if (material_id and material_id in v.material_ids)
+                or (user_id and not v.material_ids and v.user_id == user_id)
+            ]
+            for k in stale:
+                del self._data[k]
+            return len(stale)
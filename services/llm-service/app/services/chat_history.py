@@ -0,0 +1,95 @@
+from __future__ import annotations
+
+from dataclasses import dataclass, field
+from datetime import datetime
+from typing import Dict, List, Optional
+import asyncio
+import uuid
+
+from app.models.models import ChatTurn
+from app.repository.chat_turn_repository import ChatTurnRepository
+
+
+@dataclass
+class ChatTurnRecord:
+    """A full Q&A turn as kept for export (unlike MemoryStore, which only keeps prompt history)."""
+    session_id: str
+    user_id: str
+    question: str
+    answer: str
+    sources: List[Dict] = field(default_factory=list)
+    latency_ms: int = 0
+    model: str = ""
+    id: str = field(default_factory=lambda: uuid.uuid4().hex)
+    created_at: datetime = field(default_factory=datetime.utcnow)
+
+
+class ChatHistoryStore:
+    """Abstract store for persisted chat turns."""
+
+    async def append(self, turn: ChatTurnRecord) -> None:  # pragma: no cover - interface
+        raise NotImplementedError
+
+    async def list_turns(self, session_id: str, user_id: Optional[str]) -> List[ChatTurnRecord]:  # pragma: no cover - interface
+        """Turns of user_id oldest first; user_id=None returns every member's turns (shared sessions)."""
+        raise NotImplementedError
+
+
+class InMemoryChatHistoryStore(ChatHistoryStore):
+    """Process-local fallback used when no database is configured."""
+
+    # soft cap to avoid unbounded growth per session
+    MAX_TURNS_PER_SESSION = 500
+
+    def __init__(self) -> None:
+        # session_id -> list[ChatTurnRecord]
+        self._data: Dict[str, List[ChatTurnRecord]] = {}
+        self._lock = asyncio.Lock()
+
+    async def append(self, turn: ChatTurnRecord) -> None:
+        async with self._lock:
+            arr = self._data.setdefault(turn.session_id, [])
+            arr.append(turn)
+            if len(arr) > self.MAX_TURNS_PER_SESSION:
+                del arr[: len(arr) - self.MAX_TURNS_PER_SESSION]
+
+    async def list_turns(self, session_id: str, user_id: Optional[str]) -> List[ChatTurnRecord]:
+        async with self._lock:
+            arr = list(self._data.get(session_id, []))
+        return [t for t in arr if user_id is None or t.user_id == user_id]
+
+
+class DatabaseChatHistoryStore(ChatHistoryStore):
+    """Stores turns in the chat_turns table."""
+
+    async def append(self, turn: ChatTurnRecord) -> None:
+        await ChatTurnRepository().create(
+            ChatTurn(
+                id=turn.id,
+                session_id=turn.session_id,
+                user_id=turn.user_id,
+                question=turn.question,
+                answer=turn.answer,
+                sources=turn.sources,
+                latency_ms=turn.latency_ms,
+                model=turn.model,
+                created_at=turn.created_at,
+            )
+        )
+
+    async def list_turns(self, session_id: str, user_id: Optional[str]) -> List[ChatTurnRecord]:
+        rows = await ChatTurnRepository().list_by_session(session_id, user_id)
+        return [
+            ChatTurnRecord(
+                id=r.id,
+                session_id=r.session_id,
+                user_id=r.user_id,
+                question=r.question,
+                answer=r.answer,
+                sources=list(r.sources or []),
+                latency_ms=r.latency_ms or 0,
+                model=r.model or "",
+                created_at=r.created_at,
+            )
+            for r in rows
+        ]
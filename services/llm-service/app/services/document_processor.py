@@ -2,7 +2,7 @@ from __future__ import annotations
 
 import logging
 import re
-from typing import Dict, Any, List
+from typing import Dict, Any, List, Optional
 from dataclasses import dataclass
 
 import io
@@ -477,9 +477,10 @@ class DocumentProcessor:
         content: str,
         file_id: str,
         user_id: str,
-        file_type: str
+        file_type: str,
+        language: Optional[str] = None
     ) -> List[str]:
-        """完整的纯文本处理流程"""
+        """完整的纯文本处理流程，language 为资料的语言，未提供时沿用分块的默认语言"""
         try:
             logger.info(f"Processing text for file {file_id}")
 
@@ -492,6 +493,9 @@ class DocumentProcessor:
             if not chunks:
                 logger.warning(f"No chunks created from {file_id}")
                 return []
+            if language:
+                for chunk in chunks:
+                    chunk.language = language
 
             # 3. 向量化和存储
             chunk_ids = []
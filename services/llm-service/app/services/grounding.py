@@ -0,0 +1,89 @@
+from __future__ import annotations
+
+from dataclasses import dataclass, field
+from typing import Dict, List
+
+# grounding outcomes reported in response metadata
+GROUNDING_SKIPPED = "skipped"
+GROUNDING_GROUNDED = "grounded"
+GROUNDING_NOT_FOUND = "not_found"
+
+# confidence averages the scores of at most this many top passages
+_CONFIDENCE_TOP_N = 3
+
+
+@dataclass
+class GroundingVerdict:
+    status: str
+    best_score: float = 0.0
+    threshold: float = 0.0
+    # calibrated answer confidence derived from retrieval scores
+    confidence: float = 0.0
+    suggestions: List[str] = field(default_factory=list)
+
+    @property
+    def grounded(self) -> bool:
+        return self.status != GROUNDING_NOT_FOUND
+
+
+def hit_score(hit: Dict) -> float:
+    """Relevance of a retrieved passage; rerank scores are preferred over vector similarity."""
+    return float(hit.get("rerank_score", hit.get("similarity_score", 0.0)))
+
+
+def grounding_threshold(context: Dict[str, str], default: float) -> float:
+    """Per-request override via context["grounding_threshold"], clamped to [0, 1]."""
+    threshold = default
+    try:
+        if context and context.get("grounding_threshold"):
+            threshold = float(context["grounding_threshold"])
+    except Exception:
+        threshold = default
+    return max(0.0, min(1.0, threshold))
+
+
+def check_grounding(hits: List[Dict], *, threshold: float, material_ids: List[str]) -> GroundingVerdict:
+    """The answer is grounded when at least one passage scores at or above threshold.
+
+    A threshold of 0 only requires that something was retrieved.
+    """
+    scores = sorted((hit_score(h) for h in hits), reverse=True)
+    best = scores[0] if scores else 0.0
+    if scores and best >= threshold:
+        top = scores[:_CONFIDENCE_TOP_N]
+        confidence = max(0.0, min(1.0, sum(top) / len(top)))
+        return GroundingVerdict(GROUNDING_GROUNDED, best_score=best, threshold=threshold, confidence=confidence)
+    return GroundingVerdict(
+        GROUNDING_NOT_FOUND,
+        best_score=best,
+        threshold=threshold,
+        suggestions=not_found_suggestions(hits, material_ids=material_ids),
+    )
+
+
+def not_found_suggestions(hits: List[Dict], *, material_ids: List[str]) -> List[str]:
+    """Next steps offered to the student when their materials do not cover the question."""
+    out: List[str] = []
+    if hits:
+        closest = max(hits, key=hit_score)
+        snippet = " ".join((closest.get("content") or "").split())[:80]
+        out.append(f'The closest passage only loosely matches: "{snippet}". Open that material to check whether it covers your question.')
+    else:
+        out.append("If you uploaded the material recently, wait until it finishes processing and ask again.")
+    if material_ids:
+        out.append("Ask again without selecting specific materials to search all of your materials.")
+    else:
+        out.append("Upload a material that covers this topic.")
+    out.append("Rephrase the question using the terms your materials use, or ask about a narrower concept.")
+    return out
+
+
+def grounding_metadata(verdict: GroundingVerdict) -> Dict[str, str]:
+    meta = {"grounding": verdict.status}
+    if verdict.status != GROUNDING_SKIPPED:
+        meta["grounding_best_score"] = f"{verdict.best_score:.4f}"
+        meta["grounding_threshold"] = f"{verdict.threshold:.4f}"
+    if verdict.suggestions:
+        # one suggestion per line; the gateway splits them into a list
+        meta["grounding_suggestions"] = "\n".join(verdict.suggestions)
+    return meta
@@ -140,6 +140,8 @@ class KafkaFileProcessor:
             user_id = message_data.get('user_id')
             text = message_data.get('text')
             source = message_data.get('source', 'unknown')
+            # material-service 检测到的资料语言，ocr-service 发布的消息没有该字段
+            language = message_data.get('language') or None
             
             if not file_id or not text:
                 logger.warning("Invalid message: missing material_id or text")
@@ -156,7 +158,8 @@ class KafkaFileProcessor:
                 content=text,
                 file_id=file_id,
                 user_id=user_id,
-                file_type=source
+                file_type=source,
+                language=language
             )
             
             logger.info(f"Processed file {file_id}: {len(chunks)} chunks created")
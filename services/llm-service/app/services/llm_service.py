@@ -1,14 +1,24 @@
 from __future__ import annotations
 
 from typing import Dict, List
+import time
 import uuid
 from sqlalchemy import text
 
+from app.config import get_settings
+from app.core import feature_flags
 from app.core.vector_store import InMemoryVectorStore
 from app.core.database import get_session_factory
 from app.repository.chunk_repository import ChunkRepository
 from app.services.openai_client import OpenAIClient
 from app.services.memory import InMemoryMemoryStore, MemoryStore
+from app.services.chat_history import ChatHistoryStore, ChatTurnRecord, DatabaseChatHistoryStore, InMemoryChatHistoryStore
+from app.services.answer_cache import AnswerCache, CachedAnswer, InMemoryAnswerCache, make_cache_key
+from app.services.reranker import Reranker, build_reranker, rerank_hits
+from app.services.moderation import STAGE_INPUT, STAGE_OUTPUT, ContentModeration, ModerationVerdict, build_moderator, moderation_metadata
+from app.services.grounding import GROUNDING_SKIPPED, GroundingVerdict, check_grounding, grounding_metadata, grounding_threshold, hit_score
+from app.services.session_summary import DatabaseSessionSummaryStore, InMemorySessionSummaryStore, SessionSummarizer
+from app.services.shared_sessions import DatabaseSharedSessionStore, InMemorySharedSessionStore, SharedSessions
 
 
 class LLMService:
@@ -23,6 +33,43 @@ class LLMService:
         self._oa = OpenAIClient()
         # session memory (pluggable)
         self._memory: MemoryStore = InMemoryMemoryStore()
+        # full Q&A turns for history export (DB when configured)
+        self._history: ChatHistoryStore = DatabaseChatHistoryStore() if self._db_enabled else InMemoryChatHistoryStore()
+        # membership and ACLs of sessions shared by several users
+        self.shared_sessions = SharedSessions(
+            DatabaseSharedSessionStore() if self._db_enabled else InMemorySharedSessionStore(),
+            self._history,
+        )
+        # answer cache for repeated questions (pluggable, None when disabled)
+        settings = get_settings()
+        # optional reranking stage after retrieval (None when no backend is configured)
+        self._reranker: Reranker | None = build_reranker(self._oa)
+        self._rerank_multiplier = max(1, settings.rerank_candidate_multiplier)
+        self._rerank_timeout = settings.rerank_timeout_seconds
+        # content moderation for questions and generated answers (no-op when no backend is configured)
+        self.moderation = ContentModeration(build_moderator(self._oa))
+        # grounding check: refuse to answer from thin retrieval instead of guessing
+        self._grounding_enabled = settings.grounding_enabled
+        self._grounding_min_score = settings.grounding_min_score
+        self.not_found_message = settings.grounding_not_found_message
+        # rolling summary of turns older than the history window (None when disabled)
+        self._summarizer: SessionSummarizer | None = None
+        if settings.session_summary_enabled:
+            self._summarizer = SessionSummarizer(
+                self._oa,
+                self._history,
+                DatabaseSessionSummaryStore() if self._db_enabled else InMemorySessionSummaryStore(),
+                batch_turns=settings.session_summary_batch_turns,
+                max_chars=settings.session_summary_max_chars,
+                ignore_answers={self.moderation.block_message, self.not_found_message},
+                shared=self.shared_sessions,
+            )
+        self._answer_cache: AnswerCache | None = None
+        if settings.answer_cache_enabled:
+            self._answer_cache = InMemoryAnswerCache(
+                ttl_seconds=settings.answer_cache_ttl_seconds,
+                max_entries=settings.answer_cache_max_entries,
+            )
 
     # ---- History selection helpers (token-budget first, turns as fallback) ----
     def _get_encoding_name(self) -> str:
@@ -55,6 +102,15 @@ class LLMService:
     def _messages_tokens(self, msgs: List[Dict]) -> int:
         return sum(self._estimate_tokens((m.get("role") or "") + (m.get("content") or "")) for m in msgs)
 
+    def _usage_metadata(self, messages: List[Dict], answer: str) -> Dict[str, int]:
+        """Estimated token usage of one chat call, reported so the gateway can enforce per-user quotas.
+        Zero when no external model is configured (placeholder answers cost nothing)."""
+        if not self._oa.is_enabled():
+            return {"prompt_tokens": 0, "completion_tokens": 0, "total_tokens": 0}
+        prompt = self._messages_tokens(messages)
+        completion = self._estimate_tokens(answer)
+        return {"prompt_tokens": prompt, "completion_tokens": completion, "total_tokens": prompt + completion}
+
     def _trim_history_by_tokens(self, history: List[Dict], limit_tokens: int) -> List[Dict]:
         if limit_tokens <= 0 or not history:
             return []
@@ -103,7 +159,8 @@ class LLMService:
         trimmed2 = all_history[k:]
         return trimmed2, max_turns, self._messages_tokens(trimmed2)
 
-    async def _build_messages(self, question: str, user_id: str, context: Dict[str, str], hits: List[Dict]) -> tuple[List[Dict], str, int, int]:
+    async def _build_messages(self, question: str, user_id: str, context: Dict[str, str], hits: List[Dict]) -> tuple[List[Dict], str, int, int, int]:
+        """Returns (messages, session_id, used_turns, used_tokens, summarized_turns)."""
         # session id and nominal turns (for deciding how much to fetch from store)
         session_id, nominal_turns = self._get_session_params(context or {})
         history_msgs: list[Dict] = []
@@ -117,13 +174,41 @@ class LLMService:
         # apply token-based trimming (or turns fallback)
         trimmed_history, used_turns, used_tokens = self._pick_history(history_msgs, context or {})
 
+        # earlier turns that no longer fit the window survive as a rolling summary
+        summary_msgs: list[Dict] = []
+        summarized_turns = 0
+        if self._summarizer is not None and self._history_enabled(context or {}):
+            summary = await self._summarizer.get(session_id, user_id)
+            if summary is not None:
+                summary_msgs.append({"role": "system", "content": f"Summary of the earlier conversation in this session:\n{summary.summary}"})
+                summarized_turns = summary.summarized_turns
+
         context_snippets = "\n\n".join(h["content"] for h in hits)
         messages = [
             {"role": "system", "content": "You are a helpful study assistant. Answer concisely using the provided context."},
+            *summary_msgs,
             *trimmed_history,
             {"role": "user", "content": f"Question: {question}\n\nContext:\n{context_snippets}"},
         ]
-        return messages, session_id, used_turns, used_tokens
+        return messages, session_id, used_turns, used_tokens, summarized_turns
+
+    def _history_enabled(self, context: Dict[str, str]) -> bool:
+        try:
+            if context and int(context.get("max_history_tokens", "0") or "0") > 0:
+                return True
+        except Exception:
+            pass
+        _, nominal_turns = self._get_session_params(context)
+        return nominal_turns > 0
+
+    def schedule_summary(self, session_id: str, user_id: str, context: Dict[str, str], used_turns: int) -> None:
+        """After a turn is recorded, fold the turns that fall out of the next window into the session summary.
+
+        The next window holds the newest used_turns turns (at least the one just recorded, e.g. when the
+        prompt memory was lost on restart); everything older is summarized.
+        """
+        if self._summarizer is not None and self._history_enabled(context or {}):
+            self._summarizer.schedule(session_id, user_id, keep_turns=max(1, used_turns))
 
     def _get_session_params(self, context: Dict[str, str]) -> tuple[str, int]:
         had_session = bool(context.get("session_id")) if context else False
@@ -145,6 +230,42 @@ class LLMService:
             session_id = uuid.uuid4().hex
         return session_id, mht
 
+    # ---- Retrieval parameters (optional, passed via QuestionRequest.context) ----
+    DEFAULT_TOP_K = 3
+    MAX_TOP_K = 50
+
+    def _retrieval_params(self, context: Dict[str, str]) -> Dict:
+        """Parse optional retrieval tuning from context.
+
+        - top_k: number of passages to retrieve (clamped to [1, 50], default 3)
+        - similarity_threshold: drop passages scoring below this value (clamped to [0, 1], default 0)
+        - rerank: "true"/"false" to force reranking on/off; None leaves the service default
+        """
+        context = context or {}
+        top_k = self.DEFAULT_TOP_K
+        try:
+            if context.get("top_k"):
+                top_k = int(context["top_k"])
+        except Exception:
+            top_k = self.DEFAULT_TOP_K
+        top_k = max(1, min(self.MAX_TOP_K, top_k))
+
+        threshold = 0.0
+        try:
+            if context.get("similarity_threshold"):
+                threshold = float(context["similarity_threshold"])
+        except Exception:
+            threshold = 0.0
+        threshold = max(0.0, min(1.0, threshold))
+
+        rerank = None
+        raw = (context.get("rerank") or "").lower()
+        if raw in ("1", "true", "yes"):
+            rerank = True
+        elif raw in ("0", "false", "no"):
+            rerank = False
+        return {"top_k": top_k, "min_score": threshold, "rerank": rerank}
+
     async def generate_embeddings(self, content: str, material_id: str, content_type: str, user_id: str = "unknown") -> Dict:
         # prefer external embeddings when configured
         if self._oa.is_enabled():
@@ -188,33 +309,65 @@ class LLMService:
             "embedding_id": f"{material_id}:0",
         }
 
-    async def upsert_chunks(self, *, user_id: str, material_id: str, chunks: list[dict]) -> int:
+    MAX_EMBED_TEXTS = 64
+
+    async def embed_texts(self, texts: List[str], language: str | None = None) -> tuple[List[List[float]], str]:
+        """Embed texts without storing them, for ad-hoc comparisons (e.g. essay plagiarism checks).
+
+        Returns (vectors, model). Vectors produced by different models are not comparable, so callers
+        should keep the model name next to any vector they persist.
+        """
+        if len(texts) > self.MAX_EMBED_TEXTS:
+            raise ValueError(f"at most {self.MAX_EMBED_TEXTS} texts per request")
+        if self._oa.is_enabled():
+            model = self._oa.embedding_model_for(language)
+            # sequential for MVP, same as upsert_chunks
+            return [await self._oa.aembedding(t, model=model) for t in texts], model
+        from app.core.embedding import embed_text
+        return [embed_text(t, dim=self.store.dim) for t in texts], "builtin"
+
+    async def upsert_chunks(self, *, user_id: str, material_id: str, chunks: list[dict], replace: bool = False) -> int:
         """Batch upsert chunks: embed -> write in-memory store -> persist to DB if configured.
 
         chunks: list of { content: str, timecode?: str, page?: int, metadata?: dict }
+        metadata is stored as-is and returned by semantic_search; material-service tags chunks with
+        provenance (source_type, char_start, char_end, chunk_index, material_version).
+        replace: drop the material's existing chunks first (used when a material is re-processed);
+        old chunks are kept if embedding the new ones fails.
+        The embedding model is picked per chunk from metadata "language" (see Settings.embedding_models)
+        and recorded as the row's vector_model.
         Returns number of inserted chunks.
         """
         if not chunks:
             return 0
         # prepare embeddings (external provider if configured)
-        embedded: list[tuple[Dict, List[float]]] = []
+        embedded: list[tuple[Dict, List[float], str]] = []
         if self._oa.is_enabled():
             # sequential for MVP; consider concurrency with asyncio.gather later
             for ch in chunks:
-                vec = await self._oa.aembedding(ch.get("content", ""))
-                embedded.append((ch, vec))
+                model = self._oa.embedding_model_for((ch.get("metadata") or {}).get("language"))
+                vec = await self._oa.aembedding(ch.get("content", ""), model=model)
+                embedded.append((ch, vec, model))
         else:
             # local embedding
             for ch in chunks:
                 # InMemoryVectorStore.upsert computes embeddings; reuse helper here by calling private embed path
                 from app.core.embedding import embed_text
                 vec = embed_text(ch.get("content", ""), dim=self.store.dim)
-                embedded.append((ch, vec))
+                embedded.append((ch, vec, "builtin"))
+
+        if replace:
+            removed = self.store.remove_material(material_id)
+            if self._db_enabled:
+                removed = await ChunkRepository().delete_by_material(material_id)
+            print(f"[INFO] Replacing {removed} existing chunks for material {material_id}")
 
         # write into in-memory store and accumulate DB rows
         db_rows: list[dict] = []
-        for ch, vec in embedded:
+        for ch, vec, model in embedded:
             meta = dict(ch.get("metadata") or {})
+            # the in-memory search path filters on the owner
+            meta["user_id"] = user_id
             # enrich metadata minimally
             if ch.get("timecode"):
                 meta["timecode"] = str(ch.get("timecode"))
@@ -222,12 +375,18 @@ class LLMService:
                 meta["page"] = str(int(ch.get("page")))
             item = self.store.upsert_with_vector(material_id=material_id, content=ch.get("content", ""), vector=vec, metadata=meta)
             db_rows.append({
+                "user_id": user_id,
                 "material_id": material_id,
                 "content": item.content,
                 "vector": item.vector,
+                "vector_model": model,
                 "metadata": item.metadata,
             })
 
+        # cached answers for this material may now be stale
+        if self._answer_cache is not None:
+            await self._answer_cache.invalidate(user_id=user_id, material_id=material_id)
+
         # persist to DB if enabled
         if self._db_enabled and db_rows:
             try:
@@ -238,7 +397,53 @@ class LLMService:
                 pass
         return len(db_rows)
 
-    async def semantic_search(self, query: str, *, user_id: str, top_k: int = 5, material_ids: List[str] = None) -> List[Dict]:
+    async def delete_chunks(self, *, user_id: str, material_id: str, material_version: str = "") -> int:
+        """Delete a material's chunks; with material_version, only chunks tagged with that version.
+
+        Returns the number of deleted chunks (database rows when persistence is enabled).
+        """
+        version = material_version or None
+        removed = self.store.remove_material(material_id, version)
+        if self._db_enabled:
+            removed = await ChunkRepository().delete_by_material(material_id, user_id=user_id, material_version=version)
+        if self._answer_cache is not None:
+            await self._answer_cache.invalidate(user_id=user_id, material_id=material_id)
+        print(f"[INFO] Deleted {removed} chunks for material {material_id} (version={version or 'all'})")
+        return removed
+
+    async def semantic_search(
+        self,
+        query: str,
+        *,
+        user_id: str,
+        top_k: int = 5,
+        material_ids: List[str] = None,
+        min_score: float = 0.0,
+        rerank: bool | None = None,
+    ) -> List[Dict]:
+        """Retrieve passages for query. Results scoring below min_score are dropped.
+
+        When reranking applies, a larger candidate pool (top_k * multiplier) is retrieved and
+        reordered by the reranker; hits then carry "rerank_score". rerank=None defers to the
+        "reranker" feature flag (per-user rollout).
+        """
+        use_rerank = self._should_rerank(user_id, rerank)
+        fetch_k = min(self.MAX_TOP_K, top_k * self._rerank_multiplier) if use_rerank else top_k
+        hits = await self._retrieve(query, user_id=user_id, top_k=fetch_k, material_ids=material_ids)
+        if min_score > 0:
+            hits = [h for h in hits if h["similarity_score"] >= min_score]
+        if use_rerank:
+            hits = await rerank_hits(self._reranker, query, hits, top_k=top_k, timeout=self._rerank_timeout)
+        return hits
+
+    def _should_rerank(self, user_id: str, rerank: bool | None) -> bool:
+        if self._reranker is None or rerank is False:
+            return False
+        if rerank is True:
+            return True
+        return feature_flags.enabled_for(feature_flags.RERANKER, user_id, False)
+
+    async def _retrieve(self, query: str, *, user_id: str, top_k: int = 5, material_ids: List[str] = None) -> List[Dict]:
         print(f"[DEBUG] Received SemanticSearch request: query='{query}', user_id='{user_id}', top_k={top_k}, material_ids={material_ids}")
         
         # 数据库搜索优先
@@ -250,7 +455,7 @@ class LLMService:
                     if material_ids and len(material_ids) == 1:
                         # 最简单的SQL，只筛选单个material_id
                         sql = """
-                        SELECT material_id, content, metadata, 1.0 as score
+                        SELECT material_id, content, metadata, 1.0 as score, chunk_id
                         FROM knowledge_chunks 
                         WHERE material_id = :material_id
                         LIMIT :limit
@@ -262,7 +467,7 @@ class LLMService:
                     else:
                         # 只按user_id过滤
                         sql = """
-                        SELECT material_id, content, metadata, 1.0 as score
+                        SELECT material_id, content, metadata, 1.0 as score, chunk_id
                         FROM knowledge_chunks 
                         WHERE user_id = :user_id
                         LIMIT :limit
@@ -277,11 +482,15 @@ class LLMService:
                     res = await sess.execute(text(sql), params)
                     out = []
                     for row in res:
+                        meta = dict(row[2] or {})
+                        # expose the chunk identity so callers (e.g. quiz generation) can cite it
+                        if row[4]:
+                            meta["chunk_id"] = row[4]
                         out.append({
                             "material_id": row[0],
                             "content": row[1],
                             "similarity_score": float(row[3]),
-                            "metadata": row[2] or {},
+                            "metadata": meta,
                         })
                     print(f"[DEBUG] SemanticSearch found {len(out)} hits")
                     return out
@@ -319,11 +528,208 @@ class LLMService:
         print(f"[DEBUG] In-memory search found {len(out)} hits")
         return out
 
+    @staticmethod
+    def _cache_bypassed(context: Dict[str, str]) -> bool:
+        if not context:
+            return False
+        if (context.get("no_cache") or "").lower() in ("1", "true", "yes"):
+            return True
+        return (context.get("cache") or "").lower() in ("0", "false", "off", "bypass")
+
+    async def _cache_lookup(self, question: str, user_id: str, material_ids: List[str], context: Dict[str, str]) -> tuple[str, str]:
+        """Returns (cache_key, cache_status). cache_key is empty when the answer must not be cached.
+
+        Answers are only cached for questions without prior conversation history, since
+        follow-up turns depend on the session and cannot be shared.
+        """
+        if self._answer_cache is None:
+            return "", "disabled"
+        session_id = (context.get("session_id") or "") if context else ""
+        if session_id:
+            try:
+                if await self._memory.history(session_id, max_turns=1):
+                    return "", "skip"
+            except Exception:
+                return "", "skip"
+        key = make_cache_key(
+            question,
+            user_id=user_id,
+            material_ids=material_ids or [],
+            model=self._oa.chat_model or "",
+            params=self._retrieval_params(context),
+        )
+        # bypass skips the read but still refreshes the entry with the new answer
+        return key, "bypass" if self._cache_bypassed(context) else "miss"
+
+    def _answer_model(self) -> str:
+        return (self._oa.chat_model or "") if self._oa.is_enabled() else ""
+
+    async def record_turn(
+        self,
+        *,
+        session_id: str,
+        user_id: str,
+        question: str,
+        answer: str,
+        sources: List[Dict],
+        started_at: float,
+        model: str,
+    ) -> None:
+        """Persist a full Q&A turn for later export (best-effort, never raises)."""
+        try:
+            await self._history.append(
+                ChatTurnRecord(
+                    session_id=session_id,
+                    user_id=user_id,
+                    question=question,
+                    answer=answer,
+                    sources=sources,
+                    latency_ms=int((time.monotonic() - started_at) * 1000),
+                    model=model,
+                )
+            )
+        except Exception as e:
+            print(f"[ERROR] Failed to record chat turn for session {session_id}: {e}")
+
+    async def session_history(self, session_id: str, user_id: str) -> List[ChatTurnRecord]:
+        """Turns of user_id, or of every member when the session is shared with user_id."""
+        history_user, _ = await self.shared_sessions.history_scope(session_id, user_id)
+        return await self._history.list_turns(session_id, history_user)
+
+    async def blocked_response(
+        self, question: str, user_id: str, context: Dict[str, str], verdict: ModerationVerdict, started_at: float
+    ) -> Dict:
+        """Refusal returned when the question is blocked by moderation; the model is never called."""
+        session_id, _ = self._get_session_params(context or {})
+        answer = self.moderation.block_message
+        await self.record_turn(
+            session_id=session_id,
+            user_id=user_id,
+            question=question,
+            answer=answer,
+            sources=[],
+            started_at=started_at,
+            model="",
+        )
+        return {
+            "answer": answer,
+            "confidence": 0.0,
+            "sources": [],
+            "metadata": {
+                "note": "mvp",
+                "session_id": session_id,
+                "used_history_turns": 0,
+                "used_history_tokens": 0,
+                **moderation_metadata(verdict),
+            },
+        }
+
+    def grounding_check(self, question: str, hits: List[Dict], context: Dict[str, str], material_ids: List[str]) -> GroundingVerdict:
+        """Check that retrieval supports an answer. Internal tasks (context "task", e.g. quiz generation)
+        carry their own content in the prompt and are not checked; their confidence stays at 0.5."""
+        context = context or {}
+        if not self._grounding_enabled or context.get("task"):
+            return GroundingVerdict(GROUNDING_SKIPPED, confidence=0.5)
+        threshold = grounding_threshold(context, self._grounding_min_score)
+        return check_grounding(hits, threshold=threshold, material_ids=material_ids or [])
+
+    async def not_found_response(
+        self,
+        question: str,
+        user_id: str,
+        context: Dict[str, str],
+        hits: List[Dict],
+        verdict: GroundingVerdict,
+        input_verdict: ModerationVerdict,
+        started_at: float,
+    ) -> Dict:
+        """Explicit "not found in your materials" answer; the model is never called.
+
+        The closest passages are still returned as sources so the student can see what was searched.
+        The turn is recorded for export but kept out of session memory and the answer cache.
+        """
+        session_id, _ = self._get_session_params(context or {})
+        sources = [
+            {
+                "material_id": h["material_id"],
+                "content_snippet": h["content"][:120],
+                "relevance_score": hit_score(h),
+            }
+            for h in hits
+        ]
+        await self.record_turn(
+            session_id=session_id,
+            user_id=user_id,
+            question=question,
+            answer=self.not_found_message,
+            sources=sources,
+            started_at=started_at,
+            model="",
+        )
+        return {
+            "answer": self.not_found_message,
+            "confidence": 0.0,
+            "sources": sources,
+            "metadata": {
+                "note": "mvp",
+                "session_id": session_id,
+                "used_history_turns": 0,
+                "used_history_tokens": 0,
+                **grounding_metadata(verdict),
+                **moderation_metadata(input_verdict),
+            },
+        }
+
     async def ask_question(self, question: str, user_id: str, material_ids: List[str], context: Dict[str, str]) -> Dict:
+        started_at = time.monotonic()
+        input_verdict = await self.moderation.check(
+            question, stage=STAGE_INPUT, user_id=user_id, session_id=(context or {}).get("session_id", "")
+        )
+        if input_verdict.blocked:
+            return await self.blocked_response(question, user_id, context or {}, input_verdict, started_at)
+
+        cache_key, cache_status = await self._cache_lookup(question, user_id, material_ids, context or {})
+        if cache_key and cache_status == "miss":
+            cached = await self._answer_cache.get(cache_key)
+            if cached is not None:
+                session_id, _ = self._get_session_params(context or {})
+                try:
+                    await self._memory.append(session_id, role="user", content=question)
+                    await self._memory.append(session_id, role="assistant", content=cached.answer)
+                except Exception:
+                    pass
+                await self.record_turn(
+                    session_id=session_id,
+                    user_id=user_id,
+                    question=question,
+                    answer=cached.answer,
+                    sources=cached.sources,
+                    started_at=started_at,
+                    model=self._answer_model(),
+                )
+                return {
+                    "answer": cached.answer,
+                    "confidence": cached.confidence,
+                    "sources": cached.sources,
+                    "metadata": {
+                        "note": "mvp",
+                        "session_id": session_id,
+                        "used_history_turns": 0,
+                        "used_history_tokens": 0,
+                        "cache": "hit",
+                        "cache_age_seconds": int(time.time() - cached.created_at),
+                        **moderation_metadata(input_verdict),
+                    },
+                }
+
         # use semantic search as grounding
-        hits = await self.semantic_search(question, user_id=user_id, top_k=3, material_ids=material_ids or None)
+        retrieval = self._retrieval_params(context or {})
+        hits = await self.semantic_search(question, user_id=user_id, material_ids=material_ids or None, **retrieval)
+        grounding = self.grounding_check(question, hits, context or {}, material_ids)
+        if not grounding.grounded:
+            return await self.not_found_response(question, user_id, context or {}, hits, grounding, input_verdict, started_at)
         # build messages with token/turns aware history
-        base_msgs, session_id, used_turns, used_tokens = await self._build_messages(
+        base_msgs, session_id, used_turns, used_tokens, summarized_turns = await self._build_messages(
             question, user_id=user_id, context=context or {}, hits=hits
         )
 
@@ -333,37 +739,73 @@ class LLMService:
         else:
             # trivial answer for MVP
             answer = f"Based on {len(hits)} context passages, here is a placeholder answer to: {question}"
+        usage = self._usage_metadata(base_msgs, answer)
 
-        # persist memory (best-effort)
-        try:
-            await self._memory.append(session_id, role="user", content=question)
-            await self._memory.append(session_id, role="assistant", content=answer)
-        except Exception:
-            pass
+        output_verdict = await self.moderation.check(answer, stage=STAGE_OUTPUT, user_id=user_id, session_id=session_id)
+        if output_verdict.blocked:
+            # blocked answers are replaced and kept out of memory, conversation vectors and the cache
+            answer = self.moderation.block_message
+            cache_key = ""
+        else:
+            # persist memory (best-effort)
+            try:
+                await self._memory.append(session_id, role="user", content=question)
+                await self._memory.append(session_id, role="assistant", content=answer)
+            except Exception:
+                pass
 
-        # also vectorize the conversational turns for future retrieval (best-effort)
-        try:
-            conv_material_id = f"session:{session_id}"
-            await self.generate_embeddings(question, material_id=conv_material_id, content_type="conversation", user_id=user_id)
-            await self.generate_embeddings(answer, material_id=conv_material_id, content_type="conversation", user_id=user_id)
-        except Exception:
-            pass
+            # also vectorize the conversational turns for future retrieval (best-effort)
+            try:
+                conv_material_id = f"session:{session_id}"
+                await self.generate_embeddings(question, material_id=conv_material_id, content_type="conversation", user_id=user_id)
+                await self.generate_embeddings(answer, material_id=conv_material_id, content_type="conversation", user_id=user_id)
+            except Exception:
+                pass
+
+        sources = [
+            {
+                "material_id": h["material_id"],
+                "content_snippet": h["content"][:120],
+                "relevance_score": h.get("rerank_score", h["similarity_score"]),
+            }
+            for h in hits
+        ] if not output_verdict.blocked else []
+        if cache_key:
+            try:
+                await self._answer_cache.set(
+                    cache_key,
+                    CachedAnswer(answer=answer, confidence=grounding.confidence, sources=sources, user_id=user_id, material_ids=list(material_ids or [])),
+                )
+            except Exception:
+                pass
+        await self.record_turn(
+            session_id=session_id,
+            user_id=user_id,
+            question=question,
+            answer=answer,
+            sources=sources,
+            started_at=started_at,
+            model=self._answer_model(),
+        )
+        if not output_verdict.blocked:
+            self.schedule_summary(session_id, user_id, context or {}, used_turns)
 
         return {
             "answer": answer,
-            "confidence": 0.5,
-            "sources": [
-                {
-                    "material_id": h["material_id"],
-                    "content_snippet": h["content"][:120],
-                    "relevance_score": h["similarity_score"],
-                }
-                for h in hits
-            ],
+            "confidence": grounding.confidence,
+            "sources": sources,
             "metadata": {
                 "note": "mvp",
                 "session_id": session_id,
                 "used_history_turns": used_turns,
                 "used_history_tokens": used_tokens,
+                "summarized_history_turns": summarized_turns,
+                "cache": cache_status,
+                "retrieval_top_k": retrieval["top_k"],
+                "retrieval_similarity_threshold": retrieval["min_score"],
+                "retrieval_rerank": str(any("rerank_score" in h for h in hits)).lower(),
+                **usage,
+                **grounding_metadata(grounding),
+                **moderation_metadata(input_verdict, output_verdict),
             },
         }
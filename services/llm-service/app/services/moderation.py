@@ -0,0 +1,252 @@
+from __future__ import annotations
+
+from dataclasses import dataclass, field
+from typing import Dict, List, Optional
+import asyncio
+import re
+
+from app.config import get_settings
+from app.core.database import get_session_factory
+from app.models.models import ModerationEvent
+from app.repository.moderation_repository import ModerationRepository
+from app.services.openai_client import OpenAIClient
+
+# policy per stage: block replaces the content with a refusal, flag lets it through but audits it
+POLICY_BLOCK = "block"
+POLICY_FLAG = "flag"
+POLICY_OFF = "off"
+
+STAGE_INPUT = "input"
+STAGE_OUTPUT = "output"
+
+
+@dataclass
+class ModerationResult:
+    flagged: bool
+    categories: List[str] = field(default_factory=list)
+    scores: Dict[str, float] = field(default_factory=dict)
+
+
+@dataclass
+class ModerationVerdict:
+    """Outcome of moderating one piece of text under the configured policy.
+
+    action: "allow" | "flag" | "block" | "skip" (stage disabled or no backend)
+    """
+    action: str
+    stage: str = ""
+    categories: List[str] = field(default_factory=list)
+    error: str = ""
+
+    @property
+    def blocked(self) -> bool:
+        return self.action == "block"
+
+
+# severity order used when reporting several verdicts at once
+_SEVERITY = {"skip": 0, "allow": 1, "flag": 2, "block": 3}
+_STATUS = {"skip": "skipped", "allow": "passed", "flag": "flagged", "block": "blocked"}
+
+
+def moderation_metadata(*verdicts: ModerationVerdict) -> Dict[str, str]:
+    """Response metadata for the most severe verdict: moderation=skipped|passed|flagged|blocked,
+    plus moderation_stage and moderation_categories when content was flagged or blocked."""
+    worst = max(verdicts, key=lambda v: _SEVERITY.get(v.action, 0), default=ModerationVerdict(action="skip"))
+    out = {"moderation": _STATUS.get(worst.action, "skipped")}
+    if worst.action in ("flag", "block"):
+        out["moderation_stage"] = worst.stage
+        if worst.categories:
+            out["moderation_categories"] = ",".join(worst.categories)
+    return out
+
+
+class Moderator:
+    """Classifies text as flagged or not; raises on backend failure."""
+
+    name = "base"
+
+    async def classify(self, text: str) -> ModerationResult:  # pragma: no cover - interface
+        raise NotImplementedError
+
+
+class OpenAIModerator(Moderator):
+    """OpenAI-compatible /moderations endpoint (e.g. omni-moderation-latest)."""
+
+    name = "openai"
+
+    def __init__(self, client: OpenAIClient, model: str) -> None:
+        self._oa = client
+        self.model = model
+
+    async def classify(self, text: str) -> ModerationResult:
+        res = await self._oa.amoderation(text, model=self.model)
+        categories = sorted(k for k, v in (res.get("categories") or {}).items() if v)
+        scores = {str(k): float(v) for k, v in (res.get("category_scores") or {}).items()}
+        return ModerationResult(flagged=bool(res.get("flagged")), categories=categories, scores=scores)
+
+
+class ClassifierModerator(Moderator):
+    """Local text-classification model (e.g. unitary/toxic-bert) via transformers.
+
+    transformers is an optional dependency; the model is loaded lazily on first use and
+    inference runs in a worker thread. Any label scoring at or above threshold flags the text.
+    """
+
+    name = "classifier"
+
+    # classifiers have a bounded input size; long answers are checked on their head
+    MAX_CHARS = 2000
+
+    def __init__(self, model_name: str, threshold: float) -> None:
+        self.model_name = model_name
+        self.threshold = threshold
+        self._pipe = None
+        self._load_lock = asyncio.Lock()
+
+    async def _get_pipe(self):
+        async with self._load_lock:
+            if self._pipe is None:
+                from transformers import pipeline  # type: ignore
+
+                self._pipe = await asyncio.to_thread(pipeline, "text-classification", model=self.model_name, top_k=None)
+            return self._pipe
+
+    async def classify(self, text: str) -> ModerationResult:
+        pipe = await self._get_pipe()
+        raw = await asyncio.to_thread(pipe, text[: self.MAX_CHARS], truncation=True)
+        # pipeline returns [[{label, score}, ...]] for a single input with top_k=None
+        labels = raw[0] if raw and isinstance(raw[0], list) else raw
+        scores = {str(x["label"]).lower(): float(x["score"]) for x in labels}
+        categories = sorted(k for k, v in scores.items() if v >= self.threshold)
+        return ModerationResult(flagged=bool(categories), categories=categories, scores=scores)
+
+
+class KeywordModerator(Moderator):
+    """Blocklist from a file: one term per line, optionally "category: term"; # starts a comment.
+
+    Matching is case-insensitive substring matching, which also works for CJK text.
+    """
+
+    name = "keywords"
+
+    def __init__(self, terms: Dict[str, List[str]]) -> None:
+        self._terms = {cat: [t.lower() for t in ts] for cat, ts in terms.items()}
+
+    @classmethod
+    def from_file(cls, path: str) -> "KeywordModerator":
+        terms: Dict[str, List[str]] = {}
+        with open(path, encoding="utf-8") as f:
+            for line in f:
+                line = line.split("#", 1)[0].strip()
+                if not line:
+                    continue
+                cat, sep, term = line.partition(":")
+                if not sep:
+                    cat, term = "custom", line
+                cat, term = cat.strip() or "custom", term.strip()
+                if term:
+                    terms.setdefault(cat, []).append(term)
+        return cls(terms)
+
+    async def classify(self, text: str) -> ModerationResult:
+        low = re.sub(r"\s+", " ", text.lower())
+        categories = sorted(cat for cat, ts in self._terms.items() if any(t in low for t in ts))
+        return ModerationResult(flagged=bool(categories), categories=categories, scores={c: 1.0 for c in categories})
+
+
+def build_moderator(client: OpenAIClient) -> Optional[Moderator]:
+    """Pick a moderator from settings; returns None when moderation is disabled or unusable."""
+    s = get_settings()
+    backend = (s.moderation_backend or "").lower()
+    if not backend or backend == POLICY_OFF:
+        return None
+    if backend == "openai":
+        if not client.is_enabled():
+            print("[WARN] LLM_MODERATION_BACKEND=openai requires OPENAI_BASE_URL/OPENAI_API_KEY; moderation disabled")
+            return None
+        return OpenAIModerator(client, s.moderation_model or "omni-moderation-latest")
+    if backend == "classifier":
+        if not s.moderation_model:
+            print("[WARN] LLM_MODERATION_BACKEND=classifier requires LLM_MODERATION_MODEL; moderation disabled")
+            return None
+        return ClassifierModerator(s.moderation_model, s.moderation_threshold)
+    if backend == "keywords":
+        if not s.moderation_terms_path:
+            print("[WARN] LLM_MODERATION_BACKEND=keywords requires LLM_MODERATION_TERMS_PATH; moderation disabled")
+            return None
+        return KeywordModerator.from_file(s.moderation_terms_path)
+    print(f"[WARN] unknown LLM_MODERATION_BACKEND={backend!r}; moderation disabled")
+    return None
+
+
+class ContentModeration:
+    """Applies the per-stage policy on top of a Moderator and writes audit records.
+
+    Flagged and blocked content is audited (moderation_events table when the database is
+    enabled, otherwise the log). Backend failures let content through unless fail_closed is set.
+    """
+
+    # excerpt kept in audit records
+    EXCERPT_CHARS = 500
+
+    def __init__(self, moderator: Optional[Moderator]) -> None:
+        s = get_settings()
+        self._moderator = moderator
+        self._policies = {
+            STAGE_INPUT: (s.moderation_input_policy or POLICY_BLOCK).lower(),
+            STAGE_OUTPUT: (s.moderation_output_policy or POLICY_BLOCK).lower(),
+        }
+        self._fail_closed = s.moderation_fail_closed
+        self._timeout = s.moderation_timeout_seconds
+        self.block_message = s.moderation_block_message
+
+    def policy(self, stage: str) -> str:
+        if self._moderator is None:
+            return POLICY_OFF
+        return self._policies.get(stage, POLICY_OFF)
+
+    async def check(self, text: str, *, stage: str, user_id: str, session_id: str = "") -> ModerationVerdict:
+        policy = self.policy(stage)
+        if policy not in (POLICY_BLOCK, POLICY_FLAG) or not text.strip():
+            return ModerationVerdict(action="skip", stage=stage)
+        try:
+            res = await asyncio.wait_for(self._moderator.classify(text), timeout=self._timeout)
+        except Exception as e:
+            print(f"[WARN] moderation ({self._moderator.name}) failed at {stage}: {e}")
+            if not self._fail_closed:
+                return ModerationVerdict(action="skip", stage=stage, error=str(e))
+            verdict = ModerationVerdict(action="block", stage=stage, error=str(e))
+            await self._audit(verdict, ModerationResult(flagged=False), text, stage=stage, user_id=user_id, session_id=session_id)
+            return verdict
+
+        if not res.flagged:
+            return ModerationVerdict(action="allow", stage=stage)
+        verdict = ModerationVerdict(action="block" if policy == POLICY_BLOCK else "flag", stage=stage, categories=res.categories)
+        await self._audit(verdict, res, text, stage=stage, user_id=user_id, session_id=session_id)
+        return verdict
+
+    async def _audit(self, verdict: ModerationVerdict, res: ModerationResult, text: str, *, stage: str, user_id: str, session_id: str) -> None:
+        provider = self._moderator.name if self._moderator else ""
+        print(
+            f"[MODERATION] action={verdict.action} stage={stage} user_id={user_id} session_id={session_id} "
+            f"provider={provider} categories={verdict.categories} error={verdict.error!r}"
+        )
+        if get_session_factory() is None:
+            return
+        try:
+            await ModerationRepository().create(
+                ModerationEvent(
+                    user_id=user_id,
+                    session_id=session_id,
+                    stage=stage,
+                    action=verdict.action,
+                    provider=provider,
+                    categories=verdict.categories,
+                    scores=res.scores,
+                    excerpt=text[: self.EXCERPT_CHARS],
+                    error=verdict.error,
+                )
+            )
+        except Exception as e:
+            # auditing must not change the moderation outcome
+            print(f"[ERROR] Failed to write moderation audit record: {e}")
@@ -11,7 +11,7 @@ import json
 class OpenAIClient:
     """Minimal OpenAI-compatible client using HTTPX.
 
-    Supports: chat completions and embeddings.
+    Supports: chat completions, embeddings and moderations.
     Compatible with OpenAI and self-hosted (vLLM/Ollama) if they follow the same API surface.
     """
 
@@ -30,10 +30,14 @@ class OpenAIClient:
     def is_enabled(self) -> bool:
         return bool(self.base_url and self.api_key)
 
-    async def aembedding(self, text: str) -> List[float]:
+    def embedding_model_for(self, language: str | None) -> str:
+        """Embedding model for text in language (ISO 639-1), see Settings.embedding_models."""
+        return get_settings().embedding_model_for(language) or self.embedding_model or "text-embedding-3-small"
+
+    async def aembedding(self, text: str, model: Optional[str] = None) -> List[float]:
         if not self.is_enabled():
             raise RuntimeError("OpenAI client not configured")
-        model = self.embedding_model or "text-embedding-3-small"
+        model = model or self.embedding_model or "text-embedding-3-small"
         url = f"{self.base_url.rstrip('/')}/embeddings"
         headers = {
             "Authorization": f"Bearer {self.api_key}",
@@ -50,6 +54,25 @@ class OpenAIClient:
             vec = data["data"][0]["embedding"]
             return [float(x) for x in vec]
 
+    async def amoderation(self, text: str, model: str = "omni-moderation-latest") -> dict:
+        """Return results[0] of the /moderations endpoint: {flagged, categories, category_scores}."""
+        if not self.is_enabled():
+            raise RuntimeError("OpenAI client not configured")
+        url = f"{self.base_url.rstrip('/')}/moderations"
+        headers = {
+            "Authorization": f"Bearer {self.api_key}",
+            "Content-Type": "application/json",
+        }
+        payload = {
+            "model": model,
+            "input": text,
+        }
+        async with httpx.AsyncClient(timeout=30.0) as client:
+            r = await client.post(url, headers=headers, json=payload)
+            r.raise_for_status()
+            data = r.json()
+            return (data.get("results") or [{}])[0]
+
     async def achat_stream(self, messages: list[dict]) -> AsyncIterator[str]:
         """Yield tokens from OpenAI-compatible streaming chat completions.
 
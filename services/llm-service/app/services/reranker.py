@@ -0,0 +1,122 @@
+from __future__ import annotations
+
+from typing import Dict, List, Optional
+import asyncio
+import json
+import math
+import re
+
+from app.config import get_settings
+from app.services.openai_client import OpenAIClient
+
+
+class Reranker:
+    """Scores (query, passage) pairs; higher is more relevant. Scores are in [0, 1]."""
+
+    name = "base"
+
+    async def score(self, query: str, passages: List[str]) -> List[float]:  # pragma: no cover - interface
+        raise NotImplementedError
+
+
+class CrossEncoderReranker(Reranker):
+    """Local cross-encoder (e.g. BAAI/bge-reranker-base) via sentence-transformers.
+
+    sentence-transformers is an optional dependency; the model is loaded lazily on first use
+    and inference runs in a worker thread to keep the event loop responsive.
+    """
+
+    name = "cross_encoder"
+
+    def __init__(self, model_name: str) -> None:
+        self.model_name = model_name
+        self._model = None
+        self._load_lock = asyncio.Lock()
+
+    async def _get_model(self):
+        async with self._load_lock:
+            if self._model is None:
+                from sentence_transformers import CrossEncoder  # type: ignore
+
+                self._model = await asyncio.to_thread(CrossEncoder, self.model_name)
+            return self._model
+
+    async def score(self, query: str, passages: List[str]) -> List[float]:
+        model = await self._get_model()
+        raw = await asyncio.to_thread(model.predict, [(query, p) for p in passages])
+        # cross-encoders output logits; squash to [0, 1] so scores are comparable across backends
+        return [1.0 / (1.0 + math.exp(-float(x))) for x in raw]
+
+
+class LLMReranker(Reranker):
+    """Asks the configured chat model to grade each passage 0-10 in one call."""
+
+    name = "llm"
+
+    # passages are truncated in the prompt to bound cost
+    MAX_PASSAGE_CHARS = 800
+
+    def __init__(self, client: OpenAIClient) -> None:
+        self._oa = client
+
+    async def score(self, query: str, passages: List[str]) -> List[float]:
+        listing = "\n\n".join(f"[{i}] {p[: self.MAX_PASSAGE_CHARS]}" for i, p in enumerate(passages))
+        messages = [
+            {
+                "role": "system",
+                "content": (
+                    "You grade how well each passage answers the query. "
+                    "Reply with only a JSON array of integers from 0 (irrelevant) to 10 (directly answers), "
+                    "one per passage, in the given order."
+                ),
+            },
+            {"role": "user", "content": f"Query: {query}\n\nPassages:\n{listing}"},
+        ]
+        reply = await self._oa.achat(messages)
+        match = re.search(r"\[[^\]]*\]", reply or "")
+        if not match:
+            raise ValueError(f"unexpected rerank reply: {reply[:200]!r}")
+        grades = json.loads(match.group(0))
+        if len(grades) != len(passages):
+            raise ValueError(f"rerank reply has {len(grades)} grades for {len(passages)} passages")
+        return [max(0.0, min(10.0, float(g))) / 10.0 for g in grades]
+
+
+def build_reranker(client: OpenAIClient) -> Optional[Reranker]:
+    """Pick a reranker from settings; returns None when no backend is usable."""
+    s = get_settings()
+    backend = (s.reranker_backend or "").lower()
+    if backend == "cross_encoder" or (not backend and s.reranker_model):
+        if not s.reranker_model:
+            print("[WARN] LLM_RERANKER_BACKEND=cross_encoder requires LLM_RERANKER_MODEL; reranking disabled")
+            return None
+        return CrossEncoderReranker(s.reranker_model)
+    if backend in ("llm", "") and client.is_enabled():
+        return LLMReranker(client)
+    return None
+
+
+async def rerank_hits(reranker: Reranker, query: str, hits: List[Dict], top_k: int, timeout: float) -> List[Dict]:
+    """Reorder hits by rerank score and keep top_k. Each hit gets a "rerank_score" key
+    (also mirrored into metadata so it survives the SearchResult proto).
+
+    On failure or timeout the original order is kept, so reranking never breaks retrieval.
+    """
+    if not hits:
+        return hits
+    try:
+        scores = await asyncio.wait_for(reranker.score(query, [h["content"] for h in hits]), timeout=timeout)
+    except Exception as e:
+        print(f"[WARN] rerank ({reranker.name}) failed, keeping vector order: {e}")
+        return hits[:top_k]
+
+    out: List[Dict] = []
+    for h, sc in sorted(zip(hits, scores), key=lambda x: x[1], reverse=True)[:top_k]:
+        item = dict(h)
+        item["rerank_score"] = float(sc)
+        meta = dict(item.get("metadata") or {})
+        meta["rerank_score"] = f"{float(sc):.4f}"
+        meta["reranker"] = reranker.name
+        item["metadata"] = meta
+        out.append(item)
+    return out
@@ -0,0 +1,202 @@
+from __future__ import annotations
+
+from dataclasses import dataclass, field
+from datetime import datetime
+from typing import Dict, List, Optional, Set
+import asyncio
+
+from app.models.models import ChatSessionSummary
+from app.repository.session_summary_repository import SessionSummaryRepository
+from app.services.chat_history import ChatHistoryStore, ChatTurnRecord
+from app.services.openai_client import OpenAIClient
+from app.services.shared_sessions import SharedSessions
+
+
+@dataclass
+class SessionSummary:
+    """Rolling summary of the turns that no longer fit in the prompt history window."""
+    session_id: str
+    user_id: str
+    summary: str = ""
+    summarized_turns: int = 0
+    # created_at of the newest folded turn (None when nothing was folded yet)
+    covered_until: Optional[datetime] = None
+    updated_at: datetime = field(default_factory=datetime.utcnow)
+
+
+class SessionSummaryStore:
+    """Abstract store for session summaries."""
+
+    async def get(self, session_id: str, user_id: str) -> Optional[SessionSummary]:  # pragma: no cover - interface
+        raise NotImplementedError
+
+    async def put(self, summary: SessionSummary) -> None:  # pragma: no cover - interface
+        raise NotImplementedError
+
+
+class InMemorySessionSummaryStore(SessionSummaryStore):
+    """Process-local fallback used when no database is configured."""
+
+    def __init__(self) -> None:
+        self._data: Dict[str, SessionSummary] = {}
+        self._lock = asyncio.Lock()
+
+    async def get(self, session_id: str, user_id: str) -> Optional[SessionSummary]:
+        async with self._lock:
+            s = self._data.get(session_id)
+        return s if s is not None and s.user_id == user_id else None
+
+    async def put(self, summary: SessionSummary) -> None:
+        async with self._lock:
+            self._data[summary.session_id] = summary
+
+
+class DatabaseSessionSummaryStore(SessionSummaryStore):
+    """Stores summaries in the chat_session_summaries table."""
+
+    async def get(self, session_id: str, user_id: str) -> Optional[SessionSummary]:
+        row = await SessionSummaryRepository().get(session_id, user_id)
+        if row is None:
+            return None
+        return SessionSummary(
+            session_id=row.session_id,
+            user_id=row.user_id,
+            summary=row.summary or "",
+            summarized_turns=row.summarized_turns or 0,
+            covered_until=row.covered_until,
+            updated_at=row.updated_at,
+        )
+
+    async def put(self, summary: SessionSummary) -> None:
+        await SessionSummaryRepository().upsert(
+            ChatSessionSummary(
+                session_id=summary.session_id,
+                user_id=summary.user_id,
+                summary=summary.summary,
+                summarized_turns=summary.summarized_turns,
+                covered_until=summary.covered_until,
+                updated_at=summary.updated_at,
+            )
+        )
+
+
+_SUMMARY_PROMPT = (
+    "You maintain a running summary of a long study conversation between a student and an assistant. "
+    "Merge the earlier summary (if any) with the new turns into one updated summary. Keep the topics covered, "
+    "questions the student asked, key explanations and conclusions, and anything the student said about their "
+    "goals or difficulties. Write compact bullet points in the language of the conversation, at most {max_chars} characters. "
+    "Output only the summary."
+)
+
+# per-turn excerpt lengths in the summarization prompt
+_QUESTION_CHARS = 1000
+_ANSWER_CHARS = 1500
+
+
+class SessionSummarizer:
+    """Folds turns that fell out of the prompt history window into a rolling per-session summary.
+
+    Turns come from the persisted chat history (see chat_history.py), so the summary also covers
+    turns lost from the process-local prompt memory after a restart. Summarization runs in the
+    background after a turn is recorded and only once at least batch_turns uncovered turns have
+    accumulated; the summary is then used from the next question on. A shared session has one
+    summary (stored under its owner) covering the turns of all members.
+    """
+
+    def __init__(
+        self,
+        oa: OpenAIClient,
+        history: ChatHistoryStore,
+        store: SessionSummaryStore,
+        *,
+        batch_turns: int = 4,
+        max_chars: int = 2000,
+        ignore_answers: Optional[Set[str]] = None,
+        shared: Optional[SharedSessions] = None,
+    ) -> None:
+        self._oa = oa
+        self._history = history
+        self._store = store
+        self._batch_turns = max(1, batch_turns)
+        self._max_chars = max(200, max_chars)
+        # answers of turns that never reached the prompt memory (e.g. moderation refusals)
+        self._ignore_answers = ignore_answers or set()
+        self._shared = shared
+        # sessions with a summarization in flight
+        self._running: Set[str] = set()
+        self._tasks: Set[asyncio.Task] = set()
+
+    def is_enabled(self) -> bool:
+        return self._oa.is_enabled()
+
+    async def _scope(self, session_id: str, user_id: str) -> tuple[Optional[str], str]:
+        """(chat history user filter, summary owner) of the session."""
+        if self._shared is None:
+            return user_id, user_id
+        return await self._shared.history_scope(session_id, user_id)
+
+    async def get(self, session_id: str, user_id: str) -> Optional[SessionSummary]:
+        """Current summary of the session (best-effort, None on errors)."""
+        if not session_id:
+            return None
+        try:
+            _, owner = await self._scope(session_id, user_id)
+            s = await self._store.get(session_id, owner)
+        except Exception as e:
+            print(f"[ERROR] Failed to load summary for session {session_id}: {e}")
+            return None
+        return s if s is not None and s.summary else None
+
+    def schedule(self, session_id: str, user_id: str, keep_turns: int) -> None:
+        """Summarize in the background the turns older than the newest keep_turns (never raises)."""
+        if not self.is_enabled() or not session_id or keep_turns <= 0 or session_id in self._running:
+            return
+        self._running.add(session_id)
+        task = asyncio.create_task(self._run(session_id, user_id, keep_turns))
+        # keep a reference so the task is not garbage-collected mid-flight
+        self._tasks.add(task)
+        task.add_done_callback(self._tasks.discard)
+
+    async def _run(self, session_id: str, user_id: str, keep_turns: int) -> None:
+        try:
+            await self.summarize(session_id, user_id, keep_turns)
+        except Exception as e:
+            print(f"[ERROR] Failed to summarize session {session_id}: {e}")
+        finally:
+            self._running.discard(session_id)
+
+    async def summarize(self, session_id: str, user_id: str, keep_turns: int) -> Optional[SessionSummary]:
+        """Fold the uncovered turns outside the window into the summary; returns the new summary
+        or None when there was not enough to fold."""
+        history_user, owner = await self._scope(session_id, user_id)
+        current = await self._store.get(session_id, owner) or SessionSummary(session_id=session_id, user_id=owner)
+        turns = [t for t in await self._history.list_turns(session_id, history_user) if t.answer not in self._ignore_answers]
+        older = turns[:-keep_turns] if keep_turns > 0 else turns
+        pending = [t for t in older if current.covered_until is None or t.created_at > current.covered_until]
+        if len(pending) < self._batch_turns:
+            return None
+
+        summary = (await self._oa.achat(self._prompt(current.summary, pending))).strip()
+        if not summary:
+            return None
+        updated = SessionSummary(
+            session_id=session_id,
+            user_id=owner,
+            summary=summary[: self._max_chars],
+            summarized_turns=current.summarized_turns + len(pending),
+            covered_until=pending[-1].created_at,
+        )
+        await self._store.put(updated)
+        print(f"[INFO] Session {session_id}: folded {len(pending)} turns into summary ({updated.summarized_turns} total)")
+        return updated
+
+    def _prompt(self, previous: str, turns: List[ChatTurnRecord]) -> List[Dict]:
+        lines = []
+        for t in turns:
+            lines.append(f"Student: {t.question[:_QUESTION_CHARS]}")
+            lines.append(f"Assistant: {t.answer[:_ANSWER_CHARS]}")
+        user = f"Earlier summary:\n{previous or '(none)'}\n\nNew turns:\n" + "\n".join(lines)
+        return [
+            {"role": "system", "content": _SUMMARY_PROMPT.format(max_chars=self._max_chars)},
+            {"role": "user", "content": user},
+        ]
@@ -0,0 +1,268 @@
+from __future__ import annotations
+
+from dataclasses import dataclass, field
+from datetime import datetime
+from typing import Dict, List, Optional
+import asyncio
+import hmac
+import secrets
+import uuid
+
+from app.models.models import SharedChatSession, SharedSessionMember
+from app.repository.shared_session_repository import SharedSessionRepository
+from app.services.chat_history import ChatHistoryStore
+
+ROLE_OWNER = "owner"
+ROLE_PARTICIPANT = "participant"
+ROLE_VIEWER = "viewer"
+# roles that can be granted through an invite code or by the owner
+GRANTABLE_ROLES = (ROLE_PARTICIPANT, ROLE_VIEWER)
+
+# SharedSessionError kinds (mapped to gRPC status codes by the handler)
+ERR_INVALID = "invalid_argument"
+ERR_NOT_FOUND = "not_found"
+ERR_FORBIDDEN = "permission_denied"
+
+
+class SharedSessionError(Exception):
+    """A shared-session request rejected by the ACL or by validation."""
+
+    def __init__(self, kind: str, message: str) -> None:
+        super().__init__(message)
+        self.kind = kind
+
+
+@dataclass
+class SessionMemberRecord:
+    user_id: str
+    role: str
+    joined_at: datetime = field(default_factory=datetime.utcnow)
+
+
+@dataclass
+class SharedSessionRecord:
+    session_id: str
+    owner_id: str
+    title: str = ""
+    join_code: str = field(default_factory=lambda: secrets.token_urlsafe(12))
+    default_role: str = ROLE_PARTICIPANT
+    created_at: datetime = field(default_factory=datetime.utcnow)
+    members: List[SessionMemberRecord] = field(default_factory=list)
+
+    def role_of(self, user_id: str) -> str:
+        """Role of user_id in the session, empty when not a member."""
+        for m in self.members:
+            if m.user_id == user_id:
+                return m.role
+        return ""
+
+
+class SharedSessionStore:
+    """Abstract store for shared sessions and their members."""
+
+    async def get(self, session_id: str) -> Optional[SharedSessionRecord]:  # pragma: no cover - interface
+        raise NotImplementedError
+
+    async def create(self, record: SharedSessionRecord) -> None:  # pragma: no cover - interface
+        """Insert the session with its members (the owner)."""
+        raise NotImplementedError
+
+    async def put_member(self, session_id: str, member: SessionMemberRecord) -> None:  # pragma: no cover - interface
+        raise NotImplementedError
+
+    async def remove_member(self, session_id: str, user_id: str) -> None:  # pragma: no cover - interface
+        raise NotImplementedError
+
+
+class InMemorySharedSessionStore(SharedSessionStore):
+    """Process-local fallback used when no database is configured."""
+
+    def __init__(self) -> None:
+        self._data: Dict[str, SharedSessionRecord] = {}
+        self._lock = asyncio.Lock()
+
+    @staticmethod
+    def _copy(record: SharedSessionRecord) -> SharedSessionRecord:
+        return SharedSessionRecord(
+            session_id=record.session_id,
+            owner_id=record.owner_id,
+            title=record.title,
+            join_code=record.join_code,
+            default_role=record.default_role,
+            created_at=record.created_at,
+            members=[SessionMemberRecord(m.user_id, m.role, m.joined_at) for m in record.members],
+        )
+
+    async def get(self, session_id: str) -> Optional[SharedSessionRecord]:
+        async with self._lock:
+            record = self._data.get(session_id)
+            return self._copy(record) if record is not None else None
+
+    async def create(self, record: SharedSessionRecord) -> None:
+        async with self._lock:
+            self._data[record.session_id] = self._copy(record)
+
+    async def put_member(self, session_id: str, member: SessionMemberRecord) -> None:
+        async with self._lock:
+            record = self._data.get(session_id)
+            if record is None:
+                return
+            record.members = [m for m in record.members if m.user_id != member.user_id]
+            record.members.append(SessionMemberRecord(member.user_id, member.role, member.joined_at))
+            record.members.sort(key=lambda m: m.joined_at)
+
+    async def remove_member(self, session_id: str, user_id: str) -> None:
+        async with self._lock:
+            record = self._data.get(session_id)
+            if record is not None:
+                record.members = [m for m in record.members if m.user_id != user_id]
+
+
+class DatabaseSharedSessionStore(SharedSessionStore):
+    """Stores sessions in shared_chat_sessions and the ACL in shared_session_members."""
+
+    async def get(self, session_id: str) -> Optional[SharedSessionRecord]:
+        repo = SharedSessionRepository()
+        row = await repo.get(session_id)
+        if row is None:
+            return None
+        members = await repo.list_members(session_id)
+        return SharedSessionRecord(
+            session_id=row.session_id,
+            owner_id=row.owner_id,
+            title=row.title or "",
+            join_code=row.join_code,
+            default_role=row.default_role or ROLE_PARTICIPANT,
+            created_at=row.created_at,
+            members=[SessionMemberRecord(m.user_id, m.role, m.joined_at) for m in members],
+        )
+
+    async def create(self, record: SharedSessionRecord) -> None:
+        owner = next(m for m in record.members if m.role == ROLE_OWNER)
+        await SharedSessionRepository().create(
+            SharedChatSession(
+                session_id=record.session_id,
+                owner_id=record.owner_id,
+                title=record.title,
+                join_code=record.join_code,
+                default_role=record.default_role,
+                created_at=record.created_at,
+            ),
+            SharedSessionMember(session_id=record.session_id, user_id=owner.user_id, role=owner.role, joined_at=owner.joined_at),
+        )
+
+    async def put_member(self, session_id: str, member: SessionMemberRecord) -> None:
+        await SharedSessionRepository().upsert_member(
+            SharedSessionMember(session_id=session_id, user_id=member.user_id, role=member.role, joined_at=member.joined_at)
+        )
+
+    async def remove_member(self, session_id: str, user_id: str) -> None:
+        await SharedSessionRepository().delete_member(session_id, user_id)
+
+
+class SharedSessions:
+    """Membership and access control of shared AI sessions.
+
+    A shared session is an ordinary chat session (same session_id, prompt memory and chat_turns)
+    that several members read and ask in. Every turn keeps the asking user's id for attribution.
+    Owners and participants can ask, viewers only read. Sessions that were never shared keep the
+    per-user isolation of the chat history.
+    """
+
+    def __init__(self, store: SharedSessionStore, history: ChatHistoryStore) -> None:
+        self._store = store
+        self._history = history
+
+    async def lookup(self, session_id: str) -> Optional[SharedSessionRecord]:
+        """The shared session, None when session_id is private or unknown."""
+        if not session_id:
+            return None
+        return await self._store.get(session_id)
+
+    async def create(self, *, owner_id: str, title: str = "", session_id: str = "", default_role: str = "") -> SharedSessionRecord:
+        """Share an existing session of owner_id, or open a new one when session_id is empty."""
+        if not owner_id:
+            raise SharedSessionError(ERR_INVALID, "user_id is required")
+        default_role = default_role or ROLE_PARTICIPANT
+        if default_role not in GRANTABLE_ROLES:
+            raise SharedSessionError(ERR_INVALID, "default_role must be participant or viewer")
+        if session_id:
+            if await self._store.get(session_id) is not None:
+                raise SharedSessionError(ERR_INVALID, "session is already shared")
+            # only the user who asked in a private session can share it
+            turns = await self._history.list_turns(session_id, None)
+            if any(t.user_id != owner_id for t in turns):
+                raise SharedSessionError(ERR_FORBIDDEN, "session belongs to another user")
+        else:
+            session_id = uuid.uuid4().hex
+        record = SharedSessionRecord(
+            session_id=session_id,
+            owner_id=owner_id,
+            title=title.strip()[:255],
+            default_role=default_role,
+        )
+        record.members.append(SessionMemberRecord(owner_id, ROLE_OWNER, record.created_at))
+        await self._store.create(record)
+        return record
+
+    async def get(self, session_id: str, user_id: str) -> SharedSessionRecord:
+        """The session as seen by a member; non-members get not_found so existence is not leaked."""
+        record = await self.lookup(session_id)
+        if record is None or not record.role_of(user_id):
+            raise SharedSessionError(ERR_NOT_FOUND, "shared session not found")
+        return record
+
+    async def join(self, session_id: str, user_id: str, join_code: str) -> SharedSessionRecord:
+        if not user_id:
+            raise SharedSessionError(ERR_INVALID, "user_id is required")
+        record = await self.lookup(session_id)
+        if record is None:
+            raise SharedSessionError(ERR_NOT_FOUND, "shared session not found")
+        if record.role_of(user_id):
+            return record
+        if not hmac.compare_digest(join_code or "", record.join_code):
+            raise SharedSessionError(ERR_FORBIDDEN, "invalid join code")
+        member = SessionMemberRecord(user_id, record.default_role)
+        await self._store.put_member(session_id, member)
+        record.members.append(member)
+        return record
+
+    async def set_member(self, session_id: str, actor_id: str, member_id: str, role: str) -> SharedSessionRecord:
+        """Owner-only: change a member's role, or remove the member when role is empty."""
+        record = await self.get(session_id, actor_id)
+        if record.role_of(actor_id) != ROLE_OWNER:
+            raise SharedSessionError(ERR_FORBIDDEN, "only the session owner can manage members")
+        if not member_id:
+            raise SharedSessionError(ERR_INVALID, "member_id is required")
+        if member_id == record.owner_id:
+            raise SharedSessionError(ERR_INVALID, "the owner's membership cannot be changed")
+        if not role:
+            await self._store.remove_member(session_id, member_id)
+            record.members = [m for m in record.members if m.user_id != member_id]
+            return record
+        if role not in GRANTABLE_ROLES:
+            raise SharedSessionError(ERR_INVALID, "role must be participant or viewer")
+        current = next((m for m in record.members if m.user_id == member_id), None)
+        member = SessionMemberRecord(member_id, role, current.joined_at if current else datetime.utcnow())
+        await self._store.put_member(session_id, member)
+        record.members = [m for m in record.members if m.user_id != member_id] + [member]
+        return record
+
+    async def check_can_ask(self, session_id: str, user_id: str) -> None:
+        """Raise unless user_id may add turns to session_id (always allowed for private sessions)."""
+        record = await self.lookup(session_id)
+        if record is None:
+            return
+        role = record.role_of(user_id)
+        if not role:
+            raise SharedSessionError(ERR_FORBIDDEN, "not a member of this shared session")
+        if role == ROLE_VIEWER:
+            raise SharedSessionError(ERR_FORBIDDEN, "viewers cannot ask in a shared session")
+
+    async def history_scope(self, session_id: str, user_id: str) -> tuple[Optional[str], str]:
+        """(user filter for the chat history, summary owner): members of a shared session see every
+        member's turns and share the owner's rolling summary; otherwise both stay per user."""
+        record = await self.lookup(session_id)
+        if record is not None and record.role_of(user_id):
+            return None, record.owner_id
+        return user_id, user_id
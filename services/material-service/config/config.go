@@ -3,30 +3,57 @@ package config
 import (
 	"log"
 	"os"
+	"strconv"
+	"time"
 
+	"github.com/RigelNana/arkstudy/pkg/registry"
 	"github.com/joho/godotenv"
 )
 
 type Config struct {
 	Database DatabaseConfig
 	MinIO    MinIOConfig
+	Janitor  JanitorConfig
+	// 存储桶生命周期与存储类型
+	Lifecycle LifecycleConfig
+	// 音视频元数据探测
+	Media MediaConfig
+	// 处理前的费用预估
+	Estimate EstimateConfig
+	// 重复资料检测
+	Dedup DedupConfig
+	// 每个用户的处理任务并发上限
+	Limits LimitsConfig
+	// 大任务的处理时段
+	Schedule ScheduleConfig
+	// 可续传的上传会话
+	UploadSession UploadSessionConfig
 }
 type DatabaseConfig struct {
-	DBUser           string
-	DBPassword       string
-	DBName           string
-	DBHost           string
-	DBPort           string
-	JWTSecret        string
-	MaterialGRPCAddr string
-	LLMGRPCAddr      string
-	OCRGRPCAddr      string
-	JWTExpireMins    int
+	DBUser     string
+	DBPassword string
+	DBName     string
+	DBHost     string
+	DBPort     string
+	// 逗号分隔的只读副本 DSN，为空时全部查询走主库；资料列表等读多的查询从副本读取
+	DBReplicaDSNs string
+	JWTSecret     string
+	// 依赖服务地址，由 registry 统一解析
+	LLMGRPCAddr   string
+	OCRGRPCAddr   string
+	ASRGRPCAddr   string
+	JWTExpireMins int
 	// Kafka
 	KafkaBrokers            string
 	KafkaTopicOCRReqs       string
 	KafkaTopicFileProcess   string
 	KafkaTopicTextExtracted string
+	// 资料生命周期事件（material.created / updated / deleted）
+	KafkaTopicMaterialEvents string
+	// 任务中心的任务事件
+	KafkaTopicTaskEvents string
+	// 通知请求，延后的任务开始处理时通知用户
+	KafkaTopicNotifications string
 	KafkaGroupID            string
 }
 
@@ -36,6 +63,95 @@ type MinIOConfig struct {
 	SecretAccessKey string
 	UseSSL          bool
 	BucketName      string
+	// 服务端加密方式：为空不加密，sse-s3 使用 MinIO 管理的密钥，sse-kms 使用 KMS 中的 SSEKMSKeyID
+	SSEMode     string
+	SSEKMSKeyID string
+	// SSE-KMS 加密上下文（JSON 对象），可为空
+	SSEKMSContext string
+}
+
+// JanitorConfig 定时清理任务配置
+type JanitorConfig struct {
+	Enabled       bool
+	Interval      time.Duration // 两次清理之间的间隔
+	ProcessingTTL time.Duration // 处理记录停留在 processing 超过该时长视为卡死，标记为失败
+	UploadTTL     time.Duration // 资料停留在 uploading 超过该时长视为上传中断
+	OrphanMinAge  time.Duration // 无资料记录的对象至少存在该时长才删除，避免误删正在写入的对象
+}
+
+// LifecycleConfig 存储桶生命周期规则与按文件选择的存储类型
+type LifecycleConfig struct {
+	// 为 false 时不修改存储桶的生命周期配置，删除资料时直接删除对象
+	Enabled bool
+	// 原始文件上传超过 TransitionDays 天后转存到 TransitionTier（MinIO 中预先配置的远程 tier，
+	// 如冷存储桶）；任一项为空时不转存
+	TransitionDays int
+	TransitionTier string
+	// 大于 0 时删除资料只将对象标记为回收站（trash=true），由生命周期规则在 TrashExpireDays 天后删除
+	TrashExpireDays int
+	// 超过 LargeVideoBytes 的视频以 LargeVideoStorageClass 写入（如 REDUCED_REDUNDANCY），为空时使用默认存储类型
+	LargeVideoStorageClass string
+	LargeVideoBytes        int64
+}
+
+// MediaConfig 音视频上传后用 ffprobe 读取时长、分辨率与编码
+type MediaConfig struct {
+	FFprobePath  string        // ffprobe 可执行文件，未安装时跳过探测
+	ProbeTimeout time.Duration // 单个文件的探测超时
+	ProbeWorkers int           // 同时运行的 ffprobe 进程数
+}
+
+// EstimateConfig 处理费用预估：按探测到的页数与时长估算 OCR、ASR 与 token 用量，单价由部署方按实际计费配置
+type EstimateConfig struct {
+	ProbeTimeout time.Duration // 单次探测（读取 PDF 结构或 ffprobe）的超时，ffprobe 未安装时按文件大小估算时长
+	Currency     string
+	// 单价
+	OCRCostPerPage     float64
+	ASRCostPerMinute   float64
+	EmbeddingCostPer1K float64 // 分片向量化，每千 token
+	LLMCostPer1K       float64 // LLM 分析，每千输入 token
+	// 识别文本量的估算系数
+	CharsPerPage   int
+	CharsPerMinute int
+	CharsPerToken  float64
+}
+
+// DedupConfig 上传时按内容哈希与 SimHash 查找同一用户内容相同或相近的资料
+type DedupConfig struct {
+	Enabled bool
+	// 两份资料文本 SimHash 的汉明距离不超过该值时视为内容相近（64 位，3 约对应少量页面或段落的差异）
+	SimHashMaxDistance int
+	// 上传时同步计算 SimHash 的纯文本资料大小上限，更大的文件在后台提取文本后计算
+	SimHashUploadBytes int64
+}
+
+// LimitsConfig 每个用户同时进行的 OCR / ASR 任务上限，避免单个用户一次提交大量任务占满处理能力。
+// 超出 MaxInFlightPerUser 的 OCR 任务进入排队，有任务结束后按提交顺序派发；排队数也达到上限时拒绝。0 表示不限制
+type LimitsConfig struct {
+	MaxInFlightPerUser int // 等待或正在处理（pending / processing）的任务数上限
+	MaxQueuedPerUser   int // 排队（queued）的任务数上限
+}
+
+// ScheduleConfig 大任务（长音视频的转写）的处理时段：时段外提交的大任务记为 scheduled，
+// 到下一个时段开始时转入排队，按 LimitsConfig 派发，并通知用户
+type ScheduleConfig struct {
+	// 每天允许处理大任务的时段，逗号分隔，如 "01:00-06:00,13:00-14:00"，结束早于开始表示跨越午夜（"22:00-06:00"）；为空时不延后
+	HeavyWindows string
+	// 时段所在的时区（IANA 名称）
+	Timezone string
+	// 时长不少于该分钟数的音视频转写视为大任务；未探测到时长时按文件大小不少于 HeavyASRMB 判断
+	HeavyASRMinutes int
+	HeavyASRMB      int
+	// 检查到期任务的间隔
+	Interval time.Duration
+}
+
+// UploadSessionConfig 可续传上传会话配置
+type UploadSessionConfig struct {
+	// 会话在最后一次追加后保留的时长，过期未完成的会话由 janitor 清理
+	TTL time.Duration
+	// 单个会话的文件大小上限
+	MaxBytes int64
 }
 
 func LoadConfig() *Config {
@@ -45,21 +161,25 @@ func LoadConfig() *Config {
 	}
 	return &Config{
 		Database: DatabaseConfig{
-			DBUser:                  os.Getenv("DB_USER"),
-			DBPassword:              os.Getenv("DB_PASSWORD"),
-			DBName:                  os.Getenv("DB_NAME"),
-			DBHost:                  os.Getenv("DB_HOST"),
-			DBPort:                  os.Getenv("DB_PORT"),
-			JWTSecret:               os.Getenv("JWT_SECRET"),
-			MaterialGRPCAddr:        os.Getenv("MATERIAL_GRPC_ADDR"),
-			LLMGRPCAddr:             os.Getenv("LLM_GRPC_ADDR"),
-			OCRGRPCAddr:             os.Getenv("OCR_GRPC_ADDR"),
-			JWTExpireMins:           60,
-			KafkaBrokers:            os.Getenv("KAFKA_BROKERS"),
-			KafkaTopicOCRReqs:       os.Getenv("KAFKA_TOPIC_OCR_REQUESTS"),
-			KafkaTopicFileProcess:   os.Getenv("KAFKA_TOPIC_FILE_PROCESSING"),
-			KafkaTopicTextExtracted: os.Getenv("KAFKA_TOPIC_TEXT_EXTRACTED"),
-			KafkaGroupID:            os.Getenv("KAFKA_GROUP_ID"),
+			DBUser:                   os.Getenv("DB_USER"),
+			DBPassword:               os.Getenv("DB_PASSWORD"),
+			DBName:                   os.Getenv("DB_NAME"),
+			DBHost:                   os.Getenv("DB_HOST"),
+			DBPort:                   os.Getenv("DB_PORT"),
+			DBReplicaDSNs:            os.Getenv("DB_REPLICA_DSNS"),
+			JWTSecret:                os.Getenv("JWT_SECRET"),
+			LLMGRPCAddr:              registry.LLM.Addr(),
+			OCRGRPCAddr:              registry.OCR.Addr(),
+			ASRGRPCAddr:              registry.ASR.Addr(),
+			JWTExpireMins:            60,
+			KafkaBrokers:             os.Getenv("KAFKA_BROKERS"),
+			KafkaTopicOCRReqs:        os.Getenv("KAFKA_TOPIC_OCR_REQUESTS"),
+			KafkaTopicFileProcess:    os.Getenv("KAFKA_TOPIC_FILE_PROCESSING"),
+			KafkaTopicTextExtracted:  os.Getenv("KAFKA_TOPIC_TEXT_EXTRACTED"),
+			KafkaTopicMaterialEvents: os.Getenv("KAFKA_TOPIC_MATERIAL_EVENTS"),
+			KafkaTopicTaskEvents:     getEnv("KAFKA_TOPIC_TASK_EVENTS", "task.events"),
+			KafkaTopicNotifications:  getEnv("KAFKA_TOPIC_NOTIFICATIONS", "notification.requests"),
+			KafkaGroupID:             os.Getenv("KAFKA_GROUP_ID"),
 		},
 		MinIO: MinIOConfig{
 			Endpoint:        os.Getenv("MINIO_ENDPOINT"),
@@ -67,6 +187,103 @@ func LoadConfig() *Config {
 			SecretAccessKey: os.Getenv("MINIO_SECRET_KEY"),
 			UseSSL:          false,
 			BucketName:      os.Getenv("MINIO_BUCKET_NAME"),
+			SSEMode:         os.Getenv("MINIO_SSE_MODE"),
+			SSEKMSKeyID:     os.Getenv("MINIO_SSE_KMS_KEY_ID"),
+			SSEKMSContext:   os.Getenv("MINIO_SSE_KMS_CONTEXT"),
+		},
+		Janitor: JanitorConfig{
+			Enabled:       getEnvBool("JANITOR_ENABLED", true),
+			Interval:      getEnvDuration("JANITOR_INTERVAL", time.Hour),
+			ProcessingTTL: getEnvDuration("JANITOR_PROCESSING_TTL", 2*time.Hour),
+			UploadTTL:     getEnvDuration("JANITOR_UPLOAD_TTL", 24*time.Hour),
+			OrphanMinAge:  getEnvDuration("JANITOR_ORPHAN_MIN_AGE", 24*time.Hour),
+		},
+		Lifecycle: LifecycleConfig{
+			Enabled:                getEnvBool("MINIO_LIFECYCLE_ENABLED", false),
+			TransitionDays:         getEnvInt("MINIO_TRANSITION_DAYS", 90),
+			TransitionTier:         os.Getenv("MINIO_TRANSITION_TIER"),
+			TrashExpireDays:        getEnvInt("MINIO_TRASH_EXPIRE_DAYS", 30),
+			LargeVideoStorageClass: os.Getenv("MINIO_LARGE_VIDEO_STORAGE_CLASS"),
+			LargeVideoBytes:        int64(getEnvInt("MINIO_LARGE_VIDEO_MB", 500)) << 20,
+		},
+		Media: MediaConfig{
+			FFprobePath:  getEnv("FFPROBE_PATH", "ffprobe"),
+			ProbeTimeout: getEnvDuration("MEDIA_PROBE_TIMEOUT", time.Minute),
+			ProbeWorkers: getEnvInt("MEDIA_PROBE_WORKERS", 2),
+		},
+		Estimate: EstimateConfig{
+			ProbeTimeout:       getEnvDuration("ESTIMATE_PROBE_TIMEOUT", 20*time.Second),
+			Currency:           getEnv("ESTIMATE_CURRENCY", "USD"),
+			OCRCostPerPage:     getEnvFloat("ESTIMATE_OCR_COST_PER_PAGE", 0.0015),
+			ASRCostPerMinute:   getEnvFloat("ESTIMATE_ASR_COST_PER_MINUTE", 0.006),
+			EmbeddingCostPer1K: getEnvFloat("ESTIMATE_EMBEDDING_COST_PER_1K_TOKENS", 0.00002),
+			LLMCostPer1K:       getEnvFloat("ESTIMATE_LLM_COST_PER_1K_TOKENS", 0.0005),
+			CharsPerPage:       getEnvInt("ESTIMATE_CHARS_PER_PAGE", 1800),
+			CharsPerMinute:     getEnvInt("ESTIMATE_CHARS_PER_MINUTE", 250),
+			CharsPerToken:      getEnvFloat("ESTIMATE_CHARS_PER_TOKEN", 1.5),
 		},
+		Dedup: DedupConfig{
+			Enabled:            getEnvBool("DEDUP_ENABLED", true),
+			SimHashMaxDistance: getEnvInt("DEDUP_SIMHASH_MAX_DISTANCE", 3),
+			SimHashUploadBytes: int64(getEnvInt("DEDUP_SIMHASH_UPLOAD_MB", 4)) << 20,
+		},
+		Limits: LimitsConfig{
+			MaxInFlightPerUser: getEnvInt("PROCESSING_MAX_INFLIGHT_PER_USER", 5),
+			MaxQueuedPerUser:   getEnvInt("PROCESSING_MAX_QUEUED_PER_USER", 100),
+		},
+		Schedule: ScheduleConfig{
+			HeavyWindows:    os.Getenv("PROCESSING_HEAVY_WINDOWS"),
+			Timezone:        getEnv("PROCESSING_WINDOW_TIMEZONE", "Asia/Shanghai"),
+			HeavyASRMinutes: getEnvInt("PROCESSING_HEAVY_ASR_MINUTES", 60),
+			HeavyASRMB:      getEnvInt("PROCESSING_HEAVY_ASR_MB", 1024),
+			Interval:        getEnvDuration("PROCESSING_SCHEDULER_INTERVAL", time.Minute),
+		},
+		UploadSession: UploadSessionConfig{
+			TTL:      getEnvDuration("UPLOAD_SESSION_TTL", 24*time.Hour),
+			MaxBytes: int64(getEnvInt("UPLOAD_SESSION_MAX_MB", 2048)) << 20,
+		},
+	}
+}
+
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+func getEnvBool(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if b, err := strconv.ParseBool(value); err == nil {
+			return b
+		}
+	}
+	return defaultValue
+}
+
+func getEnvInt(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		if n, err := strconv.Atoi(value); err == nil {
+			return n
+		}
+	}
+	return defaultValue
+}
+
+func getEnvFloat(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if f, err := strconv.ParseFloat(value, 64); err == nil {
+			return f
+		}
+	}
+	return defaultValue
+}
+
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if d, err := time.ParseDuration(value); err == nil {
+			return d
+		}
 	}
+	return defaultValue
 }
@@ -1,6 +1,7 @@
 package database
 
 import (
+	"github.com/RigelNana/arkstudy/pkg/dbx"
 	"github.com/RigelNana/arkstudy/services/material-service/config"
 
 	"gorm.io/driver/postgres"
@@ -16,5 +17,13 @@ func InitDB() *gorm.DB {
 	if err != nil {
 		panic("failed to connect database: " + err.Error())
 	}
+	// 审计字段、乐观锁与软删除检查
+	if err := db.Use(dbx.Plugin{}); err != nil {
+		panic("failed to register dbx plugin: " + err.Error())
+	}
+	// 只读副本，处理任务大量写入时列表查询不占用主库
+	if err := dbx.UseReplicas(db, postgres.Open, dbx.ParseDSNList(config.Database.DBReplicaDSNs), nil); err != nil {
+		panic("failed to connect read replicas: " + err.Error())
+	}
 	return db
 }
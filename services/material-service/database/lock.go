@@ -0,0 +1,37 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// TryAdvisoryLock 尝试获取 Postgres 会话级 advisory lock，用于多副本之间选出唯一执行者。
+// 锁绑定在一条独占连接上，获取成功时返回的 unlock 负责释放锁并归还连接；
+// 锁已被其他副本持有时 ok 为 false。连接断开（进程崩溃）时锁由数据库自动释放。
+func TryAdvisoryLock(ctx context.Context, db *gorm.DB, key int64) (unlock func(), ok bool, err error) {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, false, err
+	}
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return nil, false, fmt.Errorf("acquire connection: %w", err)
+	}
+
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", key).Scan(&ok); err != nil {
+		conn.Close()
+		return nil, false, fmt.Errorf("try advisory lock: %w", err)
+	}
+	if !ok {
+		conn.Close()
+		return nil, false, nil
+	}
+
+	unlock = func() {
+		_, _ = conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", key)
+		conn.Close()
+	}
+	return unlock, true, nil
+}
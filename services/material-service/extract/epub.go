@@ -0,0 +1,142 @@
+package extract
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/url"
+	"path"
+	"strings"
+)
+
+const (
+	// 单个部件的解压上限，防止 zip 炸弹
+	maxPartSize = 32 << 20
+	// 全书提取文本的总长度上限
+	maxBookTextSize = 64 << 20
+)
+
+// EPUB 容器描述文件，指向 OPF 包文件
+type epubContainer struct {
+	Rootfiles []struct {
+		FullPath string `xml:"full-path,attr"`
+	} `xml:"rootfiles>rootfile"`
+}
+
+// OPF 包文件：manifest 列出全部资源，spine 给出阅读顺序
+type epubPackage struct {
+	Title    string `xml:"metadata>title"`
+	Manifest []struct {
+		ID        string `xml:"id,attr"`
+		Href      string `xml:"href,attr"`
+		MediaType string `xml:"media-type,attr"`
+	} `xml:"manifest>item"`
+	Spine []struct {
+		IDRef  string `xml:"idref,attr"`
+		Linear string `xml:"linear,attr"`
+	} `xml:"spine>itemref"`
+}
+
+// EPUBText 按 spine 阅读顺序提取 EPUB 各章节正文，章节之间以空行分隔。
+// linear="no" 的附属内容（如版权页、注释弹窗）不计入
+func EPUBText(data []byte) (string, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return "", fmt.Errorf("open epub: %w", err)
+	}
+	files := make(map[string]*zip.File, len(zr.File))
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+
+	var container epubContainer
+	if err := readXML(files, "META-INF/container.xml", &container); err != nil {
+		return "", err
+	}
+	if len(container.Rootfiles) == 0 || container.Rootfiles[0].FullPath == "" {
+		return "", fmt.Errorf("epub container has no rootfile")
+	}
+	opfPath := container.Rootfiles[0].FullPath
+	var pkg epubPackage
+	if err := readXML(files, opfPath, &pkg); err != nil {
+		return "", err
+	}
+
+	hrefs := make(map[string]string, len(pkg.Manifest))
+	for _, item := range pkg.Manifest {
+		if item.MediaType == "application/xhtml+xml" || item.MediaType == "text/html" {
+			hrefs[item.ID] = item.Href
+		}
+	}
+
+	var chapters []string
+	total := 0
+	base := path.Dir(opfPath)
+	for _, ref := range pkg.Spine {
+		href, ok := hrefs[ref.IDRef]
+		if !ok || ref.Linear == "no" {
+			continue
+		}
+		href = strings.SplitN(href, "#", 2)[0]
+		if unescaped, err := url.PathUnescape(href); err == nil {
+			href = unescaped
+		}
+		name := path.Join(base, href)
+		raw, err := readPart(files, name)
+		if err != nil {
+			return "", err
+		}
+		text, err := HTMLText(bytes.NewReader(raw))
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", name, err)
+		}
+		if text = strings.TrimSpace(text); text == "" {
+			continue
+		}
+		total += len(text)
+		if total > maxBookTextSize {
+			return "", fmt.Errorf("epub text exceeds %d bytes", maxBookTextSize)
+		}
+		chapters = append(chapters, text)
+	}
+	if len(chapters) == 0 {
+		return "", fmt.Errorf("epub has no readable chapters")
+	}
+	if title := strings.TrimSpace(pkg.Title); title != "" && !strings.HasPrefix(chapters[0], "# ") {
+		chapters = append([]string{"# " + title}, chapters...)
+	}
+	return strings.Join(chapters, "\n\n"), nil
+}
+
+func readXML(files map[string]*zip.File, name string, v interface{}) error {
+	raw, err := readPart(files, name)
+	if err != nil {
+		return err
+	}
+	if err := xml.Unmarshal(raw, v); err != nil {
+		return fmt.Errorf("parse %s: %w", name, err)
+	}
+	return nil
+}
+
+func readPart(files map[string]*zip.File, name string) ([]byte, error) {
+	f, ok := files[name]
+	if !ok {
+		return nil, fmt.Errorf("epub part %s not found", name)
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return nil, fmt.Errorf("open %s: %w", name, err)
+	}
+	defer rc.Close()
+	raw, err := io.ReadAll(io.LimitReader(rc, maxPartSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", name, err)
+	}
+	if len(raw) > maxPartSize {
+		return nil, fmt.Errorf("%s exceeds %d bytes", name, maxPartSize)
+	}
+	return raw, nil
+}
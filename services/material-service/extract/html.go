@@ -0,0 +1,244 @@
+// Package extract 从 HTML 与 EPUB 资料中提取正文文本，供 text.extracted 流水线切分入库。
+// 导航、页眉页脚、脚本等模板内容会被去除，标题以 Markdown "#" 形式保留，便于下游按章节分块。
+package extract
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+	"golang.org/x/net/html/charset"
+)
+
+// 不属于正文的元素，整棵子树跳过
+var skippedElements = map[atom.Atom]bool{
+	atom.Head:     true,
+	atom.Script:   true,
+	atom.Style:    true,
+	atom.Noscript: true,
+	atom.Template: true,
+	atom.Nav:      true,
+	atom.Aside:    true,
+	atom.Form:     true,
+	atom.Iframe:   true,
+	atom.Svg:      true,
+	atom.Button:   true,
+	atom.Select:   true,
+}
+
+// 块级元素，前后换行
+var blockElements = map[atom.Atom]bool{
+	atom.P: true, atom.Div: true, atom.Section: true, atom.Article: true, atom.Main: true,
+	atom.Blockquote: true, atom.Pre: true, atom.Ul: true, atom.Ol: true, atom.Li: true,
+	atom.Dl: true, atom.Dt: true, atom.Dd: true, atom.Table: true, atom.Tr: true,
+	atom.Figure: true, atom.Figcaption: true, atom.Hr: true, atom.Br: true,
+}
+
+// 标题层级
+var headingLevels = map[atom.Atom]int{
+	atom.H1: 1, atom.H2: 2, atom.H3: 3, atom.H4: 4, atom.H5: 5, atom.H6: 6,
+}
+
+// 常见的模板区块 class/id 关键字（导航栏、侧边栏、广告、评论区等）
+var boilerplateHints = []string{"nav", "menu", "sidebar", "footer", "breadcrumb", "advert", "banner", "cookie", "comment", "share", "related"}
+
+// HTMLText 提取 HTML 正文。页面含 <main> 或 <article> 时只取其内容，否则取 <body> 并去除模板区块。
+// 编码按 BOM 与 <meta charset> 识别，默认 UTF-8
+func HTMLText(r io.Reader) (string, error) {
+	decoded, err := charset.NewReader(r, "text/html")
+	if err != nil {
+		return "", fmt.Errorf("detect html charset: %w", err)
+	}
+	doc, err := html.Parse(decoded)
+	if err != nil {
+		return "", fmt.Errorf("parse html: %w", err)
+	}
+	root := findElement(doc, atom.Main)
+	if root == nil {
+		root = findElement(doc, atom.Article)
+	}
+	if root == nil {
+		root = findElement(doc, atom.Body)
+	}
+	if root == nil {
+		root = doc
+	}
+
+	w := &textWriter{scoped: root.DataAtom == atom.Main || root.DataAtom == atom.Article}
+	w.walk(root)
+	text := w.String()
+	if text == "" {
+		// 没有正文时退回页面标题
+		if t := findElement(doc, atom.Title); t != nil {
+			text = strings.TrimSpace(collapseSpace(nodeText(t)))
+		}
+	}
+	return text, nil
+}
+
+type textWriter struct {
+	lines []string
+	cur   strings.Builder
+	pre   int // 所在 <pre> 嵌套层数，其中保留原始换行
+	// 已限定在 <main>/<article> 内时，其中的 <header>/<footer> 属于正文（如文章标题），不再跳过
+	scoped bool
+}
+
+func (w *textWriter) walk(n *html.Node) {
+	switch n.Type {
+	case html.TextNode:
+		if w.pre > 0 {
+			for i, part := range strings.Split(n.Data, "\n") {
+				if i > 0 {
+					w.breakLine()
+				}
+				w.cur.WriteString(part)
+			}
+			return
+		}
+		w.cur.WriteString(collapseSpace(n.Data))
+		return
+	case html.ElementNode:
+		if skippedElements[n.DataAtom] || isBoilerplate(n) {
+			return
+		}
+		if !w.scoped && (n.DataAtom == atom.Header || n.DataAtom == atom.Footer) {
+			return
+		}
+		if level, ok := headingLevels[n.DataAtom]; ok {
+			w.breakLine()
+			if heading := strings.TrimSpace(collapseSpace(nodeText(n))); heading != "" {
+				w.lines = append(w.lines, strings.Repeat("#", level)+" "+heading)
+			}
+			return
+		}
+		if n.DataAtom == atom.Img {
+			// 图片只保留替代文本
+			if alt := strings.TrimSpace(attr(n, "alt")); alt != "" {
+				w.cur.WriteString(" " + alt + " ")
+			}
+			return
+		}
+	}
+
+	block := n.Type == html.ElementNode && blockElements[n.DataAtom]
+	if block {
+		w.breakLine()
+		if n.DataAtom == atom.Li {
+			w.cur.WriteString("- ")
+		}
+	}
+	if n.DataAtom == atom.Pre {
+		w.pre++
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		w.walk(c)
+		// 表格单元格之间用制表符分隔
+		if c.Type == html.ElementNode && (c.DataAtom == atom.Td || c.DataAtom == atom.Th) && c.NextSibling != nil {
+			w.cur.WriteString("\t")
+		}
+	}
+	if n.DataAtom == atom.Pre {
+		w.pre--
+	}
+	if block {
+		w.breakLine()
+	}
+}
+
+func (w *textWriter) breakLine() {
+	line := strings.TrimSpace(w.cur.String())
+	w.cur.Reset()
+	if line != "" && line != "-" {
+		w.lines = append(w.lines, line)
+	}
+}
+
+func (w *textWriter) String() string {
+	w.breakLine()
+	return strings.Join(w.lines, "\n")
+}
+
+// isBoilerplate 根据 role 与 class/id 判断是否为导航、侧边栏等模板区块
+func isBoilerplate(n *html.Node) bool {
+	switch attr(n, "role") {
+	case "navigation", "banner", "contentinfo", "complementary", "search":
+		return true
+	}
+	if hasAttr(n, "hidden") || attr(n, "aria-hidden") == "true" {
+		return true
+	}
+	if n.DataAtom != atom.Div && n.DataAtom != atom.Section && n.DataAtom != atom.Ul {
+		return false
+	}
+	hints := strings.ToLower(attr(n, "class") + " " + attr(n, "id"))
+	for _, h := range boilerplateHints {
+		if strings.Contains(hints, h) {
+			return true
+		}
+	}
+	return false
+}
+
+func findElement(n *html.Node, a atom.Atom) *html.Node {
+	if n.Type == html.ElementNode && n.DataAtom == a {
+		return n
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if found := findElement(c, a); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+func nodeText(n *html.Node) string {
+	if n.Type == html.TextNode {
+		return n.Data
+	}
+	var b strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		b.WriteString(nodeText(c))
+	}
+	return b.String()
+}
+
+func hasAttr(n *html.Node, key string) bool {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+// collapseSpace 将连续空白折叠为单个空格，保留首尾空白的位置以免相邻行内元素粘连
+func collapseSpace(s string) string {
+	fields := strings.Fields(s)
+	if len(fields) == 0 {
+		if s != "" {
+			return " "
+		}
+		return ""
+	}
+	out := strings.Join(fields, " ")
+	if strings.TrimLeftFunc(s, unicode.IsSpace) != s {
+		out = " " + out
+	}
+	if strings.TrimRightFunc(s, unicode.IsSpace) != s {
+		out += " "
+	}
+	return out
+}
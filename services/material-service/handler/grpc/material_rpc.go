@@ -1,16 +1,22 @@
 package grpc
 
 import (
-	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
+	"time"
 
 	"github.com/RigelNana/arkstudy/proto/material"
 	"github.com/RigelNana/arkstudy/services/material-service/models"
+	"github.com/RigelNana/arkstudy/services/material-service/repository"
 	"github.com/RigelNana/arkstudy/services/material-service/service"
 	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 type MaterialRPCServer struct {
@@ -23,31 +29,19 @@ func NewMaterialRPCServer(svc service.MaterialService) *MaterialRPCServer {
 }
 
 func (s *MaterialRPCServer) UploadMaterial(stream material.MaterialService_UploadMaterialServer) error {
-	var metadata *material.MaterialInfo
-	var fileData bytes.Buffer
-
-	for {
-		req, err := stream.Recv()
-		if err == io.EOF {
-			break
-		}
-		if err != nil {
-			log.Printf("UploadMaterial receive error: %v", err)
-			return err
-		}
-
-		switch data := req.Data.(type) {
-		case *material.UploadMaterialRequest_Metadata:
-			metadata = data.Metadata
-			log.Printf("UploadMaterial metadata: UserID=%s, Title=%s, Filename=%s",
-				metadata.UserId, metadata.Title, metadata.OriginalFilename)
-		case *material.UploadMaterialRequest_ChunkData:
-			fileData.Write(data.ChunkData)
-		}
+	// 第一条消息必须是元数据，之后的文件分块直接以流的方式写入 MinIO
+	first, err := stream.Recv()
+	if err != nil && err != io.EOF {
+		log.Printf("UploadMaterial receive error: %v", err)
+		return err
 	}
 
+	var metadata *material.MaterialInfo
+	if first != nil {
+		metadata = first.GetMetadata()
+	}
 	if metadata == nil {
-		err := fmt.Errorf("metadata is required")
+		err := fmt.Errorf("metadata is required as the first message")
 		log.Printf("UploadMaterial failed: %v", err)
 		return stream.SendAndClose(&material.UploadMaterialResponse{
 			Success: false,
@@ -64,7 +58,10 @@ func (s *MaterialRPCServer) UploadMaterial(stream material.MaterialService_Uploa
 		})
 	}
 
-	mat, err := s.svc.UploadFile(userID, metadata.Title, metadata.OriginalFilename, fileData.Bytes())
+	log.Printf("UploadMaterial metadata: UserID=%s, Title=%s, Filename=%s",
+		metadata.UserId, metadata.Title, metadata.OriginalFilename)
+
+	result, err := s.svc.UploadFile(userID, metadata.Title, metadata.OriginalFilename, &uploadStreamReader{stream: stream}, -1, first.OnDuplicate)
 	if err != nil {
 		log.Printf("UploadMaterial failed: %v", err)
 		return stream.SendAndClose(&material.UploadMaterialResponse{
@@ -73,12 +70,79 @@ func (s *MaterialRPCServer) UploadMaterial(stream material.MaterialService_Uploa
 		})
 	}
 
-	log.Printf("UploadMaterial success: ID=%s", mat.ID.String())
+	message := "Upload successful"
+	if result.Linked {
+		message = "Linked to existing material"
+	}
+	log.Printf("UploadMaterial success: ID=%s, Linked=%v, Duplicates=%d", result.Material.ID.String(), result.Linked, len(result.Duplicates))
 	return stream.SendAndClose(&material.UploadMaterialResponse{
 		Success:    true,
-		Message:    "Upload successful",
-		MaterialId: mat.ID.String(),
+		Message:    message,
+		MaterialId: result.Material.ID.String(),
+		Duplicates: convertToProtoDuplicates(result.Duplicates),
+		Linked:     result.Linked,
+	})
+}
+
+// MinIO 预签名 URL 的最长有效期
+const maxPresignExpiry = 7 * 24 * time.Hour
+
+// uploadStreamReader 将 UploadMaterial 流中的文件分块适配为 io.Reader，客户端结束发送时返回 io.EOF
+type uploadStreamReader struct {
+	stream material.MaterialService_UploadMaterialServer
+	buf    []byte
+}
+
+func (r *uploadStreamReader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		req, err := r.stream.Recv()
+		if err != nil {
+			return 0, err
+		}
+		chunk, ok := req.Data.(*material.UploadMaterialRequest_ChunkData)
+		if !ok {
+			return 0, fmt.Errorf("unexpected metadata after file data")
+		}
+		r.buf = chunk.ChunkData
+	}
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
+
+func (s *MaterialRPCServer) CreateClip(ctx context.Context, req *material.CreateClipRequest) (*material.CreateClipResponse, error) {
+	log.Printf("CreateClip called: UserID=%s, SourceURL=%s, ContentType=%s, Size=%d",
+		req.UserId, req.SourceUrl, req.ContentType, len(req.Content))
+
+	userID, err := uuid.Parse(req.UserId)
+	if err != nil {
+		return &material.CreateClipResponse{
+			Success: false,
+			Message: "invalid user_id",
+		}, nil
+	}
+
+	mat, err := s.svc.CreateClip(userID, service.ClipInput{
+		Title:       req.Title,
+		Content:     req.Content,
+		ContentType: req.ContentType,
+		SourceURL:   req.SourceUrl,
+		PageTitle:   req.PageTitle,
 	})
+	if err != nil {
+		log.Printf("CreateClip failed: %v", err)
+		return &material.CreateClipResponse{
+			Success: false,
+			Message: err.Error(),
+		}, nil
+	}
+
+	log.Printf("CreateClip success: ID=%s", mat.ID.String())
+	return &material.CreateClipResponse{
+		Success:  true,
+		Message:  "Clip saved",
+		Material: convertToProtoMaterialInfo(mat),
+	}, nil
 }
 
 func (s *MaterialRPCServer) DeleteMaterial(ctx context.Context, req *material.DeleteMaterialRequest) (*material.DeleteMaterialResponse, error) {
@@ -172,23 +236,186 @@ func (s *MaterialRPCServer) ListMaterials(ctx context.Context, req *material.Lis
 	}
 
 	for _, mat := range materials {
-		materialInfo := &material.MaterialInfo{
-			Id:               mat.ID.String(),
-			UserId:           mat.UserID.String(),
-			Title:            mat.Title,
-			OriginalFilename: mat.OriginalFilename,
-			FileType:         mat.FileType,
-			SizeBytes:        mat.SizeBytes,
-			Status:           mat.Status,
-			CreatedAt:        mat.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
-		}
-		resp.Materials = append(resp.Materials, materialInfo)
+		resp.Materials = append(resp.Materials, convertToProtoMaterialInfo(mat))
 	}
 
 	log.Printf("ListMaterials success: returned %d materials, total %d", len(resp.Materials), total)
 	return resp, nil
 }
 
+func (s *MaterialRPCServer) GetMaterial(ctx context.Context, req *material.GetMaterialRequest) (*material.GetMaterialResponse, error) {
+	log.Printf("GetMaterial called: MaterialID=%s, UserID=%s", req.MaterialId, req.UserId)
+
+	materialID, err := uuid.Parse(req.MaterialId)
+	if err != nil {
+		log.Printf("GetMaterial failed: invalid material_id %s", req.MaterialId)
+		return &material.GetMaterialResponse{
+			Found:   false,
+			Message: "invalid material_id",
+		}, nil
+	}
+
+	mat, err := s.svc.GetByID(materialID)
+	if err != nil {
+		log.Printf("GetMaterial failed: material not found %s", req.MaterialId)
+		return &material.GetMaterialResponse{
+			Found:   false,
+			Message: "material not found",
+		}, nil
+	}
+
+	// 提供 user_id 时校验材料归属
+	if req.UserId != "" && mat.UserID.String() != req.UserId {
+		log.Printf("GetMaterial failed: permission denied for user %s", req.UserId)
+		return &material.GetMaterialResponse{
+			Found:   false,
+			Message: "permission denied",
+		}, nil
+	}
+
+	return &material.GetMaterialResponse{
+		Found:    true,
+		Message:  "success",
+		Material: convertToProtoMaterialInfo(mat),
+	}, nil
+}
+
+// ListChildMaterials 列出压缩包展开出的子资料
+func (s *MaterialRPCServer) ListChildMaterials(ctx context.Context, req *material.ListChildMaterialsRequest) (*material.ListChildMaterialsResponse, error) {
+	log.Printf("ListChildMaterials called: MaterialID=%s, UserID=%s", req.MaterialId, req.UserId)
+
+	materialID, err := uuid.Parse(req.MaterialId)
+	if err != nil {
+		return &material.ListChildMaterialsResponse{
+			Success: false,
+			Message: "invalid material_id",
+		}, nil
+	}
+	userID, err := uuid.Parse(req.UserId)
+	if err != nil {
+		return &material.ListChildMaterialsResponse{
+			Success: false,
+			Message: "invalid user_id",
+		}, nil
+	}
+
+	children, err := s.svc.ListChildren(materialID, userID)
+	if err != nil {
+		log.Printf("ListChildMaterials failed: %v", err)
+		return &material.ListChildMaterialsResponse{
+			Success: false,
+			Message: err.Error(),
+		}, nil
+	}
+
+	resp := &material.ListChildMaterialsResponse{Success: true, Materials: []*material.MaterialInfo{}}
+	for _, child := range children {
+		resp.Materials = append(resp.Materials, convertToProtoMaterialInfo(child))
+	}
+	return resp, nil
+}
+
+// ListDuplicateMaterials 列出与资料内容相同或相近的本人资料
+func (s *MaterialRPCServer) ListDuplicateMaterials(ctx context.Context, req *material.ListDuplicateMaterialsRequest) (*material.ListDuplicateMaterialsResponse, error) {
+	log.Printf("ListDuplicateMaterials called: MaterialID=%s, UserID=%s", req.MaterialId, req.UserId)
+
+	materialID, err := uuid.Parse(req.MaterialId)
+	if err != nil {
+		return &material.ListDuplicateMaterialsResponse{
+			Success: false,
+			Message: "invalid material_id",
+		}, nil
+	}
+	userID, err := uuid.Parse(req.UserId)
+	if err != nil {
+		return &material.ListDuplicateMaterialsResponse{
+			Success: false,
+			Message: "invalid user_id",
+		}, nil
+	}
+
+	matches, err := s.svc.ListDuplicateMaterials(materialID, userID)
+	if err != nil {
+		log.Printf("ListDuplicateMaterials failed: %v", err)
+		return &material.ListDuplicateMaterialsResponse{
+			Success: false,
+			Message: err.Error(),
+		}, nil
+	}
+	return &material.ListDuplicateMaterialsResponse{
+		Success:    true,
+		Duplicates: convertToProtoDuplicates(matches),
+	}, nil
+}
+
+func convertToProtoDuplicates(matches []service.DuplicateMatch) []*material.DuplicateMaterial {
+	out := make([]*material.DuplicateMaterial, 0, len(matches))
+	for _, m := range matches {
+		out = append(out, &material.DuplicateMaterial{
+			Material: convertToProtoMaterialInfo(m.Material),
+			Match:    m.Match,
+			Distance: int32(m.Distance),
+		})
+	}
+	return out
+}
+
+func (s *MaterialRPCServer) GetMaterialURL(ctx context.Context, req *material.GetMaterialURLRequest) (*material.GetMaterialURLResponse, error) {
+	log.Printf("GetMaterialURL called: MaterialID=%s, UserID=%s", req.MaterialId, req.UserId)
+
+	materialID, err := uuid.Parse(req.MaterialId)
+	if err != nil {
+		log.Printf("GetMaterialURL failed: invalid material_id %s", req.MaterialId)
+		return &material.GetMaterialURLResponse{
+			Success: false,
+			Message: "invalid material_id",
+		}, nil
+	}
+
+	mat, err := s.svc.GetByID(materialID)
+	if err != nil {
+		log.Printf("GetMaterialURL failed: material not found %s", req.MaterialId)
+		return &material.GetMaterialURLResponse{
+			Success: false,
+			Message: "material not found",
+		}, nil
+	}
+
+	if mat.UserID.String() != req.UserId {
+		log.Printf("GetMaterialURL failed: permission denied for user %s", req.UserId)
+		return &material.GetMaterialURLResponse{
+			Success: false,
+			Message: "permission denied",
+		}, nil
+	}
+
+	expiry := time.Duration(req.ExpirySeconds) * time.Second
+	if expiry <= 0 {
+		expiry = time.Hour
+	}
+	if expiry > maxPresignExpiry {
+		expiry = maxPresignExpiry
+	}
+
+	url, contentType, err := s.svc.GetMediaURL(mat, expiry)
+	if err != nil {
+		log.Printf("GetMaterialURL failed: %v", err)
+		return &material.GetMaterialURLResponse{
+			Success: false,
+			Message: err.Error(),
+		}, nil
+	}
+
+	return &material.GetMaterialURLResponse{
+		Success:     true,
+		Message:     "success",
+		Url:         url,
+		ContentType: contentType,
+		SizeBytes:   mat.SizeBytes,
+		ExpiresAt:   timestamppb.New(time.Now().Add(expiry)),
+	}, nil
+}
+
 // ======================= AI 处理相关 RPC 方法 =======================
 
 func (s *MaterialRPCServer) ProcessMaterial(ctx context.Context, req *material.ProcessMaterialRequest) (*material.ProcessMaterialResponse, error) {
@@ -223,12 +450,13 @@ func (s *MaterialRPCServer) ProcessMaterial(ctx context.Context, req *material.P
 	}
 
 	// 调用服务层
-	result, err := s.svc.ProcessMaterial(materialID, userID, processType, req.Options)
+	result, err := s.svc.ProcessMaterial(ctx, materialID, userID, processType, req.Options)
 	if err != nil {
 		log.Printf("ProcessMaterial failed: %v", err)
 		return &material.ProcessMaterialResponse{
 			Success: false,
 			Message: err.Error(),
+			Limit:   limitFromError(err),
 		}, nil
 	}
 
@@ -236,12 +464,19 @@ func (s *MaterialRPCServer) ProcessMaterial(ctx context.Context, req *material.P
 	protoResult := convertToProtoProcessingResult(result)
 
 	log.Printf("ProcessMaterial success: TaskID=%s", result.TaskID)
-	return &material.ProcessMaterialResponse{
+	resp := &material.ProcessMaterialResponse{
 		Success: true,
 		Message: "Processing started successfully",
 		TaskId:  result.TaskID,
 		Result:  protoResult,
-	}, nil
+	}
+	switch result.Status {
+	case models.ProcessingStatusQueued:
+		resp.Message, resp.Limit = s.queuedMessage(userID)
+	case models.ProcessingStatusScheduled:
+		resp.Message = scheduledMessage(result)
+	}
+	return resp, nil
 }
 
 func (s *MaterialRPCServer) GetProcessingResult(ctx context.Context, req *material.GetProcessingResultRequest) (*material.GetProcessingResultResponse, error) {
@@ -372,6 +607,317 @@ func (s *MaterialRPCServer) UpdateProcessingResult(ctx context.Context, req *mat
 	}, nil
 }
 
+func (s *MaterialRPCServer) UpdateProcessingProgress(ctx context.Context, req *material.UpdateProcessingProgressRequest) (*material.UpdateProcessingProgressResponse, error) {
+	if req.TaskId == "" {
+		log.Printf("UpdateProcessingProgress failed: task_id is required")
+		return &material.UpdateProcessingProgressResponse{
+			Success: false,
+			Message: "task_id is required",
+		}, nil
+	}
+
+	// 调用服务层
+	if err := s.svc.UpdateProgress(req.TaskId, req.Progress); err != nil {
+		log.Printf("UpdateProcessingProgress failed: %v", err)
+		return &material.UpdateProcessingProgressResponse{
+			Success: false,
+			Message: err.Error(),
+		}, nil
+	}
+
+	return &material.UpdateProcessingProgressResponse{
+		Success: true,
+		Message: "Processing progress updated successfully",
+	}, nil
+}
+
+func (s *MaterialRPCServer) RetryProcessingTask(ctx context.Context, req *material.RetryProcessingTaskRequest) (*material.RetryProcessingTaskResponse, error) {
+	log.Printf("RetryProcessingTask called: TaskID=%s, UserID=%s", req.TaskId, req.UserId)
+
+	if req.TaskId == "" {
+		log.Printf("RetryProcessingTask failed: task_id is required")
+		return &material.RetryProcessingTaskResponse{
+			Success: false,
+			Message: "task_id is required",
+		}, nil
+	}
+
+	userID, err := uuid.Parse(req.UserId)
+	if err != nil {
+		log.Printf("RetryProcessingTask failed: invalid user_id %s", req.UserId)
+		return &material.RetryProcessingTaskResponse{
+			Success: false,
+			Message: "invalid user_id",
+		}, nil
+	}
+
+	// 调用服务层
+	result, err := s.svc.RetryProcessingTask(ctx, req.TaskId, userID)
+	if err != nil {
+		log.Printf("RetryProcessingTask failed: %v", err)
+		return &material.RetryProcessingTaskResponse{
+			Success: false,
+			Message: err.Error(),
+			Limit:   limitFromError(err),
+		}, nil
+	}
+
+	log.Printf("RetryProcessingTask success: TaskID=%s, RetryCount=%d", result.TaskID, result.RetryCount)
+	resp := &material.RetryProcessingTaskResponse{
+		Success: true,
+		Message: "Task re-dispatched successfully",
+		Result:  convertToProtoProcessingResult(result),
+	}
+	switch result.Status {
+	case models.ProcessingStatusQueued:
+		resp.Message, resp.Limit = s.queuedMessage(userID)
+	case models.ProcessingStatusScheduled:
+		resp.Message = scheduledMessage(result)
+	}
+	return resp, nil
+}
+
+// scheduledMessage 大任务延后到处理时段时返回给客户端的说明
+func scheduledMessage(result *models.ProcessingResult) string {
+	if result.ScheduledFor == nil {
+		return "Processing scheduled for the next off-peak window"
+	}
+	return fmt.Sprintf("Processing scheduled for %s (off-peak window for large jobs); you will be notified when it starts",
+		result.ScheduledFor.UTC().Format(time.RFC3339))
+}
+
+// limitFromError 超出用户并发上限时返回当前用量，其他错误返回 nil
+func limitFromError(err error) *material.ProcessingLimit {
+	var limitErr *service.ProcessingLimitError
+	if errors.As(err, &limitErr) {
+		return convertToProtoProcessingLimit(&limitErr.Usage)
+	}
+	return nil
+}
+
+// queuedMessage 任务进入排队时返回给客户端的说明与当前用量
+func (s *MaterialRPCServer) queuedMessage(userID uuid.UUID) (string, *material.ProcessingLimit) {
+	usage, err := s.svc.GetProcessingUsage(userID)
+	if err != nil {
+		return "Processing queued: per-user job limit reached, it will start when earlier jobs finish", nil
+	}
+	return fmt.Sprintf("Processing queued: %d jobs in progress (limit %d), %d queued; it will start when earlier jobs finish",
+		usage.InFlight, usage.MaxInFlight, usage.Queued), convertToProtoProcessingLimit(usage)
+}
+
+func convertToProtoProcessingLimit(usage *service.ProcessingUsage) *material.ProcessingLimit {
+	return &material.ProcessingLimit{
+		InFlight:    int32(usage.InFlight),
+		Queued:      int32(usage.Queued),
+		MaxInFlight: int32(usage.MaxInFlight),
+		MaxQueued:   int32(usage.MaxQueued),
+	}
+}
+
+// ======================= 派生内容相关 =======================
+
+func (s *MaterialRPCServer) CompareProcessingResults(ctx context.Context, req *material.CompareProcessingResultsRequest) (*material.CompareProcessingResultsResponse, error) {
+	log.Printf("CompareProcessingResults called: MaterialID=%s, Base=%s, Target=%s", req.MaterialId, req.BaseTaskId, req.TargetTaskId)
+
+	materialID, err := uuid.Parse(req.MaterialId)
+	if err != nil {
+		return &material.CompareProcessingResultsResponse{
+			Success: false,
+			Message: "invalid material_id",
+		}, nil
+	}
+	userID, err := uuid.Parse(req.UserId)
+	if err != nil {
+		return &material.CompareProcessingResultsResponse{
+			Success: false,
+			Message: "invalid user_id",
+		}, nil
+	}
+
+	cmp, err := s.svc.CompareProcessingResults(materialID, userID, convertProcessingType(req.Type), req.BaseTaskId, req.TargetTaskId, int(req.ContextLines))
+	if err != nil {
+		log.Printf("CompareProcessingResults failed: %v", err)
+		return &material.CompareProcessingResultsResponse{
+			Success: false,
+			Message: err.Error(),
+		}, nil
+	}
+
+	hunks := make([]*material.DiffHunk, 0, len(cmp.Hunks))
+	for _, h := range cmp.Hunks {
+		lines := make([]*material.DiffLine, 0, len(h.Lines))
+		for _, l := range h.Lines {
+			lines = append(lines, &material.DiffLine{Op: l.Op, Text: l.Text})
+		}
+		hunks = append(hunks, &material.DiffHunk{
+			BaseStart:   int32(h.BaseStart),
+			BaseLines:   int32(h.BaseLines),
+			TargetStart: int32(h.TargetStart),
+			TargetLines: int32(h.TargetLines),
+			Lines:       lines,
+		})
+	}
+	return &material.CompareProcessingResultsResponse{
+		Success:        true,
+		Base:           convertToProtoResultQuality(cmp.Base),
+		Target:         convertToProtoResultQuality(cmp.Target),
+		Similarity:     cmp.Similarity,
+		LinesAdded:     int32(cmp.LinesAdded),
+		LinesRemoved:   int32(cmp.LinesRemoved),
+		LinesUnchanged: int32(cmp.LinesUnchanged),
+		Hunks:          hunks,
+		Truncated:      cmp.Truncated,
+	}, nil
+}
+
+func (s *MaterialRPCServer) EstimateProcessing(ctx context.Context, req *material.EstimateProcessingRequest) (*material.EstimateProcessingResponse, error) {
+	log.Printf("EstimateProcessing called: MaterialID=%s", req.MaterialId)
+
+	materialID, err := uuid.Parse(req.MaterialId)
+	if err != nil {
+		return &material.EstimateProcessingResponse{
+			Success: false,
+			Message: "invalid material_id",
+		}, nil
+	}
+	userID, err := uuid.Parse(req.UserId)
+	if err != nil {
+		return &material.EstimateProcessingResponse{
+			Success: false,
+			Message: "invalid user_id",
+		}, nil
+	}
+
+	est, err := s.svc.EstimateProcessing(materialID, userID)
+	if err != nil {
+		log.Printf("EstimateProcessing failed: %v", err)
+		return &material.EstimateProcessingResponse{
+			Success: false,
+			Message: err.Error(),
+		}, nil
+	}
+	if !est.Probed {
+		log.Printf("EstimateProcessing: material %s not probed, using size heuristic: %s", materialID, est.ProbeError)
+	}
+
+	estimates := make([]*material.ProcessingEstimate, 0, len(est.Estimates))
+	for _, e := range est.Estimates {
+		estimates = append(estimates, &material.ProcessingEstimate{
+			Type:    convertToProtoProcessingType(e.Type),
+			Pages:   int32(e.Pages),
+			Minutes: e.Minutes,
+			Chars:   e.Chars,
+			Tokens:  e.Tokens,
+			Cost:    e.Cost,
+		})
+	}
+	return &material.EstimateProcessingResponse{
+		Success:         true,
+		FileType:        est.Material.FileType,
+		SizeBytes:       est.Material.SizeBytes,
+		PageCount:       int32(est.PageCount),
+		DurationSeconds: est.Duration.Seconds(),
+		Probed:          est.Probed,
+		ProbeError:      est.ProbeError,
+		Estimates:       estimates,
+		TotalCost:       est.TotalCost,
+		Currency:        est.Currency,
+	}, nil
+}
+
+func (s *MaterialRPCServer) ListDerivedArtifacts(ctx context.Context, req *material.ListDerivedArtifactsRequest) (*material.ListDerivedArtifactsResponse, error) {
+	log.Printf("ListDerivedArtifacts called: MaterialID=%s, UserID=%s", req.MaterialId, req.UserId)
+
+	materialID, err := uuid.Parse(req.MaterialId)
+	if err != nil {
+		return &material.ListDerivedArtifactsResponse{
+			Success: false,
+			Message: "invalid material_id",
+		}, nil
+	}
+	userID, err := uuid.Parse(req.UserId)
+	if err != nil {
+		return &material.ListDerivedArtifactsResponse{
+			Success: false,
+			Message: "invalid user_id",
+		}, nil
+	}
+
+	artifacts, err := s.svc.ListDerivedArtifacts(materialID, userID)
+	if err != nil {
+		log.Printf("ListDerivedArtifacts failed: %v", err)
+		return &material.ListDerivedArtifactsResponse{
+			Success: false,
+			Message: err.Error(),
+		}, nil
+	}
+
+	return &material.ListDerivedArtifactsResponse{
+		Success:   true,
+		Artifacts: convertToProtoDerivedArtifacts(artifacts),
+	}, nil
+}
+
+func (s *MaterialRPCServer) RegenerateDerived(ctx context.Context, req *material.RegenerateDerivedRequest) (*material.RegenerateDerivedResponse, error) {
+	log.Printf("RegenerateDerived called: MaterialID=%s, UserID=%s, Kinds=%v", req.MaterialId, req.UserId, req.Kinds)
+
+	materialID, err := uuid.Parse(req.MaterialId)
+	if err != nil {
+		return &material.RegenerateDerivedResponse{
+			Success: false,
+			Message: "invalid material_id",
+		}, nil
+	}
+	userID, err := uuid.Parse(req.UserId)
+	if err != nil {
+		return &material.RegenerateDerivedResponse{
+			Success: false,
+			Message: "invalid user_id",
+		}, nil
+	}
+
+	artifacts, err := s.svc.RegenerateDerived(materialID, userID, req.Kinds)
+	if err != nil {
+		log.Printf("RegenerateDerived failed: %v", err)
+		return &material.RegenerateDerivedResponse{
+			Success: false,
+			Message: err.Error(),
+		}, nil
+	}
+
+	log.Printf("RegenerateDerived success: MaterialID=%s", req.MaterialId)
+	return &material.RegenerateDerivedResponse{
+		Success:   true,
+		Message:   "Regeneration started successfully",
+		Artifacts: convertToProtoDerivedArtifacts(artifacts),
+	}, nil
+}
+
+func (s *MaterialRPCServer) UpdateDerivedArtifact(ctx context.Context, req *material.UpdateDerivedArtifactRequest) (*material.UpdateDerivedArtifactResponse, error) {
+	log.Printf("UpdateDerivedArtifact called: MaterialID=%s, Kind=%s, Status=%s", req.MaterialId, req.Kind, req.Status)
+
+	materialID, err := uuid.Parse(req.MaterialId)
+	if err != nil {
+		return &material.UpdateDerivedArtifactResponse{
+			Success: false,
+			Message: "invalid material_id",
+		}, nil
+	}
+
+	if err := s.svc.UpdateDerivedArtifact(materialID, req.Kind, req.Status, req.SourceTaskId, req.ErrorMessage); err != nil {
+		log.Printf("UpdateDerivedArtifact failed: %v", err)
+		return &material.UpdateDerivedArtifactResponse{
+			Success: false,
+			Message: err.Error(),
+		}, nil
+	}
+
+	return &material.UpdateDerivedArtifactResponse{
+		Success: true,
+		Message: "Derived artifact updated successfully",
+	}, nil
+}
+
 // ======================= 辅助转换函数 =======================
 
 func convertProcessingType(protoType material.ProcessingType) string {
@@ -397,6 +943,10 @@ func convertProcessingStatus(protoStatus material.ProcessingStatus) string {
 		return models.ProcessingStatusCompleted
 	case material.ProcessingStatus_FAILED:
 		return models.ProcessingStatusFailed
+	case material.ProcessingStatus_QUEUED:
+		return models.ProcessingStatusQueued
+	case material.ProcessingStatus_SCHEDULED:
+		return models.ProcessingStatusScheduled
 	default:
 		return ""
 	}
@@ -425,6 +975,10 @@ func convertToProtoProcessingStatus(modelStatus string) material.ProcessingStatu
 		return material.ProcessingStatus_COMPLETED
 	case models.ProcessingStatusFailed:
 		return material.ProcessingStatus_FAILED
+	case models.ProcessingStatusQueued:
+		return material.ProcessingStatus_QUEUED
+	case models.ProcessingStatusScheduled:
+		return material.ProcessingStatus_SCHEDULED
 	default:
 		return material.ProcessingStatus_PENDING // 默认值
 	}
@@ -432,10 +986,23 @@ func convertToProtoProcessingStatus(modelStatus string) material.ProcessingStatu
 
 func convertToProtoProcessingResult(result *models.ProcessingResult) *material.ProcessingResult {
 	metadata := make(map[string]string)
-	// 简单的 JSON 转换，实际项目中可能需要更复杂的处理
-	// 这里假设 Metadata 是简单的 key-value 对
+	// Metadata 为扁平的 key-value JSON，非字符串值按 JSON 文本返回
+	if len(result.Metadata) > 0 {
+		var raw map[string]interface{}
+		if err := json.Unmarshal(result.Metadata, &raw); err == nil {
+			for k, v := range raw {
+				if str, ok := v.(string); ok {
+					metadata[k] = str
+					continue
+				}
+				if b, err := json.Marshal(v); err == nil {
+					metadata[k] = string(b)
+				}
+			}
+		}
+	}
 
-	return &material.ProcessingResult{
+	pr := &material.ProcessingResult{
 		Id:           result.ID.String(),
 		MaterialId:   result.MaterialID.String(),
 		TaskId:       result.TaskID,
@@ -443,9 +1010,29 @@ func convertToProtoProcessingResult(result *models.ProcessingResult) *material.P
 		Status:       convertToProtoProcessingStatus(result.Status),
 		Content:      result.Content,
 		Metadata:     metadata,
-		CreatedAt:    result.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
-		UpdatedAt:    result.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		CreatedAt:    timestamppb.New(result.CreatedAt),
+		UpdatedAt:    timestamppb.New(result.UpdatedAt),
 		ErrorMessage: result.ErrorMessage,
+		RetryCount:   int32(result.RetryCount),
+		Progress:     result.Progress,
+		Shadow:       result.Shadow,
+	}
+	if result.ScheduledFor != nil {
+		pr.ScheduledFor = timestamppb.New(*result.ScheduledFor)
+	}
+	return pr
+}
+
+func convertToProtoResultQuality(q service.ResultQuality) *material.ResultQuality {
+	return &material.ResultQuality{
+		TaskId:     q.Result.TaskID,
+		Engine:     q.Engine,
+		Shadow:     q.Result.Shadow,
+		Chars:      int32(q.Chars),
+		Lines:      int32(q.Lines),
+		Confidence: float32(q.Confidence),
+		DurationMs: q.Duration.Milliseconds(),
+		CreatedAt:  timestamppb.New(q.Result.CreatedAt),
 	}
 }
 
@@ -456,3 +1043,325 @@ func convertMetadata(protoMetadata map[string]string) map[string]interface{} {
 	}
 	return metadata
 }
+
+func convertToProtoMaterialInfo(mat *models.Material) *material.MaterialInfo {
+	info := &material.MaterialInfo{
+		Id:               mat.ID.String(),
+		UserId:           mat.UserID.String(),
+		Title:            mat.Title,
+		OriginalFilename: mat.OriginalFilename,
+		FileType:         mat.FileType,
+		SizeBytes:        mat.SizeBytes,
+		Status:           mat.Status,
+		CreatedAt:        timestamppb.New(mat.CreatedAt),
+		Language:         mat.Language,
+		ContentHash:      mat.ContentHash,
+	}
+	if mat.ParentID != nil {
+		info.ParentId = mat.ParentID.String()
+	}
+	if m := mat.Media; m.ProbedAt != nil {
+		info.Media = &material.MediaInfo{
+			DurationSeconds: m.DurationSeconds,
+			Width:           int32(m.Width),
+			Height:          int32(m.Height),
+			VideoCodec:      m.VideoCodec,
+			AudioCodec:      m.AudioCodec,
+			FormatName:      m.FormatName,
+			ProbedAt:        timestamppb.New(*m.ProbedAt),
+		}
+	}
+	if d := mat.Document; d.ProbedAt != nil {
+		doc := &material.DocumentInfo{
+			PageCount: int32(d.PageCount),
+			Title:     d.Title,
+			Author:    d.Author,
+			ProbedAt:  timestamppb.New(*d.ProbedAt),
+		}
+		for _, e := range d.GetTOC() {
+			doc.Toc = append(doc.Toc, &material.TocEntry{Title: e.Title, Page: int32(e.Page), Level: int32(e.Level)})
+		}
+		info.Document = doc
+	}
+	return info
+}
+
+func convertToProtoDerivedArtifacts(artifacts []*models.DerivedArtifact) []*material.DerivedArtifact {
+	out := make([]*material.DerivedArtifact, 0, len(artifacts))
+	for _, a := range artifacts {
+		pa := &material.DerivedArtifact{
+			MaterialId:   a.MaterialID.String(),
+			Kind:         a.Kind,
+			Status:       a.Status,
+			SourceTaskId: a.SourceTaskID,
+			StaleReason:  a.StaleReason,
+			ErrorMessage: a.ErrorMessage,
+			UpdatedAt:    timestamppb.New(a.UpdatedAt),
+		}
+		if a.StaleSince != nil {
+			pa.StaleSince = timestamppb.New(*a.StaleSince)
+		}
+		if a.RegeneratedAt != nil {
+			pa.RegeneratedAt = timestamppb.New(*a.RegeneratedAt)
+		}
+		out = append(out, pa)
+	}
+	return out
+}
+
+// ======================= 批注相关 =======================
+
+func (s *MaterialRPCServer) CreateAnnotation(ctx context.Context, req *material.CreateAnnotationRequest) (*material.AnnotationResponse, error) {
+	log.Printf("CreateAnnotation called: MaterialID=%s, UserID=%s, Kind=%s, Anchor=%s", req.MaterialId, req.UserId, req.Kind, req.AnchorType)
+
+	materialID, err := uuid.Parse(req.MaterialId)
+	if err != nil {
+		return &material.AnnotationResponse{Success: false, Message: "invalid material_id"}, nil
+	}
+	userID, err := uuid.Parse(req.UserId)
+	if err != nil {
+		return &material.AnnotationResponse{Success: false, Message: "invalid user_id"}, nil
+	}
+
+	annotation, err := s.svc.CreateAnnotation(userID, service.AnnotationInput{
+		MaterialID: materialID,
+		Kind:       req.Kind,
+		AnchorType: req.AnchorType,
+		Page:       req.Page,
+		StartTime:  req.StartTime,
+		EndTime:    req.EndTime,
+		Quote:      req.Quote,
+		Content:    req.Content,
+		Color:      req.Color,
+	})
+	if err != nil {
+		log.Printf("CreateAnnotation failed: %v", err)
+		return &material.AnnotationResponse{Success: false, Message: err.Error()}, nil
+	}
+	return &material.AnnotationResponse{Success: true, Annotation: convertToProtoAnnotation(annotation)}, nil
+}
+
+func (s *MaterialRPCServer) UpdateAnnotation(ctx context.Context, req *material.UpdateAnnotationRequest) (*material.AnnotationResponse, error) {
+	id, err := uuid.Parse(req.Id)
+	if err != nil {
+		return &material.AnnotationResponse{Success: false, Message: "invalid annotation id"}, nil
+	}
+	userID, err := uuid.Parse(req.UserId)
+	if err != nil {
+		return &material.AnnotationResponse{Success: false, Message: "invalid user_id"}, nil
+	}
+
+	var content, color *string
+	if req.Content != "" {
+		content = &req.Content
+	}
+	if req.Color != "" {
+		color = &req.Color
+	}
+	annotation, err := s.svc.UpdateAnnotation(id, userID, content, color)
+	if err != nil {
+		return &material.AnnotationResponse{Success: false, Message: err.Error()}, nil
+	}
+	return &material.AnnotationResponse{Success: true, Annotation: convertToProtoAnnotation(annotation)}, nil
+}
+
+func (s *MaterialRPCServer) DeleteAnnotation(ctx context.Context, req *material.DeleteAnnotationRequest) (*material.DeleteAnnotationResponse, error) {
+	id, err := uuid.Parse(req.Id)
+	if err != nil {
+		return &material.DeleteAnnotationResponse{Success: false, Message: "invalid annotation id"}, nil
+	}
+	userID, err := uuid.Parse(req.UserId)
+	if err != nil {
+		return &material.DeleteAnnotationResponse{Success: false, Message: "invalid user_id"}, nil
+	}
+	if err := s.svc.DeleteAnnotation(id, userID); err != nil {
+		return &material.DeleteAnnotationResponse{Success: false, Message: err.Error()}, nil
+	}
+	return &material.DeleteAnnotationResponse{Success: true, Message: "annotation deleted"}, nil
+}
+
+func (s *MaterialRPCServer) ListAnnotations(ctx context.Context, req *material.ListAnnotationsRequest) (*material.ListAnnotationsResponse, error) {
+	userID, err := uuid.Parse(req.UserId)
+	if err != nil {
+		return &material.ListAnnotationsResponse{Success: false, Message: "invalid user_id"}, nil
+	}
+	materialIDs := make([]uuid.UUID, 0, len(req.MaterialIds))
+	for _, raw := range req.MaterialIds {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			return &material.ListAnnotationsResponse{Success: false, Message: "invalid material_id: " + raw}, nil
+		}
+		materialIDs = append(materialIDs, id)
+	}
+
+	annotations, err := s.svc.ListAnnotations(userID, materialIDs, repository.AnnotationFilter{
+		Kind:      req.Kind,
+		Page:      req.Page,
+		StartTime: req.StartTime,
+		EndTime:   req.EndTime,
+	})
+	if err != nil {
+		log.Printf("ListAnnotations failed: %v", err)
+		return &material.ListAnnotationsResponse{Success: false, Message: err.Error()}, nil
+	}
+
+	out := make([]*material.Annotation, 0, len(annotations))
+	for _, a := range annotations {
+		out = append(out, convertToProtoAnnotation(a))
+	}
+	return &material.ListAnnotationsResponse{Success: true, Annotations: out}, nil
+}
+
+func convertToProtoAnnotation(a *models.Annotation) *material.Annotation {
+	return &material.Annotation{
+		Id:         a.ID.String(),
+		MaterialId: a.MaterialID.String(),
+		UserId:     a.UserID.String(),
+		Kind:       a.Kind,
+		AnchorType: a.AnchorType,
+		Page:       a.Page,
+		StartTime:  a.StartTime,
+		EndTime:    a.EndTime,
+		Quote:      a.Quote,
+		Content:    a.Content,
+		Color:      a.Color,
+		CreatedAt:  timestamppb.New(a.CreatedAt),
+		UpdatedAt:  timestamppb.New(a.UpdatedAt),
+	}
+}
+
+// uploadSessionError 将上传会话的业务错误映射为 gRPC 状态码，gateway 据此返回 tus 协议约定的状态
+func uploadSessionError(err error) error {
+	code := codes.Internal
+	switch {
+	case errors.Is(err, service.ErrUploadSessionNotFound):
+		code = codes.NotFound
+	case errors.Is(err, service.ErrUploadSessionExpired):
+		code = codes.FailedPrecondition
+	case errors.Is(err, service.ErrUploadOffsetMismatch):
+		code = codes.Aborted
+	case errors.Is(err, service.ErrInvalidUploadSession):
+		code = codes.InvalidArgument
+	}
+	return status.Error(code, err.Error())
+}
+
+func convertToProtoUploadSession(session *models.UploadSession) *material.UploadSession {
+	info := &material.UploadSession{
+		Id:               session.ID.String(),
+		UserId:           session.UserID.String(),
+		Title:            session.Title,
+		OriginalFilename: session.OriginalFilename,
+		Size:             session.SizeBytes,
+		Offset:           session.ReceivedBytes,
+		Status:           session.Status,
+		ExpiresAt:        timestamppb.New(session.ExpiresAt),
+		CreatedAt:        timestamppb.New(session.CreatedAt),
+	}
+	if session.MaterialID != nil {
+		info.MaterialId = session.MaterialID.String()
+	}
+	return info
+}
+
+// parseUploadSessionIDs 解析请求中的会话 ID 与用户 ID
+func parseUploadSessionIDs(sessionID, userID string) (uuid.UUID, uuid.UUID, error) {
+	id, err := uuid.Parse(sessionID)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, status.Error(codes.InvalidArgument, "invalid session_id")
+	}
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, status.Error(codes.InvalidArgument, "invalid user_id")
+	}
+	return id, uid, nil
+}
+
+func (s *MaterialRPCServer) CreateUploadSession(ctx context.Context, req *material.CreateUploadSessionRequest) (*material.UploadSessionResponse, error) {
+	userID, err := uuid.Parse(req.UserId)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid user_id")
+	}
+	session, err := s.svc.CreateUploadSession(userID, req.Title, req.OriginalFilename, req.Size, req.OnDuplicate)
+	if err != nil {
+		return nil, uploadSessionError(err)
+	}
+	log.Printf("CreateUploadSession: ID=%s, UserID=%s, Filename=%s, Size=%d", session.ID, req.UserId, req.OriginalFilename, req.Size)
+	return &material.UploadSessionResponse{Session: convertToProtoUploadSession(session)}, nil
+}
+
+func (s *MaterialRPCServer) GetUploadSession(ctx context.Context, req *material.GetUploadSessionRequest) (*material.UploadSessionResponse, error) {
+	id, userID, err := parseUploadSessionIDs(req.SessionId, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+	session, err := s.svc.GetUploadSession(id, userID)
+	if err != nil {
+		return nil, uploadSessionError(err)
+	}
+	return &material.UploadSessionResponse{Session: convertToProtoUploadSession(session)}, nil
+}
+
+func (s *MaterialRPCServer) AppendUploadSession(stream material.MaterialService_AppendUploadSessionServer) error {
+	// 第一条消息必须是目标会话，之后的分块作为一个分段写入 MinIO
+	first, err := stream.Recv()
+	if err != nil && err != io.EOF {
+		return err
+	}
+	target := first.GetTarget()
+	if target == nil {
+		return status.Error(codes.InvalidArgument, "target is required as the first message")
+	}
+	id, userID, err := parseUploadSessionIDs(target.SessionId, target.UserId)
+	if err != nil {
+		return err
+	}
+
+	result, err := s.svc.AppendUploadSession(id, userID, target.Offset, &appendStreamReader{stream: stream})
+	if err != nil {
+		log.Printf("AppendUploadSession failed: ID=%s, Offset=%d: %v", target.SessionId, target.Offset, err)
+		return uploadSessionError(err)
+	}
+	resp := &material.UploadSessionResponse{Session: convertToProtoUploadSession(result.Session)}
+	if result.Upload != nil {
+		log.Printf("AppendUploadSession completed: ID=%s, MaterialID=%s, Linked=%v", target.SessionId, result.Upload.Material.ID, result.Upload.Linked)
+		resp.Completed = true
+		resp.Duplicates = convertToProtoDuplicates(result.Upload.Duplicates)
+		resp.Linked = result.Upload.Linked
+	}
+	return stream.SendAndClose(resp)
+}
+
+// appendStreamReader 将 AppendUploadSession 流中的分块适配为 io.Reader，客户端结束发送时返回 io.EOF
+type appendStreamReader struct {
+	stream material.MaterialService_AppendUploadSessionServer
+	buf    []byte
+}
+
+func (r *appendStreamReader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		req, err := r.stream.Recv()
+		if err != nil {
+			return 0, err
+		}
+		chunk, ok := req.Data.(*material.AppendUploadSessionRequest_ChunkData)
+		if !ok {
+			return 0, fmt.Errorf("unexpected target after file data")
+		}
+		r.buf = chunk.ChunkData
+	}
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
+
+func (s *MaterialRPCServer) DeleteUploadSession(ctx context.Context, req *material.DeleteUploadSessionRequest) (*material.DeleteUploadSessionResponse, error) {
+	id, userID, err := parseUploadSessionIDs(req.SessionId, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.svc.DeleteUploadSession(id, userID); err != nil {
+		return nil, uploadSessionError(err)
+	}
+	return &material.DeleteUploadSessionResponse{Success: true, Message: "upload session deleted"}, nil
+}
@@ -1,11 +1,16 @@
 package main
 
 import (
+	"context"
 	"log"
 	"net"
 
+	grpcDbx "github.com/RigelNana/arkstudy/pkg/dbx/grpc"
+	"github.com/RigelNana/arkstudy/pkg/discovery"
+	"github.com/RigelNana/arkstudy/pkg/interceptor"
 	"github.com/RigelNana/arkstudy/pkg/metrics"
 	grpcMetrics "github.com/RigelNana/arkstudy/pkg/metrics/grpc"
+	"github.com/RigelNana/arkstudy/pkg/registry"
 	"github.com/RigelNana/arkstudy/proto/material"
 	"github.com/RigelNana/arkstudy/services/material-service/config"
 	"github.com/RigelNana/arkstudy/services/material-service/database"
@@ -19,43 +24,71 @@ import (
 )
 
 func autoMigrate(db *gorm.DB) {
-	if err := db.AutoMigrate(&models.Material{}, &models.ProcessingResult{}); err != nil {
+	if err := db.AutoMigrate(&models.Material{}, &models.ProcessingResult{}, &models.DerivedArtifact{}, &models.Annotation{}, &models.UploadSession{}); err != nil {
 		log.Fatalf("auto migrate failed: %v", err)
 	}
 }
 
 func main() {
-	// 启动 Prometheus metrics 服务器
-	metrics.StartMetricsServer("2112")
-	log.Printf("Prometheus metrics server started on :2112")
+	// 启动内部 HTTP 监听：/metrics 与 /healthz、/readyz，地址由 METRICS_ADDR 配置
+	log.Printf("Prometheus metrics server started on %s", metrics.StartMetricsServer())
 
 	db := database.InitDB()
+	if sqlDB, err := db.DB(); err == nil {
+		metrics.AddReadinessCheck("database", sqlDB.PingContext)
+	}
 	autoMigrate(db)
 
 	repo := repository.NewMaterialRepository(db)
 	processingRepo := repository.NewProcessingResultRepository(db)
+	derivedRepo := repository.NewDerivedArtifactRepository(db)
+	annotationRepo := repository.NewAnnotationRepository(db)
+	uploadSessionRepo := repository.NewUploadSessionRepository(db)
 	config := config.LoadConfig()
+	if err := registry.Validate(registry.Material, registry.LLM, registry.OCR, registry.ASR); err != nil {
+		log.Fatalf("%v", err)
+	}
 
-	service, err := service.NewMaterialService(repo, processingRepo, config)
+	svc, err := service.NewMaterialService(repo, processingRepo, derivedRepo, annotationRepo, uploadSessionRepo, config)
 	if err != nil {
 		log.Fatalf("failed to create material service: %v", err)
 	}
+
+	// 定时清理孤立对象与卡死的记录，多副本通过 advisory lock 选出执行者
+	if config.Janitor.Enabled {
+		janitor, err := service.NewJanitor(svc, repo, processingRepo, uploadSessionRepo, db, config)
+		if err != nil {
+			log.Printf("Warning: janitor disabled: %v", err)
+		} else {
+			go janitor.Run(context.Background())
+		}
+	}
+
+	// 大任务延后到处理时段（PROCESSING_HEAVY_WINDOWS）派发
+	scheduler, err := service.NewScheduler(svc, processingRepo, config)
+	if err != nil {
+		log.Fatalf("failed to create processing scheduler: %v", err)
+	}
+	go scheduler.Run(context.Background())
+
+	// panic 恢复、错误码规范化与慢请求日志（GRPC_SLOW_THRESHOLD）
+	interceptorOpts := interceptor.DefaultOptions("material-service")
 	grpcServer := grpc.NewServer(
-		grpc.UnaryInterceptor(grpcMetrics.UnaryServerInterceptor("material-service")),
-		grpc.StreamInterceptor(grpcMetrics.StreamServerInterceptor("material-service")),
+		grpc.ChainUnaryInterceptor(grpcMetrics.UnaryServerInterceptor("material-service"), interceptor.UnaryServerInterceptor(interceptorOpts), grpcDbx.UnaryServerInterceptor()),
+		grpc.ChainStreamInterceptor(grpcMetrics.StreamServerInterceptor("material-service"), interceptor.StreamServerInterceptor(interceptorOpts)),
 	)
-	material.RegisterMaterialServiceServer(grpcServer, rpc.NewMaterialRPCServer(service))
+	material.RegisterMaterialServiceServer(grpcServer, rpc.NewMaterialRPCServer(svc))
+	// 健康检查供客户端在多个副本间故障转移（见 pkg/discovery）
+	discovery.RegisterHealth(grpcServer)
 	// Enable server reflection
 	reflection.Register(grpcServer)
-	port := config.Database.MaterialGRPCAddr
-	if port == "" {
-		port = "50053"
-	}
+	port := registry.Material.ListenPort()
 	lis, err := net.Listen("tcp", ":"+port)
 	if err != nil {
 		log.Fatalf("listen error: %v", err)
 	}
 	log.Printf("Material gRPC server listening on %s", port)
+	metrics.SetReady(true)
 	if err := grpcServer.Serve(lis); err != nil {
 		log.Fatalf("serve error: %v", err)
 	}
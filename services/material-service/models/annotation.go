@@ -0,0 +1,36 @@
+package models
+
+import (
+	"github.com/google/uuid"
+)
+
+// Annotation 用户对资料的批注：评论或高亮，锚定到文档页码或音视频时间段，未锚定时针对整份资料
+type Annotation struct {
+	Base
+	MaterialID uuid.UUID `gorm:"type:uuid;not null;index:idx_annotation_material_user" json:"material_id"`
+	UserID     uuid.UUID `gorm:"type:uuid;not null;index:idx_annotation_material_user" json:"user_id"`
+	Kind       string    `gorm:"type:varchar(20);not null" json:"kind"`
+	AnchorType string    `gorm:"type:varchar(20)" json:"anchor_type"`
+	Page       int32     `json:"page"`
+	StartTime  float64   `json:"start_time"`
+	EndTime    float64   `json:"end_time"`
+	Quote      string    `gorm:"type:text" json:"quote"`
+	Content    string    `gorm:"type:text" json:"content"`
+	Color      string    `gorm:"type:varchar(20)" json:"color"`
+}
+
+func (Annotation) TableName() string {
+	return "annotations"
+}
+
+// 批注类型常量
+const (
+	AnnotationKindComment   = "comment"
+	AnnotationKindHighlight = "highlight"
+)
+
+// 批注锚点类型常量
+const (
+	AnnotationAnchorPage = "page"
+	AnnotationAnchorTime = "time"
+)
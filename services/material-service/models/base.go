@@ -3,6 +3,8 @@ package models
 import (
 	"time"
 
+	"github.com/RigelNana/arkstudy/pkg/dbx"
+
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
@@ -12,6 +14,8 @@ type Base struct {
 	CreatedAt time.Time
 	UpdatedAt time.Time
 	DeletedAt gorm.DeletedAt `gorm:"index"`
+	dbx.Audit
+	LockVersion dbx.Version `gorm:"not null;default:1"`
 }
 
 func (base *Base) BeforeCreate(tx *gorm.DB) (err error) {
@@ -0,0 +1,40 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DerivedArtifact 记录资料某类派生内容（向量分片、摘要、题目）相对于最新处理结果的新鲜度。
+// 资料被重新 OCR/ASR 后，已有派生内容标记为 stale，待 RegenerateDerived 刷新
+type DerivedArtifact struct {
+	Base
+	MaterialID    uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex:idx_derived_material_kind" json:"material_id"`
+	Kind          string     `gorm:"type:varchar(50);not null;uniqueIndex:idx_derived_material_kind" json:"kind"`
+	Status        string     `gorm:"type:varchar(50);not null;index;default:'fresh'" json:"status"`
+	SourceTaskID  string     `gorm:"type:varchar(255)" json:"source_task_id"` // 派生内容所依据的处理任务
+	StaleReason   string     `gorm:"type:text" json:"stale_reason"`
+	StaleSince    *time.Time `json:"stale_since,omitempty"`
+	RegeneratedAt *time.Time `json:"regenerated_at,omitempty"`
+	ErrorMessage  string     `gorm:"type:text" json:"error_message"`
+}
+
+func (DerivedArtifact) TableName() string {
+	return "derived_artifacts"
+}
+
+// 派生内容类型常量
+const (
+	DerivedKindChunks  = "chunks"  // llm-service 中的向量分片，由 material-service 直接重建
+	DerivedKindSummary = "summary" // 音视频章节摘要，由 asr-service 重建
+	DerivedKindQuiz    = "quiz"    // 基于资料生成的题目，由 quiz-service 重建
+)
+
+// 派生内容状态常量
+const (
+	DerivedStatusFresh        = "fresh"
+	DerivedStatusStale        = "stale"
+	DerivedStatusRegenerating = "regenerating"
+	DerivedStatusFailed       = "failed"
+)
@@ -1,6 +1,9 @@
 package models
 
 import (
+	"encoding/json"
+	"time"
+
 	"github.com/google/uuid"
 	"gorm.io/datatypes"
 )
@@ -16,4 +19,58 @@ type Material struct {
 	MinioBucket      string         `gorm:"not null"`
 	MinioObjectName  string         `gorm:"not null"`
 	Metadata         datatypes.JSON `gorm:"type:jsonb"`
+
+	// 由压缩包展开的子资料指向所属的 bundle 资料
+	ParentID *uuid.UUID `gorm:"type:uuid;index"`
+
+	// 提取文本的主要语言（ISO 639-1，如 zh、en），上传或识别完成后检测；尚未提取文本或无法判断时为空
+	Language string `gorm:"type:varchar(16)"`
+
+	// 文件内容的 SHA-256（十六进制），上传时计算，用于发现重复上传
+	ContentHash string `gorm:"type:varchar(64);index"`
+	// 提取文本的 64 位 SimHash，用于发现内容相近的资料（如重新导出的课件）；尚未提取文本时为空
+	SimHash *int64
+
+	// 音视频元数据，上传后由 ffprobe 探测；非音视频或尚未探测时为零值
+	Media MediaInfo `gorm:"embedded;embeddedPrefix:media_"`
+	// PDF 的页数、标题、作者与目录，上传后由 pdfcpu 读取；非 PDF 或尚未读取时为零值
+	Document DocumentInfo `gorm:"embedded;embeddedPrefix:doc_"`
+}
+
+// MediaInfo 音视频的时长、分辨率与编码，纯音频的 Width / Height 与 VideoCodec 为空
+type MediaInfo struct {
+	DurationSeconds float64
+	Width           int
+	Height          int
+	VideoCodec      string
+	AudioCodec      string
+	// 容器格式，如 mov,mp4,m4a,3gp,3g2,mj2
+	FormatName string
+	ProbedAt   *time.Time
+}
+
+// DocumentInfo PDF 文档信息，Title / Author 取自文档信息字典，可能为空
+type DocumentInfo struct {
+	PageCount int
+	Title     string
+	Author    string
+	// 书签目录，[]TOCEntry 的 JSON
+	TOC      datatypes.JSON `gorm:"type:jsonb"`
+	ProbedAt *time.Time
+}
+
+// TOCEntry 目录条目，Level 从 1 开始，Page 为书签指向的页码（从 1 开始，未指向页面时为 0）
+type TOCEntry struct {
+	Title string `json:"title"`
+	Page  int    `json:"page"`
+	Level int    `json:"level"`
+}
+
+// GetTOC 解析目录
+func (d DocumentInfo) GetTOC() []TOCEntry {
+	var toc []TOCEntry
+	if len(d.TOC) > 0 {
+		_ = json.Unmarshal(d.TOC, &toc)
+	}
+	return toc
 }
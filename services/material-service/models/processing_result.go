@@ -1,6 +1,8 @@
 package models
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 	"gorm.io/datatypes"
 )
@@ -14,6 +16,13 @@ type ProcessingResult struct {
 	Content      string         `gorm:"type:text" json:"content"`
 	Metadata     datatypes.JSON `gorm:"type:jsonb" json:"metadata"`
 	ErrorMessage string         `gorm:"type:text" json:"error_message"`
+	Options      datatypes.JSON `gorm:"type:jsonb" json:"options"`             // 派发时的处理选项，重试时复用
+	RetryCount   int            `gorm:"not null;default:0" json:"retry_count"` // 手动重试次数
+	Progress     float32        `gorm:"not null;default:0" json:"progress"`    // 处理进度 0~1
+	// Shadow 对比处理：结果只保存供与其他结果对比（如评估新的 OCR 引擎），不写入向量库，也不替换资料当前的处理结果
+	Shadow bool `gorm:"not null;default:false;index" json:"shadow"`
+	// 延后到处理时段的任务计划派发的时间，其余任务为空
+	ScheduledFor *time.Time `gorm:"index" json:"scheduled_for,omitempty"`
 
 	// 关联关系
 	Material Material `gorm:"foreignKey:MaterialID" json:"material,omitempty"`
@@ -36,4 +45,28 @@ const (
 	ProcessingStatusProcessing = "processing"
 	ProcessingStatusCompleted  = "completed"
 	ProcessingStatusFailed     = "failed"
+	// 用户同时处理的任务数已达上限，等待有任务结束后派发
+	ProcessingStatusQueued = "queued"
+	// 大任务延后到处理时段开始时转入排队，见 ScheduledFor
+	ProcessingStatusScheduled = "scheduled"
+)
+
+// 处理选项中的对比处理开关，值为 "true" 时创建 Shadow 结果
+const ProcessingOptionShadow = "shadow"
+
+// 处理选项中的调用方执行开关，值为 "true" 时只登记 ASR 任务、不派发，
+// 用于 asr-service 直接收到转写请求后登记任务，转写与结果由 asr-service 回写
+const ProcessingOptionCallerDriven = "caller_driven"
+
+// 处理阶段，各阶段的时间戳以 "<阶段>_at"（RFC3339）记录在 Metadata 中，按先后顺序为：
+// uploaded（资料上传）、requested（发起处理）、dispatched（派发给 OCR/ASR）、started / recognized（识别开始 / 得到文本）、
+// embedded（分片写入向量库）、finished（处理完成或失败）。未经过的阶段不记录
+const (
+	ProcessingStageUploaded   = "uploaded"
+	ProcessingStageRequested  = "requested"
+	ProcessingStageDispatched = "dispatched"
+	ProcessingStageStarted    = "started"
+	ProcessingStageRecognized = "recognized"
+	ProcessingStageEmbedded   = "embedded"
+	ProcessingStageFinished   = "finished"
 )
@@ -0,0 +1,34 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+)
+
+// UploadSession 可续传上传的会话：文件内容按追加顺序分段暂存在 MinIO，接收满 SizeBytes 后合并为资料
+type UploadSession struct {
+	Base
+	UserID           uuid.UUID      `gorm:"type:uuid;not null;index" json:"user_id"`
+	Title            string         `gorm:"type:varchar(255)" json:"title"`
+	OriginalFilename string         `gorm:"type:varchar(255)" json:"original_filename"`
+	OnDuplicate      string         `gorm:"type:varchar(20)" json:"on_duplicate"`
+	SizeBytes        int64          `gorm:"not null" json:"size_bytes"`
+	ReceivedBytes    int64          `gorm:"not null;default:0" json:"received_bytes"`
+	Parts            datatypes.JSON `gorm:"type:jsonb" json:"parts"` // 已接收分段的对象名，按追加顺序排列
+	Status           string         `gorm:"type:varchar(20);not null;index" json:"status"`
+	MaterialID       *uuid.UUID     `gorm:"type:uuid" json:"material_id,omitempty"`
+	ExpiresAt        time.Time      `gorm:"not null;index" json:"expires_at"`
+}
+
+func (UploadSession) TableName() string {
+	return "upload_sessions"
+}
+
+// 上传会话状态常量
+const (
+	UploadSessionActive     = "active"
+	UploadSessionCompleting = "completing" // 正在合并分段并创建资料
+	UploadSessionCompleted  = "completed"
+)
@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/RigelNana/arkstudy/services/material-service/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// AnnotationFilter 批注列表的过滤条件，零值表示不过滤
+type AnnotationFilter struct {
+	Kind string
+	Page int32
+	// StartTime / EndTime 只保留与该时间段重叠的时间批注，EndTime 为 0 表示到结尾
+	StartTime float64
+	EndTime   float64
+}
+
+type AnnotationRepository interface {
+	BaseRepository[models.Annotation]
+	// ListByUserID 列出用户在 materialIDs 上的批注，按资料与锚点位置排序
+	ListByUserID(userID uuid.UUID, materialIDs []uuid.UUID, filter AnnotationFilter) ([]*models.Annotation, error)
+	// WithContext 返回以 ctx 访问数据库的仓库，ctx 中的操作人写入 created_by/updated_by
+	WithContext(ctx context.Context) AnnotationRepository
+}
+
+type AnnotationRepositoryImpl struct {
+	*BaseRepositoryImpl[models.Annotation]
+}
+
+func NewAnnotationRepository(db *gorm.DB) AnnotationRepository {
+	return &AnnotationRepositoryImpl{
+		BaseRepositoryImpl: NewBaseRepository[models.Annotation](db),
+	}
+}
+
+func (r *AnnotationRepositoryImpl) WithContext(ctx context.Context) AnnotationRepository {
+	return NewAnnotationRepository(r.db.WithContext(ctx))
+}
+
+func (r *AnnotationRepositoryImpl) ListByUserID(userID uuid.UUID, materialIDs []uuid.UUID, filter AnnotationFilter) ([]*models.Annotation, error) {
+	query := r.db.Where("user_id = ? AND material_id IN ?", userID, materialIDs)
+	if filter.Kind != "" {
+		query = query.Where("kind = ?", filter.Kind)
+	}
+	if filter.Page > 0 {
+		query = query.Where("anchor_type = ? AND page = ?", models.AnnotationAnchorPage, filter.Page)
+	}
+	if filter.StartTime > 0 || filter.EndTime > 0 {
+		query = query.Where("anchor_type = ? AND end_time >= ?", models.AnnotationAnchorTime, filter.StartTime)
+		if filter.EndTime > 0 {
+			query = query.Where("start_time <= ?", filter.EndTime)
+		}
+	}
+
+	var annotations []*models.Annotation
+	err := query.Order("material_id, page, start_time, created_at").Find(&annotations).Error
+	if err != nil {
+		return nil, err
+	}
+	return annotations, nil
+}
@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"github.com/RigelNana/arkstudy/services/material-service/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type DerivedArtifactRepository interface {
+	BaseRepository[models.DerivedArtifact]
+	ListByMaterialID(materialID uuid.UUID) ([]*models.DerivedArtifact, error)
+	GetByMaterialIDAndKind(materialID uuid.UUID, kind string) (*models.DerivedArtifact, error)
+	// Upsert 按 (material_id, kind) 写入，已存在时只覆盖 columns 指定的列
+	Upsert(artifact *models.DerivedArtifact, columns ...string) error
+}
+
+type DerivedArtifactRepositoryImpl struct {
+	*BaseRepositoryImpl[models.DerivedArtifact]
+}
+
+func NewDerivedArtifactRepository(db *gorm.DB) DerivedArtifactRepository {
+	return &DerivedArtifactRepositoryImpl{
+		BaseRepositoryImpl: NewBaseRepository[models.DerivedArtifact](db),
+	}
+}
+
+func (r *DerivedArtifactRepositoryImpl) ListByMaterialID(materialID uuid.UUID) ([]*models.DerivedArtifact, error) {
+	var artifacts []*models.DerivedArtifact
+	err := r.db.Where("material_id = ?", materialID).Order("kind ASC").Find(&artifacts).Error
+	if err != nil {
+		return nil, err
+	}
+	return artifacts, nil
+}
+
+func (r *DerivedArtifactRepositoryImpl) GetByMaterialIDAndKind(materialID uuid.UUID, kind string) (*models.DerivedArtifact, error) {
+	var artifact models.DerivedArtifact
+	err := r.db.Where("material_id = ? AND kind = ?", materialID, kind).First(&artifact).Error
+	if err != nil {
+		return nil, err
+	}
+	return &artifact, nil
+}
+
+func (r *DerivedArtifactRepositoryImpl) Upsert(artifact *models.DerivedArtifact, columns ...string) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "material_id"}, {Name: "kind"}},
+		DoUpdates: clause.AssignmentColumns(append(columns, "updated_at")),
+	}).Create(artifact).Error
+}
@@ -1,6 +1,10 @@
 package repository
 
 import (
+	"context"
+	"time"
+
+	"github.com/RigelNana/arkstudy/pkg/dbx"
 	"github.com/RigelNana/arkstudy/services/material-service/models"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
@@ -15,6 +19,17 @@ type MaterialRepository interface {
 	CountByUserID(userID uuid.UUID) (int64, error)
 	CountByStatus(status string) (int64, error)
 	UpdateStatus(id uuid.UUID, status string) error
+	GetStaleByStatus(status string, before time.Time, limit int) ([]*models.Material, error)
+	ExistingObjectNames(bucket string, names []string) (map[string]bool, error)
+	GetByParentID(parentID uuid.UUID) ([]*models.Material, error)
+	UpdateMedia(id uuid.UUID, media models.MediaInfo) error
+	UpdateDocument(id uuid.UUID, doc models.DocumentInfo) error
+	UpdateLanguage(id uuid.UUID, language string) error
+	UpdateSimHash(id uuid.UUID, simHash int64) error
+	GetByContentHash(userID uuid.UUID, contentHash string, excludeID uuid.UUID) ([]*models.Material, error)
+	GetWithSimHash(userID uuid.UUID, excludeID uuid.UUID) ([]*models.Material, error)
+	// WithContext 返回以 ctx 访问数据库的仓库，ctx 中的操作人（见 dbx.WithActor）写入 created_by/updated_by
+	WithContext(ctx context.Context) MaterialRepository
 }
 
 type MaterialRepositoryImpl struct {
@@ -27,6 +42,10 @@ func NewMaterialRepository(db *gorm.DB) MaterialRepository {
 	}
 }
 
+func (r *MaterialRepositoryImpl) WithContext(ctx context.Context) MaterialRepository {
+	return NewMaterialRepository(r.db.WithContext(ctx))
+}
+
 func (r *MaterialRepositoryImpl) GetByUserID(userID uuid.UUID, limit, offset int) ([]*models.Material, error) {
 	var materials []*models.Material
 	err := r.db.Where("user_id = ?", userID).Limit(limit).Offset(offset).Find(&materials).Error
@@ -43,14 +62,17 @@ func (r *MaterialRepositoryImpl) GetByUserIDWithPagination(userID uuid.UUID, pag
 	// 计算 offset
 	offset := (page - 1) * pageSize
 
+	// 资料列表可容忍复制延迟，配置了只读副本时从副本读取
+	db := r.db.Scopes(dbx.ReadReplica)
+
 	// 获取总数
-	err := r.db.Model(&models.Material{}).Where("user_id = ?", userID).Count(&total).Error
+	err := db.Model(&models.Material{}).Where("user_id = ?", userID).Count(&total).Error
 	if err != nil {
 		return nil, 0, err
 	}
 
 	// 获取分页数据
-	err = r.db.Where("user_id = ?", userID).
+	err = db.Where("user_id = ?", userID).
 		Limit(int(pageSize)).
 		Offset(int(offset)).
 		Order("created_at DESC").
@@ -95,3 +117,102 @@ func (r *MaterialRepositoryImpl) CountByStatus(status string) (int64, error) {
 func (r *MaterialRepositoryImpl) UpdateStatus(id uuid.UUID, status string) error {
 	return r.db.Model(&models.Material{}).Where("id = ?", id).Update("status", status).Error
 }
+
+// UpdateMedia 写入探测到的音视频元数据，零值字段同样覆盖
+func (r *MaterialRepositoryImpl) UpdateMedia(id uuid.UUID, media models.MediaInfo) error {
+	return r.db.Model(&models.Material{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"media_duration_seconds": media.DurationSeconds,
+		"media_width":            media.Width,
+		"media_height":           media.Height,
+		"media_video_codec":      media.VideoCodec,
+		"media_audio_codec":      media.AudioCodec,
+		"media_format_name":      media.FormatName,
+		"media_probed_at":        media.ProbedAt,
+	}).Error
+}
+
+// UpdateDocument 写入读取到的 PDF 文档信息，零值字段同样覆盖
+func (r *MaterialRepositoryImpl) UpdateDocument(id uuid.UUID, doc models.DocumentInfo) error {
+	return r.db.Model(&models.Material{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"doc_page_count": doc.PageCount,
+		"doc_title":      doc.Title,
+		"doc_author":     doc.Author,
+		"doc_toc":        doc.TOC,
+		"doc_probed_at":  doc.ProbedAt,
+	}).Error
+}
+
+// UpdateLanguage 写入检测到的资料语言
+func (r *MaterialRepositoryImpl) UpdateLanguage(id uuid.UUID, language string) error {
+	return r.db.Model(&models.Material{}).Where("id = ?", id).Update("language", language).Error
+}
+
+// UpdateSimHash 写入提取文本的 SimHash
+func (r *MaterialRepositoryImpl) UpdateSimHash(id uuid.UUID, simHash int64) error {
+	return r.db.Model(&models.Material{}).Where("id = ?", id).Update("sim_hash", simHash).Error
+}
+
+// GetByContentHash 查询用户已上传成功、内容哈希相同的资料，排除 excludeID，按上传时间先后排列
+func (r *MaterialRepositoryImpl) GetByContentHash(userID uuid.UUID, contentHash string, excludeID uuid.UUID) ([]*models.Material, error) {
+	var materials []*models.Material
+	err := r.db.Where("user_id = ? AND content_hash = ? AND id <> ? AND status NOT IN ?", userID, contentHash, excludeID, []string{"uploading", "failed"}).
+		Order("created_at ASC").
+		Find(&materials).Error
+	if err != nil {
+		return nil, err
+	}
+	return materials, nil
+}
+
+// GetWithSimHash 查询用户已计算 SimHash 的资料，排除 excludeID；只读取比对与展示所需的列
+func (r *MaterialRepositoryImpl) GetWithSimHash(userID uuid.UUID, excludeID uuid.UUID) ([]*models.Material, error) {
+	var materials []*models.Material
+	err := r.db.Select("id", "user_id", "title", "original_filename", "file_type", "size_bytes", "status", "created_at", "parent_id", "language", "content_hash", "sim_hash").
+		Where("user_id = ? AND sim_hash IS NOT NULL AND id <> ? AND status NOT IN ?", userID, excludeID, []string{"uploading", "failed"}).
+		Order("created_at ASC").
+		Find(&materials).Error
+	if err != nil {
+		return nil, err
+	}
+	return materials, nil
+}
+
+// GetStaleByStatus 查询创建时间早于 before 且仍处于指定状态的资料
+func (r *MaterialRepositoryImpl) GetStaleByStatus(status string, before time.Time, limit int) ([]*models.Material, error) {
+	var materials []*models.Material
+	err := r.db.Where("status = ? AND created_at < ?", status, before).Limit(limit).Find(&materials).Error
+	if err != nil {
+		return nil, err
+	}
+	return materials, nil
+}
+
+// ExistingObjectNames 返回 names 中仍有资料记录引用的对象名。
+// 已软删除的资料同样计入：其对象可能在回收站中，由生命周期规则到期删除
+func (r *MaterialRepositoryImpl) ExistingObjectNames(bucket string, names []string) (map[string]bool, error) {
+	existing := make(map[string]bool, len(names))
+	if len(names) == 0 {
+		return existing, nil
+	}
+	var found []string
+	err := r.db.Unscoped().Model(&models.Material{}).
+		Where("minio_bucket = ? AND minio_object_name IN ?", bucket, names).
+		Pluck("minio_object_name", &found).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range found {
+		existing[name] = true
+	}
+	return existing, nil
+}
+
+// GetByParentID 查询压缩包展开出的子资料，按创建顺序返回
+func (r *MaterialRepositoryImpl) GetByParentID(parentID uuid.UUID) ([]*models.Material, error) {
+	var materials []*models.Material
+	err := r.db.Where("parent_id = ?", parentID).Order("created_at ASC").Find(&materials).Error
+	if err != nil {
+		return nil, err
+	}
+	return materials, nil
+}
@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"time"
+
 	"github.com/RigelNana/arkstudy/services/material-service/models"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
@@ -16,6 +18,16 @@ type ProcessingResultRepository interface {
 	UpdateByTaskID(taskID string, updates map[string]interface{}) error
 	CountByMaterialID(materialID uuid.UUID) (int64, error)
 	CountByStatus(status string) (int64, error)
+	GetStaleByStatus(status string, before time.Time, limit int) ([]*models.ProcessingResult, error)
+	GetLatestCompletedByMaterialID(materialID uuid.UUID, processTypes []string) (*models.ProcessingResult, error)
+	CountCompletedByMaterialIDAndType(materialID uuid.UUID, processType, excludeTaskID string) (int64, error)
+	ListCompletedByMaterialIDAndType(materialID uuid.UUID, processType string, limit int) ([]*models.ProcessingResult, error)
+	CountActiveByUserID(userID uuid.UUID, processTypes []string) (inFlight, queued int64, err error)
+	ListQueuedByUserID(userID uuid.UUID, limit int) ([]*models.ProcessingResult, error)
+	ListQueuedUserIDs(limit int) ([]uuid.UUID, error)
+	ClaimQueued(taskID string) (bool, error)
+	ListDueScheduled(before time.Time, limit int) ([]*models.ProcessingResult, error)
+	ClaimScheduled(taskID string) (bool, error)
 }
 
 type ProcessingResultRepositoryImpl struct {
@@ -52,7 +64,7 @@ func (r *ProcessingResultRepositoryImpl) GetByMaterialID(materialID uuid.UUID, l
 
 func (r *ProcessingResultRepositoryImpl) GetByMaterialIDAndType(materialID uuid.UUID, processType string) (*models.ProcessingResult, error) {
 	var result models.ProcessingResult
-	err := r.db.Where("material_id = ? AND type = ?", materialID, processType).
+	err := r.db.Where("material_id = ? AND type = ? AND shadow = ?", materialID, processType, false).
 		Order("created_at DESC").
 		First(&result).Error
 	if err != nil {
@@ -115,3 +127,139 @@ func (r *ProcessingResultRepositoryImpl) CountByStatus(status string) (int64, er
 	err := r.db.Model(&models.ProcessingResult{}).Where("status = ?", status).Count(&count).Error
 	return count, err
 }
+
+// GetStaleByStatus 查询最后更新时间早于 before 且仍处于指定状态的处理记录
+func (r *ProcessingResultRepositoryImpl) GetStaleByStatus(status string, before time.Time, limit int) ([]*models.ProcessingResult, error) {
+	var results []*models.ProcessingResult
+	err := r.db.Where("status = ? AND updated_at < ?", status, before).Limit(limit).Find(&results).Error
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// GetLatestCompletedByMaterialID 查询资料最近一次完成的指定类型处理记录（不含对比处理结果）
+func (r *ProcessingResultRepositoryImpl) GetLatestCompletedByMaterialID(materialID uuid.UUID, processTypes []string) (*models.ProcessingResult, error) {
+	var result models.ProcessingResult
+	err := r.db.Where("material_id = ? AND type IN ? AND status = ? AND shadow = ?", materialID, processTypes, models.ProcessingStatusCompleted, false).
+		Order("updated_at DESC").
+		First(&result).Error
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// CountCompletedByMaterialIDAndType 统计资料除 excludeTaskID 外已完成的指定类型处理记录数
+func (r *ProcessingResultRepositoryImpl) CountCompletedByMaterialIDAndType(materialID uuid.UUID, processType, excludeTaskID string) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.ProcessingResult{}).
+		Where("material_id = ? AND type = ? AND status = ? AND task_id <> ? AND shadow = ?", materialID, processType, models.ProcessingStatusCompleted, excludeTaskID, false).
+		Count(&count).Error
+	return count, err
+}
+
+// ListCompletedByMaterialIDAndType 查询资料已完成的指定类型处理记录（含对比处理结果），按创建时间倒序
+func (r *ProcessingResultRepositoryImpl) ListCompletedByMaterialIDAndType(materialID uuid.UUID, processType string, limit int) ([]*models.ProcessingResult, error) {
+	var results []*models.ProcessingResult
+	err := r.db.Where("material_id = ? AND type = ? AND status = ?", materialID, processType, models.ProcessingStatusCompleted).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&results).Error
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// CountActiveByUserID 统计用户指定类型的处理记录中，等待或正在处理（pending / processing）与排队（queued）的数量
+func (r *ProcessingResultRepositoryImpl) CountActiveByUserID(userID uuid.UUID, processTypes []string) (inFlight, queued int64, err error) {
+	var rows []struct {
+		Status string
+		Count  int64
+	}
+	err = r.db.Model(&models.ProcessingResult{}).
+		Select("processing_results.status, COUNT(*) AS count").
+		Joins("JOIN materials ON materials.id = processing_results.material_id AND materials.deleted_at IS NULL").
+		Where("materials.user_id = ? AND processing_results.type IN ? AND processing_results.status IN ?", userID, processTypes,
+			[]string{models.ProcessingStatusPending, models.ProcessingStatusProcessing, models.ProcessingStatusQueued}).
+		Group("processing_results.status").
+		Scan(&rows).Error
+	if err != nil {
+		return 0, 0, err
+	}
+	for _, row := range rows {
+		if row.Status == models.ProcessingStatusQueued {
+			queued += row.Count
+		} else {
+			inFlight += row.Count
+		}
+	}
+	return inFlight, queued, nil
+}
+
+// ListQueuedByUserID 查询用户排队中的处理记录，按创建时间正序（先提交先派发）
+func (r *ProcessingResultRepositoryImpl) ListQueuedByUserID(userID uuid.UUID, limit int) ([]*models.ProcessingResult, error) {
+	var results []*models.ProcessingResult
+	err := r.db.Preload("Material").
+		Joins("JOIN materials ON materials.id = processing_results.material_id AND materials.deleted_at IS NULL").
+		Where("materials.user_id = ? AND processing_results.status = ?", userID, models.ProcessingStatusQueued).
+		Order("processing_results.created_at ASC").
+		Limit(limit).
+		Find(&results).Error
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// ListQueuedUserIDs 查询有排队中处理记录的用户
+func (r *ProcessingResultRepositoryImpl) ListQueuedUserIDs(limit int) ([]uuid.UUID, error) {
+	var userIDs []uuid.UUID
+	err := r.db.Model(&models.ProcessingResult{}).
+		Distinct("materials.user_id").
+		Joins("JOIN materials ON materials.id = processing_results.material_id AND materials.deleted_at IS NULL").
+		Where("processing_results.status = ?", models.ProcessingStatusQueued).
+		Limit(limit).
+		Pluck("materials.user_id", &userIDs).Error
+	if err != nil {
+		return nil, err
+	}
+	return userIDs, nil
+}
+
+// ClaimQueued 将排队中的处理记录改为 pending，返回是否由本次调用取得；多个副本同时派发时只有一个能取得
+func (r *ProcessingResultRepositoryImpl) ClaimQueued(taskID string) (bool, error) {
+	res := r.db.Model(&models.ProcessingResult{}).
+		Where("task_id = ? AND status = ?", taskID, models.ProcessingStatusQueued).
+		Update("status", models.ProcessingStatusPending)
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected == 1, nil
+}
+
+// ListDueScheduled 查询计划派发时间不晚于 before 的延后处理记录，按计划时间正序
+func (r *ProcessingResultRepositoryImpl) ListDueScheduled(before time.Time, limit int) ([]*models.ProcessingResult, error) {
+	var results []*models.ProcessingResult
+	err := r.db.Preload("Material").
+		Where("status = ? AND scheduled_for <= ?", models.ProcessingStatusScheduled, before).
+		Order("scheduled_for ASC, created_at ASC").
+		Limit(limit).
+		Find(&results).Error
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// ClaimScheduled 将延后的处理记录转入排队，返回是否由本次调用取得，与 ClaimQueued 相同
+func (r *ProcessingResultRepositoryImpl) ClaimScheduled(taskID string) (bool, error) {
+	res := r.db.Model(&models.ProcessingResult{}).
+		Where("task_id = ? AND status = ?", taskID, models.ProcessingStatusScheduled).
+		Update("status", models.ProcessingStatusQueued)
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected == 1, nil
+}
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/RigelNana/arkstudy/services/material-service/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type UploadSessionRepository interface {
+	BaseRepository[models.UploadSession]
+	// GetExpired 查询过期时间早于 before 的会话
+	GetExpired(before time.Time, limit int) ([]*models.UploadSession, error)
+	// ExistingIDs 返回 ids 中仍存在（未删除）的会话
+	ExistingIDs(ids []uuid.UUID) (map[uuid.UUID]bool, error)
+	// WithContext 返回以 ctx 访问数据库的仓库，ctx 中的操作人写入 created_by/updated_by
+	WithContext(ctx context.Context) UploadSessionRepository
+}
+
+type UploadSessionRepositoryImpl struct {
+	*BaseRepositoryImpl[models.UploadSession]
+}
+
+func NewUploadSessionRepository(db *gorm.DB) UploadSessionRepository {
+	return &UploadSessionRepositoryImpl{
+		BaseRepositoryImpl: NewBaseRepository[models.UploadSession](db),
+	}
+}
+
+func (r *UploadSessionRepositoryImpl) WithContext(ctx context.Context) UploadSessionRepository {
+	return NewUploadSessionRepository(r.db.WithContext(ctx))
+}
+
+func (r *UploadSessionRepositoryImpl) GetExpired(before time.Time, limit int) ([]*models.UploadSession, error) {
+	var sessions []*models.UploadSession
+	err := r.db.Where("expires_at < ?", before).Limit(limit).Find(&sessions).Error
+	if err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+func (r *UploadSessionRepositoryImpl) ExistingIDs(ids []uuid.UUID) (map[uuid.UUID]bool, error) {
+	existing := make(map[uuid.UUID]bool, len(ids))
+	if len(ids) == 0 {
+		return existing, nil
+	}
+	var found []uuid.UUID
+	err := r.db.Model(&models.UploadSession{}).Where("id IN ?", ids).Pluck("id", &found).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range found {
+		existing[id] = true
+	}
+	return existing, nil
+}
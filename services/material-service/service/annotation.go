@@ -0,0 +1,163 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/RigelNana/arkstudy/pkg/dbx"
+	"github.com/RigelNana/arkstudy/services/material-service/models"
+	"github.com/RigelNana/arkstudy/services/material-service/repository"
+	"github.com/google/uuid"
+)
+
+const (
+	// 评论与引用原文的长度上限（按字符）
+	maxAnnotationContentRunes = 5000
+	maxAnnotationQuoteRunes   = 2000
+	// 一次列出批注的资料数上限，避免检索结果附带批注时查询过大
+	maxAnnotationMaterials = 100
+)
+
+// AnnotationInput 创建批注的参数
+type AnnotationInput struct {
+	MaterialID uuid.UUID
+	Kind       string
+	AnchorType string
+	Page       int32
+	StartTime  float64
+	EndTime    float64
+	Quote      string
+	Content    string
+	Color      string
+}
+
+// validateAnnotation 校验批注类型与锚点：页码锚点用于文档，时间锚点用于音视频，评论必须有内容，高亮必须有锚点
+func validateAnnotation(in *AnnotationInput, material *models.Material) error {
+	switch in.Kind {
+	case models.AnnotationKindComment:
+		if strings.TrimSpace(in.Content) == "" {
+			return fmt.Errorf("invalid annotation: comment content is required")
+		}
+	case models.AnnotationKindHighlight:
+		if in.AnchorType == "" {
+			return fmt.Errorf("invalid annotation: highlight requires a page or time anchor")
+		}
+	default:
+		return fmt.Errorf("invalid annotation: kind must be comment or highlight")
+	}
+
+	switch in.AnchorType {
+	case "":
+		in.Page, in.StartTime, in.EndTime = 0, 0, 0
+	case models.AnnotationAnchorPage:
+		if in.Page < 1 {
+			return fmt.Errorf("invalid annotation: page must be at least 1")
+		}
+		in.StartTime, in.EndTime = 0, 0
+	case models.AnnotationAnchorTime:
+		if material.FileType != "video" && material.FileType != "audio" {
+			return fmt.Errorf("invalid annotation: time anchors are only supported on video and audio materials")
+		}
+		if in.StartTime < 0 || in.EndTime < in.StartTime {
+			return fmt.Errorf("invalid annotation: time range must satisfy 0 <= start_time <= end_time")
+		}
+		in.Page = 0
+	default:
+		return fmt.Errorf("invalid annotation: anchor_type must be page or time")
+	}
+
+	if utf8.RuneCountInString(in.Content) > maxAnnotationContentRunes {
+		return fmt.Errorf("invalid annotation: content exceeds %d characters", maxAnnotationContentRunes)
+	}
+	if utf8.RuneCountInString(in.Quote) > maxAnnotationQuoteRunes {
+		return fmt.Errorf("invalid annotation: quote exceeds %d characters", maxAnnotationQuoteRunes)
+	}
+	return nil
+}
+
+func (s *MaterialServiceImpl) CreateAnnotation(userID uuid.UUID, in AnnotationInput) (*models.Annotation, error) {
+	material, err := s.repo.GetByID(in.MaterialID)
+	if err != nil {
+		return nil, fmt.Errorf("material not found: %w", err)
+	}
+	if material.UserID != userID {
+		return nil, fmt.Errorf("permission denied: material does not belong to user")
+	}
+	if err := validateAnnotation(&in, material); err != nil {
+		return nil, err
+	}
+
+	annotation := &models.Annotation{
+		MaterialID: in.MaterialID,
+		UserID:     userID,
+		Kind:       in.Kind,
+		AnchorType: in.AnchorType,
+		Page:       in.Page,
+		StartTime:  in.StartTime,
+		EndTime:    in.EndTime,
+		Quote:      in.Quote,
+		Content:    in.Content,
+		Color:      in.Color,
+	}
+	if err := s.annotationRepo.WithContext(dbx.WithActor(context.Background(), userID.String())).Create(annotation); err != nil {
+		return nil, fmt.Errorf("failed to create annotation: %w", err)
+	}
+	return annotation, nil
+}
+
+// getOwnAnnotation 获取属于 userID 的批注，不属于该用户时与不存在一样返回 not found
+func (s *MaterialServiceImpl) getOwnAnnotation(id, userID uuid.UUID) (*models.Annotation, error) {
+	annotation, err := s.annotationRepo.GetByID(id)
+	if err != nil || annotation.UserID != userID {
+		return nil, fmt.Errorf("annotation not found")
+	}
+	return annotation, nil
+}
+
+// UpdateAnnotation 修改评论内容或高亮颜色，为 nil 的字段保持不变
+func (s *MaterialServiceImpl) UpdateAnnotation(id, userID uuid.UUID, content, color *string) (*models.Annotation, error) {
+	annotation, err := s.getOwnAnnotation(id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if content != nil {
+		if annotation.Kind == models.AnnotationKindComment && strings.TrimSpace(*content) == "" {
+			return nil, fmt.Errorf("invalid annotation: comment content is required")
+		}
+		if utf8.RuneCountInString(*content) > maxAnnotationContentRunes {
+			return nil, fmt.Errorf("invalid annotation: content exceeds %d characters", maxAnnotationContentRunes)
+		}
+		annotation.Content = *content
+	}
+	if color != nil {
+		annotation.Color = *color
+	}
+	if err := s.annotationRepo.WithContext(dbx.WithActor(context.Background(), userID.String())).Update(annotation); err != nil {
+		return nil, fmt.Errorf("failed to update annotation: %w", err)
+	}
+	return annotation, nil
+}
+
+func (s *MaterialServiceImpl) DeleteAnnotation(id, userID uuid.UUID) error {
+	annotation, err := s.getOwnAnnotation(id, userID)
+	if err != nil {
+		return err
+	}
+	if err := s.annotationRepo.Delete(annotation.ID); err != nil {
+		return fmt.Errorf("failed to delete annotation: %w", err)
+	}
+	return nil
+}
+
+// ListAnnotations 列出用户在若干资料上的批注；只查询 user_id 自己的批注，因此无需逐个校验资料归属
+func (s *MaterialServiceImpl) ListAnnotations(userID uuid.UUID, materialIDs []uuid.UUID, filter repository.AnnotationFilter) ([]*models.Annotation, error) {
+	if len(materialIDs) == 0 {
+		return nil, fmt.Errorf("invalid annotation query: material_ids is required")
+	}
+	if len(materialIDs) > maxAnnotationMaterials {
+		return nil, fmt.Errorf("invalid annotation query: at most %d material_ids", maxAnnotationMaterials)
+	}
+	return s.annotationRepo.ListByUserID(userID, materialIDs, filter)
+}
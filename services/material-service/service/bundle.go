@@ -0,0 +1,181 @@
+package service
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"path"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/RigelNana/arkstudy/services/material-service/models"
+	"github.com/google/uuid"
+	"github.com/minio/minio-go/v7"
+	"golang.org/x/text/encoding/simplifiedchinese"
+	"gorm.io/datatypes"
+)
+
+// 压缩包资料类型，上传后展开为子资料，本身不做处理
+const FileTypeBundle = "bundle"
+
+// 压缩包正在展开时的资料状态，展开结束后为 success（无可用文件时为 failed）
+const MaterialStatusExpanding = "expanding"
+
+const (
+	// 单个压缩包最多展开的文件数
+	maxBundleEntries = 200
+	// 单个文件与全部文件解压后的大小上限，防止 zip 炸弹
+	maxBundleEntrySize = 512 << 20
+	maxBundleTotalSize = 2 << 30
+)
+
+// bundleEntryResult 记录压缩包中每个文件的展开结果，写入 bundle 资料的 Metadata
+type bundleEntryResult struct {
+	Name       string `json:"name"`
+	MaterialID string `json:"material_id,omitempty"`
+	FileType   string `json:"file_type,omitempty"`
+	Skipped    string `json:"skipped,omitempty"` // 跳过原因
+}
+
+// expandBundle 读取 MinIO 中的压缩包，为其中每个支持的文件创建子资料并按类型触发处理。
+// 不支持的类型、隐藏文件与嵌套压缩包会被跳过，展开结果记录在 bundle 的 Metadata 中
+func (s *MaterialServiceImpl) expandBundle(bundle *models.Material) {
+	entries, err := s.expandBundleEntries(bundle)
+	status := "success"
+	metadata := map[string]interface{}{"entries": entries}
+	if err != nil {
+		log.Printf("Expand bundle %s failed: %v", bundle.ID, err)
+		status = "failed"
+		metadata["error"] = err.Error()
+	}
+
+	created := 0
+	for _, e := range entries {
+		if e.MaterialID != "" {
+			created++
+		}
+	}
+	metadata["children"] = created
+	if created == 0 && err == nil {
+		status = "failed"
+		metadata["error"] = "bundle contains no supported files"
+	}
+
+	if raw, mErr := json.Marshal(metadata); mErr == nil {
+		bundle.Metadata = datatypes.JSON(raw)
+	}
+	bundle.Status = status
+	if err := s.repo.Update(bundle); err != nil {
+		log.Printf("Warning: failed to save bundle %s expansion result: %v", bundle.ID, err)
+		return
+	}
+	s.publishMaterialEvent(EventMaterialUpdated, bundle, map[string]interface{}{"status": status, "children": created})
+	log.Printf("Bundle %s expanded: %d child materials, %d entries", bundle.ID, created, len(entries))
+}
+
+func (s *MaterialServiceImpl) expandBundleEntries(bundle *models.Material) ([]bundleEntryResult, error) {
+	ctx := context.Background()
+	obj, err := s.minioClient.GetObject(ctx, bundle.MinioBucket, bundle.MinioObjectName, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bundle from minio: %w", err)
+	}
+	defer obj.Close()
+	info, err := obj.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat bundle: %w", err)
+	}
+	// minio.Object 支持随机读取，无需将整个压缩包读入内存
+	zr, err := zip.NewReader(obj, info.Size)
+	if err != nil {
+		return nil, fmt.Errorf("invalid zip archive: %w", err)
+	}
+
+	var results []bundleEntryResult
+	var stored int
+	var total uint64
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		name := entryName(f)
+		base := path.Base(name)
+		// 跳过 macOS 资源分支与隐藏文件
+		if strings.HasPrefix(name, "__MACOSX/") || strings.HasPrefix(base, ".") {
+			continue
+		}
+
+		result := bundleEntryResult{Name: name}
+		fileType := s.detectFileType(base)
+		switch {
+		case fileType == "other":
+			result.Skipped = "unsupported file type"
+		case fileType == FileTypeBundle:
+			result.Skipped = "nested archives are not expanded"
+		case f.UncompressedSize64 > maxBundleEntrySize:
+			result.Skipped = fmt.Sprintf("file exceeds %d bytes", maxBundleEntrySize)
+		case stored >= maxBundleEntries:
+			result.Skipped = fmt.Sprintf("bundle exceeds %d files", maxBundleEntries)
+		case total+f.UncompressedSize64 > maxBundleTotalSize:
+			result.Skipped = fmt.Sprintf("bundle exceeds %d bytes uncompressed", maxBundleTotalSize)
+		}
+		if result.Skipped != "" {
+			results = append(results, result)
+			continue
+		}
+		total += f.UncompressedSize64
+		stored++
+
+		child, err := s.storeBundleEntry(bundle, f, base)
+		if err != nil {
+			log.Printf("Warning: failed to store bundle entry %s of %s: %v", name, bundle.ID, err)
+			result.Skipped = err.Error()
+			results = append(results, result)
+			continue
+		}
+		result.MaterialID = child.ID.String()
+		result.FileType = child.FileType
+		results = append(results, result)
+
+		s.runUploadSteps(child)
+	}
+	return results, nil
+}
+
+// storeBundleEntry 将压缩包中的单个文件写入为子资料，标题取文件名（不含扩展名）
+func (s *MaterialServiceImpl) storeBundleEntry(bundle *models.Material, f *zip.File, base string) (*models.Material, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, fmt.Errorf("open entry: %w", err)
+	}
+	defer rc.Close()
+	title := strings.TrimSuffix(base, path.Ext(base))
+	// 解压时 archive/zip 会校验实际长度与 CRC，声明的大小不可信时读取报错
+	return s.storeFile(bundle.UserID, title, base, rc, int64(f.UncompressedSize64), storeOptions{ParentID: &bundle.ID})
+}
+
+// entryName 返回压缩包内的文件路径。Windows 下创建的压缩包常以 GBK 编码文件名且不设 UTF-8 标志，
+// 此时按 GB18030 解码
+func entryName(f *zip.File) string {
+	if !f.NonUTF8 || utf8.ValidString(f.Name) {
+		return f.Name
+	}
+	decoded, err := simplifiedchinese.GB18030.NewDecoder().String(f.Name)
+	if err != nil {
+		return f.Name
+	}
+	return decoded
+}
+
+// ListChildren 列出压缩包展开出的子资料
+func (s *MaterialServiceImpl) ListChildren(parentID uuid.UUID, userID uuid.UUID) ([]*models.Material, error) {
+	parent, err := s.repo.GetByID(parentID)
+	if err != nil {
+		return nil, fmt.Errorf("material not found: %w", err)
+	}
+	if parent.UserID != userID {
+		return nil, fmt.Errorf("permission denied: material does not belong to user")
+	}
+	return s.repo.GetByParentID(parentID)
+}
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/RigelNana/arkstudy/services/material-service/extract"
+	"github.com/RigelNana/arkstudy/services/material-service/models"
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+)
+
+// 网页摘录资料类型：由浏览器扩展提交的选中文本，按纯文本进入 text.extracted 流水线
+const FileTypeClip = "clip"
+
+// 摘录内容类型
+const (
+	ClipContentText = "text"
+	ClipContentHTML = "html"
+)
+
+const (
+	// 单条摘录提交内容的大小上限
+	maxClipBytes = 1 << 20
+	// 未提供标题时取正文开头作为标题的长度（字符）
+	clipTitleRunes = 60
+)
+
+// ClipInput 网页摘录，Content 为选中的文本或 HTML 片段
+type ClipInput struct {
+	Title       string
+	Content     string
+	ContentType string // text（默认）或 html
+	SourceURL   string
+	PageTitle   string
+}
+
+// CreateClip 将网页摘录保存为轻量资料：HTML 片段先提取正文，来源 URL 与页面标题记录在资料元数据中，
+// 之后与文本资料一样发起分片入库，可用于问答与出题
+func (s *MaterialServiceImpl) CreateClip(userID uuid.UUID, clip ClipInput) (*models.Material, error) {
+	if len(clip.Content) > maxClipBytes {
+		return nil, fmt.Errorf("clip content exceeds %d bytes", maxClipBytes)
+	}
+	if clip.SourceURL != "" {
+		u, err := url.Parse(clip.SourceURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("invalid source_url: must be an http(s) URL")
+		}
+	}
+
+	contentType := strings.ToLower(strings.TrimSpace(clip.ContentType))
+	text := clip.Content
+	switch contentType {
+	case "", ClipContentText:
+		contentType = ClipContentText
+	case ClipContentHTML:
+		extracted, err := extract.HTMLText(strings.NewReader(clip.Content))
+		if err != nil {
+			return nil, fmt.Errorf("failed to extract clip html: %w", err)
+		}
+		text = extracted
+	default:
+		return nil, fmt.Errorf("unsupported content_type: %s", clip.ContentType)
+	}
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil, fmt.Errorf("clip content is empty")
+	}
+
+	title := strings.TrimSpace(clip.Title)
+	if title == "" {
+		title = strings.TrimSpace(clip.PageTitle)
+	}
+	if title == "" {
+		title = clipTitleFromText(text)
+	}
+
+	metadata, err := json.Marshal(map[string]interface{}{
+		"source_url":   clip.SourceURL,
+		"page_title":   clip.PageTitle,
+		"content_type": contentType,
+		"captured_at":  time.Now().UTC().Format(time.RFC3339),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal clip metadata: %w", err)
+	}
+
+	material, err := s.storeFile(userID, title, "clip.txt", strings.NewReader(text), int64(len(text)), storeOptions{
+		FileType: FileTypeClip,
+		Metadata: datatypes.JSON(metadata),
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.afterUpload(material)
+	return material, nil
+}
+
+// clipTitleFromText 取正文第一行开头作为标题
+func clipTitleFromText(text string) string {
+	line := strings.TrimSpace(strings.SplitN(text, "\n", 2)[0])
+	line = strings.TrimLeft(line, "# ")
+	if utf8.RuneCountInString(line) > clipTitleRunes {
+		line = string([]rune(line)[:clipTitleRunes]) + "…"
+	}
+	if line == "" {
+		return "网页摘录"
+	}
+	return line
+}
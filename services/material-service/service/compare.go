@@ -0,0 +1,354 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/RigelNana/arkstudy/services/material-service/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	// 逐行比对的编辑步数上限，超出时只按行统计异同，不返回差异块
+	maxDiffEdits = 2000
+	// 对比结果最多返回的差异块数
+	maxDiffHunks = 200
+	// 差异块前后保留的未改动行数
+	defaultDiffContext = 3
+	maxDiffContext     = 20
+)
+
+// 差异行类型
+const (
+	DiffEqual  = "equal"
+	DiffInsert = "insert"
+	DiffDelete = "delete"
+)
+
+// DiffLine 差异中的一行
+type DiffLine struct {
+	Op   string
+	Text string
+}
+
+// DiffHunk 差异块，行号从 1 开始
+type DiffHunk struct {
+	BaseStart, BaseLines     int
+	TargetStart, TargetLines int
+	Lines                    []DiffLine
+}
+
+// ResultQuality 单次处理结果的质量指标，Engine、Confidence、Duration 取自处理记录的 metadata，未记录时为零值
+type ResultQuality struct {
+	Result     *models.ProcessingResult
+	Engine     string
+	Chars      int
+	Lines      int
+	Confidence float64
+	Duration   time.Duration
+}
+
+// ResultComparison 两次处理结果的对比
+type ResultComparison struct {
+	Base, Target                             ResultQuality
+	Similarity                               float64 // 按行计算：2 × 未改动行数 / 两侧总行数
+	LinesAdded, LinesRemoved, LinesUnchanged int
+	Hunks                                    []DiffHunk
+	Truncated                                bool
+}
+
+// CompareProcessingResults 对比资料的两次处理结果，用于用真实资料评估 OCR 引擎升级或重新处理的效果。
+// 任务 ID 为空时 base 取资料当前的处理结果，target 取除 base 外最近一次完成的同类型结果（含对比处理）
+func (s *MaterialServiceImpl) CompareProcessingResults(materialID, userID uuid.UUID, processType, baseTaskID, targetTaskID string, contextLines int) (*ResultComparison, error) {
+	material, err := s.repo.GetByID(materialID)
+	if err != nil {
+		return nil, fmt.Errorf("material not found: %w", err)
+	}
+	if material.UserID != userID {
+		return nil, fmt.Errorf("permission denied: material does not belong to user")
+	}
+
+	base, err := s.comparableResult(materialID, processType, baseTaskID, "")
+	if err != nil {
+		return nil, fmt.Errorf("base result: %w", err)
+	}
+	target, err := s.comparableResult(materialID, processType, targetTaskID, base.TaskID)
+	if err != nil {
+		return nil, fmt.Errorf("target result: %w", err)
+	}
+	if base.Type != target.Type {
+		return nil, fmt.Errorf("cannot compare %s result with %s result", base.Type, target.Type)
+	}
+
+	if contextLines <= 0 {
+		contextLines = defaultDiffContext
+	}
+	contextLines = min(contextLines, maxDiffContext)
+
+	a, b := splitResultLines(base.Content), splitResultLines(target.Content)
+	cmp := &ResultComparison{Base: resultQuality(base, len(a)), Target: resultQuality(target, len(b))}
+	ops, ok := diffLines(a, b, maxDiffEdits)
+	if !ok {
+		cmp.LinesUnchanged = commonLineCount(a, b)
+		cmp.Truncated = true
+	} else {
+		for _, op := range ops {
+			if op.Op == DiffEqual {
+				cmp.LinesUnchanged++
+			}
+		}
+		cmp.Hunks = groupHunks(ops, contextLines)
+		if len(cmp.Hunks) > maxDiffHunks {
+			cmp.Hunks = cmp.Hunks[:maxDiffHunks]
+			cmp.Truncated = true
+		}
+	}
+	cmp.LinesAdded, cmp.LinesRemoved = len(b)-cmp.LinesUnchanged, len(a)-cmp.LinesUnchanged
+	cmp.Similarity = 1
+	if total := len(a) + len(b); total > 0 {
+		cmp.Similarity = float64(2*cmp.LinesUnchanged) / float64(total)
+	}
+	return cmp, nil
+}
+
+// comparableResult 按任务 ID 取资料的已完成处理结果；任务 ID 为空时 exclude 为空取资料当前结果，
+// 否则取除 exclude 外最近一次完成的结果
+func (s *MaterialServiceImpl) comparableResult(materialID uuid.UUID, processType, taskID, exclude string) (*models.ProcessingResult, error) {
+	if taskID != "" {
+		result, err := s.processingRepo.GetByTaskID(taskID)
+		if err != nil {
+			return nil, fmt.Errorf("task %s not found: %w", taskID, err)
+		}
+		if result.MaterialID != materialID {
+			return nil, fmt.Errorf("task %s does not belong to material", taskID)
+		}
+		if result.Status != models.ProcessingStatusCompleted {
+			return nil, fmt.Errorf("task %s is %s, only completed results can be compared", taskID, result.Status)
+		}
+		return result, nil
+	}
+	if exclude == "" {
+		// 优先取当前结果（非对比处理），没有时取最近一次完成的对比处理结果
+		if current, err := s.processingRepo.GetLatestCompletedByMaterialID(materialID, []string{processType}); err == nil {
+			return current, nil
+		}
+	}
+	results, err := s.processingRepo.ListCompletedByMaterialIDAndType(materialID, processType, 2)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range results {
+		if r.TaskID != exclude {
+			return r, nil
+		}
+	}
+	return nil, fmt.Errorf("no completed %s result to compare: %w", processType, gorm.ErrRecordNotFound)
+}
+
+func resultQuality(result *models.ProcessingResult, lines int) ResultQuality {
+	q := ResultQuality{Result: result, Chars: utf8.RuneCountInString(result.Content), Lines: lines}
+	metadata := decodeMetadata(result.Metadata)
+	q.Engine, _ = metadata["engine"].(string)
+	if q.Engine == "" && len(result.Options) > 0 {
+		var options map[string]string
+		_ = json.Unmarshal(result.Options, &options)
+		q.Engine = options["engine"]
+	}
+	// 同步编排写入数字，ocr-service 回调写入字符串
+	switch v := metadata["confidence"].(type) {
+	case float64:
+		q.Confidence = v
+	case string:
+		q.Confidence, _ = strconv.ParseFloat(v, 64)
+	}
+	times := stageTimes(metadata)
+	if start, ok := times[models.ProcessingStageStarted]; ok {
+		if end, ok := times[models.ProcessingStageRecognized]; ok && end.After(start) {
+			q.Duration = end.Sub(start)
+		}
+	}
+	return q
+}
+
+// splitResultLines 将识别文本按行切分，忽略行尾空白，空文本没有行
+func splitResultLines(text string) []string {
+	text = strings.TrimRight(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	if text == "" {
+		return nil
+	}
+	lines := strings.Split(text, "\n")
+	for i, l := range lines {
+		lines[i] = strings.TrimRight(l, " \t\f")
+	}
+	return lines
+}
+
+// commonLineCount 不考虑顺序时两侧相同的行数，差异过大无法逐行比对时使用
+func commonLineCount(a, b []string) int {
+	counts := make(map[string]int, len(a))
+	for _, l := range a {
+		counts[l]++
+	}
+	n := 0
+	for _, l := range b {
+		if counts[l] > 0 {
+			counts[l]--
+			n++
+		}
+	}
+	return n
+}
+
+// diffLines 用 Myers 算法计算 a 到 b 的最短逐行编辑序列，编辑步数超过 maxEdits 时返回 false。
+// 先去掉公共的首尾行，识别结果通常只有局部不同
+func diffLines(a, b []string, maxEdits int) ([]DiffLine, bool) {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	ops := make([]DiffLine, 0, len(a)+len(b))
+	for _, l := range a[:prefix] {
+		ops = append(ops, DiffLine{Op: DiffEqual, Text: l})
+	}
+	middle, ok := myers(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix], maxEdits)
+	if !ok {
+		return nil, false
+	}
+	ops = append(ops, middle...)
+	for _, l := range a[len(a)-suffix:] {
+		ops = append(ops, DiffLine{Op: DiffEqual, Text: l})
+	}
+	return ops, true
+}
+
+func myers(a, b []string, maxEdits int) ([]DiffLine, bool) {
+	n, m := len(a), len(b)
+	maxD := min(n+m, maxEdits)
+	offset := maxD + 1
+	// v[k+offset] 为对角线 k 上能到达的最远 x
+	v := make([]int32, 2*maxD+3)
+	// trace[d] 为第 d 步开始前 v 在 [-d, d] 上的快照，回溯时使用
+	var trace [][]int32
+	for d := 0; d <= maxD; d++ {
+		snap := make([]int32, 2*d+1)
+		copy(snap, v[offset-d:offset+d+1])
+		trace = append(trace, snap)
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = int(v[offset+k+1])
+			} else {
+				x = int(v[offset+k-1]) + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = int32(x)
+			if x >= n && y >= m {
+				return backtrack(trace, a, b, d), true
+			}
+		}
+	}
+	return nil, false
+}
+
+func backtrack(trace [][]int32, a, b []string, d int) []DiffLine {
+	x, y := len(a), len(b)
+	var rev []DiffLine
+	for ; d > 0; d-- {
+		prev := trace[d]
+		at := func(k int) int { return int(prev[k+d]) }
+		k := x - y
+		down := k == -d || (k != d && at(k-1) < at(k+1))
+		prevK := k - 1
+		if down {
+			prevK = k + 1
+		}
+		prevX := at(prevK)
+		prevY := prevX - prevK
+		for x > prevX && y > prevY {
+			x, y = x-1, y-1
+			rev = append(rev, DiffLine{Op: DiffEqual, Text: a[x]})
+		}
+		if down {
+			y--
+			rev = append(rev, DiffLine{Op: DiffInsert, Text: b[y]})
+		} else {
+			x--
+			rev = append(rev, DiffLine{Op: DiffDelete, Text: a[x]})
+		}
+	}
+	for x > 0 && y > 0 {
+		x, y = x-1, y-1
+		rev = append(rev, DiffLine{Op: DiffEqual, Text: a[x]})
+	}
+	for i, j := 0, len(rev)-1; i < j; i, j = i+1, j-1 {
+		rev[i], rev[j] = rev[j], rev[i]
+	}
+	return rev
+}
+
+// groupHunks 将编辑序列按改动位置分块，每块前后保留 context 行未改动的内容，相距较近的改动合并为一块
+func groupHunks(ops []DiffLine, context int) []DiffHunk {
+	var hunks []DiffHunk
+	var cur *DiffHunk
+	baseLine, targetLine := 1, 1
+	lastChange := -1
+	for i, op := range ops {
+		if op.Op != DiffEqual {
+			if cur == nil || i-lastChange-1 > 2*context {
+				if cur != nil {
+					hunks = append(hunks, trimHunk(*cur, ops, lastChange, context))
+				}
+				// 新开一块，带上前面至多 context 行未改动的内容
+				start := max(i-context, lastChange+1)
+				cur = &DiffHunk{BaseStart: baseLine - (i - start), TargetStart: targetLine - (i - start)}
+				cur.Lines = append(cur.Lines, ops[start:i]...)
+			} else {
+				cur.Lines = append(cur.Lines, ops[lastChange+1:i]...)
+			}
+			cur.Lines = append(cur.Lines, op)
+			lastChange = i
+		}
+		switch op.Op {
+		case DiffEqual:
+			baseLine++
+			targetLine++
+		case DiffDelete:
+			baseLine++
+		case DiffInsert:
+			targetLine++
+		}
+	}
+	if cur != nil {
+		hunks = append(hunks, trimHunk(*cur, ops, lastChange, context))
+	}
+	return hunks
+}
+
+// trimHunk 为块追加最后一处改动后的至多 context 行，并统计两侧的行数
+func trimHunk(h DiffHunk, ops []DiffLine, lastChange, context int) DiffHunk {
+	end := min(lastChange+1+context, len(ops))
+	h.Lines = append(h.Lines, ops[lastChange+1:end]...)
+	for _, l := range h.Lines {
+		if l.Op != DiffInsert {
+			h.BaseLines++
+		}
+		if l.Op != DiffDelete {
+			h.TargetLines++
+		}
+	}
+	return h
+}
@@ -0,0 +1,229 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/RigelNana/arkstudy/services/material-service/models"
+	"github.com/google/uuid"
+)
+
+// 重新处理后可能过期的派生内容类型，按来源处理类型区分（摘要仅音视频资料有）
+var derivedKindsBySource = map[string][]string{
+	models.ProcessingTypeOCR: {models.DerivedKindChunks, models.DerivedKindQuiz},
+	models.ProcessingTypeASR: {models.DerivedKindChunks, models.DerivedKindSummary, models.DerivedKindQuiz},
+}
+
+// trackDerivedArtifacts 在 OCR/ASR 处理完成后更新派生内容状态：首次处理时登记分片为 fresh；
+// 资料此前已有同类型的完成结果（即重新处理）时，将已有派生内容标记为 stale 并返回这些类型
+func (s *MaterialServiceImpl) trackDerivedArtifacts(taskID string) []string {
+	result, err := s.processingRepo.GetByTaskID(taskID)
+	if err != nil {
+		return nil
+	}
+	kinds, ok := derivedKindsBySource[result.Type]
+	if !ok {
+		return nil
+	}
+
+	previous, err := s.processingRepo.CountCompletedByMaterialIDAndType(result.MaterialID, result.Type, taskID)
+	if err != nil {
+		log.Printf("Warning: failed to check previous %s results for material %s: %v", result.Type, result.MaterialID, err)
+		return nil
+	}
+	if previous == 0 {
+		now := time.Now()
+		s.saveDerivedArtifact(&models.DerivedArtifact{
+			MaterialID:    result.MaterialID,
+			Kind:          models.DerivedKindChunks,
+			Status:        models.DerivedStatusFresh,
+			SourceTaskID:  taskID,
+			RegeneratedAt: &now,
+		}, "status", "source_task_id", "stale_reason", "stale_since", "regenerated_at", "error_message")
+		return nil
+	}
+
+	now := time.Now()
+	reason := fmt.Sprintf("material re-processed by %s task %s", result.Type, taskID)
+	for _, kind := range kinds {
+		s.saveDerivedArtifact(&models.DerivedArtifact{
+			MaterialID:  result.MaterialID,
+			Kind:        kind,
+			Status:      models.DerivedStatusStale,
+			StaleReason: reason,
+			StaleSince:  &now,
+		}, "status", "stale_reason", "stale_since")
+	}
+	log.Printf("Derived artifacts %v of material %s marked stale: %s", kinds, result.MaterialID, reason)
+	return kinds
+}
+
+// saveDerivedArtifact 写入派生内容状态，失败只记录日志，不影响处理结果回调
+func (s *MaterialServiceImpl) saveDerivedArtifact(artifact *models.DerivedArtifact, columns ...string) {
+	if err := s.derivedRepo.Upsert(artifact, columns...); err != nil {
+		log.Printf("Warning: failed to save %s artifact state for material %s: %v", artifact.Kind, artifact.MaterialID, err)
+	}
+}
+
+func (s *MaterialServiceImpl) ListDerivedArtifacts(materialID uuid.UUID, userID uuid.UUID) ([]*models.DerivedArtifact, error) {
+	material, err := s.repo.GetByID(materialID)
+	if err != nil {
+		return nil, fmt.Errorf("material not found: %w", err)
+	}
+	if material.UserID != userID {
+		return nil, fmt.Errorf("permission denied: material does not belong to user")
+	}
+	return s.derivedRepo.ListByMaterialID(materialID)
+}
+
+// RegenerateDerived 以资料最新的 OCR/ASR 结果重建派生内容，kinds 为空时重建所有 stale / failed 的派生内容。
+// 文档分片由本服务直接重建；摘要、题目及音视频分片（需要时间码）由所属服务负责，
+// 这里发布 material.regenerate_requested 事件，所属服务完成后通过 UpdateDerivedArtifact 回报
+func (s *MaterialServiceImpl) RegenerateDerived(materialID uuid.UUID, userID uuid.UUID, kinds []string) ([]*models.DerivedArtifact, error) {
+	material, err := s.repo.GetByID(materialID)
+	if err != nil {
+		return nil, fmt.Errorf("material not found: %w", err)
+	}
+	if material.UserID != userID {
+		return nil, fmt.Errorf("permission denied: material does not belong to user")
+	}
+
+	source, err := s.processingRepo.GetLatestCompletedByMaterialID(materialID, []string{models.ProcessingTypeOCR, models.ProcessingTypeASR})
+	if err != nil {
+		return nil, fmt.Errorf("material has no completed OCR/ASR result to regenerate from")
+	}
+
+	if len(kinds) == 0 {
+		existing, err := s.derivedRepo.ListByMaterialID(materialID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list derived artifacts: %w", err)
+		}
+		for _, a := range existing {
+			if a.Status == models.DerivedStatusStale || a.Status == models.DerivedStatusFailed {
+				kinds = append(kinds, a.Kind)
+			}
+		}
+		if len(kinds) == 0 {
+			return existing, nil
+		}
+	}
+
+	for _, kind := range kinds {
+		if !isDerivedKind(kind) {
+			return nil, fmt.Errorf("unsupported derived artifact kind: %s", kind)
+		}
+	}
+
+	var delegated []string
+	seen := map[string]bool{}
+	for _, kind := range kinds {
+		if seen[kind] {
+			continue
+		}
+		seen[kind] = true
+
+		s.saveDerivedArtifact(&models.DerivedArtifact{
+			MaterialID:   materialID,
+			Kind:         kind,
+			Status:       models.DerivedStatusRegenerating,
+			SourceTaskID: source.TaskID,
+		}, "status", "source_task_id", "error_message")
+
+		if kind == models.DerivedKindChunks && source.Type == models.ProcessingTypeOCR {
+			go s.regenerateChunks(material, source)
+			continue
+		}
+		delegated = append(delegated, kind)
+	}
+
+	if len(delegated) > 0 {
+		if err := s.requestRegeneration(material, source, delegated); err != nil {
+			for _, kind := range delegated {
+				s.saveDerivedArtifact(&models.DerivedArtifact{
+					MaterialID:   materialID,
+					Kind:         kind,
+					Status:       models.DerivedStatusFailed,
+					ErrorMessage: err.Error(),
+				}, "status", "error_message")
+			}
+		}
+	}
+
+	return s.derivedRepo.ListByMaterialID(materialID)
+}
+
+// requestRegeneration 通过资料事件通知下游服务重建其负责的派生内容
+func (s *MaterialServiceImpl) requestRegeneration(material *models.Material, source *models.ProcessingResult, kinds []string) error {
+	if s.materialEventsKafkaWriter == nil {
+		return fmt.Errorf("material events topic not configured, cannot request regeneration")
+	}
+	return s.writeMaterialEvent(EventMaterialRegenerateRequested, material, map[string]interface{}{
+		"kinds":          kinds,
+		"source_task_id": source.TaskID,
+		"source_type":    source.Type,
+	})
+}
+
+// regenerateChunks 以最新的 OCR 文本重新切分并替换 llm-service 中的分片
+func (s *MaterialServiceImpl) regenerateChunks(material *models.Material, source *models.ProcessingResult) {
+	err := func() error {
+		// 早期版本的同步 OCR 路径没有保存识别文本，只能重新处理
+		if source.Content == "" || source.Content == "embedded" {
+			return fmt.Errorf("source text of task %s is unavailable, re-run processing with force=true", source.TaskID)
+		}
+		chunks := splitTextToChunks(source.Content)
+		if len(chunks) == 0 {
+			return fmt.Errorf("no chunks")
+		}
+		_, err := s.upsertTextChunks(material, chunks, source.TaskID, true)
+		return err
+	}()
+
+	if err != nil {
+		log.Printf("Regenerate chunks for material %s failed: %v", material.ID, err)
+		_ = s.UpdateDerivedArtifact(material.ID, models.DerivedKindChunks, models.DerivedStatusFailed, source.TaskID, err.Error())
+		return
+	}
+	_ = s.UpdateDerivedArtifact(material.ID, models.DerivedKindChunks, models.DerivedStatusFresh, source.TaskID, "")
+}
+
+// UpdateDerivedArtifact 记录派生内容重建结果，status 只能为 fresh 或 failed
+func (s *MaterialServiceImpl) UpdateDerivedArtifact(materialID uuid.UUID, kind, status, sourceTaskID, errorMessage string) error {
+	if !isDerivedKind(kind) {
+		return fmt.Errorf("unsupported derived artifact kind: %s", kind)
+	}
+	if status != models.DerivedStatusFresh && status != models.DerivedStatusFailed {
+		return fmt.Errorf("status must be %s or %s", models.DerivedStatusFresh, models.DerivedStatusFailed)
+	}
+
+	artifact := &models.DerivedArtifact{
+		MaterialID:   materialID,
+		Kind:         kind,
+		Status:       status,
+		SourceTaskID: sourceTaskID,
+		ErrorMessage: errorMessage,
+	}
+	columns := []string{"status", "error_message"}
+	if sourceTaskID != "" {
+		columns = append(columns, "source_task_id")
+	}
+	if status == models.DerivedStatusFresh {
+		now := time.Now()
+		artifact.RegeneratedAt = &now
+		columns = append(columns, "regenerated_at", "stale_reason", "stale_since")
+	}
+	if err := s.derivedRepo.Upsert(artifact, columns...); err != nil {
+		return fmt.Errorf("failed to update derived artifact: %w", err)
+	}
+	return nil
+}
+
+func isDerivedKind(kind string) bool {
+	switch kind {
+	case models.DerivedKindChunks, models.DerivedKindSummary, models.DerivedKindQuiz:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,233 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"hash/fnv"
+	"log"
+	"math/bits"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/RigelNana/arkstudy/services/material-service/models"
+	"github.com/google/uuid"
+	"github.com/minio/minio-go/v7"
+)
+
+// 重复资料的匹配方式
+const (
+	DuplicateMatchContentHash = "content_hash" // 文件内容完全相同
+	DuplicateMatchSimHash     = "simhash"      // 提取文本相近
+)
+
+// 上传遇到重复资料时的处理方式
+const (
+	DuplicateActionWarn = "warn" // 默认：照常保存，结果附带重复的资料供用户确认
+	DuplicateActionLink = "link" // 不保存新文件也不发起处理，直接返回最匹配的已有资料
+)
+
+const (
+	// SimHash 的分词窗口：相邻 3 个词（中日韩文字按字）组成一个特征，对语序敏感
+	simHashShingle = 3
+	// 特征数少于该值的文本过短，SimHash 不可靠，不计算
+	minSimHashFeatures = 16
+)
+
+// DuplicateMatch 与某份资料内容相同或相近的已有资料；Distance 为 SimHash 的汉明距离，内容完全相同时为 0
+type DuplicateMatch struct {
+	Material *models.Material
+	Match    string
+	Distance int
+}
+
+// UploadResult 上传结果。Linked 为 true 时未保存新文件，Material 为关联到的已有资料
+type UploadResult struct {
+	Material   *models.Material
+	Duplicates []DuplicateMatch
+	Linked     bool
+}
+
+// ListDuplicateMaterials 列出与资料内容相同或相近的本人资料，内容相同的在前，相近的按距离排列
+func (s *MaterialServiceImpl) ListDuplicateMaterials(materialID, userID uuid.UUID) ([]DuplicateMatch, error) {
+	material, err := s.repo.GetByID(materialID)
+	if err != nil {
+		return nil, fmt.Errorf("material not found: %w", err)
+	}
+	if material.UserID != userID {
+		return nil, fmt.Errorf("permission denied: material does not belong to user")
+	}
+	return s.findDuplicates(material)
+}
+
+// findDuplicates 按内容哈希与 SimHash 查找同一用户的重复资料，同一资料只按更精确的方式出现一次
+func (s *MaterialServiceImpl) findDuplicates(material *models.Material) ([]DuplicateMatch, error) {
+	var matches []DuplicateMatch
+	seen := make(map[uuid.UUID]bool)
+	if material.ContentHash != "" {
+		same, err := s.repo.GetByContentHash(material.UserID, material.ContentHash, material.ID)
+		if err != nil {
+			return nil, fmt.Errorf("find materials by content hash: %w", err)
+		}
+		for _, m := range same {
+			seen[m.ID] = true
+			matches = append(matches, DuplicateMatch{Material: m, Match: DuplicateMatchContentHash})
+		}
+	}
+	if material.SimHash == nil {
+		return matches, nil
+	}
+
+	candidates, err := s.repo.GetWithSimHash(material.UserID, material.ID)
+	if err != nil {
+		return nil, fmt.Errorf("find materials by simhash: %w", err)
+	}
+	var near []DuplicateMatch
+	for _, m := range candidates {
+		if seen[m.ID] {
+			continue
+		}
+		if d := bits.OnesCount64(uint64(*material.SimHash ^ *m.SimHash)); d <= s.config.Dedup.SimHashMaxDistance {
+			near = append(near, DuplicateMatch{Material: m, Match: DuplicateMatchSimHash, Distance: d})
+		}
+	}
+	sort.SliceStable(near, func(i, j int) bool { return near[i].Distance < near[j].Distance })
+	return append(matches, near...), nil
+}
+
+// linkDuplicate 放弃刚写入的资料，返回最匹配的已有资料。新资料尚未发布事件或发起处理，删除对象与记录即可
+func (s *MaterialServiceImpl) linkDuplicate(material *models.Material, match DuplicateMatch) (*models.Material, error) {
+	// SimHash 候选只读取了部分列
+	target, err := s.repo.GetByID(match.Material.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get duplicate material: %w", err)
+	}
+	if err := s.minioClient.RemoveObject(context.Background(), material.MinioBucket, material.MinioObjectName, minio.RemoveObjectOptions{}); err != nil {
+		log.Printf("Warning: failed to remove object %s of linked duplicate: %v", material.MinioObjectName, err)
+	}
+	if err := s.repo.Delete(material.ID); err != nil {
+		return nil, fmt.Errorf("failed to delete duplicate material record: %w", err)
+	}
+	log.Printf("Upload of %s linked to existing material %s (%s, distance %d)", material.OriginalFilename, target.ID, match.Match, match.Distance)
+	return target, nil
+}
+
+// indexExtractedText 提取出资料文本后检测语言并计算 SimHash。首次得到 SimHash 时查找内容相近的资料，
+// 找到时发布 material.updated，由前端提示用户（上传时无法提取文本的资料只能在此时发现）
+func (s *MaterialServiceImpl) indexExtractedText(material *models.Material, text string) {
+	s.detectMaterialLanguage(material, text)
+
+	hash, ok := simHash(text)
+	if !ok || (material.SimHash != nil && *material.SimHash == hash) {
+		return
+	}
+	first := material.SimHash == nil
+	if err := s.repo.UpdateSimHash(material.ID, hash); err != nil {
+		log.Printf("Warning: failed to save simhash of material %s: %v", material.ID, err)
+		return
+	}
+	material.SimHash = &hash
+	if !first || !s.config.Dedup.Enabled {
+		return
+	}
+
+	matches, err := s.findDuplicates(material)
+	if err != nil {
+		log.Printf("Warning: failed to find duplicates of material %s: %v", material.ID, err)
+		return
+	}
+	var ids []string
+	for _, m := range matches {
+		if m.Match == DuplicateMatchSimHash {
+			ids = append(ids, m.Material.ID.String())
+		}
+	}
+	if len(ids) > 0 {
+		s.publishMaterialEvent(EventMaterialUpdated, material, map[string]interface{}{
+			"near_duplicates": ids,
+		})
+	}
+}
+
+// isPlainTextType 上传时即可读取正文的资料类型，与 text.extracted 流水线处理的类型一致
+func isPlainTextType(fileType string) bool {
+	switch fileType {
+	case "text", "html", "epub", FileTypeClip:
+		return true
+	}
+	return false
+}
+
+// simHash 计算文本的 64 位 SimHash：拉丁字母与数字按词、中日韩文字按字切分，相邻 simHashShingle 个词组成特征，
+// 各特征的 FNV-1a 哈希按位投票。文本过短时返回 false
+func simHash(text string) (int64, bool) {
+	tokens := simHashTokens(text)
+	if len(tokens) < simHashShingle+minSimHashFeatures-1 {
+		return 0, false
+	}
+	var votes [64]int
+	h := fnv.New64a()
+	for i := 0; i+simHashShingle <= len(tokens); i++ {
+		h.Reset()
+		h.Write([]byte(strings.Join(tokens[i:i+simHashShingle], " ")))
+		sum := h.Sum64()
+		for b := 0; b < 64; b++ {
+			if sum&(1<<b) != 0 {
+				votes[b]++
+			} else {
+				votes[b]--
+			}
+		}
+	}
+	var out uint64
+	for b, v := range votes {
+		if v > 0 {
+			out |= 1 << b
+		}
+	}
+	return int64(out), true
+}
+
+func simHashTokens(text string) []string {
+	var tokens []string
+	var word strings.Builder
+	flush := func() {
+		if word.Len() > 0 {
+			tokens = append(tokens, word.String())
+			word.Reset()
+		}
+	}
+	for _, r := range text {
+		switch {
+		case unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul):
+			flush()
+			tokens = append(tokens, string(r))
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			word.WriteRune(unicode.ToLower(r))
+		default:
+			flush()
+		}
+	}
+	flush()
+	return tokens
+}
+
+// sampleBuffer 保留写入数据的前 limit 字节，超出部分丢弃并记为 truncated；写入总是成功，可与 io.TeeReader 配合
+type sampleBuffer struct {
+	buf       bytes.Buffer
+	limit     int64
+	truncated bool
+}
+
+func (b *sampleBuffer) Write(p []byte) (int, error) {
+	if room := b.limit - int64(b.buf.Len()); room < int64(len(p)) {
+		b.truncated = true
+		if room > 0 {
+			b.buf.Write(p[:room])
+		}
+		return len(p), nil
+	}
+	b.buf.Write(p)
+	return len(p), nil
+}
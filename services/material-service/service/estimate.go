@@ -0,0 +1,174 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"time"
+	"unicode/utf8"
+
+	"github.com/RigelNana/arkstudy/services/material-service/models"
+	"github.com/google/uuid"
+	"github.com/minio/minio-go/v7"
+)
+
+// 无法探测元数据时按文件大小估算所用的系数
+const (
+	heuristicPDFBytesPerPage   = 100 << 10 // 扫描件与图文混排 PDF 的常见页大小
+	heuristicAudioBytesPerSec  = 16000     // 128 kbps
+	heuristicVideoBytesPerSec  = 250000    // 2 Mbps
+	heuristicDocumentCharBytes = 4         // Office 文档为压缩包，按每字符 4 字节估算
+	// 纯文本资料超过该大小时不读取全文，按文件大小估算字数
+	maxEstimateTextBytes = 20 << 20
+)
+
+// ProcessingEstimate 单项处理的预估用量与费用
+type ProcessingEstimate struct {
+	Type    string
+	Pages   int     // OCR 页数
+	Minutes float64 // ASR 转写分钟数
+	Chars   int64   // 识别或提取出的文本字数
+	Tokens  int64
+	Cost    float64
+}
+
+// MaterialEstimate 资料处理前的预估。Probed 为 false 时页数或时长按文件大小估算，ProbeError 为探测失败的原因
+type MaterialEstimate struct {
+	Material   *models.Material
+	PageCount  int
+	Duration   time.Duration
+	Probed     bool
+	ProbeError string
+	Estimates  []ProcessingEstimate
+	TotalCost  float64
+	Currency   string
+}
+
+// EstimateProcessing 在用户确认处理前预估资料适用的各项处理的用量与费用：PDF 优先使用上传时读取的页数，未读取时用 pdfcpu 读取，
+// 音视频优先使用上传时探测的时长，未探测时用 ffprobe 读取，文本量按每页 / 每分钟字数估算。不创建处理记录，也不调用下游服务
+func (s *MaterialServiceImpl) EstimateProcessing(materialID, userID uuid.UUID) (*MaterialEstimate, error) {
+	material, err := s.repo.GetByID(materialID)
+	if err != nil {
+		return nil, fmt.Errorf("material not found: %w", err)
+	}
+	if material.UserID != userID {
+		return nil, fmt.Errorf("permission denied: material does not belong to user")
+	}
+
+	cfg := s.config.Estimate
+	est := &MaterialEstimate{Material: material, Currency: cfg.Currency, Probed: true}
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.ProbeTimeout)
+	defer cancel()
+
+	var chars int64
+	switch material.FileType {
+	case "pdf", "image":
+		est.PageCount = 1
+		if material.FileType == "pdf" {
+			// 上传时已读取的页数直接使用
+			pages := material.Document.PageCount
+			if pages <= 0 {
+				info, err := s.readPDFInfo(ctx, material)
+				pages = info.PageCount
+				if err != nil {
+					est.Probed, est.ProbeError = false, err.Error()
+					pages = max(1, int(material.SizeBytes/heuristicPDFBytesPerPage))
+				}
+			}
+			est.PageCount = pages
+		}
+		chars = int64(est.PageCount * cfg.CharsPerPage)
+		tokens := estimateTokens(chars, cfg.CharsPerToken)
+		est.Estimates = append(est.Estimates, ProcessingEstimate{
+			Type:   models.ProcessingTypeOCR,
+			Pages:  est.PageCount,
+			Chars:  chars,
+			Tokens: tokens,
+			Cost:   float64(est.PageCount)*cfg.OCRCostPerPage + per1K(tokens, cfg.EmbeddingCostPer1K),
+		})
+	case "audio", "video":
+		// 上传时已探测的时长直接使用
+		duration := time.Duration(material.Media.DurationSeconds * float64(time.Second))
+		if duration <= 0 {
+			var info models.MediaInfo
+			info, err = s.probeMedia(ctx, material)
+			duration = time.Duration(info.DurationSeconds * float64(time.Second))
+		}
+		if err != nil {
+			est.Probed, est.ProbeError = false, err.Error()
+			rate := int64(heuristicAudioBytesPerSec)
+			if material.FileType == "video" {
+				rate = heuristicVideoBytesPerSec
+			}
+			duration = time.Duration(material.SizeBytes/rate) * time.Second
+		}
+		est.Duration = duration
+		minutes := duration.Minutes()
+		chars = int64(minutes * float64(cfg.CharsPerMinute))
+		tokens := estimateTokens(chars, cfg.CharsPerToken)
+		est.Estimates = append(est.Estimates, ProcessingEstimate{
+			Type:    models.ProcessingTypeASR,
+			Minutes: minutes,
+			Chars:   chars,
+			Tokens:  tokens,
+			Cost:    minutes*cfg.ASRCostPerMinute + per1K(tokens, cfg.EmbeddingCostPer1K),
+		})
+	case "text", "html", "epub", FileTypeClip:
+		// 文本资料上传时已直接切分入库，只有 LLM 分析产生费用
+		chars, err = s.probeTextChars(ctx, material)
+		if err != nil {
+			est.Probed, est.ProbeError = false, err.Error()
+			chars = material.SizeBytes
+		}
+	case "document", "presentation":
+		est.Probed = false
+		chars = material.SizeBytes / heuristicDocumentCharBytes
+	default:
+		return nil, fmt.Errorf("estimate is not supported for file type %q", material.FileType)
+	}
+
+	tokens := estimateTokens(chars, cfg.CharsPerToken)
+	est.Estimates = append(est.Estimates, ProcessingEstimate{
+		Type:   models.ProcessingTypeLLMAnalysis,
+		Chars:  chars,
+		Tokens: tokens,
+		Cost:   per1K(tokens, cfg.LLMCostPer1K),
+	})
+	for _, e := range est.Estimates {
+		est.TotalCost += e.Cost
+	}
+	return est, nil
+}
+
+// probeTextChars 读取纯文本资料并按上传时的提取规则统计正文字数
+func (s *MaterialServiceImpl) probeTextChars(ctx context.Context, material *models.Material) (int64, error) {
+	if material.SizeBytes > maxEstimateTextBytes {
+		return 0, fmt.Errorf("file larger than %d bytes", maxEstimateTextBytes)
+	}
+	obj, err := s.minioClient.GetObject(ctx, material.MinioBucket, material.MinioObjectName, minio.GetObjectOptions{})
+	if err != nil {
+		return 0, fmt.Errorf("get object: %w", err)
+	}
+	defer obj.Close()
+	data, err := io.ReadAll(io.LimitReader(obj, maxEstimateTextBytes))
+	if err != nil {
+		return 0, fmt.Errorf("read object: %w", err)
+	}
+	text, err := extractMaterialText(material.FileType, data)
+	if err != nil {
+		return 0, fmt.Errorf("extract text: %w", err)
+	}
+	return int64(utf8.RuneCountInString(text)), nil
+}
+
+func estimateTokens(chars int64, charsPerToken float64) int64 {
+	if charsPerToken <= 0 {
+		charsPerToken = 1
+	}
+	return int64(math.Ceil(float64(chars) / charsPerToken))
+}
+
+func per1K(tokens int64, cost float64) float64 {
+	return float64(tokens) / 1000 * cost
+}
@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	arkkafka "github.com/RigelNana/arkstudy/pkg/kafka"
+	kafkaMetrics "github.com/RigelNana/arkstudy/pkg/metrics/kafka"
+	"github.com/RigelNana/arkstudy/services/material-service/config"
+	"github.com/RigelNana/arkstudy/services/material-service/models"
+	"github.com/google/uuid"
+	kafka "github.com/segmentio/kafka-go"
+)
+
+// 资料生命周期事件类型，下游服务据此订阅而不必直接调用 gRPC
+const (
+	EventMaterialCreated = "material.created"
+	EventMaterialUpdated = "material.updated"
+	EventMaterialDeleted = "material.deleted"
+	// 请求下游服务重建其负责的派生内容（摘要、题目等），changes.kinds 为需要重建的类型
+	EventMaterialRegenerateRequested = "material.regenerate_requested"
+)
+
+// MaterialEvent 为发布到资料事件 topic 的消息结构，消息 key 为 material_id，保证同一资料的事件有序
+type MaterialEvent struct {
+	EventID    string                 `json:"event_id"`
+	Type       string                 `json:"type"`
+	MaterialID string                 `json:"material_id"`
+	UserID     string                 `json:"user_id"`
+	Title      string                 `json:"title"`
+	FileType   string                 `json:"file_type"`
+	Status     string                 `json:"status"`
+	SizeBytes  int64                  `json:"size_bytes"`
+	Changes    map[string]interface{} `json:"changes,omitempty"` // material.updated 记录本次变更的字段，material.regenerate_requested 记录重建范围
+	OccurredAt time.Time              `json:"occurred_at"`
+}
+
+// newMaterialEventsKafkaWriter 创建资料事件的 Kafka writer，未配置 topic 时返回 nil
+func newMaterialEventsKafkaWriter(cfg *config.Config) *kafka.Writer {
+	return newTopicKafkaWriter(cfg.Database.KafkaBrokers, cfg.Database.KafkaTopicMaterialEvents)
+}
+
+// publishMaterialEvent 发布资料事件。事件是尽力而为的通知，发送失败只记录日志，不影响主流程
+func (s *MaterialServiceImpl) publishMaterialEvent(eventType string, material *models.Material, changes map[string]interface{}) {
+	if s.materialEventsKafkaWriter == nil {
+		return
+	}
+	if err := s.writeMaterialEvent(eventType, material, changes); err != nil {
+		log.Printf("Warning: failed to publish %s event for material %s: %v", eventType, material.ID.String(), err)
+	}
+}
+
+func (s *MaterialServiceImpl) writeMaterialEvent(eventType string, material *models.Material, changes map[string]interface{}) error {
+	event := MaterialEvent{
+		EventID:    uuid.New().String(),
+		Type:       eventType,
+		MaterialID: material.ID.String(),
+		UserID:     material.UserID.String(),
+		Title:      material.Title,
+		FileType:   material.FileType,
+		Status:     material.Status,
+		SizeBytes:  material.SizeBytes,
+		Changes:    changes,
+		OccurredAt: time.Now().UTC(),
+	}
+
+	messageBytes, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	err = kafkaMetrics.WriteMessages(ctx, s.materialEventsKafkaWriter, kafka.Message{
+		Key:   arkkafka.MaterialKey(material.ID.String()),
+		Value: messageBytes,
+		Headers: []kafka.Header{
+			{Key: "event_type", Value: []byte(eventType)},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write message to kafka: %w", err)
+	}
+	return nil
+}
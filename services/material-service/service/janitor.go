@@ -0,0 +1,294 @@
+package service
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/RigelNana/arkstudy/services/material-service/config"
+	"github.com/RigelNana/arkstudy/services/material-service/database"
+	"github.com/RigelNana/arkstudy/services/material-service/models"
+	"github.com/RigelNana/arkstudy/services/material-service/repository"
+	"github.com/google/uuid"
+	"github.com/minio/minio-go/v7"
+	"gorm.io/gorm"
+)
+
+const (
+	// 多副本共用的 advisory lock 键，同一时刻只有一个副本执行清理
+	janitorLockKey int64 = 0x6a616e69746f72
+	// 单轮每类记录最多处理的数量，积压时由后续轮次继续
+	janitorBatchSize = 500
+)
+
+// Janitor 定时清理孤立对象与卡死的记录：
+//   - 没有资料记录引用的 MinIO 对象
+//   - 停留在 processing 超过 TTL 的处理记录（标记为失败）
+//   - 停留在 uploading 超过 TTL 的资料（标记为失败），以及未完成的分片上传
+//   - 过期的上传会话及其分段；过期仍停留在 completing 的会话（合并中副本退出）改回 active 以便重试
+//
+// 同时派发有空余名额的用户排队中的处理任务，兜底任务结束时未能派发的情况（如派发前副本退出）
+type Janitor struct {
+	svc            MaterialService
+	repo           repository.MaterialRepository
+	processingRepo repository.ProcessingResultRepository
+	sessionRepo    repository.UploadSessionRepository
+	db             *gorm.DB
+	minioClient    *minio.Client
+	bucket         string
+	cfg            config.JanitorConfig
+	sessionTTL     time.Duration
+}
+
+func NewJanitor(svc MaterialService, repo repository.MaterialRepository, processingRepo repository.ProcessingResultRepository, sessionRepo repository.UploadSessionRepository, db *gorm.DB, cfg *config.Config) (*Janitor, error) {
+	minioClient, err := newMinioClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &Janitor{
+		svc:            svc,
+		repo:           repo,
+		processingRepo: processingRepo,
+		sessionRepo:    sessionRepo,
+		db:             db,
+		minioClient:    minioClient,
+		bucket:         cfg.MinIO.BucketName,
+		cfg:            cfg.Janitor,
+		sessionTTL:     cfg.UploadSession.TTL,
+	}, nil
+}
+
+// Run 启动后立即清理一次，之后按 Interval 周期执行，直到 ctx 取消
+func (j *Janitor) Run(ctx context.Context) {
+	log.Printf("Janitor started: interval=%s processing_ttl=%s upload_ttl=%s orphan_min_age=%s",
+		j.cfg.Interval, j.cfg.ProcessingTTL, j.cfg.UploadTTL, j.cfg.OrphanMinAge)
+
+	ticker := time.NewTicker(j.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		j.runOnce(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (j *Janitor) runOnce(ctx context.Context) {
+	unlock, ok, err := database.TryAdvisoryLock(ctx, j.db, janitorLockKey)
+	if err != nil {
+		log.Printf("Janitor: failed to acquire lock: %v", err)
+		return
+	}
+	if !ok {
+		log.Printf("Janitor: another replica holds the lock, skipping this round")
+		return
+	}
+	defer unlock()
+
+	start := time.Now()
+	stuck := j.failStuckProcessing()
+	stale := j.failStaleUploads()
+	aborted := j.abortIncompleteUploads(ctx)
+	sessions, recovered := j.purgeExpiredUploadSessions(ctx)
+	orphans := j.removeOrphanObjects(ctx)
+	queued := j.dispatchQueued()
+	log.Printf("Janitor: round finished in %s: stuck_processing=%d stale_uploads=%d aborted_multipart=%d expired_upload_sessions=%d recovered_upload_sessions=%d orphan_objects=%d queued_dispatched=%d",
+		time.Since(start), stuck, stale, aborted, sessions, recovered, orphans, queued)
+}
+
+// purgeExpiredUploadSessions 删除过期的上传会话及其分段；已完成的会话保留到过期，供客户端查询结果。
+// 过期仍为 completing 的会话不删除，改回 active 并延长有效期，返回删除与恢复的数量
+func (j *Janitor) purgeExpiredUploadSessions(ctx context.Context) (purged, recovered int) {
+	sessions, err := j.sessionRepo.GetExpired(time.Now(), janitorBatchSize)
+	if err != nil {
+		log.Printf("Janitor: failed to query expired upload sessions: %v", err)
+		return 0, 0
+	}
+	for _, session := range sessions {
+		if session.Status == models.UploadSessionCompleting {
+			// 分段已全部接收，客户端可用 offset 等于 size 的空追加重试合并；
+			// 乐观锁保证与同时接管该会话的重试只有一方成功
+			session.Status = models.UploadSessionActive
+			session.ExpiresAt = time.Now().Add(j.sessionTTL)
+			if err := j.sessionRepo.Update(session); err != nil {
+				log.Printf("Janitor: failed to recover upload session %s: %v", session.ID, err)
+				continue
+			}
+			recovered++
+			continue
+		}
+		if err := removeObjectsWithPrefix(ctx, j.minioClient, j.bucket, uploadSessionObjectPrefix(session.ID)); err != nil {
+			log.Printf("Janitor: failed to remove parts of upload session %s: %v", session.ID, err)
+			continue
+		}
+		if err := j.sessionRepo.Delete(session.ID); err != nil {
+			log.Printf("Janitor: failed to delete upload session %s: %v", session.ID, err)
+			continue
+		}
+		purged++
+	}
+	return purged, recovered
+}
+
+// dispatchQueued 为有排队任务的用户派发空出名额的任务
+func (j *Janitor) dispatchQueued() int {
+	userIDs, err := j.processingRepo.ListQueuedUserIDs(janitorBatchSize)
+	if err != nil {
+		log.Printf("Janitor: failed to query users with queued processing: %v", err)
+		return 0
+	}
+	count := 0
+	for _, userID := range userIDs {
+		count += j.svc.DispatchQueued(userID)
+	}
+	return count
+}
+
+// failStuckProcessing 将超时仍处于 processing 的处理记录标记为失败，客户端可据此重试
+func (j *Janitor) failStuckProcessing() int {
+	results, err := j.processingRepo.GetStaleByStatus(models.ProcessingStatusProcessing, time.Now().Add(-j.cfg.ProcessingTTL), janitorBatchSize)
+	if err != nil {
+		log.Printf("Janitor: failed to query stuck processing results: %v", err)
+		return 0
+	}
+	count := 0
+	for _, r := range results {
+		if err := j.svc.UpdateProcessingResult(r.TaskID, models.ProcessingStatusFailed, "", nil, "processing timed out"); err != nil {
+			log.Printf("Janitor: failed to mark task %s as failed: %v", r.TaskID, err)
+			continue
+		}
+		count++
+	}
+	return count
+}
+
+// failStaleUploads 将上传中断（停留在 uploading）的资料标记为失败，残留对象由孤立对象清理处理
+func (j *Janitor) failStaleUploads() int {
+	materials, err := j.repo.GetStaleByStatus("uploading", time.Now().Add(-j.cfg.UploadTTL), janitorBatchSize)
+	if err != nil {
+		log.Printf("Janitor: failed to query stale uploads: %v", err)
+		return 0
+	}
+	count := 0
+	for _, m := range materials {
+		if err := j.svc.UpdateStatus(m.ID, "failed"); err != nil {
+			log.Printf("Janitor: failed to mark material %s as failed: %v", m.ID.String(), err)
+			continue
+		}
+		count++
+	}
+	return count
+}
+
+// abortIncompleteUploads 中止超过 UploadTTL 仍未完成的分片上传，释放其占用的分片
+func (j *Janitor) abortIncompleteUploads(ctx context.Context) int {
+	cutoff := time.Now().Add(-j.cfg.UploadTTL)
+	count := 0
+	for upload := range j.minioClient.ListIncompleteUploads(ctx, j.bucket, "", true) {
+		if upload.Err != nil {
+			log.Printf("Janitor: failed to list incomplete uploads: %v", upload.Err)
+			break
+		}
+		if upload.Initiated.After(cutoff) {
+			continue
+		}
+		if err := j.minioClient.RemoveIncompleteUpload(ctx, j.bucket, upload.Key); err != nil {
+			log.Printf("Janitor: failed to abort incomplete upload %s: %v", upload.Key, err)
+			continue
+		}
+		count++
+	}
+	return count
+}
+
+// removeOrphanObjects 删除存储桶中没有资料记录引用、且存在时间超过 OrphanMinAge 的对象；
+// 上传会话的分段按会话是否存在判断
+func (j *Janitor) removeOrphanObjects(ctx context.Context) int {
+	cutoff := time.Now().Add(-j.cfg.OrphanMinAge)
+	count := 0
+	var batch []string
+	var sessionBatch []string
+
+	remove := func(name string) {
+		if err := j.minioClient.RemoveObject(ctx, j.bucket, name, minio.RemoveObjectOptions{}); err != nil {
+			log.Printf("Janitor: failed to remove orphan object %s: %v", name, err)
+			return
+		}
+		count++
+	}
+
+	flush := func() {
+		existing, err := j.repo.ExistingObjectNames(j.bucket, batch)
+		if err != nil {
+			log.Printf("Janitor: failed to check object references: %v", err)
+			batch = batch[:0]
+			return
+		}
+		for _, name := range batch {
+			if !existing[name] {
+				remove(name)
+			}
+		}
+		batch = batch[:0]
+	}
+
+	flushSessions := func() {
+		ids := make([]uuid.UUID, 0, len(sessionBatch))
+		for _, name := range sessionBatch {
+			if id, ok := uploadSessionIDFromObject(name); ok {
+				ids = append(ids, id)
+			}
+		}
+		existing, err := j.sessionRepo.ExistingIDs(ids)
+		if err != nil {
+			log.Printf("Janitor: failed to check upload session references: %v", err)
+			sessionBatch = sessionBatch[:0]
+			return
+		}
+		for _, name := range sessionBatch {
+			if id, ok := uploadSessionIDFromObject(name); !ok || !existing[id] {
+				remove(name)
+			}
+		}
+		sessionBatch = sessionBatch[:0]
+	}
+
+	for obj := range j.minioClient.ListObjects(ctx, j.bucket, minio.ListObjectsOptions{Recursive: true}) {
+		if obj.Err != nil {
+			log.Printf("Janitor: failed to list objects: %v", obj.Err)
+			break
+		}
+		if obj.LastModified.After(cutoff) {
+			continue
+		}
+		if strings.HasPrefix(obj.Key, uploadSessionPrefix) {
+			sessionBatch = append(sessionBatch, obj.Key)
+			if len(sessionBatch) >= janitorBatchSize {
+				flushSessions()
+			}
+			continue
+		}
+		batch = append(batch, obj.Key)
+		if len(batch) >= janitorBatchSize {
+			flush()
+		}
+	}
+	if len(batch) > 0 {
+		flush()
+	}
+	if len(sessionBatch) > 0 {
+		flushSessions()
+	}
+	return count
+}
+
+// uploadSessionIDFromObject 从上传会话分段的对象名中解析会话 ID
+func uploadSessionIDFromObject(name string) (uuid.UUID, bool) {
+	rest := strings.TrimPrefix(name, uploadSessionPrefix)
+	idPart, _, _ := strings.Cut(rest, "/")
+	id, err := uuid.Parse(idPart)
+	return id, err == nil
+}
@@ -0,0 +1,51 @@
+package service
+
+import (
+	"log"
+	"maps"
+
+	"github.com/RigelNana/arkstudy/pkg/langdetect"
+	"github.com/RigelNana/arkstudy/services/material-service/models"
+)
+
+// ocr-service 识别语言选项（lang）的取值，与 PaddleOCR 的语言代码一致。
+// 中文及未列出的语言不传提示，使用 ocr-service 的默认语言 ch（兼容中英文混排）
+var ocrLanguageHints = map[string]string{
+	langdetect.English:    "en",
+	langdetect.Japanese:   "japan",
+	langdetect.Korean:     "korean",
+	langdetect.French:     "fr",
+	langdetect.German:     "german",
+	langdetect.Spanish:    "es",
+	langdetect.Portuguese: "pt",
+	langdetect.Italian:    "it",
+	langdetect.Russian:    "ru",
+	langdetect.Arabic:     "ar",
+	langdetect.Hindi:      "hi",
+}
+
+// detectMaterialLanguage 检测提取文本的主要语言并写入资料记录，无法判断时保留已有的语言
+func (s *MaterialServiceImpl) detectMaterialLanguage(material *models.Material, text string) {
+	lang := langdetect.Detect(text)
+	if lang == "" || lang == material.Language {
+		return
+	}
+	if err := s.repo.UpdateLanguage(material.ID, lang); err != nil {
+		log.Printf("Warning: failed to save language of material %s: %v", material.ID, err)
+		return
+	}
+	material.Language = lang
+}
+
+// withOCRLanguageHint 未指定识别语言时按资料已检测的语言补充 lang 选项，如更换引擎重新识别或对比处理时。
+// 返回新的 map，不修改 options
+func withOCRLanguageHint(material *models.Material, options map[string]string) map[string]string {
+	hint, ok := ocrLanguageHints[material.Language]
+	if !ok || options["lang"] != "" {
+		return options
+	}
+	hinted := make(map[string]string, len(options)+1)
+	maps.Copy(hinted, options)
+	hinted["lang"] = hint
+	return hinted
+}
@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/RigelNana/arkstudy/services/material-service/config"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/lifecycle"
+	"github.com/minio/minio-go/v7/pkg/tags"
+)
+
+// 由 material-service 管理的生命周期规则 ID，应用配置时只替换这些规则，存储桶上的其他规则保持不变
+const (
+	lifecycleRuleTransition = "arkstudy-transition-originals"
+	lifecycleRuleTrash      = "arkstudy-expire-trash"
+)
+
+// 删除资料时为对象打上的回收站标签，由 lifecycleRuleTrash 到期删除
+const (
+	trashTagKey   = "trash"
+	trashTagValue = "true"
+)
+
+// applyBucketLifecycle 按配置更新存储桶的生命周期规则：原始文件超过 TransitionDays 天转存到冷存储 tier，
+// 回收站对象超过 TrashExpireDays 天过期删除。配置中关闭的规则会从存储桶上移除
+func applyBucketLifecycle(ctx context.Context, client *minio.Client, bucket string, cfg config.LifecycleConfig) error {
+	current, err := client.GetBucketLifecycle(ctx, bucket)
+	if err != nil {
+		if minio.ToErrorResponse(err).Code != "NoSuchLifecycleConfiguration" {
+			return fmt.Errorf("failed to get bucket lifecycle: %w", err)
+		}
+		current = lifecycle.NewConfiguration()
+	}
+
+	next := lifecycle.NewConfiguration()
+	for _, rule := range current.Rules {
+		if rule.ID != lifecycleRuleTransition && rule.ID != lifecycleRuleTrash {
+			next.Rules = append(next.Rules, rule)
+		}
+	}
+	if cfg.TransitionDays > 0 && cfg.TransitionTier != "" {
+		next.Rules = append(next.Rules, lifecycle.Rule{
+			ID:     lifecycleRuleTransition,
+			Status: "Enabled",
+			Transition: lifecycle.Transition{
+				Days:         lifecycle.ExpirationDays(cfg.TransitionDays),
+				StorageClass: cfg.TransitionTier,
+			},
+		})
+	}
+	if cfg.TrashExpireDays > 0 {
+		next.Rules = append(next.Rules, lifecycle.Rule{
+			ID:         lifecycleRuleTrash,
+			Status:     "Enabled",
+			RuleFilter: lifecycle.Filter{Tag: lifecycle.Tag{Key: trashTagKey, Value: trashTagValue}},
+			Expiration: lifecycle.Expiration{Days: lifecycle.ExpirationDays(cfg.TrashExpireDays)},
+		})
+	}
+
+	// 空配置表示删除存储桶上的全部生命周期规则
+	if err := client.SetBucketLifecycle(ctx, bucket, next); err != nil {
+		return fmt.Errorf("failed to set bucket lifecycle: %w", err)
+	}
+	log.Printf("Bucket %s lifecycle applied: %d rule(s), transition after %d day(s) to %q, trash expires after %d day(s)",
+		bucket, len(next.Rules), cfg.TransitionDays, cfg.TransitionTier, cfg.TrashExpireDays)
+	return nil
+}
+
+// trashEnabled 删除资料时是否转入回收站而非立即删除对象
+func (s *MaterialServiceImpl) trashEnabled() bool {
+	return s.config.Lifecycle.Enabled && s.config.Lifecycle.TrashExpireDays > 0
+}
+
+// moveToTrash 为对象打上回收站标签，对象在 TrashExpireDays 天后由生命周期规则删除
+func (s *MaterialServiceImpl) moveToTrash(ctx context.Context, bucket, objectName string) error {
+	t, err := tags.NewTags(map[string]string{trashTagKey: trashTagValue}, true)
+	if err != nil {
+		return err
+	}
+	return s.minioClient.PutObjectTagging(ctx, bucket, objectName, t, minio.PutObjectTaggingOptions{})
+}
+
+// storageClassFor 按文件类型与大小选择写入的存储类型，为空时使用存储桶默认类型。
+// 大小未知（流式上传）时无法判断，使用默认类型
+func (s *MaterialServiceImpl) storageClassFor(fileType string, size int64) string {
+	lc := s.config.Lifecycle
+	if fileType == "video" && lc.LargeVideoStorageClass != "" && size > 0 && size >= lc.LargeVideoBytes {
+		return lc.LargeVideoStorageClass
+	}
+	return ""
+}
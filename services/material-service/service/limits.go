@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/RigelNana/arkstudy/services/material-service/models"
+	"github.com/google/uuid"
+)
+
+// 受用户并发上限约束的处理类型，见 config.LimitsConfig
+var limitedProcessingTypes = []string{models.ProcessingTypeOCR, models.ProcessingTypeASR}
+
+func isLimitedProcessingType(processType string) bool {
+	return processType == models.ProcessingTypeOCR || processType == models.ProcessingTypeASR
+}
+
+// ProcessingUsage 用户 OCR / ASR 任务的并发用量，上限为 0 表示不限制
+type ProcessingUsage struct {
+	InFlight    int // 等待或正在处理的任务数
+	Queued      int // 排队中的任务数
+	MaxInFlight int
+	MaxQueued   int
+}
+
+func (u ProcessingUsage) inFlightFull() bool {
+	return u.MaxInFlight > 0 && u.InFlight >= u.MaxInFlight
+}
+
+func (u ProcessingUsage) queueFull() bool {
+	return u.MaxQueued > 0 && u.Queued >= u.MaxQueued
+}
+
+// ProcessingLimitError 用户的任务数已达上限，新任务既不能立即派发也不能排队
+type ProcessingLimitError struct {
+	Usage ProcessingUsage
+}
+
+func (e *ProcessingLimitError) Error() string {
+	u := e.Usage
+	if u.queueFull() {
+		return fmt.Sprintf("processing queue is full: %d jobs in progress (limit %d) and %d queued (limit %d); wait for some jobs to finish and try again",
+			u.InFlight, u.MaxInFlight, u.Queued, u.MaxQueued)
+	}
+	return fmt.Sprintf("too many processing jobs in progress: %d (limit %d per user); wait for some jobs to finish and try again",
+		u.InFlight, u.MaxInFlight)
+}
+
+// GetProcessingUsage 统计用户当前的 OCR / ASR 任务用量
+func (s *MaterialServiceImpl) GetProcessingUsage(userID uuid.UUID) (*ProcessingUsage, error) {
+	inFlight, queued, err := s.processingRepo.CountActiveByUserID(userID, limitedProcessingTypes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count processing jobs: %w", err)
+	}
+	return &ProcessingUsage{
+		InFlight:    int(inFlight),
+		Queued:      int(queued),
+		MaxInFlight: s.config.Limits.MaxInFlightPerUser,
+		MaxQueued:   s.config.Limits.MaxQueuedPerUser,
+	}, nil
+}
+
+// admitProcessing 决定用户新提交（或重试）的任务的初始状态：未达上限时为 pending 立即派发，否则为 queued 排队。
+// 已有排队任务时新任务排在其后，保证先提交先派发；canQueue=false（如 ASR 由 asr-service 同步转写，无法等待）
+// 或排队数已达上限时返回 *ProcessingLimitError。并发提交时上限可能被短暂超出
+func (s *MaterialServiceImpl) admitProcessing(userID uuid.UUID, processType string, canQueue bool) (string, error) {
+	if !isLimitedProcessingType(processType) || s.config.Limits.MaxInFlightPerUser <= 0 {
+		return models.ProcessingStatusPending, nil
+	}
+	usage, err := s.GetProcessingUsage(userID)
+	if err != nil {
+		// 统计失败时不阻塞处理
+		log.Printf("Warning: %v, skipping limit check for user %s", err, userID)
+		return models.ProcessingStatusPending, nil
+	}
+	if !usage.inFlightFull() && (usage.Queued == 0 || !canQueue) {
+		return models.ProcessingStatusPending, nil
+	}
+	if !canQueue || usage.queueFull() {
+		return "", &ProcessingLimitError{Usage: *usage}
+	}
+	return models.ProcessingStatusQueued, nil
+}
+
+// DispatchQueued 按提交顺序派发用户排队中的任务，直到等待或正在处理的任务数达到上限，返回派发的数量。
+// 任务结束时与 Janitor 定时调用；多个副本同时派发时由 ClaimQueued 保证同一任务只派发一次
+func (s *MaterialServiceImpl) DispatchQueued(userID uuid.UUID) int {
+	usage, err := s.GetProcessingUsage(userID)
+	if err != nil {
+		log.Printf("Warning: failed to dispatch queued jobs of user %s: %v", userID, err)
+		return 0
+	}
+	free := usage.Queued
+	if usage.MaxInFlight > 0 {
+		free = min(free, usage.MaxInFlight-usage.InFlight)
+	}
+	if free <= 0 {
+		return 0
+	}
+	results, err := s.processingRepo.ListQueuedByUserID(userID, free)
+	if err != nil {
+		log.Printf("Warning: failed to list queued jobs of user %s: %v", userID, err)
+		return 0
+	}
+
+	dispatched := 0
+	for _, result := range results {
+		claimed, err := s.processingRepo.ClaimQueued(result.TaskID)
+		if err != nil {
+			log.Printf("Warning: failed to claim queued task %s: %v", result.TaskID, err)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+		result.Status = models.ProcessingStatusPending
+		s.publishTaskEvent(result.TaskID)
+		s.dispatchQueuedTask(&result.Material, result)
+		dispatched++
+	}
+	if dispatched > 0 {
+		log.Printf("Dispatched %d queued processing jobs for user %s", dispatched, userID)
+	}
+	return dispatched
+}
+
+// dispatchQueuedTask 以提交时保存的选项派发排队结束的任务，与 RetryProcessingTask 相同；延后到处理时段的任务派发时通知用户
+func (s *MaterialServiceImpl) dispatchQueuedTask(material *models.Material, result *models.ProcessingResult) {
+	var options map[string]string
+	if len(result.Options) > 0 {
+		if err := json.Unmarshal(result.Options, &options); err != nil {
+			_ = s.UpdateProcessingResult(result.TaskID, models.ProcessingStatusFailed, "", nil, fmt.Sprintf("decode options: %v", err))
+			return
+		}
+	}
+	s.notifyProcessingStarted(material, result)
+	if result.Type == models.ProcessingTypeASR {
+		go s.handleASR(material, result)
+		return
+	}
+	s.dispatchProcessing(context.Background(), material, result, options)
+}
@@ -3,178 +3,252 @@ package service
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"io"
 	"log"
+	"maps"
+	"mime"
+	"net/url"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
-
+	"unicode/utf8"
+
+	"github.com/RigelNana/arkstudy/pkg/dbx"
+	"github.com/RigelNana/arkstudy/pkg/discovery"
+	"github.com/RigelNana/arkstudy/pkg/featureflags"
+	arkkafka "github.com/RigelNana/arkstudy/pkg/kafka"
+	"github.com/RigelNana/arkstudy/pkg/metrics"
+	kafkaMetrics "github.com/RigelNana/arkstudy/pkg/metrics/kafka"
+	"github.com/RigelNana/arkstudy/pkg/registry"
 	aipb "github.com/RigelNana/arkstudy/proto/ai"
+	asrpb "github.com/RigelNana/arkstudy/proto/asr"
 	llmpb "github.com/RigelNana/arkstudy/proto/llm"
 	"github.com/RigelNana/arkstudy/services/material-service/config"
+	"github.com/RigelNana/arkstudy/services/material-service/extract"
 	"github.com/RigelNana/arkstudy/services/material-service/models"
 	"github.com/RigelNana/arkstudy/services/material-service/repository"
 	"github.com/google/uuid"
 	"github.com/minio/minio-go/v7"
 	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/minio/minio-go/v7/pkg/encrypt"
 	kafka "github.com/segmentio/kafka-go"
-	"google.golang.org/grpc"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
 )
 
 type MaterialService interface {
-	UploadFile(userID uuid.UUID, title, originalFilename string, fileData []byte) (*models.Material, error)
+	UploadFile(userID uuid.UUID, title, originalFilename string, reader io.Reader, size int64, onDuplicate string) (*UploadResult, error)
+	ListDuplicateMaterials(materialID, userID uuid.UUID) ([]DuplicateMatch, error)
 	GetByID(id uuid.UUID) (*models.Material, error)
 	GetByUserIDWithPagination(userID uuid.UUID, page, pageSize int32) ([]*models.Material, int64, error)
 	UpdateStatus(id uuid.UUID, status string) error
 	Delete(id uuid.UUID) error
 	GetFileURL(material *models.Material, expiry time.Duration) (string, error)
+	GetMediaURL(material *models.Material, expiry time.Duration) (string, string, error)
+	ListChildren(parentID uuid.UUID, userID uuid.UUID) ([]*models.Material, error)
+	CreateClip(userID uuid.UUID, clip ClipInput) (*models.Material, error)
 
 	// AI 处理相关方法
-	ProcessMaterial(materialID uuid.UUID, userID uuid.UUID, processType string, options map[string]string) (*models.ProcessingResult, error)
+	ProcessMaterial(ctx context.Context, materialID uuid.UUID, userID uuid.UUID, processType string, options map[string]string) (*models.ProcessingResult, error)
 	GetProcessingResult(materialID uuid.UUID, processType string) (*models.ProcessingResult, error)
 	ListProcessingResults(materialID uuid.UUID, page, pageSize int32) ([]*models.ProcessingResult, int64, error)
 	UpdateProcessingResult(taskID string, status string, content string, metadata map[string]interface{}, errorMessage string) error
+	RetryProcessingTask(ctx context.Context, taskID string, userID uuid.UUID) (*models.ProcessingResult, error)
+	UpdateProgress(taskID string, progress float32) error
+	GetProcessingUsage(userID uuid.UUID) (*ProcessingUsage, error)
+	DispatchQueued(userID uuid.UUID) int
+	CompareProcessingResults(materialID, userID uuid.UUID, processType, baseTaskID, targetTaskID string, contextLines int) (*ResultComparison, error)
+	EstimateProcessing(materialID, userID uuid.UUID) (*MaterialEstimate, error)
+
+	// 派生内容新鲜度与重建
+	ListDerivedArtifacts(materialID uuid.UUID, userID uuid.UUID) ([]*models.DerivedArtifact, error)
+	RegenerateDerived(materialID uuid.UUID, userID uuid.UUID, kinds []string) ([]*models.DerivedArtifact, error)
+	UpdateDerivedArtifact(materialID uuid.UUID, kind, status, sourceTaskID, errorMessage string) error
+
+	// 批注
+	CreateAnnotation(userID uuid.UUID, in AnnotationInput) (*models.Annotation, error)
+	UpdateAnnotation(id, userID uuid.UUID, content, color *string) (*models.Annotation, error)
+	DeleteAnnotation(id, userID uuid.UUID) error
+	ListAnnotations(userID uuid.UUID, materialIDs []uuid.UUID, filter repository.AnnotationFilter) ([]*models.Annotation, error)
+
+	// 可续传的上传会话
+	CreateUploadSession(userID uuid.UUID, title, originalFilename string, size int64, onDuplicate string) (*models.UploadSession, error)
+	GetUploadSession(id, userID uuid.UUID) (*models.UploadSession, error)
+	AppendUploadSession(id, userID uuid.UUID, offset int64, reader io.Reader) (*UploadSessionResult, error)
+	DeleteUploadSession(id, userID uuid.UUID) error
 }
 
 type MaterialServiceImpl struct {
 	repo                     repository.MaterialRepository
 	processingRepo           repository.ProcessingResultRepository
+	derivedRepo              repository.DerivedArtifactRepository
+	annotationRepo           repository.AnnotationRepository
+	uploadSessionRepo        repository.UploadSessionRepository
 	minioClient              *minio.Client
 	config                   *config.Config
 	kafkaWriter              *kafka.Writer
 	textExtractedKafkaWriter *kafka.Writer
+	// OCR 任务，由 ocr-service 消费
+	ocrRequestsKafkaWriter *kafka.Writer
+	// 资料生命周期事件，未配置时不发布
+	materialEventsKafkaWriter *kafka.Writer
+	// 任务中心的任务事件，OCR / ASR 处理记录的状态变化时发布
+	taskEventsKafkaWriter *kafka.Writer
+	// 通知请求，未配置时不通知
+	notificationsKafkaWriter *kafka.Writer
+	// 大任务的处理时段
+	schedule *processingSchedule
+	// 上传对象时使用的服务端加密，未配置时为 nil
+	sse encrypt.ServerSide
+	// 限制同时运行的 ffprobe 进程数
+	mediaProbeSlots chan struct{}
 }
 
-func NewMaterialService(repo repository.MaterialRepository, processingRepo repository.ProcessingResultRepository, cfg *config.Config) (MaterialService, error) {
-	// 初始化 MinIO 客户端
-	minioClient, err := minio.New(cfg.MinIO.Endpoint, &minio.Options{
-		Creds:  credentials.NewStaticV4(cfg.MinIO.AccessKeyID, cfg.MinIO.SecretAccessKey, ""),
-		Secure: cfg.MinIO.UseSSL,
-	})
+func NewMaterialService(repo repository.MaterialRepository, processingRepo repository.ProcessingResultRepository, derivedRepo repository.DerivedArtifactRepository, annotationRepo repository.AnnotationRepository, uploadSessionRepo repository.UploadSessionRepository, cfg *config.Config) (MaterialService, error) {
+	minioClient, err := newMinioClient(cfg)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create MinIO client: %w", err)
+		return nil, err
 	}
-
-	// 确保存储桶存在
-	ctx := context.Background()
-	exists, err := minioClient.BucketExists(ctx, cfg.MinIO.BucketName)
+	// 加密配置错误时拒绝启动，避免以未加密方式写入合规要求加密的资料
+	sse, err := newServerSideEncryption(cfg.MinIO)
 	if err != nil {
-		return nil, fmt.Errorf("failed to check bucket existence: %w", err)
+		return nil, err
 	}
-	if !exists {
-		err = minioClient.MakeBucket(ctx, cfg.MinIO.BucketName, minio.MakeBucketOptions{})
-		if err != nil {
-			return nil, fmt.Errorf("failed to create bucket: %w", err)
+	if sse != nil {
+		log.Printf("MinIO server-side encryption enabled: %s", cfg.MinIO.SSEMode)
+	}
+	schedule, err := newProcessingSchedule(cfg.Schedule)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.Lifecycle.Enabled {
+		// 生命周期规则应用失败（如 tier 未在 MinIO 中配置）不影响服务启动
+		if err := applyBucketLifecycle(context.Background(), minioClient, cfg.MinIO.BucketName, cfg.Lifecycle); err != nil {
+			log.Printf("Warning: %v", err)
 		}
 	}
 
 	log.Printf("Initializing MaterialService with Kafka writer...")
-	kafkaWriter := newFileProcessingKafkaWriter(cfg)
+	kafkaWriter := kafkaMetrics.InstrumentWriter("material-service", newFileProcessingKafkaWriter(cfg))
 	if kafkaWriter != nil {
 		log.Printf("Kafka writer initialized successfully")
 	} else {
 		log.Printf("Kafka writer is nil - not configured")
 	}
 
-	textExtractedKafkaWriter := newTextExtractedKafkaWriter(cfg)
+	textExtractedKafkaWriter := kafkaMetrics.InstrumentWriter("material-service", newTextExtractedKafkaWriter(cfg))
 	if textExtractedKafkaWriter != nil {
 		log.Printf("Text extracted Kafka writer initialized successfully")
 	} else {
 		log.Printf("Text extracted Kafka writer is nil - not configured")
 	}
 
+	ocrRequestsKafkaWriter := kafkaMetrics.InstrumentWriter("material-service", newOCRRequestsKafkaWriter(cfg))
+	if ocrRequestsKafkaWriter != nil {
+		log.Printf("OCR requests Kafka writer initialized successfully")
+	} else {
+		log.Printf("OCR requests Kafka writer is nil - not configured")
+	}
+
+	materialEventsKafkaWriter := kafkaMetrics.InstrumentWriter("material-service", newMaterialEventsKafkaWriter(cfg))
+	if materialEventsKafkaWriter != nil {
+		log.Printf("Material events Kafka writer initialized successfully")
+	} else {
+		log.Printf("Material events Kafka writer is nil - not configured")
+	}
+
+	taskEventsKafkaWriter := kafkaMetrics.InstrumentWriter("material-service", newTaskEventsKafkaWriter(cfg))
+	if taskEventsKafkaWriter != nil {
+		log.Printf("Task events Kafka writer initialized successfully")
+	} else {
+		log.Printf("Task events Kafka writer is nil - not configured")
+	}
+
+	notificationsKafkaWriter := kafkaMetrics.InstrumentWriter("material-service", newNotificationsKafkaWriter(cfg))
+
 	return &MaterialServiceImpl{
-		repo:                     repo,
-		processingRepo:           processingRepo,
-		minioClient:              minioClient,
-		config:                   cfg,
-		kafkaWriter:              kafkaWriter,
-		textExtractedKafkaWriter: textExtractedKafkaWriter,
+		repo:                      repo,
+		processingRepo:            processingRepo,
+		derivedRepo:               derivedRepo,
+		annotationRepo:            annotationRepo,
+		uploadSessionRepo:         uploadSessionRepo,
+		minioClient:               minioClient,
+		config:                    cfg,
+		kafkaWriter:               kafkaWriter,
+		textExtractedKafkaWriter:  textExtractedKafkaWriter,
+		ocrRequestsKafkaWriter:    ocrRequestsKafkaWriter,
+		materialEventsKafkaWriter: materialEventsKafkaWriter,
+		taskEventsKafkaWriter:     taskEventsKafkaWriter,
+		notificationsKafkaWriter:  notificationsKafkaWriter,
+		schedule:                  schedule,
+		sse:                       sse,
+		mediaProbeSlots:           make(chan struct{}, max(cfg.Media.ProbeWorkers, 1)),
 	}, nil
 }
 
-// newKafkaWriter creates a Kafka writer if brokers and topic are configured; otherwise returns nil.
-func newKafkaWriter(cfg *config.Config) *kafka.Writer {
-	brokers := strings.TrimSpace(cfg.Database.KafkaBrokers)
-	topic := strings.TrimSpace(cfg.Database.KafkaTopicOCRReqs)
-	if brokers == "" || topic == "" {
-		return nil
-	}
-	// support comma-separated brokers
-	var bs []string
-	for _, b := range strings.Split(brokers, ",") {
-		b = strings.TrimSpace(b)
-		if b != "" {
-			bs = append(bs, b)
-		}
+// newMinioClient 初始化 MinIO 客户端并确保存储桶存在
+func newMinioClient(cfg *config.Config) (*minio.Client, error) {
+	minioClient, err := minio.New(cfg.MinIO.Endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(cfg.MinIO.AccessKeyID, cfg.MinIO.SecretAccessKey, ""),
+		Secure: cfg.MinIO.UseSSL,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create MinIO client: %w", err)
 	}
-	if len(bs) == 0 {
-		return nil
+
+	// 确保存储桶存在
+	ctx := context.Background()
+	exists, err := minioClient.BucketExists(ctx, cfg.MinIO.BucketName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check bucket existence: %w", err)
 	}
-	return &kafka.Writer{
-		Addr:         kafka.TCP(bs...),
-		Topic:        topic,
-		Balancer:     &kafka.LeastBytes{},
-		RequiredAcks: kafka.RequireOne,
-		Compression:  kafka.Snappy,
+	if !exists {
+		err = minioClient.MakeBucket(ctx, cfg.MinIO.BucketName, minio.MakeBucketOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create bucket: %w", err)
+		}
 	}
+	return minioClient, nil
+}
+
+// newOCRRequestsKafkaWriter 创建 OCR 任务的 Kafka writer，未配置 brokers 或 topic 时返回 nil
+func newOCRRequestsKafkaWriter(cfg *config.Config) *kafka.Writer {
+	return newTopicKafkaWriter(cfg.Database.KafkaBrokers, cfg.Database.KafkaTopicOCRReqs)
 }
 
 // newFileProcessingKafkaWriter 创建文件处理的 Kafka writer
 func newFileProcessingKafkaWriter(cfg *config.Config) *kafka.Writer {
-	brokers := strings.TrimSpace(cfg.Database.KafkaBrokers)
-	topic := strings.TrimSpace(cfg.Database.KafkaTopicFileProcess)
-	log.Printf("Kafka config: brokers=%s, topic=%s", brokers, topic)
-	if brokers == "" || topic == "" {
+	log.Printf("Kafka config: brokers=%s, topic=%s", cfg.Database.KafkaBrokers, cfg.Database.KafkaTopicFileProcess)
+	w := newTopicKafkaWriter(cfg.Database.KafkaBrokers, cfg.Database.KafkaTopicFileProcess)
+	if w == nil {
 		log.Printf("Kafka writer not created: missing brokers or topic")
-		return nil
-	}
-	// support comma-separated brokers
-	var bs []string
-	for _, b := range strings.Split(brokers, ",") {
-		b = strings.TrimSpace(b)
-		if b != "" {
-			bs = append(bs, b)
-		}
-	}
-	if len(bs) == 0 {
-		return nil
-	}
-	return &kafka.Writer{
-		Addr:         kafka.TCP(bs...),
-		Topic:        topic,
-		Balancer:     &kafka.LeastBytes{},
-		RequiredAcks: kafka.RequireOne,
-		Compression:  kafka.Snappy,
 	}
+	return w
 }
 
 func newTextExtractedKafkaWriter(cfg *config.Config) *kafka.Writer {
-	brokers := strings.TrimSpace(cfg.Database.KafkaBrokers)
-	topic := strings.TrimSpace(cfg.Database.KafkaTopicTextExtracted)
-	if brokers == "" || topic == "" {
-		return nil
-	}
-	var bs []string
-	for _, b := range strings.Split(brokers, ",") {
-		b = strings.TrimSpace(b)
-		if b != "" {
-			bs = append(bs, b)
-		}
-	}
-	if len(bs) == 0 {
+	return newTopicKafkaWriter(cfg.Database.KafkaBrokers, cfg.Database.KafkaTopicTextExtracted)
+}
+
+// newTopicKafkaWriter 按 pkg/kafka 的约定创建 writer（以 material_id 为 key 分区），brokers 或 topic 为空时返回 nil
+func newTopicKafkaWriter(brokers, topic string) *kafka.Writer {
+	bs := arkkafka.SplitBrokers(brokers)
+	topic = strings.TrimSpace(topic)
+	if len(bs) == 0 || topic == "" {
 		return nil
 	}
-	return &kafka.Writer{
-		Addr:         kafka.TCP(bs...),
-		Topic:        topic,
-		Balancer:     &kafka.LeastBytes{},
-		RequiredAcks: kafka.RequireOne,
-		Compression:  kafka.Snappy,
-	}
+	return arkkafka.NewWriter(bs, topic)
 }
 
+// 流式上传时的分片大小，决定单次上传的内存占用
+const uploadPartSize = 16 << 20
+
 // ocrJob is the message schema sent to Kafka for OCR tasks.
 type ocrJob struct {
 	TaskID     string            `json:"task_id"`
@@ -185,13 +259,71 @@ type ocrJob struct {
 	Options    map[string]string `json:"options,omitempty"`
 }
 
-func (s *MaterialServiceImpl) UploadFile(userID uuid.UUID, title, originalFilename string, fileData []byte) (*models.Material, error) {
+// UploadFile 将 reader 中的数据流式写入 MinIO。size 未知时传 -1，
+// 此时按 uploadPartSize 分片上传，内存占用固定为单个分片大小。
+// 资料提交后立即返回，事件发布与处理派发在后台并行执行（见 upload_pipeline.go）；
+// 压缩包（.zip）上传后在后台展开为子资料，由各子资料分别触发处理。
+// 写入后按内容哈希（纯文本资料另按 SimHash）查找本人已有的重复资料：onDuplicate 为 link 且找到时
+// 放弃本次上传并返回已有资料，否则照常处理并在结果中附带重复的资料
+func (s *MaterialServiceImpl) UploadFile(userID uuid.UUID, title, originalFilename string, reader io.Reader, size int64, onDuplicate string) (*UploadResult, error) {
+	switch onDuplicate {
+	case "", DuplicateActionWarn, DuplicateActionLink:
+	default:
+		return nil, fmt.Errorf("invalid on_duplicate %q, must be %s or %s", onDuplicate, DuplicateActionWarn, DuplicateActionLink)
+	}
+	var opts storeOptions
+	if s.detectFileType(originalFilename) == FileTypeBundle {
+		opts.Status = MaterialStatusExpanding
+	}
+	material, err := s.storeFile(userID, title, originalFilename, reader, size, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &UploadResult{Material: material}
+	if s.config.Dedup.Enabled {
+		result.Duplicates, err = s.findDuplicates(material)
+		if err != nil {
+			log.Printf("Warning: failed to check duplicates of material %s: %v", material.ID, err)
+		}
+		if onDuplicate == DuplicateActionLink && len(result.Duplicates) > 0 {
+			linked, err := s.linkDuplicate(material, result.Duplicates[0])
+			if err != nil {
+				return nil, err
+			}
+			result.Material, result.Linked = linked, true
+			return result, nil
+		}
+	}
+	s.afterUpload(material)
+	return result, nil
+}
+
+// storeOptions 创建资料记录时的附加属性
+type storeOptions struct {
+	ParentID *uuid.UUID     // 非空时记录为该压缩包的子资料
+	FileType string         // 为空时按文件扩展名判断
+	Metadata datatypes.JSON // 资料元数据，如网页摘录的来源 URL
+	Status   string         // 写入成功后提交的状态，为空时为 success
+}
+
+// storeFile 创建资料记录并将文件写入 MinIO，成功后资料以 opts.Status 提交，写入时计算内容哈希，
+// 纯文本资料不超过 DEDUP_SIMHASH_UPLOAD_MB 时同时计算 SimHash。
+// 任一步失败时回滚已完成的步骤：写入失败中止分片上传，提交失败删除已写入的对象，资料均标记为 failed。
+// 提交后的事件与处理由调用方通过 afterUpload 发起
+func (s *MaterialServiceImpl) storeFile(userID uuid.UUID, title, originalFilename string, reader io.Reader, size int64, opts storeOptions) (*models.Material, error) {
+	// 资料记录的写入以上传者为操作人
+	repo := s.repo.WithContext(dbx.WithActor(context.Background(), userID.String()))
+
 	// 生成唯一的对象名
 	ext := filepath.Ext(originalFilename)
 	objectName := fmt.Sprintf("%s/%s%s", userID.String(), uuid.New().String(), ext)
 
 	// 检测文件类型
-	fileType := s.detectFileType(originalFilename)
+	fileType := opts.FileType
+	if fileType == "" {
+		fileType = s.detectFileType(originalFilename)
+	}
 
 	// 创建材料记录
 	material := &models.Material{
@@ -199,62 +331,107 @@ func (s *MaterialServiceImpl) UploadFile(userID uuid.UUID, title, originalFilena
 		Title:            title,
 		OriginalFilename: originalFilename,
 		FileType:         fileType,
-		SizeBytes:        int64(len(fileData)),
+		SizeBytes:        max(size, 0),
 		Status:           "uploading",
 		MinioBucket:      s.config.MinIO.BucketName,
 		MinioObjectName:  objectName,
+		Metadata:         opts.Metadata,
+		ParentID:         opts.ParentID,
 	}
 
 	// 先保存到数据库
-	if err := s.repo.Create(material); err != nil {
+	if err := observeStep(uploadStepRecord, func() error { return repo.Create(material) }); err != nil {
 		return nil, fmt.Errorf("failed to save material record: %w", err)
 	}
 
 	// 上传到 MinIO
+	hasher := sha256.New()
+	var sample *sampleBuffer
+	if s.config.Dedup.Enabled && isPlainTextType(fileType) {
+		sample = &sampleBuffer{limit: s.config.Dedup.SimHashUploadBytes}
+		reader = io.TeeReader(reader, io.MultiWriter(hasher, sample))
+	} else {
+		reader = io.TeeReader(reader, hasher)
+	}
 	ctx := context.Background()
-	reader := bytes.NewReader(fileData)
-
-	_, err := s.minioClient.PutObject(ctx, s.config.MinIO.BucketName, objectName, reader, int64(len(fileData)), minio.PutObjectOptions{
-		ContentType: s.getContentType(fileType),
+	var info minio.UploadInfo
+	err := observeStep(uploadStepStore, func() error {
+		var err error
+		info, err = s.minioClient.PutObject(ctx, s.config.MinIO.BucketName, objectName, reader, size, minio.PutObjectOptions{
+			ContentType:          s.getContentType(fileType),
+			PartSize:             uploadPartSize,
+			StorageClass:         s.storageClassFor(fileType, size),
+			ServerSideEncryption: s.sse,
+		})
+		return err
 	})
-
 	if err != nil {
-		// 上传失败，更新状态
-		s.repo.UpdateStatus(material.ID, "failed")
+		// 上传失败：中止未完成的分片上传并标记失败
+		metrics.MaterialUploadCompensations.WithLabelValues(uploadStepStore).Inc()
+		if rmErr := s.minioClient.RemoveIncompleteUpload(ctx, s.config.MinIO.BucketName, objectName); rmErr != nil {
+			log.Printf("Warning: failed to abort incomplete upload %s: %v", objectName, rmErr)
+		}
+		repo.UpdateStatus(material.ID, "failed")
 		return nil, fmt.Errorf("failed to upload file to MinIO: %w", err)
 	}
 
-	// 上传成功，更新状态
-	if err := s.repo.UpdateStatus(material.ID, "success"); err != nil {
+	// 上传成功，回填实际大小与内容指纹并提交状态
+	material.SizeBytes = info.Size
+	material.ContentHash = hex.EncodeToString(hasher.Sum(nil))
+	if sample != nil && !sample.truncated {
+		if text, err := extractMaterialText(fileType, sample.buf.Bytes()); err == nil {
+			if hash, ok := simHash(text); ok {
+				material.SimHash = &hash
+			}
+		}
+	}
+	material.Status = opts.Status
+	if material.Status == "" {
+		material.Status = "success"
+	}
+	if err := observeStep(uploadStepCommit, func() error { return repo.Update(material) }); err != nil {
+		// 提交失败：删除已写入的对象，避免留下无法访问的资料
+		metrics.MaterialUploadCompensations.WithLabelValues(uploadStepCommit).Inc()
+		if rmErr := s.minioClient.RemoveObject(ctx, s.config.MinIO.BucketName, objectName, minio.RemoveObjectOptions{}); rmErr != nil {
+			log.Printf("Warning: failed to remove object %s after commit failure: %v", objectName, rmErr)
+		}
+		repo.UpdateStatus(material.ID, "failed")
 		return nil, fmt.Errorf("failed to update material status: %w", err)
 	}
+	return material, nil
+}
 
-	material.Status = "success"
-
-	// 发送 Kafka 消息给 llm-service 进行文档处理
-	log.Printf("Attempting to send file processing message for material %s", material.ID.String())
-	if material.FileType == "pdf" || material.FileType == "document" || material.FileType == "image" {
+// dispatchUploadProcessing 按文件类型发起上传后的处理。Kafka 未配置时返回 errStepSkipped
+func (s *MaterialServiceImpl) dispatchUploadProcessing(material *models.Material) error {
+	userID := material.UserID
+	// docx/pptx 同样交给 ocr-service，由其直接提取文字层，扫描内容才回退 OCR
+	if material.FileType == "pdf" || material.FileType == "document" || material.FileType == "presentation" || material.FileType == "image" {
+		if s.kafkaWriter == nil {
+			return errStepSkipped
+		}
 		if err := s.sendOcrRequestMessage(material, userID); err != nil {
-			log.Printf("Warning: failed to send ocr request message: %v", err)
-		} else {
-			log.Printf("Successfully sent ocr request message for material %s", material.ID.String())
+			return fmt.Errorf("send ocr request message: %w", err)
+		}
+		log.Printf("Successfully sent ocr request message for material %s", material.ID.String())
+	} else if material.FileType == "text" || material.FileType == "html" || material.FileType == "epub" || material.FileType == FileTypeClip {
+		if s.textExtractedKafkaWriter == nil {
+			return errStepSkipped
 		}
-	} else if material.FileType == "text" {
 		if err := s.sendTextExtractedMessage(material, userID); err != nil {
-			log.Printf("Warning: failed to send text extracted message: %v", err)
-		} else {
-			log.Printf("Successfully sent text extracted message for material %s", material.ID.String())
+			return fmt.Errorf("send text extracted message: %w", err)
 		}
+		log.Printf("Successfully sent text extracted message for material %s", material.ID.String())
 	} else {
+		if s.kafkaWriter == nil {
+			return errStepSkipped
+		}
+		// 发送 Kafka 消息给 llm-service 进行文档处理
 		if err := s.sendFileProcessingMessage(material, userID); err != nil {
-			// 记录错误但不影响上传成功
-			log.Printf("Warning: failed to send file processing message: %v", err)
-		} else {
-			log.Printf("Successfully sent file processing message for material %s", material.ID.String())
+			return fmt.Errorf("send file processing message: %w", err)
 		}
+		log.Printf("Successfully sent file processing message for material %s", material.ID.String())
 	}
-
-	return material, nil
+	return nil
 }
 
 func (s *MaterialServiceImpl) GetByID(id uuid.UUID) (*models.Material, error) {
@@ -266,7 +443,13 @@ func (s *MaterialServiceImpl) GetByUserIDWithPagination(userID uuid.UUID, page,
 }
 
 func (s *MaterialServiceImpl) UpdateStatus(id uuid.UUID, status string) error {
-	return s.repo.UpdateStatus(id, status)
+	if err := s.repo.UpdateStatus(id, status); err != nil {
+		return err
+	}
+	if material, err := s.repo.GetByID(id); err == nil {
+		s.publishMaterialEvent(EventMaterialUpdated, material, map[string]interface{}{"status": status})
+	}
+	return nil
 }
 
 func (s *MaterialServiceImpl) Delete(id uuid.UUID) error {
@@ -276,17 +459,42 @@ func (s *MaterialServiceImpl) Delete(id uuid.UUID) error {
 		return fmt.Errorf("failed to get material: %w", err)
 	}
 
-	// 从 MinIO 删除文件
+	// 删除压缩包时一并删除其子资料
+	if material.FileType == FileTypeBundle {
+		children, err := s.repo.GetByParentID(id)
+		if err != nil {
+			return fmt.Errorf("failed to list bundle children: %w", err)
+		}
+		for _, child := range children {
+			if err := s.Delete(child.ID); err != nil {
+				return fmt.Errorf("failed to delete bundle child %s: %w", child.ID, err)
+			}
+		}
+	}
+
+	// 从 MinIO 删除文件；启用回收站时只打标签，由生命周期规则到期删除
 	ctx := context.Background()
-	err = s.minioClient.RemoveObject(ctx, material.MinioBucket, material.MinioObjectName, minio.RemoveObjectOptions{})
-	if err != nil {
-		return fmt.Errorf("failed to remove file from MinIO: %w", err)
+	if s.trashEnabled() {
+		if err := s.moveToTrash(ctx, material.MinioBucket, material.MinioObjectName); err != nil {
+			return fmt.Errorf("failed to move file to trash: %w", err)
+		}
+	} else {
+		err = s.minioClient.RemoveObject(ctx, material.MinioBucket, material.MinioObjectName, minio.RemoveObjectOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to remove file from MinIO: %w", err)
+		}
 	}
 
 	// 从数据库删除记录
-	return s.repo.Delete(id)
+	if err := s.repo.Delete(id); err != nil {
+		return err
+	}
+	s.publishMaterialEvent(EventMaterialDeleted, material, nil)
+	return nil
 }
 
+// GetFileURL 生成对象的预签名下载地址。预签名使用 SigV4（SSE-KMS 对象要求），
+// SSE-S3 / SSE-KMS 对象由服务端解密，URL 中无需携带加密参数
 func (s *MaterialServiceImpl) GetFileURL(material *models.Material, expiry time.Duration) (string, error) {
 	ctx := context.Background()
 	url, err := s.minioClient.PresignedGetObject(ctx, material.MinioBucket, material.MinioObjectName, expiry, nil)
@@ -296,6 +504,57 @@ func (s *MaterialServiceImpl) GetFileURL(material *models.Material, expiry time.
 	return url.String(), nil
 }
 
+// 音视频扩展名对应的 MIME 类型，不依赖系统 mime.types
+var mediaContentTypes = map[string]string{
+	".mp4":  "video/mp4",
+	".mov":  "video/quicktime",
+	".avi":  "video/x-msvideo",
+	".webm": "video/webm",
+	".mkv":  "video/x-matroska",
+	".mp3":  "audio/mpeg",
+	".wav":  "audio/wav",
+	".flac": "audio/flac",
+	".m4a":  "audio/mp4",
+}
+
+// isPlayableMedia 是否为可内联播放的音视频类型
+func isPlayableMedia(contentType string) bool {
+	return strings.HasPrefix(contentType, "audio/") || strings.HasPrefix(contentType, "video/")
+}
+
+// GetMediaURL 生成用于在线播放/预览的预签名 URL，并返回文件的具体 MIME 类型。
+// S3 预签名 GET 原生支持 Range 请求；这里覆盖响应头，使浏览器按正确类型内联播放音视频而不是下载。
+// 其他类型（如 html、svg）内联打开时会作为页面渲染并执行脚本，一律以附件下载
+func (s *MaterialServiceImpl) GetMediaURL(material *models.Material, expiry time.Duration) (string, string, error) {
+	ext := strings.ToLower(filepath.Ext(material.OriginalFilename))
+	contentType, ok := mediaContentTypes[ext]
+	if !ok {
+		contentType = mime.TypeByExtension(ext)
+	}
+	if contentType == "" {
+		contentType = s.getContentType(material.FileType)
+	}
+	if strings.HasSuffix(contentType, "/*") {
+		// 通配类型无法被播放器识别
+		contentType = "application/octet-stream"
+	}
+
+	params := url.Values{}
+	params.Set("response-content-type", contentType)
+	disposition := "attachment"
+	if isPlayableMedia(contentType) {
+		disposition = "inline"
+	}
+	params.Set("response-content-disposition", mime.FormatMediaType(disposition, map[string]string{"filename": material.OriginalFilename}))
+
+	ctx := context.Background()
+	u, err := s.minioClient.PresignedGetObject(ctx, material.MinioBucket, material.MinioObjectName, expiry, params)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to generate presigned URL: %w", err)
+	}
+	return u.String(), contentType, nil
+}
+
 // 辅助方法：检测文件类型
 func (s *MaterialServiceImpl) detectFileType(filename string) string {
 	ext := strings.ToLower(filepath.Ext(filename))
@@ -304,6 +563,8 @@ func (s *MaterialServiceImpl) detectFileType(filename string) string {
 		return "pdf"
 	case ".doc", ".docx":
 		return "document"
+	case ".ppt", ".pptx":
+		return "presentation"
 	case ".jpg", ".jpeg", ".png", ".gif":
 		return "image"
 	case ".mp4", ".avi", ".mov":
@@ -312,6 +573,12 @@ func (s *MaterialServiceImpl) detectFileType(filename string) string {
 		return "audio"
 	case ".txt":
 		return "text"
+	case ".html", ".htm", ".xhtml":
+		return "html"
+	case ".epub":
+		return "epub"
+	case ".zip":
+		return FileTypeBundle
 	default:
 		return "other"
 	}
@@ -324,6 +591,8 @@ func (s *MaterialServiceImpl) getContentType(fileType string) string {
 		return "application/pdf"
 	case "document":
 		return "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+	case "presentation":
+		return "application/vnd.openxmlformats-officedocument.presentationml.presentation"
 	case "image":
 		return "image/*"
 	case "video":
@@ -332,6 +601,14 @@ func (s *MaterialServiceImpl) getContentType(fileType string) string {
 		return "audio/*"
 	case "text":
 		return "text/plain"
+	case "html":
+		return "text/html"
+	case "epub":
+		return "application/epub+zip"
+	case FileTypeBundle:
+		return "application/zip"
+	case FileTypeClip:
+		return "text/plain; charset=utf-8"
 	default:
 		return "application/octet-stream"
 	}
@@ -339,7 +616,7 @@ func (s *MaterialServiceImpl) getContentType(fileType string) string {
 
 // ======================= AI 处理相关方法 =======================
 
-func (s *MaterialServiceImpl) ProcessMaterial(materialID uuid.UUID, userID uuid.UUID, processType string, options map[string]string) (*models.ProcessingResult, error) {
+func (s *MaterialServiceImpl) ProcessMaterial(ctx context.Context, materialID uuid.UUID, userID uuid.UUID, processType string, options map[string]string) (*models.ProcessingResult, error) {
 	// 1. 验证材料存在且属于该用户
 	material, err := s.repo.GetByID(materialID)
 	if err != nil {
@@ -349,41 +626,108 @@ func (s *MaterialServiceImpl) ProcessMaterial(materialID uuid.UUID, userID uuid.
 		return nil, fmt.Errorf("permission denied: material does not belong to user")
 	}
 
-	// 2. 检查是否已有相同类型的处理结果
+	// 对比处理（shadow=true）总是新建记录，结果只用于与其他结果对比，见 CompareProcessingResults
+	shadow := options[models.ProcessingOptionShadow] == "true"
+	if shadow && processType != models.ProcessingTypeOCR {
+		return nil, fmt.Errorf("shadow processing is only supported for OCR")
+	}
+	callerDriven := options[models.ProcessingOptionCallerDriven] == "true"
+	if callerDriven && processType != models.ProcessingTypeASR {
+		return nil, fmt.Errorf("caller-driven processing is only supported for ASR")
+	}
+	if callerDriven {
+		// 开关只对本次登记有效，不保存到处理选项，重试时仍由 material-service 派发
+		options = maps.Clone(options)
+		delete(options, models.ProcessingOptionCallerDriven)
+	}
+
+	// 2. 检查是否已有相同类型的处理结果，force=true 时重新处理（如更换了识别引擎），
+	// 完成后已有的派生内容会被标记为 stale
 	existingResult, err := s.processingRepo.GetByMaterialIDAndType(materialID, processType)
-	if err == nil && existingResult.Status == models.ProcessingStatusCompleted {
+	if err == nil && existingResult.Status == models.ProcessingStatusCompleted && options["force"] != "true" && !shadow {
 		return existingResult, nil // 返回已有的结果
 	}
+	// 已在排队或等待处理时段的任务不重复登记
+	if err == nil && (existingResult.Status == models.ProcessingStatusQueued || existingResult.Status == models.ProcessingStatusScheduled) && !shadow {
+		return existingResult, nil
+	}
+
+	// 时段外提交的大任务延后到处理时段；其余任务超出用户并发上限时，OCR 进入排队，
+	// ASR 由 asr-service 同步转写，超出时直接拒绝
+	status := models.ProcessingStatusScheduled
+	scheduledFor := s.scheduleProcessing(material, processType)
+	if scheduledFor == nil {
+		if status, err = s.admitProcessing(userID, processType, processType != models.ProcessingTypeASR); err != nil {
+			return nil, err
+		}
+	}
 
 	// 3. 生成任务ID
 	taskID := uuid.New().String()
 
-	// 4. 创建处理记录
+	// 4. 创建处理记录（保存处理选项，供重试时复用）
 	result := &models.ProcessingResult{
-		MaterialID: materialID,
-		TaskID:     taskID,
-		Type:       processType,
-		Status:     models.ProcessingStatusPending,
+		MaterialID:   materialID,
+		TaskID:       taskID,
+		Type:         processType,
+		Status:       status,
+		Metadata:     initialProcessingMetadata(material),
+		Shadow:       shadow,
+		ScheduledFor: scheduledFor,
+	}
+	if len(options) > 0 {
+		if data, err := json.Marshal(options); err == nil {
+			result.Options = datatypes.JSON(data)
+		}
 	}
 
 	if err := s.processingRepo.Create(result); err != nil {
 		return nil, fmt.Errorf("failed to create processing record: %w", err)
 	}
+	s.publishTaskEvent(taskID)
 
-	// 5. 触发异步处理
-	if processType == models.ProcessingTypeOCR && s.textExtractedKafkaWriter != nil {
-		// 5.1 生成短期下载 URL
-		urlStr, err := s.GetFileURL(material, 15*time.Minute)
-		if err != nil {
-			_ = s.UpdateProcessingResult(result.TaskID, models.ProcessingStatusFailed, "", nil, fmt.Sprintf("presign: %v", err))
-			return result, nil
+	// 5. 触发异步处理，排队的任务在用户已有任务结束后由 DispatchQueued 派发，延后的任务由 Scheduler 在处理时段转入排队
+	switch status {
+	case models.ProcessingStatusQueued:
+		log.Printf("Processing task %s of user %s queued: per-user limit reached", taskID, userID)
+	case models.ProcessingStatusScheduled:
+		log.Printf("Processing task %s of user %s scheduled for %s", taskID, userID, scheduledFor.Format(time.RFC3339))
+	default:
+		if callerDriven {
+			log.Printf("Processing task %s of user %s registered for caller-driven ASR", taskID, userID)
+			break
 		}
-		// 5.2 发送 Kafka 消息
+		s.dispatchProcessing(ctx, material, result, options)
+	}
+	// 上传后派发失败的资料由本次处理接替
+	if material.Status == MaterialStatusDispatchFailed && !shadow {
+		if err := s.UpdateStatus(materialID, "success"); err != nil {
+			log.Printf("Warning: failed to reset status of material %s: %v", materialID, err)
+		}
+	}
+	return result, nil
+}
+
+// dispatchProcessing 派发处理任务：OCR 优先走 Kafka，否则回退到内部同步编排。
+// ctx 只用于传递请求 ID，派发不随调用方取消
+func (s *MaterialServiceImpl) dispatchProcessing(ctx context.Context, material *models.Material, result *models.ProcessingResult, options map[string]string) {
+	taskID := result.TaskID
+	materialID := material.ID
+	userID := material.UserID
+	processType := result.Type
+	if processType == models.ProcessingTypeOCR {
+		options = withOCRLanguageHint(material, options)
+	}
+
+	useKafka := featureflags.Enabled(featureflags.KafkaOCRPath, true)
+	if processType == models.ProcessingTypeOCR && useKafka && s.ocrRequestsKafkaWriter != nil {
+		// 发送对象引用而非预签名 URL：任务可能在队列中等待或重试超过 URL 有效期，
+		// 由 ocr-service 在读取时签发凭证
 		job := ocrJob{
 			TaskID:     taskID,
 			MaterialID: materialID.String(),
 			UserID:     userID.String(),
-			FileURL:    urlStr,
+			FileURL:    objectRef(material),
 			FileType:   material.FileType,
 			Options:    options,
 		}
@@ -391,23 +735,23 @@ func (s *MaterialServiceImpl) ProcessMaterial(materialID uuid.UUID, userID uuid.
 		payload, err := json.Marshal(job)
 		if err != nil {
 			_ = s.UpdateProcessingResult(result.TaskID, models.ProcessingStatusFailed, "", nil, fmt.Sprintf("marshal job: %v", err))
-			return result, nil
+			return
 		}
-		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 10*time.Second)
 		defer cancel()
-		err = s.kafkaWriter.WriteMessages(ctx, kafka.Message{Value: payload})
+		// 以 material_id 为 key，同一资料的任务进入同一分区按序处理
+		err = kafkaMetrics.WriteMessages(ctx, s.ocrRequestsKafkaWriter, kafka.Message{Key: arkkafka.MaterialKey(materialID.String()), Value: payload})
 		if err != nil {
 			_ = s.UpdateProcessingResult(result.TaskID, models.ProcessingStatusFailed, "", nil, fmt.Sprintf("kafka publish: %v", err))
-			return result, nil
+			return
 		}
 		// 更新状态为 processing，等待 ocr-service 回调
-		_ = s.UpdateProcessingResult(result.TaskID, models.ProcessingStatusProcessing, "", map[string]interface{}{"dispatched": true}, "")
-		return result, nil
+		_ = s.UpdateProcessingResult(result.TaskID, models.ProcessingStatusProcessing, "", stampStage(map[string]interface{}{"dispatched": true}, models.ProcessingStageDispatched, time.Now()), "")
+		return
 	}
 
 	// 回退：直接调用内部同步编排（gRPC 轮询）
 	go s.callAIService(material, result, processType, options)
-	return result, nil
 }
 
 func (s *MaterialServiceImpl) GetProcessingResult(materialID uuid.UUID, processType string) (*models.ProcessingResult, error) {
@@ -424,29 +768,243 @@ func (s *MaterialServiceImpl) UpdateProcessingResult(taskID string, status strin
 		"content": content,
 	}
 
+	// metadata 整体替换，已记录的阶段时间戳需要带入本次更新；处理结束时记录 finished 阶段
+	finished := status == models.ProcessingStatusCompleted || status == models.ProcessingStatusFailed
+	var processType string
+	var shadow bool
+	var materialID, userID uuid.UUID
+	if metadata != nil || finished {
+		if prev, err := s.processingRepo.GetByTaskID(taskID); err == nil {
+			processType, shadow, materialID, userID = prev.Type, prev.Shadow, prev.MaterialID, prev.Material.UserID
+			prevMetadata := decodeMetadata(prev.Metadata)
+			if metadata == nil {
+				metadata = prevMetadata
+			} else {
+				carryStages(prevMetadata, metadata)
+			}
+		}
+		if finished {
+			metadata = stampStage(metadata, models.ProcessingStageFinished, time.Now())
+		}
+	}
+
 	if metadata != nil {
-		updates["metadata"] = metadata
+		data, err := json.Marshal(metadata)
+		if err != nil {
+			return fmt.Errorf("failed to marshal metadata: %w", err)
+		}
+		updates["metadata"] = datatypes.JSON(data)
 	}
 
 	if errorMessage != "" {
 		updates["error_message"] = errorMessage
 	}
 
-	return s.processingRepo.UpdateByTaskID(taskID, updates)
+	if status == models.ProcessingStatusCompleted {
+		updates["progress"] = 1
+	}
+
+	if err := s.processingRepo.UpdateByTaskID(taskID, updates); err != nil {
+		return err
+	}
+	s.publishTaskEvent(taskID)
+
+	// 处理完成或失败意味着资料的派生内容发生变化，通知下游
+	if finished {
+		if processType != "" {
+			observeProcessingLatency(processType, status, metadata)
+		}
+		// 任务结束空出名额，派发该用户排队中的任务
+		if isLimitedProcessingType(processType) && userID != uuid.Nil {
+			go s.DispatchQueued(userID)
+		}
+		// 对比处理的结果不改变资料内容，无需更新派生内容或通知下游
+		if shadow {
+			return nil
+		}
+		var staleKinds []string
+		if status == models.ProcessingStatusCompleted {
+			// 识别或转写出的文本决定资料语言，供分片、出题与后续识别使用
+			if content != "" && materialID != uuid.Nil && (processType == models.ProcessingTypeOCR || processType == models.ProcessingTypeASR) {
+				if material, err := s.repo.GetByID(materialID); err == nil {
+					s.indexExtractedText(material, content)
+				}
+			}
+			staleKinds = s.trackDerivedArtifacts(taskID)
+		}
+		s.publishProcessingUpdate(taskID, status, staleKinds)
+	}
+	return nil
+}
+
+// UpdateProgress 只更新处理进度，不改变状态与内容
+func (s *MaterialServiceImpl) UpdateProgress(taskID string, progress float32) error {
+	if progress < 0 {
+		progress = 0
+	}
+	if progress > 1 {
+		progress = 1
+	}
+	if err := s.processingRepo.UpdateByTaskID(taskID, map[string]interface{}{"progress": progress}); err != nil {
+		return err
+	}
+	s.publishTaskEvent(taskID)
+	return nil
+}
+
+// publishProcessingUpdate 根据任务 ID 找到资料并发布 material.updated 事件，
+// staleKinds 为本次处理导致过期的派生内容类型
+func (s *MaterialServiceImpl) publishProcessingUpdate(taskID, status string, staleKinds []string) {
+	if s.materialEventsKafkaWriter == nil {
+		return
+	}
+	result, err := s.processingRepo.GetByTaskID(taskID)
+	if err != nil {
+		return
+	}
+	material, err := s.repo.GetByID(result.MaterialID)
+	if err != nil {
+		return
+	}
+	changes := map[string]interface{}{
+		"processing_task_id": taskID,
+		"processing_type":    result.Type,
+		"processing_status":  status,
+	}
+	if len(staleKinds) > 0 {
+		changes["derived_stale"] = staleKinds
+	}
+	s.publishMaterialEvent(EventMaterialUpdated, material, changes)
+}
+
+// RetryProcessingTask 重置失败的处理任务并以原选项重新派发，任务 ID 保持不变，重试次数加一
+func (s *MaterialServiceImpl) RetryProcessingTask(ctx context.Context, taskID string, userID uuid.UUID) (*models.ProcessingResult, error) {
+	result, err := s.processingRepo.GetByTaskID(taskID)
+	if err != nil {
+		return nil, fmt.Errorf("task not found: %w", err)
+	}
+	material, err := s.repo.GetByID(result.MaterialID)
+	if err != nil {
+		return nil, fmt.Errorf("material not found: %w", err)
+	}
+	if material.UserID != userID {
+		return nil, fmt.Errorf("permission denied: material does not belong to user")
+	}
+	if result.Status != models.ProcessingStatusFailed {
+		return nil, fmt.Errorf("only failed tasks can be retried, current status: %s", result.Status)
+	}
+
+	var options map[string]string
+	if len(result.Options) > 0 {
+		if err := json.Unmarshal(result.Options, &options); err != nil {
+			return nil, fmt.Errorf("failed to decode task options: %w", err)
+		}
+	}
+
+	// 与新提交的任务相同：时段外的大任务延后，超出用户并发上限时进入排队
+	status := models.ProcessingStatusScheduled
+	scheduledFor := s.scheduleProcessing(material, result.Type)
+	if scheduledFor == nil {
+		if status, err = s.admitProcessing(userID, result.Type, true); err != nil {
+			return nil, err
+		}
+	}
+
+	// 重置为 pending（或 queued / scheduled），清空上次的输出与错误
+	err = s.processingRepo.UpdateByTaskID(taskID, map[string]interface{}{
+		"status":        status,
+		"scheduled_for": scheduledFor,
+		"content":       "",
+		"metadata":      initialProcessingMetadata(material),
+		"error_message": "",
+		"progress":      0,
+		"retry_count":   gorm.Expr("retry_count + 1"),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to reset task: %w", err)
+	}
+	s.publishTaskEvent(taskID)
+	result, err = s.processingRepo.GetByTaskID(taskID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reload task: %w", err)
+	}
+
+	if status == models.ProcessingStatusQueued || status == models.ProcessingStatusScheduled {
+		log.Printf("Retry of task %s deferred: status=%s", taskID, status)
+	} else if result.Type == models.ProcessingTypeASR {
+		// ASR 由 asr-service 驱动，带上原任务 ID 重新发起转写
+		go s.handleASR(material, result)
+	} else {
+		s.dispatchProcessing(ctx, material, result, options)
+	}
+	return result, nil
+}
+
+// handleASR 让 asr-service 以该任务 ID 转写，结果由 asr-service 通过 UpdateProcessingResult 回调。
+// asr-service 在登记任务前拒绝请求或调用失败时没有回调，由此处把任务标记为失败；
+// 转写成功但回调丢失时以返回的分段补记完成，避免任务停留在 processing
+func (s *MaterialServiceImpl) handleASR(material *models.Material, result *models.ProcessingResult) {
+	urlStr, err := s.GetFileURL(material, time.Hour)
+	if err != nil {
+		_ = s.UpdateProcessingResult(result.TaskID, models.ProcessingStatusFailed, "", nil, fmt.Sprintf("presign: %v", err))
+		return
+	}
+
+	addr := s.config.Database.ASRGRPCAddr
+	conn, err := discovery.DialAddr(registry.ASR.Name, addr)
+	if err != nil {
+		_ = s.UpdateProcessingResult(result.TaskID, models.ProcessingStatusFailed, "", nil, fmt.Sprintf("dial asr: %v", err))
+		return
+	}
+	defer conn.Close()
+
+	// 转写是同步调用，超时需覆盖下载、ffmpeg 与识别的总时长
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	defer cancel()
+	resp, err := asrpb.NewASRServiceClient(conn).ProcessVideo(ctx, &asrpb.ProcessVideoRequest{
+		MaterialId: material.ID.String(),
+		UserId:     material.UserID.String(),
+		VideoUrl:   urlStr,
+		TaskId:     result.TaskID,
+	})
+	current, getErr := s.processingRepo.GetByTaskID(result.TaskID)
+	if getErr != nil {
+		log.Printf("Warning: failed to reload ASR task %s: %v", result.TaskID, getErr)
+		return
+	}
+	// 已由 asr-service 回调结束，或被延后到处理时段
+	if current.Status != models.ProcessingStatusProcessing {
+		return
+	}
+	switch {
+	case err != nil:
+		_ = s.UpdateProcessingResult(result.TaskID, models.ProcessingStatusFailed, "", nil, fmt.Sprintf("process asr: %v", err))
+	case !resp.Success:
+		_ = s.UpdateProcessingResult(result.TaskID, models.ProcessingStatusFailed, "", nil, resp.Message)
+	default:
+		texts := make([]string, 0, len(resp.Segments))
+		for _, seg := range resp.Segments {
+			if text := strings.TrimSpace(seg.Text); text != "" {
+				texts = append(texts, text)
+			}
+		}
+		log.Printf("ASR task %s finished without a callback, recording %d segments", result.TaskID, len(resp.Segments))
+		_ = s.UpdateProcessingResult(result.TaskID, models.ProcessingStatusCompleted, strings.Join(texts, "\n"), map[string]interface{}{"segment_count": len(resp.Segments)}, "")
+	}
 }
 
 // callAIService 异步调用AI服务 (占位符，后续实现)
 func (s *MaterialServiceImpl) callAIService(material *models.Material, result *models.ProcessingResult, processType string, options map[string]string) {
 	// 更新处理状态为 processing
-	_ = s.UpdateProcessingResult(result.TaskID, models.ProcessingStatusProcessing, "", nil, "")
+	_ = s.UpdateProcessingResult(result.TaskID, models.ProcessingStatusProcessing, "", stampStage(nil, models.ProcessingStageDispatched, time.Now()), "")
 
 	switch processType {
 	case models.ProcessingTypeOCR:
-		s.handleOCR(material, result)
+		s.handleOCR(material, result, options)
 		return
 	case models.ProcessingTypeASR:
-		// 预留：交给独立 asr-service
-		_ = s.UpdateProcessingResult(result.TaskID, models.ProcessingStatusFailed, "", nil, "ASR handled by asr-service")
+		// 由独立的 asr-service 转写并通过 UpdateProcessingResult 回调
+		s.handleASR(material, result)
 		return
 	default:
 		_ = s.UpdateProcessingResult(result.TaskID, models.ProcessingStatusFailed, "", nil, "unsupported processing type")
@@ -454,20 +1012,15 @@ func (s *MaterialServiceImpl) callAIService(material *models.Material, result *m
 	}
 }
 
-func (s *MaterialServiceImpl) handleOCR(material *models.Material, result *models.ProcessingResult) {
-	// 1) 生成短期下载 URL
-	urlStr, err := s.GetFileURL(material, 15*time.Minute)
-	if err != nil {
-		_ = s.UpdateProcessingResult(result.TaskID, models.ProcessingStatusFailed, "", nil, fmt.Sprintf("presign: %v", err))
-		return
-	}
+// objectRef 资料原始文件的对象引用（s3://bucket/key），供下游服务自行读取
+func objectRef(material *models.Material) string {
+	return fmt.Sprintf("s3://%s/%s", material.MinioBucket, material.MinioObjectName)
+}
 
-	// 2) 连接 ocr-service
+func (s *MaterialServiceImpl) handleOCR(material *models.Material, result *models.ProcessingResult, options map[string]string) {
+	// 1) 连接 ocr-service
 	addr := s.config.Database.OCRGRPCAddr
-	if addr == "" {
-		addr = "localhost:50055"
-	}
-	conn, err := grpc.Dial(addr, grpc.WithInsecure())
+	conn, err := discovery.DialAddr(registry.OCR.Name, addr)
 	if err != nil {
 		_ = s.UpdateProcessingResult(result.TaskID, models.ProcessingStatusFailed, "", nil, fmt.Sprintf("dial ocr: %v", err))
 		return
@@ -475,18 +1028,21 @@ func (s *MaterialServiceImpl) handleOCR(material *models.Material, result *model
 	defer conn.Close()
 	ocr := aipb.NewAIServiceClient(conn)
 
-	// 3) 发起 OCR 任务
+	// 2) 发起 OCR 任务，传递对象引用，由 ocr-service 在读取时签发凭证
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
-	_, err = ocr.ProcessOCR(ctx, &aipb.OCRRequest{TaskId: result.TaskID, FileUrl: urlStr, FileType: material.FileType})
+	_, err = ocr.ProcessOCR(ctx, &aipb.OCRRequest{TaskId: result.TaskID, FileUrl: objectRef(material), FileType: material.FileType, Options: options})
 	if err != nil {
 		_ = s.UpdateProcessingResult(result.TaskID, models.ProcessingStatusFailed, "", nil, fmt.Sprintf("process ocr: %v", err))
 		return
 	}
+	stages := stampStage(nil, models.ProcessingStageStarted, time.Now())
 
-	// 4) 轮询任务状态
+	// 3) 轮询任务状态
 	deadline := time.Now().Add(10 * time.Minute)
 	var finalText string
+	var confidence float32
+	var lastProgress float32
 	for time.Now().Before(deadline) {
 		stx, cancel2 := context.WithTimeout(context.Background(), 5*time.Second)
 		status, err := ocr.GetTaskStatus(stx, &aipb.TaskStatusRequest{TaskId: result.TaskID})
@@ -495,6 +1051,10 @@ func (s *MaterialServiceImpl) handleOCR(material *models.Material, result *model
 			time.Sleep(2 * time.Second)
 			continue
 		}
+		if status.Status == aipb.TaskStatus_PROCESSING && status.Progress > lastProgress {
+			lastProgress = status.Progress
+			_ = s.UpdateProgress(result.TaskID, lastProgress)
+		}
 		if status.Status == aipb.TaskStatus_COMPLETED {
 			// 再拉取一次最终结果（复用 ProcessOCR 返回完成结果的能力）
 			rctx, cancel3 := context.WithTimeout(context.Background(), 10*time.Second)
@@ -504,7 +1064,8 @@ func (s *MaterialServiceImpl) handleOCR(material *models.Material, result *model
 				_ = s.UpdateProcessingResult(result.TaskID, models.ProcessingStatusFailed, "", nil, fmt.Sprintf("fetch ocr result: %v", err))
 				return
 			}
-			finalText = resp.GetText()
+			finalText, confidence = resp.GetText(), resp.GetConfidence()
+			stampStage(stages, models.ProcessingStageRecognized, time.Now())
 			break
 		}
 		if status.Status == aipb.TaskStatus_FAILED {
@@ -518,64 +1079,139 @@ func (s *MaterialServiceImpl) handleOCR(material *models.Material, result *model
 		return
 	}
 
-	// 5) 将 OCR 文本拆分为 chunk（简单策略：按换行 / 500 字一段）并入库向量
+	// 识别引擎与置信度，用于对比不同引擎的结果；未指定引擎时为 ocr-service 的默认引擎
+	if engine := options["engine"]; engine != "" {
+		stages["engine"] = engine
+	}
+	stages["confidence"] = confidence
+	if result.Shadow {
+		_ = s.UpdateProcessingResult(result.TaskID, models.ProcessingStatusCompleted, finalText, stages, "")
+		return
+	}
+
+	// 4) 将 OCR 文本拆分为 chunk（简单策略：按换行 / 500 字一段）并入库向量，分片标注检测到的语言
+	s.indexExtractedText(material, finalText)
 	chunks := splitTextToChunks(finalText)
 	if len(chunks) == 0 {
 		_ = s.UpdateProcessingResult(result.TaskID, models.ProcessingStatusFailed, "", nil, "no chunks")
 		return
 	}
 
-	llmAddr := s.config.Database.LLMGRPCAddr
-	if llmAddr == "" {
-		llmAddr = "localhost:50054"
-	}
-	lconn, err := grpc.Dial(llmAddr, grpc.WithInsecure())
+	inserted, err := s.upsertTextChunks(material, chunks, result.TaskID, false)
 	if err != nil {
-		_ = s.UpdateProcessingResult(result.TaskID, models.ProcessingStatusFailed, "", nil, fmt.Sprintf("dial llm: %v", err))
+		_ = s.UpdateProcessingResult(result.TaskID, models.ProcessingStatusFailed, "", stages, err.Error())
 		return
 	}
+	stages["chunks"] = inserted
+	stampStage(stages, models.ProcessingStageEmbedded, time.Now())
+	// 保存识别文本，资料重新处理或派生内容重建时据此重新切分
+	_ = s.UpdateProcessingResult(result.TaskID, models.ProcessingStatusCompleted, finalText, stages, "")
+}
+
+// 分片来源类型，写入分片元数据 source_type
+const (
+	ChunkSourceOCR  = "ocr"
+	ChunkSourceASR  = "asr"
+	ChunkSourceText = "text"
+)
+
+// chunkSourceType 按资料类型判断分片文本来源：文本与 Office 文档取自文字层，音视频来自转写，其余来自 OCR
+func chunkSourceType(material *models.Material) string {
+	switch material.FileType {
+	case "text", "html", "epub", FileTypeClip, "document", "presentation":
+		return ChunkSourceText
+	case "video", "audio":
+		return ChunkSourceASR
+	default:
+		return ChunkSourceOCR
+	}
+}
+
+// textChunk 文本分片及其在源文本中的位置
+type textChunk struct {
+	Content   string
+	Page      int // 源文本以换页符 \f 分页时的页码（从 1 开始），未分页为 0
+	CharStart int // 在源文本中的起始字符偏移（按字符计）
+	CharEnd   int // 结束字符偏移（不含）
+}
+
+// upsertTextChunks 将文本分片写入 llm-service 向量库，replace 为 true 时替换该资料已有的分片。
+// version 为产生源文本的处理任务 ID，作为分片元数据 material_version，可据此按版本删除分片
+func (s *MaterialServiceImpl) upsertTextChunks(material *models.Material, chunks []textChunk, version string, replace bool) (int32, error) {
+	llmAddr := s.config.Database.LLMGRPCAddr
+	lconn, err := discovery.DialAddr(registry.LLM.Name, llmAddr)
+	if err != nil {
+		return 0, fmt.Errorf("dial llm: %w", err)
+	}
 	defer lconn.Close()
 	llm := llmpb.NewLLMServiceClient(lconn)
 
-	ureq := &llmpb.UpsertChunksRequest{UserId: material.UserID.String(), MaterialId: material.ID.String()}
+	sourceType := chunkSourceType(material)
+	ureq := &llmpb.UpsertChunksRequest{UserId: material.UserID.String(), MaterialId: material.ID.String(), Replace: replace}
 	for i, c := range chunks {
-		ureq.Chunks = append(ureq.Chunks, &llmpb.UpsertChunkItem{Content: c, Page: int32(i), Metadata: map[string]string{"source": material.FileType}})
+		ureq.Chunks = append(ureq.Chunks, &llmpb.UpsertChunkItem{
+			Content: c.Content,
+			Page:    int32(c.Page),
+			Metadata: map[string]string{
+				"source":           material.FileType,
+				"source_type":      sourceType,
+				"chunk_index":      strconv.Itoa(i),
+				"char_start":       strconv.Itoa(c.CharStart),
+				"char_end":         strconv.Itoa(c.CharEnd),
+				"material_version": version,
+				"language":         material.Language,
+			},
+		})
 	}
 	uctx, ucancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer ucancel()
 	uresp, err := llm.UpsertChunks(uctx, ureq)
 	if err != nil {
-		_ = s.UpdateProcessingResult(result.TaskID, models.ProcessingStatusFailed, "", nil, fmt.Sprintf("upsert chunks: %v", err))
-		return
+		return 0, fmt.Errorf("upsert chunks: %w", err)
 	}
-	_ = s.UpdateProcessingResult(result.TaskID, models.ProcessingStatusCompleted, "embedded", map[string]interface{}{"chunks": uresp.Inserted}, "")
+	return uresp.Inserted, nil
 }
 
-func splitTextToChunks(text string) []string {
-	text = strings.TrimSpace(text)
-	if text == "" {
-		return nil
-	}
-	// 简单切分：按换行聚合，保证每段<=500字符
-	maxLen := 500
-	var out []string
-	var cur strings.Builder
-	for _, line := range strings.Split(text, "\n") {
-		line = strings.TrimSpace(line)
-		if line == "" {
-			continue
+// splitTextToChunks 按换行聚合文本，保证每段<=500字节，并记录每段在源文本中的字符偏移。
+// 文本含换页符时按页切分，分片不跨页
+func splitTextToChunks(text string) []textChunk {
+	const maxLen = 500
+	paged := strings.Contains(text, "\f")
+	var out []textChunk
+	offset := 0 // 当前行在源文本中的起始字符偏移
+	for pageIdx, pageText := range strings.Split(text, "\f") {
+		var cur strings.Builder
+		chunk := textChunk{}
+		if paged {
+			chunk.Page = pageIdx + 1
 		}
-		if cur.Len()+len(line)+1 > maxLen {
-			out = append(out, cur.String())
+		flush := func() {
+			if cur.Len() == 0 {
+				return
+			}
+			chunk.Content = cur.String()
+			out = append(out, chunk)
 			cur.Reset()
 		}
-		if cur.Len() > 0 {
-			cur.WriteString("\n")
+		for _, line := range strings.SplitAfter(pageText, "\n") {
+			trimmed := strings.TrimSpace(line)
+			if trimmed != "" {
+				start := offset + utf8.RuneCountInString(line[:strings.Index(line, trimmed)])
+				if cur.Len()+len(trimmed)+1 > maxLen {
+					flush()
+				}
+				if cur.Len() == 0 {
+					chunk.CharStart = start
+				} else {
+					cur.WriteString("\n")
+				}
+				cur.WriteString(trimmed)
+				chunk.CharEnd = start + utf8.RuneCountInString(trimmed)
+			}
+			offset += utf8.RuneCountInString(line)
 		}
-		cur.WriteString(line)
-	}
-	if cur.Len() > 0 {
-		out = append(out, cur.String())
+		flush()
+		offset++ // 换页符
 	}
 	return out
 }
@@ -603,8 +1239,8 @@ func (s *MaterialServiceImpl) sendOcrRequestMessage(material *models.Material, u
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	err = s.kafkaWriter.WriteMessages(ctx, kafka.Message{
-		Key:   []byte(material.ID.String()),
+	err = kafkaMetrics.WriteMessages(ctx, s.kafkaWriter, kafka.Message{
+		Key:   arkkafka.MaterialKey(material.ID.String()),
 		Value: messageBytes,
 	})
 
@@ -630,14 +1266,22 @@ func (s *MaterialServiceImpl) sendTextExtractedMessage(material *models.Material
 	if _, err := buf.ReadFrom(obj); err != nil {
 		return fmt.Errorf("failed to read object content: %w", err)
 	}
-	content := buf.String()
+	content, err := extractMaterialText(material.FileType, buf.Bytes())
+	if err != nil {
+		return permanent(fmt.Errorf("failed to extract text from %s: %w", material.FileType, err))
+	}
+	if strings.TrimSpace(content) == "" {
+		return permanent(fmt.Errorf("no text extracted from %s material", material.FileType))
+	}
+	s.indexExtractedText(material, content)
 
 	// 构建消息
 	message := map[string]interface{}{
 		"material_id": material.ID.String(),
 		"user_id":     userID.String(),
 		"text":        content,
-		"source":      "text",
+		"source":      material.FileType,
+		"language":    material.Language,
 	}
 
 	messageBytes, err := json.Marshal(message)
@@ -649,8 +1293,8 @@ func (s *MaterialServiceImpl) sendTextExtractedMessage(material *models.Material
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	err = s.textExtractedKafkaWriter.WriteMessages(ctx, kafka.Message{
-		Key:   []byte(material.ID.String()),
+	err = kafkaMetrics.WriteMessages(ctx, s.textExtractedKafkaWriter, kafka.Message{
+		Key:   arkkafka.MaterialKey(material.ID.String()),
 		Value: messageBytes,
 	})
 
@@ -661,6 +1305,18 @@ func (s *MaterialServiceImpl) sendTextExtractedMessage(material *models.Material
 	return nil
 }
 
+// extractMaterialText 提取纯文本资料的正文：HTML/EPUB 去除模板内容并保留标题，纯文本原样返回
+func extractMaterialText(fileType string, data []byte) (string, error) {
+	switch fileType {
+	case "html":
+		return extract.HTMLText(bytes.NewReader(data))
+	case "epub":
+		return extract.EPUBText(data)
+	default:
+		return string(data), nil
+	}
+}
+
 // sendFileProcessingMessage 发送文件处理消息到 Kafka
 func (s *MaterialServiceImpl) sendFileProcessingMessage(material *models.Material, userID uuid.UUID) error {
 	if s.kafkaWriter == nil {
@@ -686,8 +1342,8 @@ func (s *MaterialServiceImpl) sendFileProcessingMessage(material *models.Materia
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	err = s.kafkaWriter.WriteMessages(ctx, kafka.Message{
-		Key:   []byte(material.ID.String()),
+	err = kafkaMetrics.WriteMessages(ctx, s.kafkaWriter, kafka.Message{
+		Key:   arkkafka.MaterialKey(material.ID.String()),
 		Value: messageBytes,
 	})
 
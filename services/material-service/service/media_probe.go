@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/RigelNana/arkstudy/services/material-service/models"
+)
+
+// ffprobe -of json 输出中用到的字段
+type ffprobeOutput struct {
+	Format struct {
+		FormatName string `json:"format_name"`
+		Duration   string `json:"duration"`
+	} `json:"format"`
+	Streams []struct {
+		CodecType string `json:"codec_type"`
+		CodecName string `json:"codec_name"`
+		Width     int    `json:"width"`
+		Height    int    `json:"height"`
+		// 封面图以视频流形式出现，disposition.attached_pic 为 1
+		Disposition struct {
+			AttachedPic int `json:"attached_pic"`
+		} `json:"disposition"`
+	} `json:"streams"`
+}
+
+// errFFprobeUnavailable 未安装 ffprobe，探测步骤跳过
+var errFFprobeUnavailable = errors.New("ffprobe not available")
+
+// isMediaFile 是否为需要探测元数据的音视频资料
+func isMediaFile(material *models.Material) bool {
+	return material.FileType == "audio" || material.FileType == "video"
+}
+
+// probeMedia 用 ffprobe 读取音视频的时长、分辨率与编码。传入预签名 URL，ffprobe 只读取容器头部，无需下载整个文件。
+// 同时运行的 ffprobe 进程数受 MEDIA_PROBE_WORKERS 限制，等待空闲进程的时间计入 ctx 的超时
+func (s *MaterialServiceImpl) probeMedia(ctx context.Context, material *models.Material) (models.MediaInfo, error) {
+	var info models.MediaInfo
+	path, err := exec.LookPath(s.config.Media.FFprobePath)
+	if err != nil {
+		return info, fmt.Errorf("%w: %v", errFFprobeUnavailable, err)
+	}
+	select {
+	case s.mediaProbeSlots <- struct{}{}:
+		defer func() { <-s.mediaProbeSlots }()
+	case <-ctx.Done():
+		return info, ctx.Err()
+	}
+
+	fileURL, err := s.GetFileURL(material, 10*time.Minute)
+	if err != nil {
+		return info, err
+	}
+	out, err := exec.CommandContext(ctx, path,
+		"-v", "error",
+		"-show_entries", "format=format_name,duration:stream=codec_type,codec_name,width,height:stream_disposition=attached_pic",
+		"-of", "json",
+		fileURL,
+	).Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+			return info, fmt.Errorf("ffprobe: %s", strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return info, fmt.Errorf("ffprobe: %w", err)
+	}
+
+	var probe ffprobeOutput
+	if err := json.Unmarshal(out, &probe); err != nil {
+		return info, fmt.Errorf("ffprobe: decode output: %w", err)
+	}
+	info.FormatName = probe.Format.FormatName
+	if d, err := strconv.ParseFloat(probe.Format.Duration, 64); err == nil && d > 0 {
+		info.DurationSeconds = d
+	}
+	for _, st := range probe.Streams {
+		switch st.CodecType {
+		case "video":
+			if info.VideoCodec == "" && st.Disposition.AttachedPic == 0 {
+				info.VideoCodec = st.CodecName
+				info.Width, info.Height = st.Width, st.Height
+			}
+		case "audio":
+			if info.AudioCodec == "" {
+				info.AudioCodec = st.CodecName
+			}
+		}
+	}
+	if info.DurationSeconds <= 0 {
+		return info, errors.New("ffprobe: unknown duration")
+	}
+	return info, nil
+}
+
+// probeUploadedMedia 上传后探测音视频元数据并写入资料记录，完成后发布 material.updated 供配额等统计转写时长。
+// 未安装 ffprobe 时跳过；文件无法解析时不重试，超时或读取失败按上传步骤重试
+func (s *MaterialServiceImpl) probeUploadedMedia(material *models.Material) error {
+	ctx, cancel := context.WithTimeout(context.Background(), s.config.Media.ProbeTimeout)
+	defer cancel()
+
+	info, err := s.probeMedia(ctx, material)
+	if errors.Is(err, errFFprobeUnavailable) {
+		return errStepSkipped
+	}
+	if err != nil {
+		// ffprobe 在超时前返回的错误说明文件本身无法解析
+		if ctx.Err() == nil && strings.HasPrefix(err.Error(), "ffprobe: ") {
+			return permanent(err)
+		}
+		return err
+	}
+	now := time.Now()
+	info.ProbedAt = &now
+	if err := s.repo.UpdateMedia(material.ID, info); err != nil {
+		return fmt.Errorf("save media info: %w", err)
+	}
+	material.Media = info
+	s.publishMaterialEvent(EventMaterialUpdated, material, map[string]interface{}{
+		"media_duration_seconds": info.DurationSeconds,
+	})
+	return nil
+}
@@ -0,0 +1,132 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/RigelNana/arkstudy/services/material-service/models"
+	"github.com/minio/minio-go/v7"
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+	"gorm.io/datatypes"
+)
+
+const (
+	// 目录最多保存的条目数与层级，避免异常文件的大纲撑大资料记录
+	maxTOCEntries = 500
+	maxTOCDepth   = 4
+	// 上传后读取 PDF 信息的超时
+	pdfInfoTimeout = time.Minute
+)
+
+func init() {
+	// pdfcpu 默认会在用户配置目录写入配置文件，容器内不需要
+	model.ConfigPath = "disable"
+}
+
+// readPDFInfo 读取 PDF 的页数、文档信息中的标题与作者以及书签目录。
+// 不做完整校验，部分损坏的文件也能读取；minio.Object 支持 Seek，只按需读取交叉引用表与相关对象所在的区间
+func (s *MaterialServiceImpl) readPDFInfo(ctx context.Context, material *models.Material) (models.DocumentInfo, error) {
+	var info models.DocumentInfo
+	obj, err := s.minioClient.GetObject(ctx, material.MinioBucket, material.MinioObjectName, minio.GetObjectOptions{})
+	if err != nil {
+		return info, fmt.Errorf("get object: %w", err)
+	}
+	defer obj.Close()
+	return parsePDFInfo(obj)
+}
+
+func parsePDFInfo(rs io.ReadSeeker) (models.DocumentInfo, error) {
+	var info models.DocumentInfo
+	conf := model.NewDefaultConfiguration()
+	conf.ValidationMode = model.ValidationRelaxed
+	pdfCtx, err := api.ReadContext(rs, conf)
+	if err != nil {
+		return info, fmt.Errorf("read pdf: %w", err)
+	}
+	if err := pdfCtx.EnsurePageCount(); err != nil {
+		return info, fmt.Errorf("count pages: %w", err)
+	}
+	if pdfCtx.PageCount <= 0 {
+		return info, errors.New("pdf has no pages")
+	}
+	info.PageCount = pdfCtx.PageCount
+
+	// 文档信息与目录缺失或损坏时只保留页数
+	if pdfCtx.Info != nil {
+		if d, err := pdfCtx.DereferenceDict(*pdfCtx.Info); err == nil && d != nil {
+			info.Title = pdfText(pdfCtx, d["Title"])
+			info.Author = pdfText(pdfCtx, d["Author"])
+		}
+	}
+	// 未经校验时 pdfcpu 不会定位大纲字典，需手动从文档目录中读取
+	if root, err := pdfCtx.Catalog(); err == nil {
+		if o, found := root.Find("Outlines"); found {
+			pdfCtx.Outlines, _ = pdfCtx.DereferenceDict(o)
+		}
+	}
+	bookmarks, err := pdfcpu.Bookmarks(pdfCtx)
+	if err != nil {
+		log.Printf("Warning: failed to read pdf outline: %v", err)
+	}
+	if toc := flattenBookmarks(nil, bookmarks, 1); len(toc) > 0 {
+		if data, err := json.Marshal(toc); err == nil {
+			info.TOC = datatypes.JSON(data)
+		}
+	}
+	return info, nil
+}
+
+func pdfText(pdfCtx *model.Context, o types.Object) string {
+	if o == nil {
+		return ""
+	}
+	text, err := pdfCtx.DereferenceText(o)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(text)
+}
+
+// flattenBookmarks 将书签树按先序展开为带层级的目录
+func flattenBookmarks(toc []models.TOCEntry, bookmarks []pdfcpu.Bookmark, level int) []models.TOCEntry {
+	for _, bm := range bookmarks {
+		if len(toc) >= maxTOCEntries {
+			return toc
+		}
+		title := strings.TrimSpace(bm.Title)
+		if title != "" {
+			toc = append(toc, models.TOCEntry{Title: title, Page: bm.PageFrom, Level: level})
+		}
+		if level < maxTOCDepth {
+			toc = flattenBookmarks(toc, bm.Kids, level+1)
+		}
+	}
+	return toc
+}
+
+// readUploadedPDFInfo 上传后读取 PDF 信息并写入资料记录，OCR 据页数换算逐页进度
+func (s *MaterialServiceImpl) readUploadedPDFInfo(material *models.Material) error {
+	ctx, cancel := context.WithTimeout(context.Background(), pdfInfoTimeout)
+	defer cancel()
+
+	info, err := s.readPDFInfo(ctx, material)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	info.ProbedAt = &now
+	if err := s.repo.UpdateDocument(material.ID, info); err != nil {
+		return fmt.Errorf("save pdf info: %w", err)
+	}
+	material.Document = info
+	return nil
+}
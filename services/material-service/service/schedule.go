@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	arkkafka "github.com/RigelNana/arkstudy/pkg/kafka"
+	kafkaMetrics "github.com/RigelNana/arkstudy/pkg/metrics/kafka"
+	"github.com/RigelNana/arkstudy/services/material-service/config"
+	"github.com/RigelNana/arkstudy/services/material-service/models"
+	"github.com/google/uuid"
+	kafka "github.com/segmentio/kafka-go"
+)
+
+// processingWindow 每天的一个处理时段 [start, end)，以零点起的分钟数表示；end 不大于 start 时跨越午夜
+type processingWindow struct {
+	start, end int
+}
+
+// processingSchedule 大任务允许派发的时段，windows 为空时不限制
+type processingSchedule struct {
+	windows []processingWindow
+	loc     *time.Location
+}
+
+// newProcessingSchedule 解析 config.ScheduleConfig 中的处理时段
+func newProcessingSchedule(cfg config.ScheduleConfig) (*processingSchedule, error) {
+	loc, err := time.LoadLocation(cfg.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid processing window timezone %q: %w", cfg.Timezone, err)
+	}
+	s := &processingSchedule{loc: loc}
+	for _, part := range strings.Split(cfg.HeavyWindows, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		from, to, ok := strings.Cut(part, "-")
+		start, err1 := parseClock(from)
+		end, err2 := parseClock(to)
+		if !ok || err1 != nil || err2 != nil {
+			return nil, fmt.Errorf("invalid processing window %q, expected HH:MM-HH:MM", part)
+		}
+		s.windows = append(s.windows, processingWindow{start: start, end: end})
+	}
+	return s, nil
+}
+
+// parseClock 解析 HH:MM，返回零点起的分钟数
+func parseClock(s string) (int, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(s))
+	if err != nil {
+		return 0, err
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// describe 以配置的格式返回处理时段，未配置时为空串
+func (s *processingSchedule) describe() string {
+	parts := make([]string, len(s.windows))
+	for i, w := range s.windows {
+		parts[i] = fmt.Sprintf("%02d:%02d-%02d:%02d", w.start/60, w.start%60, w.end/60, w.end%60)
+	}
+	return strings.Join(parts, ",")
+}
+
+// enabled 是否配置了处理时段
+func (s *processingSchedule) enabled() bool {
+	return s != nil && len(s.windows) > 0
+}
+
+// open t 是否处于某个处理时段内，未配置时段时总是为 true
+func (s *processingSchedule) open(t time.Time) bool {
+	if !s.enabled() {
+		return true
+	}
+	local := t.In(s.loc)
+	minute := local.Hour()*60 + local.Minute()
+	for _, w := range s.windows {
+		if w.start < w.end {
+			if minute >= w.start && minute < w.end {
+				return true
+			}
+		} else if minute >= w.start || minute < w.end {
+			return true
+		}
+	}
+	return false
+}
+
+// next 返回 t 之后最近的时段开始时间，t 处于时段内时返回 t
+func (s *processingSchedule) next(t time.Time) time.Time {
+	if s.open(t) {
+		return t
+	}
+	local := t.In(s.loc)
+	var earliest time.Time
+	for day := 0; day <= 1; day++ {
+		for _, w := range s.windows {
+			start := time.Date(local.Year(), local.Month(), local.Day()+day, w.start/60, w.start%60, 0, 0, s.loc)
+			if start.After(t) && (earliest.IsZero() || start.Before(earliest)) {
+				earliest = start
+			}
+		}
+	}
+	return earliest
+}
+
+// isHeavyProcessing 判断任务是否为需要放到处理时段的大任务：时长（或未探测到时长时的文件大小）超过阈值的音视频转写
+func (s *MaterialServiceImpl) isHeavyProcessing(material *models.Material, processType string) bool {
+	if processType != models.ProcessingTypeASR {
+		return false
+	}
+	cfg := s.config.Schedule
+	if material.Media.DurationSeconds > 0 {
+		return cfg.HeavyASRMinutes > 0 && material.Media.DurationSeconds >= float64(cfg.HeavyASRMinutes*60)
+	}
+	return cfg.HeavyASRMB > 0 && material.SizeBytes >= int64(cfg.HeavyASRMB)<<20
+}
+
+// scheduleProcessing 时段外提交的大任务返回计划派发时间，其余任务返回 nil
+func (s *MaterialServiceImpl) scheduleProcessing(material *models.Material, processType string) *time.Time {
+	now := time.Now()
+	if !s.schedule.enabled() || s.schedule.open(now) || !s.isHeavyProcessing(material, processType) {
+		return nil
+	}
+	at := s.schedule.next(now)
+	return &at
+}
+
+// newNotificationsKafkaWriter 创建通知请求的 Kafka writer，未配置 brokers 时返回 nil。与任务事件相同异步发送
+func newNotificationsKafkaWriter(cfg *config.Config) *kafka.Writer {
+	w := newTopicKafkaWriter(cfg.Database.KafkaBrokers, cfg.Database.KafkaTopicNotifications)
+	if w == nil {
+		return nil
+	}
+	w.Async = true
+	w.Completion = func(messages []kafka.Message, err error) {
+		if err != nil {
+			log.Printf("Warning: failed to publish %d notifications: %v", len(messages), err)
+		}
+	}
+	return w
+}
+
+// notifyProcessingStarted 延后的任务开始派发时发送站内通知，发送失败只记录日志
+func (s *MaterialServiceImpl) notifyProcessingStarted(material *models.Material, result *models.ProcessingResult) {
+	if s.notificationsKafkaWriter == nil || result.ScheduledFor == nil {
+		return
+	}
+	label := processingTaskKinds[result.Type].label
+	msg, err := arkkafka.NotificationMessage(arkkafka.NotificationEvent{
+		// 同一次延后重复派发时 ID 不变，由通知服务去重
+		NotificationID: uuid.NewSHA1(uuid.NameSpaceOID, []byte("processing_started:"+result.TaskID+":"+result.ScheduledFor.UTC().Format(time.RFC3339))).String(),
+		UserID:         material.UserID.String(),
+		Channel:        arkkafka.NotificationChannelInApp,
+		Template:       arkkafka.NotificationTemplateProcessingStarted,
+		Subject:        label + "已开始：" + material.Title,
+		Body:           fmt.Sprintf("你提交的「%s」%s任务已在处理时段开始处理，完成后可在任务中心查看结果。", material.Title, label),
+		Data: map[string]string{
+			"task_id":         result.TaskID,
+			"material_id":     material.ID.String(),
+			"title":           material.Title,
+			"processing_type": result.Type,
+			"scheduled_for":   result.ScheduledFor.UTC().Format(time.RFC3339),
+		},
+	})
+	if err != nil {
+		log.Printf("Warning: failed to marshal notification for task %s: %v", result.TaskID, err)
+		return
+	}
+	if err := kafkaMetrics.WriteMessages(context.Background(), s.notificationsKafkaWriter, msg); err != nil {
+		log.Printf("Warning: failed to publish notification for task %s: %v", result.TaskID, err)
+	}
+}
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/RigelNana/arkstudy/services/material-service/config"
+	"github.com/RigelNana/arkstudy/services/material-service/repository"
+	"github.com/google/uuid"
+)
+
+// 单轮最多转入排队的延后任务数，积压时由后续轮次继续
+const schedulerBatchSize = 200
+
+// Scheduler 在处理时段内将到期的延后任务（scheduled）转入排队，再由 DispatchQueued 按用户并发上限派发。
+// 未配置时段时也运行，使配置变更前延后的任务不会一直停留在 scheduled；
+// 多副本同时运行时由 ClaimScheduled 保证同一任务只转入一次
+type Scheduler struct {
+	svc            MaterialService
+	processingRepo repository.ProcessingResultRepository
+	schedule       *processingSchedule
+	interval       time.Duration
+}
+
+func NewScheduler(svc MaterialService, processingRepo repository.ProcessingResultRepository, cfg *config.Config) (*Scheduler, error) {
+	schedule, err := newProcessingSchedule(cfg.Schedule)
+	if err != nil {
+		return nil, err
+	}
+	return &Scheduler{
+		svc:            svc,
+		processingRepo: processingRepo,
+		schedule:       schedule,
+		interval:       cfg.Schedule.Interval,
+	}, nil
+}
+
+// Run 按 interval 周期检查到期的延后任务，直到 ctx 取消
+func (s *Scheduler) Run(ctx context.Context) {
+	log.Printf("Processing scheduler started: windows=%q timezone=%s interval=%s",
+		s.schedule.describe(), s.schedule.loc, s.interval)
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		s.runOnce(time.Now())
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Scheduler) runOnce(now time.Time) {
+	if !s.schedule.open(now) {
+		return
+	}
+	results, err := s.processingRepo.ListDueScheduled(now, schedulerBatchSize)
+	if err != nil {
+		log.Printf("Scheduler: failed to query scheduled processing: %v", err)
+		return
+	}
+	if len(results) == 0 {
+		return
+	}
+
+	released := 0
+	users := make(map[uuid.UUID]bool)
+	for _, r := range results {
+		claimed, err := s.processingRepo.ClaimScheduled(r.TaskID)
+		if err != nil {
+			log.Printf("Scheduler: failed to release task %s: %v", r.TaskID, err)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+		released++
+		if r.Material.UserID != uuid.Nil {
+			users[r.Material.UserID] = true
+		}
+	}
+	dispatched := 0
+	for userID := range users {
+		dispatched += s.svc.DispatchQueued(userID)
+	}
+	log.Printf("Scheduler: released %d scheduled tasks, dispatched %d", released, dispatched)
+}
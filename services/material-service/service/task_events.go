@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"log"
+	"strconv"
+	"time"
+
+	arkkafka "github.com/RigelNana/arkstudy/pkg/kafka"
+	kafkaMetrics "github.com/RigelNana/arkstudy/pkg/metrics/kafka"
+	"github.com/RigelNana/arkstudy/services/material-service/config"
+	"github.com/RigelNana/arkstudy/services/material-service/models"
+	kafka "github.com/segmentio/kafka-go"
+)
+
+// 处理类型对应的任务中心任务类型与展示名称，其余处理类型不进入任务中心
+var processingTaskKinds = map[string]struct{ kind, label string }{
+	models.ProcessingTypeOCR: {arkkafka.TaskKindOCR, "文字识别"},
+	models.ProcessingTypeASR: {arkkafka.TaskKindASR, "语音转写"},
+}
+
+// newTaskEventsKafkaWriter 创建任务事件的 Kafka writer，未配置 brokers 时返回 nil。
+// 异步发送，进度回调等路径不等待投递；同一用户的事件进入同一分区，仍按发送顺序投递
+func newTaskEventsKafkaWriter(cfg *config.Config) *kafka.Writer {
+	w := newTopicKafkaWriter(cfg.Database.KafkaBrokers, cfg.Database.KafkaTopicTaskEvents)
+	if w == nil {
+		return nil
+	}
+	w.Async = true
+	w.Completion = func(messages []kafka.Message, err error) {
+		if err != nil {
+			log.Printf("Warning: failed to publish %d task events: %v", len(messages), err)
+		}
+	}
+	return w
+}
+
+// taskStatus 将处理状态映射为任务中心的统一状态
+func taskStatus(processingStatus string) string {
+	switch processingStatus {
+	case models.ProcessingStatusProcessing:
+		return arkkafka.TaskStatusRunning
+	case models.ProcessingStatusCompleted:
+		return arkkafka.TaskStatusSucceeded
+	case models.ProcessingStatusFailed:
+		return arkkafka.TaskStatusFailed
+	default:
+		return arkkafka.TaskStatusPending
+	}
+}
+
+// publishTaskEvent 将处理任务的当前状态发布到任务中心。与资料事件相同，发送失败只记录日志
+func (s *MaterialServiceImpl) publishTaskEvent(taskID string) {
+	if s.taskEventsKafkaWriter == nil {
+		return
+	}
+	result, err := s.processingRepo.GetByTaskID(taskID)
+	if err != nil {
+		return
+	}
+	kind, ok := processingTaskKinds[result.Type]
+	if !ok {
+		return
+	}
+	material, err := s.repo.GetByID(result.MaterialID)
+	if err != nil {
+		return
+	}
+
+	metadata := map[string]string{
+		"processing_type": result.Type,
+		"retry_count":     strconv.Itoa(result.RetryCount),
+	}
+	// 延后到处理时段的任务，供任务中心显示计划开始时间
+	if result.ScheduledFor != nil {
+		metadata["scheduled_for"] = result.ScheduledFor.UTC().Format(time.RFC3339)
+	}
+	// 已知页数的 PDF 按进度换算当前页，供任务中心显示“第 n / m 页”
+	if pages := material.Document.PageCount; result.Type == models.ProcessingTypeOCR && pages > 0 {
+		done := int(result.Progress * float32(pages))
+		if result.Status == models.ProcessingStatusCompleted {
+			done = pages
+		}
+		metadata["pages_total"] = strconv.Itoa(pages)
+		metadata["pages_done"] = strconv.Itoa(min(done, pages))
+	}
+
+	msg, err := arkkafka.TaskMessage(arkkafka.TaskEvent{
+		TaskID:     result.TaskID,
+		UserID:     material.UserID.String(),
+		Kind:       kind.kind,
+		Service:    "material-service",
+		Status:     taskStatus(result.Status),
+		Progress:   result.Progress,
+		Title:      kind.label + "：" + material.Title,
+		TargetID:   material.ID.String(),
+		Error:      result.ErrorMessage,
+		Metadata:   metadata,
+		OccurredAt: time.Now(),
+	})
+	if err != nil {
+		log.Printf("Warning: failed to marshal task event for task %s: %v", taskID, err)
+		return
+	}
+	if err := kafkaMetrics.WriteMessages(context.Background(), s.taskEventsKafkaWriter, msg); err != nil {
+		log.Printf("Warning: failed to publish task event for task %s: %v", taskID, err)
+	}
+}
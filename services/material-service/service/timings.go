@@ -0,0 +1,108 @@
+package service
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/RigelNana/arkstudy/pkg/metrics"
+	"github.com/RigelNana/arkstudy/services/material-service/models"
+	"gorm.io/datatypes"
+)
+
+// processingStages 处理阶段的先后顺序，相邻两个已记录阶段的时间差计入 processing_stage_duration_seconds
+var processingStages = []string{
+	models.ProcessingStageUploaded,
+	models.ProcessingStageRequested,
+	models.ProcessingStageDispatched,
+	models.ProcessingStageStarted,
+	models.ProcessingStageRecognized,
+	models.ProcessingStageEmbedded,
+	models.ProcessingStageFinished,
+}
+
+// stageKey 阶段时间戳在 metadata 中的键
+func stageKey(stage string) string {
+	return stage + "_at"
+}
+
+// stampStage 在 metadata 中记录阶段时间戳，返回 metadata 便于链式构造
+func stampStage(metadata map[string]interface{}, stage string, at time.Time) map[string]interface{} {
+	if metadata == nil {
+		metadata = map[string]interface{}{}
+	}
+	metadata[stageKey(stage)] = at.UTC().Format(time.RFC3339Nano)
+	return metadata
+}
+
+// decodeMetadata 解析 ProcessingResult.Metadata，内容为空或无法解析时返回空 map
+func decodeMetadata(data datatypes.JSON) map[string]interface{} {
+	out := map[string]interface{}{}
+	if len(data) > 0 {
+		_ = json.Unmarshal(data, &out)
+	}
+	return out
+}
+
+// carryStages 将 prev 中已记录的阶段时间戳带入本次更新的 metadata，next 中已有的阶段不覆盖。
+// metadata 每次更新整体替换，阶段时间戳需要跨更新保留
+func carryStages(prev, next map[string]interface{}) {
+	for _, stage := range processingStages {
+		key := stageKey(stage)
+		if _, ok := next[key]; ok {
+			continue
+		}
+		if v, ok := prev[key]; ok {
+			next[key] = v
+		}
+	}
+}
+
+// stageTimes 从 metadata 中读取已记录的阶段时间戳
+func stageTimes(metadata map[string]interface{}) map[string]time.Time {
+	out := map[string]time.Time{}
+	for _, stage := range processingStages {
+		v, ok := metadata[stageKey(stage)].(string)
+		if !ok {
+			continue
+		}
+		if t, err := time.Parse(time.RFC3339Nano, v); err == nil {
+			out[stage] = t
+		}
+	}
+	return out
+}
+
+// observeProcessingLatency 处理结束时记录端到端耗时（发起处理到结束）及各阶段耗时
+func observeProcessingLatency(processType, status string, metadata map[string]interface{}) {
+	times := stageTimes(metadata)
+	finished, ok := times[models.ProcessingStageFinished]
+	if !ok {
+		return
+	}
+	if requested, ok := times[models.ProcessingStageRequested]; ok {
+		metrics.ProcessingDuration.WithLabelValues(processType, status).Observe(finished.Sub(requested).Seconds())
+	}
+
+	var prev time.Time
+	for _, stage := range processingStages {
+		t, ok := times[stage]
+		if !ok {
+			continue
+		}
+		// 各服务时钟可能存在偏差，忽略倒序的阶段
+		if !prev.IsZero() && !t.Before(prev) {
+			metrics.ProcessingStageDuration.WithLabelValues(processType, stage).Observe(t.Sub(prev).Seconds())
+		}
+		if prev.IsZero() || !t.Before(prev) {
+			prev = t
+		}
+	}
+}
+
+// initialProcessingMetadata 新建或重置处理记录时的 metadata，记录资料上传与发起处理的时间
+func initialProcessingMetadata(material *models.Material) datatypes.JSON {
+	metadata := stampStage(nil, models.ProcessingStageUploaded, material.CreatedAt)
+	stampStage(metadata, models.ProcessingStageRequested, time.Now())
+	data, _ := json.Marshal(metadata)
+	return datatypes.JSON(data)
+}
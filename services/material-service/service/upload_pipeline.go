@@ -0,0 +1,182 @@
+package service
+
+import (
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/RigelNana/arkstudy/pkg/metrics"
+	"github.com/RigelNana/arkstudy/services/material-service/models"
+	"github.com/google/uuid"
+)
+
+// 上传流水线的步骤名，作为指标的 step 标签。
+// record / store_object / commit 在请求内顺序执行，提交后的步骤在后台执行
+const (
+	uploadStepRecord   = "record"          // 创建 uploading 资料记录
+	uploadStepStore    = "store_object"    // 写入 MinIO
+	uploadStepCommit   = "commit"          // 回填实际大小并提交状态
+	uploadStepEvent    = "publish_created" // 发布 material.created 事件
+	uploadStepDispatch = "dispatch"        // 按文件类型发起 OCR / 文本入库 / 文件处理
+	uploadStepExpand   = "expand_bundle"   // 展开压缩包
+	uploadStepProbe    = "probe_media"     // 用 ffprobe 读取音视频时长、分辨率与编码
+	uploadStepPDFInfo  = "read_pdf_info"   // 读取 PDF 页数、标题、作者与目录
+)
+
+// 步骤结果，作为指标的 status 标签
+const (
+	stepStatusOK      = "ok"
+	stepStatusFailed  = "failed"
+	stepStatusSkipped = "skipped"
+)
+
+// 资料已保存但处理请求未能发出时的状态，可通过 ProcessMaterial 重新发起处理
+const MaterialStatusDispatchFailed = "dispatch_failed"
+
+const (
+	// 提交后的步骤失败时的最大尝试次数，第 n 次重试前等待 n * postCommitBackoff
+	postCommitAttempts = 3
+	postCommitBackoff  = time.Second
+)
+
+// errStepSkipped 步骤依赖的组件未配置（如 Kafka），跳过而不视为失败
+var errStepSkipped = errors.New("upload step skipped")
+
+// permanentError 标记重试无意义的失败，如文件中没有可提取的文字
+type permanentError struct{ err error }
+
+func (e permanentError) Error() string { return e.err.Error() }
+func (e permanentError) Unwrap() error { return e.err }
+
+func permanent(err error) error { return permanentError{err: err} }
+
+// uploadStep 资料提交后执行的一个步骤
+type uploadStep struct {
+	name string
+	run  func() error
+	// 重试耗尽后的补偿动作，为 nil 时失败只记录日志
+	compensate func(err error)
+}
+
+// observeStep 执行 fn 并按结果记录耗时
+func observeStep(step string, fn func() error) error {
+	start := time.Now()
+	err := fn()
+	status := stepStatusOK
+	switch {
+	case errors.Is(err, errStepSkipped):
+		status = stepStatusSkipped
+	case err != nil:
+		status = stepStatusFailed
+	}
+	metrics.MaterialUploadStepDuration.WithLabelValues(step, status).Observe(time.Since(start).Seconds())
+	return err
+}
+
+// runStages 执行资料提交后的步骤：阶段之间顺序执行，同一阶段内的步骤并行扇出，
+// 每个步骤独立重试，互不阻塞
+func (s *MaterialServiceImpl) runStages(materialID uuid.UUID, stages ...[]uploadStep) {
+	for _, stage := range stages {
+		var wg sync.WaitGroup
+		for _, step := range stage {
+			wg.Add(1)
+			go func(step uploadStep) {
+				defer wg.Done()
+				s.runStep(materialID, step)
+			}(step)
+		}
+		wg.Wait()
+	}
+}
+
+func (s *MaterialServiceImpl) runStep(materialID uuid.UUID, step uploadStep) {
+	var err error
+	for attempt := 1; attempt <= postCommitAttempts; attempt++ {
+		err = observeStep(step.name, step.run)
+		if err == nil || errors.Is(err, errStepSkipped) {
+			return
+		}
+		var perm permanentError
+		if errors.As(err, &perm) || attempt == postCommitAttempts {
+			break
+		}
+		log.Printf("Upload step %s for material %s failed (attempt %d/%d), retrying: %v", step.name, materialID, attempt, postCommitAttempts, err)
+		time.Sleep(postCommitBackoff * time.Duration(attempt))
+	}
+	log.Printf("Warning: upload step %s for material %s failed: %v", step.name, materialID, err)
+	if step.compensate != nil {
+		metrics.MaterialUploadCompensations.WithLabelValues(step.name).Inc()
+		step.compensate(err)
+	}
+}
+
+// afterUpload 在后台执行资料提交后的步骤，上传请求不等待其完成
+func (s *MaterialServiceImpl) afterUpload(material *models.Material) {
+	// 后台步骤使用副本，与调用方返回的对象互不影响
+	m := *material
+	go s.runUploadSteps(&m)
+}
+
+// runUploadSteps 发布 material.created 事件并按类型发起处理，两者并行，PDF 同时读取文档信息；音视频随后探测元数据。
+// 压缩包在事件发布之后再展开，保证 material.created 先于展开结果的 material.updated；
+// 展开时各子资料依次同步执行本流程，避免同时读取大量文件
+func (s *MaterialServiceImpl) runUploadSteps(material *models.Material) {
+	var changes map[string]interface{}
+	if material.ParentID != nil {
+		changes = map[string]interface{}{"parent_id": material.ParentID.String()}
+	}
+	event := uploadStep{
+		name: uploadStepEvent,
+		run: func() error {
+			if s.materialEventsKafkaWriter == nil {
+				return errStepSkipped
+			}
+			return s.writeMaterialEvent(EventMaterialCreated, material, changes)
+		},
+	}
+
+	if material.FileType == FileTypeBundle {
+		s.runStages(material.ID, []uploadStep{event}, []uploadStep{{
+			name: uploadStepExpand,
+			run: func() error {
+				// 展开失败记录在 bundle 的状态与 Metadata 中，不重试
+				s.expandBundle(material)
+				return nil
+			},
+		}})
+		return
+	}
+
+	first := []uploadStep{event, {
+		name: uploadStepDispatch,
+		run: func() error {
+			return s.dispatchUploadProcessing(material)
+		},
+		compensate: func(err error) {
+			if uErr := s.UpdateStatus(material.ID, MaterialStatusDispatchFailed); uErr != nil {
+				log.Printf("Warning: failed to mark material %s %s: %v", material.ID, MaterialStatusDispatchFailed, uErr)
+			}
+		},
+	}}
+	if material.FileType == "pdf" {
+		// 与 OCR 并行读取，OCR 进度回调时已有页数即可换算逐页进度
+		first = append(first, uploadStep{
+			name: uploadStepPDFInfo,
+			run: func() error {
+				return s.readUploadedPDFInfo(material)
+			},
+		})
+	}
+	stages := [][]uploadStep{first}
+	if isMediaFile(material) {
+		// 探测结果以 material.updated 发布，需在 material.created 之后
+		stages = append(stages, []uploadStep{{
+			name: uploadStepProbe,
+			run: func() error {
+				return s.probeUploadedMedia(material)
+			},
+		}})
+	}
+	s.runStages(material.ID, stages...)
+}
@@ -0,0 +1,312 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/RigelNana/arkstudy/pkg/dbx"
+	"github.com/RigelNana/arkstudy/services/material-service/models"
+	"github.com/RigelNana/arkstudy/services/material-service/repository"
+	"github.com/google/uuid"
+	"github.com/minio/minio-go/v7"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// 上传会话的业务错误，由 RPC 层映射为 gRPC 状态码
+var (
+	ErrUploadSessionNotFound = errors.New("upload session not found")
+	ErrUploadSessionExpired  = errors.New("upload session expired")
+	ErrUploadOffsetMismatch  = errors.New("upload offset mismatch")
+	ErrInvalidUploadSession  = errors.New("invalid upload session")
+)
+
+// 上传会话分段对象的前缀，对象名为 <前缀><会话 ID>/<起始偏移量>-<随机后缀>；
+// 不属于任何资料记录，孤立对象清理按会话是否存在判断
+const uploadSessionPrefix = "upload-sessions/"
+
+func uploadSessionObjectPrefix(id uuid.UUID) string {
+	return uploadSessionPrefix + id.String() + "/"
+}
+
+// UploadSessionResult 追加后的会话；本次追加使会话完成时 Upload 为创建资料的结果
+type UploadSessionResult struct {
+	Session *models.UploadSession
+	Upload  *UploadResult
+}
+
+// CreateUploadSession 创建可续传的上传会话，文件内容之后经 AppendUploadSession 按偏移量追加
+func (s *MaterialServiceImpl) CreateUploadSession(userID uuid.UUID, title, originalFilename string, size int64, onDuplicate string) (*models.UploadSession, error) {
+	switch onDuplicate {
+	case "", DuplicateActionWarn, DuplicateActionLink:
+	default:
+		return nil, fmt.Errorf("%w: on_duplicate must be %s or %s", ErrInvalidUploadSession, DuplicateActionWarn, DuplicateActionLink)
+	}
+	if strings.TrimSpace(originalFilename) == "" {
+		return nil, fmt.Errorf("%w: filename is required", ErrInvalidUploadSession)
+	}
+	if size <= 0 {
+		return nil, fmt.Errorf("%w: size must be positive", ErrInvalidUploadSession)
+	}
+	if size > s.config.UploadSession.MaxBytes {
+		return nil, fmt.Errorf("%w: size exceeds %d bytes", ErrInvalidUploadSession, s.config.UploadSession.MaxBytes)
+	}
+
+	session := &models.UploadSession{
+		UserID:           userID,
+		Title:            title,
+		OriginalFilename: originalFilename,
+		OnDuplicate:      onDuplicate,
+		SizeBytes:        size,
+		Parts:            datatypes.JSON("[]"),
+		Status:           models.UploadSessionActive,
+		ExpiresAt:        time.Now().Add(s.config.UploadSession.TTL),
+	}
+	if err := s.uploadSessionRepo.WithContext(dbx.WithActor(context.Background(), userID.String())).Create(session); err != nil {
+		return nil, fmt.Errorf("failed to create upload session: %w", err)
+	}
+	return session, nil
+}
+
+// getOwnUploadSession 获取属于 userID 的会话，不属于该用户时与不存在一样返回 ErrUploadSessionNotFound
+func (s *MaterialServiceImpl) getOwnUploadSession(id, userID uuid.UUID) (*models.UploadSession, error) {
+	session, err := s.uploadSessionRepo.GetByID(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && session.UserID != userID) {
+		return nil, ErrUploadSessionNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get upload session: %w", err)
+	}
+	return session, nil
+}
+
+// GetUploadSession 获取会话，未完成且已过期的会话返回 ErrUploadSessionExpired
+func (s *MaterialServiceImpl) GetUploadSession(id, userID uuid.UUID) (*models.UploadSession, error) {
+	session, err := s.getOwnUploadSession(id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if session.Status != models.UploadSessionCompleted && time.Now().After(session.ExpiresAt) {
+		return nil, ErrUploadSessionExpired
+	}
+	return session, nil
+}
+
+// AppendUploadSession 将 reader 中的内容作为一个分段追加到会话，offset 必须等于已接收的字节数。
+// reader 结束前中断时（如客户端断开）返回错误，本次内容不保存；接收满后合并分段创建资料。
+// 合并失败时会话保持 active，可用 offset 等于 size 的空追加重试；合并中副本退出导致会话
+// 停留在 completing 时，过期后的重试接管该会话重新合并
+func (s *MaterialServiceImpl) AppendUploadSession(id, userID uuid.UUID, offset int64, reader io.Reader) (*UploadSessionResult, error) {
+	session, err := s.getOwnUploadSession(id, userID)
+	if err != nil {
+		return nil, err
+	}
+	expired := time.Now().After(session.ExpiresAt)
+	if expired && session.Status == models.UploadSessionActive {
+		return nil, ErrUploadSessionExpired
+	}
+	if offset != session.ReceivedBytes {
+		return nil, fmt.Errorf("%w: expected offset %d, got %d", ErrUploadOffsetMismatch, session.ReceivedBytes, offset)
+	}
+	switch session.Status {
+	case models.UploadSessionCompleted:
+		if trailingData(reader) {
+			return nil, fmt.Errorf("%w: upload session is already completed", ErrInvalidUploadSession)
+		}
+		return &UploadSessionResult{Session: session}, nil
+	case models.UploadSessionCompleting:
+		if !expired {
+			return nil, fmt.Errorf("%w: upload session is being completed", ErrUploadOffsetMismatch)
+		}
+		log.Printf("Taking over upload session %s left in completing since %s", session.ID, session.UpdatedAt.Format(time.RFC3339))
+	}
+
+	repo := s.uploadSessionRepo.WithContext(dbx.WithActor(context.Background(), userID.String()))
+	if err := s.appendUploadPart(repo, session, reader); err != nil {
+		return nil, err
+	}
+	if session.ReceivedBytes < session.SizeBytes {
+		return &UploadSessionResult{Session: session}, nil
+	}
+	upload, err := s.completeUploadSession(repo, session)
+	if err != nil {
+		return nil, err
+	}
+	return &UploadSessionResult{Session: session, Upload: upload}, nil
+}
+
+// appendUploadPart 将 reader 写入新的分段对象并记入会话。内容超出剩余字节数时拒绝；
+// 并发追加同一偏移量时只有先提交的一方成功（乐观锁），其余删除已写入的分段
+func (s *MaterialServiceImpl) appendUploadPart(repo repository.UploadSessionRepository, session *models.UploadSession, reader io.Reader) error {
+	remaining := session.SizeBytes - session.ReceivedBytes
+	if remaining == 0 {
+		if trailingData(reader) {
+			return fmt.Errorf("%w: data exceeds upload size", ErrInvalidUploadSession)
+		}
+		return nil
+	}
+
+	ctx := context.Background()
+	bucket := s.config.MinIO.BucketName
+	objectName := fmt.Sprintf("%s%020d-%s", uploadSessionObjectPrefix(session.ID), session.ReceivedBytes, uuid.New().String())
+	info, err := s.minioClient.PutObject(ctx, bucket, objectName, io.LimitReader(reader, remaining), -1, minio.PutObjectOptions{
+		ContentType:          "application/octet-stream",
+		PartSize:             uploadPartSize,
+		ServerSideEncryption: s.sse,
+	})
+	if err != nil {
+		if rmErr := s.minioClient.RemoveIncompleteUpload(ctx, bucket, objectName); rmErr != nil {
+			log.Printf("Warning: failed to abort incomplete upload %s: %v", objectName, rmErr)
+		}
+		return fmt.Errorf("failed to store upload part: %w", err)
+	}
+	removePart := func() {
+		if err := s.minioClient.RemoveObject(ctx, bucket, objectName, minio.RemoveObjectOptions{}); err != nil {
+			log.Printf("Warning: failed to remove upload part %s: %v", objectName, err)
+		}
+	}
+	if info.Size == remaining && trailingData(reader) {
+		removePart()
+		return fmt.Errorf("%w: data exceeds upload size", ErrInvalidUploadSession)
+	}
+	if info.Size == 0 {
+		removePart()
+		return nil
+	}
+
+	var parts []string
+	if err := json.Unmarshal(session.Parts, &parts); err != nil {
+		removePart()
+		return fmt.Errorf("failed to decode upload parts: %w", err)
+	}
+	encoded, _ := json.Marshal(append(parts, objectName))
+	session.Parts = datatypes.JSON(encoded)
+	session.ReceivedBytes += info.Size
+	session.ExpiresAt = time.Now().Add(s.config.UploadSession.TTL)
+	if err := repo.Update(session); err != nil {
+		removePart()
+		if errors.Is(err, dbx.ErrStaleVersion) {
+			return fmt.Errorf("%w: upload session was modified concurrently", ErrUploadOffsetMismatch)
+		}
+		return fmt.Errorf("failed to update upload session: %w", err)
+	}
+	return nil
+}
+
+// completeUploadSession 按顺序读取全部分段创建资料，成功后删除分段。
+// 会话先标记为 completing，避免并发的重试重复创建资料
+func (s *MaterialServiceImpl) completeUploadSession(repo repository.UploadSessionRepository, session *models.UploadSession) (*UploadResult, error) {
+	session.Status = models.UploadSessionCompleting
+	session.ExpiresAt = time.Now().Add(s.config.UploadSession.TTL)
+	if err := repo.Update(session); err != nil {
+		if errors.Is(err, dbx.ErrStaleVersion) {
+			return nil, fmt.Errorf("%w: upload session was modified concurrently", ErrUploadOffsetMismatch)
+		}
+		return nil, fmt.Errorf("failed to update upload session: %w", err)
+	}
+
+	var parts []string
+	if err := json.Unmarshal(session.Parts, &parts); err != nil {
+		return nil, fmt.Errorf("failed to decode upload parts: %w", err)
+	}
+	reader := &uploadPartsReader{client: s.minioClient, bucket: s.config.MinIO.BucketName, parts: parts}
+	result, err := s.UploadFile(session.UserID, session.Title, session.OriginalFilename, reader, session.SizeBytes, session.OnDuplicate)
+	reader.Close()
+	if err != nil {
+		session.Status = models.UploadSessionActive
+		if uerr := repo.Update(session); uerr != nil {
+			log.Printf("Warning: failed to reset upload session %s: %v", session.ID, uerr)
+		}
+		return nil, fmt.Errorf("failed to create material from upload session: %w", err)
+	}
+
+	session.Status = models.UploadSessionCompleted
+	session.MaterialID = &result.Material.ID
+	if err := repo.Update(session); err != nil {
+		log.Printf("Warning: failed to mark upload session %s as completed: %v", session.ID, err)
+	}
+	if err := removeObjectsWithPrefix(context.Background(), s.minioClient, s.config.MinIO.BucketName, uploadSessionObjectPrefix(session.ID)); err != nil {
+		log.Printf("Warning: failed to remove parts of upload session %s: %v", session.ID, err)
+	}
+	return result, nil
+}
+
+// DeleteUploadSession 删除会话及已接收的分段，已创建的资料不受影响
+func (s *MaterialServiceImpl) DeleteUploadSession(id, userID uuid.UUID) error {
+	session, err := s.getOwnUploadSession(id, userID)
+	if err != nil {
+		return err
+	}
+	if err := removeObjectsWithPrefix(context.Background(), s.minioClient, s.config.MinIO.BucketName, uploadSessionObjectPrefix(session.ID)); err != nil {
+		return fmt.Errorf("failed to remove upload parts: %w", err)
+	}
+	if err := s.uploadSessionRepo.WithContext(dbx.WithActor(context.Background(), userID.String())).Delete(session.ID); err != nil {
+		return fmt.Errorf("failed to delete upload session: %w", err)
+	}
+	return nil
+}
+
+// trailingData 报告 reader 中是否还有未读取的内容
+func trailingData(reader io.Reader) bool {
+	n, _ := reader.Read(make([]byte, 1))
+	return n > 0
+}
+
+// removeObjectsWithPrefix 删除存储桶中以 prefix 开头的全部对象
+func removeObjectsWithPrefix(ctx context.Context, client *minio.Client, bucket, prefix string) error {
+	for obj := range client.ListObjects(ctx, bucket, minio.ListObjectsOptions{Prefix: prefix, Recursive: true}) {
+		if obj.Err != nil {
+			return obj.Err
+		}
+		if err := client.RemoveObject(ctx, bucket, obj.Key, minio.RemoveObjectOptions{}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// uploadPartsReader 依次读取会话的分段对象，整体作为一个文件
+type uploadPartsReader struct {
+	client  *minio.Client
+	bucket  string
+	parts   []string
+	current *minio.Object
+}
+
+func (r *uploadPartsReader) Read(p []byte) (int, error) {
+	for {
+		if r.current == nil {
+			if len(r.parts) == 0 {
+				return 0, io.EOF
+			}
+			obj, err := r.client.GetObject(context.Background(), r.bucket, r.parts[0], minio.GetObjectOptions{})
+			if err != nil {
+				return 0, err
+			}
+			r.current, r.parts = obj, r.parts[1:]
+		}
+		n, err := r.current.Read(p)
+		if err == io.EOF {
+			r.current.Close()
+			r.current = nil
+			if n == 0 {
+				continue
+			}
+			err = nil
+		}
+		return n, err
+	}
+}
+
+func (r *uploadPartsReader) Close() {
+	if r.current != nil {
+		r.current.Close()
+		r.current = nil
+	}
+}
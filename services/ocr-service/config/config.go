@@ -5,16 +5,22 @@ import (
 	"log"
 	"os"
 
+	"github.com/RigelNana/arkstudy/pkg/registry"
 	"github.com/joho/godotenv"
 )
 
 type Config struct {
 	GRPCAddr string
-	MinIO    MinIOConfig
-	Paddle   PaddleOCRConfig
-	Kafka    KafkaConfig
-	Material MaterialCallbackConfig
-	OpenAI   OpenAIConfig
+	// 默认 OCR 引擎（openai | paddle），可被请求 options 中的 engine 覆盖
+	Engine     string
+	MinIO      MinIOConfig
+	Paddle     PaddleOCRConfig
+	Kafka      KafkaConfig
+	Material   MaterialCallbackConfig
+	OpenAI     OpenAIConfig
+	Preprocess PreprocessConfig
+	Extract    ExtractConfig
+	Formula    FormulaConfig
 }
 
 type MinIOConfig struct {
@@ -23,13 +29,19 @@ type MinIOConfig struct {
 	SecretAccessKey string
 	UseSSL          bool
 	BucketName      string
+	// 为 true 时读取 s3:// 引用前通过 STS AssumeRole 签发仅能读取该对象的临时凭证，
+	// 有效期 STSDurationSeconds 秒（MinIO 最短 900 秒）
+	STSEnabled         bool
+	STSDurationSeconds int
 }
 
 // PaddleOCR HTTP 服务配置
 // 典型示例：Endpoint = http://paddleocr:8868/predict/ocr_system
 type PaddleOCRConfig struct {
 	Endpoint      string
+	TableEndpoint string // 表格模式使用的 PP-Structure 端点，为空时回退到 Endpoint
 	TimeoutSecond int
+	MaxConcurrent int // 同时发往 PaddleOCR 的最大请求数
 }
 
 // Kafka consumer configuration
@@ -37,6 +49,10 @@ type KafkaConfig struct {
 	Brokers string
 	Topic   string
 	GroupID string
+	// 同时处理中的最大任务数，达到上限后暂停拉取新消息
+	MaxInFlight int
+	// 单个任务从启动到完成的最长等待时间（分钟），超时按失败回调
+	TaskTimeoutMinutes int
 }
 
 // material-service callback (gRPC)
@@ -44,6 +60,26 @@ type MaterialCallbackConfig struct {
 	Addr string // e.g., material-service:50053 (UpdateProcessingResult)
 }
 
+// 图像预处理配置（是否启用由请求 options 控制）
+type PreprocessConfig struct {
+	MinWidth int // upscale 步骤放大后的最小宽度（像素）
+}
+
+// docx/pptx 文本提取配置
+type ExtractConfig struct {
+	MinTextChars int // 文字层少于该字符数时视为扫描件，对内嵌图片回退 OCR
+}
+
+// 公式识别后端配置（是否启用由请求 options 中的 formula 控制）
+// Provider: mathpix（Mathpix 兼容的 /v3/text）或 pix2tex（LaTeX-OCR API），为空则不启用
+type FormulaConfig struct {
+	Provider      string
+	Endpoint      string // 如 https://api.mathpix.com/v3/text 或 http://pix2tex:8502/predict/
+	AppID         string
+	AppKey        string
+	TimeoutSecond int
+}
+
 type OpenAIConfig struct {
 	APIKey  string
 	BaseURL string
@@ -53,31 +89,51 @@ type OpenAIConfig struct {
 func Load() *Config {
 	_ = godotenv.Load()
 	return &Config{
-		GRPCAddr: getEnv("OCR_GRPC_ADDR", "50055"),
+		GRPCAddr: registry.OCR.ListenPort(),
+		Engine:   getEnv("OCR_ENGINE", "openai"),
 		MinIO: MinIOConfig{
-			Endpoint:        os.Getenv("MINIO_ENDPOINT"),
-			AccessKeyID:     os.Getenv("MINIO_ACCESS_KEY"),
-			SecretAccessKey: os.Getenv("MINIO_SECRET_KEY"),
-			UseSSL:          false,
-			BucketName:      os.Getenv("MINIO_BUCKET_NAME"),
+			Endpoint:           os.Getenv("MINIO_ENDPOINT"),
+			AccessKeyID:        os.Getenv("MINIO_ACCESS_KEY"),
+			SecretAccessKey:    os.Getenv("MINIO_SECRET_KEY"),
+			UseSSL:             false,
+			BucketName:         os.Getenv("MINIO_BUCKET_NAME"),
+			STSEnabled:         getEnv("MINIO_STS_ENABLED", "false") == "true",
+			STSDurationSeconds: getEnvInt("MINIO_STS_DURATION_SECONDS", 900),
 		},
 		Paddle: PaddleOCRConfig{
 			Endpoint:      os.Getenv("PADDLE_OCR_ENDPOINT"),
+			TableEndpoint: os.Getenv("PADDLE_OCR_TABLE_ENDPOINT"),
 			TimeoutSecond: getEnvInt("PADDLE_OCR_TIMEOUT", 20),
+			MaxConcurrent: getEnvInt("PADDLE_OCR_MAX_CONCURRENCY", 4),
 		},
 		Kafka: KafkaConfig{
-			Brokers: os.Getenv("KAFKA_BROKERS"),
-			Topic:   getEnv("KAFKA_TOPIC_OCR_REQUESTS", "ocr.requests"),
-			GroupID: getEnv("KAFKA_GROUP_ID", "ocr-worker"),
+			Brokers:            os.Getenv("KAFKA_BROKERS"),
+			Topic:              getEnv("KAFKA_TOPIC_OCR_REQUESTS", "ocr.requests"),
+			GroupID:            getEnv("KAFKA_GROUP_ID", "ocr-worker"),
+			MaxInFlight:        getEnvInt("OCR_MAX_IN_FLIGHT", 8),
+			TaskTimeoutMinutes: getEnvInt("OCR_TASK_TIMEOUT_MINUTES", 10),
 		},
 		Material: MaterialCallbackConfig{
-			Addr: getEnv("MATERIAL_GRPC_ADDR", "material-service:50053"),
+			Addr: registry.Material.Addr(),
 		},
 		OpenAI: OpenAIConfig{
 			APIKey:  os.Getenv("OPENAI_API_KEY"),
 			BaseURL: os.Getenv("OPENAI_BASE_URL"),
 			Model:   getEnv("OPENAI_MODEL", "gpt-4o-mini"),
 		},
+		Preprocess: PreprocessConfig{
+			MinWidth: getEnvInt("OCR_PREPROCESS_MIN_WIDTH", 1600),
+		},
+		Extract: ExtractConfig{
+			MinTextChars: getEnvInt("EXTRACT_MIN_TEXT_CHARS", 50),
+		},
+		Formula: FormulaConfig{
+			Provider:      os.Getenv("FORMULA_PROVIDER"),
+			Endpoint:      os.Getenv("FORMULA_ENDPOINT"),
+			AppID:         os.Getenv("MATHPIX_APP_ID"),
+			AppKey:        os.Getenv("MATHPIX_APP_KEY"),
+			TimeoutSecond: getEnvInt("FORMULA_TIMEOUT", 20),
+		},
 	}
 }
 
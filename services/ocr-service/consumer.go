@@ -0,0 +1,295 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/RigelNana/arkstudy/pkg/discovery"
+	arkkafka "github.com/RigelNana/arkstudy/pkg/kafka"
+	kafkaMetrics "github.com/RigelNana/arkstudy/pkg/metrics/kafka"
+	"github.com/RigelNana/arkstudy/pkg/registry"
+	"github.com/RigelNana/arkstudy/proto/ai"
+	mpb "github.com/RigelNana/arkstudy/proto/material"
+	"github.com/RigelNana/arkstudy/services/ocr-service/config"
+	"github.com/RigelNana/arkstudy/services/ocr-service/service"
+	kafka "github.com/segmentio/kafka-go"
+)
+
+// 完成调度器检查在途任务状态的间隔
+const dispatchInterval = 2 * time.Second
+
+// pendingJob 已启动、等待完成的 OCR 任务
+type pendingJob struct {
+	msg          kafka.Message
+	job          ocrJob
+	startedAt    time.Time
+	deadline     time.Time
+	lastProgress float32
+	confidence   float32 // 完成时的识别置信度
+}
+
+// consumer 将任务接收与完成处理分离：接收循环只负责拉取消息并启动任务，随后把任务交给完成调度器，
+// 由调度器统一跟踪所有在途任务、转发进度、回调 material-service 并提交 offset。
+// 在途任务数达到 MaxInFlight 时接收循环暂停拉取，形成背压，而不是让单条消息阻塞整个分区
+type consumer struct {
+	cfg     *config.Config
+	svc     *service.OCRService
+	reader  *kafka.Reader
+	writer  *kafka.Writer
+	mcli    mpb.MaterialServiceClient
+	offsets *offsetTracker
+	// slots 在途任务槽位，接收前获取，任务完成后释放
+	slots chan struct{}
+	// queue 接收循环交给完成调度器的任务
+	queue chan *pendingJob
+}
+
+func startConsumer(cfg *config.Config, svc *service.OCRService) {
+	brokers := arkkafka.SplitBrokers(cfg.Kafka.Brokers)
+	// 消费组内的副本按分区分配任务，副本数上限为 ocr.requests 的分区数（见 pkg/kafka）
+	r := kafkaMetrics.InstrumentReader("ocr-service", arkkafka.NewReader(brokers, cfg.Kafka.GroupID, cfg.Kafka.Topic))
+	defer r.Close()
+	log.Printf("Kafka consumer started: topic=%s group=%s", cfg.Kafka.Topic, cfg.Kafka.GroupID)
+
+	// Kafka writer for text.extracted topic
+	textExtractedWriter := kafkaMetrics.InstrumentWriter("ocr-service", arkkafka.NewWriter(brokers, arkkafka.TextExtracted.Name))
+	defer textExtractedWriter.Close()
+
+	// material-service callback client
+	conn, err := discovery.DialAddr(registry.Material.Name, cfg.Material.Addr)
+	if err != nil {
+		log.Printf("dial material-service: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	maxInFlight := cfg.Kafka.MaxInFlight
+	if maxInFlight <= 0 {
+		maxInFlight = 1
+	}
+	c := &consumer{
+		cfg:     cfg,
+		svc:     svc,
+		reader:  r,
+		writer:  textExtractedWriter,
+		mcli:    mpb.NewMaterialServiceClient(conn),
+		offsets: newOffsetTracker(),
+		slots:   make(chan struct{}, maxInFlight),
+		queue:   make(chan *pendingJob, maxInFlight),
+	}
+	go c.dispatch()
+	c.intake()
+}
+
+// intake 拉取任务消息并启动 OCR，不等待任务完成
+func (c *consumer) intake() {
+	timeout := time.Duration(c.cfg.Kafka.TaskTimeoutMinutes) * time.Minute
+	for {
+		c.slots <- struct{}{}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		msg, err := kafkaMetrics.FetchMessage(ctx, c.reader)
+		cancel()
+		if err != nil {
+			<-c.slots
+			if ctx.Err() != nil {
+				continue
+			}
+			log.Printf("kafka fetch: %v", err)
+			time.Sleep(time.Second)
+			continue
+		}
+		c.offsets.add(msg)
+
+		var job ocrJob
+		if err := json.Unmarshal(msg.Value, &job); err != nil {
+			log.Printf("bad job json: %v", err)
+			c.commit(msg)
+			<-c.slots
+			continue
+		}
+		// Run OCR via svc
+		startedAt := time.Now()
+		tctx, cancel2 := context.WithTimeout(arkkafka.RequestIDContext(context.Background(), msg), 10*time.Second)
+		_, err = c.svc.ProcessOCR(tctx, &ai.OCRRequest{TaskId: job.TaskID, FileUrl: job.FileURL, FileType: job.FileType, Options: job.Options})
+		cancel2()
+		if err != nil {
+			log.Printf("ProcessOCR start err: %v", err)
+		}
+		c.queue <- &pendingJob{msg: msg, job: job, startedAt: startedAt, deadline: startedAt.Add(timeout)}
+	}
+}
+
+// dispatch 完成调度器：定期检查所有在途任务，转发进度并处理已结束的任务
+func (c *consumer) dispatch() {
+	ticker := time.NewTicker(dispatchInterval)
+	defer ticker.Stop()
+	var inFlight []*pendingJob
+	for {
+		select {
+		case p := <-c.queue:
+			inFlight = append(inFlight, p)
+		case <-ticker.C:
+			remaining := inFlight[:0]
+			for _, p := range inFlight {
+				if !c.check(p) {
+					remaining = append(remaining, p)
+				}
+			}
+			inFlight = remaining
+		}
+	}
+}
+
+// check 检查任务状态，任务已结束（完成、失败或超时）时完成回调并返回 true
+func (c *consumer) check(p *pendingJob) bool {
+	sctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	st, err := c.svc.GetTaskStatus(sctx, &ai.TaskStatusRequest{TaskId: p.job.TaskID})
+	cancel()
+	if err != nil {
+		if time.Now().After(p.deadline) {
+			c.finish(p, mpb.ProcessingStatus_FAILED, "", "ocr task timed out")
+			return true
+		}
+		return false
+	}
+	switch st.Status {
+	case ai.TaskStatus_COMPLETED:
+		rctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		resp, err := c.svc.ProcessOCR(rctx, &ai.OCRRequest{TaskId: p.job.TaskID})
+		cancel()
+		if err != nil {
+			c.finish(p, mpb.ProcessingStatus_FAILED, "", err.Error())
+		} else {
+			p.confidence = resp.GetConfidence()
+			c.finish(p, mpb.ProcessingStatus_COMPLETED, resp.GetText(), "")
+		}
+		return true
+	case ai.TaskStatus_FAILED:
+		c.finish(p, mpb.ProcessingStatus_FAILED, "", st.ErrorMessage)
+		return true
+	}
+	if time.Now().After(p.deadline) {
+		c.finish(p, mpb.ProcessingStatus_FAILED, "", "ocr task timed out")
+		return true
+	}
+	// Forward progress changes so the UI can render a progress bar
+	if st.Status == ai.TaskStatus_PROCESSING && st.Progress > p.lastProgress {
+		p.lastProgress = st.Progress
+		pctx, cancelP := context.WithTimeout(context.Background(), 5*time.Second)
+		if _, err := c.mcli.UpdateProcessingProgress(pctx, &mpb.UpdateProcessingProgressRequest{TaskId: p.job.TaskID, Progress: p.lastProgress}); err != nil {
+			log.Printf("update processing progress: %v", err)
+		}
+		cancelP()
+	}
+	return false
+}
+
+// finish 回调 material-service，成功时发布 text.extracted，然后提交 offset 并释放槽位
+func (c *consumer) finish(p *pendingJob, status mpb.ProcessingStatus, content, errMsg string) {
+	defer func() { <-c.slots }()
+
+	// Callback material-service，附带识别开始与结束时间（material-service 的 started / recognized 阶段）
+	metadata := map[string]string{
+		"source":     "ocr-service",
+		"started_at": p.startedAt.UTC().Format(time.RFC3339Nano),
+	}
+	// 附带实际使用的引擎与置信度，供 material-service 对比不同引擎的结果
+	if opts, err := service.ParseOCROptions(p.job.Options, c.cfg.Engine); err == nil {
+		metadata["engine"] = opts.Engine
+	}
+	if status == mpb.ProcessingStatus_COMPLETED {
+		metadata["recognized_at"] = time.Now().UTC().Format(time.RFC3339Nano)
+		metadata["confidence"] = strconv.FormatFloat(float64(p.confidence), 'f', 4, 32)
+	}
+	// Carry the request ID of the ocr.requests message to material-service and text.extracted
+	rctx := arkkafka.RequestIDContext(context.Background(), p.msg)
+	uctx, cancel := context.WithTimeout(rctx, 10*time.Second)
+	_, err := c.mcli.UpdateProcessingResult(uctx, &mpb.UpdateProcessingResultRequest{
+		TaskId:       p.job.TaskID,
+		Status:       status,
+		Content:      content,
+		Metadata:     metadata,
+		ErrorMessage: errMsg,
+	})
+	cancel()
+	// 对比处理（shadow）的结果只保存在 material-service，不发布 text.extracted，避免替换资料的检索内容
+	shadow := p.job.Options["shadow"] == "true"
+	if err != nil {
+		log.Printf("update processing result (request_id=%s): %v", arkkafka.RequestID(p.msg), err)
+	} else if status == mpb.ProcessingStatus_COMPLETED && !shadow {
+		// Publish to text.extracted topic
+		extractedPayload, _ := json.Marshal(map[string]string{
+			"material_id": p.job.MaterialID,
+			"user_id":     p.job.UserID,
+			"text":        content,
+			"source":      "ocr",
+		})
+		err = kafkaMetrics.WriteMessages(rctx, c.writer, kafka.Message{
+			Key:   arkkafka.MaterialKey(p.job.MaterialID),
+			Value: extractedPayload,
+		})
+		if err != nil {
+			log.Printf("failed to write message to text.extracted topic: %v", err)
+		}
+	}
+
+	c.commit(p.msg)
+}
+
+// commit 标记消息处理完毕，并提交同一分区内此前所有消息均已完成的最大 offset
+func (c *consumer) commit(msg kafka.Message) {
+	if upTo, ok := c.offsets.complete(msg); ok {
+		_ = kafkaMetrics.CommitMessages(context.Background(), c.reader, upTo)
+	}
+}
+
+// offsetTracker 按分区记录已拉取但未提交的消息。任务完成顺序与拉取顺序不同，
+// 而提交某条消息的 offset 会同时确认该分区之前的所有消息，因此只能提交连续完成的前缀
+type offsetTracker struct {
+	mu      sync.Mutex
+	pending map[int][]*trackedMessage
+}
+
+type trackedMessage struct {
+	msg  kafka.Message
+	done bool
+}
+
+func newOffsetTracker() *offsetTracker {
+	return &offsetTracker{pending: map[int][]*trackedMessage{}}
+}
+
+func (t *offsetTracker) add(msg kafka.Message) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.pending[msg.Partition] = append(t.pending[msg.Partition], &trackedMessage{msg: msg})
+}
+
+// complete 标记 msg 已完成，返回可以提交的最后一条消息；前面仍有未完成的消息时返回 false
+func (t *offsetTracker) complete(msg kafka.Message) (kafka.Message, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	list := t.pending[msg.Partition]
+	for _, m := range list {
+		if m.msg.Offset == msg.Offset {
+			m.done = true
+			break
+		}
+	}
+	var upTo kafka.Message
+	n := 0
+	for n < len(list) && list[n].done {
+		upTo = list[n].msg
+		n++
+	}
+	if n == 0 {
+		return kafka.Message{}, false
+	}
+	t.pending[msg.Partition] = list[n:]
+	return upTo, true
+}
@@ -0,0 +1,262 @@
+// Package extract 直接从 Office Open XML 文档（.docx/.pptx）中提取文本，
+// 避免对自带文字层的文档走 OCR。
+package extract
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// 文档类型
+const (
+	KindDocx = "docx"
+	KindPptx = "pptx"
+)
+
+const (
+	// 单个 XML 部件的解压上限，防止 zip 炸弹
+	maxPartSize = 32 << 20
+	// 最多返回的内嵌图片数量与单张大小
+	maxImages    = 20
+	maxImageSize = 10 << 20
+)
+
+// Image 为文档内嵌的图片，扫描件通常以图片形式存在
+type Image struct {
+	Name string
+	Data []byte
+}
+
+// Document 为提取结果
+type Document struct {
+	Kind   string
+	Text   string
+	Images []Image
+}
+
+// Detect 根据 zip 内容判断是否为 docx/pptx，其他格式返回空字符串
+func Detect(data []byte) string {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return ""
+	}
+	for _, f := range zr.File {
+		switch f.Name {
+		case "word/document.xml":
+			return KindDocx
+		case "ppt/presentation.xml":
+			return KindPptx
+		}
+	}
+	return ""
+}
+
+// Extract 提取文档文本与内嵌图片
+func Extract(data []byte) (*Document, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("open office document: %w", err)
+	}
+
+	files := make(map[string]*zip.File, len(zr.File))
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+
+	doc := &Document{}
+	switch {
+	case files["word/document.xml"] != nil:
+		doc.Kind = KindDocx
+		doc.Text, err = extractDocx(files)
+	case files["ppt/presentation.xml"] != nil:
+		doc.Kind = KindPptx
+		doc.Text, err = extractPptx(files)
+	default:
+		return nil, fmt.Errorf("not a docx or pptx document")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	doc.Images, err = collectImages(zr.File, doc.Kind)
+	if err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// extractDocx 读取正文，并附加页眉页脚
+func extractDocx(files map[string]*zip.File) (string, error) {
+	body, err := partText(files["word/document.xml"])
+	if err != nil {
+		return "", err
+	}
+
+	parts := []string{body}
+	for _, name := range sortedNames(files, "word/header", "word/footer") {
+		text, err := partText(files[name])
+		if err != nil {
+			return "", err
+		}
+		parts = append(parts, text)
+	}
+	return joinNonEmpty(parts, "\n\n"), nil
+}
+
+// extractPptx 按幻灯片顺序读取文本，每页附加演讲者备注
+func extractPptx(files map[string]*zip.File) (string, error) {
+	var slides []string
+	for _, name := range sortedNames(files, "ppt/slides/slide") {
+		text, err := partText(files[name])
+		if err != nil {
+			return "", err
+		}
+
+		notesName := strings.Replace(name, "ppt/slides/slide", "ppt/notesSlides/notesSlide", 1)
+		if notes, ok := files[notesName]; ok {
+			notesText, err := partText(notes)
+			if err != nil {
+				return "", err
+			}
+			text = joinNonEmpty([]string{text, notesText}, "\n")
+		}
+		slides = append(slides, text)
+	}
+	return joinNonEmpty(slides, "\n\n"), nil
+}
+
+// partText 解析 WordprocessingML / DrawingML 部件中的文本。
+// w:t、a:t 为文本，w:tab 为制表符，w:br、a:br 为换行，段落与表格行结束时换行。
+func partText(f *zip.File) (string, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return "", fmt.Errorf("open %s: %w", f.Name, err)
+	}
+	defer rc.Close()
+
+	dec := xml.NewDecoder(io.LimitReader(rc, maxPartSize))
+	var b strings.Builder
+	inText := false
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("parse %s: %w", f.Name, err)
+		}
+
+		switch el := tok.(type) {
+		case xml.StartElement:
+			switch el.Name.Local {
+			case "t":
+				inText = true
+			case "tab":
+				b.WriteString("\t")
+			case "br", "cr":
+				b.WriteString("\n")
+			}
+		case xml.EndElement:
+			switch el.Name.Local {
+			case "t":
+				inText = false
+			case "p", "tr":
+				b.WriteString("\n")
+			case "tc":
+				b.WriteString("\t")
+			}
+		case xml.CharData:
+			if inText {
+				b.Write(el)
+			}
+		}
+	}
+	return strings.TrimSpace(b.String()), nil
+}
+
+// collectImages 读取 media 目录下的位图，供扫描内容回退 OCR
+func collectImages(files []*zip.File, kind string) ([]Image, error) {
+	prefix := "word/media/"
+	if kind == KindPptx {
+		prefix = "ppt/media/"
+	}
+
+	var images []Image
+	for _, f := range files {
+		if len(images) >= maxImages {
+			break
+		}
+		if !strings.HasPrefix(f.Name, prefix) || f.UncompressedSize64 > maxImageSize {
+			continue
+		}
+		switch strings.ToLower(path.Ext(f.Name)) {
+		case ".png", ".jpg", ".jpeg", ".gif", ".bmp", ".tif", ".tiff":
+		default:
+			continue
+		}
+
+		rc, err := f.Open()
+		if err != nil {
+			return nil, fmt.Errorf("open %s: %w", f.Name, err)
+		}
+		data, err := io.ReadAll(io.LimitReader(rc, maxImageSize))
+		rc.Close()
+		if err != nil {
+			return nil, fmt.Errorf("read %s: %w", f.Name, err)
+		}
+		images = append(images, Image{Name: path.Base(f.Name), Data: data})
+	}
+	return images, nil
+}
+
+// sortedNames 返回以任一前缀开头的 XML 部件，按名称中的序号排序（slide2 在 slide10 之前）
+func sortedNames(files map[string]*zip.File, prefixes ...string) []string {
+	var names []string
+	for name := range files {
+		if !strings.HasSuffix(name, ".xml") {
+			continue
+		}
+		for _, p := range prefixes {
+			if strings.HasPrefix(name, p) && !strings.Contains(strings.TrimPrefix(name, p), "/") {
+				names = append(names, name)
+				break
+			}
+		}
+	}
+	sort.Slice(names, func(i, j int) bool {
+		ni, nj := partNumber(names[i]), partNumber(names[j])
+		if ni != nj {
+			return ni < nj
+		}
+		return names[i] < names[j]
+	})
+	return names
+}
+
+// partNumber 提取部件名末尾的数字，如 ppt/slides/slide12.xml -> 12
+func partNumber(name string) int {
+	base := strings.TrimSuffix(path.Base(name), ".xml")
+	i := len(base)
+	for i > 0 && base[i-1] >= '0' && base[i-1] <= '9' {
+		i--
+	}
+	n, _ := strconv.Atoi(base[i:])
+	return n
+}
+
+func joinNonEmpty(parts []string, sep string) string {
+	var out []string
+	for _, p := range parts {
+		if p = strings.TrimSpace(p); p != "" {
+			out = append(out, p)
+		}
+	}
+	return strings.Join(out, sep)
+}
@@ -0,0 +1,213 @@
+package service
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"image"
+	"io"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/RigelNana/arkstudy/proto/ai"
+	"github.com/disintegration/imaging"
+)
+
+// 公式识别后端
+const (
+	FormulaProviderMathpix = "mathpix" // Mathpix 兼容的 /v3/text 接口，整图识别并在文本中内联 LaTeX
+	FormulaProviderPix2Tex = "pix2tex" // pix2tex (LaTeX-OCR) API，对疑似公式的文本框逐个裁剪识别
+)
+
+// 行内公式分隔符，与 Mathpix 默认输出保持一致
+const (
+	latexInlineOpen  = "$"
+	latexInlineClose = "$"
+)
+
+// 包含这些字符的文本行被视为疑似公式
+const formulaHintChars = "=^_∑∫√∞≤≥≠±×÷∂∇αβγδθλμπσφω∈∉⊂⊆∀∃→⇒"
+
+// formulaEnabled 当前请求是否需要并且能够做公式识别
+func (s *OCRService) formulaEnabled(opts *OCROptions) bool {
+	return opts.Formula && s.cfg.Formula.Provider != "" && s.cfg.Formula.Endpoint != ""
+}
+
+// mathpixResponse 为 /v3/text 的返回结构（仅取用到的字段）
+type mathpixResponse struct {
+	Text       string  `json:"text"`
+	Confidence float64 `json:"confidence"`
+	Error      string  `json:"error"`
+}
+
+// callMathpix 整图识别，返回内联 LaTeX（$...$）的文本
+func (s *OCRService) callMathpix(data []byte) (string, float32, error) {
+	body, err := json.Marshal(map[string]interface{}{
+		"src":                    fmt.Sprintf("data:%s;base64,%s", http.DetectContentType(data), base64.StdEncoding.EncodeToString(data)),
+		"formats":                []string{"text"},
+		"math_inline_delimiters": []string{latexInlineOpen, latexInlineClose},
+		"rm_spaces":              true,
+	})
+	if err != nil {
+		return "", 0, err
+	}
+
+	req, err := http.NewRequest("POST", s.cfg.Formula.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return "", 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.cfg.Formula.AppID != "" {
+		req.Header.Set("app_id", s.cfg.Formula.AppID)
+		req.Header.Set("app_key", s.cfg.Formula.AppKey)
+	}
+
+	raw, err := s.doFormulaRequest(req)
+	if err != nil {
+		return "", 0, err
+	}
+
+	var resp mathpixResponse
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		return "", 0, fmt.Errorf("decode mathpix resp: %v; raw=%s", err, string(raw))
+	}
+	if resp.Error != "" {
+		return "", 0, fmt.Errorf("mathpix: %s", resp.Error)
+	}
+	return resp.Text, float32(resp.Confidence), nil
+}
+
+// callPix2Tex 识别单个公式图片，返回 LaTeX 源码
+func (s *OCRService) callPix2Tex(data []byte) (string, error) {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	fw, err := writer.CreateFormFile("file", "formula.png")
+	if err != nil {
+		return "", err
+	}
+	if _, err := fw.Write(data); err != nil {
+		return "", err
+	}
+	writer.Close()
+
+	req, err := http.NewRequest("POST", s.cfg.Formula.Endpoint, &body)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+
+	raw, err := s.doFormulaRequest(req)
+	if err != nil {
+		return "", err
+	}
+
+	// pix2tex API 直接返回 JSON 字符串
+	var latex string
+	if err := json.Unmarshal(raw, &latex); err != nil {
+		latex = string(raw)
+	}
+	return strings.TrimSpace(latex), nil
+}
+
+func (s *OCRService) doFormulaRequest(req *http.Request) ([]byte, error) {
+	httpClient := &http.Client{Timeout: time.Duration(s.cfg.Formula.TimeoutSecond) * time.Second}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("formula http %d: %s", resp.StatusCode, string(raw))
+	}
+	return raw, nil
+}
+
+// applyPix2Tex 将 PaddleOCR 结果中疑似公式的文本框裁剪后交给 pix2tex，
+// 并把对应行替换为行内 LaTeX。单个公式识别失败时保留原文。
+func (s *OCRService) applyPix2Tex(data []byte, result *ai.OCRResponse) {
+	var candidates []*ai.BoundingBox
+	for _, box := range result.Boxes {
+		if looksLikeFormula(box.Text) && box.Width > 0 && box.Height > 0 {
+			candidates = append(candidates, box)
+		}
+	}
+	if len(candidates) == 0 {
+		return
+	}
+
+	img, err := imaging.Decode(bytes.NewReader(data))
+	if err != nil {
+		log.Printf("formula: decode image failed: %v", err)
+		return
+	}
+
+	lines := strings.Split(result.Text, "\n")
+	next := 0
+	replaced := 0
+	for _, box := range candidates {
+		// 在文本中按顺序定位该框对应的行
+		idx := -1
+		for i := next; i < len(lines); i++ {
+			if lines[i] == box.Text {
+				idx = i
+				break
+			}
+		}
+		if idx < 0 {
+			continue
+		}
+		next = idx + 1
+
+		pad := int(box.Height * 0.15)
+		rect := image.Rect(int(box.X)-pad, int(box.Y)-pad, int(box.X+box.Width)+pad, int(box.Y+box.Height)+pad)
+		crop := imaging.Crop(img, rect)
+		var buf bytes.Buffer
+		if err := imaging.Encode(&buf, crop, imaging.PNG); err != nil {
+			continue
+		}
+
+		latex, err := s.callPix2Tex(buf.Bytes())
+		if err != nil || latex == "" {
+			log.Printf("formula: pix2tex failed for %q: %v", box.Text, err)
+			continue
+		}
+		box.Text = latexInlineOpen + latex + latexInlineClose
+		lines[idx] = box.Text
+		replaced++
+	}
+
+	if replaced > 0 {
+		result.Text = strings.Join(lines, "\n")
+		log.Printf("formula: converted %d of %d candidate lines to LaTeX", replaced, len(candidates))
+	}
+}
+
+// looksLikeFormula 粗略判断一行 OCR 文本是否包含数学公式
+func looksLikeFormula(text string) bool {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return false
+	}
+	var hints, digits, letters int
+	for _, r := range text {
+		switch {
+		case strings.ContainsRune(formulaHintChars, r):
+			hints++
+		case r >= '0' && r <= '9':
+			digits++
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z':
+			letters++
+		}
+	}
+	// 至少一个数学符号，且主体由字母数字构成（排除普通中文句子里出现的等号）
+	total := len([]rune(text))
+	return hints > 0 && (hints+digits+letters)*2 >= total
+}
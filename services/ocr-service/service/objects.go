@@ -0,0 +1,41 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// objectReadPolicy 仅允许读取单个对象的会话策略
+const objectReadPolicy = `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":["s3:GetObject"],"Resource":["arn:aws:s3:::%s/%s"]}]}`
+
+// objectClient 返回读取 bucket/object 使用的 MinIO 客户端。
+// 启用 STS 时在读取时刻签发仅能读取该对象的临时凭证，任务排队或重试多久都不会因 URL 过期而失败，
+// 泄露的凭证也无法访问其他资料；未启用时使用服务自身的凭证
+func (s *OCRService) objectClient(bucket, object string) (*minio.Client, error) {
+	if !s.cfg.MinIO.STSEnabled {
+		return s.minio, nil
+	}
+	scheme := "http"
+	if s.cfg.MinIO.UseSSL {
+		scheme = "https"
+	}
+	creds, err := credentials.NewSTSAssumeRole(scheme+"://"+s.cfg.MinIO.Endpoint, credentials.STSAssumeRoleOptions{
+		AccessKey:       s.cfg.MinIO.AccessKeyID,
+		SecretKey:       s.cfg.MinIO.SecretAccessKey,
+		Policy:          fmt.Sprintf(objectReadPolicy, bucket, object),
+		DurationSeconds: s.cfg.MinIO.STSDurationSeconds,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("sts assume role: %w", err)
+	}
+	// 立即签发，在读取前暴露 STS 错误
+	if _, err := creds.Get(); err != nil {
+		return nil, fmt.Errorf("sts assume role: %w", err)
+	}
+	return minio.New(s.cfg.MinIO.Endpoint, &minio.Options{
+		Creds:  creds,
+		Secure: s.cfg.MinIO.UseSSL,
+	})
+}
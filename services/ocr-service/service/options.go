@@ -0,0 +1,124 @@
+package service
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// OCR 引擎
+const (
+	EngineOpenAI = "openai"
+	EnginePaddle = "paddle"
+)
+
+// 支持透传的选项键（来自 OCRRequest.options / ocrJob.Options）
+const (
+	OptionEngine     = "engine"        // openai | paddle
+	OptionLanguage   = "lang"          // 识别语言，如 ch、en、japan
+	OptionDet        = "det"           // 是否执行文本检测
+	OptionRec        = "rec"           // 是否执行文本识别
+	OptionAngleCls   = "use_angle_cls" // 是否做方向分类（旋转矫正）
+	OptionTableMode  = "table"         // 表格模式，走版面/表格识别
+	OptionPreprocess = "preprocess"    // 图像预处理步骤，如 all 或 deskew,contrast
+	OptionFormula    = "formula"       // 公式识别，输出行内 LaTeX
+)
+
+// OCROptions 为解析后的请求选项
+type OCROptions struct {
+	Engine     string
+	Language   string
+	Det        bool
+	Rec        bool
+	AngleCls   bool
+	TableMode  bool
+	Preprocess PreprocessOptions
+	Formula    bool
+}
+
+// ParseOCROptions 解析选项并填充默认值，未知键会被忽略
+func ParseOCROptions(raw map[string]string, defaultEngine string) (*OCROptions, error) {
+	opts := &OCROptions{
+		Engine:   defaultEngine,
+		Language: "ch",
+		Det:      true,
+		Rec:      true,
+		AngleCls: true,
+	}
+
+	if v := strings.ToLower(strings.TrimSpace(raw[OptionEngine])); v != "" {
+		if v != EngineOpenAI && v != EnginePaddle {
+			return nil, fmt.Errorf("unsupported ocr engine %q", v)
+		}
+		opts.Engine = v
+	}
+	if v := strings.TrimSpace(raw[OptionLanguage]); v != "" {
+		opts.Language = v
+	}
+
+	var err error
+	if opts.Det, err = parseBoolOption(raw, OptionDet, opts.Det); err != nil {
+		return nil, err
+	}
+	if opts.Rec, err = parseBoolOption(raw, OptionRec, opts.Rec); err != nil {
+		return nil, err
+	}
+	if opts.AngleCls, err = parseBoolOption(raw, OptionAngleCls, opts.AngleCls); err != nil {
+		return nil, err
+	}
+	if opts.TableMode, err = parseBoolOption(raw, OptionTableMode, opts.TableMode); err != nil {
+		return nil, err
+	}
+	if opts.Formula, err = parseBoolOption(raw, OptionFormula, opts.Formula); err != nil {
+		return nil, err
+	}
+	if opts.Preprocess, err = parsePreprocessOption(raw[OptionPreprocess]); err != nil {
+		return nil, err
+	}
+	if !opts.Det && !opts.Rec {
+		return nil, fmt.Errorf("det and rec cannot both be disabled")
+	}
+
+	return opts, nil
+}
+
+func parseBoolOption(raw map[string]string, key string, def bool) (bool, error) {
+	v := strings.TrimSpace(raw[key])
+	if v == "" {
+		return def, nil
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, fmt.Errorf("invalid value %q for option %s", v, key)
+	}
+	return b, nil
+}
+
+// paddleFields 转为 PaddleOCR 服务的表单参数
+func (o *OCROptions) paddleFields() map[string]string {
+	return map[string]string{
+		"lang":          o.Language,
+		"det":           strconv.FormatBool(o.Det),
+		"rec":           strconv.FormatBool(o.Rec),
+		"use_angle_cls": strconv.FormatBool(o.AngleCls),
+	}
+}
+
+// openAIPrompt 根据选项生成视觉模型的提示词
+func (o *OCROptions) openAIPrompt() string {
+	var b strings.Builder
+	b.WriteString("Extract all text from this image.")
+	if o.Language != "" && o.Language != "ch" {
+		fmt.Fprintf(&b, " The text is mainly in language code %q.", o.Language)
+	}
+	if o.AngleCls {
+		b.WriteString(" The image may be rotated; read it in its upright orientation.")
+	}
+	if o.TableMode {
+		b.WriteString(" Render any tables as Markdown tables and keep their row and column structure.")
+	}
+	if o.Formula {
+		b.WriteString(" Transcribe mathematical expressions as LaTeX wrapped in $...$, inline with the surrounding text.")
+	}
+	return b.String()
+}
@@ -0,0 +1,186 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"log"
+	"math"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/disintegration/imaging"
+)
+
+// 预处理步骤名称，对应选项 preprocess 的取值（逗号分隔，all 表示全部）
+const (
+	PreprocessDeskew   = "deskew"
+	PreprocessDenoise  = "denoise"
+	PreprocessContrast = "contrast"
+	PreprocessUpscale  = "upscale"
+)
+
+const (
+	// 纠偏角度搜索范围与步长（度）
+	deskewMaxAngle = 10.0
+	deskewStep     = 0.5
+	// 小于该角度不做旋转，避免无谓的插值损失
+	deskewMinAngle = 0.3
+	// 估计倾角时先缩放到该宽度以控制计算量
+	deskewSampleWidth = 600
+)
+
+// PreprocessOptions 控制 OCR 前的图像预处理
+type PreprocessOptions struct {
+	Deskew   bool
+	Denoise  bool
+	Contrast bool
+	Upscale  bool
+}
+
+// Enabled 是否启用了任一预处理步骤
+func (p PreprocessOptions) Enabled() bool {
+	return p.Deskew || p.Denoise || p.Contrast || p.Upscale
+}
+
+// parsePreprocessOption 解析 preprocess 选项，如 "all" 或 "deskew,contrast"
+func parsePreprocessOption(v string) (PreprocessOptions, error) {
+	var p PreprocessOptions
+	v = strings.ToLower(strings.TrimSpace(v))
+	switch v {
+	case "", "none", "false":
+		return p, nil
+	case "all", "auto", "true":
+		return PreprocessOptions{Deskew: true, Denoise: true, Contrast: true, Upscale: true}, nil
+	}
+
+	for _, step := range strings.Split(v, ",") {
+		switch strings.TrimSpace(step) {
+		case PreprocessDeskew:
+			p.Deskew = true
+		case PreprocessDenoise:
+			p.Denoise = true
+		case PreprocessContrast:
+			p.Contrast = true
+		case PreprocessUpscale:
+			p.Upscale = true
+		case "":
+		default:
+			return p, fmt.Errorf("unsupported preprocess step %q", step)
+		}
+	}
+	return p, nil
+}
+
+// preprocessImage 对图片执行预处理，返回 PNG 编码后的数据与新文件名。
+// 非图片或解码失败时原样返回，预处理失败不应阻断 OCR。
+func (s *OCRService) preprocessImage(data []byte, filename string, opts PreprocessOptions) ([]byte, string) {
+	if !opts.Enabled() || !strings.HasPrefix(http.DetectContentType(data), "image/") {
+		return data, filename
+	}
+
+	// AutoOrientation 根据 EXIF 方向信息摆正手机照片
+	img, err := imaging.Decode(bytes.NewReader(data), imaging.AutoOrientation(true))
+	if err != nil {
+		log.Printf("preprocess: decode %s failed, using original: %v", filename, err)
+		return data, filename
+	}
+
+	if opts.Upscale {
+		img = upscaleImage(img, s.cfg.Preprocess.MinWidth)
+	}
+	if opts.Deskew {
+		if angle := estimateSkew(img); math.Abs(angle) >= deskewMinAngle {
+			img = imaging.Rotate(img, angle, color.White)
+		}
+	}
+	if opts.Denoise {
+		img = imaging.Blur(img, 0.7)
+	}
+	if opts.Contrast {
+		img = imaging.Grayscale(img)
+		img = imaging.AdjustContrast(img, 25)
+		img = imaging.Sharpen(img, 1)
+	}
+
+	var buf bytes.Buffer
+	if err := imaging.Encode(&buf, img, imaging.PNG); err != nil {
+		log.Printf("preprocess: encode %s failed, using original: %v", filename, err)
+		return data, filename
+	}
+
+	name := strings.TrimSuffix(filename, path.Ext(filename)) + ".png"
+	return buf.Bytes(), name
+}
+
+// upscaleImage 放大过小的图片（相当于提高 DPI），使宽度至少为 minWidth
+func upscaleImage(img image.Image, minWidth int) image.Image {
+	if minWidth <= 0 || img.Bounds().Dx() >= minWidth {
+		return img
+	}
+	return imaging.Resize(img, minWidth, 0, imaging.Lanczos)
+}
+
+// estimateSkew 使用投影轮廓法估计文字行的倾斜角（度）。
+// 对每个候选角度按剪切后的行坐标统计深色像素直方图，文字行对齐时直方图起伏最大。
+// 返回值可直接传给 imaging.Rotate（逆时针为正）进行矫正。
+func estimateSkew(img image.Image) float64 {
+	sample := imaging.Grayscale(img)
+	if sample.Bounds().Dx() > deskewSampleWidth {
+		sample = imaging.Resize(sample, deskewSampleWidth, 0, imaging.Box)
+	}
+	bounds := sample.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	if w == 0 || h == 0 {
+		return 0
+	}
+
+	// 以平均亮度为阈值二值化，收集深色像素
+	var sum int
+	for i := 0; i < len(sample.Pix); i += 4 {
+		sum += int(sample.Pix[i])
+	}
+	threshold := uint8(sum / (w * h) * 3 / 4)
+
+	type point struct{ x, y int }
+	var dark []point
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			if sample.Pix[y*sample.Stride+x*4] < threshold {
+				dark = append(dark, point{x, y})
+			}
+		}
+	}
+	if len(dark) == 0 {
+		return 0
+	}
+
+	bestAngle, bestScore := 0.0, -1.0
+	offset := int(float64(w)*math.Tan(deskewMaxAngle*math.Pi/180)) + 1
+	hist := make([]float64, h+2*offset)
+	for angle := -deskewMaxAngle; angle <= deskewMaxAngle; angle += deskewStep {
+		slope := math.Tan(angle * math.Pi / 180)
+		for i := range hist {
+			hist[i] = 0
+		}
+		for _, p := range dark {
+			row := p.y - int(math.Round(float64(p.x)*slope)) + offset
+			if row >= 0 && row < len(hist) {
+				hist[row]++
+			}
+		}
+		var score float64
+		for i := 1; i < len(hist); i++ {
+			d := hist[i] - hist[i-1]
+			score += d * d
+		}
+		if score > bestScore {
+			bestScore = score
+			bestAngle = angle
+		}
+	}
+
+	return bestAngle
+}
@@ -6,17 +6,21 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"log"
 	"mime/multipart"
 	"net/http"
 	"net/url"
 	"path"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"encoding/base64"
 
+	"github.com/RigelNana/arkstudy/pkg/metrics"
 	"github.com/RigelNana/arkstudy/proto/ai"
 	"github.com/RigelNana/arkstudy/services/ocr-service/config"
+	"github.com/RigelNana/arkstudy/services/ocr-service/extract"
 	"github.com/google/uuid"
 	"github.com/minio/minio-go/v7"
 	"github.com/minio/minio-go/v7/pkg/credentials"
@@ -31,6 +35,8 @@ type OCRService struct {
 	statusStore  map[string]*ai.TaskStatusResponse
 	// 缓存最终 OCR 结果，键为 task_id
 	ocrStore map[string]*ai.OCRResponse
+	// PaddleOCR 并发槽位，限制同时发往推理容器的请求数
+	paddleSlots chan struct{}
 }
 
 func NewOCRService(cfg *config.Config) (*OCRService, error) {
@@ -48,12 +54,18 @@ func NewOCRService(cfg *config.Config) (*OCRService, error) {
 	}
 	openaiClient := openai.NewClientWithConfig(openaiConfig)
 
+	maxConcurrent := cfg.Paddle.MaxConcurrent
+	if maxConcurrent <= 0 {
+		maxConcurrent = 1
+	}
+
 	return &OCRService{
 		cfg:          cfg,
 		minio:        mc,
 		openaiClient: openaiClient,
 		statusStore:  map[string]*ai.TaskStatusResponse{},
 		ocrStore:     map[string]*ai.OCRResponse{},
+		paddleSlots:  make(chan struct{}, maxConcurrent),
 	}, nil
 }
 
@@ -85,12 +97,22 @@ func (s *OCRService) ProcessOCR(ctx context.Context, req *ai.OCRRequest) (*ai.OC
 		return &ai.OCRResponse{TaskId: req.TaskId, Status: t.Status}, nil
 	}
 
+	// 解析透传选项，非法选项直接判定任务失败
+	opts, err := ParseOCROptions(req.Options, s.cfg.Engine)
+	if err != nil {
+		t.Status = ai.TaskStatus_FAILED
+		t.Message = "invalid options"
+		t.ErrorMessage = err.Error()
+		return &ai.OCRResponse{TaskId: req.TaskId, Status: t.Status, ErrorMessage: t.ErrorMessage}, nil
+	}
+
 	// 若任务尚未开始或处于排队，则启动；若已在处理，直接返回处理中的状态
 	if t.Status == ai.TaskStatus_QUEUED || t.Status == ai.TaskStatus_FAILED {
 		t.Status = ai.TaskStatus_PROCESSING
 		t.Message = "downloading"
+		t.ErrorMessage = ""
 		// 异步执行，避免阻塞调用方
-		go s.runOCRTask(req, t)
+		go s.runOCRTask(req, opts, t)
 	}
 
 	return &ai.OCRResponse{TaskId: req.TaskId, Status: t.Status}, nil
@@ -112,27 +134,133 @@ func (s *OCRService) GetTaskStatus(ctx context.Context, req *ai.TaskStatusReques
 	return s.getTask(req.TaskId), nil
 }
 
-// runOCRTask 执行下载与 PaddleOCR 推理
-func (s *OCRService) runOCRTask(req *ai.OCRRequest, t *ai.TaskStatusResponse) {
+// runOCRTask 执行下载与 OCR 推理，按 opts.Engine 选择 OpenAI 视觉模型或 PaddleOCR
+func (s *OCRService) runOCRTask(req *ai.OCRRequest, opts *OCROptions, t *ai.TaskStatusResponse) {
+	start := time.Now()
+	metrics.OCRActiveTasks.Inc()
 	defer func() {
 		// 确保进度最大化
 		if t.Status == ai.TaskStatus_COMPLETED && t.Progress < 1.0 {
 			t.Progress = 1
 		}
+		metrics.OCRActiveTasks.Dec()
+		status := "completed"
+		if t.Status != ai.TaskStatus_COMPLETED {
+			status = "failed"
+		}
+		metrics.OCRTaskDuration.WithLabelValues(opts.Engine, status).Observe(time.Since(start).Seconds())
 	}()
 
 	// 1) 下载文件字节
-	data, _, err := s.fetchFile(req.FileUrl)
+	data, filename, err := s.fetchFile(req.FileUrl)
 	if err != nil {
 		t.Status = ai.TaskStatus_FAILED
 		t.ErrorMessage = fmt.Sprintf("download error: %v", err)
 		t.Message = "download failed"
 		return
 	}
+	t.Progress = 0.2
+
+	// 2) docx/pptx 直接提取文字层，仅在内容像扫描件时对内嵌图片回退 OCR；其他文件走 OCR
+	var result *ai.OCRResponse
+	if kind := extract.Detect(data); kind != "" {
+		t.Message = "extracting"
+		result, err = s.extractDocument(data, opts, t)
+	} else {
+		result, err = s.recognize(data, filename, opts, t)
+	}
+	if err != nil {
+		t.Status = ai.TaskStatus_FAILED
+		t.ErrorMessage = err.Error()
+		t.Message = "ocr failed"
+		return
+	}
+
+	// 3) 更新任务完成
+	t.Status = ai.TaskStatus_COMPLETED
+	t.Message = "done"
+	t.Progress = 1.0
+
+	// 缓存最终结果
+	result.TaskId = req.TaskId
+	result.Status = ai.TaskStatus_COMPLETED
+	s.ocrStore[req.TaskId] = result
+}
+
+// recognize 对单张图片执行可选预处理并调用 OCR 引擎
+func (s *OCRService) recognize(data []byte, filename string, opts *OCROptions, t *ai.TaskStatusResponse) (*ai.OCRResponse, error) {
+	// 可选的图像预处理（纠偏、降噪、增强对比度、放大）
+	if opts.Preprocess.Enabled() {
+		t.Message = "preprocessing"
+		data, filename = s.preprocessImage(data, filename, opts.Preprocess)
+	}
 	t.Message = "ocr running"
 	t.Progress = 0.3
 
-	// 2) 调用 OpenAI GPT-4o-mini
+	// Mathpix 兼容接口整图识别并内联 LaTeX，失败时回退到常规引擎
+	if s.formulaEnabled(opts) && s.cfg.Formula.Provider == FormulaProviderMathpix {
+		text, confidence, err := s.callMathpix(data)
+		if err == nil {
+			return &ai.OCRResponse{Text: text, Confidence: confidence, Boxes: []*ai.BoundingBox{}}, nil
+		}
+		log.Printf("mathpix failed, falling back to %s: %v", opts.Engine, err)
+	}
+
+	switch opts.Engine {
+	case EnginePaddle:
+		text, boxes, confidence, err := s.callPaddleOCR(data, filename, opts)
+		if err != nil {
+			return nil, fmt.Errorf("paddle ocr error: %w", err)
+		}
+		result := &ai.OCRResponse{Text: text, Confidence: confidence, Boxes: boxes}
+		if s.formulaEnabled(opts) && s.cfg.Formula.Provider == FormulaProviderPix2Tex {
+			s.applyPix2Tex(data, result)
+		}
+		return result, nil
+	default:
+		text, err := s.callOpenAIOCR(data, opts)
+		if err != nil {
+			return nil, fmt.Errorf("openai ocr error: %w", err)
+		}
+		// OpenAI 不直接提供置信度，默认为 1.0
+		return &ai.OCRResponse{Text: text, Confidence: 1.0, Boxes: []*ai.BoundingBox{}}, nil
+	}
+}
+
+// extractDocument 提取 docx/pptx 文本；文字层过少时视为扫描件，对内嵌图片逐张 OCR
+func (s *OCRService) extractDocument(data []byte, opts *OCROptions, t *ai.TaskStatusResponse) (*ai.OCRResponse, error) {
+	doc, err := extract.Extract(data)
+	if err != nil {
+		return nil, fmt.Errorf("extract error: %w", err)
+	}
+
+	result := &ai.OCRResponse{Text: doc.Text, Confidence: 1.0, Boxes: []*ai.BoundingBox{}}
+	if utf8.RuneCountInString(doc.Text) >= s.cfg.Extract.MinTextChars || len(doc.Images) == 0 {
+		log.Printf("extracted %d chars from %s natively", utf8.RuneCountInString(doc.Text), doc.Kind)
+		return result, nil
+	}
+
+	log.Printf("%s has little text (%d chars), falling back to OCR on %d embedded images",
+		doc.Kind, utf8.RuneCountInString(doc.Text), len(doc.Images))
+	texts := []string{doc.Text}
+	var sumConf float32
+	for i, img := range doc.Images {
+		r, err := s.recognize(img.Data, img.Name, opts, t)
+		if err != nil {
+			return nil, fmt.Errorf("image %s: %w", img.Name, err)
+		}
+		texts = append(texts, r.Text)
+		sumConf += r.Confidence
+		t.Progress = 0.3 + 0.6*float32(i+1)/float32(len(doc.Images))
+	}
+
+	result.Text = strings.TrimSpace(strings.Join(texts, "\n\n"))
+	result.Confidence = sumConf / float32(len(doc.Images))
+	return result, nil
+}
+
+// callOpenAIOCR 使用 OpenAI 视觉模型识别图片文字
+func (s *OCRService) callOpenAIOCR(data []byte, opts *OCROptions) (string, error) {
 	encoded := base64.StdEncoding.EncodeToString(data)
 	imageUrl := fmt.Sprintf("data:%s;base64,%s", http.DetectContentType(data), encoded)
 
@@ -146,7 +274,7 @@ func (s *OCRService) runOCRTask(req *ai.OCRRequest, t *ai.TaskStatusResponse) {
 					MultiContent: []openai.ChatMessagePart{
 						{
 							Type: openai.ChatMessagePartTypeText,
-							Text: "Extract all text from this image.",
+							Text: opts.openAIPrompt(),
 						},
 						{
 							Type: openai.ChatMessagePartTypeImageURL,
@@ -159,32 +287,18 @@ func (s *OCRService) runOCRTask(req *ai.OCRRequest, t *ai.TaskStatusResponse) {
 			},
 		},
 	)
-
 	if err != nil {
-		t.Status = ai.TaskStatus_FAILED
-		t.ErrorMessage = fmt.Sprintf("openai api error: %v", err)
-		t.Message = "ocr failed"
-		return
+		return "", fmt.Errorf("openai api error: %w", err)
 	}
-
-	// 3) 更新任务完成
-	t.Status = ai.TaskStatus_COMPLETED
-	t.Message = "done"
-	t.Progress = 1.0
-
-	// 缓存最终结果
-	s.ocrStore[req.TaskId] = &ai.OCRResponse{
-		TaskId:     req.TaskId,
-		Status:     ai.TaskStatus_COMPLETED,
-		Text:       resp.Choices[0].Message.Content,
-		Confidence: 1.0, // OpenAI 不直接提供置信度，默认为 1.0
-		Boxes:      []*ai.BoundingBox{},
+	if len(resp.Choices) == 0 {
+		return "", fmt.Errorf("openai api returned no choices")
 	}
+	return resp.Choices[0].Message.Content, nil
 }
 
 // fetchFile 支持两种 URL：
+// - s3://bucket/object（material-service 发送的对象引用，凭证在读取时签发，见 objectClient）
 // - 预签名 HTTP(S) 直链
-// - s3://bucket/object
 func (s *OCRService) fetchFile(fileURL string) ([]byte, string, error) {
 	if strings.HasPrefix(fileURL, "s3://") {
 		u, err := url.Parse(fileURL)
@@ -196,9 +310,13 @@ func (s *OCRService) fetchFile(fileURL string) ([]byte, string, error) {
 		if bucket == "" {
 			bucket = s.cfg.MinIO.BucketName
 		}
+		client, err := s.objectClient(bucket, object)
+		if err != nil {
+			return nil, "", err
+		}
 		ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
 		defer cancel()
-		obj, err := s.minio.GetObject(ctx, bucket, object, minio.GetObjectOptions{})
+		obj, err := client.GetObject(ctx, bucket, object, minio.GetObjectOptions{})
 		if err != nil {
 			return nil, "", err
 		}
@@ -241,8 +359,28 @@ type paddleOCRItem struct {
 	Res [][]interface{} `json:"res"`
 }
 
-func (s *OCRService) callPaddleOCR(data []byte, filename string) (text string, boxes []*ai.BoundingBox, avgConfidence float32, err error) {
-	if s.cfg.Paddle.Endpoint == "" {
+// acquirePaddleSlot 等待 PaddleOCR 并发槽位，等待期间计入队列深度；返回释放函数
+func (s *OCRService) acquirePaddleSlot() func() {
+	metrics.OCRQueueDepth.Inc()
+	s.paddleSlots <- struct{}{}
+	metrics.OCRQueueDepth.Dec()
+	return func() { <-s.paddleSlots }
+}
+
+func (s *OCRService) callPaddleOCR(data []byte, filename string, opts *OCROptions) (text string, boxes []*ai.BoundingBox, avgConfidence float32, err error) {
+	defer func() {
+		status := "success"
+		if err != nil {
+			status = "error"
+		}
+		metrics.PaddleOCRRequestsTotal.WithLabelValues(status).Inc()
+	}()
+
+	endpoint := s.cfg.Paddle.Endpoint
+	if opts.TableMode && s.cfg.Paddle.TableEndpoint != "" {
+		endpoint = s.cfg.Paddle.TableEndpoint
+	}
+	if endpoint == "" {
 		return "", nil, 0, fmt.Errorf("PaddleOCR endpoint not configured")
 	}
 
@@ -256,25 +394,21 @@ func (s *OCRService) callPaddleOCR(data []byte, filename string) (text string, b
 	if _, err := fw.Write(data); err != nil {
 		return "", nil, 0, err
 	}
-	// 某些实现需要 extra params, 允许 options 透传，后续扩展
+	// 透传识别选项（语言、检测/识别开关、方向分类）
+	for k, v := range opts.paddleFields() {
+		if err := writer.WriteField(k, v); err != nil {
+			return "", nil, 0, err
+		}
+	}
 	writer.Close()
 
 	httpClient := &http.Client{Timeout: time.Duration(s.cfg.Paddle.TimeoutSecond) * time.Second}
-	req, err := http.NewRequest("POST", s.cfg.Paddle.Endpoint, &body)
+	req, err := http.NewRequest("POST", endpoint, &body)
 	if err != nil {
 		return "", nil, 0, err
 	}
 	req.Header.Set("Content-Type", writer.FormDataContentType())
-	resp, err := httpClient.Do(req)
-	if err != nil {
-		return "", nil, 0, err
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode >= 300 {
-		b, _ := io.ReadAll(resp.Body)
-		return "", nil, 0, fmt.Errorf("paddle http %d: %s", resp.StatusCode, string(b))
-	}
-	raw, err := io.ReadAll(resp.Body)
+	raw, err := s.doPaddleRequest(httpClient, req)
 	if err != nil {
 		return "", nil, 0, err
 	}
@@ -384,3 +518,20 @@ func (s *OCRService) callPaddleOCR(data []byte, filename string) (text string, b
 	}
 	return strings.Join(texts, "\n"), outBoxes, avg, nil
 }
+
+// doPaddleRequest 在并发槽位内发送推理请求并读取响应
+func (s *OCRService) doPaddleRequest(httpClient *http.Client, req *http.Request) ([]byte, error) {
+	release := s.acquirePaddleSlot()
+	defer release()
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		b, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("paddle http %d: %s", resp.StatusCode, string(b))
+	}
+	return io.ReadAll(resp.Body)
+}
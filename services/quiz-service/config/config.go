@@ -3,15 +3,28 @@ package config
 import (
 	"fmt"
 	"log"
+	"time"
 
+	"github.com/RigelNana/arkstudy/pkg/registry"
 	"github.com/spf13/viper"
 )
 
 type Config struct {
-	Database   DatabaseConfig   `mapstructure:"database"`
-	OpenAI     OpenAIConfig     `mapstructure:"openai"`
-	GRPC       GRPCConfig       `mapstructure:"grpc"`
-	LLMService LLMServiceConfig `mapstructure:"llm_service"`
+	Database        DatabaseConfig        `mapstructure:"database"`
+	OpenAI          OpenAIConfig          `mapstructure:"openai"`
+	GRPC            GRPCConfig            `mapstructure:"grpc"`
+	LLMService      LLMServiceConfig      `mapstructure:"llm_service"`
+	ASRService      ASRServiceConfig      `mapstructure:"asr_service"`
+	MaterialService MaterialServiceConfig `mapstructure:"material_service"`
+	UserService     UserServiceConfig     `mapstructure:"user_service"`
+	Analytics       AnalyticsConfig       `mapstructure:"analytics"`
+	Mistakes        MistakesConfig        `mapstructure:"mistakes"`
+	Grading         GradingConfig         `mapstructure:"grading"`
+	Worker          WorkerConfig          `mapstructure:"worker"`
+	Difficulty      DifficultyConfig      `mapstructure:"difficulty"`
+	Shares          SharesConfig          `mapstructure:"shares"`
+	AntiCheat       AntiCheatConfig       `mapstructure:"anti_cheat"`
+	Plagiarism      PlagiarismConfig      `mapstructure:"plagiarism"`
 }
 
 type DatabaseConfig struct {
@@ -21,6 +34,14 @@ type DatabaseConfig struct {
 	Password string `mapstructure:"password"`
 	DBName   string `mapstructure:"dbname"`
 	SSLMode  string `mapstructure:"sslmode"`
+
+	MaxOpenConns    int           `mapstructure:"max_open_conns"`
+	MaxIdleConns    int           `mapstructure:"max_idle_conns"`
+	ConnMaxLifetime time.Duration `mapstructure:"conn_max_lifetime"`
+	LogLevel        string        `mapstructure:"log_level"` // silent/error/warn/info
+
+	// 逗号分隔的只读副本 DSN，为空时全部查询走主库；答题历史与统计等查询从副本读取
+	ReplicaDSNs string `mapstructure:"replica_dsns"`
 }
 
 type OpenAIConfig struct {
@@ -35,31 +56,178 @@ type GRPCConfig struct {
 
 type LLMServiceConfig struct {
 	Address string `mapstructure:"address"`
+	// 出题时的检索参数，为0时使用 llm-service 默认值
+	RetrievalTopK       int     `mapstructure:"retrieval_top_k"`
+	SimilarityThreshold float32 `mapstructure:"similarity_threshold"`
+}
+
+// asr-service 配置，基于音视频转写出题时获取转写分段
+type ASRServiceConfig struct {
+	Address string `mapstructure:"address"`
+}
+
+// material-service 配置，多材料出题时校验材料归属、展开课程合集
+type MaterialServiceConfig struct {
+	Address string `mapstructure:"address"`
+}
+
+// user-service 配置，设置全局评分策略、查看题库分析与审核题目时校验教师/管理员角色
+type UserServiceConfig struct {
+	Address string `mapstructure:"address"`
+}
+
+// 题库分析与难度校准配置
+type AnalyticsConfig struct {
+	CalibrationInterval time.Duration `mapstructure:"calibration_interval"` // 为0时不启用定时校准
+	MinAttempts         int           `mapstructure:"min_attempts"`
+	EasyThreshold       float32       `mapstructure:"easy_threshold"`
+	HardThreshold       float32       `mapstructure:"hard_threshold"`
+}
+
+// 错题本配置
+type MistakesConfig struct {
+	MasteryStreak int `mapstructure:"mastery_streak"` // 连续答对多少次视为已掌握
+}
+
+// 默认评分策略，可被数据库中的全局或材料级策略覆盖
+type GradingConfig struct {
+	PassThreshold     float32 `mapstructure:"pass_threshold"`
+	PartialCreditMode string  `mapstructure:"partial_credit_mode"`
+	RetryPenalty      float32 `mapstructure:"retry_penalty"`
+	MaxRetryPenalty   float32 `mapstructure:"max_retry_penalty"`
+}
+
+// 知识点统计异步任务配置
+type WorkerConfig struct {
+	Interval    time.Duration `mapstructure:"interval"`
+	BatchSize   int           `mapstructure:"batch_size"`
+	MaxAttempts int           `mapstructure:"max_attempts"`
+	// 处理失败后轮询间隔按指数增长的上限，成功处理一轮后恢复为 Interval
+	MaxBackoff time.Duration `mapstructure:"max_backoff"`
+}
+
+// 出题难度估计配置
+type DifficultyConfig struct {
+	Estimator string `mapstructure:"estimator"` // off / heuristic / llm
+	Override  bool   `mapstructure:"override"`  // 以估计难度覆盖请求难度
+}
+
+// 题目公开分享配置
+type SharesConfig struct {
+	DefaultTTL   time.Duration `mapstructure:"default_ttl"`
+	MaxTTL       time.Duration `mapstructure:"max_ttl"`
+	MaxQuestions int           `mapstructure:"max_questions"` // 单个分享最多包含的题目数
+}
+
+// 分享作答异常标记阈值，设为 0 时不检查该项
+type AntiCheatConfig struct {
+	MaxFocusLosses int           `mapstructure:"max_focus_losses"` // 离开页面次数
+	LongFocusLoss  time.Duration `mapstructure:"long_focus_loss"`  // 单次离开页面时长
+	MinPasteLength int           `mapstructure:"min_paste_length"` // 单次粘贴字符数
+	MinAnswerTime  time.Duration `mapstructure:"min_answer_time"`  // 答对一题的最短用时
+}
+
+// 论述题查重配置，相似度阈值为余弦相似度，设为 0 时不检查该项
+type PlagiarismConfig struct {
+	Enabled           bool    `mapstructure:"enabled"`
+	MaterialThreshold float32 `mapstructure:"material_threshold"`
+	PeerThreshold     float32 `mapstructure:"peer_threshold"`
+	MinAnswerLength   int     `mapstructure:"min_answer_length"` // 字符数
+	MaxPeers          int     `mapstructure:"max_peers"`
 }
 
 func LoadConfig() (*Config, error) {
 	config := &Config{}
 
 	// 设置默认值
-	viper.SetDefault("grpc.port", "50055")
+	// 端口与 llm-service、asr-service、material-service 地址由 registry 统一解析
+	// （QUIZ_GRPC_PORT / GRPC_PORT、LLM_GRPC_ADDR / LLM_SERVICE_ADDR、ASR_GRPC_ADDR / ASR_SERVICE_ADDR、MATERIAL_GRPC_ADDR、USER_GRPC_ADDR）
+	viper.SetDefault("grpc.port", registry.Quiz.ListenPort())
 	viper.SetDefault("database.sslmode", "disable")
+	viper.SetDefault("database.max_open_conns", 20)
+	viper.SetDefault("database.max_idle_conns", 5)
+	viper.SetDefault("database.conn_max_lifetime", "30m")
+	viper.SetDefault("database.log_level", "warn")
 	viper.SetDefault("openai.model", "gpt-3.5-turbo")
-	viper.SetDefault("llm_service.address", "arkstudy-llm-service:50054")
+	viper.SetDefault("llm_service.address", registry.LLM.Addr())
+	viper.SetDefault("asr_service.address", registry.ASR.Addr())
+	viper.SetDefault("material_service.address", registry.Material.Addr())
+	viper.SetDefault("user_service.address", registry.User.Addr())
+	viper.SetDefault("analytics.calibration_interval", "1h")
+	viper.SetDefault("analytics.min_attempts", 20)
+	viper.SetDefault("analytics.easy_threshold", 0.8)
+	viper.SetDefault("analytics.hard_threshold", 0.4)
+	viper.SetDefault("mistakes.mastery_streak", 2)
+	viper.SetDefault("grading.pass_threshold", 0.6)
+	viper.SetDefault("grading.partial_credit_mode", "weighted")
+	viper.SetDefault("grading.retry_penalty", 0)
+	viper.SetDefault("grading.max_retry_penalty", 0.5)
+	viper.SetDefault("worker.interval", "5s")
+	viper.SetDefault("worker.batch_size", 50)
+	viper.SetDefault("worker.max_attempts", 5)
+	viper.SetDefault("worker.max_backoff", "5m")
+	viper.SetDefault("difficulty.estimator", "heuristic")
+	viper.SetDefault("difficulty.override", true)
+	viper.SetDefault("shares.default_ttl", "168h")
+	viper.SetDefault("shares.max_ttl", "720h")
+	viper.SetDefault("shares.max_questions", 100)
+	viper.SetDefault("anti_cheat.max_focus_losses", 3)
+	viper.SetDefault("anti_cheat.long_focus_loss", "30s")
+	viper.SetDefault("anti_cheat.min_paste_length", 20)
+	viper.SetDefault("anti_cheat.min_answer_time", "2s")
+	viper.SetDefault("plagiarism.enabled", true)
+	viper.SetDefault("plagiarism.material_threshold", 0.9)
+	viper.SetDefault("plagiarism.peer_threshold", 0.92)
+	viper.SetDefault("plagiarism.min_answer_length", 80)
+	viper.SetDefault("plagiarism.max_peers", 200)
 
 	// 从环境变量读取配置
 	viper.AutomaticEnv()
 
 	// 绑定环境变量
-	viper.BindEnv("grpc.port", "GRPC_PORT")
 	viper.BindEnv("database.host", "DB_HOST")
 	viper.BindEnv("database.port", "DB_PORT")
 	viper.BindEnv("database.user", "DB_USER")
 	viper.BindEnv("database.password", "DB_PASSWORD")
 	viper.BindEnv("database.dbname", "DB_NAME")
+	viper.BindEnv("database.sslmode", "DB_SSLMODE")
+	viper.BindEnv("database.max_open_conns", "DB_MAX_OPEN_CONNS")
+	viper.BindEnv("database.max_idle_conns", "DB_MAX_IDLE_CONNS")
+	viper.BindEnv("database.conn_max_lifetime", "DB_CONN_MAX_LIFETIME")
+	viper.BindEnv("database.log_level", "DB_LOG_LEVEL")
+	viper.BindEnv("database.replica_dsns", "DB_REPLICA_DSNS")
 	viper.BindEnv("openai.api_key", "OPENAI_API_KEY")
 	viper.BindEnv("openai.model", "OPENAI_MODEL")
 	viper.BindEnv("openai.base_url", "OPENAI_BASE_URL")
-	viper.BindEnv("llm_service.address", "LLM_SERVICE_ADDR")
+	viper.BindEnv("llm_service.retrieval_top_k", "QUIZ_RETRIEVAL_TOP_K")
+	viper.BindEnv("llm_service.similarity_threshold", "QUIZ_RETRIEVAL_SIMILARITY_THRESHOLD")
+	viper.BindEnv("analytics.calibration_interval", "CALIBRATION_INTERVAL")
+	viper.BindEnv("analytics.min_attempts", "CALIBRATION_MIN_ATTEMPTS")
+	viper.BindEnv("analytics.easy_threshold", "CALIBRATION_EASY_THRESHOLD")
+	viper.BindEnv("analytics.hard_threshold", "CALIBRATION_HARD_THRESHOLD")
+	viper.BindEnv("mistakes.mastery_streak", "MISTAKE_MASTERY_STREAK")
+	viper.BindEnv("grading.pass_threshold", "GRADING_PASS_THRESHOLD")
+	viper.BindEnv("grading.partial_credit_mode", "GRADING_PARTIAL_CREDIT_MODE")
+	viper.BindEnv("grading.retry_penalty", "GRADING_RETRY_PENALTY")
+	viper.BindEnv("grading.max_retry_penalty", "GRADING_MAX_RETRY_PENALTY")
+	viper.BindEnv("worker.interval", "STATS_WORKER_INTERVAL")
+	viper.BindEnv("worker.batch_size", "STATS_WORKER_BATCH_SIZE")
+	viper.BindEnv("worker.max_attempts", "STATS_WORKER_MAX_ATTEMPTS")
+	viper.BindEnv("worker.max_backoff", "STATS_WORKER_MAX_BACKOFF")
+	viper.BindEnv("difficulty.estimator", "QUIZ_DIFFICULTY_ESTIMATOR")
+	viper.BindEnv("difficulty.override", "QUIZ_DIFFICULTY_OVERRIDE")
+	viper.BindEnv("shares.default_ttl", "QUIZ_SHARE_DEFAULT_TTL")
+	viper.BindEnv("shares.max_ttl", "QUIZ_SHARE_MAX_TTL")
+	viper.BindEnv("shares.max_questions", "QUIZ_SHARE_MAX_QUESTIONS")
+	viper.BindEnv("anti_cheat.max_focus_losses", "ANTI_CHEAT_MAX_FOCUS_LOSSES")
+	viper.BindEnv("anti_cheat.long_focus_loss", "ANTI_CHEAT_LONG_FOCUS_LOSS")
+	viper.BindEnv("anti_cheat.min_paste_length", "ANTI_CHEAT_MIN_PASTE_LENGTH")
+	viper.BindEnv("anti_cheat.min_answer_time", "ANTI_CHEAT_MIN_ANSWER_TIME")
+	viper.BindEnv("plagiarism.enabled", "PLAGIARISM_CHECK_ENABLED")
+	viper.BindEnv("plagiarism.material_threshold", "PLAGIARISM_MATERIAL_THRESHOLD")
+	viper.BindEnv("plagiarism.peer_threshold", "PLAGIARISM_PEER_THRESHOLD")
+	viper.BindEnv("plagiarism.min_answer_length", "PLAGIARISM_MIN_ANSWER_LENGTH")
+	viper.BindEnv("plagiarism.max_peers", "PLAGIARISM_MAX_PEERS")
 
 	if err := viper.Unmarshal(config); err != nil {
 		return nil, fmt.Errorf("unable to decode config: %v", err)
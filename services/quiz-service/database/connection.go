@@ -0,0 +1,68 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/RigelNana/arkstudy/pkg/dbx"
+	"github.com/RigelNana/arkstudy/pkg/metrics"
+	"github.com/RigelNana/arkstudy/quiz-service/config"
+)
+
+var DB *gorm.DB
+
+func InitDB(cfg *config.DatabaseConfig) (*gorm.DB, error) {
+	db, err := gorm.Open(postgres.Open(cfg.DSN()), &gorm.Config{
+		Logger:                                   logger.Default.LogMode(parseLogLevel(cfg.LogLevel)),
+		DisableForeignKeyConstraintWhenMigrating: true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to database: %v", err)
+	}
+	// 审计字段、乐观锁与软删除检查
+	if err := db.Use(dbx.Plugin{}); err != nil {
+		return nil, fmt.Errorf("failed to register dbx plugin: %v", err)
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sql.DB: %v", err)
+	}
+	sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
+	sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
+	sqlDB.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+
+	// 只读副本与主库使用相同的连接池设置
+	err = dbx.UseReplicas(db, postgres.Open, dbx.ParseDSNList(cfg.ReplicaDSNs), func(replica *sql.DB) {
+		replica.SetMaxOpenConns(cfg.MaxOpenConns)
+		replica.SetMaxIdleConns(cfg.MaxIdleConns)
+		replica.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to read replicas: %v", err)
+	}
+
+	// 连接池状态上报到 Prometheus
+	metrics.StartDBStatsCollector("quiz-service", sqlDB, 15*time.Second)
+
+	DB = db
+	return db, nil
+}
+
+func parseLogLevel(level string) logger.LogLevel {
+	switch level {
+	case "silent":
+		return logger.Silent
+	case "error":
+		return logger.Error
+	case "info":
+		return logger.Info
+	default:
+		return logger.Warn
+	}
+}
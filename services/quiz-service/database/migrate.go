@@ -0,0 +1,37 @@
+package database
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"github.com/RigelNana/arkstudy/quiz-service/models"
+)
+
+// 自动迁移表结构 - 分别迁移每个表，避免外键依赖问题
+func Migrate(db *gorm.DB) error {
+	tables := []struct {
+		name  string
+		model interface{}
+	}{
+		{"Question", &models.Question{}},
+		{"KnowledgePointStats", &models.KnowledgePointStats{}},
+		{"UserAnswer", &models.UserAnswer{}},
+		{"QuestionStats", &models.QuestionStats{}},
+		{"MistakeRecord", &models.MistakeRecord{}},
+		{"GradingPolicy", &models.GradingPolicy{}},
+		{"AnswerEvent", &models.AnswerEvent{}},
+		{"QuizShare", &models.QuizShare{}},
+		{"QuizShareAttempt", &models.QuizShareAttempt{}},
+		{"QuestionVersion", &models.QuestionVersion{}},
+		{"QuestionAttempt", &models.QuestionAttempt{}},
+		{"AnswerEmbedding", &models.AnswerEmbedding{}},
+	}
+
+	for _, t := range tables {
+		if err := db.Migrator().AutoMigrate(t.model); err != nil {
+			return fmt.Errorf("failed to migrate %s table: %v", t.name, err)
+		}
+	}
+	return nil
+}
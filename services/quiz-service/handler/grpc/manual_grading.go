@@ -0,0 +1,100 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	pb "github.com/RigelNana/arkstudy/proto/quiz"
+	"github.com/RigelNana/arkstudy/quiz-service/models"
+	"github.com/RigelNana/arkstudy/quiz-service/repository"
+	"github.com/RigelNana/arkstudy/quiz-service/service"
+)
+
+// 获取教师的评分队列
+func (h *QuizGRPCHandler) ListGradingQueue(ctx context.Context, req *pb.ListGradingQueueRequest) (*pb.ListGradingQueueResponse, error) {
+	page, pageSize := normalizePage(req.Page, req.PageSize)
+	items, total, err := h.manualGrading.ListQueue(req.UserId, repository.GradingQueueFilter{
+		MaterialID:  req.MaterialId,
+		CourseID:    req.CourseId,
+		QuestionID:  req.QuestionId,
+		FlaggedOnly: req.FlaggedOnly,
+	}, page, pageSize)
+	if err != nil {
+		h.logger.Errorf("获取评分队列失败: %v", err)
+		return &pb.ListGradingQueueResponse{Success: false, Message: err.Error()}, nil
+	}
+
+	resp := &pb.ListGradingQueueResponse{Success: true, Message: "获取成功", Total: int32(total)}
+	for _, item := range items {
+		pbQ, err := h.convertToPBQuestion(item.Question)
+		if err != nil {
+			h.logger.Errorf("转换题目格式失败: %v", err)
+			continue
+		}
+		resp.Items = append(resp.Items, &pb.GradingQueueItem{
+			Answer:   convertToPBUserAnswer(item.Answer),
+			Question: pbQ,
+		})
+	}
+	return resp, nil
+}
+
+// 批量确认或修改主观题得分
+func (h *QuizGRPCHandler) GradeAnswers(ctx context.Context, req *pb.GradeAnswersRequest) (*pb.GradeAnswersResponse, error) {
+	grades := make([]service.AnswerGrade, 0, len(req.Grades))
+	for _, g := range req.Grades {
+		grades = append(grades, service.AnswerGrade{
+			AnswerID: g.AnswerId,
+			Accept:   g.Accept,
+			Score:    g.Score,
+			Comment:  g.Comment,
+		})
+	}
+	answers, skipped, err := h.manualGrading.Grade(req.UserId, grades)
+	if err != nil {
+		h.logger.Errorf("批量评分失败: %v", err)
+		return &pb.GradeAnswersResponse{Success: false, Message: err.Error()}, nil
+	}
+
+	resp := &pb.GradeAnswersResponse{Success: true, Message: fmt.Sprintf("已评分 %d 条作答", len(answers))}
+	for _, a := range answers {
+		resp.Answers = append(resp.Answers, convertToPBUserAnswer(a))
+	}
+	for _, s := range skipped {
+		resp.Skipped = append(resp.Skipped, &pb.GradingSkip{AnswerId: s.AnswerID, Reason: s.Reason})
+	}
+	return resp, nil
+}
+
+// 辅助函数：转换答题记录
+func convertToPBUserAnswer(answer *models.UserAnswer) *pb.UserAnswer {
+	pbAnswer := &pb.UserAnswer{
+		AnswerId:    answer.AnswerID,
+		QuestionId:  answer.QuestionID,
+		UserId:      answer.UserID,
+		Answer:      answer.Answer,
+		IsCorrect:   answer.IsCorrect,
+		Score:       answer.Score,
+		AnsweredAt:  timestamppb.New(answer.AnsweredAt),
+		PartResults: convertToPBPartResults(answer.GetPartResults()),
+		TimeSpentMs: answer.TimeSpentMs,
+		// 早于版本功能的记录为 0
+		QuestionVersion: int32(answer.QuestionVersion),
+		AttemptId:       answer.AttemptID,
+		Similarity:      convertToPBSimilarity(answer.GetSimilarity()),
+		GradingStatus:   answer.GradingStatus,
+		SuggestedScore:  answer.SuggestedScore,
+		AiFeedback:      answer.AIFeedback,
+		GradedBy:        answer.GradedBy,
+		GradingComment:  answer.GradingComment,
+	}
+	if answer.GradedAt != nil {
+		pbAnswer.GradedAt = timestamppb.New(*answer.GradedAt)
+	}
+	for _, i := range answer.GetOptionOrder() {
+		pbAnswer.OptionOrder = append(pbAnswer.OptionOrder, int32(i))
+	}
+	return pbAnswer
+}
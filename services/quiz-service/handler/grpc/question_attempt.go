@@ -0,0 +1,34 @@
+package grpc
+
+import (
+	"context"
+
+	pb "github.com/RigelNana/arkstudy/proto/quiz"
+	"github.com/RigelNana/arkstudy/quiz-service/service"
+)
+
+// 开始作答，返回按会话选项顺序排列、去掉答案的题目
+func (h *QuizGRPCHandler) StartQuestionAttempt(ctx context.Context, req *pb.StartQuestionAttemptRequest) (*pb.StartQuestionAttemptResponse, error) {
+	if req.UserId == "" {
+		return &pb.StartQuestionAttemptResponse{Success: false, Message: "用户ID不能为空"}, nil
+	}
+	attempt, question, err := h.attemptService.Start(req.QuestionId, req.UserId)
+	if err != nil {
+		return &pb.StartQuestionAttemptResponse{Success: false, Message: err.Error()}, nil
+	}
+
+	pbQuestion, err := h.convertToPBQuestion(question)
+	if err != nil {
+		return &pb.StartQuestionAttemptResponse{Success: false, Message: "转换题目格式失败"}, nil
+	}
+	order := attempt.GetOptionOrder()
+	pbQuestion.Options = service.PermuteOptions(pbQuestion.Options, order)
+
+	return &pb.StartQuestionAttemptResponse{
+		Success:         true,
+		Message:         "开始作答",
+		AttemptId:       attempt.AttemptID,
+		Question:        publicQuestion(pbQuestion),
+		OptionsShuffled: len(order) > 0,
+	}, nil
+}
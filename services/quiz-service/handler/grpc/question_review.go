@@ -0,0 +1,92 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+
+	pb "github.com/RigelNana/arkstudy/proto/quiz"
+	"github.com/RigelNana/arkstudy/quiz-service/models"
+	"github.com/RigelNana/arkstudy/quiz-service/service"
+)
+
+// 提交审核并指定审核人
+func (h *QuizGRPCHandler) AssignQuestionReviewer(ctx context.Context, req *pb.AssignQuestionReviewerRequest) (*pb.ReviewQuestionsResponse, error) {
+	if req.UserId == "" {
+		return &pb.ReviewQuestionsResponse{Success: false, Message: "用户ID不能为空"}, nil
+	}
+	questions, skipped, err := h.reviewService.AssignReviewer(ctx, req.UserId, req.ReviewerId, req.QuestionIds)
+	if err != nil {
+		return &pb.ReviewQuestionsResponse{Success: false, Message: err.Error()}, nil
+	}
+	return h.reviewResponse(fmt.Sprintf("已提交 %d 道题目审核", len(questions)), questions, skipped), nil
+}
+
+// 审核单道题目
+func (h *QuizGRPCHandler) ReviewQuestion(ctx context.Context, req *pb.ReviewQuestionRequest) (*pb.ReviewQuestionsResponse, error) {
+	if req.UserId == "" {
+		return &pb.ReviewQuestionsResponse{Success: false, Message: "用户ID不能为空"}, nil
+	}
+	question, err := h.reviewService.Review(ctx, req.UserId, req.QuestionId, req.Approve, req.Comment)
+	if err != nil {
+		return &pb.ReviewQuestionsResponse{Success: false, Message: err.Error()}, nil
+	}
+	message := "题目已通过审核"
+	if !req.Approve {
+		message = "题目已驳回"
+	}
+	return h.reviewResponse(message, []*models.Question{question}, nil), nil
+}
+
+// 批量通过待审核题目
+func (h *QuizGRPCHandler) BulkApproveQuestions(ctx context.Context, req *pb.BulkApproveQuestionsRequest) (*pb.ReviewQuestionsResponse, error) {
+	if req.UserId == "" {
+		return &pb.ReviewQuestionsResponse{Success: false, Message: "用户ID不能为空"}, nil
+	}
+	questions, skipped, err := h.reviewService.BulkApprove(ctx, req.UserId, req.CourseId, req.QuestionIds, req.Comment)
+	if err != nil {
+		return &pb.ReviewQuestionsResponse{Success: false, Message: err.Error()}, nil
+	}
+	return h.reviewResponse(fmt.Sprintf("已通过 %d 道题目", len(questions)), questions, skipped), nil
+}
+
+// 获取审核队列
+func (h *QuizGRPCHandler) ListReviewQueue(ctx context.Context, req *pb.ListReviewQueueRequest) (*pb.ListReviewQueueResponse, error) {
+	page, pageSize := normalizePage(req.Page, req.PageSize)
+	questions, total, err := h.reviewService.ListQueue(req.UserId, req.CourseId, req.Status, page, pageSize)
+	if err != nil {
+		h.logger.Errorf("获取审核队列失败: %v", err)
+		return &pb.ListReviewQueueResponse{Success: false, Message: err.Error()}, nil
+	}
+	return &pb.ListReviewQueueResponse{
+		Success:   true,
+		Message:   "获取成功",
+		Questions: h.convertToPBQuestions(questions),
+		Total:     int32(total),
+	}, nil
+}
+
+func (h *QuizGRPCHandler) reviewResponse(message string, questions []*models.Question, skipped []service.ReviewSkip) *pb.ReviewQuestionsResponse {
+	resp := &pb.ReviewQuestionsResponse{
+		Success:   true,
+		Message:   message,
+		Questions: h.convertToPBQuestions(questions),
+	}
+	for _, s := range skipped {
+		resp.Skipped = append(resp.Skipped, &pb.ReviewSkip{QuestionId: s.QuestionID, Reason: s.Reason})
+	}
+	return resp
+}
+
+// 辅助函数：批量转换题目，转换失败的题目会被跳过
+func (h *QuizGRPCHandler) convertToPBQuestions(questions []*models.Question) []*pb.Question {
+	pbQuestions := make([]*pb.Question, 0, len(questions))
+	for _, q := range questions {
+		pbQ, err := h.convertToPBQuestion(q)
+		if err != nil {
+			h.logger.Errorf("转换题目格式失败: %v", err)
+			continue
+		}
+		pbQuestions = append(pbQuestions, pbQ)
+	}
+	return pbQuestions
+}
@@ -0,0 +1,120 @@
+package grpc
+
+import (
+	"context"
+	"encoding/json"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	pb "github.com/RigelNana/arkstudy/proto/quiz"
+	"github.com/RigelNana/arkstudy/quiz-service/models"
+	"github.com/RigelNana/arkstudy/quiz-service/service"
+)
+
+// 编辑题目并保存新版本
+func (h *QuizGRPCHandler) UpdateQuestion(ctx context.Context, req *pb.UpdateQuestionRequest) (*pb.UpdateQuestionResponse, error) {
+	if req.UserId == "" {
+		return &pb.UpdateQuestionResponse{Success: false, Message: "用户ID不能为空"}, nil
+	}
+	parts := make([]models.QuestionPart, 0, len(req.Parts))
+	for _, p := range req.Parts {
+		parts = append(parts, models.QuestionPart{
+			Label:            p.Label,
+			CorrectAnswer:    p.CorrectAnswer,
+			AnswerAliases:    p.AnswerAliases,
+			NumericTolerance: p.NumericTolerance,
+			Weight:           p.Weight,
+		})
+	}
+	question, changed, err := h.versionService.Update(req.UserId, req.QuestionId, int(req.ExpectedVersion), service.QuestionEdit{
+		Content:          req.Content,
+		Options:          req.Options,
+		CorrectAnswer:    req.CorrectAnswer,
+		AnswerAliases:    req.AnswerAliases,
+		NumericTolerance: req.NumericTolerance,
+		Parts:            parts,
+		Explanation:      req.Explanation,
+		Difficulty:       models.DifficultyLevel(req.Difficulty),
+		KnowledgePoints:  req.KnowledgePoints,
+	}, req.ChangeNote)
+	if err != nil {
+		return &pb.UpdateQuestionResponse{Success: false, Message: err.Error()}, nil
+	}
+
+	pbQ, err := h.convertToPBQuestion(question)
+	if err != nil {
+		return &pb.UpdateQuestionResponse{Success: false, Message: "转换题目格式失败"}, nil
+	}
+	return &pb.UpdateQuestionResponse{
+		Success:       true,
+		Message:       "题目已更新",
+		Question:      pbQ,
+		ChangedFields: changed,
+	}, nil
+}
+
+// 获取题目的版本历史
+func (h *QuizGRPCHandler) ListQuestionVersions(ctx context.Context, req *pb.ListQuestionVersionsRequest) (*pb.ListQuestionVersionsResponse, error) {
+	versions, err := h.versionService.List(req.QuestionId, req.UserId)
+	if err != nil {
+		return &pb.ListQuestionVersionsResponse{Success: false, Message: err.Error()}, nil
+	}
+	pbVersions := make([]*pb.QuestionVersion, 0, len(versions))
+	for _, v := range versions {
+		pbVersions = append(pbVersions, convertToPBQuestionVersion(v))
+	}
+	return &pb.ListQuestionVersionsResponse{
+		Success:  true,
+		Message:  "获取成功",
+		Versions: pbVersions,
+	}, nil
+}
+
+// 获取题目的指定版本
+func (h *QuizGRPCHandler) GetQuestionVersion(ctx context.Context, req *pb.GetQuestionVersionRequest) (*pb.GetQuestionVersionResponse, error) {
+	v, err := h.versionService.Get(req.QuestionId, int(req.Version), req.UserId)
+	if err != nil {
+		return &pb.GetQuestionVersionResponse{Success: false, Message: err.Error()}, nil
+	}
+	return &pb.GetQuestionVersionResponse{
+		Success: true,
+		Message: "获取成功",
+		Version: convertToPBQuestionVersion(v),
+	}, nil
+}
+
+// 辅助函数：转换题目版本
+func convertToPBQuestionVersion(v *models.QuestionVersion) *pb.QuestionVersion {
+	var options, aliases, knowledgePoints []string
+	var parts []models.QuestionPart
+	if v.Options != "" {
+		json.Unmarshal([]byte(v.Options), &options)
+	}
+	if v.AnswerAliases != "" {
+		json.Unmarshal([]byte(v.AnswerAliases), &aliases)
+	}
+	if v.Parts != "" {
+		json.Unmarshal([]byte(v.Parts), &parts)
+	}
+	if v.KnowledgePoints != "" {
+		json.Unmarshal([]byte(v.KnowledgePoints), &knowledgePoints)
+	}
+	return &pb.QuestionVersion{
+		QuestionId:       v.QuestionID,
+		Version:          int32(v.Version),
+		Type:             pb.QuestionType(v.Type),
+		Content:          v.Content,
+		Options:          options,
+		CorrectAnswer:    v.CorrectAnswer,
+		AnswerAliases:    aliases,
+		NumericTolerance: v.NumericTolerance,
+		Parts:            convertToPBParts(parts),
+		Explanation:      v.Explanation,
+		Difficulty:       pb.DifficultyLevel(v.Difficulty),
+		KnowledgePoints:  knowledgePoints,
+		EditorId:         v.EditorID,
+		ChangedFields:    v.GetChangedFields(),
+		ChangeNote:       v.ChangeNote,
+		CreatedAt:        timestamppb.New(v.CreatedAt),
+	}
+}
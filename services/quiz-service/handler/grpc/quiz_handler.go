@@ -3,10 +3,19 @@ package grpc
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
+	"github.com/RigelNana/arkstudy/pkg/langdetect"
 	pb "github.com/RigelNana/arkstudy/proto/quiz"
 	"github.com/RigelNana/arkstudy/quiz-service/models"
 	"github.com/RigelNana/arkstudy/quiz-service/repository"
@@ -17,13 +26,36 @@ type QuizGRPCHandler struct {
 	pb.UnimplementedQuizServiceServer
 	quizService    *service.QuizService
 	quizRepository *repository.QuizRepository
+	calibrator     *service.DifficultyCalibrator
+	gradingService *service.GradingService
+	shareService   *service.ShareService
+	reviewService  *service.ReviewService
+	versionService *service.VersionService
+	attemptService *service.AttemptService
+	plagiarism     *service.PlagiarismChecker
+	manualGrading  *service.ManualGradingService
+	access         *service.AccessChecker
+	masteryStreak  int
 	logger         *logrus.Logger
 }
 
-func NewQuizGRPCHandler(quizService *service.QuizService, quizRepository *repository.QuizRepository, logger *logrus.Logger) *QuizGRPCHandler {
+func NewQuizGRPCHandler(quizService *service.QuizService, quizRepository *repository.QuizRepository, calibrator *service.DifficultyCalibrator, gradingService *service.GradingService, shareService *service.ShareService, reviewService *service.ReviewService, versionService *service.VersionService, attemptService *service.AttemptService, plagiarism *service.PlagiarismChecker, manualGrading *service.ManualGradingService, access *service.AccessChecker, masteryStreak int, logger *logrus.Logger) *QuizGRPCHandler {
+	if masteryStreak <= 0 {
+		masteryStreak = 1
+	}
 	return &QuizGRPCHandler{
 		quizService:    quizService,
 		quizRepository: quizRepository,
+		calibrator:     calibrator,
+		gradingService: gradingService,
+		shareService:   shareService,
+		reviewService:  reviewService,
+		versionService: versionService,
+		attemptService: attemptService,
+		plagiarism:     plagiarism,
+		manualGrading:  manualGrading,
+		access:         access,
+		masteryStreak:  masteryStreak,
 		logger:         logger,
 	}
 }
@@ -32,6 +64,62 @@ func NewQuizGRPCHandler(quizService *service.QuizService, quizRepository *reposi
 func (h *QuizGRPCHandler) GenerateQuiz(ctx context.Context, req *pb.GenerateQuizRequest) (*pb.GenerateQuizResponse, error) {
 	h.logger.Infof("收到生成题目请求，材料ID: %s, 用户ID: %s", req.MaterialId, req.UserId)
 
+	multiSource := len(req.MaterialIds) > 0 || req.CollectionId != ""
+	if multiSource && (req.SelectionText != "" || req.FromTranscript || req.StartTime > 0 || req.EndTime > 0) {
+		return &pb.GenerateQuizResponse{
+			Success: false,
+			Message: "多材料出题不支持选段或转写时间范围",
+		}, nil
+	}
+	if len(req.MaterialIds) > service.MaxQuizSources {
+		return &pb.GenerateQuizResponse{
+			Success: false,
+			Message: fmt.Sprintf("最多同时基于 %d 份材料出题", service.MaxQuizSources),
+		}, nil
+	}
+
+	if req.StartTime < 0 || (req.EndTime > 0 && req.EndTime <= req.StartTime) {
+		return &pb.GenerateQuizResponse{
+			Success: false,
+			Message: "无效的时间范围",
+		}, nil
+	}
+
+	// 在调用 LLM 之前校验课程题目的审核人
+	if req.CourseId != "" && req.ReviewerId != "" {
+		if err := h.reviewService.CheckReviewer(ctx, req.UserId, req.ReviewerId); err != nil {
+			return &pb.GenerateQuizResponse{
+				Success: false,
+				Message: err.Error(),
+			}, nil
+		}
+	}
+
+	selection := strings.TrimSpace(req.SelectionText)
+	if utf8.RuneCountInString(selection) > service.MaxSelectionRunes {
+		return &pb.GenerateQuizResponse{
+			Success: false,
+			Message: fmt.Sprintf("选中内容过长，最多 %d 个字符", service.MaxSelectionRunes),
+		}, nil
+	}
+
+	// 蓝图槽位
+	slots := make([]service.BlueprintSlot, len(req.Blueprint))
+	for i, s := range req.Blueprint {
+		slots[i] = service.BlueprintSlot{
+			Type:           models.QuestionType(s.Type),
+			KnowledgePoint: strings.TrimSpace(s.KnowledgePoint),
+			Difficulty:     models.DifficultyLevel(s.Difficulty),
+			Count:          int(s.Count),
+		}
+	}
+	if err := service.ValidateBlueprint(slots); err != nil {
+		return &pb.GenerateQuizResponse{
+			Success: false,
+			Message: err.Error(),
+		}, nil
+	}
+
 	// 转换请求参数
 	questionTypes := make([]models.QuestionType, len(req.Types))
 	for i, t := range req.Types {
@@ -46,10 +134,28 @@ func (h *QuizGRPCHandler) GenerateQuiz(ctx context.Context, req *pb.GenerateQuiz
 		Difficulty:      models.DifficultyLevel(req.Difficulty),
 		Count:           int(req.Count),
 		KnowledgePoints: req.KnowledgePoints,
+		// 指定时间范围即表示基于转写出题，选中段落优先
+		FromTranscript: selection == "" && (req.FromTranscript || req.StartTime > 0 || req.EndTime > 0),
+		TimeRange:      service.TimeRange{Start: req.StartTime, End: req.EndTime},
+		Selection:      selection,
+		SelectionPage:  int(req.SelectionPage),
+		Language:       langdetect.Normalize(req.Language),
+		MaterialIDs:    req.MaterialIds,
+		CollectionID:   req.CollectionId,
 	}
 
-	// 生成题目
-	generatedQuestions, err := h.quizService.GenerateQuestions(ctx, genReq)
+	// 生成题目，指定蓝图时按槽位生成，忽略 types、difficulty 与 count
+	var generatedQuestions []*service.GeneratedQuestion
+	var fills []service.SlotFill
+	var err error
+	if len(slots) > 0 {
+		fills, err = h.quizService.GenerateFromBlueprint(ctx, genReq, slots)
+		for _, fill := range fills {
+			generatedQuestions = append(generatedQuestions, fill.Questions...)
+		}
+	} else {
+		generatedQuestions, err = h.quizService.GenerateQuestions(ctx, genReq)
+	}
 	if err != nil {
 		h.logger.Errorf("生成题目失败: %v", err)
 		return &pb.GenerateQuizResponse{
@@ -58,14 +164,36 @@ func (h *QuizGRPCHandler) GenerateQuiz(ctx context.Context, req *pb.GenerateQuiz
 		}, nil
 	}
 
+	if len(slots) > 0 && len(generatedQuestions) == 0 {
+		return &pb.GenerateQuizResponse{
+			Success:         false,
+			Message:         "未能按蓝图生成题目",
+			BlueprintReport: buildBlueprintReport(fills, nil),
+		}, nil
+	}
+
 	// 转换为数据库模型并保存
 	var questions []*models.Question
 	for _, gq := range generatedQuestions {
 		question := h.quizService.ConvertToQuestionModel(gq, req.MaterialId, req.UserId)
 		questions = append(questions, question)
 	}
+	// 课程内生成的题目需经审核才对学生可见
+	if err := h.reviewService.PrepareGenerated(questions, req.CourseId, req.ReviewerId); err != nil {
+		return &pb.GenerateQuizResponse{
+			Success: false,
+			Message: err.Error(),
+		}, nil
+	}
+	versions := h.versionService.InitialVersions(questions)
 
-	if err := h.quizRepository.CreateQuestions(questions); err != nil {
+	err = h.quizRepository.WithContext(ctx).Transaction(func(txRepo *repository.QuizRepository) error {
+		if err := txRepo.CreateQuestions(questions); err != nil {
+			return err
+		}
+		return txRepo.CreateQuestionVersions(versions)
+	})
+	if err != nil {
 		h.logger.Errorf("保存题目失败: %v", err)
 		return &pb.GenerateQuizResponse{
 			Success: false,
@@ -84,17 +212,61 @@ func (h *QuizGRPCHandler) GenerateQuiz(ctx context.Context, req *pb.GenerateQuiz
 		pbQuestions = append(pbQuestions, pbQ)
 	}
 
-	return &pb.GenerateQuizResponse{
+	resp := &pb.GenerateQuizResponse{
 		Success:   true,
 		Message:   "题目生成成功",
 		Questions: pbQuestions,
-	}, nil
+	}
+	if len(slots) > 0 {
+		resp.BlueprintReport = buildBlueprintReport(fills, questions)
+		if !resp.BlueprintReport.Satisfied {
+			resp.Message = fmt.Sprintf("蓝图部分未满足：已生成 %d/%d 道题目", resp.BlueprintReport.Filled, resp.BlueprintReport.Requested)
+		}
+	}
+	return resp, nil
 }
 
-// 获取题目
+// 生成蓝图完成情况报告；questions 与各槽位题目按顺序一一对应，未保存时为 nil
+func buildBlueprintReport(fills []service.SlotFill, questions []*models.Question) *pb.BlueprintReport {
+	report := &pb.BlueprintReport{Satisfied: true}
+	offset := 0
+	for i, fill := range fills {
+		slotReport := &pb.BlueprintSlotReport{
+			SlotIndex: int32(i),
+			Slot: &pb.BlueprintSlot{
+				Type:           pb.QuestionType(fill.Slot.Type),
+				KnowledgePoint: fill.Slot.KnowledgePoint,
+				Difficulty:     pb.DifficultyLevel(fill.Slot.Difficulty),
+				Count:          int32(fill.Slot.Count),
+			},
+			Filled:   int32(len(fill.Questions)),
+			Rejected: make(map[string]int32, len(fill.Rejected)),
+			Message:  fill.Message,
+		}
+		for reason, n := range fill.Rejected {
+			slotReport.Rejected[reason] = int32(n)
+		}
+		for range fill.Questions {
+			if offset < len(questions) {
+				slotReport.QuestionIds = append(slotReport.QuestionIds, questions[offset].QuestionID)
+			}
+			offset++
+		}
+		report.Slots = append(report.Slots, slotReport)
+		report.Requested += int32(fill.Slot.Count)
+		report.Filled += slotReport.Filled
+		if !fill.Satisfied() {
+			report.Satisfied = false
+		}
+	}
+	return report
+}
+
+// 获取题目，未通过审核的课程题目对创建者与审核人以外的用户视为不存在；
+// 创建者与审核人以外的用户看不到答案与解析
 func (h *QuizGRPCHandler) GetQuiz(ctx context.Context, req *pb.GetQuizRequest) (*pb.GetQuizResponse, error) {
 	question, err := h.quizRepository.GetQuestionByID(req.QuestionId)
-	if err != nil {
+	if err != nil || !question.VisibleTo(req.UserId) {
 		return &pb.GetQuizResponse{
 			Success: false,
 			Message: "题目不存在",
@@ -108,6 +280,9 @@ func (h *QuizGRPCHandler) GetQuiz(ctx context.Context, req *pb.GetQuizRequest) (
 			Message: "转换题目格式失败",
 		}, nil
 	}
+	if !canSeeAnswers(question, req.UserId) {
+		pbQuestion = publicQuestion(pbQuestion)
+	}
 
 	return &pb.GetQuizResponse{
 		Success:  true,
@@ -116,7 +291,7 @@ func (h *QuizGRPCHandler) GetQuiz(ctx context.Context, req *pb.GetQuizRequest) (
 	}, nil
 }
 
-// 获取题目列表
+// 获取题目列表，指定 course_id 时列出课程题目（学生只能看到审核通过的题目，且不含答案与解析），否则列出本人创建的题目
 func (h *QuizGRPCHandler) ListQuizzes(ctx context.Context, req *pb.ListQuizzesRequest) (*pb.ListQuizzesResponse, error) {
 	var questionType *models.QuestionType
 	if req.Type != pb.QuestionType_MULTIPLE_CHOICE && req.Type != 0 {
@@ -139,7 +314,14 @@ func (h *QuizGRPCHandler) ListQuizzes(ctx context.Context, req *pb.ListQuizzesRe
 		pageSize = 10
 	}
 
-	questions, total, err := h.quizRepository.ListQuestions(req.UserId, req.MaterialId, questionType, difficulty, page, pageSize)
+	var questions []*models.Question
+	var total int64
+	var err error
+	if req.CourseId != "" {
+		questions, total, err = h.quizRepository.ListCourseQuestions(req.CourseId, req.UserId, req.MaterialId, questionType, difficulty, page, pageSize)
+	} else {
+		questions, total, err = h.quizRepository.ListQuestions(req.UserId, req.MaterialId, questionType, difficulty, page, pageSize)
+	}
 	if err != nil {
 		return &pb.ListQuizzesResponse{
 			Success: false,
@@ -154,6 +336,9 @@ func (h *QuizGRPCHandler) ListQuizzes(ctx context.Context, req *pb.ListQuizzesRe
 			h.logger.Errorf("转换题目格式失败: %v", err)
 			continue
 		}
+		if !canSeeAnswers(q, req.UserId) {
+			pbQ = publicQuestion(pbQ)
+		}
 		pbQuestions = append(pbQuestions, pbQ)
 	}
 
@@ -169,17 +354,52 @@ func (h *QuizGRPCHandler) ListQuizzes(ctx context.Context, req *pb.ListQuizzesRe
 
 // 提交答案
 func (h *QuizGRPCHandler) SubmitAnswer(ctx context.Context, req *pb.SubmitAnswerRequest) (*pb.SubmitAnswerResponse, error) {
-	// 获取题目
+	// 获取题目，未通过审核的课程题目只有创建者与审核人可以试答
 	question, err := h.quizRepository.GetQuestionByID(req.QuestionId)
-	if err != nil {
+	if err != nil || !question.VisibleTo(req.UserId) {
 		return &pb.SubmitAnswerResponse{
 			Success: false,
 			Message: "题目不存在",
 		}, nil
 	}
 
-	// 评估答案
-	score, evaluationExplanation, err := h.quizService.EvaluateSubjectiveAnswer(ctx, question, req.Answer, req.UserId)
+	// 通过作答会话提交时，答案按会话的选项顺序换算回原选项再评分；选项会被打乱的选择题必须通过作答会话提交
+	var attempt *models.QuestionAttempt
+	var optionOrder []int
+	answer := req.Answer
+	if req.TimeSpentMs < 0 {
+		return &pb.SubmitAnswerResponse{
+			Success: false,
+			Message: service.ErrInvalidTimeSpent.Error(),
+		}, nil
+	}
+	if req.AttemptId == "" && service.RequiresAttempt(question) {
+		return &pb.SubmitAnswerResponse{
+			Success: false,
+			Message: service.ErrAttemptRequired.Error(),
+		}, nil
+	}
+	if req.AttemptId != "" {
+		attempt, err = h.attemptService.Resolve(req.AttemptId, req.UserId, question)
+		if err != nil {
+			return &pb.SubmitAnswerResponse{
+				Success: false,
+				Message: err.Error(),
+			}, nil
+		}
+		optionOrder = attempt.GetOptionOrder()
+		answer = service.UnpermuteAnswer(req.Answer, optionOrder)
+	}
+	// 上报的耗时直接计入题目统计，限制在上限与作答会话的实际时长内
+	var startedAt time.Time
+	if attempt != nil {
+		startedAt = attempt.CreatedAt
+	}
+	timeSpentMs := service.ClampTimeSpent(req.TimeSpentMs, startedAt)
+
+	// 解析评分策略并评估答案
+	policy := h.gradingService.Resolve(question.MaterialID, question.CourseID)
+	evaluation, err := h.quizService.EvaluateAnswer(ctx, question, answer, req.PartAnswers, policy.PartialCreditMode, req.UserId)
 	if err != nil {
 		h.logger.Errorf("评估答案失败: %v", err)
 		return &pb.SubmitAnswerResponse{
@@ -188,29 +408,110 @@ func (h *QuizGRPCHandler) SubmitAnswer(ctx context.Context, req *pb.SubmitAnswer
 		}, nil
 	}
 
-	isCorrect := score >= 0.6 // 设置及格线为60%
+	// 是否及格按原始得分判断，重复作答扣分只影响记录的得分
+	rawScore := evaluation.Score
+	isCorrect := rawScore >= policy.PassThreshold
+
+	previousAttempts, err := h.quizRepository.CountUserAttempts(req.QuestionId, req.UserId)
+	if err != nil {
+		h.logger.Errorf("统计历史作答次数失败: %v", err)
+	}
+	score := policy.ApplyRetryPenalty(rawScore, int(previousAttempts))
 
-	// 保存答题记录
+	// 保存答题记录，多空题只提交分项答案时拼接保存
+	answerText := answer
+	if answerText == "" && len(req.PartAnswers) > 0 {
+		answerText = strings.Join(req.PartAnswers, " | ")
+	}
 	userAnswer := &models.UserAnswer{
-		AnswerID:   uuid.New().String(),
-		QuestionID: req.QuestionId,
-		UserID:     req.UserId,
-		Answer:     req.Answer,
-		IsCorrect:  isCorrect,
-		Score:      score,
+		AnswerID:    uuid.New().String(),
+		QuestionID:  req.QuestionId,
+		UserID:      req.UserId,
+		Answer:      answerText,
+		IsCorrect:   isCorrect,
+		Score:       score,
+		TimeSpentMs: timeSpentMs,
+		AnsweredAt:  time.Now(),
+		// 记录作答时的题目版本，题目之后被编辑也能核对当时的题面
+		QuestionVersion: question.Version,
+		AttemptID:       req.AttemptId,
+	}
+	if attempt != nil {
+		userAnswer.OptionOrder = attempt.OptionOrder
+	}
+	if len(evaluation.PartResults) > 0 {
+		partResultsJSON, _ := json.Marshal(evaluation.PartResults)
+		userAnswer.PartResults = string(partResultsJSON)
+	}
+	// 主观题的 AI 得分作为建议得分，进入教师评分队列
+	if question.RequiresManualGrading() {
+		userAnswer.GradingStatus = models.GradingStatusPending
+		userAnswer.SuggestedScore = rawScore
+		userAnswer.AIFeedback = evaluation.Feedback
 	}
 
-	if err := h.quizRepository.CreateUserAnswer(userAnswer); err != nil {
-		h.logger.Errorf("保存答题记录失败: %v", err)
+	// 论述题查重，作答向量随答题记录保存，供之后的作答比较
+	similarity, embedding := h.plagiarism.Check(ctx, question, req.UserId, answerText)
+	if similarity != nil {
+		similarityJSON, _ := json.Marshal(similarity)
+		userAnswer.Similarity = string(similarityJSON)
+		userAnswer.SimilarityFlagged = similarity.Flagged
+	}
+	if embedding != nil {
+		embedding.AnswerID = userAnswer.AnswerID
 	}
 
-	// 更新知识点统计
-	var knowledgePoints []string
-	if question.KnowledgePoints != "" {
-		json.Unmarshal([]byte(question.KnowledgePoints), &knowledgePoints)
-		for _, kp := range knowledgePoints {
-			h.quizRepository.CalculateAndUpdateKnowledgeStats(req.UserId, question.MaterialID, kp)
+	// 答题记录、题目统计、错题本和答题事件在同一事务中写入，知识点统计由后台任务根据事件异步重算
+	err = h.quizRepository.WithContext(ctx).Transaction(func(txRepo *repository.QuizRepository) error {
+		if attempt != nil {
+			n, err := txRepo.CompleteQuestionAttempt(attempt.AttemptID, userAnswer.AnsweredAt)
+			if err != nil {
+				return fmt.Errorf("更新作答会话失败: %w", err)
+			}
+			if n == 0 {
+				return service.ErrAttemptSubmitted
+			}
+		}
+		if err := txRepo.CreateUserAnswer(userAnswer); err != nil {
+			return fmt.Errorf("保存答题记录失败: %w", err)
+		}
+		if embedding != nil {
+			if err := txRepo.CreateAnswerEmbedding(embedding); err != nil {
+				return fmt.Errorf("保存作答向量失败: %w", err)
+			}
+		}
+		if err := txRepo.RecordQuestionAttempt(question, isCorrect, rawScore, timeSpentMs); err != nil {
+			return fmt.Errorf("更新题目统计失败: %w", err)
+		}
+		if err := txRepo.RecordMistakeAttempt(question, req.UserId, isCorrect, h.masteryStreak); err != nil {
+			return fmt.Errorf("更新错题本失败: %w", err)
 		}
+		if question.KnowledgePoints != "" {
+			event := &models.AnswerEvent{
+				AnswerID:        userAnswer.AnswerID,
+				UserID:          req.UserId,
+				QuestionID:      question.QuestionID,
+				MaterialID:      question.MaterialID,
+				KnowledgePoints: question.KnowledgePoints,
+			}
+			if err := txRepo.CreateAnswerEvent(event); err != nil {
+				return fmt.Errorf("写入答题事件失败: %w", err)
+			}
+		}
+		return nil
+	})
+	if errors.Is(err, service.ErrAttemptSubmitted) {
+		return &pb.SubmitAnswerResponse{
+			Success: false,
+			Message: err.Error(),
+		}, nil
+	}
+	if err != nil {
+		h.logger.Errorf("提交答案失败: %v", err)
+		return &pb.SubmitAnswerResponse{
+			Success: false,
+			Message: "保存答题记录失败",
+		}, nil
 	}
 
 	return &pb.SubmitAnswerResponse{
@@ -218,8 +519,16 @@ func (h *QuizGRPCHandler) SubmitAnswer(ctx context.Context, req *pb.SubmitAnswer
 		Message:       "答案提交成功",
 		IsCorrect:     isCorrect,
 		Score:         score,
-		CorrectAnswer: question.CorrectAnswer,
-		Explanation:   evaluationExplanation,
+		CorrectAnswer: service.PermuteAnswer(question.CorrectAnswer, optionOrder),
+		Explanation:   evaluation.Feedback,
+		BlankMatch:    convertToPBBlankMatch(evaluation.BlankMatch),
+		PartResults:   convertToPBPartResults(evaluation.PartResults),
+		RawScore:      rawScore,
+		PassThreshold: policy.PassThreshold,
+		AttemptNumber: int32(previousAttempts) + 1,
+		AttemptId:     req.AttemptId,
+		Similarity:    convertToPBSimilarity(similarity),
+		GradingStatus: userAnswer.GradingStatus,
 	}, nil
 }
 
@@ -244,16 +553,7 @@ func (h *QuizGRPCHandler) GetUserQuizHistory(ctx context.Context, req *pb.GetUse
 
 	var pbAnswers []*pb.UserAnswer
 	for _, answer := range answers {
-		pbAnswer := &pb.UserAnswer{
-			AnswerId:   answer.AnswerID,
-			QuestionId: answer.QuestionID,
-			UserId:     answer.UserID,
-			Answer:     answer.Answer,
-			IsCorrect:  answer.IsCorrect,
-			Score:      answer.Score,
-			AnsweredAt: answer.AnsweredAt.Format("2006-01-02 15:04:05"),
-		}
-		pbAnswers = append(pbAnswers, pbAnswer)
+		pbAnswers = append(pbAnswers, convertToPBUserAnswer(answer))
 	}
 
 	return &pb.GetUserQuizHistoryResponse{
@@ -302,6 +602,330 @@ func (h *QuizGRPCHandler) GetKnowledgeStats(ctx context.Context, req *pb.GetKnow
 	}, nil
 }
 
+// 获取题库作答分析
+func (h *QuizGRPCHandler) GetQuestionAnalytics(ctx context.Context, req *pb.GetQuestionAnalyticsRequest) (*pb.GetQuestionAnalyticsResponse, error) {
+	var stats []*models.QuestionStats
+	var total int64
+
+	// 统计包含其他用户的作答，教师与管理员可查看全部题目，其他用户只能查看本人创建的题目
+	if req.UserId == "" {
+		return nil, status.Error(codes.PermissionDenied, service.ErrPermissionDenied.Error())
+	}
+	staff, err := h.access.IsStaff(ctx, req.UserId)
+	if err != nil {
+		return nil, accessError(err)
+	}
+	creatorID := ""
+	if !staff {
+		creatorID = req.UserId
+	}
+
+	if req.QuestionId != "" {
+		if creatorID != "" {
+			question, err := h.quizRepository.GetQuestionByID(req.QuestionId)
+			if err != nil || question.CreatorID != creatorID {
+				return nil, status.Error(codes.PermissionDenied, "只能查看本人创建的题目的作答统计")
+			}
+		}
+		st, err := h.quizRepository.GetQuestionStats(req.QuestionId)
+		if err != nil {
+			return &pb.GetQuestionAnalyticsResponse{
+				Success: false,
+				Message: "题目暂无作答统计",
+			}, nil
+		}
+		stats = []*models.QuestionStats{st}
+		total = 1
+	} else {
+		page := int(req.Page)
+		if page <= 0 {
+			page = 1
+		}
+		pageSize := int(req.PageSize)
+		if pageSize <= 0 {
+			pageSize = 10
+		}
+
+		stats, total, err = h.quizRepository.ListQuestionStats(req.MaterialId, creatorID, int(req.MinAttempts), page, pageSize)
+		if err != nil {
+			return &pb.GetQuestionAnalyticsResponse{
+				Success: false,
+				Message: "获取题库分析失败",
+			}, nil
+		}
+	}
+
+	ids := make([]string, len(stats))
+	for i, st := range stats {
+		ids[i] = st.QuestionID
+	}
+	questions, err := h.quizRepository.GetQuestionsByIDs(ids)
+	if err != nil {
+		return &pb.GetQuestionAnalyticsResponse{
+			Success: false,
+			Message: "获取题目信息失败",
+		}, nil
+	}
+	current := make(map[string]models.DifficultyLevel, len(questions))
+	for _, q := range questions {
+		current[q.QuestionID] = q.Difficulty
+	}
+
+	var analytics []*pb.QuestionAnalytics
+	for _, st := range stats {
+		item := &pb.QuestionAnalytics{
+			QuestionId:          st.QuestionID,
+			MaterialId:          st.MaterialID,
+			AttemptCount:        int32(st.AttemptCount),
+			CorrectCount:        int32(st.CorrectCount),
+			SuccessRate:         st.SuccessRate(),
+			AvgScore:            st.AvgScore(),
+			AvgTimeMs:           st.AvgTimeMs(),
+			OriginalDifficulty:  pb.DifficultyLevel(st.OriginalDifficulty),
+			CurrentDifficulty:   pb.DifficultyLevel(current[st.QuestionID]),
+			SuggestedDifficulty: pb.DifficultyLevel(current[st.QuestionID]),
+		}
+		// 作答次数足够时才给出建议难度
+		if st.AttemptCount >= h.calibrator.MinAttempts() {
+			item.SuggestedDifficulty = pb.DifficultyLevel(h.calibrator.EstimateDifficulty(st.SuccessRate()))
+		}
+		if st.LastCalibratedAt != nil {
+			item.LastCalibratedAt = timestamppb.New(*st.LastCalibratedAt)
+		}
+		analytics = append(analytics, item)
+	}
+
+	return &pb.GetQuestionAnalyticsResponse{
+		Success:   true,
+		Message:   "获取成功",
+		Analytics: analytics,
+		Total:     int32(total),
+	}, nil
+}
+
+// 获取错题本
+func (h *QuizGRPCHandler) ListMistakes(ctx context.Context, req *pb.ListMistakesRequest) (*pb.ListMistakesResponse, error) {
+	page := int(req.Page)
+	if page <= 0 {
+		page = 1
+	}
+	pageSize := int(req.PageSize)
+	if pageSize <= 0 {
+		pageSize = 20
+	}
+
+	records, total, err := h.quizRepository.ListMistakes(req.UserId, req.MaterialId, req.IncludeMastered, page, pageSize)
+	if err != nil {
+		return &pb.ListMistakesResponse{
+			Success: false,
+			Message: "获取错题本失败",
+		}, nil
+	}
+
+	items, questions, err := h.buildMistakeItems(records)
+	if err != nil {
+		return &pb.ListMistakesResponse{
+			Success: false,
+			Message: "获取题目信息失败",
+		}, nil
+	}
+
+	// 按知识点分组，一道题关联多个知识点时会出现在每个分组中
+	var groups []*pb.MistakeGroup
+	groupIndex := make(map[string]*pb.MistakeGroup)
+	for i, item := range items {
+		knowledgePoints := questions[i].KnowledgePoints
+		if len(knowledgePoints) == 0 {
+			knowledgePoints = []string{"未分类"}
+		}
+		for _, kp := range knowledgePoints {
+			group, ok := groupIndex[kp]
+			if !ok {
+				group = &pb.MistakeGroup{KnowledgePoint: kp}
+				groupIndex[kp] = group
+				groups = append(groups, group)
+			}
+			group.Items = append(group.Items, item)
+		}
+	}
+
+	return &pb.ListMistakesResponse{
+		Success: true,
+		Message: "获取成功",
+		Groups:  groups,
+		Total:   int32(total),
+	}, nil
+}
+
+// 获取待重练的错题
+func (h *QuizGRPCHandler) GetRetryQuestions(ctx context.Context, req *pb.GetRetryQuestionsRequest) (*pb.GetRetryQuestionsResponse, error) {
+	count := int(req.Count)
+	if count <= 0 {
+		count = 10
+	}
+
+	records, err := h.quizRepository.GetRetryQuestions(req.UserId, req.MaterialId, req.KnowledgePoint, count)
+	if err != nil {
+		return &pb.GetRetryQuestionsResponse{
+			Success: false,
+			Message: "获取重练题目失败",
+		}, nil
+	}
+
+	items, _, err := h.buildMistakeItems(records)
+	if err != nil {
+		return &pb.GetRetryQuestionsResponse{
+			Success: false,
+			Message: "获取题目信息失败",
+		}, nil
+	}
+
+	remaining, err := h.quizRepository.CountUnmasteredMistakes(req.UserId, req.MaterialId)
+	if err != nil {
+		h.logger.Errorf("统计未掌握错题失败: %v", err)
+	}
+
+	message := "获取成功"
+	if len(items) == 0 {
+		message = "错题已全部掌握"
+	}
+
+	return &pb.GetRetryQuestionsResponse{
+		Success:   true,
+		Message:   message,
+		Items:     items,
+		Remaining: int32(remaining),
+	}, nil
+}
+
+// 辅助函数：将错题记录与题目合并为响应条目，已删除的题目会被跳过
+func (h *QuizGRPCHandler) buildMistakeItems(records []*models.MistakeRecord) ([]*pb.MistakeItem, []*pb.Question, error) {
+	ids := make([]string, len(records))
+	for i, rec := range records {
+		ids[i] = rec.QuestionID
+	}
+	questions, err := h.quizRepository.GetQuestionsByIDs(ids)
+	if err != nil {
+		return nil, nil, err
+	}
+	questionIndex := make(map[string]*models.Question, len(questions))
+	for _, q := range questions {
+		questionIndex[q.QuestionID] = q
+	}
+
+	var items []*pb.MistakeItem
+	var pbQuestions []*pb.Question
+	for _, rec := range records {
+		q, ok := questionIndex[rec.QuestionID]
+		if !ok {
+			continue
+		}
+		pbQ, err := h.convertToPBQuestion(q)
+		if err != nil {
+			h.logger.Errorf("转换题目格式失败: %v", err)
+			continue
+		}
+		items = append(items, &pb.MistakeItem{
+			Question:      pbQ,
+			WrongCount:    int32(rec.WrongCount),
+			CorrectStreak: int32(rec.CorrectStreak),
+			Mastered:      rec.Mastered,
+			LastWrongAt:   timestamppb.New(rec.LastWrongAt),
+			LastAttemptAt: timestamppb.New(rec.LastAttemptAt),
+		})
+		pbQuestions = append(pbQuestions, pbQ)
+	}
+	return items, pbQuestions, nil
+}
+
+// 获取评分策略
+func (h *QuizGRPCHandler) GetGradingPolicy(ctx context.Context, req *pb.GetGradingPolicyRequest) (*pb.GetGradingPolicyResponse, error) {
+	policy := h.gradingService.Resolve(req.MaterialId, req.CourseId)
+	return &pb.GetGradingPolicyResponse{
+		Success: true,
+		Message: "获取成功",
+		Policy:  convertToPBGradingPolicy(policy),
+	}, nil
+}
+
+// 设置评分策略
+func (h *QuizGRPCHandler) SetGradingPolicy(ctx context.Context, req *pb.SetGradingPolicyRequest) (*pb.SetGradingPolicyResponse, error) {
+	if req.Policy == nil {
+		return &pb.SetGradingPolicyResponse{
+			Success: false,
+			Message: "评分策略不能为空",
+		}, nil
+	}
+	if err := h.authorizeGradingPolicy(ctx, req.UserId, req.Policy); err != nil {
+		h.logger.Warnf("用户 %s 设置 %s 评分策略被拒绝: %v", req.UserId, req.Policy.ScopeType, err)
+		return nil, accessError(err)
+	}
+
+	policy := &models.GradingPolicy{
+		ScopeType:         req.Policy.ScopeType,
+		ScopeID:           req.Policy.ScopeId,
+		PassThreshold:     req.Policy.PassThreshold,
+		PartialCreditMode: req.Policy.PartialCreditMode,
+		RetryPenalty:      req.Policy.RetryPenalty,
+		MaxRetryPenalty:   req.Policy.MaxRetryPenalty,
+		UpdatedBy:         req.UserId,
+	}
+	if err := h.gradingService.Save(policy); err != nil {
+		h.logger.Errorf("保存评分策略失败: %v", err)
+		return &pb.SetGradingPolicyResponse{
+			Success: false,
+			Message: err.Error(),
+		}, nil
+	}
+
+	return &pb.SetGradingPolicyResponse{
+		Success: true,
+		Message: "评分策略已保存",
+		Policy:  convertToPBGradingPolicy(policy),
+	}, nil
+}
+
+// 材料评分策略只能由材料上传者设置，课程与全局策略只能由教师或管理员设置
+func (h *QuizGRPCHandler) authorizeGradingPolicy(ctx context.Context, userID string, policy *pb.GradingPolicy) error {
+	switch policy.ScopeType {
+	case models.PolicyScopeGlobal, models.PolicyScopeCourse:
+		return h.access.RequireStaff(ctx, userID)
+	case models.PolicyScopeMaterial:
+		// 材料ID为空时由 GradingService.Save 返回参数错误
+		if policy.ScopeId == "" {
+			return nil
+		}
+		return h.access.RequireMaterialOwner(ctx, policy.ScopeId, userID)
+	}
+	return nil
+}
+
+// 权限不足返回 PermissionDenied，权限查询失败返回 Unavailable
+func accessError(err error) error {
+	if errors.Is(err, service.ErrPermissionDenied) {
+		return status.Error(codes.PermissionDenied, err.Error())
+	}
+	return status.Error(codes.Unavailable, err.Error())
+}
+
+// 题目的答案与解析只对创建者与审核人可见，其他用户作答后从提交结果中获得
+func canSeeAnswers(q *models.Question, userID string) bool {
+	return userID != "" && (userID == q.CreatorID || userID == q.ReviewerID)
+}
+
+// 辅助函数：转换评分策略
+func convertToPBGradingPolicy(p *models.GradingPolicy) *pb.GradingPolicy {
+	return &pb.GradingPolicy{
+		ScopeType:         p.ScopeType,
+		ScopeId:           p.ScopeID,
+		PassThreshold:     p.PassThreshold,
+		PartialCreditMode: p.PartialCreditMode,
+		RetryPenalty:      p.RetryPenalty,
+		MaxRetryPenalty:   p.MaxRetryPenalty,
+		UpdatedBy:         p.UpdatedBy,
+	}
+}
+
 // 辅助函数：转换为protobuf格式
 func (h *QuizGRPCHandler) convertToPBQuestion(q *models.Question) (*pb.Question, error) {
 	var options []string
@@ -314,18 +938,111 @@ func (h *QuizGRPCHandler) convertToPBQuestion(q *models.Question) (*pb.Question,
 		json.Unmarshal([]byte(q.KnowledgePoints), &knowledgePoints)
 	}
 
-	return &pb.Question{
-		QuestionId:      q.QuestionID,
-		Type:            pb.QuestionType(q.Type),
-		Content:         q.Content,
-		Options:         options,
-		CorrectAnswer:   q.CorrectAnswer,
-		Explanation:     q.Explanation,
-		Difficulty:      pb.DifficultyLevel(q.Difficulty),
-		KnowledgePoints: knowledgePoints,
-		MaterialId:      q.MaterialID,
-		CreatedAt:       q.CreatedAt.Format("2006-01-02 15:04:05"),
-	}, nil
+	pbQ := &pb.Question{
+		QuestionId:          q.QuestionID,
+		Type:                pb.QuestionType(q.Type),
+		Content:             q.Content,
+		Options:             options,
+		CorrectAnswer:       q.CorrectAnswer,
+		Explanation:         q.Explanation,
+		Difficulty:          pb.DifficultyLevel(q.Difficulty),
+		KnowledgePoints:     knowledgePoints,
+		MaterialId:          q.MaterialID,
+		CreatedAt:           timestamppb.New(q.CreatedAt),
+		AnswerAliases:       q.GetAnswerAliases(),
+		NumericTolerance:    q.NumericTolerance,
+		Parts:               convertToPBParts(q.GetParts()),
+		Citations:           convertToPBCitations(q.GetCitations()),
+		RequestedDifficulty: pb.DifficultyLevel(q.RequestedDifficulty),
+		EstimatedDifficulty: pb.DifficultyLevel(q.EstimatedDifficulty),
+		DifficultySource:    q.DifficultySource,
+		CourseId:            q.CourseID,
+		ReviewStatus:        q.ReviewStatus,
+		ReviewerId:          q.ReviewerID,
+		ReviewComment:       q.ReviewComment,
+		Version:             int32(q.Version),
+	}
+	if q.ReviewedAt != nil {
+		pbQ.ReviewedAt = timestamppb.New(*q.ReviewedAt)
+	}
+	return pbQ, nil
+}
+
+// 辅助函数：转换题目引用的材料片段
+func convertToPBCitations(citations []models.QuestionCitation) []*pb.QuestionCitation {
+	var pbCitations []*pb.QuestionCitation
+	for _, c := range citations {
+		pbCitations = append(pbCitations, &pb.QuestionCitation{
+			ChunkId:       c.ChunkID,
+			MaterialId:    c.MaterialID,
+			MaterialTitle: c.MaterialTitle,
+			Page:          int32(c.Page),
+			Timecode:      c.Timecode,
+			Snippet:       c.Snippet,
+		})
+	}
+	return pbCitations
+}
+
+// 辅助函数：转换题目分项
+func convertToPBParts(parts []models.QuestionPart) []*pb.QuestionPart {
+	var pbParts []*pb.QuestionPart
+	for _, p := range parts {
+		pbParts = append(pbParts, &pb.QuestionPart{
+			Label:            p.Label,
+			CorrectAnswer:    p.CorrectAnswer,
+			AnswerAliases:    p.AnswerAliases,
+			NumericTolerance: p.NumericTolerance,
+			Weight:           p.Weight,
+		})
+	}
+	return pbParts
+}
+
+// 辅助函数：转换分项评分结果
+func convertToPBPartResults(results []models.PartResult) []*pb.PartResult {
+	var pbResults []*pb.PartResult
+	for _, r := range results {
+		pbResults = append(pbResults, &pb.PartResult{
+			Index:         int32(r.Index),
+			Answer:        r.Answer,
+			IsCorrect:     r.IsCorrect,
+			Score:         r.Score,
+			Weight:        r.Weight,
+			CorrectAnswer: r.CorrectAnswer,
+			MatchType:     r.MatchType,
+		})
+	}
+	return pbResults
+}
+
+// 辅助函数：转换填空题匹配详情
+func convertToPBBlankMatch(m *service.FillBlankMatch) *pb.FillBlankMatch {
+	if m == nil {
+		return nil
+	}
+	return &pb.FillBlankMatch{
+		Matched:          m.Matched,
+		MatchType:        m.MatchType,
+		MatchedAnswer:    m.MatchedAnswer,
+		NormalizedAnswer: m.NormalizedAnswer,
+		NumericDiff:      m.NumericDiff,
+	}
+}
+
+// 辅助函数：转换论述题查重结果，不返回最相近的其他学生作答
+func convertToPBSimilarity(c *models.SimilarityCheck) *pb.SimilarityCheck {
+	if c == nil {
+		return nil
+	}
+	return &pb.SimilarityCheck{
+		MaterialSimilarity: c.MaterialSimilarity,
+		MaterialChunkId:    c.MaterialChunkID,
+		PeerSimilarity:     c.PeerSimilarity,
+		PeersCompared:      int32(c.PeersCompared),
+		Flagged:            c.Flagged,
+		Reasons:            c.Reasons,
+	}
 }
 
 // 辅助函数：检查答案
@@ -334,8 +1051,11 @@ func (h *QuizGRPCHandler) checkAnswer(question *models.Question, userAnswer stri
 	case models.MultipleChoice, models.TrueFalse:
 		return question.CorrectAnswer == userAnswer
 	case models.FillBlank:
-		// 简单的字符串匹配，可以后续优化为模糊匹配
-		return question.CorrectAnswer == userAnswer
+		if parts := question.GetParts(); len(parts) > 0 {
+			_, score := service.EvaluateParts(parts, service.SplitPartAnswers(userAnswer), models.PartialCreditWeighted)
+			return score == 1
+		}
+		return service.MatchFillBlank(question, userAnswer).Matched
 	case models.ShortAnswer, models.Essay:
 		// 对于主观题，这里可以集成AI评分
 		return true // 暂时返回true，需要人工或AI评分
@@ -0,0 +1,93 @@
+package grpc
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	grpclib "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	materialpb "github.com/RigelNana/arkstudy/proto/material"
+	pb "github.com/RigelNana/arkstudy/proto/quiz"
+	userpb "github.com/RigelNana/arkstudy/proto/user"
+	"github.com/RigelNana/arkstudy/quiz-service/models"
+	"github.com/RigelNana/arkstudy/quiz-service/service"
+)
+
+// fakeMaterials 按材料ID返回上传者，与 material-service 一样在 user_id 不符时返回 Found=false
+type fakeMaterials struct {
+	materialpb.MaterialServiceClient
+	owners map[string]string
+}
+
+func (f fakeMaterials) GetMaterial(ctx context.Context, req *materialpb.GetMaterialRequest, opts ...grpclib.CallOption) (*materialpb.GetMaterialResponse, error) {
+	owner, ok := f.owners[req.MaterialId]
+	if !ok || (req.UserId != "" && req.UserId != owner) {
+		return &materialpb.GetMaterialResponse{Found: false, Message: "permission denied"}, nil
+	}
+	return &materialpb.GetMaterialResponse{Found: true, Material: &materialpb.MaterialInfo{Id: req.MaterialId, UserId: owner}}, nil
+}
+
+// fakeUsers 按用户ID返回角色
+type fakeUsers struct {
+	userpb.UserServiceClient
+	roles map[string]string
+}
+
+func (f fakeUsers) GetUserByID(ctx context.Context, req *userpb.GetUserByIDRequest, opts ...grpclib.CallOption) (*userpb.GetUserResponse, error) {
+	role, ok := f.roles[req.Id]
+	if !ok {
+		return &userpb.GetUserResponse{Found: false}, nil
+	}
+	return &userpb.GetUserResponse{Found: true, User: &userpb.UserInfo{Id: req.Id, Role: role}}, nil
+}
+
+func newPolicyTestHandler() *QuizGRPCHandler {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	access := service.NewAccessCheckerWithClients(
+		fakeMaterials{owners: map[string]string{"material-1": "owner"}},
+		fakeUsers{roles: map[string]string{"owner": "student", "student": "student", "teacher": service.RoleTeacher}},
+		logger,
+	)
+	return &QuizGRPCHandler{access: access, logger: logger}
+}
+
+func TestSetGradingPolicyRejectsGlobalPolicyFromStudent(t *testing.T) {
+	h := newPolicyTestHandler()
+	_, err := h.SetGradingPolicy(context.Background(), &pb.SetGradingPolicyRequest{
+		UserId: "student",
+		Policy: &pb.GradingPolicy{ScopeType: models.PolicyScopeGlobal, PassThreshold: 0.1},
+	})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("expected PermissionDenied, got %v", err)
+	}
+}
+
+func TestSetGradingPolicyRejectsMaterialPolicyFromNonOwner(t *testing.T) {
+	h := newPolicyTestHandler()
+	// 教师也不能修改他人材料的评分策略
+	for _, userID := range []string{"student", "teacher"} {
+		_, err := h.SetGradingPolicy(context.Background(), &pb.SetGradingPolicyRequest{
+			UserId: userID,
+			Policy: &pb.GradingPolicy{ScopeType: models.PolicyScopeMaterial, ScopeId: "material-1", PassThreshold: 0.1},
+		})
+		if status.Code(err) != codes.PermissionDenied {
+			t.Fatalf("user %s: expected PermissionDenied, got %v", userID, err)
+		}
+	}
+}
+
+func TestSetGradingPolicyRejectsCoursePolicyFromStudent(t *testing.T) {
+	h := newPolicyTestHandler()
+	_, err := h.SetGradingPolicy(context.Background(), &pb.SetGradingPolicyRequest{
+		UserId: "student",
+		Policy: &pb.GradingPolicy{ScopeType: models.PolicyScopeCourse, ScopeId: "course-1", PassThreshold: 0.1},
+	})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("expected PermissionDenied, got %v", err)
+	}
+}
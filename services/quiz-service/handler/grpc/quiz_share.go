@@ -0,0 +1,239 @@
+package grpc
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	pb "github.com/RigelNana/arkstudy/proto/quiz"
+	"github.com/RigelNana/arkstudy/quiz-service/models"
+	"github.com/RigelNana/arkstudy/quiz-service/service"
+)
+
+// 创建题目分享
+func (h *QuizGRPCHandler) CreateQuizShare(ctx context.Context, req *pb.CreateQuizShareRequest) (*pb.QuizShareResponse, error) {
+	if req.UserId == "" {
+		return &pb.QuizShareResponse{Success: false, Message: "用户ID不能为空"}, nil
+	}
+	share, err := h.shareService.Create(req.UserId, req.Title, req.MaterialId, req.QuestionIds, req.AllowAnonymous, time.Duration(req.TtlSeconds)*time.Second)
+	if err != nil {
+		return &pb.QuizShareResponse{Success: false, Message: err.Error()}, nil
+	}
+	h.logger.Infof("用户 %s 创建题目分享 %s", req.UserId, share.ShareID)
+	return &pb.QuizShareResponse{
+		Success: true,
+		Message: "分享已创建",
+		Share:   convertToPBShare(share, 0),
+	}, nil
+}
+
+// 获取分享与题目，公开访问时不返回答案、解析与出题依据
+func (h *QuizGRPCHandler) GetQuizShare(ctx context.Context, req *pb.GetQuizShareRequest) (*pb.GetQuizShareResponse, error) {
+	share, questions, err := h.shareService.Get(req.ShareId, req.UserId)
+	if err != nil {
+		return &pb.GetQuizShareResponse{Success: false, Message: err.Error()}, nil
+	}
+
+	pbQuestions := make([]*pb.Question, 0, len(questions))
+	for _, q := range questions {
+		pbQ, err := h.convertToPBQuestion(q)
+		if err != nil {
+			h.logger.Errorf("转换题目格式失败: %v", err)
+			continue
+		}
+		if req.UserId == "" {
+			pbQ = publicQuestion(pbQ)
+		}
+		pbQuestions = append(pbQuestions, pbQ)
+	}
+	return &pb.GetQuizShareResponse{
+		Success:   true,
+		Message:   "获取成功",
+		Share:     convertToPBShare(share, 0),
+		Questions: pbQuestions,
+	}, nil
+}
+
+// 获取用户创建的分享
+func (h *QuizGRPCHandler) ListQuizShares(ctx context.Context, req *pb.ListQuizSharesRequest) (*pb.ListQuizSharesResponse, error) {
+	page, pageSize := normalizePage(req.Page, req.PageSize)
+	shares, counts, total, err := h.shareService.List(req.UserId, page, pageSize)
+	if err != nil {
+		h.logger.Errorf("获取分享列表失败: %v", err)
+		return &pb.ListQuizSharesResponse{Success: false, Message: "获取分享列表失败"}, nil
+	}
+
+	pbShares := make([]*pb.QuizShare, 0, len(shares))
+	for _, s := range shares {
+		pbShares = append(pbShares, convertToPBShare(s, counts[s.ShareID]))
+	}
+	return &pb.ListQuizSharesResponse{
+		Success: true,
+		Message: "获取成功",
+		Shares:  pbShares,
+		Total:   int32(total),
+	}, nil
+}
+
+// 撤销分享
+func (h *QuizGRPCHandler) RevokeQuizShare(ctx context.Context, req *pb.RevokeQuizShareRequest) (*pb.QuizShareResponse, error) {
+	share, err := h.shareService.Revoke(req.ShareId, req.UserId)
+	if err != nil {
+		return &pb.QuizShareResponse{Success: false, Message: err.Error()}, nil
+	}
+	return &pb.QuizShareResponse{
+		Success: true,
+		Message: "分享已撤销",
+		Share:   convertToPBShare(share, 0),
+	}, nil
+}
+
+// 提交分享作答
+func (h *QuizGRPCHandler) SubmitQuizShareAttempt(ctx context.Context, req *pb.SubmitQuizShareAttemptRequest) (*pb.SubmitQuizShareAttemptResponse, error) {
+	answers := make([]service.ShareAnswer, 0, len(req.Answers))
+	for _, a := range req.Answers {
+		answers = append(answers, service.ShareAnswer{
+			QuestionID:  a.QuestionId,
+			Answer:      a.Answer,
+			PartAnswers: a.PartAnswers,
+			TimeSpentMs: a.TimeSpentMs,
+		})
+	}
+	attempt, err := h.shareService.Submit(ctx, req.ShareId, req.UserId, req.Nickname, answers, convertFromPBTelemetry(req.Telemetry))
+	if err != nil {
+		return &pb.SubmitQuizShareAttemptResponse{Success: false, Message: err.Error()}, nil
+	}
+	// 埋点与异常标记只给创建者看，不返回给作答者
+	pbAttempt := convertToPBShareAttempt(attempt)
+	pbAttempt.Telemetry, pbAttempt.Flags = nil, nil
+	return &pb.SubmitQuizShareAttemptResponse{
+		Success: true,
+		Message: "作答已提交",
+		Attempt: pbAttempt,
+	}, nil
+}
+
+// 获取分享的作答记录
+func (h *QuizGRPCHandler) ListQuizShareAttempts(ctx context.Context, req *pb.ListQuizShareAttemptsRequest) (*pb.ListQuizShareAttemptsResponse, error) {
+	page, pageSize := normalizePage(req.Page, req.PageSize)
+	attempts, total, err := h.shareService.ListAttempts(req.ShareId, req.UserId, req.FlaggedOnly, page, pageSize)
+	if err != nil {
+		return &pb.ListQuizShareAttemptsResponse{Success: false, Message: err.Error()}, nil
+	}
+
+	pbAttempts := make([]*pb.QuizShareAttempt, 0, len(attempts))
+	for _, a := range attempts {
+		pbAttempts = append(pbAttempts, convertToPBShareAttempt(a))
+	}
+	return &pb.ListQuizShareAttemptsResponse{
+		Success:  true,
+		Message:  "获取成功",
+		Attempts: pbAttempts,
+		Total:    int32(total),
+	}, nil
+}
+
+func normalizePage(page, pageSize int32) (int, int) {
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 || pageSize > 100 {
+		pageSize = 20
+	}
+	return int(page), int(pageSize)
+}
+
+// 辅助函数：去掉题目中的答案、解析与出题依据，供公开作答
+func publicQuestion(q *pb.Question) *pb.Question {
+	q.CorrectAnswer = ""
+	q.Explanation = ""
+	q.AnswerAliases = nil
+	q.NumericTolerance = 0
+	q.Citations = nil
+	for _, p := range q.Parts {
+		p.CorrectAnswer = ""
+		p.AnswerAliases = nil
+		p.NumericTolerance = 0
+	}
+	return q
+}
+
+// 辅助函数：转换题目分享
+func convertToPBShare(s *models.QuizShare, attemptCount int) *pb.QuizShare {
+	return &pb.QuizShare{
+		ShareId:        s.ShareID,
+		OwnerId:        s.OwnerID,
+		Title:          s.Title,
+		MaterialId:     s.MaterialID,
+		QuestionIds:    s.GetQuestionIDs(),
+		AllowAnonymous: s.AllowAnonymous,
+		ExpiresAt:      timestamppb.New(s.ExpiresAt),
+		Revoked:        s.RevokedAt != nil,
+		CreatedAt:      timestamppb.New(s.CreatedAt),
+		AttemptCount:   int32(attemptCount),
+	}
+}
+
+// 辅助函数：转换分享作答记录
+func convertToPBShareAttempt(a *models.QuizShareAttempt) *pb.QuizShareAttempt {
+	var results []*pb.QuizShareAnswerResult
+	for _, r := range a.GetResults() {
+		results = append(results, &pb.QuizShareAnswerResult{
+			QuestionId:      r.QuestionID,
+			Answer:          r.Answer,
+			IsCorrect:       r.IsCorrect,
+			Score:           r.Score,
+			QuestionVersion: int32(r.QuestionVersion),
+			TimeSpentMs:     r.TimeSpentMs,
+			Pending:         r.Pending,
+		})
+	}
+	var flags []*pb.AttemptFlag
+	for _, f := range a.GetFlags() {
+		flags = append(flags, &pb.AttemptFlag{Type: f.Type, QuestionId: f.QuestionID, Detail: f.Detail})
+	}
+	return &pb.QuizShareAttempt{
+		AttemptId:      a.AttemptID,
+		ShareId:        a.ShareID,
+		UserId:         a.UserID,
+		Nickname:       a.Nickname,
+		Score:          a.Score,
+		CorrectCount:   int32(a.CorrectCount),
+		TotalQuestions: int32(a.TotalQuestions),
+		Results:        results,
+		SubmittedAt:    timestamppb.New(a.SubmittedAt),
+		Telemetry:      convertToPBTelemetry(a.GetTelemetry()),
+		Flags:          flags,
+	}
+}
+
+// 辅助函数：转换作答埋点
+func convertToPBTelemetry(t *models.AttemptTelemetry) *pb.AttemptTelemetry {
+	if t == nil {
+		return nil
+	}
+	pbT := &pb.AttemptTelemetry{DurationMs: t.DurationMs}
+	for _, e := range t.FocusEvents {
+		pbT.FocusEvents = append(pbT.FocusEvents, &pb.FocusEvent{QuestionId: e.QuestionID, OffsetMs: e.OffsetMs, AwayMs: e.AwayMs})
+	}
+	for _, e := range t.PasteEvents {
+		pbT.PasteEvents = append(pbT.PasteEvents, &pb.PasteEvent{QuestionId: e.QuestionID, OffsetMs: e.OffsetMs, Length: int32(e.Length)})
+	}
+	return pbT
+}
+
+// 辅助函数：解析前端上报的作答埋点
+func convertFromPBTelemetry(t *pb.AttemptTelemetry) *models.AttemptTelemetry {
+	if t == nil {
+		return nil
+	}
+	telemetry := &models.AttemptTelemetry{DurationMs: t.DurationMs}
+	for _, e := range t.FocusEvents {
+		telemetry.FocusEvents = append(telemetry.FocusEvents, models.FocusEvent{QuestionID: e.QuestionId, OffsetMs: e.OffsetMs, AwayMs: e.AwayMs})
+	}
+	for _, e := range t.PasteEvents {
+		telemetry.PasteEvents = append(telemetry.PasteEvents, models.PasteEvent{QuestionID: e.QuestionId, OffsetMs: e.OffsetMs, Length: int(e.Length)})
+	}
+	return telemetry
+}
@@ -1,17 +1,24 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"net"
 	"os"
 	"os/signal"
 	"syscall"
 
+	grpcDbx "github.com/RigelNana/arkstudy/pkg/dbx/grpc"
+	"github.com/RigelNana/arkstudy/pkg/discovery"
+	"github.com/RigelNana/arkstudy/pkg/interceptor"
 	"github.com/RigelNana/arkstudy/pkg/metrics"
 	grpcMetrics "github.com/RigelNana/arkstudy/pkg/metrics/grpc"
+	"github.com/RigelNana/arkstudy/pkg/registry"
 	pb "github.com/RigelNana/arkstudy/proto/quiz"
 	"github.com/RigelNana/arkstudy/quiz-service/config"
+	"github.com/RigelNana/arkstudy/quiz-service/database"
 	grpcHandler "github.com/RigelNana/arkstudy/quiz-service/handler/grpc"
+	"github.com/RigelNana/arkstudy/quiz-service/models"
 	"github.com/RigelNana/arkstudy/quiz-service/repository"
 	"github.com/RigelNana/arkstudy/quiz-service/service"
 	"github.com/sirupsen/logrus"
@@ -25,9 +32,8 @@ func main() {
 	logger.SetFormatter(&logrus.JSONFormatter{})
 	logger.SetLevel(logrus.InfoLevel)
 
-	// 启动 Prometheus metrics 服务器
-	metrics.StartMetricsServer("2112")
-	logger.Info("Prometheus metrics server started on :2112")
+	// 启动内部 HTTP 监听：/metrics 与 /healthz、/readyz，地址由 METRICS_ADDR 配置
+	logger.Infof("Prometheus metrics server started on %s", metrics.StartMetricsServer())
 
 	// 加载配置
 	cfg, err := config.LoadConfig()
@@ -36,16 +42,33 @@ func main() {
 	}
 
 	logger.Infof("Quiz服务启动，配置: %+v", cfg)
+	if err := registry.Validate(registry.Quiz, registry.LLM); err != nil {
+		logger.Fatalf("服务地址配置错误: %v", err)
+	}
 
 	// 初始化数据库
-	quizRepo, err := repository.NewQuizRepository(cfg.Database.DSN())
+	db, err := database.InitDB(&cfg.Database)
 	if err != nil {
 		logger.Fatalf("初始化数据库失败: %v", err)
 	}
+	if err := database.Migrate(db); err != nil {
+		logger.Fatalf("数据库迁移失败: %v", err)
+	}
 	logger.Info("数据库连接成功")
+	if sqlDB, err := db.DB(); err == nil {
+		metrics.AddReadinessCheck("database", sqlDB.PingContext)
+	}
+
+	quizRepo := repository.NewQuizRepository(db)
 
 	// 初始化服务
-	quizService := service.NewQuizService(cfg.OpenAI.APIKey, cfg.OpenAI.BaseURL, cfg.LLMService.Address, logger)
+	quizService := service.NewQuizService(cfg.OpenAI.APIKey, cfg.OpenAI.BaseURL, cfg.LLMService.Address, cfg.ASRService.Address, cfg.MaterialService.Address, service.RetrievalOptions{
+		TopK:                cfg.LLMService.RetrievalTopK,
+		SimilarityThreshold: cfg.LLMService.SimilarityThreshold,
+	}, service.DifficultyOptions{
+		Estimator: cfg.Difficulty.Estimator,
+		Override:  cfg.Difficulty.Override,
+	}, logger)
 
 	// 启动gRPC服务器
 	lis, err := net.Listen("tcp", fmt.Sprintf(":%s", cfg.GRPC.Port))
@@ -53,12 +76,62 @@ func main() {
 		logger.Fatalf("gRPC监听失败: %v", err)
 	}
 
+	// panic 恢复、错误码规范化与慢请求日志（GRPC_SLOW_THRESHOLD）
+	interceptorOpts := interceptor.DefaultOptions("quiz-service")
 	grpcServer := grpc.NewServer(
-		grpc.UnaryInterceptor(grpcMetrics.UnaryServerInterceptor("quiz-service")),
-		grpc.StreamInterceptor(grpcMetrics.StreamServerInterceptor("quiz-service")),
+		grpc.ChainUnaryInterceptor(grpcMetrics.UnaryServerInterceptor("quiz-service"), interceptor.UnaryServerInterceptor(interceptorOpts), grpcDbx.UnaryServerInterceptor()),
+		grpc.ChainStreamInterceptor(grpcMetrics.StreamServerInterceptor("quiz-service"), interceptor.StreamServerInterceptor(interceptorOpts)),
 	)
-	quizGRPCHandler := grpcHandler.NewQuizGRPCHandler(quizService, quizRepo, logger)
+	// 启动后台任务：难度校准与知识点统计重算
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	calibrator := service.NewDifficultyCalibrator(quizRepo, logger,
+		cfg.Analytics.CalibrationInterval, cfg.Analytics.MinAttempts,
+		cfg.Analytics.EasyThreshold, cfg.Analytics.HardThreshold)
+	calibrator.Start(workerCtx)
+
+	statsWorker := service.NewStatsWorker(quizRepo, logger, cfg.Worker.Interval, cfg.Worker.MaxBackoff, cfg.Worker.BatchSize, cfg.Worker.MaxAttempts)
+	statsWorker.Start(workerCtx)
+
+	gradingService := service.NewGradingService(quizRepo, models.GradingPolicy{
+		PassThreshold:     cfg.Grading.PassThreshold,
+		PartialCreditMode: cfg.Grading.PartialCreditMode,
+		RetryPenalty:      cfg.Grading.RetryPenalty,
+		MaxRetryPenalty:   cfg.Grading.MaxRetryPenalty,
+	}, logger)
+
+	shareService := service.NewShareService(quizRepo, quizService, gradingService, service.ShareOptions{
+		DefaultTTL:   cfg.Shares.DefaultTTL,
+		MaxTTL:       cfg.Shares.MaxTTL,
+		MaxQuestions: cfg.Shares.MaxQuestions,
+		Anomaly: service.AnomalyThresholds{
+			MaxFocusLosses: cfg.AntiCheat.MaxFocusLosses,
+			LongFocusLoss:  cfg.AntiCheat.LongFocusLoss,
+			MinPasteLength: cfg.AntiCheat.MinPasteLength,
+			MinAnswerTime:  cfg.AntiCheat.MinAnswerTime,
+		},
+	}, logger)
+
+	// 评分策略、题库分析与题目审核的权限校验：材料归属与教师/管理员角色
+	access, err := service.NewAccessChecker(cfg.MaterialService.Address, cfg.UserService.Address, logger)
+	if err != nil {
+		logger.Fatalf("初始化权限校验失败: %v", err)
+	}
+	reviewService := service.NewReviewService(quizRepo, access, logger)
+	versionService := service.NewVersionService(quizRepo, logger)
+	attemptService := service.NewAttemptService(quizRepo, logger)
+	plagiarism := service.NewPlagiarismChecker(quizRepo, quizService, service.PlagiarismOptions{
+		Enabled:           cfg.Plagiarism.Enabled,
+		MaterialThreshold: cfg.Plagiarism.MaterialThreshold,
+		PeerThreshold:     cfg.Plagiarism.PeerThreshold,
+		MinAnswerLength:   cfg.Plagiarism.MinAnswerLength,
+		MaxPeers:          cfg.Plagiarism.MaxPeers,
+	}, logger)
+
+	manualGrading := service.NewManualGradingService(quizRepo, gradingService, logger)
+	quizGRPCHandler := grpcHandler.NewQuizGRPCHandler(quizService, quizRepo, calibrator, gradingService, shareService, reviewService, versionService, attemptService, plagiarism, manualGrading, access, cfg.Mistakes.MasteryStreak, logger)
 	pb.RegisterQuizServiceServer(grpcServer, quizGRPCHandler)
+	// 健康检查供客户端在多个副本间故障转移（见 pkg/discovery）
+	discovery.RegisterHealth(grpcServer)
 
 	// 启用反射，便于调试
 	reflection.Register(grpcServer)
@@ -66,6 +139,7 @@ func main() {
 	logger.Infof("gRPC服务器启动在端口 %s", cfg.GRPC.Port)
 
 	// 在goroutine中启动gRPC服务器
+	metrics.SetReady(true)
 	go func() {
 		if err := grpcServer.Serve(lis); err != nil {
 			logger.Fatalf("gRPC服务器启动失败: %v", err)
@@ -78,5 +152,8 @@ func main() {
 	<-c
 
 	logger.Info("Quiz服务正在关闭...")
+	// 先标记为未就绪，探针摘除流量后再停止服务
+	metrics.SetReady(false)
+	stopWorkers()
 	grpcServer.GracefulStop()
 }
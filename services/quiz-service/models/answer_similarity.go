@@ -0,0 +1,49 @@
+package models
+
+import "encoding/json"
+
+// 作答向量模型，用于论述题作答与其他学生作答的相似度比较。Model 为生成向量的模型，不同模型的向量不可比较
+type AnswerEmbedding struct {
+	BaseModel
+	AnswerID   string `gorm:"uniqueIndex;size:255" json:"answer_id"`
+	QuestionID string `gorm:"size:255;index" json:"question_id"`
+	UserID     string `gorm:"size:255" json:"user_id"`
+	Model      string `gorm:"size:128" json:"model"`
+	Vector     string `gorm:"type:text" json:"vector"` // JSON格式存储向量
+}
+
+// 论述题查重结果：与出题依据的材料片段、其他学生作答的最高相似度（余弦相似度）
+type SimilarityCheck struct {
+	MaterialSimilarity float32  `json:"material_similarity"`
+	MaterialChunkID    string   `json:"material_chunk_id,omitempty"` // 最相近的材料片段
+	PeerSimilarity     float32  `json:"peer_similarity"`
+	PeerAnswerID       string   `json:"peer_answer_id,omitempty"` // 最相近的其他学生作答，不返回给作答者
+	PeersCompared      int      `json:"peers_compared"`
+	Flagged            bool     `json:"flagged"`
+	Reasons            []string `json:"reasons,omitempty"`
+}
+
+// 解析向量
+func (e *AnswerEmbedding) GetVector() []float32 {
+	var vector []float32
+	if e.Vector != "" {
+		json.Unmarshal([]byte(e.Vector), &vector)
+	}
+	return vector
+}
+
+// 解析查重结果，未查重时返回 nil
+func (a *UserAnswer) GetSimilarity() *SimilarityCheck {
+	if a.Similarity == "" {
+		return nil
+	}
+	var check SimilarityCheck
+	if err := json.Unmarshal([]byte(a.Similarity), &check); err != nil {
+		return nil
+	}
+	return &check
+}
+
+func (AnswerEmbedding) TableName() string {
+	return "answer_embeddings"
+}
@@ -0,0 +1,46 @@
+package models
+
+// 评分策略作用范围
+const (
+	PolicyScopeGlobal   = "global"   // 全局默认
+	PolicyScopeMaterial = "material" // 按材料（题集）
+	PolicyScopeCourse   = "course"   // 按课程，作用于课程内的题目
+)
+
+// 多空题部分得分方式
+const (
+	PartialCreditWeighted     = "weighted"       // 按分项权重计分
+	PartialCreditEqual        = "equal"          // 各分项等权计分
+	PartialCreditAllOrNothing = "all_or_nothing" // 全部答对才得分
+)
+
+// 评分策略模型
+type GradingPolicy struct {
+	BaseModel
+	ScopeType         string  `gorm:"size:32;uniqueIndex:idx_grading_scope" json:"scope_type"`
+	ScopeID           string  `gorm:"size:255;uniqueIndex:idx_grading_scope" json:"scope_id"`
+	PassThreshold     float32 `json:"pass_threshold"`                     // 及格线（0~1）
+	PartialCreditMode string  `gorm:"size:32" json:"partial_credit_mode"` // 多空题部分得分方式
+	RetryPenalty      float32 `json:"retry_penalty"`                      // 每次重复作答扣减的得分比例
+	MaxRetryPenalty   float32 `json:"max_retry_penalty"`                  // 重复作答累计扣减上限
+	UpdatedBy         string  `gorm:"size:255" json:"updated_by"`
+}
+
+// 按重复作答次数扣减得分
+func (p *GradingPolicy) ApplyRetryPenalty(score float32, previousAttempts int) float32 {
+	if p.RetryPenalty <= 0 || previousAttempts <= 0 {
+		return score
+	}
+	penalty := p.RetryPenalty * float32(previousAttempts)
+	if p.MaxRetryPenalty > 0 && penalty > p.MaxRetryPenalty {
+		penalty = p.MaxRetryPenalty
+	}
+	if penalty > 1 {
+		penalty = 1
+	}
+	return score * (1 - penalty)
+}
+
+func (GradingPolicy) TableName() string {
+	return "grading_policies"
+}
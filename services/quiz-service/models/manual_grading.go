@@ -0,0 +1,24 @@
+package models
+
+// 主观题人工评分状态
+const (
+	GradingStatusPending = "pending_review" // 已由 AI 给出建议得分，等待教师复核
+	GradingStatusGraded  = "graded"         // 教师已确认或修改得分
+)
+
+// 是否需要教师复核：简答题与论述题由 AI 评分，结果需人工确认；多空题按分项答案自动评分
+func (q *Question) RequiresManualGrading() bool {
+	return (q.Type == ShortAnswer || q.Type == Essay) && q.Parts == ""
+}
+
+// 教师能否评阅该题的作答：个人题目由创建者评阅；课程题目只由审核人评阅，
+// 且审核人不能是创建者，否则创建者指定自己为审核人即可绕过审核为自己的题目评分
+func (q *Question) GradableBy(userID string) bool {
+	if userID == "" {
+		return false
+	}
+	if q.RequiresReview() {
+		return userID == q.ReviewerID && userID != q.CreatorID
+	}
+	return userID == q.CreatorID
+}
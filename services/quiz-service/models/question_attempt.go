@@ -0,0 +1,40 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// 题目作答会话：开始作答时为选择题生成一次选项的随机排列并保存，提交时按排列后的答案键评分，
+// 避免生成的选择题正确答案总是集中在 A。每个会话只能提交一次
+type QuestionAttempt struct {
+	BaseModel
+	AttemptID       string     `gorm:"uniqueIndex;size:255" json:"attempt_id"`
+	QuestionID      string     `gorm:"size:255;index" json:"question_id"`
+	UserID          string     `gorm:"size:255;index" json:"user_id"`
+	QuestionVersion int        `json:"question_version"`              // 开始作答时的题目版本，题目被编辑后会话失效
+	OptionOrder     string     `gorm:"type:text" json:"option_order"` // JSON格式存储排列，第 i 个展示的选项为原第 OptionOrder[i] 个选项，未打乱时为空
+	SubmittedAt     *time.Time `json:"submitted_at,omitempty"`
+}
+
+// 解析选项排列
+func (a *QuestionAttempt) GetOptionOrder() []int {
+	return parseOptionOrder(a.OptionOrder)
+}
+
+// 解析作答时的选项排列
+func (a *UserAnswer) GetOptionOrder() []int {
+	return parseOptionOrder(a.OptionOrder)
+}
+
+func parseOptionOrder(s string) []int {
+	var order []int
+	if s != "" {
+		json.Unmarshal([]byte(s), &order)
+	}
+	return order
+}
+
+func (QuestionAttempt) TableName() string {
+	return "question_attempts"
+}
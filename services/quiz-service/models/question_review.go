@@ -0,0 +1,48 @@
+package models
+
+// 题目审核状态：draft → pending_review → approved / rejected，被驳回的题目修改后可重新提交审核
+const (
+	ReviewStatusDraft         = "draft"          // 课程内新生成，尚未提交审核
+	ReviewStatusPendingReview = "pending_review" // 已指定审核人，等待审核
+	ReviewStatusApproved      = "approved"       // 审核通过，对课程学生可见
+	ReviewStatusRejected      = "rejected"       // 审核驳回，ReviewComment 为驳回原因
+)
+
+// 各审核状态可转入的状态，approved 为终态；课程题目被编辑后回到 draft，需重新审核
+var reviewTransitions = map[string][]string{
+	ReviewStatusDraft:         {ReviewStatusPendingReview},
+	ReviewStatusPendingReview: {ReviewStatusPendingReview, ReviewStatusApproved, ReviewStatusRejected}, // 待审核时可改派审核人
+	ReviewStatusRejected:      {ReviewStatusPendingReview},
+}
+
+// 是否为有效的审核状态
+func IsReviewStatus(status string) bool {
+	switch status {
+	case ReviewStatusDraft, ReviewStatusPendingReview, ReviewStatusApproved, ReviewStatusRejected:
+		return true
+	}
+	return false
+}
+
+// 题目能否从当前审核状态转为 to，不需要审核的题目不能转换
+func (q *Question) CanTransitionTo(to string) bool {
+	for _, s := range reviewTransitions[q.ReviewStatus] {
+		if s == to {
+			return true
+		}
+	}
+	return false
+}
+
+// 题目是否需要经审核才对学生可见
+func (q *Question) RequiresReview() bool {
+	return q.CourseID != ""
+}
+
+// 用户能否查看与作答题目：课程题目通过审核前只对创建者与审核人可见
+func (q *Question) VisibleTo(userID string) bool {
+	if !q.RequiresReview() || q.ReviewStatus == ReviewStatusApproved {
+		return true
+	}
+	return userID != "" && (userID == q.CreatorID || userID == q.ReviewerID)
+}
@@ -0,0 +1,62 @@
+package models
+
+import "encoding/json"
+
+// 题目版本模型：题目每次编辑后保存一份不可变的快照，答题记录按作答时的版本号引用，
+// 便于申诉时核对学生看到的题面与当时的标准答案。难度校准只调整统计难度，不产生新版本
+type QuestionVersion struct {
+	BaseModel
+	QuestionID       string          `gorm:"size:255;uniqueIndex:idx_question_version" json:"question_id"`
+	Version          int             `gorm:"uniqueIndex:idx_question_version" json:"version"`
+	Type             QuestionType    `gorm:"type:int" json:"type"`
+	Content          string          `gorm:"type:text" json:"content"`
+	Options          string          `gorm:"type:text" json:"options"`
+	CorrectAnswer    string          `gorm:"type:text" json:"correct_answer"`
+	AnswerAliases    string          `gorm:"type:text" json:"answer_aliases"`
+	NumericTolerance float64         `json:"numeric_tolerance"`
+	Parts            string          `gorm:"type:text" json:"parts"`
+	Explanation      string          `gorm:"type:text" json:"explanation"`
+	Difficulty       DifficultyLevel `gorm:"type:int" json:"difficulty"`
+	KnowledgePoints  string          `gorm:"type:text" json:"knowledge_points"`
+	EditorID         string          `gorm:"size:255" json:"editor_id"`       // 生成题目的版本为创建者
+	ChangedFields    string          `gorm:"type:text" json:"changed_fields"` // JSON格式存储相对上一版本修改的字段，首个版本为空
+	ChangeNote       string          `gorm:"type:text" json:"change_note"`
+}
+
+// 生成题目当前内容的快照
+func (q *Question) Snapshot(editorID string, changedFields []string, note string) *QuestionVersion {
+	v := &QuestionVersion{
+		QuestionID:       q.QuestionID,
+		Version:          q.Version,
+		Type:             q.Type,
+		Content:          q.Content,
+		Options:          q.Options,
+		CorrectAnswer:    q.CorrectAnswer,
+		AnswerAliases:    q.AnswerAliases,
+		NumericTolerance: q.NumericTolerance,
+		Parts:            q.Parts,
+		Explanation:      q.Explanation,
+		Difficulty:       q.Difficulty,
+		KnowledgePoints:  q.KnowledgePoints,
+		EditorID:         editorID,
+		ChangeNote:       note,
+	}
+	if len(changedFields) > 0 {
+		data, _ := json.Marshal(changedFields)
+		v.ChangedFields = string(data)
+	}
+	return v
+}
+
+// 解析修改的字段
+func (v *QuestionVersion) GetChangedFields() []string {
+	var fields []string
+	if v.ChangedFields != "" {
+		json.Unmarshal([]byte(v.ChangedFields), &fields)
+	}
+	return fields
+}
+
+func (QuestionVersion) TableName() string {
+	return "question_versions"
+}
@@ -1,9 +1,12 @@
 package models
 
 import (
+	"encoding/json"
 	"time"
 
 	"gorm.io/gorm"
+
+	"github.com/RigelNana/arkstudy/pkg/dbx"
 )
 
 // 题目类型枚举
@@ -62,33 +65,172 @@ type BaseModel struct {
 	CreatedAt time.Time      `json:"created_at"`
 	UpdatedAt time.Time      `json:"updated_at"`
 	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
+	dbx.Audit
+	// 乐观锁版本号，与题目的内容版本 Question.Version 无关
+	LockVersion dbx.Version `gorm:"not null;default:1" json:"lock_version"`
 }
 
 // 题目模型
 type Question struct {
 	BaseModel
-	QuestionID      string          `gorm:"uniqueIndex;size:255" json:"question_id"`
-	Type            QuestionType    `gorm:"type:int" json:"type"`
-	Content         string          `gorm:"type:text" json:"content"`
-	Options         string          `gorm:"type:text" json:"options"` // JSON格式存储选项
-	CorrectAnswer   string          `gorm:"type:text" json:"correct_answer"`
-	Explanation     string          `gorm:"type:text" json:"explanation"`
-	Difficulty      DifficultyLevel `gorm:"type:int" json:"difficulty"`
-	KnowledgePoints string          `gorm:"type:text" json:"knowledge_points"` // JSON格式存储知识点
-	MaterialID      string          `gorm:"size:255;index" json:"material_id"`
-	CreatorID       string          `gorm:"size:255;index" json:"creator_id"`
+	QuestionID       string          `gorm:"uniqueIndex;size:255" json:"question_id"`
+	Type             QuestionType    `gorm:"type:int" json:"type"`
+	Content          string          `gorm:"type:text" json:"content"`
+	Options          string          `gorm:"type:text" json:"options"` // JSON格式存储选项
+	CorrectAnswer    string          `gorm:"type:text" json:"correct_answer"`
+	AnswerAliases    string          `gorm:"type:text" json:"answer_aliases"` // JSON格式存储同义答案（填空题用）
+	NumericTolerance float64         `json:"numeric_tolerance"`               // 数值答案允许的误差（填空题用）
+	Parts            string          `gorm:"type:text" json:"parts"`          // JSON格式存储多空/小题的分项答案
+	Explanation      string          `gorm:"type:text" json:"explanation"`
+	Difficulty       DifficultyLevel `gorm:"type:int" json:"difficulty"`
+	KnowledgePoints  string          `gorm:"type:text" json:"knowledge_points"` // JSON格式存储知识点
+	Citations        string          `gorm:"type:text" json:"citations"`        // JSON格式存储出题依据的材料片段
+	MaterialID       string          `gorm:"size:255;index" json:"material_id"`
+	CreatorID        string          `gorm:"size:255;index" json:"creator_id"`
+	Version          int             `gorm:"default:1" json:"version"` // 当前版本号，每次编辑加 1，历史版本见 QuestionVersion
+
+	// 出题请求指定的难度与按题目文本估计的难度，DifficultySource 为估计方式（heuristic/llm），未估计时为空
+	RequestedDifficulty DifficultyLevel `gorm:"type:int" json:"requested_difficulty"`
+	EstimatedDifficulty DifficultyLevel `gorm:"type:int" json:"estimated_difficulty"`
+	DifficultySource    string          `gorm:"size:32" json:"difficulty_source"`
+
+	// 课程内生成的题目需经审核才对学生可见，个人出题时 CourseID 与 ReviewStatus 为空
+	CourseID      string     `gorm:"size:255;index" json:"course_id"`
+	ReviewStatus  string     `gorm:"size:32;index" json:"review_status"`
+	ReviewerID    string     `gorm:"size:255;index" json:"reviewer_id"`
+	ReviewComment string     `gorm:"type:text" json:"review_comment"`
+	ReviewedAt    *time.Time `json:"reviewed_at,omitempty"`
 }
 
 // 用户答题记录模型
 type UserAnswer struct {
 	BaseModel
-	AnswerID   string    `gorm:"uniqueIndex;size:255" json:"answer_id"`
-	QuestionID string    `gorm:"size:255;index" json:"question_id"`
-	UserID     string    `gorm:"size:255;index" json:"user_id"`
-	Answer     string    `gorm:"type:text" json:"answer"`
-	IsCorrect  bool      `json:"is_correct"`
-	Score      float32   `json:"score"`
-	AnsweredAt time.Time `json:"answered_at"`
+	AnswerID    string    `gorm:"uniqueIndex;size:255" json:"answer_id"`
+	QuestionID  string    `gorm:"size:255;index" json:"question_id"`
+	UserID      string    `gorm:"size:255;index" json:"user_id"`
+	Answer      string    `gorm:"type:text" json:"answer"`
+	IsCorrect   bool      `json:"is_correct"`
+	Score       float32   `json:"score"`
+	PartResults string    `gorm:"type:text" json:"part_results"` // JSON格式存储分项评分结果
+	TimeSpentMs int64     `json:"time_spent_ms"`                 // 作答耗时（毫秒），由客户端上报
+	AnsweredAt  time.Time `json:"answered_at"`
+	// 作答时的题目版本，早于题目版本功能的记录为 0
+	QuestionVersion int `json:"question_version"`
+	// 通过作答会话提交时的会话ID与选项排列；Answer 保存按原选项顺序换算后的答案
+	AttemptID   string `gorm:"size:255;index" json:"attempt_id,omitempty"`
+	OptionOrder string `gorm:"type:text" json:"option_order,omitempty"` // JSON格式存储排列，见 QuestionAttempt
+	// 论述题查重结果，JSON格式存储 SimilarityCheck，未查重时为空
+	Similarity        string `gorm:"type:text" json:"similarity,omitempty"`
+	SimilarityFlagged bool   `gorm:"index" json:"similarity_flagged"`
+	// 主观题人工复核：作答时记录 AI 建议得分与评语，教师确认或改分后 GradingStatus 为 graded，客观题为空
+	GradingStatus  string     `gorm:"size:32;index" json:"grading_status,omitempty"`
+	SuggestedScore float32    `json:"suggested_score"` // AI 给出的原始得分，未扣除重复作答惩罚
+	AIFeedback     string     `gorm:"type:text" json:"ai_feedback,omitempty"`
+	GradedBy       string     `gorm:"size:255" json:"graded_by,omitempty"`
+	GradedAt       *time.Time `json:"graded_at,omitempty"`
+	GradingComment string     `gorm:"type:text" json:"grading_comment,omitempty"`
+}
+
+// 题目作答统计模型，用于难度校准
+type QuestionStats struct {
+	BaseModel
+	QuestionID         string          `gorm:"uniqueIndex;size:255" json:"question_id"`
+	MaterialID         string          `gorm:"size:255;index" json:"material_id"`
+	AttemptCount       int             `json:"attempt_count"`
+	CorrectCount       int             `json:"correct_count"`
+	TotalScore         float64         `json:"total_score"`
+	TimedAttempts      int             `json:"timed_attempts"` // 上报了耗时的作答次数
+	TotalTimeMs        int64           `json:"total_time_ms"`
+	OriginalDifficulty DifficultyLevel `gorm:"type:int" json:"original_difficulty"` // 出题时标注的难度
+	LastCalibratedAt   *time.Time      `json:"last_calibrated_at,omitempty"`
+}
+
+// 答题事件状态
+const (
+	AnswerEventPending   = "pending"
+	AnswerEventProcessed = "processed"
+	AnswerEventFailed    = "failed"
+)
+
+// 答题事件模型，作为知识点统计异步重算的任务队列
+type AnswerEvent struct {
+	BaseModel
+	AnswerID        string     `gorm:"uniqueIndex;size:255" json:"answer_id"`
+	UserID          string     `gorm:"size:255" json:"user_id"`
+	QuestionID      string     `gorm:"size:255" json:"question_id"`
+	MaterialID      string     `gorm:"size:255" json:"material_id"`
+	KnowledgePoints string     `gorm:"type:text" json:"knowledge_points"` // JSON格式存储知识点
+	Status          string     `gorm:"size:32;index" json:"status"`
+	Attempts        int        `json:"attempts"`
+	LastError       string     `gorm:"type:text" json:"last_error"`
+	ProcessedAt     *time.Time `json:"processed_at,omitempty"`
+}
+
+// 错题本记录模型，跟踪用户答错的题目直至掌握
+type MistakeRecord struct {
+	BaseModel
+	UserID        string    `gorm:"size:255;uniqueIndex:idx_mistake_user_question" json:"user_id"`
+	QuestionID    string    `gorm:"size:255;uniqueIndex:idx_mistake_user_question" json:"question_id"`
+	MaterialID    string    `gorm:"size:255;index" json:"material_id"`
+	WrongCount    int       `json:"wrong_count"`
+	CorrectStreak int       `json:"correct_streak"` // 最近一次答错后连续答对的次数
+	Mastered      bool      `gorm:"index" json:"mastered"`
+	LastWrongAt   time.Time `json:"last_wrong_at"`
+	LastAttemptAt time.Time `json:"last_attempt_at"`
+}
+
+// 正确率
+func (s *QuestionStats) SuccessRate() float32 {
+	if s.AttemptCount == 0 {
+		return 0
+	}
+	return float32(s.CorrectCount) / float32(s.AttemptCount)
+}
+
+// 平均得分
+func (s *QuestionStats) AvgScore() float32 {
+	if s.AttemptCount == 0 {
+		return 0
+	}
+	return float32(s.TotalScore / float64(s.AttemptCount))
+}
+
+// 平均作答耗时（毫秒）
+func (s *QuestionStats) AvgTimeMs() int64 {
+	if s.TimedAttempts == 0 {
+		return 0
+	}
+	return s.TotalTimeMs / int64(s.TimedAttempts)
+}
+
+// 题目分项（多空填空题的每个空或小题）
+type QuestionPart struct {
+	Label            string   `json:"label,omitempty"`
+	CorrectAnswer    string   `json:"correct_answer"`
+	AnswerAliases    []string `json:"answer_aliases,omitempty"`
+	NumericTolerance float64  `json:"numeric_tolerance,omitempty"`
+	Weight           float32  `json:"weight,omitempty"` // 分项权重，未设置时按1计算
+}
+
+// 题目引用的材料片段
+type QuestionCitation struct {
+	ChunkID       string `json:"chunk_id,omitempty"`
+	MaterialID    string `json:"material_id"`
+	MaterialTitle string `json:"material_title,omitempty"` // 多材料出题时的来源材料标题
+	Page          int    `json:"page,omitempty"`           // 文档页码，未知时为0
+	Timecode      string `json:"timecode,omitempty"`       // 音视频时间码
+	Snippet       string `json:"snippet,omitempty"`
+}
+
+// 分项评分结果
+type PartResult struct {
+	Index         int     `json:"index"`
+	Answer        string  `json:"answer"`
+	IsCorrect     bool    `json:"is_correct"`
+	Score         float32 `json:"score"`
+	Weight        float32 `json:"weight"`
+	CorrectAnswer string  `json:"correct_answer"`
+	MatchType     string  `json:"match_type"`
 }
 
 // 知识点统计模型
@@ -103,6 +245,42 @@ type KnowledgePointStats struct {
 	AvgDifficulty  DifficultyLevel `gorm:"type:int" json:"avg_difficulty"`
 }
 
+// 解析同义答案列表
+func (q *Question) GetAnswerAliases() []string {
+	var aliases []string
+	if q.AnswerAliases != "" {
+		json.Unmarshal([]byte(q.AnswerAliases), &aliases)
+	}
+	return aliases
+}
+
+// 解析分项答案列表
+func (q *Question) GetParts() []QuestionPart {
+	var parts []QuestionPart
+	if q.Parts != "" {
+		json.Unmarshal([]byte(q.Parts), &parts)
+	}
+	return parts
+}
+
+// 解析出题依据的材料片段
+func (q *Question) GetCitations() []QuestionCitation {
+	var citations []QuestionCitation
+	if q.Citations != "" {
+		json.Unmarshal([]byte(q.Citations), &citations)
+	}
+	return citations
+}
+
+// 解析分项评分结果
+func (a *UserAnswer) GetPartResults() []PartResult {
+	var results []PartResult
+	if a.PartResults != "" {
+		json.Unmarshal([]byte(a.PartResults), &results)
+	}
+	return results
+}
+
 // 表名设置
 func (Question) TableName() string {
 	return "questions"
@@ -115,3 +293,15 @@ func (UserAnswer) TableName() string {
 func (KnowledgePointStats) TableName() string {
 	return "knowledge_point_stats"
 }
+
+func (QuestionStats) TableName() string {
+	return "question_stats"
+}
+
+func (MistakeRecord) TableName() string {
+	return "mistake_records"
+}
+
+func (AnswerEvent) TableName() string {
+	return "answer_events"
+}
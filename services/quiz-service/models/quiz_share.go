@@ -0,0 +1,136 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// 题目公开分享模型，访问地址由 gateway 按 ShareID 与 ExpiresAt 签名生成
+type QuizShare struct {
+	BaseModel
+	ShareID        string     `gorm:"uniqueIndex;size:255" json:"share_id"`
+	OwnerID        string     `gorm:"size:255;index" json:"owner_id"`
+	Title          string     `gorm:"size:255" json:"title"`
+	MaterialID     string     `gorm:"size:255" json:"material_id"`
+	QuestionIDs    string     `gorm:"type:text" json:"question_ids"` // JSON格式存储分享的题目ID，按作答顺序
+	AllowAnonymous bool       `json:"allow_anonymous"`               // 是否允许未登录访客填写昵称作答
+	ExpiresAt      time.Time  `json:"expires_at"`
+	RevokedAt      *time.Time `json:"revoked_at,omitempty"`
+}
+
+// 分享作答记录模型，与 UserAnswer 分开存储，不计入作答者的答题历史、错题本与题目统计
+type QuizShareAttempt struct {
+	BaseModel
+	AttemptID      string    `gorm:"uniqueIndex;size:255" json:"attempt_id"`
+	ShareID        string    `gorm:"size:255;index" json:"share_id"`
+	UserID         string    `gorm:"size:255" json:"user_id"` // 匿名作答时为空
+	Nickname       string    `gorm:"size:64" json:"nickname"`
+	Score          float32   `json:"score"` // 各题平均得分（0~1）
+	CorrectCount   int       `json:"correct_count"`
+	TotalQuestions int       `json:"total_questions"`
+	Results        string    `gorm:"type:text" json:"results"` // JSON格式存储各题评分
+	SubmittedAt    time.Time `json:"submitted_at"`
+	// 前端上报的作答埋点与据此标记的异常，仅创建者可见
+	Telemetry string `gorm:"type:text" json:"telemetry"` // JSON格式存储 AttemptTelemetry
+	Flags     string `gorm:"type:text" json:"flags"`     // JSON格式存储 AttemptFlag 列表
+	Flagged   bool   `gorm:"index" json:"flagged"`
+}
+
+// 分享作答中一题的评分
+type ShareAnswerResult struct {
+	QuestionID      string  `json:"question_id"`
+	QuestionVersion int     `json:"question_version,omitempty"`
+	Answer          string  `json:"answer"`
+	IsCorrect       bool    `json:"is_correct"`
+	Score           float32 `json:"score"`
+	TimeSpentMs     int64   `json:"time_spent_ms,omitempty"` // 前端上报的本题作答耗时
+	Pending         bool    `json:"pending,omitempty"`       // 匿名作答的主观题不调用 LLM 评分，待分享者评阅
+}
+
+// 异常作答标记类型
+const (
+	AttemptFlagFocusLoss     = "focus_loss"      // 作答期间多次离开页面
+	AttemptFlagLongFocusLoss = "long_focus_loss" // 单次离开页面时间过长
+	AttemptFlagPaste         = "paste"           // 向答案粘贴了较长的内容
+	AttemptFlagFastAnswer    = "fast_answer"     // 用时过短却答对
+)
+
+// 作答过程的前端埋点，时间均为距开始作答的毫秒数
+type AttemptTelemetry struct {
+	DurationMs  int64        `json:"duration_ms,omitempty"` // 开始作答到提交的总耗时
+	FocusEvents []FocusEvent `json:"focus_events,omitempty"`
+	PasteEvents []PasteEvent `json:"paste_events,omitempty"`
+}
+
+// 一次离开作答页面（切换标签页、窗口失焦等）
+type FocusEvent struct {
+	QuestionID string `json:"question_id,omitempty"` // 离开时所在的题目
+	OffsetMs   int64  `json:"offset_ms"`
+	AwayMs     int64  `json:"away_ms"`
+}
+
+// 一次向答案粘贴内容
+type PasteEvent struct {
+	QuestionID string `json:"question_id,omitempty"`
+	OffsetMs   int64  `json:"offset_ms"`
+	Length     int    `json:"length"` // 粘贴的字符数
+}
+
+// 异常作答标记，QuestionID 为空时针对整份作答
+type AttemptFlag struct {
+	Type       string `json:"type"`
+	QuestionID string `json:"question_id,omitempty"`
+	Detail     string `json:"detail"`
+}
+
+// 分享是否可公开访问
+func (s *QuizShare) Active(now time.Time) bool {
+	return s.RevokedAt == nil && now.Before(s.ExpiresAt)
+}
+
+// 解析分享的题目ID
+func (s *QuizShare) GetQuestionIDs() []string {
+	var ids []string
+	if s.QuestionIDs != "" {
+		json.Unmarshal([]byte(s.QuestionIDs), &ids)
+	}
+	return ids
+}
+
+// 解析各题评分
+func (a *QuizShareAttempt) GetResults() []ShareAnswerResult {
+	var results []ShareAnswerResult
+	if a.Results != "" {
+		json.Unmarshal([]byte(a.Results), &results)
+	}
+	return results
+}
+
+// 解析作答埋点
+func (a *QuizShareAttempt) GetTelemetry() *AttemptTelemetry {
+	if a.Telemetry == "" {
+		return nil
+	}
+	var telemetry AttemptTelemetry
+	if err := json.Unmarshal([]byte(a.Telemetry), &telemetry); err != nil {
+		return nil
+	}
+	return &telemetry
+}
+
+// 解析异常标记
+func (a *QuizShareAttempt) GetFlags() []AttemptFlag {
+	var flags []AttemptFlag
+	if a.Flags != "" {
+		json.Unmarshal([]byte(a.Flags), &flags)
+	}
+	return flags
+}
+
+func (QuizShare) TableName() string {
+	return "quiz_shares"
+}
+
+func (QuizShareAttempt) TableName() string {
+	return "quiz_share_attempts"
+}
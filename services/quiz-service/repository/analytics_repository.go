@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/RigelNana/arkstudy/pkg/dbx"
+	"github.com/RigelNana/arkstudy/quiz-service/models"
+)
+
+// 记录一次作答，累加题目统计
+func (r *QuizRepository) RecordQuestionAttempt(question *models.Question, isCorrect bool, score float32, timeSpentMs int64) error {
+	correct := 0
+	if isCorrect {
+		correct = 1
+	}
+	timed := 0
+	if timeSpentMs > 0 {
+		timed = 1
+	} else {
+		timeSpentMs = 0
+	}
+
+	stats := &models.QuestionStats{
+		QuestionID:         question.QuestionID,
+		MaterialID:         question.MaterialID,
+		AttemptCount:       1,
+		CorrectCount:       correct,
+		TotalScore:         float64(score),
+		TimedAttempts:      timed,
+		TotalTimeMs:        timeSpentMs,
+		OriginalDifficulty: question.Difficulty,
+	}
+
+	return r.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "question_id"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"attempt_count":  gorm.Expr("question_stats.attempt_count + 1"),
+			"correct_count":  gorm.Expr("question_stats.correct_count + ?", correct),
+			"total_score":    gorm.Expr("question_stats.total_score + ?", score),
+			"timed_attempts": gorm.Expr("question_stats.timed_attempts + ?", timed),
+			"total_time_ms":  gorm.Expr("question_stats.total_time_ms + ?", timeSpentMs),
+			"updated_at":     time.Now(),
+		}),
+	}).Create(stats).Error
+}
+
+// 获取单题统计
+func (r *QuizRepository) GetQuestionStats(questionID string) (*models.QuestionStats, error) {
+	var stats models.QuestionStats
+	if err := r.db.Where("question_id = ?", questionID).First(&stats).Error; err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
+// 分页获取题目统计，配置了只读副本时从副本读取；creatorID 不为空时只返回该用户创建的题目
+func (r *QuizRepository) ListQuestionStats(materialID, creatorID string, minAttempts, page, pageSize int) ([]*models.QuestionStats, int64, error) {
+	var stats []*models.QuestionStats
+	var total int64
+
+	query := r.db.Scopes(dbx.ReadReplica).Model(&models.QuestionStats{}).Where("attempt_count >= ?", minAttempts)
+	if materialID != "" {
+		query = query.Where("material_id = ?", materialID)
+	}
+	if creatorID != "" {
+		query = query.Where("question_id IN (?)", r.db.Model(&models.Question{}).Select("question_id").Where("creator_id = ?", creatorID))
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	if err := query.Offset(offset).Limit(pageSize).Order("attempt_count DESC").Find(&stats).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return stats, total, nil
+}
+
+// 获取达到最少作答次数、需要校准的题目统计
+func (r *QuizRepository) ListCalibrationCandidates(minAttempts int) ([]*models.QuestionStats, error) {
+	var stats []*models.QuestionStats
+	err := r.db.Where("attempt_count >= ?", minAttempts).Find(&stats).Error
+	return stats, err
+}
+
+// 批量获取题目
+func (r *QuizRepository) GetQuestionsByIDs(questionIDs []string) ([]*models.Question, error) {
+	var questions []*models.Question
+	if len(questionIDs) == 0 {
+		return questions, nil
+	}
+	err := r.db.Where("question_id IN ?", questionIDs).Find(&questions).Error
+	return questions, err
+}
+
+// 更新题目难度并记录校准时间
+func (r *QuizRepository) UpdateQuestionDifficulty(questionID string, difficulty models.DifficultyLevel) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Question{}).Where("question_id = ?", questionID).Update("difficulty", difficulty).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.QuestionStats{}).Where("question_id = ?", questionID).Update("last_calibrated_at", time.Now()).Error
+	})
+}
@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/RigelNana/arkstudy/quiz-service/models"
+)
+
+// 写入答题事件
+func (r *QuizRepository) CreateAnswerEvent(event *models.AnswerEvent) error {
+	if event.Status == "" {
+		event.Status = models.AnswerEventPending
+	}
+	return r.db.Create(event).Error
+}
+
+// 领取一批待处理事件并逐条处理，多个实例并发运行时通过 SKIP LOCKED 避免重复处理；
+// 单条事件处理失败只回滚该事件的修改，失败次数达到 maxAttempts 后标记为 failed。返回成功与失败的事件数
+func (r *QuizRepository) ProcessPendingAnswerEvents(batchSize, maxAttempts int, handle func(txRepo *QuizRepository, event *models.AnswerEvent) error) (processed, failed int, err error) {
+	err = r.db.Transaction(func(tx *gorm.DB) error {
+		var events []*models.AnswerEvent
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ?", models.AnswerEventPending).
+			Order("id ASC").
+			Limit(batchSize).
+			Find(&events).Error
+		if err != nil {
+			return err
+		}
+
+		for _, event := range events {
+			handleErr := tx.Transaction(func(sp *gorm.DB) error {
+				return handle(&QuizRepository{db: sp}, event)
+			})
+
+			updates := map[string]interface{}{"attempts": event.Attempts + 1}
+			if handleErr == nil {
+				now := time.Now()
+				updates["status"] = models.AnswerEventProcessed
+				updates["processed_at"] = &now
+				updates["last_error"] = ""
+				processed++
+			} else {
+				updates["last_error"] = handleErr.Error()
+				failed++
+				if event.Attempts+1 >= maxAttempts {
+					updates["status"] = models.AnswerEventFailed
+				}
+			}
+
+			if err := tx.Model(event).Updates(updates).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return processed, failed, err
+}
@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/RigelNana/arkstudy/quiz-service/models"
+)
+
+// 创建作答会话
+func (r *QuizRepository) CreateQuestionAttempt(attempt *models.QuestionAttempt) error {
+	return r.db.Create(attempt).Error
+}
+
+// 根据会话ID获取作答会话
+func (r *QuizRepository) GetQuestionAttempt(attemptID string) (*models.QuestionAttempt, error) {
+	var attempt models.QuestionAttempt
+	if err := r.db.Where("attempt_id = ?", attemptID).First(&attempt).Error; err != nil {
+		return nil, err
+	}
+	return &attempt, nil
+}
+
+// 将未提交的作答会话标记为已提交，会话已被提交过时不更新，返回更新的行数
+func (r *QuizRepository) CompleteQuestionAttempt(attemptID string, submittedAt time.Time) (int64, error) {
+	result := r.db.Model(&models.QuestionAttempt{}).
+		Where("attempt_id = ? AND submitted_at IS NULL", attemptID).
+		Update("submitted_at", submittedAt)
+	return result.RowsAffected, result.Error
+}
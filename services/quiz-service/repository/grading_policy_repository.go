@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"gorm.io/gorm/clause"
+
+	"github.com/RigelNana/arkstudy/quiz-service/models"
+)
+
+// 获取指定范围的评分策略
+func (r *QuizRepository) GetGradingPolicy(scopeType, scopeID string) (*models.GradingPolicy, error) {
+	var policy models.GradingPolicy
+	if err := r.db.Where("scope_type = ? AND scope_id = ?", scopeType, scopeID).First(&policy).Error; err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// 创建或更新评分策略
+func (r *QuizRepository) UpsertGradingPolicy(policy *models.GradingPolicy) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "scope_type"}, {Name: "scope_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"pass_threshold", "partial_credit_mode", "retry_penalty", "max_retry_penalty", "updated_by", "updated_at"}),
+	}).Create(policy).Error
+}
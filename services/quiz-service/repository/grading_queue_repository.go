@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/RigelNana/arkstudy/quiz-service/models"
+)
+
+// 评分队列筛选条件
+type GradingQueueFilter struct {
+	MaterialID  string
+	CourseID    string
+	QuestionID  string
+	FlaggedOnly bool
+}
+
+// 分页获取教师待复核的主观题作答：本人创建的个人题目，或指定本人为审核人且不是本人创建的课程题目，与 Question.GradableBy 一致
+func (r *QuizRepository) ListGradingQueue(teacherID string, filter GradingQueueFilter, page, pageSize int) ([]*models.UserAnswer, int64, error) {
+	var answers []*models.UserAnswer
+	var total int64
+
+	query := r.db.Model(&models.UserAnswer{}).
+		Joins("JOIN questions ON questions.question_id = user_answers.question_id AND questions.deleted_at IS NULL").
+		Where("user_answers.grading_status = ?", models.GradingStatusPending).
+		Where("(questions.course_id = '' AND questions.creator_id = ?) OR (questions.course_id <> '' AND questions.reviewer_id = ? AND questions.creator_id <> ?)", teacherID, teacherID, teacherID)
+	if filter.MaterialID != "" {
+		query = query.Where("questions.material_id = ?", filter.MaterialID)
+	}
+	if filter.CourseID != "" {
+		query = query.Where("questions.course_id = ?", filter.CourseID)
+	}
+	if filter.QuestionID != "" {
+		query = query.Where("user_answers.question_id = ?", filter.QuestionID)
+	}
+	if filter.FlaggedOnly {
+		query = query.Where("user_answers.similarity_flagged = ?", true)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	offset := (page - 1) * pageSize
+	if err := query.Select("user_answers.*").Offset(offset).Limit(pageSize).Order("user_answers.answered_at ASC").Find(&answers).Error; err != nil {
+		return nil, 0, err
+	}
+	return answers, total, nil
+}
+
+// 按ID批量获取答题记录
+func (r *QuizRepository) GetUserAnswersByIDs(answerIDs []string) ([]*models.UserAnswer, error) {
+	var answers []*models.UserAnswer
+	if len(answerIDs) == 0 {
+		return answers, nil
+	}
+	err := r.db.Where("answer_id IN ?", answerIDs).Find(&answers).Error
+	return answers, err
+}
+
+// 统计用户在某次作答之前对该题的作答次数，用于改分时重新计算重复作答惩罚
+func (r *QuizRepository) CountUserAttemptsBefore(questionID, userID string, before time.Time) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.UserAnswer{}).
+		Where("question_id = ? AND user_id = ? AND answered_at < ?", questionID, userID, before).
+		Count(&count).Error
+	return count, err
+}
+
+// 保存人工评分，仅更新仍在待复核状态的记录，返回受影响的行数
+func (r *QuizRepository) ApplyManualGrade(answerID string, updates map[string]interface{}) (int64, error) {
+	result := r.db.Model(&models.UserAnswer{}).
+		Where("answer_id = ? AND grading_status = ?", answerID, models.GradingStatusPending).
+		Updates(updates)
+	return result.RowsAffected, result.Error
+}
+
+// 改分后按差值修正题目统计，作答次数不变
+func (r *QuizRepository) AdjustQuestionStats(questionID string, correctDelta int, scoreDelta float64) error {
+	return r.db.Model(&models.QuestionStats{}).
+		Where("question_id = ?", questionID).
+		Updates(map[string]interface{}{
+			"correct_count": gorm.Expr("correct_count + ?", correctDelta),
+			"total_score":   gorm.Expr("total_score + ?", scoreDelta),
+			"updated_at":    time.Now(),
+		}).Error
+}
+
+// 重新排入答题事件：事件已存在（每条作答一条）时重置为待处理，由后台任务重算知识点统计
+func (r *QuizRepository) RequeueAnswerEvent(event *models.AnswerEvent) error {
+	event.Status = models.AnswerEventPending
+	return r.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "answer_id"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"status":       models.AnswerEventPending,
+			"attempts":     0,
+			"last_error":   "",
+			"processed_at": nil,
+			"updated_at":   time.Now(),
+		}),
+	}).Create(event).Error
+}
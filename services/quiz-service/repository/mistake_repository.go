@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/RigelNana/arkstudy/quiz-service/models"
+)
+
+// 根据作答结果更新错题本：答错时加入或重置，答对时累计连对次数，达到 masteryStreak 后标记为已掌握
+// 两种情况都是单条语句，同一用户并发提交同一题时不会在 (user_id, question_id) 唯一索引上冲突
+func (r *QuizRepository) RecordMistakeAttempt(question *models.Question, userID string, isCorrect bool, masteryStreak int) error {
+	now := time.Now()
+	if isCorrect {
+		// 从未答错过的题目答对时无需记录，只更新已有记录；SET 中的 correct_streak 为更新前的值
+		return r.db.Model(&models.MistakeRecord{}).
+			Where("user_id = ? AND question_id = ?", userID, question.QuestionID).
+			Updates(map[string]interface{}{
+				"correct_streak":  gorm.Expr("correct_streak + 1"),
+				"mastered":        gorm.Expr("correct_streak + 1 >= ?", masteryStreak),
+				"last_attempt_at": now,
+			}).Error
+	}
+
+	record := &models.MistakeRecord{
+		UserID:        userID,
+		QuestionID:    question.QuestionID,
+		MaterialID:    question.MaterialID,
+		WrongCount:    1,
+		LastWrongAt:   now,
+		LastAttemptAt: now,
+	}
+	return r.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "user_id"}, {Name: "question_id"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"wrong_count":     gorm.Expr("mistake_records.wrong_count + 1"),
+			"correct_streak":  0,
+			"mastered":        false,
+			"last_wrong_at":   now,
+			"last_attempt_at": now,
+			"updated_at":      now,
+			"lock_version":    gorm.Expr("mistake_records.lock_version + 1"),
+		}),
+	}).Create(record).Error
+}
+
+// 分页获取用户错题记录
+func (r *QuizRepository) ListMistakes(userID, materialID string, includeMastered bool, page, pageSize int) ([]*models.MistakeRecord, int64, error) {
+	var records []*models.MistakeRecord
+	var total int64
+
+	query := r.db.Model(&models.MistakeRecord{}).Where("user_id = ?", userID)
+	if materialID != "" {
+		query = query.Where("material_id = ?", materialID)
+	}
+	if !includeMastered {
+		query = query.Where("mastered = ?", false)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	if err := query.Offset(offset).Limit(pageSize).Order("last_wrong_at DESC").Find(&records).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return records, total, nil
+}
+
+// 获取待重练的错题，优先返回最久未练习的题目
+func (r *QuizRepository) GetRetryQuestions(userID, materialID, knowledgePoint string, count int) ([]*models.MistakeRecord, error) {
+	query := r.db.Model(&models.MistakeRecord{}).
+		Select("mistake_records.*").
+		Joins("JOIN questions ON questions.question_id = mistake_records.question_id AND questions.deleted_at IS NULL").
+		Where("mistake_records.user_id = ? AND mistake_records.mastered = ?", userID, false)
+	if materialID != "" {
+		query = query.Where("mistake_records.material_id = ?", materialID)
+	}
+	// knowledge_points 为 JSON 数组文本，按完整元素匹配，避免子串与通配符误匹配
+	if knowledgePoint != "" {
+		query = query.Where("NULLIF(questions.knowledge_points, '')::jsonb @> jsonb_build_array(?::text)", knowledgePoint)
+	}
+
+	var records []*models.MistakeRecord
+	err := query.Order("mistake_records.last_attempt_at ASC").Limit(count).Find(&records).Error
+	return records, err
+}
+
+// 统计用户尚未掌握的错题数
+func (r *QuizRepository) CountUnmasteredMistakes(userID, materialID string) (int64, error) {
+	var total int64
+	query := r.db.Model(&models.MistakeRecord{}).Where("user_id = ? AND mastered = ?", userID, false)
+	if materialID != "" {
+		query = query.Where("material_id = ?", materialID)
+	}
+	err := query.Count(&total).Error
+	return total, err
+}
@@ -1,12 +1,11 @@
 package repository
 
 import (
-	"fmt"
+	"context"
 
-	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
-	"gorm.io/gorm/logger"
 
+	"github.com/RigelNana/arkstudy/pkg/dbx"
 	"github.com/RigelNana/arkstudy/quiz-service/models"
 )
 
@@ -14,33 +13,25 @@ type QuizRepository struct {
 	db *gorm.DB
 }
 
-func NewQuizRepository(dsn string) (*QuizRepository, error) {
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
-		Logger:                                   logger.Default.LogMode(logger.Info),
-		DisableForeignKeyConstraintWhenMigrating: true,
-	})
-	if err != nil {
-		return nil, fmt.Errorf("failed to connect to database: %v", err)
-	}
-
-	// 自动迁移表结构 - 先创建独立表，再创建依赖表
-	// 分别迁移每个表，避免外键依赖问题
-	err = db.Migrator().AutoMigrate(&models.Question{})
-	if err != nil {
-		return nil, fmt.Errorf("failed to migrate Question table: %v", err)
-	}
+func NewQuizRepository(db *gorm.DB) *QuizRepository {
+	return &QuizRepository{db: db}
+}
 
-	err = db.Migrator().AutoMigrate(&models.KnowledgePointStats{})
-	if err != nil {
-		return nil, fmt.Errorf("failed to migrate KnowledgePointStats table: %v", err)
-	}
+// 返回以 ctx 访问数据库的仓库，ctx 中的操作人（见 dbx.WithActor）由插件写入 created_by/updated_by
+func (r *QuizRepository) WithContext(ctx context.Context) *QuizRepository {
+	return &QuizRepository{db: r.db.WithContext(ctx)}
+}
 
-	err = db.Migrator().AutoMigrate(&models.UserAnswer{})
-	if err != nil {
-		return nil, fmt.Errorf("failed to migrate UserAnswer table: %v", err)
-	}
+// 以指定用户为操作人访问数据库，用于不经过 gRPC 上下文的服务层写入
+func (r *QuizRepository) AsActor(userID string) *QuizRepository {
+	return r.WithContext(dbx.WithActor(context.Background(), userID))
+}
 
-	return &QuizRepository{db: db}, nil
+// 在同一个数据库事务中执行 fn，fn 内应使用传入的 txRepo 访问数据库
+func (r *QuizRepository) Transaction(fn func(txRepo *QuizRepository) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return fn(&QuizRepository{db: tx})
+	})
 }
 
 // 创建题目
@@ -112,12 +103,19 @@ func (r *QuizRepository) GetUserAnswer(questionID, userID string) (*models.UserA
 	return &answer, nil
 }
 
-// 获取用户答题历史
+// 统计用户对某题的历史作答次数
+func (r *QuizRepository) CountUserAttempts(questionID, userID string) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.UserAnswer{}).Where("question_id = ? AND user_id = ?", questionID, userID).Count(&count).Error
+	return count, err
+}
+
+// 获取用户答题历史，配置了只读副本时从副本读取
 func (r *QuizRepository) GetUserAnswerHistory(userID string, page, pageSize int) ([]*models.UserAnswer, int64, error) {
 	var answers []*models.UserAnswer
 	var total int64
 
-	query := r.db.Model(&models.UserAnswer{}).Where("user_id = ?", userID)
+	query := r.db.Scopes(dbx.ReadReplica).Model(&models.UserAnswer{}).Where("user_id = ?", userID)
 
 	// 计算总数
 	if err := query.Count(&total).Error; err != nil {
@@ -133,11 +131,11 @@ func (r *QuizRepository) GetUserAnswerHistory(userID string, page, pageSize int)
 	return answers, total, nil
 }
 
-// 获取知识点统计
+// 获取知识点统计，配置了只读副本时从副本读取
 func (r *QuizRepository) GetKnowledgeStats(userID, materialID string) ([]*models.KnowledgePointStats, error) {
 	var stats []*models.KnowledgePointStats
 
-	query := r.db.Model(&models.KnowledgePointStats{}).Where("user_id = ?", userID)
+	query := r.db.Scopes(dbx.ReadReplica).Model(&models.KnowledgePointStats{}).Where("user_id = ?", userID)
 	if materialID != "" {
 		query = query.Where("material_id = ?", materialID)
 	}
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"github.com/RigelNana/arkstudy/quiz-service/models"
+)
+
+// 按审核状态条件更新题目，只更新当前状态属于 fromStatus 的题目；reviewerID 非空时还要求为该审核人，返回更新的题目数
+func (r *QuizRepository) TransitionReview(questionIDs []string, fromStatus []string, reviewerID string, updates map[string]interface{}) (int64, error) {
+	query := r.db.Model(&models.Question{}).
+		Where("question_id IN ? AND review_status IN ?", questionIDs, fromStatus)
+	if reviewerID != "" {
+		query = query.Where("reviewer_id = ?", reviewerID)
+	}
+	result := query.Updates(updates)
+	return result.RowsAffected, result.Error
+}
+
+// 获取课程题目，学生只能看到审核通过的题目，创建者与审核人还能看到本人相关的未通过题目
+func (r *QuizRepository) ListCourseQuestions(courseID, viewerID, materialID string, questionType *models.QuestionType, difficulty *models.DifficultyLevel, page, pageSize int) ([]*models.Question, int64, error) {
+	var questions []*models.Question
+	var total int64
+
+	query := r.db.Model(&models.Question{}).
+		Where("course_id = ?", courseID).
+		Where("review_status = ? OR creator_id = ? OR reviewer_id = ?", models.ReviewStatusApproved, viewerID, viewerID)
+	if materialID != "" {
+		query = query.Where("material_id = ?", materialID)
+	}
+	if questionType != nil {
+		query = query.Where("type = ?", *questionType)
+	}
+	if difficulty != nil {
+		query = query.Where("difficulty = ?", *difficulty)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	offset := (page - 1) * pageSize
+	if err := query.Offset(offset).Limit(pageSize).Order("created_at DESC").Find(&questions).Error; err != nil {
+		return nil, 0, err
+	}
+	return questions, total, nil
+}
+
+// 获取审核人的审核队列，courseID、status 为空时不限，最早提交的在前
+func (r *QuizRepository) ListReviewQueue(reviewerID, courseID, status string, page, pageSize int) ([]*models.Question, int64, error) {
+	var questions []*models.Question
+	var total int64
+
+	query := r.db.Model(&models.Question{}).Where("reviewer_id = ?", reviewerID)
+	if courseID != "" {
+		query = query.Where("course_id = ?", courseID)
+	}
+	if status != "" {
+		query = query.Where("review_status = ?", status)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	offset := (page - 1) * pageSize
+	if err := query.Offset(offset).Limit(pageSize).Order("updated_at ASC").Find(&questions).Error; err != nil {
+		return nil, 0, err
+	}
+	return questions, total, nil
+}
+
+// 获取审核人在课程中待审核的全部题目ID
+func (r *QuizRepository) ListPendingReviewIDs(reviewerID, courseID string, limit int) ([]string, error) {
+	var ids []string
+	err := r.db.Model(&models.Question{}).
+		Where("reviewer_id = ? AND course_id = ? AND review_status = ?", reviewerID, courseID, models.ReviewStatusPendingReview).
+		Order("created_at ASC").
+		Limit(limit).
+		Pluck("question_id", &ids).Error
+	return ids, err
+}
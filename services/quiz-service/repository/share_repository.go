@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/RigelNana/arkstudy/quiz-service/models"
+)
+
+// 创建题目分享
+func (r *QuizRepository) CreateShare(share *models.QuizShare) error {
+	return r.db.Create(share).Error
+}
+
+// 根据分享ID获取分享
+func (r *QuizRepository) GetShareByID(shareID string) (*models.QuizShare, error) {
+	var share models.QuizShare
+	if err := r.db.Where("share_id = ?", shareID).First(&share).Error; err != nil {
+		return nil, err
+	}
+	return &share, nil
+}
+
+// 获取用户创建的分享，最新的在前
+func (r *QuizRepository) ListSharesByOwner(ownerID string, page, pageSize int) ([]*models.QuizShare, int64, error) {
+	var shares []*models.QuizShare
+	var total int64
+
+	query := r.db.Model(&models.QuizShare{}).Where("owner_id = ?", ownerID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	offset := (page - 1) * pageSize
+	if err := query.Offset(offset).Limit(pageSize).Order("created_at DESC").Find(&shares).Error; err != nil {
+		return nil, 0, err
+	}
+	return shares, total, nil
+}
+
+// 撤销分享，已撤销的分享保留原撤销时间
+func (r *QuizRepository) RevokeShare(shareID string, at time.Time) error {
+	return r.db.Model(&models.QuizShare{}).
+		Where("share_id = ? AND revoked_at IS NULL", shareID).
+		Update("revoked_at", at).Error
+}
+
+// 创建分享作答记录
+func (r *QuizRepository) CreateShareAttempt(attempt *models.QuizShareAttempt) error {
+	return r.db.Create(attempt).Error
+}
+
+// 获取分享的作答记录，最新的在前；flaggedOnly 时只返回有异常标记的作答
+func (r *QuizRepository) ListShareAttempts(shareID string, flaggedOnly bool, page, pageSize int) ([]*models.QuizShareAttempt, int64, error) {
+	var attempts []*models.QuizShareAttempt
+	var total int64
+
+	query := r.db.Model(&models.QuizShareAttempt{}).Where("share_id = ?", shareID)
+	if flaggedOnly {
+		query = query.Where("flagged = ?", true)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	offset := (page - 1) * pageSize
+	if err := query.Offset(offset).Limit(pageSize).Order("submitted_at DESC").Find(&attempts).Error; err != nil {
+		return nil, 0, err
+	}
+	return attempts, total, nil
+}
+
+// 统计各分享的作答次数
+func (r *QuizRepository) CountShareAttempts(shareIDs []string) (map[string]int, error) {
+	counts := make(map[string]int, len(shareIDs))
+	if len(shareIDs) == 0 {
+		return counts, nil
+	}
+	var rows []struct {
+		ShareID string
+		Count   int
+	}
+	err := r.db.Model(&models.QuizShareAttempt{}).
+		Select("share_id, COUNT(*) AS count").
+		Where("share_id IN ?", shareIDs).
+		Group("share_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		counts[row.ShareID] = row.Count
+	}
+	return counts, nil
+}
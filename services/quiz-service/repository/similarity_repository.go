@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"github.com/RigelNana/arkstudy/quiz-service/models"
+)
+
+// 保存作答向量
+func (r *QuizRepository) CreateAnswerEmbedding(embedding *models.AnswerEmbedding) error {
+	return r.db.Create(embedding).Error
+}
+
+// 获取其他用户对同一题目的作答向量，只返回同一模型生成的向量，最新的在前
+func (r *QuizRepository) ListPeerAnswerEmbeddings(questionID, excludeUserID, model string, limit int) ([]*models.AnswerEmbedding, error) {
+	var embeddings []*models.AnswerEmbedding
+	err := r.db.Where("question_id = ? AND user_id <> ? AND model = ?", questionID, excludeUserID, model).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&embeddings).Error
+	return embeddings, err
+}
@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"github.com/RigelNana/arkstudy/quiz-service/models"
+)
+
+// 批量保存题目版本
+func (r *QuizRepository) CreateQuestionVersions(versions []*models.QuestionVersion) error {
+	if len(versions) == 0 {
+		return nil
+	}
+	return r.db.CreateInBatches(versions, 100).Error
+}
+
+// 获取题目的指定版本
+func (r *QuizRepository) GetQuestionVersion(questionID string, version int) (*models.QuestionVersion, error) {
+	var v models.QuestionVersion
+	if err := r.db.Where("question_id = ? AND version = ?", questionID, version).First(&v).Error; err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+// 获取题目的全部版本，最新的在前
+func (r *QuizRepository) ListQuestionVersions(questionID string) ([]*models.QuestionVersion, error) {
+	var versions []*models.QuestionVersion
+	err := r.db.Where("question_id = ?", questionID).Order("version DESC").Find(&versions).Error
+	return versions, err
+}
+
+// 按版本号条件更新题目内容，题目已被他人修改（版本号不一致）时不更新，返回更新的行数
+func (r *QuizRepository) UpdateQuestionContent(questionID string, version int, updates map[string]interface{}) (int64, error) {
+	result := r.db.Model(&models.Question{}).
+		Where("question_id = ? AND version = ?", questionID, version).
+		Updates(updates)
+	return result.RowsAffected, result.Error
+}
+
+// 用户是否作答过题目的指定版本，分享作答单独存储，不计入
+func (r *QuizRepository) HasAnsweredVersion(questionID, userID string, version int) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.UserAnswer{}).
+		Where("question_id = ? AND user_id = ? AND question_version = ?", questionID, userID, version).
+		Count(&count).Error
+	return count > 0, err
+}
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/RigelNana/arkstudy/pkg/discovery"
+	"github.com/RigelNana/arkstudy/pkg/registry"
+	"github.com/sirupsen/logrus"
+
+	materialPb "github.com/RigelNana/arkstudy/proto/material"
+	userPb "github.com/RigelNana/arkstudy/proto/user"
+)
+
+// user-service 中教师与管理员的角色
+const (
+	RoleTeacher = "teacher"
+	RoleAdmin   = "admin"
+)
+
+var ErrPermissionDenied = errors.New("无权执行该操作")
+
+// AccessChecker 校验写操作的权限：材料归属经 material-service 查询，教师/管理员角色经 user-service 查询
+type AccessChecker struct {
+	materials materialPb.MaterialServiceClient
+	users     userPb.UserServiceClient
+	logger    *logrus.Logger
+}
+
+func NewAccessChecker(materialServiceAddr, userServiceAddr string, logger *logrus.Logger) (*AccessChecker, error) {
+	materialConn, err := discovery.DialAddr(registry.Material.Name, materialServiceAddr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to material service: %v", err)
+	}
+	userConn, err := discovery.DialAddr(registry.User.Name, userServiceAddr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to user service: %v", err)
+	}
+	return NewAccessCheckerWithClients(materialPb.NewMaterialServiceClient(materialConn), userPb.NewUserServiceClient(userConn), logger), nil
+}
+
+// 使用已有的客户端构造，便于替换为测试实现
+func NewAccessCheckerWithClients(materials materialPb.MaterialServiceClient, users userPb.UserServiceClient, logger *logrus.Logger) *AccessChecker {
+	return &AccessChecker{materials: materials, users: users, logger: logger}
+}
+
+// 校验 userID 为材料的上传者，材料不存在或属于他人时返回 ErrPermissionDenied
+func (a *AccessChecker) RequireMaterialOwner(ctx context.Context, materialID, userID string) error {
+	if materialID == "" || userID == "" {
+		return ErrPermissionDenied
+	}
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	resp, err := a.materials.GetMaterial(ctx, &materialPb.GetMaterialRequest{MaterialId: materialID, UserId: userID})
+	if err != nil {
+		return fmt.Errorf("failed to query material service: %v", err)
+	}
+	if !resp.Found || resp.Material.GetUserId() != userID {
+		return ErrPermissionDenied
+	}
+	return nil
+}
+
+// 用户在 user-service 中是否为教师或管理员
+func (a *AccessChecker) IsStaff(ctx context.Context, userID string) (bool, error) {
+	if userID == "" {
+		return false, nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	resp, err := a.users.GetUserByID(ctx, &userPb.GetUserByIDRequest{Id: userID})
+	if err != nil {
+		return false, fmt.Errorf("failed to query user service: %v", err)
+	}
+	if !resp.Found {
+		return false, nil
+	}
+	role := resp.User.GetRole()
+	return role == RoleTeacher || role == RoleAdmin, nil
+}
+
+// 校验用户为教师或管理员，否则返回 ErrPermissionDenied
+func (a *AccessChecker) RequireStaff(ctx context.Context, userID string) error {
+	ok, err := a.IsStaff(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrPermissionDenied
+	}
+	return nil
+}
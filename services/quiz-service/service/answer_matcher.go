@@ -0,0 +1,249 @@
+package service
+
+import (
+	"encoding/json"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/RigelNana/arkstudy/quiz-service/models"
+)
+
+// 填空题匹配方式
+const (
+	MatchTypeExact      = "exact"      // 原文完全一致
+	MatchTypeNormalized = "normalized" // 归一化后一致（大小写、空白、标点）
+	MatchTypeAlias      = "alias"      // 命中同义答案
+	MatchTypeNumeric    = "numeric"    // 数值在容差范围内
+	MatchTypeNone       = "none"       // 未匹配
+)
+
+// 填空题匹配结果
+type FillBlankMatch struct {
+	Matched          bool    `json:"matched"`
+	MatchType        string  `json:"match_type"`
+	MatchedAnswer    string  `json:"matched_answer"`    // 命中的标准答案或同义答案
+	NormalizedAnswer string  `json:"normalized_answer"` // 归一化后的用户答案
+	NumericDiff      float64 `json:"numeric_diff"`      // 数值匹配时与标准答案的差值
+}
+
+// 匹配填空题答案：依次尝试原文、归一化、同义答案和数值容差
+func MatchFillBlank(question *models.Question, userAnswer string) *FillBlankMatch {
+	return matchAnswer(question.CorrectAnswer, question.GetAnswerAliases(), question.NumericTolerance, userAnswer)
+}
+
+func matchAnswer(correctAnswer string, aliases []string, tolerance float64, userAnswer string) *FillBlankMatch {
+	normalizedUser := normalizeAnswer(userAnswer)
+	result := &FillBlankMatch{
+		MatchType:        MatchTypeNone,
+		NormalizedAnswer: normalizedUser,
+	}
+
+	if correctAnswer == userAnswer {
+		result.Matched = true
+		result.MatchType = MatchTypeExact
+		result.MatchedAnswer = correctAnswer
+		return result
+	}
+
+	if normalizedUser != "" && normalizeAnswer(correctAnswer) == normalizedUser {
+		result.Matched = true
+		result.MatchType = MatchTypeNormalized
+		result.MatchedAnswer = correctAnswer
+		return result
+	}
+
+	for _, alias := range aliases {
+		if normalizedUser != "" && normalizeAnswer(alias) == normalizedUser {
+			result.Matched = true
+			result.MatchType = MatchTypeAlias
+			result.MatchedAnswer = alias
+			return result
+		}
+	}
+
+	// 数值答案：容差为0时仅做数值等价判断（如 3 与 3.0）
+	userNum, ok := parseNumber(userAnswer)
+	if !ok {
+		return result
+	}
+	for _, candidate := range append([]string{correctAnswer}, aliases...) {
+		expected, ok := parseNumber(candidate)
+		if !ok {
+			continue
+		}
+		diff := math.Abs(userNum - expected)
+		if diff <= tolerance+1e-9 {
+			result.Matched = true
+			result.MatchType = MatchTypeNumeric
+			result.MatchedAnswer = candidate
+			result.NumericDiff = diff
+			return result
+		}
+	}
+
+	return result
+}
+
+// 逐项评估多空题，返回每一项的结果以及按 mode 计算的总分（0~1）
+func EvaluateParts(parts []models.QuestionPart, answers []string, mode string) ([]models.PartResult, float32) {
+	results := make([]models.PartResult, len(parts))
+	var totalWeight, gained float32
+	allCorrect := true
+	for i, part := range parts {
+		weight := part.Weight
+		if weight <= 0 || mode == models.PartialCreditEqual {
+			weight = 1
+		}
+		var answer string
+		if i < len(answers) {
+			answer = answers[i]
+		}
+
+		match := matchAnswer(part.CorrectAnswer, part.AnswerAliases, part.NumericTolerance, answer)
+		result := models.PartResult{
+			Index:         i,
+			Answer:        answer,
+			IsCorrect:     match.Matched,
+			Weight:        weight,
+			CorrectAnswer: part.CorrectAnswer,
+			MatchType:     match.MatchType,
+		}
+		if match.Matched {
+			result.Score = 1
+			gained += weight
+		} else {
+			allCorrect = false
+		}
+		totalWeight += weight
+		results[i] = result
+	}
+
+	if totalWeight == 0 {
+		return results, 0
+	}
+	if mode == models.PartialCreditAllOrNothing {
+		if allCorrect {
+			return results, 1
+		}
+		return results, 0
+	}
+	return results, gained / totalWeight
+}
+
+// 将单个答案字符串拆分为多空答案，支持 | 和换行分隔；空项会保留以对齐空位
+func SplitPartAnswers(answer string) []string {
+	replacer := strings.NewReplacer("｜", "|", "\r\n", "|", "\n", "|")
+	fields := strings.Split(replacer.Replace(answer), "|")
+	for i := range fields {
+		fields[i] = strings.TrimSpace(fields[i])
+	}
+	return fields
+}
+
+// 归一化答案：统一全角字符、转小写、去掉标点并压缩空白
+// 数字之间的小数点、分数线以及数字前的负号会保留，避免 3.14 与 314 被视为相同
+func normalizeAnswer(s string) string {
+	runes := []rune(s)
+	for i, r := range runes {
+		runes[i] = toHalfWidth(r)
+	}
+
+	var b strings.Builder
+	lastSpace := true
+	for i, r := range runes {
+		switch {
+		case unicode.IsSpace(r):
+			if !lastSpace {
+				b.WriteRune(' ')
+				lastSpace = true
+			}
+		case unicode.IsPunct(r) || unicode.IsSymbol(r):
+			if keepNumericPunct(runes, i) {
+				b.WriteRune(r)
+				lastSpace = false
+			}
+		default:
+			b.WriteRune(unicode.ToLower(r))
+			lastSpace = false
+		}
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// 判断标点是否属于数值的一部分
+func keepNumericPunct(runes []rune, i int) bool {
+	nextDigit := i+1 < len(runes) && unicode.IsDigit(runes[i+1])
+	prevDigit := i > 0 && unicode.IsDigit(runes[i-1])
+	switch runes[i] {
+	case '.', '/':
+		return prevDigit && nextDigit
+	case '-':
+		return nextDigit && (i == 0 || unicode.IsSpace(runes[i-1]))
+	default:
+		return false
+	}
+}
+
+// 全角字符转半角
+func toHalfWidth(r rune) rune {
+	if r == 0x3000 {
+		return ' '
+	}
+	if r >= 0xFF01 && r <= 0xFF5E {
+		return r - 0xFEE0
+	}
+	return r
+}
+
+// 解析数值答案，支持全角数字、千分位分隔符和末尾的百分号
+func parseNumber(s string) (float64, bool) {
+	var b strings.Builder
+	for _, r := range strings.TrimSpace(s) {
+		r = toHalfWidth(r)
+		if r == ',' || unicode.IsSpace(r) {
+			continue
+		}
+		b.WriteRune(r)
+	}
+	str := strings.TrimSuffix(b.String(), "%")
+	if str == "" {
+		return 0, false
+	}
+	v, err := strconv.ParseFloat(str, 64)
+	if err != nil || math.IsNaN(v) || math.IsInf(v, 0) {
+		return 0, false
+	}
+	return v, true
+}
+
+// 序列化分项答案，忽略没有标准答案的分项
+func marshalParts(parts []models.QuestionPart) string {
+	var cleaned []models.QuestionPart
+	for _, p := range parts {
+		if strings.TrimSpace(p.CorrectAnswer) != "" {
+			cleaned = append(cleaned, p)
+		}
+	}
+	if len(cleaned) == 0 {
+		return ""
+	}
+	data, _ := json.Marshal(cleaned)
+	return string(data)
+}
+
+// 序列化同义答案列表
+func marshalAliases(aliases []string) string {
+	var cleaned []string
+	for _, a := range aliases {
+		if a = strings.TrimSpace(a); a != "" {
+			cleaned = append(cleaned, a)
+		}
+	}
+	if len(cleaned) == 0 {
+		return ""
+	}
+	data, _ := json.Marshal(cleaned)
+	return string(data)
+}
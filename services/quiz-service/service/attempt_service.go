@@ -0,0 +1,177 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+
+	"github.com/RigelNana/arkstudy/quiz-service/models"
+	"github.com/RigelNana/arkstudy/quiz-service/repository"
+)
+
+var (
+	ErrAttemptNotFound  = errors.New("作答会话不存在")
+	ErrAttemptSubmitted = errors.New("该作答会话已提交")
+	ErrAttemptStale     = errors.New("题目已被修改，请重新开始作答")
+	ErrAttemptRequired  = errors.New("选择题需先开始作答，再携带作答会话提交")
+	ErrInvalidTimeSpent = errors.New("作答耗时不能为负数")
+)
+
+// 选项开头的字母序号，如 "A. "、"B、"、"C）"
+var optionLabelPattern = regexp.MustCompile(`^\s*[A-Za-z]\s*[.．、:：)）]\s*`)
+
+// 作答会话服务：开始作答时打乱选择题的选项顺序并保存排列，提交时把作答时看到的选项换算回原选项评分
+type AttemptService struct {
+	repo   *repository.QuizRepository
+	logger *logrus.Logger
+}
+
+func NewAttemptService(repo *repository.QuizRepository, logger *logrus.Logger) *AttemptService {
+	return &AttemptService{repo: repo, logger: logger}
+}
+
+// 开始作答，返回会话与题目。只有单选项组的选择题会打乱选项，其余题型的会话不带排列
+func (s *AttemptService) Start(questionID, userID string) (*models.QuestionAttempt, *models.Question, error) {
+	question, err := s.repo.GetQuestionByID(questionID)
+	if err != nil || !question.VisibleTo(userID) {
+		return nil, nil, ErrQuestionNotFound
+	}
+
+	attempt := &models.QuestionAttempt{
+		AttemptID:       uuid.New().String(),
+		QuestionID:      questionID,
+		UserID:          userID,
+		QuestionVersion: question.Version,
+	}
+	if order := shuffleOrder(question); len(order) > 0 {
+		data, _ := json.Marshal(order)
+		attempt.OptionOrder = string(data)
+	}
+	if err := s.repo.CreateQuestionAttempt(attempt); err != nil {
+		return nil, nil, fmt.Errorf("创建作答会话失败: %w", err)
+	}
+	return attempt, question, nil
+}
+
+// 校验提交答案时引用的作答会话：会话须属于该用户与题目、尚未提交，且题目在此期间未被编辑
+func (s *AttemptService) Resolve(attemptID, userID string, question *models.Question) (*models.QuestionAttempt, error) {
+	attempt, err := s.repo.GetQuestionAttempt(attemptID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrAttemptNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("获取作答会话失败: %w", err)
+	}
+	if attempt.UserID != userID || attempt.QuestionID != question.QuestionID {
+		return nil, ErrAttemptNotFound
+	}
+	if attempt.SubmittedAt != nil {
+		return nil, ErrAttemptSubmitted
+	}
+	if attempt.QuestionVersion != question.Version {
+		return nil, ErrAttemptStale
+	}
+	return attempt, nil
+}
+
+// 单题作答耗时的上限，超出的上报值按上限计入统计
+const MaxAnswerTimeSpent = 2 * time.Hour
+
+// ClampTimeSpent 将前端上报的作答耗时限制在上限内；startedAt 非零时（通过作答会话提交）不超过开始作答至今的时长
+func ClampTimeSpent(ms int64, startedAt time.Time) int64 {
+	limit := MaxAnswerTimeSpent.Milliseconds()
+	if !startedAt.IsZero() {
+		limit = min(limit, max(time.Since(startedAt).Milliseconds(), 0))
+	}
+	return min(max(ms, 0), limit)
+}
+
+// RequiresAttempt 题目的选项是否会被打乱；这类题目必须通过作答会话提交，否则可按原选项顺序作答绕过打乱
+func RequiresAttempt(q *models.Question) bool {
+	return optionCount(q) >= 2
+}
+
+// 可打乱的选择题的选项数，其他题目为 0
+func optionCount(q *models.Question) int {
+	if q.Type != models.MultipleChoice || q.Parts != "" {
+		return 0
+	}
+	var options []string
+	if q.Options != "" {
+		json.Unmarshal([]byte(q.Options), &options)
+	}
+	return len(options)
+}
+
+// 生成选项的随机排列，不适合打乱的题目返回 nil
+func shuffleOrder(q *models.Question) []int {
+	n := optionCount(q)
+	if n < 2 {
+		return nil
+	}
+	return rand.Perm(n)
+}
+
+// PermuteOptions 按排列重排选项，原选项带字母序号时按新位置重新编号；排列与选项数不一致时原样返回
+func PermuteOptions(options []string, order []int) []string {
+	if len(order) == 0 || len(order) != len(options) {
+		return options
+	}
+	permuted := make([]string, len(options))
+	for i, src := range order {
+		option := options[src]
+		if loc := optionLabelPattern.FindStringIndex(option); loc != nil {
+			option = optionLetter(i) + ". " + option[loc[1]:]
+		}
+		permuted[i] = option
+	}
+	return permuted
+}
+
+// PermuteAnswer 将原选项的答案字母换算为排列后展示的字母
+func PermuteAnswer(answer string, order []int) string {
+	shown := make([]int, len(order))
+	for i, src := range order {
+		shown[src] = i
+	}
+	return remapAnswerLetters(answer, len(order), func(i int) int { return shown[i] })
+}
+
+// UnpermuteAnswer 将作答时看到的选项字母换算回原选项的字母
+func UnpermuteAnswer(answer string, order []int) string {
+	return remapAnswerLetters(answer, len(order), func(i int) int { return order[i] })
+}
+
+// 逐个换算由选项字母组成的答案（不区分大小写，多选题按字母顺序输出）；含其他字符的答案原样返回
+func remapAnswerLetters(answer string, n int, mapIndex func(int) int) string {
+	trimmed := strings.ToUpper(strings.TrimSpace(answer))
+	if n == 0 || trimmed == "" {
+		return answer
+	}
+	indexes := make([]int, 0, len(trimmed))
+	for _, r := range trimmed {
+		if r < 'A' || int(r-'A') >= n {
+			return answer
+		}
+		indexes = append(indexes, mapIndex(int(r-'A')))
+	}
+	sort.Ints(indexes)
+	var b strings.Builder
+	for _, i := range indexes {
+		b.WriteString(optionLetter(i))
+	}
+	return b.String()
+}
+
+func optionLetter(i int) string {
+	return string(rune('A' + i))
+}
@@ -0,0 +1,120 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/RigelNana/arkstudy/quiz-service/models"
+)
+
+// 单份作答每类埋点最多保存的事件数，超出部分丢弃
+const maxTelemetryEvents = 500
+
+// AnomalyThresholds 异常作答判定阈值，零值表示不检查该项
+type AnomalyThresholds struct {
+	MaxFocusLosses int           // 离开页面次数达到该值时标记
+	LongFocusLoss  time.Duration // 单次离开页面达到该时长时标记
+	MinPasteLength int           // 单次粘贴字符数达到该值时标记
+	MinAnswerTime  time.Duration // 答对的题目用时低于该值时标记
+}
+
+// 丢弃时间为负的事件，不属于本次作答题目的事件保留但清空题目ID
+func sanitizeTelemetry(t *models.AttemptTelemetry, questionIDs map[string]bool) *models.AttemptTelemetry {
+	if t == nil {
+		return nil
+	}
+	cleaned := &models.AttemptTelemetry{DurationMs: max(t.DurationMs, 0)}
+	for _, e := range t.FocusEvents {
+		if len(cleaned.FocusEvents) >= maxTelemetryEvents {
+			break
+		}
+		if e.OffsetMs < 0 || e.AwayMs < 0 {
+			continue
+		}
+		if !questionIDs[e.QuestionID] {
+			e.QuestionID = ""
+		}
+		cleaned.FocusEvents = append(cleaned.FocusEvents, e)
+	}
+	for _, e := range t.PasteEvents {
+		if len(cleaned.PasteEvents) >= maxTelemetryEvents {
+			break
+		}
+		if e.OffsetMs < 0 || e.Length <= 0 {
+			continue
+		}
+		if !questionIDs[e.QuestionID] {
+			e.QuestionID = ""
+		}
+		cleaned.PasteEvents = append(cleaned.PasteEvents, e)
+	}
+	if cleaned.DurationMs == 0 && len(cleaned.FocusEvents) == 0 && len(cleaned.PasteEvents) == 0 {
+		return nil
+	}
+	return cleaned
+}
+
+// 按阈值检查作答埋点与各题用时，返回异常标记。标记只提示创建者复核，不影响评分
+func detectAnomalies(t *models.AttemptTelemetry, results []models.ShareAnswerResult, th AnomalyThresholds) []models.AttemptFlag {
+	var flags []models.AttemptFlag
+	if t != nil {
+		if th.MaxFocusLosses > 0 && len(t.FocusEvents) >= th.MaxFocusLosses {
+			var away int64
+			for _, e := range t.FocusEvents {
+				away += e.AwayMs
+			}
+			flags = append(flags, models.AttemptFlag{
+				Type:   models.AttemptFlagFocusLoss,
+				Detail: fmt.Sprintf("离开页面 %d 次，共 %s", len(t.FocusEvents), formatMs(away)),
+			})
+		}
+		if th.LongFocusLoss > 0 {
+			for _, e := range t.FocusEvents {
+				if e.AwayMs >= th.LongFocusLoss.Milliseconds() {
+					flags = append(flags, models.AttemptFlag{
+						Type:       models.AttemptFlagLongFocusLoss,
+						QuestionID: e.QuestionID,
+						Detail:     fmt.Sprintf("单次离开页面 %s", formatMs(e.AwayMs)),
+					})
+				}
+			}
+		}
+		if th.MinPasteLength > 0 {
+			// 同一题的多次粘贴合并为一个标记
+			pasted := make(map[string]int)
+			var order []string
+			for _, e := range t.PasteEvents {
+				if e.Length < th.MinPasteLength {
+					continue
+				}
+				if _, ok := pasted[e.QuestionID]; !ok {
+					order = append(order, e.QuestionID)
+				}
+				pasted[e.QuestionID] += e.Length
+			}
+			for _, id := range order {
+				flags = append(flags, models.AttemptFlag{
+					Type:       models.AttemptFlagPaste,
+					QuestionID: id,
+					Detail:     fmt.Sprintf("粘贴了 %d 个字符", pasted[id]),
+				})
+			}
+		}
+	}
+	if th.MinAnswerTime > 0 {
+		for _, r := range results {
+			if r.IsCorrect && r.TimeSpentMs > 0 && r.TimeSpentMs < th.MinAnswerTime.Milliseconds() {
+				flags = append(flags, models.AttemptFlag{
+					Type:       models.AttemptFlagFastAnswer,
+					QuestionID: r.QuestionID,
+					Detail:     fmt.Sprintf("用时 %s 即答对", formatMs(r.TimeSpentMs)),
+				})
+			}
+		}
+	}
+	return flags
+}
+
+func formatMs(ms int64) string {
+	return fmt.Sprintf("%.1f 秒", float64(ms)/1000)
+}
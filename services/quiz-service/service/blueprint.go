@@ -0,0 +1,203 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/RigelNana/arkstudy/quiz-service/models"
+)
+
+const (
+	// MaxBlueprintSlots 蓝图最多的槽位数
+	MaxBlueprintSlots = 20
+	// MaxBlueprintQuestions 蓝图的题目总数上限
+	MaxBlueprintQuestions = 100
+	// 单个槽位最多尝试生成的次数，每次只补齐仍缺少的题目
+	blueprintMaxAttempts = 3
+)
+
+// 蓝图题目未通过校验的原因
+const (
+	RejectDifficultyMismatch     = "difficulty_mismatch"
+	RejectKnowledgePointMismatch = "knowledge_point_mismatch"
+	RejectIncomplete             = "incomplete"
+	RejectDuplicate              = "duplicate" // 与蓝图中已保留的题目题干相同
+)
+
+// 组卷蓝图槽位：生成 Count 道 KnowledgePoint 知识点、Difficulty 难度的 Type 题型题目，知识点为空时不限
+type BlueprintSlot struct {
+	Type           models.QuestionType
+	KnowledgePoint string
+	Difficulty     models.DifficultyLevel
+	Count          int
+}
+
+// 槽位的生成结果，Questions 为通过校验的题目，Rejected 按原因统计丢弃的题目数
+type SlotFill struct {
+	Slot      BlueprintSlot
+	Questions []*GeneratedQuestion
+	Rejected  map[string]int
+	Message   string // 未满足时的说明
+}
+
+func (f *SlotFill) Satisfied() bool {
+	return len(f.Questions) >= f.Slot.Count
+}
+
+// ValidateBlueprint 校验蓝图的槽位数、题目总数、题型与难度，并拒绝重复的槽位
+func ValidateBlueprint(slots []BlueprintSlot) error {
+	if len(slots) > MaxBlueprintSlots {
+		return fmt.Errorf("蓝图最多 %d 个槽位", MaxBlueprintSlots)
+	}
+	total := 0
+	seen := map[string]int{}
+	for i, slot := range slots {
+		if slot.Count <= 0 {
+			return fmt.Errorf("第 %d 个槽位的题目数量必须大于 0", i+1)
+		}
+		if slot.Type < models.MultipleChoice || slot.Type > models.Essay {
+			return fmt.Errorf("第 %d 个槽位的题型无效", i+1)
+		}
+		if slot.Difficulty < models.Easy || slot.Difficulty > models.Hard {
+			return fmt.Errorf("第 %d 个槽位的难度无效", i+1)
+		}
+		key := fmt.Sprintf("%d|%d|%s", slot.Type, slot.Difficulty, normalizeKnowledgePoint(slot.KnowledgePoint))
+		if j, ok := seen[key]; ok {
+			return fmt.Errorf("第 %d 个槽位与第 %d 个槽位重复", i+1, j+1)
+		}
+		seen[key] = i
+		total += slot.Count
+	}
+	if total > MaxBlueprintQuestions {
+		return fmt.Errorf("蓝图题目总数最多为 %d，当前为 %d", MaxBlueprintQuestions, total)
+	}
+	return nil
+}
+
+// GenerateFromBlueprint 按蓝图逐个槽位出题：材料内容只获取一次，每个槽位生成后校验题型、难度与知识点，
+// 不满足的题目丢弃并补充生成，至多尝试 blueprintMaxAttempts 次，返回与 slots 一一对应的完成情况
+func (s *QuizService) GenerateFromBlueprint(ctx context.Context, req *QuestionGenerationRequest, slots []BlueprintSlot) ([]SlotFill, error) {
+	s.logger.Infof("开始按蓝图生成题目，材料ID: %s, 用户ID: %s, 槽位数: %d", req.MaterialID, req.UserID, len(slots))
+
+	// 知识点由蓝图给出，不再从材料中提取
+	var points []string
+	for _, slot := range slots {
+		if slot.KnowledgePoint != "" {
+			points = append(points, slot.KnowledgePoint)
+		}
+	}
+	req.KnowledgePoints = points
+	if err := s.prepareMaterial(ctx, req); err != nil {
+		return nil, err
+	}
+
+	fills := make([]SlotFill, len(slots))
+	filled, requested := 0, 0
+	seen := map[string]bool{}
+	for i, slot := range slots {
+		fills[i] = s.fillSlot(ctx, req, slot, seen)
+		filled += len(fills[i].Questions)
+		requested += slot.Count
+	}
+
+	s.logger.Infof("按蓝图生成题目完成，满足 %d/%d 道", filled, requested)
+	return fills, nil
+}
+
+// 生成单个槽位的题目，直到数量满足或尝试次数用尽；seen 为整份蓝图已保留题目的题干，用于去重
+func (s *QuizService) fillSlot(ctx context.Context, req *QuestionGenerationRequest, slot BlueprintSlot, seen map[string]bool) SlotFill {
+	fill := SlotFill{Slot: slot, Rejected: map[string]int{}}
+
+	slotReq := *req
+	slotReq.Difficulty = slot.Difficulty
+	slotReq.KnowledgePoints = nil
+	slotReq.RequiredKnowledgePoint = slot.KnowledgePoint
+
+	var lastErr error
+	for attempt := 0; attempt < blueprintMaxAttempts && !fill.Satisfied(); attempt++ {
+		need := slot.Count - len(fill.Questions)
+		generated, err := s.generateQuestionsByType(ctx, &slotReq, slot.Type, need)
+		if err != nil {
+			s.logger.Errorf("蓝图槽位（%s/%s/%s）生成题目失败: %v", slot.Type.String(), slot.KnowledgePoint, slot.Difficulty.String(), err)
+			lastErr = err
+			continue
+		}
+		s.attachCitations(&slotReq, generated)
+		s.estimator.Apply(ctx, generated, slot.Difficulty, req.UserID)
+
+		for _, q := range generated {
+			if fill.Satisfied() {
+				break
+			}
+			if reason := slot.reject(q); reason != "" {
+				fill.Rejected[reason]++
+				continue
+			}
+			key := normalizeKnowledgePoint(q.Content)
+			if seen[key] {
+				fill.Rejected[RejectDuplicate]++
+				continue
+			}
+			seen[key] = true
+			fill.Questions = append(fill.Questions, q)
+		}
+	}
+
+	if !fill.Satisfied() {
+		fill.Message = fmt.Sprintf("仅生成 %d/%d 道符合要求的题目", len(fill.Questions), slot.Count)
+		if len(fill.Rejected) > 0 {
+			fill.Message += fmt.Sprintf("，%d 道因不符合槽位要求被丢弃", sumCounts(fill.Rejected))
+		}
+		if lastErr != nil {
+			fill.Message += "；最近一次生成失败: " + lastErr.Error()
+		}
+	}
+	return fill
+}
+
+// 返回题目不符合槽位要求的原因，符合时返回空串；知识点匹配时将槽位知识点放在题目知识点首位
+func (slot BlueprintSlot) reject(q *GeneratedQuestion) string {
+	if strings.TrimSpace(q.Content) == "" || (strings.TrimSpace(q.CorrectAnswer) == "" && len(q.Parts) == 0) {
+		return RejectIncomplete
+	}
+	if slot.Type == models.MultipleChoice && len(q.Options) < 2 {
+		return RejectIncomplete
+	}
+	// 难度估计开启覆盖时 Difficulty 为估计难度
+	if q.Difficulty != slot.Difficulty {
+		return RejectDifficultyMismatch
+	}
+	if slot.KnowledgePoint == "" {
+		return ""
+	}
+	want := normalizeKnowledgePoint(slot.KnowledgePoint)
+	for i, point := range q.KnowledgePoints {
+		got := normalizeKnowledgePoint(point)
+		if got == "" || (!strings.Contains(got, want) && !strings.Contains(want, got)) {
+			continue
+		}
+		points := []string{slot.KnowledgePoint}
+		for j, other := range q.KnowledgePoints {
+			if j != i {
+				points = append(points, other)
+			}
+		}
+		q.KnowledgePoints = points
+		return ""
+	}
+	return RejectKnowledgePointMismatch
+}
+
+// 知识点（与题干去重）比较时忽略大小写与空白
+func normalizeKnowledgePoint(s string) string {
+	return strings.ToLower(strings.Join(strings.Fields(s), ""))
+}
+
+func sumCounts(counts map[string]int) int {
+	n := 0
+	for _, c := range counts {
+		n += c
+	}
+	return n
+}
@@ -0,0 +1,198 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	llmPb "github.com/RigelNana/arkstudy/proto/llm"
+
+	"github.com/RigelNana/arkstudy/quiz-service/models"
+)
+
+// 出题时提供给模型的材料总长度上限（字节）
+const maxMaterialContentLen = 4000
+
+// 引用中保留的片段开头长度（字符）
+const citationSnippetRunes = 80
+
+// 材料片段，ID 为提示词中的编号（S1、S2...），模型通过 source_ids 引用
+type MaterialPassage struct {
+	ID         string
+	ChunkID    string
+	MaterialID string
+	Page       int
+	Timecode   string
+	Language   string // 分片标注的材料语言，早期分片没有该字段
+	Source     string // 来源材料标题，多材料出题时标注在片段编号后
+	Content    string
+}
+
+// 将检索结果转换为材料片段，页码、时间码与分片 ID 取自检索结果的 metadata
+func passagesFromResults(results []*llmPb.SearchResult) []MaterialPassage {
+	var passages []MaterialPassage
+	for _, r := range results {
+		if strings.TrimSpace(r.Content) == "" {
+			continue
+		}
+		page, _ := strconv.Atoi(r.Metadata["page"])
+		passages = append(passages, MaterialPassage{
+			ID:         fmt.Sprintf("S%d", len(passages)+1),
+			ChunkID:    r.Metadata["chunk_id"],
+			MaterialID: r.MaterialId,
+			Page:       page,
+			Timecode:   r.Metadata["timecode"],
+			Language:   r.Metadata["language"],
+			Content:    r.Content,
+		})
+	}
+	return passages
+}
+
+// 按片段截断到总长度上限；第一个片段过长时截断其内容，保证至少保留一个片段
+func limitPassages(passages []MaterialPassage, limit int) []MaterialPassage {
+	total := 0
+	for i, p := range passages {
+		total += len(p.Content)
+		if total <= limit {
+			continue
+		}
+		if i == 0 {
+			// 在字符边界处截断，避免截断多字节字符
+			cut := limit
+			for cut > 0 && !utf8.RuneStart(p.Content[cut]) {
+				cut--
+			}
+			p.Content = p.Content[:cut] + "..."
+			return []MaterialPassage{p}
+		}
+		return passages[:i]
+	}
+	return passages
+}
+
+// 将片段拼接为提示词中的材料内容，每段以编号和出处开头，如 "[S1]（第12页）"
+func formatPassages(passages []MaterialPassage) string {
+	parts := make([]string, 0, len(passages))
+	for _, p := range passages {
+		header := "[" + p.ID + "]"
+		if p.Source != "" {
+			header += "《" + p.Source + "》"
+		}
+		if loc := passageLocation(p.Page, p.Timecode); loc != "" {
+			header += "（" + loc + "）"
+		}
+		parts = append(parts, header+"\n"+p.Content)
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+func passageLocation(page int, timecode string) string {
+	if page > 0 {
+		return fmt.Sprintf("第%d页", page)
+	}
+	if timecode != "" {
+		return timecode
+	}
+	return ""
+}
+
+// 根据模型返回的 source_ids 解析题目引用；未给出或编号无效时，
+// 退化为选取与题干、答案、解析文本重合度最高的片段
+func resolveCitations(q *GeneratedQuestion, passages []MaterialPassage) []models.QuestionCitation {
+	if len(passages) == 0 {
+		return nil
+	}
+	byID := make(map[string]MaterialPassage, len(passages))
+	for _, p := range passages {
+		byID[strings.ToUpper(p.ID)] = p
+	}
+
+	var citations []models.QuestionCitation
+	seen := map[string]bool{}
+	for _, id := range q.SourceIDs {
+		id = strings.ToUpper(strings.Trim(strings.TrimSpace(id), "[]"))
+		p, ok := byID[id]
+		if !ok || seen[id] {
+			continue
+		}
+		seen[id] = true
+		citations = append(citations, toCitation(p))
+	}
+	if len(citations) > 0 {
+		return citations
+	}
+
+	if best, ok := bestMatchingPassage(q.Content+" "+q.CorrectAnswer+" "+q.Explanation, passages); ok {
+		return []models.QuestionCitation{toCitation(best)}
+	}
+	return nil
+}
+
+func toCitation(p MaterialPassage) models.QuestionCitation {
+	return models.QuestionCitation{
+		ChunkID:       p.ChunkID,
+		MaterialID:    p.MaterialID,
+		MaterialTitle: p.Source,
+		Page:          p.Page,
+		Timecode:      p.Timecode,
+		Snippet:       truncateRunes(strings.Join(strings.Fields(p.Content), " "), citationSnippetRunes),
+	}
+}
+
+// 按字符二元组重合数选取最相关的片段，对中文无需分词
+func bestMatchingPassage(text string, passages []MaterialPassage) (MaterialPassage, bool) {
+	grams := bigrams(text)
+	if len(grams) == 0 {
+		return MaterialPassage{}, false
+	}
+	bestScore, bestIdx := 0, -1
+	for i, p := range passages {
+		score := 0
+		for g := range bigrams(p.Content) {
+			if grams[g] {
+				score++
+			}
+		}
+		if score > bestScore {
+			bestScore, bestIdx = score, i
+		}
+	}
+	if bestIdx < 0 {
+		return MaterialPassage{}, false
+	}
+	return passages[bestIdx], true
+}
+
+func bigrams(s string) map[string]bool {
+	var runes []rune
+	for _, r := range strings.ToLower(s) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			runes = append(runes, r)
+		}
+	}
+	grams := make(map[string]bool, len(runes))
+	for i := 0; i+1 < len(runes); i++ {
+		grams[string(runes[i:i+2])] = true
+	}
+	return grams
+}
+
+func truncateRunes(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return string([]rune(s)[:n])
+}
+
+// 序列化题目引用
+func marshalCitations(citations []models.QuestionCitation) string {
+	if len(citations) == 0 {
+		return ""
+	}
+	data, _ := json.Marshal(citations)
+	return string(data)
+}
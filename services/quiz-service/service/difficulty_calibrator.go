@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/RigelNana/arkstudy/quiz-service/models"
+	"github.com/RigelNana/arkstudy/quiz-service/repository"
+)
+
+// 难度校准器：根据题目实际作答正确率定期调整存储的难度
+type DifficultyCalibrator struct {
+	repo          *repository.QuizRepository
+	logger        *logrus.Logger
+	interval      time.Duration
+	minAttempts   int
+	easyThreshold float32 // 正确率高于该值视为简单
+	hardThreshold float32 // 正确率低于该值视为困难
+}
+
+func NewDifficultyCalibrator(repo *repository.QuizRepository, logger *logrus.Logger, interval time.Duration, minAttempts int, easyThreshold, hardThreshold float32) *DifficultyCalibrator {
+	return &DifficultyCalibrator{
+		repo:          repo,
+		logger:        logger,
+		interval:      interval,
+		minAttempts:   minAttempts,
+		easyThreshold: easyThreshold,
+		hardThreshold: hardThreshold,
+	}
+}
+
+// 最少作答次数，低于该值的统计不参与校准
+func (c *DifficultyCalibrator) MinAttempts() int {
+	return c.minAttempts
+}
+
+// 根据正确率推算难度
+func (c *DifficultyCalibrator) EstimateDifficulty(successRate float32) models.DifficultyLevel {
+	switch {
+	case successRate >= c.easyThreshold:
+		return models.Easy
+	case successRate < c.hardThreshold:
+		return models.Hard
+	default:
+		return models.Medium
+	}
+}
+
+// 定期执行校准，直到 ctx 取消
+func (c *DifficultyCalibrator) Start(ctx context.Context) {
+	if c.interval <= 0 {
+		c.logger.Info("难度校准任务未启用")
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(c.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				updated, err := c.RunOnce(ctx)
+				if err != nil {
+					c.logger.Errorf("难度校准失败: %v", err)
+					continue
+				}
+				c.logger.Infof("难度校准完成，调整了 %d 道题目", updated)
+			}
+		}
+	}()
+}
+
+// 执行一次校准，返回调整难度的题目数量
+func (c *DifficultyCalibrator) RunOnce(ctx context.Context) (int, error) {
+	candidates, err := c.repo.ListCalibrationCandidates(c.minAttempts)
+	if err != nil {
+		return 0, err
+	}
+
+	ids := make([]string, len(candidates))
+	for i, st := range candidates {
+		ids[i] = st.QuestionID
+	}
+	questions, err := c.repo.GetQuestionsByIDs(ids)
+	if err != nil {
+		return 0, err
+	}
+	current := make(map[string]models.DifficultyLevel, len(questions))
+	for _, q := range questions {
+		current[q.QuestionID] = q.Difficulty
+	}
+
+	updated := 0
+	for _, st := range candidates {
+		if ctx.Err() != nil {
+			return updated, ctx.Err()
+		}
+		difficulty, ok := current[st.QuestionID]
+		if !ok {
+			continue
+		}
+		estimated := c.EstimateDifficulty(st.SuccessRate())
+		if estimated == difficulty {
+			continue
+		}
+		if err := c.repo.UpdateQuestionDifficulty(st.QuestionID, estimated); err != nil {
+			c.logger.Errorf("更新题目 %s 难度失败: %v", st.QuestionID, err)
+			continue
+		}
+		c.logger.Infof("题目 %s 难度由 %s 调整为 %s（正确率 %.2f，作答 %d 次）",
+			st.QuestionID, difficulty.String(), estimated.String(), st.SuccessRate(), st.AttemptCount)
+		updated++
+	}
+
+	return updated, nil
+}
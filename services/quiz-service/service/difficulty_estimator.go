@@ -0,0 +1,162 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/RigelNana/arkstudy/quiz-service/models"
+)
+
+// 难度估计方式
+const (
+	DifficultyEstimatorOff       = "off"
+	DifficultyEstimatorHeuristic = "heuristic"
+	DifficultyEstimatorLLM       = "llm"
+)
+
+// 出题难度估计参数
+type DifficultyOptions struct {
+	Estimator string // off / heuristic / llm，llm 调用失败时回退到 heuristic
+	Override  bool   // 估计难度与请求难度不一致时，是否以估计难度作为题目难度
+}
+
+// 难度估计器：根据题目文本估计每道生成题目的实际难度，校验请求中指定的难度。
+// 与 DifficultyCalibrator 不同，估计发生在出题时，无需作答数据
+type DifficultyEstimator struct {
+	opts      DifficultyOptions
+	llmClient *LLMServiceClient
+	logger    *logrus.Logger
+}
+
+func NewDifficultyEstimator(opts DifficultyOptions, llmClient *LLMServiceClient, logger *logrus.Logger) *DifficultyEstimator {
+	if opts.Estimator == "" {
+		opts.Estimator = DifficultyEstimatorHeuristic
+	}
+	return &DifficultyEstimator{opts: opts, llmClient: llmClient, logger: logger}
+}
+
+// 为题目填充请求难度与估计难度，并按配置决定最终难度
+func (e *DifficultyEstimator) Apply(ctx context.Context, questions []*GeneratedQuestion, requested models.DifficultyLevel, userID string) {
+	for _, q := range questions {
+		q.RequestedDifficulty = requested
+		q.Difficulty = requested
+	}
+	if e.opts.Estimator == DifficultyEstimatorOff || len(questions) == 0 {
+		return
+	}
+
+	var estimated []models.DifficultyLevel
+	source := DifficultyEstimatorHeuristic
+	if e.opts.Estimator == DifficultyEstimatorLLM && e.llmClient != nil {
+		levels, err := e.llmClient.EstimateQuestionDifficulty(ctx, questions, userID)
+		if err != nil {
+			e.logger.Errorf("LLM估计题目难度失败，回退到启发式估计: %v", err)
+		} else {
+			estimated, source = levels, DifficultyEstimatorLLM
+		}
+	}
+	if estimated == nil {
+		estimated = make([]models.DifficultyLevel, len(questions))
+		for i, q := range questions {
+			estimated[i] = EstimateDifficultyHeuristic(q)
+		}
+	}
+
+	mismatched := 0
+	for i, q := range questions {
+		q.EstimatedDifficulty = estimated[i]
+		q.DifficultySource = source
+		if estimated[i] == requested {
+			continue
+		}
+		mismatched++
+		if e.opts.Override {
+			q.Difficulty = estimated[i]
+		}
+	}
+	if mismatched > 0 {
+		e.logger.Infof("%d/%d 道题目的估计难度与请求难度 %s 不一致（估计方式: %s，覆盖: %v）",
+			mismatched, len(questions), requested.String(), source, e.opts.Override)
+	}
+}
+
+// 考查高阶认知（分析、评价、创造）的提示词
+var analyticalCues = []string{"分析", "比较", "对比", "为什么", "原因", "推导", "证明", "评价", "论述", "设计", "如何", "影响", "区别", "综合", "解释", "explain", "why", "compare", "analy", "derive", "prove", "evaluate"}
+
+// 考查记忆与识别的提示词
+var recallCues = []string{"是什么", "定义", "称为", "叫做", "下列哪", "以下哪", "属于", "是否", "what is", "which of", "define"}
+
+// 根据题型、题干长度、认知层次提示词、分项数量等文本特征估计难度
+func EstimateDifficultyHeuristic(q *GeneratedQuestion) models.DifficultyLevel {
+	score := 0.0
+	switch q.Type {
+	case models.TrueFalse:
+		score -= 1
+	case models.ShortAnswer:
+		score += 1
+	case models.Essay:
+		score += 2
+	}
+
+	length := utf8.RuneCountInString(q.Content)
+	switch {
+	case length > 120:
+		score += 1
+	case length < 25:
+		score -= 0.5
+	}
+
+	text := strings.ToLower(q.Content)
+	if containsAny(text, analyticalCues) {
+		score += 1
+	}
+	if containsAny(text, recallCues) {
+		score -= 1
+	}
+
+	// 多空题、多知识点综合、涉及数值计算的题目更难
+	if len(q.Parts) > 1 {
+		score += 0.5
+	}
+	if len(q.KnowledgePoints) >= 3 {
+		score += 0.5
+	}
+	if q.NumericTolerance > 0 || strings.ContainsAny(q.Content, "=+×÷^√∑∫") {
+		score += 0.5
+	}
+
+	switch {
+	case score <= -1:
+		return models.Easy
+	case score >= 1.5:
+		return models.Hard
+	default:
+		return models.Medium
+	}
+}
+
+func containsAny(text string, cues []string) bool {
+	for _, c := range cues {
+		if strings.Contains(text, c) {
+			return true
+		}
+	}
+	return false
+}
+
+// 解析难度名称，支持英文与中文
+func parseDifficultyName(s string) (models.DifficultyLevel, bool) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "easy", "简单":
+		return models.Easy, true
+	case "medium", "中等":
+		return models.Medium, true
+	case "hard", "困难":
+		return models.Hard, true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,105 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+
+	"github.com/RigelNana/arkstudy/quiz-service/models"
+	"github.com/RigelNana/arkstudy/quiz-service/repository"
+)
+
+// 评分策略服务：按 材料 > 课程 > 全局 的顺序解析生效的评分策略，均未配置时使用配置文件中的默认值
+type GradingService struct {
+	repo     *repository.QuizRepository
+	defaults models.GradingPolicy
+	logger   *logrus.Logger
+}
+
+func NewGradingService(repo *repository.QuizRepository, defaults models.GradingPolicy, logger *logrus.Logger) *GradingService {
+	defaults.ScopeType = models.PolicyScopeGlobal
+	if defaults.PartialCreditMode == "" {
+		defaults.PartialCreditMode = models.PartialCreditWeighted
+	}
+	return &GradingService{
+		repo:     repo,
+		defaults: defaults,
+		logger:   logger,
+	}
+}
+
+// 解析材料与课程生效的评分策略，个人题目的 courseID 为空
+func (s *GradingService) Resolve(materialID, courseID string) *models.GradingPolicy {
+	if materialID != "" {
+		policy, err := s.lookup(models.PolicyScopeMaterial, materialID)
+		if err != nil {
+			s.logger.Errorf("查询材料 %s 评分策略失败: %v", materialID, err)
+		} else if policy != nil {
+			return policy
+		}
+	}
+	if courseID != "" {
+		policy, err := s.lookup(models.PolicyScopeCourse, courseID)
+		if err != nil {
+			s.logger.Errorf("查询课程 %s 评分策略失败: %v", courseID, err)
+		} else if policy != nil {
+			return policy
+		}
+	}
+
+	policy, err := s.lookup(models.PolicyScopeGlobal, "")
+	if err != nil {
+		s.logger.Errorf("查询全局评分策略失败: %v", err)
+	} else if policy != nil {
+		return policy
+	}
+
+	defaults := s.defaults
+	return &defaults
+}
+
+func (s *GradingService) lookup(scopeType, scopeID string) (*models.GradingPolicy, error) {
+	policy, err := s.repo.GetGradingPolicy(scopeType, scopeID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return policy, err
+}
+
+// 校验并保存评分策略
+func (s *GradingService) Save(policy *models.GradingPolicy) error {
+	switch policy.ScopeType {
+	case models.PolicyScopeGlobal:
+		policy.ScopeID = ""
+	case models.PolicyScopeMaterial:
+		if policy.ScopeID == "" {
+			return fmt.Errorf("材料ID不能为空")
+		}
+	case models.PolicyScopeCourse:
+		if policy.ScopeID == "" {
+			return fmt.Errorf("课程ID不能为空")
+		}
+	default:
+		return fmt.Errorf("不支持的策略范围: %s", policy.ScopeType)
+	}
+
+	// 及格线为 0 时任何作答都判为正确
+	if policy.PassThreshold <= 0 || policy.PassThreshold > 1 {
+		return fmt.Errorf("及格线必须大于0且不超过1")
+	}
+	if policy.RetryPenalty < 0 || policy.RetryPenalty > 1 || policy.MaxRetryPenalty < 0 || policy.MaxRetryPenalty > 1 {
+		return fmt.Errorf("重复作答扣分比例必须在0到1之间")
+	}
+
+	switch policy.PartialCreditMode {
+	case "":
+		policy.PartialCreditMode = models.PartialCreditWeighted
+	case models.PartialCreditWeighted, models.PartialCreditEqual, models.PartialCreditAllOrNothing:
+	default:
+		return fmt.Errorf("不支持的部分得分方式: %s", policy.PartialCreditMode)
+	}
+
+	return s.repo.UpsertGradingPolicy(policy)
+}
@@ -2,40 +2,79 @@ package service
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"strconv"
 	"strings"
 
+	"github.com/RigelNana/arkstudy/pkg/discovery"
+	"github.com/RigelNana/arkstudy/pkg/registry"
 	"github.com/sirupsen/logrus"
-	"google.golang.org/grpc"
 
 	llmPb "github.com/RigelNana/arkstudy/proto/llm"
+
+	"github.com/RigelNana/arkstudy/quiz-service/models"
 )
 
 type LLMServiceClient struct {
-	client llmPb.LLMServiceClient
-	logger *logrus.Logger
+	client    llmPb.LLMServiceClient
+	retrieval RetrievalOptions
+	logger    *logrus.Logger
+}
+
+// RetrievalOptions 出题调用 AskQuestion 时的检索参数，经 context 透传给 llm-service，零值表示使用默认值
+type RetrievalOptions struct {
+	TopK                int
+	SimilarityThreshold float32
+}
+
+func (o RetrievalOptions) applyTo(ctx map[string]string) {
+	if o.TopK > 0 {
+		ctx["top_k"] = strconv.Itoa(o.TopK)
+	}
+	if o.SimilarityThreshold > 0 {
+		ctx["similarity_threshold"] = strconv.FormatFloat(float64(o.SimilarityThreshold), 'f', -1, 32)
+	}
 }
 
-func NewLLMServiceClient(llmServiceAddr string, logger *logrus.Logger) (*LLMServiceClient, error) {
-	conn, err := grpc.Dial(llmServiceAddr, grpc.WithInsecure())
+func NewLLMServiceClient(llmServiceAddr string, retrieval RetrievalOptions, logger *logrus.Logger) (*LLMServiceClient, error) {
+	conn, err := discovery.DialAddr(registry.LLM.Name, llmServiceAddr)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to LLM service: %v", err)
 	}
 
 	client := llmPb.NewLLMServiceClient(conn)
 	return &LLMServiceClient{
-		client: client,
-		logger: logger,
+		client:    client,
+		retrieval: retrieval,
+		logger:    logger,
 	}, nil
 }
 
-// 获取材料内容，用于出题
-func (c *LLMServiceClient) GetMaterialContent(ctx context.Context, materialID, userID string) (string, error) {
+// 检索出题材料片段的查询词
+const materialContentQuery = "深度学习 机器学习 神经网络 算法"
+
+// 获取单个材料的检索片段，不回退到广泛搜索、不截断，由多材料出题按来源均衡抽样
+func (c *LLMServiceClient) GetSourcePassages(ctx context.Context, materialID, userID string) ([]MaterialPassage, error) {
+	resp, err := c.client.SemanticSearch(ctx, &llmPb.SearchRequest{
+		Query:       materialContentQuery,
+		UserId:      userID,
+		TopK:        50,
+		MaterialIds: []string{materialID},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to search material content: %v", err)
+	}
+	return passagesFromResults(resp.Results), nil
+}
+
+// 获取材料片段，用于出题；片段保留分片 ID、页码与时间码，以便为题目标注出处
+func (c *LLMServiceClient) GetMaterialContent(ctx context.Context, materialID, userID string) ([]MaterialPassage, error) {
 	c.logger.Infof("获取材料内容，材料ID: %s, 用户ID: %s", materialID, userID)
 
 	// 策略1: 使用material_ids精确查找指定材料
 	searchReq := &llmPb.SearchRequest{
-		Query:       "深度学习 机器学习 神经网络 算法", // 使用更具体的查询词汇
+		Query:       materialContentQuery,
 		UserId:      userID,
 		TopK:        50,
 		MaterialIds: []string{materialID}, // 精确指定material_id
@@ -43,17 +82,14 @@ func (c *LLMServiceClient) GetMaterialContent(ctx context.Context, materialID, u
 
 	resp, err := c.client.SemanticSearch(ctx, searchReq)
 	if err != nil {
-		return "", fmt.Errorf("failed to search material content: %v", err)
+		return nil, fmt.Errorf("failed to search material content: %v", err)
 	}
 
 	// 收集内容片段
-	var contentParts []string
-	for _, result := range resp.Results {
-		contentParts = append(contentParts, result.Content)
-	}
+	passages := passagesFromResults(resp.Results)
 
 	// 如果没有找到指定material_id的内容，尝试策略2: 不限制material_id的广泛搜索
-	if len(contentParts) == 0 {
+	if len(passages) == 0 {
 		c.logger.Infof("未找到material_id=%s的内容，尝试广泛搜索", materialID)
 
 		// 策略2: 使用语义搜索该用户的所有内容
@@ -61,27 +97,22 @@ func (c *LLMServiceClient) GetMaterialContent(ctx context.Context, materialID, u
 		searchReq.Query = "学习 内容 知识 材料"
 		resp, err = c.client.SemanticSearch(ctx, searchReq)
 		if err != nil {
-			return "", fmt.Errorf("failed to search with broad query: %v", err)
+			return nil, fmt.Errorf("failed to search with broad query: %v", err)
 		}
 
 		// 收集所有相关内容
-		for _, result := range resp.Results {
-			contentParts = append(contentParts, result.Content)
-		}
+		passages = passagesFromResults(resp.Results)
 	}
 
-	if len(contentParts) == 0 {
-		return "", fmt.Errorf("no content found for user %s", userID)
+	if len(passages) == 0 {
+		return nil, fmt.Errorf("no content found for user %s", userID)
 	}
 
-	// 合并内容，限制总长度
-	fullContent := strings.Join(contentParts, "\n\n")
-	if len(fullContent) > 4000 {
-		fullContent = fullContent[:4000] + "..."
-	}
+	// 限制总长度，按片段截断以保证编号与内容对应
+	passages = limitPassages(passages, maxMaterialContentLen)
 
-	c.logger.Infof("获取到材料内容，片段数: %d, 总长度: %d 字符", len(contentParts), len(fullContent))
-	return fullContent, nil
+	c.logger.Infof("获取到材料内容，片段数: %d", len(passages))
+	return passages, nil
 }
 
 // 使用LLM进行智能出题
@@ -97,8 +128,11 @@ func (c *LLMServiceClient) GenerateQuestionsWithLLM(ctx context.Context, materia
 		Context: map[string]string{
 			"task": "question_generation",
 			"type": "educational",
+			// 同一材料多次出题应得到不同题目，不使用答案缓存
+			"no_cache": "true",
 		},
 	}
+	c.retrieval.applyTo(questionReq.Context)
 
 	resp, err := c.client.AskQuestion(ctx, questionReq)
 	if err != nil {
@@ -109,6 +143,67 @@ func (c *LLMServiceClient) GenerateQuestionsWithLLM(ctx context.Context, materia
 	return resp.Answer, nil
 }
 
+// 批量估计题目难度，返回与 questions 一一对应的难度
+func (c *LLMServiceClient) EstimateQuestionDifficulty(ctx context.Context, questions []*GeneratedQuestion, userID string) ([]models.DifficultyLevel, error) {
+	c.logger.Infof("估计题目难度，题目数: %d, 用户ID: %s", len(questions), userID)
+
+	var list strings.Builder
+	for i, q := range questions {
+		fmt.Fprintf(&list, "%d. %s\n", i+1, q.Content)
+		if len(q.Options) > 0 {
+			fmt.Fprintf(&list, "   选项：%s\n", strings.Join(q.Options, "；"))
+		}
+		fmt.Fprintf(&list, "   答案：%s\n", q.CorrectAnswer)
+	}
+
+	prompt := fmt.Sprintf(`请评估以下每道题目对学生的难度，分为 easy（基础概念记忆与理解）、medium（概念应用）、hard（深度分析与综合运用）三级：
+
+%s
+请按题目顺序返回，且只返回如下JSON格式：
+{
+  "difficulties": ["easy", "medium"]
+}`, list.String())
+
+	questionReq := &llmPb.QuestionRequest{
+		Question: prompt,
+		UserId:   userID,
+		Context: map[string]string{
+			"task":     "difficulty_estimation",
+			"type":     "educational",
+			"no_cache": "true",
+		},
+	}
+
+	resp, err := c.client.AskQuestion(ctx, questionReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to estimate difficulty with LLM: %v", err)
+	}
+
+	start, end := strings.Index(resp.Answer, "{"), strings.LastIndex(resp.Answer, "}")
+	if start == -1 || end < start {
+		return nil, fmt.Errorf("no JSON found in difficulty estimation response")
+	}
+	var result struct {
+		Difficulties []string `json:"difficulties"`
+	}
+	if err := json.Unmarshal([]byte(resp.Answer[start:end+1]), &result); err != nil {
+		return nil, fmt.Errorf("failed to parse difficulty estimation response: %v", err)
+	}
+	if len(result.Difficulties) != len(questions) {
+		return nil, fmt.Errorf("difficulty estimation returned %d levels for %d questions", len(result.Difficulties), len(questions))
+	}
+
+	levels := make([]models.DifficultyLevel, len(questions))
+	for i, name := range result.Difficulties {
+		level, ok := parseDifficultyName(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown difficulty %q in estimation response", name)
+		}
+		levels[i] = level
+	}
+	return levels, nil
+}
+
 // 评估主观题答案
 func (c *LLMServiceClient) EvaluateSubjectiveAnswer(ctx context.Context, question, correctAnswer, userAnswer, userID string) (float32, string, error) {
 	c.logger.Infof("评估主观题答案，用户ID: %s", userID)
@@ -150,6 +245,22 @@ func (c *LLMServiceClient) EvaluateSubjectiveAnswer(ctx context.Context, questio
 	return score, feedback, nil
 }
 
+// 计算文本向量，不写入向量库；返回与 texts 一一对应的向量及生成向量的模型
+func (c *LLMServiceClient) EmbedTexts(ctx context.Context, texts []string, language string) ([][]float32, string, error) {
+	resp, err := c.client.EmbedTexts(ctx, &llmPb.EmbedTextsRequest{Texts: texts, Language: language})
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to embed texts: %v", err)
+	}
+	if len(resp.Embeddings) != len(texts) {
+		return nil, "", fmt.Errorf("expected %d embeddings, got %d", len(texts), len(resp.Embeddings))
+	}
+	vectors := make([][]float32, len(resp.Embeddings))
+	for i, e := range resp.Embeddings {
+		vectors[i] = e.Values
+	}
+	return vectors, resp.Model, nil
+}
+
 // 提取知识点
 func (c *LLMServiceClient) ExtractKnowledgePoints(ctx context.Context, content, userID string) ([]string, error) {
 	c.logger.Infof("提取知识点，内容长度: %d", len(content))
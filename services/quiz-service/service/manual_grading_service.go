@@ -0,0 +1,228 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/RigelNana/arkstudy/quiz-service/models"
+	"github.com/RigelNana/arkstudy/quiz-service/repository"
+)
+
+// 单次批量评分最多处理的作答数
+const maxGradingBatch = 200
+
+var ErrGradingStateChanged = errors.New("作答已被评分，请刷新后重试")
+
+// 单条作答的评分：Accept 为 true 时采纳 AI 建议得分，否则以 Score（扣除重复作答惩罚前）为准
+type AnswerGrade struct {
+	AnswerID string
+	Accept   bool
+	Score    float32
+	Comment  string
+}
+
+// 批量评分中未处理的作答及原因
+type GradingSkip struct {
+	AnswerID string
+	Reason   string
+}
+
+// 评分队列中的作答及其题目
+type GradingQueueItem struct {
+	Answer   *models.UserAnswer
+	Question *models.Question
+}
+
+// 主观题人工评分服务：简答题与论述题提交时记录 AI 建议得分并进入评分队列，由题目创建者或课程审核人确认或改分
+type ManualGradingService struct {
+	repo           *repository.QuizRepository
+	gradingService *GradingService
+	logger         *logrus.Logger
+}
+
+func NewManualGradingService(repo *repository.QuizRepository, gradingService *GradingService, logger *logrus.Logger) *ManualGradingService {
+	return &ManualGradingService{repo: repo, gradingService: gradingService, logger: logger}
+}
+
+// 获取教师的评分队列
+func (s *ManualGradingService) ListQueue(teacherID string, filter repository.GradingQueueFilter, page, pageSize int) ([]GradingQueueItem, int64, error) {
+	if teacherID == "" {
+		return nil, 0, errors.New("用户ID不能为空")
+	}
+	answers, total, err := s.repo.ListGradingQueue(teacherID, filter, page, pageSize)
+	if err != nil {
+		return nil, 0, fmt.Errorf("获取评分队列失败: %w", err)
+	}
+	questions, err := s.questionsOf(answers)
+	if err != nil {
+		return nil, 0, err
+	}
+	items := make([]GradingQueueItem, 0, len(answers))
+	for _, a := range answers {
+		if q := questions[a.QuestionID]; q != nil {
+			items = append(items, GradingQueueItem{Answer: a, Question: q})
+		}
+	}
+	return items, total, nil
+}
+
+// 批量确认或修改主观题得分。不满足条件的作答跳过并返回原因，不影响其余作答；
+// 改分后按差值修正题目统计并重新排入答题事件以重算知识点统计。错题本按作答时的结果记录，不随改分回溯
+func (s *ManualGradingService) Grade(teacherID string, grades []AnswerGrade) ([]*models.UserAnswer, []GradingSkip, error) {
+	if teacherID == "" {
+		return nil, nil, errors.New("用户ID不能为空")
+	}
+	if len(grades) == 0 {
+		return nil, nil, errors.New("需要指定作答")
+	}
+	if len(grades) > maxGradingBatch {
+		return nil, nil, fmt.Errorf("单次最多处理 %d 条作答", maxGradingBatch)
+	}
+	ids := make([]string, len(grades))
+	for i, g := range grades {
+		ids[i] = g.AnswerID
+	}
+	answers, err := s.repo.GetUserAnswersByIDs(dedupe(ids))
+	if err != nil {
+		return nil, nil, fmt.Errorf("获取答题记录失败: %w", err)
+	}
+	byID := make(map[string]*models.UserAnswer, len(answers))
+	for _, a := range answers {
+		byID[a.AnswerID] = a
+	}
+	questions, err := s.questionsOf(answers)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var graded []*models.UserAnswer
+	var skipped []GradingSkip
+	seen := make(map[string]bool, len(grades))
+	for _, g := range grades {
+		if seen[g.AnswerID] {
+			continue
+		}
+		seen[g.AnswerID] = true
+		answer := byID[g.AnswerID]
+		var question *models.Question
+		if answer != nil {
+			question = questions[answer.QuestionID]
+		}
+		reason := ""
+		switch {
+		case answer == nil || question == nil:
+			reason = "答题记录不存在"
+		case !question.GradableBy(teacherID):
+			reason = "无权评阅该作答"
+		case answer.UserID == teacherID:
+			reason = "不能评阅本人的作答"
+		case answer.GradingStatus != models.GradingStatusPending:
+			reason = "作答不在待复核状态"
+		case !g.Accept && (g.Score < 0 || g.Score > 1):
+			reason = "得分需在 0 到 1 之间"
+		}
+		if reason == "" {
+			if err := s.apply(teacherID, answer, question, g); errors.Is(err, ErrGradingStateChanged) {
+				reason = err.Error()
+			} else if err != nil {
+				return nil, nil, err
+			}
+		}
+		if reason != "" {
+			skipped = append(skipped, GradingSkip{AnswerID: g.AnswerID, Reason: reason})
+			continue
+		}
+		graded = append(graded, answer)
+	}
+	s.logger.Infof("教师 %s 评分 %d 条作答，跳过 %d 条", teacherID, len(graded), len(skipped))
+	return graded, skipped, nil
+}
+
+// 在事务中保存单条作答的评分并修正统计，成功后更新 answer
+func (s *ManualGradingService) apply(teacherID string, answer *models.UserAnswer, question *models.Question, g AnswerGrade) error {
+	rawScore := answer.SuggestedScore
+	score, isCorrect := answer.Score, answer.IsCorrect
+	if !g.Accept {
+		// 与提交时一致：是否及格按原始得分判断，记录的得分扣除重复作答惩罚
+		policy := s.gradingService.Resolve(question.MaterialID, question.CourseID)
+		previous, err := s.repo.CountUserAttemptsBefore(answer.QuestionID, answer.UserID, answer.AnsweredAt)
+		if err != nil {
+			return fmt.Errorf("统计历史作答次数失败: %w", err)
+		}
+		rawScore = g.Score
+		score = policy.ApplyRetryPenalty(rawScore, int(previous))
+		isCorrect = rawScore >= policy.PassThreshold
+	}
+
+	now := time.Now()
+	comment := strings.TrimSpace(g.Comment)
+	err := s.repo.AsActor(teacherID).Transaction(func(txRepo *repository.QuizRepository) error {
+		n, err := txRepo.ApplyManualGrade(answer.AnswerID, map[string]interface{}{
+			"grading_status":  models.GradingStatusGraded,
+			"score":           score,
+			"is_correct":      isCorrect,
+			"graded_by":       teacherID,
+			"graded_at":       now,
+			"grading_comment": comment,
+		})
+		if err != nil {
+			return fmt.Errorf("保存评分失败: %w", err)
+		}
+		if n == 0 {
+			return ErrGradingStateChanged
+		}
+		if g.Accept {
+			return nil
+		}
+		correctDelta := 0
+		if isCorrect != answer.IsCorrect {
+			correctDelta = 1
+			if !isCorrect {
+				correctDelta = -1
+			}
+		}
+		if err := txRepo.AdjustQuestionStats(question.QuestionID, correctDelta, float64(rawScore-answer.SuggestedScore)); err != nil {
+			return fmt.Errorf("更新题目统计失败: %w", err)
+		}
+		if question.KnowledgePoints != "" && isCorrect != answer.IsCorrect {
+			event := &models.AnswerEvent{
+				AnswerID:        answer.AnswerID,
+				UserID:          answer.UserID,
+				QuestionID:      question.QuestionID,
+				MaterialID:      question.MaterialID,
+				KnowledgePoints: question.KnowledgePoints,
+			}
+			if err := txRepo.RequeueAnswerEvent(event); err != nil {
+				return fmt.Errorf("写入答题事件失败: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	answer.GradingStatus, answer.Score, answer.IsCorrect = models.GradingStatusGraded, score, isCorrect
+	answer.GradedBy, answer.GradedAt, answer.GradingComment = teacherID, &now, comment
+	return nil
+}
+
+// 读取答题记录对应的题目，按题目ID索引
+func (s *ManualGradingService) questionsOf(answers []*models.UserAnswer) (map[string]*models.Question, error) {
+	ids := make([]string, 0, len(answers))
+	for _, a := range answers {
+		ids = append(ids, a.QuestionID)
+	}
+	questions, err := s.repo.GetQuestionsByIDs(dedupe(ids))
+	if err != nil {
+		return nil, fmt.Errorf("获取题目失败: %w", err)
+	}
+	byID := make(map[string]*models.Question, len(questions))
+	for _, q := range questions {
+		byID[q.QuestionID] = q
+	}
+	return byID, nil
+}
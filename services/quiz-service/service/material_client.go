@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/RigelNana/arkstudy/pkg/discovery"
+	"github.com/RigelNana/arkstudy/pkg/registry"
+	"github.com/sirupsen/logrus"
+
+	materialPb "github.com/RigelNana/arkstudy/proto/material"
+)
+
+// 多材料出题的来源材料
+type SourceMaterial struct {
+	ID    string
+	Title string
+}
+
+// MaterialClient 多材料出题时校验材料归属、展开课程合集
+type MaterialClient struct {
+	client materialPb.MaterialServiceClient
+	logger *logrus.Logger
+}
+
+func NewMaterialClient(materialServiceAddr string, logger *logrus.Logger) (*MaterialClient, error) {
+	conn, err := discovery.DialAddr(registry.Material.Name, materialServiceAddr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to material service: %v", err)
+	}
+	return &MaterialClient{
+		client: materialPb.NewMaterialServiceClient(conn),
+		logger: logger,
+	}, nil
+}
+
+// 获取材料并校验归属
+func (c *MaterialClient) GetMaterial(ctx context.Context, materialID, userID string) (SourceMaterial, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	resp, err := c.client.GetMaterial(ctx, &materialPb.GetMaterialRequest{MaterialId: materialID, UserId: userID})
+	if err != nil {
+		return SourceMaterial{}, fmt.Errorf("failed to query material service: %v", err)
+	}
+	if !resp.Found {
+		return SourceMaterial{}, fmt.Errorf("material %s not found", materialID)
+	}
+	return SourceMaterial{ID: resp.Material.Id, Title: resp.Material.Title}, nil
+}
+
+// 列出课程合集（bundle 资料）中的子资料，按 material-service 返回的顺序
+func (c *MaterialClient) ListCollectionMaterials(ctx context.Context, collectionID, userID string) ([]SourceMaterial, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	resp, err := c.client.ListChildMaterials(ctx, &materialPb.ListChildMaterialsRequest{MaterialId: collectionID, UserId: userID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query material service: %v", err)
+	}
+	if !resp.Success {
+		return nil, fmt.Errorf("failed to list collection materials: %s", resp.Message)
+	}
+
+	materials := make([]SourceMaterial, 0, len(resp.Materials))
+	for _, m := range resp.Materials {
+		materials = append(materials, SourceMaterial{ID: m.Id, Title: m.Title})
+	}
+	return materials, nil
+}
@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// MaxQuizSources 单次出题最多使用的来源材料数
+const MaxQuizSources = 10
+
+// 多材料出题时提供给模型的材料总长度上限（字节），高于单材料出题以容纳多个来源
+const maxMultiSourceContentLen = 8000
+
+// 是否为多材料出题：指定了材料列表或课程合集
+func (r *QuestionGenerationRequest) multiSource() bool {
+	return len(r.MaterialIDs) > 0 || r.CollectionID != ""
+}
+
+// 合并 MaterialID、MaterialIDs 与课程合集中的材料，去重并校验归属
+func (s *QuizService) resolveSources(ctx context.Context, req *QuestionGenerationRequest) ([]SourceMaterial, error) {
+	if req.CollectionID != "" && s.materials == nil {
+		return nil, fmt.Errorf("材料服务不可用，无法按课程合集出题")
+	}
+
+	var sources []SourceMaterial
+	seen := map[string]bool{}
+	add := func(m SourceMaterial) {
+		if m.ID == "" || seen[m.ID] {
+			return
+		}
+		seen[m.ID] = true
+		sources = append(sources, m)
+	}
+
+	if req.CollectionID != "" {
+		children, err := s.materials.ListCollectionMaterials(ctx, req.CollectionID, req.UserID)
+		if err != nil {
+			s.logger.Errorf("获取课程合集材料失败: %v", err)
+			return nil, fmt.Errorf("无法获取课程合集中的材料: %v", err)
+		}
+		for _, m := range children {
+			add(m)
+		}
+	}
+	ids := req.MaterialIDs
+	if req.MaterialID != "" {
+		ids = append([]string{req.MaterialID}, ids...)
+	}
+	for _, id := range ids {
+		id = strings.TrimSpace(id)
+		if id == "" || seen[id] {
+			continue
+		}
+		// 材料服务不可用时仍可出题，llm-service 检索按 user_id 过滤，只是片段不带材料标题
+		if s.materials == nil {
+			add(SourceMaterial{ID: id})
+			continue
+		}
+		m, err := s.materials.GetMaterial(ctx, id, req.UserID)
+		if err != nil {
+			return nil, fmt.Errorf("材料 %s 不存在或无权访问", id)
+		}
+		add(m)
+	}
+
+	if len(sources) == 0 {
+		return nil, fmt.Errorf("没有可用于出题的材料")
+	}
+	if len(sources) > MaxQuizSources {
+		return nil, fmt.Errorf("最多同时基于 %d 份材料出题，当前 %d 份", MaxQuizSources, len(sources))
+	}
+	return sources, nil
+}
+
+// 逐个来源检索材料片段并均衡抽样，没有检索到内容的来源跳过
+func (s *QuizService) loadSourcePassages(ctx context.Context, req *QuestionGenerationRequest, sources []SourceMaterial) ([]MaterialPassage, error) {
+	if s.llmClient == nil {
+		return nil, fmt.Errorf("LLM服务不可用，无法基于多份材料出题")
+	}
+
+	bySource := make([][]MaterialPassage, 0, len(sources))
+	for _, source := range sources {
+		passages, err := s.llmClient.GetSourcePassages(ctx, source.ID, req.UserID)
+		if err != nil {
+			s.logger.Errorf("获取材料 %s 的内容失败: %v", source.ID, err)
+			continue
+		}
+		if len(passages) == 0 {
+			s.logger.Infof("材料 %s 没有可用于出题的内容，跳过", source.ID)
+			continue
+		}
+		for i := range passages {
+			passages[i].Source = source.Title
+		}
+		bySource = append(bySource, passages)
+	}
+	if len(bySource) == 0 {
+		return nil, fmt.Errorf("所选材料均没有可用于出题的内容")
+	}
+
+	passages := balancePassages(bySource, maxMultiSourceContentLen)
+	s.logger.Infof("多材料出题，来源数: %d，片段数: %d", len(bySource), len(passages))
+	return passages, nil
+}
+
+// 按来源轮流选取片段直到总长度上限，使每个来源获得大致相同的篇幅；
+// 每个来源的片段按检索相关度排列，放不下时停止选取该来源。结果按来源分组并重新编号为 S1、S2...
+func balancePassages(bySource [][]MaterialPassage, limit int) []MaterialPassage {
+	share := limit / len(bySource)
+	picked := make([][]MaterialPassage, len(bySource))
+	next := make([]int, len(bySource))
+	done := make([]bool, len(bySource))
+	total := 0
+	for progress := true; progress; {
+		progress = false
+		for i, passages := range bySource {
+			if done[i] || next[i] >= len(passages) {
+				continue
+			}
+			p := passages[next[i]]
+			// 每个来源至少保留一个片段，过长时截断到平均篇幅
+			if next[i] == 0 && len(p.Content) > share {
+				p = limitPassages([]MaterialPassage{p}, share)[0]
+			}
+			if next[i] > 0 && total+len(p.Content) > limit {
+				done[i] = true
+				continue
+			}
+			picked[i] = append(picked[i], p)
+			total += len(p.Content)
+			next[i]++
+			progress = true
+		}
+	}
+
+	var passages []MaterialPassage
+	for _, group := range picked {
+		for _, p := range group {
+			p.ID = fmt.Sprintf("S%d", len(passages)+1)
+			passages = append(passages, p)
+		}
+	}
+	return passages
+}
+
+// 多材料出题的提示词说明，列出来源材料并要求题目均匀覆盖
+func sourcesPrompt(passages []MaterialPassage) string {
+	var titles []string
+	seen := map[string]bool{}
+	for _, p := range passages {
+		if seen[p.MaterialID] {
+			continue
+		}
+		seen[p.MaterialID] = true
+		title := p.Source
+		if title == "" {
+			title = p.MaterialID
+		}
+		titles = append(titles, "《"+title+"》")
+	}
+	return fmt.Sprintf("学习材料来自 %d 份资料：%s，每个片段编号后标注了所属资料，题目应尽量均匀覆盖每份资料。\n",
+		len(titles), strings.Join(titles, "、"))
+}
@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/RigelNana/arkstudy/pkg/langdetect"
+	"github.com/RigelNana/arkstudy/quiz-service/models"
+	"github.com/RigelNana/arkstudy/quiz-service/repository"
+)
+
+// 参与比较的材料片段上限，与 llm-service 单次计算向量的文本数上限一致（含作答本身）
+const maxSimilaritySources = 63
+
+// PlagiarismOptions 论述题查重配置
+type PlagiarismOptions struct {
+	Enabled           bool
+	MaterialThreshold float32 // 与材料片段的相似度达到该值时标记
+	PeerThreshold     float32 // 与其他学生作答的相似度达到该值时标记
+	MinAnswerLength   int     // 字符数少于该值的作答不查重
+	MaxPeers          int     // 最多比较的其他学生作答数
+}
+
+// 论述题查重：计算作答与出题依据的材料片段、其他学生作答的向量相似度，相似度过高时在评分结果中标记。
+// 查重失败不影响评分
+type PlagiarismChecker struct {
+	repo      *repository.QuizRepository
+	llmClient *LLMServiceClient
+	opts      PlagiarismOptions
+	logger    *logrus.Logger
+}
+
+func NewPlagiarismChecker(repo *repository.QuizRepository, quizService *QuizService, opts PlagiarismOptions, logger *logrus.Logger) *PlagiarismChecker {
+	if opts.MaxPeers <= 0 {
+		opts.MaxPeers = 200
+	}
+	return &PlagiarismChecker{
+		repo:      repo,
+		llmClient: quizService.llmClient,
+		opts:      opts,
+		logger:    logger,
+	}
+}
+
+// 检查论述题作答，返回查重结果与需随答题记录保存的作答向量（AnswerID 由调用方填写）；不需要或无法查重时均返回 nil
+func (c *PlagiarismChecker) Check(ctx context.Context, question *models.Question, userID, answer string) (*models.SimilarityCheck, *models.AnswerEmbedding) {
+	answer = strings.TrimSpace(answer)
+	if !c.opts.Enabled || c.llmClient == nil || question.Type != models.Essay || utf8.RuneCountInString(answer) < c.opts.MinAnswerLength {
+		return nil, nil
+	}
+
+	texts := []string{answer}
+	var chunkIDs []string
+	for _, citation := range question.GetCitations() {
+		if len(texts) > maxSimilaritySources {
+			break
+		}
+		if snippet := strings.TrimSpace(citation.Snippet); snippet != "" {
+			texts = append(texts, snippet)
+			chunkIDs = append(chunkIDs, citation.ChunkID)
+		}
+	}
+	vectors, model, err := c.llmClient.EmbedTexts(ctx, texts, langdetect.Detect(answer))
+	if err != nil {
+		c.logger.Warnf("计算作答向量失败，跳过查重: %v", err)
+		return nil, nil
+	}
+
+	check := &models.SimilarityCheck{}
+	for i, v := range vectors[1:] {
+		if sim := cosineSimilarity(vectors[0], v); sim > check.MaterialSimilarity {
+			check.MaterialSimilarity, check.MaterialChunkID = sim, chunkIDs[i]
+		}
+	}
+
+	peers, err := c.repo.ListPeerAnswerEmbeddings(question.QuestionID, userID, model, c.opts.MaxPeers)
+	if err != nil {
+		c.logger.Warnf("获取其他作答向量失败: %v", err)
+	}
+	for _, p := range peers {
+		check.PeersCompared++
+		if sim := cosineSimilarity(vectors[0], p.GetVector()); sim > check.PeerSimilarity {
+			check.PeerSimilarity, check.PeerAnswerID = sim, p.AnswerID
+		}
+	}
+
+	if c.opts.MaterialThreshold > 0 && check.MaterialSimilarity >= c.opts.MaterialThreshold {
+		check.Reasons = append(check.Reasons, fmt.Sprintf("与材料原文高度相似（%.0f%%）", check.MaterialSimilarity*100))
+	}
+	if c.opts.PeerThreshold > 0 && check.PeerSimilarity >= c.opts.PeerThreshold {
+		check.Reasons = append(check.Reasons, fmt.Sprintf("与其他同学的作答高度相似（%.0f%%）", check.PeerSimilarity*100))
+	}
+	check.Flagged = len(check.Reasons) > 0
+	if check.Flagged {
+		c.logger.Infof("用户 %s 对题目 %s 的作答疑似抄袭: %s", userID, question.QuestionID, strings.Join(check.Reasons, "；"))
+	}
+
+	data, _ := json.Marshal(vectors[0])
+	return check, &models.AnswerEmbedding{
+		QuestionID: question.QuestionID,
+		UserID:     userID,
+		Model:      model,
+		Vector:     string(data),
+	}
+}
+
+// 余弦相似度，向量维度不同或为零向量时返回 0
+func cosineSimilarity(a, b []float32) float32 {
+	if len(a) == 0 || len(a) != len(b) {
+		return 0
+	}
+	var dot, na, nb float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		na += float64(a[i]) * float64(a[i])
+		nb += float64(b[i]) * float64(b[i])
+	}
+	if na == 0 || nb == 0 {
+		return 0
+	}
+	return float32(dot / (math.Sqrt(na) * math.Sqrt(nb)))
+}
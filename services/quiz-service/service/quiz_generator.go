@@ -10,16 +10,20 @@ import (
 	"github.com/sashabaranov/go-openai"
 	"github.com/sirupsen/logrus"
 
+	"github.com/RigelNana/arkstudy/pkg/langdetect"
 	"github.com/RigelNana/arkstudy/quiz-service/models"
 )
 
 type QuizService struct {
 	openaiClient *openai.Client
 	llmClient    *LLMServiceClient
+	transcripts  *TranscriptClient
+	materials    *MaterialClient
+	estimator    *DifficultyEstimator
 	logger       *logrus.Logger
 }
 
-func NewQuizService(apiKey string, baseURL string, llmServiceAddr string, logger *logrus.Logger) *QuizService {
+func NewQuizService(apiKey string, baseURL string, llmServiceAddr string, asrServiceAddr string, materialServiceAddr string, retrieval RetrievalOptions, difficulty DifficultyOptions, logger *logrus.Logger) *QuizService {
 	var client *openai.Client
 	if baseURL != "" {
 		// 使用自定义baseURL创建客户端
@@ -32,19 +36,37 @@ func NewQuizService(apiKey string, baseURL string, llmServiceAddr string, logger
 	}
 
 	// 初始化LLM服务客户端
-	llmClient, err := NewLLMServiceClient(llmServiceAddr, logger)
+	llmClient, err := NewLLMServiceClient(llmServiceAddr, retrieval, logger)
 	if err != nil {
 		logger.Errorf("Failed to create LLM client: %v", err)
 		llmClient = nil // 如果连接失败，设为nil，后续使用OpenAI作为后备
 	}
 
+	// 初始化ASR服务客户端，用于基于转写出题
+	transcripts, err := NewTranscriptClient(asrServiceAddr, logger)
+	if err != nil {
+		logger.Errorf("Failed to create ASR client: %v", err)
+	}
+
+	// 初始化材料服务客户端，用于多材料出题时校验材料与展开课程合集
+	materials, err := NewMaterialClient(materialServiceAddr, logger)
+	if err != nil {
+		logger.Errorf("Failed to create material client: %v", err)
+	}
+
 	return &QuizService{
 		openaiClient: client,
 		llmClient:    llmClient,
+		transcripts:  transcripts,
+		materials:    materials,
+		estimator:    NewDifficultyEstimator(difficulty, llmClient, logger),
 		logger:       logger,
 	}
 }
 
+// MaxSelectionRunes 基于选中段落出题时选段的最大长度
+const MaxSelectionRunes = 4000
+
 type QuestionGenerationRequest struct {
 	MaterialContent string                 `json:"material_content"`
 	MaterialID      string                 `json:"material_id"`
@@ -53,32 +75,130 @@ type QuestionGenerationRequest struct {
 	Difficulty      models.DifficultyLevel `json:"difficulty"`
 	Count           int                    `json:"count"`
 	KnowledgePoints []string               `json:"knowledge_points"`
+	// 基于音视频转写出题，TimeRange 为转写的时间范围
+	FromTranscript bool      `json:"from_transcript"`
+	TimeRange      TimeRange `json:"time_range"`
+	// 基于用户选中的段落出题，Selection 非空时不检索材料，SelectionPage 为选段所在页码
+	Selection     string `json:"selection,omitempty"`
+	SelectionPage int    `json:"selection_page,omitempty"`
+	// 题目语言（ISO 639-1），为空时按材料片段标注的语言，片段未标注时按材料内容检测
+	Language string `json:"language,omitempty"`
+	// 基于多份材料出题（如单元测验），与 MaterialID 合并去重；CollectionID 为课程合集，出题范围为其中全部材料
+	MaterialIDs  []string `json:"material_ids,omitempty"`
+	CollectionID string   `json:"collection_id,omitempty"`
+	// 按蓝图出题时每道题都须考查的知识点
+	RequiredKnowledgePoint string `json:"required_knowledge_point,omitempty"`
+	// 从LLM服务检索到的材料片段（或转写片段），用于为题目标注出处
+	Passages []MaterialPassage `json:"-"`
 }
 
 type GeneratedQuestion struct {
-	Type            models.QuestionType    `json:"type"`
-	Content         string                 `json:"content"`
-	Options         []string               `json:"options,omitempty"`
-	CorrectAnswer   string                 `json:"correct_answer"`
-	Explanation     string                 `json:"explanation"`
-	Difficulty      models.DifficultyLevel `json:"difficulty"`
-	KnowledgePoints []string               `json:"knowledge_points"`
+	Type             models.QuestionType       `json:"type"`
+	Content          string                    `json:"content"`
+	Options          []string                  `json:"options,omitempty"`
+	CorrectAnswer    string                    `json:"correct_answer"`
+	AnswerAliases    []string                  `json:"answer_aliases,omitempty"`
+	NumericTolerance float64                   `json:"numeric_tolerance,omitempty"`
+	Parts            []models.QuestionPart     `json:"parts,omitempty"`
+	Explanation      string                    `json:"explanation"`
+	Difficulty       models.DifficultyLevel    `json:"difficulty"` // 最终采用的难度
+	KnowledgePoints  []string                  `json:"knowledge_points"`
+	SourceIDs        []string                  `json:"source_ids,omitempty"` // 模型给出的材料片段编号
+	Citations        []models.QuestionCitation `json:"citations,omitempty"`
+	MaterialID       string                    `json:"material_id,omitempty"` // 多材料出题时为题目首个引用的材料
+
+	// 请求指定的难度与按题目文本估计的难度，DifficultySource 为估计方式，未估计时为空
+	RequestedDifficulty models.DifficultyLevel `json:"requested_difficulty"`
+	EstimatedDifficulty models.DifficultyLevel `json:"estimated_difficulty"`
+	DifficultySource    string                 `json:"difficulty_source,omitempty"`
+}
+
+// 答案评估结果
+type AnswerEvaluation struct {
+	Score       float32
+	Feedback    string
+	BlankMatch  *FillBlankMatch     // 仅单空填空题返回
+	PartResults []models.PartResult // 仅多空题返回
 }
 
 // 生成题目的主要方法
 func (s *QuizService) GenerateQuestions(ctx context.Context, req *QuestionGenerationRequest) ([]*GeneratedQuestion, error) {
 	s.logger.Infof("开始生成题目，材料ID: %s, 用户ID: %s, 题目数量: %d", req.MaterialID, req.UserID, req.Count)
 
+	if err := s.prepareMaterial(ctx, req); err != nil {
+		return nil, err
+	}
+
+	var questions []*GeneratedQuestion
+
+	for _, questionType := range req.QuestionTypes {
+		count := req.Count / len(req.QuestionTypes)
+		if count == 0 {
+			count = 1
+		}
+
+		typeQuestions, err := s.generateQuestionsByType(ctx, req, questionType, count)
+		if err != nil {
+			s.logger.Errorf("生成 %s 类型题目失败: %v", questionType.String(), err)
+			continue
+		}
+		s.attachCitations(req, typeQuestions)
+		s.estimator.Apply(ctx, typeQuestions, req.Difficulty, req.UserID)
+
+		questions = append(questions, typeQuestions...)
+	}
+
+	s.logger.Infof("成功生成 %d 道题目", len(questions))
+	return questions, nil
+}
+
+// 获取出题依据的材料内容、题目语言与知识点，写回 req
+func (s *QuizService) prepareMaterial(ctx context.Context, req *QuestionGenerationRequest) error {
 	// 从LLM服务获取材料内容
 	var materialContent string
-	var err error
 
-	if s.llmClient != nil {
-		materialContent, err = s.llmClient.GetMaterialContent(ctx, req.MaterialID, req.UserID)
+	if req.Selection != "" {
+		// 选中段落即全部出题依据，不回退到材料检索
+		req.Passages = []MaterialPassage{{
+			ID:         "S1",
+			MaterialID: req.MaterialID,
+			Page:       req.SelectionPage,
+			Content:    req.Selection,
+		}}
+		materialContent = formatPassages(req.Passages)
+	} else if req.multiSource() {
+		// 多材料出题按来源均衡抽样，不回退到单材料检索
+		sources, err := s.resolveSources(ctx, req)
+		if err != nil {
+			return err
+		}
+		passages, err := s.loadSourcePassages(ctx, req, sources)
+		if err != nil {
+			return err
+		}
+		req.Passages = passages
+		materialContent = formatPassages(passages)
+	} else if req.FromTranscript {
+		// 指定了转写时间范围时不回退到材料分片，否则题目会超出所选范围
+		if s.transcripts == nil {
+			return fmt.Errorf("ASR服务不可用，无法基于转写出题")
+		}
+		passages, err := s.transcripts.GetTranscriptPassages(ctx, req.MaterialID, req.UserID, req.TimeRange)
+		if err != nil {
+			s.logger.Errorf("从ASR服务获取转写内容失败: %v", err)
+			return fmt.Errorf("无法获取转写内容: %v", err)
+		}
+		req.Passages = passages
+		materialContent = formatPassages(passages)
+	} else if s.llmClient != nil {
+		passages, err := s.llmClient.GetMaterialContent(ctx, req.MaterialID, req.UserID)
 		if err != nil {
 			s.logger.Errorf("从LLM服务获取材料内容失败: %v", err)
 			// 使用传入的内容作为后备
 			materialContent = req.MaterialContent
+		} else {
+			req.Passages = passages
+			materialContent = formatPassages(passages)
 		}
 	} else {
 		// 如果LLM客户端不可用，使用传入的内容
@@ -86,7 +206,10 @@ func (s *QuizService) GenerateQuestions(ctx context.Context, req *QuestionGenera
 	}
 
 	if materialContent == "" {
-		return nil, fmt.Errorf("无法获取材料内容")
+		return fmt.Errorf("无法获取材料内容")
+	}
+	if req.Language == "" {
+		req.Language = materialLanguage(req.Passages, materialContent)
 	}
 
 	// 如果没有指定知识点，尝试从LLM服务提取
@@ -103,26 +226,17 @@ func (s *QuizService) GenerateQuestions(ctx context.Context, req *QuestionGenera
 	// 更新请求对象
 	req.MaterialContent = materialContent
 	req.KnowledgePoints = knowledgePoints
+	return nil
+}
 
-	var questions []*GeneratedQuestion
-
-	for _, questionType := range req.QuestionTypes {
-		count := req.Count / len(req.QuestionTypes)
-		if count == 0 {
-			count = 1
+// 为题目标注引用的材料片段，多材料出题时题目归属首个引用的材料
+func (s *QuizService) attachCitations(req *QuestionGenerationRequest, questions []*GeneratedQuestion) {
+	for _, q := range questions {
+		q.Citations = resolveCitations(q, req.Passages)
+		if req.multiSource() && len(q.Citations) > 0 {
+			q.MaterialID = q.Citations[0].MaterialID
 		}
-
-		typeQuestions, err := s.generateQuestionsByType(ctx, req, questionType, count)
-		if err != nil {
-			s.logger.Errorf("生成 %s 类型题目失败: %v", questionType.String(), err)
-			continue
-		}
-
-		questions = append(questions, typeQuestions...)
 	}
-
-	s.logger.Infof("成功生成 %d 道题目", len(questions))
-	return questions, nil
 }
 
 // 根据题目类型生成题目
@@ -185,15 +299,33 @@ func (s *QuizService) buildPrompt(req *QuestionGenerationRequest, questionType m
 	var promptBuilder strings.Builder
 
 	promptBuilder.WriteString(fmt.Sprintf("基于以下学习材料，生成 %d 道 %s 类型的题目。\n\n", count, s.getQuestionTypeDescription(questionType)))
+	if req.Selection != "" {
+		promptBuilder.WriteString("学习材料为用户从资料中选中的一段内容，题目应只考查这段内容，不要引入段落之外的知识。\n")
+	} else if req.FromTranscript {
+		promptBuilder.WriteString(fmt.Sprintf("学习材料为课程视频 %s 时间段的讲课转写，片段开头标注了时间码，题目应只考查该时间段讲授的内容。\n", req.TimeRange))
+	} else if req.multiSource() {
+		promptBuilder.WriteString(sourcesPrompt(req.Passages))
+	}
 	promptBuilder.WriteString("学习材料内容:\n")
 	promptBuilder.WriteString(req.MaterialContent)
 	promptBuilder.WriteString("\n\n")
 
 	promptBuilder.WriteString(fmt.Sprintf("难度级别: %s\n", s.getDifficultyDescription(req.Difficulty)))
+	if req.Language != "" {
+		promptBuilder.WriteString(fmt.Sprintf("题目、选项、答案与解析均使用 %s 书写\n", langdetect.Name(req.Language)))
+	}
 
 	if len(req.KnowledgePoints) > 0 {
 		promptBuilder.WriteString("重点关注的知识点: " + strings.Join(req.KnowledgePoints, ", ") + "\n")
 	}
+	if req.RequiredKnowledgePoint != "" {
+		promptBuilder.WriteString(fmt.Sprintf("每道题都必须考查知识点「%s」，并在 knowledge_points 中包含该知识点\n", req.RequiredKnowledgePoint))
+	}
+
+	// 材料按片段编号时，要求模型标注每道题依据的片段
+	if len(req.Passages) > 0 {
+		promptBuilder.WriteString("学习材料按片段编号（如 [S1]），请在每道题的 source_ids 字段中列出该题依据的片段编号，如 [\"S1\"]\n")
+	}
 
 	promptBuilder.WriteString("\n请按照以下JSON格式返回题目:\n")
 	promptBuilder.WriteString(s.getQuestionFormat(questionType))
@@ -201,6 +333,25 @@ func (s *QuizService) buildPrompt(req *QuestionGenerationRequest, questionType m
 	return promptBuilder.String()
 }
 
+// materialLanguage 取材料片段中标注最多的语言，片段均未标注时按材料内容检测
+func materialLanguage(passages []MaterialPassage, content string) string {
+	counts := map[string]int{}
+	best := ""
+	for _, p := range passages {
+		if p.Language == "" {
+			continue
+		}
+		counts[p.Language]++
+		if counts[p.Language] > counts[best] {
+			best = p.Language
+		}
+	}
+	if best != "" {
+		return best
+	}
+	return langdetect.Detect(content)
+}
+
 // 获取题目类型描述
 func (s *QuizService) getQuestionTypeDescription(questionType models.QuestionType) string {
 	switch questionType {
@@ -254,6 +405,12 @@ func (s *QuizService) getQuestionFormat(questionType models.QuestionType) string
     {
       "content": "题目内容，使用 _____ 表示填空位置",
       "correct_answer": "正确答案",
+      "answer_aliases": ["同义答案1", "同义答案2"],
+      "numeric_tolerance": 0,
+      "parts": [
+        {"correct_answer": "第1空答案", "answer_aliases": ["同义答案"], "weight": 1},
+        {"correct_answer": "第2空答案", "weight": 1}
+      ],
       "explanation": "答案解析",
       "knowledge_points": ["知识点1", "知识点2"]
     }
@@ -298,11 +455,15 @@ func (s *QuizService) parseQuestions(content string, questionType models.Questio
 
 	var result struct {
 		Questions []struct {
-			Content         string   `json:"content"`
-			Options         []string `json:"options,omitempty"`
-			CorrectAnswer   string   `json:"correct_answer"`
-			Explanation     string   `json:"explanation"`
-			KnowledgePoints []string `json:"knowledge_points"`
+			Content          string                `json:"content"`
+			Options          []string              `json:"options,omitempty"`
+			CorrectAnswer    string                `json:"correct_answer"`
+			AnswerAliases    []string              `json:"answer_aliases,omitempty"`
+			NumericTolerance float64               `json:"numeric_tolerance,omitempty"`
+			Parts            []models.QuestionPart `json:"parts,omitempty"`
+			Explanation      string                `json:"explanation"`
+			KnowledgePoints  []string              `json:"knowledge_points"`
+			SourceIDs        []string              `json:"source_ids,omitempty"`
 		} `json:"questions"`
 	}
 
@@ -314,13 +475,17 @@ func (s *QuizService) parseQuestions(content string, questionType models.Questio
 	var questions []*GeneratedQuestion
 	for _, q := range result.Questions {
 		question := &GeneratedQuestion{
-			Type:            questionType,
-			Content:         q.Content,
-			Options:         q.Options,
-			CorrectAnswer:   q.CorrectAnswer,
-			Explanation:     q.Explanation,
-			Difficulty:      difficulty,
-			KnowledgePoints: q.KnowledgePoints,
+			Type:             questionType,
+			Content:          q.Content,
+			Options:          q.Options,
+			CorrectAnswer:    q.CorrectAnswer,
+			AnswerAliases:    q.AnswerAliases,
+			NumericTolerance: q.NumericTolerance,
+			Parts:            q.Parts,
+			Explanation:      q.Explanation,
+			Difficulty:       difficulty,
+			KnowledgePoints:  q.KnowledgePoints,
+			SourceIDs:        q.SourceIDs,
 		}
 		questions = append(questions, question)
 	}
@@ -328,17 +493,36 @@ func (s *QuizService) parseQuestions(content string, questionType models.Questio
 	return questions, nil
 }
 
-// 将生成的题目转换为数据库模型
+// 将生成的题目转换为数据库模型，多材料出题时题目归属其引用的材料
 func (s *QuizService) ConvertToQuestionModel(generated *GeneratedQuestion, materialID, userID string) *models.Question {
+	if generated.MaterialID != "" {
+		materialID = generated.MaterialID
+	}
 	question := &models.Question{
-		QuestionID:    uuid.New().String(),
-		Type:          generated.Type,
-		Content:       generated.Content,
-		CorrectAnswer: generated.CorrectAnswer,
-		Explanation:   generated.Explanation,
-		Difficulty:    generated.Difficulty,
-		MaterialID:    materialID,
-		CreatorID:     userID,
+		QuestionID:          uuid.New().String(),
+		Type:                generated.Type,
+		Content:             generated.Content,
+		CorrectAnswer:       generated.CorrectAnswer,
+		AnswerAliases:       marshalAliases(generated.AnswerAliases),
+		NumericTolerance:    generated.NumericTolerance,
+		Parts:               marshalParts(generated.Parts),
+		Citations:           marshalCitations(generated.Citations),
+		Explanation:         generated.Explanation,
+		Difficulty:          generated.Difficulty,
+		RequestedDifficulty: generated.RequestedDifficulty,
+		EstimatedDifficulty: generated.EstimatedDifficulty,
+		DifficultySource:    generated.DifficultySource,
+		MaterialID:          materialID,
+		CreatorID:           userID,
+	}
+
+	// 多空题未给出整体答案时，用分项答案拼接，便于展示
+	if question.CorrectAnswer == "" && len(generated.Parts) > 0 {
+		answers := make([]string, len(generated.Parts))
+		for i, p := range generated.Parts {
+			answers[i] = p.CorrectAnswer
+		}
+		question.CorrectAnswer = strings.Join(answers, " | ")
 	}
 
 	// 序列化选项
@@ -356,23 +540,34 @@ func (s *QuizService) ConvertToQuestionModel(generated *GeneratedQuestion, mater
 	return question
 }
 
-// 评估主观题答案
-func (s *QuizService) EvaluateSubjectiveAnswer(ctx context.Context, question *models.Question, userAnswer, userID string) (float32, string, error) {
+// 评估答案，partAnswers 为多空题的分项答案，为空时从 userAnswer 中拆分；
+// partialCreditMode 决定多空题的部分得分方式
+func (s *QuizService) EvaluateAnswer(ctx context.Context, question *models.Question, userAnswer string, partAnswers []string, partialCreditMode, userID string) (*AnswerEvaluation, error) {
+	// 多空题按分项计分
+	if parts := question.GetParts(); len(parts) > 0 {
+		if len(partAnswers) == 0 {
+			partAnswers = SplitPartAnswers(userAnswer)
+		}
+		results, score := EvaluateParts(parts, partAnswers, partialCreditMode)
+		feedback := fmt.Sprintf("共 %d 空，答对 %d 空", len(results), countCorrectParts(results))
+		return &AnswerEvaluation{Score: score, Feedback: feedback, PartResults: results}, nil
+	}
+
 	// 对于客观题，直接比较答案
 	if question.Type == models.MultipleChoice || question.Type == models.TrueFalse {
 		if question.CorrectAnswer == userAnswer {
-			return 1.0, "答案正确", nil
+			return &AnswerEvaluation{Score: 1.0, Feedback: "答案正确"}, nil
 		}
-		return 0.0, "答案错误", nil
+		return &AnswerEvaluation{Score: 0.0, Feedback: "答案错误"}, nil
 	}
 
-	// 对于填空题，进行简单的字符串匹配
+	// 对于填空题，进行归一化、同义答案和数值容差匹配
 	if question.Type == models.FillBlank {
-		// 可以在这里添加更复杂的匹配逻辑，如模糊匹配、关键词匹配等
-		if strings.TrimSpace(strings.ToLower(question.CorrectAnswer)) == strings.TrimSpace(strings.ToLower(userAnswer)) {
-			return 1.0, "答案正确", nil
+		match := MatchFillBlank(question, userAnswer)
+		if match.Matched {
+			return &AnswerEvaluation{Score: 1.0, Feedback: "答案正确", BlankMatch: match}, nil
 		}
-		return 0.0, "答案不正确", nil
+		return &AnswerEvaluation{Score: 0.0, Feedback: "答案不正确", BlankMatch: match}, nil
 	}
 
 	// 对于主观题，使用LLM服务评估
@@ -381,11 +576,21 @@ func (s *QuizService) EvaluateSubjectiveAnswer(ctx context.Context, question *mo
 		if err != nil {
 			s.logger.Errorf("LLM评估主观题失败: %v", err)
 			// 回退到简单评估
-			return 0.8, "答案已提交，需要人工评估", nil
+			return &AnswerEvaluation{Score: 0.8, Feedback: "答案已提交，需要人工评估"}, nil
 		}
-		return score, feedback, nil
+		return &AnswerEvaluation{Score: score, Feedback: feedback}, nil
 	}
 
 	// 如果LLM服务不可用，对主观题给予默认分数
-	return 0.8, "答案已提交，需要人工评估", nil
+	return &AnswerEvaluation{Score: 0.8, Feedback: "答案已提交，需要人工评估"}, nil
+}
+
+func countCorrectParts(results []models.PartResult) int {
+	n := 0
+	for _, r := range results {
+		if r.IsCorrect {
+			n++
+		}
+	}
+	return n
 }
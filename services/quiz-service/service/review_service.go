@@ -0,0 +1,306 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/RigelNana/arkstudy/quiz-service/models"
+	"github.com/RigelNana/arkstudy/quiz-service/repository"
+)
+
+// 单次审核操作最多处理的题目数
+const maxReviewBatch = 200
+
+var (
+	ErrQuestionNotFound   = errors.New("题目不存在")
+	ErrReviewForbidden    = errors.New("无权审核该题目")
+	ErrReviewStateChanged = errors.New("题目审核状态已变化，请刷新后重试")
+	ErrSelfReview         = errors.New("不能审核本人创建的题目")
+	ErrReviewerNotStaff   = errors.New("审核人需为教师或管理员")
+)
+
+// 批量审核操作中未处理的题目及原因
+type ReviewSkip struct {
+	QuestionID string
+	Reason     string
+}
+
+// 题目审核服务：课程内生成的题目由创建者提交审核并指定审核人，审核通过后才对学生可见。
+// 审核人须为教师或管理员且不能是题目创建者，否则创建者可以自行通过审核
+type ReviewService struct {
+	repo   *repository.QuizRepository
+	access *AccessChecker
+	logger *logrus.Logger
+}
+
+func NewReviewService(repo *repository.QuizRepository, access *AccessChecker, logger *logrus.Logger) *ReviewService {
+	return &ReviewService{repo: repo, access: access, logger: logger}
+}
+
+// 校验 reviewerID 能审核 creatorID 创建的题目：不是创建者本人，且在 user-service 中为教师或管理员
+func (s *ReviewService) CheckReviewer(ctx context.Context, creatorID, reviewerID string) error {
+	if reviewerID == creatorID {
+		return ErrSelfReview
+	}
+	return s.requireStaff(ctx, reviewerID)
+}
+
+func (s *ReviewService) requireStaff(ctx context.Context, userID string) error {
+	err := s.access.RequireStaff(ctx, userID)
+	if errors.Is(err, ErrPermissionDenied) {
+		return ErrReviewerNotStaff
+	}
+	return err
+}
+
+// 为新生成的课程题目设置初始审核状态：指定了审核人时直接进入待审核，否则为草稿。
+// 审核人需事先经 CheckReviewer 校验，此处只拒绝审核本人创建的题目
+func (s *ReviewService) PrepareGenerated(questions []*models.Question, courseID, reviewerID string) error {
+	if courseID == "" {
+		return nil
+	}
+	for _, q := range questions {
+		if reviewerID != "" && reviewerID == q.CreatorID {
+			return ErrSelfReview
+		}
+	}
+	for _, q := range questions {
+		q.CourseID = courseID
+		q.ReviewStatus = models.ReviewStatusDraft
+		if reviewerID != "" {
+			q.ReviewStatus = models.ReviewStatusPendingReview
+			q.ReviewerID = reviewerID
+		}
+	}
+	return nil
+}
+
+// 提交审核并指定审核人：创建者可提交草稿或被驳回的题目，也可改派待审核题目的审核人
+func (s *ReviewService) AssignReviewer(ctx context.Context, userID, reviewerID string, questionIDs []string) ([]*models.Question, []ReviewSkip, error) {
+	if reviewerID == "" {
+		return nil, nil, errors.New("需要指定审核人")
+	}
+	// 只能提交本人创建的题目，审核人不是本人即不是任何待提交题目的创建者
+	if err := s.CheckReviewer(ctx, userID, reviewerID); err != nil {
+		return nil, nil, err
+	}
+	questions, skipped, err := s.load(questionIDs, func(q *models.Question) string {
+		switch {
+		case q.CreatorID != userID:
+			return "不是本人创建的题目"
+		case q.CreatorID == reviewerID:
+			return ErrSelfReview.Error()
+		case !q.RequiresReview():
+			return "不是课程题目，无需审核"
+		case !q.CanTransitionTo(models.ReviewStatusPendingReview):
+			return fmt.Sprintf("当前状态为 %s，不能提交审核", q.ReviewStatus)
+		}
+		return ""
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	updated, lost, err := s.transition(userID, questions,
+		[]string{models.ReviewStatusDraft, models.ReviewStatusPendingReview, models.ReviewStatusRejected}, "",
+		map[string]interface{}{
+			"review_status":  models.ReviewStatusPendingReview,
+			"reviewer_id":    reviewerID,
+			"review_comment": "",
+			"reviewed_at":    nil,
+		},
+		func(q *models.Question) bool {
+			return q.ReviewStatus == models.ReviewStatusPendingReview && q.ReviewerID == reviewerID
+		})
+	if err != nil {
+		return nil, nil, err
+	}
+	s.logger.Infof("用户 %s 将 %d 道题目提交给 %s 审核", userID, len(updated), reviewerID)
+	return updated, append(skipped, lost...), nil
+}
+
+// 审核单道待审核题目，驳回时需要填写原因
+func (s *ReviewService) Review(ctx context.Context, reviewerID, questionID string, approve bool, comment string) (*models.Question, error) {
+	question, err := s.repo.GetQuestionByID(questionID)
+	if err != nil {
+		return nil, ErrQuestionNotFound
+	}
+	if !question.RequiresReview() {
+		return nil, errors.New("该题目无需审核")
+	}
+	if question.ReviewerID != reviewerID {
+		return nil, ErrReviewForbidden
+	}
+	// 指定审核人之后角色可能变化，或是在校验加入前指定的本人
+	if question.CreatorID == reviewerID {
+		return nil, ErrSelfReview
+	}
+	if err := s.requireStaff(ctx, reviewerID); err != nil {
+		return nil, err
+	}
+	if question.ReviewStatus != models.ReviewStatusPendingReview {
+		return nil, fmt.Errorf("题目当前状态为 %s，不在待审核状态", question.ReviewStatus)
+	}
+	comment = strings.TrimSpace(comment)
+	status := models.ReviewStatusApproved
+	if !approve {
+		if comment == "" {
+			return nil, errors.New("驳回时需要填写原因")
+		}
+		status = models.ReviewStatusRejected
+	}
+
+	now := time.Now()
+	n, err := s.repo.AsActor(reviewerID).TransitionReview([]string{questionID}, []string{models.ReviewStatusPendingReview}, reviewerID, map[string]interface{}{
+		"review_status":  status,
+		"review_comment": comment,
+		"reviewed_at":    now,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("保存审核结果失败: %w", err)
+	}
+	if n == 0 {
+		return nil, ErrReviewStateChanged
+	}
+	question.ReviewStatus, question.ReviewComment, question.ReviewedAt = status, comment, &now
+	s.logger.Infof("审核人 %s 将题目 %s 标记为 %s", reviewerID, questionID, status)
+	return question, nil
+}
+
+// 批量通过待审核题目。questionIDs 为空时通过审核人在 courseID 下待审核的全部题目（单次最多 maxReviewBatch 道）；
+// 不满足条件的题目跳过并返回原因，不影响其余题目
+func (s *ReviewService) BulkApprove(ctx context.Context, reviewerID, courseID string, questionIDs []string, comment string) ([]*models.Question, []ReviewSkip, error) {
+	if err := s.requireStaff(ctx, reviewerID); err != nil {
+		return nil, nil, err
+	}
+	if len(questionIDs) == 0 {
+		if courseID == "" {
+			return nil, nil, errors.New("需要指定题目或课程")
+		}
+		ids, err := s.repo.ListPendingReviewIDs(reviewerID, courseID, maxReviewBatch)
+		if err != nil {
+			return nil, nil, fmt.Errorf("获取待审核题目失败: %w", err)
+		}
+		if len(ids) == 0 {
+			return nil, nil, nil
+		}
+		questionIDs = ids
+	}
+	questions, skipped, err := s.load(questionIDs, func(q *models.Question) string {
+		switch {
+		case q.ReviewerID != reviewerID:
+			return "未指定你为审核人"
+		case q.CreatorID == reviewerID:
+			return ErrSelfReview.Error()
+		case courseID != "" && q.CourseID != courseID:
+			return "不属于该课程"
+		case q.ReviewStatus != models.ReviewStatusPendingReview:
+			return fmt.Sprintf("当前状态为 %s，不在待审核状态", q.ReviewStatus)
+		}
+		return ""
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	updated, lost, err := s.transition(reviewerID, questions, []string{models.ReviewStatusPendingReview}, reviewerID,
+		map[string]interface{}{
+			"review_status":  models.ReviewStatusApproved,
+			"review_comment": strings.TrimSpace(comment),
+			"reviewed_at":    time.Now(),
+		},
+		func(q *models.Question) bool { return q.ReviewStatus == models.ReviewStatusApproved })
+	if err != nil {
+		return nil, nil, err
+	}
+	s.logger.Infof("审核人 %s 批量通过 %d 道题目，跳过 %d 道", reviewerID, len(updated), len(skipped)+len(lost))
+	return updated, append(skipped, lost...), nil
+}
+
+// 获取审核人的审核队列，status 为空时只返回待审核的题目
+func (s *ReviewService) ListQueue(reviewerID, courseID, status string, page, pageSize int) ([]*models.Question, int64, error) {
+	if status == "" {
+		status = models.ReviewStatusPendingReview
+	}
+	if !models.IsReviewStatus(status) {
+		return nil, 0, fmt.Errorf("无效的审核状态: %s", status)
+	}
+	questions, total, err := s.repo.ListReviewQueue(reviewerID, courseID, status, page, pageSize)
+	if err != nil {
+		return nil, 0, fmt.Errorf("获取审核队列失败: %w", err)
+	}
+	return questions, total, nil
+}
+
+// 按ID读取题目并逐题检查，check 返回非空原因的题目与不存在的题目计入跳过列表
+func (s *ReviewService) load(questionIDs []string, check func(*models.Question) string) ([]*models.Question, []ReviewSkip, error) {
+	questionIDs = dedupe(questionIDs)
+	if len(questionIDs) == 0 {
+		return nil, nil, errors.New("需要指定题目")
+	}
+	if len(questionIDs) > maxReviewBatch {
+		return nil, nil, fmt.Errorf("单次最多处理 %d 道题目", maxReviewBatch)
+	}
+	questions, err := s.repo.GetQuestionsByIDs(questionIDs)
+	if err != nil {
+		return nil, nil, fmt.Errorf("获取题目失败: %w", err)
+	}
+	byID := make(map[string]*models.Question, len(questions))
+	for _, q := range questions {
+		byID[q.QuestionID] = q
+	}
+
+	var eligible []*models.Question
+	var skipped []ReviewSkip
+	for _, id := range questionIDs {
+		q := byID[id]
+		if q == nil {
+			skipped = append(skipped, ReviewSkip{QuestionID: id, Reason: ErrQuestionNotFound.Error()})
+			continue
+		}
+		if reason := check(q); reason != "" {
+			skipped = append(skipped, ReviewSkip{QuestionID: id, Reason: reason})
+			continue
+		}
+		eligible = append(eligible, q)
+	}
+	return eligible, skipped, nil
+}
+
+// 以 actor 身份按状态条件批量更新题目后重新读取，done 不成立的题目（期间被他人修改）计入跳过列表
+func (s *ReviewService) transition(actor string, questions []*models.Question, fromStatus []string, reviewerID string, updates map[string]interface{}, done func(*models.Question) bool) ([]*models.Question, []ReviewSkip, error) {
+	if len(questions) == 0 {
+		return nil, nil, nil
+	}
+	ids := make([]string, len(questions))
+	for i, q := range questions {
+		ids[i] = q.QuestionID
+	}
+	if _, err := s.repo.AsActor(actor).TransitionReview(ids, fromStatus, reviewerID, updates); err != nil {
+		return nil, nil, fmt.Errorf("保存审核状态失败: %w", err)
+	}
+	current, err := s.repo.GetQuestionsByIDs(ids)
+	if err != nil {
+		return nil, nil, fmt.Errorf("获取题目失败: %w", err)
+	}
+	byID := make(map[string]*models.Question, len(current))
+	for _, q := range current {
+		byID[q.QuestionID] = q
+	}
+
+	var updated []*models.Question
+	var lost []ReviewSkip
+	for _, id := range ids {
+		if q := byID[id]; q != nil && done(q) {
+			updated = append(updated, q)
+			continue
+		}
+		lost = append(lost, ReviewSkip{QuestionID: id, Reason: ErrReviewStateChanged.Error()})
+	}
+	return updated, lost, nil
+}
@@ -0,0 +1,364 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+
+	"github.com/RigelNana/arkstudy/quiz-service/models"
+	"github.com/RigelNana/arkstudy/quiz-service/repository"
+)
+
+// 昵称最大长度（字符数）
+const maxShareNickname = 32
+
+var (
+	ErrShareNotFound      = errors.New("分享不存在或已失效")
+	ErrShareForbidden     = errors.New("无权访问该分享")
+	ErrShareLoginRequired = errors.New("该分享需要登录后作答")
+)
+
+// ShareOptions 题目分享配置
+type ShareOptions struct {
+	DefaultTTL   time.Duration
+	MaxTTL       time.Duration
+	MaxQuestions int               // 单个分享最多包含的题目数
+	Anomaly      AnomalyThresholds // 异常作答判定阈值
+}
+
+// 分享作答中的一题
+type ShareAnswer struct {
+	QuestionID  string
+	Answer      string
+	PartAnswers []string
+	TimeSpentMs int64 // 前端上报的本题作答耗时
+}
+
+// 题目分享服务：分享题目给未注册的访客作答，作答记录单独存储，只有创建者可以查看
+type ShareService struct {
+	repo           *repository.QuizRepository
+	quizService    *QuizService
+	gradingService *GradingService
+	opts           ShareOptions
+	logger         *logrus.Logger
+}
+
+func NewShareService(repo *repository.QuizRepository, quizService *QuizService, gradingService *GradingService, opts ShareOptions, logger *logrus.Logger) *ShareService {
+	if opts.MaxTTL <= 0 {
+		opts.MaxTTL = 30 * 24 * time.Hour
+	}
+	if opts.DefaultTTL <= 0 || opts.DefaultTTL > opts.MaxTTL {
+		opts.DefaultTTL = min(7*24*time.Hour, opts.MaxTTL)
+	}
+	if opts.MaxQuestions <= 0 {
+		opts.MaxQuestions = 100
+	}
+	return &ShareService{
+		repo:           repo,
+		quizService:    quizService,
+		gradingService: gradingService,
+		opts:           opts,
+		logger:         logger,
+	}
+}
+
+// 创建分享，questionIDs 为空时分享 materialID 下本人创建的全部题目；只能分享本人创建的题目，
+// 课程题目须审核通过（按材料分享时跳过未通过的题目）
+func (s *ShareService) Create(ownerID, title, materialID string, questionIDs []string, allowAnonymous bool, ttl time.Duration) (*models.QuizShare, error) {
+	if ttl == 0 {
+		ttl = s.opts.DefaultTTL
+	}
+	if ttl < 0 || ttl > s.opts.MaxTTL {
+		return nil, fmt.Errorf("有效期须在 0 到 %s 之间", s.opts.MaxTTL)
+	}
+
+	var questions []*models.Question
+	var err error
+	if len(questionIDs) == 0 {
+		if materialID == "" {
+			return nil, errors.New("需要指定题目或材料")
+		}
+		questions, _, err = s.repo.ListQuestions(ownerID, materialID, nil, nil, 1, s.opts.MaxQuestions)
+		questions = slices.DeleteFunc(questions, func(q *models.Question) bool { return !q.VisibleTo("") })
+	} else {
+		questionIDs = dedupe(questionIDs)
+		if len(questionIDs) > s.opts.MaxQuestions {
+			return nil, fmt.Errorf("单个分享最多包含 %d 道题目", s.opts.MaxQuestions)
+		}
+		questions, err = s.repo.GetQuestionsByIDs(questionIDs)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("获取题目失败: %w", err)
+	}
+	if len(questions) == 0 {
+		return nil, errors.New("没有可分享的题目")
+	}
+
+	byID := make(map[string]*models.Question, len(questions))
+	for _, q := range questions {
+		if q.CreatorID != ownerID {
+			return nil, fmt.Errorf("题目 %s 不是本人创建的，不能分享", q.QuestionID)
+		}
+		if !q.VisibleTo("") {
+			return nil, fmt.Errorf("题目 %s 尚未通过审核，不能分享", q.QuestionID)
+		}
+		byID[q.QuestionID] = q
+	}
+	ordered := questionIDs
+	if len(ordered) == 0 {
+		// 材料下的题目按创建时间正序作答
+		for i := len(questions) - 1; i >= 0; i-- {
+			ordered = append(ordered, questions[i].QuestionID)
+		}
+	}
+	for _, id := range ordered {
+		if byID[id] == nil {
+			return nil, fmt.Errorf("题目 %s 不存在", id)
+		}
+	}
+	if title == "" {
+		title = fmt.Sprintf("%d 道练习题", len(ordered))
+	}
+
+	idsJSON, _ := json.Marshal(ordered)
+	share := &models.QuizShare{
+		ShareID:        uuid.New().String(),
+		OwnerID:        ownerID,
+		Title:          title,
+		MaterialID:     materialID,
+		QuestionIDs:    string(idsJSON),
+		AllowAnonymous: allowAnonymous,
+		ExpiresAt:      time.Now().Add(ttl),
+	}
+	if err := s.repo.CreateShare(share); err != nil {
+		return nil, fmt.Errorf("保存分享失败: %w", err)
+	}
+	return share, nil
+}
+
+// 获取分享与题目。ownerID 为空时为公开访问，分享须在有效期内；否则须为创建者，可查看已失效的分享
+func (s *ShareService) Get(shareID, ownerID string) (*models.QuizShare, []*models.Question, error) {
+	share, err := s.lookup(shareID, ownerID)
+	if err != nil {
+		return nil, nil, err
+	}
+	questions, err := s.questions(share)
+	if err != nil {
+		return nil, nil, err
+	}
+	return share, questions, nil
+}
+
+// 获取用户创建的分享及各分享的作答次数
+func (s *ShareService) List(ownerID string, page, pageSize int) ([]*models.QuizShare, map[string]int, int64, error) {
+	shares, total, err := s.repo.ListSharesByOwner(ownerID, page, pageSize)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	ids := make([]string, 0, len(shares))
+	for _, sh := range shares {
+		ids = append(ids, sh.ShareID)
+	}
+	counts, err := s.repo.CountShareAttempts(ids)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	return shares, counts, total, nil
+}
+
+// 撤销分享，撤销后公开地址立即失效，作答记录保留
+func (s *ShareService) Revoke(shareID, ownerID string) (*models.QuizShare, error) {
+	if ownerID == "" {
+		return nil, ErrShareForbidden
+	}
+	share, err := s.lookup(shareID, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	if share.RevokedAt == nil {
+		now := time.Now()
+		if err := s.repo.RevokeShare(shareID, now); err != nil {
+			return nil, fmt.Errorf("撤销分享失败: %w", err)
+		}
+		share.RevokedAt = &now
+	}
+	return share, nil
+}
+
+// 提交分享作答。按题目所属材料的评分策略评分，作答记录不写入 UserAnswer、错题本与题目统计，
+// 以免匿名访客的作答影响创建者的数据；主观题由 LLM 评分时计入创建者名下。
+// telemetry 为前端上报的作答埋点，与各题用时一起用于标记异常作答
+func (s *ShareService) Submit(ctx context.Context, shareID, userID, nickname string, answers []ShareAnswer, telemetry *models.AttemptTelemetry) (*models.QuizShareAttempt, error) {
+	share, err := s.lookup(shareID, "")
+	if err != nil {
+		return nil, err
+	}
+	nickname = strings.TrimSpace(nickname)
+	if userID == "" {
+		if !share.AllowAnonymous {
+			return nil, ErrShareLoginRequired
+		}
+		if nickname == "" {
+			return nil, errors.New("匿名作答需要填写昵称")
+		}
+	}
+	if utf8.RuneCountInString(nickname) > maxShareNickname {
+		return nil, fmt.Errorf("昵称最多 %d 个字符", maxShareNickname)
+	}
+
+	questions, err := s.questions(share)
+	if err != nil {
+		return nil, err
+	}
+	submitted := make(map[string]ShareAnswer, len(answers))
+	for _, a := range answers {
+		submitted[a.QuestionID] = a
+	}
+
+	results := make([]models.ShareAnswerResult, 0, len(questions))
+	var total float32
+	correct, graded := 0, 0
+	for _, q := range questions {
+		a, ok := submitted[q.QuestionID]
+		result := models.ShareAnswerResult{QuestionID: q.QuestionID, QuestionVersion: q.Version, TimeSpentMs: ClampTimeSpent(a.TimeSpentMs, time.Time{})}
+		if ok && (a.Answer != "" || len(a.PartAnswers) > 0) {
+			result.Answer = a.Answer
+			if result.Answer == "" {
+				result.Answer = strings.Join(a.PartAnswers, " | ")
+			}
+			// 主观题评分会调用 LLM 并计入分享者的用量，匿名作答不评分，标记为待评阅
+			if userID == "" && isSubjective(q) {
+				result.Pending = true
+				results = append(results, result)
+				continue
+			}
+			policy := s.gradingService.Resolve(q.MaterialID, q.CourseID)
+			evaluation, err := s.quizService.EvaluateAnswer(ctx, q, a.Answer, a.PartAnswers, policy.PartialCreditMode, share.OwnerID)
+			if err != nil {
+				s.logger.Errorf("评估分享作答失败: %v", err)
+				return nil, errors.New("答案评估失败")
+			}
+			result.Score = evaluation.Score
+			result.IsCorrect = evaluation.Score >= policy.PassThreshold
+		}
+		if result.IsCorrect {
+			correct++
+		}
+		total += result.Score
+		graded++
+		results = append(results, result)
+	}
+
+	questionIDs := make(map[string]bool, len(questions))
+	for _, q := range questions {
+		questionIDs[q.QuestionID] = true
+	}
+	telemetry = sanitizeTelemetry(telemetry, questionIDs)
+	flags := detectAnomalies(telemetry, results, s.opts.Anomaly)
+
+	resultsJSON, _ := json.Marshal(results)
+	attempt := &models.QuizShareAttempt{
+		AttemptID:      uuid.New().String(),
+		ShareID:        share.ShareID,
+		UserID:         userID,
+		Nickname:       nickname,
+		CorrectCount:   correct,
+		TotalQuestions: len(results),
+		Results:        string(resultsJSON),
+		SubmittedAt:    time.Now(),
+		Flagged:        len(flags) > 0,
+	}
+	// 待评阅的题目不计入平均分
+	if graded > 0 {
+		attempt.Score = total / float32(graded)
+	}
+	if telemetry != nil {
+		data, _ := json.Marshal(telemetry)
+		attempt.Telemetry = string(data)
+	}
+	if len(flags) > 0 {
+		data, _ := json.Marshal(flags)
+		attempt.Flags = string(data)
+		s.logger.Infof("分享 %s 的作答 %s 有 %d 项异常标记", share.ShareID, attempt.AttemptID, len(flags))
+	}
+	if err := s.repo.CreateShareAttempt(attempt); err != nil {
+		return nil, fmt.Errorf("保存作答记录失败: %w", err)
+	}
+	return attempt, nil
+}
+
+// isSubjective 是否为需要 LLM 评分的主观题（简答与论述，多空题按分项计分除外）
+func isSubjective(q *models.Question) bool {
+	return len(q.GetParts()) == 0 && (q.Type == models.ShortAnswer || q.Type == models.Essay)
+}
+
+// 获取分享的作答记录，仅创建者可查看；flaggedOnly 时只返回有异常标记的作答
+func (s *ShareService) ListAttempts(shareID, ownerID string, flaggedOnly bool, page, pageSize int) ([]*models.QuizShareAttempt, int64, error) {
+	if ownerID == "" {
+		return nil, 0, ErrShareForbidden
+	}
+	if _, err := s.lookup(shareID, ownerID); err != nil {
+		return nil, 0, err
+	}
+	return s.repo.ListShareAttempts(shareID, flaggedOnly, page, pageSize)
+}
+
+func (s *ShareService) lookup(shareID, ownerID string) (*models.QuizShare, error) {
+	share, err := s.repo.GetShareByID(shareID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrShareNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("获取分享失败: %w", err)
+	}
+	if ownerID == "" {
+		if !share.Active(time.Now()) {
+			return nil, ErrShareNotFound
+		}
+	} else if share.OwnerID != ownerID {
+		return nil, ErrShareForbidden
+	}
+	return share, nil
+}
+
+// 按分享时的顺序返回题目，分享后被删除的题目跳过
+func (s *ShareService) questions(share *models.QuizShare) ([]*models.Question, error) {
+	ids := share.GetQuestionIDs()
+	found, err := s.repo.GetQuestionsByIDs(ids)
+	if err != nil {
+		return nil, fmt.Errorf("获取题目失败: %w", err)
+	}
+	byID := make(map[string]*models.Question, len(found))
+	for _, q := range found {
+		byID[q.QuestionID] = q
+	}
+	questions := make([]*models.Question, 0, len(ids))
+	for _, id := range ids {
+		if q := byID[id]; q != nil {
+			questions = append(questions, q)
+		}
+	}
+	return questions, nil
+}
+
+func dedupe(ids []string) []string {
+	seen := make(map[string]bool, len(ids))
+	out := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		out = append(out, id)
+	}
+	return out
+}
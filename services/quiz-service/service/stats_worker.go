@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/RigelNana/arkstudy/quiz-service/models"
+	"github.com/RigelNana/arkstudy/quiz-service/repository"
+)
+
+// 知识点统计异步重算任务：轮询答题事件表，按事件重算对应用户的知识点统计
+type StatsWorker struct {
+	repo        *repository.QuizRepository
+	logger      *logrus.Logger
+	interval    time.Duration
+	maxBackoff  time.Duration
+	batchSize   int
+	maxAttempts int
+}
+
+func NewStatsWorker(repo *repository.QuizRepository, logger *logrus.Logger, interval, maxBackoff time.Duration, batchSize, maxAttempts int) *StatsWorker {
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+	if maxBackoff < interval {
+		maxBackoff = interval
+	}
+	if batchSize <= 0 {
+		batchSize = 50
+	}
+	if maxAttempts <= 0 {
+		maxAttempts = 5
+	}
+	return &StatsWorker{
+		repo:        repo,
+		logger:      logger,
+		interval:    interval,
+		maxBackoff:  maxBackoff,
+		batchSize:   batchSize,
+		maxAttempts: maxAttempts,
+	}
+}
+
+// 在后台持续处理事件，直到 ctx 取消。一轮处理出错时轮询间隔加倍（不超过 maxBackoff），
+// 避免数据库故障期间反复重试同一批事件
+func (w *StatsWorker) Start(ctx context.Context) {
+	go func() {
+		delay := w.interval
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-timer.C:
+			}
+			if w.drain(ctx) {
+				delay = w.interval
+			} else {
+				delay = min(delay*2, w.maxBackoff)
+				w.logger.Warnf("答题事件处理失败，%s 后重试", delay)
+			}
+			timer.Reset(delay)
+		}
+	}()
+}
+
+// 连续处理直到没有待处理事件；出错或有事件处理失败时停止本轮并返回 false，失败的事件留待下一轮重试
+func (w *StatsWorker) drain(ctx context.Context) bool {
+	for ctx.Err() == nil {
+		n, failed, err := w.repo.ProcessPendingAnswerEvents(w.batchSize, w.maxAttempts, w.handle)
+		if err != nil {
+			w.logger.Errorf("处理答题事件失败: %v", err)
+			return false
+		}
+		if n > 0 {
+			w.logger.Debugf("处理了 %d 条答题事件", n)
+		}
+		if failed > 0 {
+			w.logger.Errorf("%d 条答题事件处理失败", failed)
+			return false
+		}
+		if n == 0 {
+			return true
+		}
+	}
+	return true
+}
+
+func (w *StatsWorker) handle(txRepo *repository.QuizRepository, event *models.AnswerEvent) error {
+	var knowledgePoints []string
+	if event.KnowledgePoints != "" {
+		if err := json.Unmarshal([]byte(event.KnowledgePoints), &knowledgePoints); err != nil {
+			return err
+		}
+	}
+	for _, kp := range knowledgePoints {
+		if err := txRepo.CalculateAndUpdateKnowledgeStats(event.UserID, event.MaterialID, kp); err != nil {
+			return err
+		}
+	}
+	return nil
+}
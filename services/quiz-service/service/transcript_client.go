@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/RigelNana/arkstudy/pkg/discovery"
+	"github.com/RigelNana/arkstudy/pkg/registry"
+	"github.com/sirupsen/logrus"
+
+	asrPb "github.com/RigelNana/arkstudy/proto/asr"
+)
+
+// 转写分段合并为出题片段时单个片段的长度上限（字节），过短的分段单独编号会让模型难以引用
+const maxTranscriptPassageLen = 600
+
+type TranscriptClient struct {
+	client asrPb.ASRServiceClient
+	logger *logrus.Logger
+}
+
+func NewTranscriptClient(asrServiceAddr string, logger *logrus.Logger) (*TranscriptClient, error) {
+	conn, err := discovery.DialAddr(registry.ASR.Name, asrServiceAddr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to ASR service: %v", err)
+	}
+	return &TranscriptClient{
+		client: asrPb.NewASRServiceClient(conn),
+		logger: logger,
+	}, nil
+}
+
+// TimeRange 转写的时间范围（秒），End 为 0 表示到结尾
+type TimeRange struct {
+	Start float32
+	End   float32
+}
+
+// 分段与时间范围有重叠即视为在范围内，避免跨越边界的句子被丢弃
+func (r TimeRange) contains(start, end float32) bool {
+	if r.End > 0 && start >= r.End {
+		return false
+	}
+	return end > r.Start
+}
+
+func (r TimeRange) String() string {
+	if r.End > 0 {
+		return formatTimecode(r.Start) + "-" + formatTimecode(r.End)
+	}
+	return formatTimecode(r.Start) + "-结尾"
+}
+
+// 获取时间范围内的转写分段，按时间顺序合并为出题片段，片段时间码为首尾分段的起止时间
+func (c *TranscriptClient) GetTranscriptPassages(ctx context.Context, materialID, userID string, r TimeRange) ([]MaterialPassage, error) {
+	c.logger.Infof("获取转写内容，材料ID: %s, 用户ID: %s, 时间范围: %s", materialID, userID, r)
+
+	resp, err := c.client.GetSegments(ctx, &asrPb.GetSegmentsRequest{MaterialId: materialID, UserId: userID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transcript segments: %v", err)
+	}
+	if !resp.Success {
+		return nil, fmt.Errorf("failed to get transcript segments: %s", resp.Message)
+	}
+
+	var passages []MaterialPassage
+	var text strings.Builder
+	var chunkID string
+	var start, end float32
+	flush := func() {
+		if text.Len() == 0 {
+			return
+		}
+		passages = append(passages, MaterialPassage{
+			ID:         fmt.Sprintf("S%d", len(passages)+1),
+			ChunkID:    chunkID,
+			MaterialID: materialID,
+			Timecode:   formatTimecode(start) + "-" + formatTimecode(end),
+			Content:    text.String(),
+		})
+		text.Reset()
+	}
+	for _, seg := range resp.Segments {
+		content := strings.TrimSpace(seg.Text)
+		if content == "" || !r.contains(seg.StartTime, seg.EndTime) {
+			continue
+		}
+		if text.Len() > 0 && text.Len()+len(content) > maxTranscriptPassageLen {
+			flush()
+		}
+		if text.Len() == 0 {
+			// 引用的分片 ID 取片段中第一个转写分段的 ID
+			chunkID, start = seg.Id, seg.StartTime
+		} else {
+			text.WriteString(" ")
+		}
+		text.WriteString(content)
+		end = seg.EndTime
+	}
+	flush()
+
+	if len(passages) == 0 {
+		return nil, fmt.Errorf("no transcript found for material %s in range %s", materialID, r)
+	}
+
+	passages = limitPassages(passages, maxMaterialContentLen)
+
+	c.logger.Infof("获取到转写内容，片段数: %d", len(passages))
+	return passages, nil
+}
+
+// 将秒数格式化为 "00:00:05" 形式的时间码
+func formatTimecode(seconds float32) string {
+	if seconds < 0 {
+		seconds = 0
+	}
+	total := int(seconds)
+	return fmt.Sprintf("%02d:%02d:%02d", total/3600, total%3600/60, total%60)
+}
@@ -0,0 +1,236 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+
+	"github.com/RigelNana/arkstudy/quiz-service/models"
+	"github.com/RigelNana/arkstudy/quiz-service/repository"
+)
+
+var (
+	ErrQuestionEditForbidden    = errors.New("只能编辑本人创建的题目")
+	ErrQuestionVersionConflict  = errors.New("题目已被修改，请刷新后重试")
+	ErrQuestionVersionNotFound  = errors.New("题目版本不存在")
+	ErrQuestionVersionForbidden = errors.New("无权查看该题目版本")
+)
+
+// 题目编辑内容，整体替换题目的可编辑字段；题型与出题依据不可编辑
+type QuestionEdit struct {
+	Content          string
+	Options          []string
+	CorrectAnswer    string
+	AnswerAliases    []string
+	NumericTolerance float64
+	Parts            []models.QuestionPart
+	Explanation      string
+	Difficulty       models.DifficultyLevel
+	KnowledgePoints  []string
+}
+
+// 题目版本服务：编辑题目时保存不可变的版本快照，记录编辑人、时间与修改的字段
+type VersionService struct {
+	repo   *repository.QuizRepository
+	logger *logrus.Logger
+}
+
+func NewVersionService(repo *repository.QuizRepository, logger *logrus.Logger) *VersionService {
+	return &VersionService{repo: repo, logger: logger}
+}
+
+// 为新生成的题目设置版本 1，返回需与题目一同保存的版本快照
+func (s *VersionService) InitialVersions(questions []*models.Question) []*models.QuestionVersion {
+	versions := make([]*models.QuestionVersion, 0, len(questions))
+	for _, q := range questions {
+		q.Version = 1
+		versions = append(versions, q.Snapshot(q.CreatorID, nil, ""))
+	}
+	return versions
+}
+
+// 编辑题目并保存新版本，返回编辑后的题目与修改的字段。expectedVersion 大于 0 时须与当前版本一致，避免覆盖他人的修改；
+// 课程题目编辑后回到草稿，需重新提交审核
+func (s *VersionService) Update(editorID, questionID string, expectedVersion int, edit QuestionEdit, note string) (*models.Question, []string, error) {
+	question, err := s.repo.GetQuestionByID(questionID)
+	if err != nil {
+		return nil, nil, ErrQuestionNotFound
+	}
+	if question.CreatorID != editorID {
+		return nil, nil, ErrQuestionEditForbidden
+	}
+	if expectedVersion > 0 && expectedVersion != question.Version {
+		return nil, nil, ErrQuestionVersionConflict
+	}
+	if err := edit.validate(question.Type); err != nil {
+		return nil, nil, err
+	}
+
+	before := *question
+	updates, changed := edit.apply(question)
+	if len(changed) == 0 {
+		return nil, nil, errors.New("题目内容没有变化")
+	}
+	question.Version = before.Version + 1
+	updates["version"] = question.Version
+	if question.RequiresReview() && question.ReviewStatus != models.ReviewStatusDraft {
+		question.ReviewStatus, question.ReviewedAt = models.ReviewStatusDraft, nil
+		updates["review_status"] = models.ReviewStatusDraft
+		updates["reviewed_at"] = nil
+	}
+
+	err = s.repo.AsActor(editorID).Transaction(func(txRepo *repository.QuizRepository) error {
+		// 早于版本功能的题目没有初始版本，编辑前补存当前内容
+		if _, err := txRepo.GetQuestionVersion(questionID, before.Version); errors.Is(err, gorm.ErrRecordNotFound) {
+			if err := txRepo.CreateQuestionVersions([]*models.QuestionVersion{before.Snapshot(before.CreatorID, nil, "")}); err != nil {
+				return fmt.Errorf("补存原始版本失败: %w", err)
+			}
+		} else if err != nil {
+			return err
+		}
+		n, err := txRepo.UpdateQuestionContent(questionID, before.Version, updates)
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return ErrQuestionVersionConflict
+		}
+		return txRepo.CreateQuestionVersions([]*models.QuestionVersion{question.Snapshot(editorID, changed, strings.TrimSpace(note))})
+	})
+	if errors.Is(err, ErrQuestionVersionConflict) {
+		return nil, nil, err
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("保存题目失败: %w", err)
+	}
+	s.logger.Infof("用户 %s 编辑题目 %s，版本 %d，修改字段 %v", editorID, questionID, question.Version, changed)
+	return question, changed, nil
+}
+
+// 获取题目的全部版本，仅创建者与审核人可查看
+func (s *VersionService) List(questionID, userID string) ([]*models.QuestionVersion, error) {
+	question, err := s.repo.GetQuestionByID(questionID)
+	if err != nil {
+		return nil, ErrQuestionNotFound
+	}
+	if userID == "" || (userID != question.CreatorID && userID != question.ReviewerID) {
+		return nil, ErrQuestionVersionForbidden
+	}
+	versions, err := s.repo.ListQuestionVersions(questionID)
+	if err != nil {
+		return nil, fmt.Errorf("获取题目版本失败: %w", err)
+	}
+	return versions, nil
+}
+
+// 获取题目的指定版本。创建者与审核人可查看全部版本，其他用户只能查看自己作答过的版本，用于申诉时核对题面
+func (s *VersionService) Get(questionID string, version int, userID string) (*models.QuestionVersion, error) {
+	question, err := s.repo.GetQuestionByID(questionID)
+	if err != nil {
+		return nil, ErrQuestionNotFound
+	}
+	if userID == "" {
+		return nil, ErrQuestionVersionForbidden
+	}
+	if userID != question.CreatorID && userID != question.ReviewerID {
+		answered, err := s.repo.HasAnsweredVersion(questionID, userID, version)
+		if err != nil {
+			return nil, fmt.Errorf("查询答题记录失败: %w", err)
+		}
+		if !answered {
+			return nil, ErrQuestionVersionForbidden
+		}
+	}
+	v, err := s.repo.GetQuestionVersion(questionID, version)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrQuestionVersionNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("获取题目版本失败: %w", err)
+	}
+	return v, nil
+}
+
+func (e *QuestionEdit) validate(questionType models.QuestionType) error {
+	if strings.TrimSpace(e.Content) == "" {
+		return errors.New("题目内容不能为空")
+	}
+	if e.Difficulty < models.Easy || e.Difficulty > models.Hard {
+		return errors.New("无效的难度级别")
+	}
+	if e.NumericTolerance < 0 {
+		return errors.New("数值误差不能为负数")
+	}
+	switch questionType {
+	case models.MultipleChoice:
+		if len(e.Options) < 2 {
+			return errors.New("选择题至少需要两个选项")
+		}
+		fallthrough
+	case models.TrueFalse, models.FillBlank:
+		if strings.TrimSpace(e.CorrectAnswer) == "" && len(e.Parts) == 0 {
+			return errors.New("客观题需要填写正确答案")
+		}
+	}
+	return nil
+}
+
+// apply 将编辑内容写入题目，返回需更新的列与修改的字段名
+func (e *QuestionEdit) apply(q *models.Question) (map[string]interface{}, []string) {
+	updates := make(map[string]interface{})
+	var changed []string
+	set := func(field string, current *string, value string) {
+		if *current != value {
+			*current = value
+			updates[field] = value
+			changed = append(changed, field)
+		}
+	}
+
+	set("content", &q.Content, strings.TrimSpace(e.Content))
+	set("options", &q.Options, marshalStrings(e.Options))
+	correctAnswer := strings.TrimSpace(e.CorrectAnswer)
+	// 与生成题目一致：多空题未给出整体答案时，用分项答案拼接
+	if correctAnswer == "" && len(e.Parts) > 0 {
+		answers := make([]string, len(e.Parts))
+		for i, p := range e.Parts {
+			answers[i] = p.CorrectAnswer
+		}
+		correctAnswer = strings.Join(answers, " | ")
+	}
+	set("correct_answer", &q.CorrectAnswer, correctAnswer)
+	set("answer_aliases", &q.AnswerAliases, marshalAliases(e.AnswerAliases))
+	set("parts", &q.Parts, marshalParts(e.Parts))
+	set("explanation", &q.Explanation, strings.TrimSpace(e.Explanation))
+	set("knowledge_points", &q.KnowledgePoints, marshalStrings(e.KnowledgePoints))
+	if q.NumericTolerance != e.NumericTolerance {
+		q.NumericTolerance = e.NumericTolerance
+		updates["numeric_tolerance"] = e.NumericTolerance
+		changed = append(changed, "numeric_tolerance")
+	}
+	if q.Difficulty != e.Difficulty {
+		q.Difficulty = e.Difficulty
+		updates["difficulty"] = e.Difficulty
+		changed = append(changed, "difficulty")
+	}
+	return updates, changed
+}
+
+// 序列化字符串列表，去掉空白项，为空时返回空串
+func marshalStrings(values []string) string {
+	var cleaned []string
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			cleaned = append(cleaned, v)
+		}
+	}
+	if len(cleaned) == 0 {
+		return ""
+	}
+	data, _ := json.Marshal(cleaned)
+	return string(data)
+}
@@ -0,0 +1,69 @@
+package config
+
+import (
+	"log"
+	"os"
+	"time"
+
+	"github.com/RigelNana/arkstudy/pkg/registry"
+	"github.com/joho/godotenv"
+)
+
+type Config struct {
+	DBUser     string
+	DBPassword string
+	DBHost     string
+	DBPort     string
+	DBName     string
+
+	GRPCPort string
+
+	// HeartbeatMaxGap 两次心跳之间最多计入的时长，页面休眠或断网期间不会被计为学习时间
+	HeartbeatMaxGap time.Duration
+	// SessionIdleTimeout 会话超过该时长没有心跳即视为已结束
+	SessionIdleTimeout time.Duration
+	// Location 按天/周汇总时使用的时区
+	Location *time.Location
+}
+
+func LoadConfig() *Config {
+	if err := godotenv.Load(); err != nil {
+		log.Println("No .env file found, using system env")
+	}
+	return &Config{
+		DBUser:             os.Getenv("DB_USER"),
+		DBPassword:         os.Getenv("DB_PASSWORD"),
+		DBHost:             os.Getenv("DB_HOST"),
+		DBPort:             os.Getenv("DB_PORT"),
+		DBName:             os.Getenv("DB_NAME"),
+		GRPCPort:           registry.Study.ListenPort(),
+		HeartbeatMaxGap:    getEnvDuration("STUDY_HEARTBEAT_MAX_GAP", 2*time.Minute),
+		SessionIdleTimeout: getEnvDuration("STUDY_SESSION_IDLE_TIMEOUT", 10*time.Minute),
+		Location:           getEnvLocation("STUDY_TIMEZONE", "Asia/Shanghai"),
+	}
+}
+
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if d, err := time.ParseDuration(value); err == nil {
+			return d
+		}
+	}
+	return defaultValue
+}
+
+func getEnvLocation(key, defaultValue string) *time.Location {
+	loc, err := time.LoadLocation(getEnv(key, defaultValue))
+	if err != nil {
+		log.Printf("invalid %s, falling back to UTC: %v", key, err)
+		return time.UTC
+	}
+	return loc
+}
@@ -0,0 +1,158 @@
+package rpc
+
+import (
+	"context"
+	"log"
+
+	"github.com/RigelNana/arkstudy/proto/study"
+	"github.com/RigelNana/arkstudy/services/study-service/models"
+	"github.com/RigelNana/arkstudy/services/study-service/service"
+
+	"github.com/google/uuid"
+)
+
+type StudyRPCServer struct {
+	study.UnimplementedStudyServiceServer
+	svc service.StudyService
+}
+
+func NewStudyRPCServer(svc service.StudyService) *StudyRPCServer { return &StudyRPCServer{svc: svc} }
+
+func toSessionInfo(s *models.StudySession) *study.StudySession {
+	info := &study.StudySession{
+		Id:              s.ID.String(),
+		UserId:          s.UserID.String(),
+		MaterialId:      s.MaterialID,
+		Status:          s.Status,
+		StartedAt:       s.StartedAt.Unix(),
+		LastHeartbeatAt: s.LastHeartbeatAt.Unix(),
+		DurationSeconds: s.DurationSeconds,
+	}
+	if s.EndedAt != nil {
+		info.EndedAt = s.EndedAt.Unix()
+	}
+	return info
+}
+
+func toGoalInfo(g *models.StudyGoal) *study.StudyGoal {
+	return &study.StudyGoal{Period: g.Period, TargetMinutes: int32(g.TargetMinutes)}
+}
+
+func (s *StudyRPCServer) StartSession(ctx context.Context, in *study.StartSessionRequest) (*study.SessionResponse, error) {
+	userID, err := uuid.Parse(in.UserId)
+	if err != nil {
+		return &study.SessionResponse{Success: false, Message: "invalid user_id"}, nil
+	}
+	sess, err := s.svc.StartSession(userID, in.MaterialId)
+	if err != nil {
+		log.Printf("StartSession failed: %v", err)
+		return &study.SessionResponse{Success: false, Message: err.Error()}, nil
+	}
+	log.Printf("StartSession success: ID=%s, UserID=%s", sess.ID.String(), in.UserId)
+	return &study.SessionResponse{Success: true, Message: "ok", Session: toSessionInfo(sess)}, nil
+}
+
+func (s *StudyRPCServer) Heartbeat(ctx context.Context, in *study.SessionActionRequest) (*study.SessionResponse, error) {
+	return s.sessionAction(in, s.svc.Heartbeat)
+}
+
+func (s *StudyRPCServer) StopSession(ctx context.Context, in *study.SessionActionRequest) (*study.SessionResponse, error) {
+	return s.sessionAction(in, s.svc.StopSession)
+}
+
+func (s *StudyRPCServer) sessionAction(in *study.SessionActionRequest, action func(sessionID, userID uuid.UUID) (*models.StudySession, error)) (*study.SessionResponse, error) {
+	sessionID, err := uuid.Parse(in.SessionId)
+	if err != nil {
+		return &study.SessionResponse{Success: false, Message: "invalid session_id"}, nil
+	}
+	userID, err := uuid.Parse(in.UserId)
+	if err != nil {
+		return &study.SessionResponse{Success: false, Message: "invalid user_id"}, nil
+	}
+	sess, err := action(sessionID, userID)
+	if err != nil {
+		resp := &study.SessionResponse{Success: false, Message: err.Error()}
+		if sess != nil {
+			resp.Session = toSessionInfo(sess)
+		}
+		return resp, nil
+	}
+	return &study.SessionResponse{Success: true, Message: "ok", Session: toSessionInfo(sess)}, nil
+}
+
+func (s *StudyRPCServer) SetGoal(ctx context.Context, in *study.SetGoalRequest) (*study.GoalResponse, error) {
+	userID, err := uuid.Parse(in.UserId)
+	if err != nil {
+		return &study.GoalResponse{Success: false, Message: "invalid user_id"}, nil
+	}
+	g, err := s.svc.SetGoal(userID, in.Period, int(in.TargetMinutes))
+	if err != nil {
+		return &study.GoalResponse{Success: false, Message: err.Error()}, nil
+	}
+	return &study.GoalResponse{Success: true, Message: "ok", Goal: toGoalInfo(g)}, nil
+}
+
+func (s *StudyRPCServer) ListGoals(ctx context.Context, in *study.ListGoalsRequest) (*study.ListGoalsResponse, error) {
+	userID, err := uuid.Parse(in.UserId)
+	if err != nil {
+		return &study.ListGoalsResponse{Success: false, Message: "invalid user_id"}, nil
+	}
+	goals, err := s.svc.ListGoals(userID)
+	if err != nil {
+		return &study.ListGoalsResponse{Success: false, Message: err.Error()}, nil
+	}
+	resp := &study.ListGoalsResponse{Success: true, Message: "ok"}
+	for _, g := range goals {
+		resp.Goals = append(resp.Goals, toGoalInfo(g))
+	}
+	return resp, nil
+}
+
+func (s *StudyRPCServer) DeleteGoal(ctx context.Context, in *study.DeleteGoalRequest) (*study.DeleteGoalResponse, error) {
+	userID, err := uuid.Parse(in.UserId)
+	if err != nil {
+		return &study.DeleteGoalResponse{Success: false, Message: "invalid user_id"}, nil
+	}
+	deleted, err := s.svc.DeleteGoal(userID, in.Period)
+	if err != nil {
+		return &study.DeleteGoalResponse{Success: false, Message: err.Error()}, nil
+	}
+	if !deleted {
+		return &study.DeleteGoalResponse{Success: false, Message: "goal not found"}, nil
+	}
+	return &study.DeleteGoalResponse{Success: true, Message: "ok"}, nil
+}
+
+func (s *StudyRPCServer) GetStudyStats(ctx context.Context, in *study.GetStudyStatsRequest) (*study.GetStudyStatsResponse, error) {
+	userID, err := uuid.Parse(in.UserId)
+	if err != nil {
+		return &study.GetStudyStatsResponse{Success: false, Message: "invalid user_id"}, nil
+	}
+	stats, err := s.svc.GetStats(userID, int(in.Days))
+	if err != nil {
+		log.Printf("GetStudyStats failed: %v", err)
+		return &study.GetStudyStatsResponse{Success: false, Message: err.Error()}, nil
+	}
+	resp := &study.GetStudyStatsResponse{
+		Success:         true,
+		Message:         "ok",
+		TodaySeconds:    stats.TodaySeconds,
+		WeekSeconds:     stats.WeekSeconds,
+		TotalSeconds:    stats.TotalSeconds,
+		StudyStreakDays: stats.StudyStreakDays,
+	}
+	for _, d := range stats.Daily {
+		resp.Daily = append(resp.Daily, &study.DailyStudy{Date: d.Day, Seconds: d.Seconds})
+	}
+	for _, g := range stats.Goals {
+		resp.Goals = append(resp.Goals, &study.GoalProgress{
+			Period:          g.Goal.Period,
+			TargetMinutes:   int32(g.Goal.TargetMinutes),
+			ProgressSeconds: g.ProgressSeconds,
+			Achieved:        g.Achieved,
+			CurrentStreak:   g.CurrentStreak,
+			LongestStreak:   g.LongestStreak,
+		})
+	}
+	return resp, nil
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"log"
+	"net"
+
+	"github.com/RigelNana/arkstudy/pkg/discovery"
+	"github.com/RigelNana/arkstudy/pkg/interceptor"
+	"github.com/RigelNana/arkstudy/pkg/metrics"
+	grpcMetrics "github.com/RigelNana/arkstudy/pkg/metrics/grpc"
+	"github.com/RigelNana/arkstudy/pkg/registry"
+	"github.com/RigelNana/arkstudy/proto/study"
+	"github.com/RigelNana/arkstudy/services/study-service/config"
+	"github.com/RigelNana/arkstudy/services/study-service/database"
+	srpc "github.com/RigelNana/arkstudy/services/study-service/handler/rpc"
+	"github.com/RigelNana/arkstudy/services/study-service/models"
+	"github.com/RigelNana/arkstudy/services/study-service/repository"
+	"github.com/RigelNana/arkstudy/services/study-service/service"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/reflection"
+	"gorm.io/gorm"
+)
+
+func autoMigrate(db *gorm.DB) {
+	if err := db.AutoMigrate(&models.StudySession{}, &models.StudyDailyTotal{}, &models.StudyGoal{}); err != nil {
+		log.Fatalf("auto migrate failed: %v", err)
+	}
+}
+
+func main() {
+	// 启动内部 HTTP 监听：/metrics 与 /healthz、/readyz，地址由 METRICS_ADDR 配置
+	log.Printf("Prometheus metrics server started on %s", metrics.StartMetricsServer())
+
+	cfg := config.LoadConfig()
+	if err := registry.Validate(registry.Study); err != nil {
+		log.Fatalf("%v", err)
+	}
+	db := database.InitDB(cfg)
+	if sqlDB, err := db.DB(); err == nil {
+		metrics.AddReadinessCheck("database", sqlDB.PingContext)
+	}
+	autoMigrate(db)
+
+	repo := repository.NewStudyRepository(db)
+	svc := service.NewStudyService(repo, cfg)
+
+	// 创建带监控的 gRPC 服务器
+	// panic 恢复、错误码规范化与慢请求日志（GRPC_SLOW_THRESHOLD）
+	interceptorOpts := interceptor.DefaultOptions("study-service")
+	grpcServer := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(grpcMetrics.UnaryServerInterceptor("study-service"), interceptor.UnaryServerInterceptor(interceptorOpts)),
+		grpc.ChainStreamInterceptor(grpcMetrics.StreamServerInterceptor("study-service"), interceptor.StreamServerInterceptor(interceptorOpts)),
+	)
+
+	study.RegisterStudyServiceServer(grpcServer, srpc.NewStudyRPCServer(svc))
+	// 健康检查供客户端在多个副本间故障转移（见 pkg/discovery）
+	discovery.RegisterHealth(grpcServer)
+	// Enable server reflection
+	reflection.Register(grpcServer)
+
+	lis, err := net.Listen("tcp", ":"+cfg.GRPCPort)
+	if err != nil {
+		log.Fatalf("listen error: %v", err)
+	}
+	log.Printf("Study gRPC server listening on %s", cfg.GRPCPort)
+	metrics.SetReady(true)
+	if err := grpcServer.Serve(lis); err != nil {
+		log.Fatalf("serve error: %v", err)
+	}
+}
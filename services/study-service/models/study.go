@@ -0,0 +1,48 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// 会话状态
+const (
+	SessionStatusActive = "active"
+	SessionStatusEnded  = "ended"
+)
+
+// 目标周期
+const (
+	GoalPeriodDaily  = "daily"
+	GoalPeriodWeekly = "weekly"
+)
+
+// StudySession 一次学习会话，时长由心跳逐段累加
+type StudySession struct {
+	Base
+	UserID          uuid.UUID `gorm:"type:uuid;not null;index:idx_study_sessions_user_status,priority:1"`
+	MaterialID      string    `gorm:"type:varchar(64)"` // 可选，正在学习的资料
+	Status          string    `gorm:"type:varchar(20);not null;default:'active';index:idx_study_sessions_user_status,priority:2"`
+	StartedAt       time.Time `gorm:"not null"`
+	LastHeartbeatAt time.Time `gorm:"not null"`
+	EndedAt         *time.Time
+	DurationSeconds int64 `gorm:"not null;default:0"`
+}
+
+// StudyDailyTotal 按用户、自然日汇总的学习时长，心跳时增量更新。
+// Day 为配置时区下的日期（YYYY-MM-DD），跨零点的会话会分别计入两天。
+type StudyDailyTotal struct {
+	UserID    uuid.UUID `gorm:"type:uuid;primaryKey"`
+	Day       string    `gorm:"type:varchar(10);primaryKey"`
+	Seconds   int64     `gorm:"not null;default:0"`
+	UpdatedAt time.Time
+}
+
+// StudyGoal 用户设定的学习目标，每个周期最多一个
+type StudyGoal struct {
+	Base
+	UserID        uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_study_goals_user_period,priority:1"`
+	Period        string    `gorm:"type:varchar(20);not null;uniqueIndex:idx_study_goals_user_period,priority:2"`
+	TargetMinutes int       `gorm:"not null"`
+}
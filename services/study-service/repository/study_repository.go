@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/RigelNana/arkstudy/services/study-service/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type StudyRepository interface {
+	CreateSession(session *models.StudySession) error
+	// WithSessionLocked 在事务中锁定会话行后执行 fn，fn 中的更新与该会话的心跳互斥
+	WithSessionLocked(sessionID, userID uuid.UUID, fn func(tx *gorm.DB, session *models.StudySession) error) error
+	// EndIdleSessions 结束用户所有最后心跳早于 before 的活跃会话，结束时间记为最后一次心跳
+	EndIdleSessions(userID uuid.UUID, before time.Time) error
+	// AddDailySeconds 将时长累加到某天的汇总中
+	AddDailySeconds(tx *gorm.DB, userID uuid.UUID, day string, seconds int64) error
+	// ListDailyTotals 返回 [fromDay, toDay] 内有记录的日期汇总，按日期升序
+	ListDailyTotals(userID uuid.UUID, fromDay, toDay string) ([]*models.StudyDailyTotal, error)
+
+	UpsertGoal(goal *models.StudyGoal) error
+	ListGoals(userID uuid.UUID) ([]*models.StudyGoal, error)
+	DeleteGoal(userID uuid.UUID, period string) (bool, error)
+}
+
+type StudyRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewStudyRepository(db *gorm.DB) StudyRepository {
+	return &StudyRepositoryImpl{db: db}
+}
+
+func (r *StudyRepositoryImpl) CreateSession(session *models.StudySession) error {
+	return r.db.Create(session).Error
+}
+
+func (r *StudyRepositoryImpl) WithSessionLocked(sessionID, userID uuid.UUID, fn func(tx *gorm.DB, session *models.StudySession) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var session models.StudySession
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND user_id = ?", sessionID, userID).
+			First(&session).Error
+		if err != nil {
+			return err
+		}
+		return fn(tx, &session)
+	})
+}
+
+func (r *StudyRepositoryImpl) EndIdleSessions(userID uuid.UUID, before time.Time) error {
+	return r.db.Model(&models.StudySession{}).
+		Where("user_id = ? AND status = ? AND last_heartbeat_at < ?", userID, models.SessionStatusActive, before).
+		Updates(map[string]interface{}{
+			"status":   models.SessionStatusEnded,
+			"ended_at": gorm.Expr("last_heartbeat_at"),
+		}).Error
+}
+
+func (r *StudyRepositoryImpl) AddDailySeconds(tx *gorm.DB, userID uuid.UUID, day string, seconds int64) error {
+	if tx == nil {
+		tx = r.db
+	}
+	return tx.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "user_id"}, {Name: "day"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"seconds":    gorm.Expr("study_daily_totals.seconds + EXCLUDED.seconds"),
+			"updated_at": gorm.Expr("EXCLUDED.updated_at"),
+		}),
+	}).Create(&models.StudyDailyTotal{UserID: userID, Day: day, Seconds: seconds}).Error
+}
+
+func (r *StudyRepositoryImpl) ListDailyTotals(userID uuid.UUID, fromDay, toDay string) ([]*models.StudyDailyTotal, error) {
+	var totals []*models.StudyDailyTotal
+	err := r.db.Where("user_id = ? AND day >= ? AND day <= ?", userID, fromDay, toDay).
+		Order("day ASC").
+		Find(&totals).Error
+	return totals, err
+}
+
+func (r *StudyRepositoryImpl) UpsertGoal(goal *models.StudyGoal) error {
+	// 已软删除的同周期目标直接恢复，避免唯一索引冲突
+	return r.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "user_id"}, {Name: "period"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"target_minutes": goal.TargetMinutes,
+			"updated_at":     gorm.Expr("EXCLUDED.updated_at"),
+			"deleted_at":     nil,
+		}),
+	}).Create(goal).Error
+}
+
+func (r *StudyRepositoryImpl) ListGoals(userID uuid.UUID) ([]*models.StudyGoal, error) {
+	var goals []*models.StudyGoal
+	err := r.db.Where("user_id = ?", userID).Order("period ASC").Find(&goals).Error
+	return goals, err
+}
+
+func (r *StudyRepositoryImpl) DeleteGoal(userID uuid.UUID, period string) (bool, error) {
+	result := r.db.Where("user_id = ? AND period = ?", userID, period).Delete(&models.StudyGoal{})
+	return result.RowsAffected > 0, result.Error
+}
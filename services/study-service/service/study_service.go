@@ -0,0 +1,287 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/RigelNana/arkstudy/services/study-service/config"
+	"github.com/RigelNana/arkstudy/services/study-service/models"
+	"github.com/RigelNana/arkstudy/services/study-service/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	dayLayout = "2006-01-02"
+	// 统计接口返回的每日明细最多覆盖的天数
+	maxStatsDays = 90
+	// 计算连续天数/周数时回看的范围
+	streakLookbackDays = 371
+	// 单个目标允许的最大分钟数（一周）
+	maxGoalMinutes = 7 * 24 * 60
+)
+
+var (
+	ErrSessionNotFound = errors.New("session not found")
+	ErrSessionEnded    = errors.New("session already ended")
+	ErrSessionExpired  = errors.New("session expired, start a new one")
+	ErrInvalidGoal     = errors.New("invalid goal")
+)
+
+// GoalProgress 目标在当前周期的完成情况
+type GoalProgress struct {
+	Goal            *models.StudyGoal
+	ProgressSeconds int64
+	Achieved        bool
+	CurrentStreak   int32
+	LongestStreak   int32
+}
+
+type DailyStudy struct {
+	Day     string
+	Seconds int64
+}
+
+type StudyStats struct {
+	TodaySeconds    int64
+	WeekSeconds     int64
+	TotalSeconds    int64
+	Daily           []DailyStudy
+	Goals           []GoalProgress
+	StudyStreakDays int32
+}
+
+type StudyService interface {
+	StartSession(userID uuid.UUID, materialID string) (*models.StudySession, error)
+	Heartbeat(sessionID, userID uuid.UUID) (*models.StudySession, error)
+	StopSession(sessionID, userID uuid.UUID) (*models.StudySession, error)
+
+	SetGoal(userID uuid.UUID, period string, targetMinutes int) (*models.StudyGoal, error)
+	ListGoals(userID uuid.UUID) ([]*models.StudyGoal, error)
+	DeleteGoal(userID uuid.UUID, period string) (bool, error)
+
+	GetStats(userID uuid.UUID, days int) (*StudyStats, error)
+}
+
+type StudyServiceImpl struct {
+	repo repository.StudyRepository
+	cfg  *config.Config
+}
+
+func NewStudyService(r repository.StudyRepository, cfg *config.Config) StudyService {
+	return &StudyServiceImpl{repo: r, cfg: cfg}
+}
+
+func (s *StudyServiceImpl) StartSession(userID uuid.UUID, materialID string) (*models.StudySession, error) {
+	now := time.Now()
+	// 顺带结束该用户已失去心跳的旧会话，例如直接关闭了页面
+	if err := s.repo.EndIdleSessions(userID, now.Add(-s.cfg.SessionIdleTimeout)); err != nil {
+		return nil, err
+	}
+	session := &models.StudySession{
+		UserID:          userID,
+		MaterialID:      materialID,
+		Status:          models.SessionStatusActive,
+		StartedAt:       now,
+		LastHeartbeatAt: now,
+	}
+	if err := s.repo.CreateSession(session); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+func (s *StudyServiceImpl) Heartbeat(sessionID, userID uuid.UUID) (*models.StudySession, error) {
+	return s.advance(sessionID, userID, false)
+}
+
+func (s *StudyServiceImpl) StopSession(sessionID, userID uuid.UUID) (*models.StudySession, error) {
+	return s.advance(sessionID, userID, true)
+}
+
+// advance 将上次心跳到现在的时长（不超过 HeartbeatMaxGap）计入会话与每日汇总，stop 为 true 时同时结束会话。
+// 超过 SessionIdleTimeout 没有心跳的会话在此时结束，本次间隔不计时并返回 ErrSessionExpired。
+func (s *StudyServiceImpl) advance(sessionID, userID uuid.UUID, stop bool) (*models.StudySession, error) {
+	now := time.Now()
+	var result *models.StudySession
+	expired := false
+
+	err := s.repo.WithSessionLocked(sessionID, userID, func(tx *gorm.DB, session *models.StudySession) error {
+		if session.Status != models.SessionStatusActive {
+			return ErrSessionEnded
+		}
+
+		gap := now.Sub(session.LastHeartbeatAt)
+		if gap > s.cfg.SessionIdleTimeout {
+			expired = true
+			endedAt := session.LastHeartbeatAt
+			session.Status = models.SessionStatusEnded
+			session.EndedAt = &endedAt
+			result = session
+			return tx.Save(session).Error
+		}
+
+		credit := gap
+		if credit > s.cfg.HeartbeatMaxGap {
+			credit = s.cfg.HeartbeatMaxGap
+		}
+		for day, seconds := range splitByDay(now.Add(-credit), now, s.cfg.Location) {
+			if seconds <= 0 {
+				continue
+			}
+			if err := s.repo.AddDailySeconds(tx, userID, day, seconds); err != nil {
+				return err
+			}
+			session.DurationSeconds += seconds
+		}
+
+		session.LastHeartbeatAt = now
+		if stop {
+			session.Status = models.SessionStatusEnded
+			session.EndedAt = &now
+		}
+		result = session
+		return tx.Save(session).Error
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrSessionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if expired && !stop {
+		return result, ErrSessionExpired
+	}
+	return result, nil
+}
+
+// splitByDay 将 [start, end) 按 loc 下的自然日切分，返回每天对应的秒数
+func splitByDay(start, end time.Time, loc *time.Location) map[string]int64 {
+	parts := make(map[string]int64)
+	start, end = start.In(loc), end.In(loc)
+	for start.Before(end) {
+		y, m, d := start.Date()
+		next := time.Date(y, m, d+1, 0, 0, 0, 0, loc)
+		if next.After(end) {
+			next = end
+		}
+		parts[start.Format(dayLayout)] += int64(next.Sub(start).Round(time.Second) / time.Second)
+		start = next
+	}
+	return parts
+}
+
+func (s *StudyServiceImpl) SetGoal(userID uuid.UUID, period string, targetMinutes int) (*models.StudyGoal, error) {
+	if period != models.GoalPeriodDaily && period != models.GoalPeriodWeekly {
+		return nil, fmt.Errorf("%w: period must be %q or %q", ErrInvalidGoal, models.GoalPeriodDaily, models.GoalPeriodWeekly)
+	}
+	if targetMinutes <= 0 || targetMinutes > maxGoalMinutes {
+		return nil, fmt.Errorf("%w: target_minutes must be between 1 and %d", ErrInvalidGoal, maxGoalMinutes)
+	}
+	goal := &models.StudyGoal{UserID: userID, Period: period, TargetMinutes: targetMinutes}
+	if err := s.repo.UpsertGoal(goal); err != nil {
+		return nil, err
+	}
+	return goal, nil
+}
+
+func (s *StudyServiceImpl) ListGoals(userID uuid.UUID) ([]*models.StudyGoal, error) {
+	return s.repo.ListGoals(userID)
+}
+
+func (s *StudyServiceImpl) DeleteGoal(userID uuid.UUID, period string) (bool, error) {
+	return s.repo.DeleteGoal(userID, period)
+}
+
+func (s *StudyServiceImpl) GetStats(userID uuid.UUID, days int) (*StudyStats, error) {
+	if days <= 0 {
+		days = 7
+	}
+	if days > maxStatsDays {
+		days = maxStatsDays
+	}
+
+	today := time.Now().In(s.cfg.Location)
+	today = time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, s.cfg.Location)
+	from := today.AddDate(0, 0, -(streakLookbackDays - 1))
+
+	totals, err := s.repo.ListDailyTotals(userID, from.Format(dayLayout), today.Format(dayLayout))
+	if err != nil {
+		return nil, err
+	}
+	byDay := make(map[string]int64, len(totals))
+	for _, t := range totals {
+		byDay[t.Day] = t.Seconds
+	}
+
+	// 回看范围内逐日时长，按日期升序，最后一项为今天
+	daily := make([]int64, streakLookbackDays)
+	for i := range daily {
+		daily[i] = byDay[from.AddDate(0, 0, i).Format(dayLayout)]
+	}
+
+	stats := &StudyStats{TodaySeconds: daily[len(daily)-1]}
+	for i := len(daily) - days; i < len(daily); i++ {
+		day := from.AddDate(0, 0, i)
+		stats.Daily = append(stats.Daily, DailyStudy{Day: day.Format(dayLayout), Seconds: daily[i]})
+		stats.TotalSeconds += daily[i]
+	}
+
+	// 以周一作为一周开始，按周汇总，最后一项为本周
+	weekday := (int(today.Weekday()) + 6) % 7
+	var weekly []int64
+	for end := len(daily) - 1 - weekday; end >= 0; end -= 7 {
+		var sum int64
+		for i := end; i < end+7 && i < len(daily); i++ {
+			sum += daily[i]
+		}
+		weekly = append([]int64{sum}, weekly...)
+	}
+	stats.WeekSeconds = weekly[len(weekly)-1]
+
+	stats.StudyStreakDays, _ = streaks(daily, 1)
+
+	goals, err := s.repo.ListGoals(userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, g := range goals {
+		threshold := int64(g.TargetMinutes) * 60
+		series := daily
+		if g.Period == models.GoalPeriodWeekly {
+			series = weekly
+		}
+		progress := GoalProgress{Goal: g, ProgressSeconds: series[len(series)-1]}
+		progress.Achieved = progress.ProgressSeconds >= threshold
+		progress.CurrentStreak, progress.LongestStreak = streaks(series, threshold)
+		stats.Goals = append(stats.Goals, progress)
+	}
+	return stats, nil
+}
+
+// streaks 计算按时间升序的周期序列中达到 threshold 的连续周期数。
+// 当前周期（最后一项）尚未达标时不打断连续记录，从上一周期开始计算。
+func streaks(values []int64, threshold int64) (current, longest int32) {
+	var run int32
+	for _, v := range values {
+		if v >= threshold {
+			run++
+			if run > longest {
+				longest = run
+			}
+		} else {
+			run = 0
+		}
+	}
+
+	end := len(values) - 1
+	if end >= 0 && values[end] < threshold {
+		end--
+	}
+	for i := end; i >= 0 && values[i] >= threshold; i-- {
+		current++
+	}
+	return current, longest
+}
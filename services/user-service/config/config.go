@@ -1,29 +1,77 @@
 package config
 
 import (
-	"github.com/joho/godotenv"
 	"log"
 	"os"
+	"strconv"
+
+	"github.com/RigelNana/arkstudy/pkg/registry"
+	"github.com/joho/godotenv"
 )
 
 type Config struct {
 	DBUser     string
-    DBPassword string
-    DBHost     string
-    DBPort     string
-    DBName     string
+	DBPassword string
+	DBHost     string
+	DBPort     string
+	DBName     string
+
+	// Kafka，用于消费用户活动事件与任务事件
+	KafkaBrokers           string
+	KafkaTopicUserActivity string
+	KafkaGroupID           string
+	KafkaTopicTaskEvents   string
+	KafkaTaskGroupID       string
+
+	// 每周学习进度邮件：按 DigestTimezone 在每周 DigestWeekday（0 为周日）的 DigestHour 点发送，
+	// 经 KafkaTopicNotifications 交给通知服务投递
+	DigestEnabled           bool
+	DigestWeekday           int
+	DigestHour              int
+	DigestTimezone          string
+	KafkaTopicNotifications string
+	QuizServiceAddr         string
 }
 
 func LoadConfig() *Config {
 	err := godotenv.Load()
-    if err != nil {
-        log.Println("No .env file found, using system env")
-    }
-    return &Config{
-        DBUser:     os.Getenv("DB_USER"),
-        DBPassword: os.Getenv("DB_PASSWORD"),
-        DBHost:     os.Getenv("DB_HOST"),
-        DBPort:     os.Getenv("DB_PORT"),
-        DBName:     os.Getenv("DB_NAME"),
-    }
-}
\ No newline at end of file
+	if err != nil {
+		log.Println("No .env file found, using system env")
+	}
+	return &Config{
+		DBUser:                 os.Getenv("DB_USER"),
+		DBPassword:             os.Getenv("DB_PASSWORD"),
+		DBHost:                 os.Getenv("DB_HOST"),
+		DBPort:                 os.Getenv("DB_PORT"),
+		DBName:                 os.Getenv("DB_NAME"),
+		KafkaBrokers:           os.Getenv("KAFKA_BROKERS"),
+		KafkaTopicUserActivity: getEnv("KAFKA_TOPIC_USER_ACTIVITY", "user.activity"),
+		KafkaGroupID:           getEnv("KAFKA_GROUP_ID", "user-activity"),
+		KafkaTopicTaskEvents:   getEnv("KAFKA_TOPIC_TASK_EVENTS", "task.events"),
+		KafkaTaskGroupID:       getEnv("KAFKA_TASK_GROUP_ID", "task-registry"),
+
+		DigestEnabled:           getEnv("DIGEST_ENABLED", "true") == "true",
+		DigestWeekday:           getEnvInt("DIGEST_WEEKDAY", 1),
+		DigestHour:              getEnvInt("DIGEST_HOUR", 8),
+		DigestTimezone:          getEnv("DIGEST_TIMEZONE", "Asia/Shanghai"),
+		KafkaTopicNotifications: getEnv("KAFKA_TOPIC_NOTIFICATIONS", "notification.requests"),
+		QuizServiceAddr:         registry.Quiz.Addr(),
+	}
+}
+
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+func getEnvInt(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		if n, err := strconv.Atoi(value); err == nil {
+			return n
+		}
+		log.Printf("invalid %s=%q, using %d", key, value, defaultValue)
+	}
+	return defaultValue
+}
@@ -5,6 +5,7 @@ import (
 	"log"
 
 	"github.com/RigelNana/arkstudy/proto/user"
+	"github.com/RigelNana/arkstudy/services/user-service/models"
 	"github.com/RigelNana/arkstudy/services/user-service/service"
 
 	"github.com/google/uuid"
@@ -12,10 +13,14 @@ import (
 
 type UserRPCServer struct {
 	user.UnimplementedUserServiceServer
-	svc service.UserService
+	svc         service.UserService
+	activitySvc service.ActivityService
+	taskSvc     service.TaskService
 }
 
-func NewUserRPCServer(svc service.UserService) *UserRPCServer { return &UserRPCServer{svc: svc} }
+func NewUserRPCServer(svc service.UserService, activitySvc service.ActivityService, taskSvc service.TaskService) *UserRPCServer {
+	return &UserRPCServer{svc: svc, activitySvc: activitySvc, taskSvc: taskSvc}
+}
 
 func (s *UserRPCServer) CreateUser(ctx context.Context, in *user.CreateUserRequest) (*user.CreateUserResponse, error) {
 	log.Printf("CreateUser called with: Username=%s, Email=%s, Role=%s", in.Username, in.Email, in.Role)
@@ -25,7 +30,7 @@ func (s *UserRPCServer) CreateUser(ctx context.Context, in *user.CreateUserReque
 		return &user.CreateUserResponse{Success: false, Message: err.Error()}, nil
 	}
 	log.Printf("CreateUser success: ID=%s", u.ID.String())
-	return &user.CreateUserResponse{Success: true, Message: "ok", User: &user.UserInfo{Id: u.ID.String(), Username: u.Username, Email: u.Email, Role: u.Role, Description: u.Description}}, nil
+	return &user.CreateUserResponse{Success: true, Message: "ok", User: toUserInfo(u)}, nil
 }
 
 func (s *UserRPCServer) GetUserByID(ctx context.Context, in *user.GetUserByIDRequest) (*user.GetUserResponse, error) {
@@ -37,7 +42,7 @@ func (s *UserRPCServer) GetUserByID(ctx context.Context, in *user.GetUserByIDReq
 	if err != nil {
 		return &user.GetUserResponse{Found: false, Message: err.Error()}, nil
 	}
-	return &user.GetUserResponse{Found: true, Message: "ok", User: &user.UserInfo{Id: u.ID.String(), Username: u.Username, Email: u.Email, Role: u.Role, Description: u.Description}}, nil
+	return &user.GetUserResponse{Found: true, Message: "ok", User: toUserInfo(u)}, nil
 }
 
 func (s *UserRPCServer) GetUserByUsername(ctx context.Context, in *user.GetUserByUsernameRequest) (*user.GetUserResponse, error) {
@@ -45,7 +50,7 @@ func (s *UserRPCServer) GetUserByUsername(ctx context.Context, in *user.GetUserB
 	if err != nil {
 		return &user.GetUserResponse{Found: false, Message: err.Error()}, nil
 	}
-	return &user.GetUserResponse{Found: true, Message: "ok", User: &user.UserInfo{Id: u.ID.String(), Username: u.Username, Email: u.Email, Role: u.Role, Description: u.Description}}, nil
+	return &user.GetUserResponse{Found: true, Message: "ok", User: toUserInfo(u)}, nil
 }
 
 func (s *UserRPCServer) GetUserByEmail(ctx context.Context, in *user.GetUserByEmailRequest) (*user.GetUserResponse, error) {
@@ -53,14 +58,141 @@ func (s *UserRPCServer) GetUserByEmail(ctx context.Context, in *user.GetUserByEm
 	if err != nil {
 		return &user.GetUserResponse{Found: false, Message: err.Error()}, nil
 	}
-	return &user.GetUserResponse{Found: true, Message: "ok", User: &user.UserInfo{Id: u.ID.String(), Username: u.Username, Email: u.Email, Role: u.Role, Description: u.Description}}, nil
+	return &user.GetUserResponse{Found: true, Message: "ok", User: toUserInfo(u)}, nil
 }
 
 func (s *UserRPCServer) ListUsers(ctx context.Context, in *user.ListUsersRequest) (*user.ListUsersResponse, error) {
 	users, total, _ := s.svc.List(int(in.Limit), int(in.Offset))
 	resp := &user.ListUsersResponse{Total: total}
 	for _, u := range users {
-		resp.Users = append(resp.Users, &user.UserInfo{Id: u.ID.String(), Username: u.Username, Email: u.Email, Role: u.Role, Description: u.Description})
+		resp.Users = append(resp.Users, toUserInfo(u))
+	}
+	return resp, nil
+}
+
+func (s *UserRPCServer) ListUserActivity(ctx context.Context, in *user.ListUserActivityRequest) (*user.ListUserActivityResponse, error) {
+	userID, err := uuid.Parse(in.UserId)
+	if err != nil {
+		return &user.ListUserActivityResponse{Success: false, Message: "invalid user_id"}, nil
+	}
+	limit, offset := int(in.Limit), int(in.Offset)
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	activities, total, err := s.activitySvc.List(userID, in.Type, limit, offset)
+	if err != nil {
+		log.Printf("ListUserActivity failed: %v", err)
+		return &user.ListUserActivityResponse{Success: false, Message: err.Error()}, nil
+	}
+	resp := &user.ListUserActivityResponse{Success: true, Message: "ok", Total: total}
+	for _, a := range activities {
+		resp.Activities = append(resp.Activities, &user.ActivityInfo{Id: a.ID.String(), Type: a.Type, TargetId: a.TargetID, Summary: a.Summary, Metadata: a.Metadata, OccurredAt: a.OccurredAt.Unix()})
+	}
+	return resp, nil
+}
+
+func (s *UserRPCServer) ListMaterialAccess(ctx context.Context, in *user.ListMaterialAccessRequest) (*user.ListMaterialAccessResponse, error) {
+	materialID, err := uuid.Parse(in.MaterialId)
+	if err != nil {
+		return &user.ListMaterialAccessResponse{Success: false, Message: "invalid material_id"}, nil
+	}
+	limit, offset := int(in.Limit), int(in.Offset)
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	accesses, total, err := s.activitySvc.ListMaterialAccess(materialID, in.Action, limit, offset)
+	if err != nil {
+		log.Printf("ListMaterialAccess failed: %v", err)
+		return &user.ListMaterialAccessResponse{Success: false, Message: err.Error()}, nil
+	}
+	resp := &user.ListMaterialAccessResponse{Success: true, Message: "ok", Total: total}
+	for _, a := range accesses {
+		resp.Entries = append(resp.Entries, &user.MaterialAccessInfo{ActivityId: a.ActivityID.String(), UserId: a.UserID.String(), Username: a.Username, Action: a.Action, OccurredAt: a.OccurredAt.Unix()})
+	}
+	return resp, nil
+}
+
+func (s *UserRPCServer) ListTasks(ctx context.Context, in *user.ListTasksRequest) (*user.ListTasksResponse, error) {
+	userID, err := uuid.Parse(in.UserId)
+	if err != nil {
+		return &user.ListTasksResponse{Success: false, Message: "invalid user_id"}, nil
+	}
+	limit, offset := int(in.Limit), int(in.Offset)
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	tasks, total, err := s.taskSvc.List(userID, in.Kind, in.Status, limit, offset)
+	if err != nil {
+		log.Printf("ListTasks failed: %v", err)
+		return &user.ListTasksResponse{Success: false, Message: err.Error()}, nil
+	}
+	resp := &user.ListTasksResponse{Success: true, Message: "ok", Total: total}
+	for _, t := range tasks {
+		info := &user.TaskInfo{TaskId: t.TaskID, Kind: t.Kind, Service: t.Service, Status: t.Status, Progress: t.Progress, Title: t.Title, TargetId: t.TargetID, Error: t.Error, Metadata: t.Metadata, CreatedAt: t.CreatedAt.Unix(), UpdatedAt: t.UpdatedAt.Unix()}
+		if t.FinishedAt != nil {
+			info.FinishedAt = t.FinishedAt.Unix()
+		}
+		resp.Tasks = append(resp.Tasks, info)
 	}
 	return resp, nil
 }
+
+// DeleteUser 回滚批量开通中未完成注册的用户
+func (s *UserRPCServer) DeleteUser(ctx context.Context, in *user.DeleteUserRequest) (*user.DeleteUserResponse, error) {
+	id, err := uuid.Parse(in.Id)
+	if err != nil {
+		return &user.DeleteUserResponse{Success: false, Message: "invalid id"}, nil
+	}
+	if err := s.svc.Purge(id); err != nil {
+		log.Printf("DeleteUser %s failed: %v", id, err)
+		return &user.DeleteUserResponse{Success: false, Message: err.Error()}, nil
+	}
+	log.Printf("DeleteUser success: ID=%s", id)
+	return &user.DeleteUserResponse{Success: true, Message: "ok"}, nil
+}
+
+func (s *UserRPCServer) SetDigestPreference(ctx context.Context, in *user.SetDigestPreferenceRequest) (*user.GetUserResponse, error) {
+	id, err := uuid.Parse(in.UserId)
+	if err != nil {
+		return &user.GetUserResponse{Found: false, Message: "invalid user_id"}, nil
+	}
+	u, err := s.svc.SetWeeklyDigest(id, in.WeeklyDigest)
+	if err != nil {
+		return &user.GetUserResponse{Found: false, Message: err.Error()}, nil
+	}
+	return &user.GetUserResponse{Found: true, Message: "ok", User: toUserInfo(u)}, nil
+}
+
+func (s *UserRPCServer) SetLocalePreference(ctx context.Context, in *user.SetLocalePreferenceRequest) (*user.GetUserResponse, error) {
+	id, err := uuid.Parse(in.UserId)
+	if err != nil {
+		return &user.GetUserResponse{Found: false, Message: "invalid user_id"}, nil
+	}
+	u, err := s.svc.SetLocale(id, in.Timezone, in.Language)
+	if err != nil {
+		return &user.GetUserResponse{Found: false, Message: err.Error()}, nil
+	}
+	return &user.GetUserResponse{Found: true, Message: "ok", User: toUserInfo(u)}, nil
+}
+
+func toUserInfo(u *models.User) *user.UserInfo {
+	return &user.UserInfo{
+		Id:           u.ID.String(),
+		Username:     u.Username,
+		Email:        u.Email,
+		Role:         u.Role,
+		Description:  u.Description,
+		WeeklyDigest: u.WeeklyDigest,
+		Timezone:     u.Timezone,
+		Language:     u.Language,
+	}
+}
@@ -1,13 +1,17 @@
 package main
 
 import (
+	"context"
 	"log"
 	"net"
-	"os"
 
+	"github.com/RigelNana/arkstudy/pkg/discovery"
+	"github.com/RigelNana/arkstudy/pkg/interceptor"
 	"github.com/RigelNana/arkstudy/pkg/metrics"
 	grpcMetrics "github.com/RigelNana/arkstudy/pkg/metrics/grpc"
+	"github.com/RigelNana/arkstudy/pkg/registry"
 	"github.com/RigelNana/arkstudy/proto/user"
+	"github.com/RigelNana/arkstudy/services/user-service/config"
 	"github.com/RigelNana/arkstudy/services/user-service/database"
 	urpc "github.com/RigelNana/arkstudy/services/user-service/handler/rpc"
 	"github.com/RigelNana/arkstudy/services/user-service/models"
@@ -20,41 +24,60 @@ import (
 )
 
 func autoMigrate(db *gorm.DB) {
-	if err := db.AutoMigrate(&models.User{}); err != nil {
+	if err := db.AutoMigrate(&models.User{}, &models.Activity{}, &models.MaterialAccess{}, &models.Task{}, &models.DigestDelivery{}); err != nil {
 		log.Fatalf("auto migrate failed: %v", err)
 	}
 }
 
 func main() {
-	// 启动 Prometheus metrics 服务器
-	metrics.StartMetricsServer("2112")
-	log.Printf("Prometheus metrics server started on :2112")
+	// 启动内部 HTTP 监听：/metrics 与 /healthz、/readyz，地址由 METRICS_ADDR 配置
+	log.Printf("Prometheus metrics server started on %s", metrics.StartMetricsServer())
 
 	db := database.InitDB()
+	if sqlDB, err := db.DB(); err == nil {
+		metrics.AddReadinessCheck("database", sqlDB.PingContext)
+	}
 	autoMigrate(db)
 
 	repo := repository.NewUserRepository(db)
 	svc := service.NewUserService(repo)
+	activityRepo := repository.NewActivityRepository(db)
+	taskRepo := repository.NewTaskRepository(db)
+	activitySvc := service.NewActivityService(activityRepo)
+	taskSvc := service.NewTaskService(taskRepo)
+
+	// 消费 gateway 发布的用户活动事件并落库
+	cfg := config.LoadConfig()
+	go service.StartActivityConsumer(context.Background(), cfg, activitySvc)
+	// 消费各服务发布的任务事件，合并为任务中心的任务记录
+	go service.StartTaskConsumer(context.Background(), cfg, taskSvc)
+	// 每周为订阅的用户汇总学习进度，经通知服务发送邮件
+	service.NewDigestJob(cfg, repo, activityRepo, taskRepo).Start(context.Background())
 
 	// 创建带监控的 gRPC 服务器
+	// panic 恢复、错误码规范化与慢请求日志（GRPC_SLOW_THRESHOLD）
+	interceptorOpts := interceptor.DefaultOptions("user-service")
 	grpcServer := grpc.NewServer(
-		grpc.UnaryInterceptor(grpcMetrics.UnaryServerInterceptor("user-service")),
-		grpc.StreamInterceptor(grpcMetrics.StreamServerInterceptor("user-service")),
+		grpc.ChainUnaryInterceptor(grpcMetrics.UnaryServerInterceptor("user-service"), interceptor.UnaryServerInterceptor(interceptorOpts)),
+		grpc.ChainStreamInterceptor(grpcMetrics.StreamServerInterceptor("user-service"), interceptor.StreamServerInterceptor(interceptorOpts)),
 	)
 
-	user.RegisterUserServiceServer(grpcServer, urpc.NewUserRPCServer(svc))
+	user.RegisterUserServiceServer(grpcServer, urpc.NewUserRPCServer(svc, activitySvc, taskSvc))
+	// 健康检查供客户端在多个副本间故障转移（见 pkg/discovery）
+	discovery.RegisterHealth(grpcServer)
 	// Enable server reflection
 	reflection.Register(grpcServer)
 
-	port := os.Getenv("USER_GRPC_PORT")
-	if port == "" {
-		port = "50052"
+	if err := registry.Validate(registry.User); err != nil {
+		log.Fatalf("%v", err)
 	}
+	port := registry.User.ListenPort()
 	lis, err := net.Listen("tcp", ":"+port)
 	if err != nil {
 		log.Fatalf("listen error: %v", err)
 	}
 	log.Printf("User gRPC server listening on %s", port)
+	metrics.SetReady(true)
 	if err := grpcServer.Serve(lis); err != nil {
 		log.Fatalf("serve error: %v", err)
 	}
@@ -0,0 +1,30 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// 活动类型
+const (
+	ActivityUpload         = "upload"
+	ActivityQuizAttempt    = "quiz_attempt"
+	ActivityAIQuestion     = "ai_question"
+	ActivityMaterialViewed = "material_viewed"
+	// 下载或播放资料原文件
+	ActivityMaterialDownloaded = "material_downloaded"
+)
+
+// Activity 用户活动记录，由 user.activity topic 的事件写入。
+// ID 使用事件的 event_id，重复投递的事件不会产生重复记录。
+type Activity struct {
+	ID         uuid.UUID         `gorm:"type:uuid;primaryKey"`
+	UserID     uuid.UUID         `gorm:"type:uuid;not null;index:idx_activities_user_time,priority:1"`
+	Type       string            `gorm:"type:varchar(50);not null;index"`
+	TargetID   string            `gorm:"type:varchar(255)"` // 关联对象 ID，如资料 ID、题目 ID
+	Summary    string            `gorm:"type:text"`         // 便于直接展示的简短描述
+	Metadata   map[string]string `gorm:"type:jsonb;serializer:json"`
+	OccurredAt time.Time         `gorm:"not null;index:idx_activities_user_time,priority:2,sort:desc"`
+	CreatedAt  time.Time
+}
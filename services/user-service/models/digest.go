@@ -0,0 +1,15 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DigestDelivery 每周学习进度邮件的发送记录，(user_id, scheduled_at) 唯一，scheduled_at 为该周期的计划发送时间，
+// 多个副本同时运行任务或任务重启时同一周期不会重复发送
+type DigestDelivery struct {
+	UserID      uuid.UUID `gorm:"type:uuid;primaryKey"`
+	ScheduledAt time.Time `gorm:"primaryKey"`
+	CreatedAt   time.Time
+}
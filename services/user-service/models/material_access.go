@@ -0,0 +1,28 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// 资料访问类型
+const (
+	AccessView       = "view"
+	AccessDownload   = "download"
+	AccessAIQuestion = "ai_question"
+)
+
+// MaterialAccess 资料访问审计记录，由活动事件派生：查看、下载资料与引用资料的 AI 提问各记一条，
+// 供资料所有者查看谁在何时访问了资料。一次 AI 提问引用多份资料时每份资料各一条
+type MaterialAccess struct {
+	ActivityID uuid.UUID `gorm:"type:uuid;primaryKey"`
+	MaterialID uuid.UUID `gorm:"type:uuid;primaryKey;index:idx_material_access_time,priority:1"`
+	UserID     uuid.UUID `gorm:"type:uuid;not null"`
+	Action     string    `gorm:"type:varchar(50);not null"`
+	OccurredAt time.Time `gorm:"not null;index:idx_material_access_time,priority:2,sort:desc"`
+	CreatedAt  time.Time
+
+	// 查询时关联 users 表得到，不落库
+	Username string `gorm:"->;-:migration"`
+}
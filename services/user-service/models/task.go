@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// Task 任务中心的任务记录，由 task.events 中的事件按 task_id 合并写入。
+// 事件携带任务的完整快照，UpdatedAt 为最近一次被采用的事件的发生时间，较旧的事件被忽略
+type Task struct {
+	TaskID     string            `gorm:"type:varchar(255);primaryKey"`
+	UserID     string            `gorm:"type:varchar(255);not null;index:idx_tasks_user_time,priority:1"`
+	Kind       string            `gorm:"type:varchar(50);not null;index"`
+	Service    string            `gorm:"type:varchar(50)"`
+	Status     string            `gorm:"type:varchar(20);not null;index"`
+	Progress   float32           `gorm:"not null;default:0"`
+	Title      string            `gorm:"type:text"`
+	TargetID   string            `gorm:"type:varchar(255)"`
+	Error      string            `gorm:"type:text"`
+	Metadata   map[string]string `gorm:"type:jsonb;serializer:json"`
+	CreatedAt  time.Time         `gorm:"not null"`
+	UpdatedAt  time.Time         `gorm:"not null;autoUpdateTime:false;index:idx_tasks_user_time,priority:2,sort:desc"`
+	FinishedAt *time.Time
+}
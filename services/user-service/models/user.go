@@ -6,4 +6,9 @@ type User struct {
 	Email       string `gorm:"uniqueIndex;not null"`
 	Role        string `gorm:"default:'student'"`
 	Description string `gorm:"type:text"`
+	// 是否订阅每周学习进度邮件
+	WeeklyDigest bool `gorm:"not null;default:false;index"`
+	// 时区（IANA 名称）与语言，为空时按请求头或服务默认值处理
+	Timezone string `gorm:"size:64"`
+	Language string `gorm:"size:16"`
 }
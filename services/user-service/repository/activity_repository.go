@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/RigelNana/arkstudy/services/user-service/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ActivityRepository interface {
+	// Create 写入活动记录，ID 已存在时忽略（事件重复投递）
+	Create(activity *models.Activity) error
+	ListByUserID(userID uuid.UUID, activityType string, limit, offset int) ([]*models.Activity, int64, error)
+	// CountQuizAttempts 统计 [from, to) 内的答题次数与答对次数
+	CountQuizAttempts(userID uuid.UUID, from, to time.Time) (attempts, correct int64, err error)
+	// 资料访问审计
+	CreateMaterialAccesses(accesses []*models.MaterialAccess) error
+	ListMaterialAccess(materialID uuid.UUID, action string, limit, offset int) ([]*models.MaterialAccess, int64, error)
+}
+
+type ActivityRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewActivityRepository(db *gorm.DB) ActivityRepository {
+	return &ActivityRepositoryImpl{db: db}
+}
+
+func (r *ActivityRepositoryImpl) Create(activity *models.Activity) error {
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(activity).Error
+}
+
+func (r *ActivityRepositoryImpl) ListByUserID(userID uuid.UUID, activityType string, limit, offset int) ([]*models.Activity, int64, error) {
+	query := r.db.Model(&models.Activity{}).Where("user_id = ?", userID)
+	if activityType != "" {
+		query = query.Where("type = ?", activityType)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var activities []*models.Activity
+	err := query.Order("occurred_at DESC").Limit(limit).Offset(offset).Find(&activities).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return activities, total, nil
+}
+
+func (r *ActivityRepositoryImpl) CreateMaterialAccesses(accesses []*models.MaterialAccess) error {
+	if len(accesses) == 0 {
+		return nil
+	}
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&accesses).Error
+}
+
+func (r *ActivityRepositoryImpl) ListMaterialAccess(materialID uuid.UUID, action string, limit, offset int) ([]*models.MaterialAccess, int64, error) {
+	query := r.db.Model(&models.MaterialAccess{}).Where("material_accesses.material_id = ?", materialID)
+	if action != "" {
+		query = query.Where("material_accesses.action = ?", action)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var accesses []*models.MaterialAccess
+	err := query.Select("material_accesses.*, users.username").
+		Joins("LEFT JOIN users ON users.id = material_accesses.user_id").
+		Order("material_accesses.occurred_at DESC").Limit(limit).Offset(offset).Find(&accesses).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return accesses, total, nil
+}
+
+func (r *ActivityRepositoryImpl) CountQuizAttempts(userID uuid.UUID, from, to time.Time) (int64, int64, error) {
+	var row struct {
+		Attempts int64
+		Correct  int64
+	}
+	err := r.db.Model(&models.Activity{}).
+		Select("COUNT(*) AS attempts, COUNT(*) FILTER (WHERE metadata->>'is_correct' = 'true') AS correct").
+		Where("user_id = ? AND type = ? AND occurred_at >= ? AND occurred_at < ?", userID, models.ActivityQuizAttempt, from, to).
+		Scan(&row).Error
+	return row.Attempts, row.Correct, err
+}
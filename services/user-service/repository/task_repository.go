@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/RigelNana/arkstudy/services/user-service/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TaskRepository interface {
+	// Upsert 按 task_id 写入任务快照，已有记录的 updated_at 比快照新时不覆盖（事件乱序或重复投递）
+	Upsert(task *models.Task) error
+	ListByUserID(userID, kind, status string, limit, offset int) ([]*models.Task, int64, error)
+	// CountFinished 统计 [from, to) 内以 status 结束的 kinds 类任务
+	CountFinished(userID string, kinds []string, status string, from, to time.Time) (int64, error)
+}
+
+type TaskRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewTaskRepository(db *gorm.DB) TaskRepository {
+	return &TaskRepositoryImpl{db: db}
+}
+
+func (r *TaskRepositoryImpl) Upsert(task *models.Task) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "task_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"kind", "service", "status", "progress", "title", "target_id", "error", "metadata", "updated_at", "finished_at",
+		}),
+		Where: clause.Where{Exprs: []clause.Expression{gorm.Expr("tasks.updated_at <= excluded.updated_at")}},
+	}).Create(task).Error
+}
+
+func (r *TaskRepositoryImpl) ListByUserID(userID, kind, status string, limit, offset int) ([]*models.Task, int64, error) {
+	query := r.db.Model(&models.Task{}).Where("user_id = ?", userID)
+	if kind != "" {
+		query = query.Where("kind = ?", kind)
+	}
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var tasks []*models.Task
+	err := query.Order("updated_at DESC").Limit(limit).Offset(offset).Find(&tasks).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return tasks, total, nil
+}
+
+func (r *TaskRepositoryImpl) CountFinished(userID string, kinds []string, status string, from, to time.Time) (int64, error) {
+	var total int64
+	err := r.db.Model(&models.Task{}).
+		Where("user_id = ? AND kind IN ? AND status = ? AND finished_at >= ? AND finished_at < ?", userID, kinds, status, from, to).
+		Count(&total).Error
+	return total, err
+}
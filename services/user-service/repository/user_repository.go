@@ -1,9 +1,13 @@
 package repository
 
 import (
+	"time"
+
 	"github.com/RigelNana/arkstudy/services/user-service/models"
 
+	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type UserRepository interface {
@@ -11,6 +15,14 @@ type UserRepository interface {
 	GetByUsername(username string) (*models.User, error)
 	GetByEmail(email string) (*models.User, error)
 	GetByUsernameOrEmail(usernameOrEmail string) (*models.User, error)
+	// ListDigestSubscribers 按 ID 顺序分页列出订阅了每周学习进度邮件的用户，afterID 为上一页最后一个用户
+	ListDigestSubscribers(afterID uuid.UUID, limit int) ([]*models.User, error)
+	// ClaimDigest 登记用户在计划发送时间为 scheduledAt 的周期的邮件，已登记时返回 false
+	ClaimDigest(userID uuid.UUID, scheduledAt time.Time) (bool, error)
+	// ReleaseDigest 撤销登记，发送失败后由下一轮任务重试
+	ReleaseDigest(userID uuid.UUID, scheduledAt time.Time) error
+	// Purge 物理删除用户，释放用户名与邮箱的唯一索引
+	Purge(id uuid.UUID) error
 }
 
 type UserRepositoryImpl struct {
@@ -23,6 +35,17 @@ func NewUserRepository(db *gorm.DB) UserRepository {
 	}
 }
 
+func (r *UserRepositoryImpl) Purge(id uuid.UUID) error {
+	result := r.db.Unscoped().Delete(&models.User{}, "id = ?", id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
 func (r *UserRepositoryImpl) GetByUsername(username string) (*models.User, error) {
 	var user models.User
 	err := r.db.Where("username = ?", username).First(&user).Error
@@ -49,3 +72,21 @@ func (r *UserRepositoryImpl) GetByUsernameOrEmail(usernameOrEmail string) (*mode
 	}
 	return &user, nil
 }
+
+func (r *UserRepositoryImpl) ListDigestSubscribers(afterID uuid.UUID, limit int) ([]*models.User, error) {
+	var users []*models.User
+	err := r.db.Where("weekly_digest = ? AND id > ?", true, afterID).Order("id").Limit(limit).Find(&users).Error
+	return users, err
+}
+
+func (r *UserRepositoryImpl) ClaimDigest(userID uuid.UUID, scheduledAt time.Time) (bool, error) {
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&models.DigestDelivery{UserID: userID, ScheduledAt: scheduledAt})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *UserRepositoryImpl) ReleaseDigest(userID uuid.UUID, scheduledAt time.Time) error {
+	return r.db.Where("user_id = ? AND scheduled_at = ?", userID, scheduledAt).Delete(&models.DigestDelivery{}).Error
+}
@@ -0,0 +1,170 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/RigelNana/arkstudy/services/user-service/config"
+	"github.com/RigelNana/arkstudy/services/user-service/models"
+	"github.com/RigelNana/arkstudy/services/user-service/repository"
+
+	arkkafka "github.com/RigelNana/arkstudy/pkg/kafka"
+	kafkaMetrics "github.com/RigelNana/arkstudy/pkg/metrics/kafka"
+	"github.com/google/uuid"
+)
+
+// ActivityEvent 为 user.activity topic 中的消息结构，由 gateway 在用户操作成功后发布
+type ActivityEvent struct {
+	EventID    string            `json:"event_id"`
+	UserID     string            `json:"user_id"`
+	Type       string            `json:"type"`
+	TargetID   string            `json:"target_id"`
+	Summary    string            `json:"summary"`
+	Metadata   map[string]string `json:"metadata,omitempty"`
+	OccurredAt time.Time         `json:"occurred_at"`
+}
+
+// ErrInvalidActivityEvent 表示事件内容不合法，重试也无法写入
+var ErrInvalidActivityEvent = errors.New("invalid activity event")
+
+type ActivityService interface {
+	Record(event *ActivityEvent) error
+	List(userID uuid.UUID, activityType string, limit, offset int) ([]*models.Activity, int64, error)
+	ListMaterialAccess(materialID uuid.UUID, action string, limit, offset int) ([]*models.MaterialAccess, int64, error)
+}
+
+type ActivityServiceImpl struct{ repo repository.ActivityRepository }
+
+func NewActivityService(r repository.ActivityRepository) ActivityService {
+	return &ActivityServiceImpl{repo: r}
+}
+
+func (s *ActivityServiceImpl) Record(event *ActivityEvent) error {
+	id, err := uuid.Parse(event.EventID)
+	if err != nil {
+		return fmt.Errorf("%w: bad event_id %q", ErrInvalidActivityEvent, event.EventID)
+	}
+	userID, err := uuid.Parse(event.UserID)
+	if err != nil {
+		return fmt.Errorf("%w: bad user_id %q", ErrInvalidActivityEvent, event.UserID)
+	}
+	if event.Type == "" {
+		return fmt.Errorf("%w: type is required", ErrInvalidActivityEvent)
+	}
+	occurredAt := event.OccurredAt
+	if occurredAt.IsZero() {
+		occurredAt = time.Now()
+	}
+	activity := &models.Activity{
+		ID:         id,
+		UserID:     userID,
+		Type:       event.Type,
+		TargetID:   event.TargetID,
+		Summary:    event.Summary,
+		Metadata:   event.Metadata,
+		OccurredAt: occurredAt,
+	}
+	if err := s.repo.Create(activity); err != nil {
+		return err
+	}
+	// 两次写入均幂等，访问记录写入失败时整条事件重试
+	return s.repo.CreateMaterialAccesses(materialAccesses(activity))
+}
+
+// materialAccesses 从活动中派生资料访问记录；AI 提问的 metadata.material_ids 为逗号分隔的资料 ID
+func materialAccesses(a *models.Activity) []*models.MaterialAccess {
+	var action string
+	var materialIDs []string
+	switch a.Type {
+	case models.ActivityMaterialViewed:
+		action, materialIDs = models.AccessView, []string{a.TargetID}
+	case models.ActivityMaterialDownloaded:
+		action, materialIDs = models.AccessDownload, []string{a.TargetID}
+	case models.ActivityAIQuestion:
+		action, materialIDs = models.AccessAIQuestion, strings.Split(a.Metadata["material_ids"], ",")
+	default:
+		return nil
+	}
+
+	var accesses []*models.MaterialAccess
+	seen := map[uuid.UUID]bool{}
+	for _, raw := range materialIDs {
+		materialID, err := uuid.Parse(strings.TrimSpace(raw))
+		if err != nil || seen[materialID] {
+			continue
+		}
+		seen[materialID] = true
+		accesses = append(accesses, &models.MaterialAccess{
+			ActivityID: a.ID,
+			MaterialID: materialID,
+			UserID:     a.UserID,
+			Action:     action,
+			OccurredAt: a.OccurredAt,
+		})
+	}
+	return accesses
+}
+
+func (s *ActivityServiceImpl) List(userID uuid.UUID, activityType string, limit, offset int) ([]*models.Activity, int64, error) {
+	return s.repo.ListByUserID(userID, activityType, limit, offset)
+}
+
+func (s *ActivityServiceImpl) ListMaterialAccess(materialID uuid.UUID, action string, limit, offset int) ([]*models.MaterialAccess, int64, error) {
+	return s.repo.ListMaterialAccess(materialID, action, limit, offset)
+}
+
+// StartActivityConsumer 消费 user.activity topic 并写入数据库，直到 ctx 取消。
+// 数据库写入失败时原地重试，保证 offset 只在写入成功后提交；不合法的消息直接跳过。
+func StartActivityConsumer(ctx context.Context, cfg *config.Config, svc ActivityService) {
+	brokers := arkkafka.SplitBrokers(cfg.KafkaBrokers)
+	if len(brokers) == 0 {
+		log.Println("KAFKA_BROKERS not set, activity consumer disabled")
+		return
+	}
+
+	r := kafkaMetrics.InstrumentReader("user-service", arkkafka.NewReader(brokers, cfg.KafkaGroupID, cfg.KafkaTopicUserActivity))
+	defer r.Close()
+	log.Printf("Activity consumer started: topic=%s group=%s", cfg.KafkaTopicUserActivity, cfg.KafkaGroupID)
+
+	for {
+		msg, err := kafkaMetrics.FetchMessage(ctx, r)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			log.Printf("activity consumer fetch: %v", err)
+			time.Sleep(time.Second)
+			continue
+		}
+
+		var event ActivityEvent
+		if err := json.Unmarshal(msg.Value, &event); err != nil {
+			log.Printf("bad activity event json: %v", err)
+			_ = kafkaMetrics.CommitMessages(ctx, r, msg)
+			continue
+		}
+		for {
+			err := svc.Record(&event)
+			if err == nil {
+				break
+			}
+			log.Printf("record activity %s failed: %v", event.EventID, err)
+			if errors.Is(err, ErrInvalidActivityEvent) {
+				break
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(time.Second):
+			}
+		}
+		if err := kafkaMetrics.CommitMessages(ctx, r, msg); err != nil {
+			log.Printf("activity consumer commit: %v", err)
+		}
+	}
+}
@@ -0,0 +1,304 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/RigelNana/arkstudy/pkg/discovery"
+	arkkafka "github.com/RigelNana/arkstudy/pkg/kafka"
+	"github.com/RigelNana/arkstudy/pkg/locale"
+	kafkaMetrics "github.com/RigelNana/arkstudy/pkg/metrics/kafka"
+	"github.com/RigelNana/arkstudy/pkg/registry"
+	quizpb "github.com/RigelNana/arkstudy/proto/quiz"
+	"github.com/RigelNana/arkstudy/services/user-service/config"
+	"github.com/RigelNana/arkstudy/services/user-service/models"
+	"github.com/RigelNana/arkstudy/services/user-service/repository"
+
+	"github.com/google/uuid"
+	kafka "github.com/segmentio/kafka-go"
+)
+
+const (
+	// digestCheckInterval 检查是否到达发送时间的间隔，错过的周期在下一次检查时补发
+	digestCheckInterval = 10 * time.Minute
+	// digestBatchSize 每批读取的订阅用户数
+	digestBatchSize = 100
+)
+
+// 视为“处理了一份资料”的任务类型
+var digestMaterialTaskKinds = []string{arkkafka.TaskKindOCR, arkkafka.TaskKindASR}
+
+// WeeklyDigest 一个用户一周的学习进度，Previous* 为再往前一周，用于计算正确率变化
+type WeeklyDigest struct {
+	From, To           time.Time
+	QuestionsAnswered  int64
+	Correct            int64
+	PreviousAnswered   int64
+	PreviousCorrect    int64
+	MaterialsProcessed int64
+	// DueReviews 尚未掌握的错题数，quiz-service 不可用时为 -1
+	DueReviews int64
+}
+
+// Empty 本周没有任何可报告的内容
+func (d *WeeklyDigest) Empty() bool {
+	return d.QuestionsAnswered == 0 && d.MaterialsProcessed == 0 && d.DueReviews <= 0
+}
+
+// DigestJob 每周为订阅的用户汇总答题数、正确率变化、待复习错题与新处理的资料，
+// 以 notification.requests 消息交给通知服务发送邮件
+type DigestJob struct {
+	users      repository.UserRepository
+	activities repository.ActivityRepository
+	tasks      repository.TaskRepository
+	quiz       quizpb.QuizServiceClient
+	writer     *kafka.Writer
+	topic      string
+	weekday    time.Weekday
+	hour       int
+	loc        *time.Location
+}
+
+// NewDigestJob 按配置创建任务，未启用或未配置 KAFKA_BROKERS 时返回 nil
+func NewDigestJob(cfg *config.Config, users repository.UserRepository, activities repository.ActivityRepository, tasks repository.TaskRepository) *DigestJob {
+	if !cfg.DigestEnabled {
+		log.Println("DIGEST_ENABLED=false, weekly digest disabled")
+		return nil
+	}
+	brokers := arkkafka.SplitBrokers(cfg.KafkaBrokers)
+	if len(brokers) == 0 {
+		log.Println("KAFKA_BROKERS not set, weekly digest disabled")
+		return nil
+	}
+	loc, err := time.LoadLocation(cfg.DigestTimezone)
+	if err != nil {
+		log.Printf("invalid DIGEST_TIMEZONE %q, using UTC: %v", cfg.DigestTimezone, err)
+		loc = time.UTC
+	}
+	if cfg.DigestWeekday < 0 || cfg.DigestWeekday > 6 || cfg.DigestHour < 0 || cfg.DigestHour > 23 {
+		log.Printf("invalid digest schedule weekday=%d hour=%d, using Monday 08:00", cfg.DigestWeekday, cfg.DigestHour)
+		cfg.DigestWeekday, cfg.DigestHour = int(time.Monday), 8
+	}
+
+	job := &DigestJob{
+		users:      users,
+		activities: activities,
+		tasks:      tasks,
+		writer:     kafkaMetrics.InstrumentWriter("user-service", arkkafka.NewWriter(brokers, cfg.KafkaTopicNotifications)),
+		topic:      cfg.KafkaTopicNotifications,
+		weekday:    time.Weekday(cfg.DigestWeekday),
+		hour:       cfg.DigestHour,
+		loc:        loc,
+	}
+	// 待复习错题来自 quiz-service，连接失败时周报省略该项
+	if conn, err := discovery.DialAddr(registry.Quiz.Name, cfg.QuizServiceAddr); err != nil {
+		log.Printf("Warning: failed to connect to quiz service, digests will omit due reviews: %v", err)
+	} else {
+		job.quiz = quizpb.NewQuizServiceClient(conn)
+	}
+	return job
+}
+
+// Start 在后台定期检查并发送本周期尚未发送的周报，直到 ctx 取消
+func (j *DigestJob) Start(ctx context.Context) {
+	if j == nil {
+		return
+	}
+	log.Printf("Weekly digest job started: %s %02d:00 %s, topic=%s", j.weekday, j.hour, j.loc, j.topic)
+	go func() {
+		defer j.writer.Close()
+		ticker := time.NewTicker(digestCheckInterval)
+		defer ticker.Stop()
+		for {
+			j.run(ctx, time.Now())
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// scheduledAt 返回不晚于 now 的最近一次计划发送时间
+func (j *DigestJob) scheduledAt(now time.Time) time.Time {
+	local := now.In(j.loc)
+	t := time.Date(local.Year(), local.Month(), local.Day(), j.hour, 0, 0, 0, j.loc)
+	for t.Weekday() != j.weekday || t.After(local) {
+		t = t.AddDate(0, 0, -1)
+	}
+	return t
+}
+
+// run 为所有尚未收到本周期周报的订阅用户发送周报
+func (j *DigestJob) run(ctx context.Context, now time.Time) {
+	scheduled := j.scheduledAt(now)
+	sent := 0
+	after := uuid.Nil
+	for ctx.Err() == nil {
+		users, err := j.users.ListDigestSubscribers(after, digestBatchSize)
+		if err != nil {
+			log.Printf("list digest subscribers failed: %v", err)
+			return
+		}
+		for _, u := range users {
+			ok, err := j.deliver(ctx, u, scheduled)
+			if err != nil {
+				log.Printf("weekly digest for user %s failed: %v", u.ID, err)
+				continue
+			}
+			if ok {
+				sent++
+			}
+		}
+		if len(users) < digestBatchSize {
+			break
+		}
+		after = users[len(users)-1].ID
+	}
+	if sent > 0 {
+		log.Printf("Sent %d weekly digests for %s", sent, scheduled.Format(time.RFC3339))
+	}
+}
+
+// deliver 登记并发送一个用户的周报，已由其他副本或上一轮发送时返回 false
+func (j *DigestJob) deliver(ctx context.Context, u *models.User, scheduled time.Time) (bool, error) {
+	claimed, err := j.users.ClaimDigest(u.ID, scheduled)
+	if err != nil || !claimed {
+		return false, err
+	}
+
+	digest, err := j.Compile(ctx, u.ID, scheduled)
+	if err == nil {
+		if digest.Empty() {
+			// 没有内容的周报不发送，登记保留以免每轮重复统计
+			return false, nil
+		}
+		err = j.publish(ctx, u, digest)
+	}
+	if err != nil {
+		if releaseErr := j.users.ReleaseDigest(u.ID, scheduled); releaseErr != nil {
+			log.Printf("release weekly digest of user %s failed: %v", u.ID, releaseErr)
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// Compile 统计 until 之前 7 天的学习进度
+func (j *DigestJob) Compile(ctx context.Context, userID uuid.UUID, until time.Time) (*WeeklyDigest, error) {
+	from := until.AddDate(0, 0, -7)
+	d := &WeeklyDigest{From: from, To: until, DueReviews: -1}
+
+	var err error
+	if d.QuestionsAnswered, d.Correct, err = j.activities.CountQuizAttempts(userID, from, until); err != nil {
+		return nil, fmt.Errorf("count quiz attempts: %w", err)
+	}
+	if d.PreviousAnswered, d.PreviousCorrect, err = j.activities.CountQuizAttempts(userID, from.AddDate(0, 0, -7), from); err != nil {
+		return nil, fmt.Errorf("count previous quiz attempts: %w", err)
+	}
+	if d.MaterialsProcessed, err = j.tasks.CountFinished(userID.String(), digestMaterialTaskKinds, arkkafka.TaskStatusSucceeded, from, until); err != nil {
+		return nil, fmt.Errorf("count processed materials: %w", err)
+	}
+
+	if j.quiz != nil {
+		qctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		resp, err := j.quiz.ListMistakes(qctx, &quizpb.ListMistakesRequest{UserId: userID.String(), Page: 1, PageSize: 1})
+		cancel()
+		switch {
+		case err != nil:
+			log.Printf("Warning: list mistakes of user %s failed: %v", userID, err)
+		case !resp.Success:
+			log.Printf("Warning: list mistakes of user %s failed: %s", userID, resp.Message)
+		default:
+			d.DueReviews = int64(resp.Total)
+		}
+	}
+	return d, nil
+}
+
+func (j *DigestJob) publish(ctx context.Context, u *models.User, d *WeeklyDigest) error {
+	subject, body, data := renderDigest(u, d, userLocation(u, j.loc))
+	msg, err := arkkafka.NotificationMessage(arkkafka.NotificationEvent{
+		// 同一周期重试时 ID 不变，由通知服务去重
+		NotificationID: uuid.NewSHA1(uuid.NameSpaceOID, []byte("weekly_digest:"+u.ID.String()+":"+d.To.UTC().Format(time.RFC3339))).String(),
+		UserID:         u.ID.String(),
+		Channel:        arkkafka.NotificationChannelEmail,
+		To:             u.Email,
+		Template:       arkkafka.NotificationTemplateWeeklyDigest,
+		Subject:        subject,
+		Body:           body,
+		Data:           data,
+	})
+	if err != nil {
+		return err
+	}
+	wctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	return kafkaMetrics.WriteMessages(wctx, j.writer, msg)
+}
+
+// renderDigest 生成周报的纯文本邮件与模板变量
+func renderDigest(u *models.User, d *WeeklyDigest, loc *time.Location) (string, string, map[string]string) {
+	const day = "2006-01-02"
+	from, to := d.From.In(loc).Format(day), d.To.In(loc).AddDate(0, 0, -1).Format(day)
+	data := map[string]string{
+		"username":            u.Username,
+		"period_start":        from,
+		"period_end":          to,
+		"questions_answered":  strconv.FormatInt(d.QuestionsAnswered, 10),
+		"correct":             strconv.FormatInt(d.Correct, 10),
+		"materials_processed": strconv.FormatInt(d.MaterialsProcessed, 10),
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s，你好！\n\n以下是你 %s 至 %s 的学习周报：\n\n", u.Username, from, to)
+	if d.QuestionsAnswered > 0 {
+		accuracy := percent(d.Correct, d.QuestionsAnswered)
+		data["accuracy"] = strconv.Itoa(accuracy)
+		fmt.Fprintf(&b, "- 答题 %d 道，正确率 %d%%", d.QuestionsAnswered, accuracy)
+		if d.PreviousAnswered > 0 {
+			previous := percent(d.PreviousCorrect, d.PreviousAnswered)
+			data["previous_accuracy"] = strconv.Itoa(previous)
+			data["accuracy_change"] = strconv.Itoa(accuracy - previous)
+			switch {
+			case accuracy > previous:
+				fmt.Fprintf(&b, "（较上周提升 %d 个百分点）", accuracy-previous)
+			case accuracy < previous:
+				fmt.Fprintf(&b, "（较上周下降 %d 个百分点）", previous-accuracy)
+			default:
+				b.WriteString("（与上周持平）")
+			}
+		}
+		b.WriteString("\n")
+	} else {
+		b.WriteString("- 本周没有答题记录\n")
+	}
+	fmt.Fprintf(&b, "- 新处理完成的资料 %d 份\n", d.MaterialsProcessed)
+	if d.DueReviews >= 0 {
+		data["due_reviews"] = strconv.FormatInt(d.DueReviews, 10)
+		fmt.Fprintf(&b, "- 待复习错题 %d 道\n", d.DueReviews)
+	}
+	b.WriteString("\n如不想再收到周报，可在个人设置中关闭学习周报。\n")
+
+	return fmt.Sprintf("ArkStudy 学习周报（%s 至 %s）", from, to), b.String(), data
+}
+
+// userLocation 用户设置了时区时按其时区展示周报日期，否则使用 DIGEST_TIMEZONE
+func userLocation(u *models.User, fallback *time.Location) *time.Location {
+	if u.Timezone == "" || !locale.ValidTimezone(u.Timezone) {
+		return fallback
+	}
+	return locale.Locale{Timezone: u.Timezone}.Location()
+}
+
+func percent(n, total int64) int {
+	if total == 0 {
+		return 0
+	}
+	return int((n*100 + total/2) / total)
+}
@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/RigelNana/arkstudy/services/user-service/config"
+	"github.com/RigelNana/arkstudy/services/user-service/models"
+	"github.com/RigelNana/arkstudy/services/user-service/repository"
+
+	arkkafka "github.com/RigelNana/arkstudy/pkg/kafka"
+	kafkaMetrics "github.com/RigelNana/arkstudy/pkg/metrics/kafka"
+	"github.com/google/uuid"
+)
+
+// ErrInvalidTaskEvent 表示任务事件内容不合法，重试也无法写入
+var ErrInvalidTaskEvent = errors.New("invalid task event")
+
+var taskStatuses = map[string]bool{
+	arkkafka.TaskStatusPending:   true,
+	arkkafka.TaskStatusRunning:   true,
+	arkkafka.TaskStatusSucceeded: true,
+	arkkafka.TaskStatusFailed:    true,
+}
+
+type TaskService interface {
+	Record(event *arkkafka.TaskEvent) error
+	List(userID uuid.UUID, kind, status string, limit, offset int) ([]*models.Task, int64, error)
+}
+
+type TaskServiceImpl struct{ repo repository.TaskRepository }
+
+func NewTaskService(r repository.TaskRepository) TaskService {
+	return &TaskServiceImpl{repo: r}
+}
+
+func (s *TaskServiceImpl) Record(event *arkkafka.TaskEvent) error {
+	if event.TaskID == "" {
+		return fmt.Errorf("%w: task_id is required", ErrInvalidTaskEvent)
+	}
+	if _, err := uuid.Parse(event.UserID); err != nil {
+		return fmt.Errorf("%w: bad user_id %q", ErrInvalidTaskEvent, event.UserID)
+	}
+	if event.Kind == "" {
+		return fmt.Errorf("%w: kind is required", ErrInvalidTaskEvent)
+	}
+	if !taskStatuses[event.Status] {
+		return fmt.Errorf("%w: unknown status %q", ErrInvalidTaskEvent, event.Status)
+	}
+	occurredAt := event.OccurredAt
+	if occurredAt.IsZero() {
+		occurredAt = time.Now()
+	}
+	task := &models.Task{
+		TaskID:    event.TaskID,
+		UserID:    event.UserID,
+		Kind:      event.Kind,
+		Service:   event.Service,
+		Status:    event.Status,
+		Progress:  event.Progress,
+		Title:     event.Title,
+		TargetID:  event.TargetID,
+		Error:     event.Error,
+		Metadata:  event.Metadata,
+		CreatedAt: occurredAt,
+		UpdatedAt: occurredAt,
+	}
+	if arkkafka.TaskFinished(event.Status) {
+		task.FinishedAt = &occurredAt
+	}
+	return s.repo.Upsert(task)
+}
+
+func (s *TaskServiceImpl) List(userID uuid.UUID, kind, status string, limit, offset int) ([]*models.Task, int64, error) {
+	return s.repo.ListByUserID(userID.String(), kind, status, limit, offset)
+}
+
+// StartTaskConsumer 消费 task.events topic 并合并到任务表，直到 ctx 取消。
+// 与活动消费者相同，数据库写入失败时原地重试，不合法的消息直接跳过
+func StartTaskConsumer(ctx context.Context, cfg *config.Config, svc TaskService) {
+	brokers := arkkafka.SplitBrokers(cfg.KafkaBrokers)
+	if len(brokers) == 0 {
+		log.Println("KAFKA_BROKERS not set, task consumer disabled")
+		return
+	}
+
+	r := kafkaMetrics.InstrumentReader("user-service", arkkafka.NewReader(brokers, cfg.KafkaTaskGroupID, cfg.KafkaTopicTaskEvents))
+	defer r.Close()
+	log.Printf("Task consumer started: topic=%s group=%s", cfg.KafkaTopicTaskEvents, cfg.KafkaTaskGroupID)
+
+	for {
+		msg, err := kafkaMetrics.FetchMessage(ctx, r)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			log.Printf("task consumer fetch: %v", err)
+			time.Sleep(time.Second)
+			continue
+		}
+
+		var event arkkafka.TaskEvent
+		if err := json.Unmarshal(msg.Value, &event); err != nil {
+			log.Printf("bad task event json: %v", err)
+			_ = kafkaMetrics.CommitMessages(ctx, r, msg)
+			continue
+		}
+		for {
+			err := svc.Record(&event)
+			if err == nil {
+				break
+			}
+			log.Printf("record task %s failed (request_id=%s): %v", event.TaskID, arkkafka.RequestID(msg), err)
+			if errors.Is(err, ErrInvalidTaskEvent) {
+				break
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(time.Second):
+			}
+		}
+		if err := kafkaMetrics.CommitMessages(ctx, r, msg); err != nil {
+			log.Printf("task consumer commit: %v", err)
+		}
+	}
+}
@@ -1,18 +1,31 @@
 package service
 
 import (
+	"errors"
+
+	"github.com/RigelNana/arkstudy/pkg/locale"
 	"github.com/RigelNana/arkstudy/services/user-service/models"
 	"github.com/RigelNana/arkstudy/services/user-service/repository"
 
 	"github.com/google/uuid"
 )
 
+var (
+	ErrInvalidTimezone = errors.New("invalid timezone")
+	ErrInvalidLanguage = errors.New("unsupported language")
+)
+
 type UserService interface {
 	Create(username, email, role, description string) (*models.User, error)
 	GetByID(id uuid.UUID) (*models.User, error)
 	GetByUsername(username string) (*models.User, error)
 	GetByEmail(email string) (*models.User, error)
 	List(limit, offset int) ([]*models.User, int64, error)
+	SetWeeklyDigest(id uuid.UUID, enabled bool) (*models.User, error)
+	// SetLocale 设置时区与语言，空串表示清除
+	SetLocale(id uuid.UUID, timezone, language string) (*models.User, error)
+	// Purge 彻底删除用户（回滚未完成的注册）
+	Purge(id uuid.UUID) error
 }
 
 type UserServiceImpl struct{ repo repository.UserRepository }
@@ -26,6 +39,7 @@ func (s *UserServiceImpl) Create(username, email, role, description string) (*mo
 	}
 	return u, nil
 }
+func (s *UserServiceImpl) Purge(id uuid.UUID) error                   { return s.repo.Purge(id) }
 func (s *UserServiceImpl) GetByID(id uuid.UUID) (*models.User, error) { return s.repo.GetByID(id) }
 func (s *UserServiceImpl) GetByUsername(username string) (*models.User, error) {
 	return s.repo.GetByUsername(username)
@@ -41,3 +55,37 @@ func (s *UserServiceImpl) List(limit, offset int) ([]*models.User, int64, error)
 	total, err := s.repo.Count()
 	return items, total, err
 }
+
+// SetWeeklyDigest 订阅或退订每周学习进度邮件
+func (s *UserServiceImpl) SetWeeklyDigest(id uuid.UUID, enabled bool) (*models.User, error) {
+	u, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	u.WeeklyDigest = enabled
+	if err := s.repo.Update(u); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+// SetLocale 设置时区与语言，language 规范化为 zh-CN / en
+func (s *UserServiceImpl) SetLocale(id uuid.UUID, timezone, language string) (*models.User, error) {
+	if timezone != "" && !locale.ValidTimezone(timezone) {
+		return nil, ErrInvalidTimezone
+	}
+	if language != "" {
+		if language = locale.NormalizeLanguage(language); language == "" {
+			return nil, ErrInvalidLanguage
+		}
+	}
+	u, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	u.Timezone, u.Language = timezone, language
+	if err := s.repo.Update(u); err != nil {
+		return nil, err
+	}
+	return u, nil
+}